@@ -10,6 +10,7 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/ai"
 	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/services"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -17,7 +18,7 @@ import (
 var aiCmd = &cobra.Command{
 	Use:   "ai",
 	Short: "AI assistant configuration and management",
-	Long:  `Configure and interact with AI assistants (Claude or Ollama) for algorithm learning support.`,
+	Long:  `Configure and interact with AI assistants (Claude, Ollama, an OpenAI-compatible API or the Anthropic API) for algorithm learning support.`,
 }
 
 var aiConfigCmd = &cobra.Command{
@@ -79,13 +80,29 @@ var aiTestCmd = &cobra.Command{
 var aiReplCmd = &cobra.Command{
 	Use:   "repl",
 	Short: "Start interactive AI chat session",
-	Long:  `Start an interactive AI chat session for algorithm learning assistance.`,
+	Long: `Start an interactive AI chat session for algorithm learning assistance.
+
+With --interviewer, the AI plays a mock interviewer for the problem given by
+--problem-id: it answers your clarifying questions, asks for your approach and
+its complexity, and never reveals the solution. Hints are off, and the
+interviewer wraps up with an assessment after --turns replies.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		problemID, _ := cmd.Flags().GetString("problem-id")
 		language, _ := cmd.Flags().GetString("language")
 		provider, _ := cmd.Flags().GetString("provider")
+		interviewer, _ := cmd.Flags().GetBool("interviewer")
+		turns, _ := cmd.Flags().GetInt("turns")
+
+		mode := ai.TutorMode
+		if interviewer {
+			if problemID == "" {
+				fmt.Println("Interviewer mode needs a problem. Example: algo-scales ai repl --interviewer --problem-id two_sum")
+				return
+			}
+			mode = ai.InterviewerMode
+		}
 		
-		startAIRepl(problemID, language, provider)
+		startAIRepl(problemID, language, provider, mode, turns)
 	},
 }
 
@@ -100,10 +117,12 @@ func init() {
 	aiCmd.AddCommand(aiReplCmd)
 
 	// Add flags
-	aiTestCmd.Flags().StringP("provider", "p", "", "AI provider to test (claude or ollama)")
+	aiTestCmd.Flags().StringP("provider", "p", "", "AI provider to test (claude, ollama, openai or anthropic)")
 	aiReplCmd.Flags().String("problem-id", "", "Problem ID for context")
 	aiReplCmd.Flags().String("language", "go", "Programming language")
-	aiReplCmd.Flags().String("provider", "", "AI provider (claude or ollama)")
+	aiReplCmd.Flags().String("provider", "", "AI provider (claude, ollama, openai or anthropic)")
+	aiReplCmd.Flags().Bool("interviewer", false, "Have the AI run a mock interview instead of tutoring")
+	aiReplCmd.Flags().Int("turns", ai.DefaultInterviewTurns, "Interviewer replies before the interview wraps up")
 
 	// Add ai command to root
 	rootCmd.AddCommand(aiCmd)
@@ -118,10 +137,25 @@ func init() {
 // reviewCmd provides AI-powered code review
 var reviewCmd = &cobra.Command{
 	Use:   "review [file]",
-	Short: "Get AI-powered code review",
-	Long:  `Submit your solution for AI-powered code review and feedback.`,
-	Args:  cobra.MaximumNArgs(1),
+	Short: "Review problems due for spaced repetition, or get AI code review",
+	Long: `Without arguments, list the problems due for spaced-repetition review.
+Every practice session reschedules its problem (SM-2): problems you struggled
+with come back the next day, problems solved cleanly come back at growing
+intervals. Use --start to practice the most overdue problem and --all to see
+the whole schedule.
+
+With a file and --problem, submit your solution for AI-powered code review
+and feedback.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		problemFlag, _ := cmd.Flags().GetString("problem")
+		if len(args) == 0 && problemFlag == "" {
+			all, _ := cmd.Flags().GetBool("all")
+			start, _ := cmd.Flags().GetBool("start")
+			runReviewQueue(cmd, all, start)
+			return
+		}
+
 		useAI, _ := cmd.Flags().GetBool("ai")
 		if !useAI {
 			fmt.Println("Code review requires AI. Use --ai flag to enable.")
@@ -234,7 +268,9 @@ func configureAIAssistant() {
 	fmt.Println("\nSelect your AI provider:")
 	fmt.Println("1. Claude (via Claude Code CLI)")
 	fmt.Println("2. Ollama (local AI)")
-	fmt.Print("\nChoice (1-2): ")
+	fmt.Println("3. OpenAI-compatible API (OpenAI, Groq, LM Studio, ...)")
+	fmt.Println("4. Anthropic API (Claude without the Claude Code CLI)")
+	fmt.Print("\nChoice (1-4): ")
 
 	var choice string
 	fmt.Scanln(&choice)
@@ -246,6 +282,12 @@ func configureAIAssistant() {
 	case "2":
 		config.DefaultProvider = "ollama"
 		configureOllama(config)
+	case "3":
+		config.DefaultProvider = "openai"
+		configureOpenAI(config)
+	case "4":
+		config.DefaultProvider = "anthropic"
+		configureAnthropic(config)
 	default:
 		fmt.Println(errorStyle.Render("Invalid choice"))
 		return
@@ -289,19 +331,18 @@ func configureClaude(config *ai.Config) {
 	var enableMCP string
 	fmt.Scanln(&enableMCP)
 	if enableMCP != "n" {
+		// Claude can only read the current problem's workspace, after asking
 		config.Claude.MCP = &ai.MCPConfig{
 			Enabled: true,
-			Servers: map[string]ai.MCPServerConfig{
-				"filesystem": {
-					Command: "npx",
-					Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "./"},
-				},
+			Servers: map[string]ai.MCPServerConfig{},
+			Permissions: &ai.MCPPermissions{
+				FileAccess: ai.FileAccessAsk,
+				AuditLog:   "~/.algo-scales/ai-file-access.log",
 			},
 		}
 		config.Claude.AllowedTools = []string{
 			"mcp__filesystem__read_file",
 			"mcp__filesystem__list_directory",
-			"Read",
 		}
 	}
 }
@@ -335,6 +376,70 @@ func configureOllama(config *ai.Config) {
 	}
 }
 
+func configureOpenAI(config *ai.Config) {
+	if config.OpenAI == nil {
+		config.OpenAI = &ai.OpenAIConfig{
+			BaseURL:     "https://api.openai.com/v1",
+			Model:       "gpt-4o-mini",
+			Temperature: 0.7,
+			Timeout:     120,
+		}
+	}
+
+	fmt.Println("\nOpenAI-compatible API Configuration")
+	fmt.Println("Note: for Groq use https://api.groq.com/openai/v1, for LM Studio http://localhost:1234/v1")
+
+	fmt.Printf("\nBase URL [%s]: ", config.OpenAI.BaseURL)
+	var baseURL string
+	fmt.Scanln(&baseURL)
+	if baseURL != "" {
+		config.OpenAI.BaseURL = baseURL
+	}
+
+	fmt.Printf("Model name [%s]: ", config.OpenAI.Model)
+	var model string
+	fmt.Scanln(&model)
+	if model != "" {
+		config.OpenAI.Model = model
+	}
+
+	fmt.Print("API key (leave empty to use OPENAI_API_KEY, or for local servers): ")
+	var apiKey string
+	fmt.Scanln(&apiKey)
+	if apiKey != "" {
+		config.OpenAI.APIKey = apiKey
+	}
+}
+
+func configureAnthropic(config *ai.Config) {
+	if config.Anthropic == nil {
+		config.Anthropic = &ai.AnthropicConfig{
+			Model:       "claude-sonnet-4-5",
+			MaxTokens:   4096,
+			Temperature: 0.7,
+			Timeout:     120,
+			MaxRetries:  3,
+		}
+	}
+
+	fmt.Println("\nAnthropic API Configuration")
+	fmt.Println("Note: create an API key at https://console.anthropic.com/settings/keys")
+
+	fmt.Printf("\nModel name [%s]: ", config.Anthropic.Model)
+	var model string
+	fmt.Scanln(&model)
+	if model != "" {
+		config.Anthropic.Model = model
+	}
+
+	fmt.Print("API key (leave empty to use ANTHROPIC_API_KEY): ")
+	var apiKey string
+	fmt.Scanln(&apiKey)
+	if apiKey != "" {
+		config.Anthropic.APIKey = apiKey
+	}
+}
+
 func displayConfig(config *ai.Config) {
 	// Convert to YAML for pretty display
 	data, err := yaml.Marshal(config)
@@ -376,6 +481,23 @@ func updateConfig(key, value string) error {
 				config.Claude.SessionDir = value
 			case "max_turns":
 				fmt.Sscanf(value, "%d", &config.Claude.MaxTurns)
+			case "file_access", "audit_log":
+				if config.Claude.MCP == nil {
+					config.Claude.MCP = &ai.MCPConfig{Enabled: true}
+				}
+				if config.Claude.MCP.Permissions == nil {
+					config.Claude.MCP.Permissions = &ai.MCPPermissions{}
+				}
+				if parts[1] == "audit_log" {
+					config.Claude.MCP.Permissions.AuditLog = value
+					break
+				}
+				switch value {
+				case ai.FileAccessAsk, ai.FileAccessAllow, ai.FileAccessDeny:
+					config.Claude.MCP.Permissions.FileAccess = value
+				default:
+					return fmt.Errorf("file_access must be ask, allow or deny")
+				}
 			default:
 				return fmt.Errorf("unknown claude setting: %s", parts[1])
 			}
@@ -400,6 +522,50 @@ func updateConfig(key, value string) error {
 				return fmt.Errorf("unknown ollama setting: %s", parts[1])
 			}
 		}
+	case "openai":
+		if config.OpenAI == nil {
+			config.OpenAI = &ai.OpenAIConfig{}
+		}
+		if len(parts) > 1 {
+			switch parts[1] {
+			case "base_url":
+				config.OpenAI.BaseURL = value
+			case "api_key":
+				config.OpenAI.APIKey = value
+			case "model":
+				config.OpenAI.Model = value
+			case "temperature":
+				fmt.Sscanf(value, "%f", &config.OpenAI.Temperature)
+			case "timeout":
+				fmt.Sscanf(value, "%d", &config.OpenAI.Timeout)
+			default:
+				return fmt.Errorf("unknown openai setting: %s", parts[1])
+			}
+		}
+	case "anthropic":
+		if config.Anthropic == nil {
+			config.Anthropic = &ai.AnthropicConfig{}
+		}
+		if len(parts) > 1 {
+			switch parts[1] {
+			case "base_url":
+				config.Anthropic.BaseURL = value
+			case "api_key":
+				config.Anthropic.APIKey = value
+			case "model":
+				config.Anthropic.Model = value
+			case "max_tokens":
+				fmt.Sscanf(value, "%d", &config.Anthropic.MaxTokens)
+			case "temperature":
+				fmt.Sscanf(value, "%f", &config.Anthropic.Temperature)
+			case "timeout":
+				fmt.Sscanf(value, "%d", &config.Anthropic.Timeout)
+			case "max_retries":
+				fmt.Sscanf(value, "%d", &config.Anthropic.MaxRetries)
+			default:
+				return fmt.Errorf("unknown anthropic setting: %s", parts[1])
+			}
+		}
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
@@ -496,6 +662,7 @@ func startAIChat(prob *problem.Problem) {
 		return
 	}
 
+	ai.SetWorkspace(agent, problemWorkspace(prob))
 	repl := ai.NewREPL(agent)
 	ctx := context.Background()
 	if err := repl.Start(ctx, prob); err != nil {
@@ -514,7 +681,7 @@ func reviewCode(prob *problem.Problem, code string, language string) {
 	fmt.Println("🔍 Reviewing your code...")
 
 	ctx := context.Background()
-	reviewChan, err := agent.ReviewCode(ctx, *prob, code)
+	reviewChan, err := agent.ReviewCode(ctx, *prob, codefmt.Code(language, code))
 	if err != nil {
 		fmt.Printf("Error reviewing code: %v\n", err)
 		return
@@ -529,7 +696,7 @@ func reviewCode(prob *problem.Problem, code string, language string) {
 	fmt.Println(formatter.FormatCodeReview(fullReview.String()))
 }
 
-func startAIRepl(problemID, language, provider string) {
+func startAIRepl(problemID, language, provider string, mode ai.REPLMode, turns int) {
 	ctx := context.Background()
 	
 	// Load AI configuration
@@ -547,6 +714,10 @@ func startAIRepl(problemID, language, provider string) {
 			aiProvider = ai.ProviderClaude
 		case "ollama":
 			aiProvider = ai.ProviderOllama
+		case "openai":
+			aiProvider = ai.ProviderOpenAI
+		case "anthropic":
+			aiProvider = ai.ProviderAnthropic
 		default:
 			fmt.Printf("Unsupported provider: %s\n", provider)
 			return
@@ -557,6 +728,10 @@ func startAIRepl(problemID, language, provider string) {
 			aiProvider = ai.ProviderClaude
 		case "ollama":
 			aiProvider = ai.ProviderOllama
+		case "openai":
+			aiProvider = ai.ProviderOpenAI
+		case "anthropic":
+			aiProvider = ai.ProviderAnthropic
 		default:
 			fmt.Println("No valid default provider configured")
 			return
@@ -580,8 +755,16 @@ func startAIRepl(problemID, language, provider string) {
 		}
 	}
 	
+	if mode == ai.InterviewerMode && prob == nil {
+		fmt.Printf("Problem not found: %s\n", problemID)
+		return
+	}
+	if prob != nil {
+		ai.SetWorkspace(agent, problemWorkspace(prob))
+	}
+
 	// Start interactive REPL
-	repl := ai.NewREPL(agent)
+	repl := ai.NewREPL(agent).WithMode(mode).WithTurnBudget(turns)
 	
 	fmt.Printf("🤖 AI Assistant Ready! Provider: %s\n", aiProvider)
 	if prob != nil {
@@ -598,4 +781,6 @@ func init() {
 	// Add flags to review command
 	reviewCmd.Flags().Bool("ai", true, "Use AI for code review")
 	reviewCmd.Flags().StringP("problem", "p", "", "Problem ID to review against")
+	reviewCmd.Flags().Bool("all", false, "Show every scheduled review, not just those due")
+	reviewCmd.Flags().Bool("start", false, "Start a practice session on the most overdue review")
 }
\ No newline at end of file
@@ -0,0 +1,125 @@
+// File access for the AI: the confined file tools' permissions and audit log
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/mcp"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// askFileAccess asks whether the AI may read the files in a workspace for
+// the rest of the session
+// Exported as variable for testing
+var askFileAccess = func(in io.Reader, out io.Writer, dir string) bool {
+	fmt.Fprintf(out, "The AI can read your files in %s to help with this problem.\n", dir)
+	fmt.Fprint(out, "Allow it for this session? Every file it reads is logged. [y/N]: ")
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.TrimSpace(response)
+	return response == "y" || response == "Y"
+}
+
+// problemWorkspace is the workspace the AI may read while helping with a
+// problem, asking on the terminal before it reads anything
+func problemWorkspace(prob *problem.Problem) ai.Workspace {
+	return ai.Workspace{
+		Dir:     session.WorkspaceDir(prob.ID),
+		Session: fmt.Sprintf("%s-%s", prob.ID, time.Now().Format("20060102-150405")),
+		Ask: func(dir string) bool {
+			return askFileAccess(os.Stdin, os.Stdout, dir)
+		},
+	}
+}
+
+// aiAuditLogPath returns the configured audit log of the AI's file reads
+// Exported as variable for testing
+var aiAuditLogPath = func() string {
+	if !ai.Configured() {
+		return ai.DefaultAuditLogPath()
+	}
+	config, err := ai.LoadConfig()
+	if err != nil || config.Claude == nil {
+		return ai.DefaultAuditLogPath()
+	}
+	return config.Claude.MCP.AuditLogPath()
+}
+
+// aiAuditCmd lists the files the AI has read
+var aiAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the files the AI has read",
+	Long: `Show the audit log of the AI's file tools: every file it read or listed,
+when, and in which session, and every request it was refused.
+
+The AI's file tools can only reach the current problem's workspace, and
+only after you allow it once per session. Set claude.file_access to ask,
+allow or deny with 'algo-scales ai config set'.
+
+Examples:
+  algo-scales ai audit
+  algo-scales ai audit --session two_sum-20250101-093000
+  algo-scales ai audit --limit 0`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sessionFilter, _ := cmd.Flags().GetString("session")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		path := aiAuditLogPath()
+		entries, err := mcp.ReadAudit(path)
+		if err != nil {
+			commandError(cmd, "reading the audit log", err)
+			return
+		}
+		if sessionFilter != "" {
+			var matching []mcp.AuditEntry
+			for _, entry := range entries {
+				if entry.Session == sessionFilter {
+					matching = append(matching, entry)
+				}
+			}
+			entries = matching
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if jsonOutput(cmd) {
+			if entries == nil {
+				entries = []mcp.AuditEntry{}
+			}
+			writeJSON(cmd, entries)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		if len(entries) == 0 {
+			fmt.Fprintf(out, "The AI has not read any files. The log is kept in %s\n", path)
+			return
+		}
+		for _, entry := range entries {
+			fmt.Fprintf(out, "%s  %-24s %-14s %s", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Session, entry.Tool, entry.Path)
+			switch {
+			case entry.Denied:
+				fmt.Fprintf(out, "  DENIED: %s", entry.Reason)
+			case entry.Bytes > 0:
+				fmt.Fprintf(out, "  (%d bytes)", entry.Bytes)
+			}
+			fmt.Fprintln(out)
+		}
+	},
+}
+
+func init() {
+	aiCmd.AddCommand(aiAuditCmd)
+
+	aiAuditCmd.Flags().String("session", "", "Only show one session's entries")
+	aiAuditCmd.Flags().Int("limit", 50, "Show only the latest entries (0 for all)")
+}
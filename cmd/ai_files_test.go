@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	original := aiAuditLogPath
+	aiAuditLogPath = func() string { return path }
+	defer func() { aiAuditLogPath = original }()
+
+	output, err := executeCommand(rootCmd, "ai", "audit", "--session", "", "--limit", "50")
+	require.NoError(t, err)
+	assert.Contains(t, output, "The AI has not read any files")
+
+	log := mcp.NewAuditLog(path)
+	now := time.Now()
+	require.NoError(t, log.Record(mcp.AuditEntry{Time: now, Session: "two_sum-1", Tool: "read_file", Path: "/tmp/algo-scales/two_sum/solution.go", Bytes: 120}))
+	require.NoError(t, log.Record(mcp.AuditEntry{Time: now, Session: "two_sum-1", Tool: "read_file", Path: "../../.ssh/id_rsa", Denied: true, Reason: "outside the workspace"}))
+	require.NoError(t, log.Record(mcp.AuditEntry{Time: now, Session: "coin_change-1", Tool: "list_directory", Path: "/tmp/algo-scales/coin_change"}))
+
+	output, err = executeCommand(rootCmd, "ai", "audit", "--session", "two_sum-1", "--limit", "50")
+	require.NoError(t, err)
+	assert.Contains(t, output, "solution.go  (120 bytes)")
+	assert.Contains(t, output, "id_rsa  DENIED: outside the workspace")
+	assert.NotContains(t, output, "coin_change")
+
+	output, err = executeCommand(rootCmd, "ai", "audit", "--session", "", "--limit", "1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(strings.TrimSpace(output), "\n")+1, "only the latest entry")
+	assert.Contains(t, output, "coin_change")
+}
+
+func TestAskFileAccess(t *testing.T) {
+	var out bytes.Buffer
+	assert.True(t, askFileAccess(strings.NewReader("y\n"), &out, "/tmp/algo-scales/two_sum"))
+	assert.Contains(t, out.String(), "/tmp/algo-scales/two_sum")
+	assert.False(t, askFileAccess(strings.NewReader("\n"), &out, "/tmp/algo-scales/two_sum"), "no by default")
+}
+
+func TestAIAuditJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	original := aiAuditLogPath
+	aiAuditLogPath = func() string { return path }
+	defer func() { aiAuditLogPath = original }()
+
+	require.NoError(t, mcp.NewAuditLog(path).Record(mcp.AuditEntry{Time: time.Now(), Tool: "read_file", Path: "solution.go", Bytes: 3}))
+
+	output, code := executeJSONCommand(t, "ai", "audit", "--session", "", "--limit", "50")
+	require.Equal(t, 0, code)
+	var entries []mcp.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(output), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "solution.go", entries[0].Path)
+}
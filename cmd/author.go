@@ -0,0 +1,341 @@
+// Author command with tools for problem pack authors
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/problemdir"
+	"github.com/spf13/cobra"
+)
+
+// minCalibrationAttempts is the sample size below which no verdict is given
+const minCalibrationAttempts = 10
+
+// solveRateBands are the solve rates expected for each difficulty label
+var solveRateBands = map[string][2]float64{
+	"easy":   {0.70, 1.00},
+	"medium": {0.40, 0.85},
+	"hard":   {0.15, 0.60},
+}
+
+// authorCmd represents the author command
+var authorCmd = &cobra.Command{
+	Use:   "author",
+	Short: "Tools for problem pack authors",
+	Long: `Tools that help problem pack authors create and calibrate problems.
+
+Examples:
+  algo-scales author new pair_sum --pattern two-pointers
+  algo-scales author lint pair_sum
+  algo-scales author preview pair_sum`,
+}
+
+// editProblemFile opens a new problem's file in the user's editor,
+// returning once it closes
+// Exported as variable for testing
+var editProblemFile = openEditor
+
+// previewProblem shows a problem in the preview screen
+// Exported as variable for testing
+var previewProblem = runAuthorPreview
+
+// authorLintReport is what 'author lint --output json' prints
+type authorLintReport struct {
+	Problems int               `json:"problems"` // Problems without schema errors
+	Issues   []authorLintIssue `json:"issues"`
+}
+
+// authorLintIssue is one issue as 'author lint --output json' describes it
+type authorLintIssue struct {
+	ProblemID string `json:"problem_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// authorStatsCmd represents the stats subcommand for author
+var authorStatsCmd = &cobra.Command{
+	Use:   "stats [problem-id]",
+	Short: "Show how a problem performs for opted-in users",
+	Long: `Fetch aggregate solve rate, median solve time and the most common
+failing tests for a problem, and check whether its difficulty and
+estimated time are well-calibrated.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		ps, err := api.GetProblemStats(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error fetching problem stats: %v\n", err)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Problem: %s\n", ps.ProblemID)
+		fmt.Fprintf(out, "Attempts: %d\n", ps.Attempts)
+		fmt.Fprintf(out, "Solve Rate: %.1f%%\n", ps.SolveRate*100)
+		fmt.Fprintf(out, "Median Solve Time: %s\n", formatDuration(time.Duration(ps.MedianSolveSeconds)*time.Second))
+		if len(ps.CommonFailingTests) > 0 {
+			fmt.Fprintln(out, "Common Failing Tests:")
+			for _, ft := range ps.CommonFailingTests {
+				fmt.Fprintf(out, "  Test %d: %d failures\n", ft.Test, ft.Failures)
+			}
+		}
+
+		// Calibration needs the local definition for its labels
+		prob, err := problem.GetByID(args[0])
+		if err != nil {
+			fmt.Fprintf(out, "\nProblem not found locally; skipping calibration (%v)\n", err)
+			return
+		}
+
+		fmt.Fprintln(out, "\nCalibration:")
+		for _, note := range calibrationNotes(*prob, *ps) {
+			fmt.Fprintf(out, "  • %s\n", note)
+		}
+	},
+}
+
+// authorEditCmd represents the edit subcommand for author
+var authorEditCmd = &cobra.Command{
+	Use:   "edit <file>",
+	Short: "Write or edit a problem in an interactive screen",
+	Long: `Open a problem file (JSON or YAML) in an interactive authoring screen.
+Fields are edited on the left; the right shows a live preview of the
+rendered statement or of the test harness generated for each language.
+Validation errors appear inline as you type, and ctrl+t runs the reference
+solution against the test cases. The file is created on first save if it
+does not exist.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAuthoring(args[0]); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		}
+	},
+}
+
+// authorNewCmd represents the new subcommand for author
+var authorNewCmd = &cobra.Command{
+	Use:   "new <id>",
+	Short: "Scaffold a new problem directory",
+	Long: `Create a directory named after the problem with a problem.yaml to fill
+in, and starter code and reference solution stubs for each language written
+from the function signature: starter.<ext> and solution.<ext>. Test cases
+are kept in problem.yaml. The new problem.yaml is opened in $EDITOR unless
+--edit=false is given.
+
+A problem directory can be checked with 'author lint', shown as solvers
+will see it with 'author preview', and have its reference solutions run
+with 'validate-pack'. Import it, or the pack directory holding it, with
+'algo-scales import'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		parent, _ := cmd.Flags().GetString("dir")
+		title, _ := cmd.Flags().GetString("title")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		difficulty, _ := cmd.Flags().GetString("difficulty")
+		signature, _ := cmd.Flags().GetString("signature")
+		languages, _ := cmd.Flags().GetStringSlice("languages")
+		edit, _ := cmd.Flags().GetBool("edit")
+
+		sig, err := problem.ParseSignature(signature)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: invalid --signature: %v\n", err)
+			return
+		}
+		files, err := problemdir.Scaffold(parent, problemdir.Options{
+			ID:         args[0],
+			Title:      title,
+			Pattern:    pattern,
+			Difficulty: difficulty,
+			Signature:  sig,
+			Languages:  languages,
+		})
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error creating problem: %v\n", err)
+			return
+		}
+
+		dir := filepath.Join(parent, args[0])
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "%s Created %s:\n", symbols.Check, dir)
+		for _, file := range files {
+			fmt.Fprintf(out, "  %s\n", file)
+		}
+		if edit {
+			editProblemFile(filepath.Join(dir, problem.ProblemFile))
+		}
+
+		fmt.Fprintf(out, "\nFill in every %s, then check the problem with:\n", problemdir.Placeholder)
+		fmt.Fprintf(out, "  algo-scales author lint %s\n", dir)
+		fmt.Fprintf(out, "  algo-scales author preview %s\n", dir)
+		fmt.Fprintf(out, "  algo-scales validate-pack %s\n", dir)
+	},
+}
+
+// authorLintCmd represents the lint subcommand for author
+var authorLintCmd = &cobra.Command{
+	Use:   "lint <directory|file>",
+	Short: "Check a problem or pack for what is left to write",
+	Long: `Check a problem directory, problem file or pack before publishing it.
+Schema errors are reported as file:line: message, then what the schema
+allows but a finished problem should not have: TODO placeholders left in,
+a missing or unusable signature, no examples, only example tests, and
+languages with starter code but no reference solution or the other way
+round. No code is run; 'validate-pack' runs the reference solutions.
+
+With --output json, a problem with issues exits with status 2.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		report, err := problemdir.Lint(args[0])
+		if err != nil {
+			commandError(cmd, "reading problems", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			result := authorLintReport{Problems: len(report.Problems), Issues: []authorLintIssue{}}
+			for _, issue := range report.Issues {
+				result.Issues = append(result.Issues, authorLintIssue{ProblemID: issue.ProblemID, Message: issue.Message})
+			}
+			writeJSON(cmd, result)
+			if len(report.Issues) > 0 {
+				exit(exitTestsFailed)
+			}
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		if len(report.Issues) == 0 {
+			fmt.Fprintf(out, "%s No issues found in %d problem(s).\n", symbols.Check, len(report.Problems))
+			return
+		}
+		for _, issue := range report.Issues {
+			fmt.Fprintf(out, "%s %s\n", symbols.Cross, issue)
+		}
+		fmt.Fprintf(out, "\n%d issue(s) to fix before publishing.\n", len(report.Issues))
+	},
+}
+
+// authorPreviewCmd represents the preview subcommand for author
+var authorPreviewCmd = &cobra.Command{
+	Use:   "preview <directory|file>",
+	Short: "Show a problem as solvers will see it",
+	Long: `Open a problem directory or file in a read-only screen showing the
+statement as a practice session renders it, the starter code and reference
+solution in each language, and the issues 'author lint' finds. A pack with
+more than one problem needs --problem to pick one.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("problem")
+
+		report, err := problemdir.Lint(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error reading problems: %v\n", err)
+			return
+		}
+		p, err := previewTarget(report.Problems, id)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return
+		}
+
+		var issues []string
+		for _, issue := range report.Issues {
+			if issue.ProblemID == "" || issue.ProblemID == p.ID {
+				issues = append(issues, issue.Message)
+			}
+		}
+		if err := previewProblem(p, issues); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		}
+	},
+}
+
+// previewTarget picks the problem to preview: the one with the given ID,
+// or the only one read
+func previewTarget(problems []problem.Problem, id string) (problem.Problem, error) {
+	ids := make([]string, len(problems))
+	for i, p := range problems {
+		if p.ID == id {
+			return p, nil
+		}
+		ids[i] = p.ID
+	}
+	switch {
+	case len(problems) == 0:
+		return problem.Problem{}, fmt.Errorf("no problem could be read; run 'algo-scales author lint' for the errors")
+	case id != "":
+		return problem.Problem{}, fmt.Errorf("no problem %q; found %s", id, strings.Join(ids, ", "))
+	case len(problems) > 1:
+		return problem.Problem{}, fmt.Errorf("%d problems found; pick one with --problem: %s", len(problems), strings.Join(ids, ", "))
+	}
+	return problems[0], nil
+}
+
+// calibrationNotes compares aggregate stats with a problem's difficulty and time estimate
+func calibrationNotes(prob problem.Problem, ps api.ProblemStats) []string {
+	if ps.Attempts < minCalibrationAttempts {
+		return []string{fmt.Sprintf("Not enough data yet (%d of %d attempts needed)", ps.Attempts, minCalibrationAttempts)}
+	}
+
+	var notes []string
+
+	if band, ok := solveRateBands[strings.ToLower(prob.Difficulty)]; ok {
+		switch {
+		case ps.SolveRate < band[0]:
+			notes = append(notes, fmt.Sprintf("Solve rate %.0f%% is low for %s; consider a harder label or clearer statement", ps.SolveRate*100, prob.Difficulty))
+		case ps.SolveRate > band[1]:
+			notes = append(notes, fmt.Sprintf("Solve rate %.0f%% is high for %s; consider an easier label", ps.SolveRate*100, prob.Difficulty))
+		}
+	}
+
+	if prob.EstimatedTime > 0 && ps.MedianSolveSeconds > 0 {
+		ratio := float64(ps.MedianSolveSeconds) / float64(prob.EstimatedTime*60)
+		switch {
+		case ratio > 1.5:
+			notes = append(notes, fmt.Sprintf("Median solve time is %.1fx the %d minute estimate; consider raising estimated_time", ratio, prob.EstimatedTime))
+		case ratio < 0.5:
+			notes = append(notes, fmt.Sprintf("Median solve time is %.1fx the %d minute estimate; consider lowering estimated_time", ratio, prob.EstimatedTime))
+		}
+	}
+
+	// A single test failing in most unsuccessful attempts usually means it is unclear
+	failed := int(float64(ps.Attempts) * (1 - ps.SolveRate))
+	for _, ft := range ps.CommonFailingTests {
+		if failed > 0 && ft.Failures*2 > failed {
+			notes = append(notes, fmt.Sprintf("Test %d fails in most unsuccessful attempts; check its input and expected output are clear", ft.Test))
+		}
+	}
+
+	if len(notes) == 0 {
+		notes = append(notes, "Well-calibrated")
+	}
+	return notes
+}
+
+func init() {
+	rootCmd.AddCommand(authorCmd)
+	authorCmd.AddCommand(authorStatsCmd)
+	authorCmd.AddCommand(authorEditCmd)
+	authorCmd.AddCommand(authorNewCmd)
+	authorCmd.AddCommand(authorLintCmd)
+	authorCmd.AddCommand(authorPreviewCmd)
+
+	authorNewCmd.Flags().String("dir", ".", "Directory to create the problem directory in")
+	authorNewCmd.Flags().String("title", "", "Problem title (default: derived from the id)")
+	authorNewCmd.Flags().StringP("pattern", "p", "", "Algorithm pattern the problem practices")
+	authorNewCmd.Flags().StringP("difficulty", "d", "easy", "Difficulty (easy, medium, hard)")
+	authorNewCmd.Flags().String("signature", problemdir.DefaultSignature, "Function signature, e.g. \"twoSum(nums int[], target int) int[]\"")
+	authorNewCmd.Flags().StringSlice("languages", problemdir.DefaultLanguages, "Languages to write starter code and solution stubs in")
+	authorNewCmd.Flags().Bool("edit", true, "Open problem.yaml in $EDITOR")
+
+	authorPreviewCmd.Flags().String("problem", "", "ID of the problem to preview, for packs with several")
+}
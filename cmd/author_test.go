@@ -0,0 +1,147 @@
+// Tests for author command
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalibrationNotes(t *testing.T) {
+	prob := problem.Problem{ID: "two-sum", Difficulty: "Easy", EstimatedTime: 15}
+
+	t.Run("NotEnoughData", func(t *testing.T) {
+		notes := calibrationNotes(prob, api.ProblemStats{Attempts: 3})
+		require.Len(t, notes, 1)
+		assert.Contains(t, notes[0], "Not enough data")
+	})
+
+	t.Run("WellCalibrated", func(t *testing.T) {
+		notes := calibrationNotes(prob, api.ProblemStats{Attempts: 20, SolveRate: 0.8, MedianSolveSeconds: 12 * 60})
+		assert.Equal(t, []string{"Well-calibrated"}, notes)
+	})
+
+	t.Run("Miscalibrated", func(t *testing.T) {
+		notes := calibrationNotes(prob, api.ProblemStats{
+			Attempts:           20,
+			SolveRate:          0.5,
+			MedianSolveSeconds: 40 * 60,
+			CommonFailingTests: []api.FailingTest{{Test: 2, Failures: 8}, {Test: 1, Failures: 1}},
+		})
+		require.Len(t, notes, 3)
+		assert.Contains(t, notes[0], "low for Easy")
+		assert.Contains(t, notes[1], "raising estimated_time")
+		assert.Contains(t, notes[2], "Test 2")
+	})
+}
+
+func TestAuthorNew(t *testing.T) {
+	var edited string
+	original := editProblemFile
+	editProblemFile = func(path string) { edited = path }
+	t.Cleanup(func() { editProblemFile = original })
+
+	parent := t.TempDir()
+	output, err := executeCommand(rootCmd, "author", "new", "pair_sum", "--dir", parent,
+		"--pattern", "two-pointers", "--signature", "pairSum(nums int[], target int) int[]", "--languages", "go,python", "--edit")
+	require.NoError(t, err)
+	dir := filepath.Join(parent, "pair_sum")
+	assert.Contains(t, output, symbols.Check.String()+" Created "+dir)
+	assert.Contains(t, output, filepath.Join(dir, "solution.py"))
+	assert.Contains(t, output, "algo-scales author lint "+dir)
+	assert.Equal(t, filepath.Join(dir, "problem.yaml"), edited)
+
+	starter, err := os.ReadFile(filepath.Join(dir, "starter.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(starter), "func pairSum(nums []int, target int) []int {")
+
+	output, err = executeCommand(rootCmd, "author", "new", "pair_sum", "--dir", parent, "--edit=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Error creating problem:")
+	assert.Contains(t, output, "already exists")
+
+	output, err = executeCommand(rootCmd, "author", "new", "other", "--dir", parent, "--signature", "nope", "--edit=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Error: invalid --signature")
+}
+
+func TestAuthorLint(t *testing.T) {
+	parent := t.TempDir()
+	_, err := executeCommand(rootCmd, "author", "new", "pair_sum", "--dir", parent,
+		"--pattern", "two-pointers", "--signature", "solve(nums int[]) int", "--languages", "go", "--edit=false")
+	require.NoError(t, err)
+	dir := filepath.Join(parent, "pair_sum")
+
+	output, err := executeCommand(rootCmd, "author", "lint", dir)
+	require.NoError(t, err)
+	assert.Contains(t, output, symbols.Cross.String()+" pair_sum: description still has TODO placeholders")
+	assert.Contains(t, output, "pair_sum: go reference solution still has TODO placeholders")
+	assert.Contains(t, output, "issue(s) to fix before publishing")
+
+	twoSum, err := problem.GetByID("two_sum")
+	require.NoError(t, err)
+	twoSum.TestCases[len(twoSum.TestCases)-1].Tier = interfaces.TierHidden
+	data, err := json.Marshal(twoSum)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "two_sum.json")
+	require.NoError(t, os.WriteFile(file, data, 0644))
+
+	output, err = executeCommand(rootCmd, "author", "lint", file)
+	require.NoError(t, err)
+	assert.Contains(t, output, symbols.Check.String()+" No issues found in 1 problem(s).")
+
+	output, code := executeJSONCommand(t, "author", "lint", dir)
+	assert.Equal(t, exitTestsFailed, code)
+	var report authorLintReport
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.Equal(t, 1, report.Problems)
+	require.NotEmpty(t, report.Issues)
+	assert.Equal(t, "pair_sum", report.Issues[0].ProblemID)
+}
+
+func TestAuthorPreview(t *testing.T) {
+	var previewed problem.Problem
+	var previewIssues []string
+	original := previewProblem
+	previewProblem = func(p problem.Problem, issues []string) error {
+		previewed, previewIssues = p, issues
+		return nil
+	}
+	t.Cleanup(func() { previewProblem = original })
+
+	parent := t.TempDir()
+	for _, id := range []string{"pair_sum", "triple_sum"} {
+		_, err := executeCommand(rootCmd, "author", "new", id, "--dir", parent,
+			"--pattern", "two-pointers", "--signature", "solve(nums int[]) int", "--languages", "go", "--edit=false")
+		require.NoError(t, err)
+	}
+
+	output, err := executeCommand(rootCmd, "author", "preview", filepath.Join(parent, "pair_sum"), "--problem", "")
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(output))
+	assert.Equal(t, "pair_sum", previewed.ID)
+	assert.Contains(t, previewed.StarterCode["go"], "func solve(nums []int) int {")
+	assert.Contains(t, previewIssues, "description still has TODO placeholders")
+
+	output, err = executeCommand(rootCmd, "author", "preview", parent, "--problem", "")
+	require.NoError(t, err)
+	assert.Contains(t, output, "2 problems found; pick one with --problem: pair_sum, triple_sum")
+
+	_, err = executeCommand(rootCmd, "author", "preview", parent, "--problem", "triple_sum")
+	require.NoError(t, err)
+	assert.Equal(t, "triple_sum", previewed.ID)
+
+	output, err = executeCommand(rootCmd, "author", "preview", parent, "--problem", "missing")
+	require.NoError(t, err)
+	assert.Contains(t, output, `no problem "missing"; found pair_sum, triple_sum`)
+}
@@ -0,0 +1,66 @@
+// Benchmark of passing solutions against the reference on large inputs
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/bench"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+// runBench times a solution against the reference on large generated inputs
+// Exported as variable for testing
+var runBench = func(prob problem.Problem, language, code string) (bench.Report, error) {
+	return bench.Run(context.Background(), prob, language, code)
+}
+
+// VimBench is the benchmark in a vim submit response
+type VimBench struct {
+	Inputs      int     `json:"inputs"`
+	UserMs      float64 `json:"user_ms"`
+	ReferenceMs float64 `json:"reference_ms"`
+	Ratio       float64 `json:"ratio,omitempty"`
+	Slow        bool    `json:"slow"`
+	TimedOut    bool    `json:"timed_out,omitempty"`
+	Mismatches  int     `json:"mismatches,omitempty"`
+	Summary     string  `json:"summary,omitempty"`
+	Error       string  `json:"error,omitempty"` // Set when the benchmark could not run
+}
+
+// newVimBench converts a benchmark report for the vim plugin
+func newVimBench(r bench.Report, err error) *VimBench {
+	if err != nil {
+		return &VimBench{Error: err.Error()}
+	}
+	return &VimBench{
+		Inputs:      r.Inputs,
+		UserMs:      float64(r.User.Microseconds()) / 1000,
+		ReferenceMs: float64(r.Reference.Microseconds()) / 1000,
+		Ratio:       r.Ratio,
+		Slow:        r.Slow,
+		TimedOut:    r.TimedOut,
+		Mismatches:  r.Mismatches,
+		Summary:     r.Summary(),
+	}
+}
+
+// writeBenchReport prints how a solution's time on large inputs compares
+// with the reference's
+func writeBenchReport(out io.Writer, r bench.Report, err error) {
+	fmt.Fprintln(out, "\n--- Benchmark ---")
+	if err != nil {
+		fmt.Fprintf(out, "Could not benchmark your solution: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "Your solution: %s on %d large inputs\n", execution.FormatDuration(r.User), r.Inputs)
+	fmt.Fprintf(out, "Reference:     %s\n", execution.FormatDuration(r.Reference))
+	if r.Slow {
+		fmt.Fprintf(out, "%s Correct but slow\n", symbols.Warning)
+	}
+	fmt.Fprintln(out, r.Summary())
+}
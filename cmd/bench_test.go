@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/bench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteBenchReport(t *testing.T) {
+	report := bench.Report{Inputs: 3, User: 2 * time.Second, Reference: 40 * time.Millisecond, Ratio: 50, Slow: true}
+
+	var out bytes.Buffer
+	writeBenchReport(&out, report, nil)
+	assert.Contains(t, out.String(), "--- Benchmark ---")
+	assert.Contains(t, out.String(), "Your solution: 2.00s on 3 large inputs")
+	assert.Contains(t, out.String(), "Reference:     40ms")
+	assert.Contains(t, out.String(), "Correct but slow")
+	assert.Contains(t, out.String(), "50.0x the reference's 40ms")
+
+	out.Reset()
+	writeBenchReport(&out, bench.Report{}, errors.New("problem two_sum has no reference solution in rust"))
+	assert.Contains(t, out.String(), "Could not benchmark your solution: problem two_sum has no reference solution in rust")
+	assert.NotContains(t, out.String(), "Your solution:")
+
+	vim := newVimBench(report, nil)
+	assert.Equal(t, 2000.0, vim.UserMs)
+	assert.Equal(t, 40.0, vim.ReferenceMs)
+	assert.True(t, vim.Slow)
+	assert.NotEmpty(t, vim.Summary)
+	assert.Equal(t, "no toolchain", newVimBench(report, errors.New("no toolchain")).Error)
+}
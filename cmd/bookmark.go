@@ -0,0 +1,253 @@
+// bookmark command, for starring problems to come back to
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// bookmarkEntry is a bookmark as 'bookmark --output json' describes it
+type bookmarkEntry struct {
+	problemSummary
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+	Note         string    `json:"note,omitempty"`
+}
+
+// bookmarkResult is what bookmark add and remove report
+type bookmarkResult struct {
+	ProblemID  string `json:"problem_id"`
+	Bookmarked bool   `json:"bookmarked"`
+	Changed    bool   `json:"changed"` // False if the problem was already in that state
+}
+
+// startPractice opens a practice session on a problem
+// Exported as variable for testing
+var startPractice = func(cmd *cobra.Command, id string) {
+	practiceCmd.Run(cmd, []string{id})
+}
+
+// bookmarkCmd lists the bookmarked problems
+var bookmarkCmd = &cobra.Command{
+	Use:     "bookmark",
+	Aliases: []string{"bookmarks"},
+	Short:   "Star problems to come back to, and practice them",
+	Long: `List the problems you bookmarked, numbered in the order you added them. Use
+bookmarks for a personal set of problems to redo, such as the ones to go over
+again before an interview. Press * in a TUI session to bookmark its problem.
+
+Examples:
+  algo-scales bookmark add lru_cache --note "redo before the onsite"
+  algo-scales bookmark
+  algo-scales bookmark start 1
+  algo-scales bookmark remove lru_cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := loadBookmarkEntries()
+		if err != nil {
+			commandError(cmd, "listing bookmarks", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string][]bookmarkEntry{"bookmarks": entries})
+			return
+		}
+		printBookmarks(cmd.OutOrStdout(), entries)
+	},
+}
+
+// bookmarkAddCmd bookmarks a problem
+var bookmarkAddCmd = &cobra.Command{
+	Use:   "add <problem>",
+	Short: "Bookmark a problem",
+	Long: `Bookmark a problem, with an optional note on why. Bookmarking a problem
+again replaces its note.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		note, _ := cmd.Flags().GetString("note")
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "bookmarking the problem", err)
+			return
+		}
+		added, err := problem.AddBookmark(p.ID, note)
+		if err != nil {
+			commandError(cmd, "bookmarking the problem", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, bookmarkResult{ProblemID: p.ID, Bookmarked: true, Changed: added})
+			return
+		}
+		switch {
+		case added:
+			fmt.Fprintf(cmd.OutOrStdout(), "Bookmarked %s. Practice it with: algo-scales bookmark start %s\n", p.ID, p.ID)
+		case note != "":
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is already bookmarked; its note is now %q.\n", p.ID, note)
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is already bookmarked.\n", p.ID)
+		}
+	},
+}
+
+// bookmarkRemoveCmd removes a problem's bookmark
+var bookmarkRemoveCmd = &cobra.Command{
+	Use:     "remove <problem|number>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a bookmark",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Problems removed since they were bookmarked can still be unbookmarked
+		id, err := bookmarkedID(args[0])
+		if err != nil {
+			commandError(cmd, "removing the bookmark", err)
+			return
+		}
+		removed, err := problem.RemoveBookmark(id)
+		if err != nil {
+			commandError(cmd, "removing the bookmark", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, bookmarkResult{ProblemID: id, Bookmarked: false, Changed: removed})
+			return
+		}
+		if !removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is not bookmarked.\n", id)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed the bookmark on %s.\n", id)
+	},
+}
+
+// bookmarkStartCmd opens a practice session on a bookmarked problem
+var bookmarkStartCmd = &cobra.Command{
+	Use:   "start [problem|number]",
+	Short: "Practice a bookmarked problem",
+	Long: `Open a practice session on a bookmarked problem, named by its ID or by its
+number in 'algo-scales bookmark'. Without one, choose it in a finder over
+your bookmarks.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var id string
+		var err error
+		if len(args) == 1 {
+			id, err = bookmarkedID(args[0])
+		} else {
+			id, err = findBookmark()
+		}
+		if err != nil {
+			commandError(cmd, "starting a bookmarked problem", err)
+			return
+		}
+		if id == "" {
+			return
+		}
+		startPractice(cmd, id)
+	},
+}
+
+// loadBookmarkEntries describes each bookmark, with the problem it marks.
+// Problems no longer available are listed by ID alone.
+func loadBookmarkEntries() ([]bookmarkEntry, error) {
+	bookmarks, err := problem.LoadBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	problems, err := problem.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]problem.Problem, len(problems))
+	for _, p := range problems {
+		byID[p.ID] = p
+	}
+
+	entries := []bookmarkEntry{}
+	for _, b := range bookmarks {
+		p, ok := byID[b.ProblemID]
+		if !ok {
+			p = problem.Problem{ID: b.ProblemID}
+		}
+		entries = append(entries, bookmarkEntry{problemSummary: newProblemSummary(p), BookmarkedAt: b.BookmarkedAt, Note: b.Note})
+	}
+	return entries, nil
+}
+
+// bookmarkedID resolves a bookmark given by its number in the list, or
+// returns arg as the problem ID it is
+func bookmarkedID(arg string) (string, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return arg, nil
+	}
+	bookmarks, err := problem.LoadBookmarks()
+	if err != nil {
+		return "", err
+	}
+	if n < 1 || n > len(bookmarks) {
+		return "", fmt.Errorf("there is no bookmark %d; 'algo-scales bookmark' lists them", n)
+	}
+	return bookmarks[n-1].ProblemID, nil
+}
+
+// findBookmark lets the user choose one of the bookmarked problems in the
+// finder, returning "" if they cancel
+func findBookmark() (string, error) {
+	bookmarks, err := problem.LoadBookmarks()
+	if err != nil {
+		return "", err
+	}
+	if len(bookmarks) == 0 {
+		return "", fmt.Errorf("no bookmarks yet; add one with 'algo-scales bookmark add <problem>'")
+	}
+	problems, err := problem.ListAll()
+	if err != nil {
+		return "", err
+	}
+	byID := make(map[string]problem.Problem, len(problems))
+	for _, p := range problems {
+		byID[p.ID] = p
+	}
+	var choices []problem.Problem
+	for _, b := range bookmarks {
+		if p, ok := byID[b.ProblemID]; ok {
+			choices = append(choices, p)
+		}
+	}
+	return findProblem(choices, "")
+}
+
+// printBookmarks lists the bookmarks, numbered for 'bookmark start'
+func printBookmarks(w io.Writer, entries []bookmarkEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No bookmarks yet. Add one with: algo-scales bookmark add <problem>")
+		return
+	}
+	fmt.Fprintln(w, "Bookmarks:")
+	for i, e := range entries {
+		line := fmt.Sprintf("%2d. %s", i+1, e.ID)
+		if e.Title != "" {
+			line += fmt.Sprintf(" (%s): %s", e.Difficulty, e.Title)
+		}
+		if e.Note != "" {
+			line += " — " + e.Note
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w, "\nPractice one with: algo-scales bookmark start <number>")
+}
+
+func init() {
+	bookmarkAddCmd.Flags().String("note", "", "Why the problem is bookmarked")
+	bookmarkCmd.AddCommand(bookmarkAddCmd)
+	bookmarkCmd.AddCommand(bookmarkRemoveCmd)
+	bookmarkCmd.AddCommand(bookmarkStartCmd)
+	rootCmd.AddCommand(bookmarkCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmark(t *testing.T) {
+	stubUserData(t)
+	original := startPractice
+	t.Cleanup(func() { startPractice = original })
+	var started []string
+	startPractice = func(cmd *cobra.Command, id string) { started = append(started, id) }
+
+	output, err := executeCommand(rootCmd, "bookmark")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No bookmarks yet.")
+
+	output, err = executeCommand(rootCmd, "bookmark", "add", "two_sum", "--note=")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Bookmarked two_sum.")
+	output, err = executeCommand(rootCmd, "bookmark", "add", "two_sum", "--note", "redo before the onsite")
+	require.NoError(t, err)
+	assert.Contains(t, output, `two_sum is already bookmarked; its note is now "redo before the onsite".`)
+	_, err = executeCommand(rootCmd, "bookmark", "add", "coin_change", "--note=")
+	require.NoError(t, err)
+
+	output, err = executeCommand(rootCmd, "bookmark")
+	require.NoError(t, err)
+	assert.Contains(t, output, " 1. two_sum (")
+	assert.Contains(t, output, " — redo before the onsite")
+	assert.Contains(t, output, " 2. coin_change (")
+
+	// Bookmarks start by number or ID
+	_, err = executeCommand(rootCmd, "bookmark", "start", "2")
+	require.NoError(t, err)
+	_, err = executeCommand(rootCmd, "bookmark", "start", "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"coin_change", "two_sum"}, started)
+	output, err = executeCommand(rootCmd, "bookmark", "start", "3")
+	require.NoError(t, err)
+	assert.Contains(t, output, "there is no bookmark 3")
+
+	// Without one, the finder offers the bookmarked problems
+	originalFind := findProblem
+	t.Cleanup(func() { findProblem = originalFind })
+	var offered []string
+	findProblem = func(problems []problem.Problem, query string) (string, error) {
+		for _, p := range problems {
+			offered = append(offered, p.ID)
+		}
+		return "", nil
+	}
+	_, err = executeCommand(rootCmd, "bookmark", "start")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two_sum", "coin_change"}, offered)
+	assert.Len(t, started, 2, "cancelling the finder starts nothing")
+
+	output, err = executeCommand(rootCmd, "bookmark", "remove", "1")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Removed the bookmark on two_sum.")
+	output, err = executeCommand(rootCmd, "bookmark", "remove", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "two_sum is not bookmarked.")
+
+	output, err = executeCommand(rootCmd, "bookmark", "add", "no_such_problem", "--note=")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Error")
+}
+
+func TestBookmarkJSON(t *testing.T) {
+	stubUserData(t)
+
+	output, code := executeJSONCommand(t, "bookmark", "add", "two_sum", "--note", "hash map")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"problem_id":"two_sum","bookmarked":true,"changed":true}`, output)
+
+	output, code = executeJSONCommand(t, "bookmark")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, `"id":"two_sum"`)
+	assert.Contains(t, output, `"note":"hash map"`)
+}
@@ -0,0 +1,101 @@
+// Clarify command for answering clarifying questions about a problem
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// aiConfigured reports whether the AI assistant is set up
+// Exported as variable for testing
+var aiConfigured = ai.Configured
+
+// askClarifyingQuestion asks the AI assistant a question the problem does
+// not settle
+// Exported as variable for testing
+var askClarifyingQuestion = func(p problem.Problem, question string) (string, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return ai.Clarify(ctx, agent, p, question)
+}
+
+// clarifyCmd represents the clarify command
+var clarifyCmd = &cobra.Command{
+	Use:   "clarify <problem> [question]",
+	Short: "Answer clarifying questions about a problem",
+	Long: `Answer the questions worth asking an interviewer before solving a problem:
+how large the input can be, whether it can be empty, the range of its values,
+negatives, duplicates, ordering and whether an answer always exists.
+
+Answers come from the problem's statement and constraints. Questions they do
+not settle go to the AI assistant, if you have set one up.
+
+Ask your own question after the problem, for example:
+  algo-scales clarify two_sum "Can the same element be used twice?"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading problem: %v\n", err)
+			return
+		}
+		out := cmd.OutOrStdout()
+
+		if len(args) > 1 {
+			c := p.ClarifyQuestion(strings.Join(args[1:], " "))
+			if !c.Answered() {
+				answer, err := askClarifyingQuestion(*p, c.Question)
+				if err != nil {
+					fmt.Fprintln(out, "The problem does not settle this question.")
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error asking the AI assistant: %v\n", err)
+					fmt.Fprintln(cmd.ErrOrStderr(), "Run 'algo-scales ai config' to set up the AI assistant.")
+					return
+				}
+				c.Answer, c.Evidence = answer, []string{"AI assistant"}
+			}
+			writeClarification(out, c)
+			return
+		}
+
+		fmt.Fprintf(out, "Clarifying questions for %s:\n", p.Title)
+		useAI := aiConfigured()
+		for _, c := range p.Clarify() {
+			if !c.Answered() && useAI {
+				if answer, err := askClarifyingQuestion(*p, c.Question); err == nil {
+					c.Answer, c.Evidence = answer, []string{"AI assistant"}
+				}
+			}
+			writeClarification(out, c)
+		}
+	},
+}
+
+// writeClarification prints a question with its answer and where it came
+// from
+func writeClarification(out io.Writer, c problem.Clarification) {
+	fmt.Fprintf(out, "\nQ: %s\n", c.Question)
+	if !c.Answered() {
+		fmt.Fprintln(out, "A: Not stated in the problem. Ask your interviewer, or state your assumption.")
+		return
+	}
+	fmt.Fprintf(out, "A: %s\n", c.Answer)
+	if len(c.Evidence) > 0 {
+		fmt.Fprintf(out, "   From: %s\n", strings.Join(c.Evidence, "; "))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(clarifyCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubClarify serves a fixed problem and AI answers to the clarify command
+func stubClarify(t *testing.T, configured bool, answer string, err error) *[]string {
+	t.Helper()
+	originalGet, originalConfigured, originalAsk := problem.GetByID, aiConfigured, askClarifyingQuestion
+	t.Cleanup(func() {
+		problem.GetByID, aiConfigured, askClarifyingQuestion = originalGet, originalConfigured, originalAsk
+	})
+
+	problem.GetByID = func(id string) (*problem.Problem, error) {
+		return &problem.Problem{ID: id, Title: "Two Sum", Constraints: []string{"2 <= nums.length <= 10^4", "-10^9 <= nums[i] <= 10^9"}}, nil
+	}
+	aiConfigured = func() bool { return configured }
+	var asked []string
+	askClarifyingQuestion = func(p problem.Problem, question string) (string, error) {
+		asked = append(asked, question)
+		return answer, err
+	}
+	return &asked
+}
+
+func TestClarifyCommand(t *testing.T) {
+	t.Run("StandardQuestions", func(t *testing.T) {
+		asked := stubClarify(t, false, "", nil)
+
+		output, err := executeCommand(rootCmd, "clarify", "two_sum")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Clarifying questions for Two Sum:")
+		assert.Contains(t, output, "Q: Can the input be empty?\nA: No, nums.length is at least 2.\n   From: 2 <= nums.length <= 10^4")
+		assert.Contains(t, output, "Q: Is the input sorted?\nA: Not stated in the problem.")
+		assert.Empty(t, *asked, "the AI is not asked unless it is configured")
+	})
+
+	t.Run("AIFallback", func(t *testing.T) {
+		asked := stubClarify(t, true, "Assume it is not sorted.", nil)
+
+		output, err := executeCommand(rootCmd, "clarify", "two_sum")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Q: Is the input sorted?\nA: Assume it is not sorted.\n   From: AI assistant")
+		assert.NotContains(t, *asked, "Can the input be empty?")
+	})
+
+	t.Run("OwnQuestion", func(t *testing.T) {
+		asked := stubClarify(t, false, "No, each element is used once.", nil)
+
+		output, err := executeCommand(rootCmd, "clarify", "two_sum", "Can", "values", "be", "negative?")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "A: Yes, nums[i] can be negative.")
+
+		output, err = executeCommand(rootCmd, "clarify", "two_sum", "Can the same element be used twice?")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "A: No, each element is used once.")
+		assert.Equal(t, []string{"Can the same element be used twice?"}, *asked)
+	})
+
+	t.Run("AIUnavailable", func(t *testing.T) {
+		stubClarify(t, false, "", errors.New("no provider"))
+
+		output, err := executeCommand(rootCmd, "clarify", "two_sum", "Can the same element be used twice?")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "The problem does not settle this question.")
+		assert.Contains(t, output, "ai config")
+	})
+}
@@ -8,8 +8,16 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
 	"github.com/spf13/cobra"
 )
 
@@ -21,8 +29,8 @@ var cliCmd = &cobra.Command{
 1. Choose a problem (or specify one)
 2. View the problem statement
 3. Edit the solution code in your editor
-4. Test your solution
-5. Submit when ready`,
+4. Run the example tests
+5. Submit to run the hidden, edge and stress tests too`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var problemID string
 		if len(args) > 0 {
@@ -59,10 +67,10 @@ func init() {
 	rootCmd.AddCommand(cliCmd)
 
 	// Add flags to the cli command
-	cliCmd.Flags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript)")
+	cliCmd.Flags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript, typescript, rust, java, cpp, pseudo)")
 	cliCmd.Flags().IntVarP(&timer, "timer", "t", 45, "Timer duration in minutes (15, 30, 45, 60)")
 	cliCmd.Flags().StringVarP(&pattern, "pattern", "p", "", "Algorithm pattern to focus on")
-	cliCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard)")
+	cliCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard, or auto to follow recent performance)")
 }
 
 // runCliWorkflow handles the CLI problem-solving workflow
@@ -80,24 +88,59 @@ func runCliWorkflow(s *SessionAdapter) error {
 	fmt.Printf("Problem: %s (%s)\n", s.Problem.Title, s.Problem.Difficulty)
 	fmt.Printf("Pattern: %s\n", JoinStrings(s.Problem.Patterns))
 	fmt.Printf("Estimated Time: %d minutes\n\n", s.Problem.EstimatedTime)
+	if s.Reason != "" {
+		fmt.Printf("Why this problem: %s.\n\n", s.Reason)
+	}
+	if notes, _ := problem.LoadNotes(s.Problem.ID); notes != "" {
+		fmt.Printf("📝 Your notes on this problem:\n\n%s\n", notes)
+	}
+
+	// Warn if results from earlier attempts were recorded against a different problem revision
+	if warning := session.CheckProblemChanged(*s.Problem); warning != "" {
+		fmt.Println(warning)
+		fmt.Println()
+	}
 
 	// Path to files
 	descFile := filepath.Join(s.Workspace, "problem.md")
 	codeFile := s.CodeFile
 
+	// An easier problem of the same pattern, offered once the user is stuck
+	var easier *problem.Problem
+	offered := false
+
 	// Main interaction loop
 	for {
+		// Each action is kept for 'algo-scales resume', in case the user
+		// quits, and code saved outside the menu for 'algo-scales replay'
+		saveCliProgress(s)
+		recordCode(s)
+
+		if !offered && session.IsStuck(*s.Problem, sessionElapsed(s)) {
+			offered = true
+			easier, _ = session.FindEasierProblem(*s.Problem, "")
+			if easier != nil {
+				fmt.Printf("\n⏳ You're past %dx the estimate. Enter 's' to switch to %s (%s) if you're stuck.\n",
+					session.StuckFactor, easier.Title, easier.Difficulty)
+			}
+		}
+
 		// Display menu
 		fmt.Println("\nOptions:")
 		fmt.Println("1. View problem description")
 		fmt.Println("2. Edit solution")
-		fmt.Println("3. Test solution")
+		fmt.Println("3. Run example tests")
+		fmt.Println("4. Submit solution")
 		if s.Options.Mode == session.LearnMode {
-			fmt.Println("4. View hints")
-			fmt.Println("5. View solution")
-			fmt.Println("6. Exit")
+			fmt.Println("5. View hints")
+			fmt.Println("6. View solution")
+			fmt.Println("7. Exit")
 		} else {
-			fmt.Println("4. Exit")
+			fmt.Println("5. Exit")
+		}
+		fmt.Println("b. Big-O reference")
+		if easier != nil {
+			fmt.Println("s. Switch to an easier problem")
 		}
 
 		// Get user choice
@@ -106,6 +149,24 @@ func runCliWorkflow(s *SessionAdapter) error {
 		fmt.Scanln(&choice)
 
 		switch choice {
+		case "b", "B": // Big-O reference
+			fmt.Println("\n--- Big-O Reference ---")
+			fmt.Println(complexity.Render())
+
+		case "s", "S": // Switch to an easier problem
+			if easier == nil {
+				fmt.Println("Invalid choice. Please try again.")
+				continue
+			}
+			recordEvent(s, timeline.Event{Kind: timeline.KindFinish, Detail: "switched to " + easier.Title})
+			swapped, err := s.Session.SwapToEasier(easier)
+			if err != nil {
+				fmt.Printf("Error switching problems: %v\n", err)
+				continue
+			}
+			fmt.Println()
+			return runCliWorkflow(&SessionAdapter{Session: swapped})
+
 		case "1": // View problem
 			viewFile(descFile)
 
@@ -120,53 +181,73 @@ func runCliWorkflow(s *SessionAdapter) error {
 				continue
 			}
 			s.SetCode(string(code))
+			recordEvent(s, timeline.Event{Kind: timeline.KindSnapshot, Code: string(code)})
 
-		case "3": // Test solution
-			// Run tests
+		case "3": // Run example tests
+			recordCode(s)
 			results, allPassed, err := s.RunTests(context.Background())
 			if err != nil {
 				fmt.Printf("Error running tests: %v\n", err)
 				continue
 			}
-
-			// Display test results
-			fmt.Println("\n--- Test Results ---")
-			for i, result := range results {
-				passed := "❌ FAILED"
-				if result.Passed {
-					passed = "✅ PASSED"
-				}
-
-				fmt.Printf("\nTest %d: %s\n", i+1, passed)
-				fmt.Printf("Input: %s\n", result.Input)
-				fmt.Printf("Expected: %s\n", result.Expected)
-				fmt.Printf("Actual: %s\n", result.Actual)
+			recordTests(s, timeline.KindTest, results)
+			printTestResults(results)
+			recordTestRun(s.Problem.ID, allPassed)
+
+			// Without other tiers, the examples are the whole submission
+			if unrun := unrunTests(s.Problem); allPassed && unrun > 0 {
+				fmt.Printf("\n%s Example tests pass. Choose 4 to submit against %d more tests.\n", symbols.Pass, unrun)
+			} else if allPassed {
+				return solvedCli(s)
 			}
 
+		case "4": // Submit solution
+			recordCode(s)
+			results, allPassed, err := s.SubmitTests(context.Background())
+			if err != nil {
+				fmt.Printf("Error running tests: %v\n", err)
+				continue
+			}
+			recordTests(s, timeline.KindSubmit, results)
+			printTestResults(results)
+			recordTestRun(s.Problem.ID, allPassed)
+			if summary := execution.TierSummary(results); summary != "" {
+				fmt.Printf("\nBy tier: %s\n", summary)
+			}
 			if allPassed {
-				fmt.Println("\n🎉 All tests passed! Problem solved! 🎉")
-
-				// Record completion
-				s.FinishSession(true)
-				return nil
+				return solvedCli(s)
 			}
+			fmt.Println("\nSubmission failed. Fix the failing tests and submit again.")
 
-		case "4":
+		case "5":
 			if s.Options.Mode == session.LearnMode {
-				// View hints - we'll just show the pattern explanation
-				fmt.Println("\n--- Pattern Information ---")
-				fmt.Println(s.Problem.PatternExplanation)
+				// View hints, one level more each time, as the hint policy allows
+				hint, err := nextHint(s.Problem, s.Options.Language)
+				if err != nil {
+					fmt.Println(err)
+					continue
+				}
+				printHint(hint)
+				recordEvent(s, timeline.Event{Kind: timeline.KindHint, Level: hint.Level})
 				s.ShowHints(true)
+				if hint.Solution != "" {
+					s.ShowSolution(true)
+				}
 			} else {
 				// Exit
 				fmt.Println("Exiting session...")
+				recordEvent(s, timeline.Event{Kind: timeline.KindFinish})
 				s.FinishSession(false)
 				return nil
 			}
 
-		case "5":
+		case "6":
 			if s.Options.Mode == session.LearnMode {
-				// View solution
+				// View solution, if the hint policy allows it
+				if decision := requestHelp(s.Problem.ID, hints.KindSolution); !decision.Allowed {
+					fmt.Println(decision.Reason)
+					continue
+				}
 				fmt.Println("\n--- Solution ---")
 
 				if solution, ok := s.Problem.Solutions[s.Options.Language]; ok {
@@ -179,15 +260,17 @@ func runCliWorkflow(s *SessionAdapter) error {
 					}
 				}
 
+				recordEvent(s, timeline.Event{Kind: timeline.KindSolution})
 				s.ShowSolution(true)
 			} else {
 				fmt.Println("Invalid choice. Please try again.")
 			}
 
-		case "6":
+		case "7":
 			if s.Options.Mode == session.LearnMode {
 				// Exit
 				fmt.Println("Exiting session...")
+				recordEvent(s, timeline.Event{Kind: timeline.KindFinish})
 				s.FinishSession(false)
 				return nil
 			} else {
@@ -200,7 +283,136 @@ func runCliWorkflow(s *SessionAdapter) error {
 	}
 }
 
+// printHint displays a hint, with as much of the way to a solution as its
+// level shows
+func printHint(hint VimHintResponse) {
+	fmt.Printf("\n--- Hint (level %d of %d) ---\n", hint.Level, hints.MaxLevel)
+	fmt.Println(hint.Hint)
+	if len(hint.Walkthrough) > 0 {
+		fmt.Println("\nWalkthrough:")
+		for i, step := range hint.Walkthrough {
+			fmt.Printf("%d. %s\n", i+1, step)
+		}
+	}
+	if hint.Solution != "" {
+		fmt.Printf("\nSolution (%s):\n%s\n", hint.Language, hint.Solution)
+	}
+	if hint.Notice != "" {
+		fmt.Println("\n" + hint.Notice)
+	}
+}
+
+// printTestResults displays each test's result and the slowest test
+func printTestResults(results []interfaces.TestResult) {
+	fmt.Println("\n--- Test Results ---")
+	durations := make([]time.Duration, len(results))
+	for i, result := range results {
+		durations[i] = result.Duration
+		passed := testStatus(result)
+
+		fmt.Printf("\nTest %d%s: %s%s\n", i+1, testTier(result.Tier), passed, testDuration(result.Duration))
+		fmt.Printf("Input: %s\n", result.Input)
+		fmt.Printf("Expected: %s\n", result.Expected)
+		fmt.Printf("Actual: %s\n", result.Actual)
+	}
+	if slowest := execution.Slowest(durations); len(durations) > 1 && slowest >= 0 {
+		fmt.Printf("\nSlowest: Test %d%s\n", slowest+1, testDuration(durations[slowest]))
+	}
+}
+
+// solvedCli reports a solved problem and finishes the session
+func solvedCli(s *SessionAdapter) error {
+	fmt.Println("\n🎉 All tests passed! Problem solved! 🎉")
+	writeComplexityFeedback(os.Stdout, assessComplexity(*s.Problem, s.Options.Language, s.Implementation.GetCode()))
+
+	// Record completion
+	recordEvent(s, timeline.Event{Kind: timeline.KindFinish, Solved: true})
+	s.FinishSession(true)
+	return nil
+}
+
+// unrunTests counts the problem's tests that only a submission runs
+func unrunTests(p *problem.Problem) int {
+	prob := &interfaces.Problem{TestCases: make([]interfaces.TestCase, len(p.TestCases))}
+	for i, tc := range p.TestCases {
+		prob.TestCases[i] = interfaces.TestCase{Input: tc.Input, Expected: tc.Expected, Tier: tc.Tier}
+	}
+	return execution.UnrunTests(prob)
+}
+
+// testTier labels tests outside the example tier, e.g. " [hidden]"
+func testTier(tier interfaces.TestTier) string {
+	if tier.OrDefault() == interfaces.TierExample {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", tier)
+}
+
+// recordEvent adds an event to the session's timeline, for
+// 'algo-scales replay'
+func recordEvent(s *SessionAdapter, e timeline.Event) {
+	e.Elapsed = sessionElapsed(s)
+	// A timeline that cannot be saved does not hold up the session
+	_ = timeline.Record(timeline.Timeline{
+		SessionID: timeline.NewID(s.Problem.ID, s.StartTime),
+		ProblemID: s.Problem.ID,
+		Title:     s.Problem.Title,
+		Language:  s.Options.Language,
+		StartedAt: s.StartTime,
+	}, e)
+}
+
+// recordCode adds the code file to the timeline, if it changed since the
+// last snapshot; it may have been edited outside the menu
+func recordCode(s *SessionAdapter) {
+	if code, err := os.ReadFile(s.CodeFile); err == nil {
+		recordEvent(s, timeline.Event{Kind: timeline.KindSnapshot, Code: string(code)})
+	}
+}
+
+// recordTests adds a test run to the timeline
+func recordTests(s *SessionAdapter, kind timeline.Kind, results []interfaces.TestResult) {
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	recordEvent(s, timeline.Event{Kind: kind, Passed: passed, Total: len(results)})
+}
+
+// sessionElapsed returns how long the user has spent in a session
+func sessionElapsed(s *SessionAdapter) time.Duration {
+	if s.Clock != nil {
+		return s.Clock.Elapsed()
+	}
+	return time.Since(s.StartTime)
+}
+
 // viewFile displays the contents of a file
+// testDuration formats a test's duration for the CLI, flagging slow tests
+func testDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	if execution.IsSlow(d) {
+		return fmt.Sprintf(" (%s %s slow)", symbols.Warning, execution.FormatDuration(d))
+	}
+	return fmt.Sprintf(" (%s)", execution.FormatDuration(d))
+}
+
+// testStatus labels a test result, naming the kind of failure so type and
+// compile errors stand apart from wrong answers
+func testStatus(result interfaces.TestResult) string {
+	if result.Passed {
+		return symbols.Pass.String() + " PASSED"
+	}
+	if label := result.Failure.Label(); label != "" {
+		return fmt.Sprintf("%s FAILED (%s)", symbols.Fail, label)
+	}
+	return symbols.Fail.String() + " FAILED"
+}
+
 func viewFile(path string) {
 	// Check for common pager programs
 	pagers := []string{"less", "more", "cat"}
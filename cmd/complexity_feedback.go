@@ -0,0 +1,113 @@
+// Complexity feedback shown after a passing submission and in stats
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// assessComplexity reviews and records a passing solution's complexity
+// Exported as variable for testing
+var assessComplexity = complexity.Assess
+
+// VimComplexity is the complexity feedback in a vim submit response
+type VimComplexity struct {
+	Time        string   `json:"time"`
+	Space       string   `json:"space"`
+	TargetTime  string   `json:"target_time,omitempty"`
+	TargetSpace string   `json:"target_space,omitempty"`
+	Verdict     string   `json:"verdict"`
+	Summary     string   `json:"summary"`
+	Notes       []string `json:"notes,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+	Source      string   `json:"source"`
+}
+
+// newVimComplexity converts feedback for the vim plugin
+func newVimComplexity(fb complexity.Feedback) *VimComplexity {
+	c := &VimComplexity{
+		Time:        fb.Time.String(),
+		Space:       fb.Space.String(),
+		Verdict:     string(fb.Verdict()),
+		Summary:     fb.Summary(),
+		Notes:       fb.Heuristic.Notes,
+		Explanation: fb.Explanation,
+		Source:      fb.Source,
+	}
+	if fb.HasTarget {
+		c.TargetTime = fb.TargetTime.String()
+		c.TargetSpace = fb.TargetSpace.String()
+	}
+	return c
+}
+
+// writeComplexityFeedback prints the estimated complexity of a solution
+// against the reference
+func writeComplexityFeedback(out io.Writer, fb complexity.Feedback) {
+	fmt.Fprintln(out, "\n--- Complexity ---")
+	fmt.Fprintf(out, "Your solution: %s time, %s space\n", fb.Time, fb.Space)
+	if fb.HasTarget {
+		fmt.Fprintf(out, "Reference:     %s time, %s space\n", fb.TargetTime, fb.TargetSpace)
+	}
+	fmt.Fprintln(out, fb.Summary())
+	if fb.Explanation != "" {
+		fmt.Fprintln(out, fb.Explanation)
+	}
+	if fb.Source == complexity.SourceHeuristic {
+		if len(fb.Heuristic.Notes) > 0 {
+			fmt.Fprintf(out, "Estimated from: %s\n", JoinStrings(fb.Heuristic.Notes))
+		}
+		fmt.Fprintln(out, "(Heuristic estimate; configure the AI assistant for a closer review.)")
+	}
+}
+
+// loadComplexity returns the recorded complexity of passing solutions
+// Exported as variable for testing
+var loadComplexity = func() ([]storage.Complexity, error) {
+	repo := storage.Default()
+	defer repo.Close()
+	return repo.LoadComplexity(context.Background())
+}
+
+// complexityTotals counts the analyzed solutions by how their complexity
+// compares with the reference
+type complexityTotals struct {
+	Analyzed  int `json:"analyzed"`
+	Matching  int `json:"matching"`
+	Slower    int `json:"slower"`
+	MoreSpace int `json:"more_space"`
+}
+
+// countComplexity totals the recorded complexity verdicts
+func countComplexity(records []storage.Complexity) complexityTotals {
+	var totals complexityTotals
+	for _, r := range records {
+		switch complexity.Verdict(r.Verdict) {
+		case complexity.VerdictSlower:
+			totals.Slower++
+		case complexity.VerdictMoreSpace:
+			totals.MoreSpace++
+		case complexity.VerdictUnknown:
+			continue
+		}
+		totals.Analyzed++
+	}
+	totals.Matching = totals.Analyzed - totals.Slower - totals.MoreSpace
+	return totals
+}
+
+// writeComplexityStats prints how many solutions match the reference
+// complexity
+func writeComplexityStats(out io.Writer, records []storage.Complexity) {
+	totals := countComplexity(records)
+	if totals.Analyzed == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\nComplexity: %d of %d analyzed solution(s) match the reference (%d slower, %d using more space)\n",
+		totals.Matching, totals.Analyzed, totals.Slower, totals.MoreSpace)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubComplexity replaces the complexity review with fixed feedback, so
+// tests neither ask the AI nor write to the real database
+func stubComplexity(t *testing.T, fb complexity.Feedback) {
+	t.Helper()
+	original := assessComplexity
+	assessComplexity = func(problem.Problem, string, string) complexity.Feedback { return fb }
+	t.Cleanup(func() { assessComplexity = original })
+}
+
+func TestWriteComplexityFeedback(t *testing.T) {
+	fb := complexity.Feedback{
+		Time: complexity.Quadratic, Space: complexity.Constant,
+		TargetTime: complexity.Linear, TargetSpace: complexity.Linear, HasTarget: true,
+		Source:    complexity.SourceHeuristic,
+		Heuristic: complexity.Estimate{Notes: []string{"nested loops (depth 2)"}},
+	}
+
+	var out bytes.Buffer
+	writeComplexityFeedback(&out, fb)
+	assert.Contains(t, out.String(), "Your solution: O(n^2) time, O(1) space")
+	assert.Contains(t, out.String(), "Reference:     O(n) time, O(n) space")
+	assert.Contains(t, out.String(), "slower than the reference")
+	assert.Contains(t, out.String(), "Estimated from: nested loops (depth 2)")
+
+	fb.Source, fb.Explanation = complexity.SourceAI, "Every pair is checked."
+	out.Reset()
+	writeComplexityFeedback(&out, fb)
+	assert.Contains(t, out.String(), "Every pair is checked.")
+	assert.NotContains(t, out.String(), "Heuristic estimate")
+
+	vim := newVimComplexity(fb)
+	assert.Equal(t, "O(n^2)", vim.Time)
+	assert.Equal(t, "O(n)", vim.TargetTime)
+	assert.Equal(t, "slower", vim.Verdict)
+}
@@ -0,0 +1,116 @@
+// config command, for settings kept in files under ~/.config/algo-scales
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+	"github.com/spf13/cobra"
+)
+
+// ThemeConfig is the theme setting config theme reports
+type ThemeConfig struct {
+	Preset  string   `json:"preset"`
+	Path    string   `json:"path"`
+	Presets []string `json:"presets"`
+}
+
+// pickTheme lets the user choose a preset interactively
+// Exported as variable for testing
+var pickTheme = runThemePicker
+
+// configCmd groups the settings kept in config files
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Change settings kept in config files",
+}
+
+// configThemeCmd chooses the theme the TUI is drawn with
+var configThemeCmd = &cobra.Command{
+	Use:   "theme [preset]",
+	Short: "Choose the colors of the TUI",
+	Long: `Choose the preset the TUI is drawn with, saved in the theme file at
+~/.config/algo-scales/theme.yaml. Without a preset, a picker previews each
+one. Colors set in the theme file are kept and override the preset's.
+
+Presets: ` + strings.Join(theme.Presets(), ", ") + `
+
+Examples:
+  algo-scales config theme
+  algo-scales config theme solarized
+  algo-scales config theme --list`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+
+		path := theme.Path()
+		file, err := theme.ReadFile(path)
+		if err != nil {
+			commandError(cmd, "reading the theme file", err)
+			return
+		}
+		current := file.Preset
+		if current == "" {
+			current = theme.DefaultPreset
+		}
+
+		if list {
+			if jsonOutput(cmd) {
+				writeJSON(cmd, ThemeConfig{Preset: current, Path: path, Presets: theme.Presets()})
+				return
+			}
+			for _, name := range theme.Presets() {
+				marker := "  "
+				if name == current {
+					marker = "* "
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), marker+name)
+			}
+			return
+		}
+
+		var preset string
+		if len(args) == 1 {
+			preset = args[0]
+		} else {
+			if jsonOutput(cmd) {
+				commandError(cmd, "choosing a theme", fmt.Errorf("name a preset: %s", strings.Join(theme.Presets(), ", ")))
+				return
+			}
+			if preset, err = pickTheme(current); err != nil {
+				commandError(cmd, "choosing a theme", err)
+				return
+			}
+			if preset == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), "Theme unchanged.")
+				return
+			}
+		}
+
+		file.Preset = preset
+		t, err := file.Theme()
+		if err != nil {
+			commandError(cmd, "choosing a theme", err)
+			return
+		}
+		if err := theme.WriteFile(path, file); err != nil {
+			commandError(cmd, "saving the theme", err)
+			return
+		}
+		theme.Set(t)
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, ThemeConfig{Preset: preset, Path: path, Presets: theme.Presets()})
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Theme set to %s in %s\n", preset, path)
+	},
+}
+
+func init() {
+	configThemeCmd.Flags().Bool("list", false, "List the presets, marking the one in use")
+	configCmd.AddCommand(configThemeCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigTheme(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original := theme.Current()
+	t.Cleanup(func() { theme.Set(original) })
+	require.NoError(t, theme.WriteFile(theme.Path(), theme.File{Colors: map[string]string{"accent": "#ff00ff"}}))
+
+	output, err := executeCommand(rootCmd, "config", "theme", "solarized", "--list=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Theme set to solarized")
+	assert.Equal(t, "solarized", theme.Current().Name)
+
+	file, err := theme.ReadFile(theme.Path())
+	require.NoError(t, err)
+	assert.Equal(t, "solarized", file.Preset)
+	assert.Equal(t, "#ff00ff", file.Colors["accent"], "colors in the file are kept")
+
+	output, err = executeCommand(rootCmd, "config", "theme", "--list")
+	require.NoError(t, err)
+	assert.Contains(t, output, "* solarized")
+	assert.Contains(t, output, "  dark")
+
+	output, err = executeCommand(rootCmd, "config", "theme", "neon", "--list=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, `unknown theme preset "neon"`)
+	file, err = theme.ReadFile(theme.Path())
+	require.NoError(t, err)
+	assert.Equal(t, "solarized", file.Preset)
+}
+
+func TestConfigThemePicker(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original, originalPick := theme.Current(), pickTheme
+	t.Cleanup(func() {
+		theme.Set(original)
+		pickTheme = originalPick
+	})
+
+	var started string
+	pickTheme = func(current string) (string, error) {
+		started = current
+		return "", nil
+	}
+	output, err := executeCommand(rootCmd, "config", "theme", "--list=false")
+	require.NoError(t, err)
+	assert.Equal(t, theme.DefaultPreset, started)
+	assert.Contains(t, output, "Theme unchanged.")
+	assert.NoFileExists(t, theme.Path())
+
+	pickTheme = func(current string) (string, error) { return "light", nil }
+	_, err = executeCommand(rootCmd, "config", "theme", "--list=false")
+	require.NoError(t, err)
+	file, err := theme.ReadFile(theme.Path())
+	require.NoError(t, err)
+	assert.Equal(t, "light", file.Preset)
+}
+
+func TestConfigThemeJSON(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original := theme.Current()
+	t.Cleanup(func() { theme.Set(original) })
+
+	output, code := executeJSONCommand(t, "config", "theme", "high-contrast", "--list=false")
+	require.Equal(t, 0, code)
+	var result ThemeConfig
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "high-contrast", result.Preset)
+	assert.Equal(t, theme.Path(), result.Path)
+	assert.Equal(t, theme.Presets(), result.Presets)
+}
@@ -0,0 +1,232 @@
+// Daemon mode, serving editor plugins from one warm process
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/daemon"
+	"github.com/lancekrogers/algo-scales/internal/services"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// daemonProblemTTL is how long the daemon trusts its copy of the problems
+// before reloading them, so syncs and new problems are picked up
+const daemonProblemTTL = time.Minute
+
+// daemonSocket returns the socket given with --socket, or the default one
+func daemonSocket(cmd *cobra.Command) string {
+	if socket, _ := cmd.Flags().GetString("socket"); socket != "" {
+		return socket
+	}
+	return filepath.Join(getConfigDir(), "daemon.sock")
+}
+
+// daemonCmd serves the editor methods over a unix socket
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Serve editor plugins from one long-running process",
+	Long: `Keep problems and sessions warm in one process and answer editor plugins
+over a unix socket, instead of starting a new process for every command.
+
+Requests are JSON-RPC 2.0, one per line. The methods are:
+  start_session  Start a session: mode, problem_id, language, pattern,
+                 difficulty and timer, as 'start --vim-mode' takes them
+  run_tests      Test a solution: problem_id, language, code or file,
+                 submit and bench, as 'test' and 'submit' take them
+  hint           The next hint for problem_id, in language; hint levels
+                 carry over from one request to the next
+  stats          Your overall, per-pattern and per-context stats
+  ping           Check the daemon is running
+  shutdown       Stop the daemon
+
+Results are the JSON the vim mode commands print. A failed request is
+answered with error code -32000 and the reason as its message.
+
+The socket is ~/.algo-scales/daemon.sock unless --socket is given, and only
+you can connect to it.
+
+Example:
+  echo '{"jsonrpc":"2.0","id":1,"method":"hint","params":{"problem_id":"two_sum"}}' \
+    | nc -U ~/.algo-scales/daemon.sock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket := daemonSocket(cmd)
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Load the problems once, up front
+		cached := services.NewCachedProblemService(services.DefaultRegistry.GetProblemService(), daemonProblemTTL)
+		services.DefaultRegistry.WithProblemService(cached)
+		if _, err := cached.ListAll(ctx); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to load problems: %v\n", err)
+		}
+
+		fmt.Fprintf(cmd.ErrOrStderr(), "Listening on %s\n", socket)
+		return newDaemonServer().ListenAndServe(ctx, socket)
+	},
+}
+
+// daemonStatusCmd reports whether the daemon is running
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check whether the daemon is running",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		socket := daemonSocket(cmd)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var result struct {
+			Requests int64 `json:"requests"`
+		}
+		if err := daemon.Call(ctx, socket, "ping", nil, &result); err != nil {
+			fmt.Fprintln(cmd.OutOrStdout(), "The daemon is not running.")
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "The daemon is running on %s and has answered %d request(s).\n", socket, result.Requests)
+	},
+}
+
+// daemonStopCmd asks the daemon to stop
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		socket := daemonSocket(cmd)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := daemon.Call(ctx, socket, "shutdown", nil, nil); err != nil {
+			fmt.Fprintln(cmd.OutOrStdout(), "The daemon is not running.")
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Stopped the daemon.")
+	},
+}
+
+// newDaemonServer creates the daemon with the editor methods
+func newDaemonServer() *daemon.Server {
+	server := daemon.NewServer()
+	server.Handle("start_session", daemonStartSession)
+	server.Handle("run_tests", daemonRunTests)
+	server.Handle("hint", daemonHint)
+	server.Handle("stats", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return loadPracticeStats()
+	})
+	return server
+}
+
+func daemonStartSession(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	opts := session.Options{
+		Mode:     session.PracticeMode,
+		Language: "go",
+		Timer:    45,
+	}
+	var p struct {
+		Mode       string `json:"mode"`
+		ProblemID  string `json:"problem_id"`
+		Language   string `json:"language"`
+		Pattern    string `json:"pattern"`
+		Difficulty string `json:"difficulty"`
+		Timer      int    `json:"timer"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid params: %v", err)
+	}
+
+	switch session.Mode(p.Mode) {
+	case "":
+	case session.LearnMode, session.PracticeMode, session.CramMode:
+		opts.Mode = session.Mode(p.Mode)
+	default:
+		return nil, fmt.Errorf("unknown mode %q; use learn, practice or cram", p.Mode)
+	}
+	if p.Language != "" {
+		opts.Language = p.Language
+	}
+	if p.Timer > 0 {
+		opts.Timer = p.Timer
+	}
+	opts.ProblemID, opts.Pattern, opts.Difficulty = p.ProblemID, p.Pattern, p.Difficulty
+	return startVimSession(ctx, opts)
+}
+
+func daemonRunTests(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProblemID string `json:"problem_id"`
+		Language  string `json:"language"`
+		Code      string `json:"code"`
+		File      string `json:"file"`
+		Submit    bool   `json:"submit"`
+		Bench     bool   `json:"bench"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid params: %v", err)
+	}
+	if p.ProblemID == "" {
+		return nil, fmt.Errorf("problem_id is required")
+	}
+	if p.Language == "" {
+		p.Language = "go"
+	}
+
+	code := p.Code
+	if code == "" {
+		if p.File == "" {
+			return nil, fmt.Errorf("code or file is required")
+		}
+		content, err := os.ReadFile(p.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %v", err)
+		}
+		code = string(content)
+	}
+
+	prob, err := services.DefaultRegistry.GetProblemService().GetByID(ctx, p.ProblemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem: %v", err)
+	}
+	return runSolutionTests(ctx, prob, p.Language, code, solutionRun{
+		FilePath:  p.File,
+		Submit:    p.Submit,
+		Benchmark: p.Bench,
+		Context:   interfaces.ContextVim,
+	})
+}
+
+func daemonHint(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProblemID string `json:"problem_id"`
+		Language  string `json:"language"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid params: %v", err)
+	}
+	if p.ProblemID == "" {
+		return nil, fmt.Errorf("problem_id is required")
+	}
+	if p.Language == "" {
+		p.Language = "go"
+	}
+	return nextVimHint(ctx, p.ProblemID, p.Language)
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+
+	daemonCmd.PersistentFlags().String("socket", "", "Unix socket to listen on (default ~/.algo-scales/daemon.sock)")
+}
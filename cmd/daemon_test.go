@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/daemon"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaemonMethods(t *testing.T) {
+	stubHintTracker(t, config.HintPolicy{})
+	dir := t.TempDir()
+	originalPath := storage.DBPath
+	t.Cleanup(func() { storage.DBPath = originalPath })
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+
+	// Unix socket paths must be short
+	socketDir, err := os.MkdirTemp("", "asd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(socketDir) })
+	socket := filepath.Join(socketDir, "daemon.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- newDaemonServer().ListenAndServe(ctx, socket) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("HintLevelsCarryOver", func(t *testing.T) {
+		for level := 1; level <= 2; level++ {
+			var resp VimHintResponse
+			require.NoError(t, daemon.Call(ctx, socket, "hint", map[string]string{"problem_id": "two_sum"}, &resp))
+			assert.Equal(t, level, resp.Level)
+			assert.NotEmpty(t, resp.Hint)
+		}
+	})
+
+	t.Run("RunTests", func(t *testing.T) {
+		var resp VimSubmitResponse
+		require.NoError(t, daemon.Call(ctx, socket, "run_tests", map[string]string{
+			"problem_id": "two_sum",
+			"code":       "func twoSum(nums []int, target int) []int {\n\treturn nil\n}\n",
+		}, &resp))
+		assert.False(t, resp.Passed)
+		assert.NotEmpty(t, resp.TestResults)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		var resp practiceStats
+		require.NoError(t, daemon.Call(ctx, socket, "stats", nil, &resp))
+		assert.NotNil(t, resp.Summary)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		err := daemon.Call(ctx, socket, "start_session", map[string]string{"mode": "sprint"}, nil)
+		var rpcErr *daemon.Error
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, daemon.CodeFailed, rpcErr.Code)
+		assert.Contains(t, rpcErr.Message, `unknown mode "sprint"`)
+
+		assert.ErrorContains(t, daemon.Call(ctx, socket, "run_tests", map[string]string{"problem_id": "two_sum"}, nil), "code or file is required")
+		assert.ErrorContains(t, daemon.Call(ctx, socket, "hint", nil, nil), "problem_id is required")
+	})
+}
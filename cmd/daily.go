@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +29,10 @@ you through one problem from each major algorithm pattern (musical scale).`,
 			startDailyVimMode()
 			return
 		}
+		if jsonOutput(cmd) {
+			startDailyJSON(cmd)
+			return
+		}
 		
 		// Check if TUI mode is requested
 		useTUI, _ := rootCmd.PersistentFlags().GetBool("tui")
@@ -61,10 +66,10 @@ func startDailyVimMode() {
 	daily.UpdateStreak(&progress)
 
 	// Check if we're continuing from a previous day
-	today := time.Now().Format("2006-01-02")
+	today := daily.Today()
 	var lastPracticedDay string
 	if !progress.LastPracticed.IsZero() {
-		lastPracticedDay = progress.LastPracticed.Format("2006-01-02")
+		lastPracticedDay = daily.DateOf(progress.LastPracticed)
 	}
 
 	if lastPracticedDay != today {
@@ -138,16 +143,16 @@ func startDailyScale() {
 	}
 
 	// Update streak based on last practice date
-	daily.UpdateStreak(&progress)
+	frozen := daily.UpdateStreak(&progress)
 
 	// Display streak information
-	displayStreakInfo(progress)
+	displayStreakInfo(progress, frozen)
 
 	// Check if we're continuing from a previous day
-	today := time.Now().Format("2006-01-02")
+	today := daily.Today()
 	var lastPracticedDay string
 	if !progress.LastPracticed.IsZero() {
-		lastPracticedDay = progress.LastPracticed.Format("2006-01-02")
+		lastPracticedDay = daily.DateOf(progress.LastPracticed)
 	}
 
 	if lastPracticedDay != today {
@@ -171,8 +176,9 @@ func startDailyScale() {
 
 	// Show information about remaining patterns
 	remaining := daily.GetRemainingPatterns(progress.Completed)
-	fmt.Printf("Patterns completed today: %d/11\n", len(progress.Completed))
-	fmt.Printf("Patterns remaining: %d/11\n\n", remaining)
+	total := len(daily.LoadRoutine().Scales())
+	fmt.Printf("Patterns completed today: %d/%d\n", len(progress.Completed), total)
+	fmt.Printf("Patterns remaining: %d/%d\n\n", remaining, total)
 
 	// Show current scale information
 	fmt.Printf("Now practicing: %s (%s)\n", nextScale.MusicalName, nextScale.Pattern)
@@ -227,7 +233,7 @@ func startDailyScale() {
 			startDailyScale() // Recursively start the next scale
 		} else {
 			fmt.Println("Practice session paused. You can continue later with 'algo-scales daily'")
-			fmt.Printf("Patterns completed today: %d/11\n", len(progress.Completed))
+			fmt.Printf("Patterns completed today: %d/%d\n", len(progress.Completed), len(daily.LoadRoutine().Scales()))
 		}
 	} else {
 		// All scales completed!
@@ -236,7 +242,7 @@ func startDailyScale() {
 		fmt.Println("│         🎵 Congratulations! Daily Scales Complete! 🎵         │")
 		fmt.Println("╰───────────────────────────────────────────────────────────────╯")
 		fmt.Println()
-		fmt.Println("You've completed all 11 algorithm pattern scales for today!")
+		fmt.Printf("You've completed all %d algorithm pattern scales for today!\n", len(daily.LoadRoutine().Scales()))
 		fmt.Println("Keep up the good work and maintain your practice streak.")
 		fmt.Println()
 		fmt.Printf("Current streak: %d days\n", progress.Streak)
@@ -251,8 +257,9 @@ func startDailyScale() {
 	}
 }
 
-// displayStreakInfo shows information about the user's practice streak
-func displayStreakInfo(progress daily.ScaleProgress) {
+// displayStreakInfo shows information about the user's practice streak and
+// the freezes that kept it going over frozen missed days
+func displayStreakInfo(progress daily.ScaleProgress, frozen int) {
 	// Create a streak indicator
 	var streakDisplay string
 	if progress.Streak > 0 {
@@ -270,14 +277,61 @@ func displayStreakInfo(progress daily.ScaleProgress) {
 	if progress.LongestStreak > progress.Streak {
 		fmt.Printf("Longest streak: %d days\n", progress.LongestStreak)
 	}
+	switch {
+	case frozen == 1:
+		fmt.Println("A streak freeze covered the day you missed.")
+	case frozen > 1:
+		fmt.Printf("Streak freezes covered the %d days you missed.\n", frozen)
+	}
+	if status := freezeStatus(progress); status != "" {
+		fmt.Println(status)
+	}
 	fmt.Println()
 }
 
+// freezeStatus describes the streak freezes held, or is empty when they are
+// turned off
+func freezeStatus(progress daily.ScaleProgress) string {
+	limit := daily.MaxFreezes()
+	if limit == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Streak freezes: %d of %d (one more every %d days of streak)",
+		min(progress.Freezes, limit), limit, daily.FreezeEarnDays)
+}
+
+// pickDailyProblem chooses the problem for the daily session's problem
+// under key. With --difficulty auto, recent performance in its pattern sets
+// the difficulty in place of the routine, and the reason says why.
+func pickDailyProblem(dailySession *daily.DailySession, key string) (*problem.Problem, string, error) {
+	if difficulty != session.DifficultyAuto {
+		prob, err := dailySession.PickProblem(key)
+		return prob, "", err
+	}
+
+	slot, ok := dailySession.Problems[key]
+	if !ok {
+		return nil, "", fmt.Errorf("pattern not found: %s", key)
+	}
+	choice := session.ChooseDifficulty(slot.Pattern)
+	slot.Difficulty = choice.Difficulty
+	dailySession.Problems[key] = slot
+	prob, err := dailySession.PickProblem(key)
+	if err != nil {
+		return nil, "", err
+	}
+	reason := choice.Reason
+	if !strings.EqualFold(prob.Difficulty, choice.Difficulty) {
+		reason = fmt.Sprintf("%s, but there are none, so this one is %s", reason, prob.Difficulty)
+	}
+	return prob, reason, nil
+}
+
 func init() {
 	rootCmd.AddCommand(dailyCmd)
 
 	// Use the same flags as start command for consistency
-	dailyCmd.Flags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript)")
+	dailyCmd.Flags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript, pseudo)")
 	dailyCmd.Flags().IntVarP(&timer, "timer", "t", 45, "Timer duration in minutes (15, 30, 45, 60)")
-	dailyCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard)")
+	dailyCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard, or auto to follow recent performance)")
 }
\ No newline at end of file
@@ -0,0 +1,251 @@
+// Daily practice with --output json, for editor extensions and scripts
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/spf13/cobra"
+)
+
+// dailyCounts is how far today's practice has got
+type dailyCounts struct {
+	Completed int `json:"completed"`
+	Skipped   int `json:"skipped"`
+	Pending   int `json:"pending"`
+	Total     int `json:"total"`
+}
+
+func newDailyCounts(s *daily.DailySession) dailyCounts {
+	return dailyCounts{
+		Completed: s.GetCompletedCount(),
+		Skipped:   s.GetSkippedCount(),
+		Pending:   s.GetPendingCount(),
+		Total:     s.GetTotalProblems(),
+	}
+}
+
+// dailyStartResponse is what 'daily --output json' prints. Problem is
+// missing when nothing is left to practice today, and Message says why.
+type dailyStartResponse struct {
+	Pattern  string          `json:"pattern,omitempty"`
+	Scale    string          `json:"scale,omitempty"`
+	Problem  *problemSummary `json:"problem,omitempty"`
+	FilePath string          `json:"file_path,omitempty"`
+	Message  string          `json:"message,omitempty"`
+	Progress dailyCounts     `json:"progress"`
+	Streak   int             `json:"streak"`
+	Frozen   int             `json:"frozen,omitempty"` // Freezes spent covering days missed since the last practice
+	Reason   string          `json:"reason,omitempty"` // Why the difficulty was chosen, for --difficulty auto
+}
+
+// dailyTestResponse is what 'daily test --output json' prints
+type dailyTestResponse struct {
+	Pattern   string `json:"pattern"`
+	ProblemID string `json:"problem_id"`
+	VimSubmitResponse
+	Progress dailyCounts `json:"progress"`
+}
+
+// dailyStatusResponse is what 'daily status --output json' prints
+type dailyStatusResponse struct {
+	Date          string               `json:"date"`
+	Today         string               `json:"today"`      // The current practice day; differs from date when the session is from an earlier day
+	DayStarts     string               `json:"day_starts"` // When a new practice day starts, such as "4:00 AM"
+	Progress      dailyCounts          `json:"progress"`
+	Routine       string               `json:"routine,omitempty"` // Routine the session was built from
+	Problems      []dailyProblemStatus `json:"problems"`          // In practice order
+	Streak        int                  `json:"streak"`
+	LongestStreak int                  `json:"longest_streak"`
+	Freezes       int                  `json:"freezes"`            // Streak freezes held
+	Rotation      []daily.RotationWeek `json:"rotation,omitempty"` // This week and the next ones, if rotating
+}
+
+// dailyProblemStatus is the state of one pattern in today's practice
+type dailyProblemStatus struct {
+	Pattern          string             `json:"pattern"`
+	Scale            string             `json:"scale"`
+	ProblemID        string             `json:"problem_id,omitempty"`
+	State            daily.ProblemState `json:"state"`
+	Attempts         int                `json:"attempts"`
+	TargetDifficulty string             `json:"target_difficulty,omitempty"` // Difficulty the routine chose
+}
+
+// startDailyJSON starts the next daily problem, as 'daily' does without
+// asking anything, and prints it as JSON
+func startDailyJSON(cmd *cobra.Command) {
+	dailySession, err := daily.GetOrCreateSession()
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error initializing daily session: %v", err))
+		return
+	}
+
+	progress, err := daily.LoadProgress()
+	if err != nil {
+		progress = daily.ScaleProgress{Completed: []string{}}
+	}
+	frozen := daily.UpdateStreak(&progress)
+	progress.LastPracticed = time.Now()
+	if err := daily.SaveProgress(progress); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: Error saving progress: %v\n", err)
+	}
+
+	resp := dailyStartResponse{Streak: progress.Streak, Frozen: frozen}
+	nextPattern := ""
+	if !dailySession.Completed {
+		nextPattern = dailySession.GetNextPendingPattern()
+	}
+	scale := daily.GetScaleByPattern(dailySession.Problems[nextPattern].Pattern)
+	if scale == nil {
+		resp.Progress = newDailyCounts(dailySession)
+		if dailySession.Completed {
+			resp.Message = "All daily scales completed for today"
+		} else {
+			resp.Message = "No pending patterns; resume a skipped problem with 'algo-scales daily resume-skipped'"
+		}
+		writeJSON(cmd, resp)
+		return
+	}
+
+	prob, reason, err := pickDailyProblem(dailySession, nextPattern)
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error selecting problem: %v", err))
+		return
+	}
+	if err := dailySession.StartProblem(nextPattern, prob.ID); err != nil {
+		failJSON(cmd, fmt.Errorf("error updating session: %v", err))
+		return
+	}
+	filePath, err := daily.CreateProblemFile(prob, language)
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error creating problem file: %v", err))
+		return
+	}
+
+	summary := newProblemSummary(*prob)
+	resp.Pattern = scale.Pattern
+	resp.Scale = scale.MusicalName
+	resp.Problem = &summary
+	resp.FilePath = filePath
+	resp.Reason = reason
+	resp.Progress = newDailyCounts(dailySession)
+	writeJSON(cmd, resp)
+}
+
+// testDailySolutionJSON tests the current daily problem against all its
+// tests, as 'daily test' does, and prints the results as JSON. A solution
+// that does not pass every test exits with exitTestsFailed.
+func testDailySolutionJSON(cmd *cobra.Command, benchmark bool) {
+	dailySession, err := daily.LoadSession()
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error loading session: %v; start one with 'algo-scales daily'", err))
+		return
+	}
+
+	var currentPattern string
+	var currentProblem daily.DailyProblem
+	for pattern, prob := range dailySession.Problems {
+		if prob.State == daily.StateInProgress {
+			currentPattern, currentProblem = pattern, prob
+			break
+		}
+	}
+	if currentPattern == "" {
+		failJSON(cmd, fmt.Errorf("no problem is currently in progress; start one with 'algo-scales daily'"))
+		return
+	}
+
+	prob, err := problem.GetByID(currentProblem.ProblemID)
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error loading problem: %v", err))
+		return
+	}
+	filePath := daily.GetProblemFilePath(currentProblem.ProblemID, language)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error reading solution file: %v", err))
+		return
+	}
+	code := string(content)
+
+	results, _, err := executeSolution(context.Background(), prob, language, code, true)
+	if err != nil {
+		failJSON(cmd, err)
+		return
+	}
+	testResults, allPassed := newTestResults(results)
+	session.RecordAttempt(prob.ID, language, code, results, allPassed, true)
+
+	resp := dailyTestResponse{
+		Pattern:   currentPattern,
+		ProblemID: prob.ID,
+		VimSubmitResponse: VimSubmitResponse{
+			Passed:      allPassed,
+			TestResults: testResults,
+		},
+	}
+	resp.Tiers = execution.TierSummary(results)
+	if allPassed {
+		resp.Complexity = newVimComplexity(assessComplexity(*prob, language, code))
+		if benchmark {
+			resp.Bench = newVimBench(runBench(*prob, language, code))
+		}
+		if err := dailySession.CompleteProblem(currentPattern); err != nil {
+			failJSON(cmd, fmt.Errorf("error updating session: %v", err))
+			return
+		}
+		recordDailySession(prob, currentProblem.StartedAt, true)
+	}
+	resp.Progress = newDailyCounts(dailySession)
+
+	writeJSON(cmd, resp)
+	if !allPassed {
+		exit(exitTestsFailed)
+	}
+}
+
+// writeDailyStatusJSON prints the state of today's practice as JSON
+func writeDailyStatusJSON(cmd *cobra.Command) {
+	dailySession, err := daily.LoadSession()
+	if err != nil {
+		failJSON(cmd, fmt.Errorf("error loading session: %v; start one with 'algo-scales daily'", err))
+		return
+	}
+
+	boundary := daily.LoadDayBoundary()
+	resp := dailyStatusResponse{
+		Date:      dailySession.Date,
+		Today:     boundary.Date(time.Now()),
+		DayStarts: boundary.Describe(),
+		Routine:   dailySession.Routine,
+		Progress:  newDailyCounts(dailySession),
+		Problems:  []dailyProblemStatus{},
+	}
+	for _, key := range dailySession.Keys() {
+		prob := dailySession.Problems[key]
+		scale := daily.GetScaleByPattern(prob.Pattern)
+		if scale == nil {
+			continue
+		}
+		resp.Problems = append(resp.Problems, dailyProblemStatus{
+			Pattern:          scale.Pattern,
+			Scale:            scale.MusicalName,
+			ProblemID:        prob.ProblemID,
+			State:            prob.State,
+			Attempts:         prob.Attempts,
+			TargetDifficulty: prob.Difficulty,
+		})
+	}
+	if progress, err := daily.LoadProgress(); err == nil {
+		resp.Streak, resp.LongestStreak, resp.Freezes = progress.Streak, progress.LongestStreak, progress.Freezes
+	}
+	resp.Rotation = daily.Plan()
+	writeJSON(cmd, resp)
+}
@@ -0,0 +1,145 @@
+// Data command for exporting and purging local user data
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/integrity"
+	"github.com/lancekrogers/algo-scales/internal/userdata"
+	"github.com/spf13/cobra"
+)
+
+// dataCmd represents the data command
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Export or delete your personal data",
+	Long:  `Export a full copy of your local AlgoScales data, check it, or permanently delete it.`,
+}
+
+// dataCheckCmd represents the check subcommand for data
+var dataCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check your configuration, daily session and progress for damage",
+	Long: `Read your configuration, daily session and progress database in full and
+check each can be loaded. A damaged file is moved aside and replaced by its
+newest valid backup, or started afresh if it has none.
+
+The same check runs on startup, but only on files changed since they were
+last found intact. Intact files are backed up once a day to
+~/.algo-scales/backups/auto, keeping the last five of each.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+		damaged := 0
+		for _, result := range integrity.Check(integrity.Files(), true) {
+			if result.Repaired() || result.Status == integrity.StatusFailed {
+				damaged++
+				fmt.Fprintln(out, integrity.Describe(result))
+				continue
+			}
+			fmt.Fprintf(out, "%-18s %s\n", result.File.Name, result.Status)
+		}
+		if damaged == 0 {
+			fmt.Fprintln(out, "\nYour data is intact.")
+		}
+	},
+}
+
+// dataExportCmd represents the export subcommand for data
+var dataExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all local user data to a zip archive",
+	Long: `Write stats, daily progress, notifications, AI transcripts, logs,
+workspaces and configuration into a single zip archive with a manifest.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = fmt.Sprintf("algo-scales-export-%s.zip", time.Now().Format("20060102-150405"))
+		}
+
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error creating export file: %v\n", err)
+			return
+		}
+		defer f.Close()
+
+		files, err := userdata.Export(f)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error exporting data: %v\n", err)
+			return
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %d files to %s\n", files, output)
+	},
+}
+
+// dataPurgeCmd represents the purge subcommand for data
+var dataPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete local user data",
+	Long: `Delete stats, daily progress, notifications, logs, AI transcripts and
+workspaces. Configuration, your license and downloaded problems are kept.
+Use --remote to also delete data synced to the server.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		yes, _ := cmd.Flags().GetBool("yes")
+		remote, _ := cmd.Flags().GetBool("remote")
+
+		if !yes {
+			fmt.Fprintln(cmd.OutOrStdout(), "This will permanently delete:")
+			for _, loc := range userdata.Locations() {
+				if loc.Purgeable {
+					fmt.Fprintf(cmd.OutOrStdout(), "  [%s] %s\n", loc.Category, loc.Path)
+				}
+			}
+			if remote {
+				fmt.Fprintln(cmd.OutOrStdout(), "  [server] all data synced with your license")
+			}
+			fmt.Fprint(cmd.OutOrStdout(), "Type 'purge' to confirm: ")
+
+			var response string
+			fmt.Fscanln(cmd.InOrStdin(), &response)
+			if response != "purge" {
+				fmt.Fprintln(cmd.OutOrStdout(), "Operation cancelled.")
+				return
+			}
+		}
+
+		// Delete remotely first so a failure leaves local data for a retry
+		if remote {
+			deleted, err := api.DeleteRemoteData(context.Background())
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error deleting server data: %v\n", err)
+				return
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d synced records from the server.\n", deleted)
+		}
+
+		removed, err := userdata.Purge()
+		for _, path := range removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", path)
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error purging data: %v\n", err)
+			return
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "Local data has been purged.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dataCmd)
+	dataCmd.AddCommand(dataExportCmd)
+	dataCmd.AddCommand(dataPurgeCmd)
+	dataCmd.AddCommand(dataCheckCmd)
+
+	dataExportCmd.Flags().StringP("output", "o", "", "Path of the zip archive to write")
+	dataPurgeCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	dataPurgeCmd.Flags().Bool("remote", false, "Also delete data synced to the server")
+}
@@ -0,0 +1,173 @@
+// Debug commands for diagnosing slow starts and integration problems
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
+	"github.com/lancekrogers/algo-scales/internal/services"
+	"github.com/spf13/cobra"
+)
+
+// activeProfiler is set while a --profile run is in progress
+var activeProfiler *profiling.Profiler
+
+// debugCmd represents the debug command
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Diagnostic tools",
+	Long:  `Tools for diagnosing performance problems such as slow starts, for
+checking which features a build includes, and for checking the services
+commands are built on.`,
+}
+
+// debugTimingsCmd represents the timings subcommand for debug
+var debugTimingsCmd = &cobra.Command{
+	Use:   "timings",
+	Short: "Summarize startup timings from the last --profile run",
+	Long: `Show how long the last run profiled with --profile spent loading
+configuration, indexing problems and initializing the UI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		report, err := profiling.LoadReport()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return
+		}
+		printTimings(cmd.OutOrStdout(), report)
+	},
+}
+
+// debugFeaturesCmd represents the features subcommand for debug
+var debugFeaturesCmd = &cobra.Command{
+	Use:   "features",
+	Short: "List the optional features included in this build",
+	Long: `Show which optional subsystems this binary was built with. Lite builds
+(go build -tags lite) leave out the AI assistant and the terminal UI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printFeatures(cmd.OutOrStdout())
+	},
+}
+
+// debugServicesCmd represents the services subcommand for debug
+var debugServicesCmd = &cobra.Command{
+	Use:   "services",
+	Short: "Check the services commands are built on",
+	Long: `Initialize each service of the service registry, in dependency order, and
+check that it works. Each service is shown with its state, where its
+implementation came from, what it depends on and its last error, to help
+diagnose commands such as the vim mode ones failing without a clear reason.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		statuses := services.DefaultRegistry.Check(cmd.Context())
+		if jsonOutput(cmd) {
+			writeJSON(cmd, statuses)
+			return
+		}
+		printServices(cmd.OutOrStdout(), statuses)
+	},
+}
+
+// startProfiling begins a --profile run for the command about to execute
+func startProfiling(cmd *cobra.Command) error {
+	dir, _ := cmd.Flags().GetString("profile")
+	if dir == "" || cmd == debugTimingsCmd {
+		return nil
+	}
+	if dir == profileDefaultDir {
+		dir = profiling.DefaultDir()
+	}
+
+	p, err := profiling.Start(dir, cmd.CommandPath())
+	if err != nil {
+		return err
+	}
+	activeProfiler = p
+	return nil
+}
+
+// stopProfiling finishes a --profile run and reports where output went
+func stopProfiling(cmd *cobra.Command) {
+	if activeProfiler == nil {
+		return
+	}
+
+	report, err := activeProfiler.Stop()
+	activeProfiler = nil
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error writing profile: %v\n", err)
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "\nProfiles written to %s (run 'algo-scales debug timings' for a summary)\n", report.ProfileDir)
+}
+
+// printTimings writes a human readable timing breakdown
+func printTimings(w io.Writer, report profiling.Report) {
+	fmt.Fprintf(w, "Last profiled run: %s (%s)\n\n", report.Command, report.StartedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "  %-16s %10s\n", "startup", formatTiming(report.Startup))
+	for _, ph := range report.Phases {
+		calls := ""
+		if ph.Calls > 1 {
+			calls = fmt.Sprintf("  (%d calls)", ph.Calls)
+		}
+		fmt.Fprintf(w, "  %-16s %10s%s\n", ph.Name, formatTiming(ph.Total), calls)
+	}
+	fmt.Fprintf(w, "  %-16s %10s\n", "total", formatTiming(report.Total))
+
+	if report.BinarySize > 0 {
+		fmt.Fprintf(w, "\nBinary size: %.1f MB\n", float64(report.BinarySize)/(1024*1024))
+	}
+	fmt.Fprintf(w, "Profiles: %s\n", report.ProfileDir)
+	fmt.Fprintf(w, "Inspect with: go tool pprof %s/cpu.pprof\n", report.ProfileDir)
+}
+
+// printFeatures lists each optional feature and whether it is available
+func printFeatures(w io.Writer) {
+	build := "full"
+	if features.Lite {
+		build = "lite"
+	}
+	fmt.Fprintf(w, "Build: %s\n\n", build)
+	for _, f := range features.All {
+		status := "available"
+		if err := features.Require(f); err != nil {
+			status = "not included"
+		}
+		fmt.Fprintf(w, "  %-6s %s\n", f, status)
+	}
+}
+
+// printServices lists each service's status, with its last error under it
+func printServices(w io.Writer, statuses []services.ServiceStatus) {
+	for _, s := range statuses {
+		deps := ""
+		if len(s.DependsOn) > 0 {
+			deps = "  (needs " + strings.Join(s.DependsOn, ", ") + ")"
+		}
+		fmt.Fprintf(w, "  %-9s %-12s %s%s\n", s.Name, s.State, s.Implementation, deps)
+		if s.Source != "" {
+			fmt.Fprintf(w, "  %-9s %-12s source: %s, started in %s\n", "", "", s.Source, formatTiming(s.InitTime))
+		}
+		if s.LastError != "" {
+			fmt.Fprintf(w, "  %-9s %-12s last error: %s (%s)\n", "", "", s.LastError, s.LastErrorAt.Format("15:04:05"))
+		}
+	}
+}
+
+// formatTiming rounds a duration for display
+func formatTiming(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugTimingsCmd)
+	debugCmd.AddCommand(debugFeaturesCmd)
+	debugCmd.AddCommand(debugServicesCmd)
+}
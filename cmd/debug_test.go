@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingProblemService cannot list problems
+type failingProblemService struct {
+	services.LegacyProblemService
+}
+
+func (*failingProblemService) ListAll(ctx context.Context) ([]problem.Problem, error) {
+	return nil, errors.New("problems directory missing")
+}
+
+func TestDebugServices(t *testing.T) {
+	original := services.DefaultRegistry
+	t.Cleanup(func() { services.DefaultRegistry = original })
+	services.DefaultRegistry = services.NewServiceRegistry().WithProblemService(&failingProblemService{})
+
+	output, err := executeCommand(rootCmd, "debug", "services")
+	require.NoError(t, err)
+	assert.Contains(t, output, "problems  failed       *cmd.failingProblemService")
+	assert.Contains(t, output, "source: configured")
+	assert.Contains(t, output, "last error: problems directory missing")
+	assert.Contains(t, output, "sessions  failed       *services.SessionServiceImpl  (needs problems)")
+	assert.Contains(t, output, "last error: depends on problems, which failed")
+	assert.Contains(t, output, "stats     ready        *services.LegacyStatsCommandService")
+
+	output, code := executeJSONCommand(t, "debug", "services")
+	require.Equal(t, 0, code)
+	assert.Contains(t, output, `"name":"problems","state":"failed"`)
+	assert.Contains(t, output, `"last_error":"problems directory missing"`)
+}
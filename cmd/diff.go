@@ -0,0 +1,161 @@
+// diff command, for comparing a solution with the reference solution
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/solutions"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/lancekrogers/algo-scales/internal/ui/diffview"
+	"github.com/spf13/cobra"
+)
+
+// defaultDiffWidth is how wide the diff is drawn when the terminal's width
+// is unknown
+const defaultDiffWidth = 120
+
+// referenceDiff is what 'diff --output json' prints
+type referenceDiff struct {
+	ProblemID string `json:"problem_id"`
+	Language  string `json:"language"`
+	Changed   bool   `json:"changed"`
+	Diff      string `json:"diff"` // Lines only in yours marked -, only in the reference +
+}
+
+// diffCmd shows a solution beside the reference solution
+var diffCmd = &cobra.Command{
+	Use:   "diff <problem>",
+	Short: "Compare your solution with the reference solution",
+	Long: `Show your solution beside the problem's reference solution in the same
+language, aligned line by line and syntax highlighted. Lines only in yours are
+marked - and lines only in the reference +. Seeing the reference counts as
+viewing the solution under the hint policy.
+
+Your latest tested code is compared, in the language you last used; choose
+another with --solution, numbered as in 'algo-scales solutions', or compare a
+file of your own with --file.
+
+Examples:
+  algo-scales diff two_sum
+  algo-scales diff two_sum --solution 2
+  algo-scales diff two_sum --file solution.py`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "comparing with the reference", err)
+			return
+		}
+		code, language, err := codeToDiff(cmd, p)
+		if err != nil {
+			commandError(cmd, "comparing with the reference", err)
+			return
+		}
+		reference := p.Solutions[language]
+		if strings.TrimSpace(reference) == "" {
+			commandError(cmd, "comparing with the reference", noReferenceError(p, language))
+			return
+		}
+		if decision := requestHelp(p.ID, hints.KindSolution); !decision.Allowed {
+			commandError(cmd, "comparing with the reference", fmt.Errorf("%s", decision.Reason))
+			return
+		}
+
+		if jsonOutput(cmd) {
+			lines := solutions.Diff(code, reference)
+			writeJSON(cmd, referenceDiff{ProblemID: p.ID, Language: language, Changed: solutions.Changed(lines), Diff: solutions.Format(lines)})
+			return
+		}
+		width, _ := cmd.Flags().GetInt("width")
+		if width <= 0 {
+			width = terminalWidth()
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "%s (%s)\n\n", p.Title, language)
+		fmt.Fprint(out, diffview.Render(code, reference, language, width, "Yours", "Reference"))
+		fmt.Fprintf(out, "\n%s\n", diffview.Summary(code, reference))
+	},
+}
+
+// codeToDiff returns the code to compare with the reference, and its
+// language: a file given with --file, a numbered solution, or else the
+// latest tested code
+func codeToDiff(cmd *cobra.Command, p *problem.Problem) (string, string, error) {
+	language, _ := cmd.Flags().GetString("language")
+	file, _ := cmd.Flags().GetString("file")
+	number, _ := cmd.Flags().GetInt("solution")
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", err
+		}
+		if language == "" {
+			language = highlight.DetectLanguage(string(data))
+		}
+		return string(data), language, nil
+	}
+
+	repo := storage.Default()
+	defer repo.Close()
+	attempts, err := repo.LoadAttempts(context.Background(), p.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if number > 0 {
+		s, err := solutions.Get(solutions.History(attempts, p.ID, false), number)
+		if err != nil {
+			return "", "", err
+		}
+		return s.Code, s.Language, nil
+	}
+	history := solutions.History(attempts, p.ID, true)
+	for i := len(history) - 1; i >= 0; i-- {
+		if language == "" || history[i].Language == language {
+			return history[i].Code, history[i].Language, nil
+		}
+	}
+	return "", "", fmt.Errorf("no code tested on %s yet; run its tests first, or compare a file with --file", p.ID)
+}
+
+// noReferenceError explains that a problem has no reference solution in a
+// language, listing the languages it has one in
+func noReferenceError(p *problem.Problem, language string) error {
+	var languages []string
+	for lang, code := range p.Solutions {
+		if strings.TrimSpace(code) != "" {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 {
+		return fmt.Errorf("%s has no reference solution", p.ID)
+	}
+	sort.Strings(languages)
+	return fmt.Errorf("%s has no reference solution in %q; it has one in %s", p.ID, language, strings.Join(languages, ", "))
+}
+
+// terminalWidth returns the width of the terminal from $COLUMNS, or a
+// default when it is not set
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultDiffWidth
+}
+
+func init() {
+	diffCmd.Flags().StringP("language", "l", "", "Compare code in this language (default: the language last used)")
+	diffCmd.Flags().String("file", "", "Compare this file instead of your tested code")
+	diffCmd.Flags().Int("solution", 0, "Compare this solution, numbered as in 'algo-scales solutions'")
+	diffCmd.Flags().Int("width", 0, "Width to draw the diff in (default: $COLUMNS, or 120)")
+	rootCmd.AddCommand(diffCmd)
+}
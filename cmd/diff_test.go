@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffWithReference(t *testing.T) {
+	stubUserData(t)
+	highlight.SetColors(highlight.NoColor)
+
+	output, err := executeCommand(rootCmd, "diff", "two_sum", "--language=", "--file=", "--solution=0", "--width=100")
+	require.NoError(t, err)
+	assert.Contains(t, output, "no code tested on two_sum yet")
+
+	mine := "func twoSum(nums []int, target int) []int {\n    for i := range nums {\n        for j := i + 1; j < len(nums); j++ {\n"
+	saveSolutions(t,
+		storage.Attempt{ProblemID: "two_sum", Language: "go", Time: time.Now().Add(-time.Minute), Submitted: true, Code: mine},
+		storage.Attempt{ProblemID: "two_sum", Language: "rust", Time: time.Now(), Code: "fn two_sum() {}\n"},
+	)
+
+	// The latest code in the language asked for is compared
+	output, err = executeCommand(rootCmd, "diff", "two_sum", "--language=go", "--file=", "--solution=0", "--width=100")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Two Sum (go)")
+	assert.Contains(t, output, "Yours")
+	assert.Contains(t, output, "│ Reference")
+	assert.Contains(t, output, " 1   func twoSum(nums []int, target int) []int {")
+	assert.Contains(t, output, "│  2 +     numMap := make(map[int]int)")
+	assert.Contains(t, output, "only in the reference.")
+
+	// The latest code is in a language without a reference
+	output, err = executeCommand(rootCmd, "diff", "two_sum", "--language=", "--file=", "--solution=0", "--width=100")
+	require.NoError(t, err)
+	assert.Contains(t, output, `two_sum has no reference solution in "rust"; it has one in cpp, go, java, python`)
+
+	output, err = executeCommand(rootCmd, "diff", "two_sum", "--language=", "--file=", "--solution=1", "--width=100")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Two Sum (go)")
+
+	file := filepath.Join(t.TempDir(), "solution.py")
+	require.NoError(t, os.WriteFile(file, []byte("def twoSum(self, nums, target):\n    pass\n"), 0644))
+	output, err = executeCommand(rootCmd, "diff", "two_sum", "--language=", "--file", file, "--solution=0", "--width=100")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Two Sum (python)", "a file's language is detected")
+}
+
+func TestDiffWithReferenceJSON(t *testing.T) {
+	stubUserData(t)
+	saveSolutions(t, storage.Attempt{ProblemID: "two_sum", Language: "go", Time: time.Now(), Code: "func twoSum(nums []int, target int) []int {\n"})
+
+	output, code := executeJSONCommand(t, "diff", "two_sum", "--language=", "--file=", "--solution=0")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, `"language":"go"`)
+	assert.Contains(t, output, `"changed":true`)
+	assert.Contains(t, output, `"diff":"  func twoSum(nums []int, target int) []int {\n+     numMap`)
+}
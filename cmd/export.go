@@ -0,0 +1,136 @@
+// Export command for study material
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/anki"
+	"github.com/lancekrogers/algo-scales/internal/notebook"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export study material",
+	Long:  `Export problems together with your own work for offline review.`,
+}
+
+// exportNotebookCmd represents the notebook subcommand for export
+var exportNotebookCmd = &cobra.Command{
+	Use:   "notebook",
+	Short: "Export problems and your solutions as a Markdown notebook",
+	Long: `Write a study notebook with each problem's description, its pattern
+notes, your latest submitted solution and your pass/fail history.
+
+The notebook is Markdown. Pass --pdf, or an --out path ending in .pdf, to
+convert it to PDF; this needs pandoc installed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		out, _ := cmd.Flags().GetString("out")
+		pdf, _ := cmd.Flags().GetBool("pdf")
+		attempted, _ := cmd.Flags().GetBool("attempted")
+
+		if strings.EqualFold(filepath.Ext(out), ".pdf") {
+			pdf = true
+		}
+		if out == "" {
+			out = "notebook.md"
+			if pdf {
+				out = "notebook.pdf"
+			}
+		}
+
+		repo := storage.Default()
+		defer repo.Close()
+
+		nb, err := notebook.Collect(context.Background(), repo, notebook.Options{Pattern: pattern, AttemptedOnly: attempted})
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error collecting notebook: %v\n", err)
+			return
+		}
+		if len(nb.Entries) == 0 && pattern != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No problems found for pattern %q\n", pattern)
+			return
+		}
+
+		if pdf {
+			err = notebook.WritePDF(nb.Markdown(), out)
+		} else {
+			err = os.WriteFile(out, []byte(nb.Markdown()), 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error writing notebook: %v\n", err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %d problem(s) to %s\n", len(nb.Entries), out)
+	},
+}
+
+// exportAnkiCmd represents the anki subcommand for export
+var exportAnkiCmd = &cobra.Command{
+	Use:   "anki",
+	Short: "Export pattern-recognition flashcards for Anki",
+	Long: `Write a deck of flashcards for learning to recognise patterns: the front
+of each card is a problem's prompt, the back its pattern and the key insight
+from the pattern notes. Paragraphs of the prompt that name the pattern are
+left off the front.
+
+The deck is tab-separated text that Anki imports with File > Import; header
+lines set the Basic note type, the deck and the tags, so no import settings
+need changing. Each card is tagged with its patterns and difficulty.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		out, _ := cmd.Flags().GetString("out")
+		deck, _ := cmd.Flags().GetString("deck")
+
+		problems, err := problem.ListAll()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing problems: %v\n", err)
+			return
+		}
+		cards := anki.Cards(problems, pattern)
+		if len(cards) == 0 && pattern != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No problems found for pattern %q\n", pattern)
+			return
+		}
+
+		f, err := os.Create(out)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error writing deck: %v\n", err)
+			return
+		}
+		err = anki.Write(f, cards, deck)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error writing deck: %v\n", err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported %d card(s) to %s\n", len(cards), out)
+		fmt.Fprintln(cmd.OutOrStdout(), "Import it in Anki with File > Import.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportNotebookCmd)
+	exportCmd.AddCommand(exportAnkiCmd)
+
+	exportNotebookCmd.Flags().StringP("pattern", "p", "", "Only include problems of this pattern")
+	exportNotebookCmd.Flags().StringP("out", "o", "", "Path to write (default: notebook.md, or notebook.pdf with --pdf)")
+	exportNotebookCmd.Flags().Bool("pdf", false, "Convert the notebook to PDF with pandoc")
+	exportNotebookCmd.Flags().Bool("attempted", false, "Only include problems you have attempted")
+
+	exportAnkiCmd.Flags().StringP("pattern", "p", "", "Only include problems of this pattern")
+	exportAnkiCmd.Flags().StringP("out", "o", "algo-scales-anki.txt", "Path to write")
+	exportAnkiCmd.Flags().String("deck", anki.DefaultDeck, "Anki deck to import the cards into")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAnki(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "deck.txt")
+
+	output, err := executeCommand(rootCmd, "export", "anki", "--pattern=hash-map", "--out", out, "--deck=Interviews")
+	require.NoError(t, err)
+	assert.Contains(t, output, "card(s) to "+out)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "#separator:tab\n#html:true\n#notetype:Basic\n#deck:Interviews\n")
+	assert.Contains(t, string(data), "<b>Two Sum</b> (easy)")
+	assert.Contains(t, string(data), "<b>Hash Map</b>")
+
+	output, err = executeCommand(rootCmd, "export", "anki", "--pattern=no-such-pattern", "--out", out)
+	require.NoError(t, err)
+	assert.Contains(t, output, `No problems found for pattern "no-such-pattern"`)
+}
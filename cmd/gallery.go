@@ -0,0 +1,117 @@
+// Gallery command for browsing and upvoting hall-of-fame solutions
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// galleryCmd represents the gallery command
+var galleryCmd = &cobra.Command{
+	Use:   "gallery <problem-id>",
+	Short: "Browse the hall of fame of solutions to a problem",
+	Long: `Each problem has a hall of fame of anonymous solutions, most upvoted
+first. A problem's hall of fame unlocks once you have solved it yourself.
+
+Publishing is opt-in: set "hallOfFame": true in your config and every
+solution that passes all tests without hints or the reference solution is
+published to its problem's hall of fame.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		problemID := args[0]
+
+		sessions, err := stats.GetAllSessions()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading your sessions: %v\n", err)
+			return
+		}
+		if !hasSolved(sessions, problemID) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Solve %s yourself to unlock its hall of fame.\n", problemID)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		entries, err := api.GetHallOfFame(ctx, problemID)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error fetching the hall of fame: %v\n", err)
+			return
+		}
+
+		language, _ := cmd.Flags().GetString("language")
+		out := cmd.OutOrStdout()
+		shown := 0
+		for _, entry := range entries {
+			if language != "" && entry.Language != language {
+				continue
+			}
+			shown++
+			fmt.Fprintf(out, "\n#%d  %s  %s  %s\n", shown, entry.ID, entry.Language, galleryBadge(entry))
+			for _, line := range strings.Split(strings.TrimRight(codefmt.Code(entry.Language, entry.Code), "\n"), "\n") {
+				fmt.Fprintf(out, "    %s\n", line)
+			}
+		}
+
+		if shown == 0 {
+			fmt.Fprintf(out, "No solutions to %s have been published yet.\n", problemID)
+			return
+		}
+		fmt.Fprintln(out, "\nUpvote a solution: algo-scales gallery upvote <entry-id>")
+	},
+}
+
+// galleryUpvoteCmd represents the upvote subcommand for gallery
+var galleryUpvoteCmd = &cobra.Command{
+	Use:   "upvote <entry-id>",
+	Short: "Upvote a hall-of-fame solution",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		upvotes, err := api.Upvote(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error upvoting: %v\n", err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Upvoted! The solution now has %d upvote(s).\n", upvotes)
+	},
+}
+
+// hasSolved reports whether any session solved the problem
+func hasSolved(sessions []stats.SessionStats, problemID string) bool {
+	for _, s := range sessions {
+		if s.ProblemID == problemID && s.Solved {
+			return true
+		}
+	}
+	return false
+}
+
+// galleryBadge summarizes an entry's upvotes and the caller's relation to it
+func galleryBadge(entry api.GalleryEntry) string {
+	badge := fmt.Sprintf("%d upvote(s)", entry.Upvotes)
+	switch {
+	case entry.Mine:
+		badge += ", yours"
+	case entry.Upvoted:
+		badge += ", upvoted by you"
+	}
+	return badge
+}
+
+func init() {
+	rootCmd.AddCommand(galleryCmd)
+	galleryCmd.AddCommand(galleryUpvoteCmd)
+
+	galleryCmd.Flags().StringP("language", "l", "", "Only show solutions in this language")
+}
@@ -0,0 +1,28 @@
+// Tests for gallery command
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasSolved(t *testing.T) {
+	sessions := []stats.SessionStats{
+		{ProblemID: "two-sum", Solved: false},
+		{ProblemID: "jump-game", Solved: true},
+	}
+
+	assert.True(t, hasSolved(sessions, "jump-game"))
+	assert.False(t, hasSolved(sessions, "two-sum"))
+	assert.False(t, hasSolved(nil, "jump-game"))
+}
+
+func TestGalleryBadge(t *testing.T) {
+	assert.Equal(t, "3 upvote(s)", galleryBadge(api.GalleryEntry{Upvotes: 3}))
+	assert.Equal(t, "1 upvote(s), yours", galleryBadge(api.GalleryEntry{Upvotes: 1, Mine: true}))
+	assert.Equal(t, "2 upvote(s), upvoted by you", galleryBadge(api.GalleryEntry{Upvotes: 2, Upvoted: true}))
+}
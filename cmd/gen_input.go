@@ -0,0 +1,129 @@
+// gen-input command, generating large inputs to stress test solutions with
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/bench"
+	"github.com/spf13/cobra"
+)
+
+// GeneratedInput is one input file gen-input wrote
+type GeneratedInput struct {
+	Path  string `json:"path"`
+	Seed  int64  `json:"seed"`
+	Bytes int    `json:"bytes"`
+}
+
+// genInputCmd generates large inputs for a problem
+var genInputCmd = &cobra.Command{
+	Use:   "gen-input <problem-id>",
+	Short: "Generate large inputs to stress test a solution",
+	Long: `Generate large inputs for a problem and write them to its workspace, to
+stress test your solution by hand. Each file holds one input in the format
+of the problem's tests, with arrays, strings and lists of --size elements.
+
+Inputs come from the problem's generator if it ships one, and otherwise
+from its signature and constraints. The size may go beyond what the
+constraints allow. The same seed always gives the same input. Benchmarks
+run on inputs made the same way.
+
+Examples:
+  algo-scales gen-input two_sum --size 1e5
+  algo-scales gen-input merge_intervals --size 10^4 --count 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sizeFlag, _ := cmd.Flags().GetString("size")
+		count, _ := cmd.Flags().GetInt("count")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		size, err := parseSize(sizeFlag)
+		if err != nil {
+			commandError(cmd, "reading --size", err)
+			return
+		}
+		if count < 1 {
+			commandError(cmd, "reading --count", fmt.Errorf("count must be at least 1"))
+			return
+		}
+		prob, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "loading problem", err)
+			return
+		}
+		if dir == "" {
+			dir = filepath.Join(session.WorkspaceDir(prob.ID), "inputs")
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			commandError(cmd, "creating the inputs directory", err)
+			return
+		}
+
+		var files []GeneratedInput
+		for i := int64(0); i < int64(count); i++ {
+			input, err := bench.GenerateSize(context.Background(), *prob, size, seed+i)
+			if err != nil {
+				commandError(cmd, "generating input", err)
+				return
+			}
+			path := filepath.Join(dir, fmt.Sprintf("input-%d-%d.txt", size, seed+i))
+			if err := os.WriteFile(path, []byte(input+"\n"), 0644); err != nil {
+				commandError(cmd, "writing input", err)
+				return
+			}
+			files = append(files, GeneratedInput{Path: path, Seed: seed + i, Bytes: len(input) + 1})
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, files)
+			return
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Generated %d input(s) of size %d for %s:\n", len(files), size, prob.Title)
+		for _, file := range files {
+			fmt.Fprintf(out, "  %s  (%d bytes)\n", file.Path, file.Bytes)
+		}
+	},
+}
+
+// parseSize reads a size written as 100000, 1e5 or 10^5
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	var size float64
+	if base, exp, ok := strings.Cut(s, "^"); ok {
+		b, err1 := strconv.ParseFloat(strings.TrimSpace(base), 64)
+		e, err2 := strconv.ParseFloat(strings.TrimSpace(exp), 64)
+		if err1 != nil || err2 != nil {
+			return 0, fmt.Errorf("invalid size %q", s)
+		}
+		size = math.Pow(b, e)
+	} else {
+		var err error
+		if size, err = strconv.ParseFloat(s, 64); err != nil {
+			return 0, fmt.Errorf("invalid size %q", s)
+		}
+	}
+	if size < 1 || size > math.MaxInt32 || size != math.Trunc(size) {
+		return 0, fmt.Errorf("size %q must be a whole number from 1 to %d", s, math.MaxInt32)
+	}
+	return int64(size), nil
+}
+
+func init() {
+	rootCmd.AddCommand(genInputCmd)
+
+	genInputCmd.Flags().String("size", "1e4", "Elements in each array, string or list, e.g. 100000, 1e5 or 10^5")
+	genInputCmd.Flags().Int("count", 1, "Number of inputs to generate")
+	genInputCmd.Flags().Int64("seed", 1, "Seed of the first input; later ones count up from it")
+	genInputCmd.Flags().String("dir", "", "Directory to write to (default: the problem's workspace)")
+}
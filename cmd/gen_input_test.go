@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenInput(t *testing.T) {
+	original := problem.GetByID
+	t.Cleanup(func() { problem.GetByID = original })
+	problem.GetByID = func(id string) (*problem.Problem, error) {
+		return &problem.Problem{
+			ID:          id,
+			Title:       "Two Sum",
+			Constraints: []string{"2 <= nums.length <= 10^4", "-10^9 <= nums[i] <= 10^9"},
+			Signature: &interfaces.FunctionSignature{
+				Name:    "twoSum",
+				Params:  []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+				Returns: "int[]",
+			},
+		}, nil
+	}
+
+	dir := t.TempDir()
+	output, err := executeCommand(rootCmd, "gen-input", "two_sum", "--size", "1e5", "--count", "2", "--seed", "1", "--dir", dir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Generated 2 input(s) of size 100000 for Two Sum")
+
+	data, err := os.ReadFile(filepath.Join(dir, "input-100000-1.txt"))
+	require.NoError(t, err)
+	var nums []int64
+	var target int64
+	require.NoError(t, json.Unmarshal([]byte("["+strings.TrimSpace(string(data))+"]"), &[]interface{}{&nums, &target}))
+	assert.Len(t, nums, 100000)
+	assert.FileExists(t, filepath.Join(dir, "input-100000-2.txt"))
+
+	output, code := executeJSONCommand(t, "gen-input", "two_sum", "--size", "10^3", "--count", "1", "--seed", "5", "--dir", dir)
+	require.Equal(t, 0, code)
+	var files []GeneratedInput
+	require.NoError(t, json.Unmarshal([]byte(output), &files))
+	require.Len(t, files, 1)
+	assert.Equal(t, filepath.Join(dir, "input-1000-5.txt"), files[0].Path)
+	assert.Equal(t, int64(5), files[0].Seed)
+}
+
+func TestParseSize(t *testing.T) {
+	for s, want := range map[string]int64{"100000": 100000, "1e5": 100000, "10^5": 100000, " 2.5e3 ": 2500} {
+		size, err := parseSize(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, size, s)
+	}
+	for _, s := range []string{"", "0", "-5", "1.5", "big", "10^x", "1e12"} {
+		_, err := parseSize(s)
+		assert.Error(t, err, s)
+	}
+}
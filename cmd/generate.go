@@ -0,0 +1,229 @@
+// generate command, for AI-written problems
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/spf13/cobra"
+)
+
+// defaultGeneratedPack is the pack generated problems are saved to
+const defaultGeneratedPack = "generated"
+
+// generatedResult is what 'generate --output json' prints
+type generatedResult struct {
+	Problem  *problem.Problem `json:"problem"`
+	Pack     string           `json:"pack,omitempty"` // Empty with --dry-run
+	Attempts int              `json:"attempts"`
+}
+
+// generateProblem asks the configured AI agent for a new problem
+// Exported as variable for testing
+var generateProblem = func(req ai.GenerateRequest) (*problem.Problem, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	return ai.GenerateProblem(ctx, agent, req)
+}
+
+// runReference runs a generated problem's reference solution against its
+// tests
+// Exported as variable for testing
+var runReference = execution.ExecuteTests
+
+// generateCmd writes a new problem with the AI assistant
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a new problem for a pattern with the AI assistant",
+	Long: `Ask the configured AI provider for a new problem of a pattern and difficulty,
+with a description, examples, test cases, starter code and a reference
+solution in one language.
+
+Before anything is saved, the problem is checked like an imported pack and
+its reference solution is run against every one of its test cases. A problem
+that fails either check is discarded and another generated, up to --attempts
+times. Problems that pass are saved to a problem pack, "generated" unless
+--pack names another, and practiced like any other problem. With --dry-run
+the problem is printed instead of saved.
+
+Examples:
+  algo-scales generate --pattern two-pointers --difficulty medium
+  algo-scales generate --pattern dp --difficulty hard --language python`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		difficulty, _ := cmd.Flags().GetString("difficulty")
+		language, _ := cmd.Flags().GetString("language")
+		pack, _ := cmd.Flags().GetString("pack")
+		attempts, _ := cmd.Flags().GetInt("attempts")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		difficulty = strings.ToLower(difficulty)
+		switch {
+		case pattern == "":
+			commandError(cmd, "generating a problem", fmt.Errorf("--pattern is required"))
+			return
+		case difficulty != "easy" && difficulty != "medium" && difficulty != "hard":
+			commandError(cmd, "generating a problem", fmt.Errorf("--difficulty must be easy, medium or hard"))
+			return
+		case !problem.ValidPackName(pack):
+			commandError(cmd, "generating a problem", fmt.Errorf("invalid pack name %q: use lowercase letters, digits, '-' and '_'", pack))
+			return
+		case attempts < 1:
+			commandError(cmd, "generating a problem", fmt.Errorf("--attempts must be at least 1"))
+			return
+		}
+
+		existing, err := problem.ListAll()
+		if err != nil {
+			commandError(cmd, "generating a problem", err)
+			return
+		}
+		req := ai.GenerateRequest{Pattern: pattern, Difficulty: difficulty, Language: language}
+		for _, p := range existing {
+			for _, pt := range p.Patterns {
+				if matchesPattern(pt, pattern) {
+					req.Pattern = pt
+					req.Avoid = append(req.Avoid, p.Title)
+					break
+				}
+			}
+		}
+
+		out := cmd.ErrOrStderr()
+		if !jsonOutput(cmd) {
+			out = cmd.OutOrStdout()
+		}
+		var generated *problem.Problem
+		attempt := 1
+		for ; attempt <= attempts; attempt++ {
+			fmt.Fprintf(out, "Generating a %s problem (%s, attempt %d of %d)...\n", req.Pattern, difficulty, attempt, attempts)
+			p, err := generateProblem(req)
+			if err == nil {
+				err = checkGenerated(p, language, out)
+			}
+			if err == nil {
+				generated = p
+				break
+			}
+			fmt.Fprintf(out, "%s Discarded: %v\n", symbols.Fail, err)
+		}
+		if generated == nil {
+			commandError(cmd, "generating a problem", fmt.Errorf("no problem passed its own tests in %d attempt(s)", attempts))
+			return
+		}
+		generated.ID = uniqueProblemID(generated.ID, existing)
+
+		if dryRun {
+			if jsonOutput(cmd) {
+				writeJSON(cmd, generatedResult{Problem: generated, Attempts: attempt})
+				return
+			}
+			fmt.Fprintln(out)
+			fmt.Fprint(out, formatGenerated(generated, language))
+			return
+		}
+
+		dir, err := problem.AddToPack(pack, "generated", []problem.Problem{*generated})
+		if err != nil {
+			commandError(cmd, "saving the problem", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, generatedResult{Problem: generated, Pack: pack, Attempts: attempt})
+			return
+		}
+		fmt.Fprintf(out, "\nSaved %s (%s) to pack %q in %s\n", generated.Title, generated.ID, pack, dir)
+		fmt.Fprintf(out, "Practice it with: algo-scales start practice %s --language %s\n", generated.ID, language)
+	},
+}
+
+// checkGenerated validates a generated problem and runs its reference
+// solution in language against its tests, reporting how the run went
+func checkGenerated(p *problem.Problem, language string, out io.Writer) error {
+	if errs := problem.Validate(*p); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("the problem is incomplete: %s", strings.Join(messages, "; "))
+	}
+	if p.Signature == nil {
+		return fmt.Errorf("the problem has no function signature")
+	}
+	code := p.Solutions[language]
+	if strings.TrimSpace(code) == "" {
+		return fmt.Errorf("the problem has no reference solution in %s", language)
+	}
+
+	// Every tier runs, as in a submission
+	prob := convertToInterfaceProblem(p)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	results, allPassed, err := runReference(ctx, &prob, code, language, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("the reference solution could not run: %v", err)
+	}
+	passed := 0
+	var failed []string
+	for i, r := range results {
+		if r.Passed {
+			passed++
+		} else {
+			failed = append(failed, fmt.Sprint(i+1))
+		}
+	}
+	if !allPassed || passed < len(p.TestCases) {
+		return fmt.Errorf("the reference solution fails test(s) %s of %d", strings.Join(failed, ", "), len(p.TestCases))
+	}
+	fmt.Fprintf(out, "%s The reference solution passes all %d test(s).\n", symbols.Pass, len(p.TestCases))
+	return nil
+}
+
+// uniqueProblemID returns id, or id with a number appended if a problem
+// with that ID exists already
+func uniqueProblemID(id string, existing []problem.Problem) string {
+	taken := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		taken[p.ID] = true
+	}
+	unique := id
+	for n := 2; taken[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", id, n)
+	}
+	return unique
+}
+
+// formatGenerated prints a generated problem for review
+func formatGenerated(p *problem.Problem, language string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s, %s)\n\n%s\n", p.Title, p.Difficulty, strings.Join(p.Patterns, ", "), strings.TrimSpace(p.Description))
+	for i, ex := range p.Examples {
+		fmt.Fprintf(&b, "\nExample %d:\n  Input: %s\n  Output: %s\n", i+1, ex.Input, ex.Output)
+	}
+	fmt.Fprintf(&b, "\nTest cases: %d\n", len(p.TestCases))
+	fmt.Fprintf(&b, "\nReference solution (%s):\n%s\n", language, strings.TrimRight(p.Solutions[language], "\n"))
+	return b.String()
+}
+
+func init() {
+	generateCmd.Flags().StringP("pattern", "p", "", "Pattern the problem is solved with, such as two-pointers")
+	generateCmd.Flags().StringP("difficulty", "d", "medium", "Difficulty: easy, medium or hard")
+	generateCmd.Flags().StringP("language", "l", "go", "Language of the starter code and reference solution")
+	generateCmd.Flags().String("pack", defaultGeneratedPack, "Problem pack to save the problem to")
+	generateCmd.Flags().Int("attempts", 3, "How many problems to generate before giving up")
+	generateCmd.Flags().Bool("dry-run", false, "Print the problem instead of saving it")
+	rootCmd.AddCommand(generateCmd)
+}
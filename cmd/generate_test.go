@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGenerateHome moves the problem repository to a temporary home holding
+// only two_sum, so generated problems are not saved to the real one
+func stubGenerateHome(t *testing.T) problem.Problem {
+	t.Helper()
+	p, err := problem.GetByID("two_sum")
+	require.NoError(t, err)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".algo-scales", "problems", "hash-map")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "two_sum.json"), data, 0644))
+	return *p
+}
+
+// stubGenerate replaces the AI agent with copies of two_sum under a new title,
+// and the reference run with runs that pass as given, one per attempt
+func stubGenerate(t *testing.T, passes ...bool) *[]ai.GenerateRequest {
+	t.Helper()
+	twoSum := stubGenerateHome(t)
+	originalGenerate, originalRun := generateProblem, runReference
+	t.Cleanup(func() { generateProblem, runReference = originalGenerate, originalRun })
+
+	var requests []ai.GenerateRequest
+	generateProblem = func(req ai.GenerateRequest) (*problem.Problem, error) {
+		requests = append(requests, req)
+		generated := twoSum
+		generated.Title = "Pair Finder"
+		return &generated, nil
+	}
+	runReference = func(ctx context.Context, p *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		if len(passes) == 0 {
+			return nil, false, errors.New("no runs left")
+		}
+		pass := passes[0]
+		passes = passes[1:]
+		results := make([]interfaces.TestResult, len(p.TestCases))
+		for i := range results {
+			results[i].Passed = pass || i > 0
+		}
+		return results, pass, nil
+	}
+	return &requests
+}
+
+func TestGenerate(t *testing.T) {
+	stubUserData(t)
+	requests := stubGenerate(t, false, true)
+
+	output, err := executeCommand(rootCmd, "generate", "--pattern=hm", "--difficulty=Easy", "--language=go", "--pack=generated", "--attempts=3", "--dry-run=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Generating a hash-map problem (easy, attempt 1 of 3)...")
+	assert.Contains(t, output, symbols.Fail.String()+" Discarded: the reference solution fails test(s) 1 of")
+	assert.Contains(t, output, "(easy, attempt 2 of 3)")
+	assert.Contains(t, output, symbols.Pass.String()+" The reference solution passes all")
+	assert.Contains(t, output, `Saved Pair Finder (two_sum_2) to pack "generated"`)
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "hash-map", (*requests)[0].Pattern, "the pattern is matched by its initials")
+	assert.Contains(t, (*requests)[0].Avoid, "Two Sum", "existing problems of the pattern are not repeated")
+
+	saved, err := problem.GetByID("two_sum_2")
+	require.NoError(t, err)
+	assert.Equal(t, "Pair Finder", saved.Title)
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	stubUserData(t)
+	stubGenerate(t, true)
+
+	output, err := executeCommand(rootCmd, "generate", "--pattern=hash-map", "--difficulty=easy", "--language=go", "--pack=generated", "--attempts=1", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Pair Finder (easy, hash-map)")
+	assert.Contains(t, output, "Reference solution (go):")
+	assert.NotContains(t, output, "Saved")
+
+	assert.NoDirExists(t, problem.PackDir("generated"), "a dry run saves nothing")
+}
+
+func TestGenerateGivesUp(t *testing.T) {
+	stubUserData(t)
+	stubGenerate(t)
+
+	output, err := executeCommand(rootCmd, "generate", "--pattern=hash-map", "--difficulty=easy", "--language=go", "--pack=generated", "--attempts=2", "--dry-run=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Discarded: the reference solution could not run: no runs left")
+	assert.Contains(t, output, "no problem passed its own tests in 2 attempt(s)")
+
+	output, err = executeCommand(rootCmd, "generate", "--pattern=hash-map", "--difficulty=extreme", "--language=go", "--pack=generated", "--attempts=1", "--dry-run=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "--difficulty must be easy, medium or hard")
+}
+
+func TestCheckGenerated(t *testing.T) {
+	stubGenerate(t, true)
+	p, err := problem.GetByID("two_sum")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	incomplete := *p
+	incomplete.TestCases = nil
+	assert.ErrorContains(t, checkGenerated(&incomplete, "go", &out), "the problem is incomplete: at least one test case is required")
+	assert.ErrorContains(t, checkGenerated(p, "cobol", &out), "the problem has no reference solution in cobol")
+	assert.NoError(t, checkGenerated(p, "go", &out))
+}
+
+func TestUniqueProblemID(t *testing.T) {
+	existing := []problem.Problem{{ID: "pairs"}, {ID: "pairs_2"}}
+	assert.Equal(t, "pairs_3", uniqueProblemID("pairs", existing))
+	assert.Equal(t, "triples", uniqueProblemID("triples", existing))
+}
@@ -0,0 +1,199 @@
+// goals command, for setting practice goals and tracking them
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// goalBarWidth is the width of a goal's progress bar
+const goalBarWidth = 20
+
+// goalsResponse is what 'goals --output json' prints
+type goalsResponse struct {
+	Goals []goals.Progress `json:"goals"`
+}
+
+// goalSetResult is what 'goals set --output json' prints
+type goalSetResult struct {
+	Goal     goals.Goal `json:"goal"`
+	Replaced bool       `json:"replaced"` // True if it replaced a goal with the same ID
+}
+
+// goalRemoveResult is what 'goals remove --output json' prints
+type goalRemoveResult struct {
+	ID      string `json:"id"`
+	Removed bool   `json:"removed"` // False if there was no such goal
+}
+
+// goalsCmd lists the goals and how far each has got
+var goalsCmd = &cobra.Command{
+	Use:   "goals",
+	Short: "Set practice goals and track your progress toward them",
+	Long: `List your practice goals with a progress bar for each. Solve goals count the
+problems you solved since Monday; streak goals count the days in a row you
+practiced, today included once you have. Reaching a goal adds a notification
+to your inbox, once per week or streak. The TUI's statistics screen shows the
+same progress.
+
+Examples:
+  algo-scales goals set solve 10 --difficulty medium
+  algo-scales goals set solve 3 --pattern dp
+  algo-scales goals set streak 5
+  algo-scales goals
+  algo-scales goals remove solve-medium`,
+	Run: func(cmd *cobra.Command, args []string) {
+		progress, err := stats.GetGoalProgress()
+		if err != nil {
+			commandError(cmd, "retrieving goals", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			if progress == nil {
+				progress = []goals.Progress{}
+			}
+			writeJSON(cmd, goalsResponse{Goals: progress})
+			return
+		}
+		printGoals(cmd.OutOrStdout(), progress)
+	},
+}
+
+// goalsSetCmd adds or replaces a goal
+var goalsSetCmd = &cobra.Command{
+	Use:   "set <solve|streak> <target>",
+	Short: "Set a weekly solve goal or a streak goal",
+	Long: `Set a goal. 'solve N' asks for N problems solved each week, counting each
+problem once; limit it to a difficulty with --difficulty and to a pattern with
+--pattern. 'streak N' asks for N days of practice in a row. Setting a goal
+with the same kind, difficulty and pattern replaces it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		target, err := strconv.Atoi(args[1])
+		if err != nil {
+			commandError(cmd, "setting the goal", fmt.Errorf("the target must be a number, not %q", args[1]))
+			return
+		}
+		difficulty, _ := cmd.Flags().GetString("difficulty")
+		pattern, _ := cmd.Flags().GetString("pattern")
+
+		var goal goals.Goal
+		switch goals.Kind(args[0]) {
+		case goals.KindSolve:
+			goal, err = goals.NewSolveGoal(target, difficulty, scalePattern(pattern))
+		case goals.KindStreak:
+			if difficulty != "" || pattern != "" {
+				err = fmt.Errorf("--difficulty and --pattern only apply to solve goals")
+				break
+			}
+			goal, err = goals.NewStreakGoal(target)
+		default:
+			err = fmt.Errorf("unknown kind of goal %q; use solve or streak", args[0])
+		}
+		if err != nil {
+			commandError(cmd, "setting the goal", err)
+			return
+		}
+		replaced, err := goals.Set(goal)
+		if err != nil {
+			commandError(cmd, "setting the goal", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, goalSetResult{Goal: goal, Replaced: replaced})
+			return
+		}
+		verb := "Set"
+		if replaced {
+			verb = "Replaced"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s goal %s: %s\n\n", verb, goal.ID, goal.Describe())
+		if progress, err := stats.GetGoalProgress(); err == nil {
+			printGoals(cmd.OutOrStdout(), progress)
+		}
+	},
+}
+
+// goalsRemoveCmd deletes a goal
+var goalsRemoveCmd = &cobra.Command{
+	Use:     "remove <goal>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a goal",
+	Long:    `Remove a goal by the ID 'algo-scales goals' lists it under, such as solve-medium or streak.`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := goals.Remove(args[0])
+		if err != nil {
+			commandError(cmd, "removing the goal", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, goalRemoveResult{ID: args[0], Removed: removed})
+			return
+		}
+		if !removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "There is no goal %s. 'algo-scales goals' lists them.\n", args[0])
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed goal %s.\n", args[0])
+	},
+}
+
+// scalePattern returns the scale pattern meant by a pattern such as dp, or
+// the pattern as given if it names no scale
+func scalePattern(pattern string) string {
+	for _, scale := range daily.Scales {
+		if matchesPattern(scale.Pattern, pattern) {
+			return scale.Pattern
+		}
+	}
+	return pattern
+}
+
+// printGoals prints each goal with a progress bar
+func printGoals(w io.Writer, progress []goals.Progress) {
+	if len(progress) == 0 {
+		fmt.Fprintln(w, "No goals set. Set one with: algo-scales goals set solve 10 --difficulty medium")
+		return
+	}
+
+	width := 0
+	for _, p := range progress {
+		width = max(width, len(p.ID))
+	}
+	fmt.Fprintln(w, "Goals:")
+	for _, p := range progress {
+		done := ""
+		if p.Done {
+			done = " " + symbols.Check.String()
+		}
+		count := fmt.Sprintf("%d/%d", p.Current, p.Target)
+		fmt.Fprintf(w, "  %-*s  %s  %-7s %s%s\n", width, p.ID, goalBar(p), count, p.Description, done)
+	}
+}
+
+// goalBar draws how far a goal has got
+func goalBar(p goals.Progress) string {
+	filled := int(p.Fraction() * goalBarWidth)
+	return strings.Repeat(symbols.BarFull.String(), filled) +
+		strings.Repeat(symbols.BarEmpty.String(), goalBarWidth-filled)
+}
+
+func init() {
+	goalsSetCmd.Flags().StringP("difficulty", "d", "", "Only count problems of this difficulty: easy, medium or hard")
+	goalsSetCmd.Flags().StringP("pattern", "p", "", "Only count problems of this pattern, such as dp or sliding-window")
+	goalsCmd.AddCommand(goalsSetCmd)
+	goalsCmd.AddCommand(goalsRemoveCmd)
+	rootCmd.AddCommand(goalsCmd)
+}
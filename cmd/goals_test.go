@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGoalProgress keeps goals in a temporary directory and measures them
+// against the given sessions
+func stubGoalProgress(t *testing.T, sessions []interfaces.SessionStats) {
+	t.Helper()
+	stubUserData(t)
+	originalASCII := symbols.ASCII()
+	symbols.SetASCII(false)
+	original := stats.GetGoalProgress
+	stats.GetGoalProgress = func() ([]goals.Progress, error) {
+		list, err := goals.Load()
+		if err != nil {
+			return nil, err
+		}
+		return goals.Evaluate(list, sessions, time.Now()), nil
+	}
+	t.Cleanup(func() {
+		stats.GetGoalProgress = original
+		symbols.SetASCII(originalASCII)
+		// The shared set command keeps the flags a test parsed
+		goalsSetCmd.Flags().Set("difficulty", "")
+		goalsSetCmd.Flags().Set("pattern", "")
+	})
+}
+
+func TestGoals(t *testing.T) {
+	now := time.Now()
+	stubGoalProgress(t, []interfaces.SessionStats{
+		{ProblemID: "coin_change", Difficulty: "medium", Patterns: []string{"dynamic-programming"}, StartTime: now, Solved: true},
+		{ProblemID: "two_sum", Difficulty: "easy", Patterns: []string{"hash-map"}, StartTime: now, Solved: true},
+	})
+
+	output, err := executeCommand(rootCmd, "goals")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No goals set.")
+
+	output, err = executeCommand(rootCmd, "goals", "set", "solve", "4", "--difficulty", "medium")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Set goal solve-medium: Solve 4 medium problems this week")
+	assert.Regexp(t, `solve-medium  █{5}░{15}  1/4`, output)
+
+	// Patterns may be given by their initials
+	output, err = executeCommand(rootCmd, "goals", "set", "solve", "1", "--difficulty", "", "--pattern", "dp")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Set goal solve-dynamic-programming: Solve 1 dynamic-programming problem this week")
+
+	output, err = executeCommand(rootCmd, "goals", "set", "streak", "5", "--pattern", "")
+	require.NoError(t, err)
+	output, err = executeCommand(rootCmd, "goals", "set", "streak", "3")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Replaced goal streak: Keep a 3-day practice streak")
+
+	output, err = executeCommand(rootCmd, "goals")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Goals:\n")
+	assert.Regexp(t, `solve-dynamic-programming  █{20}  1/1 +Solve 1 dynamic-programming problem this week ✓`, output)
+	assert.Regexp(t, `streak +█{6}░{14}  1/3 +Keep a 3-day practice streak\n`, output)
+
+	output, err = executeCommand(rootCmd, "goals", "set", "weekly", "3")
+	require.NoError(t, err)
+	assert.Contains(t, output, `unknown kind of goal "weekly"`)
+	output, err = executeCommand(rootCmd, "goals", "set", "streak", "3", "--difficulty", "hard")
+	require.NoError(t, err)
+	assert.Contains(t, output, "only apply to solve goals")
+	goalsSetCmd.Flags().Set("difficulty", "")
+	output, err = executeCommand(rootCmd, "goals", "set", "solve", "many")
+	require.NoError(t, err)
+	assert.Contains(t, output, `the target must be a number, not "many"`)
+
+	output, err = executeCommand(rootCmd, "goals", "remove", "solve-medium")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Removed goal solve-medium.")
+	output, err = executeCommand(rootCmd, "goals", "rm", "solve-medium")
+	require.NoError(t, err)
+	assert.Contains(t, output, "There is no goal solve-medium.")
+
+	output, code := executeJSONCommand(t, "goals")
+	require.Equal(t, 0, code)
+	var resp goalsResponse
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.Len(t, resp.Goals, 2)
+	assert.Equal(t, "solve-dynamic-programming", resp.Goals[0].ID)
+	assert.True(t, resp.Goals[0].Done)
+	assert.Equal(t, 1, resp.Goals[1].Current)
+
+	output, code = executeJSONCommand(t, "goals", "remove", "streak")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"id": "streak", "removed": true}`, output)
+}
@@ -0,0 +1,88 @@
+// hide and unhide commands, for archiving problems the user never wants
+// to be given
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// HideResult is what hide and unhide report
+type HideResult struct {
+	ProblemID string `json:"problem_id"`
+	Hidden    bool   `json:"hidden"`
+	Changed   bool   `json:"changed"` // False if the problem was already in that state
+}
+
+// hideCmd archives a problem
+var hideCmd = &cobra.Command{
+	Use:   "hide <problem>",
+	Short: "Hide a problem from random and adaptive selection",
+	Long: `Archive a problem you never want to be given, such as one that is too hard
+or irrelevant to you. Hidden problems are left out of random picks, daily
+scales, interviews, refreshers, easier-problem offers and the review queue.
+They still open by ID, and 'algo-scales list --archived' lists them.
+
+Examples:
+  algo-scales hide median_of_two_sorted_arrays
+  algo-scales unhide median_of_two_sorted_arrays`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "hiding the problem", err)
+			return
+		}
+		changed, err := problem.Hide(p.ID)
+		if err != nil {
+			commandError(cmd, "hiding the problem", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, HideResult{ProblemID: p.ID, Hidden: true, Changed: changed})
+			return
+		}
+		if !changed {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is already hidden.\n", p.ID)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Hid %s. Bring it back with: algo-scales unhide %s\n", p.ID, p.ID)
+	},
+}
+
+// unhideCmd returns an archived problem to selection
+var unhideCmd = &cobra.Command{
+	Use:   "unhide <problem>",
+	Short: "Return a hidden problem to selection",
+	Long: `Return a problem hidden with 'algo-scales hide' to random and adaptive
+selection. 'algo-scales list --archived' lists the hidden problems.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Problems removed since they were hidden can still be unhidden
+		id := args[0]
+		changed, err := problem.Unhide(id)
+		if err != nil {
+			commandError(cmd, "unhiding the problem", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, HideResult{ProblemID: id, Hidden: false, Changed: changed})
+			return
+		}
+		if !changed {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is not hidden.\n", id)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is back in selection.\n", id)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hideCmd)
+	rootCmd.AddCommand(unhideCmd)
+}
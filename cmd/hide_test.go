@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubUserData keeps user data files in a temporary directory for the test
+func stubUserData(t *testing.T) {
+	t.Helper()
+	original := utils.GetConfigDir
+	dir := t.TempDir()
+	utils.GetConfigDir = func() string { return dir }
+	t.Cleanup(func() {
+		utils.GetConfigDir = original
+		// The shared list command keeps the flag a test parsed
+		listCmd.Flags().Set("archived", "false")
+	})
+}
+
+func TestHideAndUnhide(t *testing.T) {
+	stubUserData(t)
+
+	output, err := executeCommand(rootCmd, "hide", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Hid two_sum")
+	output, err = executeCommand(rootCmd, "hide", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "two_sum is already hidden.")
+	assert.True(t, problem.HiddenIDs()["two_sum"])
+
+	output, err = executeCommand(rootCmd, "list", "--archived=false")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "- two_sum ")
+	assert.Contains(t, output, "1 hidden problem(s) not listed")
+
+	output, err = executeCommand(rootCmd, "list", "--archived")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Hidden Problems:")
+	assert.Contains(t, output, "- two_sum ")
+
+	output, err = executeCommand(rootCmd, "unhide", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "two_sum is back in selection.")
+	output, err = executeCommand(rootCmd, "list", "--archived")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No hidden problems.")
+
+	output, err = executeCommand(rootCmd, "hide", "no_such_problem")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Error")
+	assert.Empty(t, problem.HiddenIDs())
+}
+
+func TestHideJSON(t *testing.T) {
+	stubUserData(t)
+
+	output, code := executeJSONCommand(t, "hide", "two_sum")
+	require.Equal(t, 0, code)
+	var result HideResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, HideResult{ProblemID: "two_sum", Hidden: true, Changed: true}, result)
+
+	output, code = executeJSONCommand(t, "list", "--archived")
+	require.Equal(t, 0, code)
+	var listed map[string][]problemSummary
+	require.NoError(t, json.Unmarshal([]byte(output), &listed))
+	require.Len(t, listed["problems"], 1)
+	assert.Equal(t, "two_sum", listed["problems"][0].ID)
+}
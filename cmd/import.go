@@ -0,0 +1,94 @@
+// Import command for user-authored problem packs
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// packNameReplacer turns characters pack names cannot hold into dashes
+var packNameReplacer = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <directory|file|url>",
+	Short: "Import a problem pack",
+	Long: `Import problems from a local directory, a JSON or YAML file, or an
+http(s) URL to one. A file holds one problem or a list of problems in the
+same format as the built-in problems.
+
+Every problem is validated before anything is installed; errors are
+reported with the file and line they occur on. The problems are installed
+into your local problem repository as a named pack, and importing a pack
+again under the same name replaces it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		name, _ := cmd.Flags().GetString("name")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if name == "" {
+			name = defaultPackName(source)
+		}
+		if !problem.ValidPackName(name) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Invalid pack name %q: use lowercase letters, digits, '-' and '_' (set one with --name)\n", name)
+			return
+		}
+
+		pack, err := problem.LoadPack(source)
+		if err != nil {
+			var validationErr *problem.PackValidationError
+			if errors.As(err, &validationErr) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Pack %s is invalid:\n", source)
+				for _, issue := range validationErr.Issues {
+					fmt.Fprintf(cmd.ErrOrStderr(), "  %s\n", issue)
+				}
+				return
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading pack: %v\n", err)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		if dryRun {
+			fmt.Fprintf(out, "Pack %s is valid: %d problem(s)\n", source, len(pack.Problems))
+			return
+		}
+
+		dir, err := problem.InstallPack(name, pack)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error installing pack: %v\n", err)
+			return
+		}
+
+		fmt.Fprintf(out, "Imported %d problem(s) as pack %q into %s\n", len(pack.Problems), name, dir)
+		for _, p := range pack.Problems {
+			fmt.Fprintf(out, "  %s  %s (%s)\n", p.ID, p.Title, p.Difficulty)
+		}
+	},
+}
+
+// defaultPackName derives a pack name from the import source
+func defaultPackName(source string) string {
+	base := filepath.Base(filepath.Clean(source))
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		base = path.Base(u.Path)
+	}
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return strings.Trim(packNameReplacer.ReplaceAllString(strings.ToLower(base), "-"), "-_")
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("name", "n", "", "Name of the pack (default: derived from the source)")
+	importCmd.Flags().Bool("dry-run", false, "Validate the pack without installing it")
+}
@@ -96,11 +96,6 @@ func TestStartCommandsWithMockSession(t *testing.T) {
 		args         []string
 		expectedMode session.Mode
 	}{
-		{
-			name:         "cram mode",
-			args:         []string{"start", "cram"},
-			expectedMode: session.CramMode,
-		},
 		{
 			name:         "learn mode",
 			args:         []string{"start", "learn"},
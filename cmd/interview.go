@@ -0,0 +1,267 @@
+// Interview command for timed mock interviews
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/interview"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/spf13/cobra"
+)
+
+// interviewWarning is how long before the end the interview clock warns
+const interviewWarning = 5 * time.Minute
+
+// interviewCmd represents the interview command
+var interviewCmd = &cobra.Command{
+	Use:   "interview",
+	Short: "Run a timed mock interview",
+	Long: `Run a mock coding interview: 2 or 3 problems of climbing difficulty worked
+against a single clock (60 minutes by default). Hints and solutions are not
+available. Submit a problem to move on to the next one; when the clock runs
+out, whatever is in your editor is submitted.
+
+Afterwards you get a report with the time spent on each problem, the share
+of tests your final code passed, and feedback from the AI interviewer when
+the AI assistant is configured.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, _ := cmd.Flags().GetInt("problems")
+		minutes, _ := cmd.Flags().GetInt("duration")
+		lang, _ := cmd.Flags().GetString("language")
+		useAI, _ := cmd.Flags().GetBool("ai")
+		outPath, _ := cmd.Flags().GetString("out")
+
+		if minutes <= 0 {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Error: --duration must be a positive number of minutes")
+			return
+		}
+
+		problems, err := problem.ListAll()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing problems: %v\n", err)
+			return
+		}
+		picks, err := interview.Select(problem.Visible(problems), count, rand.New(rand.NewSource(time.Now().UnixNano())))
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return
+		}
+
+		// Skip the interactive interview during testing
+		if os.Getenv("TESTING") == "1" {
+			return
+		}
+
+		report := runInterview(picks, lang, time.Duration(minutes)*time.Minute)
+
+		if useAI {
+			fmt.Println("\n🤖 Asking the interviewer for feedback...")
+			if feedback, err := interviewFeedback(report); err != nil {
+				fmt.Printf("AI feedback unavailable: %v\n", err)
+				fmt.Println("Run 'algo-scales ai config' to set up the AI assistant.")
+			} else {
+				report.Feedback = feedback
+			}
+		}
+
+		markdown := report.Markdown()
+		fmt.Println()
+		fmt.Println(markdown)
+
+		if outPath != "" {
+			if err := os.WriteFile(outPath, []byte(markdown), 0644); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error writing report: %v\n", err)
+				return
+			}
+			fmt.Printf("Report saved to %s\n", outPath)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(interviewCmd)
+
+	interviewCmd.Flags().IntP("problems", "n", interview.DefaultProblems,
+		fmt.Sprintf("Number of problems (%d-%d)", interview.MinProblems, interview.MaxProblems))
+	interviewCmd.Flags().Int("duration", int(interview.DefaultDuration.Minutes()), "Interview length in minutes")
+	interviewCmd.Flags().StringP("language", "l", "go", "Programming language (go, python, javascript, typescript, rust, java, cpp, pseudo)")
+	interviewCmd.Flags().Bool("ai", true, "Ask the AI interviewer for feedback afterwards")
+	interviewCmd.Flags().StringP("out", "o", "", "Also save the report as Markdown to this file")
+}
+
+// runInterview works through the problems against one clock
+func runInterview(picks []problem.Problem, lang string, duration time.Duration) *interview.Report {
+	fmt.Println("🎤 AlgoScales Mock Interview 🎤")
+	fmt.Println("———————————————————————————————")
+	difficulties := make([]string, len(picks))
+	for i, p := range picks {
+		difficulties[i] = p.Difficulty
+	}
+	fmt.Printf("%d problems (%s) in %d minutes. Hints and solutions are disabled.\n",
+		len(picks), strings.Join(difficulties, ", "), int(duration.Minutes()))
+	fmt.Print("Press Enter to start the clock...")
+	fmt.Scanln()
+
+	interviewClock := clock.NewCountdown(duration)
+	interviewClock.Start()
+	report := &interview.Report{Date: time.Now(), Duration: duration}
+
+	ended := false
+	for i, p := range picks {
+		if ended || interviewClock.Expired() {
+			report.Results = append(report.Results, interview.Result{Problem: p, Language: lang})
+			continue
+		}
+		var result interview.Result
+		result, ended = runInterviewProblem(i, len(picks), p, lang, interviewClock)
+		report.Results = append(report.Results, result)
+	}
+
+	interviewClock.Pause()
+	report.Elapsed = interviewClock.Elapsed()
+	return report
+}
+
+// runInterviewProblem works on one problem until it is submitted or the
+// clock runs out, then tests the final code. It reports whether the user
+// ended the interview early.
+func runInterviewProblem(index, total int, p problem.Problem, lang string, interviewClock *clock.Clock) (interview.Result, bool) {
+	result := interview.Result{Problem: p, Language: lang, Reached: true}
+
+	sess, err := session.CreateSession(session.Options{Mode: session.InterviewMode, Language: lang, ProblemID: p.ID})
+	if err != nil {
+		fmt.Printf("Error creating session: %v\n", err)
+		return result, false
+	}
+	s := &SessionAdapter{Session: sess}
+	result.Problem = *s.Problem
+	result.Language = s.Options.Language
+
+	fmt.Printf("\n━━ Problem %d/%d: %s (%s) ━━\n", index+1, total, s.Problem.Title, s.Problem.Difficulty)
+	fmt.Printf("Statement: %s\n", filepath.Join(s.Workspace, "problem.md"))
+	fmt.Printf("Solution file: %s\n", s.CodeFile)
+
+	warned := false
+	for !interviewClock.Expired() {
+		remaining := interviewClock.Remaining()
+		if !warned && remaining <= interviewWarning {
+			warned = true
+			fmt.Printf("\n⚠ %d minutes left!\n", int(interviewWarning.Minutes()))
+		}
+
+		fmt.Printf("\n⏱  %s remaining\n", interview.FormatClock(remaining))
+		fmt.Println("1. View problem description")
+		fmt.Println("2. Edit solution")
+		fmt.Println("3. Run example tests")
+		if index+1 < total {
+			fmt.Println("4. Submit and move to the next problem")
+		} else {
+			fmt.Println("4. Submit and finish the interview")
+		}
+		fmt.Println("q. End the interview now")
+		fmt.Print("\nEnter your choice: ")
+		var choice string
+		fmt.Scanln(&choice)
+
+		if interviewClock.Expired() {
+			break
+		}
+
+		switch choice {
+		case "1":
+			viewFile(filepath.Join(s.Workspace, "problem.md"))
+		case "2":
+			openEditor(s.CodeFile)
+		case "3":
+			if _, _, err := runInterviewTests(s, false); err != nil {
+				fmt.Printf("Error running tests: %v\n", err)
+			}
+		case "4":
+			return finishInterviewProblem(s, result), false
+		case "q", "Q":
+			return finishInterviewProblem(s, result), true
+		default:
+			fmt.Println("Invalid choice. Please try again.")
+		}
+	}
+
+	fmt.Println("\n⏰ Time's up! Submitting your current code.")
+	return finishInterviewProblem(s, result), false
+}
+
+// finishInterviewProblem tests the final code and records the session
+func finishInterviewProblem(s *SessionAdapter, result interview.Result) interview.Result {
+	passed, total, err := runInterviewTests(s, true)
+	if err != nil {
+		fmt.Printf("Error running tests: %v\n", err)
+	}
+	result.Passed, result.Total = passed, total
+	if code, err := os.ReadFile(s.CodeFile); err == nil {
+		result.Code = string(code)
+	}
+	result.Time = sessionElapsed(s)
+
+	if err := s.FinishSession(result.Solved()); err != nil {
+		fmt.Printf("Warning: Error recording session: %v\n", err)
+	}
+	fmt.Printf("Submitted %s: %d/%d tests passed in %s\n",
+		s.Problem.Title, passed, total, interview.FormatClock(result.Time))
+	return result
+}
+
+// runInterviewTests runs the solution file's example tests, printing each
+// result, or submits it against every tier, and returns how many passed
+func runInterviewTests(s *SessionAdapter, submit bool) (int, int, error) {
+	code, err := os.ReadFile(s.CodeFile)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read solution: %v", err)
+	}
+	s.SetCode(string(code))
+
+	run := s.RunTests
+	if submit {
+		run = s.SubmitTests
+	}
+	results, _, err := run(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	passed := 0
+	for i, r := range results {
+		if r.Passed {
+			passed++
+		}
+		if !submit {
+			fmt.Printf("Test %d%s: %s\n", i+1, testTier(r.Tier), testStatus(r))
+			if !r.Passed {
+				fmt.Printf("  Input: %s\n  Expected: %s\n  Actual: %s\n", r.Input, r.Expected, r.Actual)
+			}
+		}
+	}
+	if !submit {
+		fmt.Printf("%d/%d tests passed\n", passed, len(results))
+	}
+	return passed, len(results), nil
+}
+
+// interviewFeedback asks the configured AI agent for interviewer feedback
+func interviewFeedback(report *interview.Report) (string, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return interview.Feedback(ctx, agent, report)
+}
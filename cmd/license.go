@@ -0,0 +1,93 @@
+// License commands for activating and inspecting a license key
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/spf13/cobra"
+)
+
+// licenseCmd represents the license command
+var licenseCmd = &cobra.Command{
+	Use:   "license",
+	Short: "Manage your license",
+	Long:  `Activate a license key and check the status of your license.`,
+}
+
+// licenseActivateCmd represents the license activate command
+var licenseActivateCmd = &cobra.Command{
+	Use:   "activate <license-key>",
+	Short: "Activate a license key",
+	Long: `Verify a license key and store it for this machine.
+
+The key's signature is checked offline. If the license server cannot be
+reached the key is still activated, and works offline for 14 days before
+it needs to be verified online again.
+
+Example:
+  algo-scales license activate AS1.eyJpZCI6...`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lic, err := license.Activate(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error activating license: %v\n", err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "License activated for %s\n", lic.Email)
+	},
+}
+
+// licenseStatusCmd represents the license status command
+var licenseStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of your license",
+	Long:  `Show who your license is for, when it expires and when it was last verified online.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := license.GetStatus()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No license activated: %v\n", err)
+			fmt.Fprintln(cmd.ErrOrStderr(), "Run 'algo-scales license activate <license-key>' to activate one.")
+			return
+		}
+		writeLicenseStatus(cmd.OutOrStdout(), status, time.Now())
+	},
+}
+
+// writeLicenseStatus prints a license status
+func writeLicenseStatus(out io.Writer, status license.Status, now time.Time) {
+	if status.Valid {
+		fmt.Fprintln(out, "License: valid")
+	} else {
+		fmt.Fprintf(out, "License: invalid (%v)\n", status.Err)
+	}
+	if status.Claims.ID == "" {
+		return
+	}
+
+	fmt.Fprintf(out, "  ID:       %s\n", status.Claims.ID)
+	fmt.Fprintf(out, "  Email:    %s\n", status.Claims.Email)
+	fmt.Fprintf(out, "  Issued:   %s\n", status.Claims.IssuedAt.Format("2006-01-02"))
+	if status.Claims.ExpiresAt.IsZero() {
+		fmt.Fprintln(out, "  Expires:  never")
+	} else {
+		fmt.Fprintf(out, "  Expires:  %s\n", status.Claims.ExpiresAt.Format("2006-01-02"))
+	}
+	if status.License.ValidatedAt.IsZero() {
+		fmt.Fprintln(out, "  Verified: never")
+		return
+	}
+	fmt.Fprintf(out, "  Verified: %s\n", status.License.ValidatedAt.Format("2006-01-02 15:04"))
+	if now.Before(status.GraceEndsAt) {
+		fmt.Fprintf(out, "  Works offline until %s\n", status.GraceEndsAt.Format("2006-01-02"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(licenseCmd)
+	licenseCmd.AddCommand(licenseActivateCmd)
+	licenseCmd.AddCommand(licenseStatusCmd)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteLicenseStatus(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	validated := now.AddDate(0, 0, -2)
+	status := license.Status{
+		License:     license.License{ValidatedAt: validated},
+		Claims:      license.Claims{ID: "lic-1", Email: "test@example.com", IssuedAt: now.AddDate(-1, 0, 0)},
+		Valid:       true,
+		GraceEndsAt: validated.Add(license.OfflineGracePeriod),
+	}
+
+	var out bytes.Buffer
+	writeLicenseStatus(&out, status, now)
+	assert.Contains(t, out.String(), "License: valid")
+	assert.Contains(t, out.String(), "test@example.com")
+	assert.Contains(t, out.String(), "Expires:  never")
+	assert.Contains(t, out.String(), "Works offline until 2026-03-22")
+
+	out.Reset()
+	status.Valid = false
+	status.Err = errors.New("license needs to be verified online")
+	writeLicenseStatus(&out, status, now.AddDate(0, 1, 0))
+	assert.Contains(t, out.String(), "License: invalid (license needs to be verified online)")
+	assert.NotContains(t, out.String(), "Works offline")
+}
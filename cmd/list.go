@@ -4,31 +4,174 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/spf13/cobra"
 )
 
+// findProblem lets the user pick one of problems interactively, starting
+// from query
+// Exported as variable for testing
+var findProblem = runFinder
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available problems",
-	Long:  `List the available algorithm problems by various criteria.`,
+	Long: `List the available algorithm problems by various criteria.
+
+Problems hidden with 'algo-scales hide' are left out; --archived lists only
+them.
+
+--search finds problems by words in their titles, descriptions, patterns and
+companies, best matches first; --pattern, --difficulty and --company narrow
+the list further. --interactive opens a finder that narrows the list as you
+type and prints the ID of the problem chosen:
+
+  algo-scales list --search "subarray" --pattern sliding-window --difficulty medium
+  algo-scales start practice $(algo-scales list --interactive)`,
 	Run: func(cmd *cobra.Command, args []string) {
+		archived, _ := cmd.Flags().GetBool("archived")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		query := listQuery(cmd)
+
 		// Default behavior when no subcommand is specified
 		problems, err := problem.ListAll()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing problems: %v\n", err)
+			commandError(cmd, "listing problems", err)
+			return
+		}
+		visible := problem.Visible(problems)
+		hidden := len(problems) - len(visible)
+		if archived {
+			if problems, err = hiddenProblems(problems); err != nil {
+				commandError(cmd, "listing hidden problems", err)
+				return
+			}
+		} else {
+			problems = visible
+		}
+
+		if interactive {
+			if jsonOutput(cmd) {
+				commandError(cmd, "finding a problem", fmt.Errorf("--interactive cannot be used with --output json"))
+				return
+			}
+			// The finder narrows by the search words itself as they are edited
+			text := query.Text
+			query.Text = ""
+			id, err := findProblem(searchProblems(problems, query), text)
+			if err != nil {
+				commandError(cmd, "finding a problem", err)
+				return
+			}
+			if id != "" {
+				fmt.Fprintln(cmd.OutOrStdout(), id)
+			}
 			return
 		}
 
-		fmt.Fprintln(cmd.OutOrStdout(), "Available Problems:")
+		filtered := query != (problem.Query{})
+		if filtered {
+			problems = searchProblems(problems, query)
+		}
+
+		if jsonOutput(cmd) {
+			summaries := []problemSummary{}
+			for _, p := range problems {
+				summaries = append(summaries, newProblemSummary(p))
+			}
+			writeJSON(cmd, map[string][]problemSummary{"problems": summaries})
+			return
+		}
+
+		if archived {
+			if len(problems) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No hidden problems.")
+				return
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Hidden Problems:")
+		} else if filtered {
+			if len(problems) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No problems match.")
+				return
+			}
+			printProblemTable(cmd.OutOrStdout(), problems)
+			return
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "Available Problems:")
+		}
 		for _, p := range problems {
 			fmt.Fprintf(cmd.OutOrStdout(), "- %s (%s): %s\n", p.ID, p.Difficulty, p.Title)
 		}
+		if !archived && hidden > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%d hidden problem(s) not listed. List them with: algo-scales list --archived\n", hidden)
+		}
 	},
 }
 
+// listQuery reads the search and filter flags of the list command
+func listQuery(cmd *cobra.Command) problem.Query {
+	var q problem.Query
+	q.Text, _ = cmd.Flags().GetString("search")
+	q.Pattern, _ = cmd.Flags().GetString("pattern")
+	q.Difficulty, _ = cmd.Flags().GetString("difficulty")
+	q.Company, _ = cmd.Flags().GetString("company")
+	if q.Pattern != "" {
+		q.Pattern = scalePattern(q.Pattern)
+	}
+	return q
+}
+
+// searchProblems returns the problems matching a query, best matches first
+func searchProblems(problems []problem.Problem, q problem.Query) []problem.Problem {
+	matches := problem.NewIndex(problems).Search(q)
+	found := make([]problem.Problem, len(matches))
+	for i, m := range matches {
+		found[i] = m.Problem
+	}
+	return found
+}
+
+// printProblemTable lists problems in aligned columns
+func printProblemTable(w io.Writer, problems []problem.Problem) {
+	idWidth, difficultyWidth, patternsWidth := len("ID"), len("DIFFICULTY"), len("PATTERNS")
+	for _, p := range problems {
+		idWidth = max(idWidth, len(p.ID))
+		difficultyWidth = max(difficultyWidth, len(p.Difficulty))
+		patternsWidth = max(patternsWidth, len(strings.Join(p.Patterns, ", ")))
+	}
+	row := func(id, difficulty, patterns, title string) {
+		fmt.Fprintf(w, "%-*s  %-*s  %-*s  %s\n", idWidth, id, difficultyWidth, difficulty, patternsWidth, patterns, title)
+	}
+	row("ID", "DIFFICULTY", "PATTERNS", "TITLE")
+	for _, p := range problems {
+		row(p.ID, p.Difficulty, strings.Join(p.Patterns, ", "), p.Title)
+	}
+}
+
+// hiddenProblems returns the problems the user has hidden, in the order
+// they were hidden
+func hiddenProblems(problems []problem.Problem) ([]problem.Problem, error) {
+	hidden, err := problem.LoadHidden()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]problem.Problem, len(problems))
+	for _, p := range problems {
+		byID[p.ID] = p
+	}
+	archived := []problem.Problem{}
+	for _, h := range hidden {
+		if p, ok := byID[h.ProblemID]; ok {
+			archived = append(archived, p)
+		}
+	}
+	return archived, nil
+}
+
 // patternsCmd represents the patterns subcommand
 var patternsCmd = &cobra.Command{
 	Use:   "patterns",
@@ -37,7 +180,11 @@ var patternsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		patterns, err := problem.ListPatterns()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing patterns: %v\n", err)
+			commandError(cmd, "listing patterns", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string]map[string][]problemSummary{"patterns": summarizeGroups(patterns)})
 			return
 		}
 
@@ -59,7 +206,11 @@ var difficultiesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		difficulties, err := problem.ListByDifficulty()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing by difficulty: %v\n", err)
+			commandError(cmd, "listing by difficulty", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string]map[string][]problemSummary{"difficulties": summarizeGroups(difficulties)})
 			return
 		}
 
@@ -81,7 +232,11 @@ var companiesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		companies, err := problem.ListByCompany()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error listing by company: %v\n", err)
+			commandError(cmd, "listing by company", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string]map[string][]problemSummary{"companies": summarizeGroups(companies)})
 			return
 		}
 
@@ -95,7 +250,24 @@ var companiesCmd = &cobra.Command{
 	},
 }
 
+// summarizeGroups describes each group of problems for --output json
+func summarizeGroups(groups map[string][]problem.Problem) map[string][]problemSummary {
+	summaries := make(map[string][]problemSummary, len(groups))
+	for name, problems := range groups {
+		for _, p := range problems {
+			summaries[name] = append(summaries[name], newProblemSummary(p))
+		}
+	}
+	return summaries
+}
+
 func init() {
+	listCmd.Flags().Bool("archived", false, "List only the problems hidden with 'algo-scales hide'")
+	listCmd.Flags().StringP("search", "s", "", "Find problems by words in their titles, descriptions, patterns and companies")
+	listCmd.Flags().StringP("pattern", "p", "", "List only problems of this pattern")
+	listCmd.Flags().StringP("difficulty", "d", "", "List only problems of this difficulty (easy, medium, hard)")
+	listCmd.Flags().StringP("company", "c", "", "List only problems this company asks")
+	listCmd.Flags().BoolP("interactive", "i", false, "Choose a problem in a finder that narrows as you type, and print its ID")
 	rootCmd.AddCommand(listCmd)
 	listCmd.AddCommand(patternsCmd)
 	listCmd.AddCommand(difficultiesCmd)
@@ -154,3 +154,56 @@ func TestListCommand(t *testing.T) {
 		assert.Contains(t, output, "test2")
 	})
 }
+
+func TestListSearch(t *testing.T) {
+	stubUserData(t)
+	t.Cleanup(func() {
+		for _, flag := range []string{"search", "pattern", "difficulty", "company"} {
+			listCmd.Flags().Set(flag, "")
+		}
+		listCmd.Flags().Set("interactive", "false")
+	})
+	restore := mockListAll([]problem.Problem{
+		{ID: "max_subarray", Title: "Maximum Subarray", Difficulty: "medium", Patterns: []string{"dynamic-programming"}, Companies: []string{"Amazon"}},
+		{ID: "min_size_subarray", Title: "Minimum Size Subarray Sum", Difficulty: "medium", Patterns: []string{"sliding-window"}, Companies: []string{"Google"}},
+		{ID: "longest_substring", Title: "Longest Substring", Difficulty: "medium", Patterns: []string{"sliding-window"}, Companies: []string{"Google"}},
+	}, nil)
+	defer restore()
+
+	t.Run("Table", func(t *testing.T) {
+		output, err := executeCommand(rootCmd, "list", "--search", "subarray", "--pattern", "sliding-window", "--difficulty", "medium", "--company", "google")
+		assert.NoError(t, err)
+		assert.Equal(t, "ID                 DIFFICULTY  PATTERNS        TITLE\n"+
+			"min_size_subarray  medium      sliding-window  Minimum Size Subarray Sum\n", output)
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		output, err := executeCommand(rootCmd, "list", "--search", "graph", "--pattern=", "--difficulty=", "--company=")
+		assert.NoError(t, err)
+		assert.Equal(t, "No problems match.\n", output)
+	})
+
+	t.Run("Interactive", func(t *testing.T) {
+		original := findProblem
+		t.Cleanup(func() { findProblem = original })
+		var offered []problem.Problem
+		var started string
+		findProblem = func(problems []problem.Problem, query string) (string, error) {
+			offered, started = problems, query
+			return "longest_substring", nil
+		}
+
+		output, err := executeCommand(rootCmd, "list", "-i", "--search", "sub", "--pattern", "sliding-window", "--difficulty=", "--company=")
+		assert.NoError(t, err)
+		assert.Equal(t, "longest_substring\n", output)
+		assert.Equal(t, "sub", started, "the finder starts from the search words")
+		assert.Len(t, offered, 2, "the filters narrow what the finder offers")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		output, code := executeJSONCommand(t, "list", "--search", "maximum", "--pattern=", "--interactive=false")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, output, `"id":"max_subarray"`)
+		assert.NotContains(t, output, "min_size_subarray")
+	})
+}
@@ -0,0 +1,124 @@
+// Login and logout commands for the API server
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// Login steps, exported as variables for testing
+var (
+	startLogin  = api.StartLogin
+	finishLogin = api.FinishLogin
+	logout      = api.Logout
+)
+
+// openBrowser opens a URL in the default browser
+// Exported as variable for testing
+var openBrowser = func(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
+
+// loginCmd logs in to the API server with a device code
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to the AlgoScales server",
+	Long: `Log this machine in to the AlgoScales server for progress sync, peer
+reviews, the hall of fame and other server features.
+
+A code is shown and a browser opened where you approve the login with your
+license key, so the key itself never has to be copied to this machine.
+The tokens the server issues are stored in the OS keychain (the macOS
+keychain, or the Secret Service keyring on Linux), or in
+~/.algo-scales/credentials.json, readable only by you, when there is none.
+They are refreshed automatically; a machine not used for 90 days has to
+log in again.
+
+Without a login, server features use the activated license key.
+
+Examples:
+  algo-scales login
+  algo-scales login --no-browser   # Only print the link, for remote shells`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		code, err := startLogin(ctx)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error starting login: %v\n", err)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "To log in, open %s and enter the code:\n\n    %s\n\n", code.VerificationURI, code.UserCode)
+		if noBrowser, _ := cmd.Flags().GetBool("no-browser"); !noBrowser {
+			link := code.VerificationURIComplete
+			if link == "" {
+				link = code.VerificationURI
+			}
+			if err := openBrowser(link); err == nil {
+				fmt.Fprintln(out, "A browser has been opened for you.")
+			}
+		}
+		fmt.Fprintf(out, "Waiting for approval for up to %d minutes (press Ctrl+C to cancel)...\n",
+			int((time.Duration(code.ExpiresIn) * time.Second).Minutes()))
+
+		where, err := finishLogin(ctx, code)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error logging in: %v\n", err)
+			return
+		}
+		fmt.Fprintf(out, "Logged in. Credentials are stored in %s.\n", where)
+	},
+}
+
+// logoutCmd removes the stored login
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Log out of the AlgoScales server",
+	Long: `Revoke this machine's login on the server and remove its stored
+credentials. Server features then fall back to the activated license key.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+
+		err := logout(ctx)
+		if errors.Is(err, auth.ErrNotLoggedIn) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Not logged in.")
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error logging out: %v\n", err)
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Logged out.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().Bool("no-browser", false, "Print the link without opening a browser")
+}
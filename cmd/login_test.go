@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin(t *testing.T) {
+	origStart, origFinish, origOpen := startLogin, finishLogin, openBrowser
+	t.Cleanup(func() { startLogin, finishLogin, openBrowser = origStart, origFinish, origOpen })
+
+	code := api.DeviceCode{
+		DeviceCode:              "device-1",
+		UserCode:                "BCDF-GHJK",
+		VerificationURI:         "https://api.example.com/device",
+		VerificationURIComplete: "https://api.example.com/device?code=BCDF-GHJK",
+		ExpiresIn:               600,
+	}
+	startLogin = func(ctx context.Context) (api.DeviceCode, error) { return code, nil }
+	var finished string
+	finishLogin = func(ctx context.Context, c api.DeviceCode) (string, error) {
+		finished = c.DeviceCode
+		return "the test keychain", nil
+	}
+	var opened string
+	openBrowser = func(url string) error {
+		opened = url
+		return nil
+	}
+
+	output, err := executeCommand(rootCmd, "login", "--no-browser=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "open https://api.example.com/device and enter the code:\n\n    BCDF-GHJK")
+	assert.Contains(t, output, "A browser has been opened for you.")
+	assert.Contains(t, output, "Waiting for approval for up to 10 minutes")
+	assert.Contains(t, output, "Logged in. Credentials are stored in the test keychain.")
+	assert.Equal(t, "https://api.example.com/device?code=BCDF-GHJK", opened)
+	assert.Equal(t, "device-1", finished)
+
+	opened = ""
+	finishLogin = func(ctx context.Context, c api.DeviceCode) (string, error) {
+		return "", errors.New("the login was not approved in time")
+	}
+	output, err = executeCommand(rootCmd, "login", "--no-browser")
+	require.NoError(t, err)
+	assert.Empty(t, opened)
+	assert.Contains(t, output, "Error logging in: the login was not approved in time")
+}
+
+func TestLogout(t *testing.T) {
+	original := logout
+	t.Cleanup(func() { logout = original })
+
+	logout = func(ctx context.Context) error { return nil }
+	output, err := executeCommand(rootCmd, "logout")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Logged out.")
+
+	logout = func(ctx context.Context) error { return auth.ErrNotLoggedIn }
+	output, err = executeCommand(rootCmd, "logout")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Not logged in.")
+}
@@ -0,0 +1,354 @@
+// MCP server mode, exposing problems, test runs and stats to editors and
+// assistants
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/mcp"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/services"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// mcpCmd groups the Model Context Protocol commands
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Model Context Protocol server for editors and assistants",
+	Long: `Serve Algo Scales to MCP clients, such as Neovim plugins and AI assistants,
+so they can browse problems, run tests and read your stats.`,
+}
+
+// mcpServeCmd serves the tools over stdio
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve MCP over stdin and stdout",
+	Long: `Speak the Model Context Protocol over stdin and stdout until the client
+disconnects. Start it from an MCP client rather than by hand:
+
+  {"command": "algo-scales", "args": ["mcp", "serve"]}
+
+The tools are:
+  list_problems  Problems, optionally filtered by pattern or difficulty
+  get_problem    A problem's description, examples and starter code
+  run_tests      Test a solution; submitting runs every tier and records it
+  get_stats      Your overall, per-pattern and per-context stats
+
+Problems are served without their solutions or hidden tests. Submissions
+are recorded in stats under the mcp context.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return newMCPServer().Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+	},
+}
+
+// mcpFilesCmd serves file tools confined to one directory over stdio
+var mcpFilesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Serve read-only file tools confined to a directory",
+	Long: `Speak the Model Context Protocol over stdin and stdout, serving read_file
+and list_directory for the files inside --root and nothing else. Paths that
+lead outside it, including through symbolic links, are refused.
+
+Every file read or listed, and every refused request, is appended to the
+audit log; 'algo-scales ai audit' shows it. The AI assistant starts this
+server itself, confined to the current problem's workspace, in place of a
+general file-system server.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, _ := cmd.Flags().GetString("root")
+		sessionName, _ := cmd.Flags().GetString("session")
+		auditPath, _ := cmd.Flags().GetString("audit-log")
+		if auditPath == "" {
+			auditPath = ai.DefaultAuditLogPath()
+		}
+
+		access, err := mcp.NewFileAccess(root, sessionName, mcp.NewAuditLog(auditPath))
+		if err != nil {
+			return err
+		}
+		server := mcp.NewServer("algo-scales-files", serverVersion())
+		for _, tool := range access.Tools() {
+			server.AddTool(tool)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return server.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+	},
+}
+
+// serverVersion is the version MCP servers introduce themselves with
+func serverVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// newMCPServer creates the MCP server with the Algo Scales tools
+func newMCPServer() *mcp.Server {
+	server := mcp.NewServer("algo-scales", serverVersion())
+	server.AddTool(mcp.Tool{
+		Name:        "list_problems",
+		Description: "List the practice problems, optionally filtered by algorithm pattern or difficulty.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"pattern":    stringProperty("Algorithm pattern, such as sliding-window or hash-map"),
+			"difficulty": stringProperty("easy, medium or hard"),
+		}),
+		Handler: mcpListProblems,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_problem",
+		Description: "Get a problem's description, examples, constraints, example tests and starter code. Solutions and hidden tests are not included.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"problem_id": stringProperty("Problem ID from list_problems"),
+			"language":   stringProperty("Only include starter code in this language (go, python or javascript)"),
+		}, "problem_id"),
+		Handler: mcpGetProblem,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "run_tests",
+		Description: "Test a solution. A test run uses the example tests; a submission runs every tier of tests and records the attempt in stats.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"problem_id": stringProperty("Problem ID from list_problems"),
+			"language":   stringProperty("go, python or javascript; go by default"),
+			"code":       stringProperty("The solution's source code"),
+			"file":       stringProperty("Path to the solution file, used when code is not given"),
+			"submit":     map[string]interface{}{"type": "boolean", "description": "Run every tier of tests and record the submission"},
+			"bench":      map[string]interface{}{"type": "boolean", "description": "Time a passing solution against the reference on large inputs"},
+		}, "problem_id"),
+		Handler: mcpRunTests,
+	})
+	server.AddTool(mcp.Tool{
+		Name:        "get_stats",
+		Description: "Get practice stats: totals, and attempts and success rates by pattern and by practice context.",
+		InputSchema: objectSchema(nil),
+		Handler:     mcpGetStats,
+	})
+	return server
+}
+
+// problemSummary is a problem as list_problems and 'list --output json'
+// describe it
+type problemSummary struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Difficulty    string   `json:"difficulty"`
+	Patterns      []string `json:"patterns"`
+	EstimatedTime int      `json:"estimated_time,omitempty"`
+	Companies     []string `json:"companies,omitempty"`
+}
+
+// mcpProblem is a problem as get_problem describes it, without anything
+// that gives the solution away
+type mcpProblem struct {
+	problemSummary
+	Description  string             `json:"description"`
+	Examples     []problem.Example  `json:"examples,omitempty"`
+	Constraints  []string           `json:"constraints,omitempty"`
+	ExampleTests []problem.TestCase `json:"example_tests,omitempty"`
+	StarterCode  map[string]string  `json:"starter_code,omitempty"`
+}
+
+// practiceStats is the result of get_stats, and of the daemon's stats
+type practiceStats struct {
+	Summary  *stats.Summary                `json:"summary"`
+	Patterns map[string]stats.PatternStats `json:"patterns"`
+	Contexts map[string]stats.ContextStats `json:"contexts"`
+}
+
+func mcpListProblems(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var params struct {
+		Pattern    string `json:"pattern"`
+		Difficulty string `json:"difficulty"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+
+	problems, err := services.DefaultRegistry.GetProblemService().ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problems: %v", err)
+	}
+
+	summaries := []problemSummary{}
+	for _, p := range problems {
+		if params.Difficulty != "" && !strings.EqualFold(p.Difficulty, params.Difficulty) {
+			continue
+		}
+		if params.Pattern != "" && !hasPattern(p, params.Pattern) {
+			continue
+		}
+		summaries = append(summaries, newProblemSummary(p))
+	}
+	return summaries, nil
+}
+
+func mcpGetProblem(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var params struct {
+		ProblemID string `json:"problem_id"`
+		Language  string `json:"language"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	if params.ProblemID == "" {
+		return nil, fmt.Errorf("problem_id is required")
+	}
+
+	p, err := services.DefaultRegistry.GetProblemService().GetByID(ctx, params.ProblemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem: %v", err)
+	}
+
+	result := mcpProblem{
+		problemSummary: newProblemSummary(*p),
+		Description:    p.Description,
+		Examples:       p.Examples,
+		Constraints:    p.Constraints,
+		StarterCode:    p.StarterCode,
+	}
+	for _, tc := range p.TestCases {
+		if tc.Tier.OrDefault() == interfaces.TierExample {
+			result.ExampleTests = append(result.ExampleTests, tc)
+		}
+	}
+	if params.Language != "" {
+		code, ok := p.StarterCode[params.Language]
+		if !ok {
+			return nil, fmt.Errorf("%s has no starter code in %s", p.ID, params.Language)
+		}
+		result.StarterCode = map[string]string{params.Language: code}
+	}
+	return result, nil
+}
+
+func mcpRunTests(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var params struct {
+		ProblemID string `json:"problem_id"`
+		Language  string `json:"language"`
+		Code      string `json:"code"`
+		File      string `json:"file"`
+		Submit    bool   `json:"submit"`
+		Bench     bool   `json:"bench"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	if params.ProblemID == "" {
+		return nil, fmt.Errorf("problem_id is required")
+	}
+	if params.Language == "" {
+		params.Language = "go"
+	}
+
+	code := params.Code
+	if code == "" {
+		if params.File == "" {
+			return nil, fmt.Errorf("code or file is required")
+		}
+		content, err := os.ReadFile(params.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %v", err)
+		}
+		code = string(content)
+	}
+
+	p, err := services.DefaultRegistry.GetProblemService().GetByID(ctx, params.ProblemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem: %v", err)
+	}
+
+	return runSolutionTests(ctx, p, params.Language, code, solutionRun{
+		FilePath:  params.File,
+		Submit:    params.Submit,
+		Benchmark: params.Bench,
+		Context:   interfaces.ContextMCP,
+	})
+}
+
+func mcpGetStats(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	return loadPracticeStats()
+}
+
+// loadPracticeStats reads the overall, per-pattern and per-context stats
+func loadPracticeStats() (*practiceStats, error) {
+	summary, err := stats.GetSummary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %v", err)
+	}
+	patterns, err := stats.GetByPattern()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern stats: %v", err)
+	}
+	contexts, err := stats.GetByContext()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get context stats: %v", err)
+	}
+	return &practiceStats{Summary: summary, Patterns: patterns, Contexts: contexts}, nil
+}
+
+// mcpSummary describes a problem for list_problems
+func newProblemSummary(p problem.Problem) problemSummary {
+	return problemSummary{
+		ID:            p.ID,
+		Title:         p.Title,
+		Difficulty:    p.Difficulty,
+		Patterns:      p.Patterns,
+		EstimatedTime: p.EstimatedTime,
+		Companies:     p.Companies,
+	}
+}
+
+// hasPattern reports whether a problem teaches a pattern
+func hasPattern(p problem.Problem, pattern string) bool {
+	for _, candidate := range p.Patterns {
+		if strings.EqualFold(candidate, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// objectSchema is the JSON Schema of an object with the given properties
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// stringProperty is the JSON Schema of a described string
+func stringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.AddCommand(mcpServeCmd)
+	mcpCmd.AddCommand(mcpFilesCmd)
+
+	mcpFilesCmd.Flags().String("root", "", "The only directory whose files can be read")
+	mcpFilesCmd.Flags().String("session", "", "Session the reads are recorded under")
+	mcpFilesCmd.Flags().String("audit-log", "", "Where reads are recorded (default ~/.algo-scales/ai-file-access.log)")
+	mcpFilesCmd.MarkFlagRequired("root")
+}
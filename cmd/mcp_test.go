@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callMCPTool calls a tool on the MCP server, returning its text result and
+// whether the call failed
+func callMCPTool(t *testing.T, name string, args interface{}) (string, bool) {
+	t.Helper()
+	request, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, newMCPServer().Serve(context.Background(), strings.NewReader(string(request)+"\n"), &out))
+
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp), out.String())
+	require.Len(t, resp.Result.Content, 1)
+	return resp.Result.Content[0].Text, resp.Result.IsError
+}
+
+func TestMCPListProblems(t *testing.T) {
+	text, isError := callMCPTool(t, "list_problems", map[string]string{"difficulty": "easy"})
+	require.False(t, isError, text)
+
+	var problems []problemSummary
+	require.NoError(t, json.Unmarshal([]byte(text), &problems))
+	require.NotEmpty(t, problems)
+	for _, p := range problems {
+		assert.Equal(t, "easy", strings.ToLower(p.Difficulty))
+	}
+}
+
+func TestMCPGetProblem(t *testing.T) {
+	text, isError := callMCPTool(t, "get_problem", map[string]string{"problem_id": "two_sum", "language": "go"})
+	require.False(t, isError, text)
+
+	var p map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &p))
+	assert.Equal(t, "two_sum", p["id"])
+	assert.Contains(t, p["starter_code"], "go")
+	assert.Len(t, p["starter_code"], 1, "only the requested language's starter code is sent")
+	for _, field := range []string{"solutions", "solution_walkthrough", "test_cases"} {
+		assert.NotContains(t, p, field, "get_problem must not give the solution away")
+	}
+
+	_, isError = callMCPTool(t, "get_problem", map[string]string{"problem_id": "no_such_problem"})
+	assert.True(t, isError)
+}
+
+func TestMCPRunTests(t *testing.T) {
+	stubHintTracker(t, config.HintPolicy{})
+	stubComplexity(t, complexity.Feedback{Time: complexity.Linear, Space: complexity.Linear, Source: complexity.SourceHeuristic})
+	recorded := stubRecordSession(t)
+
+	solution := `func twoSum(nums []int, target int) []int {
+		seen := make(map[int]int)
+		for i, num := range nums {
+			if j, ok := seen[target-num]; ok {
+				return []int{j, i}
+			}
+			seen[num] = i
+		}
+		return nil
+	}`
+
+	text, isError := callMCPTool(t, "run_tests", map[string]interface{}{
+		"problem_id": "two_sum",
+		"code":       solution,
+		"submit":     true,
+	})
+	require.False(t, isError, text)
+
+	var resp VimSubmitResponse
+	require.NoError(t, json.Unmarshal([]byte(text), &resp))
+	assert.NotEmpty(t, resp.TestResults)
+	require.Len(t, *recorded, 1, "a submission is recorded as a session")
+	assert.Equal(t, interfaces.ContextMCP, (*recorded)[0].Context)
+	assert.Equal(t, resp.Passed, (*recorded)[0].Solved)
+
+	text, isError = callMCPTool(t, "run_tests", map[string]string{"problem_id": "two_sum"})
+	assert.True(t, isError)
+	assert.Contains(t, text, "code or file is required")
+}
+
+func TestMCPGetStats(t *testing.T) {
+	origSummary, origPattern, origContext := stats.GetSummary, stats.GetByPattern, stats.GetByContext
+	t.Cleanup(func() {
+		stats.GetSummary, stats.GetByPattern, stats.GetByContext = origSummary, origPattern, origContext
+	})
+	stats.GetSummary = func() (*stats.Summary, error) {
+		return &stats.Summary{TotalAttempted: 3, TotalSolved: 2}, nil
+	}
+	stats.GetByPattern = func() (map[string]stats.PatternStats, error) {
+		return map[string]stats.PatternStats{"hash-map": {Pattern: "hash-map", Attempted: 3, Solved: 2}}, nil
+	}
+	stats.GetByContext = func() (map[string]stats.ContextStats, error) {
+		return map[string]stats.ContextStats{interfaces.ContextMCP: {Context: interfaces.ContextMCP, Attempted: 3, Solved: 2}}, nil
+	}
+
+	text, isError := callMCPTool(t, "get_stats", nil)
+	require.False(t, isError, text)
+
+	var result practiceStats
+	require.NoError(t, json.Unmarshal([]byte(text), &result))
+	assert.Equal(t, 3, result.Summary.TotalAttempted)
+	assert.Equal(t, 2, result.Patterns["hash-map"].Solved)
+	assert.Equal(t, 3, result.Contexts[interfaces.ContextMCP].Attempted)
+}
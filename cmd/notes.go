@@ -0,0 +1,175 @@
+// notes command, for the Markdown notes kept on each problem
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// notesResult is a problem's notes as 'notes --output json' describes them
+type notesResult struct {
+	ProblemID string `json:"problem_id"`
+	Notes     string `json:"notes"`
+	Changed   bool   `json:"changed,omitempty"` // Set by notes edit and clear when the notes changed
+}
+
+// editNotesFile opens a file in the user's editor, returning once it closes
+// Exported as variable for testing
+var editNotesFile = openEditor
+
+// notesCmd prints a problem's notes
+var notesCmd = &cobra.Command{
+	Use:   "notes <problem>",
+	Short: "Keep Markdown notes on a problem",
+	Long: `Print the notes you keep on a problem: your approach, the gotchas that
+caught you, anything to remember on the next attempt. Notes are freeform
+Markdown. They are shown when you start the problem again, in the TUI session,
+in 'algo-scales cli' and in 'algo-scales show'. Press N in a TUI session to
+edit them there.
+
+Examples:
+  algo-scales notes edit two_sum
+  algo-scales notes two_sum
+  algo-scales notes clear two_sum`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "reading notes", err)
+			return
+		}
+		notes, err := problem.LoadNotes(p.ID)
+		if err != nil {
+			commandError(cmd, "reading notes", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, notesResult{ProblemID: p.ID, Notes: notes})
+			return
+		}
+		if notes == "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "No notes on %s yet. Write some with: algo-scales notes edit %s\n", p.ID, p.ID)
+			return
+		}
+		fmt.Fprint(cmd.OutOrStdout(), notes)
+	},
+}
+
+// notesEditCmd edits a problem's notes in the user's editor
+var notesEditCmd = &cobra.Command{
+	Use:   "edit <problem>",
+	Short: "Edit a problem's notes in your editor",
+	Long: `Open a problem's notes in $EDITOR, or in vim or nano if it is not set, and
+save them when the editor closes. A problem without notes starts from a short
+outline. Saving the notes empty deletes them.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			commandError(cmd, "editing notes", err)
+			return
+		}
+		notes, err := problem.LoadNotes(p.ID)
+		if err != nil {
+			commandError(cmd, "editing notes", err)
+			return
+		}
+		draft := notes
+		if draft == "" {
+			draft = problem.NotesOutline(*p)
+		}
+		edited, err := editNotes(draft)
+		if err != nil {
+			commandError(cmd, "editing notes", err)
+			return
+		}
+
+		// An untouched outline is not worth keeping
+		changed := edited != draft
+		if changed {
+			if err := problem.SaveNotes(p.ID, edited); err != nil {
+				commandError(cmd, "saving notes", err)
+				return
+			}
+			notes, _ = problem.LoadNotes(p.ID)
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, notesResult{ProblemID: p.ID, Notes: notes, Changed: changed})
+			return
+		}
+		switch {
+		case !changed:
+			fmt.Fprintf(cmd.OutOrStdout(), "Notes on %s unchanged.\n", p.ID)
+		case notes == "":
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted the notes on %s.\n", p.ID)
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved the notes on %s.\n", p.ID)
+		}
+	},
+}
+
+// notesClearCmd deletes a problem's notes
+var notesClearCmd = &cobra.Command{
+	Use:   "clear <problem>",
+	Short: "Delete a problem's notes",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Problems removed since they were noted can still have their notes cleared
+		id := args[0]
+		notes, err := problem.LoadNotes(id)
+		if err == nil && notes != "" {
+			err = problem.SaveNotes(id, "")
+		}
+		if err != nil {
+			commandError(cmd, "deleting notes", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, notesResult{ProblemID: id, Changed: notes != ""})
+			return
+		}
+		if notes == "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s has no notes.\n", id)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted the notes on %s.\n", id)
+	},
+}
+
+// editNotes opens notes in the user's editor in a temporary file, and
+// returns them as the editor left them
+func editNotes(notes string) (string, error) {
+	f, err := os.CreateTemp("", "algo-scales-notes-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a file to edit: %v", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(notes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write the notes to edit: %v", err)
+	}
+
+	editNotesFile(f.Name())
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read the edited notes: %v", err)
+	}
+	return string(data), nil
+}
+
+func init() {
+	notesCmd.AddCommand(notesEditCmd)
+	notesCmd.AddCommand(notesClearCmd)
+	rootCmd.AddCommand(notesCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotes(t *testing.T) {
+	stubUserData(t)
+	original := editNotesFile
+	t.Cleanup(func() { editNotesFile = original })
+	var opened string
+	edit := func(text string) func(path string) {
+		return func(path string) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			opened = string(data)
+			require.NoError(t, os.WriteFile(path, []byte(text), 0644))
+		}
+	}
+
+	output, err := executeCommand(rootCmd, "notes", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No notes on two_sum yet.")
+
+	// An outline left untouched is not saved
+	editNotesFile = func(path string) {}
+	output, err = executeCommand(rootCmd, "notes", "edit", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Notes on two_sum unchanged.")
+
+	editNotesFile = edit("# Two Sum\n\nStore each complement in a map.\n")
+	output, err = executeCommand(rootCmd, "notes", "edit", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Saved the notes on two_sum.")
+	assert.Contains(t, opened, "## Approach", "first notes start from the outline")
+
+	output, err = executeCommand(rootCmd, "notes", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Store each complement in a map.")
+	output, err = executeCommand(rootCmd, "show", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "## Your Notes\n\n# Two Sum\n\nStore each complement in a map.")
+
+	// Later edits start from the saved notes
+	editNotesFile = edit("")
+	output, err = executeCommand(rootCmd, "notes", "edit", "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, "# Two Sum\n\nStore each complement in a map.\n", opened)
+	assert.Contains(t, output, "Deleted the notes on two_sum.")
+
+	output, err = executeCommand(rootCmd, "notes", "clear", "two_sum")
+	require.NoError(t, err)
+	assert.Contains(t, output, "two_sum has no notes.")
+	notes, err := problem.LoadNotes("two_sum")
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestNotesJSON(t *testing.T) {
+	stubUserData(t)
+	require.NoError(t, problem.SaveNotes("two_sum", "hash map"))
+
+	output, code := executeJSONCommand(t, "notes", "two_sum")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"problem_id":"two_sum","notes":"hash map\n"}`, output)
+
+	output, code = executeJSONCommand(t, "notes", "clear", "two_sum")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"problem_id":"two_sum","notes":"","changed":true}`, output)
+}
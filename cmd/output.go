@@ -0,0 +1,76 @@
+// Machine-readable output for editor extensions and scripts
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Formats --output takes
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Exit codes of commands run with --output json
+const (
+	exitFailed      = 1 // The command failed; the error is printed as {"error": "..."}
+	exitTestsFailed = 2 // The tests ran, and not all of them passed
+)
+
+// exit ends the process with a code
+// Exported as variable for testing
+var exit = os.Exit
+
+// outputFormat returns the global --output. It is read from the inherited
+// flags, as data export has an --output of its own.
+func outputFormat(cmd *cobra.Command) string {
+	flags := cmd.InheritedFlags()
+	if !cmd.HasParent() {
+		flags = cmd.PersistentFlags()
+	}
+	format, _ := flags.GetString("output")
+	return format
+}
+
+// checkOutputFormat rejects an --output the commands don't know
+func checkOutputFormat(cmd *cobra.Command) error {
+	switch format := outputFormat(cmd); format {
+	case "", outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q; use text or json", format)
+	}
+}
+
+// jsonOutput reports whether --output json was given
+func jsonOutput(cmd *cobra.Command) bool {
+	return outputFormat(cmd) == outputJSON
+}
+
+// writeJSON prints v as one line of JSON
+func writeJSON(cmd *cobra.Command, v interface{}) {
+	if err := json.NewEncoder(cmd.OutOrStdout()).Encode(v); err != nil {
+		failJSON(cmd, fmt.Errorf("failed to marshal response: %v", err))
+	}
+}
+
+// failJSON prints err as {"error": "..."} and exits with exitFailed
+func failJSON(cmd *cobra.Command, err error) {
+	json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]string{"error": err.Error()})
+	exit(exitFailed)
+}
+
+// commandError reports that a command failed while doing something: as
+// JSON with --output json, or as "Error <doing>: <err>" on stderr
+func commandError(cmd *cobra.Command, doing string, err error) {
+	if jsonOutput(cmd) {
+		failJSON(cmd, fmt.Errorf("error %s: %v", doing, err))
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Error %s: %v\n", doing, err)
+}
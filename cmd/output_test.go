@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// executeJSONCommand runs a command with --output json, returning what it
+// printed and the exit code it asked for
+func executeJSONCommand(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	code := 0
+	original := exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = original })
+
+	root := &cobra.Command{Use: rootCmd.Use}
+	root.PersistentFlags().String("output", outputText, "")
+	for _, sub := range rootCmd.Commands() {
+		root.AddCommand(sub)
+	}
+	out := new(bytes.Buffer)
+	root.SetOut(out)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append(args, "--output", "json"))
+	cmd, err := root.ExecuteC()
+	require.NoError(t, err)
+	// The command keeps the flag it parsed, whichever root it is run from next
+	t.Cleanup(func() { cmd.InheritedFlags().Set("output", outputText) })
+	return out.String(), code
+}
+
+func TestCheckOutputFormat(t *testing.T) {
+	root := &cobra.Command{Use: "algo-scales"}
+	root.PersistentFlags().String("output", outputText, "")
+
+	assert.NoError(t, checkOutputFormat(root))
+	require.NoError(t, root.PersistentFlags().Set("output", "json"))
+	assert.NoError(t, checkOutputFormat(root))
+	require.NoError(t, root.PersistentFlags().Set("output", "yaml"))
+	assert.EqualError(t, checkOutputFormat(root), `unknown output format "yaml"; use text or json`)
+}
+
+func TestListJSON(t *testing.T) {
+	twoSum := problem.Problem{ID: "two_sum", Title: "Two Sum", Difficulty: "easy", Patterns: []string{"hash-map"}, Solutions: map[string]string{"go": "secret"}}
+	defer mockListAll([]problem.Problem{twoSum}, nil)()
+	defer mockListPatterns(map[string][]problem.Problem{"hash-map": {twoSum}}, nil)()
+
+	out, code := executeJSONCommand(t, "list")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"problems": [{"id": "two_sum", "title": "Two Sum", "difficulty": "easy", "patterns": ["hash-map"]}]}`, out)
+
+	out, _ = executeJSONCommand(t, "list", "patterns")
+	assert.JSONEq(t, `{"patterns": {"hash-map": [{"id": "two_sum", "title": "Two Sum", "difficulty": "easy", "patterns": ["hash-map"]}]}}`, out)
+
+	defer mockListAll(nil, assert.AnError)()
+	out, code = executeJSONCommand(t, "list")
+	assert.Equal(t, exitFailed, code)
+	assert.JSONEq(t, `{"error": "error listing problems: `+assert.AnError.Error()+`"}`, out)
+}
+
+func TestStatsJSON(t *testing.T) {
+	defer mockGetSummary(&stats.Summary{TotalAttempted: 4, TotalSolved: 3}, nil)()
+	defer mockHintReport(hints.Report{Policy: config.HintPolicy{}, Hints: 2, AIHints: 1, Problems: 1}, nil)()
+	defer mockComplexity([]storage.Complexity{{Verdict: "optimal"}, {Verdict: "slower"}, {Verdict: "unknown"}}, nil)()
+
+	out, code := executeJSONCommand(t, "stats")
+	assert.Equal(t, 0, code)
+	var resp statsResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, 3, resp.Summary.TotalSolved)
+	assert.Equal(t, 2, resp.Hints.Hints)
+	assert.Equal(t, complexityTotals{Analyzed: 2, Matching: 1, Slower: 1}, resp.Complexity)
+
+	defer mockGetTrends(&stats.Trends{Daily: []stats.DailyTrend{{Date: "2024-01-02", Solved: 1, AvgTime: "10:00"}}}, nil)()
+	defer mockGetPatternTrends(stats.PatternTrends{Weeks: 8, Recommended: "dfs", Reason: "your weakest pattern"}, nil)()
+	out, _ = executeJSONCommand(t, "stats", "trends")
+	assert.JSONEq(t, `{"daily": [{"date": "2024-01-02", "solved": 1, "avg_time": "10:00"}], "weekly": null,
+		"weeks": 8, "patterns": null, "recommended": "dfs", "reason": "your weakest pattern"}`, out)
+}
+
+func TestTestCommandJSON(t *testing.T) {
+	stubHintTracker(t, config.HintPolicy{})
+	dir := t.TempDir()
+	originalPath := storage.DBPath
+	t.Cleanup(func() { storage.DBPath = originalPath })
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+
+	file := filepath.Join(dir, "solution.go")
+	require.NoError(t, os.WriteFile(file, []byte("func twoSum(nums []int, target int) []int {\n\treturn nil\n}\n"), 0644))
+
+	out, code := executeJSONCommand(t, "test", "--problem-id", "two_sum", "--language", "go", "--file", file)
+	assert.Equal(t, exitTestsFailed, code, "failing tests exit with their own code")
+	var resp VimSubmitResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.False(t, resp.Passed)
+	assert.NotEmpty(t, resp.TestResults)
+
+	out, code = executeJSONCommand(t, "test", "--problem-id", "two_sum", "--language", "go", "--file", filepath.Join(dir, "missing.go"))
+	assert.Equal(t, exitFailed, code)
+	assert.Contains(t, out, `"error":"failed to read file`)
+}
+
+func TestDailyStatusJSON(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	originalPath := storage.DBPath
+	t.Cleanup(func() { storage.DBPath = originalPath })
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+
+	_, code := executeJSONCommand(t, "daily", "status")
+	assert.Equal(t, exitFailed, code, "there is no session yet")
+
+	dailySession, err := daily.GetOrCreateSession()
+	require.NoError(t, err)
+	require.NoError(t, dailySession.StartProblem(daily.Scales[0].Pattern, "max_sum_subarray"))
+
+	out, code := executeJSONCommand(t, "daily", "status")
+	assert.Equal(t, 0, code)
+	var resp dailyStatusResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, dailySession.Date, resp.Date)
+	require.Len(t, resp.Problems, len(daily.Scales))
+	assert.Equal(t, dailyProblemStatus{
+		Pattern:   daily.Scales[0].Pattern,
+		Scale:     daily.Scales[0].MusicalName,
+		ProblemID: "max_sum_subarray",
+		State:     daily.StateInProgress,
+		Attempts:  1,
+	}, resp.Problems[0])
+	assert.Equal(t, len(daily.Scales)-1, resp.Progress.Pending)
+}
+
+func TestDailyRoutineStatusJSON(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	originalPath := storage.DBPath
+	t.Cleanup(func() { storage.DBPath = originalPath })
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+	original := daily.LoadRoutine
+	t.Cleanup(func() { daily.LoadRoutine = original })
+	daily.LoadRoutine = func() daily.Routine {
+		return daily.Routine{Name: "graphs", Patterns: []string{"dfs", "bfs"}, ProblemsPerPattern: 2, DifficultyWeights: map[string]int{"hard": 1}}
+	}
+	_, err := daily.GetOrCreateSession()
+	require.NoError(t, err)
+
+	out, code := executeJSONCommand(t, "daily", "status")
+	assert.Equal(t, 0, code)
+	var resp dailyStatusResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	assert.Equal(t, "graphs", resp.Routine)
+	require.Len(t, resp.Problems, 4)
+	var patterns []string
+	for _, p := range resp.Problems {
+		patterns = append(patterns, p.Pattern)
+		assert.Equal(t, "hard", p.TargetDifficulty)
+	}
+	assert.Equal(t, []string{"dfs", "dfs", "bfs", "bfs"}, patterns, "problems are in the routine's order")
+}
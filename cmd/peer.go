@@ -0,0 +1,162 @@
+// Peer command for the anonymous peer review exchange
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/spf13/cobra"
+)
+
+// peerCmd represents the peer command
+var peerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Exchange anonymous reviews of solutions with other users",
+	Long: `Peer review is opt-in: set "peerReview": true in your config and every
+problem you solve is shared anonymously with other users solving the same
+problem. In return, review one of theirs with 'algo-scales peer review'.
+
+Without a subcommand, shows the reviews you have received.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		peerInboxCmd.Run(cmd, args)
+	},
+}
+
+// peerInboxCmd represents the inbox subcommand for peer
+var peerInboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Show the reviews you have received",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		reviews, err := api.GetReviewInbox(ctx)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error fetching reviews: %v\n", err)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		if len(reviews) == 0 {
+			fmt.Fprintln(out, "No reviews yet. Reviews arrive as other users review your shared solutions.")
+			return
+		}
+
+		fmt.Fprintf(out, "Review Inbox (%d)\n", len(reviews))
+		for _, r := range reviews {
+			fmt.Fprintf(out, "\n%s  %s  %s\n", r.ProblemID, ratingStars(r.Rating), r.CreatedAt.Local().Format("2006-01-02 15:04"))
+			for _, line := range strings.Split(strings.TrimSpace(r.Comment), "\n") {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+	},
+}
+
+// peerReviewCmd represents the review subcommand for peer
+var peerReviewCmd = &cobra.Command{
+	Use:   "review [problem-id]",
+	Short: "Review another user's solution",
+	Long: `Fetch an anonymous solution from another user who solved a problem you
+have shared a solution for, and send them a review. Without --comment the
+review is read from standard input, ending with a line containing only "."`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		problemID := ""
+		if len(args) > 0 {
+			problemID = args[0]
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		sub, err := api.NextReview(ctx, problemID)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error fetching a solution to review: %v\n", err)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		if sub == nil {
+			fmt.Fprintln(out, "No solutions are waiting for review. Check back later.")
+			return
+		}
+
+		fmt.Fprintf(out, "Problem: %s (%s)\n\n", sub.ProblemID, sub.Language)
+		fmt.Fprintln(out, strings.TrimRight(codefmt.Code(sub.Language, sub.Code), "\n"))
+		fmt.Fprintln(out)
+
+		in := bufio.NewReader(cmd.InOrStdin())
+
+		rating, _ := cmd.Flags().GetInt("rating")
+		if rating == 0 {
+			fmt.Fprint(out, "Rating (1-5): ")
+			line, _ := in.ReadString('\n')
+			rating, _ = strconv.Atoi(strings.TrimSpace(line))
+		}
+		if rating < 1 || rating > 5 {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Error: rating must be between 1 and 5")
+			return
+		}
+
+		comment, _ := cmd.Flags().GetString("comment")
+		if comment == "" {
+			fmt.Fprintln(out, "Review (end with a line containing only \".\"):")
+			comment = readReviewComment(in)
+		}
+		if strings.TrimSpace(comment) == "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Error: a review needs a comment")
+			return
+		}
+
+		if err := api.SendReview(ctx, sub.ID, api.Review{Rating: rating, Comment: comment}); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error sending review: %v\n", err)
+			return
+		}
+		fmt.Fprintln(out, "Thanks! Your review was sent anonymously.")
+	},
+}
+
+// readReviewComment reads lines until a line containing only "." or EOF
+func readReviewComment(r *bufio.Reader) string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "." {
+			break
+		}
+		lines = append(lines, line)
+		if err != nil {
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ratingStars renders a 1-5 rating as stars
+func ratingStars(rating int) string {
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 5 {
+		rating = 5
+	}
+	return strings.Repeat(symbols.Star.String(), rating) + strings.Repeat(symbols.StarEmpty.String(), 5-rating)
+}
+
+func init() {
+	rootCmd.AddCommand(peerCmd)
+	peerCmd.AddCommand(peerInboxCmd)
+	peerCmd.AddCommand(peerReviewCmd)
+
+	peerReviewCmd.Flags().Int("rating", 0, "Rating from 1 to 5")
+	peerReviewCmd.Flags().String("comment", "", "Review comment")
+}
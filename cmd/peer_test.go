@@ -0,0 +1,29 @@
+// Tests for peer command
+
+package cmd
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadReviewComment(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("Clear names.\n\nConsider an early return.\n.\nignored\n"))
+	assert.Equal(t, "Clear names.\n\nConsider an early return.", readReviewComment(in))
+
+	// EOF ends the comment too
+	in = bufio.NewReader(strings.NewReader("Looks good"))
+	assert.Equal(t, "Looks good", readReviewComment(in))
+}
+
+func TestRatingStars(t *testing.T) {
+	symbols.SetASCII(true)
+	defer symbols.SetASCII(false)
+
+	assert.Equal(t, "***--", ratingStars(3))
+	assert.Equal(t, "*****", ratingStars(7))
+}
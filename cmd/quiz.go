@@ -0,0 +1,232 @@
+// quiz command, for practising pattern recognition
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/quiz"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// quizAccuracy is a pattern's recognition accuracy as
+// 'quiz stats --output json' describes it
+type quizAccuracy struct {
+	Pattern  string  `json:"pattern"`
+	Answered int     `json:"answered"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"` // Percentage answered right
+	Mistaken string  `json:"mistaken_for,omitempty"`
+}
+
+// loadQuizAnswers returns the answers given in the pattern quiz
+// Exported as variable for testing
+var loadQuizAnswers = func() ([]storage.QuizAnswer, error) {
+	repo := storage.Default()
+	defer repo.Close()
+	return repo.LoadQuizAnswers(context.Background())
+}
+
+// quizCmd asks which pattern solves each of a few problems
+var quizCmd = &cobra.Command{
+	Use:   "quiz",
+	Short: "Practice recognizing which pattern solves a problem",
+	Long: `Show problem descriptions one at a time, each with a few patterns to choose
+from, and pick the pattern that solves it before writing any code. Each answer
+is marked right or wrong straight away, with an explanation of the pattern.
+Paragraphs of a description that name its pattern are left out.
+
+Answers are kept, and 'algo-scales quiz stats' shows how accurately you
+recognize each pattern. Answer with a choice's number or the pattern's name,
+or q to stop early.
+
+Examples:
+  algo-scales quiz
+  algo-scales quiz --pattern sliding-window --count 5
+  algo-scales quiz stats`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		count, _ := cmd.Flags().GetInt("count")
+		choices, _ := cmd.Flags().GetInt("choices")
+		if count < 1 {
+			commandError(cmd, "starting the quiz", fmt.Errorf("--count must be at least 1"))
+			return
+		}
+		if choices < 2 {
+			commandError(cmd, "starting the quiz", fmt.Errorf("--choices must be at least 2"))
+			return
+		}
+
+		problems, err := problem.ListAll()
+		if err != nil {
+			commandError(cmd, "starting the quiz", err)
+			return
+		}
+		if pattern != "" {
+			if pattern, err = resolveQuizPattern(problems, pattern); err != nil {
+				commandError(cmd, "starting the quiz", err)
+				return
+			}
+		}
+		questions := quiz.Questions(problems, pattern, count, choices)
+		if len(questions) == 0 {
+			commandError(cmd, "starting the quiz", fmt.Errorf("no problems to ask about"))
+			return
+		}
+
+		repo := storage.Default()
+		defer repo.Close()
+		runQuiz(cmd, repo, questions)
+	},
+}
+
+// quizStatsCmd shows how accurately each pattern is recognized
+var quizStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how accurately you recognize each pattern",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		answers, err := loadQuizAnswers()
+		if err != nil {
+			commandError(cmd, "retrieving quiz answers", err)
+			return
+		}
+		accuracy := quiz.ByPattern(answers)
+
+		if jsonOutput(cmd) {
+			entries := []quizAccuracy{}
+			for _, a := range accuracy {
+				entries = append(entries, quizAccuracy{
+					Pattern:  a.Pattern,
+					Answered: a.Answered,
+					Correct:  a.Correct,
+					Accuracy: a.Rate(),
+					Mistaken: a.Mistaken,
+				})
+			}
+			writeJSON(cmd, map[string][]quizAccuracy{"patterns": entries})
+			return
+		}
+		writeQuizStats(cmd.OutOrStdout(), accuracy)
+	},
+}
+
+// runQuiz asks each question in turn, saving every answer, until the
+// questions run out, the input ends or the user stops
+func runQuiz(cmd *cobra.Command, repo storage.Repository, questions []quiz.Question) {
+	out := cmd.OutOrStdout()
+	in := bufio.NewReader(cmd.InOrStdin())
+
+	asked, right := 0, 0
+	for i, q := range questions {
+		fmt.Fprintf(out, "Question %d of %d: %s (%s)\n\n", i+1, len(questions), q.Problem.Title, q.Problem.Difficulty)
+		if prompt := q.Problem.Prompt(); prompt != "" {
+			fmt.Fprintf(out, "%s\n\n", prompt)
+		}
+		fmt.Fprintln(out, "Which pattern solves this?")
+		for n, choice := range q.Choices {
+			fmt.Fprintf(out, "  %d. %s\n", n+1, problem.PatternName(choice))
+		}
+
+		chosen, ok := readQuizChoice(in, out, q.Choices)
+		if !ok {
+			break
+		}
+		answer := q.Answer(chosen, time.Now())
+		if err := repo.SaveQuizAnswer(context.Background(), answer); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error saving the answer: %v\n", err)
+		}
+		asked++
+
+		if answer.Correct {
+			right++
+			fmt.Fprintf(out, "%s Right: %s.\n", symbols.Pass, problem.PatternName(q.Pattern))
+		} else {
+			fmt.Fprintf(out, "%s Not quite: this is %s, not %s.\n", symbols.Fail, problem.PatternName(q.Pattern), problem.PatternName(chosen))
+		}
+		if explanation := strings.TrimSpace(q.Problem.PatternExplanation); explanation != "" {
+			fmt.Fprintf(out, "%s\n", explanation)
+		}
+		fmt.Fprintln(out)
+	}
+
+	if asked == 0 {
+		fmt.Fprintln(out, "No questions answered.")
+		return
+	}
+	fmt.Fprintf(out, "You recognized %d of %d pattern(s).\n", right, asked)
+	fmt.Fprintln(out, "See your accuracy by pattern with: algo-scales quiz stats")
+}
+
+// readQuizChoice prompts until a choice is picked by number or pattern name,
+// reporting false if the user stops or the input ends
+func readQuizChoice(in *bufio.Reader, out io.Writer, choices []string) (string, bool) {
+	for {
+		fmt.Fprintf(out, "Your answer (1-%d, q to stop): ", len(choices))
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "q" || (line == "" && err != nil) {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], true
+		}
+		for _, choice := range choices {
+			if strings.EqualFold(line, choice) || strings.EqualFold(line, problem.PatternName(choice)) {
+				return choice, true
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		fmt.Fprintf(out, "Pick a number from 1 to %d.\n", len(choices))
+	}
+}
+
+// resolveQuizPattern returns the pattern of problems meant by query, in full
+// or by its initials, so dp is dynamic-programming
+func resolveQuizPattern(problems []problem.Problem, query string) (string, error) {
+	for _, p := range problems {
+		for _, pattern := range p.Patterns {
+			if matchesPattern(pattern, query) {
+				return pattern, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no problems found for pattern %q", query)
+}
+
+// writeQuizStats prints the recognition accuracy of each pattern
+func writeQuizStats(out io.Writer, accuracy []quiz.Accuracy) {
+	if len(accuracy) == 0 {
+		fmt.Fprintln(out, "No quiz answers yet. Start a quiz with: algo-scales quiz")
+		return
+	}
+	fmt.Fprintln(out, "Pattern Recognition:")
+	for _, a := range accuracy {
+		line := fmt.Sprintf("  %-22s %3.0f%% (%d of %d)", problem.PatternName(a.Pattern), a.Rate(), a.Correct, a.Answered)
+		if a.Mistaken != "" {
+			line += ", most often mistaken for " + problem.PatternName(a.Mistaken)
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+func init() {
+	quizCmd.Flags().StringP("pattern", "p", "", "Only ask about this pattern's problems")
+	quizCmd.Flags().IntP("count", "n", quiz.DefaultQuestions, "How many questions to ask")
+	quizCmd.Flags().Int("choices", quiz.DefaultChoices, "How many patterns to choose from")
+	quizCmd.AddCommand(quizStatsCmd)
+	rootCmd.AddCommand(quizCmd)
+}
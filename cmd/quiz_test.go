@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/quiz"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quizInput answers the quiz's questions from input
+func quizInput(t *testing.T, input string) {
+	t.Helper()
+	quizCmd.SetIn(strings.NewReader(input))
+	t.Cleanup(func() { quizCmd.SetIn(nil) })
+}
+
+func TestQuiz(t *testing.T) {
+	stubUserData(t)
+
+	// An unknown answer asks again, then the pattern's name is accepted
+	quizInput(t, "7\nhash map\n")
+	output, err := executeCommand(rootCmd, "quiz", "--pattern=hash-map", "--count=1", "--choices=2")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Question 1 of 1: Two Sum (easy)")
+	assert.Contains(t, output, "Which pattern solves this?")
+	assert.Contains(t, output, "Pick a number from 1 to 2.")
+	assert.Contains(t, output, symbols.Pass.String()+" Right: Hash Map.")
+	assert.Contains(t, output, "The hash map pattern is extremely useful")
+	assert.Contains(t, output, "You recognized 1 of 1 pattern(s).")
+
+	quizInput(t, "q\n")
+	output, err = executeCommand(rootCmd, "quiz", "--pattern=hash-map", "--count=1", "--choices=2")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No questions answered.")
+
+	output, err = executeCommand(rootCmd, "quiz", "--pattern=no-such-pattern", "--count=1", "--choices=2")
+	require.NoError(t, err)
+	assert.Contains(t, output, `no problems found for pattern "no-such-pattern"`)
+
+	output, err = executeCommand(rootCmd, "quiz", "--pattern=", "--count=1", "--choices=1")
+	require.NoError(t, err)
+	assert.Contains(t, output, "--choices must be at least 2")
+}
+
+func TestRunQuizWrongAnswer(t *testing.T) {
+	stubUserData(t)
+	repo := storage.Default()
+	defer repo.Close()
+
+	q := quiz.Question{
+		Problem: problem.Problem{ID: "two_sum", Title: "Two Sum", Difficulty: "easy", Patterns: []string{"hash-map"}},
+		Pattern: "hash-map",
+		Choices: []string{"two-pointers", "hash-map"},
+	}
+	cmd := &cobra.Command{}
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("1\n"))
+
+	// The input ends before the second question
+	runQuiz(cmd, repo, []quiz.Question{q, q})
+	assert.Contains(t, out.String(), symbols.Fail.String()+" Not quite: this is Hash Map, not Two Pointers.")
+	assert.Contains(t, out.String(), "Question 2 of 2")
+	assert.Contains(t, out.String(), "You recognized 0 of 1 pattern(s).")
+
+	answers, err := loadQuizAnswers()
+	require.NoError(t, err)
+	require.Len(t, answers, 1)
+	assert.Equal(t, "two-pointers", answers[0].Chosen)
+	assert.False(t, answers[0].Correct)
+
+	output, err := executeCommand(rootCmd, "quiz", "stats")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Hash Map                 0% (0 of 1), most often mistaken for Two Pointers")
+
+	output, _ = executeJSONCommand(t, "quiz", "stats")
+	assert.JSONEq(t, `{"patterns": [{"pattern": "hash-map", "answered": 1, "correct": 0, "accuracy": 0, "mistaken_for": "two-pointers"}]}`, output)
+}
@@ -0,0 +1,128 @@
+// replay command, for stepping back through how a session went
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/spf13/cobra"
+)
+
+// replayListResponse is what 'replay --output json' prints without a session
+type replayListResponse struct {
+	Sessions []timeline.Summary `json:"sessions"`
+}
+
+// replayCmd lists recorded sessions, or steps through one
+var replayCmd = &cobra.Command{
+	Use:   "replay [session-id]",
+	Short: "Step through how a recorded session went",
+	Long: `Sessions in CLI mode and the split screen record a timeline as you work: the
+code each time you save it, test runs and submissions with how many passed,
+hints and the solution you viewed, and how the session ended. Without an
+argument, replay lists the recorded sessions, the most recent first. Given a
+session ID, or 'last' for the most recent, it steps through that session in
+the terminal UI, showing the code as it stood at each point and marking the
+lines each save added. Builds without the terminal UI, and output that is not
+a terminal, print the timeline and the final code instead.
+
+Examples:
+  algo-scales replay
+  algo-scales replay last
+  algo-scales replay two_sum-1772647200 --output json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		summaries, err := timeline.List()
+		if err != nil {
+			commandError(cmd, "listing recorded sessions", err)
+			return
+		}
+		if len(args) == 0 {
+			if jsonOutput(cmd) {
+				if summaries == nil {
+					summaries = []timeline.Summary{}
+				}
+				writeJSON(cmd, replayListResponse{Sessions: summaries})
+				return
+			}
+			printTimelines(cmd.OutOrStdout(), summaries)
+			return
+		}
+
+		id := args[0]
+		if id == "last" {
+			if len(summaries) == 0 {
+				commandError(cmd, "replaying the session", fmt.Errorf("no sessions have been recorded yet"))
+				return
+			}
+			id = summaries[0].SessionID
+		}
+		t, err := timeline.Load(id)
+		if err != nil {
+			commandError(cmd, "replaying the session", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, t)
+			return
+		}
+		if features.Available(features.TUI) && os.Getenv("TESTING") != "1" && isTerminal() {
+			if err := runReplay(*t); err != nil {
+				commandError(cmd, "replaying the session", err)
+			}
+			return
+		}
+		printTimeline(cmd.OutOrStdout(), *t)
+	},
+}
+
+// printTimelines lists the recorded sessions
+func printTimelines(w io.Writer, summaries []timeline.Summary) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No sessions recorded yet. Sessions in CLI mode and the split screen are recorded as you work.")
+		return
+	}
+
+	width := 0
+	for _, s := range summaries {
+		width = max(width, len(s.SessionID))
+	}
+	fmt.Fprintln(w, "Recorded sessions:")
+	for _, s := range summaries {
+		outcome := "in progress"
+		if s.Finished {
+			outcome = "not solved"
+			if s.Solved {
+				outcome = "solved"
+			}
+		}
+		fmt.Fprintf(w, "  %-*s  %s  %s (%s), %d events, %s\n",
+			width, s.SessionID, formatTime(s.StartedAt.Local()), s.Title, s.Language, s.Events, outcome)
+	}
+	fmt.Fprintln(w, "\nReplay one with: algo-scales replay <session-id>")
+}
+
+// printTimeline prints a session's events and the code it ended with
+func printTimeline(w io.Writer, t timeline.Timeline) {
+	fmt.Fprintf(w, "Replay: %s (%s), started %s\n\n", t.Title, t.Language, formatTime(t.StartedAt.Local()))
+	if len(t.Events) == 0 {
+		fmt.Fprintln(w, "Nothing was recorded in this session.")
+		return
+	}
+	for _, e := range t.Events {
+		fmt.Fprintf(w, "  %s  %s\n", timeline.FormatElapsed(e.Elapsed), e.Describe())
+	}
+	if code := t.Code(len(t.Events) - 1); code != "" {
+		fmt.Fprintf(w, "\nFinal code:\n%s\n", strings.TrimRight(code, "\n"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+}
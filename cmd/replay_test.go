@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	stubUserData(t)
+
+	output, err := executeCommand(rootCmd, "replay")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No sessions recorded yet.")
+	output, err = executeCommand(rootCmd, "replay", "last")
+	require.NoError(t, err)
+	assert.Contains(t, output, "no sessions have been recorded yet")
+
+	start := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	session := timeline.Timeline{SessionID: timeline.NewID("two_sum", start), ProblemID: "two_sum", Title: "Two Sum", Language: "go", StartedAt: start}
+	for _, e := range []timeline.Event{
+		{Kind: timeline.KindSnapshot, Elapsed: time.Minute, Code: "func twoSum() {\n}"},
+		{Kind: timeline.KindTest, Elapsed: 3 * time.Minute, Passed: 1, Total: 3},
+		{Kind: timeline.KindHint, Elapsed: 4 * time.Minute, Level: 1},
+		{Kind: timeline.KindSnapshot, Elapsed: 5 * time.Minute, Code: "func twoSum() {\n\treturn nil\n}"},
+		{Kind: timeline.KindFinish, Elapsed: 6 * time.Minute, Solved: true},
+	} {
+		require.NoError(t, timeline.Record(session, e))
+	}
+
+	output, err = executeCommand(rootCmd, "replay")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Recorded sessions:")
+	assert.Contains(t, output, session.SessionID+"  Mar 4, 2026 6:00 PM  Two Sum (go), 5 events, solved")
+
+	// Without a terminal the timeline is printed
+	output, err = executeCommand(rootCmd, "replay", "last")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Replay: Two Sum (go), started Mar 4, 2026 6:00 PM")
+	assert.Contains(t, output, "  00:03:00  Ran tests: 1/3 passed\n  00:04:00  Requested hint level 1\n")
+	assert.Contains(t, output, "Final code:\nfunc twoSum() {\n\treturn nil\n}\n")
+
+	output, err = executeCommand(rootCmd, "replay", "coin_change-1")
+	require.NoError(t, err)
+	assert.Contains(t, output, "no timeline was recorded for session coin_change-1")
+
+	output, status := executeJSONCommand(t, "replay", session.SessionID)
+	require.Equal(t, 0, status)
+	var replayed timeline.Timeline
+	require.NoError(t, json.Unmarshal([]byte(output), &replayed))
+	assert.Len(t, replayed.Events, 5)
+	assert.Equal(t, timeline.KindHint, replayed.Events[2].Kind)
+
+	output, status = executeJSONCommand(t, "replay")
+	require.Equal(t, 0, status)
+	var list replayListResponse
+	require.NoError(t, json.Unmarshal([]byte(output), &list))
+	require.Len(t, list.Sessions, 1)
+	assert.True(t, list.Sessions[0].Solved)
+}
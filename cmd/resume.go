@@ -0,0 +1,134 @@
+// resume command, for picking up the session left in progress
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/spf13/cobra"
+)
+
+// resumeResponse is what 'resume --output json' prints
+type resumeResponse struct {
+	Session   *resume.State `json:"session"` // Null when no session is in progress
+	Discarded bool          `json:"discarded,omitempty"`
+}
+
+// resumeCmd reopens the saved session
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Pick up the session you left in progress",
+	Long: `Reopen the problem you quit mid-way, with your code, the time spent and the
+hints you had seen as you left them. Sessions are saved as you work in the
+split-screen UI and in CLI mode, and forgotten once you submit or exit them.
+A session left in the split-screen UI reopens there; builds without the
+terminal UI, and terminals that cannot show it, reopen it in CLI mode.
+
+Examples:
+  algo-scales resume
+  algo-scales resume --discard
+  algo-scales resume --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		state, err := resume.Load()
+		if err != nil {
+			commandError(cmd, "loading the saved session", err)
+			return
+		}
+		discard, _ := cmd.Flags().GetBool("discard")
+		if discard && state != nil {
+			if err := resume.Clear(); err != nil {
+				commandError(cmd, "discarding the saved session", err)
+				return
+			}
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, resumeResponse{Session: state, Discarded: discard && state != nil})
+			return
+		}
+		if state == nil {
+			fmt.Fprintln(cmd.OutOrStdout(), "No session in progress. Start one with: algo-scales start practice")
+			return
+		}
+		if discard {
+			fmt.Fprintf(cmd.OutOrStdout(), "Discarded the session on %s.\n", state.Title)
+			return
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Resuming %s (%s), %s in.\n", state.Title, state.Language, formatDuration(state.Elapsed()))
+		if err := resumeSession(cmd, *state); err != nil {
+			commandError(cmd, "resuming the session", err)
+		}
+	},
+}
+
+// resumeSession reopens a saved session in the UI it was left in, falling
+// back to CLI mode where the terminal UI cannot run
+func resumeSession(cmd *cobra.Command, state resume.State) error {
+	if state.UI == resume.TUI && features.Available(features.TUI) && os.Getenv("TESTING") != "1" && isTerminal() {
+		return resumeTUI(state)
+	}
+	return resumeCli(state)
+}
+
+// resumeCli reopens a saved session in the CLI workflow
+func resumeCli(state resume.State) error {
+	mode := session.Mode(state.Mode)
+	if mode == "" {
+		mode = session.PracticeMode
+	}
+	sess, err := session.CreateSession(session.Options{
+		Mode:      mode,
+		Language:  state.Language,
+		ProblemID: state.ProblemID,
+	})
+	if err != nil {
+		return err
+	}
+	if sess.Clock, err = state.RestoreClock(); err != nil {
+		return err
+	}
+	if !state.StartedAt.IsZero() {
+		sess.StartTime = state.StartedAt
+	}
+	sess.ShowPattern = state.HintsShown
+	sess.ShowSolution = state.SolutionShown
+
+	adapter := &SessionAdapter{Session: sess}
+	if err := adapter.SetCode(state.Code); err != nil {
+		return err
+	}
+	recordEvent(adapter, timeline.Event{Kind: timeline.KindResume})
+	return runCliWorkflow(adapter)
+}
+
+// saveCliProgress saves a CLI session as it stands, for 'algo-scales resume'
+func saveCliProgress(s *SessionAdapter) {
+	code, _ := os.ReadFile(s.CodeFile)
+	state := resume.State{
+		ProblemID:     s.Problem.ID,
+		Title:         s.Problem.Title,
+		Mode:          string(s.Options.Mode),
+		Language:      s.Options.Language,
+		UI:            resume.CLI,
+		Code:          string(code),
+		HintsShown:    s.ShowPattern,
+		SolutionShown: s.Session.ShowSolution,
+		StartedAt:     s.StartTime,
+	}
+	if s.Clock != nil {
+		state.Clock = s.Clock.State()
+	}
+	// A session that cannot be saved can still be finished
+	_ = resume.Save(state)
+}
+
+func init() {
+	resumeCmd.Flags().Bool("discard", false, "Forget the saved session instead of resuming it")
+	rootCmd.AddCommand(resumeCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResume(t *testing.T) {
+	stubUserData(t)
+	t.Cleanup(func() { resumeCmd.Flags().Set("discard", "false") })
+	dir := filepath.Join(utils.GetConfigDir(), "problems", "hash-map")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	data, err := json.Marshal(problem.Problem{
+		ID:          "two_sum",
+		Title:       "Two Sum",
+		Patterns:    []string{"hash-map"},
+		StarterCode: map[string]string{"go": "func twoSum(nums []int, target int) []int {\n}"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "two_sum.json"), data, 0644))
+
+	output, err := executeCommand(rootCmd, "resume")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No session in progress.")
+
+	code := "func twoSum(nums []int, target int) []int {\n    return nil // resumed\n}"
+	require.NoError(t, resume.Save(resume.State{
+		ProblemID: "two_sum",
+		Title:     "Two Sum",
+		Language:  "go",
+		UI:        resume.TUI,
+		Code:      code,
+		Clock:     clock.State{Mode: clock.Stopwatch, Elapsed: 12 * time.Minute},
+		StartedAt: time.Now().Add(-time.Hour),
+	}))
+
+	// Tests have no terminal, so the session reopens in CLI mode
+	output, err = executeCommand(rootCmd, "resume")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Resuming Two Sum (go), 12m in.")
+	written, err := os.ReadFile(filepath.Join(session.WorkspaceDir("two_sum"), "solution.go"))
+	require.NoError(t, err)
+	assert.Equal(t, code, string(written))
+
+	// The session's timeline goes on where it left off
+	recorded, err := timeline.List()
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	replayed, err := timeline.Load(recorded[0].SessionID)
+	require.NoError(t, err)
+	assert.Equal(t, timeline.KindResume, replayed.Events[0].Kind)
+
+	output, status := executeJSONCommand(t, "resume")
+	require.Equal(t, 0, status)
+	var resp resumeResponse
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	require.NotNil(t, resp.Session)
+	assert.Equal(t, "two_sum", resp.Session.ProblemID)
+	assert.Equal(t, resume.TUI, resp.Session.UI)
+	assert.False(t, resp.Discarded)
+
+	output, status = executeJSONCommand(t, "resume", "--discard")
+	require.Equal(t, 0, status)
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.True(t, resp.Discarded)
+
+	state, err := resume.Load()
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
@@ -0,0 +1,139 @@
+// Spaced-repetition review queue
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/review"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// dailyReviewLimit is how many due reviews the daily session lists
+const dailyReviewLimit = 3
+
+// loadReviews returns every scheduled review and those due now. Hidden
+// problems are never due.
+// Exported as variable for testing
+var loadReviews = func() (all, due []storage.Review, err error) {
+	repo := storage.Default()
+	defer repo.Close()
+
+	ctx := context.Background()
+	if all, err = repo.LoadReviews(ctx); err != nil {
+		return nil, nil, err
+	}
+	if due, err = review.Due(ctx, repo, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	hidden := problem.HiddenIDs()
+	visible := due[:0]
+	for _, r := range due {
+		if !hidden[r.ProblemID] {
+			visible = append(visible, r)
+		}
+	}
+	return all, visible, nil
+}
+
+// runReviewQueue lists the problems due for review, or with all the whole
+// schedule, and with start opens a practice session on the most overdue one
+func runReviewQueue(cmd *cobra.Command, all, start bool) {
+	out := cmd.OutOrStdout()
+	scheduled, due, err := loadReviews()
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error loading reviews: %v\n", err)
+		return
+	}
+	if len(scheduled) == 0 {
+		fmt.Fprintln(out, "No problems scheduled for review yet. Practice a few problems first.")
+		return
+	}
+
+	if start {
+		if len(due) == 0 {
+			fmt.Fprintf(out, "Nothing due for review. Next up: %s %s.\n",
+				scheduled[0].ProblemID, formatReviewDue(scheduled[0], time.Now()))
+			return
+		}
+		fmt.Fprintf(out, "Reviewing %s (%s)\n", due[0].ProblemID, formatReviewDue(due[0], time.Now()))
+		practiceCmd.Run(cmd, []string{due[0].ProblemID})
+		return
+	}
+
+	now := time.Now()
+	if all {
+		fmt.Fprintf(out, "📚 Review Schedule — %d problem(s), %d due\n\n", len(scheduled), len(due))
+		writeReviews(out, scheduled, now)
+		return
+	}
+
+	if len(due) == 0 {
+		fmt.Fprintf(out, "Nothing due for review. Next up: %s %s.\n", scheduled[0].ProblemID, formatReviewDue(scheduled[0], now))
+		return
+	}
+	fmt.Fprintf(out, "📚 Review Queue — %d problem(s) due\n\n", len(due))
+	writeReviews(out, due, now)
+	fmt.Fprintln(out, "\nPractice the most overdue with: algo-scales review --start")
+	fmt.Fprintln(out, "Or pick one with: algo-scales start practice <problem>")
+}
+
+// writeReviews prints one line per scheduled review
+func writeReviews(out io.Writer, reviews []storage.Review, now time.Time) {
+	for _, r := range reviews {
+		fmt.Fprintf(out, "  %-28s %-18s every %dd, ease %.2f", r.ProblemID, formatReviewDue(r, now), r.Interval, r.Ease)
+		if r.Lapses > 0 {
+			fmt.Fprintf(out, ", forgotten %dx", r.Lapses)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// formatReviewDue describes when a review is due relative to now
+func formatReviewDue(r storage.Review, now time.Time) string {
+	days := int(r.Due.Sub(now).Hours() / 24)
+	switch {
+	case !r.Due.After(now) && days == 0:
+		return "due today"
+	case !r.Due.After(now):
+		return fmt.Sprintf("overdue %s", pluralDays(-days))
+	case days == 0:
+		return "due later today"
+	default:
+		return fmt.Sprintf("due in %s", pluralDays(days))
+	}
+}
+
+// pluralDays formats a number of days
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// printDailyReviewSection lists a few problems due for review alongside the
+// daily scales. Nothing is printed when no reviews are due.
+func printDailyReviewSection(out io.Writer) {
+	_, due, err := loadReviews()
+	if err != nil || len(due) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "📚 Review (%d due):\n", len(due))
+	now := time.Now()
+	for i, r := range due {
+		if i == dailyReviewLimit {
+			fmt.Fprintf(out, "  ...and %d more\n", len(due)-dailyReviewLimit)
+			break
+		}
+		fmt.Fprintf(out, "  - %s (%s)\n", r.ProblemID, formatReviewDue(r, now))
+	}
+	fmt.Fprintln(out, "Run 'algo-scales review --start' to practice the most overdue one.")
+	fmt.Fprintln(out)
+}
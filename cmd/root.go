@@ -9,11 +9,21 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/cloudsync"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/integrity"
 	"github.com/lancekrogers/algo-scales/internal/license"
-	"github.com/lancekrogers/algo-scales/internal/ui"
-	"github.com/lancekrogers/algo-scales/internal/ui/splitscreen"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +38,33 @@ patterns through curated problems and features different learning modes.
 By default, Algo Scales runs in CLI mode with interactive commands. For a terminal
 UI experience, use the --tui or --split flags.`,
 	
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(cmd); err != nil {
+			return err
+		}
+		if err := startProfiling(cmd); err != nil {
+			return err
+		}
+		checkUserData(cmd)
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			cfg = config.DefaultConfig()
+		}
+		configureSymbols(cmd, cfg)
+		configureAccessibility(cmd, cfg)
+		configureHighlighting(cmd, cfg)
+		configureTheme(cmd)
+		configureTestTiming(cfg)
+		configureSandbox(cfg)
+		setBlankAfter(time.Duration(cfg.BlankAfterMin) * time.Minute)
+		autoSubmit = cfg.AutoSubmit
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		stopProfiling(cmd)
+		cloudsync.MaybeSync()
+	},
+
 	// Run the CLI by default now, with option for TUI
 	Run: func(cmd *cobra.Command, args []string) {
 		// Don't attempt to run any UI in test mode
@@ -66,7 +103,7 @@ UI experience, use the --tui or --split flags.`,
 		
 		// Use split-screen UI if requested
 		if useSplitScreen && isTerminal() {
-			if err := splitscreen.StartUI(nil); err != nil {
+			if err := startSplitScreen(); err != nil {
 				fmt.Printf("Error running split-screen UI: %v\n", err)
 				fmt.Println("Falling back to CLI mode...")
 				// Fall through to CLI mode
@@ -75,7 +112,7 @@ UI experience, use the --tui or --split flags.`,
 			}
 		} else if useTUI && isTerminal() {
 			// Use standard TUI if requested
-			err := ui.StartTUI()
+			err := startTUI()
 			if err != nil {
 				fmt.Printf("Error starting TUI: %v\n", err)
 				fmt.Println("Falling back to CLI mode...")
@@ -92,6 +129,9 @@ UI experience, use the --tui or --split flags.`,
 	},
 }
 
+// profileDefaultDir is the --profile value used when no directory is given
+const profileDefaultDir = "default"
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -123,6 +163,9 @@ func hideFlags(cmd *cobra.Command) {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+
+	// Problems missing locally are fetched from the server and cached
+	problem.RemoteFetcher = api.FetchProblem
 	
 	// Add global flags
 	rootCmd.PersistentFlags().Bool("tui", false, "Use terminal UI mode instead of CLI")
@@ -130,6 +173,12 @@ func init() {
 	rootCmd.PersistentFlags().Bool("splitscreen", false, "Alias for --split")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug mode")
 	rootCmd.PersistentFlags().Bool("vim-mode", false, "Use VIM-optimized mode")
+	rootCmd.PersistentFlags().String("profile", "", "Write CPU/heap profiles and startup timings (optionally to a directory)")
+	rootCmd.PersistentFlags().Lookup("profile").NoOptDefVal = profileDefaultDir
+	rootCmd.PersistentFlags().Bool("ascii", false, "Use plain ASCII output instead of emoji symbols")
+	rootCmd.PersistentFlags().Bool("accessible", false, "Draw the TUI as plain, label-prefixed text for screen readers")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Turn off colors")
+	rootCmd.PersistentFlags().String("output", outputText, "Output format: text, or json for editor extensions and scripts")
 	
 	// Keep these for backward compatibility but hide them
 	rootCmd.PersistentFlags().Bool("cli", false, "Legacy flag (CLI is now the default)")
@@ -149,14 +198,15 @@ func init() {
 		}
 		
 		if err := license.RequestLicense(); err != nil {
-			fmt.Printf("License setup failed: %v\n", err)
-			os.Exit(1)
-		}
-		
-		fmt.Println("Downloading problem sets...")
-		if err := api.DownloadProblems(true); err != nil {
-			fmt.Printf("Problem download failed: %v\n", err)
-			os.Exit(1)
+			// Practice works without a license, so only the download is skipped
+			fmt.Printf("License setup skipped: %v\n", err)
+			fmt.Println("Run 'algo-scales license activate <license-key>' to download problem sets later.")
+		} else {
+			fmt.Println("Downloading problem sets...")
+			if err := api.DownloadProblems(true); err != nil {
+				fmt.Printf("Problem download failed: %v\n", err)
+				os.Exit(1)
+			}
 		}
 		
 		fmt.Println("Setup complete! You're ready to start practicing.")
@@ -168,6 +218,86 @@ func initConfig() {
 	// Set up config if needed
 }
 
+// checkUserData repairs damaged user data files before anything loads them,
+// and says what it did
+func checkUserData(cmd *cobra.Command) {
+	if os.Getenv("TESTING") == "1" || cmd == dataCheckCmd {
+		return
+	}
+	for _, result := range integrity.Check(integrity.Files(), false) {
+		if result.Repaired() {
+			fmt.Fprintln(cmd.ErrOrStderr(), integrity.Describe(result))
+		}
+	}
+}
+
+// configureSymbols switches to ASCII-only output when requested by flag or
+// config; otherwise the terminal locale decides
+func configureSymbols(cmd *cobra.Command, cfg config.UserConfig) {
+	if ascii, _ := cmd.Flags().GetBool("ascii"); ascii {
+		symbols.SetASCII(true)
+		return
+	}
+	if cfg.ASCIIOnly {
+		symbols.SetASCII(true)
+	}
+}
+
+// configureAccessibility turns on accessible mode, which also limits output
+// to ASCII, and turns colors off in it, with --no-color or with NO_COLOR
+func configureAccessibility(cmd *cobra.Command, cfg config.UserConfig) {
+	accessible, _ := cmd.Flags().GetBool("accessible")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	if accessible || cfg.Accessible {
+		access.SetEnabled(true)
+		symbols.SetASCII(true)
+		noColor = true
+	}
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		highlight.SetColors(highlight.NoColor)
+	}
+}
+
+// configureHighlighting applies the configured code theme, keeping the
+// default when the theme is unknown
+func configureHighlighting(cmd *cobra.Command, cfg config.UserConfig) {
+	if err := highlight.SetTheme(cfg.CodeTheme); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+}
+
+// configureTheme applies the theme file, keeping the default theme when it
+// is broken
+func configureTheme(cmd *cobra.Command) {
+	if err := theme.Load(); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+}
+
+// autoSubmit moves on as soon as a test run passes every test, instead of
+// asking first
+var autoSubmit bool
+
+// configureTestTiming applies the configured threshold for flagging slow tests
+func configureTestTiming(cfg config.UserConfig) {
+	if cfg.SlowTestMs > 0 {
+		execution.SlowTestThreshold = time.Duration(cfg.SlowTestMs) * time.Millisecond
+	}
+}
+
+// configureSandbox applies the configured sandbox and limits for running
+// solutions
+func configureSandbox(cfg config.UserConfig) {
+	execution.ActiveSandbox = execution.Sandbox{
+		Mode:     cfg.Sandbox,
+		TestTime: time.Duration(cfg.TestTimeMs) * time.Millisecond,
+		CPUTime:  time.Duration(cfg.TestCPUMs) * time.Millisecond,
+		MemoryMB: cfg.MemoryLimitMB,
+		Images:   cfg.SandboxImages,
+	}
+}
+
 // isFirstRun checks if this is the first time the app is run
 func isFirstRun() bool {
 	// Skip setup during tests
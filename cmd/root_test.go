@@ -8,6 +8,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -100,6 +106,40 @@ func TestSetupConfigDir(t *testing.T) {
 	assert.True(t, fileExists(filepath.Join(tempDir, "stats")))
 }
 
+func TestConfigureAccessibility(t *testing.T) {
+	profile, ascii, colors := lipgloss.ColorProfile(), symbols.ASCII(), highlight.TerminalColors()
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		symbols.SetASCII(ascii)
+		highlight.SetColors(colors)
+		access.SetEnabled(false)
+	})
+	t.Setenv("NO_COLOR", "")
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("accessible", false, "")
+		cmd.Flags().Bool("no-color", false, "")
+		require.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	configureAccessibility(newCmd(), config.UserConfig{})
+	assert.False(t, access.Enabled())
+	assert.Equal(t, termenv.TrueColor, lipgloss.ColorProfile())
+
+	configureAccessibility(newCmd("--no-color"), config.UserConfig{})
+	assert.False(t, access.Enabled())
+	assert.Equal(t, termenv.Ascii, lipgloss.ColorProfile())
+	assert.Equal(t, highlight.NoColor, highlight.TerminalColors())
+
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	configureAccessibility(newCmd(), config.UserConfig{Accessible: true})
+	assert.True(t, access.Enabled())
+	assert.True(t, symbols.ASCII())
+	assert.Equal(t, termenv.Ascii, lipgloss.ColorProfile())
+}
+
 func TestIsFirstRun(t *testing.T) {
 	// Skip this test for now until we can debug the CI environment
 	t.Skip("Skipping test as it's failing in CI")
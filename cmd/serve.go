@@ -0,0 +1,70 @@
+// Serve command for the local web dashboard
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/lancekrogers/algo-scales/internal/dashboard"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// serveDashboard serves the dashboard on addr until ctx is cancelled
+// Exported as variable for testing
+var serveDashboard = func(ctx context.Context, addr string, ready func(addr string)) error {
+	repo := storage.Default()
+	defer repo.Close()
+	return dashboard.NewServer(repo).ListenAndServe(ctx, addr, ready)
+}
+
+// serveCmd serves the web dashboard
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local web dashboard of your stats and history",
+	Long: `Serve a web dashboard on this machine with your practice calendar,
+session history, pattern mastery and the archive of the solutions you
+submitted. The page is built into the binary and loads nothing from the
+network.
+
+The page is drawn from a read-only JSON API served alongside it:
+  /api/summary            Overall statistics
+  /api/calendar           Sessions on each day of the past year
+  /api/sessions           Session history, newest first (limit, offset)
+  /api/patterns           Success rate, recall and trend of each pattern
+  /api/solutions          Problems with submitted solutions
+  /api/solutions/<id>     The solutions submitted to a problem, with code
+
+The dashboard only listens on localhost unless --host is given. Stop it
+with Ctrl+C.
+
+Examples:
+  algo-scales serve
+  algo-scales serve --port 8080`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host, _ := cmd.Flags().GetString("host")
+		port, _ := cmd.Flags().GetInt("port")
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		return serveDashboard(ctx, addr, func(addr string) {
+			fmt.Fprintf(cmd.OutOrStdout(), "Dashboard running at http://%s/ (press Ctrl+C to stop)\n", addr)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().IntP("port", "p", dashboard.DefaultPort, "Port to serve the dashboard on")
+	serveCmd.Flags().String("host", "127.0.0.1", "Address to listen on")
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe(t *testing.T) {
+	var served string
+	original := serveDashboard
+	serveDashboard = func(ctx context.Context, addr string, ready func(addr string)) error {
+		served = addr
+		ready(addr)
+		return nil
+	}
+	t.Cleanup(func() { serveDashboard = original })
+
+	output, err := executeCommand(rootCmd, "serve", "--port", "9090", "--host", "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9090", served)
+	assert.Contains(t, output, "Dashboard running at http://127.0.0.1:9090/")
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
 )
 
 // SessionAdapter adapts a session.Session to implement the SessionImpl interface methods needed by CLI
@@ -39,6 +40,12 @@ func (s *SessionAdapter) RunTests(ctx context.Context) ([]interfaces.TestResult,
 	return s.Implementation.RunTests(ctx)
 }
 
+// SubmitTests implements the SubmitTests method for CLI usage
+func (s *SessionAdapter) SubmitTests(ctx context.Context) ([]interfaces.TestResult, bool, error) {
+	s.ensureImplementation()
+	return s.Implementation.SubmitTests(ctx)
+}
+
 // ShowHints implements the ShowHints method for CLI usage
 func (s *SessionAdapter) ShowHints(show bool) {
 	s.ShowPattern = show
@@ -46,11 +53,13 @@ func (s *SessionAdapter) ShowHints(show bool) {
 
 // ShowSolution implements the ShowSolution method for CLI usage
 func (s *SessionAdapter) ShowSolution(show bool) {
-	// Nothing to do here for CLI mode
+	s.Session.ShowSolution = show
 }
 
-// FinishSession implements the session finish method
+// FinishSession implements the session finish method. A finished session
+// is no longer there to resume.
 func (s *SessionAdapter) FinishSession(solved bool) error {
+	_ = resume.Clear()
 	return s.Session.FinishSession(solved)
 }
 
@@ -0,0 +1,115 @@
+// Show command for reading a problem statement
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// summarizeProblem generates a problem summary with the configured AI agent
+// Exported as variable for testing
+var summarizeProblem = func(p problem.Problem) (*problem.Summary, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return ai.Summarize(ctx, agent, p)
+}
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show <problem>",
+	Short: "Show a problem statement",
+	Long: `Print a problem's statement, examples and constraints.
+
+With --summarize, the AI assistant also writes a three-sentence summary and a
+checklist of clarifying questions to ask before solving. Practice restating
+the problem in your own words first, then compare. Summaries are cached and
+regenerated only when the problem changes or --refresh is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		summarize, _ := cmd.Flags().GetBool("summarize")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		out := cmd.OutOrStdout()
+
+		p, err := problem.GetByID(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading problem: %v\n", err)
+			return
+		}
+
+		fmt.Fprint(out, (&session.Session{Problem: p}).FormatProblemDescription())
+		notes, err := problem.LoadNotes(p.ID)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		}
+		overlay, _ := problem.LoadOverlay(p.ID)
+		if notes != "" || overlay != nil {
+			writeNotes(out, notes, overlay)
+		}
+		if !summarize {
+			return
+		}
+
+		summary, cached := problem.LoadSummary(*p)
+		if !cached || refresh {
+			fmt.Fprintln(out, "🤖 Summarizing the problem...")
+			summary, err = summarizeProblem(*p)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error summarizing problem: %v\n", err)
+				fmt.Fprintln(cmd.ErrOrStderr(), "Run 'algo-scales ai config' to set up the AI assistant.")
+				return
+			}
+			if err := problem.SaveSummary(*summary); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			}
+		}
+		writeSummary(out, summary)
+	},
+}
+
+// writeSummary prints a problem summary and its clarifying questions
+func writeSummary(out io.Writer, s *problem.Summary) {
+	fmt.Fprintf(out, "\n## Summary\n\n%s\n", s.Summary)
+	if len(s.Questions) > 0 {
+		fmt.Fprint(out, "\n## Clarifying Questions\n\n")
+		for _, q := range s.Questions {
+			fmt.Fprintf(out, "- [ ] %s\n", q)
+		}
+	}
+}
+
+// writeNotes prints the notes a user kept on a problem: their own Markdown
+// notes, then any additions carried over by sync
+func writeNotes(out io.Writer, notes string, o *problem.Overlay) {
+	fmt.Fprint(out, "\n## Your Notes\n\n")
+	if notes != "" {
+		fmt.Fprint(out, notes)
+		if o != nil {
+			fmt.Fprintln(out)
+		}
+	}
+	if o == nil {
+		return
+	}
+	for _, note := range o.Notes {
+		fmt.Fprintf(out, "- %s\n", note)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().BoolP("summarize", "s", false, "Add an AI summary and clarifying-questions checklist")
+	showCmd.Flags().Bool("refresh", false, "Regenerate the summary instead of using the cached one")
+}
@@ -0,0 +1,213 @@
+// Snapshot commands for sharing a solution and its failing tests
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/lancekrogers/algo-scales/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Share your solution and its failing tests to ask for help",
+	Long: `Package a problem, your solution and the tests it fails into an encrypted
+snapshot, and print a link to share with a friend or mentor. They open it
+with 'algo-scales snapshot open <link>' to see what you see.
+
+The snapshot is encrypted before it leaves your machine. The key is the
+part of the link after '#', which is never sent to the server, so only
+people you give the whole link to can read it. Shared snapshots expire
+after 30 days. With --out, the snapshot is saved to a file instead, for
+sending some other way; the link then names the file.
+
+The example tests and your own tests are run, or every tier with --all.
+Without --problem, the problem in progress in daily practice is used, and
+without --file its daily workspace file.
+
+Examples:
+  algo-scales snapshot --note "Why does [3,3] fail?"
+  algo-scales snapshot --problem two_sum --file solution.go --out two_sum.asnap`,
+	Run: func(cmd *cobra.Command, args []string) {
+		language, _ := cmd.Flags().GetString("language")
+		filePath, _ := cmd.Flags().GetString("file")
+		all, _ := cmd.Flags().GetBool("all")
+		note, _ := cmd.Flags().GetString("note")
+		outPath, _ := cmd.Flags().GetString("out")
+		out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+
+		problemID, ok := userTestProblem(cmd)
+		if !ok {
+			return
+		}
+		prob, err := problem.GetByID(problemID)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error loading problem: %v\n", err)
+			return
+		}
+		if filePath == "" {
+			filePath = daily.GetProblemFilePath(problemID, language)
+		}
+		code, err := os.ReadFile(filePath)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error reading solution: %v. Use --file <path>.\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		fmt.Fprintf(out, "Running tests for %s...\n", prob.Title)
+		snap, err := takeSnapshot(ctx, prob, language, string(code), all)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error running tests: %v\n", err)
+			return
+		}
+		snap.Note = note
+		if len(snap.Failures) == 0 {
+			fmt.Fprintln(out, "Every test passes; sharing the snapshot anyway.")
+		} else {
+			fmt.Fprintf(out, "%d of %d tests pass.\n", snap.Passed, snap.Total)
+		}
+
+		sealed, key, err := snapshot.Seal(snap)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error encrypting snapshot: %v\n", err)
+			return
+		}
+
+		location := outPath
+		if outPath != "" {
+			if err := os.WriteFile(outPath, sealed, 0600); err != nil {
+				fmt.Fprintf(errOut, "Error saving snapshot: %v\n", err)
+				return
+			}
+		} else {
+			location, err = api.UploadSnapshot(ctx, sealed)
+			if err != nil {
+				fmt.Fprintf(errOut, "Error sharing snapshot: %v\nUse --out <file> to save it instead.\n", err)
+				return
+			}
+		}
+
+		fmt.Fprintln(out, "\nShare this with whoever you are asking for help:")
+		fmt.Fprintf(out, "  algo-scales snapshot open '%s'\n", snapshot.Reference(location, key))
+		fmt.Fprintln(out, "\nAnyone with the whole link can read the snapshot.")
+	},
+}
+
+// snapshotOpenCmd represents the snapshot open command
+var snapshotOpenCmd = &cobra.Command{
+	Use:   "open <link>",
+	Short: "View a snapshot someone shared with you",
+	Long: `Open a snapshot from its link: a URL or snapshot ID, or a snapshot file,
+followed by '#' and its key. The problem, the solution and its failing
+tests are shown read-only; nothing is recorded in your stats.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		location, key, err := snapshot.ParseReference(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		sealed, err := readSnapshot(ctx, location)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error fetching snapshot: %v\n", err)
+			return
+		}
+		snap, err := snapshot.Open(sealed, key)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error opening snapshot: %v\n", err)
+			return
+		}
+		writeSnapshot(cmd.OutOrStdout(), snap)
+	},
+}
+
+// takeSnapshot runs a solution's tests and packages it with the ones it
+// fails. The run is not counted towards the hint policy or stats.
+func takeSnapshot(ctx context.Context, prob *problem.Problem, language, code string, all bool) (snapshot.Snapshot, error) {
+	results, _, err := executeSolution(ctx, prob, language, code, all)
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+
+	snap := snapshot.Snapshot{
+		Version:   snapshot.Version,
+		ProblemID: prob.ID,
+		Title:     prob.Title,
+		Language:  language,
+		Code:      code,
+		Total:     len(results),
+		CreatedAt: time.Now(),
+	}
+	for _, result := range results {
+		if result.Passed {
+			snap.Passed++
+			continue
+		}
+		snap.Failures = append(snap.Failures, snapshot.Failure{
+			Input:    fmt.Sprintf("%v", result.Input),
+			Expected: fmt.Sprintf("%v", result.Expected),
+			Actual:   fmt.Sprintf("%v", result.Actual),
+			Tier:     string(result.Tier.OrDefault()),
+		})
+	}
+	return snap, nil
+}
+
+// readSnapshot reads a sealed snapshot from a file, or else downloads it
+func readSnapshot(ctx context.Context, location string) ([]byte, error) {
+	if info, err := os.Stat(location); err == nil && !info.IsDir() {
+		return os.ReadFile(location)
+	}
+	return api.DownloadSnapshot(ctx, location)
+}
+
+// writeSnapshot shows a snapshot the way its author saw their test results
+func writeSnapshot(out io.Writer, snap snapshot.Snapshot) {
+	fmt.Fprintf(out, "Snapshot: %s (%s, %s)\n", snap.Title, snap.ProblemID, snap.Language)
+	fmt.Fprintf(out, "Shared %s\n", snap.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if snap.Note != "" {
+		fmt.Fprintf(out, "\nQuestion: %s\n", snap.Note)
+	}
+
+	fmt.Fprintln(out, "\nSolution:")
+	fmt.Fprintln(out, strings.TrimRight(codefmt.Code(snap.Language, snap.Code), "\n"))
+
+	fmt.Fprintf(out, "\n%d of %d tests pass.\n", snap.Passed, snap.Total)
+	for i, failure := range snap.Failures {
+		fmt.Fprintf(out, "\nFailing test %d (%s)\n", i+1, failure.Tier)
+		fmt.Fprintf(out, "  Input:    %s\n", failure.Input)
+		fmt.Fprintf(out, "  Expected: %s\n", failure.Expected)
+		fmt.Fprintf(out, "  Actual:   %s\n", failure.Actual)
+	}
+	fmt.Fprintf(out, "\nTry the problem yourself with 'algo-scales start practice %s'.\n", snap.ProblemID)
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotOpenCmd)
+
+	snapshotCmd.Flags().String("problem", "", "Problem ID (default: the problem in progress in daily practice)")
+	snapshotCmd.Flags().String("file", "", "Solution file (default: the problem's daily workspace file)")
+	snapshotCmd.Flags().String("language", "go", "Programming language")
+	snapshotCmd.Flags().Bool("all", false, "Run every tier of tests, as a submission does")
+	snapshotCmd.Flags().String("note", "", "Your question for whoever opens the snapshot")
+	snapshotCmd.Flags().String("out", "", "Save the snapshot to a file instead of sharing it through the server")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotCommands(t *testing.T) {
+	dir := t.TempDir()
+	originalPath, originalUpload, originalDownload := storage.DBPath, api.UploadSnapshot, api.DownloadSnapshot
+	t.Cleanup(func() {
+		storage.DBPath, api.UploadSnapshot, api.DownloadSnapshot = originalPath, originalUpload, originalDownload
+		for _, name := range []string{"problem", "file", "note", "out"} {
+			snapshotCmd.Flags().Set(name, "")
+		}
+	})
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+
+	// A solution that fails every test
+	solution := filepath.Join(dir, "solution.go")
+	require.NoError(t, os.WriteFile(solution, []byte("func twoSum(nums []int, target int) []int {\n\treturn nil\n}\n"), 0644))
+
+	openCommand := regexp.MustCompile(`algo-scales snapshot open '([^']+)'`)
+	shareAndOpen := func(t *testing.T, args ...string) string {
+		t.Helper()
+		output, err := executeCommand(rootCmd, append([]string{"snapshot", "--problem", "two_sum", "--file", solution, "--note", "Why nil?"}, args...)...)
+		require.NoError(t, err)
+		match := openCommand.FindStringSubmatch(output)
+		require.NotNil(t, match, output)
+
+		output, err = executeCommand(rootCmd, "snapshot", "open", match[1])
+		require.NoError(t, err)
+		return output
+	}
+
+	t.Run("File", func(t *testing.T) {
+		out := filepath.Join(dir, "two_sum.asnap")
+		output := shareAndOpen(t, "--out", out)
+		assert.FileExists(t, out)
+		assert.Contains(t, output, "Snapshot: Two Sum (two_sum, go)")
+		assert.Contains(t, output, "Question: Why nil?")
+		assert.Contains(t, output, "return nil")
+		assert.Contains(t, output, "Failing test 1 (example)")
+		assert.Regexp(t, `0 of \d+ tests pass`, output)
+	})
+
+	t.Run("Server", func(t *testing.T) {
+		stored := map[string][]byte{}
+		api.UploadSnapshot = func(ctx context.Context, sealed []byte) (string, error) {
+			stored["https://api.example.com/v1/snapshots/abc"] = sealed
+			return "https://api.example.com/v1/snapshots/abc", nil
+		}
+		api.DownloadSnapshot = func(ctx context.Context, location string) ([]byte, error) {
+			return stored[location], nil
+		}
+		snapshotCmd.Flags().Set("out", "")
+
+		output := shareAndOpen(t)
+		assert.Contains(t, output, "Snapshot: Two Sum")
+		assert.NotContains(t, string(stored["https://api.example.com/v1/snapshots/abc"]), "twoSum", "the server only sees ciphertext")
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		output, err := executeCommand(rootCmd, "snapshot", "open", filepath.Join(dir, "two_sum.asnap")+"#AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+		require.NoError(t, err)
+		assert.Contains(t, output, "wrong key")
+
+		output, err = executeCommand(rootCmd, "snapshot", "open", "no-key")
+		require.NoError(t, err)
+		assert.Contains(t, output, "needs a key")
+	})
+}
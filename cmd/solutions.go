@@ -0,0 +1,281 @@
+// solutions command, for the history of the solutions tried on a problem
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/solutions"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// solutionEntry is a solution as 'solutions --output json' describes it
+type solutionEntry struct {
+	Number      int       `json:"number"`
+	Language    string    `json:"language"`
+	Time        time.Time `json:"time"`
+	Submitted   bool      `json:"submitted"`
+	Passed      bool      `json:"passed"`
+	TestsPassed int       `json:"tests_passed"`
+	TestsTotal  int       `json:"tests_total"`
+	Code        string    `json:"code"`
+}
+
+// solutionDiff is what 'solutions diff --output json' prints
+type solutionDiff struct {
+	ProblemID string `json:"problem_id"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Changed   bool   `json:"changed"`
+	Diff      string `json:"diff"`
+}
+
+// openRestoredSession opens a session restored from an earlier solution
+// Exported as variable for testing
+var openRestoredSession = resumeSession
+
+// solutionsCmd lists the solutions tried on a problem
+var solutionsCmd = &cobra.Command{
+	Use:   "solutions <problem>",
+	Short: "List, compare and restore your past solutions to a problem",
+	Long: `List every solution you submitted to a problem, oldest first, with its
+language and how many tests it passed. Each submission keeps its code, so you
+can see how your approach evolved: print one with 'solutions show', compare two
+with 'solutions diff', or start a new session from one with 'solutions restore'.
+With --runs, the runs of the example tests are included too.
+
+Examples:
+  algo-scales solutions two_sum
+  algo-scales solutions show two_sum 2
+  algo-scales solutions diff two_sum 1 3
+  algo-scales solutions restore two_sum 1`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, history, err := loadSolutions(cmd, args[0])
+		if err != nil {
+			commandError(cmd, "listing solutions", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			entries := []solutionEntry{}
+			for _, s := range history {
+				entries = append(entries, newSolutionEntry(s))
+			}
+			writeJSON(cmd, map[string]interface{}{"problem_id": p.ID, "solutions": entries})
+			return
+		}
+		runs, _ := cmd.Flags().GetBool("runs")
+		printSolutions(cmd.OutOrStdout(), p, history, runs)
+	},
+}
+
+// solutionsShowCmd prints a past solution
+var solutionsShowCmd = &cobra.Command{
+	Use:   "show <problem> <number>",
+	Short: "Print a past solution's code",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, s, err := loadSolution(cmd, args[0], args[1])
+		if err != nil {
+			commandError(cmd, "showing the solution", err)
+			return
+		}
+
+		if jsonOutput(cmd) {
+			writeJSON(cmd, newSolutionEntry(s))
+			return
+		}
+		fmt.Fprint(cmd.OutOrStdout(), s.Code)
+	},
+}
+
+// solutionsDiffCmd compares two past solutions
+var solutionsDiffCmd = &cobra.Command{
+	Use:   "diff <problem> [from] [to]",
+	Short: "Compare two past solutions",
+	Long: `Compare two solutions to a problem line by line, marking the lines the later
+one removed with - and those it added with +. Without numbers, the last two
+solutions are compared; with one, that solution is compared with the latest.`,
+	Args: cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, history, err := loadSolutions(cmd, args[0])
+		if err == nil && len(history) < 2 {
+			err = fmt.Errorf("%s has %d solution(s); comparing needs two", p.ID, len(history))
+		}
+		if err != nil {
+			commandError(cmd, "comparing solutions", err)
+			return
+		}
+
+		// By default, compare the last two; one number is compared with the latest
+		from, to := len(history)-1, len(history)
+		for i, arg := range args[1:] {
+			n, convErr := strconv.Atoi(arg)
+			if convErr != nil {
+				commandError(cmd, "comparing solutions", fmt.Errorf("%q is not a solution number", arg))
+				return
+			}
+			if i == 0 {
+				from = n
+			} else {
+				to = n
+			}
+		}
+		a, err := solutions.Get(history, from)
+		if err != nil {
+			commandError(cmd, "comparing solutions", err)
+			return
+		}
+		b, err := solutions.Get(history, to)
+		if err != nil {
+			commandError(cmd, "comparing solutions", err)
+			return
+		}
+
+		lines := solutions.Diff(a.Code, b.Code)
+		if jsonOutput(cmd) {
+			writeJSON(cmd, solutionDiff{ProblemID: p.ID, From: from, To: to, Changed: solutions.Changed(lines), Diff: solutions.Format(lines)})
+			return
+		}
+		printSolutionDiff(cmd.OutOrStdout(), a, b, lines)
+	},
+}
+
+// solutionsRestoreCmd starts a new session from a past solution
+var solutionsRestoreCmd = &cobra.Command{
+	Use:   "restore <problem> <number>",
+	Short: "Start a new session from a past solution",
+	Long: `Start a new practice session on a problem with an earlier solution's code in
+place of the starter code, in that solution's language. The clock starts from
+zero.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		p, s, err := loadSolution(cmd, args[0], args[1])
+		if err != nil {
+			commandError(cmd, "restoring the solution", err)
+			return
+		}
+
+		state := resume.State{
+			ProblemID: p.ID,
+			Title:     p.Title,
+			Mode:      string(session.PracticeMode),
+			Language:  s.Language,
+			UI:        resume.TUI,
+			Code:      s.Code,
+			StartedAt: time.Now(),
+		}
+		if p.EstimatedTime > 0 {
+			state.Clock = clock.State{Mode: clock.Countdown, Limit: time.Duration(p.EstimatedTime) * time.Minute}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Starting %s (%s) from solution %d of %s.\n",
+			p.Title, s.Language, s.Number, s.Time.Format("2006-01-02 15:04"))
+		if err := openRestoredSession(cmd, state); err != nil {
+			commandError(cmd, "restoring the solution", err)
+		}
+	},
+}
+
+// loadSolutions returns a problem's solution history, including the runs of
+// the example tests if --runs is given
+func loadSolutions(cmd *cobra.Command, id string) (*problem.Problem, []solutions.Solution, error) {
+	p, err := problem.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	repo := storage.Default()
+	defer repo.Close()
+	attempts, err := repo.LoadAttempts(context.Background(), p.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	runs, _ := cmd.Flags().GetBool("runs")
+	return p, solutions.History(attempts, p.ID, runs), nil
+}
+
+// loadSolution returns the solution numbered arg in a problem's history
+func loadSolution(cmd *cobra.Command, id, arg string) (*problem.Problem, solutions.Solution, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, solutions.Solution{}, fmt.Errorf("%q is not a solution number", arg)
+	}
+	p, history, err := loadSolutions(cmd, id)
+	if err != nil {
+		return nil, solutions.Solution{}, err
+	}
+	s, err := solutions.Get(history, n)
+	return p, s, err
+}
+
+// newSolutionEntry describes a solution for JSON output
+func newSolutionEntry(s solutions.Solution) solutionEntry {
+	return solutionEntry{
+		Number:      s.Number,
+		Language:    s.Language,
+		Time:        s.Time,
+		Submitted:   s.Submitted,
+		Passed:      s.Passed,
+		TestsPassed: s.TestsPassed(),
+		TestsTotal:  len(s.Results),
+		Code:        s.Code,
+	}
+}
+
+// printSolutions lists a problem's solutions, numbered for the subcommands
+func printSolutions(w io.Writer, p *problem.Problem, history []solutions.Solution, runs bool) {
+	if len(history) == 0 {
+		fmt.Fprintf(w, "No solutions submitted to %s yet.\n", p.ID)
+		if !runs {
+			fmt.Fprintln(w, "Runs of the example tests are listed with --runs.")
+		}
+		return
+	}
+	fmt.Fprintf(w, "Solutions to %s (%s):\n", p.Title, p.ID)
+	for _, s := range history {
+		result := fmt.Sprintf("%s failed", symbols.Fail)
+		if s.Passed {
+			result = fmt.Sprintf("%s passed", symbols.Pass)
+		}
+		line := fmt.Sprintf("%2d. %s  %-10s %s", s.Number, s.Time.Format("2006-01-02 15:04"), s.Language, result)
+		if len(s.Results) > 0 {
+			line += fmt.Sprintf(" %d/%d tests", s.TestsPassed(), len(s.Results))
+		}
+		line += fmt.Sprintf(", %d lines", s.Lines())
+		if !s.Submitted {
+			line += " (example run)"
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "\nCompare two with: algo-scales solutions diff %s <from> <to>\n", p.ID)
+	fmt.Fprintf(w, "Start a new session from one with: algo-scales solutions restore %s <number>\n", p.ID)
+}
+
+// printSolutionDiff prints the changes from one solution to another
+func printSolutionDiff(w io.Writer, a, b solutions.Solution, lines []solutions.Line) {
+	fmt.Fprintf(w, "--- solution %d (%s, %s)\n", a.Number, a.Language, a.Time.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "+++ solution %d (%s, %s)\n", b.Number, b.Language, b.Time.Format("2006-01-02 15:04"))
+	if !solutions.Changed(lines) {
+		fmt.Fprintln(w, "The solutions are the same.")
+		return
+	}
+	fmt.Fprint(w, solutions.Format(lines))
+}
+
+func init() {
+	solutionsCmd.PersistentFlags().Bool("runs", false, "Include the runs of the example tests, not only submissions")
+	solutionsCmd.AddCommand(solutionsShowCmd)
+	solutionsCmd.AddCommand(solutionsDiffCmd)
+	solutionsCmd.AddCommand(solutionsRestoreCmd)
+	rootCmd.AddCommand(solutionsCmd)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveSolutions records attempts at two_sum in the progress database
+func saveSolutions(t *testing.T, attempts ...storage.Attempt) {
+	t.Helper()
+	repo := storage.Default()
+	defer repo.Close()
+	for _, a := range attempts {
+		require.NoError(t, repo.SaveAttempt(context.Background(), a))
+	}
+}
+
+func TestSolutions(t *testing.T) {
+	stubUserData(t)
+	original := openRestoredSession
+	t.Cleanup(func() { openRestoredSession = original })
+	var restored *resume.State
+	openRestoredSession = func(cmd *cobra.Command, state resume.State) error {
+		restored = &state
+		return nil
+	}
+
+	output, err := executeCommand(rootCmd, "solutions", "two_sum", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No solutions submitted to two_sum yet.")
+
+	start := time.Now().Add(-time.Hour)
+	saveSolutions(t,
+		storage.Attempt{ProblemID: "two_sum", Language: "go", Time: start, Code: "brute force\n"},
+		storage.Attempt{ProblemID: "two_sum", Language: "go", Time: start.Add(time.Minute), Submitted: true,
+			Code: "for i\n\tfor j\nreturn\n", Results: []storage.TestResult{{Number: 1, Passed: true}, {Number: 2}}},
+		storage.Attempt{ProblemID: "two_sum", Language: "python", Time: start.Add(2 * time.Minute), Submitted: true, Passed: true,
+			Code: "seen = {}\nfor i\nreturn\n", Results: []storage.TestResult{{Number: 1, Passed: true}, {Number: 2, Passed: true}}},
+	)
+
+	output, err = executeCommand(rootCmd, "solutions", "two_sum", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Solutions to Two Sum (two_sum):")
+	assert.Contains(t, output, " 1. ")
+	assert.Contains(t, output, "go         ❌ failed 1/2 tests, 3 lines")
+	assert.Contains(t, output, "python     ✅ passed 2/2 tests, 3 lines")
+	assert.NotContains(t, output, "example run")
+
+	output, err = executeCommand(rootCmd, "solutions", "two_sum", "--runs")
+	require.NoError(t, err)
+	assert.Contains(t, output, "1 lines (example run)")
+	assert.Contains(t, output, " 3. ")
+
+	output, err = executeCommand(rootCmd, "solutions", "show", "two_sum", "1", "--runs=false")
+	require.NoError(t, err)
+	assert.Equal(t, "for i\n\tfor j\nreturn\n", output)
+
+	// Without numbers, the last two are compared
+	output, err = executeCommand(rootCmd, "solutions", "diff", "two_sum", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "--- solution 1 (go, ")
+	assert.Contains(t, output, "+++ solution 2 (python, ")
+	assert.Contains(t, output, "+ seen = {}\n  for i\n- \tfor j\n  return\n")
+
+	output, err = executeCommand(rootCmd, "solutions", "diff", "two_sum", "2", "2", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "The solutions are the same.")
+	output, err = executeCommand(rootCmd, "solutions", "diff", "two_sum", "1", "5", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "there is no solution 5; this problem has 2")
+
+	output, err = executeCommand(rootCmd, "solutions", "restore", "two_sum", "1", "--runs=false")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Starting Two Sum (go) from solution 1")
+	require.NotNil(t, restored)
+	assert.Equal(t, "two_sum", restored.ProblemID)
+	assert.Equal(t, "go", restored.Language)
+	assert.Equal(t, "for i\n\tfor j\nreturn\n", restored.Code)
+	assert.Zero(t, restored.Elapsed(), "the restored session starts its clock afresh")
+}
+
+func TestSolutionsJSON(t *testing.T) {
+	stubUserData(t)
+	saveSolutions(t, storage.Attempt{ProblemID: "two_sum", Language: "go", Time: time.Now(), Submitted: true, Passed: true, Code: "return nil\n"})
+
+	output, code := executeJSONCommand(t, "solutions", "two_sum", "--runs=false")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, output, `"problem_id":"two_sum"`)
+	assert.Contains(t, output, `"number":1`)
+	assert.Contains(t, output, `"code":"return nil\n"`)
+
+	output, code = executeJSONCommand(t, "solutions", "diff", "two_sum", "--runs=false")
+	assert.NotEqual(t, 0, code)
+	assert.Contains(t, output, "comparing needs two")
+}
@@ -5,10 +5,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/session"
-	"github.com/lancekrogers/algo-scales/internal/ui"
-	"github.com/lancekrogers/algo-scales/internal/ui/splitscreen"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/common/features"
 	"github.com/spf13/cobra"
 )
 
@@ -96,24 +97,23 @@ but allows you to request them when needed.`,
 var cramCmd = &cobra.Command{
 	Use:   "cram",
 	Short: "Start in Cram mode",
-	Long: `Start a session in Cram mode, which quickly cycles through problems
-from the most common algorithm patterns, with a timer for each problem.`,
+	Long: `Start a session in Cram mode, which works through a queue of problems
+against one countdown, set by --timer. Each problem gets an even share of the
+time left, so time saved on one carries over to the rest, and a problem whose
+share runs out moves on to the next by itself. The queue rotates through the
+patterns, starting with those never practiced and then those your recall of
+is predicted to fade from soonest, and only repeats a pattern once every
+other has had a turn. A summary of how each problem went ends the session.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		opts := session.Options{
-			Mode:       session.CramMode,
-			Language:   language,
-			Timer:      timer,
-			Pattern:    pattern,
-			Difficulty: difficulty,
-		}
-
-		if err := session.Start(opts); err != nil {
+		queue, err := planCram(pattern, difficulty, cramProblems)
+		if err != nil {
 			fmt.Fprintf(cmd.ErrOrStderr(), "Error starting session: %v\n", err)
 			return
 		}
-		
-		// Launch the appropriate UI
-		if err := launchUI(cmd); err != nil {
+
+		// Launch the cram loop
+		c := cram.New(queue, time.Duration(timer)*time.Minute)
+		if err := launchCram(cmd, c); err != nil {
 			fmt.Fprintf(cmd.ErrOrStderr(), "Error launching UI: %v\n", err)
 		}
 	},
@@ -124,12 +124,13 @@ func init() {
 	startCmd.AddCommand(learnCmd)
 	startCmd.AddCommand(practiceCmd)
 	startCmd.AddCommand(cramCmd)
+	cramCmd.Flags().IntVarP(&cramProblems, "problems", "n", cram.DefaultProblems, "Number of problems to queue")
 
 	// Add flags to the start command and all subcommands
-	startCmd.PersistentFlags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript)")
+	startCmd.PersistentFlags().StringVarP(&language, "language", "l", "go", "Programming language (go, python, javascript, typescript, rust, java, cpp, pseudo)")
 	startCmd.PersistentFlags().IntVarP(&timer, "timer", "t", 45, "Timer duration in minutes (15, 30, 45, 60)")
 	startCmd.PersistentFlags().StringVarP(&pattern, "pattern", "p", "", "Algorithm pattern to focus on")
-	startCmd.PersistentFlags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard)")
+	startCmd.PersistentFlags().StringVarP(&difficulty, "difficulty", "d", "", "Problem difficulty (easy, medium, hard, or auto to follow recent performance)")
 }
 
 // launchUI determines which UI to launch based on flags
@@ -153,17 +154,24 @@ func launchUI(cmd *cobra.Command) error {
 	// Determine if any TUI mode is requested
 	useSplitScreen := useSplit || splitscreenFlag
 	
+	// Builds without the TUI leave the session to CLI mode
+	if !features.Available(features.TUI) {
+		fmt.Println("Session created successfully!")
+		fmt.Println("This build has no terminal UI; practice in CLI mode with 'algo-scales solve'.")
+		return nil
+	}
+	
 	// Use split-screen UI if requested
 	if useSplitScreen && isTerminal() {
-		return splitscreen.StartUI(nil)
+		return startSplitScreen()
 	} else if useTUI && isTerminal() {
 		// Use standard TUI if requested
-		return ui.StartTUI()
+		return startTUI()
 	}
 	
 	// Default to TUI mode for start commands (interactive problem solving)
 	if isTerminal() {
-		return ui.StartTUI()
+		return startTUI()
 	}
 	
 	// If not in terminal, print a message
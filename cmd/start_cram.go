@@ -0,0 +1,128 @@
+// Cram sessions: a queue of problems worked through against one countdown
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// cramProblems is how many problems 'start cram' queues
+var cramProblems int
+
+// planCram queues n problems for a cram session, rotating through the
+// patterns from the least recently reinforced, or through pattern alone if
+// one is given. Difficulty auto picks a difficulty for each pattern.
+func planCram(pattern, difficulty string, n int) ([]problem.Problem, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("a cram session needs at least 1 problem")
+	}
+	problems, err := problem.ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load problems: %v", err)
+	}
+	problems = problem.Visible(problems)
+
+	// Without history every pattern counts as never practiced
+	forecasts, _ := stats.GetForecasts()
+	order := cram.PatternOrder(problems, forecasts)
+	if pattern != "" {
+		order = []string{pattern}
+	}
+
+	queue := cram.Plan(filterCramDifficulty(problems, order, difficulty), n, order)
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("no problems found matching criteria")
+	}
+	return queue, nil
+}
+
+// filterCramDifficulty keeps the problems of difficulty. With auto, each
+// pattern keeps the difficulty recent performance in it calls for, or all
+// its problems when it has none of that difficulty.
+func filterCramDifficulty(problems []problem.Problem, order []string, difficulty string) []problem.Problem {
+	if difficulty == "" {
+		return problems
+	}
+	wanted := make(map[string]string, len(order))
+	for _, pattern := range order {
+		wanted[pattern] = difficulty
+		if difficulty == session.DifficultyAuto {
+			wanted[pattern] = session.ChooseDifficulty(pattern).Difficulty
+		}
+	}
+
+	var kept []problem.Problem
+	available := make(map[string]bool)
+	for _, p := range problems {
+		pattern := cramPattern(p, order)
+		if pattern != "" && strings.EqualFold(p.Difficulty, wanted[pattern]) {
+			kept = append(kept, p)
+			available[pattern] = true
+		}
+	}
+	if difficulty != session.DifficultyAuto {
+		return kept
+	}
+	for _, p := range problems {
+		if pattern := cramPattern(p, order); pattern != "" && !available[pattern] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// cramPattern returns the pattern a problem counts towards in a cram
+// session: the first of its patterns in order
+func cramPattern(p problem.Problem, order []string) string {
+	for _, pattern := range order {
+		for _, own := range p.Patterns {
+			if own == pattern {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+// launchCram works through a cram session in the terminal UI. Tests, builds
+// without the TUI and output that is not a terminal print the queue instead.
+func launchCram(cmd *cobra.Command, c *cram.Session) error {
+	if os.Getenv("TESTING") == "1" || !isTerminal() {
+		printCramPlan(cmd.OutOrStdout(), c)
+		return nil
+	}
+	if !features.Available(features.TUI) {
+		printCramPlan(cmd.OutOrStdout(), c)
+		fmt.Fprintln(cmd.OutOrStdout(), "This build has no terminal UI; practice these in CLI mode with 'algo-scales solve <problem>'.")
+		return nil
+	}
+	return startCram(c)
+}
+
+// printCramPlan lists a cram session's queue
+func printCramPlan(w io.Writer, c *cram.Session) {
+	fmt.Fprintf(w, "Cram session: %d problems in %s\n", len(c.Queue), formatMinutes(c.Budget))
+	for i, p := range c.Queue {
+		fmt.Fprintf(w, "  %d. %s (%s, %s)\n", i+1, p.Title, strings.Join(p.Patterns, ", "), p.Difficulty)
+	}
+}
+
+// formatMinutes writes a duration in whole minutes, such as "45 minutes"
+func formatMinutes(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
@@ -4,9 +4,13 @@ package cmd
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +125,44 @@ func TestStartCommand(t *testing.T) {
 		assert.Contains(t, output, "Error starting session") // But output should contain error message
 	})
 }
+
+func TestStartCram(t *testing.T) {
+	originalList := problem.ListAll
+	originalForecasts := stats.GetForecasts
+	defer func() {
+		problem.ListAll = originalList
+		stats.GetForecasts = originalForecasts
+	}()
+	problem.ListAll = func() ([]problem.Problem, error) {
+		return []problem.Problem{
+			{ID: "pair_sum", Title: "Pair Sum", Difficulty: "easy", Patterns: []string{"two-pointers"}},
+			{ID: "three_sum", Title: "Three Sum", Difficulty: "medium", Patterns: []string{"two-pointers"}},
+			{ID: "two_sum", Title: "Two Sum", Difficulty: "easy", Patterns: []string{"hash-map"}},
+			{ID: "group_anagrams", Title: "Group Anagrams", Difficulty: "medium", Patterns: []string{"hash-map"}},
+			{ID: "islands", Title: "Number of Islands", Difficulty: "medium", Patterns: []string{"dfs"}},
+		}, nil
+	}
+	// Only dfs has been practiced, so it comes after the others
+	stats.GetForecasts = func() ([]stats.PatternRetention, error) {
+		return []stats.PatternRetention{{Pattern: "dfs", DecaysAt: time.Now()}}, nil
+	}
+
+	output, err := executeCommand(rootCmd, "start", "cram", "-n", "4", "--timer", "20", "--pattern=", "--difficulty=")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Cram session: 4 problems in 20 minutes")
+	assert.Contains(t, output, "  3. Number of Islands (dfs, medium)")
+	// Each pattern has a turn before any comes back
+	first := strings.Join(strings.Split(output, "\n")[1:3], "\n")
+	assert.Contains(t, first, "two-pointers")
+	assert.Contains(t, first, "hash-map")
+
+	// Only medium problems, one per pattern until they run out
+	output, err = executeCommand(rootCmd, "start", "cram", "-n", "5", "--difficulty", "medium")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Cram session: 3 problems")
+	assert.NotContains(t, output, "easy")
+
+	output, err = executeCommand(rootCmd, "start", "cram", "--pattern", "dfs", "--difficulty", "hard")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Error starting session: no problems found matching criteria")
+}
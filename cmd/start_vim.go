@@ -13,28 +13,53 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// handleVimModeSession handles starting a session in vim mode
-func handleVimModeSession(opts session.Options) {
+// handleVimModeSession handles starting a session in vim mode, or with
+// --output json
+func handleVimModeSession(cmd *cobra.Command, opts session.Options) {
+	resp, err := startVimSession(context.Background(), opts)
+	if jsonOutput(cmd) {
+		if err != nil {
+			failJSON(cmd, err)
+			return
+		}
+		writeJSON(cmd, resp)
+		return
+	}
+	if err != nil {
+		outputVimError(err)
+		return
+	}
+
+	// Return JSON response
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		outputVimError(fmt.Errorf("error marshaling JSON: %v", err))
+		return
+	}
+
+	fmt.Println(string(jsonResp))
+}
+
+// startVimSession picks a problem and creates a session and workspace for it
+func startVimSession(ctx context.Context, opts session.Options) (VimProblemResponse, error) {
 	// Get problem service
 	problemService := services.DefaultRegistry.GetProblemService()
 
 	var prob *problem.Problem
+	var reason string
 	var err error
 
-	ctx := context.Background()
 	if opts.ProblemID != "" {
 		// Get specific problem
 		prob, err = problemService.GetByID(ctx, opts.ProblemID)
 		if err != nil {
-			outputVimError(fmt.Errorf("failed to get problem: %v", err))
-			return
+			return VimProblemResponse{}, fmt.Errorf("failed to get problem: %v", err)
 		}
 	} else {
 		// Get a random problem based on filters
 		problems, err := problemService.ListAll(ctx)
 		if err != nil {
-			outputVimError(fmt.Errorf("failed to get problems: %v", err))
-			return
+			return VimProblemResponse{}, fmt.Errorf("failed to get problems: %v", err)
 		}
 
 		// Filter by pattern if specified
@@ -51,6 +76,12 @@ func handleVimModeSession(opts session.Options) {
 			problems = filtered
 		}
 
+		// Difficulty auto follows recent performance in the pattern
+		if opts.Difficulty == session.DifficultyAuto {
+			choice := session.ChooseDifficulty(opts.Pattern)
+			opts.Difficulty, reason = choice.Difficulty, choice.Reason
+		}
+
 		// Filter by difficulty if specified
 		if opts.Difficulty != "" {
 			var filtered []problem.Problem
@@ -59,12 +90,17 @@ func handleVimModeSession(opts session.Options) {
 					filtered = append(filtered, p)
 				}
 			}
+			if len(filtered) == 0 && reason != "" && len(problems) > 0 {
+				// Nothing at the chosen difficulty; any will do
+				reason = fmt.Sprintf("%s, but there are none, so this one is %s", reason, problems[0].Difficulty)
+				opts.Difficulty = problems[0].Difficulty
+				filtered = problems[:1]
+			}
 			problems = filtered
 		}
 
 		if len(problems) == 0 {
-			outputVimError(fmt.Errorf("no problems found matching criteria"))
-			return
+			return VimProblemResponse{}, fmt.Errorf("no problems found matching criteria")
 		}
 
 		// Select first problem (could be randomized)
@@ -74,8 +110,7 @@ func handleVimModeSession(opts session.Options) {
 	// Create a session and workspace for this problem
 	sess, err := session.CreateSession(opts)
 	if err != nil {
-		outputVimError(fmt.Errorf("failed to create session: %v", err))
-		return
+		return VimProblemResponse{}, fmt.Errorf("failed to create session: %v", err)
 	}
 
 	// Create the response with workspace information
@@ -88,6 +123,8 @@ func handleVimModeSession(opts session.Options) {
 		Description: formatProblemDescriptionForVim(prob),
 		WorkspacePath: sess.Workspace,
 		SessionID:   sess.Problem.ID, // Use problem ID as session identifier
+		Warning:     session.CheckProblemChanged(*prob),
+		Reason:      reason,
 	}
 
 	// Get the starter code
@@ -111,14 +148,7 @@ func handleVimModeSession(opts session.Options) {
 		}
 	}
 
-	// Return JSON response
-	jsonResp, err := json.Marshal(resp)
-	if err != nil {
-		outputVimError(fmt.Errorf("error marshaling JSON: %v", err))
-		return
-	}
-
-	fmt.Println(string(jsonResp))
+	return resp, nil
 }
 
 // formatProblemDescriptionForVim formats the problem description for vim mode
@@ -155,7 +185,7 @@ func init() {
 	oldLearnRun := learnCmd.Run
 	learnCmd.Run = func(cmd *cobra.Command, args []string) {
 		isVimMode, _ := cmd.Root().PersistentFlags().GetBool("vim-mode")
-		if isVimMode {
+		if isVimMode || jsonOutput(cmd) {
 			var problemID string
 			if len(args) > 0 {
 				problemID = args[0]
@@ -169,7 +199,7 @@ func init() {
 				Difficulty: difficulty,
 				ProblemID:  problemID,
 			}
-			handleVimModeSession(opts)
+			handleVimModeSession(cmd, opts)
 			return
 		}
 		// Call original handler
@@ -179,7 +209,7 @@ func init() {
 	oldPracticeRun := practiceCmd.Run
 	practiceCmd.Run = func(cmd *cobra.Command, args []string) {
 		isVimMode, _ := cmd.Root().PersistentFlags().GetBool("vim-mode")
-		if isVimMode {
+		if isVimMode || jsonOutput(cmd) {
 			var problemID string
 			if len(args) > 0 {
 				problemID = args[0]
@@ -193,7 +223,7 @@ func init() {
 				Difficulty: difficulty,
 				ProblemID:  problemID,
 			}
-			handleVimModeSession(opts)
+			handleVimModeSession(cmd, opts)
 			return
 		}
 		// Call original handler
@@ -203,7 +233,7 @@ func init() {
 	oldCramRun := cramCmd.Run
 	cramCmd.Run = func(cmd *cobra.Command, args []string) {
 		isVimMode, _ := cmd.Root().PersistentFlags().GetBool("vim-mode")
-		if isVimMode {
+		if isVimMode || jsonOutput(cmd) {
 			opts := session.Options{
 				Mode:       session.CramMode,
 				Language:   language,
@@ -211,7 +241,7 @@ func init() {
 				Pattern:    pattern,
 				Difficulty: difficulty,
 			}
-			handleVimModeSession(opts)
+			handleVimModeSession(cmd, opts)
 			return
 		}
 		// Call original handler
@@ -3,10 +3,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"strings"
 
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/quiz"
 	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/ui/heatmap"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +26,11 @@ var statsCmd = &cobra.Command{
 		// Default behavior shows summary stats
 		statistics, err := stats.GetSummary()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error retrieving stats: %v\n", err)
+			commandError(cmd, "retrieving stats", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeStatsJSON(cmd, statistics)
 			return
 		}
 
@@ -29,40 +40,213 @@ var statsCmd = &cobra.Command{
 		fmt.Fprintf(cmd.OutOrStdout(), "Average Solve Time: %s\n", statistics.AvgSolveTime)
 		fmt.Fprintf(cmd.OutOrStdout(), "Fastest Solve: %s (%s)\n", statistics.FastestSolve.Time, statistics.FastestSolve.ProblemID)
 		fmt.Fprintf(cmd.OutOrStdout(), "Most Challenging: %s (attempts: %d)\n", statistics.MostChallenging.ProblemID, statistics.MostChallenging.Attempts)
+		if statistics.Swaps > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Swapped for Easier: %d\n", statistics.Swaps)
+		}
+
+		report, err := loadHintReport()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error retrieving hint usage: %v\n", err)
+			return
+		}
+		writeHintReport(cmd.OutOrStdout(), report)
+
+		records, err := loadComplexity()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error retrieving complexity feedback: %v\n", err)
+			return
+		}
+		writeComplexityStats(cmd.OutOrStdout(), records)
 	},
 }
 
+// loadHintReport totals hint usage under the configured hint policy
+// Exported as variable for testing
+var loadHintReport = func() (hints.Report, error) {
+	tracker := openHintTracker()
+	defer tracker.Close()
+	return tracker.Report(context.Background())
+}
+
+// statsResponse is what 'stats --output json' prints
+type statsResponse struct {
+	Summary    *stats.Summary   `json:"summary"`
+	Hints      hintUsage        `json:"hints"`
+	Complexity complexityTotals `json:"complexity"`
+}
+
+// hintUsage is the help used under the hint policy, as JSON
+type hintUsage struct {
+	Policy          string `json:"policy"`
+	Hints           int    `json:"hints"`
+	AIHints         int    `json:"ai_hints"`
+	Problems        int    `json:"problems"` // Unsolved problems with any hints or a viewed solution
+	SolutionsViewed int    `json:"solutions_viewed"`
+	Denied          int    `json:"denied"`
+}
+
+// writeStatsJSON prints the summary, hint usage and complexity together
+func writeStatsJSON(cmd *cobra.Command, summary *stats.Summary) {
+	report, err := loadHintReport()
+	if err != nil {
+		commandError(cmd, "retrieving hint usage", err)
+		return
+	}
+	records, err := loadComplexity()
+	if err != nil {
+		commandError(cmd, "retrieving complexity feedback", err)
+		return
+	}
+	writeJSON(cmd, statsResponse{
+		Summary: summary,
+		Hints: hintUsage{
+			Policy:          hints.Describe(report.Policy),
+			Hints:           report.Hints,
+			AIHints:         report.AIHints,
+			Problems:        report.Problems,
+			SolutionsViewed: report.SolutionsViewed,
+			Denied:          report.Denied,
+		},
+		Complexity: countComplexity(records),
+	})
+}
+
+// writeHintReport prints the hint policy and the help used under it
+func writeHintReport(out io.Writer, report hints.Report) {
+	fmt.Fprintf(out, "\nHint Policy: %s\n", hints.Describe(report.Policy))
+	if report.Hints == 0 && report.SolutionsViewed == 0 && report.Denied == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Hints Used: %d (%d from AI) across %d unsolved problem(s)\n", report.Hints, report.AIHints, report.Problems)
+	fmt.Fprintf(out, "Solutions Viewed: %d\n", report.SolutionsViewed)
+	fmt.Fprintf(out, "Hint Requests Denied: %d\n", report.Denied)
+}
+
 // patternStatsCmd represents the patterns subcommand for stats
 var patternStatsCmd = &cobra.Command{
 	Use:   "patterns",
 	Short: "View stats by pattern",
-	Long:  `View your algorithm problem-solving statistics organized by pattern.`,
+	Long: `View your algorithm problem-solving statistics organized by pattern, with
+how accurately you recognize each pattern in 'algo-scales quiz'.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		patternStats, err := stats.GetByPattern()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error retrieving pattern stats: %v\n", err)
+			commandError(cmd, "retrieving pattern stats", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string]map[string]stats.PatternStats{"patterns": patternStats})
 			return
 		}
 
+		answers, err := loadQuizAnswers()
+		if err != nil {
+			commandError(cmd, "retrieving quiz answers", err)
+			return
+		}
+		recognition := make(map[string]quiz.Accuracy)
+		for _, a := range quiz.ByPattern(answers) {
+			recognition[a.Pattern] = a
+		}
+
 		fmt.Fprintln(cmd.OutOrStdout(), "Stats by Pattern:")
 		for pattern, pstat := range patternStats {
 			fmt.Fprintf(cmd.OutOrStdout(), "\n%s:\n", pattern)
 			fmt.Fprintf(cmd.OutOrStdout(), "  Attempted: %d, Solved: %d\n", pstat.Attempted, pstat.Solved)
 			fmt.Fprintf(cmd.OutOrStdout(), "  Success Rate: %.1f%%\n", pstat.SuccessRate)
 			fmt.Fprintf(cmd.OutOrStdout(), "  Average Time: %s\n", pstat.AvgTime)
+			if a, ok := recognition[pattern]; ok {
+				fmt.Fprintf(cmd.OutOrStdout(), "  Recognition: %.0f%% (%d of %d quiz answers)\n", a.Rate(), a.Correct, a.Answered)
+			}
+		}
+	},
+}
+
+// contextStatsCmd represents the contexts subcommand for stats
+var contextStatsCmd = &cobra.Command{
+	Use:   "contexts",
+	Short: "View stats by practice context",
+	Long: `View your statistics split by where you practiced: the CLI, the TUI, the
+Neovim plugin, daily practice or an MCP client. Sessions recorded before
+contexts were kept are listed as unknown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		contextStats, err := stats.GetByContext()
+		if err != nil {
+			commandError(cmd, "retrieving context stats", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, map[string]map[string]stats.ContextStats{"contexts": contextStats})
+			return
 		}
+		writeContextStats(cmd.OutOrStdout(), contextStats)
 	},
 }
 
+// contextOrder lists practice contexts in the order they are shown
+var contextOrder = []string{
+	interfaces.ContextCLI,
+	interfaces.ContextTUI,
+	interfaces.ContextVim,
+	interfaces.ContextDaily,
+	interfaces.ContextMCP,
+	stats.UnknownContext,
+}
+
+// writeContextStats prints the stats of each practice context
+func writeContextStats(out io.Writer, contextStats map[string]stats.ContextStats) {
+	if len(contextStats) == 0 {
+		fmt.Fprintln(out, "No sessions recorded yet.")
+		return
+	}
+
+	fmt.Fprintln(out, "Stats by Context:")
+	for _, name := range contextOrder {
+		cstat, ok := contextStats[name]
+		if !ok {
+			continue
+		}
+		avgTime := cstat.AvgTime
+		if avgTime == "" {
+			avgTime = "-"
+		}
+		fmt.Fprintf(out, "  %-8s %3d attempted, %3d solved (%.1f%%), avg time %s\n",
+			name, cstat.Attempted, cstat.Solved, cstat.SuccessRate, avgTime)
+	}
+}
+
+// trendsResponse is what 'stats trends --output json' prints: the overall
+// trends, and each pattern's with the one to practice next
+type trendsResponse struct {
+	*stats.Trends
+	stats.PatternTrends
+}
+
 // trendsCmd represents the trends subcommand for stats
 var trendsCmd = &cobra.Command{
 	Use:   "trends",
 	Short: "View progress trends",
-	Long:  `View your progress trends over time.`,
+	Long: `View your progress trends over time: problems solved over the last days and
+weeks, then each pattern's fail rate, hint usage and solve time over the last
+8 weeks. Patterns that fail often, need hints or take long are flagged as weak,
+and the scale to practice next is recommended.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		trends, err := stats.GetTrends()
 		if err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error retrieving trend stats: %v\n", err)
+			commandError(cmd, "retrieving trend stats", err)
+			return
+		}
+		var known []string
+		for _, scale := range daily.Scales {
+			known = append(known, scale.Pattern)
+		}
+		patterns, err := stats.GetPatternTrends(known)
+		if err != nil {
+			commandError(cmd, "retrieving pattern trends", err)
+			return
+		}
+		if jsonOutput(cmd) {
+			writeJSON(cmd, trendsResponse{Trends: trends, PatternTrends: patterns})
 			return
 		}
 
@@ -76,46 +260,106 @@ var trendsCmd = &cobra.Command{
 		for _, week := range trends.Weekly {
 			fmt.Fprintf(cmd.OutOrStdout(), "  Week of %s: %d solved (success rate: %.1f%%)\n", week.StartDate, week.Solved, week.SuccessRate)
 		}
+
+		fmt.Fprintln(cmd.OutOrStdout())
+		printPatternTrends(cmd.OutOrStdout(), patterns)
 	},
 }
 
-// resetStatsCmd represents the reset subcommand for stats
-var resetStatsCmd = &cobra.Command{
-	Use:   "reset",
-	Short: "Reset statistics",
-	Long:  `Reset your problem-solving statistics.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Mock user interaction for tests
-		if os.Getenv("TESTING") == "1" {
-			fmt.Fprintln(cmd.OutOrStdout(), "Are you sure you want to reset all statistics? (y/N): Operation cancelled.")
-			return
+// printPatternTrends prints a table of each practiced pattern, the weakest
+// patterns and why, and the scale to practice next
+func printPatternTrends(w io.Writer, trends stats.PatternTrends) {
+	fmt.Fprintf(w, "Pattern Trends (last %d weeks):\n", trends.Weeks)
+	practiced := 0
+	for _, p := range trends.Patterns {
+		if p.Attempted == 0 {
+			continue
+		}
+		if practiced == 0 {
+			fmt.Fprintf(w, "  %-22s %5s %7s %6s %9s  %-10s %s\n",
+				"Pattern", "Tries", "Failed", "Hints", "Avg time", "Trend", "Weakness")
 		}
+		practiced++
+		avg, direction := p.AvgSolveTime, p.Direction
+		if avg == "" {
+			avg = "-"
+		}
+		if direction == "" {
+			direction = "-"
+		}
+		weakness := fmt.Sprintf("%.2f", p.Weakness)
+		switch {
+		case !p.Judged:
+			weakness = "too few tries"
+		case p.Weak:
+			weakness += " weak"
+		}
+		fmt.Fprintf(w, "  %-22s %5d %6.0f%% %5.0f%% %9s  %-10s %s\n",
+			p.Pattern, p.Attempted, p.FailRate, p.HintRate, avg, direction, weakness)
+	}
+	if practiced == 0 {
+		fmt.Fprintln(w, "  No patterns practiced yet")
+	}
 
-		confirm := false
-		fmt.Fprint(cmd.OutOrStdout(), "Are you sure you want to reset all statistics? (y/N): ")
-		var response string
-		fmt.Scanln(&response)
-		if response == "y" || response == "Y" {
-			confirm = true
+	if weakest := trends.Weakest(); len(weakest) > 0 {
+		fmt.Fprintln(w, "\nWeakest patterns:")
+		for _, p := range weakest {
+			fmt.Fprintf(w, "  %s: %s\n", p.Pattern, strings.Join(p.Reasons, ", "))
 		}
+	}
 
-		if !confirm {
-			fmt.Fprintln(cmd.OutOrStdout(), "Operation cancelled.")
+	if trends.Recommended == "" {
+		return
+	}
+	name := trends.Recommended
+	if scale := daily.GetScaleByPattern(trends.Recommended); scale != nil {
+		name = fmt.Sprintf("%s (%s)", scale.MusicalName, trends.Recommended)
+	}
+	fmt.Fprintf(w, "\nPractice next: %s, %s\n", name, trends.Reason)
+	fmt.Fprintf(w, "  algo-scales start practice --pattern %s\n", trends.Recommended)
+}
+
+// calendarResponse is what 'stats calendar --output json' prints: the
+// calendar, and the daily rotation's coming weeks if one is configured
+type calendarResponse struct {
+	stats.Calendar
+	Rotation []daily.RotationWeek `json:"rotation,omitempty"`
+}
+
+// calendarCmd represents the calendar subcommand for stats
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "View a calendar of your daily practice",
+	Long: `View a GitHub-style calendar of the past year, with a column per week and a
+row per weekday, shaded by how many problems you practiced that day. If daily
+practice rotates through patterns weekly, the coming weeks' patterns are
+listed under it. The statistics screen of the TUI shows the same calendar.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cal, err := stats.GetCalendar()
+		if err != nil {
+			commandError(cmd, "retrieving the practice calendar", err)
 			return
 		}
-
-		if err := stats.Reset(); err != nil {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Error resetting stats: %v\n", err)
+		plan := daily.Plan()
+		if jsonOutput(cmd) {
+			writeJSON(cmd, calendarResponse{Calendar: cal, Rotation: plan})
 			return
 		}
-
-		fmt.Fprintln(cmd.OutOrStdout(), "Statistics have been reset.")
+		fmt.Fprintln(cmd.OutOrStdout(), heatmap.Summary(cal))
+		fmt.Fprintln(cmd.OutOrStdout())
+		fmt.Fprintln(cmd.OutOrStdout(), heatmap.Render(cal))
+		if len(plan) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+			printRotationPlan(cmd.OutOrStdout(), plan)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(statsCmd)
 	statsCmd.AddCommand(patternStatsCmd)
+	statsCmd.AddCommand(contextStatsCmd)
 	statsCmd.AddCommand(trendsCmd)
+	statsCmd.AddCommand(calendarCmd)
 	statsCmd.AddCommand(resetStatsCmd)
 }
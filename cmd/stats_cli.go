@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/spf13/cobra"
 )
@@ -87,9 +88,9 @@ func displayCLIStats() {
 	fmt.Printf("\n## Recent Activity\n")
 	recent := getRecentSessions(sessions, 5)
 	for i, s := range recent {
-		solved := "❌"
+		solved := symbols.Fail.String()
 		if s.Solved {
-			solved = "✅"
+			solved = symbols.Pass.String()
 		}
 		fmt.Printf("%d. %s %s [%s] - %s\n", i+1, solved, s.ProblemID, JoinStrings(s.Patterns), formatTime(s.EndTime))
 	}
@@ -0,0 +1,208 @@
+// Stats reset command for deleting all or some of the recorded progress
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// resetDateLayout is the format of the --before date
+const resetDateLayout = "2006-01-02"
+
+// confirmReset asks the user to confirm a reset
+// Exported as variable for testing
+var confirmReset = func(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprint(out, prompt)
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.TrimSpace(response)
+	return response == "y" || response == "Y"
+}
+
+// resetStatsCmd represents the reset subcommand for stats
+var resetStatsCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset statistics",
+	Long: `Reset your problem-solving statistics, or only some of them.
+
+With --pattern, only progress on that pattern's problems is deleted; with
+--before, only progress from before that date. Sessions, test runs, review
+schedules, hint usage, complexity estimates and quiz answers are deleted, and
+the daily streak is recounted from the sessions that remain. Pattern ratings
+and trends are always computed from the remaining sessions.
+
+The progress database is backed up first, to the backups directory next to
+it, so a reset can be undone by copying the backup back.
+
+Examples:
+  algo-scales stats reset
+  algo-scales stats reset --pattern dp
+  algo-scales stats reset --before 2024-01-01 --yes`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern, _ := cmd.Flags().GetString("pattern")
+		before, _ := cmd.Flags().GetString("before")
+		yes, _ := cmd.Flags().GetBool("yes")
+		out := cmd.OutOrStdout()
+		ctx := context.Background()
+
+		var prune storage.Prune
+		if before != "" {
+			cutoff, err := time.ParseInLocation(resetDateLayout, before, time.Local)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Invalid --before date %q: use YYYY-MM-DD\n", before)
+				return
+			}
+			prune.Before = cutoff
+		}
+
+		repo := storage.Default()
+		defer repo.Close()
+
+		if pattern != "" {
+			ids, err := patternProblemIDs(ctx, repo, pattern)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error finding problems for pattern %q: %v\n", pattern, err)
+				return
+			}
+			if len(ids) == 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "No problems found for pattern %q\n", pattern)
+				return
+			}
+			prune.ProblemIDs = ids
+		}
+
+		prompt := fmt.Sprintf("Are you sure you want to reset %s? (y/N): ", describeReset(pattern, before))
+		if !yes && !confirmReset(cmd.InOrStdin(), out, prompt) {
+			fmt.Fprintln(out, "Operation cancelled.")
+			return
+		}
+
+		// Nothing is deleted without a backup to restore it from
+		backup := backupPath(filepath.Join(filepath.Dir(storage.DBPath()), "backups"), time.Now())
+		if err := repo.Backup(ctx, backup); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error backing up progress, nothing was reset: %v\n", err)
+			return
+		}
+		fmt.Fprintf(out, "Backed up your progress to %s\n", backup)
+
+		pruned, err := repo.PruneProgress(ctx, prune)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error resetting stats: %v\n", err)
+			return
+		}
+		writePruned(out, pruned)
+
+		streak, err := daily.RecountStreak(ctx, repo)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error recounting the daily streak: %v\n", err)
+			return
+		}
+		fmt.Fprintf(out, "Daily streak recounted: %d day(s), longest %d.\n", streak.Current, streak.Longest)
+		fmt.Fprintln(out, "Statistics have been reset.")
+	},
+}
+
+// backupPath names a new backup of the progress database after the time it
+// was taken, numbering backups taken within the same second
+func backupPath(dir string, now time.Time) string {
+	name := "progress-" + now.Format("20060102-150405")
+	path := filepath.Join(dir, name+".db")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.db", name, i))
+	}
+}
+
+// describeReset names what a reset deletes, for the confirmation prompt
+func describeReset(pattern, before string) string {
+	scope := "all statistics"
+	if pattern != "" {
+		scope = fmt.Sprintf("statistics for the %s pattern", pattern)
+	}
+	if before != "" {
+		scope += " from before " + before
+	}
+	return scope
+}
+
+// patternProblemIDs returns the problems of a pattern, both those in the
+// catalog and those with recorded sessions, which covers problems since
+// removed. Patterns match by name or by initials, so dp is
+// dynamic-programming.
+func patternProblemIDs(ctx context.Context, repo storage.Repository, pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string, patterns []string) {
+		for _, p := range patterns {
+			if matchesPattern(p, pattern) && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	problems, err := problem.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range problems {
+		add(p.ID, p.Patterns)
+	}
+
+	sessions, err := repo.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		add(s.ProblemID, s.Patterns)
+	}
+	return ids, nil
+}
+
+// matchesPattern reports whether a pattern name such as dynamic-programming
+// is meant by query, either in full or by its initials
+func matchesPattern(name, query string) bool {
+	if strings.EqualFold(name, query) {
+		return true
+	}
+	words := strings.Split(name, "-")
+	if len(words) < 2 {
+		return false
+	}
+	var initials strings.Builder
+	for _, word := range words {
+		if word != "" {
+			initials.WriteByte(word[0])
+		}
+	}
+	return strings.EqualFold(initials.String(), query)
+}
+
+// writePruned prints how many records a reset deleted
+func writePruned(out io.Writer, pruned storage.Pruned) {
+	if pruned.Total() == 0 {
+		fmt.Fprintln(out, "No recorded progress matched; nothing was deleted.")
+		return
+	}
+	fmt.Fprintf(out, "Deleted %d session(s), %d test run(s), %d review schedule(s), %d hint record(s), %d complexity estimate(s) and %d quiz answer(s).\n",
+		pruned.Sessions, pruned.Attempts, pruned.Reviews, pruned.HintUsage, pruned.Complexity, pruned.QuizAnswers)
+}
+
+func init() {
+	resetStatsCmd.Flags().StringP("pattern", "p", "", "Only reset progress on this pattern's problems")
+	resetStatsCmd.Flags().String("before", "", "Only reset progress from before this date (YYYY-MM-DD)")
+	resetStatsCmd.Flags().BoolP("yes", "y", false, "Reset without asking for confirmation")
+}
@@ -3,12 +3,26 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock stats.GetSummary for testing
@@ -33,6 +47,17 @@ func mockGetByPattern(patternStats map[string]stats.PatternStats, err error) fun
 	}
 }
 
+// Mock stats.GetByContext for testing
+func mockGetByContext(contextStats map[string]stats.ContextStats, err error) func() {
+	original := stats.GetByContext
+	stats.GetByContext = func() (map[string]stats.ContextStats, error) {
+		return contextStats, err
+	}
+	return func() {
+		stats.GetByContext = original
+	}
+}
+
 // Mock stats.GetTrends for testing
 func mockGetTrends(trends *stats.Trends, err error) func() {
 	original := stats.GetTrends
@@ -44,18 +69,73 @@ func mockGetTrends(trends *stats.Trends, err error) func() {
 	}
 }
 
-// Mock stats.Reset for testing
-func mockReset(err error) func() {
-	original := stats.Reset
-	stats.Reset = func() error {
-		return err
+// Mock stats.GetPatternTrends for testing
+func mockGetPatternTrends(trends stats.PatternTrends, err error) func() {
+	original := stats.GetPatternTrends
+	stats.GetPatternTrends = func([]string) (stats.PatternTrends, error) {
+		return trends, err
+	}
+	return func() {
+		stats.GetPatternTrends = original
+	}
+}
+
+// stubProgress points the progress database at a fresh directory, with the
+// given problems in the catalog and the user's answer to any confirmation
+func stubProgress(t *testing.T, problems []problem.Problem, confirm bool) storage.Repository {
+	t.Helper()
+	dir := t.TempDir()
+	originalPath, originalList, originalConfirm := storage.DBPath, problem.ListAll, confirmReset
+	t.Cleanup(func() { storage.DBPath, problem.ListAll, confirmReset = originalPath, originalList, originalConfirm })
+
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+	problem.ListAll = func() ([]problem.Problem, error) { return problems, nil }
+	confirmReset = func(in io.Reader, out io.Writer, prompt string) bool {
+		fmt.Fprint(out, prompt)
+		return confirm
+	}
+	repo := storage.NewSQLiteStore(storage.DBPath())
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// Mock loadHintReport for testing
+func mockHintReport(report hints.Report, err error) func() {
+	original := loadHintReport
+	loadHintReport = func() (hints.Report, error) {
+		return report, err
+	}
+	return func() {
+		loadHintReport = original
+	}
+}
+
+// Mock loadComplexity for testing
+func mockComplexity(records []storage.Complexity, err error) func() {
+	original := loadComplexity
+	loadComplexity = func() ([]storage.Complexity, error) {
+		return records, err
 	}
 	return func() {
-		stats.Reset = original
+		loadComplexity = original
+	}
+}
+
+// Mock loadQuizAnswers for testing
+func mockQuizAnswers(answers []storage.QuizAnswer, err error) func() {
+	original := loadQuizAnswers
+	loadQuizAnswers = func() ([]storage.QuizAnswer, error) {
+		return answers, err
+	}
+	return func() {
+		loadQuizAnswers = original
 	}
 }
 
 func TestStatsCommand(t *testing.T) {
+	defer mockHintReport(hints.Report{}, nil)()
+	defer mockComplexity(nil, nil)()
+
 	t.Run("Summary", func(t *testing.T) {
 		// Create a sample summary
 		summary := &stats.Summary{
@@ -97,6 +177,37 @@ func TestStatsCommand(t *testing.T) {
 		assert.Contains(t, output, "3")            // MostChallenging attempts
 	})
 
+	t.Run("HintUsage", func(t *testing.T) {
+		restore := mockGetSummary(&stats.Summary{}, nil)
+		defer restore()
+		defer mockHintReport(hints.Report{
+			Policy:   config.HintPolicy{MaxHints: 3, SolutionAfterFailures: 2},
+			Problems: 2, Hints: 4, AIHints: 1, SolutionsViewed: 1, Denied: 5,
+		}, nil)()
+
+		output, err := executeCommand(rootCmd, "stats")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Hint Policy: 3 hints per problem, solution after 2 failed runs")
+		assert.Contains(t, output, "Hints Used: 4 (1 from AI) across 2 unsolved problem(s)")
+		assert.Contains(t, output, "Solutions Viewed: 1")
+		assert.Contains(t, output, "Hint Requests Denied: 5")
+	})
+
+	t.Run("Complexity", func(t *testing.T) {
+		restore := mockGetSummary(&stats.Summary{}, nil)
+		defer restore()
+		defer mockComplexity([]storage.Complexity{
+			{ProblemID: "two_sum", Language: "go", Verdict: "optimal"},
+			{ProblemID: "two_sum", Language: "python", Verdict: "slower"},
+			{ProblemID: "3sum", Language: "go", Verdict: "more-space"},
+			{ProblemID: "custom", Language: "go", Verdict: "unknown"},
+		}, nil)()
+
+		output, err := executeCommand(rootCmd, "stats")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Complexity: 1 of 3 analyzed solution(s) match the reference (1 slower, 1 using more space)")
+	})
+
 	t.Run("PatternStats", func(t *testing.T) {
 		// Create sample pattern stats
 		patternStats := map[string]stats.PatternStats{
@@ -119,10 +230,16 @@ func TestStatsCommand(t *testing.T) {
 		// Mock GetByPattern
 		restore := mockGetByPattern(patternStats, nil)
 		defer restore()
+		defer mockQuizAnswers([]storage.QuizAnswer{
+			{Pattern: "hash-map", Chosen: "hash-map", Correct: true},
+			{Pattern: "hash-map", Chosen: "two-pointers"},
+		}, nil)()
 
 		// Execute patterns command
 		output, err := executeCommand(rootCmd, "stats", "patterns")
 		assert.NoError(t, err)
+		assert.Contains(t, output, "  Recognition: 50% (1 of 2 quiz answers)")
+		assert.Equal(t, 1, strings.Count(output, "Recognition:"), "only patterns with quiz answers")
 
 		// Check output contains pattern stats
 		assert.Contains(t, output, "hash-map")
@@ -135,6 +252,25 @@ func TestStatsCommand(t *testing.T) {
 		assert.Contains(t, output, "2")     // two-pointers solved
 	})
 
+	t.Run("ContextStats", func(t *testing.T) {
+		restore := mockGetByContext(map[string]stats.ContextStats{
+			interfaces.ContextVim: {Context: interfaces.ContextVim, Attempted: 6, Solved: 5, SuccessRate: 83.3, AvgTime: "00:12:00"},
+			interfaces.ContextTUI: {Context: interfaces.ContextTUI, Attempted: 2, Solved: 0},
+			stats.UnknownContext:  {Context: stats.UnknownContext, Attempted: 1, Solved: 1, SuccessRate: 100, AvgTime: "00:30:00"},
+		}, nil)
+		defer restore()
+
+		output, err := executeCommand(rootCmd, "stats", "contexts")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "vim        6 attempted,   5 solved (83.3%), avg time 00:12:00")
+		assert.Contains(t, output, "tui        2 attempted,   0 solved (0.0%), avg time -")
+		assert.Contains(t, output, "unknown")
+
+		// Contexts are listed in a fixed order
+		assert.Less(t, strings.Index(output, "tui"), strings.Index(output, "vim"))
+		assert.Less(t, strings.Index(output, "vim"), strings.Index(output, "unknown"))
+	})
+
 	t.Run("Trends", func(t *testing.T) {
 		// Create sample trends
 		now := time.Now()
@@ -173,6 +309,7 @@ func TestStatsCommand(t *testing.T) {
 		// Mock GetTrends
 		restore := mockGetTrends(trends, nil)
 		defer restore()
+		defer mockGetPatternTrends(stats.PatternTrends{Weeks: stats.TrendWeeks}, nil)()
 
 		// Execute trends command
 		output, err := executeCommand(rootCmd, "stats", "trends")
@@ -189,21 +326,12 @@ func TestStatsCommand(t *testing.T) {
 	})
 
 	t.Run("Reset", func(t *testing.T) {
-		// We can't easily test the interactive part of reset that requires user input
-		// but we can test the command execution
+		stubProgress(t, nil, false)
 
-		// Mock Reset to return no error
-		restore := mockReset(nil)
-		defer restore()
-
-		// Execute reset command with automatic yes response
-		// Note: In a real test, we would mock the prompt/response
 		output, err := executeCommand(rootCmd, "stats", "reset")
 		assert.NoError(t, err)
-
-		// Since we can't easily mock the prompt response, we can just check
-		// that the command ran without error
-		assert.NotContains(t, output, "Error resetting stats")
+		assert.Contains(t, output, "Are you sure you want to reset all statistics? (y/N): Operation cancelled.")
+		assert.NotContains(t, output, "Backed up")
 	})
 
 	t.Run("GetSummaryError", func(t *testing.T) {
@@ -239,3 +367,216 @@ func TestStatsCommand(t *testing.T) {
 		assert.Contains(t, output, "Error retrieving trend stats") // But output should contain error message
 	})
 }
+
+func TestStatsReset(t *testing.T) {
+	catalog := []problem.Problem{
+		{ID: "two_sum", Patterns: []string{"hash-map"}},
+		{ID: "coin_change", Patterns: []string{"dynamic-programming"}},
+	}
+	// Flags keep their values between runs of the same command
+	resetFlags := func() {
+		resetStatsCmd.Flags().Set("pattern", "")
+		resetStatsCmd.Flags().Set("before", "")
+		resetStatsCmd.Flags().Set("yes", "false")
+	}
+	t.Cleanup(resetFlags)
+
+	t.Run("Selective", func(t *testing.T) {
+		repo := stubProgress(t, catalog, true)
+		ctx := context.Background()
+		old := time.Date(2023, 12, 30, 9, 0, 0, 0, time.Local)
+		recent := time.Now().Add(-time.Hour)
+		for _, s := range []interfaces.SessionStats{
+			{ProblemID: "two_sum", StartTime: old, Mode: "daily", Patterns: []string{"hash-map"}},
+			{ProblemID: "two_sum", StartTime: old.AddDate(0, 0, 1), Mode: "daily", Patterns: []string{"hash-map"}},
+			{ProblemID: "two_sum", StartTime: recent, Mode: "daily", Patterns: []string{"hash-map"}},
+			{ProblemID: "coin_change", StartTime: recent, Mode: "daily", Patterns: []string{"dynamic-programming"}},
+			// A problem no longer in the catalog
+			{ProblemID: "house_robber", StartTime: recent, Patterns: []string{"dynamic-programming"}},
+		} {
+			require.NoError(t, repo.SaveSession(ctx, s))
+		}
+		require.NoError(t, repo.SaveStreak(ctx, storage.Streak{Name: storage.DailyStreak, Current: 7, Longest: 7}))
+
+		resetFlags()
+		output, err := executeCommand(rootCmd, "stats", "reset", "--pattern", "dp")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Are you sure you want to reset statistics for the dp pattern? (y/N): ")
+		assert.Contains(t, output, "Deleted 2 session(s), 0 test run(s)")
+		assert.Contains(t, output, "Statistics have been reset.")
+		backups, _ := filepath.Glob(filepath.Join(filepath.Dir(storage.DBPath()), "backups", "progress-*.db"))
+		assert.Len(t, backups, 1)
+
+		resetFlags()
+		output, err = executeCommand(rootCmd, "stats", "reset", "--before", "2024-01-01", "--yes")
+		assert.NoError(t, err)
+		assert.NotContains(t, output, "Are you sure")
+		assert.Contains(t, output, "Deleted 2 session(s)")
+		assert.Contains(t, output, "Daily streak recounted: 1 day(s), longest 1.")
+
+		sessions, err := repo.LoadAllSessions(ctx)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.True(t, sessions[0].StartTime.Equal(recent))
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		stubProgress(t, catalog, true)
+
+		resetFlags()
+		output, err := executeCommand(rootCmd, "stats", "reset", "--before", "01/01/2024")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "use YYYY-MM-DD")
+
+		resetFlags()
+		output, err = executeCommand(rootCmd, "stats", "reset", "--pattern", "backtracking")
+		assert.NoError(t, err)
+		assert.Contains(t, output, `No problems found for pattern "backtracking"`)
+		assert.NotContains(t, output, "Backed up")
+		_, err = os.Stat(filepath.Join(filepath.Dir(storage.DBPath()), "backups"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestStatsNotebook(t *testing.T) {
+	repo := stubProgress(t, nil, true)
+	defer mockHintReport(hints.Report{Policy: config.HintPolicy{}, Hints: 3}, nil)()
+	defer mockComplexity([]storage.Complexity{{Verdict: "optimal"}, {Verdict: "slower"}}, nil)()
+	t.Cleanup(func() { statsNotebookCmd.Flags().Set("out", "analytics.html") })
+
+	ctx := context.Background()
+	now := time.Now()
+	for _, s := range []interfaces.SessionStats{
+		{ProblemID: "two_sum", StartTime: now.Add(-2 * time.Hour), Duration: 10 * time.Minute, Solved: true, Patterns: []string{"hash-map"}, Difficulty: "easy"},
+		{ProblemID: "coin_change", StartTime: now.Add(-time.Hour), Duration: 30 * time.Minute, Patterns: []string{"dynamic-programming"}, Difficulty: "medium"},
+	} {
+		require.NoError(t, repo.SaveSession(ctx, s))
+	}
+
+	out := filepath.Join(t.TempDir(), "report.html")
+	output, err := executeCommand(rootCmd, "stats", "notebook", "--out", out)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Wrote statistics from 2 session(s) to "+out)
+
+	page, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "dynamic-programming")
+	assert.Contains(t, string(page), "1 of 2 analyzed solutions match the reference")
+	assert.NotContains(t, string(page), "http", "the page loads nothing from the network")
+}
+
+func TestMatchesPattern(t *testing.T) {
+	assert.True(t, matchesPattern("dynamic-programming", "dp"))
+	assert.True(t, matchesPattern("dynamic-programming", "Dynamic-Programming"))
+	assert.True(t, matchesPattern("bfs", "BFS"))
+	assert.False(t, matchesPattern("bfs", "b"))
+	assert.False(t, matchesPattern("two-pointers", "dp"))
+}
+
+func TestStatsCalendar(t *testing.T) {
+	original := stats.GetCalendar
+	t.Cleanup(func() { stats.GetCalendar = original })
+	now := time.Now()
+	stats.GetCalendar = func() (stats.Calendar, error) {
+		return stats.BuildCalendar([]interfaces.SessionStats{{ProblemID: "two_sum", StartTime: now}}, now), nil
+	}
+	var rotation config.DailyRotation
+	originalRotation := daily.LoadRotation
+	t.Cleanup(func() { daily.LoadRotation = originalRotation })
+	daily.LoadRotation = func() config.DailyRotation { return rotation }
+
+	output, err := executeCommand(rootCmd, "stats", "calendar")
+	require.NoError(t, err)
+	assert.Contains(t, output, "1 problem practiced on 1 day in the past year")
+	assert.Contains(t, output, "Less")
+	assert.NotContains(t, output, "Weekly rotation")
+
+	// A weekly rotation is listed under the calendar
+	rotation = config.DailyRotation{PatternsPerWeek: 2, Patterns: []string{"dfs", "bfs"}}
+	output, err = executeCommand(rootCmd, "stats", "calendar")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Weekly rotation:\n  This week, from ")
+	assert.Contains(t, output, ": dfs, bfs\n")
+
+	output, code := executeJSONCommand(t, "stats", "calendar")
+	require.Equal(t, 0, code)
+	assert.Contains(t, output, `"total":1`)
+	assert.Contains(t, output, `"active_days":1`)
+	assert.Contains(t, output, `"rotation":[{"start":`)
+
+	rotation = config.DailyRotation{}
+	output, code = executeJSONCommand(t, "stats", "calendar")
+	require.Equal(t, 0, code)
+	assert.NotContains(t, output, `"rotation"`)
+}
+
+func TestStatsPatternTrends(t *testing.T) {
+	defer mockGetTrends(&stats.Trends{}, nil)()
+	now := time.Now()
+	session := func(pattern string, solved, hinted bool, minutes int) interfaces.SessionStats {
+		return interfaces.SessionStats{
+			Patterns:  []string{pattern},
+			StartTime: now,
+			Solved:    solved,
+			HintsUsed: hinted,
+			Duration:  time.Duration(minutes) * time.Minute,
+		}
+	}
+	sessions := []interfaces.SessionStats{
+		session("dynamic-programming", false, true, 0),
+		session("dynamic-programming", true, true, 40),
+		session("dynamic-programming", false, false, 0),
+		session("hash-map", true, false, 10),
+		session("hash-map", true, false, 10),
+		session("dfs", true, false, 15),
+	}
+	var known []string
+	original := stats.GetPatternTrends
+	t.Cleanup(func() { stats.GetPatternTrends = original })
+	stats.GetPatternTrends = func(patterns []string) (stats.PatternTrends, error) {
+		known = patterns
+		return stats.BuildPatternTrends(sessions, patterns, now), nil
+	}
+
+	output, err := executeCommand(rootCmd, "stats", "trends")
+	require.NoError(t, err)
+	assert.Len(t, known, len(daily.Scales), "every scale's pattern is considered")
+	assert.Contains(t, output, "Pattern Trends (last 8 weeks):")
+	assert.Regexp(t, `dynamic-programming\s+3\s+67%\s+67%`, output)
+	assert.Regexp(t, `dfs\s+1 .* too few tries`, output)
+	assert.Less(t, strings.Index(output, "dynamic-programming"), strings.Index(output, "hash-map"), "weakest first")
+	assert.Contains(t, output, "Weakest patterns:\n  dynamic-programming: fails 67% of attempts, needs hints on 67%")
+	assert.Contains(t, output, "Practice next: Db Major (dynamic-programming), your weakest pattern")
+	assert.Contains(t, output, "algo-scales start practice --pattern dynamic-programming")
+
+	// Without weak patterns a scale not practiced lately is recommended
+	sessions = sessions[3:]
+	output, err = executeCommand(rootCmd, "stats", "trends")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "Weakest patterns")
+	assert.Contains(t, output, "Practice next: C Major (sliding-window), not practiced in the last 8 weeks")
+
+	stats.GetPatternTrends = func([]string) (stats.PatternTrends, error) {
+		return stats.PatternTrends{}, errors.New("trends error")
+	}
+	output, err = executeCommand(rootCmd, "stats", "trends")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Error retrieving pattern trends")
+	stats.GetPatternTrends = func(patterns []string) (stats.PatternTrends, error) {
+		return stats.BuildPatternTrends(sessions, patterns, now), nil
+	}
+
+	output, code := executeJSONCommand(t, "stats", "trends")
+	require.Equal(t, 0, code)
+	var resp struct {
+		Weeks       int                  `json:"weeks"`
+		Patterns    []stats.PatternTrend `json:"patterns"`
+		Recommended string               `json:"recommended"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &resp))
+	assert.Equal(t, stats.TrendWeeks, resp.Weeks)
+	assert.Equal(t, "sliding-window", resp.Recommended)
+	require.NotEmpty(t, resp.Patterns)
+	assert.Equal(t, "hash-map", resp.Patterns[0].Pattern)
+	assert.Len(t, resp.Patterns[0].Weeks, stats.TrendWeeks)
+}
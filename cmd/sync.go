@@ -0,0 +1,299 @@
+// Sync command for merging problem set updates into the local repository
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/cloudsync"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download the problems that changed since your last sync",
+	Long: `Fetches only the problems added, changed or removed since the problem
+set version you have, and merges them into your local problems.
+
+Problems you have edited locally are kept, and the server's copy of each
+is saved in ~/.algo-scales/sync-conflicts. In a terminal you are then asked
+how to resolve each conflict; 'algo-scales sync resolve' asks again later.
+Run with --overwrite to replace your edits instead.
+
+Run 'algo-scales sync now' to sync your practice progress with your other
+machines.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		result, err := api.SyncProblems(ctx, api.SyncOptions{Overwrite: overwrite})
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error syncing problems: %v\n", err)
+			return
+		}
+		writeSyncResult(cmd.OutOrStdout(), result)
+
+		if len(result.Conflicts) > 0 && !overwrite && isTerminal() {
+			fmt.Fprintln(cmd.OutOrStdout())
+			resolveConflicts(ctx, cmd, result.Conflicts, "")
+		}
+	},
+}
+
+// writeSyncResult summarizes what a sync changed
+func writeSyncResult(w io.Writer, result *api.SyncResult) {
+	if result.UpToDate {
+		fmt.Fprintf(w, "Problems are up to date (version %s).\n", result.Version)
+		return
+	}
+
+	fmt.Fprintf(w, "Synced to version %s.\n", result.Version)
+	for _, group := range []struct {
+		label string
+		ids   []string
+	}{
+		{"Added", result.Added},
+		{"Updated", result.Updated},
+		{"Removed", result.Removed},
+	} {
+		if len(group.ids) > 0 {
+			fmt.Fprintf(w, "  %-8s %d: %s\n", group.label, len(group.ids), strings.Join(group.ids, ", "))
+		}
+	}
+	if len(result.Added)+len(result.Updated)+len(result.Removed) == 0 {
+		fmt.Fprintln(w, "  No problems changed.")
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(w, "\n%d invalid problem(s) skipped:\n", len(result.Skipped))
+		for _, skipped := range result.Skipped {
+			fmt.Fprintf(w, "  %s\n", skipped)
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(w, "\nKept your local changes to %d problem(s): %s\n", len(result.Conflicts), strings.Join(result.Conflicts, ", "))
+		fmt.Fprintf(w, "The server's versions are in %s.\n", result.ConflictDir)
+		fmt.Fprintln(w, "Run 'algo-scales sync resolve' to choose between them, or 'algo-scales sync --overwrite' to replace your changes.")
+	}
+}
+
+// syncResolveCmd resolves the conflicts a sync left
+var syncResolveCmd = &cobra.Command{
+	Use:   "resolve [problem-id...]",
+	Short: "Choose between your edits and the server's for conflicting problems",
+	Long: `Resolves the conflicts left when a sync changed or removed problems you
+had edited. For each conflict, you are shown how the server's copy differs
+and asked whether to:
+
+  keep   your copy, discarding the server's
+  take   the server's copy, replacing yours
+  merge  your additions onto the server's copy
+
+Merging takes the server's copy and keeps the test cases you added as your
+own tests (see 'algo-scales test list'), and the constraints, examples and
+wording you added as notes shown with the problem. Your notes are kept apart
+from the problem, so later syncs leave them alone. Whenever your copy is
+replaced, it is first backed up in ~/.algo-scales/sync-conflicts/backups.
+
+Without problem IDs every conflict is resolved. Use --use to resolve them
+all the same way without being asked.
+
+Examples:
+  algo-scales sync resolve
+  algo-scales sync resolve two_sum --use merge`,
+	Run: func(cmd *cobra.Command, args []string) {
+		use, _ := cmd.Flags().GetString("use")
+		resolution := api.Resolution(use)
+		switch resolution {
+		case "", api.KeepLocal, api.TakeRemote, api.MergeOverlays:
+		default:
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: --use must be %s, %s or %s\n", api.KeepLocal, api.TakeRemote, api.MergeOverlays)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		resolveConflicts(ctx, cmd, args, resolution)
+	},
+}
+
+// resolveConflicts resolves the conflicts over the given problems, or all of
+// them, asking the user how unless a resolution is given
+func resolveConflicts(ctx context.Context, cmd *cobra.Command, ids []string, resolution api.Resolution) {
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+
+	conflicts, err := api.ListConflicts()
+	if err != nil {
+		fmt.Fprintf(errOut, "Error reading sync conflicts: %v\n", err)
+		return
+	}
+	if len(ids) > 0 {
+		wanted := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		var selected []api.Conflict
+		for _, c := range conflicts {
+			if wanted[c.ProblemID] {
+				selected = append(selected, c)
+				delete(wanted, c.ProblemID)
+			}
+		}
+		for _, id := range ids {
+			if wanted[id] {
+				fmt.Fprintf(errOut, "No sync conflict for %s.\n", id)
+			}
+		}
+		conflicts = selected
+	}
+	if len(conflicts) == 0 {
+		fmt.Fprintln(out, "No sync conflicts to resolve.")
+		return
+	}
+
+	in := bufio.NewReader(cmd.InOrStdin())
+	for _, c := range conflicts {
+		choice := resolution
+		if choice == "" {
+			choice = promptResolution(in, out, c)
+			if choice == "" {
+				fmt.Fprintf(out, "Left %s unresolved.\n\n", c.ProblemID)
+				continue
+			}
+		}
+
+		result, err := api.ResolveConflict(ctx, c.ProblemID, choice)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error resolving %s: %v\n", c.ProblemID, err)
+			continue
+		}
+		writeResolveResult(out, c, choice, result)
+	}
+}
+
+// promptResolution shows a conflict and asks how to resolve it, returning
+// "" if the user skips it
+func promptResolution(in *bufio.Reader, out io.Writer, c api.Conflict) api.Resolution {
+	fmt.Fprintf(out, "%s, the server's copy compared with yours:\n", c.ProblemID)
+	differences := c.Differences()
+	if len(differences) == 0 {
+		differences = []string{"only minor differences"}
+	}
+	for _, difference := range differences {
+		fmt.Fprintf(out, "  %s\n", difference)
+	}
+	tests, notes := c.Additions()
+	if len(tests)+len(notes) > 0 {
+		fmt.Fprintf(out, "Your copy adds %d test(s) and %d other addition(s) to it.\n", len(tests), len(notes))
+	}
+
+	prompt := "[k]eep yours, [t]ake the server's, [m]erge your additions onto the server's, or [s]kip? "
+	if c.Removed {
+		prompt = "[k]eep yours, [t]ake the removal, or [s]kip? "
+	}
+	for {
+		fmt.Fprint(out, prompt)
+		line, err := in.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "k", "keep":
+			return api.KeepLocal
+		case "t", "take":
+			return api.TakeRemote
+		case "m", "merge":
+			if !c.Removed {
+				return api.MergeOverlays
+			}
+		case "s", "skip":
+			return ""
+		}
+		if err != nil {
+			fmt.Fprintln(out)
+			return ""
+		}
+	}
+}
+
+// writeResolveResult summarizes how a conflict was resolved
+func writeResolveResult(w io.Writer, c api.Conflict, resolution api.Resolution, result api.ResolveResult) {
+	switch {
+	case resolution == api.KeepLocal:
+		fmt.Fprintf(w, "Kept your copy of %s.\n", c.ProblemID)
+	case c.Removed:
+		fmt.Fprintf(w, "Removed %s.\n", c.ProblemID)
+	case resolution == api.MergeOverlays:
+		fmt.Fprintf(w, "Took the server's copy of %s, keeping %d test(s) as your own tests and %d note(s).\n", c.ProblemID, result.TestsAdded, result.NotesAdded)
+	default:
+		fmt.Fprintf(w, "Took the server's copy of %s.\n", c.ProblemID)
+	}
+	if result.Backup != "" {
+		fmt.Fprintf(w, "  Your copy was backed up to %s\n", result.Backup)
+	}
+	fmt.Fprintln(w)
+}
+
+// syncNowCmd syncs practice progress between machines
+var syncNowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Sync your practice progress with your other machines",
+	Long: `Sends your sessions, streaks and daily scale progress to the server and
+brings in the progress your other machines sent. When both machines changed
+the same record, the most recent change wins.
+
+Progress sync is opt-in. Once enabled with "cloudSync": true in
+~/.algo-scales/config.json, progress is also synced automatically after
+commands that change it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading config: %v\n", err)
+			return
+		}
+		if !cfg.CloudSync {
+			fmt.Fprintln(cmd.OutOrStdout(), "Progress sync is off.")
+			fmt.Fprintln(cmd.OutOrStdout(), `Set "cloudSync": true in ~/.algo-scales/config.json to sync progress between machines.`)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		result, err := cloudsync.Sync(ctx)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error syncing progress: %v\n", err)
+			return
+		}
+		writeProgressSyncResult(cmd.OutOrStdout(), result)
+	},
+}
+
+// writeProgressSyncResult summarizes a progress sync
+func writeProgressSyncResult(w io.Writer, result cloudsync.Result) {
+	fmt.Fprintf(w, "Sent %d progress record(s) to the server.\n", result.Pushed)
+	if result.Pulled == 0 {
+		fmt.Fprintln(w, "No newer progress from your other machines.")
+		return
+	}
+	fmt.Fprintf(w, "Brought in %d newer record(s) from your other machines.\n", result.Pulled)
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncNowCmd)
+	syncCmd.AddCommand(syncResolveCmd)
+
+	syncCmd.Flags().Bool("overwrite", false, "Replace locally modified problems with the server's versions")
+	syncResolveCmd.Flags().String("use", "", "Resolve every conflict this way without asking: local, remote or merge")
+}
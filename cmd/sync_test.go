@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/cloudsync"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSyncResult(t *testing.T) {
+	var out bytes.Buffer
+	writeSyncResult(&out, &api.SyncResult{Version: "1.0.0", UpToDate: true})
+	assert.Equal(t, "Problems are up to date (version 1.0.0).\n", out.String())
+
+	out.Reset()
+	writeSyncResult(&out, &api.SyncResult{
+		Version:     "1.1.0",
+		Added:       []string{"three-sum"},
+		Updated:     []string{"max-subarray", "jump-game"},
+		Conflicts:   []string{"two-sum"},
+		ConflictDir: "/tmp/sync-conflicts",
+	})
+	assert.Contains(t, out.String(), "Synced to version 1.1.0.")
+	assert.Contains(t, out.String(), "Added    1: three-sum")
+	assert.Contains(t, out.String(), "Updated  2: max-subarray, jump-game")
+	assert.NotContains(t, out.String(), "Removed")
+	assert.Contains(t, out.String(), "Kept your local changes to 1 problem(s): two-sum")
+	assert.Contains(t, out.String(), "/tmp/sync-conflicts")
+	assert.Contains(t, out.String(), "algo-scales sync resolve")
+}
+
+func TestPromptResolution(t *testing.T) {
+	local := problem.Problem{ID: "two_sum", Title: "Two Sum", TestCases: []problem.TestCase{{Input: "[3,3], 6", Expected: "[0,1]"}}}
+	remote := problem.Problem{ID: "two_sum", Title: "Two Sum II"}
+	changed := api.Conflict{ProblemID: "two_sum", Local: &local, Remote: &remote}
+
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("x\nm\n"))
+	assert.Equal(t, api.MergeOverlays, promptResolution(in, &out, changed))
+	assert.Contains(t, out.String(), `renamed to "Two Sum II"`)
+	assert.Contains(t, out.String(), "Your copy adds 1 test(s)")
+	assert.Equal(t, 2, strings.Count(out.String(), "[m]erge"), "asks again after an unknown answer")
+
+	// Removed problems cannot be merged, and EOF skips
+	removed := api.Conflict{ProblemID: "jump_game", Removed: true, Local: &local}
+	out.Reset()
+	in = bufio.NewReader(strings.NewReader("merge\n"))
+	assert.Equal(t, api.Resolution(""), promptResolution(in, &out, removed))
+	assert.Contains(t, out.String(), "removed from the problem set")
+	assert.Contains(t, out.String(), "[t]ake the removal")
+}
+
+func TestSyncResolveCommand(t *testing.T) {
+	originalList, originalResolve := api.ListConflicts, api.ResolveConflict
+	t.Cleanup(func() {
+		api.ListConflicts, api.ResolveConflict = originalList, originalResolve
+		syncResolveCmd.Flags().Set("use", "")
+	})
+	api.ListConflicts = func() ([]api.Conflict, error) {
+		return []api.Conflict{{ProblemID: "jump_game", Removed: true}, {ProblemID: "two_sum"}}, nil
+	}
+	resolved := map[string]api.Resolution{}
+	api.ResolveConflict = func(ctx context.Context, id string, resolution api.Resolution) (api.ResolveResult, error) {
+		resolved[id] = resolution
+		return api.ResolveResult{TestsAdded: 2, NotesAdded: 1, Backup: "/tmp/backups/" + id + ".json"}, nil
+	}
+
+	output, err := executeCommand(rootCmd, "sync", "resolve", "two_sum", "three_sum", "--use", "merge")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]api.Resolution{"two_sum": api.MergeOverlays}, resolved)
+	assert.Contains(t, output, "No sync conflict for three_sum.")
+	assert.Contains(t, output, "keeping 2 test(s) as your own tests and 1 note(s)")
+	assert.Contains(t, output, "/tmp/backups/two_sum.json")
+
+	output, err = executeCommand(rootCmd, "sync", "resolve", "--use", "theirs")
+	require.NoError(t, err)
+	assert.Contains(t, output, "--use must be")
+}
+
+func TestWriteProgressSyncResult(t *testing.T) {
+	var out bytes.Buffer
+	writeProgressSyncResult(&out, cloudsync.Result{Pushed: 12})
+	assert.Equal(t, "Sent 12 progress record(s) to the server.\nNo newer progress from your other machines.\n", out.String())
+
+	out.Reset()
+	writeProgressSyncResult(&out, cloudsync.Result{Pushed: 12, Pulled: 3})
+	assert.Contains(t, out.String(), "Brought in 3 newer record(s)")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/lancekrogers/algo-scales/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +11,7 @@ var tuiCmd = &cobra.Command{
 	Long: `Start Algo Scales with a full-featured terminal UI that provides
 language selection, timer configuration, and split-screen problem solving.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return ui.StartTUI()
+		return startTUI()
 	},
 }
 
@@ -0,0 +1,79 @@
+//go:build !lite
+
+// Terminal UI launchers, which lite builds leave out
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/lancekrogers/algo-scales/internal/ui"
+	"github.com/lancekrogers/algo-scales/internal/ui/authoring"
+	"github.com/lancekrogers/algo-scales/internal/ui/finder"
+	"github.com/lancekrogers/algo-scales/internal/ui/privacy"
+	"github.com/lancekrogers/algo-scales/internal/ui/replay"
+	"github.com/lancekrogers/algo-scales/internal/ui/splitscreen"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme/picker"
+)
+
+// startTUI runs the full-screen terminal UI
+func startTUI() error {
+	return ui.StartTUI()
+}
+
+// startSplitScreen runs the split-screen terminal UI. Its panels sit side
+// by side, which screen readers cannot follow, so accessible mode runs the
+// single-column TUI instead.
+func startSplitScreen() error {
+	if access.Enabled() {
+		return ui.StartTUI()
+	}
+	return splitscreen.StartUI(nil)
+}
+
+// startCram works through a cram session in the terminal UI
+func startCram(c *cram.Session) error {
+	return ui.StartCram(c)
+}
+
+// resumeTUI reopens a session saved in the split-screen UI
+func resumeTUI(state resume.State) error {
+	return splitscreen.Resume(state)
+}
+
+// runReplay steps through a recorded session
+func runReplay(t timeline.Timeline) error {
+	return replay.Run(t)
+}
+
+// runAuthoring opens a problem file in the interactive authoring screen
+func runAuthoring(path string) error {
+	return authoring.Run(path)
+}
+
+// runAuthorPreview shows a problem as solvers will see it, with the issues
+// lint found in it
+func runAuthorPreview(p problem.Problem, issues []string) error {
+	return authoring.Preview(p, issues)
+}
+
+// runThemePicker lets the user choose a theme preset, starting at current
+func runThemePicker(current string) (string, error) {
+	return picker.Run(current)
+}
+
+// runFinder lets the user pick one of problems by typing parts of its name,
+// starting from query
+func runFinder(problems []problem.Problem, query string) (string, error) {
+	return finder.Run(problems, query)
+}
+
+// setBlankAfter sets how long the TUI may sit idle before it blanks
+func setBlankAfter(d time.Duration) {
+	privacy.BlankAfter = d
+}
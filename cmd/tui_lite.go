@@ -0,0 +1,53 @@
+//go:build lite
+
+// Terminal UI stand-ins for lite builds, which report the TUI as unavailable
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+)
+
+func startTUI() error {
+	return features.Require(features.TUI)
+}
+
+func startSplitScreen() error {
+	return features.Require(features.TUI)
+}
+
+func startCram(c *cram.Session) error {
+	return features.Require(features.TUI)
+}
+
+func resumeTUI(state resume.State) error {
+	return features.Require(features.TUI)
+}
+
+func runReplay(t timeline.Timeline) error {
+	return features.Require(features.TUI)
+}
+
+func runAuthoring(path string) error {
+	return features.Require(features.TUI)
+}
+
+func runAuthorPreview(p problem.Problem, issues []string) error {
+	return features.Require(features.TUI)
+}
+
+func runThemePicker(current string) (string, error) {
+	return "", features.Require(features.TUI)
+}
+
+func runFinder(problems []problem.Problem, query string) (string, error) {
+	return "", features.Require(features.TUI)
+}
+
+func setBlankAfter(d time.Duration) {}
@@ -0,0 +1,170 @@
+// Commands for managing custom test cases
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
+	"github.com/spf13/cobra"
+)
+
+// activeProblem returns the problem in progress in daily practice, which
+// the user test commands work on when no --problem is given
+// Exported as variable for testing
+var activeProblem = func() (string, error) {
+	dailySession, err := daily.LoadSession()
+	if err != nil {
+		return "", fmt.Errorf("no daily session: %v", err)
+	}
+	for _, prob := range dailySession.Problems {
+		if prob.State == daily.StateInProgress {
+			return prob.ProblemID, nil
+		}
+	}
+	return "", fmt.Errorf("no problem is in progress")
+}
+
+// testAddCmd represents the test add command
+var testAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a test case of your own to a problem",
+	Long: `Add a test case of your own to a problem. User tests are kept per
+problem and run with the example tests on every test run and submission,
+in the TUI, the CLI, vim mode and daily practice. Results label them "user".
+
+Input and expected output are written the way the problem's own tests are.
+Without --problem, the test is added to the problem in progress in daily
+practice. In a TUI session, press 'a' to add a test instead.
+
+Examples:
+  algo-scales test add --input "[3,3], 6" --expected "[0,1]"
+  algo-scales test add --problem two_sum --input "[-1,-2,-3], -5" --expected "[1,2]"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		expected, _ := cmd.Flags().GetString("expected")
+		out := cmd.OutOrStdout()
+
+		problemID, ok := userTestProblem(cmd)
+		if !ok {
+			return
+		}
+
+		suite := usertests.Open()
+		defer suite.Close()
+		ctx := context.Background()
+		test, err := suite.Add(ctx, problemID, input, expected)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error adding test: %v\n", err)
+			return
+		}
+		tests, _ := suite.List(ctx, problemID)
+		fmt.Fprintf(out, "Added user test %d to %s: %s\n", len(tests), problemID, describeUserTest(test))
+		fmt.Fprintln(out, "It runs with the example tests on every test run.")
+	},
+}
+
+// testListCmd represents the test list command
+var testListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the test cases you added to a problem",
+	Long: `List the test cases you added to a problem, numbered for
+'algo-scales test remove'. Without --problem, lists the tests of the problem
+in progress in daily practice.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+		problemID, ok := userTestProblem(cmd)
+		if !ok {
+			return
+		}
+
+		suite := usertests.Open()
+		defer suite.Close()
+		tests, err := suite.List(context.Background(), problemID)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading tests: %v\n", err)
+			return
+		}
+		if len(tests) == 0 {
+			fmt.Fprintf(out, "No user tests for %s. Add one with 'algo-scales test add --input ... --expected ...'.\n", problemID)
+			return
+		}
+		fmt.Fprintf(out, "User tests for %s:\n", problemID)
+		for i, test := range tests {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, describeUserTest(test))
+		}
+	},
+}
+
+// testRemoveCmd represents the test remove command
+var testRemoveCmd = &cobra.Command{
+	Use:   "remove <number>",
+	Short: "Remove a test case you added to a problem",
+	Long: `Remove a test case you added to a problem, by its number in
+'algo-scales test list'. Without --problem, removes from the problem in
+progress in daily practice.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		number, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Invalid test number %q\n", args[0])
+			return
+		}
+		problemID, ok := userTestProblem(cmd)
+		if !ok {
+			return
+		}
+
+		suite := usertests.Open()
+		defer suite.Close()
+		test, err := suite.Remove(context.Background(), problemID, number)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error removing test: %v\n", err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed user test %d from %s: %s\n", number, problemID, describeUserTest(test))
+	},
+}
+
+// userTestProblem returns the problem named by --problem, or the one in
+// progress, reporting on stderr if there is none or it does not exist
+func userTestProblem(cmd *cobra.Command) (string, bool) {
+	problemID, _ := cmd.Flags().GetString("problem")
+	if problemID == "" {
+		active, err := activeProblem()
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "No problem given and %v. Use --problem <id>.\n", err)
+			return "", false
+		}
+		problemID = active
+	}
+	if _, err := problem.GetByID(problemID); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Unknown problem %q: %v\n", problemID, err)
+		return "", false
+	}
+	return problemID, true
+}
+
+// describeUserTest writes a user test the way authoring drafts do
+func describeUserTest(test storage.UserTest) string {
+	return test.Input + " => " + test.Expected
+}
+
+func init() {
+	testCmd.AddCommand(testAddCmd)
+	testCmd.AddCommand(testListCmd)
+	testCmd.AddCommand(testRemoveCmd)
+
+	for _, c := range []*cobra.Command{testAddCmd, testListCmd, testRemoveCmd} {
+		c.Flags().String("problem", "", "Problem ID (default: the problem in progress in daily practice)")
+	}
+	testAddCmd.Flags().String("input", "", "Test input, e.g. \"[2,7,11,15], 9\"")
+	testAddCmd.Flags().String("expected", "", "Expected output, e.g. \"[0,1]\"")
+	testAddCmd.MarkFlagRequired("input")
+	testAddCmd.MarkFlagRequired("expected")
+}
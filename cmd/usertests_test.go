@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserTestCommands(t *testing.T) {
+	dir := t.TempDir()
+	originalPath, originalGet, originalActive := storage.DBPath, problem.GetByID, activeProblem
+	t.Cleanup(func() {
+		storage.DBPath, problem.GetByID, activeProblem = originalPath, originalGet, originalActive
+		for _, c := range []*cobra.Command{testAddCmd, testListCmd, testRemoveCmd} {
+			c.Flags().Set("problem", "")
+		}
+	})
+	storage.DBPath = func() string { return filepath.Join(dir, storage.DBFileName) }
+	problem.GetByID = func(id string) (*problem.Problem, error) {
+		if id != "two_sum" && id != "coin_change" {
+			return nil, fmt.Errorf("not found")
+		}
+		return &problem.Problem{ID: id}, nil
+	}
+	activeProblem = func() (string, error) { return "two_sum", nil }
+
+	t.Run("Add", func(t *testing.T) {
+		output, err := executeCommand(rootCmd, "test", "add", "--input", "[3,3], 6", "--expected", "[0,1]")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Added user test 1 to two_sum: [3,3], 6 => [0,1]")
+
+		output, err = executeCommand(rootCmd, "test", "add", "--problem", "two_sum", "--input", "[1,2], 3", "--expected", "[0,1]")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Added user test 2 to two_sum")
+
+		output, err = executeCommand(rootCmd, "test", "add", "--problem", "missing", "--input", "1", "--expected", "1")
+		require.NoError(t, err)
+		assert.Contains(t, output, `Unknown problem "missing"`)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testListCmd.Flags().Set("problem", "")
+		output, err := executeCommand(rootCmd, "test", "list")
+		require.NoError(t, err)
+		assert.Contains(t, output, "User tests for two_sum:\n  1. [3,3], 6 => [0,1]\n  2. [1,2], 3 => [0,1]")
+
+		output, err = executeCommand(rootCmd, "test", "list", "--problem", "coin_change")
+		require.NoError(t, err)
+		assert.Contains(t, output, "No user tests for coin_change.")
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		testRemoveCmd.Flags().Set("problem", "")
+		output, err := executeCommand(rootCmd, "test", "remove", "1")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Removed user test 1 from two_sum: [3,3], 6 => [0,1]")
+
+		output, err = executeCommand(rootCmd, "test", "remove", "5")
+		require.NoError(t, err)
+		assert.Contains(t, output, "two_sum has no user test 5 (it has 1)")
+
+		testListCmd.Flags().Set("problem", "")
+		output, err = executeCommand(rootCmd, "test", "list")
+		require.NoError(t, err)
+		assert.Contains(t, output, "  1. [1,2], 3 => [0,1]")
+		assert.NotContains(t, output, "[3,3], 6")
+	})
+
+	t.Run("NoActiveProblem", func(t *testing.T) {
+		activeProblem = func() (string, error) { return "", fmt.Errorf("no problem is in progress") }
+		testListCmd.Flags().Set("problem", "")
+		output, err := executeCommand(rootCmd, "test", "list")
+		require.NoError(t, err)
+		assert.Contains(t, output, "No problem given and no problem is in progress. Use --problem <id>.")
+	})
+}
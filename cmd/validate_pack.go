@@ -0,0 +1,215 @@
+// Validate-pack command for problem authors
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/packcheck"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/spf13/cobra"
+)
+
+// packMismatch is a failing test as 'validate-pack --output json' describes it
+type packMismatch struct {
+	Test     int    `json:"test"`
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Failure  string `json:"failure,omitempty"`
+}
+
+// packCheckResult is one problem and language as 'validate-pack --output
+// json' describes it
+type packCheckResult struct {
+	ProblemID      string         `json:"problem_id"`
+	Language       string         `json:"language,omitempty"`
+	Status         string         `json:"status"`
+	MissingStarter bool           `json:"missing_starter,omitempty"`
+	TotalTests     int            `json:"total_tests"`
+	Mismatches     []packMismatch `json:"mismatches,omitempty"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// packReport is what 'validate-pack --output json' prints
+type packReport struct {
+	Source       string            `json:"source"`
+	Valid        bool              `json:"valid"`
+	Problems     int               `json:"problems"` // Problems without schema errors
+	SchemaErrors []string          `json:"schema_errors"`
+	Results      []packCheckResult `json:"results"`
+}
+
+// validatePackCmd checks a problem pack's reference solutions
+var validatePackCmd = &cobra.Command{
+	Use:   "validate-pack <directory|file|url>",
+	Short: "Check a problem pack's schema and run its reference solutions",
+	Long: `Check a problem pack before importing or publishing it. The pack is read
+like 'algo-scales import' reads it, and schema errors are reported as
+file:line: message. Then every reference solution, in every language the
+pack's problems have code in, is compiled and run against every one of its
+problem's test cases, and each mismatch is reported with its input, the
+expected output and the actual output. Solutions without starter code in
+their language, and starter code without a solution, are reported too.
+
+With --output json, a pack that is not ready exits with status 2, so the
+check can gate a CI build.
+
+Examples:
+  algo-scales validate-pack ./my-problems
+  algo-scales validate-pack ./my-problems --language python`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		language, _ := cmd.Flags().GetString("language")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		pack, issues, err := problem.ReadPack(source)
+		if err != nil {
+			commandError(cmd, "reading pack", err)
+			return
+		}
+
+		report := packReport{Source: source, Problems: len(pack.Problems), SchemaErrors: []string{}, Results: []packCheckResult{}}
+		for _, issue := range issues {
+			report.SchemaErrors = append(report.SchemaErrors, issue.String())
+		}
+
+		out := cmd.OutOrStdout()
+		asJSON := jsonOutput(cmd)
+		if !asJSON {
+			fmt.Fprintf(out, "Checking pack %s\n\n", source)
+			if len(issues) > 0 {
+				fmt.Fprintf(out, "%s Schema errors:\n", symbols.Fail)
+				for _, issue := range report.SchemaErrors {
+					fmt.Fprintf(out, "  %s\n", issue)
+				}
+				fmt.Fprintln(out)
+			}
+		}
+
+		results, err := packcheck.Check(context.Background(), pack, packcheck.Options{Language: language, Timeout: timeout}, func(r packcheck.Result) {
+			if !asJSON {
+				writePackCheckResult(out, r)
+			}
+		})
+		if err != nil {
+			commandError(cmd, "checking pack", err)
+			return
+		}
+
+		report.Valid = len(issues) == 0
+		for _, r := range results {
+			report.Valid = report.Valid && r.OK()
+			report.Results = append(report.Results, toPackCheckResult(r))
+		}
+		if asJSON {
+			writeJSON(cmd, report)
+			if !report.Valid {
+				exit(exitTestsFailed)
+			}
+			return
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, packSummary(results, len(issues)))
+	},
+}
+
+// writePackCheckResult prints one problem and language's outcome, with a
+// line for each failing test
+func writePackCheckResult(out io.Writer, r packcheck.Result) {
+	name := r.ProblemID
+	if r.Language != "" {
+		name = fmt.Sprintf("%s (%s)", r.ProblemID, r.Language)
+	}
+	switch r.Status {
+	case packcheck.StatusPass:
+		fmt.Fprintf(out, "%s %s: passes all %d test(s)\n", symbols.Pass, name, r.TotalTests)
+	case packcheck.StatusFail:
+		fmt.Fprintf(out, "%s %s: fails %d of %d test(s)\n", symbols.Fail, name, len(r.Mismatches), r.TotalTests)
+		for _, m := range r.Mismatches {
+			fmt.Fprintf(out, "    test %d: input %s, expected %s, got %s", m.Test, m.Input, m.Expected, m.Actual)
+			if m.Failure != "" {
+				fmt.Fprintf(out, " (%s)", m.Failure)
+			}
+			fmt.Fprintln(out)
+		}
+	case packcheck.StatusNoSolution:
+		if r.Language == "" {
+			fmt.Fprintf(out, "%s %s: no reference solution or starter code in any language\n", symbols.Fail, name)
+		} else {
+			fmt.Fprintf(out, "%s %s: starter code but no reference solution\n", symbols.Fail, name)
+		}
+	default:
+		fmt.Fprintf(out, "%s %s: could not run tests: %s\n", symbols.Error, name, r.Err)
+	}
+	if r.MissingStarter {
+		fmt.Fprintf(out, "%s %s: no starter code\n", symbols.Warning, name)
+	}
+}
+
+// packSummary totals a pack's check
+func packSummary(results []packcheck.Result, schemaErrors int) string {
+	counts := make(map[packcheck.Status]int)
+	missingStarter := 0
+	problems := make(map[string]bool)
+	for _, r := range results {
+		counts[r.Status]++
+		problems[r.ProblemID] = true
+		if r.MissingStarter {
+			missingStarter++
+		}
+	}
+
+	summary := fmt.Sprintf("Checked %d solution(s) in %d problem(s): %d pass, %d fail",
+		counts[packcheck.StatusPass]+counts[packcheck.StatusFail]+counts[packcheck.StatusError], len(problems),
+		counts[packcheck.StatusPass], counts[packcheck.StatusFail])
+	if n := counts[packcheck.StatusError]; n > 0 {
+		summary += fmt.Sprintf(", %d could not run", n)
+	}
+	if n := counts[packcheck.StatusNoSolution]; n > 0 {
+		summary += fmt.Sprintf(", %d missing a reference solution", n)
+	}
+	if missingStarter > 0 {
+		summary += fmt.Sprintf(", %d missing starter code", missingStarter)
+	}
+	if schemaErrors > 0 {
+		summary += fmt.Sprintf(", %d schema error(s)", schemaErrors)
+	}
+	if schemaErrors == 0 && counts[packcheck.StatusPass] == len(results) && missingStarter == 0 {
+		return summary + "\nThe pack is ready to import."
+	}
+	return summary
+}
+
+// toPackCheckResult converts a result for JSON output
+func toPackCheckResult(r packcheck.Result) packCheckResult {
+	result := packCheckResult{
+		ProblemID:      r.ProblemID,
+		Language:       r.Language,
+		Status:         string(r.Status),
+		MissingStarter: r.MissingStarter,
+		TotalTests:     r.TotalTests,
+		Error:          r.Err,
+	}
+	for _, m := range r.Mismatches {
+		result.Mismatches = append(result.Mismatches, packMismatch{
+			Test:     m.Test,
+			Input:    m.Input,
+			Expected: m.Expected,
+			Actual:   m.Actual,
+			Failure:  string(m.Failure),
+		})
+	}
+	return result
+}
+
+func init() {
+	rootCmd.AddCommand(validatePackCmd)
+
+	validatePackCmd.Flags().StringP("language", "l", "", "Only check solutions in this language")
+	validatePackCmd.Flags().Duration("timeout", packcheck.DefaultTimeout, "Time limit for each solution's test run")
+}
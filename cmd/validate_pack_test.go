@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTwoSumPack writes a pack of two_sum, a copy of it whose Go solution
+// is wrong, and a file with a schema error
+func writeTwoSumPack(t *testing.T) string {
+	t.Helper()
+	twoSum, err := problem.GetByID("two_sum")
+	require.NoError(t, err)
+	broken := *twoSum
+	broken.ID = "two_sum_broken"
+	broken.Solutions = map[string]string{"go": "func twoSum(nums []int, target int) []int {\n\treturn []int{}\n}\n"}
+	broken.StarterCode = nil
+
+	dir := t.TempDir()
+	for _, p := range []problem.Problem{*twoSum, broken} {
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, p.ID+".json"), data, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("id: bad\ntitle: Bad\ndifficulty: extreme\n"), 0644))
+	return dir
+}
+
+func TestValidatePack(t *testing.T) {
+	dir := writeTwoSumPack(t)
+
+	output, err := executeCommand(rootCmd, "validate-pack", dir, "--language=go")
+	require.NoError(t, err)
+	assert.Contains(t, output, symbols.Fail.String()+" Schema errors:")
+	assert.Contains(t, output, `bad.yaml:3: difficulty "extreme" must be easy, medium or hard`)
+	assert.Contains(t, output, symbols.Pass.String()+" two_sum (go): passes all")
+	assert.Contains(t, output, symbols.Fail.String()+" two_sum_broken (go): fails")
+	assert.Contains(t, output, "expected [0,1], got []")
+	assert.Contains(t, output, symbols.Warning.String()+" two_sum_broken (go): no starter code")
+	assert.Contains(t, output, "Checked 2 solution(s) in 2 problem(s): 1 pass, 1 fail, 1 missing starter code")
+	assert.NotContains(t, output, "ready to import")
+
+	output, code := executeJSONCommand(t, "validate-pack", dir, "--language=go")
+	assert.Equal(t, exitTestsFailed, code)
+	var report packReport
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.False(t, report.Valid)
+	assert.Equal(t, 2, report.Problems)
+	assert.NotEmpty(t, report.SchemaErrors)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "pass", report.Results[0].Status)
+	assert.Equal(t, "fail", report.Results[1].Status)
+	assert.True(t, report.Results[1].MissingStarter)
+	assert.NotEmpty(t, report.Results[1].Mismatches)
+}
+
+func TestValidatePackReady(t *testing.T) {
+	dir := writeTwoSumPack(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, "bad.yaml")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "two_sum_broken.json")))
+
+	output, err := executeCommand(rootCmd, "validate-pack", dir, "--language=go")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Checked 1 solution(s) in 1 problem(s): 1 pass, 0 fail\nThe pack is ready to import.")
+
+	output, err = executeCommand(rootCmd, "validate-pack", filepath.Join(dir, "missing"), "--language=go")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Error reading pack:")
+}
@@ -0,0 +1,89 @@
+// Verify-archive command for re-checking accepted solutions
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/archive"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// verifyArchiveCmd represents the verify-archive command
+var verifyArchiveCmd = &cobra.Command{
+	Use:   "verify-archive",
+	Short: "Re-run your accepted solutions against the current tests",
+	Long: `Replay the latest accepted solution to every problem, in every language
+you solved it in, against the problem's current test set. Solutions that no
+longer pass are reported with their failing tests, and flagged when the
+problem's tests changed since you solved it. Useful after syncing new
+problem content.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		problemID, _ := cmd.Flags().GetString("problem")
+		lang, _ := cmd.Flags().GetString("language")
+
+		repo := storage.Default()
+		defer repo.Close()
+
+		out := cmd.OutOrStdout()
+		results, err := archive.Verify(context.Background(), repo, archive.Options{ProblemID: problemID, Language: lang}, func(r archive.Result) {
+			fmt.Fprintln(out, formatVerifyResult(r))
+		})
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error verifying archive: %v\n", err)
+			return
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No accepted solutions to verify yet.")
+			return
+		}
+
+		counts := make(map[archive.Status]int)
+		for _, r := range results {
+			counts[r.Status]++
+		}
+		fmt.Fprintf(out, "\nVerified %d solution(s): %d still pass, %d no longer pass",
+			len(results), counts[archive.StatusPass], counts[archive.StatusFail])
+		if n := counts[archive.StatusMissing]; n > 0 {
+			fmt.Fprintf(out, ", %d problem(s) unavailable", n)
+		}
+		if n := counts[archive.StatusError]; n > 0 {
+			fmt.Fprintf(out, ", %d could not run", n)
+		}
+		fmt.Fprintln(out)
+	},
+}
+
+// formatVerifyResult describes one re-verified solution
+func formatVerifyResult(r archive.Result) string {
+	name := fmt.Sprintf("%s (%s, solved %s)", r.ProblemID, r.Language, r.SolvedAt.Format("2006-01-02"))
+	switch r.Status {
+	case archive.StatusPass:
+		return fmt.Sprintf("%s %s", symbols.Pass, name)
+	case archive.StatusFail:
+		failed := make([]string, len(r.FailedTests))
+		for i, n := range r.FailedTests {
+			failed[i] = fmt.Sprint(n)
+		}
+		line := fmt.Sprintf("%s %s: fails test(s) %s of %d", symbols.Fail, name, strings.Join(failed, ", "), r.TotalTests)
+		if r.Changed {
+			line += " (tests changed since you solved it)"
+		}
+		return line
+	case archive.StatusMissing:
+		return fmt.Sprintf("%s %s: problem no longer available", symbols.Warning, name)
+	default:
+		return fmt.Sprintf("%s %s: could not run tests: %s", symbols.Error, name, r.Err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(verifyArchiveCmd)
+
+	verifyArchiveCmd.Flags().String("problem", "", "Only verify solutions to this problem")
+	verifyArchiveCmd.Flags().StringP("language", "l", "", "Only verify solutions in this language")
+}
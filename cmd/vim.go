@@ -13,9 +13,6 @@ import (
 // Flag to enable vim mode output (JSON format)
 var vimMode bool
 
-// Track hint levels for each problem in the current session
-var hintLevels = make(map[string]int)
-
 // VimProblemResponse represents the JSON response for a problem in vim mode
 type VimProblemResponse struct {
 	ID            string            `json:"id"`
@@ -29,6 +26,8 @@ type VimProblemResponse struct {
 	ScaleDesc     string            `json:"scale_desc,omitempty"` // Musical scale description
 	WorkspacePath string            `json:"workspace_path,omitempty"` // Path to workspace directory
 	SessionID     string            `json:"session_id,omitempty"` // Session identifier
+	Warning       string            `json:"warning,omitempty"` // Set when the problem changed since the last attempt
+	Reason        string            `json:"reason,omitempty"` // Why the difficulty was chosen, for --difficulty auto
 }
 
 // VimTestResponse represents the JSON response for test results in vim mode
@@ -38,16 +37,23 @@ type VimTestResponse struct {
 
 // TestResult represents a single test result
 type TestResult struct {
-	Input    string `json:"input"`
-	Expected string `json:"expected"`
-	Actual   string `json:"actual,omitempty"`
-	Passed   bool   `json:"passed"`
+	Input      string  `json:"input"`
+	Expected   string  `json:"expected"`
+	Actual     string  `json:"actual,omitempty"`
+	Passed     bool    `json:"passed"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+	Slow       bool    `json:"slow,omitempty"`
+	Tier       string  `json:"tier,omitempty"`
 }
 
 // VimSubmitResponse represents the JSON response for a submission in vim mode
 type VimSubmitResponse struct {
-	Passed      bool         `json:"passed"`
-	TestResults []TestResult `json:"test_results"`
+	Passed      bool           `json:"passed"`
+	TestResults []TestResult   `json:"test_results"`
+	Tiers       string         `json:"tiers,omitempty"`      // Passed tests per tier, when a submission ran several
+	Unrun       int            `json:"unrun,omitempty"`      // Tests only a submission runs, after a test run
+	Complexity  *VimComplexity `json:"complexity,omitempty"` // Set when all tests pass
+	Bench       *VimBench      `json:"bench,omitempty"`      // Set when all tests pass and --bench is given
 }
 
 // VimHintResponse represents the JSON response for a hint in vim mode
@@ -57,6 +63,7 @@ type VimHintResponse struct {
 	Walkthrough []string `json:"walkthrough,omitempty"`
 	Solution  string   `json:"solution,omitempty"`
 	Language  string   `json:"language,omitempty"`
+	Notice    string   `json:"notice,omitempty"` // Why the hint policy withheld the solution
 }
 
 // VimSolutionResponse represents the JSON response for a solution in vim mode
@@ -134,6 +141,7 @@ func handleVimModeStart(s *session.Session) error {
 		Difficulty:  s.Problem.Difficulty,
 		Patterns:    s.Problem.Patterns,
 		Language:    s.Options.Language,
+		Warning:     session.CheckProblemChanged(*s.Problem),
 	}
 
 	// Get the description
@@ -5,16 +5,22 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/ai"
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/services"
 	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
 	"github.com/spf13/cobra"
 )
 
@@ -22,109 +28,210 @@ import (
 var submitCmd = &cobra.Command{
 	Use:   "submit",
 	Short: "Submit solution for testing (vim mode)",
-	Long:  `Submit a solution file for testing. Used by the Neovim plugin.`,
+	Long: `Submit a solution file for testing. Used by the Neovim plugin, and by
+other editors with --output json.
+
+A submission runs every tier of the problem's tests: the examples and the
+hidden, edge and stress tests. With --bench, a passing solution is also
+timed against the reference solution on large inputs generated from the
+problem's constraints.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Get flags
-		problemID, _ := cmd.Flags().GetString("problem-id")
-		language, _ := cmd.Flags().GetString("language")
-		filePath, _ := cmd.Flags().GetString("file")
-		isVimMode, _ := cmd.Flags().GetBool("vim-mode")
+		runVimTests(cmd, true)
+	},
+}
 
-		if !isVimMode {
-			fmt.Println("This command is for vim mode only")
-			return
-		}
+// testCmd represents the test command for vim mode
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run tests on solution (vim mode)",
+	Long: `Run the example tests on a solution file. Used by the Neovim plugin, and
+by other editors with --output json.
 
-		// Create context - in production, this should have timeout
-		ctx := context.Background()
+The hidden, edge and stress tests only run on submit. With --bench, a
+passing solution is also timed against the reference solution on large
+inputs generated from the problem's constraints.
 
-		// Read the solution file
-		content, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			outputVimError(fmt.Errorf("failed to read file: %v", err))
-			return
-		}
+Tests you add with 'algo-scales test add' run on every test run and submit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runVimTests(cmd, false)
+	},
+}
 
-		// Get problem from repository
-		problemService := services.DefaultRegistry.GetProblemService()
-		prob, err := problemService.GetByID(ctx, problemID)
-		if err != nil {
-			outputVimError(fmt.Errorf("failed to get problem: %v", err))
-			return
-		}
+// runVimTests runs a solution file's example tests, or all its tests for a
+// submission, and prints the results as JSON. With --output json, a run
+// where not every test passed exits with exitTestsFailed.
+func runVimTests(cmd *cobra.Command, submit bool) {
+	// Get flags
+	problemID, _ := cmd.Flags().GetString("problem-id")
+	language, _ := cmd.Flags().GetString("language")
+	filePath, _ := cmd.Flags().GetString("file")
+	isVimMode, _ := cmd.Flags().GetBool("vim-mode")
+	benchmark, _ := cmd.Flags().GetBool("bench")
+	asJSON := jsonOutput(cmd)
+
+	if !isVimMode && !asJSON {
+		fmt.Println("This command is for vim mode only")
+		return
+	}
 
-		// Get test runner registry
-		registry := execution.NewRunnerRegistry()
-		runner, err := registry.GetRunner(language)
-		if err != nil {
-			outputVimError(fmt.Errorf("unsupported language: %v", err))
-			return
-		}
+	fail := outputVimError
+	if asJSON {
+		fail = func(err error) { failJSON(cmd, err) }
+	}
+	sessionContext := interfaces.ContextVim
+	if !isVimMode {
+		sessionContext = interfaces.ContextCLI
+	}
 
-		// Run tests directly
-		// Convert test cases to interface type
-		var interfaceTestCases []interfaces.TestCase
-		for _, tc := range prob.TestCases {
-			interfaceTestCases = append(interfaceTestCases, interfaces.TestCase{
-				Input:    tc.Input,
-				Expected: tc.Expected,
-			})
-		}
-		
-		interfaceProb := &interfaces.Problem{
-			ID:          prob.ID,
-			Title:       prob.Title,
-			Description: prob.Description,
-			TestCases:   interfaceTestCases,
-		}
-		
-		results, _, err := runner.ExecuteTests(ctx, interfaceProb, string(content), 30*time.Second)
-		if err != nil {
-			outputVimError(fmt.Errorf("failed to run tests: %v", err))
-			return
-		}
+	// Create context - in production, this should have timeout
+	ctx := context.Background()
 
-		// Convert to vim response format
-		var testResults []TestResult
-		allPassed := true
-		for _, result := range results {
-			tr := TestResult{
-				Input:    fmt.Sprintf("%v", result.Input),
-				Expected: fmt.Sprintf("%v", result.Expected),
-				Actual:   fmt.Sprintf("%v", result.Actual),
-				Passed:   result.Passed,
-			}
-			testResults = append(testResults, tr)
-			if !result.Passed {
-				allPassed = false
-			}
-		}
+	// Read the solution file
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		fail(fmt.Errorf("failed to read file: %v", err))
+		return
+	}
 
-		// Create and output response
-		resp := VimSubmitResponse{
-			Passed:      allPassed,
-			TestResults: testResults,
+	// Get problem from repository
+	problemService := services.DefaultRegistry.GetProblemService()
+	prob, err := problemService.GetByID(ctx, problemID)
+	if err != nil {
+		fail(fmt.Errorf("failed to get problem: %v", err))
+		return
+	}
+
+	resp, err := runSolutionTests(ctx, prob, language, string(content), solutionRun{
+		FilePath:  filePath,
+		Submit:    submit,
+		Benchmark: benchmark,
+		Context:   sessionContext,
+	})
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if asJSON {
+		writeJSON(cmd, resp)
+		if !resp.Passed {
+			exit(exitTestsFailed)
 		}
+		return
+	}
 
-		jsonResp, err := json.Marshal(resp)
-		if err != nil {
-			outputVimError(fmt.Errorf("failed to marshal response: %v", err))
-			return
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		outputVimError(fmt.Errorf("failed to marshal response: %v", err))
+		return
+	}
+
+	fmt.Println(string(jsonResp))
+}
+
+// solutionRun describes how runSolutionTests tests a solution
+type solutionRun struct {
+	FilePath  string // Where the solution is saved, if anywhere
+	Submit    bool   // Run every tier of tests and record the submission
+	Benchmark bool   // Time a passing solution against the reference
+	Context   string // Where the session took place, for stats
+}
+
+// runSolutionTests runs a solution's example tests, or all its tests for a
+// submission, counting the run towards the hint policy and recording
+// submissions in stats
+func runSolutionTests(ctx context.Context, prob *problem.Problem, language, code string, run solutionRun) (VimSubmitResponse, error) {
+	results, interfaceProb, err := executeSolution(ctx, prob, language, code, run.Submit)
+	if err != nil {
+		return VimSubmitResponse{}, err
+	}
+
+	testResults, allPassed := newTestResults(results)
+
+	recordTestRun(prob.ID, allPassed)
+	if run.Submit {
+		recordSubmission(prob, run.FilePath, allPassed, run.Context)
+	}
+
+	resp := VimSubmitResponse{
+		Passed:      allPassed,
+		TestResults: testResults,
+		Tiers:       execution.TierSummary(results),
+	}
+	if !run.Submit {
+		resp.Unrun = execution.UnrunTests(interfaceProb)
+	}
+	if allPassed {
+		resp.Complexity = newVimComplexity(assessComplexity(*prob, language, code))
+		if run.Benchmark {
+			resp.Bench = newVimBench(runBench(*prob, language, code))
 		}
+	}
+	return resp, nil
+}
 
-		fmt.Println(string(jsonResp))
-	},
+// newTestResults converts test results to the vim response format, and
+// reports whether they all passed
+func newTestResults(results []interfaces.TestResult) ([]TestResult, bool) {
+	var testResults []TestResult
+	allPassed := true
+	for _, result := range results {
+		tr := TestResult{
+			Input:      fmt.Sprintf("%v", result.Input),
+			Expected:   fmt.Sprintf("%v", result.Expected),
+			Actual:     fmt.Sprintf("%v", result.Actual),
+			Passed:     result.Passed,
+			DurationMs: float64(result.Duration.Microseconds()) / 1000,
+			Slow:       execution.IsSlow(result.Duration),
+			Tier:       string(result.Tier.OrDefault()),
+		}
+		testResults = append(testResults, tr)
+		if !result.Passed {
+			allPassed = false
+		}
+	}
+	return testResults, allPassed
 }
 
-// testCmd represents the test command for vim mode
-var testCmd = &cobra.Command{
-	Use:   "test",
-	Short: "Run tests on solution (vim mode)",
-	Long:  `Run tests on a solution file. Used by the Neovim plugin.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Same implementation as submit for now
-		submitCmd.Run(cmd, args)
-	},
+// executeSolution runs a solution's example and user tests, or every tier
+// of tests for a submission, returning the results and the problem with
+// all its tests
+func executeSolution(ctx context.Context, prob *problem.Problem, language, code string, submit bool) ([]interfaces.TestResult, *interfaces.Problem, error) {
+	// Get test runner registry
+	registry := execution.NewRunnerRegistry()
+	runner, err := registry.GetRunner(language)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unsupported language: %v", err)
+	}
+
+	// Convert test cases to interface type
+	var interfaceTestCases []interfaces.TestCase
+	for _, tc := range prob.TestCases {
+		interfaceTestCases = append(interfaceTestCases, interfaces.TestCase{
+			Input:    tc.Input,
+			Expected: tc.Expected,
+			Tier:     tc.Tier,
+		})
+	}
+
+	interfaceProb := &interfaces.Problem{
+		ID:          prob.ID,
+		Title:       prob.Title,
+		Description: prob.Description,
+		Signature:   prob.Signature,
+		TestCases:   interfaceTestCases,
+	}
+	interfaceProb = usertests.Include(interfaceProb)
+	testProb := interfaceProb
+	if !submit {
+		testProb = execution.ExampleTests(interfaceProb)
+	}
+
+	results, _, err := runner.ExecuteTests(ctx, testProb, code, 30*time.Second)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to run tests: %v", err)
+	}
+	return results, interfaceProb, nil
 }
 
 // hintCmd represents the hint command for vim mode
@@ -143,68 +250,89 @@ var hintCmd = &cobra.Command{
 			return
 		}
 
-		// Create context
-		ctx := context.Background()
+		resp, err := nextVimHint(context.Background(), problemID, language)
+		if err != nil {
+			outputVimError(err)
+			return
+		}
 
-		// Get problem from repository
-		problemService := services.DefaultRegistry.GetProblemService()
-		prob, err := problemService.GetByID(ctx, problemID)
+		jsonResp, err := json.Marshal(resp)
 		if err != nil {
-			outputVimError(fmt.Errorf("failed to get problem: %v", err))
+			outputVimError(fmt.Errorf("failed to marshal response: %v", err))
 			return
 		}
 
-		// Get current hint level for this problem
-		currentLevel := hintLevels[problemID]
-		currentLevel++ // Increment for this request
-		hintLevels[problemID] = currentLevel
+		fmt.Println(string(jsonResp))
+	},
+}
 
-		// Create response with appropriate level of detail
-		resp := VimHintResponse{
-			Level: currentLevel,
-		}
+// nextVimHint returns the next level of hint for a problem, as far as the
+// hint policy allows
+func nextVimHint(ctx context.Context, problemID, language string) (VimHintResponse, error) {
+	// Get problem from repository
+	problemService := services.DefaultRegistry.GetProblemService()
+	prob, err := problemService.GetByID(ctx, problemID)
+	if err != nil {
+		return VimHintResponse{}, fmt.Errorf("failed to get problem: %v", err)
+	}
+	return nextHint(prob, language)
+}
 
-		// Level 1: Pattern explanation
-		if currentLevel >= 1 {
-			if prob.PatternExplanation != "" {
-				resp.Hint = prob.PatternExplanation
-			} else {
-				// Fallback to generic pattern hint
-				resp.Hint = "Think about the pattern: " + getPatternHint(prob.Patterns)
-			}
-		}
+// nextHint returns the next level of hint for a problem, as far as the hint
+// policy allows. The CLI and vim mode share it, and the TUI's hint toggle is
+// the first level.
+func nextHint(prob *problem.Problem, language string) (VimHintResponse, error) {
+	problemID := prob.ID
+
+	// The hint policy keeps the problem's level, so the next hint follows on
+	// from the last one shown in any mode
+	decision := requestHelp(problemID, hints.KindHint)
+	if !decision.Allowed {
+		return VimHintResponse{}, errors.New(decision.Reason)
+	}
+	currentLevel := decision.Level
+
+	// Create response with appropriate level of detail
+	resp := VimHintResponse{
+		Level: currentLevel,
+	}
 
-		// Level 2: Add solution walkthrough
-		if currentLevel >= 2 && len(prob.SolutionWalkthrough) > 0 {
-			resp.Walkthrough = prob.SolutionWalkthrough
+	// Level 1: Pattern explanation
+	if currentLevel >= hints.LevelPattern {
+		if prob.PatternExplanation != "" {
+			resp.Hint = prob.PatternExplanation
+		} else {
+			// Fallback to generic pattern hint
+			resp.Hint = "Think about the pattern: " + getPatternHint(prob.Patterns)
 		}
+	}
+
+	// Level 2: Add solution walkthrough
+	if currentLevel >= hints.LevelWalkthrough && len(prob.SolutionWalkthrough) > 0 {
+		resp.Walkthrough = prob.SolutionWalkthrough
+	}
 
-		// Level 3: Add actual solution code
-		if currentLevel >= 3 {
+	// Level 3: Add actual solution code, if the hint policy allows it
+	if currentLevel >= hints.LevelSolution {
+		if decision := requestHelp(problemID, hints.KindSolution); !decision.Allowed {
+			resp.Notice = decision.Reason
+		} else if prob.Solutions != nil {
 			// Get solution in the requested language
-			if prob.Solutions != nil {
-				if solution, ok := prob.Solutions[language]; ok {
-					resp.Solution = solution
-					resp.Language = language
-				} else {
-					// Try to get any solution
-					for lang, sol := range prob.Solutions {
-						resp.Solution = sol
-						resp.Language = lang
-						break
-					}
+			if solution, ok := prob.Solutions[language]; ok {
+				resp.Solution = solution
+				resp.Language = language
+			} else {
+				// Try to get any solution
+				for lang, sol := range prob.Solutions {
+					resp.Solution = sol
+					resp.Language = lang
+					break
 				}
 			}
 		}
+	}
 
-		jsonResp, err := json.Marshal(resp)
-		if err != nil {
-			outputVimError(fmt.Errorf("failed to marshal response: %v", err))
-			return
-		}
-
-		fmt.Println(string(jsonResp))
-	},
+	return resp, nil
 }
 
 // solutionCmd represents the solution command for vim mode
@@ -234,6 +362,11 @@ var solutionCmd = &cobra.Command{
 			return
 		}
 
+		if decision := requestHelp(problemID, hints.KindSolution); !decision.Allowed {
+			outputVimError(errors.New(decision.Reason))
+			return
+		}
+
 		// Get solution code
 		solutionCode := ""
 		if prob.Solutions != nil {
@@ -278,7 +411,7 @@ var aiHintCmd = &cobra.Command{
 		language, _ := cmd.Flags().GetString("language")
 		userCode, _ := cmd.Flags().GetString("user-code")
 		filePath, _ := cmd.Flags().GetString("file")
-		provider, _ := cmd.Flags().GetString("provider") // "claude" or "ollama"
+		provider, _ := cmd.Flags().GetString("provider") // "claude", "ollama", "openai" or "anthropic"
 		model, _ := cmd.Flags().GetString("model")
 		isVimMode, _ := cmd.Flags().GetBool("vim-mode")
 		chatMode, _ := cmd.Flags().GetBool("chat")
@@ -324,6 +457,10 @@ var aiHintCmd = &cobra.Command{
 				aiProvider = ai.ProviderClaude
 			case "ollama":
 				aiProvider = ai.ProviderOllama
+			case "openai":
+				aiProvider = ai.ProviderOpenAI
+			case "anthropic":
+				aiProvider = ai.ProviderAnthropic
 			default:
 				outputVimError(fmt.Errorf("unsupported provider: %s", provider))
 				return
@@ -334,6 +471,10 @@ var aiHintCmd = &cobra.Command{
 				aiProvider = ai.ProviderClaude
 			case "ollama":
 				aiProvider = ai.ProviderOllama
+			case "openai":
+				aiProvider = ai.ProviderOpenAI
+			case "anthropic":
+				aiProvider = ai.ProviderAnthropic
 			default:
 				outputVimError(fmt.Errorf("no valid default provider configured"))
 				return
@@ -345,6 +486,12 @@ var aiHintCmd = &cobra.Command{
 			if aiProvider == ai.ProviderOllama && aiConfig.Ollama != nil {
 				aiConfig.Ollama.Model = model
 			}
+			if aiProvider == ai.ProviderOpenAI && aiConfig.OpenAI != nil {
+				aiConfig.OpenAI.Model = model
+			}
+			if aiProvider == ai.ProviderAnthropic && aiConfig.Anthropic != nil {
+				aiConfig.Anthropic.Model = model
+			}
 			// Claude uses default model from CLI, no need to override
 		}
 
@@ -355,6 +502,12 @@ var aiHintCmd = &cobra.Command{
 			return
 		}
 
+		// Chat sessions count as a single hint
+		if decision := requestHelp(problemID, hints.KindAIHint); !decision.Allowed {
+			outputVimError(errors.New(decision.Reason))
+			return
+		}
+
 		if chatMode {
 			// Launch interactive chat mode
 			resp := map[string]interface{}{
@@ -415,6 +568,62 @@ var aiHintCmd = &cobra.Command{
 	},
 }
 
+// openHintTracker opens the tracker enforcing the hint policy
+// Exported as variable for testing
+var openHintTracker = hints.Open
+
+// requestHelp asks the hint policy to show help with a problem. Help is
+// allowed if usage cannot be read, rather than locking the user out.
+func requestHelp(problemID string, kind hints.Kind) hints.Decision {
+	tracker := openHintTracker()
+	defer tracker.Close()
+
+	decision, err := tracker.Request(context.Background(), problemID, kind)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to track %s: %v\n", kind, err)
+		return hints.Decision{Allowed: true}
+	}
+	return decision
+}
+
+// recordTestRun counts a test run towards the hint policy
+func recordTestRun(problemID string, passed bool) {
+	tracker := openHintTracker()
+	defer tracker.Close()
+
+	if err := tracker.RecordRun(context.Background(), problemID, passed); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record test run: %v\n", err)
+	}
+}
+
+// recordSubmission records a submission in stats. The session is timed from
+// when 'start' wrote the problem description next to the solution file, so
+// later submissions of the same session replace the earlier ones instead of
+// counting as new attempts. Solutions without a file are timed from now.
+func recordSubmission(prob *problem.Problem, filePath string, solved bool, sessionContext string) {
+	now := time.Now()
+	started := now
+	if filePath != "" {
+		if info, err := os.Stat(filepath.Join(filepath.Dir(filePath), "problem.md")); err == nil && info.ModTime().Before(now) {
+			started = info.ModTime()
+		}
+	}
+
+	err := stats.RecordSession(stats.SessionStats{
+		ProblemID:  prob.ID,
+		StartTime:  started,
+		EndTime:    now,
+		Duration:   now.Sub(started),
+		Solved:     solved,
+		Patterns:   prob.Patterns,
+		Difficulty: prob.Difficulty,
+		Context:    sessionContext,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record session: %v\n", err)
+	}
+}
+
 // Helper function to output vim mode errors
 func outputVimError(err error) {
 	errResp := map[string]string{
@@ -450,6 +659,7 @@ func init() {
 	submitCmd.Flags().String("language", "go", "Programming language")
 	submitCmd.Flags().String("file", "", "Solution file path")
 	submitCmd.Flags().Bool("vim-mode", false, "Enable vim mode output")
+	submitCmd.Flags().Bool("bench", false, "Time a passing solution against the reference on large inputs")
 	submitCmd.MarkFlagRequired("problem-id")
 	submitCmd.MarkFlagRequired("file")
 
@@ -457,6 +667,7 @@ func init() {
 	testCmd.Flags().String("language", "go", "Programming language")
 	testCmd.Flags().String("file", "", "Solution file path")
 	testCmd.Flags().Bool("vim-mode", false, "Enable vim mode output")
+	testCmd.Flags().Bool("bench", false, "Time a passing solution against the reference on large inputs")
 	testCmd.MarkFlagRequired("problem-id")
 	testCmd.MarkFlagRequired("file")
 
@@ -477,7 +688,7 @@ func init() {
 	aiHintCmd.Flags().String("language", "go", "Programming language")
 	aiHintCmd.Flags().String("user-code", "", "User's current solution code")
 	aiHintCmd.Flags().String("file", "", "Path to solution file (alternative to --user-code)")
-	aiHintCmd.Flags().String("provider", "claude", "AI provider (claude or ollama)")
+	aiHintCmd.Flags().String("provider", "claude", "AI provider (claude, ollama, openai or anthropic)")
 	aiHintCmd.Flags().String("model", "", "AI model to use")
 	aiHintCmd.Flags().Bool("vim-mode", false, "Enable vim mode output")
 	aiHintCmd.Flags().Bool("chat", false, "Launch interactive chat mode")
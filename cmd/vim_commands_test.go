@@ -8,13 +8,23 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/hints"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestVimCommands(t *testing.T) {
+	stubHintTracker(t, config.HintPolicy{})
+	stubComplexity(t, complexity.Feedback{Time: complexity.Linear, Space: complexity.Linear, Source: complexity.SourceHeuristic})
+	recorded := stubRecordSession(t)
+
 	// Create a temporary solution file for testing
 	tmpDir := t.TempDir()
 	solutionFile := filepath.Join(tmpDir, "solution.go")
@@ -55,6 +65,13 @@ func TestVimCommands(t *testing.T) {
 				err := json.Unmarshal([]byte(output), &resp)
 				assert.NoError(t, err, "output should be valid JSON")
 				assert.NotNil(t, resp.TestResults, "should have test results")
+				if resp.Passed {
+					require.NotNil(t, resp.Complexity, "a passing submission has complexity feedback")
+					assert.Equal(t, "O(n)", resp.Complexity.Time)
+				}
+				require.Len(t, *recorded, 1, "a submission is recorded as a session")
+				assert.Equal(t, interfaces.ContextVim, (*recorded)[0].Context)
+				assert.Equal(t, resp.Passed, (*recorded)[0].Solved)
 			},
 		},
 		{
@@ -319,9 +336,33 @@ func TestGetPatternHint(t *testing.T) {
 	}
 }
 
+// stubRecordSession collects recorded sessions instead of saving them
+func stubRecordSession(t *testing.T) *[]stats.SessionStats {
+	t.Helper()
+	var recorded []stats.SessionStats
+	original := stats.RecordSession
+	t.Cleanup(func() { stats.RecordSession = original })
+	stats.RecordSession = func(s stats.SessionStats) error {
+		recorded = append(recorded, s)
+		return nil
+	}
+	return &recorded
+}
+
+// stubHintTracker enforces policy on a temporary database for the test
+func stubHintTracker(t *testing.T, policy config.HintPolicy) {
+	t.Helper()
+	original := openHintTracker
+	dbPath := filepath.Join(t.TempDir(), storage.DBFileName)
+	openHintTracker = func() *hints.Tracker {
+		return hints.NewTracker(policy, storage.NewSQLiteStore(dbPath))
+	}
+	t.Cleanup(func() { openHintTracker = original })
+}
+
 func TestMultiLevelHints(t *testing.T) {
-	// Reset hint levels before test
-	hintLevels = make(map[string]int)
+	// Hint levels are kept in the tracker's fresh database
+	stubHintTracker(t, config.HintPolicy{SolutionAfterFailures: 1})
 	
 	// Mock problem service to return a problem with walkthrough
 	// This test verifies that hint levels increase with each call
@@ -385,5 +426,6 @@ func TestMultiLevelHints(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, resp3.Level, "Third call should be level 3")
 	assert.NotEmpty(t, resp3.Hint, "Should have hint text")
-	// Note: solution presence depends on problem data
+	assert.Empty(t, resp3.Solution, "Solution should wait for a failed test run")
+	assert.Contains(t, resp3.Notice, "unlocks after 1 failed test run")
 }
\ No newline at end of file
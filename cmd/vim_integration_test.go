@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/hints"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -120,29 +122,19 @@ func TestVimModeIntegration(t *testing.T) {
 	})
 }
 
-// TestHintLevelPersistence verifies that hint levels persist within a session
-// Note: For CLI, each command is a new session, so levels reset
+// TestHintLevelPersistence verifies that hint levels persist per problem
+// across commands, each of which opens the tracker afresh
 func TestHintLevelPersistence(t *testing.T) {
-	// Within a single process, hint levels should persist
-	
-	// Reset hint levels
-	hintLevels = make(map[string]int)
-	
+	stubHintTracker(t, config.HintPolicy{})
+
 	problemID := "two_sum"
-	
-	// Simulate multiple hint calls within same process
-	for i := 1; i <= 3; i++ {
-		level := hintLevels[problemID]
-		level++
-		hintLevels[problemID] = level
-		
-		assert.Equal(t, i, level, "Hint level should increment")
+	for i := 1; i <= hints.MaxLevel+1; i++ {
+		decision := requestHelp(problemID, hints.KindHint)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, min(i, hints.MaxLevel), decision.Level, "Hint level should increment up to the last level")
 	}
-	
+
 	// Different problem should start at level 1
-	anotherProblem := "three_sum"
-	level := hintLevels[anotherProblem]
-	level++
-	hintLevels[anotherProblem] = level
-	assert.Equal(t, 1, level, "New problem should start at level 1")
-}
\ No newline at end of file
+	decision := requestHelp("three_sum", hints.KindHint)
+	assert.Equal(t, hints.LevelPattern, decision.Level, "New problem should start at level 1")
+}
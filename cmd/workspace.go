@@ -0,0 +1,141 @@
+// Workspace commands for managing practice directories
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// dailyWorkspaceRoot returns the directory holding the daily workspaces
+// Exported as variable for testing
+var dailyWorkspaceRoot = daily.GetDailyWorkspacePath
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage your practice workspaces",
+	Long:  `Manage the directories your practice solutions are written to.`,
+}
+
+// workspaceGCCmd represents the workspace gc command
+var workspaceGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Archive or delete old daily workspaces",
+	Long: `Clean up the daily workspaces, one directory per day of practice, that
+accumulate under ~/Dev/AlgoScalesPractice/Daily.
+
+Workspaces older than --keep-days are zipped into the archive directory
+next to them and removed, or only removed with --delete. With
+--keep-solved, workspaces where you solved a daily problem are kept at any
+age. Use --dry-run to list what would happen without changing anything.
+
+Examples:
+  algo-scales workspace gc --dry-run
+  algo-scales workspace gc --keep-days 90 --keep-solved
+  algo-scales workspace gc --keep-days 30 --delete`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keepDays, _ := cmd.Flags().GetInt("keep-days")
+		keepSolved, _ := cmd.Flags().GetBool("keep-solved")
+		remove, _ := cmd.Flags().GetBool("delete")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		out := cmd.OutOrStdout()
+
+		if keepDays < 0 {
+			fmt.Fprintln(cmd.ErrOrStderr(), "--keep-days cannot be negative")
+			return
+		}
+		policy := daily.GCPolicy{KeepDays: keepDays, KeepSolved: keepSolved, Delete: remove}
+
+		var solved map[string][]string
+		if keepSolved {
+			repo := storage.Default()
+			sessions, err := repo.LoadAllSessions(context.Background())
+			repo.Close()
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error loading your sessions, nothing was cleaned up: %v\n", err)
+				return
+			}
+			solved = daily.SolvedByDay(sessions)
+		}
+
+		root := dailyWorkspaceRoot()
+		entries, err := daily.PlanGC(root, policy, solved, time.Now())
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error planning cleanup: %v\n", err)
+			return
+		}
+		if !writeGCPlan(out, root, entries, keepDays, dryRun) {
+			return
+		}
+		if dryRun {
+			fmt.Fprintln(out, "Run without --dry-run to apply.")
+			return
+		}
+
+		if err := daily.ApplyGC(root, entries); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error cleaning up workspaces: %v\n", err)
+			return
+		}
+		fmt.Fprintln(out, "Done.")
+	},
+}
+
+// writeGCPlan lists the workspaces gc archives, deletes or keeps because of
+// solved work, and sums up the plan. It reports whether there is anything
+// to do.
+func writeGCPlan(out io.Writer, root string, entries []daily.GCEntry, keepDays int, dryRun bool) bool {
+	var archived, deleted, recent int
+	var freed int64
+	var lines []string
+	for _, e := range entries {
+		day := e.Date.Format("2006-01-02")
+		switch {
+		case e.Action == daily.GCArchive:
+			archived++
+		case e.Action == daily.GCDelete:
+			deleted++
+		case e.Expired:
+			lines = append(lines, fmt.Sprintf("  %s  keep     solved %s", day, strings.Join(e.Solved, ", ")))
+			continue
+		default:
+			recent++
+			continue
+		}
+		freed += e.Size
+		lines = append(lines, fmt.Sprintf("  %s  %-7s  %d file(s), %s", day, e.Action, e.Files, daily.FormatSize(e.Size)))
+	}
+
+	if archived+deleted == 0 {
+		fmt.Fprintf(out, "No daily workspaces older than %d days to clean up in %s.\n", keepDays, root)
+		return false
+	}
+
+	fmt.Fprintf(out, "Daily workspaces in %s:\n", root)
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	summary := "Archiving %d and deleting %d workspace(s), freeing %s; keeping %d from the last %d days.\n"
+	if dryRun {
+		summary = "Would archive %d and delete %d workspace(s), freeing %s; keeping %d from the last %d days.\n"
+	}
+	fmt.Fprintf(out, summary, archived, deleted, daily.FormatSize(freed), recent, keepDays)
+	return true
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceGCCmd)
+
+	workspaceGCCmd.Flags().Int("keep-days", daily.DefaultKeepDays, "Keep workspaces from the last this many days")
+	workspaceGCCmd.Flags().Bool("keep-solved", false, "Keep workspaces where you solved a daily problem, at any age")
+	workspaceGCCmd.Flags().Bool("delete", false, "Delete old workspaces instead of archiving them")
+	workspaceGCCmd.Flags().Bool("dry-run", false, "List what would be archived or deleted without changing anything")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceGC(t *testing.T) {
+	root := t.TempDir()
+	original := dailyWorkspaceRoot
+	dailyWorkspaceRoot = func() string { return root }
+	defer func() { dailyWorkspaceRoot = original }()
+
+	today := time.Now().Format("2006-01-02")
+	for _, day := range []string{"2020-01-05", today} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, day), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, day, "two_sum.go"), []byte("package main\n"), 0644))
+	}
+	resetFlags := func() {
+		workspaceGCCmd.Flags().Set("dry-run", "false")
+		workspaceGCCmd.Flags().Set("keep-days", "90")
+	}
+	defer resetFlags()
+
+	t.Run("DryRun", func(t *testing.T) {
+		output, err := executeCommand(rootCmd, "workspace", "gc", "--dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, output, "2020-01-05  archive  1 file(s), 13 B")
+		assert.NotContains(t, output, today)
+		assert.Contains(t, output, "Would archive 1 and delete 0 workspace(s), freeing 13 B; keeping 1 from the last 90 days.")
+		assert.Contains(t, output, "Run without --dry-run to apply.")
+		assert.DirExists(t, filepath.Join(root, "2020-01-05"))
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		resetFlags()
+		output, err := executeCommand(rootCmd, "workspace", "gc")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Archiving 1 and deleting 0 workspace(s)")
+		assert.Contains(t, output, "Done.")
+		assert.NoDirExists(t, filepath.Join(root, "2020-01-05"))
+		assert.FileExists(t, filepath.Join(root, daily.ArchiveDirName, "2020-01-05.zip"))
+		assert.DirExists(t, filepath.Join(root, today))
+
+		output, err = executeCommand(rootCmd, "workspace", "gc")
+		require.NoError(t, err)
+		assert.Contains(t, output, "No daily workspaces older than 90 days to clean up")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/lancekrogers/algo-scales/internal/common/features"
 	"github.com/lancekrogers/algo-scales/internal/problem"
 )
 
@@ -49,26 +50,18 @@ type ChatResponse struct {
 type Provider string
 
 const (
-	ProviderClaude Provider = "claude"
-	ProviderOllama Provider = "ollama"
+	ProviderClaude    Provider = "claude"
+	ProviderOllama    Provider = "ollama"
+	ProviderOpenAI    Provider = "openai"
+	ProviderAnthropic Provider = "anthropic"
 )
 
 // NewAgent creates a new AI agent based on the configuration
 func NewAgent(provider Provider, config *Config) (Agent, error) {
-	switch provider {
-	case ProviderClaude:
-		if config.Claude == nil {
-			return nil, fmt.Errorf("claude configuration not found")
-		}
-		return NewClaudeProvider(*config.Claude)
-	case ProviderOllama:
-		if config.Ollama == nil {
-			return nil, fmt.Errorf("ollama configuration not found")
-		}
-		return NewOllamaProvider(*config.Ollama)
-	default:
-		return nil, fmt.Errorf("unknown provider: %s", provider)
+	if err := features.Require(features.AI); err != nil {
+		return nil, err
 	}
+	return newProvider(provider, config)
 }
 
 // GetDefaultAgent returns an agent using the default provider from config
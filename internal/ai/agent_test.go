@@ -1,3 +1,5 @@
+//go:build !lite
+
 package ai
 
 import (
@@ -14,6 +16,12 @@ func TestNewAgent(t *testing.T) {
 			Host:  "http://localhost:11434",
 			Model: "llama3",
 		},
+		OpenAI: &OpenAIConfig{
+			BaseURL: "http://localhost:1234/v1",
+		},
+		Anthropic: &AnthropicConfig{
+			APIKey: "sk-ant-test",
+		},
 	}
 
 	tests := []struct {
@@ -31,6 +39,16 @@ func TestNewAgent(t *testing.T) {
 			provider: ProviderOllama,
 			wantErr:  false,
 		},
+		{
+			name:     "OpenAI provider",
+			provider: ProviderOpenAI,
+			wantErr:  false,
+		},
+		{
+			name:     "Anthropic provider",
+			provider: ProviderAnthropic,
+			wantErr:  false,
+		},
 		{
 			name:     "Unknown provider",
 			provider: Provider("unknown"),
@@ -68,6 +86,16 @@ func TestNewAgentMissingConfig(t *testing.T) {
 			provider: ProviderOllama,
 			config:   &Config{}, // No Ollama config
 		},
+		{
+			name:     "Missing OpenAI config",
+			provider: ProviderOpenAI,
+			config:   &Config{}, // No OpenAI config
+		},
+		{
+			name:     "Missing Anthropic config",
+			provider: ProviderAnthropic,
+			config:   &Config{}, // No Anthropic config
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,327 @@
+//go:build !lite
+
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// anthropicVersion is the Messages API version requests are made against
+const anthropicVersion = "2023-06-01"
+
+// anthropicRetryDelay is the delay before the first retry, doubling with
+// each further attempt
+// Exported as variable for testing
+var anthropicRetryDelay = time.Second
+
+// anthropicMaxRetryDelay caps both the backoff and server retry-after hints
+const anthropicMaxRetryDelay = 30 * time.Second
+
+// AnthropicProvider implements the Agent interface by calling the Anthropic
+// Messages API directly, for users without the Claude Code CLI
+type AnthropicProvider struct {
+	config     AnthropicConfig
+	client     *http.Client
+	apiBaseURL string
+	prompts    *PromptBuilder
+}
+
+// NewAnthropicProvider creates a new Anthropic API provider
+func NewAnthropicProvider(config AnthropicConfig) (*AnthropicProvider, error) {
+	// Set default values
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.anthropic.com"
+	}
+	if config.Model == "" {
+		config.Model = "claude-sonnet-4-5"
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("%w: set anthropic.api_key or ANTHROPIC_API_KEY", ErrNoAPIKey)
+	}
+	if config.MaxTokens == 0 {
+		config.MaxTokens = 4096
+	}
+	if config.Temperature == 0 {
+		config.Temperature = 0.7
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 120
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(config.Timeout) * time.Second,
+	}
+
+	return &AnthropicProvider{
+		config:     config,
+		client:     client,
+		apiBaseURL: strings.TrimRight(config.BaseURL, "/"),
+		prompts:    NewPromptBuilder(),
+	}, nil
+}
+
+// Chat implements the Agent interface, streaming the reply as server-sent
+// events. System messages are sent as the request's system prompt.
+func (a *AnthropicProvider) Chat(ctx context.Context, messages []Message, opts ChatOptions) (<-chan ChatResponse, error) {
+	respChan := make(chan ChatResponse)
+
+	go func() {
+		defer close(respChan)
+
+		var system []string
+		var anthropicMessages []anthropicMessage
+		for _, msg := range messages {
+			if msg.Role == "system" {
+				system = append(system, msg.Content)
+				continue
+			}
+			anthropicMessages = append(anthropicMessages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+		}
+
+		temperature := opts.Temperature
+		if temperature == 0 {
+			temperature = a.config.Temperature
+		}
+		maxTokens := opts.MaxTokens
+		if maxTokens == 0 {
+			maxTokens = a.config.MaxTokens
+		}
+		reqBody := anthropicRequest{
+			Model:       a.config.Model,
+			System:      strings.Join(system, "\n\n"),
+			Messages:    anthropicMessages,
+			MaxTokens:   maxTokens,
+			Temperature: temperature,
+			Stream:      true, // Always stream for real-time responses
+		}
+
+		reqData, err := json.Marshal(reqBody)
+		if err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("failed to marshal request: %w", err)}
+			return
+		}
+
+		resp, err := a.send(ctx, reqData)
+		if err != nil {
+			respChan <- ChatResponse{Error: err}
+			return
+		}
+		defer resp.Body.Close()
+
+		// Each event is an "event:" line followed by a "data:" line; the
+		// data's type field repeats the event name, so only data is read
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &event); err != nil {
+				respChan <- ChatResponse{Error: fmt.Errorf("failed to decode response: %w", err)}
+				return
+			}
+			switch event.Type {
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+					respChan <- ChatResponse{Content: event.Delta.Text}
+				}
+			case "message_stop":
+				respChan <- ChatResponse{Done: true}
+				return
+			case "error":
+				// Errors after the stream starts, e.g. overloaded_error,
+				// cannot be retried without repeating the text already sent
+				respChan <- ChatResponse{Error: APIError{Provider: "anthropic", Message: event.Error.Message}}
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("failed to read response: %w", err)}
+			return
+		}
+		respChan <- ChatResponse{Error: fmt.Errorf("%w: stream ended before message_stop", ErrInvalidResponse)}
+	}()
+
+	return respChan, nil
+}
+
+// send posts a request, retrying connection failures, rate limits and
+// overloaded or failing servers with exponential backoff. A retry-after
+// header from the server takes precedence over the backoff delay.
+func (a *AnthropicProvider) send(ctx context.Context, reqData []byte) (*http.Response, error) {
+	delay := anthropicRetryDelay
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", a.apiBaseURL+"/v1/messages", bytes.NewReader(reqData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("x-api-key", a.config.APIKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+
+		var wait time.Duration
+		resp, err := a.client.Do(req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			err = fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+		case resp.StatusCode == http.StatusOK:
+			return resp, nil
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			err = APIError{Provider: "anthropic", StatusCode: resp.StatusCode, Message: anthropicErrorMessage(body)}
+			wait = retryAfter(resp.Header.Get("retry-after"))
+		}
+
+		if attempt >= a.config.MaxRetries || !IsRetryable(err) {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = delay
+		}
+		if wait > anthropicMaxRetryDelay {
+			wait = anthropicMaxRetryDelay
+		}
+		delay *= 2
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// GetHint implements progressive hint generation
+func (a *AnthropicProvider) GetHint(ctx context.Context, prob problem.Problem, userCode string, level int) (<-chan string, error) {
+	prompt, err := a.prompts.BuildHintPrompt(prob, userCode, level)
+	if err != nil {
+		return nil, err
+	}
+	return a.stream(ctx, NewSystemPrompts().GetTutorPrompt(), prompt, "Error generating hint")
+}
+
+// ReviewCode provides AI-powered code review
+func (a *AnthropicProvider) ReviewCode(ctx context.Context, prob problem.Problem, code string) (<-chan string, error) {
+	prompt, err := a.prompts.BuildReviewPrompt(prob, code, "go")
+	if err != nil {
+		return nil, err
+	}
+	return a.stream(ctx, NewSystemPrompts().GetReviewerPrompt(), prompt, "Error generating review")
+}
+
+// ExplainPattern provides detailed pattern explanations
+func (a *AnthropicProvider) ExplainPattern(ctx context.Context, pattern string, examples []problem.Problem) (<-chan string, error) {
+	if len(examples) > 3 {
+		examples = examples[:3] // Limit to 3 examples
+	}
+	prompt, err := a.prompts.BuildPatternPrompt(pattern, examples)
+	if err != nil {
+		return nil, err
+	}
+	return a.stream(ctx, NewSystemPrompts().GetTutorPrompt(), prompt, "Error generating explanation")
+}
+
+// stream sends a single-turn conversation and forwards the reply text as it
+// arrives. Errors are reported in-band, prefixed with errPrefix.
+func (a *AnthropicProvider) stream(ctx context.Context, systemPrompt, userPrompt, errPrefix string) (<-chan string, error) {
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+
+		respChan, err := a.Chat(ctx, []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		}, ChatOptions{Stream: true})
+		if err != nil {
+			out <- fmt.Sprintf("%s: %v", errPrefix, err)
+			return
+		}
+
+		for resp := range respChan {
+			if resp.Error != nil {
+				out <- fmt.Sprintf("Error: %v", resp.Error)
+				return
+			}
+			if resp.Content != "" {
+				out <- resp.Content
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// retryAfter parses a retry-after header given in seconds, returning zero
+// when it is absent or not understood
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// anthropicErrorMessage extracts the message from an API error body,
+// falling back to the raw body
+func anthropicErrorMessage(body []byte) string {
+	var parsed anthropicStreamEvent
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error.Message != "" {
+		return parsed.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// Anthropic API types
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
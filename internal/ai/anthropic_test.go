@@ -0,0 +1,178 @@
+//go:build !lite
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// collectAnthropic drains a chat response channel
+func collectAnthropic(t *testing.T, respChan <-chan ChatResponse) (string, bool, error) {
+	t.Helper()
+	var text strings.Builder
+	done := false
+	for resp := range respChan {
+		if resp.Error != nil {
+			return text.String(), done, resp.Error
+		}
+		text.WriteString(resp.Content)
+		done = done || resp.Done
+	}
+	return text.String(), done, nil
+}
+
+func TestAnthropicChatStreams(t *testing.T) {
+	var got anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if key := r.Header.Get("x-api-key"); key != "sk-ant-test" {
+			t.Errorf("unexpected x-api-key %q", key)
+		}
+		if version := r.Header.Get("anthropic-version"); version != anthropicVersion {
+			t.Errorf("unexpected anthropic-version %q", version)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\"}\n\n")
+		fmt.Fprint(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		for _, piece := range []string{"Use a ", "hash map."} {
+			fmt.Fprintf(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":%q}}\n\n", piece)
+		}
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	provider, err := NewAnthropicProvider(AnthropicConfig{BaseURL: server.URL + "/", APIKey: "sk-ant-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	respChan, err := provider.Chat(context.Background(), []Message{
+		{Role: "system", Content: "You are a tutor."},
+		{Role: "user", Content: "hint?"},
+	}, ChatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, done, err := collectAnthropic(t, respChan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Use a hash map." || !done {
+		t.Errorf("got %q (done %v)", text, done)
+	}
+	if got.System != "You are a tutor." || len(got.Messages) != 1 || got.Messages[0].Role != "user" {
+		t.Errorf("system prompt should be sent separately, got %+v", got)
+	}
+	if !got.Stream || got.MaxTokens != 4096 || got.Model == "" {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
+
+func TestAnthropicRetries(t *testing.T) {
+	original := anthropicRetryDelay
+	anthropicRetryDelay = time.Millisecond
+	defer func() { anthropicRetryDelay = original }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"type":"error","error":{"type":"rate_limit_error","message":"Rate limited"}}`)
+		case 2:
+			w.WriteHeader(529)
+			fmt.Fprint(w, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+		default:
+			fmt.Fprint(w, "data: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"ok\"}}\n\n")
+			fmt.Fprint(w, "data: {\"type\":\"message_stop\"}\n\n")
+		}
+	}))
+	defer server.Close()
+
+	provider, _ := NewAnthropicProvider(AnthropicConfig{BaseURL: server.URL, APIKey: "sk-ant-test"})
+	respChan, _ := provider.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}, ChatOptions{})
+	text, done, err := collectAnthropic(t, respChan)
+	if err != nil || text != "ok" || !done {
+		t.Fatalf("got %q (done %v, err %v)", text, done, err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+
+	// Retries stop after MaxRetries
+	calls = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	provider, _ = NewAnthropicProvider(AnthropicConfig{BaseURL: server.URL, APIKey: "sk-ant-test", MaxRetries: 2})
+	respChan, _ = provider.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}, ChatOptions{})
+	_, _, err = collectAnthropic(t, respChan)
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last API error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 1 attempt and 2 retries, got %d calls", calls)
+	}
+}
+
+func TestAnthropicErrors(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if _, err := NewAnthropicProvider(AnthropicConfig{}); !errors.Is(err, ErrNoAPIKey) {
+		t.Errorf("expected ErrNoAPIKey, got %v", err)
+	}
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("ANTHROPIC_API_KEY", "sk-ant-env")
+	provider, err := NewAnthropicProvider(AnthropicConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hintChan, err := provider.GetHint(context.Background(), problem.Problem{Title: "Two Sum"}, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text strings.Builder
+	for chunk := range hintChan {
+		text.WriteString(chunk)
+	}
+	if !strings.Contains(text.String(), "(status 401): invalid x-api-key") {
+		t.Errorf("expected the API error message, got %q", text.String())
+	}
+	if calls != 1 {
+		t.Errorf("authentication errors should not be retried, got %d calls", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got := retryAfter("2"); got != 2*time.Second {
+		t.Errorf("retryAfter(2) = %v", got)
+	}
+	if got := retryAfter("Wed, 21 Oct 2015 07:28:00 GMT"); got != 0 {
+		t.Errorf("dates are not understood, got %v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// Clarify asks the agent to answer a clarifying question about a problem as
+// an interviewer would, without hinting at the solution
+func Clarify(ctx context.Context, agent Agent, prob problem.Problem, question string) (string, error) {
+	prompt, err := NewPromptBuilder().BuildClarifyPrompt(prob, question)
+	if err != nil {
+		return "", err
+	}
+	messages := []Message{
+		{Role: "system", Content: NewSystemPrompts().GetInterviewerPrompt()},
+		{Role: "user", Content: prompt},
+	}
+	responses, err := agent.Chat(ctx, messages, ChatOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var reply strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		reply.WriteString(resp.Content)
+	}
+
+	answer := strings.TrimSpace(reply.String())
+	if answer == "" {
+		return "", fmt.Errorf("AI reply did not include an answer")
+	}
+	return answer, nil
+}
@@ -0,0 +1,31 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+func TestClarify(t *testing.T) {
+	prob := problem.Problem{Title: "Two Sum", Description: "Find two numbers that add up to target", Constraints: []string{"2 <= nums.length <= 10^4"}}
+	agent := &replyAgent{reply: "  You may assume they are not sorted.\n"}
+
+	answer, err := Clarify(context.Background(), agent, prob, "Is the input sorted?")
+	if err != nil {
+		t.Fatalf("Clarify failed: %v", err)
+	}
+	if answer != "You may assume they are not sorted." {
+		t.Errorf("answer = %q", answer)
+	}
+	for _, want := range []string{"Two Sum", "- 2 <= nums.length <= 10^4", "Question: Is the input sorted?"} {
+		if !strings.Contains(agent.prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, agent.prompt)
+		}
+	}
+
+	if _, err := Clarify(context.Background(), &replyAgent{reply: " "}, prob, "Is the input sorted?"); err == nil {
+		t.Error("expected an error for an empty reply")
+	}
+}
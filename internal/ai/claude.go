@@ -1,3 +1,5 @@
+//go:build !lite
+
 package ai
 
 import (
@@ -18,8 +20,18 @@ type ClaudeProvider struct {
 	config    ClaudeConfig
 	client    *claude.ClaudeClient
 	sessionID string // Track current session for multi-turn conversations
+
+	workspace Workspace
+	// The user's answer to the file access prompt, asked once per provider
+	fileAccessAsked   bool
+	fileAccessGranted bool
 }
 
+// executable returns the path of the running binary, which serves the
+// confined file tools
+// Exported as variable for testing
+var executable = os.Executable
+
 // NewClaudeProvider creates a new Claude provider
 func NewClaudeProvider(config ClaudeConfig) (*ClaudeProvider, error) {
 	// Initialize the claude-code-go client
@@ -57,10 +69,29 @@ func NewClaudeProvider(config ClaudeConfig) (*ClaudeProvider, error) {
 	}, nil
 }
 
+// SessionID returns the conversation the next call continues
+func (c *ClaudeProvider) SessionID() string {
+	return c.sessionID
+}
+
+// SetWorkspace lets the AI read files in a problem's workspace, subject to
+// the configured file access policy
+func (c *ClaudeProvider) SetWorkspace(workspace Workspace) {
+	c.workspace = workspace
+	c.fileAccessAsked = false
+	c.fileAccessGranted = false
+}
+
 // Chat implements the Agent interface
 func (c *ClaudeProvider) Chat(ctx context.Context, messages []Message, opts ChatOptions) (<-chan ChatResponse, error) {
 	respChan := make(chan ChatResponse)
 
+	// Ask before streaming starts, while the terminal is free
+	fileRoot := ""
+	if c.allowFileAccess() {
+		fileRoot = c.workspace.Dir
+	}
+
 	go func() {
 		defer close(respChan)
 
@@ -85,19 +116,25 @@ func (c *ClaudeProvider) Chat(ctx context.Context, messages []Message, opts Chat
 
 		// Set up MCP if configured
 		if c.config.MCP != nil && c.config.MCP.Enabled {
-			mcpFile, err := c.writeMCPConfig(c.config.MCP)
+			mcpConfig, err := c.sessionMCPConfig(fileRoot, c.workspace.Session)
 			if err != nil {
-				respChan <- ChatResponse{Error: fmt.Errorf("failed to write MCP config: %w", err)}
+				respChan <- ChatResponse{Error: err}
 				return
 			}
-			defer os.Remove(mcpFile)
-			runOpts.MCPConfigPath = mcpFile
+			if len(mcpConfig.Servers) > 0 {
+				mcpFile, err := c.writeMCPConfig(mcpConfig)
+				if err != nil {
+					respChan <- ChatResponse{Error: fmt.Errorf("failed to write MCP config: %w", err)}
+					return
+				}
+				defer os.Remove(mcpFile)
+				runOpts.MCPConfigPath = mcpFile
+			}
 		}
 
-		// Set allowed tools
-		if len(c.config.AllowedTools) > 0 {
-			runOpts.AllowedTools = c.config.AllowedTools
-		}
+		// Set allowed tools; files can only be read through the MCP server
+		runOpts.AllowedTools = c.allowedTools(fileRoot != "")
+		runOpts.DisallowedTools = c.disallowedTools()
 
 		// Stream the response
 		messageCh, errCh := c.client.StreamPrompt(ctx, prompt, runOpts)
@@ -184,28 +221,19 @@ func (c *ClaudeProvider) ReviewCode(ctx context.Context, prob problem.Problem, c
 	go func() {
 		defer close(reviewChan)
 
-		// Create temporary file with the code
-		tmpFile, err := os.CreateTemp("", fmt.Sprintf("review-%s-*%s", prob.ID, getFileExtension("go")))
+		// The AI can read the code, and only the code, from a directory of its own
+		dir, err := os.MkdirTemp("", fmt.Sprintf("review-%s-*", prob.ID))
 		if err != nil {
-			reviewChan <- fmt.Sprintf("Error creating temp file: %v", err)
+			reviewChan <- fmt.Sprintf("Error creating review directory: %v", err)
 			return
 		}
-		defer os.Remove(tmpFile.Name())
+		defer os.RemoveAll(dir)
 
-		if _, err := tmpFile.WriteString(code); err != nil {
+		codeFile := filepath.Join(dir, "solution"+getFileExtension("go"))
+		if err := os.WriteFile(codeFile, []byte(code), 0600); err != nil {
 			reviewChan <- fmt.Sprintf("Error writing code: %v", err)
 			return
 		}
-		tmpFile.Close()
-
-		// Use MCP filesystem tool to analyze the code
-		mcpConfig := c.createCodeReviewMCPConfig()
-		mcpFile, err := c.writeMCPConfig(mcpConfig)
-		if err != nil {
-			reviewChan <- fmt.Sprintf("Error creating MCP config: %v", err)
-			return
-		}
-		defer os.Remove(mcpFile)
 
 		// Review prompt
 		prompt := fmt.Sprintf(`Review the code in %s for the problem "%s". 
@@ -220,15 +248,35 @@ Problem details:
 - Pattern: %s
 - Difficulty: %s
 - Description: %s`,
-			tmpFile.Name(), prob.Title, getPrimaryPattern(prob), prob.Difficulty, prob.Description)
+			codeFile, prob.Title, getPrimaryPattern(prob), prob.Difficulty, prob.Description)
+
+		runOpts := &claude.RunOptions{
+			SystemPrompt:    "You are a senior software engineer conducting a thorough code review. Focus on educational feedback that helps the student improve.",
+			Format:          claude.StreamJSONOutput,
+			DisallowedTools: c.disallowedTools(),
+		}
+
+		// Without file access, the code is part of the prompt instead
+		if c.config.MCP.FileAccessPolicy() == FileAccessDeny {
+			prompt = strings.Replace(prompt, "the code in "+codeFile, "this code", 1) + "\n\nCode:\n```\n" + code + "\n```"
+		} else {
+			mcpConfig, err := c.reviewMCPConfig(dir, "review-"+prob.ID)
+			if err != nil {
+				reviewChan <- fmt.Sprintf("Error creating MCP config: %v", err)
+				return
+			}
+			mcpFile, err := c.writeMCPConfig(mcpConfig)
+			if err != nil {
+				reviewChan <- fmt.Sprintf("Error creating MCP config: %v", err)
+				return
+			}
+			defer os.Remove(mcpFile)
+			runOpts.MCPConfigPath = mcpFile
+			runOpts.AllowedTools = []string{"mcp__filesystem__read_file"}
+		}
 
 		// Stream the review
-		messageCh, errCh := c.client.StreamPrompt(ctx, prompt, &claude.RunOptions{
-			SystemPrompt:  "You are a senior software engineer conducting a thorough code review. Focus on educational feedback that helps the student improve.",
-			MCPConfigPath: mcpFile,
-			AllowedTools:  []string{"mcp__filesystem__read_file"},
-			Format:        claude.StreamJSONOutput,
-		})
+		messageCh, errCh := c.client.StreamPrompt(ctx, prompt, runOpts)
 
 		// Handle errors
 		go func() {
@@ -331,16 +379,130 @@ func (c *ClaudeProvider) buildHintUserPrompt(prob problem.Problem, userCode stri
 	return fmt.Sprintf("I need a level %d hint for this problem.", level)
 }
 
-func (c *ClaudeProvider) createCodeReviewMCPConfig() *MCPConfig {
-	return &MCPConfig{
-		Enabled: true,
-		Servers: map[string]MCPServerConfig{
-			"filesystem": {
-				Command: "npx",
-				Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "./"},
-			},
-		},
+// allowFileAccess reports whether the AI may read the workspace, asking the
+// first time when the policy is to ask
+func (c *ClaudeProvider) allowFileAccess() bool {
+	if c.workspace.Dir == "" {
+		return false
+	}
+	if info, err := os.Stat(c.workspace.Dir); err != nil || !info.IsDir() {
+		return false
+	}
+	if c.fileAccessAsked {
+		return c.fileAccessGranted
+	}
+
+	switch c.config.MCP.FileAccessPolicy() {
+	case FileAccessAllow:
+		c.fileAccessGranted = true
+	case FileAccessDeny:
+		c.fileAccessGranted = false
+	default:
+		c.fileAccessGranted = c.workspace.Ask != nil && c.workspace.Ask(c.workspace.Dir)
+	}
+	c.fileAccessAsked = true
+	return c.fileAccessGranted
+}
+
+// sessionMCPConfig is the configured MCP servers, with any file-system
+// server replaced by one confined to root. With no root, the AI gets no
+// file-system server at all.
+func (c *ClaudeProvider) sessionMCPConfig(root, session string) (*MCPConfig, error) {
+	config := &MCPConfig{Enabled: true, Servers: map[string]MCPServerConfig{}}
+	for name, server := range c.config.MCP.Servers {
+		if !isFileSystemServer(name, server) {
+			config.Servers[name] = server
+		}
+	}
+	if root != "" {
+		server, err := c.fileServer(root, session)
+		if err != nil {
+			return nil, err
+		}
+		config.Servers["filesystem"] = server
+	}
+	return config, nil
+}
+
+// reviewMCPConfig gives a code review only the file-system server, confined
+// to the directory holding the code
+func (c *ClaudeProvider) reviewMCPConfig(dir, session string) (*MCPConfig, error) {
+	server, err := c.fileServer(dir, session)
+	if err != nil {
+		return nil, err
+	}
+	return &MCPConfig{Enabled: true, Servers: map[string]MCPServerConfig{"filesystem": server}}, nil
+}
+
+// fileServer runs 'algo-scales mcp files', which serves read_file and
+// list_directory for root alone and records what they read
+func (c *ClaudeProvider) fileServer(root, session string) (MCPServerConfig, error) {
+	exe, err := executable()
+	if err != nil {
+		return MCPServerConfig{}, fmt.Errorf("failed to locate algo-scales for the file tools: %w", err)
+	}
+	args := []string{"mcp", "files", "--root", root, "--audit-log", c.config.MCP.AuditLogPath()}
+	if session != "" {
+		args = append(args, "--session", session)
+	}
+	return MCPServerConfig{Command: exe, Args: args}, nil
+}
+
+// isFileSystemServer reports whether a configured server gives file-system
+// access. Such servers are replaced, since they are not confined to the
+// workspace.
+func isFileSystemServer(name string, server MCPServerConfig) bool {
+	if name == "filesystem" {
+		return true
+	}
+	for _, arg := range server.Args {
+		if strings.Contains(arg, "server-filesystem") {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedTools is the configured allowed tools without Claude Code's own
+// file tools, and with the confined file tools when files may be read
+func (c *ClaudeProvider) allowedTools(files bool) []string {
+	var tools []string
+	for _, tool := range c.config.AllowedTools {
+		if files || !strings.HasPrefix(tool, "mcp__filesystem__") {
+			if !containsTool(builtinFileTools, tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	if files {
+		for _, tool := range []string{"mcp__filesystem__read_file", "mcp__filesystem__list_directory"} {
+			if !containsTool(tools, tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	return tools
+}
+
+// disallowedTools is the configured disallowed tools and Claude Code's own
+// file tools
+func (c *ClaudeProvider) disallowedTools() []string {
+	tools := append([]string{}, c.config.DisallowedTools...)
+	for _, tool := range builtinFileTools {
+		if !containsTool(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+func containsTool(tools []string, tool string) bool {
+	for _, t := range tools {
+		if t == tool {
+			return true
+		}
 	}
+	return false
 }
 
 func (c *ClaudeProvider) writeMCPConfig(config *MCPConfig) (string, error) {
@@ -404,10 +566,16 @@ func getFileExtension(language string) string {
 		return ".py"
 	case "javascript":
 		return ".js"
+	case "typescript", "ts":
+		return ".ts"
 	case "go":
 		return ".go"
 	case "java":
 		return ".java"
+	case "rust":
+		return ".rs"
+	case "cpp", "c++":
+		return ".cpp"
 	default:
 		return ".txt"
 	}
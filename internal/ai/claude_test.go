@@ -0,0 +1,93 @@
+//go:build !lite
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClaude(t *testing.T, policy string) *ClaudeProvider {
+	t.Helper()
+	provider, err := NewClaudeProvider(ClaudeConfig{
+		MCP: &MCPConfig{
+			Enabled: true,
+			Servers: map[string]MCPServerConfig{
+				"filesystem": {Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-filesystem", "./"}},
+				"files":      {Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-filesystem", "/"}},
+				"docs":       {Command: "docs-server"},
+			},
+			Permissions: &MCPPermissions{FileAccess: policy, AuditLog: "/tmp/audit.log"},
+		},
+		AllowedTools:    []string{"mcp__filesystem__read_file", "Bash", "Read", "mcp__docs__search"},
+		DisallowedTools: []string{"mcp__filesystem__write_file"},
+	})
+	require.NoError(t, err)
+	return provider
+}
+
+func TestClaudeFileAccessPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	asked := 0
+	ask := func(string) bool { asked++; return true }
+
+	provider := newTestClaude(t, FileAccessAsk)
+	assert.False(t, provider.allowFileAccess(), "no workspace, no access")
+	provider.SetWorkspace(Workspace{Dir: dir, Ask: ask})
+	assert.True(t, provider.allowFileAccess())
+	assert.True(t, provider.allowFileAccess())
+	assert.Equal(t, 1, asked, "asked once per session")
+
+	provider.SetWorkspace(Workspace{Dir: dir})
+	assert.False(t, provider.allowFileAccess(), "cannot ask, so denied")
+
+	provider.SetWorkspace(Workspace{Dir: dir + "/missing", Ask: ask})
+	assert.False(t, provider.allowFileAccess())
+	assert.Equal(t, 1, asked, "no workspace to read yet")
+
+	provider = newTestClaude(t, FileAccessDeny)
+	provider.SetWorkspace(Workspace{Dir: dir, Ask: ask})
+	assert.False(t, provider.allowFileAccess())
+
+	provider = newTestClaude(t, FileAccessAllow)
+	provider.SetWorkspace(Workspace{Dir: dir, Ask: ask})
+	assert.True(t, provider.allowFileAccess())
+	assert.Equal(t, 1, asked)
+}
+
+func TestClaudeSessionMCPConfig(t *testing.T) {
+	original := executable
+	executable = func() (string, error) { return "/usr/local/bin/algo-scales", nil }
+	defer func() { executable = original }()
+
+	provider := newTestClaude(t, FileAccessAsk)
+
+	// File-system servers are never passed on as configured
+	config, err := provider.sessionMCPConfig("", "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]MCPServerConfig{"docs": {Command: "docs-server"}}, config.Servers)
+
+	config, err = provider.sessionMCPConfig("/tmp/algo-scales/two_sum", "two_sum-1")
+	require.NoError(t, err)
+	assert.Len(t, config.Servers, 2)
+	assert.Equal(t, MCPServerConfig{
+		Command: "/usr/local/bin/algo-scales",
+		Args:    []string{"mcp", "files", "--root", "/tmp/algo-scales/two_sum", "--audit-log", "/tmp/audit.log", "--session", "two_sum-1"},
+	}, config.Servers["filesystem"])
+}
+
+func TestClaudeTools(t *testing.T) {
+	provider := newTestClaude(t, FileAccessAsk)
+
+	assert.Equal(t, []string{"mcp__docs__search"}, provider.allowedTools(false))
+	assert.Equal(t, []string{"mcp__filesystem__read_file", "mcp__docs__search", "mcp__filesystem__list_directory"}, provider.allowedTools(true))
+
+	disallowed := provider.disallowedTools()
+	assert.Equal(t, "mcp__filesystem__write_file", disallowed[0])
+	for _, tool := range []string{"Bash", "Read", "Grep", "Write"} {
+		assert.Contains(t, disallowed, tool, "Claude Code's own file tools bypass the workspace")
+	}
+}
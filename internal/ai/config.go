@@ -10,13 +10,15 @@ import (
 
 // Config represents the AI assistant configuration
 type Config struct {
-	Version         string         `yaml:"version"`
-	DefaultProvider string         `yaml:"default_provider"`
-	Claude          *ClaudeConfig  `yaml:"claude,omitempty"`
-	Ollama          *OllamaConfig  `yaml:"ollama,omitempty"`
-	Prompts         *PromptConfig  `yaml:"prompts,omitempty"`
-	Features        *FeatureConfig `yaml:"features,omitempty"`
-	Logging         *LoggingConfig `yaml:"logging,omitempty"`
+	Version         string           `yaml:"version"`
+	DefaultProvider string           `yaml:"default_provider"`
+	Claude          *ClaudeConfig    `yaml:"claude,omitempty"`
+	Ollama          *OllamaConfig    `yaml:"ollama,omitempty"`
+	OpenAI          *OpenAIConfig    `yaml:"openai,omitempty"`
+	Anthropic       *AnthropicConfig `yaml:"anthropic,omitempty"`
+	Prompts         *PromptConfig    `yaml:"prompts,omitempty"`
+	Features        *FeatureConfig   `yaml:"features,omitempty"`
+	Logging         *LoggingConfig   `yaml:"logging,omitempty"`
 }
 
 // ClaudeConfig configures the Claude Code integration
@@ -34,9 +36,17 @@ type ClaudeConfig struct {
 
 // MCPConfig represents Model Context Protocol configuration
 type MCPConfig struct {
-	Enabled    bool                       `yaml:"enabled"`
-	ConfigFile string                     `yaml:"config_file"`
-	Servers    map[string]MCPServerConfig `yaml:"servers"`
+	Enabled     bool                       `yaml:"enabled"`
+	ConfigFile  string                     `yaml:"config_file"`
+	Servers     map[string]MCPServerConfig `yaml:"servers"`
+	Permissions *MCPPermissions            `yaml:"permissions,omitempty"`
+}
+
+// MCPPermissions controls what the AI's file-system tools may read. They can
+// only ever read the current problem's workspace.
+type MCPPermissions struct {
+	FileAccess string `yaml:"file_access"` // ask (the default), allow or deny
+	AuditLog   string `yaml:"audit_log"`   // Records every file the AI reads
 }
 
 // MCPServerConfig represents an MCP server configuration
@@ -55,6 +65,26 @@ type OllamaConfig struct {
 	Temperature float64 `yaml:"temperature"`
 }
 
+// OpenAIConfig configures an OpenAI-compatible chat completions API
+type OpenAIConfig struct {
+	BaseURL     string  `yaml:"base_url"` // e.g. https://api.groq.com/openai/v1 or http://localhost:1234/v1
+	APIKey      string  `yaml:"api_key"`  // Falls back to OPENAI_API_KEY
+	Model       string  `yaml:"model"`
+	Timeout     int     `yaml:"timeout"`
+	Temperature float64 `yaml:"temperature"`
+}
+
+// AnthropicConfig configures direct access to the Anthropic Messages API
+type AnthropicConfig struct {
+	BaseURL     string  `yaml:"base_url"`
+	APIKey      string  `yaml:"api_key"` // Falls back to ANTHROPIC_API_KEY
+	Model       string  `yaml:"model"`
+	MaxTokens   int     `yaml:"max_tokens"`
+	Timeout     int     `yaml:"timeout"`
+	Temperature float64 `yaml:"temperature"`
+	MaxRetries  int     `yaml:"max_retries"` // Retries on rate limits and overloaded servers
+}
+
 // PromptConfig contains prompt templates
 type PromptConfig struct {
 	SystemPrefix string `yaml:"system_prefix"`
@@ -107,6 +137,18 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// Configured reports whether the user has an AI configuration file. Features
+// that call the AI without being asked check it, so that they do not create a
+// default configuration or spend tokens for users who never set one up.
+func Configured() bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(homeDir, ".algo-scales", "ai-config.yaml"))
+	return err == nil
+}
+
 // SaveConfig saves the configuration to file
 func SaveConfig(config *Config) error {
 	homeDir, err := os.UserHomeDir()
@@ -150,18 +192,15 @@ func createDefaultConfig(configPath string) (*Config, error) {
 			Verbose:       false,
 			MCP: &MCPConfig{
 				Enabled: true,
-				Servers: map[string]MCPServerConfig{
-					"filesystem": {
-						Command: "npx",
-						Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "./"},
-					},
+				Servers: map[string]MCPServerConfig{},
+				Permissions: &MCPPermissions{
+					FileAccess: FileAccessAsk,
+					AuditLog:   "~/.algo-scales/ai-file-access.log",
 				},
 			},
 			AllowedTools: []string{
 				"mcp__filesystem__read_file",
 				"mcp__filesystem__list_directory",
-				"Bash",
-				"Read",
 			},
 			DisallowedTools: []string{
 				"mcp__filesystem__write_file",
@@ -213,6 +252,9 @@ func (c *Config) expandPaths(homeDir string) {
 	if c.Claude != nil && c.Claude.MCP != nil && c.Claude.MCP.ConfigFile != "" {
 		c.Claude.MCP.ConfigFile = expandPath(c.Claude.MCP.ConfigFile, homeDir)
 	}
+	if c.Claude != nil && c.Claude.MCP != nil && c.Claude.MCP.Permissions != nil && c.Claude.MCP.Permissions.AuditLog != "" {
+		c.Claude.MCP.Permissions.AuditLog = expandPath(c.Claude.MCP.Permissions.AuditLog, homeDir)
+	}
 	if c.Logging != nil && c.Logging.LogFile != "" {
 		c.Logging.LogFile = expandPath(c.Logging.LogFile, homeDir)
 	}
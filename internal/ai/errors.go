@@ -102,7 +102,7 @@ func IsRetryable(err error) bool {
 	var apiErr APIError
 	if errors.As(err, &apiErr) {
 		switch apiErr.StatusCode {
-		case 429, 502, 503, 504: // Rate limit, bad gateway, service unavailable, timeout
+		case 429, 500, 502, 503, 504, 529: // Rate limit, server errors, timeout, overloaded
 			return true
 		}
 	}
@@ -136,6 +136,14 @@ func HandleProviderError(provider Provider, err error) string {
 		case errors.Is(err, ErrProviderNotConfigured):
 			return "Ollama is not configured. Run 'algo-scales ai config' to set up Ollama."
 		}
+
+	case ProviderAnthropic:
+		switch {
+		case errors.Is(err, ErrNoAPIKey):
+			return "No Anthropic API key found. Set ANTHROPIC_API_KEY or run 'algo-scales ai config set anthropic.api_key <key>'."
+		case errors.Is(err, ErrProviderNotConfigured):
+			return "Anthropic is not configured. Run 'algo-scales ai config' to set up the Anthropic API."
+		}
 	}
 
 	// Generic error handling
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// File access policies for the AI's file-system tools
+const (
+	FileAccessAsk   = "ask"   // Ask once per AI session
+	FileAccessAllow = "allow" // Allow without asking
+	FileAccessDeny  = "deny"  // Never let the AI read files
+)
+
+// Workspace is the directory an agent's file tools may read: the current
+// problem's workspace, and nothing outside it
+type Workspace struct {
+	Dir     string
+	Session string // Labels the reads in the audit log
+	// Ask asks whether the AI may read files in dir. Under the ask policy it
+	// is called at most once per agent, before the first request that would
+	// let the AI read files; without it, access is denied.
+	Ask func(dir string) bool
+}
+
+// WorkspaceAgent is implemented by agents whose tools can read files
+type WorkspaceAgent interface {
+	SetWorkspace(workspace Workspace)
+}
+
+// SetWorkspace gives an agent's file tools a problem's workspace. Agents
+// without file tools ignore it.
+func SetWorkspace(agent Agent, workspace Workspace) {
+	if a, ok := agent.(WorkspaceAgent); ok {
+		a.SetWorkspace(workspace)
+	}
+}
+
+// builtinFileTools are Claude Code's own tools that can reach the file
+// system. They are always disallowed, so the AI can only read files through
+// the MCP server confined to the workspace.
+var builtinFileTools = []string{
+	"Bash", "Read", "Glob", "Grep", "LS", "Edit", "MultiEdit", "Write", "NotebookRead", "NotebookEdit",
+}
+
+// FileAccessPolicy returns the configured file access policy, ask by default
+func (m *MCPConfig) FileAccessPolicy() string {
+	if m == nil || m.Permissions == nil {
+		return FileAccessAsk
+	}
+	switch m.Permissions.FileAccess {
+	case FileAccessAllow, FileAccessDeny:
+		return m.Permissions.FileAccess
+	default:
+		return FileAccessAsk
+	}
+}
+
+// AuditLogPath returns the file recording what the AI reads
+func (m *MCPConfig) AuditLogPath() string {
+	if m != nil && m.Permissions != nil && m.Permissions.AuditLog != "" {
+		homeDir, _ := os.UserHomeDir()
+		return expandPath(m.Permissions.AuditLog, homeDir)
+	}
+	return DefaultAuditLogPath()
+}
+
+// DefaultAuditLogPath returns where the files the AI reads are recorded when
+// the configuration does not say
+func DefaultAuditLogPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".algo-scales", "ai-file-access.log")
+	}
+	return filepath.Join(homeDir, ".algo-scales", "ai-file-access.log")
+}
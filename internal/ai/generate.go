@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// GenerateRequest describes a problem for the agent to write
+type GenerateRequest struct {
+	Pattern    string
+	Difficulty string
+	Language   string   // Of the starter code and the reference solution
+	Avoid      []string // Titles of existing problems the new one must differ from
+}
+
+// GenerateProblem asks the agent to write a new problem of a pattern and
+// difficulty, with test cases and a reference solution. The problem is not
+// checked: its reference solution still has to be run against its tests.
+func GenerateProblem(ctx context.Context, agent Agent, req GenerateRequest) (*problem.Problem, error) {
+	difficulty := strings.ToLower(req.Difficulty)
+	prompt, err := NewPromptBuilder().BuildGeneratePrompt(req.Pattern, difficulty, req.Language, req.Avoid)
+	if err != nil {
+		return nil, err
+	}
+	messages := []Message{
+		{Role: "system", Content: NewSystemPrompts().GetInterviewerPrompt()},
+		{Role: "user", Content: prompt},
+	}
+	responses, err := agent.Chat(ctx, messages, ChatOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		reply.WriteString(resp.Content)
+	}
+
+	p, err := ParseProblem(reply.String())
+	if err != nil {
+		return nil, err
+	}
+	p.Difficulty = difficulty
+	if len(p.Patterns) == 0 || p.Patterns[0] != req.Pattern {
+		p.Patterns = append([]string{req.Pattern}, removeString(p.Patterns, req.Pattern)...)
+	}
+	return p, nil
+}
+
+// generatedProblem is a problem as the agent writes it, whose test inputs
+// and expected values may come as JSON values rather than strings
+type generatedProblem struct {
+	problem.Problem
+	TestCases []struct {
+		Input    jsonText            `json:"input"`
+		Expected jsonText            `json:"expected"`
+		Tier     interfaces.TestTier `json:"tier"`
+	} `json:"test_cases"`
+}
+
+// jsonText is a string, or any other JSON value kept as its compact text
+type jsonText string
+
+func (t *jsonText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = jsonText(s)
+		return nil
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+	*t = jsonText(compact.String())
+	return nil
+}
+
+// ParseProblem reads the problem from a reply to the generate prompt,
+// tolerating a Markdown code fence or other text around the JSON object
+func ParseProblem(reply string) (*problem.Problem, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("AI reply did not include a problem")
+	}
+
+	var generated generatedProblem
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &generated); err != nil {
+		return nil, fmt.Errorf("AI reply is not a valid problem: %v", err)
+	}
+	p := generated.Problem
+	p.TestCases = make([]problem.TestCase, len(generated.TestCases))
+	for i, tc := range generated.TestCases {
+		p.TestCases[i] = problem.TestCase{Input: string(tc.Input), Expected: string(tc.Expected), Tier: tc.Tier}
+	}
+	return &p, nil
+}
+
+// removeString returns list without s
+func removeString(list []string, s string) []string {
+	var kept []string
+	for _, item := range list {
+		if item != s {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+func TestParseProblem(t *testing.T) {
+	reply := "Here is the problem:\n```json\n" + `{
+  "id": "pair_with_sum",
+  "title": "Pair With Sum",
+  "description": "Find two numbers in a sorted array that add up to target.",
+  "signature": {"name": "pairWithSum", "params": [{"name": "nums", "type": "int[]"}, {"name": "target", "type": "int"}], "returns": "int[]"},
+  "solutions": {"python": "def pair_with_sum(nums, target):\n    return []"},
+  "test_cases": [
+    {"input": "[1,2,3], 5", "expected": "[1,2]", "tier": "example"},
+    {"input": "[1, 4], 5", "expected": [0, 1], "tier": "edge"}
+  ]
+}` + "\n```"
+
+	p, err := ParseProblem(reply)
+	if err != nil {
+		t.Fatalf("ParseProblem failed: %v", err)
+	}
+	if p.ID != "pair_with_sum" || p.Signature == nil || p.Signature.Name != "pairWithSum" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+	want := []problem.TestCase{
+		{Input: "[1,2,3], 5", Expected: "[1,2]", Tier: "example"},
+		{Input: "[1, 4], 5", Expected: "[0,1]", Tier: "edge"},
+	}
+	if !reflect.DeepEqual(p.TestCases, want) {
+		t.Errorf("test cases = %+v, want %+v", p.TestCases, want)
+	}
+
+	if _, err := ParseProblem("I can't write that."); err == nil {
+		t.Error("expected an error for a reply without a problem")
+	}
+	if _, err := ParseProblem(`{"id": 7}`); err == nil {
+		t.Error("expected an error for a malformed problem")
+	}
+}
+
+func TestGenerateProblem(t *testing.T) {
+	agent := &replyAgent{reply: `{"id": "pair_with_sum", "title": "Pair With Sum", "difficulty": "Hard", "patterns": ["hash-map", "two-pointers"]}`}
+
+	p, err := GenerateProblem(context.Background(), agent, GenerateRequest{
+		Pattern: "two-pointers", Difficulty: "Medium", Language: "python", Avoid: []string{"Two Sum"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateProblem failed: %v", err)
+	}
+	if p.Difficulty != "medium" {
+		t.Errorf("difficulty = %q, want the one asked for", p.Difficulty)
+	}
+	if want := []string{"two-pointers", "hash-map"}; !reflect.DeepEqual(p.Patterns, want) {
+		t.Errorf("patterns = %q, want %q", p.Patterns, want)
+	}
+	for _, want := range []string{`medium coding interview problem that is solved with the "two-pointers" pattern`, "- Two Sum", `"python"`} {
+		if !strings.Contains(agent.prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, agent.prompt)
+		}
+	}
+}
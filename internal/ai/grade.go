@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// MaxRubricScore is the score for a criterion the pseudo-code fully meets
+const MaxRubricScore = 2
+
+// Criterion is one part of the rubric pseudo-code is graded against
+type Criterion struct {
+	Name        string
+	Description string
+}
+
+// Rubric lists the criteria pseudo-code is graded against, in the order
+// they are reported. Correctness comes first and must be fully met to pass.
+var Rubric = []Criterion{
+	{Name: "Correctness", Description: "returns the right result for every valid input"},
+	{Name: "Approach", Description: "uses the reference approach, or one at least as sound"},
+	{Name: "Complexity", Description: "matches the time and space complexity of the reference"},
+	{Name: "Edge cases", Description: "handles empty, minimal and boundary inputs"},
+}
+
+// RubricScore is the grade given for one criterion
+type RubricScore struct {
+	Criterion string
+	Score     int // 0 to MaxRubricScore
+	Comment   string
+}
+
+// Grade is the AI's assessment of a pseudo-code solution
+type Grade struct {
+	Scores   []RubricScore // In Rubric order
+	Feedback string
+}
+
+// Met reports whether the score meets its criterion: correctness must be
+// fully met, every other criterion at least partly
+func (s RubricScore) Met() bool {
+	if s.Criterion == Rubric[0].Name {
+		return s.Score == MaxRubricScore
+	}
+	return s.Score > 0
+}
+
+// Passed reports whether every criterion of the rubric was met
+func (g *Grade) Passed() bool {
+	for _, score := range g.Scores {
+		if !score.Met() {
+			return false
+		}
+	}
+	return len(g.Scores) == len(Rubric)
+}
+
+// GradePseudoCode asks the agent to grade whiteboard-style pseudo-code
+// against the problem's reference solutions using the Rubric
+func GradePseudoCode(ctx context.Context, agent Agent, prob problem.Problem, code string) (*Grade, error) {
+	prompt, err := NewPromptBuilder().BuildGradePrompt(prob, code)
+	if err != nil {
+		return nil, err
+	}
+	messages := []Message{
+		{Role: "system", Content: NewSystemPrompts().GetInterviewerPrompt()},
+		{Role: "user", Content: prompt},
+	}
+	responses, err := agent.Chat(ctx, messages, ChatOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		reply.WriteString(resp.Content)
+	}
+
+	return ParseGrade(reply.String())
+}
+
+// ParseGrade reads a reply to the grade prompt: a line per rubric criterion
+// with its score and a comment, followed by overall feedback
+func ParseGrade(reply string) (*Grade, error) {
+	scores := make(map[string]RubricScore)
+	var feedback []string
+	inFeedback := false
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := cutHeading(line, "FEEDBACK"); ok {
+			inFeedback, line = true, rest
+		} else if score, ok := parseRubricLine(line); ok {
+			scores[score.Criterion] = score
+			inFeedback = false
+			continue
+		}
+		if inFeedback && line != "" {
+			feedback = append(feedback, line)
+		}
+	}
+
+	grade := &Grade{Feedback: strings.Join(feedback, " ")}
+	for _, criterion := range Rubric {
+		score, ok := scores[criterion.Name]
+		if !ok {
+			return nil, fmt.Errorf("AI reply did not grade %s", strings.ToLower(criterion.Name))
+		}
+		grade.Scores = append(grade.Scores, score)
+	}
+	return grade, nil
+}
+
+// parseRubricLine parses a line such as "CORRECTNESS: 2/2 - handles all
+// inputs" into the score for that criterion
+func parseRubricLine(line string) (RubricScore, bool) {
+	for _, criterion := range Rubric {
+		rest, ok := cutHeading(line, strings.ToUpper(criterion.Name))
+		if !ok || rest == "" {
+			continue
+		}
+
+		after := strings.TrimLeft(rest, "0123456789")
+		score, err := strconv.Atoi(rest[:len(rest)-len(after)])
+		if err != nil || score > MaxRubricScore {
+			return RubricScore{}, false
+		}
+		after = strings.TrimPrefix(after, "/"+strconv.Itoa(MaxRubricScore))
+		comment := strings.TrimSpace(strings.TrimLeft(after, " -–—:."))
+		return RubricScore{Criterion: criterion.Name, Score: score, Comment: comment}, true
+	}
+	return RubricScore{}, false
+}
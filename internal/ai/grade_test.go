@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+func TestParseGrade(t *testing.T) {
+	reply := `**CORRECTNESS:** 2/2 - Returns the right pair for every input.
+Approach: 2 — Uses a hash map like the reference.
+COMPLEXITY: 1/2 - Time is O(n) but space is not discussed.
+EDGE CASES: 1/2 - Does not say what happens with no answer.
+FEEDBACK: Solid approach.
+State the space complexity next time.`
+
+	grade, err := ParseGrade(reply)
+	if err != nil {
+		t.Fatalf("ParseGrade failed: %v", err)
+	}
+	if len(grade.Scores) != len(Rubric) {
+		t.Fatalf("got %d scores, want %d", len(grade.Scores), len(Rubric))
+	}
+	want := []RubricScore{
+		{Criterion: "Correctness", Score: 2, Comment: "Returns the right pair for every input."},
+		{Criterion: "Approach", Score: 2, Comment: "Uses a hash map like the reference."},
+		{Criterion: "Complexity", Score: 1, Comment: "Time is O(n) but space is not discussed."},
+		{Criterion: "Edge cases", Score: 1, Comment: "Does not say what happens with no answer."},
+	}
+	for i, score := range grade.Scores {
+		if score != want[i] {
+			t.Errorf("score %d = %+v, want %+v", i, score, want[i])
+		}
+	}
+	if grade.Feedback != "Solid approach. State the space complexity next time." {
+		t.Errorf("feedback = %q", grade.Feedback)
+	}
+	if !grade.Passed() {
+		t.Error("a correct solution meeting every criterion should pass")
+	}
+
+	if _, err := ParseGrade("CORRECTNESS: 2/2 - fine"); err == nil {
+		t.Error("expected an error for a reply missing criteria")
+	}
+}
+
+func TestGradePassed(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []int
+		want   bool
+	}{
+		{"AllFull", []int{2, 2, 2, 2}, true},
+		{"PartlyCorrect", []int{1, 2, 2, 2}, false},
+		{"CriterionMissed", []int{2, 2, 0, 2}, false},
+		{"Incomplete", []int{2, 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade := &Grade{}
+			for i, score := range tt.scores {
+				grade.Scores = append(grade.Scores, RubricScore{Criterion: Rubric[i].Name, Score: score})
+			}
+			if got := grade.Passed(); got != tt.want {
+				t.Errorf("Passed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradePseudoCode(t *testing.T) {
+	prob := problem.Problem{
+		ID:          "two_sum",
+		Title:       "Two Sum",
+		Description: "Find two numbers that add up to target",
+		Solutions:   map[string]string{"rust": "fn two_sum() {}", "python": "def two_sum(): pass"},
+	}
+	agent := &replyAgent{reply: "CORRECTNESS: 2/2 - ok\nAPPROACH: 2/2 - ok\nCOMPLEXITY: 2/2 - ok\nEDGE CASES: 0/2 - none\nFEEDBACK: Cover empty input."}
+
+	grade, err := GradePseudoCode(context.Background(), agent, prob, "for each num: look up target - num in seen")
+	if err != nil {
+		t.Fatalf("GradePseudoCode failed: %v", err)
+	}
+	if grade.Passed() {
+		t.Error("a grade missing a criterion should not pass")
+	}
+	for _, expected := range []string{"Two Sum", "look up target - num in seen", "def two_sum(): pass", "EDGE CASES: <score>/2"} {
+		if !strings.Contains(agent.prompt, expected) {
+			t.Errorf("Prompt missing expected content: %s", expected)
+		}
+	}
+	if strings.Contains(agent.prompt, "fn two_sum") {
+		t.Error("prompt should only include one reference solution")
+	}
+}
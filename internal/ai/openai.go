@@ -0,0 +1,257 @@
+//go:build !lite
+
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// OpenAIProvider implements the Agent interface for OpenAI's chat
+// completions API and compatible servers such as Groq or LM Studio
+type OpenAIProvider struct {
+	config     OpenAIConfig
+	client     *http.Client
+	apiBaseURL string
+	prompts    *PromptBuilder
+}
+
+// NewOpenAIProvider creates a new OpenAI-compatible provider
+func NewOpenAIProvider(config OpenAIConfig) (*OpenAIProvider, error) {
+	// Set default values
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.openai.com/v1"
+	}
+	if config.Model == "" {
+		config.Model = "gpt-4o-mini"
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if config.Temperature == 0 {
+		config.Temperature = 0.7
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 120
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(config.Timeout) * time.Second,
+	}
+
+	return &OpenAIProvider{
+		config:     config,
+		client:     client,
+		apiBaseURL: strings.TrimRight(config.BaseURL, "/"),
+		prompts:    NewPromptBuilder(),
+	}, nil
+}
+
+// Chat implements the Agent interface, streaming the reply as server-sent
+// events
+func (o *OpenAIProvider) Chat(ctx context.Context, messages []Message, opts ChatOptions) (<-chan ChatResponse, error) {
+	respChan := make(chan ChatResponse)
+
+	go func() {
+		defer close(respChan)
+
+		openaiMessages := make([]openaiMessage, len(messages))
+		for i, msg := range messages {
+			openaiMessages[i] = openaiMessage{Role: msg.Role, Content: msg.Content}
+		}
+
+		temperature := opts.Temperature
+		if temperature == 0 {
+			temperature = o.config.Temperature
+		}
+		reqBody := openaiChatRequest{
+			Model:       o.config.Model,
+			Messages:    openaiMessages,
+			Temperature: temperature,
+			MaxTokens:   opts.MaxTokens,
+			Stream:      true, // Always stream for real-time responses
+		}
+
+		reqData, err := json.Marshal(reqBody)
+		if err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("failed to marshal request: %w", err)}
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", o.apiBaseURL+"/chat/completions", bytes.NewReader(reqData))
+		if err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("failed to create request: %w", err)}
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		// Local servers such as LM Studio need no key
+		if o.config.APIKey != "" {
+			req.Header.Set("Authorization", "Bearer "+o.config.APIKey)
+		}
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("request failed: %w", err)}
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			respChan <- ChatResponse{Error: fmt.Errorf("openai API error: %s (status %d)", openaiErrorMessage(body), resp.StatusCode)}
+			return
+		}
+
+		// Each event is a "data:" line holding a JSON chunk; "[DONE]" ends
+		// the stream
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				respChan <- ChatResponse{Done: true}
+				return
+			}
+
+			var chunk openaiStreamChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				respChan <- ChatResponse{Error: fmt.Errorf("failed to decode response: %w", err)}
+				return
+			}
+			if chunk.Error != nil {
+				respChan <- ChatResponse{Error: fmt.Errorf("openai API error: %s", chunk.Error.Message)}
+				return
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Delta.Content != "" {
+					respChan <- ChatResponse{Content: choice.Delta.Content}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			respChan <- ChatResponse{Error: fmt.Errorf("failed to read response: %w", err)}
+			return
+		}
+		// Some compatible servers close the stream without [DONE]
+		respChan <- ChatResponse{Done: true}
+	}()
+
+	return respChan, nil
+}
+
+// GetHint implements progressive hint generation
+func (o *OpenAIProvider) GetHint(ctx context.Context, prob problem.Problem, userCode string, level int) (<-chan string, error) {
+	prompt, err := o.prompts.BuildHintPrompt(prob, userCode, level)
+	if err != nil {
+		return nil, err
+	}
+	return o.stream(ctx, NewSystemPrompts().GetTutorPrompt(), prompt, "Error generating hint")
+}
+
+// ReviewCode provides AI-powered code review
+func (o *OpenAIProvider) ReviewCode(ctx context.Context, prob problem.Problem, code string) (<-chan string, error) {
+	prompt, err := o.prompts.BuildReviewPrompt(prob, code, "go")
+	if err != nil {
+		return nil, err
+	}
+	return o.stream(ctx, NewSystemPrompts().GetReviewerPrompt(), prompt, "Error generating review")
+}
+
+// ExplainPattern provides detailed pattern explanations
+func (o *OpenAIProvider) ExplainPattern(ctx context.Context, pattern string, examples []problem.Problem) (<-chan string, error) {
+	if len(examples) > 3 {
+		examples = examples[:3] // Limit to 3 examples
+	}
+	prompt, err := o.prompts.BuildPatternPrompt(pattern, examples)
+	if err != nil {
+		return nil, err
+	}
+	return o.stream(ctx, NewSystemPrompts().GetTutorPrompt(), prompt, "Error generating explanation")
+}
+
+// stream sends a single-turn conversation and forwards the reply text as it
+// arrives. Errors are reported in-band, prefixed with errPrefix.
+func (o *OpenAIProvider) stream(ctx context.Context, systemPrompt, userPrompt, errPrefix string) (<-chan string, error) {
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+
+		respChan, err := o.Chat(ctx, []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		}, ChatOptions{Stream: true})
+		if err != nil {
+			out <- fmt.Sprintf("%s: %v", errPrefix, err)
+			return
+		}
+
+		for resp := range respChan {
+			if resp.Error != nil {
+				out <- fmt.Sprintf("Error: %v", resp.Error)
+				return
+			}
+			if resp.Content != "" {
+				out <- resp.Content
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// openaiErrorMessage extracts the message from an API error body, falling
+// back to the raw body
+func openaiErrorMessage(body []byte) string {
+	var parsed struct {
+		Error *openaiError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error != nil && parsed.Error.Message != "" {
+		return parsed.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// OpenAI API types
+
+type openaiMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openaiChatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openaiMessage `json:"messages"`
+	Temperature float64         `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream"`
+}
+
+type openaiStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *openaiError `json:"error,omitempty"`
+}
+
+type openaiError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
@@ -0,0 +1,86 @@
+//go:build !lite
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+func TestOpenAIChatStreams(t *testing.T) {
+	var got openaiChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk-test" {
+			t.Errorf("unexpected Authorization %q", auth)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, piece := range []string{"Use a ", "hash map."} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", piece)
+		}
+		fmt.Fprint(w, ": keep-alive\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider, err := NewOpenAIProvider(OpenAIConfig{BaseURL: server.URL + "/v1/", APIKey: "sk-test", Model: "llama-3.1-8b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	respChan, err := provider.Chat(context.Background(), []Message{{Role: "user", Content: "hint?"}}, ChatOptions{MaxTokens: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text strings.Builder
+	done := false
+	for resp := range respChan {
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %v", resp.Error)
+		}
+		text.WriteString(resp.Content)
+		done = done || resp.Done
+	}
+	if text.String() != "Use a hash map." || !done {
+		t.Errorf("got %q (done %v)", text.String(), done)
+	}
+	if got.Model != "llama-3.1-8b" || !got.Stream || got.MaxTokens != 100 || got.Temperature != 0.7 {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("no key should be sent when none is configured")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("OPENAI_API_KEY", "")
+	provider, _ := NewOpenAIProvider(OpenAIConfig{BaseURL: server.URL})
+	hintChan, err := provider.GetHint(context.Background(), problem.Problem{Title: "Two Sum"}, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text strings.Builder
+	for chunk := range hintChan {
+		text.WriteString(chunk)
+	}
+	if !strings.Contains(text.String(), "Incorrect API key provided (status 401)") {
+		t.Errorf("expected the API error message, got %q", text.String())
+	}
+}
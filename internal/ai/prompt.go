@@ -91,11 +91,94 @@ Provide:
 5. Complexity analysis
 6. Testing strategy`
 
+	// Problem summary template
+	summaryTemplate := `Summarize the "{{.Problem.Title}}" problem so a student can check their own restatement of it.
+
+Problem statement:
+{{.Problem.Description}}
+{{if .Problem.Constraints}}
+Constraints:
+{{range .Problem.Constraints}}- {{.}}
+{{end}}{{end}}
+Reply in exactly this format and nothing else:
+
+SUMMARY:
+<exactly three plain sentences: the input, the required output, and what makes the problem tricky>
+
+QUESTIONS:
+- <a clarifying question a strong candidate would ask the interviewer>
+- <3 to 6 questions in total, about edge cases, input limits and expected behavior>
+
+Do not hint at the algorithm or pattern to use.`
+
+	// Clarifying question template
+	clarifyTemplate := `A candidate solving "{{.Problem.Title}}" asks you a clarifying question before coding.
+
+Problem statement:
+{{.Problem.Description}}
+{{if .Problem.Constraints}}
+Constraints:
+{{range .Problem.Constraints}}- {{.}}
+{{end}}{{end}}
+Question: {{.Question}}
+
+Answer in one or two sentences, as the interviewer. If the problem does not settle the question, say so and state the assumption the candidate should make.
+Do not hint at the algorithm or pattern to use.`
+
+	// Pseudo-code grading template
+	gradeTemplate := `Grade this whiteboard-style pseudo-code for "{{.Problem.Title}}". It is not meant to run, so do not judge syntax.
+
+Problem statement:
+{{.Problem.Description}}
+{{if .Problem.Constraints}}
+Constraints:
+{{range .Problem.Constraints}}- {{.}}
+{{end}}{{end}}{{if .Problem.SolutionWalkthrough}}
+Reference approach:
+{{range .Problem.SolutionWalkthrough}}- {{.}}
+{{end}}{{end}}{{if .Reference}}
+Reference solution:
+` + "```{{.ReferenceLanguage}}\n{{.Reference}}\n```" + `
+{{end}}
+Pseudo-code:
+` + "```\n{{.Code}}\n```" + `
+
+Score each criterion 0 (not met), 1 (partly met) or {{.MaxScore}} (fully met):
+{{range .Rubric}}- {{.Name}}: {{.Description}}
+{{end}}
+Reply in exactly this format and nothing else:
+
+{{range .Rubric}}{{.Name | upper}}: <score>/{{$.MaxScore}} - <one sentence explaining the score>
+{{end}}FEEDBACK: <two or three sentences on what to improve>`
+
+	// Problem generation template
+	generateTemplate := `Write a new {{.Difficulty}} coding interview problem that is solved with the "{{.Pattern}}" pattern.
+{{if .Avoid}}
+It must differ from these existing problems:
+{{range .Avoid}}- {{.}}
+{{end}}{{end}}
+Reply with a single JSON object and nothing else, with these fields:
+- "id": snake_case identifier
+- "title", "description" (the statement, without naming the pattern), "estimated_time" (minutes)
+- "examples": 2 or 3 objects with "input", "output" and "explanation"
+- "constraints": list of strings
+- "pattern_explanation": why the pattern fits this problem, in 2 or 3 sentences
+- "solution_walkthrough": list of steps
+- "signature": {"name": camelCase function name, "params": [{"name", "type"}], "returns": type, "unordered": true only if an array result may be in any order}. Types are int, float, bool, string, char, arrays of them such as int[] and int[][], TreeNode and ListNode.
+- "starter_code" and "solutions": objects with one key, "{{.Language}}": a function stub and a correct, efficient reference solution calling the signature's function name ({{.Language}} naming conventions apply)
+- "test_cases": at least 8 objects with "input", "expected" and "tier" (example, hidden or edge). "input" is a string of the arguments as JSON values separated by ", " in parameter order, such as "[2,7,11,15], 9", and "expected" a string of the return value as JSON, such as "[0,1]".
+
+Every test case must be one the reference solution passes. Include edge cases such as empty and single-element inputs where the constraints allow them.`
+
 	// Load templates
 	pb.templates["hint"] = template.Must(template.New("hint").Parse(hintTemplate))
 	pb.templates["review"] = template.Must(template.New("review").Parse(reviewTemplate))
 	pb.templates["pattern"] = template.Must(template.New("pattern").Parse(patternTemplate))
 	pb.templates["walkthrough"] = template.Must(template.New("walkthrough").Parse(walkthroughTemplate))
+	pb.templates["summary"] = template.Must(template.New("summary").Parse(summaryTemplate))
+	pb.templates["clarify"] = template.Must(template.New("clarify").Parse(clarifyTemplate))
+	pb.templates["generate"] = template.Must(template.New("generate").Parse(generateTemplate))
+	pb.templates["grade"] = template.Must(template.New("grade").Funcs(template.FuncMap{"upper": strings.ToUpper}).Parse(gradeTemplate))
 }
 
 // BuildHintPrompt creates a hint prompt
@@ -135,6 +218,60 @@ func (pb *PromptBuilder) BuildWalkthroughPrompt(prob problem.Problem) (string, e
 	return pb.executeTemplate("walkthrough", data)
 }
 
+// BuildSummaryPrompt creates a prompt for a problem summary and
+// clarifying questions
+func (pb *PromptBuilder) BuildSummaryPrompt(prob problem.Problem) (string, error) {
+	data := map[string]interface{}{
+		"Problem": prob,
+	}
+	return pb.executeTemplate("summary", data)
+}
+
+// BuildClarifyPrompt creates a prompt answering a clarifying question about
+// a problem
+func (pb *PromptBuilder) BuildClarifyPrompt(prob problem.Problem, question string) (string, error) {
+	data := map[string]interface{}{
+		"Problem":  prob,
+		"Question": question,
+	}
+	return pb.executeTemplate("clarify", data)
+}
+
+// BuildGradePrompt creates a prompt grading pseudo-code against the
+// problem's reference solutions
+func (pb *PromptBuilder) BuildGradePrompt(prob problem.Problem, code string) (string, error) {
+	data := map[string]interface{}{
+		"Problem":  prob,
+		"Code":     code,
+		"Rubric":   Rubric,
+		"MaxScore": MaxRubricScore,
+	}
+	for _, language := range referenceLanguages {
+		if solution := prob.Solutions[language]; solution != "" {
+			data["Reference"], data["ReferenceLanguage"] = solution, language
+			break
+		}
+	}
+	return pb.executeTemplate("grade", data)
+}
+
+// BuildGeneratePrompt creates a prompt for a new problem of a pattern and
+// difficulty, with a reference solution in language, unlike the problems
+// titled in avoid
+func (pb *PromptBuilder) BuildGeneratePrompt(pattern, difficulty, language string, avoid []string) (string, error) {
+	data := map[string]interface{}{
+		"Pattern":    pattern,
+		"Difficulty": difficulty,
+		"Language":   language,
+		"Avoid":      avoid,
+	}
+	return pb.executeTemplate("generate", data)
+}
+
+// referenceLanguages orders the languages a reference solution is taken
+// from for grading, those reading most like pseudo-code first
+var referenceLanguages = []string{"python", "go", "javascript", "typescript", "java", "cpp", "rust"}
+
 // executeTemplate executes a template with the given data
 func (pb *PromptBuilder) executeTemplate(name string, data interface{}) (string, error) {
 	tmpl, ok := pb.templates[name]
@@ -0,0 +1,33 @@
+//go:build !lite
+
+package ai
+
+import "fmt"
+
+// newProvider creates the agent for a provider
+func newProvider(provider Provider, config *Config) (Agent, error) {
+	switch provider {
+	case ProviderClaude:
+		if config.Claude == nil {
+			return nil, fmt.Errorf("claude configuration not found")
+		}
+		return NewClaudeProvider(*config.Claude)
+	case ProviderOllama:
+		if config.Ollama == nil {
+			return nil, fmt.Errorf("ollama configuration not found")
+		}
+		return NewOllamaProvider(*config.Ollama)
+	case ProviderOpenAI:
+		if config.OpenAI == nil {
+			return nil, fmt.Errorf("openai configuration not found")
+		}
+		return NewOpenAIProvider(*config.OpenAI)
+	case ProviderAnthropic:
+		if config.Anthropic == nil {
+			return nil, fmt.Errorf("anthropic configuration not found")
+		}
+		return NewAnthropicProvider(*config.Anthropic)
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", provider)
+	}
+}
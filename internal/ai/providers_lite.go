@@ -0,0 +1,9 @@
+//go:build lite
+
+package ai
+
+// newProvider is never reached in lite builds, which leave the providers
+// out; NewAgent reports the AI assistant as unavailable first
+func newProvider(provider Provider, config *Config) (Agent, error) {
+	return nil, ErrInvalidProvider
+}
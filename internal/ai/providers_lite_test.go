@@ -0,0 +1,17 @@
+//go:build lite
+
+package ai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/features"
+)
+
+func TestNewAgent_Lite(t *testing.T) {
+	config := &Config{Ollama: &OllamaConfig{Host: "http://localhost:11434", Model: "llama3"}}
+	if _, err := NewAgent(ProviderOllama, config); !errors.Is(err, features.ErrUnavailable) {
+		t.Fatalf("expected the AI assistant to be unavailable, got %v", err)
+	}
+}
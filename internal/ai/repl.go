@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -11,20 +12,39 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/problem"
-	"github.com/lancekrogers/claude-code-go/pkg/claude"
 )
 
 // REPL provides an interactive chat interface for AI assistance
 type REPL struct {
-	agent        Agent
-	claudeClient *claude.ClaudeClient // Direct client for Claude provider
-	sessionID    string               // Track conversation session
-	context      []Message
-	style        REPLStyle
-	usingClaude  bool
-	problem      *problem.Problem // Current problem context
+	agent      Agent
+	sessionID  string // Track conversation session
+	context    []Message
+	style      REPLStyle
+	problem    *problem.Problem // Current problem context
+	mode       REPLMode
+	turnBudget int       // Interviewer replies before the interview wraps up
+	turns      int       // Interviewer replies so far
+	input      io.Reader // Where the user's messages are read from
 }
 
+// REPLMode selects the persona the AI plays in the REPL
+type REPLMode string
+
+const (
+	// TutorMode guides the student with hints and explanations
+	TutorMode REPLMode = "tutor"
+	// InterviewerMode runs a mock interview: the AI answers clarifying
+	// questions, asks for complexity analysis and withholds the solution
+	InterviewerMode REPLMode = "interviewer"
+)
+
+// DefaultInterviewTurns is the interviewer's default turn budget
+const DefaultInterviewTurns = 15
+
+// complexityReminderTurns is how many interviewer turns before the end the
+// interviewer is reminded to ask for a complexity analysis
+const complexityReminderTurns = 3
+
 // REPLStyle defines the visual styling for the REPL
 type REPLStyle struct {
 	User      lipgloss.Style
@@ -38,8 +58,11 @@ type REPLStyle struct {
 // NewREPL creates a new REPL instance
 func NewREPL(agent Agent) *REPL {
 	repl := &REPL{
-		agent:   agent,
-		context: []Message{},
+		agent:      agent,
+		context:    []Message{},
+		mode:       TutorMode,
+		turnBudget: DefaultInterviewTurns,
+		input:      os.Stdin,
 		style: REPLStyle{
 			User:      lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true),
 			Assistant: lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
@@ -50,22 +73,46 @@ func NewREPL(agent Agent) *REPL {
 		},
 	}
 
-	// Check if we're using Claude provider to leverage streaming
-	if provider, ok := agent.(*ClaudeProvider); ok {
-		repl.usingClaude = true
-		repl.claudeClient = provider.client
-		repl.sessionID = provider.sessionID
+	// Continue the provider's conversation if it keeps one
+	if provider, ok := agent.(interface{ SessionID() string }); ok {
+		repl.sessionID = provider.SessionID()
 	}
 
 	return repl
 }
 
+// WithMode sets the persona the AI plays
+func (r *REPL) WithMode(mode REPLMode) *REPL {
+	r.mode = mode
+	return r
+}
+
+// WithTurnBudget sets how many replies the interviewer gives before it wraps
+// up the interview. The tutor has no budget.
+func (r *REPL) WithTurnBudget(turns int) *REPL {
+	r.turnBudget = turns
+	return r
+}
+
+// WithInput sets where the user's messages are read from
+func (r *REPL) WithInput(input io.Reader) *REPL {
+	r.input = input
+	return r
+}
+
 // Start begins an interactive chat session
 func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 	r.problem = prob
+	interviewing := r.mode == InterviewerMode
+	if interviewing && prob == nil {
+		return fmt.Errorf("interviewer mode needs a problem")
+	}
 
 	// Build system context
 	systemPrompt := r.buildSystemPrompt(prob)
+	if interviewing {
+		systemPrompt = r.buildInterviewerPrompt(prob)
+	}
 
 	// Set up signal handling for graceful exit
 	sigChan := make(chan os.Signal, 1)
@@ -89,6 +136,13 @@ func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 	}
 	fmt.Println()
 
+	if interviewing {
+		fmt.Println(r.style.System.Render(fmt.Sprintf(
+			"🎤 Mock interview: hints are off and the interviewer has %d replies before wrapping up.", r.turnBudget)))
+		fmt.Println()
+		r.send(ctx, systemPrompt, "Please start the interview.")
+	}
+
 	// Exit commands based on claude-code-go demo
 	exitCommands := []string{
 		"exit", "quit", "bye", "goodbye", "q", ":q", ":quit", ":exit",
@@ -96,7 +150,7 @@ func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 		"/exit", "/quit", "\\q", "\\quit", // Common variations
 	}
 
-	scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(r.input)
 	for {
 		fmt.Print(r.style.User.Render("You> "))
 
@@ -129,6 +183,12 @@ func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 			return nil
 		}
 
+		// The interviewer gives no hints; questions go to the interviewer instead
+		if interviewing && (strings.HasPrefix(lowInput, "hint") || lowInput == "pattern") {
+			fmt.Println(r.style.System.Render("Hints are off in an interview. Ask the interviewer a question instead."))
+			continue
+		}
+
 		// Handle other commands
 		switch lowInput {
 		case "help", "h", "?":
@@ -137,6 +197,7 @@ func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 		case "clear", "reset":
 			r.context = []Message{}
 			r.sessionID = ""
+			r.turns = 0
 			fmt.Println(r.style.System.Render("Conversation cleared."))
 			continue
 		case "code":
@@ -183,72 +244,126 @@ func (r *REPL) Start(ctx context.Context, prob *problem.Problem) error {
 		}
 
 		// Regular chat message
-		fmt.Print(r.style.Assistant.Render("Assistant> "))
+		if !interviewing {
+			r.send(ctx, systemPrompt, input)
+			continue
+		}
+
+		r.turns++
+		r.send(ctx, systemPrompt, input+interviewerNote(r.turnBudget-r.turns))
+		if r.turns >= r.turnBudget {
+			fmt.Println(r.style.System.Render("⏰ That's time for this interview."))
+			r.send(ctx, systemPrompt, "We are out of time. Stop the interview and give the candidate your final assessment: "+
+				"problem understanding, approach, code quality, complexity analysis and communication, with one thing to improve.")
+			return nil
+		}
+	}
+
+	return nil
+}
 
-		// Add to context
-		userMsg := Message{Role: "user", Content: input}
-		r.context = append(r.context, userMsg)
+// Helper methods
+
+// send adds a user message to the conversation and streams the reply
+func (r *REPL) send(ctx context.Context, systemPrompt, content string) {
+	label, maxTokens := "Assistant> ", 2048
+	if r.mode == InterviewerMode {
+		// Interviewers talk in short turns
+		label, maxTokens = "Interviewer> ", 1024
+	}
+	fmt.Print(r.style.Assistant.Render(label))
 
-		// Prepare messages with system prompt
-		messages := append([]Message{{Role: "system", Content: systemPrompt}}, r.context...)
+	// Add to context
+	r.context = append(r.context, Message{Role: "user", Content: content})
 
-		// Get response
-		respChan, err := r.agent.Chat(ctx, messages, ChatOptions{
-			Temperature: 0.7,
-			MaxTokens:   2048,
-			Stream:      true,
-		})
+	// Prepare messages with system prompt
+	messages := append([]Message{{Role: "system", Content: systemPrompt}}, r.context...)
 
-		if err != nil {
-			fmt.Println(r.style.Error.Render(fmt.Sprintf("\nError: %v", err)))
+	// Get response
+	respChan, err := r.agent.Chat(ctx, messages, ChatOptions{
+		Temperature: 0.7,
+		MaxTokens:   maxTokens,
+		Stream:      true,
+	})
+
+	if err != nil {
+		fmt.Println(r.style.Error.Render(fmt.Sprintf("\nError: %v", err)))
+		return
+	}
+
+	// Process streaming response
+	var fullResponse strings.Builder
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Println(r.style.Error.Render(fmt.Sprintf("\nError: %v", resp.Error)))
+			break
+		}
+
+		// Handle special content (tool usage, etc.)
+		if strings.HasPrefix(resp.Content, "[Using tool:") {
+			fmt.Println()
+			fmt.Println(r.style.Tool.Render(resp.Content))
 			continue
 		}
 
-		// Process streaming response
-		var fullResponse strings.Builder
-		for resp := range respChan {
-			if resp.Error != nil {
-				fmt.Println(r.style.Error.Render(fmt.Sprintf("\nError: %v", resp.Error)))
-				break
-			}
+		// Stream the response
+		fmt.Print(resp.Content)
+		fullResponse.WriteString(resp.Content)
 
-			// Handle special content (tool usage, etc.)
-			if strings.HasPrefix(resp.Content, "[Using tool:") {
-				fmt.Println()
-				fmt.Println(r.style.Tool.Render(resp.Content))
-				continue
+		// Handle completion
+		if resp.Done {
+			fmt.Println()
+			if resp.SessionID != "" {
+				r.sessionID = resp.SessionID
 			}
-
-			// Stream the response
-			fmt.Print(resp.Content)
-			fullResponse.WriteString(resp.Content)
-
-			// Handle completion
-			if resp.Done {
-				fmt.Println()
-				if resp.SessionID != "" {
-					r.sessionID = resp.SessionID
-				}
-				if resp.Cost > 0 {
-					fmt.Println(r.style.Cost.Render(fmt.Sprintf("💰 Cost: $%.4f", resp.Cost)))
-				}
+			if resp.Cost > 0 {
+				fmt.Println(r.style.Cost.Render(fmt.Sprintf("💰 Cost: $%.4f", resp.Cost)))
 			}
 		}
-		fmt.Println()
+	}
+	fmt.Println()
 
-		// Save assistant response to context
-		if fullResponse.Len() > 0 {
-			r.context = append(r.context, Message{
-				Role:    "assistant",
-				Content: fullResponse.String(),
-			})
-		}
+	// Save assistant response to context
+	if fullResponse.Len() > 0 {
+		r.context = append(r.context, Message{
+			Role:    "assistant",
+			Content: fullResponse.String(),
+		})
 	}
+}
 
-	return nil
+// interviewerNote steers the interviewer as its turn budget runs down
+func interviewerNote(remaining int) string {
+	if remaining == complexityReminderTurns {
+		return fmt.Sprintf("\n\n[Interviewer note: %d replies remain. If the candidate has not yet analyzed "+
+			"the time and space complexity of their approach, ask them to now.]", remaining)
+	}
+	return ""
 }
 
-// Helper methods
+// buildInterviewerPrompt is the system prompt of the mock interviewer
+func (r *REPL) buildInterviewerPrompt(prob *problem.Problem) string {
+	return fmt.Sprintf(
+		`%s
+
+You are interviewing the candidate on the problem "%s" (%s).
+
+Problem Description: %s
+
+How to run the interview:
+- Open by presenting the problem briefly in your own words and inviting clarifying questions.
+- Answer clarifying questions precisely and briefly. If the candidate starts coding without clarifying inputs, limits or edge cases, ask what assumptions they are making.
+- Ask the candidate to describe their approach before they code, and to walk through an example.
+- Once they have an approach or code, ask for its time and space complexity and press on any hand-waving.
+- Ask about edge cases and whether the approach could be improved.
+
+What NOT to do:
+- Never reveal the solution, name the intended algorithm or pattern, or write code for the candidate.
+- Don't give hints unprompted. If the candidate is truly stuck, ask a guiding question instead.
+- Keep each reply short, like a spoken turn in an interview, and ask one thing at a time.`,
+		NewSystemPrompts().GetInterviewerPrompt(), prob.Title, prob.Difficulty, prob.Description,
+	)
+}
 
 func (r *REPL) buildSystemPrompt(prob *problem.Problem) string {
 	if prob == nil {
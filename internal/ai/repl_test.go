@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// scriptedAgent records chats and hint requests
+type scriptedAgent struct {
+	Agent
+	chats [][]Message
+	hints int
+}
+
+func (a *scriptedAgent) Chat(ctx context.Context, messages []Message, opts ChatOptions) (<-chan ChatResponse, error) {
+	a.chats = append(a.chats, messages)
+	responses := make(chan ChatResponse, 1)
+	responses <- ChatResponse{Content: "What are the input limits?", Done: true}
+	close(responses)
+	return responses, nil
+}
+
+func (a *scriptedAgent) GetHint(ctx context.Context, prob problem.Problem, userCode string, level int) (<-chan string, error) {
+	a.hints++
+	hints := make(chan string)
+	close(hints)
+	return hints, nil
+}
+
+func TestREPLInterviewerMode(t *testing.T) {
+	agent := &scriptedAgent{}
+	prob := &problem.Problem{ID: "two_sum", Title: "Two Sum", Difficulty: "easy", Description: "Find two numbers that add up to target"}
+	input := "Can numbers repeat?\nhint\nI'll use a hash map.\nHere is my code.\nIt's linear.\nDone coding.\n"
+
+	repl := NewREPL(agent).WithMode(InterviewerMode).WithTurnBudget(5).WithInput(strings.NewReader(input))
+	if err := repl.Start(context.Background(), prob); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if agent.hints != 0 {
+		t.Errorf("interviewer gave %d hints", agent.hints)
+	}
+	// Opening, five candidate turns, then the final assessment
+	if len(agent.chats) != 7 {
+		t.Fatalf("got %d chats, want 7", len(agent.chats))
+	}
+
+	system := agent.chats[0][0]
+	if system.Role != "system" || !strings.Contains(system.Content, "Never reveal the solution") || !strings.Contains(system.Content, "Two Sum") {
+		t.Errorf("unexpected interviewer system prompt: %q", system.Content)
+	}
+
+	last := func(i int) string {
+		messages := agent.chats[i]
+		return messages[len(messages)-1].Content
+	}
+	if last(0) != "Please start the interview." {
+		t.Errorf("opening message = %q", last(0))
+	}
+	if !strings.Contains(last(1), "Can numbers repeat?") || strings.Contains(last(1), "Interviewer note") {
+		t.Errorf("first turn = %q", last(1))
+	}
+	// With three replies left the interviewer is reminded to ask about complexity
+	if !strings.Contains(last(2), "time and space complexity") {
+		t.Errorf("second turn should carry the complexity reminder: %q", last(2))
+	}
+	if !strings.Contains(last(6), "final assessment") {
+		t.Errorf("interview should end with an assessment: %q", last(5))
+	}
+}
+
+func TestREPLInterviewerNeedsProblem(t *testing.T) {
+	repl := NewREPL(&scriptedAgent{}).WithMode(InterviewerMode).WithInput(strings.NewReader(""))
+	if err := repl.Start(context.Background(), nil); err == nil {
+		t.Error("expected an error without a problem")
+	}
+}
@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// Summarize asks the agent for a short summary of a problem and a checklist
+// of clarifying questions
+func Summarize(ctx context.Context, agent Agent, prob problem.Problem) (*problem.Summary, error) {
+	prompt, err := NewPromptBuilder().BuildSummaryPrompt(prob)
+	if err != nil {
+		return nil, err
+	}
+	messages := []Message{
+		{Role: "system", Content: NewSystemPrompts().GetTutorPrompt()},
+		{Role: "user", Content: prompt},
+	}
+	responses, err := agent.Chat(ctx, messages, ChatOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		reply.WriteString(resp.Content)
+	}
+
+	summary, questions, err := ParseSummary(reply.String())
+	if err != nil {
+		return nil, err
+	}
+	return &problem.Summary{
+		ProblemID: prob.ID,
+		Version:   prob.DefinitionVersion(),
+		Summary:   summary,
+		Questions: questions,
+		Generated: time.Now(),
+	}, nil
+}
+
+// ParseSummary splits a reply to the summary prompt into the summary and
+// the clarifying questions
+func ParseSummary(reply string) (string, []string, error) {
+	var summary []string
+	var questions []string
+	section := ""
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := cutHeading(line, "SUMMARY"); ok {
+			section, line = "summary", rest
+		} else if rest, ok := cutHeading(line, "CLARIFYING QUESTIONS"); ok {
+			section, line = "questions", rest
+		} else if rest, ok := cutHeading(line, "QUESTIONS"); ok {
+			section, line = "questions", rest
+		}
+		if line == "" {
+			continue
+		}
+
+		switch section {
+		case "summary":
+			summary = append(summary, line)
+		case "questions":
+			question := strings.TrimSpace(strings.TrimLeft(line, "-*•0123456789.)"))
+			question = strings.TrimSpace(strings.TrimPrefix(question, "[ ]"))
+			if question != "" {
+				questions = append(questions, question)
+			}
+		}
+	}
+
+	if len(summary) == 0 {
+		return "", nil, fmt.Errorf("AI reply did not include a summary")
+	}
+	return strings.Join(summary, " "), questions, nil
+}
+
+// cutHeading reports whether line is the named section heading, tolerating
+// Markdown emphasis, and returns any text following it on the same line
+func cutHeading(line, name string) (string, bool) {
+	trimmed := strings.TrimLeft(line, "*# ")
+	if !strings.HasPrefix(strings.ToUpper(trimmed), name) {
+		return "", false
+	}
+	rest := strings.TrimLeft(trimmed[len(name):], "* ")
+	if !strings.HasPrefix(rest, ":") {
+		return "", rest == ""
+	}
+	return strings.TrimSpace(strings.TrimLeft(rest[1:], "* ")), true
+}
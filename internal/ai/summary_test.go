@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+func TestParseSummary(t *testing.T) {
+	reply := `**SUMMARY:** Given an array and a target, return the indices of two numbers.
+Exactly one answer exists.
+
+## Questions
+1. Can the same element be used twice?
+- [ ] Are the numbers sorted?
+* What should happen with negative numbers?
+`
+	summary, questions, err := ParseSummary(reply)
+	if err != nil {
+		t.Fatalf("ParseSummary failed: %v", err)
+	}
+	if want := "Given an array and a target, return the indices of two numbers. Exactly one answer exists."; summary != want {
+		t.Errorf("summary = %q, want %q", summary, want)
+	}
+	want := []string{"Can the same element be used twice?", "Are the numbers sorted?", "What should happen with negative numbers?"}
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("questions = %q, want %q", questions, want)
+	}
+
+	if _, _, err := ParseSummary("I can't help with that."); err == nil {
+		t.Error("expected an error for a reply without a summary")
+	}
+}
+
+// replyAgent answers every chat with a fixed reply
+type replyAgent struct {
+	Agent
+	reply  string
+	prompt string
+}
+
+func (a *replyAgent) Chat(ctx context.Context, messages []Message, opts ChatOptions) (<-chan ChatResponse, error) {
+	a.prompt = messages[len(messages)-1].Content
+	responses := make(chan ChatResponse, 1)
+	responses <- ChatResponse{Content: a.reply, Done: true}
+	close(responses)
+	return responses, nil
+}
+
+func TestSummarize(t *testing.T) {
+	prob := problem.Problem{ID: "two_sum", Title: "Two Sum", Description: "Find two numbers that add up to target", Constraints: []string{"One solution exists"}}
+	agent := &replyAgent{reply: "SUMMARY:\nReturn two indices.\n\nQUESTIONS:\n- Is the input sorted?"}
+
+	s, err := Summarize(context.Background(), agent, prob)
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if s.ProblemID != "two_sum" || s.Version != prob.DefinitionVersion() {
+		t.Errorf("summary not tied to the problem revision: %+v", s)
+	}
+	if s.Summary != "Return two indices." || len(s.Questions) != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	for _, expected := range []string{"Two Sum", "Find two numbers that add up to target", "- One solution exists", "QUESTIONS:"} {
+		if !strings.Contains(agent.prompt, expected) {
+			t.Errorf("Prompt missing expected content: %s", expected)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Package analytics renders every local statistic as a self-contained HTML
+// notebook. The charts are drawn by a small embedded script, and the page
+// loads nothing from the network, so it can be kept, shared or opened
+// offline without any server being involved.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// activityDays is how many days the daily activity chart covers
+const activityDays = 90
+
+// weeksShown is how many weeks the weekly success rate chart covers
+const weeksShown = 26
+
+// dateLayout formats the days and weeks of the charts
+const dateLayout = "2006-01-02"
+
+// Notebook is everything the HTML notebook shows
+type Notebook struct {
+	Generated    time.Time
+	Summary      Summary
+	Activity     []Day   // The last activityDays days, oldest first
+	Weeks        []Week  // The last weeksShown weeks, oldest first
+	Patterns     []Group // Most attempted first
+	Difficulties []Group // Easy, medium, hard, then any others
+	Contexts     []Group // Most attempted first
+	Retention    []stats.PatternRetention
+	Hints        hints.Report
+	Complexity   Complexity
+}
+
+// Summary is the whole practice history at a glance
+type Summary struct {
+	Sessions      int
+	Problems      int // Different problems attempted
+	Solved        int // Different problems solved
+	SuccessRate   float64
+	PracticeTime  time.Duration
+	AvgSolveTime  time.Duration
+	ActiveDays    int
+	CurrentStreak int // Days in a row with a session, up to today or yesterday
+	LongestStreak int
+	First, Last   time.Time
+}
+
+// Day is one day of practice
+type Day struct {
+	Date      string
+	Attempted int
+	Solved    int
+}
+
+// Week is one week of practice, starting on Monday
+type Week struct {
+	Start       string
+	Attempted   int
+	Solved      int
+	SuccessRate float64
+}
+
+// Group is the sessions of one pattern, difficulty or practice context
+type Group struct {
+	Name        string
+	Attempted   int
+	Solved      int
+	SuccessRate float64
+	AvgTime     time.Duration // Average time of the solved sessions
+}
+
+// Complexity counts the analyzed solutions by how their complexity compares
+// with the reference
+type Complexity struct {
+	Analyzed  int
+	Matching  int
+	Slower    int
+	MoreSpace int
+}
+
+// Collect loads the sessions from repo and builds a notebook from them and
+// the hint usage and complexity totals
+func Collect(ctx context.Context, repo storage.Repository, report hints.Report, complexity Complexity) (*Notebook, error) {
+	sessions, err := repo.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	return Build(sessions, report, complexity, time.Now()), nil
+}
+
+// Build assembles a notebook from already loaded data, as of now
+func Build(sessions []interfaces.SessionStats, report hints.Report, complexity Complexity, now time.Time) *Notebook {
+	nb := &Notebook{
+		Generated:  now,
+		Summary:    summarize(sessions, now),
+		Activity:   activity(sessions, now),
+		Weeks:      weeks(sessions, now),
+		Retention:  stats.ForecastRetention(sessions, now),
+		Hints:      report,
+		Complexity: complexity,
+	}
+	nb.Patterns = groupBy(sessions, func(s interfaces.SessionStats) []string { return s.Patterns })
+	nb.Difficulties = groupBy(sessions, func(s interfaces.SessionStats) []string { return []string{s.Difficulty} })
+	nb.Contexts = groupBy(sessions, func(s interfaces.SessionStats) []string {
+		if s.Context == "" {
+			return []string{stats.UnknownContext}
+		}
+		return []string{s.Context}
+	})
+	sortDifficulties(nb.Difficulties)
+	return nb
+}
+
+// summarize totals the practice history
+func summarize(sessions []interfaces.SessionStats, now time.Time) Summary {
+	var sum Summary
+	problems := make(map[string]bool)
+	solved := make(map[string]bool)
+	days := make(map[string]bool)
+	var solveTime time.Duration
+	solves := 0
+	for _, s := range sessions {
+		sum.Sessions++
+		sum.PracticeTime += s.Duration
+		problems[s.ProblemID] = true
+		days[s.StartTime.Local().Format(dateLayout)] = true
+		if s.Solved {
+			solved[s.ProblemID] = true
+			solveTime += s.Duration
+			solves++
+		}
+		if sum.First.IsZero() || s.StartTime.Before(sum.First) {
+			sum.First = s.StartTime
+		}
+		if s.StartTime.After(sum.Last) {
+			sum.Last = s.StartTime
+		}
+	}
+	sum.Problems, sum.Solved, sum.ActiveDays = len(problems), len(solved), len(days)
+	if sum.Sessions > 0 {
+		sum.SuccessRate = float64(solves) / float64(sum.Sessions) * 100
+	}
+	if solves > 0 {
+		sum.AvgSolveTime = solveTime / time.Duration(solves)
+	}
+	sum.CurrentStreak, sum.LongestStreak = streaks(days, now)
+	return sum
+}
+
+// streaks returns the current and longest runs of days with a session. The
+// current run still counts until a day passes without practice.
+func streaks(days map[string]bool, now time.Time) (int, int) {
+	var sorted []string
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Strings(sorted)
+
+	longest, run := 0, 0
+	var prev time.Time
+	for _, day := range sorted {
+		date, _ := time.Parse(dateLayout, day)
+		if run > 0 && date.Sub(prev) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+		prev = date
+	}
+
+	current := 0
+	today := now.Local()
+	if !days[today.Format(dateLayout)] {
+		today = today.AddDate(0, 0, -1)
+	}
+	for days[today.Format(dateLayout)] {
+		current++
+		today = today.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// activity counts the sessions of each of the last activityDays days
+func activity(sessions []interfaces.SessionStats, now time.Time) []Day {
+	index := make(map[string]int)
+	days := make([]Day, activityDays)
+	for i := range days {
+		date := now.Local().AddDate(0, 0, i-activityDays+1).Format(dateLayout)
+		days[i].Date = date
+		index[date] = i
+	}
+	for _, s := range sessions {
+		i, ok := index[s.StartTime.Local().Format(dateLayout)]
+		if !ok {
+			continue
+		}
+		days[i].Attempted++
+		if s.Solved {
+			days[i].Solved++
+		}
+	}
+	return days
+}
+
+// weeks counts the sessions of each of the last weeksShown weeks
+func weeks(sessions []interfaces.SessionStats, now time.Time) []Week {
+	thisWeek := startOfWeek(now.Local())
+	index := make(map[string]int)
+	out := make([]Week, weeksShown)
+	for i := range out {
+		start := thisWeek.AddDate(0, 0, 7*(i-weeksShown+1)).Format(dateLayout)
+		out[i].Start = start
+		index[start] = i
+	}
+	for _, s := range sessions {
+		i, ok := index[startOfWeek(s.StartTime.Local()).Format(dateLayout)]
+		if !ok {
+			continue
+		}
+		out[i].Attempted++
+		if s.Solved {
+			out[i].Solved++
+		}
+	}
+	for i := range out {
+		if out[i].Attempted > 0 {
+			out[i].SuccessRate = float64(out[i].Solved) / float64(out[i].Attempted) * 100
+		}
+	}
+	return out
+}
+
+// startOfWeek returns the Monday of t's week, at midnight
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// groupBy totals the sessions under each of the names keys gives them,
+// most attempted first. Sessions without a name are left out.
+func groupBy(sessions []interfaces.SessionStats, keys func(interfaces.SessionStats) []string) []Group {
+	groups := make(map[string]*Group)
+	solveTime := make(map[string]time.Duration)
+	for _, s := range sessions {
+		for _, name := range keys(s) {
+			if name == "" {
+				continue
+			}
+			g, ok := groups[name]
+			if !ok {
+				g = &Group{Name: name}
+				groups[name] = g
+			}
+			g.Attempted++
+			if s.Solved {
+				g.Solved++
+				solveTime[name] += s.Duration
+			}
+		}
+	}
+
+	out := make([]Group, 0, len(groups))
+	for name, g := range groups {
+		g.SuccessRate = float64(g.Solved) / float64(g.Attempted) * 100
+		if g.Solved > 0 {
+			g.AvgTime = solveTime[name] / time.Duration(g.Solved)
+		}
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Attempted != out[j].Attempted {
+			return out[i].Attempted > out[j].Attempted
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// difficultyOrder is the order difficulties are shown in
+var difficultyOrder = map[string]int{"easy": 0, "medium": 1, "hard": 2}
+
+// sortDifficulties puts easy, medium and hard first, then any others by name
+func sortDifficulties(groups []Group) {
+	rank := func(name string) int {
+		if r, ok := difficultyOrder[name]; ok {
+			return r
+		}
+		return len(difficultyOrder)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if rank(groups[i].Name) != rank(groups[j].Name) {
+			return rank(groups[i].Name) < rank(groups[j].Name)
+		}
+		return groups[i].Name < groups[j].Name
+	})
+}
@@ -0,0 +1,108 @@
+package analytics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSessions(now time.Time) []interfaces.SessionStats {
+	day := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	return []interfaces.SessionStats{
+		{ProblemID: "two_sum", StartTime: day(10), Duration: 20 * time.Minute, Solved: true, Patterns: []string{"hash-map"}, Difficulty: "easy", Context: interfaces.ContextCLI},
+		{ProblemID: "coin_change", StartTime: day(9), Duration: 40 * time.Minute, Patterns: []string{"dynamic-programming"}, Difficulty: "medium"},
+		{ProblemID: "coin_change", StartTime: day(1), Duration: 30 * time.Minute, Solved: true, Patterns: []string{"dynamic-programming"}, Difficulty: "medium", Context: interfaces.ContextTUI},
+		{ProblemID: "two_sum", StartTime: day(0), Duration: 10 * time.Minute, Solved: true, Patterns: []string{"hash-map"}, Difficulty: "easy", Context: interfaces.ContextTUI},
+		{ProblemID: "word_ladder", StartTime: day(0), Duration: time.Hour, Patterns: []string{"bfs"}, Difficulty: "hard", Context: interfaces.ContextTUI},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.Local)
+	nb := Build(testSessions(now), hints.Report{}, Complexity{}, now)
+
+	assert.Equal(t, Summary{
+		Sessions:      5,
+		Problems:      3,
+		Solved:        2,
+		SuccessRate:   60,
+		PracticeTime:  160 * time.Minute,
+		AvgSolveTime:  20 * time.Minute,
+		ActiveDays:    4,
+		CurrentStreak: 2,
+		LongestStreak: 2,
+		First:         now.AddDate(0, 0, -10),
+		Last:          now,
+	}, nb.Summary)
+
+	require.Len(t, nb.Activity, activityDays)
+	assert.Equal(t, Day{Date: "2026-03-11", Attempted: 2, Solved: 1}, nb.Activity[activityDays-1])
+	require.Len(t, nb.Weeks, weeksShown)
+	week := nb.Weeks[weeksShown-1]
+	assert.Equal(t, "2026-03-09", week.Start, "weeks start on Monday")
+	assert.Equal(t, 3, week.Attempted)
+	assert.Equal(t, 2, week.Solved)
+	assert.InDelta(t, 66.7, week.SuccessRate, 0.1)
+
+	assert.Equal(t, []Group{
+		{Name: "dynamic-programming", Attempted: 2, Solved: 1, SuccessRate: 50, AvgTime: 30 * time.Minute},
+		{Name: "hash-map", Attempted: 2, Solved: 2, SuccessRate: 100, AvgTime: 15 * time.Minute},
+		{Name: "bfs", Attempted: 1, SuccessRate: 0},
+	}, nb.Patterns, "most attempted first")
+
+	var difficulties, contexts []string
+	for _, g := range nb.Difficulties {
+		difficulties = append(difficulties, g.Name)
+	}
+	for _, g := range nb.Contexts {
+		contexts = append(contexts, g.Name)
+	}
+	assert.Equal(t, []string{"easy", "medium", "hard"}, difficulties)
+	assert.Equal(t, []string{interfaces.ContextTUI, interfaces.ContextCLI, stats.UnknownContext}, contexts)
+	assert.Len(t, nb.Retention, 3)
+}
+
+func TestStreaks(t *testing.T) {
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local)
+	days := map[string]bool{"2026-03-01": true, "2026-03-02": true, "2026-03-03": true, "2026-03-09": true, "2026-03-10": true}
+
+	current, longest := streaks(days, now)
+	assert.Equal(t, 2, current, "a streak lasts until a whole day passes without practice")
+	assert.Equal(t, 3, longest)
+
+	current, _ = streaks(days, now.AddDate(0, 0, 1))
+	assert.Equal(t, 0, current)
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.Local)
+	sessions := append(testSessions(now), interfaces.SessionStats{
+		ProblemID: "custom", StartTime: now, Patterns: []string{`<img src=x onerror="alert(1)">`},
+	})
+	nb := Build(sessions, hints.Report{Policy: config.HintPolicy{MaxHints: 2}, Hints: 4}, Complexity{Analyzed: 3, Matching: 2, Slower: 1}, now)
+
+	var page bytes.Buffer
+	require.NoError(t, Render(&page, nb))
+	html := page.String()
+
+	assert.Contains(t, html, "<!DOCTYPE html>")
+	assert.Contains(t, html, "var Charts =", "the chart script is inlined")
+	assert.Contains(t, html, `"label":"dynamic-programming"`)
+	assert.Contains(t, html, "2 hints per problem")
+	assert.Contains(t, html, "2 of 3 analyzed solutions match the reference")
+	assert.NotContains(t, html, "http", "the page loads nothing from the network")
+	assert.NotContains(t, html, "<img", "names from the data are escaped")
+}
+
+func TestRenderEmpty(t *testing.T) {
+	var page bytes.Buffer
+	require.NoError(t, Render(&page, Build(nil, hints.Report{}, Complexity{}, time.Now())))
+	assert.Contains(t, page.String(), "No solutions analyzed yet")
+}
@@ -0,0 +1,106 @@
+// Tiny SVG charts for the analytics notebook. The page must work offline,
+// so there are no dependencies: each chart is drawn into its element as
+// inline SVG. Points are {label, value, note}; the note is the tooltip.
+var Charts = (function () {
+  var W = 720;
+
+  function esc(s) {
+    return String(s).replace(/[&<>"]/g, function (c) {
+      return { "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;" }[c];
+    });
+  }
+
+  function draw(el, height, body) {
+    el.innerHTML = '<svg viewBox="0 0 ' + W + " " + height + '" width="100%" role="img">' + body + "</svg>";
+  }
+
+  function empty(el, points) {
+    if (points && points.length) {
+      return false;
+    }
+    el.innerHTML = '<p class="empty">Nothing recorded yet.</p>';
+    return true;
+  }
+
+  function top(points, opts) {
+    var max = opts.max || 0;
+    points.forEach(function (p) { max = Math.max(max, p.value); });
+    return max || 1;
+  }
+
+  function tip(p, unit) {
+    return "<title>" + esc(p.label + ": " + (p.note || p.value + (unit || ""))) + "</title>";
+  }
+
+  function text(x, y, s, cls, anchor) {
+    return '<text x="' + x + '" y="' + y + '" class="' + cls + '" text-anchor="' + (anchor || "start") + '">' + esc(s) + "</text>";
+  }
+
+  // columns draws a vertical bar per point, labelling every labelEvery-th
+  function columns(el, points, opts) {
+    opts = opts || {};
+    if (empty(el, points)) {
+      return;
+    }
+    var h = 180, left = 32, bottom = 24, plot = h - bottom - 8;
+    var max = top(points, opts), every = opts.labelEvery || 1;
+    var step = (W - left) / points.length, body = "";
+    body += text(left - 6, 14, max + (opts.unit || ""), "axis", "end");
+    body += '<line x1="' + left + '" y1="' + (h - bottom) + '" x2="' + W + '" y2="' + (h - bottom) + '" class="rule"/>';
+    points.forEach(function (p, i) {
+      var bh = p.value / max * plot, x = left + i * step;
+      body += '<rect class="bar" x="' + (x + step * 0.15) + '" y="' + (h - bottom - bh) + '" width="' + step * 0.7 + '" height="' + bh + '">' + tip(p, opts.unit) + "</rect>";
+      if (i % every === 0) {
+        body += text(x + step / 2, h - 8, p.label, "axis", "middle");
+      }
+    });
+    draw(el, h, body);
+  }
+
+  // bars draws a horizontal bar per point with its label and value, and a
+  // dashed line at opts.threshold if given
+  function bars(el, points, opts) {
+    opts = opts || {};
+    if (empty(el, points)) {
+      return;
+    }
+    var row = 24, left = 170, right = 70, h = points.length * row + 8;
+    var max = top(points, opts), width = W - left - right, body = "";
+    points.forEach(function (p, i) {
+      var y = 4 + i * row, bw = p.value / max * width;
+      body += text(left - 8, y + 16, p.label, "label", "end");
+      body += '<rect class="bar" x="' + left + '" y="' + (y + 4) + '" width="' + bw + '" height="' + (row - 8) + '">' + tip(p, opts.unit) + "</rect>";
+      body += text(left + bw + 6, y + 16, Math.round(p.value) + (opts.unit || ""), "value");
+    });
+    if (opts.threshold) {
+      var x = left + opts.threshold / max * width;
+      body += '<line x1="' + x + '" y1="0" x2="' + x + '" y2="' + h + '" class="threshold"/>';
+    }
+    draw(el, h, body);
+  }
+
+  // line draws the points joined up, labelling every labelEvery-th
+  function line(el, points, opts) {
+    opts = opts || {};
+    if (empty(el, points)) {
+      return;
+    }
+    var h = 180, left = 40, bottom = 24, plot = h - bottom - 12;
+    var max = top(points, opts), every = opts.labelEvery || 1;
+    var step = (W - left - 12) / Math.max(points.length - 1, 1), path = [], dots = "";
+    points.forEach(function (p, i) {
+      var x = left + i * step, y = h - bottom - p.value / max * plot;
+      path.push(x + "," + y);
+      dots += '<circle class="dot" cx="' + x + '" cy="' + y + '" r="3">' + tip(p, opts.unit) + "</circle>";
+      if (i % every === 0) {
+        dots += text(x, h - 8, p.label, "axis", "middle");
+      }
+    });
+    var body = text(left - 6, 16, max + (opts.unit || ""), "axis", "end");
+    body += '<line x1="' + left + '" y1="' + (h - bottom) + '" x2="' + W + '" y2="' + (h - bottom) + '" class="rule"/>';
+    body += '<polyline class="line" points="' + path.join(" ") + '"/>' + dots;
+    draw(el, h, body);
+  }
+
+  return { columns: columns, bars: bars, line: line };
+})();
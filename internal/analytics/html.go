@@ -0,0 +1,141 @@
+// HTML rendering of the notebook
+
+package analytics
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// notebookHTML is the page the notebook is rendered into
+//
+//go:embed notebook.html
+var notebookHTML string
+
+// chartsJS draws the charts, inlined so the page needs nothing else
+//
+//go:embed charts.js
+var chartsJS string
+
+var notebookTemplate = template.Must(template.New("notebook").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Local().Format("Jan 2, 2006") },
+	"datetime": func(t time.Time) string { return t.Local().Format("Jan 2, 2006 at 15:04") },
+	"duration": formatDuration,
+	"percent":  func(rate float64) string { return fmt.Sprintf("%.0f%%", rate) },
+	"recall":   func(retention float64) float64 { return retention * 100 },
+	"policy":   func(report hints.Report) string { return hints.Describe(report.Policy) },
+}).Parse(notebookHTML))
+
+// point is one value in a chart; the note is shown when it is hovered
+type point struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Note  string  `json:"note,omitempty"`
+}
+
+// charts is the data the page's script draws
+type charts struct {
+	Activity     []point `json:"activity"`
+	Weeks        []point `json:"weeks"`
+	Patterns     []point `json:"patterns"`
+	Difficulties []point `json:"difficulties"`
+	Retention    []point `json:"retention"`
+	Contexts     []point `json:"contexts"`
+	Threshold    float64 `json:"threshold"`
+}
+
+// page is what the template is executed with
+type page struct {
+	*Notebook
+	Now       time.Time // When retention is judged
+	Threshold float64
+	Charts    charts
+	Script    template.JS
+}
+
+// Render writes the notebook as a single HTML page, with its styles, script
+// and data inline
+func Render(w io.Writer, nb *Notebook) error {
+	p := page{
+		Notebook:  nb,
+		Now:       nb.Generated,
+		Threshold: stats.RetentionThreshold * 100,
+		Charts:    chartData(nb),
+		Script:    template.JS(chartsJS),
+	}
+	if err := notebookTemplate.Execute(w, p); err != nil {
+		return fmt.Errorf("failed to render notebook: %v", err)
+	}
+	return nil
+}
+
+// chartData turns the notebook into the points of each chart
+func chartData(nb *Notebook) charts {
+	c := charts{Threshold: stats.RetentionThreshold * 100}
+	for _, day := range nb.Activity {
+		date, _ := time.Parse(dateLayout, day.Date)
+		c.Activity = append(c.Activity, point{
+			Label: date.Format("Jan 2"),
+			Value: float64(day.Solved),
+			Note:  fmt.Sprintf("%d solved of %d attempted", day.Solved, day.Attempted),
+		})
+	}
+	// Weeks without practice have no success rate to draw
+	for _, week := range nb.Weeks {
+		if week.Attempted == 0 {
+			continue
+		}
+		date, _ := time.Parse(dateLayout, week.Start)
+		c.Weeks = append(c.Weeks, point{
+			Label: date.Format("Jan 2"),
+			Value: week.SuccessRate,
+			Note:  fmt.Sprintf("%.0f%%, %d solved of %d", week.SuccessRate, week.Solved, week.Attempted),
+		})
+	}
+	for _, g := range nb.Patterns {
+		c.Patterns = append(c.Patterns, groupPoint(g, g.SuccessRate))
+	}
+	for _, g := range nb.Difficulties {
+		if g.Solved > 0 {
+			c.Difficulties = append(c.Difficulties, groupPoint(g, g.AvgTime.Minutes()))
+		}
+	}
+	for _, g := range nb.Contexts {
+		c.Contexts = append(c.Contexts, groupPoint(g, float64(g.Attempted)))
+	}
+	for _, r := range nb.Retention {
+		c.Retention = append(c.Retention, point{
+			Label: r.Pattern,
+			Value: r.Retention * 100,
+			Note:  fmt.Sprintf("%.0f%% recall, due %s", r.Retention*100, r.DecaysAt.Local().Format("Jan 2")),
+		})
+	}
+	return c
+}
+
+func groupPoint(g Group, value float64) point {
+	return point{
+		Label: g.Name,
+		Value: value,
+		Note:  fmt.Sprintf("%d solved of %d, average solve %s", g.Solved, g.Attempted, formatDuration(g.AvgTime)),
+	}
+}
+
+// formatDuration formats a duration as hours and minutes, or minutes and
+// seconds when under an hour
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
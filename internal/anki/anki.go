@@ -0,0 +1,118 @@
+// Package anki builds pattern-recognition flashcards from the problems and
+// writes them as a deck Anki can import: the front of each card is a
+// problem's prompt, without the paragraphs that name its pattern, and the
+// back its pattern with the key insight
+package anki
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// DefaultDeck is the deck cards are imported into unless another is named
+const DefaultDeck = "Algo Scales::Patterns"
+
+// Card is one flashcard. Front and Back are HTML, as Anki shows them.
+type Card struct {
+	ProblemID string
+	Front     string
+	Back      string
+	Tags      []string
+}
+
+// Cards builds a card for each problem tagged with a pattern, or with
+// pattern alone if it is not empty, ordered by pattern and then title
+func Cards(problems []problem.Problem, pattern string) []Card {
+	var selected []problem.Problem
+	for _, p := range problems {
+		if len(p.Patterns) == 0 || (pattern != "" && !hasPattern(p, pattern)) {
+			continue
+		}
+		selected = append(selected, p)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if a, b := selected[i].Patterns[0], selected[j].Patterns[0]; a != b {
+			return a < b
+		}
+		return selected[i].Title < selected[j].Title
+	})
+
+	cards := make([]Card, 0, len(selected))
+	for _, p := range selected {
+		cards = append(cards, NewCard(p))
+	}
+	return cards
+}
+
+// NewCard builds the card for a problem
+func NewCard(p problem.Problem) Card {
+	var front strings.Builder
+	fmt.Fprintf(&front, "<b>%s</b> (%s)", html.EscapeString(p.Title), html.EscapeString(p.Difficulty))
+	if prompt := p.Prompt(); prompt != "" {
+		front.WriteString("<br><br>" + toHTML(prompt))
+	}
+	front.WriteString("<br><br><i>Which pattern solves this?</i>")
+
+	names := make([]string, len(p.Patterns))
+	for i, pattern := range p.Patterns {
+		names[i] = problem.PatternName(pattern)
+	}
+	back := "<b>" + html.EscapeString(strings.Join(names, ", ")) + "</b>"
+	if explanation := strings.TrimSpace(p.PatternExplanation); explanation != "" {
+		back += "<br><br>" + toHTML(explanation)
+	}
+
+	tags := []string{"algo-scales", strings.ToLower(p.Difficulty)}
+	tags = append(tags, p.Patterns...)
+	return Card{ProblemID: p.ID, Front: front.String(), Back: back, Tags: tags}
+}
+
+// Write writes cards as tab-separated text with the header lines Anki's
+// importer reads to pick the note type, deck and tags column
+func Write(w io.Writer, cards []Card, deck string) error {
+	if deck == "" {
+		deck = DefaultDeck
+	}
+	header := []string{
+		"#separator:tab",
+		"#html:true",
+		"#notetype:Basic",
+		"#deck:" + deck,
+		"#tags column:3",
+	}
+	if _, err := io.WriteString(w, strings.Join(header, "\n")+"\n"); err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	out.Comma = '\t'
+	for _, c := range cards {
+		if err := out.Write([]string{c.Front, c.Back, strings.Join(c.Tags, " ")}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// toHTML escapes text and turns its line breaks into <br>
+func toHTML(text string) string {
+	text = strings.ReplaceAll(html.EscapeString(text), "\t", "    ")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// hasPattern reports whether a problem is tagged with pattern
+func hasPattern(p problem.Problem, pattern string) bool {
+	for _, candidate := range p.Patterns {
+		if candidate == pattern {
+			return true
+		}
+	}
+	return false
+}
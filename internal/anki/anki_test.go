@@ -0,0 +1,75 @@
+package anki
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProblems = []problem.Problem{
+	{
+		ID:                 "linked_list_cycle",
+		Title:              "Linked List Cycle",
+		Difficulty:         "Easy",
+		Patterns:           []string{"fast-slow-pointers"},
+		Description:        "Given the head of a list, determine if it has a cycle.\n\nThe fast-slow pointers pattern is perfect for this problem.",
+		PatternExplanation: "Two pointers move at different speeds;\nif they meet, there is a cycle.",
+	},
+	{
+		ID:                 "max_window",
+		Title:              "Max <Sum> Window",
+		Difficulty:         "Medium",
+		Patterns:           []string{"sliding-window", "hash-map"},
+		Description:        "Find the largest sum of k consecutive elements.",
+		PatternExplanation: "Slide a fixed window.",
+	},
+	{ID: "untagged", Title: "Untagged", Difficulty: "Easy", Description: "No pattern."},
+}
+
+func TestCards(t *testing.T) {
+	cards := Cards(testProblems, "")
+	require.Len(t, cards, 2, "problems without a pattern have no card")
+	assert.Equal(t, "linked_list_cycle", cards[0].ProblemID, "ordered by pattern")
+	assert.Equal(t, "max_window", cards[1].ProblemID)
+
+	cards = Cards(testProblems, "hash-map")
+	require.Len(t, cards, 1)
+	assert.Equal(t, "max_window", cards[0].ProblemID)
+}
+
+func TestNewCard(t *testing.T) {
+	card := NewCard(testProblems[0])
+
+	assert.Equal(t, "<b>Linked List Cycle</b> (Easy)<br><br>Given the head of a list, determine if it has a cycle.<br><br><i>Which pattern solves this?</i>", card.Front)
+	assert.NotContains(t, card.Front, "fast-slow", "the paragraph naming the pattern is left off the front")
+	assert.Equal(t, "<b>Fast &amp; Slow Pointers</b><br><br>Two pointers move at different speeds;<br>if they meet, there is a cycle.", card.Back)
+	assert.Equal(t, []string{"algo-scales", "easy", "fast-slow-pointers"}, card.Tags)
+
+	card = NewCard(testProblems[1])
+	assert.Contains(t, card.Front, "<b>Max &lt;Sum&gt; Window</b> (Medium)")
+	assert.True(t, strings.HasPrefix(card.Back, "<b>Sliding Window, Hash Map</b>"))
+}
+
+func TestWrite(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Write(&b, Cards(testProblems, ""), ""))
+
+	lines := strings.SplitN(b.String(), "\n", 6)
+	assert.Equal(t, []string{"#separator:tab", "#html:true", "#notetype:Basic", "#deck:Algo Scales::Patterns", "#tags column:3"}, lines[:5])
+
+	r := csv.NewReader(strings.NewReader(lines[5]))
+	r.Comma = '\t'
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "algo-scales easy fast-slow-pointers", records[0][2])
+	assert.Equal(t, NewCard(testProblems[0]).Back, records[0][1])
+
+	b.Reset()
+	require.NoError(t, Write(&b, nil, "Interview Prep"))
+	assert.Contains(t, b.String(), "#deck:Interview Prep\n")
+}
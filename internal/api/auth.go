@@ -0,0 +1,251 @@
+// Device-code login and the bearer tokens requests are authenticated with
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/auth"
+	"github.com/lancekrogers/algo-scales/internal/license"
+)
+
+const (
+	// deviceGrantType is the OAuth grant type of a device code (RFC 8628)
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// refreshMargin is how long before it expires an access token is
+	// replaced, so it does not expire on the way to the server
+	refreshMargin = time.Minute
+)
+
+// ErrLoginExpired is returned when the server no longer accepts the stored
+// refresh token
+var ErrLoginExpired = errors.New("your login has expired, run 'algo-scales login' again")
+
+// DeviceCode is a login waiting to be approved in a browser
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"` // Seconds
+	Interval                int    `json:"interval"`   // Seconds to wait between polls
+}
+
+// tokenResponse is the server's answer to a token request; Error is set
+// instead of the tokens when it is refused
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// wait pauses between polls for a login
+// Exported as variable for testing
+var wait = func(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refreshMu keeps this process from rotating the refresh token twice at once
+var refreshMu sync.Mutex
+
+// StartLogin asks the server for a device code for the user to approve
+func StartLogin(ctx context.Context) (DeviceCode, error) {
+	var code DeviceCode
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/auth/device", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return code, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return code, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return code, fmt.Errorf("server refused to start a login (%d)", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&code); err != nil {
+		return code, fmt.Errorf("invalid login response: %v", err)
+	}
+	return code, nil
+}
+
+// FinishLogin waits for the user to approve a device code, then stores the
+// tokens the server issues. It returns where they were stored.
+func FinishLogin(ctx context.Context, code DeviceCode) (string, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*time.Second)
+	defer cancel()
+
+	for {
+		if err := wait(ctx, interval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return "", errors.New("the login was not approved in time")
+			}
+			return "", err
+		}
+
+		tokens, err := requestTokens(ctx, map[string]string{"grant_type": deviceGrantType, "device_code": code.DeviceCode})
+		if err != nil {
+			return "", err
+		}
+		switch tokens.Error {
+		case "":
+			return auth.Save(baseURL, credentialsFrom(tokens, time.Now()))
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "expired_token":
+			return "", errors.New("the login was not approved in time")
+		default:
+			return "", fmt.Errorf("login failed: %s", tokens.describe())
+		}
+	}
+}
+
+// Logout revokes the stored refresh token and removes the stored
+// credentials. The credentials are removed even when the server cannot be
+// reached.
+func Logout(ctx context.Context) error {
+	creds, err := auth.Load(baseURL)
+	if errors.Is(err, auth.ErrNotLoggedIn) {
+		return err
+	}
+	if err == nil && creds.RefreshToken != "" {
+		// Best effort: an unrevoked token still expires on its own
+		if data, err := json.Marshal(map[string]string{"refresh_token": creds.RefreshToken}); err == nil {
+			if req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/auth/revoke", bytes.NewReader(data)); err == nil {
+				req.Header.Set("Content-Type", "application/json")
+				if resp, err := httpClient.Do(req); err == nil {
+					resp.Body.Close()
+				}
+			}
+		}
+	}
+	return auth.Delete(baseURL)
+}
+
+// LoggedIn reports whether there are credentials for the server
+func LoggedIn() bool {
+	_, err := auth.Load(baseURL)
+	return err == nil
+}
+
+// bearerToken returns the credential requests are authenticated with: an
+// access token from login, refreshed when it is about to expire, or the
+// license key on machines that have not logged in
+func bearerToken(ctx context.Context) (string, error) {
+	token, err := accessToken(ctx)
+	if !errors.Is(err, auth.ErrNotLoggedIn) {
+		return token, err
+	}
+
+	lic, err := license.LoadLicense()
+	if err != nil {
+		return "", fmt.Errorf("run 'algo-scales login' or activate a license first: %v", err)
+	}
+	return lic.LicenseKey, nil
+}
+
+// accessToken returns a current access token, refreshing the stored one
+// when it is about to expire
+func accessToken(ctx context.Context) (string, error) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+
+	creds, err := auth.Load(baseURL)
+	if err != nil {
+		return "", err
+	}
+	if !creds.Expiring(time.Now(), refreshMargin) {
+		return creds.AccessToken, nil
+	}
+
+	tokens, err := requestTokens(ctx, map[string]string{"grant_type": "refresh_token", "refresh_token": creds.RefreshToken})
+	if err != nil {
+		return "", err
+	}
+	if tokens.Error != "" {
+		// Another process may have used the refresh token first and stored
+		// the one it got back
+		if latest, err := auth.Load(baseURL); err == nil && latest.RefreshToken != creds.RefreshToken {
+			return latest.AccessToken, nil
+		}
+		if tokens.Error == "invalid_grant" {
+			return "", ErrLoginExpired
+		}
+		return "", fmt.Errorf("failed to refresh login: %s", tokens.describe())
+	}
+
+	creds = credentialsFrom(tokens, time.Now())
+	if _, err := auth.Save(baseURL, creds); err != nil {
+		return "", fmt.Errorf("failed to store refreshed login: %v", err)
+	}
+	return creds.AccessToken, nil
+}
+
+// requestTokens posts a token request. Refusals come back in the
+// response's Error rather than as an error.
+func requestTokens(ctx context.Context, body map[string]string) (tokenResponse, error) {
+	var tokens tokenResponse
+	data, err := json.Marshal(body)
+	if err != nil {
+		return tokens, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/auth/token", bytes.NewReader(data))
+	if err != nil {
+		return tokens, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return tokens, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return tokens, fmt.Errorf("invalid token response (%d): %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK && tokens.Error == "" {
+		return tokens, fmt.Errorf("server rejected token request (%d)", resp.StatusCode)
+	}
+	return tokens, nil
+}
+
+// describe explains why a token request was refused
+func (t tokenResponse) describe() string {
+	if t.ErrorDescription != "" {
+		return t.ErrorDescription
+	}
+	return t.Error
+}
+
+// credentialsFrom converts issued tokens for storage
+func credentialsFrom(tokens tokenResponse, now time.Time) auth.Credentials {
+	return auth.Credentials{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    now.Add(time.Duration(tokens.ExpiresIn) * time.Second),
+	}
+}
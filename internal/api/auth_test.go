@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/auth"
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useCredentials keeps stored credentials in a map for the test, keyed by
+// server
+func useCredentials(t *testing.T) map[string]auth.Credentials {
+	t.Helper()
+	stored := make(map[string]auth.Credentials)
+	origLoad, origSave, origDelete := auth.Load, auth.Save, auth.Delete
+	t.Cleanup(func() { auth.Load, auth.Save, auth.Delete = origLoad, origSave, origDelete })
+	auth.Load = func(server string) (auth.Credentials, error) {
+		creds, ok := stored[server]
+		if !ok {
+			return creds, auth.ErrNotLoggedIn
+		}
+		return creds, nil
+	}
+	auth.Save = func(server string, creds auth.Credentials) (string, error) {
+		stored[server] = creds
+		return "the test keychain", nil
+	}
+	auth.Delete = func(server string) error {
+		delete(stored, server)
+		return nil
+	}
+	return stored
+}
+
+// useAuthServer serves the login endpoints, answering token requests with
+// the responses given in turn, and records the requests it gets
+func useAuthServer(t *testing.T, responses ...string) *[]map[string]string {
+	t.Helper()
+	var requests []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		body["path"] = r.URL.Path
+		requests = append(requests, body)
+
+		switch r.URL.Path {
+		case "/auth/device":
+			w.Write([]byte(`{"device_code":"device-1","user_code":"BCDF-GHJK","verification_uri":"http://example.com/device","expires_in":600,"interval":5}`))
+		case "/auth/token":
+			response := responses[0]
+			responses = responses[1:]
+			var tokens tokenResponse
+			json.Unmarshal([]byte(response), &tokens)
+			if tokens.Error != "" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.Write([]byte(response))
+		case "/auth/revoke":
+			w.Write([]byte(`{"revoked":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	origBaseURL := baseURL
+	t.Cleanup(func() { baseURL = origBaseURL })
+	baseURL = server.URL
+	return &requests
+}
+
+func TestLogin(t *testing.T) {
+	stored := useCredentials(t)
+	requests := useAuthServer(t,
+		`{"error":"authorization_pending"}`,
+		`{"error":"slow_down"}`,
+		`{"access_token":"AT1.a","token_type":"Bearer","expires_in":900,"refresh_token":"RT1.a"}`,
+	)
+	var waits []time.Duration
+	origWait := wait
+	t.Cleanup(func() { wait = origWait })
+	wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	code, err := StartLogin(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "BCDF-GHJK", code.UserCode)
+	assert.Equal(t, "http://example.com/device", code.VerificationURI)
+
+	where, err := FinishLogin(context.Background(), code)
+	require.NoError(t, err)
+	assert.Equal(t, "the test keychain", where)
+	assert.Equal(t, []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}, waits, "slow_down adds 5 seconds")
+	assert.Equal(t, deviceGrantType, (*requests)[1]["grant_type"])
+	assert.Equal(t, "device-1", (*requests)[1]["device_code"])
+
+	creds := stored[baseURL]
+	assert.Equal(t, "RT1.a", creds.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), creds.ExpiresAt, time.Minute)
+	assert.True(t, LoggedIn())
+}
+
+func TestLogin_Refused(t *testing.T) {
+	useCredentials(t)
+	useAuthServer(t, `{"error":"expired_token"}`, `{"error":"access_denied","error_description":"Denied"}`)
+	origWait := wait
+	t.Cleanup(func() { wait = origWait })
+	wait = func(ctx context.Context, d time.Duration) error { return nil }
+
+	_, err := FinishLogin(context.Background(), DeviceCode{DeviceCode: "device-1", ExpiresIn: 600})
+	assert.EqualError(t, err, "the login was not approved in time")
+	_, err = FinishLogin(context.Background(), DeviceCode{DeviceCode: "device-1", ExpiresIn: 600})
+	assert.EqualError(t, err, "login failed: Denied")
+	assert.False(t, LoggedIn())
+}
+
+func TestBearerToken(t *testing.T) {
+	origLoadLicense := license.LoadLicense
+	t.Cleanup(func() { license.LoadLicense = origLoadLicense })
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	t.Run("LicenseWithoutLogin", func(t *testing.T) {
+		useCredentials(t)
+		requests := useAuthServer(t)
+		token, err := bearerToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "LICENSE-test", token)
+		assert.Empty(t, *requests)
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		stored := useCredentials(t)
+		requests := useAuthServer(t)
+		stored[baseURL] = auth.Credentials{AccessToken: "AT1.a", RefreshToken: "RT1.a", ExpiresAt: time.Now().Add(10 * time.Minute)}
+		token, err := bearerToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "AT1.a", token)
+		assert.Empty(t, *requests, "no refresh needed")
+	})
+
+	t.Run("Refreshed", func(t *testing.T) {
+		stored := useCredentials(t)
+		requests := useAuthServer(t, `{"access_token":"AT1.b","token_type":"Bearer","expires_in":900,"refresh_token":"RT1.b"}`)
+		stored[baseURL] = auth.Credentials{AccessToken: "AT1.a", RefreshToken: "RT1.a", ExpiresAt: time.Now().Add(30 * time.Second)}
+		token, err := bearerToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "AT1.b", token)
+		assert.Equal(t, map[string]string{"path": "/auth/token", "grant_type": "refresh_token", "refresh_token": "RT1.a"}, (*requests)[0])
+		assert.Equal(t, "RT1.b", stored[baseURL].RefreshToken, "the rotated refresh token is stored")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		stored := useCredentials(t)
+		useAuthServer(t, `{"error":"invalid_grant"}`)
+		stored[baseURL] = auth.Credentials{RefreshToken: "RT1.a"}
+		_, err := bearerToken(context.Background())
+		assert.ErrorIs(t, err, ErrLoginExpired)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	stored := useCredentials(t)
+	requests := useAuthServer(t)
+
+	assert.ErrorIs(t, Logout(context.Background()), auth.ErrNotLoggedIn)
+
+	stored[baseURL] = auth.Credentials{AccessToken: "AT1.a", RefreshToken: "RT1.a"}
+	require.NoError(t, Logout(context.Background()))
+	assert.Empty(t, stored)
+	require.Len(t, *requests, 1)
+	assert.Equal(t, map[string]string{"path": "/auth/revoke", "refresh_token": "RT1.a"}, (*requests)[0])
+}
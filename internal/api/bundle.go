@@ -0,0 +1,179 @@
+// Content-addressed problem bundle downloads
+
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// APIURLEnv names the environment variable holding the REST API base URL.
+// When set, problems are downloaded as hash-verified bundles from it.
+const APIURLEnv = "ALGO_SCALES_API_URL"
+
+// BundleManifest points at the current problem bundle
+type BundleManifest struct {
+	Version string `json:"version"`
+	Hash    string `json:"hash"` // hex SHA-256 of the uncompressed bundle
+	Size    int    `json:"size"`
+	URL     string `json:"url"`
+}
+
+// fetchBundleManifest asks the server which bundle is current
+func fetchBundleManifest(ctx context.Context) (BundleManifest, error) {
+	var manifest BundleManifest
+
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/problems/manifest", nil)
+	if err != nil {
+		return manifest, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return manifest, fmt.Errorf("server returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid bundle manifest: %v", err)
+	}
+	if manifest.Hash == "" || manifest.URL == "" {
+		return manifest, fmt.Errorf("invalid bundle manifest: missing hash or url")
+	}
+	return manifest, nil
+}
+
+// downloadBundle fetches a bundle, decompresses it and checks it against
+// the manifest hash, so a misbehaving cache or CDN cannot serve bad data
+func downloadBundle(ctx context.Context, manifest BundleManifest) (ProblemSet, error) {
+	var set ProblemSet
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundleURL(manifest.URL), nil)
+	if err != nil {
+		return set, err
+	}
+	// Asking explicitly turns off the transport's transparent gzip handling
+	req.Header.Set("Accept-Encoding", "br, gzip")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return set, fmt.Errorf("failed to download bundle: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return set, fmt.Errorf("failed to download bundle: server returned %s", resp.Status)
+	}
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return set, fmt.Errorf("failed to decompress bundle: %v", err)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return set, fmt.Errorf("failed to read bundle: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != manifest.Hash {
+		return set, fmt.Errorf("bundle hash mismatch: expected %s, got %s", manifest.Hash, got)
+	}
+
+	// Problems are checked one by one so a malformed entry is skipped
+	// instead of failing the whole sync
+	var bundle struct {
+		Version     string            `json:"version"`
+		LastUpdated time.Time         `json:"last_updated"`
+		Problems    []json.RawMessage `json:"problems"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return set, fmt.Errorf("invalid bundle: %v", err)
+	}
+	set.Version, set.LastUpdated = bundle.Version, bundle.LastUpdated
+	set.Problems, set.Skipped = decodeBundleProblems(bundle.Problems)
+	return set, nil
+}
+
+// decodeBundleProblems checks each bundled problem against the problem
+// schema, returning the valid ones and a description of each one skipped
+func decodeBundleProblems(raws []json.RawMessage) ([]problem.Problem, []string) {
+	var problems []problem.Problem
+	var skipped []string
+	for i, raw := range raws {
+		p, err := problem.DecodeProblem(raw)
+		if err == nil {
+			problems = append(problems, p)
+			continue
+		}
+
+		name := fmt.Sprintf("problems[%d]", i)
+		var id struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &id) == nil && id.ID != "" {
+			name += fmt.Sprintf(" (%s)", id.ID)
+		}
+		// Positions within the bundle mean little to a user, paths do
+		var schemaErr *problem.SchemaError
+		if errors.As(err, &schemaErr) {
+			messages := make([]string, len(schemaErr.Issues))
+			for j, issue := range schemaErr.Issues {
+				messages[j] = issue.Message
+			}
+			skipped = append(skipped, fmt.Sprintf("%s: %s", name, strings.Join(messages, "; ")))
+			continue
+		}
+		skipped = append(skipped, fmt.Sprintf("%s: %v", name, err))
+	}
+	return problems, skipped
+}
+
+// fetchBundle downloads the current bundle unless the local copy is already
+// at the manifest's version
+func fetchBundle(ctx context.Context, force bool) (ProblemSet, error) {
+	manifest, err := fetchBundleManifest(ctx)
+	if err != nil {
+		return ProblemSet{}, err
+	}
+	if !force && manifest.Version != "" && manifest.Version == localVersion() {
+		return ProblemSet{Version: manifest.Version, UpToDate: true}, nil
+	}
+	return downloadBundle(ctx, manifest)
+}
+
+// bundleURL resolves a manifest URL against the API host
+func bundleURL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v1") + path
+}
+
+// decodeBody wraps a response body according to its Content-Encoding
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip":
+		return gzip.NewReader(resp.Body)
+	case "br":
+		return brotli.NewReader(resp.Body), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchBundle(t *testing.T) {
+	data, err := json.Marshal(getSampleProblems())
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	var gz, br bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(data)
+	gw.Close()
+	bw := brotli.NewWriter(&br)
+	bw.Write(data)
+	bw.Close()
+
+	// encoding selects what the fake CDN sends; "tampered" sends the wrong bytes
+	encoding := ""
+	bundleRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/problems/manifest":
+			if r.Header.Get("Authorization") != "Bearer LICENSE-test" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(BundleManifest{Version: "1.0.0", Hash: hash, Size: len(data), URL: "/v1/bundles/" + hash + ".json"})
+		case "/v1/bundles/" + hash + ".json":
+			bundleRequests++
+			assert.Equal(t, "br, gzip", r.Header.Get("Accept-Encoding"))
+			switch encoding {
+			case "br":
+				w.Header().Set("Content-Encoding", "br")
+				w.Write(br.Bytes())
+			case "gzip":
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write(gz.Bytes())
+			case "tampered":
+				w.Write(bytes.Replace(data, []byte("Two Sum"), []byte("Two Sun"), 1))
+			default:
+				w.Write(data)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	tempDir := t.TempDir()
+	origGetConfigDir := getConfigDir
+	defer func() { getConfigDir = origGetConfigDir }()
+	getConfigDir = func() string {
+		return tempDir
+	}
+
+	for _, enc := range []string{"", "gzip", "br"} {
+		t.Run("Encoding_"+enc, func(t *testing.T) {
+			encoding = enc
+			set, err := fetchBundle(context.Background(), false)
+			require.NoError(t, err)
+			assert.Equal(t, "1.0.0", set.Version)
+			assert.Len(t, set.Problems, len(getSampleProblems().Problems))
+		})
+	}
+
+	t.Run("HashMismatch", func(t *testing.T) {
+		encoding = "tampered"
+		_, err := fetchBundle(context.Background(), false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bundle hash mismatch")
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		encoding = ""
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "version.json"), []byte(`{"version":"1.0.0"}`), 0644))
+		before := bundleRequests
+
+		set, err := fetchBundle(context.Background(), false)
+		require.NoError(t, err)
+		assert.True(t, set.UpToDate)
+		assert.Equal(t, before, bundleRequests, "bundle should not be downloaded again")
+
+		set, err = fetchBundle(context.Background(), true)
+		require.NoError(t, err)
+		assert.False(t, set.UpToDate)
+		assert.Equal(t, before+1, bundleRequests)
+	})
+}
+
+func TestDecodeBundleProblems(t *testing.T) {
+	raws := []json.RawMessage{
+		json.RawMessage(`{"id": "two_sum", "title": "Two Sum", "test_cases": [{"input": "1", "expected": "1"}]}`),
+		json.RawMessage(`{"id": "broken", "title": "Broken", "test_cases": [{"input": "1"}], "estimated_time": "soon"}`),
+		json.RawMessage(`["not", "a", "problem"]`),
+	}
+
+	problems, skipped := decodeBundleProblems(raws)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "two_sum", problems[0].ID)
+	assert.Equal(t, []string{
+		"problems[1] (broken): test_cases[0].expected missing; estimated_time should be an integer, got a string",
+		"problems[2]: problem should be an object, got an array",
+	}, skipped)
+}
@@ -10,8 +10,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
 )
 
 const (
@@ -42,47 +45,107 @@ func DownloadProblems(force bool) error {
 		return err
 	}
 
-	// For MVP, we'll use embedded sample problems instead of API call
-	problemSet := getSampleProblems()
-
-	// Save version info
-	versionFile := filepath.Join(configDir, "version.json")
-	versionData, err := json.MarshalIndent(map[string]interface{}{
-		"version":      "1.0.0",
-		"last_updated": time.Now(),
-	}, "", "  ")
+	problemSet, err := fetchProblemSet(force)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(versionFile, versionData, 0644); err != nil {
+	if problemSet.UpToDate {
+		return nil
+	}
+
+	// Save version info
+	if err := writeVersionFile(problemSet.Version); err != nil {
 		return err
 	}
 
+	// Problems the user has solved get a changelog entry instead of changing silently
+	solved := solvedProblemIDs()
+	changed := 0
+	state := loadSyncState()
+
 	// Save individual problems
 	for _, p := range problemSet.Problems {
-		// Create pattern directories
-		for _, pattern := range p.Patterns {
-			patternDir := filepath.Join(problemsDir, pattern)
-			if err := os.MkdirAll(patternDir, 0755); err != nil {
-				return err
-			}
-
-			// Save problem file
-			problemData, err := json.MarshalIndent(p, "", "  ")
-			if err != nil {
-				return err
-			}
-
-			problemFile := filepath.Join(patternDir, fmt.Sprintf("%s.json", p.ID))
-			if err := os.WriteFile(problemFile, problemData, 0644); err != nil {
-				return err
-			}
+		if solved[p.ID] && notifyProblemChanges(problemsDir, p) {
+			changed++
+		}
+
+		// Save the problem file in each pattern directory
+		problemData, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
 		}
+		if err := saveProblem(problemsDir, p, problemData, state); err != nil {
+			return err
+		}
+	}
+	if err := state.save(); err != nil {
+		return err
 	}
 
+	details := []string{fmt.Sprintf("%d problems synced (version %s)", len(problemSet.Problems), problemSet.Version)}
+	if changed > 0 {
+		details = append(details, fmt.Sprintf("%d solved problem(s) changed, see their changelog entries", changed))
+	}
+	if len(problemSet.Skipped) > 0 {
+		details = append(details, fmt.Sprintf("%d invalid problem(s) skipped:", len(problemSet.Skipped)))
+		details = append(details, problemSet.Skipped...)
+	}
+	_ = notifications.Add(notifications.Notification{
+		Kind:    notifications.KindSync,
+		Title:   "Problem sync complete",
+		Details: details,
+	})
+
 	return nil
 }
 
+// solvedProblemIDs returns the set of problem IDs the user has solved at least once
+func solvedProblemIDs() map[string]bool {
+	solved := make(map[string]bool)
+	sessions, err := stats.GetAllSessions()
+	if err != nil {
+		return solved
+	}
+	for _, s := range sessions {
+		if s.Solved {
+			solved[s.ProblemID] = true
+		}
+	}
+	return solved
+}
+
+// notifyProblemChanges compares an incoming problem with the local copy and
+// adds a changelog entry to the notifications inbox when they differ.
+// It reports whether the problem changed.
+func notifyProblemChanges(problemsDir string, updated problem.Problem) bool {
+	for _, pattern := range updated.Patterns {
+		data, err := os.ReadFile(filepath.Join(problemsDir, pattern, fmt.Sprintf("%s.json", updated.ID)))
+		if err != nil {
+			continue
+		}
+
+		var existing problem.Problem
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return false
+		}
+
+		changes := problem.DescribeChanges(existing, updated)
+		if len(changes) == 0 {
+			return false
+		}
+
+		// A failed notification must not block the sync itself
+		_ = notifications.Add(notifications.Notification{
+			Kind:      notifications.KindProblemUpdate,
+			Title:     fmt.Sprintf("%s was updated", updated.Title),
+			Details:   changes,
+			ProblemID: updated.ID,
+		})
+		return true
+	}
+	return false
+}
+
 // shouldUpdate checks if we need to update problem sets
 func shouldUpdate() bool {
 	configDir := getConfigDir()
@@ -108,9 +171,9 @@ func shouldUpdate() bool {
 		return true
 	}
 
-	// For MVP, we'll always return false after initial download
-	// In a real implementation, you'd check against the server version
-	return false
+	// With a server configured, it decides whether our version is current.
+	// Otherwise the bundled sample set never changes after the initial download.
+	return os.Getenv(GRPCAddrEnv) != "" || os.Getenv(APIURLEnv) != ""
 }
 
 // getConfigDir returns the configuration directory
@@ -125,6 +188,8 @@ type ProblemSet struct {
 	Version     string            `json:"version"`
 	LastUpdated time.Time         `json:"last_updated"`
 	Problems    []problem.Problem `json:"problems"`
+	UpToDate    bool              `json:"-"` // Set when the server has nothing newer
+	Skipped     []string          `json:"-"` // Problems left out for not matching the schema
 }
 
 // getSampleProblems returns a set of sample problems for MVP
@@ -169,11 +234,25 @@ func getSampleProblems() ProblemSet {
 					"go":         "func twoSum(nums []int, target int) []int {\n    // Your code here\n}",
 					"python":     "def two_sum(nums, target):\n    # Your code here\n    pass",
 					"javascript": "function twoSum(nums, target) {\n    // Your code here\n}",
+					"rust":       "pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    // Your code here\n    todo!()\n}",
+					"java":       "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        // Your code here\n        return new int[0];\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        // Your code here\n        return {};\n    }\n};",
+					"typescript": "function twoSum(nums: number[], target: number): number[] {\n    // Your code here\n    return [];\n}",
 				},
 				Solutions: map[string]string{
 					"go":         "func twoSum(nums []int, target int) []int {\n    seen := make(map[int]int)\n    for i, num := range nums {\n        complement := target - num\n        if j, ok := seen[complement]; ok {\n            return []int{j, i}\n        }\n        seen[num] = i\n    }\n    return []int{}\n}",
 					"python":     "def two_sum(nums, target):\n    seen = {}\n    for i, num in enumerate(nums):\n        complement = target - num\n        if complement in seen:\n            return [seen[complement], i]\n        seen[num] = i\n    return []",
 					"javascript": "function twoSum(nums, target) {\n    const seen = {};\n    for (let i = 0; i < nums.length; i++) {\n        const complement = target - nums[i];\n        if (complement in seen) {\n            return [seen[complement], i];\n        }\n        seen[nums[i]] = i;\n    }\n    return [];\n}",
+					"rust":       "use std::collections::HashMap;\n\npub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    let mut seen = HashMap::new();\n    for (i, &num) in nums.iter().enumerate() {\n        if let Some(&j) = seen.get(&(target - num)) {\n            return vec![j as i32, i as i32];\n        }\n        seen.insert(num, i);\n    }\n    vec![]\n}",
+					"java":       "import java.util.HashMap;\nimport java.util.Map;\n\nclass Solution {\n    public int[] twoSum(int[] nums, int target) {\n        Map<Integer, Integer> seen = new HashMap<>();\n        for (int i = 0; i < nums.length; i++) {\n            Integer j = seen.get(target - nums[i]);\n            if (j != null) {\n                return new int[]{j, i};\n            }\n            seen.put(nums[i], i);\n        }\n        return new int[0];\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        unordered_map<int, int> seen;\n        for (int i = 0; i < (int)nums.size(); i++) {\n            auto it = seen.find(target - nums[i]);\n            if (it != seen.end()) {\n                return {it->second, i};\n            }\n            seen[nums[i]] = i;\n        }\n        return {};\n    }\n};",
+					"typescript": "function twoSum(nums: number[], target: number): number[] {\n    const seen = new Map<number, number>();\n    for (let i = 0; i < nums.length; i++) {\n        const j = seen.get(target - nums[i]);\n        if (j !== undefined) {\n            return [j, i];\n        }\n        seen.set(nums[i], i);\n    }\n    return [];\n}",
+				},
+				Signature: &interfaces.FunctionSignature{
+					Name:      "twoSum",
+					Params:    []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+					Returns:   "int[]",
+					Unordered: true,
 				},
 				TestCases: []problem.TestCase{
 					{
@@ -222,11 +301,24 @@ func getSampleProblems() ProblemSet {
 					"go":         "func maxSubArray(nums []int) int {\n    // Your code here\n}",
 					"python":     "def max_subarray(nums):\n    # Your code here\n    pass",
 					"javascript": "function maxSubArray(nums) {\n    // Your code here\n}",
+					"rust":       "pub fn max_sub_array(nums: Vec<i32>) -> i32 {\n    // Your code here\n    todo!()\n}",
+					"java":       "class Solution {\n    public int maxSubArray(int[] nums) {\n        // Your code here\n        return 0;\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    int maxSubArray(vector<int>& nums) {\n        // Your code here\n        return 0;\n    }\n};",
+					"typescript": "function maxSubArray(nums: number[]): number {\n    // Your code here\n    return 0;\n}",
 				},
 				Solutions: map[string]string{
 					"go":         "func maxSubArray(nums []int) int {\n    if len(nums) == 0 {\n        return 0\n    }\n    \n    currentSum := nums[0]\n    maxSum := nums[0]\n    \n    for i := 1; i < len(nums); i++ {\n        currentSum = max(nums[i], currentSum + nums[i])\n        maxSum = max(maxSum, currentSum)\n    }\n    \n    return maxSum\n}\n\nfunc max(a, b int) int {\n    if a > b {\n        return a\n    }\n    return b\n}",
 					"python":     "def max_subarray(nums):\n    if not nums:\n        return 0\n        \n    current_sum = max_sum = nums[0]\n    \n    for num in nums[1:]:\n        current_sum = max(num, current_sum + num)\n        max_sum = max(max_sum, current_sum)\n        \n    return max_sum",
 					"javascript": "function maxSubArray(nums) {\n    if (nums.length === 0) {\n        return 0;\n    }\n    \n    let currentSum = nums[0];\n    let maxSum = nums[0];\n    \n    for (let i = 1; i < nums.length; i++) {\n        currentSum = Math.max(nums[i], currentSum + nums[i]);\n        maxSum = Math.max(maxSum, currentSum);\n    }\n    \n    return maxSum;\n}",
+					"rust":       "pub fn max_sub_array(nums: Vec<i32>) -> i32 {\n    let mut current_sum = nums[0];\n    let mut max_sum = nums[0];\n    for &num in &nums[1..] {\n        current_sum = num.max(current_sum + num);\n        max_sum = max_sum.max(current_sum);\n    }\n    max_sum\n}",
+					"java":       "class Solution {\n    public int maxSubArray(int[] nums) {\n        int currentSum = nums[0];\n        int maxSum = nums[0];\n        for (int i = 1; i < nums.length; i++) {\n            currentSum = Math.max(nums[i], currentSum + nums[i]);\n            maxSum = Math.max(maxSum, currentSum);\n        }\n        return maxSum;\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    int maxSubArray(vector<int>& nums) {\n        int currentSum = nums[0];\n        int maxSum = nums[0];\n        for (size_t i = 1; i < nums.size(); i++) {\n            currentSum = max(nums[i], currentSum + nums[i]);\n            maxSum = max(maxSum, currentSum);\n        }\n        return maxSum;\n    }\n};",
+					"typescript": "function maxSubArray(nums: number[]): number {\n    let currentSum = nums[0];\n    let maxSum = nums[0];\n    for (let i = 1; i < nums.length; i++) {\n        currentSum = Math.max(nums[i], currentSum + nums[i]);\n        maxSum = Math.max(maxSum, currentSum);\n    }\n    return maxSum;\n}",
+				},
+				Signature: &interfaces.FunctionSignature{
+					Name:    "maxSubArray",
+					Params:  []interfaces.Param{{Name: "nums", Type: "int[]"}},
+					Returns: "int",
 				},
 				TestCases: []problem.TestCase{
 					{
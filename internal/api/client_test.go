@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -38,6 +41,18 @@ func TestDownloadProblems(t *testing.T) {
 		return tempDir
 	}
 
+	// Keep the inbox and stats lookups inside the temp directory
+	origInboxPath := notifications.GetInboxPath
+	defer func() { notifications.GetInboxPath = origInboxPath }()
+	notifications.GetInboxPath = func() string {
+		return filepath.Join(tempDir, "notifications.json")
+	}
+	origGetAllSessions := stats.GetAllSessions
+	defer func() { stats.GetAllSessions = origGetAllSessions }()
+	stats.GetAllSessions = func() ([]stats.SessionStats, error) {
+		return []stats.SessionStats{{ProblemID: "two-sum", Solved: true}}, nil
+	}
+
 	// Test cases
 	t.Run("InvalidLicense", func(t *testing.T) {
 		// Mock invalid license
@@ -86,6 +101,41 @@ func TestDownloadProblems(t *testing.T) {
 		assert.Equal(t, "1.0.0", version.Version)
 	})
 
+	t.Run("ChangedSolvedProblemIsNotified", func(t *testing.T) {
+		restore := mockLicenseValidation(true, nil)
+		defer restore()
+
+		// Simulate a stale local copy of a solved problem
+		problemFile := filepath.Join(tempDir, "problems", "hash-map", "two-sum.json")
+		data, err := os.ReadFile(problemFile)
+		require.NoError(t, err)
+		var stale problem.Problem
+		require.NoError(t, json.Unmarshal(data, &stale))
+		stale.TestCases = stale.TestCases[:1]
+		data, err = json.Marshal(stale)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(problemFile, data, 0644))
+
+		require.NoError(t, DownloadProblems(true))
+
+		inbox, err := notifications.Load()
+		require.NoError(t, err)
+		require.NotEmpty(t, inbox)
+
+		// The changelog entry precedes the sync summary
+		var update *notifications.Notification
+		for i := range inbox {
+			if inbox[i].Kind == notifications.KindProblemUpdate {
+				update = &inbox[i]
+				break
+			}
+		}
+		require.NotNil(t, update)
+		assert.Equal(t, "two-sum", update.ProblemID)
+		assert.Contains(t, update.Details, "2 test case(s) added")
+		assert.Equal(t, notifications.KindSync, inbox[0].Kind)
+	})
+
 	t.Run("NoUpdateNeeded", func(t *testing.T) {
 		// Mock valid license
 		restore := mockLicenseValidation(true, nil)
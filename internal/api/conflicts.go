@@ -0,0 +1,235 @@
+// Resolving the conflicts a sync leaves when it meets local edits
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
+)
+
+// removedSuffix marks a conflict over a problem the server removed
+const removedSuffix = ".removed"
+
+// Resolution is how a sync conflict is resolved
+type Resolution string
+
+const (
+	// KeepLocal keeps the user's copy and discards the server's
+	KeepLocal Resolution = "local"
+	// TakeRemote replaces the user's copy with the server's
+	TakeRemote Resolution = "remote"
+	// MergeOverlays takes the server's copy and keeps what the user added
+	// to theirs apart from it: added tests become user tests and other
+	// additions become notes in the problem's overlay
+	MergeOverlays Resolution = "merge"
+)
+
+// Conflict is a problem the user modified that a sync changed or removed
+type Conflict struct {
+	ProblemID string
+	Removed   bool             // The server removed the problem
+	Local     *problem.Problem // The user's copy
+	Remote    *problem.Problem // The server's copy, unless Removed
+}
+
+// Differences describes how the server's copy differs from the user's
+func (c Conflict) Differences() []string {
+	if c.Removed || c.Local == nil {
+		return []string{"removed from the problem set"}
+	}
+	return problem.DescribeChanges(*c.Local, *c.Remote)
+}
+
+// Additions returns what the user's copy adds to the server's, as merging
+// would keep it
+func (c Conflict) Additions() ([]problem.TestCase, []string) {
+	if c.Removed || c.Local == nil {
+		return nil, nil
+	}
+	return problem.Additions(*c.Local, *c.Remote)
+}
+
+// ResolveResult describes how a conflict was resolved
+type ResolveResult struct {
+	TestsAdded int    // Tests moved into the user's tests
+	NotesAdded int    // Notes added to the problem's overlay
+	Backup     string // Where the replaced local copy was saved
+}
+
+// conflictDir returns where syncs keep the server's copies of conflicts
+func conflictDir() string {
+	return filepath.Join(getConfigDir(), "sync-conflicts")
+}
+
+// ListConflicts returns the unresolved sync conflicts, by problem ID
+// Exported as variable for testing
+var ListConflicts = func() ([]Conflict, error) {
+	entries, err := os.ReadDir(conflictDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []Conflict
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, removedSuffix)) {
+			continue
+		}
+		c, err := loadConflict(strings.TrimSuffix(strings.TrimSuffix(name, ".json"), removedSuffix))
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].ProblemID < conflicts[j].ProblemID })
+	return conflicts, nil
+}
+
+// loadConflict reads the two sides of a conflict
+func loadConflict(id string) (Conflict, error) {
+	c := Conflict{ProblemID: id}
+	data, err := os.ReadFile(filepath.Join(conflictDir(), id+".json"))
+	switch {
+	case os.IsNotExist(err):
+		if _, err := os.Stat(filepath.Join(conflictDir(), id+removedSuffix)); err != nil {
+			return c, fmt.Errorf("no sync conflict for %s", id)
+		}
+		c.Removed = true
+	case err != nil:
+		return c, err
+	default:
+		remote, err := problem.DecodeProblem(data)
+		if err != nil {
+			return c, fmt.Errorf("invalid server copy of %s: %v", id, err)
+		}
+		c.Remote = &remote
+	}
+
+	state := loadSyncState()
+	if path, ok := localProblemFile(id, state); ok {
+		if data, err := os.ReadFile(path); err == nil {
+			if local, err := problem.DecodeProblem(data); err == nil {
+				c.Local = &local
+			}
+		}
+	}
+	return c, nil
+}
+
+// ResolveConflict resolves a sync conflict
+// Exported as variable for testing
+var ResolveConflict = func(ctx context.Context, id string, resolution Resolution) (ResolveResult, error) {
+	var result ResolveResult
+	c, err := loadConflict(id)
+	if err != nil {
+		return result, err
+	}
+
+	switch resolution {
+	case KeepLocal:
+		return result, clearConflict(id)
+	case TakeRemote, MergeOverlays:
+	default:
+		return result, fmt.Errorf("unknown resolution %q", resolution)
+	}
+	if resolution == MergeOverlays && c.Removed {
+		return result, fmt.Errorf("%s was removed from the problem set, so there is nothing to merge into; keep yours or take the removal", id)
+	}
+
+	problemsDir := filepath.Join(getConfigDir(), "problems")
+	state := loadSyncState()
+	if path, ok := localProblemFile(id, state); ok {
+		if result.Backup, err = backupLocalCopy(id, path); err != nil {
+			return result, err
+		}
+	}
+
+	if resolution == MergeOverlays && c.Local != nil {
+		tests, notes := c.Additions()
+		suite := usertests.Open()
+		for _, tc := range tests {
+			if _, err := suite.Add(ctx, id, tc.Input, tc.Expected); err != nil {
+				suite.Close()
+				return result, fmt.Errorf("failed to keep your test %s: %v", tc.Input, err)
+			}
+			result.TestsAdded++
+		}
+		suite.Close()
+		if len(notes) > 0 {
+			if err := problem.AddNotes(id, notes); err != nil {
+				return result, err
+			}
+			result.NotesAdded = len(notes)
+		}
+	}
+
+	if err := removeProblemFiles(problemsDir, id, state); err != nil {
+		return result, err
+	}
+	if !c.Removed {
+		data, err := os.ReadFile(filepath.Join(conflictDir(), id+".json"))
+		if err != nil {
+			return result, err
+		}
+		if err := saveProblem(problemsDir, *c.Remote, data, state); err != nil {
+			return result, err
+		}
+	}
+	if err := state.save(); err != nil {
+		return result, err
+	}
+	return result, clearConflict(id)
+}
+
+// localProblemFile returns the first of the files a sync wrote for a problem
+// that still exists
+func localProblemFile(id string, state *syncState) (string, bool) {
+	var rels []string
+	for rel := range state.Problems[id] {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	problemsDir := filepath.Join(getConfigDir(), "problems")
+	for _, rel := range rels {
+		path := filepath.Join(problemsDir, rel)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// backupLocalCopy saves the user's copy of a problem before it is replaced
+func backupLocalCopy(id, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	backupDir := filepath.Join(conflictDir(), "backups")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return "", err
+	}
+	backup := filepath.Join(backupDir, id+".json")
+	return backup, os.WriteFile(backup, data, 0644)
+}
+
+// clearConflict forgets a resolved conflict
+func clearConflict(id string) error {
+	for _, name := range []string{id + ".json", id + removedSuffix} {
+		if err := os.Remove(filepath.Join(conflictDir(), name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
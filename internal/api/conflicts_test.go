@@ -0,0 +1,132 @@
+// Tests for resolving sync conflicts
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	tempDir := t.TempDir()
+	origGetConfigDir, origUtilsConfigDir, origOpen := getConfigDir, utils.GetConfigDir, usertests.Open
+	defer func() {
+		getConfigDir, utils.GetConfigDir, usertests.Open = origGetConfigDir, origUtilsConfigDir, origOpen
+	}()
+	getConfigDir = func() string { return tempDir }
+	utils.GetConfigDir = func() string { return tempDir }
+	dbPath := filepath.Join(tempDir, storage.DBFileName)
+	usertests.Open = func() *usertests.Suite {
+		return usertests.NewSuite(storage.NewSQLiteStore(dbPath))
+	}
+
+	problemsDir := filepath.Join(tempDir, "problems")
+	encode := func(p problem.Problem) []byte {
+		data, err := json.MarshalIndent(p, "", "  ")
+		require.NoError(t, err)
+		return data
+	}
+
+	// A synced problem the user then edits, while the server changes it too
+	synced := getSampleProblems().Problems[0]
+	state := loadSyncState()
+	require.NoError(t, saveProblem(problemsDir, synced, encode(synced), state))
+	require.NoError(t, state.save())
+
+	local := synced
+	local.Constraints = append(append([]string{}, synced.Constraints...), "Watch out for duplicates")
+	local.TestCases = append(append([]problem.TestCase{}, synced.TestCases...), problem.TestCase{Input: "[1,5,9], 14", Expected: "[1,2]"})
+	localFile := filepath.Join(problemsDir, synced.Patterns[0], synced.ID+".json")
+	require.NoError(t, os.WriteFile(localFile, encode(local), 0644))
+
+	remote := synced
+	remote.Title = "Two Sum II"
+	require.NoError(t, saveConflict(conflictDir(), synced.ID, encode(remote)))
+
+	// A problem the user edited that the server removed
+	removed := getSampleProblems().Problems[1]
+	require.NoError(t, saveProblem(problemsDir, removed, encode(removed), state))
+	require.NoError(t, state.save())
+	require.NoError(t, saveRemovalConflict(conflictDir(), removed.ID))
+
+	conflicts, err := ListConflicts()
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	byID := map[string]Conflict{conflicts[0].ProblemID: conflicts[0], conflicts[1].ProblemID: conflicts[1]}
+
+	changed := byID[synced.ID]
+	assert.False(t, changed.Removed)
+	assert.Contains(t, changed.Differences(), `renamed to "Two Sum II"`)
+	tests, notes := changed.Additions()
+	assert.Len(t, tests, 1)
+	assert.Equal(t, []string{"Constraint: Watch out for duplicates"}, notes)
+	assert.True(t, byID[removed.ID].Removed)
+
+	ctx := context.Background()
+
+	t.Run("Merge", func(t *testing.T) {
+		result, err := ResolveConflict(ctx, synced.ID, MergeOverlays)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.TestsAdded)
+		assert.Equal(t, 1, result.NotesAdded)
+		assert.FileExists(t, result.Backup)
+
+		// The server's copy replaced the user's
+		data, err := os.ReadFile(localFile)
+		require.NoError(t, err)
+		assert.Equal(t, encode(remote), data)
+
+		// Their additions live on outside it
+		suite := usertests.Open()
+		defer suite.Close()
+		userTests, err := suite.List(ctx, synced.ID)
+		require.NoError(t, err)
+		require.Len(t, userTests, 1)
+		assert.Equal(t, "[1,5,9], 14", userTests[0].Input)
+		overlay, ok := problem.LoadOverlay(synced.ID)
+		require.True(t, ok)
+		assert.Equal(t, []string{"Constraint: Watch out for duplicates"}, overlay.Notes)
+
+		// And the file no longer counts as modified
+		assert.False(t, locallyModified(problemsDir, synced.ID, loadSyncState()))
+	})
+
+	t.Run("NoMergeForRemoved", func(t *testing.T) {
+		_, err := ResolveConflict(ctx, removed.ID, MergeOverlays)
+		assert.ErrorContains(t, err, "nothing to merge into")
+	})
+
+	t.Run("TakeRemoval", func(t *testing.T) {
+		_, err := ResolveConflict(ctx, removed.ID, TakeRemote)
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(problemsDir, removed.Patterns[0], removed.ID+".json"))
+
+		conflicts, err := ListConflicts()
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("KeepLocal", func(t *testing.T) {
+		require.NoError(t, saveConflict(conflictDir(), synced.ID, encode(remote)))
+		require.NoError(t, os.WriteFile(localFile, encode(local), 0644))
+
+		_, err := ResolveConflict(ctx, synced.ID, KeepLocal)
+		require.NoError(t, err)
+		data, err := os.ReadFile(localFile)
+		require.NoError(t, err)
+		assert.Equal(t, encode(local), data)
+
+		_, err = ResolveConflict(ctx, synced.ID, KeepLocal)
+		assert.ErrorContains(t, err, "no sync conflict")
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GalleryEntry is an anonymous solution in a problem's hall of fame
+type GalleryEntry struct {
+	ID          string    `json:"id,omitempty"`
+	ProblemID   string    `json:"problem_id"`
+	Language    string    `json:"language"`
+	Code        string    `json:"code"`
+	Upvotes     int       `json:"upvotes,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Upvoted     bool      `json:"upvoted,omitempty"` // Whether the caller upvoted the entry
+	Mine        bool      `json:"mine,omitempty"`    // Whether the caller published the entry
+}
+
+// PublishToHallOfFame adds a solution to its problem's hall of fame and
+// returns the entry ID
+// Exported as variable for testing
+var PublishToHallOfFame = func(ctx context.Context, entry GalleryEntry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/gallery/entries", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server rejected solution (%d): %s", resp.StatusCode, body.Error)
+	}
+	return body.ID, nil
+}
+
+// GetHallOfFame fetches a problem's hall of fame, most upvoted first
+func GetHallOfFame(ctx context.Context, problemID string) ([]GalleryEntry, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/problems/"+url.PathEscape(problemID)+"/gallery", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Entries []GalleryEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid server response: %v", err)
+	}
+	return body.Entries, nil
+}
+
+// Upvote upvotes another user's hall-of-fame entry and returns its new
+// upvote count
+func Upvote(ctx context.Context, entryID string) (int, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/gallery/entries/"+url.PathEscape(entryID)+"/upvote", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Upvotes int    `json:"upvotes"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("server rejected upvote (%d): %s", resp.StatusCode, body.Error)
+	}
+	return body.Upvotes, nil
+}
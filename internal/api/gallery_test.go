@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHallOfFameClient(t *testing.T) {
+	var published GalleryEntry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gallery/entries":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&published))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"hof-1"}`))
+		case "/problems/jump-game/gallery":
+			w.Write([]byte(`{"entries":[{"id":"hof-2","problem_id":"jump-game","language":"python","code":"def can_jump(): pass","upvotes":3,"upvoted":true}]}`))
+		case "/gallery/entries/hof-2/upvote":
+			w.Write([]byte(`{"upvotes":4}`))
+		case "/gallery/entries/hof-1/upvote":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"You cannot upvote your own solution"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	ctx := context.Background()
+
+	id, err := PublishToHallOfFame(ctx, GalleryEntry{ProblemID: "jump-game", Language: "go", Code: "func canJump() {}"})
+	require.NoError(t, err)
+	assert.Equal(t, "hof-1", id)
+	assert.Equal(t, "func canJump() {}", published.Code)
+
+	entries, err := GetHallOfFame(ctx, "jump-game")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 3, entries[0].Upvotes)
+	assert.True(t, entries[0].Upvoted)
+
+	upvotes, err := Upvote(ctx, "hof-2")
+	require.NoError(t, err)
+	assert.Equal(t, 4, upvotes)
+
+	_, err = Upvote(ctx, "hof-1")
+	assert.ErrorContains(t, err, "You cannot upvote your own solution")
+}
@@ -0,0 +1,75 @@
+// Single problem lookups for the local problem cache
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	pb "github.com/lancekrogers/algo-scales/internal/rpc/algoscalesv1"
+)
+
+// FetchProblem retrieves one problem from the configured server. It fails
+// without a valid license or when no server is configured, so callers can
+// treat any error as "not available offline".
+func FetchProblem(ctx context.Context, id string) (*problem.Problem, error) {
+	valid, err := license.ValidateLicense()
+	if err != nil || !valid {
+		return nil, fmt.Errorf("invalid license: %v", err)
+	}
+
+	if addr := os.Getenv(GRPCAddrEnv); addr != "" {
+		return fetchProblemGRPC(ctx, addr, id)
+	}
+	if os.Getenv(APIURLEnv) != "" {
+		return fetchProblemREST(ctx, id)
+	}
+	return nil, fmt.Errorf("no problem server configured")
+}
+
+// fetchProblemGRPC looks up a problem over the gRPC API
+func fetchProblemGRPC(ctx context.Context, addr, id string) (*problem.Problem, error) {
+	client, closeConn, err := dialAlgoScales(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	resp, err := client.GetProblem(withAuthMetadata(ctx), &pb.GetProblemRequest{Id: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch problem %s: %v", id, err)
+	}
+
+	p := problemFromProto(resp)
+	return &p, nil
+}
+
+// fetchProblemREST looks up a problem over the REST API
+func fetchProblemREST(ctx context.Context, id string) (*problem.Problem, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/problems/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch problem %s: server returned %s", id, resp.Status)
+	}
+
+	var p problem.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid problem response: %v", err)
+	}
+	return &p, nil
+}
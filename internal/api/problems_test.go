@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchProblem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer LICENSE-test", r.Header.Get("Authorization"))
+		if r.URL.Path != "/problems/two-sum" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Problem not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(getSampleProblems().Problems[0])
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	t.Run("NoServerConfigured", func(t *testing.T) {
+		defer mockLicenseValidation(true, nil)()
+		t.Setenv(GRPCAddrEnv, "")
+		t.Setenv(APIURLEnv, "")
+		_, err := FetchProblem(context.Background(), "two-sum")
+		require.Error(t, err)
+	})
+
+	t.Run("Unlicensed", func(t *testing.T) {
+		defer mockLicenseValidation(false, nil)()
+		t.Setenv(APIURLEnv, server.URL)
+		_, err := FetchProblem(context.Background(), "two-sum")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid license")
+	})
+
+	t.Run("REST", func(t *testing.T) {
+		defer mockLicenseValidation(true, nil)()
+		t.Setenv(GRPCAddrEnv, "")
+		t.Setenv(APIURLEnv, server.URL)
+
+		p, err := FetchProblem(context.Background(), "two-sum")
+		require.NoError(t, err)
+		assert.Equal(t, "two-sum", p.ID)
+
+		_, err = FetchProblem(context.Background(), "missing")
+		require.Error(t, err)
+	})
+}
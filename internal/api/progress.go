@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ProgressRecord is one piece of progress synced between machines, such as
+// a session or a streak. Of two versions of a record, identified by kind and
+// key, the one updated last wins.
+type ProgressRecord struct {
+	Kind      string          `json:"kind"`
+	Key       string          `json:"key"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ProgressSnapshot is all the progress synced to the server
+type ProgressSnapshot struct {
+	Records   []ProgressRecord `json:"records"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// PushProgress sends progress records to the server, which keeps the newer
+// version of each, and returns everything synced from all machines
+// Exported as variable for testing
+var PushProgress = func(ctx context.Context, records []ProgressRecord) (ProgressSnapshot, error) {
+	if records == nil {
+		records = []ProgressRecord{}
+	}
+	data, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return ProgressSnapshot{}, err
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/progress", bytes.NewReader(data))
+	if err != nil {
+		return ProgressSnapshot{}, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return ProgressSnapshot{}, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProgressSnapshot{}, fmt.Errorf("server rejected progress (%d)", resp.StatusCode)
+	}
+
+	var snapshot ProgressSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return ProgressSnapshot{}, fmt.Errorf("invalid progress response: %v", err)
+	}
+	return snapshot, nil
+}
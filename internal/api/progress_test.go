@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushProgress(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	var received struct {
+		Records []ProgressRecord `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/progress" || r.Header.Get("Authorization") != "Bearer LICENSE-test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		json.NewEncoder(w).Encode(ProgressSnapshot{
+			Records:   append(received.Records, ProgressRecord{Kind: "streak", Key: "daily", UpdatedAt: updated, Data: json.RawMessage(`{"Current":3}`)}),
+			UpdatedAt: updated,
+		})
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	snapshot, err := PushProgress(context.Background(), []ProgressRecord{
+		{Kind: "session", Key: "two-sum@2026-10-01T08:00:00Z", UpdatedAt: updated, Data: json.RawMessage(`{"Solved":true}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, received.Records, 1)
+	assert.Equal(t, "session", received.Records[0].Kind)
+	require.Len(t, snapshot.Records, 2)
+	assert.Equal(t, "daily", snapshot.Records[1].Key)
+	assert.JSONEq(t, `{"Current":3}`, string(snapshot.Records[1].Data))
+}
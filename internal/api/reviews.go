@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ReviewSubmission is a solution shared for anonymous peer review
+type ReviewSubmission struct {
+	ID          string    `json:"id,omitempty"`
+	ProblemID   string    `json:"problem_id"`
+	Language    string    `json:"language"`
+	Code        string    `json:"code"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Review is anonymous feedback on a shared solution
+type Review struct {
+	SubmissionID string    `json:"submission_id,omitempty"`
+	ProblemID    string    `json:"problem_id,omitempty"`
+	Rating       int       `json:"rating"` // 1-5
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SubmitForReview queues a solution for peer review and returns its ID
+// Exported as variable for testing
+var SubmitForReview = func(ctx context.Context, sub ReviewSubmission) (string, error) {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/reviews/submissions", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("server rejected submission (%d): %s", resp.StatusCode, body.Error)
+	}
+	return body.ID, nil
+}
+
+// NextReview fetches another user's solution to review. An empty problem ID
+// accepts any problem the caller has shared a solution for. It returns nil
+// when no solutions are waiting.
+func NextReview(ctx context.Context, problemID string) (*ReviewSubmission, error) {
+	path := "/reviews/next"
+	if problemID != "" {
+		path += "?problem_id=" + url.QueryEscape(problemID)
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	var body struct {
+		ReviewSubmission
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d: %s", resp.StatusCode, body.Error)
+	}
+	return &body.ReviewSubmission, nil
+}
+
+// SendReview posts a review of another user's solution
+func SendReview(ctx context.Context, submissionID string, review Review) error {
+	data, err := json.Marshal(review)
+	if err != nil {
+		return err
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/reviews/submissions/"+url.PathEscape(submissionID)+"/reviews", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var body struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("server rejected review (%d): %s", resp.StatusCode, body.Error)
+	}
+	return nil
+}
+
+// GetReviewInbox fetches the reviews other users wrote of the caller's
+// solutions, newest first
+func GetReviewInbox(ctx context.Context) ([]Review, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/reviews/inbox", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Reviews []Review `json:"reviews"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid server response: %v", err)
+	}
+	return body.Reviews, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerReviewClient(t *testing.T) {
+	var submitted ReviewSubmission
+	var reviewed Review
+	waiting := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/reviews/submissions":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"rev-1"}`))
+		case "/reviews/next":
+			assert.Equal(t, "two-sum", r.URL.Query().Get("problem_id"))
+			if !waiting {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"No solutions are waiting for review"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"rev-2","problem_id":"two-sum","language":"python","code":"def two_sum(): pass"}`))
+		case "/reviews/submissions/rev-2/reviews":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reviewed))
+			w.WriteHeader(http.StatusCreated)
+		case "/reviews/inbox":
+			w.Write([]byte(`{"reviews":[{"submission_id":"rev-1","problem_id":"two-sum","rating":4,"comment":"Nice"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	ctx := context.Background()
+
+	id, err := SubmitForReview(ctx, ReviewSubmission{ProblemID: "two-sum", Language: "go", Code: "func twoSum() {}"})
+	require.NoError(t, err)
+	assert.Equal(t, "rev-1", id)
+	assert.Equal(t, "func twoSum() {}", submitted.Code)
+
+	next, err := NextReview(ctx, "two-sum")
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "rev-2", next.ID)
+	assert.Equal(t, "python", next.Language)
+
+	require.NoError(t, SendReview(ctx, next.ID, Review{Rating: 5, Comment: "Clean"}))
+	assert.Equal(t, 5, reviewed.Rating)
+	assert.Equal(t, "Clean", reviewed.Comment)
+
+	// Nothing waiting is not an error
+	waiting = false
+	next, err = NextReview(ctx, "two-sum")
+	require.NoError(t, err)
+	assert.Nil(t, next)
+
+	reviews, err := GetReviewInbox(ctx)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "Nice", reviews[0].Comment)
+}
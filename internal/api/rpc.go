@@ -0,0 +1,128 @@
+// gRPC client for problem sync
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	pb "github.com/lancekrogers/algo-scales/internal/rpc/algoscalesv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCAddrEnv names the environment variable holding the gRPC server address.
+// When unset, problem sync falls back to the bundled sample problems.
+const GRPCAddrEnv = "ALGO_SCALES_GRPC_ADDR"
+
+// syncTimeout bounds a single sync call
+const syncTimeout = 30 * time.Second
+
+// dialAlgoScales connects to the gRPC API
+// Exported as variable for testing
+var dialAlgoScales = func(addr string) (pb.AlgoScalesClient, func() error, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+	return pb.NewAlgoScalesClient(conn), conn.Close, nil
+}
+
+// fetchProblemSet returns the latest problem set, using the gRPC API or the
+// REST bundle endpoint when configured.
+// Unless forced, only a version newer than the local one is downloaded.
+func fetchProblemSet(force bool) (ProblemSet, error) {
+	addr := os.Getenv(GRPCAddrEnv)
+	if addr == "" {
+		if os.Getenv(APIURLEnv) != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+			defer cancel()
+			return fetchBundle(ctx, force)
+		}
+		return getSampleProblems(), nil
+	}
+
+	client, closeConn, err := dialAlgoScales(addr)
+	if err != nil {
+		return ProblemSet{}, err
+	}
+	defer closeConn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	ctx = withAuthMetadata(ctx)
+
+	req := &pb.SyncRequest{}
+	if !force {
+		req.SinceVersion = localVersion()
+	}
+
+	resp, err := client.Sync(ctx, req)
+	if err != nil {
+		return ProblemSet{}, fmt.Errorf("failed to sync problems: %v", err)
+	}
+
+	set := ProblemSet{Version: resp.GetVersion(), LastUpdated: time.Now(), UpToDate: resp.GetUpToDate()}
+	for _, p := range resp.GetProblems() {
+		set.Problems = append(set.Problems, problemFromProto(p))
+	}
+	return set, nil
+}
+
+// withAuthMetadata attaches the stored login or license to outgoing gRPC
+// calls, see bearerToken
+func withAuthMetadata(ctx context.Context) context.Context {
+	if token, err := bearerToken(ctx); err == nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	return ctx
+}
+
+// localVersion returns the problem set version stored by the last sync
+func localVersion() string {
+	data, err := os.ReadFile(filepath.Join(getConfigDir(), "version.json"))
+	if err != nil {
+		return ""
+	}
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return ""
+	}
+	return version.Version
+}
+
+// problemFromProto converts a wire problem to the local representation
+func problemFromProto(p *pb.Problem) problem.Problem {
+	out := problem.Problem{
+		ID:                  p.GetId(),
+		Title:               p.GetTitle(),
+		Difficulty:          p.GetDifficulty(),
+		Patterns:            p.GetPatterns(),
+		EstimatedTime:       int(p.GetEstimatedTime()),
+		Companies:           p.GetCompanies(),
+		Description:         p.GetDescription(),
+		Constraints:         p.GetConstraints(),
+		PatternExplanation:  p.GetPatternExplanation(),
+		SolutionWalkthrough: p.GetSolutionWalkthrough(),
+		StarterCode:         p.GetStarterCode(),
+		Solutions:           p.GetSolutions(),
+		Version:             p.GetVersion(),
+	}
+	for _, e := range p.GetExamples() {
+		out.Examples = append(out.Examples, problem.Example{Input: e.GetInput(), Output: e.GetOutput(), Explanation: e.GetExplanation()})
+	}
+	for _, tc := range p.GetTestCases() {
+		out.TestCases = append(out.TestCases, problem.TestCase{Input: tc.GetInput(), Expected: tc.GetExpected()})
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+// Tests for the gRPC sync client
+
+package api
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	pb "github.com/lancekrogers/algo-scales/internal/rpc/algoscalesv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeSyncServer records sync requests and serves a fixed problem set
+type fakeSyncServer struct {
+	pb.UnimplementedAlgoScalesServer
+	sinceVersion  string
+	authorization []string
+}
+
+func (f *fakeSyncServer) Sync(ctx context.Context, req *pb.SyncRequest) (*pb.SyncResponse, error) {
+	f.sinceVersion = req.GetSinceVersion()
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.authorization = md.Get("authorization")
+
+	if req.GetSinceVersion() == "2.0.0" {
+		return &pb.SyncResponse{Version: "2.0.0", UpToDate: true}, nil
+	}
+	return &pb.SyncResponse{
+		Version: "2.0.0",
+		Problems: []*pb.Problem{{
+			Id:         "two-sum",
+			Title:      "Two Sum",
+			Difficulty: "Easy",
+			Patterns:   []string{"hash-map"},
+			Examples:   []*pb.Example{{Input: "[2,7], 9", Output: "[0,1]"}},
+			TestCases:  []*pb.TestCase{{Input: "[2,7], 9", Expected: "[0,1]"}},
+			StarterCode: map[string]string{
+				"go": "func twoSum(nums []int, target int) []int {}",
+			},
+		}},
+	}, nil
+}
+
+// useFakeSyncServer routes the gRPC client to an in-memory server
+func useFakeSyncServer(t *testing.T, fake *fakeSyncServer) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterAlgoScalesServer(s, fake)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	origDial := dialAlgoScales
+	t.Cleanup(func() { dialAlgoScales = origDial })
+	dialAlgoScales = func(addr string) (pb.AlgoScalesClient, func() error, error) {
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, nil, err
+		}
+		return pb.NewAlgoScalesClient(conn), conn.Close, nil
+	}
+
+	t.Setenv(GRPCAddrEnv, "bufnet")
+}
+
+func TestFetchProblemSet(t *testing.T) {
+	tempDir := t.TempDir()
+	origGetConfigDir := getConfigDir
+	defer func() { getConfigDir = origGetConfigDir }()
+	getConfigDir = func() string {
+		return tempDir
+	}
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	t.Run("WithoutServerUsesSamples", func(t *testing.T) {
+		t.Setenv(GRPCAddrEnv, "")
+		set, err := fetchProblemSet(false)
+		require.NoError(t, err)
+		assert.Equal(t, getSampleProblems().Version, set.Version)
+		assert.NotEmpty(t, set.Problems)
+	})
+
+	t.Run("FullSync", func(t *testing.T) {
+		fake := &fakeSyncServer{}
+		useFakeSyncServer(t, fake)
+
+		set, err := fetchProblemSet(false)
+		require.NoError(t, err)
+		assert.Equal(t, "", fake.sinceVersion)
+		assert.Equal(t, []string{"Bearer LICENSE-test"}, fake.authorization)
+		assert.False(t, set.UpToDate)
+		require.Len(t, set.Problems, 1)
+
+		p := set.Problems[0]
+		assert.Equal(t, "two-sum", p.ID)
+		assert.Equal(t, []string{"hash-map"}, p.Patterns)
+		assert.Equal(t, "[0,1]", p.TestCases[0].Expected)
+		assert.Contains(t, p.StarterCode, "go")
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		fake := &fakeSyncServer{}
+		useFakeSyncServer(t, fake)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "version.json"), []byte(`{"version":"2.0.0"}`), 0644))
+
+		set, err := fetchProblemSet(false)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", fake.sinceVersion)
+		assert.True(t, set.UpToDate)
+
+		// Forcing ignores the local version
+		_, err = fetchProblemSet(true)
+		require.NoError(t, err)
+		assert.Equal(t, "", fake.sinceVersion)
+	})
+}
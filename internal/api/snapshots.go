@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxSnapshotBytes bounds the size of a downloaded snapshot
+const maxSnapshotBytes = 256 * 1024
+
+// UploadSnapshot stores a sealed snapshot on the server and returns the URL
+// it can be downloaded from
+// Exported as variable for testing
+var UploadSnapshot = func(ctx context.Context, sealed []byte) (string, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/snapshots", bytes.NewReader(sealed))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("server rejected snapshot (%d): %s", resp.StatusCode, body.Error)
+	}
+	return SnapshotURL(body.ID), nil
+}
+
+// SnapshotURL returns the URL of a snapshot stored on the server
+func SnapshotURL(id string) string {
+	return baseURL + "/snapshots/" + url.PathEscape(id)
+}
+
+// DownloadSnapshot fetches a sealed snapshot by its URL or server ID.
+// Snapshots need no license to download.
+// Exported as variable for testing
+var DownloadSnapshot = func(ctx context.Context, location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		location = SnapshotURL(location)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("snapshot not found; snapshots expire 30 days after they are shared")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSnapshotBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %v", err)
+	}
+	if len(data) > maxSnapshotBytes {
+		return nil, fmt.Errorf("snapshot is larger than %d bytes", maxSnapshotBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotClient(t *testing.T) {
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/snapshots":
+			assert.Equal(t, "Bearer LICENSE-test", r.Header.Get("Authorization"))
+			uploaded, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"abc123"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/snapshots/abc123":
+			assert.Empty(t, r.Header.Get("Authorization"), "snapshots download without a license")
+			w.Write(uploaded)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	ctx := context.Background()
+
+	location, err := UploadSnapshot(ctx, []byte("sealed"))
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/snapshots/abc123", location)
+	assert.Equal(t, "sealed", string(uploaded))
+
+	// By URL or by ID
+	for _, ref := range []string{location, "abc123"} {
+		data, err := DownloadSnapshot(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, "sealed", string(data))
+	}
+
+	_, err = DownloadSnapshot(ctx, "missing")
+	assert.ErrorContains(t, err, "not found")
+}
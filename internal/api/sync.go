@@ -0,0 +1,334 @@
+// Incremental problem sync that keeps local edits to problem files
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// SyncOptions controls an incremental problem sync
+type SyncOptions struct {
+	// Overwrite replaces locally modified problems instead of keeping them
+	Overwrite bool
+}
+
+// SyncResult summarizes an incremental problem sync
+type SyncResult struct {
+	Version  string
+	UpToDate bool
+	Added    []string
+	Updated  []string
+	Removed  []string
+
+	// Conflicts lists locally modified problems that were kept. The server's
+	// copy of each is saved in ConflictDir for comparison.
+	Conflicts   []string
+	ConflictDir string
+
+	// Skipped describes incoming problems that failed validation
+	Skipped []string
+}
+
+// problemUpdates is the server's list of changes since a version
+type problemUpdates struct {
+	Version  string            `json:"version"`
+	Problems []json.RawMessage `json:"problems"`
+	Removed  []string          `json:"removed"`
+}
+
+// syncState records the hash of every problem file a sync wrote, keyed by
+// problem ID and then by path within the problems directory, so later syncs
+// can tell which files the user has edited since
+type syncState struct {
+	Problems map[string]map[string]string `json:"problems"`
+}
+
+// SyncProblems merges the problems changed on the server since the local
+// version into the local problem repository. Locally modified problems are
+// left alone unless opts.Overwrite is set.
+// Exported as variable for testing
+var SyncProblems = func(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	valid, err := license.ValidateLicense()
+	if err != nil || !valid {
+		return nil, fmt.Errorf("invalid license: %v", err)
+	}
+
+	set, removed, err := fetchProblemUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &SyncResult{
+		Version:     set.Version,
+		Skipped:     set.Skipped,
+		ConflictDir: conflictDir(),
+	}
+	if set.UpToDate {
+		result.UpToDate = true
+		return result, nil
+	}
+
+	problemsDir := filepath.Join(getConfigDir(), "problems")
+	if err := os.MkdirAll(problemsDir, 0755); err != nil {
+		return nil, err
+	}
+	state := loadSyncState()
+	solved := solvedProblemIDs()
+
+	for _, p := range set.Problems {
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		exists := hasProblemFiles(problemsDir, p, state)
+		if exists && !opts.Overwrite && locallyModified(problemsDir, p.ID, state) {
+			if err := saveConflict(result.ConflictDir, p.ID, data); err != nil {
+				return nil, err
+			}
+			result.Conflicts = append(result.Conflicts, p.ID)
+			continue
+		}
+		if exists && unchanged(problemsDir, p, state, data) {
+			continue
+		}
+
+		if solved[p.ID] {
+			notifyProblemChanges(problemsDir, p)
+		}
+		if err := removeProblemFiles(problemsDir, p.ID, state); err != nil {
+			return nil, err
+		}
+		if err := saveProblem(problemsDir, p, data, state); err != nil {
+			return nil, err
+		}
+		if !exists {
+			result.Added = append(result.Added, p.ID)
+		} else {
+			result.Updated = append(result.Updated, p.ID)
+		}
+	}
+
+	for _, id := range removed {
+		if len(state.Problems[id]) == 0 {
+			continue
+		}
+		if !opts.Overwrite && locallyModified(problemsDir, id, state) {
+			if err := saveRemovalConflict(result.ConflictDir, id); err != nil {
+				return nil, err
+			}
+			result.Conflicts = append(result.Conflicts, id)
+			continue
+		}
+		if err := removeProblemFiles(problemsDir, id, state); err != nil {
+			return nil, err
+		}
+		result.Removed = append(result.Removed, id)
+	}
+
+	if err := state.save(); err != nil {
+		return nil, err
+	}
+	if err := writeVersionFile(set.Version); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fetchProblemUpdates asks the REST API for the problems changed since the
+// local version, returning them with the IDs of removed problems. Without a
+// REST API the whole set from fetchProblemSet is used instead.
+func fetchProblemUpdates(ctx context.Context) (ProblemSet, []string, error) {
+	if os.Getenv(APIURLEnv) == "" {
+		set, err := fetchProblemSet(false)
+		return set, nil, err
+	}
+
+	since := localVersion()
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/problems/updates?since="+url.QueryEscape(since), nil)
+	if err != nil {
+		return ProblemSet{}, nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return ProblemSet{}, nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ProblemSet{}, nil, fmt.Errorf("failed to fetch problem updates: server returned %s", resp.Status)
+	}
+
+	var updates problemUpdates
+	if err := json.NewDecoder(resp.Body).Decode(&updates); err != nil {
+		return ProblemSet{}, nil, fmt.Errorf("invalid problem updates: %v", err)
+	}
+
+	set := ProblemSet{Version: updates.Version, LastUpdated: time.Now()}
+	set.UpToDate = updates.Version == since && len(updates.Problems) == 0 && len(updates.Removed) == 0
+	set.Problems, set.Skipped = decodeBundleProblems(updates.Problems)
+	return set, updates.Removed, nil
+}
+
+// saveProblem writes a problem to each of its pattern directories and
+// records the files in the sync state
+func saveProblem(problemsDir string, p problem.Problem, data []byte, state *syncState) error {
+	files := make(map[string]string, len(p.Patterns))
+	for _, pattern := range p.Patterns {
+		patternDir := filepath.Join(problemsDir, pattern)
+		if err := os.MkdirAll(patternDir, 0755); err != nil {
+			return err
+		}
+
+		rel := filepath.Join(pattern, fmt.Sprintf("%s.json", p.ID))
+		if err := os.WriteFile(filepath.Join(problemsDir, rel), data, 0644); err != nil {
+			return err
+		}
+		files[rel] = hashBytes(data)
+	}
+	state.Problems[p.ID] = files
+	return nil
+}
+
+// hasProblemFiles reports whether a problem is already on disk, either in
+// files a sync recorded or at the problem's pattern paths
+func hasProblemFiles(problemsDir string, p problem.Problem, state *syncState) bool {
+	var paths []string
+	for rel := range state.Problems[p.ID] {
+		paths = append(paths, rel)
+	}
+	for _, pattern := range p.Patterns {
+		paths = append(paths, filepath.Join(pattern, fmt.Sprintf("%s.json", p.ID)))
+	}
+
+	for _, rel := range paths {
+		if _, err := os.Stat(filepath.Join(problemsDir, rel)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// locallyModified reports whether any file a sync wrote for a problem has
+// been edited since. Files written before sync state was kept count as
+// unmodified.
+func locallyModified(problemsDir, id string, state *syncState) bool {
+	for rel, hash := range state.Problems[id] {
+		data, err := os.ReadFile(filepath.Join(problemsDir, rel))
+		if err != nil {
+			continue
+		}
+		if hashBytes(data) != hash {
+			return true
+		}
+	}
+	return false
+}
+
+// unchanged reports whether a problem's files already hold data, with no
+// files left over from patterns the problem no longer has
+func unchanged(problemsDir string, p problem.Problem, state *syncState, data []byte) bool {
+	if len(state.Problems[p.ID]) != len(p.Patterns) {
+		return false
+	}
+	for _, pattern := range p.Patterns {
+		rel := filepath.Join(pattern, fmt.Sprintf("%s.json", p.ID))
+		if _, ok := state.Problems[p.ID][rel]; !ok {
+			return false
+		}
+		current, err := os.ReadFile(filepath.Join(problemsDir, rel))
+		if err != nil || !bytes.Equal(current, data) {
+			return false
+		}
+	}
+	return true
+}
+
+// removeProblemFiles deletes the files a sync wrote for a problem
+func removeProblemFiles(problemsDir, id string, state *syncState) error {
+	for rel := range state.Problems[id] {
+		if err := os.Remove(filepath.Join(problemsDir, rel)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	delete(state.Problems, id)
+	return nil
+}
+
+// saveConflict keeps the server's copy of a problem the user has modified.
+// It is stored outside the problems directory so it is never loaded as a
+// problem itself.
+func saveConflict(conflictDir, id string, data []byte) error {
+	if err := os.MkdirAll(conflictDir, 0755); err != nil {
+		return err
+	}
+	os.Remove(filepath.Join(conflictDir, id+removedSuffix))
+	return os.WriteFile(filepath.Join(conflictDir, fmt.Sprintf("%s.json", id)), data, 0644)
+}
+
+// saveRemovalConflict records that the server removed a problem the user
+// has modified
+func saveRemovalConflict(conflictDir, id string) error {
+	if err := os.MkdirAll(conflictDir, 0755); err != nil {
+		return err
+	}
+	os.Remove(filepath.Join(conflictDir, fmt.Sprintf("%s.json", id)))
+	return os.WriteFile(filepath.Join(conflictDir, id+removedSuffix), nil, 0644)
+}
+
+// loadSyncState reads the sync state, starting empty when there is none
+func loadSyncState() *syncState {
+	state := &syncState{}
+	if data, err := os.ReadFile(syncStatePath()); err == nil {
+		_ = json.Unmarshal(data, state)
+	}
+	if state.Problems == nil {
+		state.Problems = make(map[string]map[string]string)
+	}
+	return state
+}
+
+// save writes the sync state
+func (s *syncState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(syncStatePath(), data, 0644)
+}
+
+// syncStatePath returns the location of the sync state file
+func syncStatePath() string {
+	return filepath.Join(getConfigDir(), "sync-state.json")
+}
+
+// writeVersionFile records the problem set version the local files are at
+func writeVersionFile(version string) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"version":      version,
+		"last_updated": time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(getConfigDir(), "version.json"), data, 0644)
+}
+
+// hashBytes returns the hex SHA-256 of data
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
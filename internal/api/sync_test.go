@@ -0,0 +1,152 @@
+// Tests for incremental problem sync
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncProblems(t *testing.T) {
+	tempDir := t.TempDir()
+	origGetConfigDir := getConfigDir
+	defer func() { getConfigDir = origGetConfigDir }()
+	getConfigDir = func() string {
+		return tempDir
+	}
+	origInboxPath := notifications.GetInboxPath
+	defer func() { notifications.GetInboxPath = origInboxPath }()
+	notifications.GetInboxPath = func() string {
+		return filepath.Join(tempDir, "notifications.json")
+	}
+	origGetAllSessions := stats.GetAllSessions
+	defer func() { stats.GetAllSessions = origGetAllSessions }()
+	stats.GetAllSessions = func() ([]stats.SessionStats, error) {
+		return nil, nil
+	}
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+	restore := mockLicenseValidation(true, nil)
+	defer restore()
+
+	// The fake server answers with whatever the current step sets
+	var (
+		version   string
+		changed   []problem.Problem
+		removed   []string
+		lastSince string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/problems/updates" || r.Header.Get("Authorization") != "Bearer LICENSE-test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lastSince = r.URL.Query().Get("since")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"version":  version,
+			"problems": changed,
+			"removed":  removed,
+		})
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+	t.Setenv(APIURLEnv, server.URL)
+
+	sample := getSampleProblems().Problems
+	twoSum, maxSubarray := sample[0], sample[1]
+	threeSum := twoSum
+	threeSum.ID, threeSum.Title = "three-sum", "Three Sum"
+
+	problemsDir := filepath.Join(tempDir, "problems")
+	twoSumFile := filepath.Join(problemsDir, "hash-map", "two-sum.json")
+	readTitle := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var p problem.Problem
+		require.NoError(t, json.Unmarshal(data, &p))
+		return p.Title
+	}
+
+	t.Run("InitialSync", func(t *testing.T) {
+		version, changed, removed = "1.0.0", []problem.Problem{twoSum, maxSubarray}, nil
+		result, err := SyncProblems(context.Background(), SyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "", lastSince)
+		assert.ElementsMatch(t, []string{"two-sum", "max-subarray"}, result.Added)
+		assert.Equal(t, "1.0.0", localVersion())
+		assert.FileExists(t, twoSumFile)
+	})
+
+	t.Run("UpToDate", func(t *testing.T) {
+		changed = nil
+		result, err := SyncProblems(context.Background(), SyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", lastSince)
+		assert.True(t, result.UpToDate)
+	})
+
+	t.Run("LocalEditsAreKept", func(t *testing.T) {
+		// The user edits their copy of two-sum
+		data, err := os.ReadFile(twoSumFile)
+		require.NoError(t, err)
+		var local problem.Problem
+		require.NoError(t, json.Unmarshal(data, &local))
+		local.Title = "My Two Sum"
+		data, err = json.MarshalIndent(local, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(twoSumFile, data, 0644))
+
+		updatedTwoSum, updatedMax := twoSum, maxSubarray
+		updatedTwoSum.Title = "Two Sum II"
+		updatedMax.Title = "Maximum Subarray II"
+		version, changed = "1.1.0", []problem.Problem{updatedTwoSum, updatedMax, threeSum}
+
+		result, err := SyncProblems(context.Background(), SyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"three-sum"}, result.Added)
+		assert.Equal(t, []string{"max-subarray"}, result.Updated)
+		assert.Equal(t, []string{"two-sum"}, result.Conflicts)
+		assert.Equal(t, "1.1.0", localVersion())
+
+		assert.Equal(t, "My Two Sum", readTitle(twoSumFile))
+		assert.Equal(t, "Two Sum II", readTitle(filepath.Join(result.ConflictDir, "two-sum.json")))
+		assert.Equal(t, "Maximum Subarray II", readTitle(filepath.Join(problemsDir, "sliding-window", "max-subarray.json")))
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		version, changed, removed = "1.2.0", nil, []string{"two-sum", "three-sum"}
+		result, err := SyncProblems(context.Background(), SyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"three-sum"}, result.Removed)
+		assert.Equal(t, []string{"two-sum"}, result.Conflicts)
+		assert.FileExists(t, twoSumFile)
+		assert.NoFileExists(t, filepath.Join(problemsDir, "hash-map", "three-sum.json"))
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		version, changed, removed = "1.3.0", []problem.Problem{twoSum}, nil
+		result, err := SyncProblems(context.Background(), SyncOptions{Overwrite: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"two-sum"}, result.Updated)
+		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, twoSum.Title, readTitle(twoSumFile))
+	})
+}
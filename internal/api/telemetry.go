@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Attempt is an anonymous record of one session shared by opted-in users
+type Attempt struct {
+	ProblemID       string `json:"problem_id"`
+	Solved          bool   `json:"solved"`
+	DurationSeconds int    `json:"duration_seconds"`
+	FailingTests    []int  `json:"failing_tests,omitempty"` // 1-based test case numbers
+	Language        string `json:"language,omitempty"`
+}
+
+// FailingTest counts how often a test case failed across all attempts
+type FailingTest struct {
+	Test     int `json:"test"`
+	Failures int `json:"failures"`
+}
+
+// ProblemStats holds server-side aggregate statistics for a problem
+type ProblemStats struct {
+	ProblemID          string        `json:"problem_id"`
+	Attempts           int           `json:"attempts"`
+	SolveRate          float64       `json:"solve_rate"`
+	MedianSolveSeconds int           `json:"median_solve_seconds"`
+	CommonFailingTests []FailingTest `json:"common_failing_tests"`
+}
+
+// SubmitAttempt sends an attempt to the telemetry endpoint
+// Exported as variable for testing
+var SubmitAttempt = func(ctx context.Context, attempt Attempt) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+
+	req, err := newAuthorizedRequest(ctx, http.MethodPost, "/telemetry/attempts", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("server rejected attempt (%d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetProblemStats fetches aggregate statistics for a problem
+func GetProblemStats(ctx context.Context, problemID string) (*ProblemStats, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodGet, "/problems/"+url.PathEscape(problemID)+"/stats", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	var stats ProblemStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("invalid server response: %v", err)
+	}
+	return &stats, nil
+}
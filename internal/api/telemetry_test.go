@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemStatsAndAttempts(t *testing.T) {
+	var received Attempt
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/telemetry/attempts":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusAccepted)
+		case "/problems/two-sum/stats":
+			w.Write([]byte(`{"problem_id":"two-sum","attempts":4,"solve_rate":0.5,"median_solve_seconds":600,"common_failing_tests":[{"test":2,"failures":2}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+	license.LoadLicense = func() (license.License, error) {
+		return license.License{LicenseKey: "LICENSE-test"}, nil
+	}
+
+	err := SubmitAttempt(context.Background(), Attempt{ProblemID: "two-sum", Solved: false, FailingTests: []int{2}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, received.FailingTests)
+
+	stats, err := GetProblemStats(context.Background(), "two-sum")
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Attempts)
+	assert.Equal(t, 600, stats.MedianSolveSeconds)
+	assert.Equal(t, []FailingTest{{Test: 2, Failures: 2}}, stats.CommonFailingTests)
+}
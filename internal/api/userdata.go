@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// baseURL is the server address used for requests
+// Exported as variable for testing
+var baseURL = func() string {
+	if url := os.Getenv(APIURLEnv); url != "" {
+		return url
+	}
+	return BaseURL
+}()
+
+// httpClient is shared by all API requests
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// newAuthorizedRequest builds a request authenticated with the stored login
+// or license, see bearerToken
+func newAuthorizedRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// DeleteRemoteData deletes all data synced to the server for the current
+// license. It returns the number of records the server removed.
+func DeleteRemoteData(ctx context.Context) (int, error) {
+	req, err := newAuthorizedRequest(ctx, http.MethodDelete, "/user-data", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Deleted int    `json:"deleted"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid server response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("server rejected delete (%d): %s", resp.StatusCode, body.Error)
+	}
+	return body.Deleted, nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRemoteData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/user-data", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer LICENSE-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid license"}`))
+			return
+		}
+		w.Write([]byte(`{"deleted":3}`))
+	}))
+	defer server.Close()
+
+	origBaseURL := baseURL
+	defer func() { baseURL = origBaseURL }()
+	baseURL = server.URL
+
+	origLoadLicense := license.LoadLicense
+	defer func() { license.LoadLicense = origLoadLicense }()
+
+	t.Run("Authorized", func(t *testing.T) {
+		license.LoadLicense = func() (license.License, error) {
+			return license.License{LicenseKey: "LICENSE-test"}, nil
+		}
+		deleted, err := DeleteRemoteData(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		license.LoadLicense = func() (license.License, error) {
+			return license.License{LicenseKey: "LICENSE-wrong"}, nil
+		}
+		_, err := DeleteRemoteData(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid license")
+	})
+}
@@ -0,0 +1,195 @@
+// Package archive re-verifies the accepted solutions kept in the progress
+// database against the current problem test sets
+package archive
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// DefaultTimeout bounds a single solution's test run
+const DefaultTimeout = 30 * time.Second
+
+// Status is the outcome of re-verifying one solution
+type Status string
+
+const (
+	StatusPass    Status = "pass"    // Still passes every test
+	StatusFail    Status = "fail"    // Fails tests it used to pass
+	StatusMissing Status = "missing" // The problem is no longer available
+	StatusError   Status = "error"   // The tests could not be run
+)
+
+// Result is the outcome for the latest accepted solution to a problem in
+// one language
+type Result struct {
+	ProblemID   string
+	Language    string
+	SolvedAt    time.Time
+	Status      Status
+	FailedTests []int // 1-based numbers of failing tests
+	TotalTests  int
+	Changed     bool   // The test set differs from the one the solution passed
+	Err         string // Why the tests could not be run
+}
+
+// Options selects which solutions to verify
+type Options struct {
+	ProblemID string        // Only this problem; empty for all
+	Language  string        // Only this language; empty for all
+	Timeout   time.Duration // Per solution; zero uses DefaultTimeout
+}
+
+// getProblem loads the current definition of a problem
+// Exported as variable for testing
+var getProblem = problem.GetByID
+
+// runTests runs code against a problem's tests
+// Exported as variable for testing
+var runTests = execution.ExecuteTests
+
+// Solutions returns the latest accepted solution to each problem in each
+// language, ordered by problem then language
+func Solutions(attempts []storage.Attempt, opts Options) []storage.Attempt {
+	type key struct{ problemID, language string }
+	latest := make(map[key]storage.Attempt)
+	for _, a := range attempts {
+		if !a.Passed || a.Code == "" {
+			continue
+		}
+		if opts.ProblemID != "" && a.ProblemID != opts.ProblemID {
+			continue
+		}
+		if opts.Language != "" && a.Language != opts.Language {
+			continue
+		}
+		k := key{a.ProblemID, a.Language}
+		if prev, ok := latest[k]; !ok || !a.Time.Before(prev.Time) {
+			latest[k] = a
+		}
+	}
+
+	solutions := make([]storage.Attempt, 0, len(latest))
+	for _, a := range latest {
+		solutions = append(solutions, a)
+	}
+	sort.Slice(solutions, func(i, j int) bool {
+		if solutions[i].ProblemID != solutions[j].ProblemID {
+			return solutions[i].ProblemID < solutions[j].ProblemID
+		}
+		return solutions[i].Language < solutions[j].Language
+	})
+	return solutions
+}
+
+// Verify replays the accepted solutions selected by opts against the
+// current test sets. report, if not nil, is called as each result is ready.
+func Verify(ctx context.Context, repo storage.Repository, opts Options, report func(Result)) ([]Result, error) {
+	attempts, err := repo.LoadAttempts(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := repo.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var results []Result
+	for _, solution := range Solutions(attempts, opts) {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		result := verifyOne(ctx, solution, sessions, timeout)
+		if report != nil {
+			report(result)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// verifyOne re-runs a single solution
+func verifyOne(ctx context.Context, solution storage.Attempt, sessions []interfaces.SessionStats, timeout time.Duration) Result {
+	result := Result{ProblemID: solution.ProblemID, Language: solution.Language, SolvedAt: solution.Time}
+
+	p, err := getProblem(solution.ProblemID)
+	if err != nil {
+		result.Status = StatusMissing
+		result.Err = err.Error()
+		return result
+	}
+	result.TotalTests = len(p.TestCases)
+	result.Changed = testSetChanged(*p, solution, sessions)
+
+	prob := toInterfaceProblem(*p)
+	testResults, allPassed, err := runTests(ctx, &prob, solution.Code, solution.Language, timeout)
+	if err != nil {
+		result.Status = StatusError
+		result.Err = err.Error()
+		return result
+	}
+
+	result.Status = StatusPass
+	if !allPassed {
+		result.Status = StatusFail
+		for i, r := range testResults {
+			if !r.Passed {
+				result.FailedTests = append(result.FailedTests, i+1)
+			}
+		}
+	}
+	return result
+}
+
+// testSetChanged reports whether the problem's tests differ from those of
+// the session the solution was accepted in. Sessions recorded before
+// environment snapshots existed cannot tell, so they count as unchanged.
+func testSetChanged(p problem.Problem, solution storage.Attempt, sessions []interfaces.SessionStats) bool {
+	var recorded string
+	for _, s := range sessions {
+		if s.ProblemID != solution.ProblemID || s.Environment == nil || s.Environment.TestSetHash == "" {
+			continue
+		}
+		if s.StartTime.After(solution.Time) {
+			break
+		}
+		recorded = s.Environment.TestSetHash
+	}
+	return recorded != "" && recorded != p.TestSetHash()
+}
+
+// toInterfaceProblem converts a problem for the test runners
+func toInterfaceProblem(p problem.Problem) interfaces.Problem {
+	testCases := make([]interfaces.TestCase, len(p.TestCases))
+	for i, tc := range p.TestCases {
+		testCases[i] = interfaces.TestCase{Input: tc.Input, Expected: tc.Expected, Tier: tc.Tier}
+	}
+
+	var pattern string
+	if len(p.Patterns) > 0 {
+		pattern = p.Patterns[0]
+	}
+
+	return interfaces.Problem{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Signature:   p.Signature,
+		Pattern:     pattern,
+		Difficulty:  p.Difficulty,
+		Companies:   p.Companies,
+		Tags:        p.Patterns,
+		TestCases:   testCases,
+		StarterCode: p.StarterCode,
+	}
+}
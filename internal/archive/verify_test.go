@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolutions(t *testing.T) {
+	day := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	attempts := []storage.Attempt{
+		{ProblemID: "two_sum", Language: "go", Time: day, Passed: true, Code: "old"},
+		{ProblemID: "two_sum", Language: "go", Time: day.Add(time.Hour), Passed: true, Code: "new"},
+		{ProblemID: "two_sum", Language: "go", Time: day.Add(2 * time.Hour), Code: "broken"},
+		{ProblemID: "two_sum", Language: "python", Time: day, Passed: true, Code: "py"},
+		{ProblemID: "3sum", Language: "go", Time: day, Passed: true},
+	}
+
+	solutions := Solutions(attempts, Options{})
+	require.Len(t, solutions, 2)
+	assert.Equal(t, "new", solutions[0].Code, "latest accepted solution wins")
+	assert.Equal(t, "python", solutions[1].Language)
+
+	assert.Len(t, Solutions(attempts, Options{Language: "python"}), 1)
+	assert.Empty(t, Solutions(attempts, Options{ProblemID: "3sum"}), "attempts without code cannot be replayed")
+}
+
+func TestVerify(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	defer store.Close()
+	ctx := context.Background()
+
+	solvedAt := time.Now().Add(-time.Hour)
+	cases := []problem.TestCase{{Input: "1", Expected: "1"}, {Input: "2", Expected: "4"}}
+	for _, a := range []storage.Attempt{
+		{ProblemID: "square", Language: "go", Time: solvedAt, Passed: true, Code: "square"},
+		{ProblemID: "identity", Language: "go", Time: solvedAt, Passed: true, Code: "identity"},
+		{ProblemID: "removed", Language: "go", Time: solvedAt, Passed: true, Code: "gone"},
+	} {
+		require.NoError(t, store.SaveAttempt(ctx, a))
+	}
+	// The identity solution was accepted against a single test
+	require.NoError(t, store.SaveSession(ctx, interfaces.SessionStats{
+		ProblemID:   "identity",
+		StartTime:   solvedAt.Add(-time.Minute),
+		Environment: &interfaces.EnvironmentSnapshot{TestSetHash: problem.HashTestCases(cases[:1])},
+	}))
+
+	originalGet, originalRun := getProblem, runTests
+	defer func() { getProblem, runTests = originalGet, originalRun }()
+	getProblem = func(id string) (*problem.Problem, error) {
+		if id == "removed" {
+			return nil, errors.New("problem not found: removed")
+		}
+		return &problem.Problem{ID: id, TestCases: cases}, nil
+	}
+	runTests = func(ctx context.Context, p *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		// The identity solution only gets the first test right
+		results := []interfaces.TestResult{{Passed: true}, {Passed: code == "square"}}
+		return results, code == "square", nil
+	}
+
+	var reported []string
+	results, err := Verify(ctx, store, Options{}, func(r Result) { reported = append(reported, r.ProblemID) })
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"identity", "removed", "square"}, reported)
+
+	assert.Equal(t, StatusFail, results[0].Status)
+	assert.Equal(t, []int{2}, results[0].FailedTests)
+	assert.Equal(t, 2, results[0].TotalTests)
+	assert.True(t, results[0].Changed)
+
+	assert.Equal(t, StatusMissing, results[1].Status)
+
+	assert.Equal(t, StatusPass, results[2].Status)
+	assert.False(t, results[2].Changed, "no recorded test set to compare with")
+}
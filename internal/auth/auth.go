@@ -0,0 +1,162 @@
+// Package auth keeps the tokens `algo-scales login` obtains from the API
+// server. Credentials are stored in the OS keychain when there is one, and
+// otherwise in a file only the user can read.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotLoggedIn is returned when there are no credentials for a server
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// Credentials are the tokens issued for one server
+type Credentials struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"` // When the access token expires
+}
+
+// Expiring reports whether the access token expires within margin of now
+func (c Credentials) Expiring(now time.Time, margin time.Duration) bool {
+	return c.AccessToken == "" || !now.Add(margin).Before(c.ExpiresAt)
+}
+
+// Load returns the credentials stored for a server
+// Exported as variable for testing
+var Load = func(server string) (Credentials, error) {
+	secret, err := keychain().Get(server)
+	if errors.Is(err, ErrNotLoggedIn) || errors.Is(err, errNoKeychain) {
+		secret, err = fileGet(server)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+		return Credentials{}, fmt.Errorf("stored credentials are damaged, log in again: %v", err)
+	}
+	return creds, nil
+}
+
+// Save stores the credentials for a server, returning where they were kept
+// Exported as variable for testing
+var Save = func(server string, creds Credentials) (string, error) {
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return "", err
+	}
+
+	kc := keychain()
+	if err := kc.Set(server, string(data)); err == nil {
+		// A copy left in the file from before would be found if the
+		// keychain later became unavailable
+		fileDelete(server)
+		return kc.Name(), nil
+	}
+	if err := fileSet(server, string(data)); err != nil {
+		return "", err
+	}
+	return credentialsFile(), nil
+}
+
+// Delete removes the credentials stored for a server, wherever they are
+// Exported as variable for testing
+var Delete = func(server string) error {
+	err := keychain().Delete(server)
+	if errors.Is(err, ErrNotLoggedIn) || errors.Is(err, errNoKeychain) {
+		err = nil
+	}
+	if fileErr := fileDelete(server); err == nil {
+		err = fileErr
+	}
+	return err
+}
+
+// The file fallback holds the credentials of each server in one JSON object
+
+// credentialsFile is where credentials are kept without a keychain
+func credentialsFile() string {
+	return filepath.Join(getConfigDir(), "credentials.json")
+}
+
+// readCredentialsFile returns the secrets in the credentials file, keyed by
+// server
+func readCredentialsFile() (map[string]string, error) {
+	secrets := make(map[string]string)
+	data, err := os.ReadFile(credentialsFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", credentialsFile(), err)
+	}
+	return secrets, nil
+}
+
+// writeCredentialsFile replaces the credentials file, readable only by the
+// user, and removes it once it is empty
+func writeCredentialsFile(secrets map[string]string) error {
+	if len(secrets) == 0 {
+		if err := os.Remove(credentialsFile()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(credentialsFile(), data, 0600)
+}
+
+func fileGet(server string) (string, error) {
+	secrets, err := readCredentialsFile()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[server]
+	if !ok {
+		return "", ErrNotLoggedIn
+	}
+	return secret, nil
+}
+
+func fileSet(server, secret string) error {
+	secrets, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+	secrets[server] = secret
+	return writeCredentialsFile(secrets)
+}
+
+func fileDelete(server string) error {
+	secrets, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[server]; !ok {
+		return nil
+	}
+	delete(secrets, server)
+	return writeCredentialsFile(secrets)
+}
+
+// Helper functions - exported as variables for testing
+var getConfigDir = func() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".algo-scales")
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryKeychain is a keychain kept in a map
+type memoryKeychain map[string]string
+
+func (memoryKeychain) Name() string { return "the test keychain" }
+
+func (k memoryKeychain) Get(account string) (string, error) {
+	secret, ok := k[account]
+	if !ok {
+		return "", ErrNotLoggedIn
+	}
+	return secret, nil
+}
+
+func (k memoryKeychain) Set(account, secret string) error {
+	k[account] = secret
+	return nil
+}
+
+func (k memoryKeychain) Delete(account string) error {
+	if _, ok := k[account]; !ok {
+		return ErrNotLoggedIn
+	}
+	delete(k, account)
+	return nil
+}
+
+// useKeychain stores credentials in kc and a temporary config directory
+func useKeychain(t *testing.T, kc keychainBackend) string {
+	t.Helper()
+	dir := t.TempDir()
+	origKeychain, origConfigDir := keychain, getConfigDir
+	keychain = func() keychainBackend { return kc }
+	getConfigDir = func() string { return dir }
+	t.Cleanup(func() { keychain, getConfigDir = origKeychain, origConfigDir })
+	return dir
+}
+
+func TestCredentials_Keychain(t *testing.T) {
+	kc := memoryKeychain{}
+	dir := useKeychain(t, kc)
+
+	_, err := Load("https://a.example.com/v1")
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+
+	creds := Credentials{AccessToken: "AT1.a", RefreshToken: "RT1.a", ExpiresAt: time.Now().Add(time.Hour).Round(0)}
+	where, err := Save("https://a.example.com/v1", creds)
+	require.NoError(t, err)
+	assert.Equal(t, "the test keychain", where)
+	assert.Contains(t, kc["https://a.example.com/v1"], "RT1.a")
+	assert.NoFileExists(t, filepath.Join(dir, "credentials.json"))
+
+	loaded, err := Load("https://a.example.com/v1")
+	require.NoError(t, err)
+	assert.True(t, loaded.ExpiresAt.Equal(creds.ExpiresAt))
+	assert.Equal(t, creds.RefreshToken, loaded.RefreshToken)
+
+	// Each server has its own credentials
+	_, err = Load("https://b.example.com/v1")
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+
+	require.NoError(t, Delete("https://a.example.com/v1"))
+	assert.Empty(t, kc)
+	require.NoError(t, Delete("https://a.example.com/v1"), "deleting twice is fine")
+}
+
+func TestCredentials_File(t *testing.T) {
+	dir := useKeychain(t, noKeychain{})
+
+	where, err := Save("https://a.example.com/v1", Credentials{RefreshToken: "RT1.a"})
+	require.NoError(t, err)
+	path := filepath.Join(dir, "credentials.json")
+	assert.Equal(t, path, where)
+	_, err = Save("https://b.example.com/v1", Credentials{RefreshToken: "RT1.b"})
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := Load("https://b.example.com/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "RT1.b", loaded.RefreshToken)
+
+	require.NoError(t, Delete("https://a.example.com/v1"))
+	_, err = Load("https://a.example.com/v1")
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+	require.NoError(t, Delete("https://b.example.com/v1"))
+	assert.NoFileExists(t, path, "the file is removed once empty")
+}
+
+func TestCredentials_MovedToKeychain(t *testing.T) {
+	kc := memoryKeychain{}
+	dir := useKeychain(t, noKeychain{})
+	_, err := Save("https://a.example.com/v1", Credentials{RefreshToken: "RT1.old"})
+	require.NoError(t, err)
+
+	// Once a keychain is available, saving moves the credentials into it
+	keychain = func() keychainBackend { return kc }
+	loaded, err := Load("https://a.example.com/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "RT1.old", loaded.RefreshToken)
+	_, err = Save("https://a.example.com/v1", Credentials{RefreshToken: "RT1.new"})
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "credentials.json"))
+}
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	creds := Credentials{AccessToken: "AT1.a", ExpiresAt: now.Add(time.Minute)}
+	assert.False(t, creds.Expiring(now, 30*time.Second))
+	assert.True(t, creds.Expiring(now, time.Minute))
+	assert.True(t, Credentials{}.Expiring(now, 0), "no access token")
+}
+
+func TestSystemKeychains(t *testing.T) {
+	var calls []string
+	var stdins []string
+	origRunTool := runTool
+	t.Cleanup(func() { runTool = origRunTool })
+	runTool = func(stdin string, name string, args ...string) (string, int, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		stdins = append(stdins, stdin)
+		switch args[0] {
+		case "find-generic-password":
+			return "", macItemNotFound, assert.AnError
+		case "lookup":
+			return "{}", 0, nil
+		}
+		return "", 0, nil
+	}
+
+	_, err := macKeychain{}.Get("https://a.example.com/v1")
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+	require.NoError(t, macKeychain{}.Set("https://a.example.com/v1", "secret"))
+	assert.Equal(t, "security add-generic-password -U -s algo-scales -a https://a.example.com/v1 -l AlgoScales -w secret", calls[1])
+
+	secret, err := secretService{}.Get("https://a.example.com/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", secret)
+	require.NoError(t, secretService{}.Set("https://a.example.com/v1", "secret"))
+	assert.Equal(t, "secret-tool store --label=AlgoScales service algo-scales account https://a.example.com/v1", calls[3])
+	assert.Equal(t, "secret", stdins[3], "the secret is passed on stdin")
+}
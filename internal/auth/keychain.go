@@ -0,0 +1,125 @@
+// OS keychains, reached through the command-line tools that ship with them
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// service is the name credentials are stored under in the keychain
+const service = "algo-scales"
+
+// errNoKeychain is returned when no keychain can be reached
+var errNoKeychain = errors.New("no keychain available")
+
+// keychainBackend stores secrets by account; each server is an account
+type keychainBackend interface {
+	// Name describes the keychain for messages
+	Name() string
+	// Get returns a secret, or ErrNotLoggedIn when there is none
+	Get(account string) (string, error)
+	// Set stores a secret, replacing the previous one
+	Set(account, secret string) error
+	// Delete removes a secret, or returns ErrNotLoggedIn when there is none
+	Delete(account string) error
+}
+
+// keychain returns the keychain of this OS: the login keychain on macOS
+// and the Secret Service (GNOME Keyring, KWallet) on Linux. Elsewhere, and
+// when the tools are missing, there is none and the file is used.
+// Exported as variable for testing
+var keychain = func() keychainBackend {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return macKeychain{}
+		}
+	case "linux", "freebsd", "openbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return secretService{}
+		}
+	}
+	return noKeychain{}
+}
+
+// runTool runs a keychain tool, returning its output and exit code
+// Exported as variable for testing
+var runTool = func(stdin string, name string, args ...string) (string, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.String(), exitErr.ExitCode(), fmt.Errorf("%s: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), 0, err
+}
+
+// macKeychain uses the security tool
+type macKeychain struct{}
+
+// macItemNotFound is the exit code security uses for a missing item
+const macItemNotFound = 44
+
+func (macKeychain) Name() string { return "the macOS keychain" }
+
+func (macKeychain) Get(account string) (string, error) {
+	out, code, err := runTool("", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	if code == macItemNotFound {
+		return "", ErrNotLoggedIn
+	}
+	return strings.TrimSuffix(out, "\n"), err
+}
+
+func (macKeychain) Set(account, secret string) error {
+	_, _, err := runTool("", "security", "add-generic-password", "-U", "-s", service, "-a", account, "-l", "AlgoScales", "-w", secret)
+	return err
+}
+
+func (macKeychain) Delete(account string) error {
+	_, code, err := runTool("", "security", "delete-generic-password", "-s", service, "-a", account)
+	if code == macItemNotFound {
+		return ErrNotLoggedIn
+	}
+	return err
+}
+
+// secretService uses secret-tool from libsecret
+type secretService struct{}
+
+func (secretService) Name() string { return "the Secret Service keyring" }
+
+func (secretService) Get(account string) (string, error) {
+	out, code, err := runTool("", "secret-tool", "lookup", "service", service, "account", account)
+	// secret-tool exits with 1 and prints nothing for a missing secret
+	if code == 1 && out == "" {
+		return "", ErrNotLoggedIn
+	}
+	return out, err
+}
+
+func (secretService) Set(account, secret string) error {
+	// The secret is read from stdin so it never shows in the process list
+	_, _, err := runTool(secret, "secret-tool", "store", "--label=AlgoScales", "service", service, "account", account)
+	return err
+}
+
+func (secretService) Delete(account string) error {
+	_, _, err := runTool("", "secret-tool", "clear", "service", service, "account", account)
+	return err
+}
+
+// noKeychain is used when there is no keychain to reach
+type noKeychain struct{}
+
+func (noKeychain) Name() string                       { return "no keychain" }
+func (noKeychain) Get(account string) (string, error) { return "", errNoKeychain }
+func (noKeychain) Set(account, secret string) error   { return errNoKeychain }
+func (noKeychain) Delete(account string) error        { return errNoKeychain }
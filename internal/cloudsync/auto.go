@@ -0,0 +1,105 @@
+// Automatic syncing after commands, for users who opted in
+
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+const (
+	// autoSyncInterval is how often progress is synced when nothing changed
+	// locally, to pick up progress from other machines
+	autoSyncInterval = time.Hour
+
+	// autoSyncTimeout bounds how long a command may wait on the server
+	autoSyncTimeout = 5 * time.Second
+)
+
+// loadConfig reads the user config
+// Exported as variable for testing
+var loadConfig = config.LoadConfig
+
+// autoSyncState records the last automatic sync attempt
+type autoSyncState struct {
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// MaybeSync syncs progress when the user opted in and either the local
+// progress changed or autoSyncInterval passed since the last attempt.
+// Failures are ignored: syncing must never get in the way of practice.
+func MaybeSync() {
+	cfg, err := loadConfig()
+	if err != nil || !cfg.CloudSync {
+		return
+	}
+
+	now := time.Now()
+	if !due(readState().LastAttempt, now) {
+		return
+	}
+	// Record the attempt first, so a server that is down is not retried on
+	// every command
+	writeState(autoSyncState{LastAttempt: now})
+
+	ctx, cancel := context.WithTimeout(context.Background(), autoSyncTimeout)
+	defer cancel()
+	if _, err := Sync(ctx); err == nil {
+		// Pulled progress changes the local files; that is not a change to
+		// push on the next command
+		writeState(autoSyncState{LastAttempt: time.Now()})
+	}
+}
+
+// due reports whether an automatic sync should run
+func due(lastAttempt, now time.Time) bool {
+	if now.Sub(lastAttempt) >= autoSyncInterval {
+		return true
+	}
+	for _, path := range progressFiles() {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(lastAttempt) {
+			return true
+		}
+	}
+	return false
+}
+
+// progressFiles returns the files local progress is kept in
+func progressFiles() []string {
+	db := storage.DBPath()
+	return []string{db, db + "-wal", daily.GetSessionDBPath()}
+}
+
+// statePath returns the location of the automatic sync state
+func statePath() string {
+	return filepath.Join(utils.GetConfigDir(), "progress-sync.json")
+}
+
+// readState reads the automatic sync state, empty when there is none
+func readState() autoSyncState {
+	var state autoSyncState
+	if data, err := os.ReadFile(statePath()); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// writeState saves the automatic sync state
+func writeState(state autoSyncState) {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath()), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(statePath(), data, 0644)
+}
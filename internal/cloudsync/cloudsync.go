@@ -0,0 +1,202 @@
+// Package cloudsync keeps practice progress in sync between machines through
+// the API server. Sessions, streaks and the daily scale session are pushed
+// as records; of two versions of a record, the one updated last wins.
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Record kinds
+const (
+	KindSession = "session" // A practice session, solved or not
+	KindStreak  = "streak"  // A practice streak, keyed by name
+	KindDaily   = "daily"   // The daily scale session, keyed by date
+)
+
+// Result counts the records a sync exchanged
+type Result struct {
+	Pushed int // Local records sent to the server
+	Pulled int // Records from other machines saved locally
+}
+
+// openRepo opens the local progress database
+// Exported as variable for testing
+var openRepo = storage.Default
+
+// loadDailySession loads the local daily session
+// Exported as variable for testing
+var loadDailySession = daily.LoadSession
+
+// restoreDailySession replaces the local daily session
+// Exported as variable for testing
+var restoreDailySession = daily.RestoreSession
+
+// Sync pushes all local progress to the server and saves the progress
+// other machines pushed that is newer than the local copy
+func Sync(ctx context.Context) (Result, error) {
+	repo := openRepo()
+	defer repo.Close()
+
+	local, err := collect(ctx, repo)
+	if err != nil {
+		return Result{}, err
+	}
+
+	snapshot, err := api.PushProgress(ctx, local)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to sync progress: %v", err)
+	}
+
+	pulled, err := apply(ctx, repo, local, snapshot.Records)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Pushed: len(local), Pulled: pulled}, nil
+}
+
+// collect turns the local progress into records
+func collect(ctx context.Context, repo storage.Repository) ([]api.ProgressRecord, error) {
+	var records []api.ProgressRecord
+
+	sessions, err := repo.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		updated := s.EndTime
+		if updated.IsZero() {
+			updated = s.StartTime
+		}
+		record, err := newRecord(KindSession, sessionKey(s), updated, s)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	streak, err := repo.LoadStreak(ctx, storage.DailyStreak)
+	if err != nil {
+		return nil, err
+	}
+	if !streak.LastPracticed.IsZero() {
+		record, err := newRecord(KindStreak, streak.Name, streak.LastPracticed, streak)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if session, err := loadDailySession(); err == nil {
+		record, err := newRecord(KindDaily, session.Date, dailyUpdatedAt(session), session)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// apply saves the remote records that are newer than their local versions,
+// returning how many were saved
+func apply(ctx context.Context, repo storage.Repository, local, remote []api.ProgressRecord) (int, error) {
+	type recordID struct{ kind, key string }
+	localUpdated := make(map[recordID]time.Time, len(local))
+	for _, r := range local {
+		localUpdated[recordID{r.Kind, r.Key}] = r.UpdatedAt
+	}
+	newer := func(r api.ProgressRecord) bool {
+		updated, ok := localUpdated[recordID{r.Kind, r.Key}]
+		return !ok || r.UpdatedAt.After(updated)
+	}
+
+	pulled := 0
+	var latestDaily *daily.DailySession
+	var latestDailyAt time.Time
+	for _, r := range remote {
+		if !newer(r) {
+			continue
+		}
+		switch r.Kind {
+		case KindSession:
+			var session interfaces.SessionStats
+			if err := json.Unmarshal(r.Data, &session); err != nil {
+				return pulled, fmt.Errorf("invalid synced session %s: %v", r.Key, err)
+			}
+			if err := repo.SaveSession(ctx, session); err != nil {
+				return pulled, err
+			}
+			pulled++
+		case KindStreak:
+			var streak storage.Streak
+			if err := json.Unmarshal(r.Data, &streak); err != nil {
+				return pulled, fmt.Errorf("invalid synced streak %s: %v", r.Key, err)
+			}
+			if err := repo.SaveStreak(ctx, streak); err != nil {
+				return pulled, err
+			}
+			pulled++
+		case KindDaily:
+			// Only one daily session is active, so only the newest counts
+			var session daily.DailySession
+			if err := json.Unmarshal(r.Data, &session); err != nil {
+				return pulled, fmt.Errorf("invalid synced daily session %s: %v", r.Key, err)
+			}
+			if latestDaily == nil || r.UpdatedAt.After(latestDailyAt) {
+				latestDaily, latestDailyAt = &session, r.UpdatedAt
+			}
+		}
+	}
+
+	if latestDaily != nil && latestDailyAt.After(localDailyUpdatedAt(local)) {
+		if err := restoreDailySession(latestDaily); err != nil {
+			return pulled, err
+		}
+		pulled++
+	}
+	return pulled, nil
+}
+
+// localDailyUpdatedAt returns when the local daily session was last saved,
+// or the zero time without one
+func localDailyUpdatedAt(local []api.ProgressRecord) time.Time {
+	for _, r := range local {
+		if r.Kind == KindDaily {
+			return r.UpdatedAt
+		}
+	}
+	return time.Time{}
+}
+
+// newRecord encodes a piece of progress as a record
+func newRecord(kind, key string, updated time.Time, value interface{}) (api.ProgressRecord, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return api.ProgressRecord{}, err
+	}
+	return api.ProgressRecord{Kind: kind, Key: key, UpdatedAt: updated.UTC(), Data: data}, nil
+}
+
+// sessionKey identifies a session the way the progress database does, by
+// problem and start time
+func sessionKey(s interfaces.SessionStats) string {
+	return s.ProblemID + "@" + s.StartTime.UTC().Format(time.RFC3339Nano)
+}
+
+// dailyUpdatedAt returns when a daily session was last saved. Sessions saved
+// before this was recorded fall back to their start time.
+func dailyUpdatedAt(session *daily.DailySession) time.Time {
+	if !session.UpdatedAt.IsZero() {
+		return session.UpdatedAt
+	}
+	return session.StartTime
+}
@@ -0,0 +1,199 @@
+package cloudsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer stands in for the progress endpoint, keeping the newest
+// version of each record like the server does
+type fakeServer struct {
+	records map[string]api.ProgressRecord
+	pushes  int
+}
+
+func (f *fakeServer) push(ctx context.Context, records []api.ProgressRecord) (api.ProgressSnapshot, error) {
+	f.pushes++
+	for _, r := range records {
+		id := r.Kind + "/" + r.Key
+		if existing, ok := f.records[id]; !ok || r.UpdatedAt.After(existing.UpdatedAt) {
+			f.records[id] = r
+		}
+	}
+	var snapshot api.ProgressSnapshot
+	for _, r := range f.records {
+		snapshot.Records = append(snapshot.Records, r)
+	}
+	return snapshot, nil
+}
+
+// machine is one machine's local progress
+type machine struct {
+	path  string
+	daily *daily.DailySession
+}
+
+// repo opens the machine's progress database
+func (m *machine) repo() storage.Repository {
+	return storage.NewSQLiteStore(m.path)
+}
+
+// use points the package at the machine's progress
+func (m *machine) use(t *testing.T) {
+	openRepo = m.repo
+	loadDailySession = func() (*daily.DailySession, error) {
+		if m.daily == nil {
+			return nil, errors.New("no active session found")
+		}
+		return m.daily, nil
+	}
+	restoreDailySession = func(session *daily.DailySession) error {
+		m.daily = session
+		return nil
+	}
+}
+
+func setupSync(t *testing.T) (*fakeServer, *machine, *machine) {
+	origOpenRepo, origLoad, origRestore, origPush := openRepo, loadDailySession, restoreDailySession, api.PushProgress
+	t.Cleanup(func() {
+		openRepo, loadDailySession, restoreDailySession, api.PushProgress = origOpenRepo, origLoad, origRestore, origPush
+	})
+
+	server := &fakeServer{records: make(map[string]api.ProgressRecord)}
+	api.PushProgress = server.push
+
+	dir := t.TempDir()
+	laptop := &machine{path: filepath.Join(dir, "laptop.db")}
+	desktop := &machine{path: filepath.Join(dir, "desktop.db")}
+	return server, laptop, desktop
+}
+
+func TestSync(t *testing.T) {
+	server, laptop, desktop := setupSync(t)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	laptopRepo, desktopRepo := laptop.repo(), desktop.repo()
+	defer laptopRepo.Close()
+	defer desktopRepo.Close()
+
+	// The laptop solves a problem and starts a streak
+	solved := interfaces.SessionStats{ProblemID: "two-sum", StartTime: start, EndTime: start.Add(20 * time.Minute), Solved: true, Patterns: []string{"hash-map"}}
+	require.NoError(t, laptopRepo.SaveSession(ctx, solved))
+	require.NoError(t, laptopRepo.SaveStreak(ctx, storage.Streak{Name: storage.DailyStreak, Current: 1, Longest: 1, LastPracticed: start}))
+	laptop.daily = &daily.DailySession{Date: "2026-10-01", StartTime: start, UpdatedAt: start.Add(time.Hour)}
+
+	laptop.use(t)
+	result, err := Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Pushed: 3, Pulled: 0}, result)
+
+	// The desktop gets everything
+	desktop.use(t)
+	result, err = Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Pushed: 0, Pulled: 3}, result)
+
+	sessions, err := desktopRepo.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "two-sum", sessions[0].ProblemID)
+	assert.True(t, sessions[0].Solved)
+	streak, err := desktopRepo.LoadStreak(ctx, storage.DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, 1, streak.Current)
+	require.NotNil(t, desktop.daily)
+	assert.Equal(t, "2026-10-01", desktop.daily.Date)
+
+	// Both continue the streak; the desktop practiced last, so it wins
+	require.NoError(t, laptopRepo.SaveStreak(ctx, storage.Streak{Name: storage.DailyStreak, Current: 2, Longest: 2, LastPracticed: start.Add(24 * time.Hour)}))
+	require.NoError(t, desktopRepo.SaveStreak(ctx, storage.Streak{Name: storage.DailyStreak, Current: 5, Longest: 5, LastPracticed: start.Add(25 * time.Hour)}))
+
+	_, err = Sync(ctx)
+	require.NoError(t, err)
+	laptop.use(t)
+	result, err = Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Pulled)
+
+	streak, err = laptopRepo.LoadStreak(ctx, storage.DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, 5, streak.Current)
+
+	// An older daily session from elsewhere does not replace a newer one
+	server.records[KindDaily+"/2026-09-30"] = mustRecord(t, KindDaily, "2026-09-30", start.Add(-time.Hour), daily.DailySession{Date: "2026-09-30"})
+	_, err = Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", laptop.daily.Date)
+}
+
+func mustRecord(t *testing.T, kind, key string, updated time.Time, value interface{}) api.ProgressRecord {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return api.ProgressRecord{Kind: kind, Key: key, UpdatedAt: updated, Data: data}
+}
+
+func TestMaybeSync(t *testing.T) {
+	server, laptop, _ := setupSync(t)
+	laptop.use(t)
+
+	dir := t.TempDir()
+	origGetConfigDir, origDBPath, origLoadConfig := utils.GetConfigDir, storage.DBPath, loadConfig
+	defer func() {
+		utils.GetConfigDir, storage.DBPath, loadConfig = origGetConfigDir, origDBPath, origLoadConfig
+	}()
+	utils.GetConfigDir = func() string { return dir }
+	dbPath := filepath.Join(dir, storage.DBFileName)
+	storage.DBPath = func() string { return dbPath }
+	t.Setenv("HOME", dir)
+
+	cloudSync := false
+	loadConfig = func() (config.UserConfig, error) {
+		return config.UserConfig{CloudSync: cloudSync}, nil
+	}
+
+	// Nothing is sent without opting in
+	MaybeSync()
+	assert.Equal(t, 0, server.pushes)
+
+	cloudSync = true
+	MaybeSync()
+	assert.Equal(t, 1, server.pushes)
+
+	// Without local changes the next sync waits for the interval
+	MaybeSync()
+	assert.Equal(t, 1, server.pushes)
+
+	// A change to the progress database syncs again
+	require.NoError(t, os.WriteFile(dbPath, []byte{}, 0644))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(dbPath, future, future))
+	MaybeSync()
+	assert.Equal(t, 2, server.pushes)
+}
+
+func TestDue(t *testing.T) {
+	origDBPath := storage.DBPath
+	defer func() { storage.DBPath = origDBPath }()
+	storage.DBPath = func() string { return filepath.Join(t.TempDir(), storage.DBFileName) }
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now()
+	assert.True(t, due(time.Time{}, now))
+	assert.True(t, due(now.Add(-2*time.Hour), now))
+	assert.False(t, due(now.Add(-time.Minute), now))
+}
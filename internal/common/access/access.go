@@ -0,0 +1,89 @@
+// Package access provides accessible mode, in which the TUI is drawn as
+// linear, label-prefixed text that screen readers and basic terminals can
+// follow: no color, box drawing, spinners or animation.
+package access
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// enabled is whether accessible mode is on
+var enabled atomic.Bool
+
+// SetEnabled turns accessible mode on or off
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled reports whether accessible mode is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Field renders a value behind its label, as "Label: value". Values that
+// span lines start on the line after the label.
+func Field(label, value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "\n") {
+		return label + ":\n" + value
+	}
+	return label + ": " + value
+}
+
+// Plain reduces a rendered screen to plain text: colors and other escape
+// codes, box drawing, block and spinner characters, and pictographs are
+// removed with the space after them, trailing spaces are trimmed, and runs
+// of blank lines are collapsed to one.
+func Plain(s string) string {
+	var text strings.Builder
+	afterDecoration := false
+	for _, r := range ansi.Strip(s) {
+		switch {
+		case decorative(r):
+			afterDecoration = true
+			continue
+		case r == ' ' && afterDecoration:
+			// The space that set the decoration apart goes with it
+		default:
+			text.WriteRune(r)
+		}
+		afterDecoration = false
+	}
+
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(text.String(), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if strings.TrimSpace(line) == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// decorative reports whether a character only decorates the screen
+func decorative(r rune) bool {
+	switch {
+	case r >= 0x2500 && r <= 0x259F: // Box drawing and block elements
+		return true
+	case r >= 0x2800 && r <= 0x28FF: // Braille patterns, used by spinners
+		return true
+	case r >= 0x1F300 && r <= 0x1FAFF: // Emoji pictographs
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r == 0xFE0F || r == 0x200D: // Emoji presentation and joiners
+		return true
+	}
+	return false
+}
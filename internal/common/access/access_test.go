@@ -0,0 +1,34 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestField(t *testing.T) {
+	assert.Equal(t, "Problem: Two Sum", Field("Problem", " Two Sum\n"))
+	assert.Equal(t, "Test results:\npassed\nfailed", Field("Test results", "passed\nfailed"))
+}
+
+func TestPlain(t *testing.T) {
+	renderer := lipgloss.NewRenderer(nil)
+	renderer.SetColorProfile(termenv.TrueColor)
+	box := renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Foreground(lipgloss.Color("#ff0000")).
+		Render("Solved: 3")
+
+	screen := "📊 Statistics\n\n\n" + box + "\n⠋ Loading   \n\n"
+	assert.Equal(t, "Statistics\n\nSolved: 3\n\nLoading", Plain(screen))
+	assert.Equal(t, "  indented code", Plain("  indented code"), "indentation is kept")
+}
+
+func TestEnabled(t *testing.T) {
+	t.Cleanup(func() { SetEnabled(false) })
+	assert.False(t, Enabled())
+	SetEnabled(true)
+	assert.True(t, Enabled())
+}
@@ -2,11 +2,14 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
 )
 
 // UserConfig represents the user's configuration
@@ -15,13 +18,103 @@ type UserConfig struct {
 	Language      string `json:"language"`      // Preferred programming language
 	TimerDuration int    `json:"timerDuration"` // Timer duration in minutes
 	Mode          string `json:"mode"`          // Default mode: "learn", "practice", "cram"
+	AutoSubmit    bool   `json:"autoSubmit"`    // Finish the session as soon as a test run passes every test
 	
 	// UI preferences
-	Theme         string `json:"theme"`         // UI theme
+	Theme         string `json:"theme"`         // Unused; the TUI theme is kept in the theme file
+	CodeTheme     string `json:"codeTheme"`     // Syntax highlighting theme, any chroma style or "ansi"; empty for monokai
 	EditorCommand string `json:"editorCommand"` // External editor command
+	ASCIIOnly     bool   `json:"asciiOnly"`     // Use ASCII instead of emoji and box symbols
+	Accessible    bool   `json:"accessible"`    // Draw the TUI as plain, label-prefixed text for screen readers
+	SlowTestMs    int    `json:"slowTestMs"`    // Highlight tests slower than this; 0 uses the default
+	BlankAfterMin int    `json:"blankAfterMin"` // Hide the session screen after this many idle minutes; 0 for never
 	
 	// Focus settings
-	FocusPatterns []string `json:"focusPatterns"` // Patterns to focus on
+	FocusPatterns []string      `json:"focusPatterns"` // Patterns to focus on
+	DailyRotation DailyRotation `json:"dailyRotation"` // Weekly rotation of the patterns daily practice covers
+	StreakFreezes int           `json:"streakFreezes"` // Most streak freezes held at once; 0 uses the default, -1 turns them off
+	DayBoundary   DayBoundary   `json:"dayBoundary"`   // When a new day of daily practice starts
+
+	// Daily practice routines
+	Routines      map[string]DailyRoutine `json:"routines,omitempty"`      // Named daily practice routines
+	ActiveRoutine string                  `json:"activeRoutine,omitempty"` // Routine daily practice follows; empty for one problem of each scale
+
+	// Code formatting before display and archival
+	Formatters map[string]string `json:"formatters,omitempty"` // Formatter commands by language, overriding the defaults; "off" disables
+
+	// Execution limits
+	Sandbox       string            `json:"sandbox"`                 // How solutions run: "process" (default), "rlimit" or "docker"
+	TestTimeMs    int               `json:"testTimeMs"`              // Wall-clock limit per test; 0 for none
+	TestCPUMs     int               `json:"testCpuMs"`               // CPU time limit per test in the rlimit and docker sandboxes; 0 for none
+	MemoryLimitMB int               `json:"memoryLimitMb"`           // Memory limit in the rlimit and docker sandboxes; 0 for none
+	SandboxImages map[string]string `json:"sandboxImages,omitempty"` // Docker images by language, overriding the defaults
+
+	// Privacy settings
+	ShareTelemetry bool `json:"shareTelemetry"` // Send anonymous attempt results to help calibrate problems
+	PeerReview     bool `json:"peerReview"`     // Share solved solutions for anonymous peer review
+	HallOfFame     bool `json:"hallOfFame"`     // Publish solutions solved without help to the problem's hall of fame
+	CloudSync      bool `json:"cloudSync"`      // Sync progress between machines through the server
+
+	// Limits on hints and solutions while solving
+	HintPolicy HintPolicy `json:"hintPolicy"`
+
+	// Where progress and other user data is kept
+	Storage StorageConfig `json:"storage"`
+}
+
+// HintPolicy limits the help available on a problem until it is solved.
+// Zero values mean no limit.
+type HintPolicy struct {
+	MaxHints              int `json:"maxHints,omitempty"`              // Hints per problem, AI hints included
+	MinIntervalSeconds    int `json:"minIntervalSeconds,omitempty"`    // Minimum time between hints
+	SolutionAfterFailures int `json:"solutionAfterFailures,omitempty"` // Failed test runs before the solution can be shown
+}
+
+// DailyRotation has daily practice focus on a few patterns each week
+// instead of every pattern every day. Zero PatternsPerWeek turns it off.
+type DailyRotation struct {
+	PatternsPerWeek int      `json:"patternsPerWeek,omitempty"` // Patterns practiced each week, such as 2 or 3
+	Patterns        []string `json:"patterns,omitempty"`        // Patterns to rotate through, in order; empty for every scale
+}
+
+// DailyRoutine sets out what a day of daily practice covers. Zero values
+// keep the default of one problem of each of today's scales at any
+// difficulty.
+type DailyRoutine struct {
+	Patterns           []string       `json:"patterns,omitempty"`           // Patterns to practice, in order; empty for today's scales
+	ProblemsPerPattern int            `json:"problemsPerPattern,omitempty"` // Problems of each pattern per day
+	DifficultyWeights  map[string]int `json:"difficultyWeights,omitempty"`  // Relative chance of each difficulty, such as {"medium": 3, "hard": 1}
+}
+
+// DayBoundary sets when one day of daily practice ends and the next begins.
+// Zero values start days at local midnight.
+type DayBoundary struct {
+	ResetHour int    `json:"resetHour,omitempty"` // Hour, 0 to 23, a new day starts, such as 4 for night owls
+	Timezone  string `json:"timezone,omitempty"`  // IANA time zone days are counted in, such as "Europe/Berlin"; empty for local time
+}
+
+// StorageConfig selects where user data is kept
+type StorageConfig struct {
+	Backend string        `json:"backend,omitempty"` // "local" (default), "s3" or "webdav"
+	S3      *S3Config     `json:"s3,omitempty"`
+	WebDAV  *WebDAVConfig `json:"webdav,omitempty"`
+}
+
+// S3Config locates user data in an S3-compatible bucket
+type S3Config struct {
+	Endpoint        string `json:"endpoint"`                  // e.g. https://s3.us-east-1.amazonaws.com or a MinIO URL
+	Region          string `json:"region"`                    // Signing region, e.g. us-east-1
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix,omitempty"`          // Key prefix for all user data
+	AccessKeyID     string `json:"accessKeyId,omitempty"`     // Falls back to AWS_ACCESS_KEY_ID
+	SecretAccessKey string `json:"secretAccessKey,omitempty"` // Falls back to AWS_SECRET_ACCESS_KEY
+}
+
+// WebDAVConfig locates user data in a WebDAV collection
+type WebDAVConfig struct {
+	URL      string `json:"url"` // Collection URL, e.g. https://cloud.example.com/remote.php/dav/files/me/algo-scales
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"` // Falls back to ALGO_SCALES_WEBDAV_PASSWORD
 }
 
 // DefaultConfig returns the default configuration
@@ -38,6 +131,8 @@ func DefaultConfig() UserConfig {
 
 // LoadConfig loads the user's configuration from file
 func LoadConfig() (UserConfig, error) {
+	defer profiling.Track("config load")()
+
 	configDir := getConfigDir()
 	configFile := filepath.Join(configDir, "config.json")
 	
@@ -90,6 +185,47 @@ func SaveConfig(config UserConfig) error {
 	return nil
 }
 
+// Path returns the configuration file's path
+func Path() string {
+	return filepath.Join(getConfigDir(), "config.json")
+}
+
+// CheckFile reports whether a configuration file can be loaded, and if not
+// where it is damaged
+func CheckFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var config UserConfig
+	err = json.Unmarshal(data, &config)
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *json.SyntaxError:
+		// The offset is just past the offending character
+		line, col := position(data, e.Offset-1)
+		return fmt.Errorf("line %d, column %d: %v", line, col, e)
+	case *json.UnmarshalTypeError:
+		line, col := position(data, e.Offset)
+		return fmt.Errorf("line %d, column %d: %s should be %s, not %s", line, col, e.Field, e.Type, e.Value)
+	default:
+		return err
+	}
+}
+
+// position returns the 1-based line and column of a byte offset
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := data[:offset]
+	return bytes.Count(before, []byte("\n")) + 1, int(offset) - bytes.LastIndexByte(before, '\n')
+}
+
 // getConfigDir returns the configuration directory path
 func getConfigDir() string {
 	homeDir, _ := os.UserHomeDir()
@@ -134,8 +270,9 @@ func ListLanguages() []string {
 		"java",
 		"javascript",
 		"typescript",
-		"c++",
+		"cpp",
 		"c#",
+		"rust",
 	}
 }
 
@@ -0,0 +1,51 @@
+// Package features reports which optional subsystems this binary was built
+// with. Building with the lite tag leaves out the AI providers and the
+// terminal UI, for low-power devices such as a Raspberry Pi.
+package features
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Feature is an optional subsystem
+type Feature string
+
+const (
+	// AI is the AI assistant and its providers
+	AI Feature = "ai"
+	// TUI is the full-screen terminal UI
+	TUI Feature = "tui"
+)
+
+// All lists every optional feature
+var All = []Feature{AI, TUI}
+
+// ErrUnavailable is returned when a feature was left out of the build
+var ErrUnavailable = errors.New("not included in this build")
+
+// Available reports whether a feature was compiled in
+func Available(f Feature) bool {
+	return !excluded[f]
+}
+
+// Require returns an error wrapping ErrUnavailable when a feature was left
+// out of the build
+func Require(f Feature) error {
+	if Available(f) {
+		return nil
+	}
+	return fmt.Errorf("%s is %w; install the full algo-scales build to use it", f.describe(), ErrUnavailable)
+}
+
+// describe names a feature for messages
+func (f Feature) describe() string {
+	switch f {
+	case AI:
+		return "the AI assistant"
+	case TUI:
+		return "the terminal UI"
+	default:
+		return string(f)
+	}
+}
@@ -0,0 +1,29 @@
+package features
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	for _, f := range All {
+		err := Require(f)
+		if Lite {
+			assert.True(t, errors.Is(err, ErrUnavailable), "%s should be unavailable", f)
+			assert.False(t, Available(f))
+		} else {
+			assert.NoError(t, err)
+			assert.True(t, Available(f))
+		}
+	}
+}
+
+func TestRequire_Message(t *testing.T) {
+	original := excluded[AI]
+	defer func() { excluded[AI] = original }()
+	excluded[AI] = true
+
+	assert.EqualError(t, Require(AI), "the AI assistant is not included in this build; install the full algo-scales build to use it")
+}
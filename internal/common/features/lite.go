@@ -0,0 +1,9 @@
+//go:build lite
+
+package features
+
+// Lite reports whether this is a lite build
+const Lite = true
+
+// excluded lists the features left out of this build
+var excluded = map[Feature]bool{AI: true, TUI: true}
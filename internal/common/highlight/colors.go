@@ -0,0 +1,118 @@
+// Themes and terminal colors for highlighted code
+
+package highlight
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/muesli/termenv"
+)
+
+// DefaultTheme is the chroma style used when no theme is configured
+const DefaultTheme = "monokai"
+
+// Colors is how many colors highlighted code may use
+type Colors int
+
+const (
+	// NoColor leaves code plain, for pipes, dumb terminals and NO_COLOR
+	NoColor Colors = iota
+	// ANSI uses the 8 basic ANSI colors, which every color terminal has
+	ANSI
+	// ANSI256 uses the xterm 256-color palette
+	ANSI256
+	// TrueColor uses 24-bit colors
+	TrueColor
+)
+
+// The configured theme and colors; until colors are set, the terminal
+// decides
+var (
+	mu        sync.RWMutex
+	theme     = DefaultTheme
+	colors    Colors
+	colorsSet bool
+)
+
+// SetTheme sets the chroma style code is highlighted with. An empty name
+// restores the default; an unknown one is an error.
+func SetTheme(name string) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+	if _, ok := styles.Registry[name]; !ok {
+		return fmt.Errorf("unknown code theme %q; choose from: %s", name, strings.Join(Themes(), ", "))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	theme = name
+	return nil
+}
+
+// Theme returns the chroma style code is highlighted with
+func Theme() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return theme
+}
+
+// Themes returns the names of the available themes, sorted
+func Themes() []string {
+	return styles.Names()
+}
+
+// SetColors limits highlighting to a number of colors, overriding detection
+func SetColors(c Colors) {
+	mu.Lock()
+	defer mu.Unlock()
+	colors = c
+	colorsSet = true
+}
+
+// TerminalColors returns how many colors highlighted code may use: the
+// number set with SetColors, or else what the terminal on stdout supports.
+// NO_COLOR turns colors off, and CLICOLOR_FORCE turns them on for pipes.
+func TerminalColors() Colors {
+	mu.RLock()
+	c, set := colors, colorsSet
+	mu.RUnlock()
+	if set {
+		return c
+	}
+
+	switch termenv.NewOutput(os.Stdout).EnvColorProfile() {
+	case termenv.TrueColor:
+		return TrueColor
+	case termenv.ANSI256:
+		return ANSI256
+	case termenv.ANSI:
+		return ANSI
+	default:
+		return NoColor
+	}
+}
+
+// ansiStyle highlights with the basic ANSI colors only, leaving plain text
+// in the terminal's own foreground color. Its colors are the ones chroma's
+// 8- and 16-color formatters map exactly to escape codes 31-37. It is also
+// available as the "ansi" theme.
+var ansiStyle = styles.Register(chroma.MustNewStyle("ansi", chroma.StyleEntries{
+	chroma.Keyword:             "#7f007f",
+	chroma.KeywordType:         "#00007f",
+	chroma.KeywordConstant:     "#00007f",
+	chroma.NameBuiltin:         "#00007f",
+	chroma.NameFunction:        "#7f7fe0",
+	chroma.NameClass:           "#7f7fe0",
+	chroma.LiteralString:       "#007f00",
+	chroma.LiteralStringEscape: "#7f007f",
+	chroma.LiteralNumber:       "#7f0000",
+	chroma.Comment:             "#007f7f",
+	chroma.CommentPreproc:      "#7f007f",
+	chroma.GenericDeleted:      "#7f0000",
+	chroma.GenericInserted:     "#007f00",
+}))
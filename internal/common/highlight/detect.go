@@ -0,0 +1,70 @@
+// Lexer selection and language detection
+
+package highlight
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers"
+)
+
+// lexerNames maps language names that chroma does not know, or knows as
+// something else, to its lexers
+var lexerNames = map[string]string{
+	"golang": "go",
+	"pseudo": "plaintext", // Pseudo-code has no grammar to highlight
+	"c#":     "csharp",
+}
+
+// Lexer returns the lexer for a language, given by name, alias or file
+// name. When the language is empty or unknown, it is detected from the code,
+// and code that cannot be recognized is highlighted as plain text.
+func Lexer(language, code string) chroma.Lexer {
+	name := strings.ToLower(strings.TrimSpace(language))
+	if mapped, ok := lexerNames[name]; ok {
+		name = mapped
+	}
+	if name != "" {
+		if l := lexers.Get(name); l != nil {
+			return l
+		}
+		if l := lexers.Match(name); l != nil {
+			return l
+		}
+	}
+	if detected := DetectLanguage(code); detected != "" {
+		return lexers.Get(detected)
+	}
+	return lexers.Fallback
+}
+
+// languageSignatures recognize the languages solutions can be written in,
+// most distinctive first
+var languageSignatures = []struct {
+	language string
+	pattern  *regexp.Regexp
+}{
+	{"go", regexp.MustCompile(`(?m)^package \w+|^func [\w(]|:= `)},
+	{"rust", regexp.MustCompile(`(?m)\bfn \w+\s*[<(]|\blet mut\b|\bimpl\b|\bVec<|&str\b`)},
+	{"cpp", regexp.MustCompile(`(?m)^#include\b|\bstd::|\busing namespace\b`)},
+	{"java", regexp.MustCompile(`(?m)\b(public|private|protected) (static )?(class|[\w<>\[\]]+ \w+\()|\bSystem\.out\.`)},
+	{"typescript", regexp.MustCompile(`(?m)\b(function|const|let) \w+\s*(\([^)]*:\s*\w|:\s*(number|string|boolean)\b)|\binterface \w+ \{|\): (number|string|boolean|void)\b`)},
+	{"python", regexp.MustCompile(`(?m)^\s*def \w+\(.*\):|^\s*(from \w+ )?import \w+$|\bself\.|\belif\b`)},
+	{"javascript", regexp.MustCompile(`(?m)\bfunction\b|\b(const|let|var) \w+ =|=> |console\.log|module\.exports`)},
+}
+
+// DetectLanguage guesses which of the solution languages code is written
+// in, returning "" when it cannot tell
+func DetectLanguage(code string) string {
+	for _, sig := range languageSignatures {
+		if sig.pattern.MatchString(code) {
+			return sig.language
+		}
+	}
+	if l := lexers.Analyse(code); l != nil {
+		return strings.ToLower(l.Config().Name)
+	}
+	return ""
+}
@@ -8,68 +8,106 @@ import (
 
 	"github.com/alecthomas/chroma"
 	"github.com/alecthomas/chroma/formatters"
-	"github.com/alecthomas/chroma/lexers"
 	"github.com/alecthomas/chroma/styles"
 )
 
 // SyntaxHighlighter provides code syntax highlighting functionality
 type SyntaxHighlighter struct {
-	// Default style to use
+	// Theme to use; empty follows the configured theme
 	defaultStyle string
 }
 
-// NewSyntaxHighlighter creates a new syntax highlighter
+// NewSyntaxHighlighter creates a new syntax highlighter. An empty style
+// follows the theme set with SetTheme.
 func NewSyntaxHighlighter(style string) *SyntaxHighlighter {
-	if style == "" {
-		style = "monokai"
-	}
-	
 	return &SyntaxHighlighter{
 		defaultStyle: style,
 	}
 }
 
-// Highlight returns syntax highlighted code for the terminal
+// Highlight returns syntax highlighted code for the terminal. The language
+// is detected from the code when it is empty or unknown, and the colors are
+// limited to what the terminal can show.
 func (h *SyntaxHighlighter) Highlight(code, language string) (string, error) {
-	// Get the lexer for the language
-	l := lexers.Get(language)
-	if l == nil {
-		// Try to match by extension
-		l = lexers.Match(language)
-		if l == nil {
-			// Fallback to plain text
-			l = lexers.Get("text")
-		}
+	colors := TerminalColors()
+	if colors == NoColor {
+		return code, nil
 	}
-	l = chroma.Coalesce(l)
 
-	// Get the style
-	s := styles.Get(h.defaultStyle)
-	if s == nil {
-		s = styles.Get("monokai")
+	it, err := chroma.Coalesce(Lexer(language, code)).Tokenise(nil, code)
+	if err != nil {
+		return "", err
 	}
 
-	// Get the formatter
-	f := formatters.Get("terminal")
-	if f == nil {
-		f = formatters.Get("terminal16m")
+	var buf bytes.Buffer
+	if err := formatter(colors).Format(&buf, h.style(colors), it); err != nil {
+		return "", err
 	}
+	return buf.String(), nil
+}
 
-	// Create an output buffer
-	var buf bytes.Buffer
+// HighlightLines highlights code and splits it into lines, each of which
+// can be displayed on its own. Code is tokenised as a whole, so comments
+// and strings spanning lines stay highlighted.
+func (h *SyntaxHighlighter) HighlightLines(code, language string) []string {
+	colors := TerminalColors()
+	plain := strings.Split(code, "\n")
+	if colors == NoColor {
+		return plain
+	}
 
-	// Tokenize and format the code
-	it, err := l.Tokenise(nil, code)
+	it, err := chroma.Coalesce(Lexer(language, code)).Tokenise(nil, code)
 	if err != nil {
-		return "", err
+		return plain
 	}
 
-	err = f.Format(&buf, s, it)
-	if err != nil {
-		return "", err
+	f, style := formatter(colors), h.style(colors)
+	lines := make([]string, 0, len(plain))
+	for _, tokens := range chroma.SplitTokensIntoLines(it.Tokens()) {
+		// Each line's tokens end with its newline, which is not displayed
+		if n := len(tokens); n > 0 {
+			tokens[n-1].Value = strings.TrimSuffix(tokens[n-1].Value, "\n")
+		}
+		var buf bytes.Buffer
+		if err := f.Format(&buf, style, chroma.Literator(tokens...)); err != nil {
+			return plain
+		}
+		lines = append(lines, buf.String())
+	}
+	// A trailing newline starts an empty last line with no tokens
+	for len(lines) < len(plain) {
+		lines = append(lines, "")
 	}
+	return lines
+}
 
-	return buf.String(), nil
+// style returns the chroma style to highlight with. Themes are made for
+// 256 colors or more; with fewer, the nearest matches of a theme's colors
+// are often unreadable, so a palette of the basic ANSI colors is used.
+func (h *SyntaxHighlighter) style(colors Colors) *chroma.Style {
+	if colors == ANSI {
+		return ansiStyle
+	}
+	name := h.defaultStyle
+	if name == "" {
+		name = Theme()
+	}
+	if s, ok := styles.Registry[name]; ok {
+		return s
+	}
+	return styles.Get(DefaultTheme)
+}
+
+// formatter returns the terminal formatter for a number of colors
+func formatter(colors Colors) chroma.Formatter {
+	switch colors {
+	case TrueColor:
+		return formatters.TTY16m
+	case ANSI256:
+		return formatters.TTY256
+	default:
+		return formatters.TTY16
+	}
 }
 
 // RenderCodeBlock creates a markdown code block with syntax highlighting
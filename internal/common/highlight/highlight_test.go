@@ -0,0 +1,111 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withColors highlights with a number of colors for the rest of a test
+func withColors(t *testing.T, c Colors) {
+	t.Helper()
+	mu.Lock()
+	saved, savedSet := colors, colorsSet
+	mu.Unlock()
+	SetColors(c)
+	t.Cleanup(func() {
+		mu.Lock()
+		colors, colorsSet = saved, savedSet
+		mu.Unlock()
+	})
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"go":         "package main\n\nfunc twoSum(nums []int, target int) []int {\n\tseen := map[int]int{}\n\treturn nil\n}\n",
+		"python":     "def two_sum(nums, target):\n    seen = {}\n    return []\n",
+		"javascript": "function twoSum(nums, target) {\n  const seen = new Map();\n  return [];\n}\n",
+		"typescript": "function twoSum(nums: number[], target: number): number[] {\n  return [];\n}\n",
+		"java":       "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        return new int[0];\n    }\n}\n",
+		"cpp":        "#include <vector>\n\nstd::vector<int> twoSum(std::vector<int>& nums, int target) {\n    return {};\n}\n",
+		"rust":       "fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    let mut seen = HashMap::new();\n    vec![]\n}\n",
+	}
+	for language, code := range tests {
+		assert.Equal(t, language, DetectLanguage(code), language)
+	}
+	assert.Equal(t, "", DetectLanguage("walk the array once, remembering what we saw"))
+}
+
+func TestLexer(t *testing.T) {
+	for language, name := range map[string]string{
+		"go":          "Go",
+		"golang":      "Go",
+		"python":      "Python",
+		"javascript":  "JavaScript",
+		"typescript":  "TypeScript",
+		"java":        "Java",
+		"cpp":         "C++",
+		"c#":          "C#",
+		"rust":        "Rust",
+		"solution.py": "Python",
+		"pseudo":      "plaintext",
+	} {
+		assert.Equal(t, name, Lexer(language, "").Config().Name, language)
+	}
+	assert.Equal(t, "Python", Lexer("", "def f(x):\n    return x\n").Config().Name, "detected from the code")
+	assert.Equal(t, "fallback", Lexer("", "just some words").Config().Name)
+}
+
+func TestHighlightNoColor(t *testing.T) {
+	withColors(t, NoColor)
+	code := "func main() {}\n"
+	highlighted, err := NewSyntaxHighlighter("").Highlight(code, "go")
+	require.NoError(t, err)
+	assert.Equal(t, code, highlighted)
+}
+
+func TestHighlightANSI(t *testing.T) {
+	withColors(t, ANSI)
+	highlighted, err := NewSyntaxHighlighter("monokai").Highlight("// sum\nfunc sum(a int) int { return a + 1 }\n", "go")
+	require.NoError(t, err)
+	assert.Contains(t, highlighted, "\x1b[")
+
+	// Only the basic foreground colors and resets, whatever the theme
+	for _, code := range regexp.MustCompile(`\x1b\[([0-9;]*)m`).FindAllStringSubmatch(highlighted, -1) {
+		assert.Regexp(t, `^(0|3[0-7])?$`, code[1])
+	}
+}
+
+func TestHighlightThemes(t *testing.T) {
+	withColors(t, TrueColor)
+	code := "def f(x):\n    return x\n"
+
+	require.NoError(t, SetTheme("github"))
+	defer SetTheme("")
+	github, err := NewSyntaxHighlighter("").Highlight(code, "python")
+	require.NoError(t, err)
+	monokai, err := NewSyntaxHighlighter("monokai").Highlight(code, "python")
+	require.NoError(t, err)
+	assert.NotEqual(t, github, monokai, "an explicit style overrides the theme")
+	assert.Contains(t, github, "\x1b[38;2;")
+
+	assert.Error(t, SetTheme("no-such-theme"))
+	assert.Equal(t, "github", Theme(), "unknown themes are not applied")
+	assert.Contains(t, Themes(), "ansi")
+}
+
+func TestHighlightLines(t *testing.T) {
+	withColors(t, ANSI256)
+	code := "/* a comment\n   over two lines */\nint x = 1;\n"
+	lines := NewSyntaxHighlighter("").HighlightLines(code, "java")
+	require.Len(t, lines, 4)
+	for i, line := range lines {
+		assert.NotContains(t, line, "\n")
+		plain := regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(line, "")
+		assert.Equal(t, strings.Split(code, "\n")[i], plain)
+	}
+	assert.Contains(t, lines[1], "\x1b[", "the comment stays highlighted on its second line")
+}
@@ -15,12 +15,75 @@ type Problem struct {
 	TestCases   []TestCase
 	Languages   []string
 	StarterCode map[string]string
+	Signature   *FunctionSignature // How tests call the solution; nil when unknown
+}
+
+// FunctionSignature describes the function a problem's solution implements
+// in language-neutral types, so test harnesses can parse each test input into
+// arguments and call the solution. Supported types are int, float, bool,
+// string and char, arrays of them such as int[] and int[][], TreeNode (built
+// from level-order values with nulls) and ListNode (built from its values).
+// A cycle parameter is the index the preceding ListNode's tail links back to,
+// -1 for none; it shapes the list and is not passed to the solution.
+type FunctionSignature struct {
+	Name      string  `json:"name"` // camelCase; Python solutions use snake_case
+	Params    []Param `json:"params"`
+	Returns   string  `json:"returns"`             // Scalar or array type
+	Unordered bool    `json:"unordered,omitempty"` // Array results may be in any order
+}
+
+// Param is a named, typed parameter of a FunctionSignature
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // TestCase represents a problem test case
 type TestCase struct {
 	Input    string
 	Expected string
+	Tier     TestTier // TierExample when empty
+}
+
+// TestTier groups a problem's test cases the way interview platforms do:
+// running tests only runs the examples, while a submission runs every tier
+type TestTier string
+
+const (
+	// TierExample tests are shown with the problem and run on every test run
+	TierExample TestTier = "example"
+	// TierHidden tests are only run when a solution is submitted
+	TierHidden TestTier = "hidden"
+	// TierEdge tests cover boundary inputs and are only run on submission
+	TierEdge TestTier = "edge"
+	// TierStress tests use large inputs and are only run on submission
+	TierStress TestTier = "stress"
+	// TierUser tests are written by the user rather than the problem, and
+	// run with the examples on every test run
+	TierUser TestTier = "user"
+)
+
+// Tiers lists the test tiers a problem can give its tests, in the order
+// they are reported. User tests are reported after them.
+var Tiers = []TestTier{TierExample, TierHidden, TierEdge, TierStress}
+
+// OrDefault returns the tier, treating an unset one as TierExample so
+// problems written before tiers existed run all their tests on every run
+func (t TestTier) OrDefault() TestTier {
+	if t == "" {
+		return TierExample
+	}
+	return t
+}
+
+// Valid reports whether the tier is unset or one of Tiers
+func (t TestTier) Valid() bool {
+	for _, tier := range Tiers {
+		if t.OrDefault() == tier {
+			return true
+		}
+	}
+	return false
 }
 
 // ProblemRepository defines the interface for accessing algorithm problems
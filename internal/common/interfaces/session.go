@@ -4,6 +4,8 @@ package interfaces
 import (
 	"context"
 	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 )
 
 // SessionMode represents a practice session mode
@@ -16,6 +18,22 @@ const (
 	PracticeMode SessionMode = "practice"
 	// CramMode focuses on rapid-fire practice with timers
 	CramMode SessionMode = "cram"
+	// InterviewMode works several problems against one clock without hints
+	InterviewMode SessionMode = "interview"
+)
+
+// Practice contexts name the entry point that recorded a session
+const (
+	// ContextCLI is the interactive command-line session
+	ContextCLI = "cli"
+	// ContextTUI is the full-screen terminal UI
+	ContextTUI = "tui"
+	// ContextVim is the Neovim plugin
+	ContextVim = "vim"
+	// ContextDaily is daily scale practice
+	ContextDaily = "daily"
+	// ContextMCP is an editor or assistant using the MCP server
+	ContextMCP = "mcp"
 )
 
 // SessionOptions represents configuration options for a session
@@ -28,12 +46,54 @@ type SessionOptions struct {
 	ProblemID  string
 }
 
+// FailureKind classifies why a test did not pass
+type FailureKind string
+
+const (
+	// FailureWrongAnswer means the solution returned an unexpected result
+	FailureWrongAnswer FailureKind = "wrong_answer"
+	// FailureRuntime means the solution raised an error or crashed
+	FailureRuntime FailureKind = "runtime_error"
+	// FailureCompile means the solution did not compile
+	FailureCompile FailureKind = "compile_error"
+	// FailureTypeCheck means the type checker rejected the solution
+	FailureTypeCheck FailureKind = "type_error"
+	// FailureTimeLimit means the solution ran past the time or CPU limit
+	FailureTimeLimit FailureKind = "time_limit_exceeded"
+	// FailureMemoryLimit means the solution ran out of memory
+	FailureMemoryLimit FailureKind = "memory_limit_exceeded"
+)
+
+// Label returns a short description of the failure for display
+func (k FailureKind) Label() string {
+	switch k {
+	case FailureWrongAnswer:
+		return "wrong answer"
+	case FailureRuntime:
+		return "runtime error"
+	case FailureCompile:
+		return "compile error"
+	case FailureTypeCheck:
+		return "type error"
+	case FailureTimeLimit:
+		return "time limit exceeded"
+	case FailureMemoryLimit:
+		return "memory limit exceeded"
+	default:
+		return ""
+	}
+}
+
 // TestResult represents the result of a test case
 type TestResult struct {
 	Input    string
 	Expected string
 	Actual   string
 	Passed   bool
+	Failure  FailureKind   // Why the test failed; empty when it passed
+	Duration time.Duration // Time spent in the solution, as measured by the harness
+	Stderr   string        // Output the solution wrote to stderr during the test
+	Tier     TestTier      // Tier of the test case
 }
 
 // Session represents an active problem-solving session
@@ -50,6 +110,9 @@ type Session interface {
 	// GetTimeRemaining returns the remaining session time
 	GetTimeRemaining() time.Duration
 	
+	// GetClock returns the authoritative session clock
+	GetClock() *clock.Clock
+	
 	// GetLanguage returns the programming language
 	GetLanguage() string
 	
@@ -74,9 +137,12 @@ type Session interface {
 	// SetCode updates the solution code
 	SetCode(code string) error
 	
-	// RunTests executes tests on the current solution
+	// RunTests executes the example tests on the current solution
 	RunTests(ctx context.Context) ([]TestResult, bool, error)
 	
+	// SubmitTests executes every tier of tests on the current solution
+	SubmitTests(ctx context.Context) ([]TestResult, bool, error)
+	
 	// Finish completes the session and records stats
 	Finish(ctx context.Context, solved bool) error
 }
@@ -139,4 +205,18 @@ type SessionStats struct {
 	SolutionUsed bool
 	Patterns     []string
 	Difficulty   string
+	Environment  *EnvironmentSnapshot
+	SwappedTo    string // Easier problem switched to when stuck, if any
+	Context      string // Entry point that recorded the session; empty for older sessions
+}
+
+// EnvironmentSnapshot captures what a session ran against so that old
+// results remain interpretable after toolchains or problems change
+type EnvironmentSnapshot struct {
+	Language       string `json:"language"`
+	Toolchain      string `json:"toolchain"`
+	OS             string `json:"os"`
+	Arch           string `json:"arch"`
+	ProblemVersion string `json:"problem_version"`
+	TestSetHash    string `json:"test_set_hash"`
 }
\ No newline at end of file
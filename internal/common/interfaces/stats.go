@@ -9,6 +9,7 @@ type Summary struct {
 	TotalSolved    int     `json:"total_solved"`
 	AvgSolveTime   string  `json:"avg_solve_time"`
 	SuccessRate    float64 `json:"success_rate"`
+	Swaps          int     `json:"swaps"` // Problems swapped for easier ones when stuck
 	FastestSolve   struct {
 		ProblemID string `json:"problem_id"`
 		Time      string `json:"time"`
@@ -28,6 +29,15 @@ type PatternStats struct {
 	AvgTime     string  `json:"avg_time"`
 }
 
+// ContextStats represents statistics for one practice context
+type ContextStats struct {
+	Context     string  `json:"context"`
+	Attempted   int     `json:"attempted"`
+	Solved      int     `json:"solved"`
+	SuccessRate float64 `json:"success_rate"`
+	AvgTime     string  `json:"avg_time"`
+}
+
 // Trends represents trends over time
 type Trends struct {
 	Daily  []DailyTrend  `json:"daily"`
@@ -0,0 +1,193 @@
+// Package profiling records startup timings and pprof profiles for --profile
+package profiling
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+)
+
+// processStart approximates when the process started; package variables are
+// initialized before any init function runs
+var processStart = time.Now()
+
+// Phase is the time spent in one part of startup
+type Phase struct {
+	Name  string        `json:"name"`
+	Calls int           `json:"calls"`
+	Total time.Duration `json:"total"`
+	first time.Duration // offset of the first call, for ordering
+}
+
+// Report summarizes a profiled run
+type Report struct {
+	Command    string        `json:"command"`
+	StartedAt  time.Time     `json:"started_at"`
+	Startup    time.Duration `json:"startup"` // process start until the command ran
+	Total      time.Duration `json:"total"`
+	Phases     []Phase       `json:"phases"`
+	BinarySize int64         `json:"binary_size"`
+	ProfileDir string        `json:"profile_dir"`
+}
+
+// Profiler collects CPU and heap profiles and phase timings for one run
+type Profiler struct {
+	dir     string
+	command string
+	cpuFile *os.File
+	ready   time.Duration
+}
+
+var (
+	mu      sync.Mutex
+	phases  = make(map[string]*Phase)
+	enabled bool
+)
+
+// GetTimingsPath returns where the last run's timings are stored
+// Exported as variable for testing
+var GetTimingsPath = func() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".algo-scales", "timings.json")
+}
+
+// DefaultDir returns the default directory for profiles
+func DefaultDir() string {
+	return filepath.Join(filepath.Dir(GetTimingsPath()), "profile")
+}
+
+// Start begins CPU profiling into dir and enables phase tracking
+func Start(dir, command string) (*Profiler, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %v", err)
+	}
+
+	cpuFile, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %v", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %v", err)
+	}
+
+	p := &Profiler{
+		dir:     dir,
+		command: command,
+		cpuFile: cpuFile,
+		ready:   time.Since(processStart),
+	}
+
+	mu.Lock()
+	enabled = true
+	phases = make(map[string]*Phase)
+	mu.Unlock()
+
+	return p, nil
+}
+
+// Track times a startup phase; call the returned function when it ends.
+// It does nothing unless profiling is active.
+func Track(name string) func() {
+	mu.Lock()
+	on := enabled
+	mu.Unlock()
+	if !on {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+
+		mu.Lock()
+		defer mu.Unlock()
+		ph, ok := phases[name]
+		if !ok {
+			ph = &Phase{Name: name, first: start.Sub(processStart)}
+			phases[name] = ph
+		}
+		ph.Calls++
+		ph.Total += elapsed
+	}
+}
+
+// Stop ends CPU profiling, writes the heap profile and saves the timing report
+func (p *Profiler) Stop() (Report, error) {
+	pprof.StopCPUProfile()
+	p.cpuFile.Close()
+
+	mu.Lock()
+	enabled = false
+	report := Report{
+		Command:    p.command,
+		StartedAt:  processStart,
+		Startup:    p.ready,
+		Total:      time.Since(processStart),
+		ProfileDir: p.dir,
+	}
+	for _, ph := range phases {
+		report.Phases = append(report.Phases, *ph)
+	}
+	mu.Unlock()
+
+	sort.Slice(report.Phases, func(i, j int) bool {
+		return report.Phases[i].first < report.Phases[j].first
+	})
+
+	if exe, err := os.Executable(); err == nil {
+		if info, err := os.Stat(exe); err == nil {
+			report.BinarySize = info.Size()
+		}
+	}
+
+	heapFile, err := os.Create(filepath.Join(p.dir, "heap.pprof"))
+	if err != nil {
+		return report, fmt.Errorf("failed to create heap profile: %v", err)
+	}
+	defer heapFile.Close()
+	runtime.GC() // get up-to-date statistics
+	if err := pprof.WriteHeapProfile(heapFile); err != nil {
+		return report, fmt.Errorf("failed to write heap profile: %v", err)
+	}
+
+	return report, SaveReport(report)
+}
+
+// SaveReport stores a report as the last run's timings
+func SaveReport(report Report) error {
+	path := GetTimingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadReport reads the last run's timings
+func LoadReport() (Report, error) {
+	var report Report
+
+	data, err := os.ReadFile(GetTimingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, fmt.Errorf("no timings recorded yet, run a command with --profile first")
+		}
+		return report, err
+	}
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("failed to parse timings: %v", err)
+	}
+	return report, nil
+}
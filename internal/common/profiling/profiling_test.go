@@ -0,0 +1,65 @@
+package profiling
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilerRecordsPhases(t *testing.T) {
+	tempDir := t.TempDir()
+	origPath := GetTimingsPath
+	defer func() { GetTimingsPath = origPath }()
+	GetTimingsPath = func() string {
+		return filepath.Join(tempDir, "timings.json")
+	}
+
+	// Phases outside a profiled run are ignored
+	Track("config load")()
+
+	p, err := Start(filepath.Join(tempDir, "profile"), "algo-scales list")
+	require.NoError(t, err)
+
+	done := Track("config load")
+	time.Sleep(time.Millisecond)
+	done()
+	Track("problem index")()
+	Track("problem index")()
+
+	report, err := p.Stop()
+	require.NoError(t, err)
+
+	require.Len(t, report.Phases, 2)
+	assert.Equal(t, "config load", report.Phases[0].Name)
+	assert.Equal(t, 1, report.Phases[0].Calls)
+	assert.GreaterOrEqual(t, report.Phases[0].Total, time.Millisecond)
+	assert.Equal(t, "problem index", report.Phases[1].Name)
+	assert.Equal(t, 2, report.Phases[1].Calls)
+
+	for _, name := range []string{"cpu.pprof", "heap.pprof"} {
+		info, err := os.Stat(filepath.Join(tempDir, "profile", name))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), name)
+	}
+
+	loaded, err := LoadReport()
+	require.NoError(t, err)
+	assert.Equal(t, "algo-scales list", loaded.Command)
+	assert.Len(t, loaded.Phases, 2)
+}
+
+func TestLoadReportWithoutRun(t *testing.T) {
+	origPath := GetTimingsPath
+	defer func() { GetTimingsPath = origPath }()
+	GetTimingsPath = func() string {
+		return filepath.Join(t.TempDir(), "timings.json")
+	}
+
+	_, err := LoadReport()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--profile")
+}
@@ -0,0 +1,81 @@
+// Package symbols provides display symbols with an ASCII-only fallback for
+// terminals and locales that cannot render emoji
+package symbols
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// ASCIIEnv forces ASCII-only output when set to a non-empty value other than "0"
+const ASCIIEnv = "ALGO_SCALES_ASCII"
+
+// Symbol is a glyph with a plain ASCII alternative
+type Symbol struct {
+	Unicode string
+	ASCII   string
+}
+
+// Display symbols used across the CLI and TUIs
+var (
+	Pass      = Symbol{Unicode: "✅", ASCII: "[+]"}
+	Fail      = Symbol{Unicode: "❌", ASCII: "[x]"}
+	Error     = Symbol{Unicode: "❌", ASCII: "[!]"}
+	Check     = Symbol{Unicode: "✓", ASCII: "+"}
+	Cross     = Symbol{Unicode: "✗", ASCII: "x"}
+	Celebrate = Symbol{Unicode: "🎉", ASCII: "*"}
+	Warning   = Symbol{Unicode: "⚠", ASCII: "!"}
+	Star      = Symbol{Unicode: "★", ASCII: "*"}
+	StarEmpty = Symbol{Unicode: "☆", ASCII: "-"}
+	BarFull   = Symbol{Unicode: "█", ASCII: "#"}
+	BarEmpty  = Symbol{Unicode: "░", ASCII: "-"}
+)
+
+// asciiOnly is set explicitly by SetASCII; until then the environment decides
+var (
+	asciiOnly  atomic.Bool
+	configured atomic.Bool
+)
+
+// SetASCII turns ASCII-only output on or off, overriding detection
+func SetASCII(on bool) {
+	asciiOnly.Store(on)
+	configured.Store(true)
+}
+
+// ASCII reports whether output should be limited to ASCII
+func ASCII() bool {
+	if configured.Load() {
+		return asciiOnly.Load()
+	}
+	return detectASCII()
+}
+
+// String returns the symbol for the current output mode
+func (s Symbol) String() string {
+	if ASCII() {
+		return s.ASCII
+	}
+	return s.Unicode
+}
+
+// detectASCII falls back to ASCII when asked to, on the Linux console, or
+// when the locale does not use UTF-8
+func detectASCII() bool {
+	if v := os.Getenv(ASCIIEnv); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "linux" {
+		return true
+	}
+
+	// The first non-empty locale variable wins, as in setlocale
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectASCII(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		ascii bool
+	}{
+		{"utf-8 locale", map[string]string{"LANG": "en_US.UTF-8"}, false},
+		{"utf8 spelling", map[string]string{"LC_ALL": "de_DE.utf8"}, false},
+		{"posix locale", map[string]string{"LANG": "C"}, true},
+		{"LC_ALL wins over LANG", map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, true},
+		{"no locale set", map[string]string{}, false},
+		{"linux console", map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"}, true},
+		{"forced by env", map[string]string{ASCIIEnv: "1", "LANG": "en_US.UTF-8"}, true},
+		{"env set to 0", map[string]string{ASCIIEnv: "0", "LANG": "en_US.UTF-8"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{ASCIIEnv, "TERM", "LC_ALL", "LC_CTYPE", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+			assert.Equal(t, tt.ascii, detectASCII())
+		})
+	}
+}
+
+func TestSetASCII(t *testing.T) {
+	defer configured.Store(false)
+
+	SetASCII(true)
+	assert.True(t, ASCII())
+	assert.Equal(t, "[+]", Pass.String())
+	assert.Equal(t, "x", Cross.String())
+
+	SetASCII(false)
+	assert.False(t, ASCII())
+	assert.Equal(t, "✅", Pass.String())
+	assert.Equal(t, "✗", Cross.String())
+}
@@ -0,0 +1,539 @@
+// Static estimation of a solution's time and space complexity
+
+package complexity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Order is a complexity class of the form n^Poly · log^Log n, or
+// exponential. Inputs of every size are called n.
+type Order struct {
+	Poly int  // Power of n
+	Log  int  // Power of log n
+	Exp  bool // Exponential, such as O(2^n) or O(n!)
+}
+
+// Common orders
+var (
+	Constant    = Order{}
+	Logarithmic = Order{Log: 1}
+	Linear      = Order{Poly: 1}
+	Linearithm  = Order{Poly: 1, Log: 1}
+	Quadratic   = Order{Poly: 2}
+	Exponential = Order{Exp: true}
+)
+
+// String formats the order in Big-O notation, e.g. O(n log n)
+func (o Order) String() string {
+	if o.Exp {
+		return "O(2^n)"
+	}
+	var parts []string
+	switch {
+	case o.Poly == 1:
+		parts = append(parts, "n")
+	case o.Poly > 1:
+		parts = append(parts, fmt.Sprintf("n^%d", o.Poly))
+	}
+	switch {
+	case o.Log == 1:
+		parts = append(parts, "log n")
+	case o.Log > 1:
+		parts = append(parts, fmt.Sprintf("log^%d n", o.Log))
+	}
+	if len(parts) == 0 {
+		return "O(1)"
+	}
+	return "O(" + strings.Join(parts, " ") + ")"
+}
+
+// Compare returns -1, 0 or 1 as o grows slower than, as fast as or faster
+// than p
+func (o Order) Compare(p Order) int {
+	switch {
+	case o.Exp != p.Exp:
+		if o.Exp {
+			return 1
+		}
+		return -1
+	case o.Poly != p.Poly:
+		if o.Poly > p.Poly {
+			return 1
+		}
+		return -1
+	case o.Log != p.Log:
+		if o.Log > p.Log {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// times returns the order of doing p for each step of o
+func (o Order) times(p Order) Order {
+	return Order{Poly: o.Poly + p.Poly, Log: o.Log + p.Log, Exp: o.Exp || p.Exp}
+}
+
+// maxOrder returns the faster growing of two orders
+func maxOrder(o, p Order) Order {
+	if o.Compare(p) >= 0 {
+		return o
+	}
+	return p
+}
+
+var (
+	orderLogPattern  = regexp.MustCompile(`(?:log|lg)(?:\^(\d+))?\(?[a-z]?\)?`)
+	orderVarPattern  = regexp.MustCompile(`[a-z](?:\^(\d+))?`)
+	orderExpPattern  = regexp.MustCompile(`!|\d\^[a-z]|[a-z]\^[a-z]`)
+	orderWordPattern = regexp.MustCompile(`[a-z]{3,}`)
+	orderSuperscript = strings.NewReplacer("²", "^2", "³", "^3", "ⁿ", "^n", "·", "", "*", "", "×", "", " ", "")
+)
+
+// ParseOrder reads Big-O notation such as "O(n log n)", "O(n^2)" or
+// "O(V + E)". Every variable counts as n, and a sum is as big as its
+// biggest term.
+func ParseOrder(s string) (Order, bool) {
+	s = orderSuperscript.Replace(strings.ToLower(strings.TrimSpace(s)))
+	if strings.HasPrefix(s, "o(") && strings.HasSuffix(s, ")") {
+		s = s[2 : len(s)-1]
+	}
+	if s == "" {
+		return Order{}, false
+	}
+
+	var order Order
+	for _, term := range strings.Split(s, "+") {
+		var t Order
+		if orderExpPattern.MatchString(term) {
+			t.Exp = true
+		} else {
+			term = orderLogPattern.ReplaceAllStringFunc(term, func(m string) string {
+				t.Log += power(orderLogPattern.FindStringSubmatch(m)[1])
+				return ""
+			})
+			if orderWordPattern.MatchString(term) {
+				return Order{}, false
+			}
+			term = orderVarPattern.ReplaceAllStringFunc(term, func(m string) string {
+				t.Poly += power(orderVarPattern.FindStringSubmatch(m)[1])
+				return ""
+			})
+			if strings.Trim(term, "0123456789()") != "" {
+				return Order{}, false
+			}
+		}
+		order = maxOrder(order, t)
+	}
+	return order, true
+}
+
+// power reads an exponent, which is 1 when absent
+func power(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return 1
+}
+
+// Estimate is a statically estimated complexity with the reasons for it
+type Estimate struct {
+	Time  Order
+	Space Order
+	Notes []string // What the estimate is based on, e.g. "nested loops"
+}
+
+// block is a loop, function or other braced or indented block of code
+type block struct {
+	header   string // The statement that opens the block
+	body     []string
+	children []*block
+	parent   *block
+}
+
+var (
+	loopHeaderPattern   = regexp.MustCompile(`^(?:\w+:\s*)?(?:for|while|do|loop|async\s+for)\b`)
+	callbackPattern     = regexp.MustCompile(`\.(?:forEach|map|filter|reduce|some|every|flatMap|for_each|iter\(\)\.for_each)\s*\(`)
+	constantLoopPattern = regexp.MustCompile(`range\(\s*\d+\s*\)|range\s+\d+\s*$|<=?\s*\d+\s*[;)]|<=?\s*\d+\s*$`)
+	halvingPattern      = regexp.MustCompile(`/=?\s*2\b|>>=?\s*1\b|\*=\s*2\b|<<=?\s*1\b|\bmid\b`)
+	comprehensionFor    = regexp.MustCompile(`\bfor\b`)
+	linearCallPattern   = regexp.MustCompile(`\.(?:indexOf|includes|index)\s*\(`)
+	sortPattern         = regexp.MustCompile(`\bsort(?:ed)?\s*\(|\bsort\.\w+\(|slices\.Sort|\.sort(?:_unstable|_by|_by_key)?\s*\(|Arrays\.sort|Collections\.sort`)
+	heapPattern         = regexp.MustCompile(`heapq\.|heap\.(?:Push|Pop|Init|Fix)|\.(?:offer|poll)\(|\bpush_heap\b|\bpop_heap\b|BinaryHeap`)
+	memoPattern         = regexp.MustCompile(`(?i)memo|cache|\bdp\b`)
+	visitedPattern      = regexp.MustCompile(`(?i)visited|seen`)
+	structuralPattern   = regexp.MustCompile(`\.(?:left|right|next|children|Left|Right|Next|Children)\b|neighbors|graph\[|adj\[`)
+	functionPatterns    = []*regexp.Regexp{
+		regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?(\w+)\s*\(`),
+		regexp.MustCompile(`^(?:async\s+)?def\s+(\w+)\s*\(`),
+		regexp.MustCompile(`^(?:pub\s+)?fn\s+(\w+)`),
+		regexp.MustCompile(`\bfunction\s+(\w+)\s*\(`),
+		regexp.MustCompile(`^(?:const|let|var)\s+(\w+)\s*=\s*function\b`),
+		regexp.MustCompile(`^(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s*)?(?:\([^)]*\)|\w+)\s*=>`),
+		regexp.MustCompile(`^(?:[\w<>\[\],:*&]+\s+)+(\w+)\s*\([^;]*\)\s*(?:const\s*)?(?:throws\s+[\w, ]+)?$`),
+	}
+	space2DPattern = regexp.MustCompile(`make\(\[\]\[\]|\[\[.*\bfor\b|new\s+\w+\s*\[[^\]]+\]\s*\[|vector<\s*vector<|Vec<\s*Vec<|=\s*\[\[[^\]]*\]\s*\*|Array\.from\(.*(?:Array|\[)`)
+	space1DPattern = regexp.MustCompile(`make\(|map\[|\[\]\w+\{|append\(|\b(?:dict|set|list|deque|Counter|defaultdict)\(|=\s*\[\]|=\s*\{\}|=\s*\[[^\]]*\]\s*\*|\.append\(|new\s+(?:Map|Set|Array|ArrayList|HashMap|HashSet|LinkedList|ArrayDeque|PriorityQueue|TreeMap|TreeSet|Stack)\b|new\s+\w+\s*\[|\.push(?:_back)?\(|vector<|unordered_(?:map|set)<|\b(?:std::)?(?:map|set|queue|stack|deque)<|Vec::|vec!|HashMap::|HashSet::|VecDeque::|BTreeMap::|\.collect|sorted\(|\[:\]|\.split\(`)
+	keywords       = map[string]bool{"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true, "else": true}
+)
+
+// Analyze estimates the time and space complexity of code by looking for
+// loops and how they nest, sorting, heap operations, recursion and the
+// collections it allocates. It is a heuristic: it cannot tell a loop over
+// the input from one over a small fixed range unless the bound is a literal.
+func Analyze(language, code string) Estimate {
+	code = stripCode(language, code)
+	var root *block
+	if language == "python" {
+		root = parseIndented(code)
+	} else {
+		root = parseBraced(code)
+	}
+
+	a := &analyzer{code: code, notes: map[string]bool{}}
+	a.walk(root, Constant, nil)
+
+	space := a.space(code)
+	return Estimate{Time: a.time, Space: space, Notes: a.order}
+}
+
+// analyzer accumulates the costs found while walking the blocks
+type analyzer struct {
+	code       string
+	time       Order
+	stackSpace Order // Space used by recursion
+	notes      map[string]bool
+	order      []string // Notes in the order found
+}
+
+// note records a reason for the estimate once
+func (a *analyzer) note(format string, args ...interface{}) {
+	note := fmt.Sprintf(format, args...)
+	if !a.notes[note] {
+		a.notes[note] = true
+		a.order = append(a.order, note)
+	}
+}
+
+// walk costs a block and its children, run cost times each, within the
+// function that self matches calls to
+func (a *analyzer) walk(b *block, cost Order, self *regexp.Regexp) {
+	if name := functionName(b.header); name != "" {
+		self = regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*\(`)
+		cost = a.recursion(b, self, cost)
+	} else if isLoop(b.header) {
+		factor := a.loopFactor(b)
+		cost = cost.times(factor)
+		if cost.Poly >= 2 && factor.Poly > 0 {
+			a.note("nested loops (depth %d)", cost.Poly)
+		}
+	}
+	a.time = maxOrder(a.time, cost)
+
+	for _, line := range b.body {
+		a.time = maxOrder(a.time, a.lineCost(line, cost, self))
+	}
+	for _, child := range b.children {
+		a.walk(child, cost, self)
+	}
+}
+
+// loopFactor is how many times a loop runs. Loops that only test a
+// condition, such as binary search, are logarithmic if their body halves.
+func (a *analyzer) loopFactor(b *block) Order {
+	header := strings.TrimSpace(b.header)
+	conditionOnly := strings.HasPrefix(header, "while") ||
+		strings.HasPrefix(header, "for") && !strings.ContainsAny(header, ";:") && !strings.Contains(header, "range") && !strings.Contains(header, " in ")
+	switch {
+	case constantLoopPattern.MatchString(header) && !strings.Contains(header, "len"):
+		return Constant
+	case halvingPattern.MatchString(header), conditionOnly && halvingPattern.MatchString(strings.Join(b.body, "\n")):
+		a.note("halving loop")
+		return Logarithmic
+	}
+	return Linear
+}
+
+// lineCost is the cost of a statement run cost times, counting inline
+// iteration such as comprehensions, sorting and heap operations. Calls to
+// the enclosing function, such as a recursive sort, are costed as recursion
+// instead.
+func (a *analyzer) lineCost(line string, cost Order, self *regexp.Regexp) Order {
+	if self != nil && self.MatchString(line) {
+		return cost
+	}
+	if n := len(comprehensionFor.FindAllString(line, -1)); n > 0 {
+		cost = cost.times(Order{Poly: n})
+	}
+	if callbackPattern.MatchString(line) || linearCallPattern.MatchString(line) {
+		cost = cost.times(Linear)
+	}
+	if sortPattern.MatchString(line) {
+		a.note("sorting")
+		cost = cost.times(Linearithm)
+	}
+	if heapPattern.MatchString(line) {
+		a.note("heap operations")
+		cost = cost.times(Logarithmic)
+	}
+	return cost
+}
+
+// recursion returns the cost of running the body of the function defined
+// by fn, given how it calls itself
+func (a *analyzer) recursion(fn *block, callPattern *regexp.Regexp, cost Order) Order {
+	var calls int
+	var inLoop bool
+	var callLines []string
+	var visit func(b *block, loop bool)
+	visit = func(b *block, loop bool) {
+		if b != fn {
+			loop = loop || isLoop(b.header)
+			if n := len(callPattern.FindAllString(b.header, -1)); n > 0 {
+				calls += n
+				callLines = append(callLines, b.header)
+				inLoop = inLoop || loop
+			}
+		}
+		for _, line := range b.body {
+			if n := len(callPattern.FindAllString(line, -1)); n > 0 {
+				calls += n
+				callLines = append(callLines, line)
+				inLoop = inLoop || loop
+			}
+		}
+		for _, child := range b.children {
+			visit(child, loop)
+		}
+	}
+	visit(fn, false)
+	if calls == 0 {
+		return cost
+	}
+
+	callText := strings.Join(callLines, "\n")
+	halving := halvingPattern.MatchString(callText) || halvingPattern.MatchString(strings.Join(fn.body, "\n"))
+	switch {
+	case memoPattern.MatchString(a.code):
+		a.note("memoized recursion")
+		a.stackSpace = maxOrder(a.stackSpace, Linear)
+		return cost.times(Linear)
+	case structuralPattern.MatchString(callText) || visitedPattern.MatchString(a.code):
+		a.note("recursion visiting each element once")
+		a.stackSpace = maxOrder(a.stackSpace, Linear)
+		return maxOrder(cost, Linear)
+	case halving && calls == 1:
+		a.note("recursion on half the input")
+		a.stackSpace = maxOrder(a.stackSpace, Logarithmic)
+		return cost.times(Logarithmic)
+	case halving:
+		a.note("divide and conquer")
+		a.stackSpace = maxOrder(a.stackSpace, Logarithmic)
+		a.time = maxOrder(a.time, cost.times(Linear))
+		return cost.times(Logarithmic)
+	case calls > 1 || inLoop:
+		a.note("branching recursion without memoization")
+		a.stackSpace = maxOrder(a.stackSpace, Linear)
+		return cost.times(Exponential)
+	}
+	a.note("linear recursion")
+	a.stackSpace = maxOrder(a.stackSpace, Linear)
+	return cost.times(Linear)
+}
+
+// space estimates the extra space used: collections allocated outside
+// return statements, and the recursion stack
+func (a *analyzer) space(code string) Order {
+	space := a.stackSpace
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		// Results and parameters are not extra space
+		if strings.HasPrefix(line, "return") || functionName(strings.TrimSuffix(strings.TrimSuffix(line, "{"), ":")) != "" {
+			continue
+		}
+		switch {
+		case space2DPattern.MatchString(line):
+			if space.Compare(Quadratic) < 0 {
+				a.note("2D table")
+			}
+			space = maxOrder(space, Quadratic)
+		case space1DPattern.MatchString(line):
+			if space.Compare(Linear) < 0 {
+				a.note("extra collection")
+			}
+			space = maxOrder(space, Linear)
+		}
+	}
+	return space
+}
+
+// isLoop reports whether a block header starts a loop
+func isLoop(header string) bool {
+	header = strings.TrimSpace(header)
+	return loopHeaderPattern.MatchString(header) || callbackPattern.MatchString(header)
+}
+
+// functionName returns the name of the function a block header defines, or
+// an empty string. Headers starting with a keyword, such as a Python for
+// loop over a call, are never definitions.
+func functionName(header string) string {
+	header = strings.TrimSpace(header)
+	if fields := strings.Fields(header); len(fields) > 0 && keywords[fields[0]] {
+		return ""
+	}
+	for _, p := range functionPatterns {
+		if m := p.FindStringSubmatch(header); m != nil && !keywords[m[1]] {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// parseBraced splits brace-delimited code into blocks. A block's header is
+// the text before its opening brace on the same line, or the previous line
+// when the brace is on a line of its own.
+func parseBraced(code string) *block {
+	root := &block{}
+	cur := root
+	var line strings.Builder
+	var prevLine string
+
+	flush := func() {
+		if text := strings.TrimSpace(line.String()); text != "" {
+			cur.body = append(cur.body, text)
+			prevLine = text
+		}
+		line.Reset()
+	}
+
+	for _, r := range code {
+		switch r {
+		case '{':
+			header := strings.TrimSpace(line.String())
+			if header == "" || header == ")" {
+				// Brace on its own line: the header is the previous line
+				if n := len(cur.body); n > 0 && cur.body[n-1] == prevLine {
+					header = prevLine + " " + header
+					cur.body = cur.body[:n-1]
+				}
+			}
+			line.Reset()
+			child := &block{header: header, parent: cur}
+			cur.children = append(cur.children, child)
+			cur = child
+		case '}':
+			flush()
+			if cur.parent != nil {
+				cur = cur.parent
+			}
+		case '\n':
+			flush()
+		default:
+			line.WriteRune(r)
+		}
+	}
+	flush()
+	return root
+}
+
+// parseIndented splits indented code, such as Python, into blocks opened by
+// lines ending in a colon
+func parseIndented(code string) *block {
+	type level struct {
+		indent int
+		block  *block
+	}
+	root := &block{}
+	stack := []level{{-1, root}}
+
+	for _, raw := range strings.Split(code, "\n") {
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+		indent := len(strings.ReplaceAll(raw, "\t", "    ")) - len(strings.TrimLeft(strings.ReplaceAll(raw, "\t", "    "), " "))
+		for len(stack) > 1 && indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		cur := stack[len(stack)-1].block
+		if strings.HasSuffix(text, ":") {
+			child := &block{header: strings.TrimSuffix(text, ":"), parent: cur}
+			cur.children = append(cur.children, child)
+			stack = append(stack, level{indent, child})
+			continue
+		}
+		cur.body = append(cur.body, text)
+	}
+	return root
+}
+
+// stripCode blanks out comments and the contents of string literals, so
+// that keywords inside them are not mistaken for code. Line breaks are kept.
+func stripCode(language, code string) string {
+	hashComments := language == "python"
+	longQuotes := language == "python" || language == "javascript" || language == "typescript"
+
+	var b strings.Builder
+	src := []rune(code)
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case hashComments && c == '#', !hashComments && c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			if i < len(src) {
+				b.WriteRune('\n')
+			}
+		case !hashComments && c == '/' && i+1 < len(src) && src[i+1] == '*':
+			for i += 2; i < len(src) && !(src[i] == '*' && i+1 < len(src) && src[i+1] == '/'); i++ {
+				if src[i] == '\n' {
+					b.WriteRune('\n')
+				}
+			}
+			i++
+		case c == '"' || c == '`' || (c == '\'' && (longQuotes || isCharLiteral(src, i))):
+			quote := string(c)
+			if language == "python" && i+2 < len(src) && src[i+1] == c && src[i+2] == c {
+				quote = strings.Repeat(string(c), 3)
+			}
+			b.WriteString(`""`)
+			for i += len(quote); i < len(src); i++ {
+				if src[i] == '\\' && c != '`' {
+					i++
+					continue
+				}
+				if src[i] == '\n' {
+					if quote == string(c) && c != '`' {
+						break // Unterminated string; resume at the line break
+					}
+					b.WriteRune('\n')
+				}
+				if strings.HasPrefix(string(src[i:min(i+len(quote), len(src))]), quote) {
+					i += len(quote) - 1
+					break
+				}
+			}
+			if i < len(src) && src[i] == '\n' {
+				b.WriteRune('\n')
+			}
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// isCharLiteral reports whether the quote at i starts a character literal
+// such as 'a' or '\n', rather than a Rust lifetime
+func isCharLiteral(src []rune, i int) bool {
+	if i+2 < len(src) && src[i+1] != '\\' && src[i+2] == '\'' {
+		return true
+	}
+	return i+3 < len(src) && src[i+1] == '\\' && src[i+3] == '\''
+}
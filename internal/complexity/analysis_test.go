@@ -0,0 +1,188 @@
+package complexity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Order
+	}{
+		{"O(1)", Constant},
+		{"O(n)", Linear},
+		{"O(n log n)", Linearithm},
+		{"O(N log(N))", Linearithm},
+		{"O(n^2)", Quadratic},
+		{"O(n²)", Quadratic},
+		{"O(n * m)", Quadratic},
+		{"O(V + E)", Linear},
+		{"O(log n)", Logarithmic},
+		{"O(2^n)", Exponential},
+		{"O(n!)", Exponential},
+		{"n^2 log n", Order{Poly: 2, Log: 1}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseOrder(tt.in)
+		assert.True(t, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, bad := range []string{"", "O()", "fast", "O(n) probably"} {
+		_, ok := ParseOrder(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestOrderString(t *testing.T) {
+	assert.Equal(t, "O(1)", Constant.String())
+	assert.Equal(t, "O(n log n)", Linearithm.String())
+	assert.Equal(t, "O(n^2 log^2 n)", Order{Poly: 2, Log: 2}.String())
+	assert.Equal(t, "O(2^n)", Exponential.String())
+	assert.Equal(t, 1, Quadratic.Compare(Linearithm))
+	assert.Equal(t, -1, Linearithm.Compare(Exponential))
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		time     Order
+		space    Order
+	}{
+		{"hash map", "go", `
+func twoSum(nums []int, target int) []int {
+	seen := make(map[int]int)
+	for i, num := range nums {
+		if j, ok := seen[target-num]; ok {
+			return []int{j, i}
+		}
+		seen[num] = i
+	}
+	return nil
+}`, Linear, Linear},
+		{"brute force", "go", `
+func twoSum(nums []int, target int) []int {
+	// for each pair
+	for i := 0; i < len(nums); i++ {
+		for j := i + 1; j < len(nums); j++ {
+			if nums[i]+nums[j] == target {
+				return []int{i, j}
+			}
+		}
+	}
+	return nil
+}`, Quadratic, Constant},
+		{"fixed alphabet", "go", `
+func count(s string) int {
+	total := 0
+	for i := 0; i < 26; i++ {
+		for _, c := range s {
+			if int(c-'a') == i {
+				total++
+			}
+		}
+	}
+	return total
+}`, Linear, Constant},
+		{"sort then scan", "python", `
+def merge(intervals):
+    intervals.sort(key=lambda x: x[0])
+    merged = []
+    for start, end in intervals:
+        if merged and merged[-1][1] >= start:
+            merged[-1][1] = max(merged[-1][1], end)
+        else:
+            merged.append([start, end])
+    return merged`, Linearithm, Linear},
+		{"binary search", "python", `
+def search(nums, target):
+    lo, hi = 0, len(nums) - 1
+    while lo <= hi:
+        mid = (lo + hi) // 2
+        if nums[mid] == target:
+            return mid
+        if nums[mid] < target:
+            lo = mid + 1
+        else:
+            hi = mid - 1
+    return -1  # for loops would not reach here`, Logarithmic, Constant},
+		{"comprehension in loop", "python", `
+def pairs(nums):
+    out = []
+    for x in nums:
+        out.extend([x + y for y in nums])
+    return out`, Quadratic, Linear},
+		{"branching recursion", "javascript", `
+function fib(n) {
+  if (n < 2) { return n; }
+  return fib(n - 1) + fib(n - 2);
+}`, Exponential, Linear},
+		{"memoized recursion", "python", `
+def fib(n, memo={}):
+    if n < 2:
+        return n
+    if n not in memo:
+        memo[n] = fib(n - 1) + fib(n - 2)
+    return memo[n]`, Linear, Linear},
+		{"tree traversal", "go", `
+func maxDepth(root *TreeNode) int {
+	if root == nil {
+		return 0
+	}
+	return 1 + max(maxDepth(root.Left), maxDepth(root.Right))
+}`, Linear, Linear},
+		{"merge sort", "java", `
+class Solution {
+    public void sort(int[] a, int lo, int hi) {
+        if (hi - lo < 1) return;
+        int mid = (lo + hi) / 2;
+        sort(a, lo, mid);
+        sort(a, mid + 1, hi);
+        int[] tmp = new int[hi - lo + 1];
+        for (int i = lo; i <= hi; i++)
+        {
+            tmp[i - lo] = a[i];
+        }
+    }
+}`, Linearithm, Linear},
+		{"grid dp", "cpp", `
+int uniquePaths(int m, int n) {
+    vector<vector<int>> dp(m, vector<int>(n, 1));
+    for (int i = 1; i < m; i++) {
+        for (int j = 1; j < n; j++) {
+            dp[i][j] = dp[i-1][j] + dp[i][j-1];
+        }
+    }
+    return dp[m-1][n-1];
+}`, Quadratic, Quadratic},
+		{"strings are not code", "python", `
+def f(nums):
+    """for x in nums: for y in nums"""
+    return "while True: sorted(x)"`, Constant, Constant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est := Analyze(tt.language, tt.code)
+			assert.Equal(t, tt.time.String(), est.Time.String(), "time")
+			assert.Equal(t, tt.space.String(), est.Space.String(), "space")
+		})
+	}
+}
+
+func TestAnalyzeNotes(t *testing.T) {
+	est := Analyze("go", `
+func f(nums []int) {
+	sort.Ints(nums)
+	for i := range nums {
+		for j := range nums {
+			_ = i + j
+		}
+	}
+}`)
+	assert.Equal(t, []string{"sorting", "nested loops (depth 2)"}, est.Notes)
+}
@@ -0,0 +1,106 @@
+// Package complexity provides the Big-O quick reference shown in sessions
+// and estimates the complexity of submitted solutions
+package complexity
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+)
+
+// tablesJSON holds the reference tables
+//
+//go:embed tables.json
+var tablesJSON []byte
+
+// Table is one reference table, such as the operations of data structures
+type Table struct {
+	Title   string   `json:"title"`
+	Subject string   `json:"subject"` // Heading of the first column
+	Columns []string `json:"columns"`
+	Rows    []Row    `json:"rows"`
+	Notes   []string `json:"notes"`
+}
+
+// Row is the complexities of one data structure or algorithm
+type Row struct {
+	Name  string   `json:"name"`
+	Cells []string `json:"cells"`
+}
+
+// asciiReplacer spells out the symbols of the tables for ASCII-only terminals
+var asciiReplacer = strings.NewReplacer(
+	"²", "^2",
+	"ⁿ", "^n",
+	"α", "a",
+	"·", "*",
+	"†", "+",
+)
+
+// Tables returns the reference tables
+func Tables() ([]Table, error) {
+	var tables []Table
+	if err := json.Unmarshal(tablesJSON, &tables); err != nil {
+		return nil, fmt.Errorf("failed to parse complexity tables: %v", err)
+	}
+	return tables, nil
+}
+
+// Render returns the reference tables as aligned plain text
+func Render() string {
+	tables, err := Tables()
+	if err != nil {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(table.Title + "\n")
+		renderTable(&b, table)
+		for _, note := range table.Notes {
+			b.WriteString("  " + note + "\n")
+		}
+	}
+
+	text := strings.TrimRight(b.String(), "\n")
+	if symbols.ASCII() {
+		text = asciiReplacer.Replace(text)
+	}
+	return text
+}
+
+// renderTable writes a table's heading and rows in aligned columns
+func renderTable(b *strings.Builder, table Table) {
+	lines := [][]string{append([]string{table.Subject}, table.Columns...)}
+	for _, row := range table.Rows {
+		lines = append(lines, append([]string{row.Name}, row.Cells...))
+	}
+
+	widths := make([]int, len(lines[0]))
+	for _, line := range lines {
+		for i, cell := range line {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
+			}
+		}
+	}
+
+	for _, line := range lines {
+		var cells []string
+		for i, cell := range line {
+			if i == len(line)-1 {
+				cells = append(cells, cell)
+				break
+			}
+			cells = append(cells, cell+strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		b.WriteString("  " + strings.Join(cells, "  ") + "\n")
+	}
+}
@@ -0,0 +1,42 @@
+package complexity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTables(t *testing.T) {
+	tables, err := Tables()
+	require.NoError(t, err)
+	require.NotEmpty(t, tables)
+
+	for _, table := range tables {
+		for _, row := range table.Rows {
+			assert.Len(t, row.Cells, len(table.Columns), "%s: %s", table.Title, row.Name)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	original := symbols.ASCII()
+	defer symbols.SetASCII(original)
+	symbols.SetASCII(false)
+
+	text := Render()
+	assert.Contains(t, text, "Data Structures\n  Structure       Access    Search    Insert    Delete    Space\n")
+	assert.Contains(t, text, "  Balanced BST    O(log n)  O(log n)  O(log n)  O(log n)  O(n)\n")
+	assert.Contains(t, text, "O(n²)")
+
+	symbols.SetASCII(true)
+	ascii := Render()
+	assert.Contains(t, ascii, "O(n^2)")
+	for _, r := range ascii {
+		if r > 127 {
+			t.Fatalf("non-ASCII %q in %q", r, strings.SplitN(ascii, string(r), 2)[0])
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Complexity feedback on a passing solution, compared with the reference
+
+package complexity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Verdict is how a solution's complexity compares with the reference
+type Verdict string
+
+const (
+	VerdictOptimal   Verdict = "optimal"    // Matches or beats the reference
+	VerdictSlower    Verdict = "slower"     // Grows faster in time than the reference
+	VerdictMoreSpace Verdict = "more-space" // As fast as the reference, but uses more space
+	VerdictUnknown   Verdict = "unknown"    // There is no reference to compare with
+)
+
+// Where an estimate came from
+const (
+	SourceHeuristic = "heuristic"
+	SourceAI        = "ai"
+)
+
+// Feedback is the estimated complexity of a solution and of the problem's
+// reference solution
+type Feedback struct {
+	Time        Order
+	Space       Order
+	TargetTime  Order
+	TargetSpace Order
+	HasTarget   bool     // The problem has a reference solution
+	Source      string   // SourceHeuristic or SourceAI
+	Heuristic   Estimate // The static estimate, kept when the AI's is used
+	Explanation string   // The AI's reasoning, if it was asked
+}
+
+// Verdict compares the solution with the reference, time first
+func (f Feedback) Verdict() Verdict {
+	switch {
+	case !f.HasTarget:
+		return VerdictUnknown
+	case f.Time.Compare(f.TargetTime) > 0:
+		return VerdictSlower
+	case f.Time.Compare(f.TargetTime) == 0 && f.Space.Compare(f.TargetSpace) > 0:
+		return VerdictMoreSpace
+	}
+	return VerdictOptimal
+}
+
+// Summary describes the verdict in a sentence
+func (f Feedback) Summary() string {
+	switch f.Verdict() {
+	case VerdictSlower:
+		return fmt.Sprintf("Your solution is slower than the reference: %s time against %s.", f.Time, f.TargetTime)
+	case VerdictMoreSpace:
+		return fmt.Sprintf("Your solution matches the reference's time but uses %s space against %s.", f.Space, f.TargetSpace)
+	case VerdictOptimal:
+		return "Your solution matches the reference complexity."
+	}
+	return "There is no reference solution to compare with."
+}
+
+// Record converts the feedback to the form kept in the progress database
+func (f Feedback) Record(problemID, language string, at time.Time) storage.Complexity {
+	c := storage.Complexity{
+		ProblemID:  problemID,
+		Language:   language,
+		Time:       f.Time.String(),
+		Space:      f.Space.String(),
+		Verdict:    string(f.Verdict()),
+		Source:     f.Source,
+		AnalyzedAt: at,
+	}
+	if f.HasTarget {
+		c.TargetTime = f.TargetTime.String()
+		c.TargetSpace = f.TargetSpace.String()
+	}
+	return c
+}
+
+// reviewTimeout bounds the assistant's part of Assess
+const reviewTimeout = 30 * time.Second
+
+// Assess reviews a passing solution and records the result in the progress
+// database. The AI assistant is asked only if the user has configured one, so
+// that a submission never creates an AI config or spends tokens unasked.
+// Exported as variable for testing
+var Assess = func(prob problem.Problem, language, code string) Feedback {
+	ctx, cancel := context.WithTimeout(context.Background(), reviewTimeout)
+	defer cancel()
+
+	var assistant Assistant
+	if ai.Configured() {
+		if agent, err := ai.GetDefaultAgent(); err == nil {
+			assistant = agent
+		}
+	}
+	f := Review(ctx, prob, language, code, assistant)
+
+	repo := storage.Default()
+	defer repo.Close()
+	// The feedback is still worth showing if it cannot be stored
+	_ = repo.SaveComplexity(ctx, f.Record(prob.ID, language, time.Now()))
+	return f
+}
+
+// Assistant is the part of an AI agent the review needs
+type Assistant interface {
+	Chat(ctx context.Context, messages []ai.Message, opts ai.ChatOptions) (<-chan ai.ChatResponse, error)
+}
+
+// Review estimates the complexity of a passing solution and of the problem's
+// reference solution in the same language, or another if there is none.
+// When an assistant is given, its estimates take precedence over the
+// heuristics; if it fails or its reply cannot be read, the heuristics stand.
+func Review(ctx context.Context, prob problem.Problem, language, code string, assistant Assistant) Feedback {
+	est := Analyze(language, code)
+	f := Feedback{Time: est.Time, Space: est.Space, Heuristic: est, Source: SourceHeuristic}
+
+	refLanguage, reference := referenceSolution(prob, language)
+	if reference != "" {
+		target := Analyze(refLanguage, reference)
+		f.TargetTime, f.TargetSpace, f.HasTarget = target.Time, target.Space, true
+	}
+
+	if assistant == nil {
+		return f
+	}
+	reply, err := ask(ctx, assistant, buildPrompt(prob, language, code, refLanguage, reference))
+	if err != nil {
+		return f
+	}
+	if parsed, ok := parseReply(reply); ok {
+		f.Time, f.Space, f.Explanation, f.Source = parsed.Time, parsed.Space, parsed.Explanation, SourceAI
+		if f.HasTarget && parsed.HasTarget {
+			f.TargetTime, f.TargetSpace = parsed.TargetTime, parsed.TargetSpace
+		}
+	}
+	return f
+}
+
+// referenceSolution returns the problem's solution in language, or in the
+// first other language that has one
+func referenceSolution(prob problem.Problem, language string) (string, string) {
+	if code := prob.Solutions[language]; strings.TrimSpace(code) != "" {
+		return language, code
+	}
+	languages := make([]string, 0, len(prob.Solutions))
+	for lang := range prob.Solutions {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	for _, lang := range languages {
+		if code := prob.Solutions[lang]; strings.TrimSpace(code) != "" {
+			return lang, code
+		}
+	}
+	return "", ""
+}
+
+// buildPrompt asks for estimates in a fixed line format parseReply reads
+func buildPrompt(prob problem.Problem, language, code, refLanguage, reference string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Estimate the worst-case time and extra space complexity of this %s solution to %q. ", language, prob.Title)
+	b.WriteString("Do not count the input or the returned output as extra space.\n\n")
+	fmt.Fprintf(&b, "```%s\n%s\n```\n\n", language, strings.TrimRight(code, "\n"))
+	if reference != "" {
+		fmt.Fprintf(&b, "Also estimate the complexity of the reference solution:\n\n```%s\n%s\n```\n\n", refLanguage, strings.TrimRight(reference, "\n"))
+	}
+	b.WriteString("Reply with exactly these lines and nothing else, using Big-O notation:\n")
+	b.WriteString("Time: O(...)\nSpace: O(...)\n")
+	if reference != "" {
+		b.WriteString("Reference time: O(...)\nReference space: O(...)\n")
+	}
+	b.WriteString("Why: one sentence about the solution's complexity\n")
+	return b.String()
+}
+
+// ask sends a single prompt and collects the streamed reply
+func ask(ctx context.Context, assistant Assistant, prompt string) (string, error) {
+	responses, err := assistant.Chat(ctx, []ai.Message{{Role: "user", Content: prompt}}, ai.ChatOptions{Temperature: 0.1, MaxTokens: 300})
+	if err != nil {
+		return "", err
+	}
+	var reply strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		reply.WriteString(resp.Content)
+	}
+	return reply.String(), nil
+}
+
+// parseReply reads the estimates from an assistant's reply. The solution's
+// time and space are required; the reference's are used if both are given.
+func parseReply(reply string) (Feedback, bool) {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(reply, "\n") {
+		key, value, ok := strings.Cut(strings.Trim(strings.TrimSpace(line), "*-` "), ":")
+		if ok {
+			fields[strings.ToLower(strings.Trim(key, "*` "))] = strings.Trim(strings.TrimSpace(value), "*` ")
+		}
+	}
+
+	var f Feedback
+	var okTime, okSpace bool
+	f.Time, okTime = ParseOrder(fields["time"])
+	f.Space, okSpace = ParseOrder(fields["space"])
+	if !okTime || !okSpace {
+		return f, false
+	}
+	var okTargetTime, okTargetSpace bool
+	f.TargetTime, okTargetTime = ParseOrder(fields["reference time"])
+	f.TargetSpace, okTargetSpace = ParseOrder(fields["reference space"])
+	f.HasTarget = okTargetTime && okTargetSpace
+	f.Explanation = fields["why"]
+	return f, true
+}
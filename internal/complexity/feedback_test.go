@@ -0,0 +1,138 @@
+package complexity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAssistant replies with a fixed text, streamed in two chunks
+type fakeAssistant struct {
+	reply  string
+	err    error
+	prompt string
+}
+
+func (f *fakeAssistant) Chat(ctx context.Context, messages []ai.Message, opts ai.ChatOptions) (<-chan ai.ChatResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.prompt = messages[len(messages)-1].Content
+	ch := make(chan ai.ChatResponse, 2)
+	half := len(f.reply) / 2
+	ch <- ai.ChatResponse{Content: f.reply[:half]}
+	ch <- ai.ChatResponse{Content: f.reply[half:], Done: true}
+	close(ch)
+	return ch, nil
+}
+
+var twoSum = problem.Problem{
+	ID:    "two_sum",
+	Title: "Two Sum",
+	Solutions: map[string]string{
+		"python": "def two_sum(nums, target):\n    seen = {}\n    for i, n in enumerate(nums):\n        if target - n in seen:\n            return [seen[target - n], i]\n        seen[n] = i\n",
+	},
+}
+
+const bruteForce = `func twoSum(nums []int, target int) []int {
+	for i := range nums {
+		for j := i + 1; j < len(nums); j++ {
+			if nums[i]+nums[j] == target {
+				return []int{i, j}
+			}
+		}
+	}
+	return nil
+}`
+
+func TestVerdict(t *testing.T) {
+	f := Feedback{Time: Quadratic, Space: Constant, TargetTime: Linear, TargetSpace: Linear, HasTarget: true}
+	assert.Equal(t, VerdictSlower, f.Verdict())
+	assert.Contains(t, f.Summary(), "O(n^2) time against O(n)")
+
+	f.Time, f.Space = Linear, Quadratic
+	assert.Equal(t, VerdictMoreSpace, f.Verdict())
+
+	f.Space = Constant
+	assert.Equal(t, VerdictOptimal, f.Verdict())
+
+	f.HasTarget = false
+	assert.Equal(t, VerdictUnknown, f.Verdict())
+	assert.Equal(t, "There is no reference solution to compare with.", f.Summary())
+}
+
+func TestRecord(t *testing.T) {
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	f := Feedback{Time: Quadratic, Space: Constant, TargetTime: Linear, TargetSpace: Linear, HasTarget: true, Source: SourceHeuristic}
+
+	c := f.Record("two_sum", "go", at)
+	assert.Equal(t, "O(n^2)", c.Time)
+	assert.Equal(t, "O(n)", c.TargetTime)
+	assert.Equal(t, "slower", c.Verdict)
+	assert.Equal(t, at, c.AnalyzedAt)
+
+	f.HasTarget = false
+	assert.Empty(t, f.Record("two_sum", "go", at).TargetTime)
+}
+
+func TestReviewHeuristic(t *testing.T) {
+	f := Review(context.Background(), twoSum, "go", bruteForce, nil)
+
+	assert.Equal(t, SourceHeuristic, f.Source)
+	assert.Equal(t, Quadratic, f.Time)
+	assert.True(t, f.HasTarget, "the Python solution is the reference for Go")
+	assert.Equal(t, Linear, f.TargetTime)
+	assert.Equal(t, VerdictSlower, f.Verdict())
+}
+
+func TestReviewAI(t *testing.T) {
+	assistant := &fakeAssistant{reply: "**Time:** O(n^2)\nSpace: O(1)\nReference time: O(n)\nReference space: O(n)\nWhy: Every pair of numbers is checked.\n"}
+	f := Review(context.Background(), twoSum, "go", bruteForce, assistant)
+
+	assert.Equal(t, SourceAI, f.Source)
+	assert.Equal(t, Quadratic, f.Time)
+	assert.Equal(t, Constant, f.Space)
+	assert.Equal(t, "Every pair of numbers is checked.", f.Explanation)
+	assert.Equal(t, VerdictSlower, f.Verdict())
+	assert.Contains(t, assistant.prompt, "Two Sum")
+	assert.Contains(t, assistant.prompt, "seen = {}", "the reference solution is included")
+
+	// Failures and unreadable replies leave the heuristic estimate
+	for _, assistant := range []*fakeAssistant{{err: errors.New("offline")}, {reply: "It is pretty fast."}} {
+		f := Review(context.Background(), twoSum, "go", bruteForce, assistant)
+		assert.Equal(t, SourceHeuristic, f.Source)
+		assert.Equal(t, Quadratic, f.Time)
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	f, ok := parseReply("Time: O(n log n)\nSpace: O(n)\nWhy: sorting dominates")
+	require.True(t, ok)
+	assert.Equal(t, Linearithm, f.Time)
+	assert.False(t, f.HasTarget)
+	assert.Equal(t, "sorting dominates", f.Explanation)
+
+	_, ok = parseReply("Time: O(n)")
+	assert.False(t, ok, "space is required")
+}
+
+func TestReferenceSolution(t *testing.T) {
+	prob := problem.Problem{Solutions: map[string]string{"java": "class A {}", "cpp": "int f();", "go": "  "}}
+
+	lang, code := referenceSolution(prob, "java")
+	assert.Equal(t, "java", lang)
+	assert.Equal(t, "class A {}", code)
+
+	lang, _ = referenceSolution(prob, "go")
+	assert.Equal(t, "cpp", lang, "blank solutions are skipped and others are tried in order")
+
+	lang, code = referenceSolution(problem.Problem{}, "go")
+	assert.Empty(t, lang)
+	assert.Empty(t, code)
+}
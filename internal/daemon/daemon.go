@@ -0,0 +1,274 @@
+// Package daemon serves JSON-RPC 2.0 requests over a unix socket, so editor
+// plugins can reuse one warm process instead of starting one per command.
+// Messages are newline-delimited JSON, as in the MCP server.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxMessageBytes bounds a single message, which may carry a solution
+const maxMessageBytes = 4 << 20
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	// CodeFailed is returned when a method runs but fails, with its error as
+	// the message
+	CodeFailed = -32000
+)
+
+// Handler runs a method. Its result is sent to the client as JSON; an error
+// is sent as a CodeFailed error.
+type Handler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// Server answers JSON-RPC requests with its methods
+type Server struct {
+	mu       sync.RWMutex
+	methods  map[string]Handler
+	shutdown chan struct{}
+	once     sync.Once
+	requests int64
+}
+
+// NewServer creates a server that answers ping and shutdown
+func NewServer() *Server {
+	s := &Server{methods: make(map[string]Handler), shutdown: make(chan struct{})}
+	s.Handle("ping", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return map[string]int64{"requests": atomic.LoadInt64(&s.requests)}, nil
+	})
+	// Serve stops once the answer to shutdown is sent
+	s.Handle("shutdown", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return struct{}{}, nil
+	})
+	return s
+}
+
+// Handle makes a method available, replacing one with the same name
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[method] = handler
+}
+
+// request is an incoming JSON-RPC request or notification. Notifications
+// have no ID.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is an outgoing JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ListenAndServe serves connections on the unix socket at path until ctx is
+// cancelled or a client calls shutdown. A socket left by a daemon that is
+// no longer running is replaced; one a running daemon answers on is not.
+func (s *Server) ListenAndServe(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	// Only the user may talk to their daemon
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve answers each connection accepted by listener until ctx is cancelled
+// or a client calls shutdown, then closes listener and waits for the
+// requests in progress
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.shutdown:
+			cancel()
+		}
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			// Closing the connection ends the blocked read on shutdown
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+			s.ServeConn(ctx, conn, conn)
+		}()
+	}
+}
+
+// ServeConn reads requests from r and writes responses to w until r ends or
+// ctx is cancelled. A connection's requests are answered one at a time, in
+// order; connections are answered concurrently.
+func (s *Server) ServeConn(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+
+	// Encode writes each response as a single line
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := encoder.Encode(errorResponse(json.RawMessage("null"), codeParseError, "invalid JSON")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		resp := s.handle(ctx, req)
+		// Notifications are run but not answered
+		if len(req.ID) > 0 {
+			if err := encoder.Encode(resp); err != nil {
+				return err
+			}
+		}
+		if req.Method == "shutdown" && resp.Error == nil {
+			s.once.Do(func() { close(s.shutdown) })
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers a request
+func (s *Server) handle(ctx context.Context, req request) response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "not a JSON-RPC 2.0 request")
+	}
+	atomic.AddInt64(&s.requests, 1)
+
+	s.mu.RLock()
+	handler, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+	if !ok {
+		return errorResponse(req.ID, codeMethodNotFound, fmt.Sprintf("method %q is not supported", req.Method))
+	}
+
+	params := req.Params
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	}
+	result, err := handler(ctx, params)
+	if err != nil {
+		return errorResponse(req.ID, CodeFailed, err.Error())
+	}
+	return response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	return response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+// Call sends one request to the daemon listening at path and decodes its
+// result into result, which may be nil. Errors the daemon returns are
+// *Error values.
+func Call(ctx context.Context, path, method string, params, result interface{}) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("no daemon is listening on %s: %v", path, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("the daemon closed the connection")
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		return fmt.Errorf("invalid response: %v", err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.Handle("echo", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Message == "" {
+			return nil, errors.New("message is required")
+		}
+		return p, nil
+	})
+	return s
+}
+
+// socketPath returns a socket path short enough for unix sockets
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "asd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "daemon.sock")
+}
+
+func TestServeConn(t *testing.T) {
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"echo","params":{"message":"hi"}}`,
+		`{"jsonrpc":"2.0","method":"echo","params":{"message":"unanswered"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"echo"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"hint"}`,
+		`not json`,
+		`{"id":4,"method":"echo"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"ping"}`,
+	}
+	var out bytes.Buffer
+	require.NoError(t, newTestServer().ServeConn(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+
+	var responses []response
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp response
+		require.NoError(t, decoder.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	require.Len(t, responses, 6, "the notification is not answered")
+
+	assert.Equal(t, map[string]interface{}{"message": "hi"}, responses[0].Result)
+	assert.Equal(t, CodeFailed, responses[1].Error.Code)
+	assert.Equal(t, "message is required", responses[1].Error.Message)
+	assert.Equal(t, codeMethodNotFound, responses[2].Error.Code)
+	assert.Equal(t, codeParseError, responses[3].Error.Code)
+	assert.Equal(t, codeInvalidRequest, responses[4].Error.Code)
+	assert.Equal(t, map[string]interface{}{"requests": float64(5)}, responses[5].Result)
+}
+
+func TestListenAndServe(t *testing.T) {
+	path := socketPath(t)
+	s := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- s.ListenAndServe(context.Background(), path) }()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	ctx := context.Background()
+	var result struct {
+		Message string `json:"message"`
+	}
+	require.NoError(t, Call(ctx, path, "echo", map[string]string{"message": "warm"}, &result))
+	assert.Equal(t, "warm", result.Message)
+
+	err = Call(ctx, path, "echo", nil, nil)
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "message is required", rpcErr.Message)
+
+	// A second daemon does not take over the socket
+	assert.ErrorContains(t, NewServer().ListenAndServe(ctx, path), "already listening")
+
+	require.NoError(t, Call(ctx, path, "shutdown", nil, nil))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the daemon did not shut down")
+	}
+	assert.NoFileExists(t, path)
+	assert.ErrorContains(t, Call(ctx, path, "ping", nil, nil), "no daemon is listening")
+}
+
+func TestListenAndServeReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer().ListenAndServe(ctx, path) }()
+	require.Eventually(t, func() bool {
+		return Call(context.Background(), path, "ping", nil, nil) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the daemon did not stop")
+	}
+}
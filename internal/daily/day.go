@@ -0,0 +1,97 @@
+// Day boundary of daily practice
+
+package daily
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// DateLayout is how practice days are written
+const DateLayout = "2006-01-02"
+
+// DayBoundary decides which practice day a moment falls on, so practice
+// late at night can count towards the day before and travel does not move
+// the day
+type DayBoundary struct {
+	ResetHour int            // Hour, 0 to 23, a new practice day starts
+	Location  *time.Location // Time zone days are counted in; nil for local time
+}
+
+// NewDayBoundary builds the boundary a config sets out. An unknown time
+// zone is an error.
+func NewDayBoundary(cfg config.DayBoundary) (DayBoundary, error) {
+	if cfg.ResetHour < 0 || cfg.ResetHour > 23 {
+		return DayBoundary{}, fmt.Errorf("reset hour %d is not between 0 and 23", cfg.ResetHour)
+	}
+	boundary := DayBoundary{ResetHour: cfg.ResetHour}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return DayBoundary{}, fmt.Errorf("unknown time zone %q", cfg.Timezone)
+		}
+		boundary.Location = loc
+	}
+	return boundary, nil
+}
+
+// LoadDayBoundary returns the configured day boundary. A config that cannot
+// be loaded or sets out an invalid boundary uses local midnight.
+// Exported as variable for testing
+var LoadDayBoundary = func() DayBoundary {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return DayBoundary{}
+	}
+	boundary, err := NewDayBoundary(cfg.DayBoundary)
+	if err != nil {
+		return DayBoundary{}
+	}
+	return boundary
+}
+
+// Day returns the practice day t falls on, as midnight UTC of its date so
+// days are always 24 hours apart
+func (b DayBoundary) Day(t time.Time) time.Time {
+	loc := b.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	shifted := t.In(loc).Add(-time.Duration(b.ResetHour) * time.Hour)
+	return time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Date returns the practice day t falls on in YYYY-MM-DD form
+func (b DayBoundary) Date(t time.Time) string {
+	return b.Day(t).Format(DateLayout)
+}
+
+// Describe says when a new practice day starts, such as "midnight" or
+// "4:00 AM in Asia/Tokyo"
+func (b DayBoundary) Describe() string {
+	when := "midnight"
+	if b.ResetHour > 0 {
+		when = time.Date(2000, 1, 1, b.ResetHour, 0, 0, 0, time.UTC).Format("3:04 PM")
+	}
+	if b.Location != nil {
+		when += " in " + b.Location.String()
+	}
+	return when
+}
+
+// DaysBetween returns how many practice days after from to falls
+func (b DayBoundary) DaysBetween(from, to time.Time) int {
+	return int(b.Day(to).Sub(b.Day(from)) / (24 * time.Hour))
+}
+
+// Today returns the current practice day in YYYY-MM-DD form
+func Today() string {
+	return LoadDayBoundary().Date(time.Now())
+}
+
+// DateOf returns the practice day t fell on in YYYY-MM-DD form
+func DateOf(t time.Time) string {
+	return LoadDayBoundary().Date(t)
+}
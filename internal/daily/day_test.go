@@ -0,0 +1,75 @@
+package daily
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDayBoundary has practice days follow boundary for the rest of a test
+func stubDayBoundary(t *testing.T, boundary DayBoundary) {
+	t.Helper()
+	original := LoadDayBoundary
+	t.Cleanup(func() { LoadDayBoundary = original })
+	LoadDayBoundary = func() DayBoundary { return boundary }
+}
+
+func TestDayBoundary(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	midnight := DayBoundary{Location: time.UTC}
+	assert.Equal(t, "2026-03-04", midnight.Date(time.Date(2026, 3, 4, 2, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "midnight in UTC", midnight.Describe())
+
+	// Practice before the reset hour counts towards the day before
+	owl := DayBoundary{ResetHour: 4, Location: time.UTC}
+	assert.Equal(t, "2026-03-03", owl.Date(time.Date(2026, 3, 4, 2, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "2026-03-04", owl.Date(time.Date(2026, 3, 4, 4, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "4:00 AM in UTC", owl.Describe())
+
+	// A fixed time zone keeps the day wherever the clock is
+	fixed := DayBoundary{Location: tokyo}
+	newYork := time.FixedZone("EST", -5*3600)
+	assert.Equal(t, "2026-03-05", fixed.Date(time.Date(2026, 3, 4, 20, 0, 0, 0, newYork)))
+
+	assert.Equal(t, 1, owl.DaysBetween(time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), time.Date(2026, 3, 6, 3, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, owl.DaysBetween(time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), time.Date(2026, 3, 5, 3, 59, 0, 0, time.UTC)))
+}
+
+func TestNewDayBoundary(t *testing.T) {
+	boundary, err := NewDayBoundary(config.DayBoundary{ResetHour: 4, Timezone: "Europe/Berlin"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, boundary.ResetHour)
+	assert.Equal(t, "Europe/Berlin", boundary.Location.String())
+
+	boundary, err = NewDayBoundary(config.DayBoundary{})
+	require.NoError(t, err)
+	assert.Nil(t, boundary.Location, "days follow local time")
+
+	_, err = NewDayBoundary(config.DayBoundary{ResetHour: 24})
+	assert.EqualError(t, err, "reset hour 24 is not between 0 and 23")
+	_, err = NewDayBoundary(config.DayBoundary{Timezone: "Mars/Olympus"})
+	assert.EqualError(t, err, `unknown time zone "Mars/Olympus"`)
+}
+
+func TestUpdateStreakAfterMidnight(t *testing.T) {
+	original := MaxFreezes
+	t.Cleanup(func() { MaxFreezes = original })
+	MaxFreezes = func() int { return 0 }
+
+	// With days starting an hour from now, practice an hour ago was today
+	now := time.Now().UTC()
+	stubDayBoundary(t, DayBoundary{ResetHour: (now.Hour() + 1) % 24, Location: time.UTC})
+	progress := ScaleProgress{Streak: 3, LongestStreak: 3, LastPracticed: now.Add(-time.Hour)}
+	UpdateStreak(&progress)
+	assert.Equal(t, 3, progress.Streak)
+
+	// And practice 25 hours ago was yesterday
+	progress.LastPracticed = now.Add(-25 * time.Hour)
+	UpdateStreak(&progress)
+	assert.Equal(t, 4, progress.Streak)
+}
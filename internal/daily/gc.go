@@ -0,0 +1,244 @@
+// Garbage collection of old daily workspaces
+
+package daily
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// DefaultKeepDays is how many days of daily workspaces gc keeps by default
+const DefaultKeepDays = 90
+
+// ArchiveDirName is the directory under the daily workspace that gc writes
+// archived workspaces to
+const ArchiveDirName = "archive"
+
+// GCPolicy decides which daily workspaces gc cleans up
+type GCPolicy struct {
+	KeepDays   int  // Workspaces from the last KeepDays days are kept
+	KeepSolved bool // Workspaces with a problem solved that day are kept at any age
+	Delete     bool // Delete old workspaces instead of archiving them
+}
+
+// GCAction is what gc does with a daily workspace
+type GCAction string
+
+const (
+	// GCKeep leaves the workspace in place
+	GCKeep GCAction = "keep"
+	// GCArchive zips the workspace into the archive directory, then removes it
+	GCArchive GCAction = "archive"
+	// GCDelete removes the workspace
+	GCDelete GCAction = "delete"
+)
+
+// GCEntry is one daily workspace and what the policy does with it
+type GCEntry struct {
+	Date    time.Time
+	Path    string
+	Files   int
+	Size    int64    // Bytes in the workspace's files
+	Solved  []string // Problems solved in the workspace
+	Expired bool     // Older than the policy keeps workspaces for
+	Action  GCAction
+}
+
+// SolvedByDay groups the problems solved in daily practice by the practice
+// day, in YYYY-MM-DD form, their session started on, which is the day their
+// workspace is named after
+func SolvedByDay(sessions []interfaces.SessionStats) map[string][]string {
+	boundary := LoadDayBoundary()
+	solved := make(map[string][]string)
+	for _, s := range sessions {
+		if s.Mode == "daily" && s.Solved {
+			day := boundary.Date(s.StartTime)
+			solved[day] = append(solved[day], s.ProblemID)
+		}
+	}
+	return solved
+}
+
+// PlanGC lists the daily workspaces under root, oldest first, and decides
+// what the policy does with each. Directories not named after a date, such
+// as the archive, are left alone.
+func PlanGC(root string, policy GCPolicy, solved map[string][]string, now time.Time) ([]GCEntry, error) {
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daily workspaces: %v", err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	cutoff := today.AddDate(0, 0, -policy.KeepDays)
+
+	var entries []GCEntry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02", dir.Name(), time.Local)
+		if err != nil {
+			continue
+		}
+
+		entry := GCEntry{Date: date, Path: filepath.Join(root, dir.Name()), Action: GCKeep}
+		if entry.Files, entry.Size, err = dirSize(entry.Path); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", entry.Path, err)
+		}
+		entry.Solved = solvedIn(entry.Path, solved[dir.Name()])
+
+		entry.Expired = date.Before(cutoff)
+		if entry.Expired && !(policy.KeepSolved && len(entry.Solved) > 0) {
+			entry.Action = GCArchive
+			if policy.Delete {
+				entry.Action = GCDelete
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date.Before(entries[j].Date) })
+	return entries, nil
+}
+
+// ApplyGC archives or deletes the planned workspaces. Archives are written
+// to the archive directory under root as YYYY-MM-DD.zip; a workspace is
+// only removed once its archive is complete.
+func ApplyGC(root string, entries []GCEntry) error {
+	for _, entry := range entries {
+		if entry.Action != GCArchive && entry.Action != GCDelete {
+			continue
+		}
+		if entry.Action == GCArchive {
+			if err := archiveWorkspace(entry.Path, filepath.Join(root, ArchiveDirName)); err != nil {
+				return fmt.Errorf("failed to archive %s: %v", entry.Path, err)
+			}
+		}
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", entry.Path, err)
+		}
+	}
+	return nil
+}
+
+// solvedIn returns the solved problems that have a file in the workspace
+func solvedIn(dir string, solved []string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, id := range solved {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if matches, _ := filepath.Glob(filepath.Join(dir, id+".*")); len(matches) > 0 {
+			found = append(found, id)
+		}
+	}
+	return found
+}
+
+// dirSize counts the files in a directory and their total size
+func dirSize(dir string) (int, int64, error) {
+	files, size := 0, int64(0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	return files, size, err
+}
+
+// archiveWorkspace zips a workspace into archiveDir, naming the archive
+// after the workspace and numbering it if an archive of that name exists
+func archiveWorkspace(dir, archiveDir string) error {
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return err
+	}
+	name := filepath.Base(dir)
+	path := filepath.Join(archiveDir, name+".zip")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(archiveDir, fmt.Sprintf("%s-%d.zip", name, i))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeZip(f, dir, name); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// writeZip writes the files of dir into a zip archive under prefix
+func writeZip(w io.Writer, dir, prefix string) error {
+	archive := zip.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		dst, err := archive.Create(prefix + "/" + filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// FormatSize formats a byte count compactly, e.g. "512 B" or "12.3 KB"
+func FormatSize(size int64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + " " + units[unit]
+}
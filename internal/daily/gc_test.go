@@ -0,0 +1,91 @@
+package daily
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeWorkspace creates a daily workspace with the given files
+func writeWorkspace(t *testing.T, root, day string, files ...string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, day), 0755))
+	for _, name := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(root, day, name), []byte("package main\n"), 0644))
+	}
+}
+
+func TestPlanGC(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspace(t, root, "2024-01-05", "two_sum.go", "valid_parentheses.go")
+	writeWorkspace(t, root, "2024-02-10", "binary_search.py")
+	writeWorkspace(t, root, "2024-06-01", "two_sum.go")
+	writeWorkspace(t, root, ArchiveDirName, "2023-12-01.zip")
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.Local)
+
+	sessions := []interfaces.SessionStats{
+		{ProblemID: "two_sum", Mode: "daily", Solved: true, StartTime: time.Date(2024, 1, 5, 9, 0, 0, 0, time.Local)},
+		{ProblemID: "binary_search", Mode: "daily", StartTime: time.Date(2024, 2, 10, 9, 0, 0, 0, time.Local)},
+		{ProblemID: "binary_search", Mode: "practice", Solved: true, StartTime: time.Date(2024, 2, 10, 10, 0, 0, 0, time.Local)},
+	}
+	solved := SolvedByDay(sessions)
+	assert.Equal(t, map[string][]string{"2024-01-05": {"two_sum"}}, solved)
+
+	entries, err := PlanGC(root, GCPolicy{KeepDays: 90}, solved, now)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, GCArchive, entries[0].Action)
+	assert.Equal(t, 2, entries[0].Files)
+	assert.Equal(t, int64(26), entries[0].Size)
+	assert.Equal(t, []string{"two_sum"}, entries[0].Solved)
+	assert.Equal(t, GCArchive, entries[1].Action)
+	assert.Equal(t, GCKeep, entries[2].Action, "recent workspaces are kept")
+	assert.False(t, entries[2].Expired)
+
+	// Solved work can be kept at any age, and deleted rather than archived
+	entries, err = PlanGC(root, GCPolicy{KeepDays: 90, KeepSolved: true, Delete: true}, solved, now)
+	require.NoError(t, err)
+	assert.Equal(t, GCKeep, entries[0].Action)
+	assert.True(t, entries[0].Expired)
+	assert.Equal(t, GCDelete, entries[1].Action)
+
+	entries, err = PlanGC(filepath.Join(root, "missing"), GCPolicy{}, nil, now)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestApplyGC(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspace(t, root, "2024-01-05", "two_sum.go")
+	writeWorkspace(t, root, "2024-02-10", "binary_search.py")
+	writeWorkspace(t, root, ArchiveDirName, "2024-01-05.zip")
+
+	entries := []GCEntry{
+		{Path: filepath.Join(root, "2024-01-05"), Action: GCArchive},
+		{Path: filepath.Join(root, "2024-02-10"), Action: GCDelete},
+	}
+	require.NoError(t, ApplyGC(root, entries))
+	assert.NoDirExists(t, entries[0].Path)
+	assert.NoDirExists(t, entries[1].Path)
+	assert.NoFileExists(t, filepath.Join(root, ArchiveDirName, "2024-02-10.zip"))
+
+	// An existing archive of the same day is not overwritten
+	archive, err := zip.OpenReader(filepath.Join(root, ArchiveDirName, "2024-01-05-2.zip"))
+	require.NoError(t, err)
+	defer archive.Close()
+	require.Len(t, archive.File, 1)
+	assert.Equal(t, "2024-01-05/two_sum.go", archive.File[0].Name)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1 KB", FormatSize(1024))
+	assert.Equal(t, "12.3 KB", FormatSize(12595))
+	assert.Equal(t, "2.5 MB", FormatSize(5*1024*1024/2))
+}
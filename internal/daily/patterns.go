@@ -66,9 +66,10 @@ var Scales = []Scale{
 	},
 }
 
-// GetNextScale finds the next scale to practice based on completed patterns
+// GetNextScale finds the next of the active routine's scales to practice
+// based on completed patterns
 func GetNextScale(completed []string) *Scale {
-	for _, scale := range Scales {
+	for _, scale := range LoadRoutine().Scales() {
 		if !Contains(completed, scale.Pattern) {
 			return &scale
 		}
@@ -96,10 +97,11 @@ func GetPatternIndex(pattern string) int {
 	return -1
 }
 
-// GetRemainingPatterns returns the number of patterns not yet completed
+// GetRemainingPatterns returns the number of the active routine's patterns
+// not yet completed
 func GetRemainingPatterns(completed []string) int {
 	count := 0
-	for _, scale := range Scales {
+	for _, scale := range LoadRoutine().Scales() {
 		if !Contains(completed, scale.Pattern) {
 			count++
 		}
@@ -3,10 +3,13 @@ package daily
 import (
 	"testing"
 
+	"github.com/lancekrogers/algo-scales/internal/common/config"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetNextScale(t *testing.T) {
+	withRotation(t, config.DailyRotation{})
+
 	tests := []struct {
 		name      string
 		completed []string
@@ -126,6 +129,8 @@ func TestGetPatternIndex(t *testing.T) {
 }
 
 func TestGetRemainingPatterns(t *testing.T) {
+	withRotation(t, config.DailyRotation{})
+
 	tests := []struct {
 		name      string
 		completed []string
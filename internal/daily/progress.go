@@ -2,24 +2,14 @@
 package daily
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
 	"time"
 
-	"go.etcd.io/bbolt"
-)
-
-const (
-	// BucketName is the BoltDB bucket for storing daily progress
-	BucketName = "daily_progress"
-	
-	// ProgressKey is the key for storing the ScaleProgress struct
-	ProgressKey = "progress"
-	
-	// DBFileName is the name of the BoltDB database file
-	DBFileName = "daily.db"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 )
 
 // ScaleProgress tracks progress through scales
@@ -29,126 +19,76 @@ type ScaleProgress struct {
 	Completed     []string  `json:"completed"`
 	Streak        int       `json:"streak"`
 	LongestStreak int       `json:"longest_streak"`
+	Freezes       int       `json:"freezes"` // Streak freezes held, each covering one missed day
 }
 
-// LoadProgress loads the scale progress from BoltDB
+// LoadProgress loads the scale progress from the progress database
 func LoadProgress() (ScaleProgress, error) {
-	dbPath := GetDBPath()
-	
-	// Create dirs if needed
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-		return ScaleProgress{}, fmt.Errorf("error creating directories: %w", err)
-	}
-	
-	// Default progress (starting fresh)
-	defaultProgress := ScaleProgress{
-		Current:       0,
-		LastPracticed: time.Time{}, // Zero time (never practiced)
-		Completed:     []string{},
-		Streak:        0,
-		LongestStreak: 0,
-	}
-	
-	// Open database file (will be created if it doesn't exist)
-	db, err := bbolt.Open(dbPath, 0600, nil)
-	if err != nil {
-		return defaultProgress, fmt.Errorf("error opening database: %w", err)
-	}
-	defer db.Close()
-	
-	// Initialize the bucket if it doesn't exist
-	err = db.Update(func(tx *bbolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(BucketName))
-		if err != nil {
-			return fmt.Errorf("error creating bucket: %w", err)
-		}
-		return nil
-	})
-	if err != nil {
-		return defaultProgress, fmt.Errorf("error initializing database: %w", err)
-	}
-	
-	// Load progress data
-	var progress ScaleProgress
-	err = db.View(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-		data := bucket.Get([]byte(ProgressKey))
-		
-		if data == nil {
-			// No data yet, return default
-			progress = defaultProgress
-			return nil
-		}
-		
-		// Unmarshal the JSON data
-		if err := json.Unmarshal(data, &progress); err != nil {
-			return fmt.Errorf("error unmarshaling progress data: %w", err)
-		}
-		
-		return nil
-	})
-	
+	store := storage.Open(GetDBPath())
+	defer store.Close()
+
+	streak, err := store.LoadStreak(context.Background(), storage.DailyStreak)
 	if err != nil {
-		return defaultProgress, fmt.Errorf("error loading progress: %w", err)
+		return ScaleProgress{Completed: []string{}}, fmt.Errorf("error loading progress: %w", err)
 	}
-	
-	return progress, nil
+
+	return ScaleProgress{
+		Current:       streak.Position,
+		LastPracticed: streak.LastPracticed,
+		Completed:     streak.Completed,
+		Streak:        streak.Current,
+		LongestStreak: streak.Longest,
+		Freezes:       streak.Freezes,
+	}, nil
 }
 
-// SaveProgress saves the scale progress to BoltDB
+// SaveProgress saves the scale progress to the progress database
 func SaveProgress(progress ScaleProgress) error {
-	dbPath := GetDBPath()
-	
-	// Open database file
-	db, err := bbolt.Open(dbPath, 0600, nil)
-	if err != nil {
-		return fmt.Errorf("error opening database: %w", err)
-	}
-	defer db.Close()
-	
-	// Marshal the progress struct to JSON
-	data, err := json.Marshal(progress)
-	if err != nil {
-		return fmt.Errorf("error marshaling progress data: %w", err)
-	}
-	
-	// Save to database
-	err = db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(BucketName))
-		err := bucket.Put([]byte(ProgressKey), data)
-		if err != nil {
-			return fmt.Errorf("error saving progress data: %w", err)
-		}
-		return nil
+	store := storage.Open(GetDBPath())
+	defer store.Close()
+
+	err := store.SaveStreak(context.Background(), storage.Streak{
+		Name:          storage.DailyStreak,
+		Current:       progress.Streak,
+		Longest:       progress.LongestStreak,
+		LastPracticed: progress.LastPracticed,
+		Position:      progress.Current,
+		Completed:     progress.Completed,
+		Freezes:       progress.Freezes,
 	})
-	
 	if err != nil {
 		return fmt.Errorf("error saving progress: %w", err)
 	}
-	
 	return nil
 }
 
-// UpdateStreak updates the practice streak based on the last practice time
-func UpdateStreak(progress *ScaleProgress) {
+// UpdateStreak updates the practice streak based on the last practice time.
+// Missed days are covered by streak freezes when enough are held, keeping
+// the streak alive; it returns how many freezes were spent.
+func UpdateStreak(progress *ScaleProgress) int {
 	// If this is the first practice session ever
 	if progress.LastPracticed.IsZero() {
 		progress.Streak = 1
 		progress.LongestStreak = 1
-		return
+		return 0
 	}
 	
-	today := time.Now().Truncate(24 * time.Hour)
-	yesterday := today.Add(-24 * time.Hour)
-	lastPracticed := progress.LastPracticed.Truncate(24 * time.Hour)
+	days := LoadDayBoundary().DaysBetween(progress.LastPracticed, time.Now())
 	
 	// If practiced today, don't update streak
-	if lastPracticed.Equal(today) {
-		return
+	if days <= 0 {
+		return 0
 	}
 	
-	// If practiced yesterday, increment streak
-	if lastPracticed.Equal(yesterday) {
+	// Practiced yesterday, or missed days the freezes can cover: the
+	// streak goes on. Otherwise it starts again, keeping the freezes.
+	limit := MaxFreezes()
+	progress.Freezes = min(progress.Freezes, limit)
+	missed := days - 1
+	spent := 0
+	if missed <= progress.Freezes {
+		spent = missed
+		progress.Freezes -= missed
 		progress.Streak++
 		if progress.Streak > progress.LongestStreak {
 			progress.LongestStreak = progress.Streak
@@ -157,19 +97,91 @@ func UpdateStreak(progress *ScaleProgress) {
 		// Break in streak, reset to 1
 		progress.Streak = 1
 	}
+	
+	// Every week of streak earns a freeze
+	if progress.Streak%FreezeEarnDays == 0 && progress.Freezes < limit {
+		progress.Freezes++
+	}
+	return spent
 }
 
-// Make getDBPath a variable for testing
-var GetDBPath = func() string {
-	// Get user's home directory
-	homeDir, err := os.UserHomeDir()
+// FreezeEarnDays is how many days of streak earn a streak freeze
+const FreezeEarnDays = 7
+
+// DefaultMaxFreezes is how many streak freezes can be held at once unless
+// configured otherwise
+const DefaultMaxFreezes = 2
+
+// MaxFreezes returns how many streak freezes can be held at once. A config
+// that cannot be loaded uses the default.
+// Exported as variable for testing
+var MaxFreezes = func() int {
+	cfg, err := config.LoadConfig()
+	if err != nil || cfg.StreakFreezes == 0 {
+		return DefaultMaxFreezes
+	}
+	return max(cfg.StreakFreezes, 0)
+}
+
+// RecountStreak recounts the daily streak from the daily practice sessions
+// left in repo, after some were deleted: the current streak is the run of
+// consecutive days ending on the last day practiced, as UpdateStreak would
+// have counted it without freezes. The position in the rotation and the
+// freezes held are kept.
+func RecountStreak(ctx context.Context, repo storage.Repository) (storage.Streak, error) {
+	sessions, err := repo.LoadAllSessions(ctx)
 	if err != nil {
-		// Fallback to current directory if can't get home
-		return DBFileName
+		return storage.Streak{}, fmt.Errorf("error loading sessions: %w", err)
 	}
-	
-	// Create path for database file in .algo-scales directory
-	return filepath.Join(homeDir, ".algo-scales", "stats", DBFileName)
+	streak, err := repo.LoadStreak(ctx, storage.DailyStreak)
+	if err != nil {
+		return storage.Streak{}, fmt.Errorf("error loading progress: %w", err)
+	}
+
+	streak.Current, streak.Longest, streak.LastPracticed = countStreak(sessions, LoadDayBoundary())
+	if err := repo.SaveStreak(ctx, streak); err != nil {
+		return storage.Streak{}, fmt.Errorf("error saving progress: %w", err)
+	}
+	return streak, nil
+}
+
+// countStreak returns the current and longest runs of consecutive days with
+// daily practice, and when the last of it started
+func countStreak(sessions []interfaces.SessionStats, boundary DayBoundary) (current, longest int, last time.Time) {
+	days := make(map[time.Time]bool)
+	for _, s := range sessions {
+		if s.Mode != "daily" {
+			continue
+		}
+		days[boundary.Day(s.StartTime)] = true
+		if s.StartTime.After(last) {
+			last = s.StartTime
+		}
+	}
+
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	for i, day := range sorted {
+		if i > 0 && day.Sub(sorted[i-1]) == 24*time.Hour {
+			current++
+		} else {
+			current = 1
+		}
+		if current > longest {
+			longest = current
+		}
+	}
+	return current, longest, last
+}
+
+// GetDBPath returns the path of the database holding daily progress
+// Exported as variable for testing
+var GetDBPath = func() string {
+	return storage.DBPath()
 }
 
 // Contains checks if a string is in a slice
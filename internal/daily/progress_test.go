@@ -1,11 +1,14 @@
 package daily
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,6 +52,11 @@ func TestLoadDefaultProgress(t *testing.T) {
 }
 
 func TestUpdateStreak(t *testing.T) {
+	original := MaxFreezes
+	t.Cleanup(func() { MaxFreezes = original })
+	MaxFreezes = func() int { return DefaultMaxFreezes }
+	stubDayBoundary(t, DayBoundary{Location: time.UTC})
+
 	tests := []struct {
 		name          string
 		progress      ScaleProgress
@@ -118,6 +126,43 @@ func TestUpdateStreak(t *testing.T) {
 	}
 }
 
+func TestUpdateStreakFreezes(t *testing.T) {
+	original := MaxFreezes
+	t.Cleanup(func() { MaxFreezes = original })
+	MaxFreezes = func() int { return 2 }
+	stubDayBoundary(t, DayBoundary{Location: time.UTC})
+	today := time.Now().Truncate(24 * time.Hour)
+
+	// Missed days are covered while enough freezes are held
+	progress := ScaleProgress{Streak: 4, LongestStreak: 4, LastPracticed: today.AddDate(0, 0, -3), Freezes: 2}
+	assert.Equal(t, 2, UpdateStreak(&progress))
+	assert.Equal(t, 5, progress.Streak)
+	assert.Equal(t, 5, progress.LongestStreak)
+	assert.Zero(t, progress.Freezes)
+
+	// Too many missed days break the streak without spending any
+	progress = ScaleProgress{Streak: 4, LongestStreak: 4, LastPracticed: today.AddDate(0, 0, -4), Freezes: 2}
+	assert.Zero(t, UpdateStreak(&progress))
+	assert.Equal(t, 1, progress.Streak)
+	assert.Equal(t, 2, progress.Freezes)
+
+	// A week of streak earns one, up to the most that can be held
+	progress = ScaleProgress{Streak: 6, LongestStreak: 6, LastPracticed: today.AddDate(0, 0, -1), Freezes: 1}
+	assert.Zero(t, UpdateStreak(&progress))
+	assert.Equal(t, 7, progress.Streak)
+	assert.Equal(t, 2, progress.Freezes)
+	progress = ScaleProgress{Streak: 13, LongestStreak: 13, LastPracticed: today.AddDate(0, 0, -1), Freezes: 2}
+	UpdateStreak(&progress)
+	assert.Equal(t, 2, progress.Freezes)
+
+	// Turning freezes off drops those held
+	MaxFreezes = func() int { return 0 }
+	progress = ScaleProgress{Streak: 4, LongestStreak: 4, LastPracticed: today.AddDate(0, 0, -2), Freezes: 2}
+	assert.Zero(t, UpdateStreak(&progress))
+	assert.Equal(t, 1, progress.Streak)
+	assert.Zero(t, progress.Freezes)
+}
+
 func TestContains(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -151,4 +196,41 @@ func TestContains(t *testing.T) {
 			assert.Equal(t, tt.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRecountStreak(t *testing.T) {
+	tempDir, cleanup := setupTestDB(t)
+	defer cleanup()
+	stubDayBoundary(t, DayBoundary{Location: time.UTC})
+	store := storage.NewSQLiteStore(filepath.Join(tempDir, storage.DBFileName))
+	defer store.Close()
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, offset := range []int{0, 1, 2, 5, 6} {
+		at := day.AddDate(0, 0, offset)
+		require.NoError(t, store.SaveSession(ctx, interfaces.SessionStats{ProblemID: "two_sum", StartTime: at, EndTime: at, Mode: "daily"}))
+	}
+	// Practice outside the daily scales does not count
+	require.NoError(t, store.SaveSession(ctx, interfaces.SessionStats{ProblemID: "two_sum", StartTime: day.AddDate(0, 0, 7), Mode: "practice"}))
+	require.NoError(t, store.SaveStreak(ctx, storage.Streak{Name: storage.DailyStreak, Current: 9, Longest: 12, Position: 4, Completed: []string{"bfs"}}))
+
+	streak, err := RecountStreak(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, 2, streak.Current)
+	assert.Equal(t, 3, streak.Longest)
+	assert.True(t, streak.LastPracticed.Equal(day.AddDate(0, 0, 6)))
+	assert.Equal(t, 4, streak.Position, "the rotation is kept")
+
+	saved, err := store.LoadStreak(ctx, storage.DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved.Current)
+
+	// With no daily sessions left, there is no streak
+	_, err = store.PruneProgress(ctx, storage.Prune{})
+	require.NoError(t, err)
+	streak, err = RecountStreak(ctx, store)
+	require.NoError(t, err)
+	assert.Zero(t, streak.Current)
+	assert.Zero(t, streak.Longest)
+	assert.True(t, streak.LastPracticed.IsZero())
+}
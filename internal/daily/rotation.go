@@ -0,0 +1,136 @@
+// Weekly rotation of the patterns daily practice covers
+
+package daily
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// PlanWeeks is how many weeks of the rotation plan are shown, this week
+// included
+const PlanWeeks = 4
+
+// RotationWeek is the patterns daily practice covers in one week
+type RotationWeek struct {
+	Start    string   `json:"start"`    // The Sunday the week starts, YYYY-MM-DD
+	Patterns []string `json:"patterns"` // In the order they are practiced
+}
+
+// rotationEpoch is the Sunday the rotation counts weeks from, so a week's
+// patterns do not depend on when the rotation was set up
+var rotationEpoch = time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+
+// LoadRotation returns the configured rotation. A config that cannot be
+// loaded turns the rotation off.
+// Exported as variable for testing
+var LoadRotation = func() config.DailyRotation {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return config.DailyRotation{}
+	}
+	return cfg.DailyRotation
+}
+
+// rotationPatterns returns the known patterns a rotation goes through, in
+// order, or every scale's if it names none
+func rotationPatterns(rotation config.DailyRotation) []string {
+	var patterns []string
+	for _, pattern := range rotation.Patterns {
+		if GetScaleByPattern(pattern) != nil && !Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		for _, scale := range Scales {
+			patterns = append(patterns, scale.Pattern)
+		}
+	}
+	return patterns
+}
+
+// weekStart returns the Sunday starting the week of day, as a UTC date
+func weekStart(day time.Time) time.Time {
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return date.AddDate(0, 0, -int(date.Weekday()))
+}
+
+// WeekPatterns returns the patterns daily practice covers in the week of
+// day. Each week takes the next PatternsPerWeek patterns of the rotation,
+// wrapping around at its end. Without a rotation it is every pattern.
+func WeekPatterns(rotation config.DailyRotation, day time.Time) []string {
+	if rotation.PatternsPerWeek <= 0 {
+		return rotationPatterns(config.DailyRotation{})
+	}
+	patterns := rotationPatterns(rotation)
+	perWeek := rotation.PatternsPerWeek
+	if perWeek >= len(patterns) {
+		return patterns
+	}
+
+	week := int(weekStart(day).Sub(rotationEpoch).Hours()) / (24 * 7)
+	first := (week*perWeek%len(patterns) + len(patterns)) % len(patterns)
+	chosen := make([]string, perWeek)
+	for i := range chosen {
+		chosen[i] = patterns[(first+i)%len(patterns)]
+	}
+	return chosen
+}
+
+// RotationPlan returns the patterns of the week of now and of the weeks
+// after it, or nothing if the rotation is off
+func RotationPlan(rotation config.DailyRotation, now time.Time, weeks int) []RotationWeek {
+	if rotation.PatternsPerWeek <= 0 {
+		return nil
+	}
+	start := weekStart(now)
+	plan := make([]RotationWeek, weeks)
+	for i := range plan {
+		day := start.AddDate(0, 0, 7*i)
+		plan[i] = RotationWeek{
+			Start:    day.Format("2006-01-02"),
+			Patterns: WeekPatterns(rotation, day),
+		}
+	}
+	return plan
+}
+
+// Plan returns the configured rotation plan from this week on, or nothing
+// if the rotation is off
+func Plan() []RotationWeek {
+	return RotationPlan(LoadRotation(), LoadDayBoundary().Day(time.Now()), PlanWeeks)
+}
+
+// TodaysScales returns the scales daily practice covers today: this week's
+// if a rotation is configured, or else every scale
+func TodaysScales() []Scale {
+	var scales []Scale
+	for _, pattern := range WeekPatterns(LoadRotation(), LoadDayBoundary().Day(time.Now())) {
+		scales = append(scales, *GetScaleByPattern(pattern))
+	}
+	return scales
+}
+
+// DescribePlan describes a rotation plan one week per line, starting with
+// this week
+func DescribePlan(plan []RotationWeek) []string {
+	lines := make([]string, len(plan))
+	for i, week := range plan {
+		label := "Week of"
+		switch i {
+		case 0:
+			label = "This week, from"
+		case 1:
+			label = "Next week, from"
+		}
+		start := week.Start
+		if day, err := time.Parse("2006-01-02", week.Start); err == nil {
+			start = day.Format("Jan 2")
+		}
+		lines[i] = fmt.Sprintf("%s %s: %s", label, start, strings.Join(week.Patterns, ", "))
+	}
+	return lines
+}
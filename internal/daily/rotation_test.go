@@ -0,0 +1,73 @@
+package daily
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withRotation configures the rotation for the test
+func withRotation(t *testing.T, rotation config.DailyRotation) {
+	original := LoadRotation
+	LoadRotation = func() config.DailyRotation { return rotation }
+	t.Cleanup(func() { LoadRotation = original })
+}
+
+func TestWeekPatterns(t *testing.T) {
+	rotation := config.DailyRotation{
+		PatternsPerWeek: 2,
+		Patterns:        []string{"dfs", "bfs", "heap", "not-a-pattern", "dfs"},
+	}
+	sunday := rotationEpoch
+	saturday := sunday.AddDate(0, 0, 6)
+
+	assert.Equal(t, []string{"dfs", "bfs"}, WeekPatterns(rotation, sunday))
+	assert.Equal(t, []string{"dfs", "bfs"}, WeekPatterns(rotation, saturday), "the whole week has the same patterns")
+	assert.Equal(t, []string{"heap", "dfs"}, WeekPatterns(rotation, sunday.AddDate(0, 0, 7)), "wraps around")
+	assert.Equal(t, []string{"bfs", "heap"}, WeekPatterns(rotation, sunday.AddDate(0, 0, 14)))
+	assert.Equal(t, []string{"bfs", "heap"}, WeekPatterns(rotation, sunday.AddDate(0, 0, -7)), "weeks before the epoch")
+
+	// Every scale in order, without a rotation or with too few patterns
+	assert.Len(t, WeekPatterns(config.DailyRotation{Patterns: []string{"dfs"}}, sunday), len(Scales))
+	assert.Equal(t, []string{"dfs", "bfs", "heap"}, WeekPatterns(config.DailyRotation{PatternsPerWeek: 5, Patterns: rotation.Patterns}, sunday))
+	all := WeekPatterns(config.DailyRotation{PatternsPerWeek: 3}, sunday.AddDate(0, 0, 7))
+	assert.Equal(t, []string{"hash-map", "binary-search", "dfs"}, all)
+}
+
+func TestRotationPlan(t *testing.T) {
+	rotation := config.DailyRotation{PatternsPerWeek: 2, Patterns: []string{"dfs", "bfs", "heap"}}
+	wednesday := time.Date(2024, 1, 10, 21, 0, 0, 0, time.Local)
+
+	plan := RotationPlan(rotation, wednesday, 3)
+	require.Len(t, plan, 3)
+	assert.Equal(t, RotationWeek{Start: "2024-01-07", Patterns: []string{"dfs", "bfs"}}, plan[0])
+	assert.Equal(t, "2024-01-14", plan[1].Start)
+	assert.Equal(t, []string{
+		"This week, from Jan 7: dfs, bfs",
+		"Next week, from Jan 14: heap, dfs",
+		"Week of Jan 21: bfs, heap",
+	}, DescribePlan(plan))
+
+	assert.Nil(t, RotationPlan(config.DailyRotation{}, wednesday, 3), "no plan without a rotation")
+}
+
+func TestTodaysScales(t *testing.T) {
+	withRotation(t, config.DailyRotation{})
+	assert.Equal(t, Scales, TodaysScales())
+	assert.Nil(t, Plan())
+
+	withRotation(t, config.DailyRotation{PatternsPerWeek: 2})
+	scales := TodaysScales()
+	require.Len(t, scales, 2)
+	assert.Equal(t, WeekPatterns(config.DailyRotation{PatternsPerWeek: 2}, time.Now()), []string{scales[0].Pattern, scales[1].Pattern})
+	assert.Len(t, Plan(), PlanWeeks)
+
+	// Daily practice only offers this week's patterns
+	assert.Equal(t, scales[0].Pattern, GetNextScale(nil).Pattern)
+	assert.Equal(t, scales[1].Pattern, GetNextScale([]string{scales[0].Pattern}).Pattern)
+	assert.Nil(t, GetNextScale([]string{scales[0].Pattern, scales[1].Pattern}))
+	assert.Equal(t, 1, GetRemainingPatterns([]string{scales[0].Pattern}))
+}
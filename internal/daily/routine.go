@@ -0,0 +1,167 @@
+// User-defined daily practice routines
+
+package daily
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// DefaultRoutine names the routine of one problem of each of today's
+// scales, which daily practice follows unless another is active
+const DefaultRoutine = "scales"
+
+// difficulties are the difficulties a routine can weight
+var difficulties = []string{"easy", "medium", "hard"}
+
+// Routine is what a day of daily practice covers: its patterns, how many
+// problems of each, and how likely each difficulty is
+type Routine struct {
+	Name               string
+	Patterns           []string       // In practice order; empty for today's scales
+	ProblemsPerPattern int            // At least 1
+	DifficultyWeights  map[string]int // Relative chance of each difficulty; empty for any
+}
+
+// NewRoutine builds the routine a config sets out, checking its patterns
+// and weights
+func NewRoutine(name string, cfg config.DailyRoutine) (Routine, error) {
+	routine := Routine{Name: name, ProblemsPerPattern: max(cfg.ProblemsPerPattern, 1)}
+	for _, pattern := range cfg.Patterns {
+		if GetScaleByPattern(pattern) == nil {
+			return Routine{}, fmt.Errorf("routine %s: unknown pattern %q", name, pattern)
+		}
+		if !Contains(routine.Patterns, pattern) {
+			routine.Patterns = append(routine.Patterns, pattern)
+		}
+	}
+	for difficulty, weight := range cfg.DifficultyWeights {
+		if !Contains(difficulties, difficulty) {
+			return Routine{}, fmt.Errorf("routine %s: unknown difficulty %q; use %s", name, difficulty, strings.Join(difficulties, ", "))
+		}
+		if weight < 0 {
+			return Routine{}, fmt.Errorf("routine %s: the weight of %s cannot be negative", name, difficulty)
+		}
+		if weight > 0 {
+			if routine.DifficultyWeights == nil {
+				routine.DifficultyWeights = make(map[string]int)
+			}
+			routine.DifficultyWeights[difficulty] = weight
+		}
+	}
+	return routine, nil
+}
+
+// ActiveRoutine returns the routine a config makes active: the default one
+// unless it names one of its routines
+func ActiveRoutine(cfg config.UserConfig) (Routine, error) {
+	if cfg.ActiveRoutine == "" || cfg.ActiveRoutine == DefaultRoutine {
+		return Routine{Name: DefaultRoutine, ProblemsPerPattern: 1}, nil
+	}
+	routine, ok := cfg.Routines[cfg.ActiveRoutine]
+	if !ok {
+		return Routine{}, fmt.Errorf("no routine is named %s", cfg.ActiveRoutine)
+	}
+	return NewRoutine(cfg.ActiveRoutine, routine)
+}
+
+// LoadRoutine returns the active routine. A config that cannot be loaded,
+// or whose active routine is missing or invalid, uses the default one.
+// Exported as variable for testing
+var LoadRoutine = func() Routine {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return Routine{Name: DefaultRoutine, ProblemsPerPattern: 1}
+	}
+	routine, err := ActiveRoutine(cfg)
+	if err != nil {
+		return Routine{Name: DefaultRoutine, ProblemsPerPattern: 1}
+	}
+	return routine
+}
+
+// Scales returns the scales the routine practices today: its own patterns,
+// or else today's scales
+func (r Routine) Scales() []Scale {
+	if len(r.Patterns) == 0 {
+		return TodaysScales()
+	}
+	scales := make([]Scale, 0, len(r.Patterns))
+	for _, pattern := range r.Patterns {
+		scales = append(scales, *GetScaleByPattern(pattern))
+	}
+	return scales
+}
+
+// Describe summarizes the routine, such as "interview: 2 problems each of
+// 3 patterns, mostly medium"
+func (r Routine) Describe() string {
+	patterns := "today's scales"
+	if len(r.Patterns) > 0 {
+		patterns = fmt.Sprintf("%d patterns", len(r.Patterns))
+	}
+	desc := fmt.Sprintf("%s: 1 problem of each of %s", r.Name, patterns)
+	if r.ProblemsPerPattern > 1 {
+		desc = fmt.Sprintf("%s: %d problems each of %s", r.Name, r.ProblemsPerPattern, patterns)
+	}
+	if favored := r.favoredDifficulty(); favored != "" {
+		desc += ", mostly " + favored
+	}
+	return desc
+}
+
+// favoredDifficulty returns the difficulty weighted above the others, if
+// there is one
+func (r Routine) favoredDifficulty() string {
+	favored, best, tied := "", 0, false
+	for _, difficulty := range difficulties {
+		switch weight := r.DifficultyWeights[difficulty]; {
+		case weight > best:
+			favored, best, tied = difficulty, weight, false
+		case weight == best && weight > 0:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+	return favored
+}
+
+// pickDifficulty chooses a difficulty by the routine's weights, or none
+// when it weights none
+func (r Routine) pickDifficulty() string {
+	total := 0
+	for _, weight := range r.DifficultyWeights {
+		total += weight
+	}
+	if total == 0 {
+		return ""
+	}
+	return weightedDifficulty(r.DifficultyWeights, rand.Intn(total))
+}
+
+// weightedDifficulty returns the difficulty that n, from 0 up to the sum of
+// the weights, falls on
+func weightedDifficulty(weights map[string]int, n int) string {
+	for _, difficulty := range difficulties {
+		if n < weights[difficulty] {
+			return difficulty
+		}
+		n -= weights[difficulty]
+	}
+	return ""
+}
+
+// SlotKey returns the key of the nth problem, from 1, of a pattern in a
+// daily session: the pattern itself for the first, so sessions from before
+// routines read the same, and "pattern#n" for the others
+func SlotKey(pattern string, n int) string {
+	if n <= 1 {
+		return pattern
+	}
+	return fmt.Sprintf("%s#%d", pattern, n)
+}
@@ -0,0 +1,115 @@
+package daily
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRoutine has daily practice follow routine for the rest of a test
+func stubRoutine(t *testing.T, routine Routine) {
+	t.Helper()
+	original := LoadRoutine
+	t.Cleanup(func() { LoadRoutine = original })
+	LoadRoutine = func() Routine { return routine }
+}
+
+func TestNewRoutine(t *testing.T) {
+	routine, err := NewRoutine("interview", config.DailyRoutine{
+		Patterns:           []string{"dfs", "heap", "dfs"},
+		ProblemsPerPattern: 2,
+		DifficultyWeights:  map[string]int{"medium": 3, "hard": 1, "easy": 0},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dfs", "heap"}, routine.Patterns, "repeated patterns are practiced once")
+	assert.Equal(t, map[string]int{"medium": 3, "hard": 1}, routine.DifficultyWeights)
+	assert.Equal(t, "interview: 2 problems each of 2 patterns, mostly medium", routine.Describe())
+	assert.Equal(t, []string{"dfs", "heap"}, []string{routine.Scales()[0].Pattern, routine.Scales()[1].Pattern})
+
+	routine, err = NewRoutine("light", config.DailyRoutine{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, routine.ProblemsPerPattern)
+	assert.Equal(t, "light: 1 problem of each of today's scales", routine.Describe())
+
+	_, err = NewRoutine("typo", config.DailyRoutine{Patterns: []string{"dsf"}})
+	assert.EqualError(t, err, `routine typo: unknown pattern "dsf"`)
+	_, err = NewRoutine("typo", config.DailyRoutine{DifficultyWeights: map[string]int{"brutal": 1}})
+	assert.EqualError(t, err, `routine typo: unknown difficulty "brutal"; use easy, medium, hard`)
+	_, err = NewRoutine("typo", config.DailyRoutine{DifficultyWeights: map[string]int{"hard": -1}})
+	assert.EqualError(t, err, "routine typo: the weight of hard cannot be negative")
+}
+
+func TestActiveRoutine(t *testing.T) {
+	cfg := config.UserConfig{Routines: map[string]config.DailyRoutine{"graphs": {Patterns: []string{"bfs", "dfs"}}}}
+	routine, err := ActiveRoutine(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRoutine, routine.Name)
+
+	cfg.ActiveRoutine = "graphs"
+	routine, err = ActiveRoutine(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bfs", "dfs"}, routine.Patterns)
+
+	cfg.ActiveRoutine = "trees"
+	_, err = ActiveRoutine(cfg)
+	assert.EqualError(t, err, "no routine is named trees")
+}
+
+func TestWeightedDifficulty(t *testing.T) {
+	weights := map[string]int{"easy": 1, "medium": 3, "hard": 1}
+	assert.Equal(t, "easy", weightedDifficulty(weights, 0))
+	assert.Equal(t, "medium", weightedDifficulty(weights, 1))
+	assert.Equal(t, "medium", weightedDifficulty(weights, 3))
+	assert.Equal(t, "hard", weightedDifficulty(weights, 4))
+	assert.Empty(t, Routine{}.pickDifficulty(), "no weights choose no difficulty")
+	assert.Equal(t, "hard", Routine{DifficultyWeights: map[string]int{"hard": 2}}.pickDifficulty())
+}
+
+func TestSessionFromRoutine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stubRoutine(t, Routine{Name: "graphs", Patterns: []string{"bfs", "dfs"}, ProblemsPerPattern: 2, DifficultyWeights: map[string]int{"medium": 1}})
+
+	session, err := GetOrCreateSession()
+	require.NoError(t, err)
+	assert.Equal(t, "graphs", session.Routine)
+	assert.Equal(t, []string{"bfs", "bfs#2", "dfs", "dfs#2"}, session.Keys())
+	assert.Equal(t, "bfs", session.Problems["bfs#2"].Pattern)
+	assert.Equal(t, "medium", session.Problems["dfs"].Difficulty)
+	assert.Equal(t, "bfs", session.GetNextPendingPattern())
+	assert.Equal(t, 4, session.GetTotalProblems())
+
+	// A problem chosen for one of a pattern's problems is not chosen again
+	original := problem.ListAll
+	t.Cleanup(func() { problem.ListAll = original })
+	problem.ListAll = func() ([]problem.Problem, error) {
+		return []problem.Problem{
+			{ID: "rotting_oranges", Difficulty: "medium", Patterns: []string{"bfs"}},
+			{ID: "word_ladder", Difficulty: "hard", Patterns: []string{"bfs"}},
+		}, nil
+	}
+	prob, err := session.PickProblem("bfs")
+	require.NoError(t, err)
+	assert.Equal(t, "rotting_oranges", prob.ID, "the chosen difficulty comes first")
+	require.NoError(t, session.StartProblem("bfs", prob.ID))
+	prob, err = session.PickProblem("bfs#2")
+	require.NoError(t, err)
+	assert.Equal(t, "word_ladder", prob.ID)
+
+	// The day's session keeps its problems when the routine changes
+	stubRoutine(t, Routine{Name: DefaultRoutine, ProblemsPerPattern: 1})
+	session, err = GetOrCreateSession()
+	require.NoError(t, err)
+	assert.Equal(t, "graphs", session.Routine)
+	assert.Equal(t, 4, session.GetTotalProblems())
+}
+
+func TestKeysOfEarlierSessions(t *testing.T) {
+	session := DailySession{Problems: map[string]DailyProblem{
+		"heap": {Pattern: "heap"},
+		"dfs":  {Pattern: "dfs"},
+	}}
+	assert.Equal(t, []string{"dfs", "heap"}, session.Keys(), "sessions without an order are in scale order")
+}
@@ -2,11 +2,13 @@ package daily
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/problem"
 	"go.etcd.io/bbolt"
 )
 
@@ -28,22 +30,32 @@ type DailySession struct {
 	StartTime time.Time               `json:"start_time"`
 	EndTime   time.Time               `json:"end_time,omitempty"`
 	Completed bool                    `json:"completed"`
+	UpdatedAt time.Time               `json:"updated_at,omitempty"` // Last saved, for progress sync
+	Routine   string                  `json:"routine,omitempty"`    // Routine the session was built from
+	Order     []string                `json:"order,omitempty"`      // Problem keys in practice order; see SlotKey
 }
 
-// CreateNewSession creates a new daily session
+// CreateNewSession creates a new daily session from the active routine
 func CreateNewSession() (*DailySession, error) {
-	today := time.Now().Format("2006-01-02")
+	today := Today()
+	routine := LoadRoutine()
 	
-	// Initialize with all patterns as pending
+	// Initialize with the routine's problems as pending
 	problems := make(map[string]DailyProblem)
-	
-	for _, scale := range Scales {
-		problems[scale.Pattern] = DailyProblem{
-			Pattern:    scale.Pattern,
-			ProblemID:  "", // Will be populated when we select a problem
-			State:      StatePending,
-			StartedAt:  time.Time{},
-			Attempts:   0,
+	var order []string
+	
+	for _, scale := range routine.Scales() {
+		for n := 1; n <= routine.ProblemsPerPattern; n++ {
+			key := SlotKey(scale.Pattern, n)
+			problems[key] = DailyProblem{
+				Pattern:    scale.Pattern,
+				ProblemID:  "", // Will be populated when we select a problem
+				State:      StatePending,
+				StartedAt:  time.Time{},
+				Attempts:   0,
+				Difficulty: routine.pickDifficulty(),
+			}
+			order = append(order, key)
 		}
 	}
 	
@@ -52,6 +64,8 @@ func CreateNewSession() (*DailySession, error) {
 		Problems:  problems,
 		StartTime: time.Now(),
 		Completed: false,
+		Routine:   routine.Name,
+		Order:     order,
 	}
 	
 	// Save the session
@@ -118,8 +132,25 @@ func LoadSession() (*DailySession, error) {
 
 // SaveSession saves the daily session to the database
 func SaveSession(session *DailySession) error {
+	session.UpdatedAt = time.Now()
+	return putSession(session)
+}
+
+// RestoreSession stores a daily session as it is, keeping its UpdatedAt.
+// It is used to bring in a session synced from another machine.
+func RestoreSession(session *DailySession) error {
+	return putSession(session)
+}
+
+// putSession writes the active session
+func putSession(session *DailySession) error {
 	dbPath := GetSessionDBPath()
 	
+	// Create dirs if needed
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return fmt.Errorf("error creating directories: %w", err)
+	}
+	
 	// Open database file
 	db, err := bbolt.Open(dbPath, 0600, nil)
 	if err != nil {
@@ -135,8 +166,11 @@ func SaveSession(session *DailySession) error {
 	
 	// Save to database
 	err = db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(SessionBucketName))
-		err := bucket.Put([]byte(ActiveSessionKey), data)
+		bucket, err := tx.CreateBucketIfNotExists([]byte(SessionBucketName))
+		if err != nil {
+			return fmt.Errorf("error creating bucket: %w", err)
+		}
+		err = bucket.Put([]byte(ActiveSessionKey), data)
 		if err != nil {
 			return fmt.Errorf("error saving session data: %w", err)
 		}
@@ -150,13 +184,52 @@ func SaveSession(session *DailySession) error {
 	return nil
 }
 
+// CheckSessionDB reports whether the daily session database at path can
+// be read. A database another process holds open is assumed to be fine.
+func CheckSessionDB(path string) (err error) {
+	// Damaged pages can make bbolt panic rather than fail
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("damaged database: %v", r)
+		}
+	}()
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true, Timeout: 200 * time.Millisecond})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(SessionBucketName))
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get([]byte(ActiveSessionKey))
+		if data == nil {
+			return nil
+		}
+		var session DailySession
+		if err := json.Unmarshal(data, &session); err != nil {
+			return fmt.Errorf("unreadable session: %v", err)
+		}
+		if _, err := time.Parse(DateLayout, session.Date); err != nil {
+			return fmt.Errorf("session has an invalid date %q", session.Date)
+		}
+		return nil
+	})
+}
+
 // GetOrCreateSession loads the active session or creates a new one if needed
 func GetOrCreateSession() (*DailySession, error) {
 	// Try to load existing session
 	session, err := LoadSession()
 	if err == nil {
 		// Check if this session is for today
-		today := time.Now().Format("2006-01-02")
+		today := Today()
 		if session.Date == today {
 			return session, nil
 		}
@@ -249,7 +322,9 @@ func (s *DailySession) SkipProblem(pattern string) error {
 	return SaveSession(s)
 }
 
-// GetNextPendingPattern returns the next pattern that is pending
+// GetNextPendingPattern returns the key of the problem in progress, or else
+// of the next pending one. Keys are patterns, with "#2" and so on added for
+// further problems of a pattern; the problem's Pattern field is its pattern.
 func (s *DailySession) GetNextPendingPattern() string {
 	// Check if any pattern is in progress
 	for pattern, prob := range s.Problems {
@@ -259,16 +334,47 @@ func (s *DailySession) GetNextPendingPattern() string {
 	}
 	
 	// If not, find the first pending pattern
-	for _, scale := range Scales {
-		pattern := scale.Pattern
-		if prob, ok := s.Problems[pattern]; ok && prob.State == StatePending {
-			return pattern
+	for _, key := range s.Keys() {
+		if s.Problems[key].State == StatePending {
+			return key
 		}
 	}
 	
 	return ""
 }
 
+// Keys returns the keys of the session's problems in practice order.
+// Sessions from before routines are in scale order.
+func (s *DailySession) Keys() []string {
+	if len(s.Order) > 0 {
+		return s.Order
+	}
+	var keys []string
+	for _, scale := range Scales {
+		if _, ok := s.Problems[scale.Pattern]; ok {
+			keys = append(keys, scale.Pattern)
+		}
+	}
+	return keys
+}
+
+// PickProblem chooses a problem for the session's problem under key: one
+// of its pattern, of the difficulty the routine chose if there is one, and
+// not already chosen for another of the session's problems
+func (s *DailySession) PickProblem(key string) (*problem.Problem, error) {
+	prob, ok := s.Problems[key]
+	if !ok {
+		return nil, fmt.Errorf("pattern not found: %s", key)
+	}
+	var chosen []string
+	for other, p := range s.Problems {
+		if other != key && p.ProblemID != "" {
+			chosen = append(chosen, p.ProblemID)
+		}
+	}
+	return problem.GetRandomProblemForRoutine(prob.Pattern, prob.Difficulty, chosen)
+}
+
 // GetCompletedCount returns the number of completed problems
 func (s *DailySession) GetCompletedCount() int {
 	count := 0
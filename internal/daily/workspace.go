@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/template"
 )
 
 // ProblemState represents the current state of a problem in daily practice
@@ -36,6 +38,7 @@ type DailyProblem struct {
 	StartedAt  time.Time    `json:"started_at"`
 	CompletedAt time.Time   `json:"completed_at,omitempty"`
 	Attempts   int          `json:"attempts"`
+	Difficulty string       `json:"difficulty,omitempty"` // Difficulty the routine chose for this problem; empty for any
 }
 
 // GetDailyWorkspacePath returns the path to the daily workspace directory
@@ -52,8 +55,7 @@ func GetDailyWorkspacePath() string {
 
 // GetTodayWorkspacePath returns the path for today's practice directory
 func GetTodayWorkspacePath() string {
-	todayStr := time.Now().Format("2006-01-02")
-	return filepath.Join(GetDailyWorkspacePath(), todayStr)
+	return filepath.Join(GetDailyWorkspacePath(), Today())
 }
 
 // CreateDailyWorkspace creates the daily practice workspace directory
@@ -71,11 +73,11 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 	var blockEnd string
 	
 	switch language {
-	case "python":
+	case "python", "pseudo":
 		lineComment = "# "
 		blockStart = "'''\n"
 		blockEnd = "'''\n"
-	case "javascript":
+	case "javascript", "typescript":
 		lineComment = "// "
 		blockStart = "/**\n"
 		blockEnd = " */\n"
@@ -83,6 +85,14 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 		lineComment = "// "
 		blockStart = "/*\n"
 		blockEnd = " */\n"
+	case "java":
+		lineComment = "// "
+		blockStart = "/**\n"
+		blockEnd = " */\n"
+	case "cpp":
+		lineComment = "// "
+		blockStart = "/*\n"
+		blockEnd = " */\n"
 	default:
 		// Default to C-style comments
 		lineComment = "// "
@@ -149,6 +159,10 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 	
 	// Add starter code
 	starterCode, ok := prob.StarterCode[language]
+	if !ok && language == "pseudo" {
+		// Another language's code would give the approach away
+		starterCode, ok = template.PseudoSkeleton(prob.Signature), true
+	}
 	if !ok {
 		// Fallback to any available language
 		for _, code := range prob.StarterCode {
@@ -188,15 +202,15 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 			
 			builder.WriteString(fmt.Sprintf("\texpected := %s\n", testCase.Expected))
 			builder.WriteString("\tif fmt.Sprint(result) == fmt.Sprint(expected) {\n")
-			builder.WriteString("\t\tfmt.Println(\"✅ PASSED\")\n")
+			builder.WriteString(fmt.Sprintf("\t\tfmt.Println(\"%s PASSED\")\n", symbols.Pass))
 			builder.WriteString("\t} else {\n")
-			builder.WriteString("\t\tfmt.Printf(\"❌ FAILED\\nExpected: %v\\nGot: %v\\n\", expected, result)\n")
+			builder.WriteString(fmt.Sprintf("\t\tfmt.Printf(\"%s FAILED\\nExpected: %%v\\nGot: %%v\\n\", expected, result)\n", symbols.Fail))
 			builder.WriteString("\t\tallPassed = false\n")
 			builder.WriteString("\t}\n\n")
 		}
 		
 		builder.WriteString("\tif allPassed {\n")
-		builder.WriteString("\t\tfmt.Println(\"🎉 All tests passed!\")\n")
+		builder.WriteString(fmt.Sprintf("\t\tfmt.Println(\"%s All tests passed!\")\n", symbols.Celebrate))
 		builder.WriteString("\t} else {\n")
 		builder.WriteString("\t\tos.Exit(1)\n")
 		builder.WriteString("\t}\n")
@@ -229,14 +243,14 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 			
 			builder.WriteString(fmt.Sprintf("    expected = %s\n", testCase.Expected))
 			builder.WriteString("    if str(result) == str(expected):\n")
-			builder.WriteString("        print(\"✅ PASSED\")\n")
+			builder.WriteString(fmt.Sprintf("        print(\"%s PASSED\")\n", symbols.Pass))
 			builder.WriteString("    else:\n")
-			builder.WriteString("        print(f\"❌ FAILED\\nExpected: {expected}\\nGot: {result}\")\n")
+			builder.WriteString(fmt.Sprintf("        print(f\"%s FAILED\\nExpected: {expected}\\nGot: {result}\")\n", symbols.Fail))
 			builder.WriteString("        all_passed = False\n\n")
 		}
 		
 		builder.WriteString("    if all_passed:\n")
-		builder.WriteString("        print(\"🎉 All tests passed!\")\n")
+		builder.WriteString(fmt.Sprintf("        print(\"%s All tests passed!\")\n", symbols.Celebrate))
 		builder.WriteString("    else:\n")
 		builder.WriteString("        exit(1)\n")
 		
@@ -261,20 +275,35 @@ func FormatProblemAsComment(prob *problem.Problem, language string) string {
 			
 			builder.WriteString(fmt.Sprintf("    const expected = %s;\n", testCase.Expected))
 			builder.WriteString("    if (String(result) === String(expected)) {\n")
-			builder.WriteString("        console.log(\"✅ PASSED\");\n")
+			builder.WriteString(fmt.Sprintf("        console.log(\"%s PASSED\");\n", symbols.Pass))
 			builder.WriteString("    } else {\n")
-			builder.WriteString("        console.log(`❌ FAILED\\nExpected: ${expected}\\nGot: ${result}`);\n")
+			builder.WriteString(fmt.Sprintf("        console.log(`%s FAILED\\nExpected: ${expected}\\nGot: ${result}`);\n", symbols.Fail))
 			builder.WriteString("        allPassed = false;\n")
 			builder.WriteString("    }\n\n")
 		}
 		
 		builder.WriteString("    if (allPassed) {\n")
-		builder.WriteString("        console.log(\"🎉 All tests passed!\");\n")
+		builder.WriteString(fmt.Sprintf("        console.log(\"%s All tests passed!\");\n", symbols.Celebrate))
 		builder.WriteString("    } else {\n")
 		builder.WriteString("        process.exit(1);\n")
 		builder.WriteString("    }\n")
 		builder.WriteString("}\n\n")
 		builder.WriteString("// Run tests\nrunTests();\n")
+
+	case "pseudo":
+		// Pseudo-code is graded by the AI rather than run against the tests
+		builder.WriteString("\n# Run 'algo-scales daily test' to have the AI grade your pseudo-code.\n")
+
+	case "java", "cpp", "typescript":
+		// Rather than a main function the file is compiled together with a
+		// generated harness that runs each test case below as its own test;
+		// for Java, javac also wants a file named after its public class, and
+		// TypeScript is type-checked with tsc first
+		builder.WriteString("\n// Run 'algo-scales daily test' to compile and test your solution.\n")
+		builder.WriteString("// Each test case is run as its own test:\n")
+		for i, testCase := range prob.TestCases {
+			builder.WriteString(fmt.Sprintf("//   Test %d: %s -> %s\n", i+1, testCase.Input, testCase.Expected))
+		}
 	}
 	
 	return builder.String()
@@ -313,6 +342,14 @@ func GetFileExtension(language string) string {
 		return "py"
 	case "javascript":
 		return "js"
+	case "rust":
+		return "rs"
+	case "java":
+		return "java"
+	case "cpp":
+		return "cpp"
+	case "typescript":
+		return "ts"
 	default:
 		return "txt"
 	}
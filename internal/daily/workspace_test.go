@@ -0,0 +1,41 @@
+package daily
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatProblemAsCommentCompiledLanguages(t *testing.T) {
+	prob := &problem.Problem{
+		ID:       "two_sum",
+		Title:    "Two Sum",
+		Patterns: []string{"hash-map"},
+		StarterCode: map[string]string{
+			"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        return {};\n    }\n};",
+			"java":       "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        return new int[0];\n    }\n}",
+			"typescript": "function twoSum(nums: number[], target: number): number[] {\n    return [];\n}",
+		},
+		TestCases: []problem.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		},
+	}
+
+	for _, language := range []string{"cpp", "java", "typescript"} {
+		content := FormatProblemAsComment(prob, language)
+		assert.True(t, strings.HasPrefix(content, "/*"), language)
+		assert.NotContains(t, content, "runTests()", language)
+		assert.Contains(t, content, prob.StarterCode[language], language)
+
+		// The harness is generated at test time, so no main is written
+		assert.Contains(t, content, "// Run 'algo-scales daily test' to compile and test your solution.", language)
+		assert.Contains(t, content, "//   Test 1: [2,7,11,15], 9 -> [0,1]", language)
+		assert.NotContains(t, content, "main(", language)
+	}
+
+	assert.Equal(t, "cpp", GetFileExtension("cpp"))
+	assert.Equal(t, "java", GetFileExtension("java"))
+	assert.Equal(t, "ts", GetFileExtension("typescript"))
+}
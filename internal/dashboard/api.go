@@ -0,0 +1,278 @@
+// The read-only JSON API the dashboard page is drawn from
+
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/solutions"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// defaultSessionLimit is how many sessions /api/sessions returns unless
+// asked for a limit
+const defaultSessionLimit = 50
+
+// calendarResponse is the practice calendar with the shade of each day
+type calendarResponse struct {
+	stats.Calendar
+	Levels []int `json:"levels"` // The shade of each day, from 0 to stats.CalendarLevels-1
+}
+
+// sessionEntry is one session in /api/sessions
+type sessionEntry struct {
+	ProblemID    string    `json:"problem_id"`
+	StartTime    time.Time `json:"start_time"`
+	Duration     string    `json:"duration"`
+	Seconds      int       `json:"seconds"`
+	Solved       bool      `json:"solved"`
+	Mode         string    `json:"mode,omitempty"`
+	HintsUsed    bool      `json:"hints_used"`
+	SolutionUsed bool      `json:"solution_used"`
+	Patterns     []string  `json:"patterns"`
+	Difficulty   string    `json:"difficulty,omitempty"`
+	Language     string    `json:"language,omitempty"`
+	Context      string    `json:"context,omitempty"`
+}
+
+// sessionsResponse is a page of the session history, newest first
+type sessionsResponse struct {
+	Total    int            `json:"total"`
+	Offset   int            `json:"offset"`
+	Sessions []sessionEntry `json:"sessions"`
+}
+
+// patternMastery is how well one pattern is known: its record, predicted
+// recall and recent trend
+type patternMastery struct {
+	interfaces.PatternStats
+	Retention float64    `json:"retention"` // Predicted recall now, 0-100
+	DecaysAt  *time.Time `json:"decays_at,omitempty"`
+	Direction string     `json:"direction,omitempty"`
+	Weak      bool       `json:"weak"`
+	Reasons   []string   `json:"reasons,omitempty"`
+}
+
+// archivedProblem is a problem with solutions in the archive
+type archivedProblem struct {
+	ProblemID string    `json:"problem_id"`
+	Solutions int       `json:"solutions"`
+	Passed    bool      `json:"passed"` // Some solution passed every test
+	Last      time.Time `json:"last"`
+}
+
+// archivedSolution is one solution in a problem's history
+type archivedSolution struct {
+	Number      int       `json:"number"`
+	Language    string    `json:"language"`
+	Time        time.Time `json:"time"`
+	Passed      bool      `json:"passed"`
+	TestsPassed int       `json:"tests_passed"`
+	TestsTotal  int       `json:"tests_total"`
+	Lines       int       `json:"lines"`
+	Code        string    `json:"code"`
+}
+
+// summary answers the overall statistics
+func (s *Server) summary(w http.ResponseWriter, r *http.Request) {
+	summary, err := s.stats.GetSummary(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, summary)
+}
+
+// calendar answers the practice calendar of the past year
+func (s *Server) calendar(w http.ResponseWriter, r *http.Request) {
+	cal, err := s.stats.GetCalendar(r.Context(), s.now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := calendarResponse{Calendar: cal, Levels: make([]int, len(cal.Days))}
+	for i, day := range cal.Days {
+		resp.Levels[i] = cal.Level(day)
+	}
+	writeJSON(w, resp)
+}
+
+// sessions answers a page of the session history, newest first. The page
+// is chosen with the limit and offset query parameters.
+func (s *Server) sessions(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultSessionLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	all, err := s.repo.LoadAllSessions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].StartTime.After(all[j].StartTime) })
+
+	resp := sessionsResponse{Total: len(all), Offset: offset, Sessions: []sessionEntry{}}
+	for i := offset; i < len(all) && len(resp.Sessions) < limit; i++ {
+		resp.Sessions = append(resp.Sessions, newSessionEntry(all[i]))
+	}
+	writeJSON(w, resp)
+}
+
+// patterns answers the mastery of each practiced pattern, most practiced
+// first
+func (s *Server) patterns(w http.ResponseWriter, r *http.Request) {
+	byPattern, err := s.stats.GetByPattern(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	now := s.now()
+	retention, err := s.stats.GetRetention(r.Context(), now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	trends, err := s.stats.GetPatternTrends(r.Context(), nil, now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	mastery := make(map[string]*patternMastery, len(byPattern))
+	list := make([]*patternMastery, 0, len(byPattern))
+	for _, ps := range byPattern {
+		m := &patternMastery{PatternStats: ps}
+		mastery[ps.Pattern] = m
+		list = append(list, m)
+	}
+	for _, f := range retention {
+		if m, ok := mastery[f.Pattern]; ok {
+			decaysAt := f.DecaysAt
+			m.Retention, m.DecaysAt = f.Retention*100, &decaysAt
+		}
+	}
+	for _, t := range trends.Patterns {
+		if m, ok := mastery[t.Pattern]; ok {
+			m.Direction, m.Weak, m.Reasons = t.Direction, t.Weak, t.Reasons
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Attempted != list[j].Attempted {
+			return list[i].Attempted > list[j].Attempted
+		}
+		return list[i].Pattern < list[j].Pattern
+	})
+	writeJSON(w, map[string]interface{}{"patterns": list})
+}
+
+// solutionList answers the problems with solutions in the archive, most
+// recently tried first
+func (s *Server) solutionList(w http.ResponseWriter, r *http.Request) {
+	attempts, err := s.repo.LoadAttempts(r.Context(), "")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	byProblem := make(map[string]*archivedProblem)
+	problems := []*archivedProblem{}
+	for _, a := range attempts {
+		if !a.Submitted || a.Code == "" {
+			continue
+		}
+		p, ok := byProblem[a.ProblemID]
+		if !ok {
+			p = &archivedProblem{ProblemID: a.ProblemID}
+			byProblem[a.ProblemID] = p
+			problems = append(problems, p)
+		}
+		p.Solutions++
+		p.Passed = p.Passed || a.Passed
+		if a.Time.After(p.Last) {
+			p.Last = a.Time
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Last.After(problems[j].Last) })
+	writeJSON(w, map[string]interface{}{"problems": problems})
+}
+
+// solutionHistory answers the solutions submitted to a problem, oldest
+// first, with their code
+func (s *Server) solutionHistory(w http.ResponseWriter, r *http.Request) {
+	problemID := r.PathValue("problem")
+	attempts, err := s.repo.LoadAttempts(r.Context(), problemID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	history := solutions.History(attempts, problemID, false)
+	if len(history) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no solutions to %s in the archive", problemID))
+		return
+	}
+
+	entries := make([]archivedSolution, len(history))
+	for i, sol := range history {
+		entries[i] = archivedSolution{
+			Number:      sol.Number,
+			Language:    sol.Language,
+			Time:        sol.Time,
+			Passed:      sol.Passed,
+			TestsPassed: sol.TestsPassed(),
+			TestsTotal:  len(sol.Results),
+			Lines:       sol.Lines(),
+			Code:        sol.Code,
+		}
+	}
+	writeJSON(w, map[string]interface{}{"problem_id": problemID, "solutions": entries})
+}
+
+// newSessionEntry converts a session for /api/sessions
+func newSessionEntry(st interfaces.SessionStats) sessionEntry {
+	entry := sessionEntry{
+		ProblemID:    st.ProblemID,
+		StartTime:    st.StartTime,
+		Duration:     st.Duration.Round(time.Second).String(),
+		Seconds:      int(st.Duration.Seconds()),
+		Solved:       st.Solved,
+		Mode:         st.Mode,
+		HintsUsed:    st.HintsUsed,
+		SolutionUsed: st.SolutionUsed,
+		Patterns:     st.Patterns,
+		Difficulty:   st.Difficulty,
+		Context:      st.Context,
+	}
+	if entry.Patterns == nil {
+		entry.Patterns = []string{}
+	}
+	if st.Environment != nil {
+		entry.Language = st.Environment.Language
+	}
+	return entry
+}
+
+// queryInt reads a non-negative integer query parameter, or def when it is
+// not given
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, not %q", name, value)
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+// Package dashboard serves the local web dashboard: a page, embedded in the
+// binary, that charts the practice calendar, session history, pattern
+// mastery and solution archive, and the read-only JSON API it draws them
+// from. Both are answered from the progress database on every request, so
+// the page shows sessions recorded while it is open once it is reloaded.
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// DefaultPort is the port the dashboard is served on unless told otherwise
+const DefaultPort = 7878
+
+// shutdownTimeout is how long requests in progress get to finish once the
+// server is stopped
+const shutdownTimeout = 5 * time.Second
+
+// static holds the page and its script and styles
+//
+//go:embed static
+var static embed.FS
+
+// Server answers the dashboard's page and API from a progress database
+type Server struct {
+	repo  storage.Repository
+	stats *stats.Service
+	now   func() time.Time
+}
+
+// NewServer creates a dashboard over repo, which it only reads
+func NewServer(repo storage.Repository) *Server {
+	return &Server{repo: repo, stats: stats.NewService().WithStorage(repo), now: time.Now}
+}
+
+// Handler routes the API under /api/ and serves the page for everything
+// else. Only GET and HEAD are answered.
+func (s *Server) Handler() http.Handler {
+	assets, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/summary", s.summary)
+	mux.HandleFunc("GET /api/calendar", s.calendar)
+	mux.HandleFunc("GET /api/sessions", s.sessions)
+	mux.HandleFunc("GET /api/patterns", s.patterns)
+	mux.HandleFunc("GET /api/solutions", s.solutionList)
+	mux.HandleFunc("GET /api/solutions/{problem}", s.solutionHistory)
+	mux.HandleFunc("GET /api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint: %s", r.URL.Path))
+	})
+	mux.Handle("GET /", http.FileServerFS(assets))
+	return mux
+}
+
+// ListenAndServe serves the dashboard on addr until ctx is cancelled, then
+// waits for the requests in progress. ready, if set, is called with the
+// address being listened on once connections are accepted.
+func (s *Server) ListenAndServe(ctx context.Context, addr string, ready func(addr string)) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if ready != nil {
+		ready(listener.Addr().String())
+	}
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// writeJSON answers with v as JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with an error as JSON
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,172 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves a dashboard over a database with two sessions and
+// two submitted solutions to two_sum
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ctx := context.Background()
+	repo := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	t.Cleanup(func() { repo.Close() })
+
+	now := time.Now()
+	for i, solved := range []bool{false, true} {
+		start := now.Add(time.Duration(i-2) * time.Hour)
+		require.NoError(t, repo.SaveSession(ctx, interfaces.SessionStats{
+			ProblemID:   "two_sum",
+			StartTime:   start,
+			EndTime:     start.Add(10 * time.Minute),
+			Duration:    10 * time.Minute,
+			Solved:      solved,
+			Patterns:    []string{"hash-map"},
+			Difficulty:  "easy",
+			Environment: &interfaces.EnvironmentSnapshot{Language: "go"},
+		}))
+		require.NoError(t, repo.SaveAttempt(ctx, storage.Attempt{
+			ProblemID: "two_sum",
+			Language:  "go",
+			Time:      start.Add(5 * time.Minute),
+			Passed:    solved,
+			Code:      "func twoSum() {}\n",
+			Submitted: true,
+			Results:   []storage.TestResult{{Number: 1, Passed: solved}},
+		}))
+	}
+
+	srv := httptest.NewServer(NewServer(repo).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// getJSON fetches path and decodes its JSON body into v
+func getJSON(t *testing.T, srv *httptest.Server, path string, v interface{}) int {
+	t.Helper()
+	res, err := http.Get(srv.URL + path)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(res.Body).Decode(v))
+	return res.StatusCode
+}
+
+func TestAPI(t *testing.T) {
+	srv := newTestServer(t)
+
+	var summary interfaces.Summary
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/summary", &summary))
+	assert.Equal(t, 2, summary.TotalAttempted)
+
+	var cal calendarResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/calendar", &cal))
+	assert.Equal(t, 2, cal.Total)
+	require.Len(t, cal.Levels, len(cal.Days))
+	assert.NotZero(t, cal.Levels[len(cal.Levels)-1]+cal.Levels[len(cal.Levels)-2])
+
+	var sessions sessionsResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/sessions?limit=1", &sessions))
+	assert.Equal(t, 2, sessions.Total)
+	require.Len(t, sessions.Sessions, 1)
+	assert.True(t, sessions.Sessions[0].Solved, "newest first")
+	assert.Equal(t, "go", sessions.Sessions[0].Language)
+	assert.Equal(t, "10m0s", sessions.Sessions[0].Duration)
+
+	var patterns struct {
+		Patterns []patternMastery `json:"patterns"`
+	}
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/patterns", &patterns))
+	require.Len(t, patterns.Patterns, 1)
+	assert.Equal(t, "hash-map", patterns.Patterns[0].Pattern)
+	assert.Equal(t, 50.0, patterns.Patterns[0].SuccessRate)
+	assert.Greater(t, patterns.Patterns[0].Retention, 0.0)
+
+	var archive struct {
+		Problems []archivedProblem `json:"problems"`
+	}
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/solutions", &archive))
+	require.Len(t, archive.Problems, 1)
+	assert.Equal(t, archivedProblem{ProblemID: "two_sum", Solutions: 2, Passed: true, Last: archive.Problems[0].Last}, archive.Problems[0])
+
+	var history struct {
+		ProblemID string             `json:"problem_id"`
+		Solutions []archivedSolution `json:"solutions"`
+	}
+	assert.Equal(t, http.StatusOK, getJSON(t, srv, "/api/solutions/two_sum", &history))
+	require.Len(t, history.Solutions, 2)
+	assert.Equal(t, 2, history.Solutions[1].Number)
+	assert.Equal(t, 1, history.Solutions[1].TestsPassed)
+	assert.Equal(t, "func twoSum() {}\n", history.Solutions[1].Code)
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	assert.Equal(t, http.StatusNotFound, getJSON(t, srv, "/api/solutions/missing", &apiErr))
+	assert.Contains(t, apiErr.Error, "no solutions to missing")
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, srv, "/api/sessions?limit=-1", &apiErr))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, srv, "/api/nothing", &apiErr))
+}
+
+func TestPage(t *testing.T) {
+	srv := newTestServer(t)
+
+	for path, want := range map[string]string{
+		"/":              "<title>AlgoScales Dashboard</title>",
+		"/dashboard.js":  "api/sessions",
+		"/dashboard.css": "--accent",
+	} {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Contains(t, string(body), want, path)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, path := range []string{"/", "/api/sessions", "/api/solutions/two_sum"} {
+		res, err := http.Post(srv.URL+path, "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode, path)
+	}
+}
+
+func TestListenAndServe(t *testing.T) {
+	repo := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addrs := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(repo).ListenAndServe(ctx, "127.0.0.1:0", func(addr string) { addrs <- addr })
+	}()
+
+	addr := <-addrs
+	res, err := http.Get("http://" + addr + "/api/summary")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,163 @@
+// The dashboard page. Everything is fetched from the read-only API served
+// alongside it and drawn as plain HTML and inline SVG, with no dependencies.
+(function () {
+  var W = 720;
+  var DAY = 12, GAP = 2;
+  var sessionsShown = 0;
+
+  function esc(s) {
+    return String(s).replace(/[&<>"]/g, function (c) {
+      return { "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;" }[c];
+    });
+  }
+
+  function get(path) {
+    return fetch(path).then(function (res) {
+      return res.json().then(function (body) {
+        if (!res.ok) {
+          throw new Error(body.error || res.statusText);
+        }
+        return body;
+      });
+    });
+  }
+
+  function fail(el) {
+    return function (err) {
+      el.innerHTML = '<p class="empty">Could not load: ' + esc(err.message) + "</p>";
+    };
+  }
+
+  function date(s) {
+    return new Date(s).toLocaleDateString(undefined, { month: "short", day: "numeric", year: "numeric" });
+  }
+
+  function datetime(s) {
+    return new Date(s).toLocaleString(undefined, { month: "short", day: "numeric", year: "numeric", hour: "2-digit", minute: "2-digit" });
+  }
+
+  function card(value, name) {
+    return '<div class="card"><div class="value">' + esc(value) + '</div><div class="name">' + esc(name) + "</div></div>";
+  }
+
+  function summary() {
+    var el = document.getElementById("summary");
+    get("api/summary").then(function (s) {
+      el.innerHTML =
+        card(s.total_solved + " / " + s.total_attempted, "Sessions solved / attempted") +
+        card(Math.round(s.success_rate) + "%", "Success rate") +
+        card(s.avg_solve_time || "-", "Average solve time") +
+        card(s.fastest_solve.problem_id ? s.fastest_solve.time : "-", "Fastest solve" + (s.fastest_solve.problem_id ? " (" + s.fastest_solve.problem_id + ")" : ""));
+    }).catch(fail(el));
+  }
+
+  // calendar draws the past year as a column of days per week, Sunday on top
+  function calendar() {
+    var el = document.getElementById("heatmap");
+    get("api/calendar").then(function (c) {
+      document.getElementById("calendar-note").textContent = c.total + " sessions on " + c.active_days + " days in the past year" +
+        (c.busiest.attempted ? "; the busiest was " + date(c.busiest.date + "T00:00:00") + " with " + c.busiest.attempted + "." : ".");
+      var top = 16, body = "", lastMonth = -1;
+      c.days.forEach(function (day, i) {
+        var week = Math.floor(i / 7), x = week * (DAY + GAP), y = top + (i % 7) * (DAY + GAP);
+        var d = new Date(day.date + "T00:00:00");
+        if (i % 7 === 0 && d.getMonth() !== lastMonth) {
+          lastMonth = d.getMonth();
+          body += '<text x="' + x + '" y="10">' + esc(d.toLocaleDateString(undefined, { month: "short" })) + "</text>";
+        }
+        body += '<rect class="day level-' + c.levels[i] + '" x="' + x + '" y="' + y + '" width="' + DAY + '" height="' + DAY + '" rx="2">' +
+          "<title>" + esc(date(day.date + "T00:00:00") + ": " + day.solved + " solved of " + day.attempted) + "</title></rect>";
+      });
+      var weeks = Math.ceil(c.days.length / 7);
+      el.innerHTML = '<svg viewBox="0 0 ' + weeks * (DAY + GAP) + " " + (top + 7 * (DAY + GAP)) + '" width="100%" role="img">' + body + "</svg>";
+    }).catch(fail(el));
+  }
+
+  // mastery draws a bar per pattern for its success rate, and a thinner one
+  // under it for its predicted recall
+  function mastery() {
+    var el = document.getElementById("mastery");
+    get("api/patterns").then(function (r) {
+      if (!r.patterns.length) {
+        el.innerHTML = '<p class="empty">Nothing recorded yet.</p>';
+        return;
+      }
+      var row = 32, left = 170, right = 140, width = W - left - right, body = "";
+      r.patterns.forEach(function (p, i) {
+        var y = 4 + i * row;
+        var note = p.solved + " solved of " + p.attempted + (p.avg_time ? ", average solve " + p.avg_time : "") +
+          (p.reasons ? "; " + p.reasons.join("; ") : "");
+        body += '<text x="' + (left - 8) + '" y="' + (y + 14) + '" class="label' + (p.weak ? " weak" : "") + '" text-anchor="end">' +
+          esc(p.pattern + (p.weak ? " (weak)" : "")) + "</text>";
+        body += '<rect class="bar" x="' + left + '" y="' + (y + 2) + '" width="' + p.success_rate / 100 * width + '" height="14"><title>' + esc(note) + "</title></rect>";
+        body += '<rect class="recall" x="' + left + '" y="' + (y + 19) + '" width="' + p.retention / 100 * width + '" height="5"><title>' +
+          esc(Math.round(p.retention) + "% predicted recall" + (p.decays_at ? ", due " + date(p.decays_at) : "")) + "</title></rect>";
+        body += '<text x="' + (left + width + 8) + '" y="' + (y + 14) + '">' +
+          esc(Math.round(p.success_rate) + "% solved, " + Math.round(p.retention) + "% recall") + "</text>";
+      });
+      el.innerHTML = '<svg viewBox="0 0 ' + W + " " + (r.patterns.length * row + 8) + '" width="100%" role="img">' + body + "</svg>";
+    }).catch(fail(el));
+  }
+
+  function sessions() {
+    var el = document.getElementById("sessions"), more = document.getElementById("more");
+    get("api/sessions?limit=50&offset=" + sessionsShown).then(function (r) {
+      if (!r.total) {
+        el.innerHTML = '<tr><td colspan="6" class="empty">No sessions yet.</td></tr>';
+        return;
+      }
+      r.sessions.forEach(function (s) {
+        var result = s.solved ? '<span class="solved">Solved</span>' : '<span class="unsolved">Unsolved</span>';
+        if (s.solution_used) {
+          result += ' <span class="muted">(solution shown)</span>';
+        } else if (s.hints_used) {
+          result += ' <span class="muted">(hints)</span>';
+        }
+        el.insertAdjacentHTML("beforeend", "<tr><td>" + esc(datetime(s.start_time)) + "</td><td>" + esc(s.problem_id) + "</td><td>" +
+          esc(s.patterns.join(", ")) + "</td><td>" + esc(s.difficulty || "") + '</td><td class="num">' + esc(s.duration) + "</td><td>" + result + "</td></tr>");
+      });
+      sessionsShown += r.sessions.length;
+      more.hidden = sessionsShown >= r.total;
+    }).catch(fail(el));
+  }
+
+  function archive() {
+    var el = document.getElementById("archive");
+    get("api/solutions").then(function (r) {
+      if (!r.problems.length) {
+        el.innerHTML = '<li class="empty">No submitted solutions yet.</li>';
+        return;
+      }
+      r.problems.forEach(function (p) {
+        var li = document.createElement("li");
+        li.innerHTML = esc(p.problem_id) + '<br><span class="muted">' + p.solutions + " solution(s), " +
+          (p.passed ? "passed" : "not passed yet") + ", " + esc(date(p.last)) + "</span>";
+        li.addEventListener("click", function () {
+          Array.prototype.forEach.call(el.children, function (c) { c.classList.remove("active"); });
+          li.classList.add("active");
+          solution(p.problem_id);
+        });
+        el.appendChild(li);
+      });
+    }).catch(fail(el));
+  }
+
+  function solution(id) {
+    var el = document.getElementById("solution");
+    get("api/solutions/" + encodeURIComponent(id)).then(function (r) {
+      el.innerHTML = "";
+      r.solutions.slice().reverse().forEach(function (s) {
+        el.insertAdjacentHTML("beforeend", "<h3>#" + s.number + " " + esc(s.language) + ' <span class="muted">' + esc(datetime(s.time)) + ", " +
+          s.tests_passed + "/" + s.tests_total + " tests, " + s.lines + " lines</span> " +
+          (s.passed ? '<span class="solved">Passed</span>' : '<span class="unsolved">Failed</span>') + "</h3><pre><code>" + esc(s.code) + "</code></pre>");
+      });
+    }).catch(fail(el));
+  }
+
+  document.getElementById("more").addEventListener("click", sessions);
+  summary();
+  calendar();
+  mastery();
+  sessions();
+  archive();
+})();
@@ -0,0 +1,183 @@
+// Package goals keeps the user's practice goals, such as solving 10 medium
+// problems a week or keeping a 5-day streak, and measures them against the
+// recorded sessions
+package goals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Kind is what a goal measures
+type Kind string
+
+const (
+	// KindSolve counts the problems solved in the current week, from Monday
+	KindSolve Kind = "solve"
+	// KindStreak counts the days in a row with practice
+	KindStreak Kind = "streak"
+)
+
+// Difficulties a solve goal can be limited to
+var Difficulties = []string{"easy", "medium", "hard"}
+
+// Goal is a target the user set
+type Goal struct {
+	ID         string    `json:"id"`
+	Kind       Kind      `json:"kind"`
+	Target     int       `json:"target"`
+	Difficulty string    `json:"difficulty,omitempty"` // Solve goals only count problems of this difficulty
+	Pattern    string    `json:"pattern,omitempty"`    // Solve goals only count problems of this pattern
+	CreatedAt  time.Time `json:"created_at"`
+	// Reached is the period the goal was last reached in, so that reaching
+	// it is announced once per week or streak
+	Reached string `json:"reached,omitempty"`
+}
+
+// NewSolveGoal returns a goal of solving target problems a week, optionally
+// only of one difficulty or pattern
+func NewSolveGoal(target int, difficulty, pattern string) (Goal, error) {
+	if target <= 0 {
+		return Goal{}, fmt.Errorf("the target must be at least 1")
+	}
+	difficulty = strings.ToLower(difficulty)
+	if difficulty != "" && !contains(Difficulties, difficulty) {
+		return Goal{}, fmt.Errorf("unknown difficulty %q, expected one of %s", difficulty, strings.Join(Difficulties, ", "))
+	}
+	pattern = strings.ToLower(pattern)
+
+	id := string(KindSolve)
+	for _, part := range []string{difficulty, pattern} {
+		if part != "" {
+			id += "-" + part
+		}
+	}
+	return Goal{ID: id, Kind: KindSolve, Target: target, Difficulty: difficulty, Pattern: pattern}, nil
+}
+
+// NewStreakGoal returns a goal of practicing target days in a row
+func NewStreakGoal(target int) (Goal, error) {
+	if target <= 0 {
+		return Goal{}, fmt.Errorf("the target must be at least 1")
+	}
+	return Goal{ID: string(KindStreak), Kind: KindStreak, Target: target}, nil
+}
+
+// Describe says what the goal asks for, such as "Solve 10 medium problems
+// this week"
+func (g Goal) Describe() string {
+	switch g.Kind {
+	case KindSolve:
+		words := []string{"Solve", fmt.Sprint(g.Target)}
+		if g.Difficulty != "" {
+			words = append(words, g.Difficulty)
+		}
+		if g.Pattern != "" {
+			words = append(words, g.Pattern)
+		}
+		noun := "problems"
+		if g.Target == 1 {
+			noun = "problem"
+		}
+		return strings.Join(append(words, noun, "this week"), " ")
+	case KindStreak:
+		return fmt.Sprintf("Keep a %d-day practice streak", g.Target)
+	default:
+		return fmt.Sprintf("Unknown goal %q", g.Kind)
+	}
+}
+
+// backend is the user data backend the goals are kept in
+// Exported as variable for testing
+var backend = storage.UserData
+
+// goalsName is the file the goals are kept in
+const goalsName = "goals.json"
+
+// Load returns the goals, in the order they were set
+func Load() ([]Goal, error) {
+	b, err := backend()
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.Get(context.Background(), goalsName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goals: %v", err)
+	}
+	var goals []Goal
+	if err := json.Unmarshal(data, &goals); err != nil {
+		return nil, fmt.Errorf("failed to decode goals: %v", err)
+	}
+	return goals, nil
+}
+
+// Set adds a goal, replacing the one with the same ID. It reports whether
+// one was replaced. A replaced goal can be reached again this week.
+func Set(goal Goal) (bool, error) {
+	goals, err := Load()
+	if err != nil {
+		return false, err
+	}
+	goal.CreatedAt = time.Now()
+	goal.Reached = ""
+	for i, g := range goals {
+		if g.ID == goal.ID {
+			goals[i] = goal
+			return true, save(goals)
+		}
+	}
+	return false, save(append(goals, goal))
+}
+
+// Remove deletes a goal. It reports false if there was no such goal.
+func Remove(id string) (bool, error) {
+	goals, err := Load()
+	if err != nil {
+		return false, err
+	}
+	for i, g := range goals {
+		if g.ID == id {
+			return true, save(append(goals[:i], goals[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+// save replaces the goals
+func save(goals []Goal) error {
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	if goals == nil {
+		goals = []Goal{}
+	}
+	data, err := json.MarshalIndent(goals, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode goals: %v", err)
+	}
+	if err := b.Put(context.Background(), goalsName, data); err != nil {
+		return fmt.Errorf("failed to write goals: %v", err)
+	}
+	return nil
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package goals
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGoals keeps the goals and the notification inbox in temporary
+// directories for the test
+func stubGoals(t *testing.T) {
+	t.Helper()
+	original := backend
+	b := storage.NewLocalBackend(t.TempDir())
+	backend = func() (storage.Backend, error) { return b, nil }
+	originalInbox := notifications.GetInboxPath
+	inbox := filepath.Join(t.TempDir(), "notifications.json")
+	notifications.GetInboxPath = func() string { return inbox }
+	t.Cleanup(func() {
+		backend = original
+		notifications.GetInboxPath = originalInbox
+	})
+}
+
+func TestNewGoals(t *testing.T) {
+	goal, err := NewSolveGoal(10, "Medium", "")
+	require.NoError(t, err)
+	assert.Equal(t, "solve-medium", goal.ID)
+	assert.Equal(t, "Solve 10 medium problems this week", goal.Describe())
+
+	goal, err = NewSolveGoal(1, "", "dynamic-programming")
+	require.NoError(t, err)
+	assert.Equal(t, "solve-dynamic-programming", goal.ID)
+	assert.Equal(t, "Solve 1 dynamic-programming problem this week", goal.Describe())
+
+	goal, err = NewStreakGoal(5)
+	require.NoError(t, err)
+	assert.Equal(t, "streak", goal.ID)
+	assert.Equal(t, "Keep a 5-day practice streak", goal.Describe())
+
+	_, err = NewSolveGoal(0, "", "")
+	assert.Error(t, err)
+	_, err = NewSolveGoal(3, "brutal", "")
+	assert.ErrorContains(t, err, `unknown difficulty "brutal"`)
+	_, err = NewStreakGoal(-1)
+	assert.Error(t, err)
+}
+
+func TestSetAndRemove(t *testing.T) {
+	stubGoals(t)
+
+	list, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	medium, _ := NewSolveGoal(10, "medium", "")
+	replaced, err := Set(medium)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	streak, _ := NewStreakGoal(5)
+	_, err = Set(streak)
+	require.NoError(t, err)
+
+	// A goal of the same kind and filters replaces the old one
+	medium.Target = 12
+	medium.Reached = "2026-03-02"
+	replaced, err = Set(medium)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+
+	list, err = Load()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 12, list[0].Target)
+	assert.Empty(t, list[0].Reached, "a replaced goal can be reached again")
+	assert.False(t, list[0].CreatedAt.IsZero())
+
+	removed, err := Remove("solve-medium")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = Remove("solve-medium")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	list, err = Load()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "streak", list[0].ID)
+}
+
+func TestEvaluate(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	session := func(id, difficulty string, daysAgo int, solved bool) interfaces.SessionStats {
+		return interfaces.SessionStats{
+			ProblemID:  id,
+			Difficulty: difficulty,
+			Patterns:   []string{"hash-map"},
+			StartTime:  now.AddDate(0, 0, -daysAgo),
+			Solved:     solved,
+		}
+	}
+	sessions := []interfaces.SessionStats{
+		session("two_sum", "easy", 0, true),
+		session("group_anagrams", "medium", 1, true),
+		session("group_anagrams", "medium", 1, true),
+		session("top_k", "medium", 2, false),
+		session("lru_cache", "medium", 3, true), // Last week
+	}
+	medium, _ := NewSolveGoal(2, "medium", "")
+	hashMap, _ := NewSolveGoal(2, "", "hash-map")
+	streak, _ := NewStreakGoal(3)
+
+	progress := Evaluate([]Goal{medium, hashMap, streak}, sessions, now)
+	require.Len(t, progress, 3)
+	assert.Equal(t, 1, progress[0].Current, "each problem counts once, from Monday")
+	assert.Equal(t, "2026-03-02", progress[0].Period)
+	assert.False(t, progress[0].Done)
+	assert.Equal(t, 0.5, progress[0].Fraction())
+
+	assert.Equal(t, 2, progress[1].Current)
+	assert.True(t, progress[1].Done)
+	assert.Equal(t, 1.0, progress[1].Fraction())
+
+	assert.Equal(t, 4, progress[2].Current, "today and the three days before")
+	assert.Equal(t, "2026-03-01", progress[2].Period)
+	assert.True(t, progress[2].Done)
+
+	// The streak still counts before practicing today, until a day is missed
+	progress = Evaluate([]Goal{streak}, sessions[1:], now)
+	assert.Equal(t, 3, progress[0].Current)
+	progress = Evaluate([]Goal{streak}, sessions[3:], now)
+	assert.Equal(t, 0, progress[0].Current)
+	assert.Empty(t, progress[0].Period)
+}
+
+func TestAnnounce(t *testing.T) {
+	stubGoals(t)
+	now := time.Now()
+	goal, _ := NewSolveGoal(1, "", "")
+	_, err := Set(goal)
+	require.NoError(t, err)
+	solved := []interfaces.SessionStats{{ProblemID: "two_sum", StartTime: now, Solved: true}}
+
+	list, _ := Load()
+	reached, err := Announce(list, nil, now)
+	require.NoError(t, err)
+	assert.Empty(t, reached, "not reached yet")
+
+	reached, err = Announce(list, solved, now)
+	require.NoError(t, err)
+	require.Len(t, reached, 1)
+	inbox, err := notifications.Load()
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	assert.Equal(t, notifications.KindGoal, inbox[0].Kind)
+	assert.Equal(t, "Goal reached: Solve 1 problem this week", inbox[0].Title)
+
+	// Reaching it again the same week is not announced again
+	list, _ = Load()
+	assert.NotEmpty(t, list[0].Reached)
+	reached, err = Announce(list, solved, now)
+	require.NoError(t, err)
+	assert.Empty(t, reached)
+	inbox, _ = notifications.Load()
+	assert.Len(t, inbox, 1)
+}
@@ -0,0 +1,149 @@
+// Progress toward the goals, measured against the recorded sessions
+
+package goals
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+)
+
+// dateLayout is how periods are written
+const dateLayout = "2006-01-02"
+
+// Progress is how far a goal has got
+type Progress struct {
+	Goal
+	Description string `json:"description"`
+	Current     int    `json:"current"`
+	// Period is what the goal is measured over: the Monday starting the
+	// week for solve goals, the first day of the current streak for streak
+	// goals. YYYY-MM-DD; empty for a streak not yet started.
+	Period string `json:"period,omitempty"`
+	Done   bool   `json:"done"`
+}
+
+// Fraction returns how far the goal has got, from 0 to 1
+func (p Progress) Fraction() float64 {
+	if p.Target <= 0 {
+		return 0
+	}
+	return min(float64(p.Current)/float64(p.Target), 1)
+}
+
+// Evaluate measures goals against sessions as of now, in local time
+func Evaluate(goals []Goal, sessions []interfaces.SessionStats, now time.Time) []Progress {
+	now = now.Local()
+	week := weekStart(now)
+	streak, since := currentStreak(sessions, now)
+
+	progress := make([]Progress, len(goals))
+	for i, g := range goals {
+		p := Progress{Goal: g, Description: g.Describe()}
+		switch g.Kind {
+		case KindSolve:
+			p.Current = solved(g, sessions, week, now)
+			p.Period = week.Format(dateLayout)
+		case KindStreak:
+			p.Current = streak
+			if streak > 0 {
+				p.Period = since.Format(dateLayout)
+			}
+		}
+		p.Done = p.Target > 0 && p.Current >= p.Target
+		progress[i] = p
+	}
+	return progress
+}
+
+// Announce records which of goals the sessions reach, and adds a
+// notification for each goal newly reached in its period. It returns the
+// goals newly reached.
+func Announce(goals []Goal, sessions []interfaces.SessionStats, now time.Time) ([]Progress, error) {
+	var reached []Progress
+	for i, p := range Evaluate(goals, sessions, now) {
+		if !p.Done || p.Reached == p.Period {
+			continue
+		}
+		goals[i].Reached = p.Period
+		reached = append(reached, p)
+	}
+	if len(reached) == 0 {
+		return nil, nil
+	}
+	if err := save(goals); err != nil {
+		return nil, err
+	}
+
+	for _, p := range reached {
+		if err := notifications.Add(notifications.Notification{
+			Kind:    notifications.KindGoal,
+			Title:   "Goal reached: " + p.Description,
+			Details: []string{fmt.Sprintf("%d of %d. See your goals with: algo-scales goals", p.Current, p.Target)},
+		}); err != nil {
+			return reached, err
+		}
+	}
+	return reached, nil
+}
+
+// solved counts the distinct problems solved from from to now that count
+// toward a solve goal
+func solved(g Goal, sessions []interfaces.SessionStats, from, now time.Time) int {
+	problems := make(map[string]bool)
+	for _, s := range sessions {
+		start := s.StartTime.Local()
+		if !s.Solved || start.Before(from) || start.After(now) {
+			continue
+		}
+		if g.Difficulty != "" && !strings.EqualFold(s.Difficulty, g.Difficulty) {
+			continue
+		}
+		if g.Pattern != "" && !hasPattern(s.Patterns, g.Pattern) {
+			continue
+		}
+		problems[s.ProblemID] = true
+	}
+	return len(problems)
+}
+
+// hasPattern reports whether patterns holds pattern, ignoring case
+func hasPattern(patterns []string, pattern string) bool {
+	for _, p := range patterns {
+		if strings.EqualFold(p, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentStreak returns the run of days in a row with a session, and the
+// day it started. The run still counts until a day passes without practice.
+func currentStreak(sessions []interfaces.SessionStats, now time.Time) (int, time.Time) {
+	days := make(map[string]bool)
+	for _, s := range sessions {
+		days[s.StartTime.Local().Format(dateLayout)] = true
+	}
+
+	day := now
+	if !days[day.Format(dateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	var since time.Time
+	for days[day.Format(dateLayout)] {
+		streak++
+		since = day
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak, since
+}
+
+// weekStart returns the Monday starting the week of t, at midnight
+func weekStart(t time.Time) time.Time {
+	weekday := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-weekday, 0, 0, 0, 0, t.Location())
+}
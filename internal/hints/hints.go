@@ -0,0 +1,255 @@
+// Package hints enforces the hint policy: how many hints a problem may have,
+// how often they may be shown, and how many failed test runs must come
+// before the solution. Usage, including the hint level reached, is kept per
+// problem in the progress database so limits and levels carry across the
+// CLI, the TUI and separate vim-mode commands, and is reset once the problem
+// is solved.
+package hints
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Kind is the sort of help being asked for
+type Kind string
+
+const (
+	KindHint     Kind = "hint"
+	KindAIHint   Kind = "ai-hint"
+	KindSolution Kind = "solution"
+)
+
+// Hint levels, each showing more of the way to a solution than the last
+const (
+	LevelPattern     = 1 // The pattern explanation
+	LevelWalkthrough = 2 // Adds the solution walkthrough
+	LevelSolution    = 3 // Adds the solution code, if the solution is unlocked
+	MaxLevel         = LevelSolution
+)
+
+// Decision is the policy's answer to a request for help
+type Decision struct {
+	Allowed bool
+	Reason  string // Why the request was refused, empty if allowed
+	Level   int    // The problem's hint level after an allowed hint
+}
+
+// allowed is the decision for requests the policy does not limit
+var allowed = Decision{Allowed: true}
+
+// Check decides whether help of the given kind may be shown now, given the
+// problem's usage so far
+func Check(policy config.HintPolicy, usage storage.HintUsage, kind Kind, now time.Time) Decision {
+	if kind == KindSolution {
+		if usage.SolutionViewed || policy.SolutionAfterFailures <= 0 || usage.FailedRuns >= policy.SolutionAfterFailures {
+			return allowed
+		}
+		return Decision{Reason: fmt.Sprintf("The solution unlocks after %d failed test %s (%d so far)",
+			policy.SolutionAfterFailures, plural(policy.SolutionAfterFailures, "run"), usage.FailedRuns)}
+	}
+
+	if policy.MaxHints > 0 && usage.Hints >= policy.MaxHints {
+		return Decision{Reason: fmt.Sprintf("Hint limit reached: %d of %d %s used on this problem",
+			usage.Hints, policy.MaxHints, plural(policy.MaxHints, "hint"))}
+	}
+	if policy.MinIntervalSeconds > 0 && !usage.LastHint.IsZero() {
+		next := usage.LastHint.Add(time.Duration(policy.MinIntervalSeconds) * time.Second)
+		if now.Before(next) {
+			wait := next.Sub(now).Round(time.Second)
+			if wait < time.Second {
+				wait = time.Second
+			}
+			return Decision{Reason: fmt.Sprintf("Next hint available in %s", wait)}
+		}
+	}
+	return allowed
+}
+
+// Describe summarizes a policy in one line
+func Describe(policy config.HintPolicy) string {
+	var parts []string
+	if policy.MaxHints > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s per problem", policy.MaxHints, plural(policy.MaxHints, "hint")))
+	}
+	if policy.MinIntervalSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("%s between hints", time.Duration(policy.MinIntervalSeconds)*time.Second))
+	}
+	if policy.SolutionAfterFailures > 0 {
+		parts = append(parts, fmt.Sprintf("solution after %d failed %s",
+			policy.SolutionAfterFailures, plural(policy.SolutionAfterFailures, "run")))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural returns word, with an s unless n is one
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// Store keeps per-problem hint usage
+type Store interface {
+	LoadHintUsage(ctx context.Context, problemID string) (storage.HintUsage, error)
+	LoadAllHintUsage(ctx context.Context) ([]storage.HintUsage, error)
+	SaveHintUsage(ctx context.Context, usage storage.HintUsage) error
+}
+
+// Tracker applies a policy to the usage kept in a store
+type Tracker struct {
+	policy config.HintPolicy
+	store  Store
+	now    func() time.Time
+}
+
+// NewTracker creates a tracker enforcing policy on the usage in store
+func NewTracker(policy config.HintPolicy, store Store) *Tracker {
+	return &Tracker{policy: policy, store: store, now: time.Now}
+}
+
+// WithClock replaces the tracker's clock
+func (t *Tracker) WithClock(now func() time.Time) *Tracker {
+	t.now = now
+	return t
+}
+
+// Policy returns the policy being enforced
+func (t *Tracker) Policy() config.HintPolicy {
+	return t.policy
+}
+
+// Usage returns the help a problem has had since it was last solved
+func (t *Tracker) Usage(ctx context.Context, problemID string) (storage.HintUsage, error) {
+	return t.store.LoadHintUsage(ctx, problemID)
+}
+
+// Request asks for help with a problem. Allowed requests are counted
+// against the problem's usage; refused ones are counted as denied. A hint
+// is the problem's next hint level, up to MaxLevel.
+func (t *Tracker) Request(ctx context.Context, problemID string, kind Kind) (Decision, error) {
+	usage, err := t.store.LoadHintUsage(ctx, problemID)
+	if err != nil {
+		return Decision{Allowed: true, Level: LevelPattern}, err
+	}
+	var level int
+	if kind == KindHint {
+		level = min(usage.Level+1, MaxLevel)
+	}
+	return t.request(ctx, usage, kind, level)
+}
+
+// RequestLevel asks for a hint of the given level. Levels the problem has
+// already reached are shown again without counting, as a viewed solution
+// is.
+func (t *Tracker) RequestLevel(ctx context.Context, problemID string, level int) (Decision, error) {
+	usage, err := t.store.LoadHintUsage(ctx, problemID)
+	if err != nil {
+		return Decision{Allowed: true, Level: level}, err
+	}
+	return t.request(ctx, usage, KindHint, level)
+}
+
+// request decides a request against usage and saves what it counted
+func (t *Tracker) request(ctx context.Context, usage storage.HintUsage, kind Kind, level int) (Decision, error) {
+	if kind == KindHint && level <= usage.Level {
+		return Decision{Allowed: true, Level: usage.Level}, nil
+	}
+
+	now := t.now()
+	decision := Check(t.policy, usage, kind, now)
+	switch {
+	case !decision.Allowed:
+		usage.Denied++
+	case kind == KindSolution:
+		if usage.SolutionViewed {
+			return decision, nil
+		}
+		usage.SolutionViewed = true
+	default:
+		usage.Hints++
+		if kind == KindAIHint {
+			usage.AIHints++
+		} else {
+			usage.Level = level
+			decision.Level = level
+		}
+		usage.LastHint = now
+	}
+	return decision, t.store.SaveHintUsage(ctx, usage)
+}
+
+// RecordRun counts a test run towards unlocking the solution. A passing run
+// solves the problem and resets its usage, keeping only the denied count.
+func (t *Tracker) RecordRun(ctx context.Context, problemID string, passed bool) error {
+	usage, err := t.store.LoadHintUsage(ctx, problemID)
+	if err != nil {
+		return err
+	}
+	if passed {
+		usage = storage.HintUsage{ProblemID: problemID, Denied: usage.Denied}
+	} else {
+		usage.FailedRuns++
+	}
+	return t.store.SaveHintUsage(ctx, usage)
+}
+
+// Report totals hint usage across every problem
+type Report struct {
+	Policy          config.HintPolicy
+	Problems        int // Problems with any hints or a viewed solution
+	Hints           int
+	AIHints         int
+	SolutionsViewed int
+	Denied          int
+}
+
+// Report totals the usage in the store
+func (t *Tracker) Report(ctx context.Context) (Report, error) {
+	report := Report{Policy: t.policy}
+	all, err := t.store.LoadAllHintUsage(ctx)
+	if err != nil {
+		return report, err
+	}
+	for _, usage := range all {
+		if usage.Hints > 0 || usage.SolutionViewed {
+			report.Problems++
+		}
+		report.Hints += usage.Hints
+		report.AIHints += usage.AIHints
+		report.Denied += usage.Denied
+		if usage.SolutionViewed {
+			report.SolutionsViewed++
+		}
+	}
+	return report, nil
+}
+
+// Close releases the store if it holds resources
+func (t *Tracker) Close() error {
+	if closer, ok := t.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Open returns a tracker enforcing the configured policy on the progress
+// database. A config that cannot be loaded enforces no limits.
+// Exported as variable for testing
+var Open = func() *Tracker {
+	var policy config.HintPolicy
+	if cfg, err := config.LoadConfig(); err == nil {
+		policy = cfg.HintPolicy
+	}
+	return NewTracker(policy, storage.Default())
+}
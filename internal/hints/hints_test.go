@@ -0,0 +1,136 @@
+package hints
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	policy := config.HintPolicy{MaxHints: 2, MinIntervalSeconds: 90, SolutionAfterFailures: 3}
+
+	tests := []struct {
+		name   string
+		policy config.HintPolicy
+		usage  storage.HintUsage
+		kind   Kind
+		reason string // Empty if allowed
+	}{
+		{"no policy", config.HintPolicy{}, storage.HintUsage{Hints: 50}, KindHint, ""},
+		{"first hint", policy, storage.HintUsage{}, KindHint, ""},
+		{"limit reached", policy, storage.HintUsage{Hints: 2, LastHint: now.Add(-time.Hour)}, KindAIHint,
+			"Hint limit reached: 2 of 2 hints used on this problem"},
+		{"too soon", policy, storage.HintUsage{Hints: 1, LastHint: now.Add(-30 * time.Second)}, KindHint,
+			"Next hint available in 1m0s"},
+		{"interval passed", policy, storage.HintUsage{Hints: 1, LastHint: now.Add(-90 * time.Second)}, KindHint, ""},
+		{"solution locked", policy, storage.HintUsage{FailedRuns: 1}, KindSolution,
+			"The solution unlocks after 3 failed test runs (1 so far)"},
+		{"solution unlocked", policy, storage.HintUsage{FailedRuns: 3}, KindSolution, ""},
+		{"solution seen before", policy, storage.HintUsage{SolutionViewed: true}, KindSolution, ""},
+		{"solution ignores hint limit", policy, storage.HintUsage{Hints: 2, FailedRuns: 3}, KindSolution, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := Check(tt.policy, tt.usage, tt.kind, now)
+			assert.Equal(t, tt.reason == "", decision.Allowed)
+			assert.Equal(t, tt.reason, decision.Reason)
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "unlimited", Describe(config.HintPolicy{}))
+	assert.Equal(t, "1 hint per problem, 2m0s between hints, solution after 2 failed runs",
+		Describe(config.HintPolicy{MaxHints: 1, MinIntervalSeconds: 120, SolutionAfterFailures: 2}))
+}
+
+func TestTracker(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tracker := NewTracker(config.HintPolicy{MaxHints: 2, MinIntervalSeconds: 60, SolutionAfterFailures: 1}, store).
+		WithClock(func() time.Time { return now })
+	defer tracker.Close()
+	ctx := context.Background()
+
+	decision, err := tracker.Request(ctx, "two_sum", KindHint)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	decision, err = tracker.Request(ctx, "two_sum", KindAIHint)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "within the interval")
+
+	now = now.Add(time.Minute)
+	decision, err = tracker.Request(ctx, "two_sum", KindAIHint)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	decision, err = tracker.Request(ctx, "two_sum", KindSolution)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "no failed runs yet")
+
+	require.NoError(t, tracker.RecordRun(ctx, "two_sum", false))
+	decision, err = tracker.Request(ctx, "two_sum", KindSolution)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	usage, err := store.LoadHintUsage(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, storage.HintUsage{ProblemID: "two_sum", Hints: 2, AIHints: 1, Level: LevelPattern, FailedRuns: 1,
+		SolutionViewed: true, LastHint: now, Denied: 2}, usage)
+
+	report, err := tracker.Report(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Report{Policy: tracker.Policy(), Problems: 1, Hints: 2, AIHints: 1, SolutionsViewed: 1, Denied: 2}, report)
+
+	// Solving resets the problem but keeps the denied count for stats
+	require.NoError(t, tracker.RecordRun(ctx, "two_sum", true))
+	usage, err = store.LoadHintUsage(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, storage.HintUsage{ProblemID: "two_sum", Denied: 2}, usage)
+}
+
+func TestTrackerLevels(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	tracker := NewTracker(config.HintPolicy{MaxHints: 3}, store)
+	defer tracker.Close()
+	ctx := context.Background()
+
+	// The first level, as the TUI shows it, counts once
+	for i := 0; i < 2; i++ {
+		decision, err := tracker.RequestLevel(ctx, "two_sum", LevelPattern)
+		require.NoError(t, err)
+		assert.Equal(t, Decision{Allowed: true, Level: LevelPattern}, decision)
+	}
+
+	// Later hints follow on from it, and stop at the last level
+	for _, want := range []int{LevelWalkthrough, LevelSolution, LevelSolution} {
+		decision, err := tracker.Request(ctx, "two_sum", KindHint)
+		require.NoError(t, err)
+		assert.Equal(t, want, decision.Level)
+	}
+
+	// AI hints count without changing the level
+	decision, err := tracker.Request(ctx, "two_sum", KindAIHint)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed, "over the limit")
+
+	usage, err := tracker.Usage(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, 3, usage.Hints)
+	assert.Equal(t, MaxLevel, usage.Level)
+
+	// Solving starts the levels over
+	require.NoError(t, tracker.RecordRun(ctx, "two_sum", true))
+	decision, err = tracker.Request(ctx, "two_sum", KindHint)
+	require.NoError(t, err)
+	assert.Equal(t, LevelPattern, decision.Level)
+}
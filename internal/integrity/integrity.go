@@ -0,0 +1,339 @@
+// Package integrity checks the user's data files on startup, and repairs a
+// damaged one from its newest valid backup instead of letting it block
+// every command with a decoding error
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// backupInterval is how often a valid file is backed up
+const backupInterval = 24 * time.Hour
+
+// keepBackups is how many automatic backups of each file are kept
+const keepBackups = 5
+
+// backupTimeLayout stamps backup file names, as stats reset does
+const backupTimeLayout = "20060102-150405"
+
+// File is a user data file and how to tell it is intact
+type File struct {
+	Name  string                  // What the file holds, for reports
+	Path  string                  // Where it is kept
+	Check func(path string) error // Reports what is wrong with a copy of it
+}
+
+// Files returns the user data files checked on startup
+// Exported as variable for testing
+var Files = func() []File {
+	return []File{
+		{Name: "configuration", Path: config.Path(), Check: config.CheckFile},
+		{Name: "daily session", Path: daily.GetSessionDBPath(), Check: daily.CheckSessionDB},
+		{Name: "progress database", Path: storage.DBPath(), Check: storage.CheckDatabase},
+	}
+}
+
+// Status is the outcome of checking a file
+type Status string
+
+const (
+	StatusOK        Status = "ok"        // Checked and intact
+	StatusUnchanged Status = "unchanged" // Not changed since it was last found intact
+	StatusMissing   Status = "missing"   // Not created yet
+	StatusRestored  Status = "restored"  // Damaged, and replaced by a backup
+	StatusReset     Status = "reset"     // Damaged with no valid backup, and moved aside
+	StatusFailed    Status = "failed"    // Could not be checked or repaired
+)
+
+// Result is the outcome of checking one file
+type Result struct {
+	File    File
+	Status  Status
+	Problem error  // What was wrong with a damaged file, or why checking failed
+	Backup  string // The backup a restored file was replaced by
+	Aside   string // Where a damaged file was moved
+}
+
+// Repaired reports whether the file was damaged and has been dealt with
+func (r Result) Repaired() bool {
+	return r.Status == StatusRestored || r.Status == StatusReset
+}
+
+// manifestEntry is what was known about a file when it was last found
+// intact
+type manifestEntry struct {
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	SHA256     string    `json:"sha256"`
+	BackedUpAt time.Time `json:"backed_up_at,omitempty"`
+}
+
+// backupDir returns where automatic backups are kept, inside the backups
+// directory stats reset writes to
+func backupDir() string {
+	return filepath.Join(utils.GetConfigDir(), "backups", "auto")
+}
+
+// manifestPath returns where the manifest of intact files is kept
+func manifestPath() string {
+	return filepath.Join(utils.GetConfigDir(), "integrity.json")
+}
+
+// Check checks each file and repairs the damaged ones. Unless full, files
+// whose size and modification time match when they were last found intact
+// are not read, so a check on an unchanged tree costs a few stats.
+func Check(files []File, full bool) []Result {
+	defer profiling.Track("integrity check")()
+
+	manifest := loadManifest()
+	results := make([]Result, 0, len(files))
+	for _, f := range files {
+		results = append(results, checkFile(f, manifest, full))
+	}
+	saveManifest(manifest)
+	return results
+}
+
+// checkFile checks and if need be repairs a file, updating its manifest
+// entry
+func checkFile(f File, manifest map[string]manifestEntry, full bool) Result {
+	result := Result{File: f, Status: StatusOK}
+	info, err := os.Stat(f.Path)
+	if os.IsNotExist(err) {
+		result.Status = StatusMissing
+		return result
+	}
+	if err != nil {
+		result.Status, result.Problem = StatusFailed, err
+		return result
+	}
+
+	entry, known := manifest[f.Path]
+	if !full && known && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		result.Status = StatusUnchanged
+		return result
+	}
+	sum, err := checksum(f.Path)
+	if err != nil {
+		result.Status, result.Problem = StatusFailed, err
+		return result
+	}
+	if !full && known && entry.SHA256 == sum {
+		entry.Size, entry.ModTime = info.Size(), info.ModTime()
+		manifest[f.Path] = entry
+		result.Status = StatusUnchanged
+		return result
+	}
+
+	if err := safeCheck(f, f.Path); err != nil {
+		result.Problem = err
+		repair(f, &result)
+		if result.Status == StatusRestored {
+			record(f, manifest, time.Time{})
+		} else {
+			delete(manifest, f.Path)
+		}
+		return result
+	}
+
+	backedUpAt := entry.BackedUpAt
+	if time.Since(backedUpAt) >= backupInterval {
+		if err := backup(f, time.Now()); err == nil {
+			backedUpAt = time.Now()
+		}
+	}
+	entry = manifestEntry{Size: info.Size(), ModTime: info.ModTime(), SHA256: sum, BackedUpAt: backedUpAt}
+	manifest[f.Path] = entry
+	return result
+}
+
+// repair moves a damaged file aside and restores its newest valid backup,
+// if it has one
+func repair(f File, result *Result) {
+	now := time.Now()
+	aside := fmt.Sprintf("%s.damaged-%s", f.Path, now.Format(backupTimeLayout))
+	if err := os.Rename(f.Path, aside); err != nil {
+		result.Status, result.Problem = StatusFailed, fmt.Errorf("%v, and it could not be moved aside: %v", result.Problem, err)
+		return
+	}
+	result.Aside = aside
+	// Journals left by a damaged SQLite database must not be replayed into
+	// its replacement
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if _, err := os.Stat(f.Path + suffix); err == nil {
+			os.Rename(f.Path+suffix, aside+suffix)
+		}
+	}
+
+	for _, candidate := range backups(f) {
+		if safeCheck(f, candidate) != nil {
+			continue
+		}
+		if err := copyFile(candidate, f.Path); err != nil {
+			os.Remove(f.Path)
+			continue
+		}
+		result.Status, result.Backup = StatusRestored, candidate
+		return
+	}
+	result.Status = StatusReset
+}
+
+// safeCheck runs a file's check, treating a panic as damage
+func safeCheck(f File, path string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unreadable: %v", r)
+		}
+	}()
+	return f.Check(path)
+}
+
+// record notes a file as intact in the manifest
+func record(f File, manifest map[string]manifestEntry, backedUpAt time.Time) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return
+	}
+	sum, err := checksum(f.Path)
+	if err != nil {
+		return
+	}
+	manifest[f.Path] = manifestEntry{Size: info.Size(), ModTime: info.ModTime(), SHA256: sum, BackedUpAt: backedUpAt}
+}
+
+// backupPrefix and backupExt split a file's name for naming its backups,
+// such as progress-20240102-150405.db for progress.db
+func backupPrefix(f File) (string, string) {
+	ext := filepath.Ext(f.Path)
+	return strings.TrimSuffix(filepath.Base(f.Path), ext) + "-", ext
+}
+
+// backup copies an intact file into the automatic backups, dropping the
+// oldest beyond keepBackups
+func backup(f File, now time.Time) error {
+	dir := backupDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	prefix, ext := backupPrefix(f)
+	if err := copyFile(f.Path, filepath.Join(dir, prefix+now.Format(backupTimeLayout)+ext)); err != nil {
+		return err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*"+ext))
+	sort.Strings(matches)
+	for len(matches) > keepBackups {
+		os.Remove(matches[0])
+		matches = matches[1:]
+	}
+	return nil
+}
+
+// backups returns a file's backups, newest first: the automatic ones and
+// any taken by stats reset
+func backups(f File) []string {
+	prefix, ext := backupPrefix(f)
+	var candidates []string
+	for _, dir := range []string{backupDir(), filepath.Dir(backupDir())} {
+		matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*"+ext))
+		candidates = append(candidates, matches...)
+	}
+	// Names end in their timestamp, so the newest sorts last whichever
+	// directory it is in
+	sort.Slice(candidates, func(i, j int) bool {
+		return filepath.Base(candidates[i]) > filepath.Base(candidates[j])
+	})
+	return candidates
+}
+
+// checksum returns the SHA-256 of a file's contents
+func checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies src to dst, replacing it
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// loadManifest reads what was known about each file when last found
+// intact. A missing or unreadable manifest just means every file is read.
+func loadManifest() map[string]manifestEntry {
+	manifest := make(map[string]manifestEntry)
+	if data, err := os.ReadFile(manifestPath()); err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+// saveManifest records the intact files for the next check
+func saveManifest(manifest map[string]manifestEntry) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(manifestPath()), 0755); err != nil {
+		return
+	}
+	os.WriteFile(manifestPath(), data, 0644)
+}
+
+// Describe explains a repaired or failed result in a sentence or two
+func Describe(r Result) string {
+	what := fmt.Sprintf("Your %s (%s) was damaged: %v.", r.File.Name, r.File.Path, r.Problem)
+	switch r.Status {
+	case StatusRestored:
+		return fmt.Sprintf("%s\n  Restored it from the backup %s; the damaged copy was kept as %s.", what, r.Backup, r.Aside)
+	case StatusReset:
+		return fmt.Sprintf("%s\n  No valid backup was found, so a new one will be started; the damaged copy was kept as %s.", what, r.Aside)
+	case StatusFailed:
+		return fmt.Sprintf("Could not check your %s (%s): %v.", r.File.Name, r.File.Path, r.Problem)
+	default:
+		return fmt.Sprintf("Your %s (%s) is %s.", r.File.Name, r.File.Path, r.Status)
+	}
+}
@@ -0,0 +1,130 @@
+package integrity
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// testFiles returns the user data files kept in dir
+func testFiles(dir string) []File {
+	return []File{
+		{Name: "configuration", Path: filepath.Join(dir, "config.json"), Check: config.CheckFile},
+		{Name: "daily session", Path: filepath.Join(dir, "stats", daily.SessionDBFileName), Check: daily.CheckSessionDB},
+		{Name: "progress database", Path: filepath.Join(dir, storage.DBFileName), Check: storage.CheckDatabase},
+	}
+}
+
+func stubConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	original := utils.GetConfigDir
+	utils.GetConfigDir = func() string { return dir }
+	t.Cleanup(func() { utils.GetConfigDir = original })
+	return dir
+}
+
+// writeSessionDB writes a daily session database holding raw as the
+// active session
+func writeSessionDB(t *testing.T, path string, raw string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	db, err := bbolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(daily.SessionBucketName))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(daily.ActiveSessionKey), []byte(raw))
+	}))
+}
+
+func statuses(results []Result) []Status {
+	var out []Status
+	for _, r := range results {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	dir := stubConfigDir(t)
+	files := testFiles(dir)
+	configFile, sessionDB, progressDB := files[0].Path, files[1].Path, files[2].Path
+
+	// Nothing exists yet
+	assert.Equal(t, []Status{StatusMissing, StatusMissing, StatusMissing}, statuses(Check(files, false)))
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`{"language": "python"}`), 0644))
+	writeSessionDB(t, sessionDB, `{"date": "2024-01-02", "problems": {}}`)
+	store := storage.NewSQLiteStore(progressDB)
+	require.NoError(t, store.SaveStreak(context.Background(), storage.Streak{Name: storage.DailyStreak, Current: 3}))
+	require.NoError(t, store.Close())
+
+	t.Run("Intact", func(t *testing.T) {
+		assert.Equal(t, []Status{StatusOK, StatusOK, StatusOK}, statuses(Check(files, false)))
+		backups, _ := filepath.Glob(filepath.Join(backupDir(), "*"))
+		assert.Len(t, backups, 3, "each intact file is backed up")
+
+		// Unchanged files are not read again, unless the check is full
+		assert.Equal(t, []Status{StatusUnchanged, StatusUnchanged, StatusUnchanged}, statuses(Check(files, false)))
+		assert.Equal(t, []Status{StatusOK, StatusOK, StatusOK}, statuses(Check(files, true)))
+	})
+
+	t.Run("RestoreFromBackup", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configFile, []byte("{\n  \"language\": \"go\",\n}"), 0644))
+		writeSessionDB(t, sessionDB, `{"date": 20240102}`)
+
+		results := Check(files, false)
+		assert.Equal(t, []Status{StatusRestored, StatusRestored, StatusUnchanged}, statuses(results))
+		assert.Contains(t, results[0].Problem.Error(), "line 3, column 1")
+		assert.Contains(t, Describe(results[0]), "Restored it from the backup")
+		assert.FileExists(t, results[0].Aside)
+
+		cfg, err := os.ReadFile(configFile)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"language": "python"}`, string(cfg))
+		assert.NoError(t, daily.CheckSessionDB(sessionDB))
+	})
+
+	t.Run("ResetWithoutBackup", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(dir, "backups")))
+		require.NoError(t, os.WriteFile(progressDB, []byte("not a database, but long enough to look like one to a reader"), 0644))
+
+		results := Check(files, false)
+		assert.Equal(t, StatusReset, results[2].Status)
+		assert.Contains(t, Describe(results[2]), "No valid backup")
+		assert.NoFileExists(t, progressDB)
+		assert.FileExists(t, results[2].Aside)
+	})
+}
+
+func TestBackupsAreRotated(t *testing.T) {
+	dir := stubConfigDir(t)
+	f := testFiles(dir)[0]
+	require.NoError(t, os.WriteFile(f.Path, []byte(`{}`), 0644))
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < keepBackups+2; i++ {
+		require.NoError(t, backup(f, start.Add(time.Duration(i)*time.Hour)))
+	}
+	kept := backups(f)
+	require.Len(t, kept, keepBackups)
+	assert.Equal(t, "config-20240102-090405.json", filepath.Base(kept[0]), "newest first")
+
+	// Backups taken by stats reset are candidates too
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "backups", "config-20250101-000000.json"), []byte(`{}`), 0644))
+	assert.Equal(t, "config-20250101-000000.json", filepath.Base(backups(f)[0]))
+}
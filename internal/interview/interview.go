@@ -0,0 +1,236 @@
+// Package interview assembles mock interviews: a few problems across
+// difficulties worked against a single clock without hints or solutions,
+// followed by a report of how each problem went
+package interview
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+)
+
+const (
+	// DefaultDuration is the length of the interview clock
+	DefaultDuration = 60 * time.Minute
+
+	// DefaultProblems is how many problems an interview has
+	DefaultProblems = 3
+
+	// MinProblems and MaxProblems bound the problems in an interview
+	MinProblems = 2
+	MaxProblems = 3
+)
+
+// difficultyLadder orders difficulties from warm-up to hardest
+var difficultyLadder = []string{"easy", "medium", "hard"}
+
+// Select picks n problems climbing in difficulty: easy, medium and hard for
+// three problems, medium and hard for two. Problems of distinct patterns are
+// preferred; a difficulty with no problem left is filled from the rest.
+func Select(problems []problem.Problem, n int, rng *rand.Rand) ([]problem.Problem, error) {
+	if n < MinProblems || n > MaxProblems {
+		return nil, fmt.Errorf("an interview has %d to %d problems, not %d", MinProblems, MaxProblems, n)
+	}
+	if len(problems) < n {
+		return nil, fmt.Errorf("need at least %d problems for an interview, found %d", n, len(problems))
+	}
+
+	pool := make([]problem.Problem, len(problems))
+	copy(pool, problems)
+	rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	used := make(map[string]bool)
+	patterns := make(map[string]bool)
+	pick := func(match func(problem.Problem) bool) (problem.Problem, bool) {
+		var fallback *problem.Problem
+		for i := range pool {
+			p := pool[i]
+			if used[p.ID] || !match(p) {
+				continue
+			}
+			if !patterns[primaryPattern(p)] {
+				return p, true
+			}
+			if fallback == nil {
+				fallback = &pool[i]
+			}
+		}
+		if fallback != nil {
+			return *fallback, true
+		}
+		return problem.Problem{}, false
+	}
+
+	selected := make([]problem.Problem, 0, n)
+	for _, difficulty := range difficultyLadder[len(difficultyLadder)-n:] {
+		p, ok := pick(func(p problem.Problem) bool { return strings.EqualFold(p.Difficulty, difficulty) })
+		if !ok {
+			p, _ = pick(func(problem.Problem) bool { return true })
+		}
+		used[p.ID] = true
+		patterns[primaryPattern(p)] = true
+		selected = append(selected, p)
+	}
+	return selected, nil
+}
+
+// primaryPattern is the pattern a problem is mainly about
+func primaryPattern(p problem.Problem) string {
+	if len(p.Patterns) == 0 {
+		return ""
+	}
+	return p.Patterns[0]
+}
+
+// Result is how one interview problem went
+type Result struct {
+	Problem  problem.Problem
+	Language string
+	Reached  bool          // False when the interview ended before the problem was opened
+	Time     time.Duration // Time spent on the problem
+	Passed   int           // Tests the final submission passed
+	Total    int
+	Code     string // The final submission
+}
+
+// Solved reports whether the final submission passed every test
+func (r Result) Solved() bool {
+	return r.Total > 0 && r.Passed == r.Total
+}
+
+// Report summarizes a finished interview
+type Report struct {
+	Date     time.Time
+	Duration time.Duration // The interview clock
+	Elapsed  time.Duration // Time actually used
+	Results  []Result
+	Feedback string // AI interviewer feedback, if any
+}
+
+// Solved counts the problems whose final submission passed every test
+func (r *Report) Solved() int {
+	solved := 0
+	for _, res := range r.Results {
+		if res.Solved() {
+			solved++
+		}
+	}
+	return solved
+}
+
+// PassRate is the share of tests passed across all problems, from 0 to 1.
+// Tests of problems never reached or never run count as failed.
+func (r *Report) PassRate() float64 {
+	passed, total := 0, 0
+	for _, res := range r.Results {
+		passed += res.Passed
+		if res.Total > 0 {
+			total += res.Total
+		} else {
+			total += len(res.Problem.TestCases)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(passed) / float64(total)
+}
+
+// Markdown renders the report
+func (r *Report) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("# Mock Interview Report\n\n")
+	fmt.Fprintf(&b, "_%s · %d problem(s) · %s of %s used_\n\n",
+		r.Date.Format("2006-01-02 15:04"), len(r.Results), FormatClock(r.Elapsed), FormatClock(r.Duration))
+	fmt.Fprintf(&b, "**Solved:** %d/%d · **Tests passed:** %.0f%%\n\n", r.Solved(), len(r.Results), r.PassRate()*100)
+
+	b.WriteString("| # | Problem | Difficulty | Time | Tests passed | Result |\n|---|---|---|---|---|---|\n")
+	for i, res := range r.Results {
+		timing, tests := "-", "-"
+		if res.Reached {
+			timing = FormatClock(res.Time)
+			if res.Total > 0 {
+				tests = fmt.Sprintf("%d/%d (%.0f%%)", res.Passed, res.Total, float64(res.Passed)/float64(res.Total)*100)
+			}
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n",
+			i+1, res.Problem.Title, res.Problem.Difficulty, timing, tests, verdict(res))
+	}
+	b.WriteString("\n")
+
+	if feedback := strings.TrimSpace(r.Feedback); feedback != "" {
+		b.WriteString("## Interviewer Feedback\n\n" + feedback + "\n")
+	}
+	return b.String()
+}
+
+// verdict describes the outcome of one problem
+func verdict(res Result) string {
+	switch {
+	case !res.Reached:
+		return "not reached"
+	case res.Solved():
+		return "✓ solved"
+	case res.Passed > 0:
+		return "partial"
+	default:
+		return "✗ unsolved"
+	}
+}
+
+// FormatClock formats a duration as mm:ss
+func FormatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// FeedbackPrompt asks an interviewer to assess the interview from its
+// timings, test results and final code
+func FeedbackPrompt(r *Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "A candidate just finished a %d-minute mock coding interview with %d problems, without hints.\n",
+		int(r.Duration.Minutes()), len(r.Results))
+	b.WriteString("Give concise interview feedback: overall assessment, time management, and for each problem ")
+	b.WriteString("what went well and what to improve. End with a hire/no-hire style verdict and the top two things to practice.\n")
+
+	for i, res := range r.Results {
+		fmt.Fprintf(&b, "\n## Problem %d: %s (%s; patterns: %s)\n", i+1, res.Problem.Title, res.Problem.Difficulty, strings.Join(res.Problem.Patterns, ", "))
+		if !res.Reached {
+			b.WriteString("Not reached before time ran out.\n")
+			continue
+		}
+		fmt.Fprintf(&b, "Time spent: %s (estimate %d min). Tests passed: %d/%d.\n", FormatClock(res.Time), res.Problem.EstimatedTime, res.Passed, res.Total)
+		if code := strings.TrimSpace(codefmt.Code(res.Language, res.Code)); code != "" {
+			fmt.Fprintf(&b, "Final %s code:\n```%s\n%s\n```\n", res.Language, res.Language, code)
+		}
+	}
+	return b.String()
+}
+
+// Feedback asks an AI agent, acting as interviewer, to assess the interview
+func Feedback(ctx context.Context, agent ai.Agent, r *Report) (string, error) {
+	messages := []ai.Message{
+		{Role: "system", Content: ai.NewSystemPrompts().GetInterviewerPrompt()},
+		{Role: "user", Content: FeedbackPrompt(r)},
+	}
+	responses, err := agent.Chat(ctx, messages, ai.ChatOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var feedback strings.Builder
+	for resp := range responses {
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		feedback.WriteString(resp.Content)
+	}
+	return strings.TrimSpace(feedback.String()), nil
+}
@@ -0,0 +1,107 @@
+package interview
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProblems = []problem.Problem{
+	{ID: "two_sum", Title: "Two Sum", Difficulty: "Easy", Patterns: []string{"hash-map"}},
+	{ID: "valid_anagram", Title: "Valid Anagram", Difficulty: "Easy", Patterns: []string{"hash-map"}},
+	{ID: "max_window", Title: "Max Sum Window", Difficulty: "Medium", Patterns: []string{"sliding-window"}},
+	{ID: "group_anagrams", Title: "Group Anagrams", Difficulty: "Medium", Patterns: []string{"hash-map"}},
+	{ID: "lru_cache", Title: "LRU Cache", Difficulty: "Hard", Patterns: []string{"linked-list"}},
+}
+
+func TestSelect(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		picks, err := Select(testProblems, 3, rand.New(rand.NewSource(seed)))
+		require.NoError(t, err)
+		require.Len(t, picks, 3)
+		assert.Equal(t, "Easy", picks[0].Difficulty)
+		assert.Equal(t, "max_window", picks[1].ID, "a medium problem of a pattern not already used")
+		assert.Equal(t, "lru_cache", picks[2].ID)
+	}
+
+	picks, err := Select(testProblems, 2, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	assert.Equal(t, "Medium", picks[0].Difficulty)
+	assert.Equal(t, "Hard", picks[1].Difficulty)
+
+	// Missing difficulties are filled from the rest
+	picks, err = Select(testProblems[:2], 2, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	assert.NotEqual(t, picks[0].ID, picks[1].ID)
+
+	_, err = Select(testProblems, 4, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+	_, err = Select(testProblems[:1], 2, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func testReport() *Report {
+	return &Report{
+		Date:     time.Date(2026, 7, 1, 14, 0, 0, 0, time.UTC),
+		Duration: 60 * time.Minute,
+		Elapsed:  52*time.Minute + 10*time.Second,
+		Results: []Result{
+			{Problem: testProblems[0], Language: "go", Reached: true, Time: 9 * time.Minute, Passed: 4, Total: 4, Code: "func twoSum() {}"},
+			{Problem: testProblems[2], Language: "go", Reached: true, Time: 43*time.Minute + 10*time.Second, Passed: 1, Total: 4},
+			{Problem: problem.Problem{Title: "LRU Cache", Difficulty: "Hard", TestCases: make([]problem.TestCase, 2)}},
+		},
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := testReport()
+	assert.Equal(t, 1, report.Solved())
+	assert.InDelta(t, 0.5, report.PassRate(), 1e-9, "5 of 10 tests, counting those never reached")
+
+	md := report.Markdown()
+	assert.Contains(t, md, "_2026-07-01 14:00 · 3 problem(s) · 52:10 of 60:00 used_")
+	assert.Contains(t, md, "**Solved:** 1/3 · **Tests passed:** 50%")
+	assert.Contains(t, md, "| 1 | Two Sum | Easy | 09:00 | 4/4 (100%) | ✓ solved |")
+	assert.Contains(t, md, "| 2 | Max Sum Window | Medium | 43:10 | 1/4 (25%) | partial |")
+	assert.Contains(t, md, "| 3 | LRU Cache | Hard | - | - | not reached |")
+	assert.NotContains(t, md, "Interviewer Feedback")
+
+	report.Feedback = "Strong start."
+	assert.Contains(t, report.Markdown(), "## Interviewer Feedback\n\nStrong start.")
+}
+
+// fakeAgent replies to chats with a fixed response
+type fakeAgent struct {
+	ai.Agent
+	messages []ai.Message
+}
+
+func (f *fakeAgent) Chat(ctx context.Context, messages []ai.Message, opts ai.ChatOptions) (<-chan ai.ChatResponse, error) {
+	f.messages = messages
+	responses := make(chan ai.ChatResponse, 2)
+	responses <- ai.ChatResponse{Content: "Good pacing. "}
+	responses <- ai.ChatResponse{Content: "Practice sliding windows.", Done: true}
+	close(responses)
+	return responses, nil
+}
+
+func TestFeedback(t *testing.T) {
+	agent := &fakeAgent{}
+	feedback, err := Feedback(context.Background(), agent, testReport())
+	require.NoError(t, err)
+	assert.Equal(t, "Good pacing. Practice sliding windows.", feedback)
+
+	require.Len(t, agent.messages, 2)
+	assert.Equal(t, "system", agent.messages[0].Role)
+	prompt := agent.messages[1].Content
+	assert.Contains(t, prompt, "60-minute mock coding interview with 3 problems")
+	assert.Contains(t, prompt, "Tests passed: 4/4")
+	assert.Contains(t, prompt, "```go\nfunc twoSum() {}\n```")
+	assert.Contains(t, prompt, "Not reached before time ran out.")
+}
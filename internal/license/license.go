@@ -3,88 +3,196 @@
 package license
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
+const (
+	// OfflineGracePeriod is how long a license keeps working without
+	// reaching the license server
+	OfflineGracePeriod = 14 * 24 * time.Hour
+
+	// revalidateAfter is how long a successful online check is trusted
+	// before the server is asked again
+	revalidateAfter = 24 * time.Hour
+
+	// apiURLEnv overrides the license server address, like the API client
+	apiURLEnv = "ALGO_SCALES_API_URL"
+
+	defaultAPIURL = "https://api.algo-scales.com/v1"
+)
+
+// ErrRevoked is returned when the license server no longer accepts a key
+var ErrRevoked = errors.New("license has been revoked")
+
 // License represents a user license
 type License struct {
 	LicenseKey   string    `json:"license_key"`
 	Email        string    `json:"email"`
 	PurchaseDate time.Time `json:"purchase_date"`
-	ExpiryDate   time.Time `json:"expiry_date"` // For potential subscription model
-	Signature    string    `json:"signature"`
+	ExpiryDate   time.Time `json:"expiry_date"`            // Zero if the license never expires
+	ValidatedAt  time.Time `json:"validated_at,omitempty"` // Last successful online check
+}
+
+// Status describes the stored license for display
+type Status struct {
+	License     License
+	Claims      Claims
+	Valid       bool
+	Err         error     // Why the license is not valid
+	GraceEndsAt time.Time // When the license stops working without an online check
 }
 
-// ValidateLicense checks if the license is valid
+// ValidateLicense checks if the license is valid. The key's signature and
+// expiry are checked offline; the license server is asked at most once a
+// day, and a license that cannot reach it keeps working for the offline
+// grace period after its last successful check.
 // Exported as variable for testing
 var ValidateLicense = func() (bool, error) {
-	licenseFile := filepath.Join(getConfigDir(), "license.json")
+	license, err := LoadLicense()
+	if err != nil {
+		return false, err
+	}
 
-	// Check if license file exists
-	if _, err := os.Stat(licenseFile); os.IsNotExist(err) {
-		return false, fmt.Errorf("license file not found")
+	if err := validate(&license, time.Now()); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	// Read license file
-	data, err := os.ReadFile(licenseFile)
+// validate checks license at now, refreshing and saving its online
+// validation when it is due
+func validate(license *License, now time.Time) error {
+	claims, err := Parse(license.LicenseKey, PublicKey())
 	if err != nil {
-		return false, err
+		return err
+	}
+	if claims.Expired(now) {
+		return fmt.Errorf("license expired on %s", claims.ExpiresAt.Format("2006-01-02"))
 	}
 
-	// Parse license
+	if now.Sub(license.ValidatedAt) < revalidateAfter {
+		return nil
+	}
+
+	valid, err := checkOnline(license.LicenseKey)
+	if err != nil {
+		// The server is unreachable, so fall back to the cached validation
+		if now.Sub(license.ValidatedAt) < OfflineGracePeriod {
+			return nil
+		}
+		return fmt.Errorf("license could not be verified for %d days, connect to the internet to continue: %v",
+			int(OfflineGracePeriod.Hours()/24), err)
+	}
+	if !valid {
+		return ErrRevoked
+	}
+
+	license.ValidatedAt = now
+	return saveLicense(*license)
+}
+
+// LoadLicense reads the stored license
+// Exported as variable for testing
+var LoadLicense = func() (License, error) {
 	var license License
+
+	data, err := os.ReadFile(filepath.Join(getConfigDir(), "license.json"))
+	if err != nil {
+		return license, fmt.Errorf("license file not found: %v", err)
+	}
+
 	if err := json.Unmarshal(data, &license); err != nil {
-		return false, err
+		return license, err
 	}
 
-	// Check expiry (for subscription model)
-	if !license.ExpiryDate.IsZero() && time.Now().After(license.ExpiryDate) {
-		return false, fmt.Errorf("license expired")
+	return license, nil
+}
+
+// Activate verifies a license key and stores it. A key the server cannot be
+// reached to confirm is still activated, starting its offline grace period.
+func Activate(licenseKey string) (License, error) {
+	licenseKey = strings.TrimSpace(licenseKey)
+	claims, err := Parse(licenseKey, PublicKey())
+	if err != nil {
+		return License{}, err
 	}
 
-	// Verify signature (simplified for MVP)
-	isValid := verifySignature(license)
-	if !isValid {
-		return false, fmt.Errorf("invalid license signature")
+	now := time.Now()
+	if claims.Expired(now) {
+		return License{}, fmt.Errorf("license expired on %s", claims.ExpiresAt.Format("2006-01-02"))
+	}
+	if valid, err := checkOnline(licenseKey); err == nil && !valid {
+		return License{}, ErrRevoked
 	}
 
-	return true, nil
+	license := License{
+		LicenseKey:   licenseKey,
+		Email:        claims.Email,
+		PurchaseDate: claims.IssuedAt,
+		ExpiryDate:   claims.ExpiresAt,
+		ValidatedAt:  now,
+	}
+	return license, saveLicense(license)
 }
 
-// RequestLicense prompts the user for their license key
-// For MVP, we'll just create a dummy license
-func RequestLicense() error {
-	var email, licenseKey string
+// GetStatus reports on the stored license without contacting the server
+func GetStatus() (Status, error) {
+	license, err := LoadLicense()
+	if err != nil {
+		return Status{}, err
+	}
 
-	// In a real implementation, you'd validate this with an API call
-	// For MVP, we'll just create a dummy license
-	fmt.Print("Enter your email: ")
-	fmt.Scanln(&email)
+	status := Status{License: license, GraceEndsAt: license.ValidatedAt.Add(OfflineGracePeriod)}
+	status.Claims, status.Err = Parse(license.LicenseKey, PublicKey())
+	now := time.Now()
+	switch {
+	case status.Err != nil:
+	case status.Claims.Expired(now):
+		status.Err = fmt.Errorf("license expired on %s", status.Claims.ExpiresAt.Format("2006-01-02"))
+	case now.After(status.GraceEndsAt):
+		status.Err = fmt.Errorf("license needs to be verified online")
+	default:
+		status.Valid = true
+	}
+	return status, nil
+}
 
+// RequestLicense prompts the user for their license key and activates it
+func RequestLicense() error {
 	fmt.Print("Enter your license key: ")
-	fmt.Scanln(&licenseKey)
+	licenseKey, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && licenseKey == "" {
+		return fmt.Errorf("failed to read license key: %v", err)
+	}
 
-	// Create a license (for demo purposes this is always valid)
-	license := License{
-		LicenseKey:   licenseKey,
-		Email:        email,
-		PurchaseDate: time.Now(),
-		ExpiryDate:   time.Now().AddDate(1, 0, 0), // Valid for 1 year
-		Signature:    generateSignature(licenseKey, email),
+	license, err := Activate(licenseKey)
+	if err != nil {
+		return err
 	}
+	fmt.Printf("License activated for %s\n", license.Email)
+	return nil
+}
 
-	// Save license to file
-	licenseFile := filepath.Join(getConfigDir(), "license.json")
-	licenseData, err := json.MarshalIndent(license, "", "  ")
+// saveLicense writes the license to the config directory
+func saveLicense(license License) error {
+	data, err := json.MarshalIndent(license, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(licenseFile, licenseData, 0644)
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(getConfigDir(), "license.json"), data, 0600)
 }
 
 // Helper functions - exported as variables for testing
@@ -93,18 +201,36 @@ var getConfigDir = func() string {
 	return filepath.Join(homeDir, ".algo-scales")
 }
 
-// verifySignature checks if a license signature is valid
-// This is a simplified version for MVP - exported as variable for testing
-var verifySignature = func(license License) bool {
-	// In a real implementation, this would use public key cryptography
-	// For MVP, we'll just check if the signature exists
-	return license.Signature != ""
-}
+// licenseClient is used for online license checks
+var licenseClient = &http.Client{Timeout: 10 * time.Second}
+
+// checkOnline asks the license server whether a key is still valid. An
+// error means the server could not be reached or gave no answer.
+// Exported as variable for testing
+var checkOnline = func(licenseKey string) (bool, error) {
+	url := defaultAPIURL
+	if env := os.Getenv(apiURLEnv); env != "" {
+		url = env
+	}
+
+	body, err := json.Marshal(map[string]string{"license_key": licenseKey})
+	if err != nil {
+		return false, err
+	}
+	resp, err := licenseClient.Post(strings.TrimSuffix(url, "/")+"/validate-license", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to reach license server: %v", err)
+	}
+	defer resp.Body.Close()
 
-// generateSignature creates a signature for a license
-// This is a simplified version for MVP
-func generateSignature(licenseKey, email string) string {
-	// In a real implementation, this would use private key cryptography
-	// For MVP, we'll just return a dummy signature
-	return "valid-signature-" + licenseKey[:4]
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("license server returned %s", resp.Status)
+	}
+	var result struct {
+		Valid bool `json:"valid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("invalid license server response: %v", err)
+	}
+	return result.Valid, nil
 }
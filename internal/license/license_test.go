@@ -1,8 +1,11 @@
 // Tests for license module
+
 package license
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,20 +15,79 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestValidateLicense(t *testing.T) {
-	// Create a temporary test directory
-	tempDir, err := os.MkdirTemp("", "algo-scales-test")
+// useTestKey makes license keys signed with the returned key verify
+func useTestKey(t *testing.T) ed25519.PrivateKey {
+	pub, priv, err := ed25519.GenerateKey(nil)
 	require.NoError(t, err)
-	defer os.RemoveAll(tempDir)
 
-	// Override config dir for testing
+	origPublicKey := PublicKey
+	t.Cleanup(func() { PublicKey = origPublicKey })
+	PublicKey = func() ed25519.PublicKey { return pub }
+	return priv
+}
+
+// useConfigDir points the license file at a temporary directory
+func useConfigDir(t *testing.T) string {
+	tempDir := t.TempDir()
 	origGetConfigDir := getConfigDir
-	defer func() { getConfigDir = origGetConfigDir }()
-	getConfigDir = func() string {
-		return tempDir
+	t.Cleanup(func() { getConfigDir = origGetConfigDir })
+	getConfigDir = func() string { return tempDir }
+	return tempDir
+}
+
+// mockCheckOnline replaces the license server check
+func mockCheckOnline(t *testing.T, valid bool, err error) *int {
+	calls := 0
+	origCheckOnline := checkOnline
+	t.Cleanup(func() { checkOnline = origCheckOnline })
+	checkOnline = func(string) (bool, error) {
+		calls++
+		return valid, err
 	}
+	return &calls
+}
+
+func signKey(t *testing.T, priv ed25519.PrivateKey, expires time.Time) string {
+	key, err := Sign(Claims{ID: "lic-1", Email: "test@example.com", IssuedAt: time.Now(), ExpiresAt: expires}, priv)
+	require.NoError(t, err)
+	return key
+}
+
+func writeLicense(t *testing.T, dir string, license License) {
+	data, err := json.MarshalIndent(license, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "license.json"), data, 0600))
+}
+
+func TestSignAndParse(t *testing.T) {
+	priv := useTestKey(t)
+	key := signKey(t, priv, time.Time{})
+
+	claims, err := Parse(key, PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, "lic-1", claims.ID)
+	assert.Equal(t, "test@example.com", claims.Email)
+	assert.False(t, claims.Expired(time.Now().AddDate(50, 0, 0)))
+
+	// A key signed by anyone else is rejected
+	_, other, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, err = Parse(signKey(t, other, time.Time{}), PublicKey())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Tampering with the claims breaks the signature
+	tampered := key[:len(keyPrefix)+2] + "x" + key[len(keyPrefix)+3:]
+	_, err = Parse(tampered, PublicKey())
+	assert.Error(t, err)
+
+	_, err = Parse("LICENSE-test", PublicKey())
+	assert.Error(t, err)
+}
+
+func TestValidateLicense(t *testing.T) {
+	priv := useTestKey(t)
+	tempDir := useConfigDir(t)
 
-	// Test cases
 	t.Run("NoLicenseFile", func(t *testing.T) {
 		valid, err := ValidateLicense()
 		require.Error(t, err)
@@ -33,54 +95,61 @@ func TestValidateLicense(t *testing.T) {
 		assert.False(t, valid)
 	})
 
-	t.Run("ValidLicense", func(t *testing.T) {
-		// Create a valid license
-		license := License{
-			LicenseKey:   "valid-key",
-			Email:        "test@example.com",
-			PurchaseDate: time.Now(),
-			ExpiryDate:   time.Now().AddDate(1, 0, 0), // Valid for 1 year
-			Signature:    "valid-signature",
-		}
-
-		// Save license to file
-		licenseFile := filepath.Join(tempDir, "license.json")
-		licenseData, err := json.MarshalIndent(license, "", "  ")
+	t.Run("RecentlyValidated", func(t *testing.T) {
+		calls := mockCheckOnline(t, false, nil)
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Now().AddDate(1, 0, 0)), ValidatedAt: time.Now()})
+
+		valid, err := ValidateLicense()
 		require.NoError(t, err)
-		err = os.WriteFile(licenseFile, licenseData, 0644)
+		assert.True(t, valid)
+		assert.Zero(t, *calls, "a fresh validation should not contact the server")
+	})
+
+	t.Run("RevalidatesOnline", func(t *testing.T) {
+		calls := mockCheckOnline(t, true, nil)
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Time{}), ValidatedAt: time.Now().AddDate(0, 0, -3)})
+
+		valid, err := ValidateLicense()
 		require.NoError(t, err)
+		assert.True(t, valid)
+		assert.Equal(t, 1, *calls)
 
-		// Override verify signature for testing
-		origVerifySignature := verifySignature
-		defer func() { verifySignature = origVerifySignature }()
-		verifySignature = func(lic License) bool {
-			return true
-		}
+		saved, err := LoadLicense()
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), saved.ValidatedAt, time.Minute)
+	})
+
+	t.Run("OfflineWithinGracePeriod", func(t *testing.T) {
+		mockCheckOnline(t, false, errors.New("no network"))
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Time{}), ValidatedAt: time.Now().AddDate(0, 0, -13)})
 
-		// Validate the license
 		valid, err := ValidateLicense()
 		require.NoError(t, err)
 		assert.True(t, valid)
 	})
 
+	t.Run("OfflinePastGracePeriod", func(t *testing.T) {
+		mockCheckOnline(t, false, errors.New("no network"))
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Time{}), ValidatedAt: time.Now().AddDate(0, 0, -15)})
+
+		valid, err := ValidateLicense()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not be verified for 14 days")
+		assert.False(t, valid)
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		mockCheckOnline(t, false, nil)
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Time{}), ValidatedAt: time.Now().AddDate(0, 0, -2)})
+
+		valid, err := ValidateLicense()
+		assert.ErrorIs(t, err, ErrRevoked)
+		assert.False(t, valid)
+	})
+
 	t.Run("ExpiredLicense", func(t *testing.T) {
-		// Create an expired license
-		license := License{
-			LicenseKey:   "expired-key",
-			Email:        "test@example.com",
-			PurchaseDate: time.Now().AddDate(-2, 0, 0), // 2 years ago
-			ExpiryDate:   time.Now().AddDate(-1, 0, 0), // Expired 1 year ago
-			Signature:    "valid-signature",
-		}
-
-		// Save license to file
-		licenseFile := filepath.Join(tempDir, "license.json")
-		licenseData, err := json.MarshalIndent(license, "", "  ")
-		require.NoError(t, err)
-		err = os.WriteFile(licenseFile, licenseData, 0644)
-		require.NoError(t, err)
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, priv, time.Now().AddDate(-1, 0, 0)), ValidatedAt: time.Now()})
 
-		// Validate the license
 		valid, err := ValidateLicense()
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "license expired")
@@ -88,93 +157,52 @@ func TestValidateLicense(t *testing.T) {
 	})
 
 	t.Run("InvalidSignature", func(t *testing.T) {
-		// Create a license with invalid signature
-		license := License{
-			LicenseKey:   "invalid-sig-key",
-			Email:        "test@example.com",
-			PurchaseDate: time.Now(),
-			ExpiryDate:   time.Now().AddDate(1, 0, 0), // Valid for 1 year
-			Signature:    "invalid-signature",
-		}
-
-		// Save license to file
-		licenseFile := filepath.Join(tempDir, "license.json")
-		licenseData, err := json.MarshalIndent(license, "", "  ")
-		require.NoError(t, err)
-		err = os.WriteFile(licenseFile, licenseData, 0644)
+		_, other, err := ed25519.GenerateKey(nil)
 		require.NoError(t, err)
+		writeLicense(t, tempDir, License{LicenseKey: signKey(t, other, time.Time{}), ValidatedAt: time.Now()})
 
-		// Override verify signature for testing
-		origVerifySignature := verifySignature
-		defer func() { verifySignature = origVerifySignature }()
-		verifySignature = func(lic License) bool {
-			return false
-		}
-
-		// Validate the license
 		valid, err := ValidateLicense()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid license signature")
+		assert.ErrorIs(t, err, ErrInvalidSignature)
 		assert.False(t, valid)
 	})
 
 	t.Run("CorruptLicenseFile", func(t *testing.T) {
-		// Create a corrupt license file
-		licenseFile := filepath.Join(tempDir, "license.json")
-		err = os.WriteFile(licenseFile, []byte("corrupt json"), 0644)
-		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "license.json"), []byte("corrupt json"), 0644))
 
-		// Validate the license
 		valid, err := ValidateLicense()
 		require.Error(t, err)
 		assert.False(t, valid)
 	})
 }
 
-func TestRequestLicense(t *testing.T) {
-	// Create a temporary test directory
-	tempDir, err := os.MkdirTemp("", "algo-scales-test")
-	require.NoError(t, err)
-	defer os.RemoveAll(tempDir)
-
-	// Override config dir for testing
-	origGetConfigDir := getConfigDir
-	defer func() { getConfigDir = origGetConfigDir }()
-	getConfigDir = func() string {
-		return tempDir
-	}
-
-	// We can't easily test the interactive parts of RequestLicense
-	// that require user input, but we can test the file writing functionality
-	// by mocking the console input.
+func TestActivate(t *testing.T) {
+	priv := useTestKey(t)
+	useConfigDir(t)
 
-	// This would be a more complete implementation in a real test:
-	// - Mock os.Stdin to provide predetermined input
-	// - Capture os.Stdout to verify prompts
-	// - Test the creation and validation of the license file
+	t.Run("Offline", func(t *testing.T) {
+		mockCheckOnline(t, false, errors.New("no network"))
+		key := signKey(t, priv, time.Now().AddDate(1, 0, 0))
 
-	// For now, we'll just test the helper functions:
+		license, err := Activate("  " + key + "\n")
+		require.NoError(t, err)
+		assert.Equal(t, key, license.LicenseKey)
+		assert.Equal(t, "test@example.com", license.Email)
 
-	t.Run("GenerateSignature", func(t *testing.T) {
-		sig := generateSignature("test-key", "test@example.com")
-		assert.NotEmpty(t, sig)
-		assert.Contains(t, sig, "test")
+		status, err := GetStatus()
+		require.NoError(t, err)
+		assert.True(t, status.Valid)
+		assert.Equal(t, "lic-1", status.Claims.ID)
+		assert.WithinDuration(t, time.Now().Add(OfflineGracePeriod), status.GraceEndsAt, time.Minute)
 	})
-}
 
-func TestVerifySignature(t *testing.T) {
-	// For the MVP version, verifySignature always returns true if the signature exists
-	license := License{
-		LicenseKey:   "test-key",
-		Email:        "test@example.com",
-		PurchaseDate: time.Now(),
-		ExpiryDate:   time.Now().AddDate(1, 0, 0),
-		Signature:    "valid-signature",
-	}
-
-	assert.True(t, verifySignature(license))
+	t.Run("Revoked", func(t *testing.T) {
+		mockCheckOnline(t, false, nil)
+		_, err := Activate(signKey(t, priv, time.Time{}))
+		assert.ErrorIs(t, err, ErrRevoked)
+	})
 
-	// Test with empty signature
-	license.Signature = ""
-	assert.False(t, verifySignature(license))
+	t.Run("NotAKey", func(t *testing.T) {
+		_, err := Activate("LICENSE-test")
+		assert.Error(t, err)
+	})
 }
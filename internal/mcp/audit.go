@@ -0,0 +1,93 @@
+// Audit log of the files MCP file tools read
+
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one use of a file tool
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session,omitempty"`
+	Tool    string    `json:"tool"`
+	Path    string    `json:"path"`
+	Bytes   int       `json:"bytes,omitempty"`
+	// Denied is set when the path was refused, with the reason
+	Denied bool   `json:"denied,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AuditLog appends entries to a file, one JSON object per line
+type AuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuditLog creates an audit log writing to path. The file is created on
+// the first entry.
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Path returns the file the log is written to
+func (l *AuditLog) Path() string {
+	return l.path
+}
+
+// Record appends an entry
+func (l *AuditLog) Record(entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %v", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %v", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %v", err)
+	}
+	return nil
+}
+
+// ReadAudit reads the entries of an audit log, oldest first. A log that does
+// not exist yet has no entries; lines that cannot be parsed are skipped.
+func ReadAudit(path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %v", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,220 @@
+// File tools confined to a single directory
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxReadBytes bounds the files read_file returns
+const maxReadBytes = 1 << 20
+
+// FileAccess serves read-only file tools that can only reach files inside
+// one directory, such as a problem's workspace, and records every use in an
+// audit log
+type FileAccess struct {
+	root    string
+	session string
+	audit   *AuditLog
+}
+
+// NewFileAccess confines file tools to root. Uses are recorded in audit,
+// labelled with session; a nil audit log records nothing.
+func NewFileAccess(root, session string, audit *AuditLog) (*FileAccess, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %v", root, err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %v", root, err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %v", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid root %s: not a directory", root)
+	}
+	return &FileAccess{root: resolved, session: session, audit: audit}, nil
+}
+
+// Root returns the directory the tools are confined to
+func (f *FileAccess) Root() string {
+	return f.root
+}
+
+// Resolve turns a path given by a client, absolute or relative to the root,
+// into the file it names, refusing any that is outside the root. Symbolic
+// links are followed before checking, so a link cannot lead outside.
+func (f *FileAccess) Resolve(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.root, path)
+	}
+	path = filepath.Clean(path)
+	if !f.contains(path) {
+		return "", fmt.Errorf("%s is outside the workspace %s", name, f.root)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s does not exist", name)
+		}
+		return "", fmt.Errorf("cannot access %s: %v", name, err)
+	}
+	if !f.contains(resolved) {
+		return "", fmt.Errorf("%s links outside the workspace %s", name, f.root)
+	}
+	return resolved, nil
+}
+
+// contains reports whether a clean absolute path is the root or inside it
+func (f *FileAccess) contains(path string) bool {
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Tools returns read_file and list_directory, the tools of the file-system
+// MCP server they replace
+func (f *FileAccess) Tools() []Tool {
+	pathSchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path relative to the workspace, or an absolute path inside it",
+			},
+		},
+		"required": []string{"path"},
+	}
+	return []Tool{
+		{
+			Name:        "read_file",
+			Description: "Read a file in the current problem's workspace. Files outside the workspace cannot be read.",
+			InputSchema: pathSchema,
+			Handler:     f.readFile,
+		},
+		{
+			Name:        "list_directory",
+			Description: "List a directory in the current problem's workspace. Directories end with a slash.",
+			InputSchema: pathSchema,
+			Handler:     f.listDirectory,
+		},
+	}
+}
+
+// fileContent is the result of read_file
+type fileContent struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// directoryListing is the result of list_directory
+type directoryListing struct {
+	Path    string   `json:"path"`
+	Entries []string `json:"entries"`
+}
+
+func (f *FileAccess) readFile(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	name, err := pathArgument(args)
+	if err != nil {
+		return nil, err
+	}
+	path, err := f.Resolve(name)
+	if err != nil {
+		return nil, f.deny("read_file", name, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access %s: %v", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", name)
+	}
+	if info.Size() > maxReadBytes {
+		return nil, f.deny("read_file", name, fmt.Errorf("%s is larger than %d bytes", name, maxReadBytes))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", name, err)
+	}
+
+	if err := f.record(AuditEntry{Tool: "read_file", Path: path, Bytes: len(data)}); err != nil {
+		return nil, err
+	}
+	return fileContent{Path: path, Content: string(data)}, nil
+}
+
+func (f *FileAccess) listDirectory(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	name, err := pathArgument(args)
+	if err != nil {
+		return nil, err
+	}
+	path, err := f.Resolve(name)
+	if err != nil {
+		return nil, f.deny("list_directory", name, err)
+	}
+
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", name, err)
+	}
+	listing := directoryListing{Path: path, Entries: []string{}}
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			listing.Entries = append(listing.Entries, entry.Name()+"/")
+		} else {
+			listing.Entries = append(listing.Entries, entry.Name())
+		}
+	}
+
+	if err := f.record(AuditEntry{Tool: "list_directory", Path: path}); err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// deny records a refused request and returns its error
+func (f *FileAccess) deny(tool, name string, reason error) error {
+	f.record(AuditEntry{Tool: tool, Path: name, Denied: true, Reason: reason.Error()})
+	return reason
+}
+
+// record adds an entry to the audit log. Nothing is returned to the client
+// unless it was recorded, so the log never misses a read.
+func (f *FileAccess) record(entry AuditEntry) error {
+	if f.audit == nil {
+		return nil
+	}
+	entry.Time = time.Now()
+	entry.Session = f.session
+	return f.audit.Record(entry)
+}
+
+func pathArgument(args json.RawMessage) (string, error) {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return "", fmt.Errorf("invalid arguments: %v", err)
+	}
+	if params.Path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	return params.Path, nil
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWorkspace creates a workspace with a solution, next to a secret
+// file outside it
+func newTestWorkspace(t *testing.T) (root, secret string) {
+	t.Helper()
+	dir := t.TempDir()
+	root = filepath.Join(dir, "two_sum")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "solution.go"), []byte("package main\n"), 0644))
+	secret = filepath.Join(dir, "secret.txt")
+	require.NoError(t, os.WriteFile(secret, []byte("token"), 0644))
+	return root, secret
+}
+
+func TestFileAccessResolve(t *testing.T) {
+	root, secret := newTestWorkspace(t)
+	require.NoError(t, os.Symlink(secret, filepath.Join(root, "link.txt")))
+
+	access, err := NewFileAccess(root, "", nil)
+	require.NoError(t, err)
+
+	for _, name := range []string{"solution.go", "./notes/../solution.go", filepath.Join(root, "solution.go")} {
+		path, err := access.Resolve(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, filepath.Join(access.Root(), "solution.go"), path, name)
+	}
+
+	for name, reason := range map[string]string{
+		"../secret.txt": "outside the workspace",
+		secret:          "outside the workspace",
+		"/etc/passwd":   "outside the workspace",
+		"link.txt":      "links outside the workspace",
+		"missing.go":    "does not exist",
+		"":              "path is required",
+	} {
+		_, err := access.Resolve(name)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), reason, name)
+	}
+
+	_, err = NewFileAccess(filepath.Join(root, "solution.go"), "", nil)
+	assert.Error(t, err, "the root must be a directory")
+}
+
+func TestFileTools(t *testing.T) {
+	root, _ := newTestWorkspace(t)
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	access, err := NewFileAccess(root, "two_sum-1", NewAuditLog(auditPath))
+	require.NoError(t, err)
+
+	s := NewServer("algo-scales-files", "test")
+	for _, tool := range access.Tools() {
+		s.AddTool(tool)
+	}
+	responses := exchange(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"read_file","arguments":{"path":"solution.go"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_directory","arguments":{"path":"."}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"read_file","arguments":{"path":"../secret.txt"}}}`,
+	)
+	require.Len(t, responses, 3)
+
+	text := func(i int) (string, bool) {
+		result := responses[i]["result"].(map[string]interface{})
+		return result["content"].([]interface{})[0].(map[string]interface{})["text"].(string), result["isError"].(bool)
+	}
+
+	content, isError := text(0)
+	require.False(t, isError)
+	var file fileContent
+	require.NoError(t, json.Unmarshal([]byte(content), &file))
+	assert.Equal(t, "package main\n", file.Content)
+
+	content, isError = text(1)
+	require.False(t, isError)
+	var listing directoryListing
+	require.NoError(t, json.Unmarshal([]byte(content), &listing))
+	assert.Equal(t, []string{"notes/", "solution.go"}, listing.Entries)
+
+	content, isError = text(2)
+	assert.True(t, isError)
+	assert.Contains(t, content, "outside the workspace")
+	assert.NotContains(t, content, "token")
+
+	entries, err := ReadAudit(auditPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "read_file", entries[0].Tool)
+	assert.Equal(t, filepath.Join(access.Root(), "solution.go"), entries[0].Path)
+	assert.Equal(t, len("package main\n"), entries[0].Bytes)
+	assert.Equal(t, "two_sum-1", entries[0].Session)
+	assert.False(t, entries[0].Time.IsZero())
+	assert.Equal(t, "list_directory", entries[1].Tool)
+	assert.True(t, entries[2].Denied, "refused requests are logged too")
+	assert.Equal(t, "../secret.txt", entries[2].Path)
+
+	info, err := os.Stat(auditPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileToolsNeedAudit(t *testing.T) {
+	root, _ := newTestWorkspace(t)
+	// A log that cannot be written to, since its directory is a file
+	blocked := filepath.Join(root, "solution.go", "audit.log")
+	access, err := NewFileAccess(root, "", NewAuditLog(blocked))
+	require.NoError(t, err)
+
+	_, err = access.readFile(context.Background(), json.RawMessage(`{"path":"solution.go"}`))
+	assert.Error(t, err, "nothing is read without being logged")
+}
+
+func TestReadAuditMissing(t *testing.T) {
+	entries, err := ReadAudit(filepath.Join(t.TempDir(), "none.log"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
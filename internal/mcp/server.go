@@ -0,0 +1,219 @@
+// Package mcp serves tools over the Model Context Protocol: JSON-RPC 2.0
+// messages, one per line, on a pair of streams such as stdin and stdout.
+// Only the tools capability is implemented.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ProtocolVersion is the MCP revision the server implements
+const ProtocolVersion = "2024-11-05"
+
+// maxMessageBytes bounds a single message, which may carry a solution
+const maxMessageBytes = 4 << 20
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function MCP clients can call
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the tool's arguments
+	InputSchema map[string]interface{}
+	// Handler runs the tool. Its result is sent to the client as JSON; an
+	// error is reported as a failed tool call the client's model can read.
+	Handler func(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// Server answers MCP requests with its tools
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+	byName  map[string]Tool
+}
+
+// NewServer creates a server that introduces itself with name and version
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version, byName: make(map[string]Tool)}
+}
+
+// AddTool makes a tool available, replacing one with the same name
+func (s *Server) AddTool(tool Tool) {
+	for i := range s.tools {
+		if s.tools[i].Name == tool.Name {
+			s.tools[i] = tool
+			s.byName[tool.Name] = tool
+			return
+		}
+	}
+	s.tools = append(s.tools, tool)
+	s.byName[tool.Name] = tool
+}
+
+// request is an incoming JSON-RPC request or notification. Notifications
+// have no ID.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is an outgoing JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends or ctx
+// is cancelled. Requests are answered one at a time, in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+
+	// Encode writes each response as a single line
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := encoder.Encode(errorResponse(json.RawMessage("null"), codeParseError, "invalid JSON")); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(req.ID) == 0 {
+			// Notifications, such as notifications/initialized, need no answer
+			continue
+		}
+
+		resp := s.handle(ctx, req)
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers a request
+func (s *Server) handle(ctx context.Context, req request) response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "not a JSON-RPC 2.0 request")
+	}
+
+	switch req.Method {
+	case "initialize":
+		return resultResponse(req.ID, s.initialize())
+	case "ping":
+		return resultResponse(req.ID, struct{}{})
+	case "tools/list":
+		return resultResponse(req.ID, s.listTools())
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return errorResponse(req.ID, codeInvalidParams, "tools/call needs a tool name")
+		}
+		tool, ok := s.byName[params.Name]
+		if !ok {
+			return errorResponse(req.ID, codeInvalidParams, fmt.Sprintf("unknown tool %q", params.Name))
+		}
+		return resultResponse(req.ID, callTool(ctx, tool, params.Arguments))
+	default:
+		return errorResponse(req.ID, codeMethodNotFound, fmt.Sprintf("method %q is not supported", req.Method))
+	}
+}
+
+// initialize describes the server and the protocol version it speaks
+func (s *Server) initialize() map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{},
+		},
+		"serverInfo": map[string]string{
+			"name":    s.name,
+			"version": s.version,
+		},
+	}
+}
+
+// listTools describes the server's tools
+func (s *Server) listTools() map[string]interface{} {
+	tools := make([]map[string]interface{}, 0, len(s.tools))
+	for _, tool := range s.tools {
+		schema := tool.InputSchema
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		tools = append(tools, map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": schema,
+		})
+	}
+	return map[string]interface{}{"tools": tools}
+}
+
+// callTool runs a tool, reporting its result or error as text content
+func callTool(ctx context.Context, tool Tool, args json.RawMessage) map[string]interface{} {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	result, err := tool.Handler(ctx, args)
+	if err != nil {
+		return toolResult(err.Error(), true)
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolResult(fmt.Sprintf("failed to encode result: %v", err), true)
+	}
+	return toolResult(string(text), false)
+}
+
+// toolResult is the result of a tool call
+func toolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func resultResponse(id json.RawMessage, result interface{}) response {
+	return response{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	return response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
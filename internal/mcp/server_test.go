@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exchange sends each message to a server on its own line and returns the
+// responses
+func exchange(t *testing.T, s *Server, messages ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, s.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+
+	var responses []map[string]interface{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp map[string]interface{}
+		require.NoError(t, decoder.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func newTestServer() *Server {
+	s := NewServer("algo-scales", "test")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echoes its message",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+		},
+		Handler: func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			var params struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+			if params.Message == "" {
+				return nil, errors.New("message is required")
+			}
+			return map[string]string{"message": params.Message}, nil
+		},
+	})
+	return s
+}
+
+func TestInitialize(t *testing.T) {
+	responses := exchange(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	)
+
+	// The notification gets no response
+	require.Len(t, responses, 2)
+	assert.Equal(t, float64(1), responses[0]["id"])
+	result := responses[0]["result"].(map[string]interface{})
+	assert.Equal(t, ProtocolVersion, result["protocolVersion"])
+	assert.Contains(t, result["capabilities"], "tools")
+	assert.Equal(t, "algo-scales", result["serverInfo"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(2), responses[1]["id"])
+}
+
+func TestListTools(t *testing.T) {
+	s := newTestServer()
+	s.AddTool(Tool{Name: "other", Handler: func(context.Context, json.RawMessage) (interface{}, error) { return nil, nil }})
+	// Adding a tool again replaces it in place
+	s.AddTool(Tool{Name: "echo", Description: "Echoes again", Handler: s.byName["echo"].Handler})
+
+	responses := exchange(t, s, `{"jsonrpc":"2.0","id":"list","method":"tools/list"}`)
+	require.Len(t, responses, 1)
+	tools := responses[0]["result"].(map[string]interface{})["tools"].([]interface{})
+	require.Len(t, tools, 2)
+
+	echo := tools[0].(map[string]interface{})
+	assert.Equal(t, "echo", echo["name"])
+	assert.Equal(t, "Echoes again", echo["description"])
+	// Tools without a schema still describe an object
+	assert.Equal(t, map[string]interface{}{"type": "object"}, tools[1].(map[string]interface{})["inputSchema"])
+}
+
+func TestCallTool(t *testing.T) {
+	responses := exchange(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"message":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	require.Len(t, responses, 3)
+
+	result := responses[0]["result"].(map[string]interface{})
+	assert.Equal(t, false, result["isError"])
+	content := result["content"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "text", content["type"])
+	assert.JSONEq(t, `{"message":"hi"}`, content["text"].(string))
+
+	// Tool errors are results the client's model can read
+	result = responses[1]["result"].(map[string]interface{})
+	assert.Equal(t, true, result["isError"])
+	assert.Equal(t, "message is required", result["content"].([]interface{})[0].(map[string]interface{})["text"])
+
+	// Unknown tools are protocol errors
+	assert.Equal(t, float64(codeInvalidParams), responses[2]["error"].(map[string]interface{})["code"])
+}
+
+func TestProtocolErrors(t *testing.T) {
+	responses := exchange(t, newTestServer(),
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"id":2,"method":"ping"}`,
+	)
+	require.Len(t, responses, 3)
+	assert.Equal(t, float64(codeParseError), responses[0]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(codeMethodNotFound), responses[1]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(codeInvalidRequest), responses[2]["error"].(map[string]interface{})["code"])
+}
@@ -0,0 +1,351 @@
+// Package notebook renders problems, solutions and practice history as a
+// Markdown study notebook for offline review
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Options selects what goes into a notebook
+type Options struct {
+	Pattern       string // Only problems of this pattern; empty for all
+	AttemptedOnly bool   // Leave out problems never attempted
+}
+
+// Entry is one problem with the user's history on it
+type Entry struct {
+	Problem  problem.Problem
+	Attempts []storage.Attempt         // Oldest first
+	Sessions []interfaces.SessionStats // Oldest first
+}
+
+// Notebook is the content of a study notebook
+type Notebook struct {
+	Options   Options
+	Generated time.Time
+	Entries   []Entry
+}
+
+// Collect gathers the problems selected by opts together with their
+// attempts and sessions from repo
+func Collect(ctx context.Context, repo storage.Repository, opts Options) (*Notebook, error) {
+	problems, err := problem.ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problems: %v", err)
+	}
+	attempts, err := repo.LoadAttempts(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := repo.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Build(problems, attempts, sessions, opts), nil
+}
+
+// Build assembles a notebook from already loaded data. Problems are ordered
+// by pattern, then difficulty, then title.
+func Build(problems []problem.Problem, attempts []storage.Attempt, sessions []interfaces.SessionStats, opts Options) *Notebook {
+	attemptsByProblem := make(map[string][]storage.Attempt)
+	for _, a := range attempts {
+		attemptsByProblem[a.ProblemID] = append(attemptsByProblem[a.ProblemID], a)
+	}
+	sessionsByProblem := make(map[string][]interfaces.SessionStats)
+	for _, s := range sessions {
+		sessionsByProblem[s.ProblemID] = append(sessionsByProblem[s.ProblemID], s)
+	}
+
+	nb := &Notebook{Options: opts, Generated: time.Now()}
+	for _, p := range problems {
+		if opts.Pattern != "" && !hasPattern(p, opts.Pattern) {
+			continue
+		}
+		entry := Entry{
+			Problem:  p,
+			Attempts: attemptsByProblem[p.ID],
+			Sessions: sessionsByProblem[p.ID],
+		}
+		if opts.AttemptedOnly && len(entry.Attempts) == 0 && len(entry.Sessions) == 0 {
+			continue
+		}
+		nb.Entries = append(nb.Entries, entry)
+	}
+
+	sort.SliceStable(nb.Entries, func(i, j int) bool {
+		a, b := nb.Entries[i].Problem, nb.Entries[j].Problem
+		if pa, pb := primaryPattern(a, opts.Pattern), primaryPattern(b, opts.Pattern); pa != pb {
+			return pa < pb
+		}
+		if da, db := difficultyOrder(a.Difficulty), difficultyOrder(b.Difficulty); da != db {
+			return da < db
+		}
+		return a.Title < b.Title
+	})
+	return nb
+}
+
+// Markdown renders the notebook
+func (nb *Notebook) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("# Algo Scales Study Notebook\n\n")
+	scope := "All patterns"
+	if nb.Options.Pattern != "" {
+		scope = "Pattern: " + nb.Options.Pattern
+	}
+	fmt.Fprintf(&b, "_%s · %d problem(s) · generated %s_\n\n", scope, len(nb.Entries), nb.Generated.Format("2006-01-02"))
+
+	if len(nb.Entries) == 0 {
+		b.WriteString("No problems matched.\n")
+		return b.String()
+	}
+
+	// Table of contents, grouped by pattern
+	b.WriteString("## Contents\n\n")
+	lastPattern := ""
+	for _, e := range nb.Entries {
+		if pattern := primaryPattern(e.Problem, nb.Options.Pattern); pattern != lastPattern {
+			fmt.Fprintf(&b, "- **%s**\n", pattern)
+			lastPattern = pattern
+		}
+		fmt.Fprintf(&b, "  - [%s](#%s) (%s)%s\n", e.Problem.Title, anchor(e.Problem.Title), e.Problem.Difficulty, statusBadge(e))
+	}
+	b.WriteString("\n")
+
+	lastPattern = ""
+	for _, e := range nb.Entries {
+		if pattern := primaryPattern(e.Problem, nb.Options.Pattern); pattern != lastPattern {
+			fmt.Fprintf(&b, "---\n\n## Pattern: %s\n\n", pattern)
+			lastPattern = pattern
+		}
+		writeEntry(&b, e)
+	}
+	return b.String()
+}
+
+// writeEntry renders one problem
+func writeEntry(b *strings.Builder, e Entry) {
+	p := e.Problem
+	fmt.Fprintf(b, "### %s\n\n", p.Title)
+	fmt.Fprintf(b, "**Difficulty:** %s · **Patterns:** %s", p.Difficulty, strings.Join(p.Patterns, ", "))
+	if p.EstimatedTime > 0 {
+		fmt.Fprintf(b, " · **Estimated time:** %d min", p.EstimatedTime)
+	}
+	b.WriteString("\n\n")
+
+	if desc := strings.TrimSpace(p.Description); desc != "" {
+		b.WriteString(desc + "\n\n")
+	}
+	for i, ex := range p.Examples {
+		fmt.Fprintf(b, "**Example %d**\n\n```\nInput: %s\nOutput: %s\n```\n\n", i+1, ex.Input, ex.Output)
+		if ex.Explanation != "" {
+			b.WriteString(ex.Explanation + "\n\n")
+		}
+	}
+	if len(p.Constraints) > 0 {
+		b.WriteString("**Constraints**\n\n")
+		for _, c := range p.Constraints {
+			fmt.Fprintf(b, "- %s\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	if explanation := strings.TrimSpace(p.PatternExplanation); explanation != "" {
+		b.WriteString("#### Pattern Notes\n\n" + explanation + "\n\n")
+	}
+
+	b.WriteString("#### My Solution\n\n")
+	if a, ok := bestAttempt(e.Attempts); ok {
+		verdict := "failing"
+		if a.Passed {
+			verdict = "passing"
+		}
+		fmt.Fprintf(b, "_%s, %s, %s_\n\n", a.Language, verdict, a.Time.Format("2006-01-02 15:04"))
+		fmt.Fprintf(b, "```%s\n%s\n```\n\n", a.Language, strings.TrimRight(codefmt.Code(a.Language, a.Code), "\n"))
+	} else {
+		b.WriteString("_No submitted solution yet._\n\n")
+	}
+
+	b.WriteString("#### History\n\n")
+	if len(e.Attempts) == 0 && len(e.Sessions) == 0 {
+		b.WriteString("_Not attempted yet._\n\n")
+		return
+	}
+	if len(e.Sessions) > 0 {
+		solved := 0
+		var best time.Duration
+		for _, s := range e.Sessions {
+			if s.Solved {
+				solved++
+				if best == 0 || s.Duration < best {
+					best = s.Duration
+				}
+			}
+		}
+		fmt.Fprintf(b, "Sessions: %d, solved %d", len(e.Sessions), solved)
+		if best > 0 {
+			fmt.Fprintf(b, ", best time %s", formatDuration(best))
+		}
+		b.WriteString("\n\n")
+	}
+	if len(e.Attempts) > 0 {
+		b.WriteString("| Date | Language | Result | Tests passed |\n|---|---|---|---|\n")
+		for _, a := range e.Attempts {
+			result := "✗ fail"
+			if a.Passed {
+				result = "✓ pass"
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", a.Time.Format("2006-01-02 15:04"), a.Language, result, testsPassed(a))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// bestAttempt picks the solution to show: the latest passing attempt with
+// code, else the latest attempt with code
+func bestAttempt(attempts []storage.Attempt) (storage.Attempt, bool) {
+	var latest storage.Attempt
+	found := false
+	for i := len(attempts) - 1; i >= 0; i-- {
+		a := attempts[i]
+		if strings.TrimSpace(a.Code) == "" {
+			continue
+		}
+		if a.Passed {
+			return a, true
+		}
+		if !found {
+			latest, found = a, true
+		}
+	}
+	return latest, found
+}
+
+// statusBadge marks solved and attempted problems in the contents
+func statusBadge(e Entry) string {
+	for _, a := range e.Attempts {
+		if a.Passed {
+			return " ✓"
+		}
+	}
+	for _, s := range e.Sessions {
+		if s.Solved {
+			return " ✓"
+		}
+	}
+	if len(e.Attempts) > 0 || len(e.Sessions) > 0 {
+		return " …"
+	}
+	return ""
+}
+
+// testsPassed summarizes an attempt's per-test results
+func testsPassed(a storage.Attempt) string {
+	if len(a.Results) == 0 {
+		return "-"
+	}
+	passed := 0
+	for _, r := range a.Results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return fmt.Sprintf("%d/%d", passed, len(a.Results))
+}
+
+// primaryPattern is the pattern a problem is filed under: the selected
+// pattern if there is one, else its first
+func primaryPattern(p problem.Problem, selected string) string {
+	if selected != "" {
+		return selected
+	}
+	if len(p.Patterns) == 0 {
+		return "other"
+	}
+	return p.Patterns[0]
+}
+
+// hasPattern reports whether a problem is tagged with pattern
+func hasPattern(p problem.Problem, pattern string) bool {
+	for _, candidate := range p.Patterns {
+		if candidate == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// difficultyOrder sorts easy before medium before hard
+func difficultyOrder(difficulty string) int {
+	switch strings.ToLower(difficulty) {
+	case "easy":
+		return 0
+	case "medium":
+		return 1
+	case "hard":
+		return 2
+	}
+	return 3
+}
+
+// anchor turns a heading into the fragment Markdown renderers link it by
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// formatDuration formats a duration as mm:ss
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// PandocPath locates pandoc, which converts notebooks to PDF
+// Exported as variable for testing
+var PandocPath = func() (string, error) {
+	return exec.LookPath("pandoc")
+}
+
+// WritePDF converts Markdown to a PDF at path using pandoc
+func WritePDF(markdown, path string) error {
+	pandoc, err := PandocPath()
+	if err != nil {
+		return fmt.Errorf("PDF output needs pandoc installed (https://pandoc.org); export Markdown instead or install it")
+	}
+
+	tmp, err := os.CreateTemp("", "algo-scales-notebook-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(markdown); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	tmp.Close()
+
+	if out, err := exec.Command(pandoc, tmp.Name(), "--from", "gfm", "-o", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("pandoc failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package notebook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProblems = []problem.Problem{
+	{
+		ID:                 "max_window",
+		Title:              "Max Sum Window",
+		Difficulty:         "Medium",
+		Patterns:           []string{"sliding-window"},
+		EstimatedTime:      20,
+		Description:        "Find the largest sum of k consecutive elements.",
+		Examples:           []problem.Example{{Input: "[1,3,2], 2", Output: "5"}},
+		PatternExplanation: "Slide a fixed window, adding the new element and dropping the old.",
+	},
+	{ID: "longest_substring", Title: "Longest Substring", Difficulty: "Easy", Patterns: []string{"sliding-window", "hash-map"}},
+	{ID: "two_sum", Title: "Two Sum", Difficulty: "Easy", Patterns: []string{"hash-map"}},
+}
+
+func TestBuild(t *testing.T) {
+	attempts := []storage.Attempt{{ProblemID: "two_sum", Language: "go", Code: "package main"}}
+
+	nb := Build(testProblems, attempts, nil, Options{Pattern: "sliding-window"})
+	require.Len(t, nb.Entries, 2)
+	assert.Equal(t, "longest_substring", nb.Entries[0].Problem.ID, "easy before medium")
+	assert.Equal(t, "max_window", nb.Entries[1].Problem.ID)
+
+	nb = Build(testProblems, attempts, nil, Options{AttemptedOnly: true})
+	require.Len(t, nb.Entries, 1)
+	assert.Equal(t, "two_sum", nb.Entries[0].Problem.ID)
+	assert.Len(t, nb.Entries[0].Attempts, 1)
+}
+
+func TestMarkdown(t *testing.T) {
+	day := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	attempts := []storage.Attempt{
+		{ProblemID: "max_window", Language: "python", Time: day, Code: "def first(): pass",
+			Results: []storage.TestResult{{Number: 1, Passed: true}, {Number: 2}}},
+		{ProblemID: "max_window", Language: "python", Time: day.Add(time.Hour), Passed: true, Code: "def solved(): pass\n"},
+		{ProblemID: "max_window", Language: "python", Time: day.Add(2 * time.Hour), Code: "def later(): pass"},
+	}
+	sessions := []interfaces.SessionStats{
+		{ProblemID: "max_window", Solved: true, Duration: 12*time.Minute + 30*time.Second},
+		{ProblemID: "max_window"},
+	}
+
+	md := Build(testProblems, attempts, sessions, Options{Pattern: "sliding-window"}).Markdown()
+
+	assert.Contains(t, md, "_Pattern: sliding-window · 2 problem(s)")
+	assert.Contains(t, md, "  - [Max Sum Window](#max-sum-window) (Medium) ✓\n")
+	assert.Contains(t, md, "### Max Sum Window\n")
+	assert.Contains(t, md, "#### Pattern Notes\n\nSlide a fixed window")
+	assert.Contains(t, md, "Input: [1,3,2], 2\nOutput: 5")
+
+	// The latest passing solution is shown over later failing ones
+	assert.Contains(t, md, "```python\ndef solved(): pass\n```")
+	assert.NotContains(t, md, "def later")
+
+	assert.Contains(t, md, "Sessions: 2, solved 1, best time 12:30")
+	assert.Contains(t, md, "| 2026-05-04 10:00 | python | ✗ fail | 1/2 |")
+	assert.Contains(t, md, "| 2026-05-04 11:00 | python | ✓ pass | - |")
+
+	// Problems without history say so
+	assert.Contains(t, md, "_No submitted solution yet._")
+	assert.Contains(t, md, "_Not attempted yet._")
+}
+
+func TestWritePDFNeedsPandoc(t *testing.T) {
+	original := PandocPath
+	defer func() { PandocPath = original }()
+	PandocPath = func() (string, error) { return "", errors.New("not found") }
+
+	err := WritePDF("# Notes", t.TempDir()+"/notes.pdf")
+	assert.ErrorContains(t, err, "pandoc")
+}
@@ -0,0 +1,177 @@
+// Package notifications provides a persisted inbox of user-facing events
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Kind identifies what produced a notification
+type Kind string
+
+const (
+	// KindProblemUpdate is raised when a synced problem changed under the user
+	KindProblemUpdate Kind = "problem_update"
+	// KindAchievement is raised when an achievement is unlocked
+	KindAchievement Kind = "achievement"
+	// KindCohort is raised for cohort and assignment events
+	KindCohort Kind = "cohort"
+	// KindSync reports the outcome of a problem or progress sync
+	KindSync Kind = "sync"
+	// KindReview is raised for peer review exchange events
+	KindReview Kind = "review"
+	// KindHallOfFame is raised when a solution is published to a hall of fame
+	KindHallOfFame Kind = "hall_of_fame"
+	// KindGoal is raised when a practice goal is reached
+	KindGoal Kind = "goal"
+)
+
+// Icon returns the symbol shown next to a notification of this kind
+func (k Kind) Icon() string {
+	switch k {
+	case KindProblemUpdate:
+		return "📝"
+	case KindAchievement:
+		return "🏆"
+	case KindCohort:
+		return "👥"
+	case KindSync:
+		return "🔄"
+	case KindReview:
+		return "💬"
+	case KindHallOfFame:
+		return "🏅"
+	case KindGoal:
+		return "🎯"
+	default:
+		return "🔔"
+	}
+}
+
+// maxNotifications bounds the inbox so the file does not grow without limit
+const maxNotifications = 200
+
+// Notification is a single inbox entry
+type Notification struct {
+	ID        string    `json:"id"`
+	Kind      Kind      `json:"kind"`
+	Title     string    `json:"title"`
+	Details   []string  `json:"details,omitempty"`
+	ProblemID string    `json:"problem_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Read      bool      `json:"read"`
+}
+
+// Load returns all notifications, newest first
+func Load() ([]Notification, error) {
+	data, err := os.ReadFile(GetInboxPath())
+	if os.IsNotExist(err) {
+		return []Notification{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications: %v", err)
+	}
+
+	var inbox []Notification
+	if err := json.Unmarshal(data, &inbox); err != nil {
+		return nil, fmt.Errorf("failed to parse notifications: %v", err)
+	}
+	return inbox, nil
+}
+
+// Add prepends a notification to the inbox
+func Add(n Notification) error {
+	inbox, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	if n.ID == "" {
+		n.ID = fmt.Sprintf("%s-%d", n.Kind, n.CreatedAt.UnixNano())
+	}
+
+	inbox = append([]Notification{n}, inbox...)
+	if len(inbox) > maxNotifications {
+		inbox = inbox[:maxNotifications]
+	}
+	return save(inbox)
+}
+
+// UnreadCount returns the number of notifications not yet read
+func UnreadCount() (int, error) {
+	inbox, err := Load()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, n := range inbox {
+		if !n.Read {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkRead marks a single notification as read
+func MarkRead(id string) error {
+	inbox, err := Load()
+	if err != nil {
+		return err
+	}
+
+	for i := range inbox {
+		if inbox[i].ID == id {
+			if inbox[i].Read {
+				return nil
+			}
+			inbox[i].Read = true
+			return save(inbox)
+		}
+	}
+	return fmt.Errorf("notification not found: %s", id)
+}
+
+// MarkAllRead marks every notification as read
+func MarkAllRead() error {
+	inbox, err := Load()
+	if err != nil {
+		return err
+	}
+
+	for i := range inbox {
+		inbox[i].Read = true
+	}
+	return save(inbox)
+}
+
+// save writes the inbox to disk
+func save(inbox []Notification) error {
+	path := GetInboxPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(inbox, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifications: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notifications: %v", err)
+	}
+	return nil
+}
+
+// GetInboxPath returns the path of the inbox file
+// Exported as variable for testing
+var GetInboxPath = func() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".algo-scales", "notifications.json")
+}
@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestInbox(t *testing.T) {
+	original := GetInboxPath
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	GetInboxPath = func() string { return path }
+	t.Cleanup(func() { GetInboxPath = original })
+}
+
+func TestAddAndLoad(t *testing.T) {
+	setupTestInbox(t)
+
+	inbox, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, inbox)
+
+	require.NoError(t, Add(Notification{Kind: KindProblemUpdate, Title: "first", ProblemID: "two-sum"}))
+	require.NoError(t, Add(Notification{Kind: KindProblemUpdate, Title: "second", Details: []string{"test cases corrected"}}))
+
+	inbox, err = Load()
+	require.NoError(t, err)
+	require.Len(t, inbox, 2)
+
+	// Newest first, with generated IDs and timestamps
+	assert.Equal(t, "second", inbox[0].Title)
+	assert.Equal(t, []string{"test cases corrected"}, inbox[0].Details)
+	assert.NotEmpty(t, inbox[1].ID)
+	assert.False(t, inbox[1].CreatedAt.IsZero())
+}
+
+func TestInboxIsBounded(t *testing.T) {
+	setupTestInbox(t)
+
+	for i := 0; i < maxNotifications+5; i++ {
+		require.NoError(t, Add(Notification{Kind: KindProblemUpdate, Title: "update"}))
+	}
+
+	inbox, err := Load()
+	require.NoError(t, err)
+	assert.Len(t, inbox, maxNotifications)
+}
+
+func TestReadState(t *testing.T) {
+	setupTestInbox(t)
+
+	require.NoError(t, Add(Notification{ID: "a", Kind: KindAchievement, Title: "Streak Virtuoso"}))
+	require.NoError(t, Add(Notification{ID: "b", Kind: KindSync, Title: "Sync complete"}))
+
+	count, err := UnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, MarkRead("a"))
+	count, err = UnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.Error(t, MarkRead("missing"))
+
+	require.NoError(t, MarkAllRead())
+	count, err = UnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,172 @@
+// Package packcheck runs the reference solutions of a problem pack against
+// the pack's own test cases, so authors find broken problems before anyone
+// practices them
+package packcheck
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+// DefaultTimeout bounds a single solution's test run
+const DefaultTimeout = 30 * time.Second
+
+// Status is the outcome of checking one problem in one language
+type Status string
+
+const (
+	StatusPass       Status = "pass"        // The reference solution passes every test
+	StatusFail       Status = "fail"        // The reference solution fails tests
+	StatusError      Status = "error"       // The tests could not be run
+	StatusNoSolution Status = "no-solution" // There is no reference solution to run
+)
+
+// Mismatch is a test the reference solution fails
+type Mismatch struct {
+	Test     int // 1-based number of the test case
+	Input    string
+	Expected string
+	Actual   string
+	Failure  interfaces.FailureKind
+}
+
+// Result is the outcome for one problem's reference solution in one
+// language
+type Result struct {
+	ProblemID      string
+	Language       string // Empty if the problem has no code in any language
+	Status         Status
+	MissingStarter bool // There is a solution but no starter code
+	Mismatches     []Mismatch
+	TotalTests     int
+	Err            string // Why the tests could not be run
+}
+
+// OK reports whether the problem is ready in the language
+func (r Result) OK() bool {
+	return r.Status == StatusPass && !r.MissingStarter
+}
+
+// Options selects what to check
+type Options struct {
+	Language string        // Only this language; empty for all
+	Timeout  time.Duration // Per solution; zero uses DefaultTimeout
+}
+
+// runTests runs code against a problem's tests
+// Exported as variable for testing
+var runTests = execution.ExecuteTests
+
+// Languages returns the languages a problem has a reference solution or
+// starter code in, in order
+func Languages(p problem.Problem) []string {
+	seen := make(map[string]bool)
+	var languages []string
+	for _, code := range []map[string]string{p.Solutions, p.StarterCode} {
+		for lang, src := range code {
+			if strings.TrimSpace(src) != "" && !seen[lang] {
+				seen[lang] = true
+				languages = append(languages, lang)
+			}
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Check runs every reference solution in the pack against every one of its
+// problem's test cases, in the pack's order. report, if not nil, is called
+// as each result is ready.
+func Check(ctx context.Context, pack *problem.Pack, opts Options, report func(Result)) ([]Result, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var results []Result
+	add := func(r Result) {
+		if report != nil {
+			report(r)
+		}
+		results = append(results, r)
+	}
+	for _, p := range pack.Problems {
+		languages := Languages(p)
+		if opts.Language != "" {
+			languages = []string{opts.Language}
+		}
+		if len(languages) == 0 {
+			add(Result{ProblemID: p.ID, Status: StatusNoSolution, TotalTests: len(p.TestCases)})
+			continue
+		}
+		for _, lang := range languages {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			add(checkOne(ctx, p, lang, timeout))
+		}
+	}
+	return results, nil
+}
+
+// checkOne runs a problem's reference solution in one language
+func checkOne(ctx context.Context, p problem.Problem, language string, timeout time.Duration) Result {
+	result := Result{ProblemID: p.ID, Language: language, TotalTests: len(p.TestCases)}
+	code := p.Solutions[language]
+	if strings.TrimSpace(code) == "" {
+		result.Status = StatusNoSolution
+		return result
+	}
+	result.MissingStarter = strings.TrimSpace(p.StarterCode[language]) == ""
+
+	prob := toInterfaceProblem(p)
+	testResults, allPassed, err := runTests(ctx, &prob, code, language, timeout)
+	if err != nil {
+		result.Status = StatusError
+		result.Err = err.Error()
+		return result
+	}
+
+	result.Status = StatusPass
+	for i, r := range testResults {
+		if !r.Passed {
+			result.Mismatches = append(result.Mismatches, Mismatch{Test: i + 1, Input: r.Input, Expected: r.Expected, Actual: r.Actual, Failure: r.Failure})
+		}
+	}
+	if !allPassed || len(result.Mismatches) > 0 {
+		result.Status = StatusFail
+	}
+	return result
+}
+
+// toInterfaceProblem converts a problem for the test runners
+func toInterfaceProblem(p problem.Problem) interfaces.Problem {
+	testCases := make([]interfaces.TestCase, len(p.TestCases))
+	for i, tc := range p.TestCases {
+		testCases[i] = interfaces.TestCase{Input: tc.Input, Expected: tc.Expected, Tier: tc.Tier}
+	}
+
+	var pattern string
+	if len(p.Patterns) > 0 {
+		pattern = p.Patterns[0]
+	}
+
+	return interfaces.Problem{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Signature:   p.Signature,
+		Pattern:     pattern,
+		Difficulty:  p.Difficulty,
+		Companies:   p.Companies,
+		Tags:        p.Patterns,
+		TestCases:   testCases,
+		StarterCode: p.StarterCode,
+	}
+}
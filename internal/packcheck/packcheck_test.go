@@ -0,0 +1,78 @@
+package packcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguages(t *testing.T) {
+	p := problem.Problem{
+		Solutions:   map[string]string{"go": "func f() {}", "python": " "},
+		StarterCode: map[string]string{"java": "class Solution {}", "go": "func f() {}"},
+	}
+	assert.Equal(t, []string{"go", "java"}, Languages(p))
+	assert.Empty(t, Languages(problem.Problem{}))
+}
+
+func TestCheck(t *testing.T) {
+	cases := []problem.TestCase{{Input: "2", Expected: "4"}, {Input: "3", Expected: "9"}}
+	pack := &problem.Pack{Problems: []problem.Problem{
+		{
+			ID:          "square",
+			TestCases:   cases,
+			Solutions:   map[string]string{"go": "square", "python": "wrong"},
+			StarterCode: map[string]string{"go": "stub"},
+		},
+		{ID: "starter_only", TestCases: cases, StarterCode: map[string]string{"go": "stub"}},
+		{ID: "crashes", TestCases: cases, Solutions: map[string]string{"go": "crash"}, StarterCode: map[string]string{"go": "stub"}},
+		{ID: "empty", TestCases: cases},
+	}}
+
+	original := runTests
+	defer func() { runTests = original }()
+	runTests = func(ctx context.Context, p *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		assert.Equal(t, DefaultTimeout, timeout)
+		switch code {
+		case "crash":
+			return nil, false, errors.New("compile error")
+		case "wrong":
+			return []interfaces.TestResult{
+				{Input: "2", Expected: "4", Actual: "4", Passed: true},
+				{Input: "3", Expected: "9", Actual: "6", Failure: interfaces.FailureWrongAnswer},
+			}, false, nil
+		}
+		return []interfaces.TestResult{{Passed: true}, {Passed: true}}, true, nil
+	}
+
+	var reported []string
+	results, err := Check(context.Background(), pack, Options{}, func(r Result) { reported = append(reported, r.ProblemID+"/"+r.Language) })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"square/go", "square/python", "starter_only/go", "crashes/go", "empty/"}, reported)
+
+	assert.Equal(t, Result{ProblemID: "square", Language: "go", Status: StatusPass, TotalTests: 2}, results[0])
+	assert.True(t, results[0].OK())
+
+	python := results[1]
+	assert.Equal(t, StatusFail, python.Status)
+	assert.True(t, python.MissingStarter)
+	assert.Equal(t, []Mismatch{{Test: 2, Input: "3", Expected: "9", Actual: "6", Failure: interfaces.FailureWrongAnswer}}, python.Mismatches)
+
+	assert.Equal(t, StatusNoSolution, results[2].Status)
+	assert.Equal(t, StatusError, results[3].Status)
+	assert.Equal(t, "compile error", results[3].Err)
+	assert.Equal(t, StatusNoSolution, results[4].Status)
+	assert.False(t, results[4].OK())
+
+	results, err = Check(context.Background(), pack, Options{Language: "python"}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, StatusFail, results[0].Status)
+	assert.Equal(t, StatusNoSolution, results[1].Status)
+}
@@ -0,0 +1,125 @@
+// Problems the user has bookmarked to come back to
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Bookmark is a problem the user starred, such as one to redo before an
+// interview
+type Bookmark struct {
+	ProblemID    string    `json:"problem_id"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+	Note         string    `json:"note,omitempty"`
+}
+
+// bookmarksBackend is the user data backend the bookmarks are kept in
+// Exported as variable for testing
+var bookmarksBackend = storage.UserData
+
+// bookmarksName is the file the bookmarks are kept in
+const bookmarksName = "bookmarks.json"
+
+// LoadBookmarks returns the bookmarks, in the order they were added
+func LoadBookmarks() ([]Bookmark, error) {
+	backend, err := bookmarksBackend()
+	if err != nil {
+		return nil, err
+	}
+	data, err := backend.Get(context.Background(), bookmarksName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %v", err)
+	}
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to decode bookmarks: %v", err)
+	}
+	return bookmarks, nil
+}
+
+// IsBookmarked reports whether a problem is bookmarked. Bookmarks that
+// cannot be read count as none.
+func IsBookmarked(id string) bool {
+	bookmarks, _ := LoadBookmarks()
+	for _, b := range bookmarks {
+		if b.ProblemID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBookmark bookmarks a problem. It reports false if the problem was
+// already bookmarked, in which case a note given replaces the old one.
+func AddBookmark(id, note string) (bool, error) {
+	bookmarks, err := LoadBookmarks()
+	if err != nil {
+		return false, err
+	}
+	for i, b := range bookmarks {
+		if b.ProblemID == id {
+			if note == "" || note == b.Note {
+				return false, nil
+			}
+			bookmarks[i].Note = note
+			return false, saveBookmarks(bookmarks)
+		}
+	}
+	return true, saveBookmarks(append(bookmarks, Bookmark{ProblemID: id, BookmarkedAt: time.Now(), Note: note}))
+}
+
+// RemoveBookmark removes a problem's bookmark. It reports false if the
+// problem was not bookmarked.
+func RemoveBookmark(id string) (bool, error) {
+	bookmarks, err := LoadBookmarks()
+	if err != nil {
+		return false, err
+	}
+	for i, b := range bookmarks {
+		if b.ProblemID == id {
+			return true, saveBookmarks(append(bookmarks[:i], bookmarks[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+// ToggleBookmark bookmarks a problem, or removes its bookmark if it has
+// one, and reports whether it is bookmarked now
+func ToggleBookmark(id string) (bool, error) {
+	removed, err := RemoveBookmark(id)
+	if err != nil || removed {
+		return false, err
+	}
+	_, err = AddBookmark(id, "")
+	return err == nil, err
+}
+
+// saveBookmarks replaces the bookmarks
+func saveBookmarks(bookmarks []Bookmark) error {
+	backend, err := bookmarksBackend()
+	if err != nil {
+		return err
+	}
+	if bookmarks == nil {
+		bookmarks = []Bookmark{}
+	}
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %v", err)
+	}
+	if err := backend.Put(context.Background(), bookmarksName, data); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	original := bookmarksBackend
+	backend := storage.NewLocalBackend(t.TempDir())
+	bookmarksBackend = func() (storage.Backend, error) { return backend, nil }
+	t.Cleanup(func() { bookmarksBackend = original })
+
+	bookmarks, err := LoadBookmarks()
+	require.NoError(t, err)
+	assert.Empty(t, bookmarks)
+	assert.False(t, IsBookmarked("two_sum"))
+
+	added, err := AddBookmark("two_sum", "")
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = AddBookmark("lru_cache", "redo before the Google onsite")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// Bookmarking again only replaces the note
+	added, err = AddBookmark("two_sum", "")
+	require.NoError(t, err)
+	assert.False(t, added)
+	added, err = AddBookmark("two_sum", "use a hash map")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	bookmarks, err = LoadBookmarks()
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 2)
+	assert.Equal(t, "two_sum", bookmarks[0].ProblemID)
+	assert.Equal(t, "use a hash map", bookmarks[0].Note)
+	assert.False(t, bookmarks[0].BookmarkedAt.IsZero())
+	assert.Equal(t, "redo before the Google onsite", bookmarks[1].Note)
+	assert.True(t, IsBookmarked("lru_cache"))
+
+	removed, err := RemoveBookmark("two_sum")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = RemoveBookmark("two_sum")
+	require.NoError(t, err)
+	assert.False(t, removed, "not bookmarked")
+
+	starred, err := ToggleBookmark("lru_cache")
+	require.NoError(t, err)
+	assert.False(t, starred)
+	starred, err = ToggleBookmark("lru_cache")
+	require.NoError(t, err)
+	assert.True(t, starred)
+	assert.True(t, IsBookmarked("lru_cache"))
+}
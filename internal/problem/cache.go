@@ -0,0 +1,70 @@
+// Read-through cache for problems fetched from the server
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// remoteFetchTimeout bounds a cache miss so offline use is not held up
+const remoteFetchTimeout = 10 * time.Second
+
+// Fetcher retrieves a single problem by ID from the server. It returns an
+// error when offline, unlicensed or the server does not know the problem.
+type Fetcher func(ctx context.Context, id string) (*Problem, error)
+
+// RemoteFetcher is consulted when a problem is not in the local cache.
+// It is nil unless a server client has been registered.
+var RemoteFetcher Fetcher
+
+// fetchAndCache fetches a problem missing from problemsDir and stores it
+// there, so later lookups are served offline
+func fetchAndCache(ctx context.Context, fetch Fetcher, fs interfaces.FileSystem, problemsDir, id string) (*Problem, error) {
+	if fetch == nil {
+		return nil, ErrProblemNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteFetchTimeout)
+	defer cancel()
+
+	p, err := fetch(ctx, id)
+	if err != nil || p == nil || p.ID != id {
+		return nil, ErrProblemNotFound
+	}
+
+	if err := cacheProblem(fs, problemsDir, *p); err != nil {
+		return nil, fmt.Errorf("failed to cache problem %s: %v", id, err)
+	}
+	return p, nil
+}
+
+// cacheProblem writes a problem into each of its pattern directories,
+// matching the layout produced by a full download
+func cacheProblem(fs interfaces.FileSystem, problemsDir string, p Problem) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	patterns := p.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"uncategorized"}
+	}
+
+	for _, pattern := range patterns {
+		patternDir := filepath.Join(problemsDir, pattern)
+		if err := fs.MkdirAll(patternDir, 0755); err != nil {
+			return err
+		}
+		if err := fs.WriteFile(filepath.Join(patternDir, p.ID+".json"), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
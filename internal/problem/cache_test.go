@@ -0,0 +1,61 @@
+package problem
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryReadThroughCache(t *testing.T) {
+	fs := utils.NewMockFileSystem()
+	fs.MkdirAll("/home/mockuser/.algo-scales/problems", 0755)
+
+	calls := 0
+	online := true
+	fetch := func(ctx context.Context, id string) (*Problem, error) {
+		calls++
+		if !online {
+			return nil, errors.New("offline")
+		}
+		if id != "remote-problem" {
+			return nil, errors.New("unknown problem")
+		}
+		return &Problem{ID: id, Title: "Remote Problem", Difficulty: "medium", Patterns: []string{"graphs"}}, nil
+	}
+
+	repo := (&Repository{}).WithFileSystem(fs).WithFetcher(fetch)
+
+	t.Run("MissIsFetchedAndStored", func(t *testing.T) {
+		p, err := repo.GetByID(context.Background(), "remote-problem")
+		require.NoError(t, err)
+		assert.Equal(t, "Remote Problem", p.Title)
+		assert.Equal(t, 1, calls)
+		assert.Contains(t, fs.Files, "/home/mockuser/.algo-scales/problems/graphs/remote-problem.json")
+	})
+
+	t.Run("ServedOfflineAfterwards", func(t *testing.T) {
+		online = false
+		p, err := repo.GetByID(context.Background(), "remote-problem")
+		require.NoError(t, err)
+		assert.Equal(t, "Remote Problem", p.Title)
+		assert.Equal(t, 1, calls, "cached problem should not be fetched again")
+	})
+
+	t.Run("UnavailableProblem", func(t *testing.T) {
+		_, err := repo.GetByID(context.Background(), "missing")
+		assert.ErrorIs(t, err, ErrProblemNotFound)
+	})
+
+	t.Run("NoFetcher", func(t *testing.T) {
+		origFetcher := RemoteFetcher
+		defer func() { RemoteFetcher = origFetcher }()
+		RemoteFetcher = nil
+
+		_, err := (&Repository{}).WithFileSystem(fs).GetByID(context.Background(), "other")
+		assert.ErrorIs(t, err, ErrProblemNotFound)
+	})
+}
@@ -0,0 +1,51 @@
+package problem
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DescribeChanges returns human-readable changelog entries describing how a
+// problem definition changed between two revisions. It returns nil when the
+// revisions are equivalent.
+func DescribeChanges(old, updated Problem) []string {
+	var changes []string
+
+	switch {
+	case len(updated.TestCases) > len(old.TestCases):
+		changes = append(changes, fmt.Sprintf("%d test case(s) added", len(updated.TestCases)-len(old.TestCases)))
+	case len(updated.TestCases) < len(old.TestCases):
+		changes = append(changes, fmt.Sprintf("%d test case(s) removed", len(old.TestCases)-len(updated.TestCases)))
+	case old.TestSetHash() != updated.TestSetHash():
+		changes = append(changes, "test cases corrected")
+	}
+
+	if old.Title != updated.Title {
+		changes = append(changes, fmt.Sprintf("renamed to %q", updated.Title))
+	}
+	if old.Difficulty != updated.Difficulty {
+		changes = append(changes, fmt.Sprintf("difficulty changed from %s to %s", old.Difficulty, updated.Difficulty))
+	}
+	if old.Description != updated.Description {
+		changes = append(changes, "description reworded")
+	}
+	if !reflect.DeepEqual(old.Constraints, updated.Constraints) {
+		changes = append(changes, "constraints clarified")
+	}
+	if !reflect.DeepEqual(old.Examples, updated.Examples) {
+		changes = append(changes, "examples updated")
+	}
+	if !reflect.DeepEqual(old.Patterns, updated.Patterns) {
+		changes = append(changes, "pattern tags updated")
+	}
+	if !reflect.DeepEqual(old.StarterCode, updated.StarterCode) {
+		changes = append(changes, "starter code updated")
+	}
+	if !reflect.DeepEqual(old.Solutions, updated.Solutions) ||
+		old.PatternExplanation != updated.PatternExplanation ||
+		!reflect.DeepEqual(old.SolutionWalkthrough, updated.SolutionWalkthrough) {
+		changes = append(changes, "reference solution revised")
+	}
+
+	return changes
+}
@@ -0,0 +1,282 @@
+// Answers to standard clarifying questions from a problem's constraints
+
+package problem
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Clarification is a clarifying question and, if the problem's statement or
+// constraints settle it, the answer
+type Clarification struct {
+	Question string
+	Answer   string   // Empty if the problem does not settle the question
+	Evidence []string // The constraints or statement the answer is based on
+}
+
+// Answered reports whether the problem settles the question
+func (c Clarification) Answered() bool {
+	return c.Answer != ""
+}
+
+// clarifier answers one standard question
+type clarifier struct {
+	question string
+	keywords *regexp.Regexp // Matches free-form questions asking the same
+	answer   func(p Problem, bounds []Bound) (string, []string)
+}
+
+// clarifiers are the standard questions, in the order they are asked
+var clarifiers = []clarifier{
+	{"How large can the input be?", regexp.MustCompile(`(?i)how (?:large|big|long|many)\b.*\b(?:input|array|list|string|tree|graph|grid|elements?|nodes?|items?)\b|\bsize\b|\blength\b`), answerSize},
+	{"Can the input be empty?", regexp.MustCompile(`(?i)\bempty\b|\bno (?:elements|nodes|items)\b|\bnull\b|\bnil\b`), answerEmpty},
+	{"What range can the values take?", regexp.MustCompile(`(?i)\branges?\b|\bvalues?\b|\bnumbers?\b|\bintegers?\b|overflow|\bbounds?\b`), answerRange},
+	{"Can values be negative?", regexp.MustCompile(`(?i)negative`), answerNegatives},
+	{"Can there be duplicate values?", regexp.MustCompile(`(?i)duplicat|\bunique\b|\bdistinct\b|repeat`), answerDuplicates},
+	{"Is the input sorted?", regexp.MustCompile(`(?i)sorted|\border(?:ed)?\b`), answerSorted},
+	{"Is there always exactly one valid answer?", regexp.MustCompile(`(?i)always|guarantee|exactly one|multiple (?:answers|solutions)|no (?:answer|solution)|valid answer`), answerUnique},
+}
+
+// matchOrder is the order free-form questions are matched in: the narrower
+// questions first, so "Can values be negative?" is not taken for a question
+// about the range
+var matchOrder = []int{1, 3, 4, 5, 6, 2, 0}
+
+// ClarifyingQuestions returns the standard clarifying questions worth asking
+// before solving any problem
+func ClarifyingQuestions() []string {
+	questions := make([]string, len(clarifiers))
+	for i, c := range clarifiers {
+		questions[i] = c.question
+	}
+	return questions
+}
+
+// Clarify answers the standard clarifying questions from the problem's
+// statement and constraints
+func (p Problem) Clarify() []Clarification {
+	bounds := parseBounds(p.Constraints)
+	clarifications := make([]Clarification, len(clarifiers))
+	for i, c := range clarifiers {
+		answer, evidence := c.answer(p, bounds)
+		clarifications[i] = Clarification{Question: c.question, Answer: answer, Evidence: evidence}
+	}
+	return clarifications
+}
+
+// ClarifyQuestion answers a free-form clarifying question if it asks one of
+// the standard questions and the problem settles it
+func (p Problem) ClarifyQuestion(question string) Clarification {
+	for _, i := range matchOrder {
+		c := clarifiers[i]
+		if c.keywords.MatchString(question) {
+			answer, evidence := c.answer(p, parseBounds(p.Constraints))
+			return Clarification{Question: question, Answer: answer, Evidence: evidence}
+		}
+	}
+	return Clarification{Question: question}
+}
+
+// Bound is a constraint of the form Low <= Term <= High, e.g. the bound
+// 1 <= nums.length <= 10^4 on the size of nums
+type Bound struct {
+	Term, Low, High string
+	Size            bool   // The term is the size of the input, not a value in it
+	Text            string // The constraint the bound was read from
+}
+
+var (
+	boundSeparator = regexp.MustCompile(`\s*(?:<=|≤|<)\s*`)
+	nodeRange      = regexp.MustCompile(`(?i)\bnumber of (\w+)\b.*\brange \[\s*([^,\]]+?)\s*,\s*([^\]]+?)\s*\]`)
+	sizeTerm       = regexp.MustCompile(`\.length$|\.size\(\)$|\.len\(\)$|^len\(`)
+)
+
+// Bounds returns the numeric bounds the problem's constraints put on its
+// input
+func (p Problem) Bounds() []Bound {
+	return parseBounds(p.Constraints)
+}
+
+// parseBounds reads the numeric bounds from constraints such as
+// "1 <= nums.length <= 10^4" and "The number of nodes in the tree is in the
+// range [0, 2000]"
+func parseBounds(constraints []string) []Bound {
+	var bounds []Bound
+	for _, c := range constraints {
+		text := strings.TrimRight(strings.Trim(strings.TrimSpace(c), "`"), ".")
+		if m := nodeRange.FindStringSubmatch(text); m != nil {
+			bounds = append(bounds, Bound{Term: "the number of " + m[1], Low: m[2], High: m[3], Size: true, Text: text})
+			continue
+		}
+		parts := boundSeparator.Split(text, -1)
+		if len(parts) < 3 {
+			continue
+		}
+		low, high := parts[0], parts[len(parts)-1]
+		for _, terms := range parts[1 : len(parts)-1] {
+			// "1 <= m, n <= 300" bounds both m and n
+			for _, term := range strings.Split(terms, ",") {
+				term = strings.TrimSpace(term)
+				bounds = append(bounds, Bound{Term: term, Low: low, High: high, Size: sizeTerm.MatchString(term), Text: text})
+			}
+		}
+	}
+	return bounds
+}
+
+// answerSize describes the bounds on the input's size
+func answerSize(p Problem, bounds []Bound) (string, []string) {
+	var parts, evidence []string
+	for _, b := range bounds {
+		if b.Size {
+			parts = append(parts, fmt.Sprintf("%s is between %s and %s", b.Term, b.Low, b.High))
+			evidence = appendUnique(evidence, b.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return sentence(strings.Join(parts, "; ")), evidence
+}
+
+// answerEmpty tells from the size bounds whether the input can be empty
+func answerEmpty(p Problem, bounds []Bound) (string, []string) {
+	var nonEmpty []string
+	var evidence []string
+	for _, b := range bounds {
+		if !b.Size {
+			continue
+		}
+		if b.Low == "0" {
+			return sentence(fmt.Sprintf("Yes, %s can be 0", b.Term)), []string{b.Text}
+		}
+		nonEmpty = append(nonEmpty, fmt.Sprintf("%s is at least %s", b.Term, b.Low))
+		evidence = appendUnique(evidence, b.Text)
+	}
+	if len(nonEmpty) == 0 {
+		return "", nil
+	}
+	return sentence("No, " + strings.Join(nonEmpty, " and ")), evidence
+}
+
+// answerRange describes the bounds on the values in the input
+func answerRange(p Problem, bounds []Bound) (string, []string) {
+	var parts, evidence []string
+	for _, b := range bounds {
+		if !b.Size {
+			parts = append(parts, fmt.Sprintf("%s is between %s and %s", b.Term, b.Low, b.High))
+			evidence = appendUnique(evidence, b.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return sentence(strings.Join(parts, "; ")), evidence
+}
+
+// answerNegatives tells from the value bounds whether values can be negative
+func answerNegatives(p Problem, bounds []Bound) (string, []string) {
+	var negative, negativeEvidence, evidence []string
+	for _, b := range bounds {
+		if b.Size {
+			continue
+		}
+		evidence = appendUnique(evidence, b.Text)
+		if strings.HasPrefix(b.Low, "-") {
+			negative = append(negative, b.Term)
+			negativeEvidence = appendUnique(negativeEvidence, b.Text)
+		}
+	}
+	switch {
+	case len(negative) > 0:
+		return sentence("Yes, " + strings.Join(negative, " and ") + " can be negative"), negativeEvidence
+	case len(evidence) > 0:
+		return "No, every value has a lower bound of 0 or more.", evidence
+	}
+	return "", nil
+}
+
+var (
+	distinctPattern   = regexp.MustCompile(`(?i)\b(?:unique|distinct)\b|\bno (?:repeated|duplicate)`)
+	duplicatesPattern = regexp.MustCompile(`(?i)\b(?:may|can|might) (?:contain|have|include) duplicates?|duplicates? (?:are )?allowed`)
+	sortedPattern     = regexp.MustCompile(`(?i)\bsorted\b|\bascending\b|\bdescending\b|non-decreasing|non-increasing`)
+	uniqueAnswer      = regexp.MustCompile(`(?i)only one valid answer|exactly one (?:valid )?(?:solution|answer)|one solution exists|always (?:exists|has a solution)`)
+)
+
+// answerDuplicates looks for constraints saying values are distinct or may
+// repeat
+func answerDuplicates(p Problem, bounds []Bound) (string, []string) {
+	if text, ok := p.statement(duplicatesPattern); ok {
+		return "Yes, values may repeat.", []string{text}
+	}
+	if text, ok := p.statement(distinctPattern); ok {
+		return "No, the values are distinct.", []string{text}
+	}
+	return "", nil
+}
+
+// answerSorted looks for constraints saying the input is sorted
+func answerSorted(p Problem, bounds []Bound) (string, []string) {
+	if text, ok := p.statement(sortedPattern); ok {
+		return "Yes, the input is in sorted order.", []string{text}
+	}
+	return "", nil
+}
+
+// answerUnique looks for constraints guaranteeing exactly one answer
+func answerUnique(p Problem, bounds []Bound) (string, []string) {
+	if text, ok := p.statement(uniqueAnswer); ok {
+		return "Yes, exactly one valid answer exists.", []string{text}
+	}
+	return "", nil
+}
+
+// Sorted reports whether the problem says its input is sorted
+func (p Problem) Sorted() bool {
+	_, ok := p.statement(sortedPattern)
+	return ok
+}
+
+// Distinct reports whether the problem says its values are distinct
+func (p Problem) Distinct() bool {
+	if _, ok := p.statement(duplicatesPattern); ok {
+		return false
+	}
+	_, ok := p.statement(distinctPattern)
+	return ok
+}
+
+// statement returns the first constraint that pattern matches, or failing
+// that the first sentence of the description
+func (p Problem) statement(pattern *regexp.Regexp) (string, bool) {
+	for _, c := range p.Constraints {
+		if pattern.MatchString(c) {
+			return strings.TrimSpace(c), true
+		}
+	}
+	for _, s := range strings.SplitAfter(p.Description, ". ") {
+		if pattern.MatchString(s) {
+			return strings.TrimSpace(s), true
+		}
+	}
+	return "", false
+}
+
+// sentence ends text with a full stop. Answers are not capitalized, since
+// they often start with a variable name.
+func sentence(text string) string {
+	if text == "" {
+		return ""
+	}
+	return text + "."
+}
+
+// appendUnique appends s unless the list already ends with it
+func appendUnique(list []string, s string) []string {
+	if len(list) > 0 && list[len(list)-1] == s {
+		return list
+	}
+	return append(list, s)
+}
@@ -0,0 +1,96 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClarify(t *testing.T) {
+	p := Problem{
+		Description: "Given an array of integers nums and an integer target, return indices of the two numbers such that they add up to target.",
+		Constraints: []string{
+			"2 <= nums.length <= 10^4",
+			"-10^9 <= nums[i] <= 10^9",
+			"-10^9 <= target <= 10^9",
+			"Only one valid answer exists",
+		},
+	}
+
+	clarifications := p.Clarify()
+	require.Len(t, clarifications, len(ClarifyingQuestions()))
+	answers := make(map[string]Clarification)
+	for _, c := range clarifications {
+		answers[c.Question] = c
+	}
+
+	assert.Equal(t, "nums.length is between 2 and 10^4.", answers["How large can the input be?"].Answer)
+	assert.Equal(t, "No, nums.length is at least 2.", answers["Can the input be empty?"].Answer)
+	assert.Equal(t, []string{"2 <= nums.length <= 10^4"}, answers["Can the input be empty?"].Evidence)
+	assert.Equal(t, "nums[i] is between -10^9 and 10^9; target is between -10^9 and 10^9.", answers["What range can the values take?"].Answer)
+	assert.Equal(t, "Yes, nums[i] and target can be negative.", answers["Can values be negative?"].Answer)
+	assert.Equal(t, "Yes, exactly one valid answer exists.", answers["Is there always exactly one valid answer?"].Answer)
+
+	// Nothing says whether values repeat or arrive sorted
+	assert.False(t, answers["Can there be duplicate values?"].Answered())
+	assert.False(t, answers["Is the input sorted?"].Answered())
+}
+
+func TestClarifyStatements(t *testing.T) {
+	tree := Problem{Constraints: []string{"The number of nodes in the tree is in the range [0, 2000]", "0 <= Node.val <= 1000"}}
+	answers := tree.Clarify()
+	assert.Equal(t, "Yes, the number of nodes can be 0.", answers[1].Answer)
+	assert.Equal(t, "No, every value has a lower bound of 0 or more.", answers[3].Answer)
+
+	sorted := Problem{
+		Description: "You are given an array. The array is sorted in ascending order.",
+		Constraints: []string{"All values of nums are unique"},
+	}
+	answers = sorted.Clarify()
+	assert.Equal(t, "No, the values are distinct.", answers[4].Answer)
+	assert.Equal(t, []string{"All values of nums are unique"}, answers[4].Evidence)
+	assert.Equal(t, "Yes, the input is in sorted order.", answers[5].Answer)
+	assert.Equal(t, []string{"The array is sorted in ascending order."}, answers[5].Evidence)
+	assert.False(t, answers[0].Answered(), "there are no size bounds")
+}
+
+func TestClarifyQuestion(t *testing.T) {
+	p := Problem{Constraints: []string{"1 <= arr.length <= 10^5", "-10^4 <= arr[i] <= 10^4", "The array may contain duplicate elements"}}
+
+	tests := []struct {
+		question string
+		answer   string
+	}{
+		{"Can the values be negative?", "Yes, arr[i] can be negative."},
+		{"could the array be empty", "No, arr.length is at least 1."},
+		{"Are there duplicates?", "Yes, values may repeat."},
+		{"How big can the array get?", "arr.length is between 1 and 10^5."},
+		{"What is the range of the numbers?", "arr[i] is between -10^4 and 10^4."},
+		{"Is the array sorted?", ""},
+		{"Should I return a copy?", ""},
+	}
+	for _, tt := range tests {
+		c := p.ClarifyQuestion(tt.question)
+		assert.Equal(t, tt.question, c.Question)
+		assert.Equal(t, tt.answer, c.Answer, tt.question)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	p := Problem{
+		Description: "Given a sorted array of distinct integers.",
+		Constraints: []string{"1 <= m, n <= 300", "The number of nodes in the tree is in the range [0, 10^4]"},
+	}
+	assert.Equal(t, []Bound{
+		{Term: "m", Low: "1", High: "300", Text: "1 <= m, n <= 300"},
+		{Term: "n", Low: "1", High: "300", Text: "1 <= m, n <= 300"},
+		{Term: "the number of nodes", Low: "0", High: "10^4", Size: true, Text: "The number of nodes in the tree is in the range [0, 10^4]"},
+	}, p.Bounds())
+	assert.True(t, p.Sorted())
+	assert.True(t, p.Distinct())
+
+	repeated := Problem{Constraints: []string{"The array may contain duplicate elements", "All values are unique per row"}}
+	assert.False(t, repeated.Sorted())
+	assert.False(t, repeated.Distinct(), "values that may repeat are not distinct")
+}
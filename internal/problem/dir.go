@@ -0,0 +1,77 @@
+// Problem directories, which keep a problem's code in files of their own
+
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ProblemFile is the file a problem directory describes its problem in.
+// Its starter code and reference solutions sit next to it as
+// starter.<ext> and solution.<ext>, one per language.
+const ProblemFile = "problem.yaml"
+
+// CodeExtensions maps languages to the extension of their code files in a
+// problem directory
+var CodeExtensions = map[string]string{
+	"go":         "go",
+	"python":     "py",
+	"javascript": "js",
+	"typescript": "ts",
+	"java":       "java",
+	"cpp":        "cpp",
+	"rust":       "rs",
+}
+
+// StarterFile returns the path of a language's starter code in a problem
+// directory
+func StarterFile(dir, language string) string {
+	return filepath.Join(dir, "starter."+CodeExtensions[language])
+}
+
+// SolutionFile returns the path of a language's reference solution in a
+// problem directory
+func SolutionFile(dir, language string) string {
+	return filepath.Join(dir, "solution."+CodeExtensions[language])
+}
+
+// loadCodeFiles fills in the starter code and reference solutions kept in
+// files next to a problem directory's problem.yaml. Code written in
+// problem.yaml itself wins over a file.
+func loadCodeFiles(dir string, p *Problem) error {
+	languages := make([]string, 0, len(CodeExtensions))
+	for lang := range CodeExtensions {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		for _, file := range []struct {
+			path string
+			code *map[string]string
+		}{
+			{StarterFile(dir, lang), &p.StarterCode},
+			{SolutionFile(dir, lang), &p.Solutions},
+		} {
+			data, err := os.ReadFile(file.path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", filepath.Base(file.path), err)
+			}
+			if *file.code == nil {
+				*file.code = make(map[string]string)
+			}
+			if (*file.code)[lang] == "" {
+				(*file.code)[lang] = string(data)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Generators that problems ship for large inputs
+
+package problem
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GeneratorLanguages are the languages generator scripts can be written in
+var GeneratorLanguages = []string{"python", "javascript"}
+
+// InputGenerator describes how a problem's large inputs are generated. By
+// default they follow from its signature and constraints; a spec refines
+// that, and a script replaces it.
+type InputGenerator struct {
+	// Script prints one input in the format of the test cases. It is run
+	// with the size and a seed as arguments, and should print the same
+	// input for the same seed.
+	Script   string `json:"script,omitempty"`
+	Language string `json:"language,omitempty"` // Language of the script: python (default) or javascript
+
+	// Params refine how the values of parameters are generated, by name
+	Params map[string]ParamSpec `json:"params,omitempty"`
+}
+
+// ParamSpec refines how one parameter's values are generated
+type ParamSpec struct {
+	Min    *int64 `json:"min,omitempty"`    // Smallest value of the parameter or its elements
+	Max    *int64 `json:"max,omitempty"`    // Largest value of the parameter or its elements
+	Length *int64 `json:"length,omitempty"` // Fixed length, instead of one that grows with the size
+	Chars  string `json:"chars,omitempty"`  // Characters its strings are made of
+}
+
+// ScriptLanguage returns the language of the generator's script
+func (g *InputGenerator) ScriptLanguage() string {
+	if g.Language == "" {
+		return "python"
+	}
+	return strings.ToLower(g.Language)
+}
+
+// validateGenerator checks a generator against the problem's signature
+func validateGenerator(p Problem) []FieldError {
+	g := p.Generator
+	if g == nil {
+		return nil
+	}
+
+	var errs []FieldError
+	if g.Language != "" && !contains(GeneratorLanguages, g.ScriptLanguage()) {
+		errs = append(errs, FieldError{"generator", fmt.Sprintf("generator language %q must be %s", g.Language, strings.Join(GeneratorLanguages, " or "))})
+	}
+	if g.Script == "" && len(g.Params) == 0 {
+		errs = append(errs, FieldError{"generator", "generator needs a script or params"})
+	}
+
+	names := make([]string, 0, len(g.Params))
+	for name := range g.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := g.Params[name]
+		if !hasParam(p, name) {
+			errs = append(errs, FieldError{"generator", fmt.Sprintf("generator params: %q is not a parameter of the signature", name)})
+		}
+		if spec.Min != nil && spec.Max != nil && *spec.Min > *spec.Max {
+			errs = append(errs, FieldError{"generator", fmt.Sprintf("generator params: %s has min %d above max %d", name, *spec.Min, *spec.Max)})
+		}
+		if spec.Length != nil && *spec.Length < 0 {
+			errs = append(errs, FieldError{"generator", fmt.Sprintf("generator params: %s has a negative length", name)})
+		}
+	}
+	return errs
+}
+
+// hasParam reports whether the problem's signature has a parameter
+func hasParam(p Problem, name string) bool {
+	if p.Signature == nil {
+		return false
+	}
+	for _, param := range p.Signature.Params {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
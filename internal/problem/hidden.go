@@ -0,0 +1,124 @@
+// Problems the user has hidden from selection
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// HiddenProblem is a problem the user archived. Hidden problems are left
+// out of random and adaptive selection but can still be listed and started
+// by ID.
+type HiddenProblem struct {
+	ProblemID string    `json:"problem_id"`
+	HiddenAt  time.Time `json:"hidden_at"`
+}
+
+// hiddenBackend is the user data backend the hidden list is kept in
+// Exported as variable for testing
+var hiddenBackend = storage.UserData
+
+// hiddenName is the file the hidden list is kept in
+const hiddenName = "hidden.json"
+
+// LoadHidden returns the hidden problems, in the order they were hidden
+func LoadHidden() ([]HiddenProblem, error) {
+	backend, err := hiddenBackend()
+	if err != nil {
+		return nil, err
+	}
+	data, err := backend.Get(context.Background(), hiddenName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hidden problems: %v", err)
+	}
+	var hidden []HiddenProblem
+	if err := json.Unmarshal(data, &hidden); err != nil {
+		return nil, fmt.Errorf("failed to decode hidden problems: %v", err)
+	}
+	return hidden, nil
+}
+
+// HiddenIDs returns the IDs of the hidden problems. A list that cannot be
+// read hides nothing, rather than keeping selection from working.
+func HiddenIDs() map[string]bool {
+	hidden, _ := LoadHidden()
+	ids := make(map[string]bool, len(hidden))
+	for _, h := range hidden {
+		ids[h.ProblemID] = true
+	}
+	return ids
+}
+
+// Hide archives a problem. It reports false if the problem was already
+// hidden.
+func Hide(id string) (bool, error) {
+	hidden, err := LoadHidden()
+	if err != nil {
+		return false, err
+	}
+	for _, h := range hidden {
+		if h.ProblemID == id {
+			return false, nil
+		}
+	}
+	return true, saveHidden(append(hidden, HiddenProblem{ProblemID: id, HiddenAt: time.Now()}))
+}
+
+// Unhide returns a problem to selection. It reports false if the problem
+// was not hidden.
+func Unhide(id string) (bool, error) {
+	hidden, err := LoadHidden()
+	if err != nil {
+		return false, err
+	}
+	for i, h := range hidden {
+		if h.ProblemID == id {
+			return true, saveHidden(append(hidden[:i], hidden[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+// saveHidden replaces the hidden list
+func saveHidden(hidden []HiddenProblem) error {
+	backend, err := hiddenBackend()
+	if err != nil {
+		return err
+	}
+	if hidden == nil {
+		hidden = []HiddenProblem{}
+	}
+	data, err := json.MarshalIndent(hidden, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode hidden problems: %v", err)
+	}
+	if err := backend.Put(context.Background(), hiddenName, data); err != nil {
+		return fmt.Errorf("failed to write hidden problems: %v", err)
+	}
+	return nil
+}
+
+// Visible returns the problems that are not hidden
+func Visible(problems []Problem) []Problem {
+	hidden := HiddenIDs()
+	if len(hidden) == 0 {
+		return problems
+	}
+	visible := make([]Problem, 0, len(problems))
+	for _, p := range problems {
+		if !hidden[p.ID] {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
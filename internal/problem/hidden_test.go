@@ -0,0 +1,78 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHidden keeps the hidden list in a temporary directory for the test
+func stubHidden(t *testing.T) {
+	t.Helper()
+	original := hiddenBackend
+	backend := storage.NewLocalBackend(t.TempDir())
+	hiddenBackend = func() (storage.Backend, error) { return backend, nil }
+	t.Cleanup(func() { hiddenBackend = original })
+}
+
+func TestHidden(t *testing.T) {
+	stubHidden(t)
+
+	hidden, err := LoadHidden()
+	require.NoError(t, err)
+	assert.Empty(t, hidden)
+
+	changed, err := Hide("two_sum")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = Hide("two_sum")
+	require.NoError(t, err)
+	assert.False(t, changed, "already hidden")
+	_, err = Hide("3sum")
+	require.NoError(t, err)
+
+	hidden, err = LoadHidden()
+	require.NoError(t, err)
+	require.Len(t, hidden, 2)
+	assert.Equal(t, "two_sum", hidden[0].ProblemID)
+	assert.False(t, hidden[0].HiddenAt.IsZero())
+	assert.Equal(t, map[string]bool{"two_sum": true, "3sum": true}, HiddenIDs())
+
+	problems := []Problem{{ID: "two_sum"}, {ID: "3sum"}, {ID: "valid_parentheses"}}
+	assert.Equal(t, []Problem{{ID: "valid_parentheses"}}, Visible(problems))
+
+	changed, err = Unhide("two_sum")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = Unhide("two_sum")
+	require.NoError(t, err)
+	assert.False(t, changed, "not hidden")
+	assert.Equal(t, map[string]bool{"3sum": true}, HiddenIDs())
+}
+
+func TestRandomSkipsHidden(t *testing.T) {
+	stubHidden(t)
+	original := ListAll
+	t.Cleanup(func() { ListAll = original })
+	ListAll = func() ([]Problem, error) {
+		return []Problem{
+			{ID: "two_sum", Difficulty: "easy", Patterns: []string{"hash-map"}},
+			{ID: "group_anagrams", Difficulty: "medium", Patterns: []string{"hash-map"}},
+		}, nil
+	}
+	_, err := Hide("two_sum")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		p, err := GetRandomProblem()
+		require.NoError(t, err)
+		assert.Equal(t, "group_anagrams", p.ID)
+		p, err = GetRandomProblemByPattern("hash-map")
+		require.NoError(t, err)
+		assert.Equal(t, "group_anagrams", p.ID)
+	}
+	_, err = GetRandomProblemByDifficulty("easy")
+	assert.Error(t, err, "the only easy problem is hidden")
+}
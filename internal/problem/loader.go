@@ -3,13 +3,14 @@ package problem
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
 )
 
 // LoadLocalProblems loads problems from the local problems directory
@@ -19,6 +20,8 @@ func LoadLocalProblems() ([]Problem, error) {
 
 // LoadLocalProblemsWithContext loads problems from the local problems directory with context
 func LoadLocalProblemsWithContext(ctx context.Context) ([]Problem, error) {
+	defer profiling.Track("problem index")()
+
 	// Create a context with timeout for file operations
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -105,11 +108,12 @@ func LoadLocalProblemsWithContext(ctx context.Context) ([]Problem, error) {
 				return nil, fmt.Errorf("failed to read problem file %s: %v", problemPath, err)
 			}
 			
-			// Parse problem data
-			var problem Problem
-			err = json.Unmarshal(data, &problem)
+			// Parse problem data, skipping malformed files rather than
+			// failing the whole listing
+			problem, err := decodeProblemFile(problemPath, data)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse problem file %s: %v", problemPath, err)
+				skipInvalid(err)
+				continue
 			}
 			
 			// Skip if already processed
@@ -244,4 +248,9 @@ func GetLanguages(allProblems []Problem) []string {
 	sort.Strings(result)
 	
 	return result
-}
\ No newline at end of file
+}
+// PatternName returns the display name of a kebab-case pattern, such as
+// "Sliding Window" for "sliding-window"
+func PatternName(pattern string) string {
+	return convertPatternToDisplay(pattern)
+}
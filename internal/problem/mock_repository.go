@@ -237,6 +237,7 @@ func (m *MockRepository) convertToInterface(p Problem) interfaces.Problem {
 		testCases[i] = interfaces.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -244,6 +245,7 @@ func (m *MockRepository) convertToInterface(p Problem) interfaces.Problem {
 		ID:          p.ID,
 		Title:       p.Title,
 		Description: p.Description,
+		Signature:   p.Signature,
 		Pattern:     pattern,
 		Difficulty:  p.Difficulty,
 		Companies:   p.Companies,
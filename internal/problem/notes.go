@@ -0,0 +1,65 @@
+// Freeform Markdown notes users keep on problems
+
+package problem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// notesBackend is the user data backend notes are kept in
+// Exported as variable for testing
+var notesBackend = storage.UserData
+
+// notesName is the file a problem's notes are kept in
+func notesName(id string) string {
+	return "notes/" + id + ".md"
+}
+
+// LoadNotes returns the Markdown notes kept on a problem, or "" if it has
+// none
+func LoadNotes(id string) (string, error) {
+	backend, err := notesBackend()
+	if err != nil {
+		return "", err
+	}
+	data, err := backend.Get(context.Background(), notesName(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes: %v", err)
+	}
+	return string(data), nil
+}
+
+// SaveNotes replaces a problem's notes. Blank notes delete them.
+func SaveNotes(id, notes string) error {
+	backend, err := notesBackend()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(notes) == "" {
+		if err := backend.Delete(context.Background(), notesName(id)); err != nil {
+			return fmt.Errorf("failed to delete notes: %v", err)
+		}
+		return nil
+	}
+	if !strings.HasSuffix(notes, "\n") {
+		notes += "\n"
+	}
+	if err := backend.Put(context.Background(), notesName(id), []byte(notes)); err != nil {
+		return fmt.Errorf("failed to write notes: %v", err)
+	}
+	return nil
+}
+
+// NotesOutline is the starting point for a problem's first notes
+func NotesOutline(p Problem) string {
+	return fmt.Sprintf("# %s\n\n## Approach\n\n## Gotchas\n", p.Title)
+}
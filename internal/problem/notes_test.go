@@ -0,0 +1,32 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotes(t *testing.T) {
+	original := notesBackend
+	backend := storage.NewLocalBackend(t.TempDir())
+	notesBackend = func() (storage.Backend, error) { return backend, nil }
+	t.Cleanup(func() { notesBackend = original })
+
+	notes, err := LoadNotes("two_sum")
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	require.NoError(t, SaveNotes("two_sum", "## Approach\n\nOne pass with a hash map."))
+	notes, err = LoadNotes("two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, "## Approach\n\nOne pass with a hash map.\n", notes)
+
+	// Blank notes delete them
+	require.NoError(t, SaveNotes("two_sum", "  \n"))
+	notes, err = LoadNotes("two_sum")
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+	require.NoError(t, SaveNotes("lru_cache", ""), "deleting notes a problem never had")
+}
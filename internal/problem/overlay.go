@@ -0,0 +1,131 @@
+// Overlays of what users add to their copies of problems
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Overlay holds notes a user added to a problem. It is kept apart from the
+// problem file, so syncs can replace the file without losing the notes.
+type Overlay struct {
+	ProblemID string    `json:"problem_id"`
+	Notes     []string  `json:"notes"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// overlayBackend is the user data backend overlays are kept in
+// Exported as variable for testing
+var overlayBackend = storage.UserData
+
+// overlayName is the file a problem's overlay is kept in, outside the
+// problems directory
+func overlayName(id string) string {
+	return "overlays/" + id + ".json"
+}
+
+// LoadOverlay returns a problem's overlay, if it has one
+func LoadOverlay(id string) (*Overlay, bool) {
+	backend, err := overlayBackend()
+	if err != nil {
+		return nil, false
+	}
+	data, err := backend.Get(context.Background(), overlayName(id))
+	if err != nil {
+		return nil, false
+	}
+	var o Overlay
+	if err := json.Unmarshal(data, &o); err != nil || len(o.Notes) == 0 {
+		return nil, false
+	}
+	return &o, true
+}
+
+// AddNotes adds notes to a problem's overlay, skipping ones it already has
+func AddNotes(id string, notes []string) error {
+	o, ok := LoadOverlay(id)
+	if !ok {
+		o = &Overlay{ProblemID: id}
+	}
+	for _, note := range notes {
+		if !contains(o.Notes, note) {
+			o.Notes = append(o.Notes, note)
+		}
+	}
+	o.UpdatedAt = time.Now()
+
+	backend, err := overlayBackend()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode overlay: %v", err)
+	}
+	if err := backend.Put(context.Background(), overlayName(id), data); err != nil {
+		return fmt.Errorf("failed to write overlay: %v", err)
+	}
+	return nil
+}
+
+// Additions returns what a user's copy of a problem adds to another
+// revision of it: the test cases the revision lacks, and notes for its
+// missing constraints and examples and for a reworded description. Other
+// edits, such as to starter code, are not carried over.
+func Additions(local, upstream Problem) ([]TestCase, []string) {
+	var tests []TestCase
+	known := make(map[string]bool, len(upstream.TestCases))
+	for _, tc := range upstream.TestCases {
+		known[tc.Input+"\x00"+tc.Expected] = true
+	}
+	for _, tc := range local.TestCases {
+		if key := tc.Input + "\x00" + tc.Expected; !known[key] {
+			known[key] = true
+			tests = append(tests, tc)
+		}
+	}
+
+	var notes []string
+	for _, constraint := range local.Constraints {
+		if !contains(upstream.Constraints, constraint) {
+			notes = append(notes, "Constraint: "+constraint)
+		}
+	}
+	for _, example := range local.Examples {
+		if !containsExample(upstream.Examples, example) {
+			note := fmt.Sprintf("Example: %s => %s", example.Input, example.Output)
+			if example.Explanation != "" {
+				note += " (" + example.Explanation + ")"
+			}
+			notes = append(notes, note)
+		}
+	}
+	if description := strings.TrimSpace(local.Description); description != "" && description != strings.TrimSpace(upstream.Description) {
+		notes = append(notes, "Your description: "+description)
+	}
+	return tests, notes
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsExample(examples []Example, e Example) bool {
+	for _, example := range examples {
+		if example.Input == e.Input && example.Output == e.Output {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdditions(t *testing.T) {
+	upstream := Problem{
+		ID:          "two_sum",
+		Description: "Find two numbers that add up to target.",
+		Constraints: []string{"2 <= nums.length <= 10^4"},
+		Examples:    []Example{{Input: "[2,7,11,15], 9", Output: "[0,1]"}},
+		TestCases:   []TestCase{{Input: "[2,7,11,15], 9", Expected: "[0,1]"}},
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		tests, notes := Additions(upstream, upstream)
+		assert.Empty(t, tests)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("Added", func(t *testing.T) {
+		local := upstream
+		local.Description = "Find two numbers that add up to target. Watch out for duplicates!"
+		local.Constraints = append([]string{"Only one valid answer exists"}, upstream.Constraints...)
+		local.Examples = append(append([]Example{}, upstream.Examples...), Example{Input: "[3,3], 6", Output: "[0,1]", Explanation: "duplicates"})
+		local.TestCases = append(append([]TestCase{}, upstream.TestCases...),
+			TestCase{Input: "[3,3], 6", Expected: "[0,1]"},
+			TestCase{Input: "[3,3], 6", Expected: "[0,1]"})
+
+		tests, notes := Additions(local, upstream)
+		assert.Equal(t, []TestCase{{Input: "[3,3], 6", Expected: "[0,1]"}}, tests)
+		assert.Equal(t, []string{
+			"Constraint: Only one valid answer exists",
+			"Example: [3,3], 6 => [0,1] (duplicates)",
+			"Your description: Find two numbers that add up to target. Watch out for duplicates!",
+		}, notes)
+	})
+}
+
+func TestOverlay(t *testing.T) {
+	original := overlayBackend
+	defer func() { overlayBackend = original }()
+	backend := storage.NewLocalBackend(t.TempDir())
+	overlayBackend = func() (storage.Backend, error) { return backend, nil }
+
+	_, ok := LoadOverlay("two_sum")
+	assert.False(t, ok)
+
+	require.NoError(t, AddNotes("two_sum", []string{"Watch out for duplicates"}))
+	require.NoError(t, AddNotes("two_sum", []string{"Watch out for duplicates", "Try a hash map"}))
+
+	o, ok := LoadOverlay("two_sum")
+	require.True(t, ok)
+	assert.Equal(t, []string{"Watch out for duplicates", "Try a hash map"}, o.Notes)
+	assert.False(t, o.UpdatedAt.IsZero())
+}
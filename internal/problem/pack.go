@@ -0,0 +1,495 @@
+// Importing user-authored problem packs
+
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// packMarker is written into a pack's directory to record where it came
+// from. It has no .json suffix, so problem loaders skip it.
+const packMarker = ".pack"
+
+// maxPackDownload caps how much a pack URL may send
+const maxPackDownload = 10 << 20
+
+// packNamePattern is what pack names may look like, since they become
+// directory names
+var packNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// yamlLinePattern finds the line number in a YAML parser error
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// packHTTPClient downloads packs given by URL
+var packHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// PackIssue is a problem found while validating a pack
+type PackIssue struct {
+	File    string
+	Line    int // 0 if the issue is not tied to a line
+	Message string
+}
+
+// String formats the issue as file:line: message
+func (i PackIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.File, i.Message)
+}
+
+// PackValidationError reports every issue found in a pack
+type PackValidationError struct {
+	Issues []PackIssue
+}
+
+func (e *PackValidationError) Error() string {
+	return fmt.Sprintf("pack has %d validation error(s)", len(e.Issues))
+}
+
+// Pack is a set of problems read from a directory, file or URL
+type Pack struct {
+	Source   string
+	Problems []Problem
+}
+
+// packFile is one JSON or YAML file of a pack
+type packFile struct {
+	name string
+	data []byte
+	dir  string // Directory the file is in; empty for downloads
+}
+
+// LoadPack reads and validates the problems at source, which may be a
+// directory, a single JSON or YAML file, or an http(s) URL to one. A file
+// holds either one problem or a list of them, and a problem.yaml also takes
+// the code in the starter.* and solution.* files beside it. If any problem
+// is invalid, the returned error is a *PackValidationError listing every
+// issue.
+func LoadPack(source string) (*Pack, error) {
+	pack, issues, err := ReadPack(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, &PackValidationError{Issues: issues}
+	}
+	return pack, nil
+}
+
+// ReadPack reads the problems at source like LoadPack, but returns the
+// problems that are valid along with the issues found in the rest
+func ReadPack(source string) (*Pack, []PackIssue, error) {
+	files, err := readPackFiles(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no JSON or YAML problem files found in %s", source)
+	}
+
+	pack := &Pack{Source: source}
+	var issues []PackIssue
+	seen := make(map[string]string)
+	for _, file := range files {
+		problems, fileIssues := parsePackFile(file.name, file.data)
+		issues = append(issues, fileIssues...)
+		for _, p := range problems {
+			if file.dir != "" && filepath.Base(file.name) == ProblemFile {
+				if err := loadCodeFiles(file.dir, &p.problem); err != nil {
+					issues = append(issues, PackIssue{File: file.name, Message: err.Error()})
+					continue
+				}
+			}
+			if first, ok := seen[p.problem.ID]; ok {
+				issues = append(issues, PackIssue{File: file.name, Line: p.line, Message: fmt.Sprintf("duplicate problem id %q, also defined in %s", p.problem.ID, first)})
+				continue
+			}
+			seen[p.problem.ID] = file.name
+			pack.Problems = append(pack.Problems, p.problem)
+		}
+	}
+	return pack, issues, nil
+}
+
+// readPackFiles collects the problem files at source
+func readPackFiles(source string) ([]packFile, error) {
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		data, err := downloadPack(source)
+		if err != nil {
+			return nil, err
+		}
+		return []packFile{{name: path.Base(u.Path), data: data}}, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack: %v", err)
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pack: %v", err)
+		}
+		return []packFile{{name: filepath.Base(source), data: data, dir: filepath.Dir(source)}}, nil
+	}
+
+	var files []packFile
+	err = filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isPackFile(p) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(source, p)
+		files = append(files, packFile{name: rel, data: data, dir: filepath.Dir(p)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack: %v", err)
+	}
+	return files, nil
+}
+
+// isPackFile reports whether a file may hold problems
+func isPackFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// downloadPack fetches a pack file
+func downloadPack(source string) ([]byte, error) {
+	resp, err := packHTTPClient.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download pack: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download pack: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPackDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download pack: %v", err)
+	}
+	if len(data) > maxPackDownload {
+		return nil, fmt.Errorf("pack is larger than %d MB", maxPackDownload>>20)
+	}
+	return data, nil
+}
+
+// parsedProblem is a problem along with the line it starts on
+type parsedProblem struct {
+	problem Problem
+	line    int
+}
+
+// parsePackFile parses and validates the problems in one file. YAML is a
+// superset of JSON, so both are read through the YAML parser, which knows
+// the line of every key.
+func parsePackFile(name string, data []byte) ([]parsedProblem, []PackIssue) {
+	// JSON files get JSON's stricter syntax check first
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			line := 0
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line = lineAt(data, syntaxErr.Offset)
+			}
+			return nil, []PackIssue{{File: name, Line: line, Message: err.Error()}}
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		line := 0
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return nil, []PackIssue{{File: name, Line: line, Message: strings.TrimPrefix(err.Error(), "yaml: ")}}
+	}
+	if len(root.Content) == 0 {
+		return nil, []PackIssue{{File: name, Message: "file is empty"}}
+	}
+
+	doc := root.Content[0]
+	var nodes []*yaml.Node
+	switch doc.Kind {
+	case yaml.MappingNode:
+		nodes = []*yaml.Node{doc}
+	case yaml.SequenceNode:
+		nodes = doc.Content
+	default:
+		return nil, []PackIssue{{File: name, Line: doc.Line, Message: "expected a problem or a list of problems"}}
+	}
+
+	var problems []parsedProblem
+	var issues []PackIssue
+	for _, node := range nodes {
+		p, nodeIssues := decodeProblem(node)
+		for _, issue := range nodeIssues {
+			issue.File = name
+			issues = append(issues, issue)
+		}
+		if len(nodeIssues) == 0 {
+			problems = append(problems, parsedProblem{problem: p, line: node.Line})
+		}
+	}
+	return problems, issues
+}
+
+// decodeProblem decodes and validates one problem node
+func decodeProblem(node *yaml.Node) (Problem, []PackIssue) {
+	var p Problem
+	if node.Kind != yaml.MappingNode {
+		return p, []PackIssue{{Line: node.Line, Message: "expected a problem object"}}
+	}
+
+	// Problem only has JSON tags, so decode generically and go through JSON
+	var generic any
+	if err := node.Decode(&generic); err != nil {
+		return p, []PackIssue{{Line: node.Line, Message: err.Error()}}
+	}
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return p, []PackIssue{{Line: node.Line, Message: err.Error()}}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return p, []PackIssue{decodeIssue(node, err)}
+	}
+
+	var issues []PackIssue
+	for _, fieldErr := range Validate(p) {
+		issues = append(issues, PackIssue{Line: keyLine(node, fieldErr.Field), Message: fieldErr.Message})
+	}
+	return p, issues
+}
+
+// decodeIssue points a JSON decoding error at the key it is about
+func decodeIssue(node *yaml.Node, err error) PackIssue {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := strings.SplitN(typeErr.Field, ".", 2)[0]
+		return PackIssue{Line: keyLine(node, field), Message: fmt.Sprintf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)}
+	}
+
+	// encoding/json reports unknown fields only in its message
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field, _ = strconv.Unquote(field)
+		return PackIssue{Line: keyLine(node, field), Message: fmt.Sprintf("unknown field %q", field)}
+	}
+	return PackIssue{Line: node.Line, Message: err.Error()}
+}
+
+// keyLine returns the line of a key in a mapping, or of the mapping itself
+// if the key is missing
+func keyLine(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i].Line
+		}
+	}
+	return node.Line
+}
+
+// FieldError is a validation failure of one field of a problem. Field is
+// the field's JSON name.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Validate checks the fields every problem needs
+func Validate(p Problem) []FieldError {
+	var errs []FieldError
+	required := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, FieldError{field, field + " is required"})
+		}
+	}
+
+	required("id", p.ID)
+	if p.ID != "" && !packNamePattern.MatchString(p.ID) {
+		errs = append(errs, FieldError{"id", fmt.Sprintf("id %q may only contain lowercase letters, digits, '-' and '_'", p.ID)})
+	}
+	required("title", p.Title)
+	required("description", p.Description)
+
+	switch strings.ToLower(p.Difficulty) {
+	case "easy", "medium", "hard":
+	case "":
+		errs = append(errs, FieldError{"difficulty", "difficulty is required"})
+	default:
+		errs = append(errs, FieldError{"difficulty", fmt.Sprintf("difficulty %q must be easy, medium or hard", p.Difficulty)})
+	}
+
+	if len(p.Patterns) == 0 {
+		errs = append(errs, FieldError{"patterns", "at least one pattern is required"})
+	}
+	if p.EstimatedTime < 0 {
+		errs = append(errs, FieldError{"estimated_time", "estimated_time cannot be negative"})
+	}
+
+	if len(p.TestCases) == 0 {
+		errs = append(errs, FieldError{"test_cases", "at least one test case is required"})
+	}
+	for i, tc := range p.TestCases {
+		if strings.TrimSpace(tc.Input) == "" || strings.TrimSpace(tc.Expected) == "" {
+			errs = append(errs, FieldError{"test_cases", fmt.Sprintf("test case %d needs both input and expected", i+1)})
+		}
+		if !tc.Tier.Valid() {
+			errs = append(errs, FieldError{"test_cases", fmt.Sprintf("test case %d has unknown tier %q: use example, hidden, edge or stress", i+1, tc.Tier)})
+		}
+	}
+	return append(errs, validateGenerator(p)...)
+}
+
+// ValidPackName reports whether name can be used for a pack
+func ValidPackName(name string) bool {
+	return packNamePattern.MatchString(name)
+}
+
+// PackDir returns the directory a pack is installed to
+func PackDir(name string) string {
+	return filepath.Join(getConfigDir(), "problems", name)
+}
+
+// InstallPack writes a pack's problems into the local problem repository
+// under name, replacing an earlier import of the same pack. It refuses to
+// overwrite a directory that is not a pack, or to shadow problems installed
+// elsewhere.
+func InstallPack(name string, pack *Pack) (string, error) {
+	if !ValidPackName(name) {
+		return "", fmt.Errorf("invalid pack name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+
+	problemsDir := filepath.Join(getConfigDir(), "problems")
+	dir := PackDir(name)
+	if _, err := os.Stat(dir); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, packMarker)); err != nil {
+			return "", fmt.Errorf("%s already exists and is not an imported pack", dir)
+		}
+	}
+
+	if conflicts := conflictingProblems(problemsDir, name, pack.Problems); len(conflicts) > 0 {
+		return "", fmt.Errorf("problem ids already installed outside this pack: %s", strings.Join(conflicts, ", "))
+	}
+
+	// Stage the pack next to its destination so a failed write leaves any
+	// earlier import intact
+	if err := os.MkdirAll(problemsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create problems directory: %v", err)
+	}
+	staging, err := os.MkdirTemp(problemsDir, ".import-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	for _, p := range pack.Problems {
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode problem %s: %v", p.ID, err)
+		}
+		if err := os.WriteFile(filepath.Join(staging, p.ID+".json"), data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write problem %s: %v", p.ID, err)
+		}
+	}
+	marker := fmt.Sprintf("source: %s\nimported: %s\n", pack.Source, time.Now().Format(time.RFC3339))
+	if err := os.WriteFile(filepath.Join(staging, packMarker), []byte(marker), 0644); err != nil {
+		return "", fmt.Errorf("failed to write pack marker: %v", err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to replace pack: %v", err)
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		return "", fmt.Errorf("failed to install pack: %v", err)
+	}
+	return dir, nil
+}
+
+// AddToPack installs problems into the pack called name, keeping the
+// problems it already holds except those with the same IDs, which are
+// replaced. The pack is created if it does not exist yet.
+func AddToPack(name, source string, problems []Problem) (string, error) {
+	pack := &Pack{Source: source}
+	dir := PackDir(name)
+	if _, err := os.Stat(filepath.Join(dir, packMarker)); err == nil {
+		existing, err := LoadPack(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to read pack %s: %v", name, err)
+		}
+		replaced := make(map[string]bool, len(problems))
+		for _, p := range problems {
+			replaced[p.ID] = true
+		}
+		for _, p := range existing.Problems {
+			if !replaced[p.ID] {
+				pack.Problems = append(pack.Problems, p)
+			}
+		}
+	}
+	pack.Problems = append(pack.Problems, problems...)
+	return InstallPack(name, pack)
+}
+
+// conflictingProblems lists the pack's problem IDs that are already
+// installed in a directory other than the pack's own
+func conflictingProblems(problemsDir, name string, problems []Problem) []string {
+	dirs, err := os.ReadDir(problemsDir)
+	if err != nil {
+		return nil
+	}
+
+	var conflicts []string
+	for _, p := range problems {
+		for _, d := range dirs {
+			if !d.IsDir() || d.Name() == name {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(problemsDir, d.Name(), p.ID+".json")); err == nil {
+				conflicts = append(conflicts, p.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// lineAt returns the 1-based line of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
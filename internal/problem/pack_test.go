@@ -0,0 +1,289 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const packJSON = `{
+  "id": "pair_sum",
+  "title": "Pair Sum",
+  "difficulty": "easy",
+  "patterns": ["two-pointers"],
+  "estimated_time": 10,
+  "description": "Find a pair that sums to the target.",
+  "test_cases": [{"input": "[1,2,3], 5", "expected": "[1,2]"}]
+}`
+
+const packYAML = `- id: window_max
+  title: Window Max
+  difficulty: medium
+  patterns: [sliding-window]
+  description: Largest sum of k consecutive elements.
+  test_cases:
+    - input: "[1,3,2], 2"
+      expected: "5"
+`
+
+// withPackConfigDir points the problem repository at a temporary directory
+func withPackConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	original := getConfigDir
+	getConfigDir = func() string { return dir }
+	t.Cleanup(func() { getConfigDir = original })
+	return dir
+}
+
+// writePack writes files into a fresh pack directory
+func writePack(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+// packIssues loads a pack expected to be invalid and returns its issues
+func packIssues(t *testing.T, source string) []PackIssue {
+	t.Helper()
+	_, err := LoadPack(source)
+	var validationErr *PackValidationError
+	require.True(t, errors.As(err, &validationErr), "expected validation error, got %v", err)
+	return validationErr.Issues
+}
+
+func TestLoadPack(t *testing.T) {
+	dir := writePack(t, map[string]string{
+		"pair_sum.json":     packJSON,
+		"windows/more.yaml": packYAML,
+		"README.md":         "not a problem",
+	})
+
+	pack, err := LoadPack(dir)
+	require.NoError(t, err)
+	require.Len(t, pack.Problems, 2)
+	assert.Equal(t, "pair_sum", pack.Problems[0].ID)
+	assert.Equal(t, 10, pack.Problems[0].EstimatedTime)
+	assert.Equal(t, "window_max", pack.Problems[1].ID)
+	assert.Equal(t, []TestCase{{Input: "[1,3,2], 2", Expected: "5"}}, pack.Problems[1].TestCases)
+}
+
+func TestLoadPackReportsLines(t *testing.T) {
+	dir := writePack(t, map[string]string{
+		"bad.yaml": `- id: no_tests
+  title: No Tests
+  difficulty: extreme
+  patterns: [dfs]
+  description: Missing test cases.
+- id: typo
+  title: Typo
+  difficulty: easy
+  patterns: [bfs]
+  descripton: Misspelled field.
+- id: bad_tier
+  title: Bad Tier
+  difficulty: easy
+  patterns: [bfs]
+  description: Unknown tier.
+  test_cases:
+    - {input: "1", expected: "1", tier: secret}
+`,
+		"broken.json": "{\n  \"id\": \"x\",\n  \"title\": \n}",
+		"types.json":  "{\n  \"id\": \"x\",\n  \"estimated_time\": \"ten\"\n}",
+	})
+
+	issues := packIssues(t, dir)
+	assert.Contains(t, issues, PackIssue{File: "bad.yaml", Line: 3, Message: `difficulty "extreme" must be easy, medium or hard`})
+	assert.Contains(t, issues, PackIssue{File: "bad.yaml", Line: 1, Message: "at least one test case is required"})
+	assert.Contains(t, issues, PackIssue{File: "bad.yaml", Line: 10, Message: `unknown field "descripton"`})
+	assert.Contains(t, issues, PackIssue{File: "bad.yaml", Line: 16, Message: `test case 1 has unknown tier "secret": use example, hidden, edge or stress`})
+	assert.Contains(t, issues, PackIssue{File: "types.json", Line: 3, Message: "estimated_time: expected int, got string"})
+
+	var broken PackIssue
+	for _, issue := range issues {
+		if issue.File == "broken.json" {
+			broken = issue
+		}
+	}
+	assert.Equal(t, 4, broken.Line)
+	assert.Equal(t, "bad.yaml:3: difficulty \"extreme\" must be easy, medium or hard", issues[0].String())
+}
+
+func TestReadPack(t *testing.T) {
+	dir := writePack(t, map[string]string{
+		"pair_sum.json": packJSON,
+		"bad.yaml":      "id: bad\ntitle: Bad\n",
+	})
+
+	pack, issues, err := ReadPack(dir)
+	require.NoError(t, err)
+	require.Len(t, pack.Problems, 1, "the valid problems are kept")
+	assert.Equal(t, "pair_sum", pack.Problems[0].ID)
+	assert.NotEmpty(t, issues)
+	for _, issue := range issues {
+		assert.Equal(t, "bad.yaml", issue.File)
+	}
+}
+
+func TestLoadPackProblemDir(t *testing.T) {
+	problemYAML := strings.Replace(packYAML[2:], "\n  ", "\n", -1) + "starter_code:\n  python: inline\n"
+	dir := writePack(t, map[string]string{
+		"window_max/problem.yaml": problemYAML,
+		"window_max/starter.go":   "func windowMax() {}",
+		"window_max/starter.py":   "from file",
+		"window_max/solution.go":  "func windowMax() { /* solved */ }",
+		"window_max/notes.txt":    "not code",
+	})
+
+	pack, err := LoadPack(dir)
+	require.NoError(t, err)
+	require.Len(t, pack.Problems, 1)
+	p := pack.Problems[0]
+	assert.Equal(t, map[string]string{"go": "func windowMax() {}", "python": "inline"}, p.StarterCode, "code in problem.yaml wins")
+	assert.Equal(t, map[string]string{"go": "func windowMax() { /* solved */ }"}, p.Solutions)
+
+	pack, err = LoadPack(filepath.Join(dir, "window_max", "problem.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "func windowMax() {}", pack.Problems[0].StarterCode["go"], "a problem.yaml given alone reads its directory")
+}
+
+func TestLoadPackGenerator(t *testing.T) {
+	const generated = `id: pair_sum
+title: Pair Sum
+difficulty: easy
+patterns: [two-pointers]
+description: Find a pair that sums to the target.
+test_cases:
+  - {input: "[1,2,3], 5", expected: "[1,2]"}
+signature: {name: pairSum, params: [{name: nums, type: "int[]"}, {name: target, type: int}], returns: "int[]"}
+generator:
+  script: |
+    import sys
+    print(list(range(int(sys.argv[1]))), 3)
+  params:
+    nums: {min: 0, max: 9}
+`
+	pack, err := LoadPack(writePack(t, map[string]string{"generated.yaml": generated}))
+	require.NoError(t, err)
+	g := pack.Problems[0].Generator
+	require.NotNil(t, g)
+	assert.Equal(t, "python", g.ScriptLanguage())
+	assert.Contains(t, g.Script, "sys.argv[1]")
+	assert.Equal(t, int64(9), *g.Params["nums"].Max)
+
+	issues := packIssues(t, writePack(t, map[string]string{"generated.yaml": strings.Replace(generated, `  script: |
+    import sys
+    print(list(range(int(sys.argv[1]))), 3)
+  params:
+    nums: {min: 0, max: 9}`, `  language: ruby
+  params:
+    values: {min: 9, max: 0}`, 1)}))
+	assert.Equal(t, []PackIssue{
+		{File: "generated.yaml", Line: 9, Message: `generator language "ruby" must be python or javascript`},
+		{File: "generated.yaml", Line: 9, Message: `generator params: "values" is not a parameter of the signature`},
+		{File: "generated.yaml", Line: 9, Message: "generator params: values has min 9 above max 0"},
+	}, issues)
+}
+
+func TestLoadPackDuplicateIDs(t *testing.T) {
+	dir := writePack(t, map[string]string{"a.json": packJSON, "b.json": packJSON})
+
+	issues := packIssues(t, dir)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "b.json", issues[0].File)
+	assert.Contains(t, issues[0].Message, `duplicate problem id "pair_sum"`)
+}
+
+func TestLoadPackFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/packs/windows.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(packYAML))
+	}))
+	defer server.Close()
+
+	pack, err := LoadPack(server.URL + "/packs/windows.yaml")
+	require.NoError(t, err)
+	require.Len(t, pack.Problems, 1)
+	assert.Equal(t, "window_max", pack.Problems[0].ID)
+
+	_, err = LoadPack(server.URL + "/missing.json")
+	assert.Error(t, err)
+}
+
+func TestInstallPack(t *testing.T) {
+	configDir := withPackConfigDir(t)
+	pack, err := LoadPack(writePack(t, map[string]string{"pair_sum.json": packJSON, "more.yml": packYAML}))
+	require.NoError(t, err)
+
+	dir, err := InstallPack("my-pack", pack)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, "problems", "my-pack"), dir)
+
+	// Installed problems are found like any other
+	p, err := GetByID("window_max")
+	require.NoError(t, err)
+	assert.Equal(t, "Window Max", p.Title)
+	all, err := ListAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	// Importing again replaces the pack
+	pack.Problems = pack.Problems[:1]
+	_, err = InstallPack("my-pack", pack)
+	require.NoError(t, err)
+	all, err = ListAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	// Other packs may not shadow its problems
+	_, err = InstallPack("other", pack)
+	assert.ErrorContains(t, err, pack.Problems[0].ID)
+
+	// Nor may a pack replace a directory it did not create
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "problems", "graphs"), 0755))
+	_, err = InstallPack("graphs", &Pack{})
+	assert.ErrorContains(t, err, "not an imported pack")
+
+	_, err = InstallPack("../escape", pack)
+	assert.ErrorContains(t, err, "invalid pack name")
+}
+
+func TestAddToPack(t *testing.T) {
+	withPackConfigDir(t)
+	pack, err := LoadPack(writePack(t, map[string]string{"pair_sum.json": packJSON, "more.yml": packYAML}))
+	require.NoError(t, err)
+	first, second := pack.Problems[0], pack.Problems[1]
+
+	_, err = AddToPack("generated", "ai", []Problem{first})
+	require.NoError(t, err)
+	_, err = AddToPack("generated", "ai", []Problem{second})
+	require.NoError(t, err)
+	all, err := ListAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2, "earlier problems are kept")
+
+	// A problem with the same ID replaces the earlier one
+	second.Title = "Renamed"
+	_, err = AddToPack("generated", "ai", []Problem{second})
+	require.NoError(t, err)
+	p, err := GetByID(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", p.Title)
+	all, err = ListAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -2,11 +2,15 @@
 package problem
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
 )
 
 // Problem represents an algorithm problem
@@ -25,6 +29,10 @@ type Problem struct {
 	StarterCode         map[string]string `json:"starter_code"`
 	Solutions           map[string]string `json:"solutions"`
 	TestCases           []TestCase        `json:"test_cases"`
+	Version             string            `json:"version,omitempty"` // Author-assigned revision, optional
+
+	Signature *interfaces.FunctionSignature `json:"signature,omitempty"` // How tests call the solution
+	Generator *InputGenerator               `json:"generator,omitempty"` // How large inputs are generated; from the constraints when absent
 }
 
 // Example represents an example for a problem
@@ -36,18 +44,23 @@ type Example struct {
 
 // TestCase represents a test case for a problem
 type TestCase struct {
-	Input    string `json:"input"`
-	Expected string `json:"expected"`
+	Input    string              `json:"input"`
+	Expected string              `json:"expected"`
+	Tier     interfaces.TestTier `json:"tier,omitempty"` // example, hidden, edge or stress; example when empty
 }
 
 // GetByID retrieves a problem by its ID
 // Exported as variable for testing
 var GetByID = func(id string) (*Problem, error) {
 	configDir := getConfigDir()
+	problemsDir := filepath.Join(configDir, "problems")
 
 	// Search in all pattern directories
-	patternDirs, err := os.ReadDir(filepath.Join(configDir, "problems"))
+	patternDirs, err := os.ReadDir(problemsDir)
 	if err != nil {
+		if p, fetchErr := fetchAndCache(context.Background(), RemoteFetcher, utils.NewFileSystem(), problemsDir, id); fetchErr == nil {
+			return p, nil
+		}
 		return nil, err
 	}
 
@@ -67,20 +80,26 @@ var GetByID = func(id string) (*Problem, error) {
 			return nil, err
 		}
 
-		var problem Problem
-		if err := json.Unmarshal(data, &problem); err != nil {
+		problem, err := decodeProblemFile(problemPath, data)
+		if err != nil {
 			return nil, err
 		}
 
 		return &problem, nil
 	}
 
+	// Not cached yet, fetch it from the server if we can
+	if p, err := fetchAndCache(context.Background(), RemoteFetcher, utils.NewFileSystem(), problemsDir, id); err == nil {
+		return p, nil
+	}
 	return nil, fmt.Errorf("problem not found: %s", id)
 }
 
 // ListAll lists all available problems
 // Exported as variable for testing
 var ListAll = func() ([]Problem, error) {
+	defer profiling.Track("problem index")()
+
 	var problems []Problem
 	configDir := getConfigDir()
 
@@ -110,14 +129,17 @@ var ListAll = func() ([]Problem, error) {
 			}
 
 			// Read problem file
-			data, err := os.ReadFile(filepath.Join(configDir, "problems", patternDir.Name(), problemFile.Name()))
+			problemPath := filepath.Join(configDir, "problems", patternDir.Name(), problemFile.Name())
+			data, err := os.ReadFile(problemPath)
 			if err != nil {
 				return nil, err
 			}
 
-			var problem Problem
-			if err := json.Unmarshal(data, &problem); err != nil {
-				return nil, err
+			// One malformed file should not hide every other problem
+			problem, err := decodeProblemFile(problemPath, data)
+			if err != nil {
+				skipInvalid(err)
+				continue
 			}
 
 			// Skip if already processed
@@ -307,3 +307,44 @@ func TestListByCompany(t *testing.T) {
 		assert.Equal(t, "problem2", companies["Microsoft"][0].ID)
 	})
 }
+
+func TestProblemVersioning(t *testing.T) {
+	p := Problem{
+		ID:        "test-problem",
+		TestCases: []TestCase{{Input: "ab", Expected: "c"}},
+	}
+
+	// Field boundaries are part of the hash
+	shifted := Problem{ID: "test-problem", TestCases: []TestCase{{Input: "a", Expected: "bc"}}}
+	assert.NotEqual(t, p.TestSetHash(), shifted.TestSetHash())
+	assert.Equal(t, p.TestSetHash(), HashTestCases(p.TestCases))
+
+	// Any edit to the definition changes the derived version
+	edited := p
+	edited.Description = "new wording"
+	assert.NotEqual(t, p.DefinitionVersion(), edited.DefinitionVersion())
+	assert.Equal(t, p.TestSetHash(), edited.TestSetHash())
+
+	// An explicit version takes precedence
+	p.Version = "2"
+	assert.Equal(t, "2", p.DefinitionVersion())
+}
+
+func TestDescribeChanges(t *testing.T) {
+	old := Problem{
+		ID:          "test-problem",
+		Description: "Find the pair",
+		Constraints: []string{"n <= 10"},
+		TestCases:   []TestCase{{Input: "1", Expected: "1"}},
+	}
+
+	assert.Empty(t, DescribeChanges(old, old))
+
+	updated := old
+	updated.Constraints = []string{"1 <= n <= 10"}
+	updated.TestCases = append([]TestCase{}, old.TestCases...)
+	updated.TestCases = append(updated.TestCases, TestCase{Input: "2", Expected: "2"})
+
+	changes := DescribeChanges(old, updated)
+	assert.Equal(t, []string{"1 test case(s) added", "constraints clarified"}, changes)
+}
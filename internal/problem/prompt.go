@@ -0,0 +1,37 @@
+// Problem statements with the pattern left unsaid
+
+package problem
+
+import "strings"
+
+// Prompt is the problem's description without the paragraphs that name its
+// patterns, for asking which pattern solves it without giving the answer
+// away. If every paragraph names one, the first is kept.
+func (p Problem) Prompt() string {
+	paragraphs := strings.Split(strings.TrimSpace(p.Description), "\n\n")
+	var kept []string
+	for _, paragraph := range paragraphs {
+		if !namesPattern(paragraph, p.Patterns) {
+			kept = append(kept, strings.TrimSpace(paragraph))
+		}
+	}
+	if len(kept) == 0 {
+		return strings.TrimSpace(paragraphs[0])
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// namesPattern reports whether text mentions one of patterns, by its
+// kebab-case name or its display name, ignoring case and hyphens
+func namesPattern(text string, patterns []string) bool {
+	normalize := func(s string) string { return strings.ReplaceAll(strings.ToLower(s), "-", " ") }
+	text = normalize(text)
+	for _, pattern := range patterns {
+		for _, name := range []string{pattern, PatternName(pattern)} {
+			if strings.Contains(text, normalize(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompt(t *testing.T) {
+	p := Problem{
+		Patterns:    []string{"fast-slow-pointers"},
+		Description: "Given the head of a list, determine if it has a cycle.\n\nThe fast-slow pointers pattern is perfect for this problem.",
+	}
+	assert.Equal(t, "Given the head of a list, determine if it has a cycle.", p.Prompt())
+
+	// Every paragraph names the pattern
+	p = Problem{Patterns: []string{"sliding-window"}, Description: "Use a sliding window.\n\nOr a Sliding Window."}
+	assert.Equal(t, "Use a sliding window.", p.Prompt())
+}
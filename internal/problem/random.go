@@ -8,11 +8,12 @@ import (
 
 // GetRandomProblemByPattern finds a random problem with the specified pattern
 var GetRandomProblemByPattern = func(pattern string) (*Problem, error) {
-	// Load all problems
+	// Load all problems the user has not hidden
 	problems, err := ListAll()
 	if err != nil {
 		return nil, err
 	}
+	problems = Visible(problems)
 
 	// Filter problems by pattern
 	var filteredProblems []Problem
@@ -37,11 +38,12 @@ var GetRandomProblemByPattern = func(pattern string) (*Problem, error) {
 
 // GetRandomProblem selects a random problem from all available problems
 var GetRandomProblem = func() (*Problem, error) {
-	// Load all problems
+	// Load all problems the user has not hidden
 	problems, err := ListAll()
 	if err != nil {
 		return nil, err
 	}
+	problems = Visible(problems)
 
 	if len(problems) == 0 {
 		return nil, fmt.Errorf("no problems found")
@@ -55,11 +57,12 @@ var GetRandomProblem = func() (*Problem, error) {
 
 // GetRandomProblemByDifficulty finds a random problem with the specified difficulty
 var GetRandomProblemByDifficulty = func(difficulty string) (*Problem, error) {
-	// Load all problems
+	// Load all problems the user has not hidden
 	problems, err := ListAll()
 	if err != nil {
 		return nil, err
 	}
+	problems = Visible(problems)
 
 	// Filter problems by difficulty
 	var filteredProblems []Problem
@@ -81,11 +84,12 @@ var GetRandomProblemByDifficulty = func(difficulty string) (*Problem, error) {
 
 // GetRandomProblemExcluding finds a random problem that is not in the excluded list
 var GetRandomProblemExcluding = func(excludedIDs []string) (*Problem, error) {
-	// Load all problems
+	// Load all problems the user has not hidden
 	problems, err := ListAll()
 	if err != nil {
 		return nil, err
 	}
+	problems = Visible(problems)
 
 	// Filter out excluded problems
 	var filteredProblems []Problem
@@ -110,4 +114,45 @@ var GetRandomProblemExcluding = func(excludedIDs []string) (*Problem, error) {
 	rand.Seed(time.Now().UnixNano())
 	randomIndex := rand.Intn(len(filteredProblems))
 	return &filteredProblems[randomIndex], nil
-}
\ No newline at end of file
+}
+// GetRandomProblemForRoutine finds a random problem with the specified
+// pattern for a practice routine, avoiding the excluded problems and
+// preferring the specified difficulty. It falls back to other difficulties,
+// then to excluded problems, rather than finding nothing.
+var GetRandomProblemForRoutine = func(pattern, difficulty string, excludedIDs []string) (*Problem, error) {
+	// Load all problems the user has not hidden
+	problems, err := ListAll()
+	if err != nil {
+		return nil, err
+	}
+	problems = GetProblemsByPattern(Visible(problems), pattern)
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("no problems found with pattern: %s", pattern)
+	}
+
+	excluded := make(map[string]bool, len(excludedIDs))
+	for _, id := range excludedIDs {
+		excluded[id] = true
+	}
+	var fresh, matching []Problem
+	for _, p := range problems {
+		if excluded[p.ID] {
+			continue
+		}
+		fresh = append(fresh, p)
+		if p.Difficulty == difficulty {
+			matching = append(matching, p)
+		}
+	}
+
+	candidates := matching
+	if len(candidates) == 0 {
+		candidates = fresh
+	}
+	if len(candidates) == 0 {
+		candidates = problems
+	}
+
+	// Pick a random problem
+	return &candidates[rand.Intn(len(candidates))], nil
+}
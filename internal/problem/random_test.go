@@ -0,0 +1,38 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomProblemForRoutine(t *testing.T) {
+	stubHidden(t)
+	original := ListAll
+	t.Cleanup(func() { ListAll = original })
+	ListAll = func() ([]Problem, error) {
+		return []Problem{
+			{ID: "two_sum", Difficulty: "easy", Patterns: []string{"hash-map"}},
+			{ID: "group_anagrams", Difficulty: "medium", Patterns: []string{"hash-map"}},
+			{ID: "number_of_islands", Difficulty: "medium", Patterns: []string{"dfs"}},
+		}, nil
+	}
+
+	for i := 0; i < 10; i++ {
+		p, err := GetRandomProblemForRoutine("hash-map", "medium", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "group_anagrams", p.ID, "the difficulty is preferred")
+
+		p, err = GetRandomProblemForRoutine("hash-map", "medium", []string{"group_anagrams"})
+		require.NoError(t, err)
+		assert.Equal(t, "two_sum", p.ID, "then problems not chosen yet")
+
+		p, err = GetRandomProblemForRoutine("dfs", "hard", []string{"number_of_islands"})
+		require.NoError(t, err)
+		assert.Equal(t, "number_of_islands", p.ID, "and then any of the pattern")
+	}
+
+	_, err := GetRandomProblemForRoutine("heap", "", nil)
+	assert.EqualError(t, err, "no problems found with pattern: heap")
+}
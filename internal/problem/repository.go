@@ -2,19 +2,20 @@ package problem
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"sort"
 	"strings"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
 	"github.com/lancekrogers/algo-scales/internal/common/utils"
 )
 
 // Repository implements the ProblemRepository interface
 type Repository struct {
-	fs interfaces.FileSystem
+	fs    interfaces.FileSystem
+	fetch Fetcher // fills cache misses; RemoteFetcher when nil
 }
 
 // NewRepository creates a new problem repository with the default file system
@@ -26,7 +27,12 @@ func NewRepository() interfaces.ProblemRepository {
 
 // WithFileSystem returns a repository with a custom file system
 func (r *Repository) WithFileSystem(fs interfaces.FileSystem) *Repository {
-	return &Repository{fs: fs}
+	return &Repository{fs: fs, fetch: r.fetch}
+}
+
+// WithFetcher returns a repository that fills cache misses with a custom fetcher
+func (r *Repository) WithFetcher(fetch Fetcher) *Repository {
+	return &Repository{fs: r.fs, fetch: fetch}
 }
 
 // GetAll returns all available problems
@@ -46,6 +52,8 @@ func (r *Repository) GetAll(ctx context.Context) ([]interfaces.Problem, error) {
 
 // getAllLocal returns all problems as local Problem types
 func (r *Repository) getAllLocal(ctx context.Context) ([]Problem, error) {
+	defer profiling.Track("problem index")()
+
 	// First try the standard config dir location
 	configDir := r.fs.GetConfigDir()
 	problemsDir := filepath.Join(configDir, "problems")
@@ -122,11 +130,12 @@ func (r *Repository) getAllLocal(ctx context.Context) ([]Problem, error) {
 				return nil, fmt.Errorf("failed to read problem file %s: %v", problemPath, err)
 			}
 			
-			// Parse problem data
-			var problem Problem
-			err = json.Unmarshal(data, &problem)
+			// Parse problem data, skipping malformed files rather than
+			// failing the whole listing
+			problem, err := decodeProblemFile(problemPath, data)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse problem file %s: %v", problemPath, err)
+				skipInvalid(err)
+				continue
 			}
 			
 			// Skip if already processed
@@ -179,10 +188,14 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*interfaces.Proble
 // getByIDLocal retrieves a specific problem by its ID as local type
 func (r *Repository) getByIDLocal(ctx context.Context, id string) (*Problem, error) {
 	configDir := r.fs.GetConfigDir()
+	problemsDir := filepath.Join(configDir, "problems")
 	
 	// Search in all pattern directories
-	patternDirs, err := r.fs.ReadDir(filepath.Join(configDir, "problems"))
+	patternDirs, err := r.fs.ReadDir(problemsDir)
 	if err != nil {
+		if p, fetchErr := fetchAndCache(ctx, r.fetcher(), r.fs, problemsDir, id); fetchErr == nil {
+			return p, nil
+		}
 		return nil, err
 	}
 	
@@ -202,15 +215,24 @@ func (r *Repository) getByIDLocal(ctx context.Context, id string) (*Problem, err
 			return nil, err
 		}
 		
-		var problem Problem
-		if err := json.Unmarshal(data, &problem); err != nil {
+		problem, err := decodeProblemFile(problemPath, data)
+		if err != nil {
 			return nil, err
 		}
 		
 		return &problem, nil
 	}
 	
-	return nil, ErrProblemNotFound
+	// Not cached yet, fetch it from the server if we can
+	return fetchAndCache(ctx, r.fetcher(), r.fs, problemsDir, id)
+}
+
+// fetcher returns the fetcher used for cache misses
+func (r *Repository) fetcher() Fetcher {
+	if r.fetch != nil {
+		return r.fetch
+	}
+	return RemoteFetcher
 }
 
 // GetByPattern returns problems matching a specific pattern
@@ -360,6 +382,7 @@ func (r *Repository) convertToInterface(p Problem) interfaces.Problem {
 		testCases[i] = interfaces.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -367,6 +390,7 @@ func (r *Repository) convertToInterface(p Problem) interfaces.Problem {
 		ID:          p.ID,
 		Title:       p.Title,
 		Description: p.Description,
+		Signature:   p.Signature,
 		Pattern:     pattern,
 		Difficulty:  p.Difficulty,
 		Companies:   p.Companies,
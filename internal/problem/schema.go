@@ -0,0 +1,397 @@
+// Schema validation of problem JSON with precise paths and positions
+
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// schemaType is the JSON type a schema accepts
+type schemaType int
+
+const (
+	typeString schemaType = iota
+	typeInteger
+	typeBoolean
+	typeArray  // Elements match items
+	typeObject // Known keys match fields, unknown keys are ignored
+	typeMap    // Every value matches items
+)
+
+// kind returns the JSON kind a schema type is written as
+func (t schemaType) kind() jsonKind {
+	return [...]jsonKind{kindString, kindNumber, kindBool, kindArray, kindObject, kindObject}[t]
+}
+
+// describe names a schema type for error messages
+func (t schemaType) describe() string {
+	return [...]string{"a string", "an integer", "a boolean", "an array", "an object", "an object"}[t]
+}
+
+// schema describes the JSON shape of a value. Null is accepted wherever a
+// value is optional, since nil slices and maps are written as null.
+type schema struct {
+	typ      schemaType
+	items    *schema
+	fields   map[string]*schema
+	required []string
+}
+
+var (
+	stringSchema  = &schema{typ: typeString}
+	stringList    = &schema{typ: typeArray, items: stringSchema}
+	codeSchema    = &schema{typ: typeMap, items: stringSchema}
+	integerSchema = &schema{typ: typeInteger}
+)
+
+// problemSchema is the shape of a problem file. It only requires what a
+// problem cannot be listed or tested without; Validate checks content.
+var problemSchema = &schema{
+	typ:      typeObject,
+	required: []string{"id", "title"},
+	fields: map[string]*schema{
+		"id":             stringSchema,
+		"title":          stringSchema,
+		"difficulty":     stringSchema,
+		"patterns":       stringList,
+		"estimated_time": integerSchema,
+		"companies":      stringList,
+		"description":    stringSchema,
+		"examples": {typ: typeArray, items: &schema{
+			typ:      typeObject,
+			required: []string{"input", "output"},
+			fields:   map[string]*schema{"input": stringSchema, "output": stringSchema, "explanation": stringSchema},
+		}},
+		"constraints":          stringList,
+		"pattern_explanation":  stringSchema,
+		"solution_walkthrough": stringList,
+		"starter_code":         codeSchema,
+		"solutions":            codeSchema,
+		"test_cases": {typ: typeArray, items: &schema{
+			typ:      typeObject,
+			required: []string{"input", "expected"},
+			fields:   map[string]*schema{"input": stringSchema, "expected": stringSchema, "tier": stringSchema},
+		}},
+		"version": stringSchema,
+		"signature": {
+			typ:      typeObject,
+			required: []string{"name", "returns"},
+			fields: map[string]*schema{
+				"name": stringSchema,
+				"params": {typ: typeArray, items: &schema{
+					typ:      typeObject,
+					required: []string{"name", "type"},
+					fields:   map[string]*schema{"name": stringSchema, "type": stringSchema},
+				}},
+				"returns":   stringSchema,
+				"unordered": {typ: typeBoolean},
+			},
+		},
+		"generator": {
+			typ: typeObject,
+			fields: map[string]*schema{
+				"script":   stringSchema,
+				"language": stringSchema,
+				"params": {typ: typeMap, items: &schema{
+					typ: typeObject,
+					fields: map[string]*schema{
+						"min":    integerSchema,
+						"max":    integerSchema,
+						"length": integerSchema,
+						"chars":  stringSchema,
+					},
+				}},
+			},
+		},
+	},
+}
+
+// SchemaIssue is one place where problem JSON departs from the schema
+type SchemaIssue struct {
+	Path    string // e.g. test_cases[2].expected, empty for the whole document
+	Line    int
+	Column  int
+	Message string // Includes the path, e.g. "test_cases[2].expected missing"
+}
+
+// String formats the issue as line:column: message
+func (i SchemaIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s", i.Line, i.Column, i.Message)
+}
+
+// SchemaError reports every schema issue found in a problem
+type SchemaError struct {
+	File   string // Empty when the problem did not come from a file
+	Issues []SchemaIssue
+}
+
+func (e *SchemaError) Error() string {
+	var b strings.Builder
+	if e.File != "" {
+		b.WriteString(e.File + ":")
+	}
+	b.WriteString(e.Issues[0].String())
+	if n := len(e.Issues) - 1; n > 0 {
+		fmt.Fprintf(&b, " (and %d more issue(s))", n)
+	}
+	return b.String()
+}
+
+// Unwrap lets callers match schema errors with ErrInvalidProblemData
+func (e *SchemaError) Unwrap() error {
+	return ErrInvalidProblemData
+}
+
+// DecodeProblem checks problem JSON against the problem schema and decodes
+// it. If the JSON is malformed or does not match, the error is a
+// *SchemaError giving the path and position of every issue.
+func DecodeProblem(data []byte) (Problem, error) {
+	var p Problem
+
+	// encoding/json gives the better message for syntax errors
+	if err := json.Unmarshal(data, new(any)); err != nil {
+		offset := int64(len(data))
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Offset > 0 {
+			offset = syntaxErr.Offset - 1 // Offset is just past the bad character
+		}
+		line, col := position(data, offset)
+		return p, &SchemaError{Issues: []SchemaIssue{{Line: line, Column: col, Message: err.Error()}}}
+	}
+
+	root, err := parseJSONTree(data)
+	if err != nil {
+		return p, &SchemaError{Issues: []SchemaIssue{{Line: 1, Column: 1, Message: err.Error()}}}
+	}
+	c := &schemaChecker{data: data}
+	c.check(problemSchema, root, "")
+	if len(c.issues) > 0 {
+		return p, &SchemaError{Issues: c.issues}
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, &SchemaError{Issues: []SchemaIssue{{Line: 1, Column: 1, Message: err.Error()}}}
+	}
+	return p, nil
+}
+
+// decodeProblemFile decodes a problem file, naming the file in schema errors
+func decodeProblemFile(path string, data []byte) (Problem, error) {
+	p, err := DecodeProblem(data)
+	var schemaErr *SchemaError
+	if errors.As(err, &schemaErr) {
+		schemaErr.File = path
+	}
+	return p, err
+}
+
+// warnedFiles remembers which invalid files were already reported, since
+// problems are listed many times per run
+var warnedFiles sync.Map
+
+// warnInvalid reports a problem file left out of a listing
+// Exported as variable for testing
+var warnInvalid = func(err error) {
+	fmt.Fprintf(os.Stderr, "Warning: skipping invalid problem %v\n", err)
+}
+
+// skipInvalid reports an invalid problem file once per run
+func skipInvalid(err error) {
+	if _, seen := warnedFiles.LoadOrStore(err.Error(), true); !seen {
+		warnInvalid(err)
+	}
+}
+
+// jsonKind is the type of a parsed JSON value
+type jsonKind int
+
+const (
+	kindNull jsonKind = iota
+	kindString
+	kindNumber
+	kindBool
+	kindArray
+	kindObject
+)
+
+// describe names a kind for error messages
+func (k jsonKind) describe() string {
+	return [...]string{"null", "a string", "a number", "a boolean", "an array", "an object"}[k]
+}
+
+// jsonValue is a parsed JSON value that remembers where it starts
+type jsonValue struct {
+	kind   jsonKind
+	offset int64
+	number json.Number
+	keys   []string // Object keys in document order
+	fields map[string]*jsonValue
+	items  []*jsonValue
+}
+
+// jsonTreeParser builds a jsonValue tree from a decoder's token stream
+type jsonTreeParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// parseJSONTree parses valid JSON into a tree of positioned values
+func parseJSONTree(data []byte) (*jsonValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	p := &jsonTreeParser{data: data, dec: dec}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the problem")
+	}
+	return root, nil
+}
+
+// start returns where the next token begins. The decoder's offset is just
+// past the previous token, before any separator.
+func (p *jsonTreeParser) start() int64 {
+	offset := p.dec.InputOffset()
+	for offset < int64(len(p.data)) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (p *jsonTreeParser) value() (*jsonValue, error) {
+	v := &jsonValue{offset: p.start()}
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			v.kind = kindObject
+			v.fields = make(map[string]*jsonValue)
+			for p.dec.More() {
+				keyTok, err := p.dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				child, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := v.fields[key]; !dup {
+					v.keys = append(v.keys, key)
+				}
+				v.fields[key] = child // Last one wins, as in encoding/json
+			}
+		} else {
+			v.kind = kindArray
+			for p.dec.More() {
+				child, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				v.items = append(v.items, child)
+			}
+		}
+		// Closing delimiter
+		if _, err := p.dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		v.kind = kindString
+	case json.Number:
+		v.kind = kindNumber
+		v.number = t
+	case bool:
+		v.kind = kindBool
+	case nil:
+		v.kind = kindNull
+	}
+	return v, nil
+}
+
+// schemaChecker collects the issues found while checking a tree
+type schemaChecker struct {
+	data   []byte
+	issues []SchemaIssue
+}
+
+// issue records a problem with the value at path
+func (c *schemaChecker) issue(path string, offset int64, message string) {
+	line, col := position(c.data, offset)
+	c.issues = append(c.issues, SchemaIssue{Path: path, Line: line, Column: col, Message: message})
+}
+
+// check matches v against s, recording issues under path
+func (c *schemaChecker) check(s *schema, v *jsonValue, path string) {
+	if v.kind == kindNull && path != "" {
+		return // Required fields reject null separately
+	}
+
+	name := path
+	if name == "" {
+		name = "problem"
+	}
+	if v.kind != s.typ.kind() {
+		c.issue(path, v.offset, fmt.Sprintf("%s should be %s, got %s", name, s.typ.describe(), v.kind.describe()))
+		return
+	}
+
+	switch s.typ {
+	case typeInteger:
+		if _, err := v.number.Int64(); err != nil {
+			c.issue(path, v.offset, fmt.Sprintf("%s should be an integer, got %s", name, v.number))
+		}
+	case typeArray:
+		for i, item := range v.items {
+			c.check(s.items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case typeMap:
+		for _, key := range v.keys {
+			c.check(s.items, v.fields[key], joinPath(path, key))
+		}
+	case typeObject:
+		for _, key := range v.keys {
+			if field, ok := s.fields[key]; ok {
+				c.check(field, v.fields[key], joinPath(path, key))
+			}
+		}
+		for _, key := range s.required {
+			field, ok := v.fields[key]
+			switch {
+			case !ok:
+				c.issue(joinPath(path, key), v.offset, joinPath(path, key)+" missing")
+			case field.kind == kindNull:
+				c.issue(joinPath(path, key), field.offset, joinPath(path, key)+" is null")
+			}
+		}
+	}
+}
+
+// joinPath appends an object key to a JSON path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// position returns the 1-based line and column of a byte offset
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	return bytes.Count(before, []byte("\n")) + 1, int(offset) - bytes.LastIndexByte(before, '\n')
+}
@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeProblemShippedProblems(t *testing.T) {
+	files, err := filepath.Glob("../../problems/*/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, err = DecodeProblem(data)
+		assert.NoError(t, err, file)
+	}
+}
+
+func TestDecodeProblemReportsPaths(t *testing.T) {
+	data := []byte(`{
+  "id": "two_sum",
+  "title": "Two Sum",
+  "estimated_time": 12.5,
+  "starter_code": {"go": 42},
+  "test_cases": [
+    {"input": "1", "expected": "1"},
+    {"input": "2", "expected": "4"},
+    {"input": "3"}
+  ],
+  "signature": {"name": "twoSum", "params": [{"name": "nums"}], "returns": null},
+  "future_field": true
+}`)
+
+	_, err := DecodeProblem(data)
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.True(t, errors.Is(err, ErrInvalidProblemData))
+
+	assert.Equal(t, []SchemaIssue{
+		{Path: "estimated_time", Line: 4, Column: 21, Message: "estimated_time should be an integer, got 12.5"},
+		{Path: "starter_code.go", Line: 5, Column: 26, Message: "starter_code.go should be a string, got a number"},
+		{Path: "test_cases[2].expected", Line: 9, Column: 5, Message: "test_cases[2].expected missing"},
+		{Path: "signature.params[0].type", Line: 11, Column: 46, Message: "signature.params[0].type missing"},
+		{Path: "signature.returns", Line: 11, Column: 76, Message: "signature.returns is null"},
+	}, schemaErr.Issues)
+	assert.Equal(t, "4:21: estimated_time should be an integer, got 12.5 (and 4 more issue(s))", err.Error())
+}
+
+func TestDecodeProblemSyntaxAndShape(t *testing.T) {
+	_, err := DecodeProblem([]byte("{\n  \"id\": \"x\",\n  \"title\": \"X\" \"oops\"\n}"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3:16: invalid character")
+
+	_, err = DecodeProblem(nil)
+	assert.EqualError(t, err, "1:1: unexpected end of JSON input")
+
+	_, err = DecodeProblem([]byte(`{"title": "No ID", "patterns": null}`))
+	assert.EqualError(t, err, "1:1: id missing", "optional fields may be null")
+}
+
+func TestListAllSkipsInvalidProblems(t *testing.T) {
+	tempDir := t.TempDir()
+	patternDir := filepath.Join(tempDir, "problems", "hash-map")
+	require.NoError(t, os.MkdirAll(patternDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(patternDir, "good.json"), []byte(`{"id": "good", "title": "Good"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(patternDir, "bad.json"), []byte(`{"id": "bad", "title": "Bad", "test_cases": [{"input": "1"}]}`), 0644))
+
+	origGetConfigDir, origWarn := getConfigDir, warnInvalid
+	defer func() { getConfigDir, warnInvalid = origGetConfigDir, origWarn }()
+	getConfigDir = func() string { return tempDir }
+	var warnings []string
+	warnInvalid = func(err error) { warnings = append(warnings, err.Error()) }
+
+	problems, err := ListAll()
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "good", problems[0].ID)
+
+	// Listing again does not repeat the warning
+	_, err = ListAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(patternDir, "bad.json") + ":1:46: test_cases[0].expected missing"}, warnings)
+
+	// Asking for the problem directly explains why it cannot be loaded
+	_, err = GetByID("bad")
+	assert.True(t, errors.Is(err, ErrInvalidProblemData))
+}
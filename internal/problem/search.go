@@ -0,0 +1,182 @@
+// Full-text search over problems
+
+package problem
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Field weights: a word in a problem's title says more about it than the
+// same word in its tags, and a word in its tags more than one in its
+// description
+const (
+	titleWeight       = 3
+	tagWeight         = 2
+	descriptionWeight = 1
+)
+
+// Query is what a search asks for: words to find, all of them, and filters
+// the problems must pass. Empty parts match every problem.
+type Query struct {
+	Text       string // Words to find in titles, descriptions, patterns and companies; the last may be a prefix
+	Pattern    string
+	Difficulty string
+	Company    string
+}
+
+// Match is a problem a search found, with how well it matched
+type Match struct {
+	Problem Problem
+	Score   int // Sum of the weights of the fields each word was found in
+}
+
+// posting is a problem a term occurs in, and the weight of the best field
+// it occurs in there
+type posting struct {
+	problem int
+	weight  int
+}
+
+// Index is an inverted index from the words of problems' titles,
+// descriptions, patterns and companies to the problems they occur in
+type Index struct {
+	problems []Problem
+	postings map[string][]posting
+	terms    []string // Every indexed term, sorted, for prefix lookups
+}
+
+// NewIndex indexes problems
+func NewIndex(problems []Problem) *Index {
+	ix := &Index{problems: problems, postings: make(map[string][]posting)}
+	for i, p := range problems {
+		weights := make(map[string]int)
+		add := func(text string, weight int) {
+			for _, term := range tokenize(text) {
+				weights[term] = max(weights[term], weight)
+			}
+		}
+		add(p.Description, descriptionWeight)
+		add(strings.Join(p.Patterns, " "), tagWeight)
+		add(strings.Join(p.Companies, " "), tagWeight)
+		add(p.Title, titleWeight)
+		add(p.ID, titleWeight)
+		for term, weight := range weights {
+			ix.postings[term] = append(ix.postings[term], posting{problem: i, weight: weight})
+		}
+	}
+	ix.terms = make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		ix.terms = append(ix.terms, term)
+	}
+	sort.Strings(ix.terms)
+	return ix
+}
+
+// Search returns the problems that contain every word of the query and pass
+// its filters, the best matches first and ties by ID. Words match whole
+// indexed words, except the last, which also matches as a prefix so results
+// can narrow as it is typed.
+func (ix *Index) Search(q Query) []Match {
+	words := tokenize(q.Text)
+	scores := make(map[int]int)
+	for i := range ix.problems {
+		if q.passes(ix.problems[i]) {
+			scores[i] = 0
+		}
+	}
+	for n, word := range words {
+		found := ix.lookup(word, n == len(words)-1)
+		for i := range scores {
+			if weight, ok := found[i]; ok {
+				scores[i] += weight
+			} else {
+				delete(scores, i)
+			}
+		}
+	}
+
+	matches := make([]Match, 0, len(scores))
+	for i, score := range scores {
+		matches = append(matches, Match{Problem: ix.problems[i], Score: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Problem.ID < matches[j].Problem.ID
+	})
+	return matches
+}
+
+// lookup returns the problems a word occurs in, with the weight of the best
+// field it occurs in; as a prefix it finds every term starting with it
+func (ix *Index) lookup(word string, prefix bool) map[int]int {
+	found := make(map[int]int)
+	collect := func(term string) {
+		for _, p := range ix.postings[term] {
+			found[p.problem] = max(found[p.problem], p.weight)
+		}
+	}
+	if !prefix {
+		collect(word)
+		return found
+	}
+	for i := sort.SearchStrings(ix.terms, word); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], word); i++ {
+		collect(ix.terms[i])
+	}
+	return found
+}
+
+// passes reports whether a problem passes the query's filters
+func (q Query) passes(p Problem) bool {
+	if q.Difficulty != "" && !strings.EqualFold(p.Difficulty, q.Difficulty) {
+		return false
+	}
+	if q.Pattern != "" && !containsFold(p.Patterns, q.Pattern) {
+		return false
+	}
+	if q.Company != "" && !containsFold(p.Companies, q.Company) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether a slice holds s, ignoring case
+func containsFold(slice []string, s string) bool {
+	for _, item := range slice {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits text into lowercase words of letters and digits. Compound
+// words such as sliding-window or two_sum give their parts and the whole,
+// joined by a hyphen.
+func tokenize(text string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		parts := strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		terms = append(terms, parts...)
+		if len(parts) > 1 {
+			terms = append(terms, strings.Join(parts, "-"))
+		}
+	}
+	return terms
+}
+
+// Search finds problems in the repository by the words of a query and its
+// filters
+func (r *Repository) Search(ctx context.Context, q Query) ([]Match, error) {
+	problems, err := r.getAllLocal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(problems).Search(q), nil
+}
@@ -0,0 +1,84 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var searchProblems = []Problem{
+	{
+		ID: "max_subarray", Title: "Maximum Subarray", Difficulty: "medium",
+		Patterns: []string{"dynamic-programming"}, Companies: []string{"Amazon"},
+		Description: "Find the contiguous subarray with the largest sum.",
+	},
+	{
+		ID: "min_size_subarray", Title: "Minimum Size Subarray Sum", Difficulty: "medium",
+		Patterns: []string{"sliding-window"}, Companies: []string{"Google", "Meta"},
+		Description: "Find the shortest subarray whose sum reaches the target.",
+	},
+	{
+		ID: "longest_substring", Title: "Longest Substring Without Repeating Characters", Difficulty: "medium",
+		Patterns: []string{"sliding-window"}, Companies: []string{"Google"},
+		Description: "Grow and shrink a window over the string.",
+	},
+	{
+		ID: "two_sum", Title: "Two Sum", Difficulty: "easy",
+		Patterns: []string{"hash-map"}, Companies: []string{"Google"},
+		Description: "Find two numbers in an array that add up to the target.",
+	},
+}
+
+func searchIDs(matches []Match) []string {
+	ids := []string{}
+	for _, m := range matches {
+		ids = append(ids, m.Problem.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex(searchProblems)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"everything without a query", Query{}, []string{"longest_substring", "max_subarray", "min_size_subarray", "two_sum"}},
+		{"titles rank above descriptions", Query{Text: "sum"}, []string{"min_size_subarray", "two_sum", "max_subarray"}},
+		{"every word must match", Query{Text: "subarray target"}, []string{"min_size_subarray"}},
+		{"the last word may be a prefix", Query{Text: "subarr"}, []string{"max_subarray", "min_size_subarray"}},
+		{"earlier words must be whole", Query{Text: "subarr sum"}, []string{}},
+		{"tags are searched", Query{Text: "window"}, []string{"longest_substring", "min_size_subarray"}},
+		{"compound tags match whole", Query{Text: "sliding-window"}, []string{"longest_substring", "min_size_subarray"}},
+		{"filters combine with text", Query{Text: "subarray", Pattern: "sliding-window", Difficulty: "Medium", Company: "google"}, []string{"min_size_subarray"}},
+		{"filters alone", Query{Company: "google", Difficulty: "easy"}, []string{"two_sum"}},
+		{"no match", Query{Text: "graph"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchIDs(ix.Search(tt.query)))
+		})
+	}
+}
+
+func TestRepositorySearch(t *testing.T) {
+	fs := utils.NewMockFileSystem()
+	for _, p := range searchProblems {
+		dir := "/home/mockuser/.algo-scales/problems/" + p.Patterns[0]
+		require.NoError(t, fs.MkdirAll(dir, 0755))
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		require.NoError(t, fs.WriteFile(dir+"/"+p.ID+".json", data, 0644))
+	}
+
+	repo := (&Repository{}).WithFileSystem(fs)
+	matches, err := repo.Search(context.Background(), Query{Text: "substring"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"longest_substring"}, searchIDs(matches))
+}
@@ -131,6 +131,7 @@ func (s *Service) convertFromInterface(p interfaces.Problem) Problem {
 		testCases[i] = TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -144,6 +145,7 @@ func (s *Service) convertFromInterface(p interfaces.Problem) Problem {
 		ID:          p.ID,
 		Title:       p.Title,
 		Description: p.Description,
+		Signature:   p.Signature,
 		Difficulty:  p.Difficulty,
 		Patterns:    p.Tags, // Use tags as patterns
 		Companies:   p.Companies,
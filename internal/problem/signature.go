@@ -0,0 +1,51 @@
+// Signatures in the text form authors write them in
+
+package problem
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// signaturePattern matches a signature such as "twoSum(nums int[], target int) int[]"
+var signaturePattern = regexp.MustCompile(`^([A-Za-z_]\w*)\s*\((.*)\)\s*(\S+)(\s+unordered)?$`)
+
+// ParseSignature parses a signature such as
+// "twoSum(nums int[], target int) int[]", optionally followed by "unordered"
+func ParseSignature(text string) (*interfaces.FunctionSignature, error) {
+	m := signaturePattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil, fmt.Errorf("signature must look like name(param type, ...) returnType")
+	}
+	sig := &interfaces.FunctionSignature{Name: m[1], Returns: m[3], Unordered: m[4] != ""}
+	if strings.TrimSpace(m[2]) == "" {
+		return sig, nil
+	}
+	for _, param := range strings.Split(m[2], ",") {
+		parts := strings.Fields(param)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("parameter %q must be a name and a type", strings.TrimSpace(param))
+		}
+		sig.Params = append(sig.Params, interfaces.Param{Name: parts[0], Type: parts[1]})
+	}
+	return sig, nil
+}
+
+// FormatSignature writes a signature in the form ParseSignature reads
+func FormatSignature(sig *interfaces.FunctionSignature) string {
+	if sig == nil {
+		return ""
+	}
+	params := make([]string, len(sig.Params))
+	for i, p := range sig.Params {
+		params[i] = p.Name + " " + p.Type
+	}
+	text := fmt.Sprintf("%s(%s) %s", sig.Name, strings.Join(params, ", "), sig.Returns)
+	if sig.Unordered {
+		text += " unordered"
+	}
+	return text
+}
@@ -0,0 +1,27 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignature(t *testing.T) {
+	sig, err := ParseSignature("twoSum(nums int[], target int) int[] unordered")
+	require.NoError(t, err)
+	assert.Equal(t, &interfaces.FunctionSignature{
+		Name:      "twoSum",
+		Params:    []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+		Returns:   "int[]",
+		Unordered: true,
+	}, sig)
+	assert.Equal(t, "twoSum(nums int[], target int) int[] unordered", FormatSignature(sig))
+
+	_, err = ParseSignature("twoSum(nums) int")
+	assert.ErrorContains(t, err, `parameter "nums" must be a name and a type`)
+	_, err = ParseSignature("not a signature")
+	assert.Error(t, err)
+	assert.Empty(t, FormatSignature(nil))
+}
@@ -0,0 +1,66 @@
+// Cached AI summaries of problem statements
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Summary is a short restatement of a problem with the questions worth
+// asking an interviewer before solving it
+type Summary struct {
+	ProblemID string    `json:"problem_id"`
+	Version   string    `json:"version"` // DefinitionVersion of the problem summarized
+	Summary   string    `json:"summary"`
+	Questions []string  `json:"clarifying_questions"`
+	Generated time.Time `json:"generated"`
+}
+
+// summaryBackend is the user data backend summaries are cached in
+// Exported as variable for testing
+var summaryBackend = storage.UserData
+
+// summaryName is the file a problem's summary is cached in. Summaries live
+// outside the problems directory so they are never mistaken for problem files.
+func summaryName(id string) string {
+	return "summaries/" + id + ".json"
+}
+
+// LoadSummary returns the cached summary of a problem. A summary written for
+// an earlier revision of the problem is stale and not returned.
+func LoadSummary(p Problem) (*Summary, bool) {
+	backend, err := summaryBackend()
+	if err != nil {
+		return nil, false
+	}
+	data, err := backend.Get(context.Background(), summaryName(p.ID))
+	if err != nil {
+		return nil, false
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil || s.Version != p.DefinitionVersion() {
+		return nil, false
+	}
+	return &s, true
+}
+
+// SaveSummary caches a problem's summary
+func SaveSummary(s Summary) error {
+	backend, err := summaryBackend()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %v", err)
+	}
+	if err := backend.Put(context.Background(), summaryName(s.ProblemID), data); err != nil {
+		return fmt.Errorf("failed to write summary: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaryCache(t *testing.T) {
+	original := summaryBackend
+	defer func() { summaryBackend = original }()
+	backend := storage.NewLocalBackend(t.TempDir())
+	summaryBackend = func() (storage.Backend, error) { return backend, nil }
+
+	p := Problem{ID: "two_sum", Title: "Two Sum", Description: "Find two numbers that add up to target."}
+
+	_, ok := LoadSummary(p)
+	assert.False(t, ok)
+
+	require.NoError(t, SaveSummary(Summary{
+		ProblemID: p.ID,
+		Version:   p.DefinitionVersion(),
+		Summary:   "Given numbers and a target, return two indices.",
+		Questions: []string{"Can the same element be used twice?"},
+	}))
+
+	s, ok := LoadSummary(p)
+	require.True(t, ok)
+	assert.Equal(t, "Given numbers and a target, return two indices.", s.Summary)
+	assert.Equal(t, []string{"Can the same element be used twice?"}, s.Questions)
+
+	// Editing the problem makes the cached summary stale
+	p.Description = "Find two numbers that add up to target, or report none."
+	_, ok = LoadSummary(p)
+	assert.False(t, ok)
+}
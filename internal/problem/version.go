@@ -0,0 +1,52 @@
+package problem
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+)
+
+// hashLength is the number of hex characters kept from a SHA-256 digest
+const hashLength = 16
+
+// HashTestCases returns a stable hash of an ordered set of test cases
+func HashTestCases(cases []TestCase) string {
+	h := sha256.New()
+	for _, tc := range cases {
+		// Length-prefix each field so that "ab"+"c" and "a"+"bc" differ
+		writeField(h, tc.Input)
+		writeField(h, tc.Expected)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:hashLength]
+}
+
+// TestSetHash returns a hash identifying the problem's current test cases
+func (p Problem) TestSetHash() string {
+	return HashTestCases(p.TestCases)
+}
+
+// DefinitionVersion returns the problem's version identifier.
+// The author-assigned Version is preferred; otherwise a hash of the
+// full problem definition is used so that any edit changes the value.
+func (p Problem) DefinitionVersion() string {
+	if p.Version != "" {
+		return p.Version
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha-" + hex.EncodeToString(sum[:])[:hashLength]
+}
+
+// writeField writes a length-prefixed string to the hash
+func writeField(h hash.Hash, s string) {
+	var prefix [8]byte
+	binary.LittleEndian.PutUint64(prefix[:], uint64(len(s)))
+	h.Write(prefix[:])
+	h.Write([]byte(s))
+}
@@ -0,0 +1,142 @@
+// Checks a problem should pass before it is published
+
+package problemdir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/packcheck"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+// Issue is something to fix before a problem is published
+type Issue struct {
+	ProblemID string // Empty for schema errors, which name their file instead
+	Message   string
+}
+
+// String formats the issue as problem: message
+func (i Issue) String() string {
+	if i.ProblemID == "" {
+		return i.Message
+	}
+	return i.ProblemID + ": " + i.Message
+}
+
+// Report is the outcome of linting a pack or a problem directory
+type Report struct {
+	Problems []problem.Problem // Those read without schema errors
+	Issues   []Issue
+}
+
+// Lint reads the problems at source as a pack import would and reports
+// their schema errors, then what the schema allows but a finished problem
+// should not have: placeholders left in, a missing or unusable signature,
+// no examples, only example tests, and languages with starter code but no
+// reference solution or the other way round. It does not run any code;
+// 'validate-pack' does.
+func Lint(source string) (*Report, error) {
+	pack, packIssues, err := problem.ReadPack(source)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Problems: pack.Problems}
+	for _, issue := range packIssues {
+		report.Issues = append(report.Issues, Issue{Message: issue.String()})
+	}
+	for _, p := range pack.Problems {
+		for _, message := range lintProblem(p) {
+			report.Issues = append(report.Issues, Issue{ProblemID: p.ID, Message: message})
+		}
+	}
+	return report, nil
+}
+
+// lintProblem lists what is left to do on a problem that passed the schema
+func lintProblem(p problem.Problem) []string {
+	var messages []string
+	for _, field := range placeholderFields(p) {
+		messages = append(messages, fmt.Sprintf("%s still has %s placeholders", field, Placeholder))
+	}
+
+	if p.Signature == nil {
+		messages = append(messages, "no signature: tests cannot call the solution")
+	} else if err := execution.ValidateSignature(p.Signature); err != nil {
+		messages = append(messages, "signature: "+err.Error())
+	}
+	if len(p.Examples) == 0 {
+		messages = append(messages, "no examples")
+	}
+	onlyExamples := true
+	for _, tc := range p.TestCases {
+		if tc.Tier.OrDefault() != interfaces.TierExample {
+			onlyExamples = false
+		}
+	}
+	if onlyExamples {
+		messages = append(messages, "every test is an example; add hidden or edge tests for submissions to run")
+	}
+
+	languages := packcheck.Languages(p)
+	if len(languages) == 0 {
+		messages = append(messages, "no starter code or reference solution in any language")
+	}
+	for _, lang := range languages {
+		starter, solution := strings.TrimSpace(p.StarterCode[lang]), strings.TrimSpace(p.Solutions[lang])
+		switch {
+		case solution == "":
+			messages = append(messages, fmt.Sprintf("starter code in %s but no reference solution", lang))
+		case starter == "":
+			messages = append(messages, fmt.Sprintf("reference solution in %s but no starter code", lang))
+		case starter == solution:
+			messages = append(messages, fmt.Sprintf("the %s reference solution is the same as the starter code", lang))
+		}
+	}
+	return messages
+}
+
+// placeholderFields names the fields of a problem that still hold the
+// scaffold's placeholders
+func placeholderFields(p problem.Problem) []string {
+	has := func(texts ...string) bool {
+		for _, text := range texts {
+			if strings.Contains(text, Placeholder) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var fields []string
+	check := func(field string, texts ...string) {
+		if has(texts...) {
+			fields = append(fields, field)
+		}
+	}
+	check("title", p.Title)
+	check("patterns", p.Patterns...)
+	check("description", p.Description)
+	for _, ex := range p.Examples {
+		if has(ex.Input, ex.Output, ex.Explanation) {
+			fields = append(fields, "examples")
+			break
+		}
+	}
+	check("constraints", p.Constraints...)
+	check("pattern_explanation", p.PatternExplanation)
+	check("solution_walkthrough", p.SolutionWalkthrough...)
+	for _, tc := range p.TestCases {
+		if has(tc.Input, tc.Expected) {
+			fields = append(fields, "test_cases")
+			break
+		}
+	}
+	for _, lang := range packcheck.Languages(p) {
+		check(lang+" reference solution", p.Solutions[lang])
+	}
+	return fields
+}
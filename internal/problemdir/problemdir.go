@@ -0,0 +1,148 @@
+// Package problemdir scaffolds problem directories for authors, and lints
+// problems before they are published
+package problemdir
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// DefaultLanguages are the languages a new problem gets code files in
+// unless told otherwise, those of the built-in problems
+var DefaultLanguages = []string{"go", "python", "java", "cpp"}
+
+// DefaultSignature is the signature a new problem starts from
+const DefaultSignature = "solve(nums int[]) int"
+
+// Placeholder marks what the author still has to write
+const Placeholder = "TODO"
+
+// Options describe a problem to scaffold
+type Options struct {
+	ID         string
+	Title      string // Derived from the ID when empty
+	Pattern    string // Left as a placeholder when empty
+	Difficulty string
+	Signature  *interfaces.FunctionSignature
+	Languages  []string
+}
+
+// problemTemplate is the problem.yaml of a new problem, with placeholders
+// for the author to fill in
+var problemTemplate = template.Must(template.New("problem").Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(`# {{.Title}}
+#
+# Fill in every TODO. Starter code and reference solutions are kept beside
+# this file, in starter.<ext> and solution.<ext>. Check the problem with:
+#   algo-scales author lint {{.Dir}}
+#   algo-scales author preview {{.Dir}}
+#   algo-scales validate-pack {{.Dir}}
+id: {{quote .ID}}
+title: {{quote .Title}}
+difficulty: {{quote .Difficulty}}
+patterns: [{{quote .Pattern}}]
+estimated_time: 20
+companies: []
+description: |
+  TODO: describe the problem.
+examples:
+  - input: "TODO"
+    output: "TODO"
+    explanation: "TODO: why this is the answer"
+constraints:
+  - "TODO: the size and range of the input"
+pattern_explanation: |
+  TODO: explain why the {{.Pattern}} pattern fits the problem.
+solution_walkthrough:
+  - "TODO: the first step of the solution"
+signature:
+  name: {{quote .Signature.Name}}
+  params:{{range .Signature.Params}}
+    - {name: {{quote .Name}}, type: {{quote .Type}}}{{else}} []{{end}}
+  returns: {{quote .Signature.Returns}}
+# Each test gives its input and expected output. The tier is example (run
+# on every test run), or hidden, edge or stress (run only on submission).
+test_cases:
+  - {input: "TODO", expected: "TODO"}
+  - {input: "TODO", expected: "TODO", tier: hidden}
+  - {input: "TODO", expected: "TODO", tier: edge}
+`))
+
+// Scaffold writes a new problem directory under parent, named after the
+// problem, and returns the paths of the files it wrote. It refuses to
+// write into a directory that already exists.
+func Scaffold(parent string, opts Options) ([]string, error) {
+	if !problem.ValidPackName(opts.ID) {
+		return nil, fmt.Errorf("invalid problem id %q: use lowercase letters, digits, '-' and '_'", opts.ID)
+	}
+	if opts.Title == "" {
+		opts.Title = titleFromID(opts.ID)
+	}
+	if opts.Pattern == "" {
+		opts.Pattern = Placeholder
+	}
+	if opts.Signature == nil {
+		opts.Signature, _ = problem.ParseSignature(DefaultSignature)
+	}
+	if len(opts.Languages) == 0 {
+		opts.Languages = DefaultLanguages
+	}
+	for _, lang := range opts.Languages {
+		if _, ok := stubs[lang]; !ok {
+			return nil, fmt.Errorf("no code template for %s; use %s", lang, strings.Join(StubLanguages(), ", "))
+		}
+	}
+
+	dir := filepath.Join(parent, opts.ID)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s already exists", dir)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	var yaml strings.Builder
+	if err := problemTemplate.Execute(&yaml, struct {
+		Options
+		Dir string
+	}{opts, dir}); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", problem.ProblemFile, err)
+	}
+	files := map[string]string{filepath.Join(dir, problem.ProblemFile): yaml.String()}
+	for _, lang := range opts.Languages {
+		files[problem.StarterFile(dir, lang)] = Stub(opts.Signature, lang, false)
+		files[problem.SolutionFile(dir, lang)] = Stub(opts.Signature, lang, true)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := os.WriteFile(path, []byte(files[path]), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	return paths, nil
+}
+
+// titleFromID turns an ID such as pair_sum into a title such as Pair Sum
+func titleFromID(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '_' || r == '-' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,114 @@
+package problemdir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStub(t *testing.T) {
+	sig, err := problem.ParseSignature("twoSum(nums int[], target int) int[]")
+	require.NoError(t, err)
+
+	assert.Equal(t, "func twoSum(nums []int, target int) []int {\n    // Your code here\n    return []int{}\n}\n", Stub(sig, "go", false))
+	assert.Equal(t, "def two_sum(nums, target):\n    # TODO: write the reference solution\n    return []\n", Stub(sig, "python", true))
+	assert.Equal(t, "function twoSum(nums, target) {\n    // Your code here\n    return [];\n}\n", Stub(sig, "javascript", false))
+	assert.Equal(t, "public class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        // Your code here\n        return new int[] {};\n    }\n}\n", Stub(sig, "java", false))
+	assert.Equal(t, "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        // Your code here\n        return {};\n    }\n};\n", Stub(sig, "cpp", false))
+
+	sig, err = problem.ParseSignature("isValid(s string) bool")
+	require.NoError(t, err)
+	assert.Contains(t, Stub(sig, "cpp", false), "bool isValid(string& s)")
+	assert.Contains(t, Stub(sig, "python", false), "return False")
+	assert.Empty(t, Stub(sig, "cobol", false))
+}
+
+func TestScaffold(t *testing.T) {
+	parent := t.TempDir()
+	sig, err := problem.ParseSignature("pairSum(nums int[], target int) int[]")
+	require.NoError(t, err)
+
+	files, err := Scaffold(parent, Options{ID: "pair_sum", Pattern: "two-pointers", Difficulty: "easy", Signature: sig, Languages: []string{"go", "python"}})
+	require.NoError(t, err)
+	dir := filepath.Join(parent, "pair_sum")
+	assert.Equal(t, []string{
+		filepath.Join(dir, "problem.yaml"),
+		filepath.Join(dir, "solution.go"),
+		filepath.Join(dir, "solution.py"),
+		filepath.Join(dir, "starter.go"),
+		filepath.Join(dir, "starter.py"),
+	}, files)
+
+	// The scaffold is a valid pack straight away, with everything left to do
+	pack, err := problem.LoadPack(dir)
+	require.NoError(t, err)
+	require.Len(t, pack.Problems, 1)
+	p := pack.Problems[0]
+	assert.Equal(t, "Pair Sum", p.Title)
+	assert.Equal(t, []string{"two-pointers"}, p.Patterns)
+	assert.Equal(t, sig, p.Signature)
+	assert.Contains(t, p.StarterCode["go"], "func pairSum(nums []int, target int) []int")
+	assert.Len(t, p.TestCases, 3)
+
+	_, err = Scaffold(parent, Options{ID: "pair_sum", Difficulty: "easy"})
+	assert.ErrorContains(t, err, "already exists")
+	_, err = Scaffold(parent, Options{ID: "Pair Sum", Difficulty: "easy"})
+	assert.ErrorContains(t, err, "invalid problem id")
+	_, err = Scaffold(parent, Options{ID: "other", Difficulty: "easy", Languages: []string{"cobol"}})
+	assert.ErrorContains(t, err, "no code template for cobol; use cpp, go, java, javascript, python")
+}
+
+func TestLint(t *testing.T) {
+	parent := t.TempDir()
+	_, err := Scaffold(parent, Options{ID: "pair_sum", Difficulty: "easy", Languages: []string{"go"}})
+	require.NoError(t, err)
+	dir := filepath.Join(parent, "pair_sum")
+
+	report, err := Lint(dir)
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	var messages []string
+	for _, issue := range report.Issues {
+		assert.Equal(t, "pair_sum", issue.ProblemID)
+		messages = append(messages, issue.Message)
+	}
+	assert.Equal(t, []string{
+		"patterns still has TODO placeholders",
+		"description still has TODO placeholders",
+		"examples still has TODO placeholders",
+		"constraints still has TODO placeholders",
+		"pattern_explanation still has TODO placeholders",
+		"solution_walkthrough still has TODO placeholders",
+		"test_cases still has TODO placeholders",
+		"go reference solution still has TODO placeholders",
+	}, messages)
+
+	// A finished problem with a few gaps of a different kind
+	p := problem.Problem{
+		ID: "pair_sum", Title: "Pair Sum", Difficulty: "easy", Patterns: []string{"two-pointers"},
+		Description: "Find a pair.",
+		TestCases:   []problem.TestCase{{Input: "[1,2], 3", Expected: "[0,1]"}},
+		StarterCode: map[string]string{"go": "stub", "python": "def f(): pass"},
+		Solutions:   map[string]string{"go": "stub", "java": "class Solution {}"},
+	}
+	assert.Equal(t, []string{
+		"no signature: tests cannot call the solution",
+		"no examples",
+		"every test is an example; add hidden or edge tests for submissions to run",
+		"the go reference solution is the same as the starter code",
+		"reference solution in java but no starter code",
+		"starter code in python but no reference solution",
+	}, lintProblem(p))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "problem.yaml"), []byte("id: pair_sum\n"), 0644))
+	report, err = Lint(dir)
+	require.NoError(t, err)
+	assert.Empty(t, report.Problems)
+	require.NotEmpty(t, report.Issues)
+	assert.True(t, strings.HasPrefix(report.Issues[0].String(), "problem.yaml:"), report.Issues[0].String())
+}
@@ -0,0 +1,199 @@
+// Starter code and solution stubs written from a function signature
+
+package problemdir
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// stubLanguage writes one language's stubs
+type stubLanguage struct {
+	typeName func(typ string, param bool) string // How a signature type is written
+	zero     func(typ string) string             // A value of the type to return
+	function func(name, params, returns, body string) string
+	comment  string
+	end      string // Ends a statement
+}
+
+// stubs are the languages stubs can be written in
+var stubs = map[string]stubLanguage{
+	"go": {
+		typeName: goType,
+		zero: func(typ string) string {
+			if strings.HasSuffix(typ, "[]") {
+				return goType(typ, false) + "{}"
+			}
+			return map[string]string{"bool": "false", "string": `""`, "TreeNode": "nil", "ListNode": "nil"}[typ]
+		},
+		function: func(name, params, returns, body string) string {
+			return fmt.Sprintf("func %s(%s) %s {\n%s}\n", name, params, returns, body)
+		},
+		comment: "//",
+	},
+	"python": {
+		zero: func(typ string) string {
+			if strings.HasSuffix(typ, "[]") {
+				return "[]"
+			}
+			return map[string]string{"float": "0.0", "bool": "False", "string": `""`, "char": `""`, "TreeNode": "None", "ListNode": "None"}[typ]
+		},
+		function: func(name, params, returns, body string) string {
+			return fmt.Sprintf("def %s(%s):\n%s", snakeCase(name), params, body)
+		},
+		comment: "#",
+	},
+	"javascript": {
+		zero: func(typ string) string {
+			if strings.HasSuffix(typ, "[]") {
+				return "[]"
+			}
+			return map[string]string{"bool": "false", "string": `""`, "char": `""`, "TreeNode": "null", "ListNode": "null"}[typ]
+		},
+		function: func(name, params, returns, body string) string {
+			return fmt.Sprintf("function %s(%s) {\n%s}\n", name, params, body)
+		},
+		comment: "//",
+		end:     ";",
+	},
+	"java": {
+		typeName: javaType,
+		zero: func(typ string) string {
+			if strings.HasSuffix(typ, "[]") {
+				return "new " + javaType(typ, false) + " {}"
+			}
+			return map[string]string{"float": "0.0", "bool": "false", "string": `""`, "char": `' '`, "TreeNode": "null", "ListNode": "null"}[typ]
+		},
+		function: func(name, params, returns, body string) string {
+			return fmt.Sprintf("public class Solution {\n    public %s %s(%s) {\n%s    }\n}\n", returns, name, params, indent(body))
+		},
+		comment: "//",
+		end:     ";",
+	},
+	"cpp": {
+		typeName: func(typ string, param bool) string {
+			name := arrayType(typ, func(elem string) string { return "vector<" + elem + ">" }, map[string]string{
+				"int": "int", "float": "double", "bool": "bool", "string": "string", "char": "char",
+				"TreeNode": "TreeNode*", "ListNode": "ListNode*", "cycle": "int",
+			})
+			if param && (strings.HasSuffix(typ, "[]") || typ == "string") {
+				name += "&"
+			}
+			return name
+		},
+		zero: func(typ string) string {
+			if strings.HasSuffix(typ, "[]") {
+				return "{}"
+			}
+			return map[string]string{"float": "0.0", "bool": "false", "string": `""`, "char": `' '`, "TreeNode": "nullptr", "ListNode": "nullptr"}[typ]
+		},
+		function: func(name, params, returns, body string) string {
+			return fmt.Sprintf("class Solution {\npublic:\n    %s %s(%s) {\n%s    }\n};\n", returns, name, params, indent(body))
+		},
+		comment: "//",
+		end:     ";",
+	},
+}
+
+// StubLanguages returns the languages stubs can be written in, sorted
+func StubLanguages() []string {
+	languages := make([]string, 0, len(stubs))
+	for lang := range stubs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Stub writes the function a signature describes in a language, returning
+// a placeholder value: starter code to hand to the solver, or, if solution
+// is set, a reference solution still to be written
+func Stub(sig *interfaces.FunctionSignature, language string, solution bool) string {
+	lang, ok := stubs[language]
+	if !ok || sig == nil {
+		return ""
+	}
+
+	params := make([]string, len(sig.Params))
+	for i, p := range sig.Params {
+		switch {
+		case lang.typeName == nil:
+			params[i] = p.Name
+		case language == "go":
+			params[i] = p.Name + " " + lang.typeName(p.Type, true)
+		default:
+			params[i] = lang.typeName(p.Type, true) + " " + p.Name
+		}
+	}
+	var returns string
+	if lang.typeName != nil {
+		returns = lang.typeName(sig.Returns, false)
+	}
+
+	note := "Your code here"
+	if solution {
+		note = Placeholder + ": write the reference solution"
+	}
+	zero := lang.zero(sig.Returns)
+	if zero == "" {
+		zero = "0"
+	}
+	body := fmt.Sprintf("    %s %s\n    return %s%s\n", lang.comment, note, zero, lang.end)
+	return lang.function(sig.Name, strings.Join(params, ", "), returns, body)
+}
+
+// goType writes a signature type in Go
+func goType(typ string, param bool) string {
+	return arrayType(typ, func(elem string) string { return "[]" + elem }, map[string]string{
+		"int": "int", "float": "float64", "bool": "bool", "string": "string", "char": "byte",
+		"TreeNode": "*TreeNode", "ListNode": "*ListNode", "cycle": "int",
+	})
+}
+
+// javaType writes a signature type in Java
+func javaType(typ string, param bool) string {
+	return arrayType(typ, func(elem string) string { return elem + "[]" }, map[string]string{
+		"int": "int", "float": "double", "bool": "boolean", "string": "String", "char": "char",
+		"TreeNode": "TreeNode", "ListNode": "ListNode", "cycle": "int",
+	})
+}
+
+// arrayType writes a signature type, building arrays from their elements
+func arrayType(typ string, array func(elem string) string, scalars map[string]string) string {
+	if elem, ok := strings.CutSuffix(typ, "[]"); ok {
+		return array(arrayType(elem, array, scalars))
+	}
+	if name, ok := scalars[typ]; ok {
+		return name
+	}
+	return typ
+}
+
+// indent indents each line of a function body one more level
+func indent(body string) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(body, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "") + "\n"
+}
+
+// snakeCase turns a camelCase name into snake_case, as Python solutions
+// are named
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+// Package quiz runs the pattern quiz: a problem's description is shown with
+// a few patterns to choose from, before any code is written, and the answers
+// are kept to measure how well each pattern is recognised
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// DefaultQuestions is how many questions a quiz asks unless told otherwise
+const DefaultQuestions = 10
+
+// DefaultChoices is how many patterns each question offers unless told
+// otherwise
+const DefaultChoices = 4
+
+// Question asks which pattern solves a problem
+type Question struct {
+	Problem problem.Problem
+	Pattern string   // The right answer, one of the problem's patterns
+	Choices []string // The patterns offered, Pattern among them
+}
+
+// Questions picks up to n problems at random, of pattern alone if it is not
+// empty, and asks about each with up to choices patterns to choose from.
+// The wrong choices are patterns of other problems that this problem does
+// not have, so exactly one choice is right.
+func Questions(problems []problem.Problem, pattern string, n, choices int) []Question {
+	var all []string
+	seen := make(map[string]bool)
+	var candidates []problem.Problem
+	for _, p := range problems {
+		for _, pt := range p.Patterns {
+			if !seen[pt] {
+				seen[pt] = true
+				all = append(all, pt)
+			}
+		}
+		if len(p.Patterns) > 0 && (pattern == "" || contains(p.Patterns, pattern)) {
+			candidates = append(candidates, p)
+		}
+	}
+	sort.Strings(all)
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+
+	questions := make([]Question, 0, len(candidates))
+	for _, p := range candidates {
+		answer := pattern
+		if answer == "" {
+			answer = p.Patterns[rand.Intn(len(p.Patterns))]
+		}
+		var wrong []string
+		for _, pt := range all {
+			if !contains(p.Patterns, pt) {
+				wrong = append(wrong, pt)
+			}
+		}
+		rand.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+		if len(wrong) > choices-1 {
+			wrong = wrong[:max(choices-1, 0)]
+		}
+		offered := append([]string{answer}, wrong...)
+		rand.Shuffle(len(offered), func(i, j int) { offered[i], offered[j] = offered[j], offered[i] })
+		questions = append(questions, Question{Problem: p, Pattern: answer, Choices: offered})
+	}
+	return questions
+}
+
+// Answer records choosing a pattern for the question
+func (q Question) Answer(chosen string, at time.Time) storage.QuizAnswer {
+	return storage.QuizAnswer{
+		ProblemID: q.Problem.ID,
+		Pattern:   q.Pattern,
+		Chosen:    chosen,
+		Correct:   chosen == q.Pattern,
+		Time:      at,
+	}
+}
+
+// Accuracy is how well a pattern is recognised in the quiz
+type Accuracy struct {
+	Pattern  string
+	Answered int    // Questions whose right answer was the pattern
+	Correct  int    // Of those, the ones answered right
+	Mistaken string // The wrong answer given most often, if any
+}
+
+// Rate is the percentage of questions on the pattern answered right
+func (a Accuracy) Rate() float64 {
+	if a.Answered == 0 {
+		return 0
+	}
+	return float64(a.Correct) / float64(a.Answered) * 100
+}
+
+// ByPattern totals answers by the pattern that was right, ordered by
+// pattern
+func ByPattern(answers []storage.QuizAnswer) []Accuracy {
+	totals := make(map[string]*Accuracy)
+	wrong := make(map[string]map[string]int)
+	for _, a := range answers {
+		acc, ok := totals[a.Pattern]
+		if !ok {
+			acc = &Accuracy{Pattern: a.Pattern}
+			totals[a.Pattern] = acc
+			wrong[a.Pattern] = make(map[string]int)
+		}
+		acc.Answered++
+		if a.Correct {
+			acc.Correct++
+		} else {
+			wrong[a.Pattern][a.Chosen]++
+		}
+	}
+
+	accuracy := make([]Accuracy, 0, len(totals))
+	for pattern, acc := range totals {
+		best := 0
+		for chosen, n := range wrong[pattern] {
+			if n > best || (n == best && chosen < acc.Mistaken) {
+				acc.Mistaken, best = chosen, n
+			}
+		}
+		accuracy = append(accuracy, *acc)
+	}
+	sort.Slice(accuracy, func(i, j int) bool { return accuracy[i].Pattern < accuracy[j].Pattern })
+	return accuracy
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
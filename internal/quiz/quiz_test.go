@@ -0,0 +1,68 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testProblems = []problem.Problem{
+	{ID: "two_sum", Patterns: []string{"hash-map", "two-pointers"}},
+	{ID: "max_window", Patterns: []string{"sliding-window"}},
+	{ID: "coin_change", Patterns: []string{"dynamic-programming"}},
+	{ID: "islands", Patterns: []string{"dfs", "bfs"}},
+	{ID: "untagged"},
+}
+
+func TestQuestions(t *testing.T) {
+	questions := Questions(testProblems, "", 10, 3)
+	require.Len(t, questions, 4, "problems without a pattern are not asked about")
+	for _, q := range questions {
+		assert.Len(t, q.Choices, 3)
+		assert.Contains(t, q.Choices, q.Pattern)
+		assert.Contains(t, q.Problem.Patterns, q.Pattern)
+		right := 0
+		for _, choice := range q.Choices {
+			if contains(q.Problem.Patterns, choice) {
+				right++
+			}
+		}
+		assert.Equal(t, 1, right, "only one choice is right for %s", q.Problem.ID)
+	}
+
+	questions = Questions(testProblems, "two-pointers", 10, 10)
+	require.Len(t, questions, 1)
+	assert.Equal(t, "two-pointers", questions[0].Pattern)
+	assert.Len(t, questions[0].Choices, 5, "every pattern the problem does not have, and the answer")
+
+	assert.Len(t, Questions(testProblems, "", 2, 4), 2)
+}
+
+func TestAnswer(t *testing.T) {
+	q := Question{Problem: testProblems[1], Pattern: "sliding-window"}
+	at := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, storage.QuizAnswer{ProblemID: "max_window", Pattern: "sliding-window", Chosen: "sliding-window", Correct: true, Time: at}, q.Answer("sliding-window", at))
+	assert.False(t, q.Answer("two-pointers", at).Correct)
+}
+
+func TestByPattern(t *testing.T) {
+	answers := []storage.QuizAnswer{
+		{Pattern: "sliding-window", Chosen: "sliding-window", Correct: true},
+		{Pattern: "sliding-window", Chosen: "two-pointers"},
+		{Pattern: "sliding-window", Chosen: "two-pointers"},
+		{Pattern: "sliding-window", Chosen: "dfs"},
+		{Pattern: "hash-map", Chosen: "hash-map", Correct: true},
+	}
+
+	accuracy := ByPattern(answers)
+	require.Len(t, accuracy, 2)
+	assert.Equal(t, Accuracy{Pattern: "hash-map", Answered: 1, Correct: 1}, accuracy[0])
+	assert.Equal(t, Accuracy{Pattern: "sliding-window", Answered: 4, Correct: 1, Mistaken: "two-pointers"}, accuracy[1])
+	assert.Equal(t, 25.0, accuracy[1].Rate())
+	assert.Zero(t, Accuracy{}.Rate())
+}
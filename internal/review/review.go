@@ -0,0 +1,145 @@
+// Package review schedules previously practiced problems for spaced
+// repetition using the SM-2 algorithm. Every recorded session grades how well
+// the problem went; problems that were a struggle come back soon, problems
+// solved cleanly come back at growing intervals.
+package review
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+const (
+	// DefaultEase is the ease factor of a problem reviewed for the first time
+	DefaultEase = 2.5
+
+	// MinEase keeps intervals of hard problems from collapsing
+	MinEase = 1.3
+
+	// PassingQuality is the lowest grade that counts as remembered
+	PassingQuality = 3
+)
+
+// day is the unit intervals are measured in
+const day = 24 * time.Hour
+
+// EstimateFor returns a problem's estimated solve time, zero if unknown
+// Exported as variable for testing
+var EstimateFor = func(problemID string) time.Duration {
+	problems, err := problem.ListAll()
+	if err != nil {
+		return 0
+	}
+	for _, p := range problems {
+		if p.ID == problemID {
+			return time.Duration(p.EstimatedTime) * time.Minute
+		}
+	}
+	return 0
+}
+
+// Grade rates a session on the SM-2 scale of 0 to 5:
+//
+//	0  gave up and looked at the solution
+//	1  not solved, or swapped for an easier problem
+//	3  solved, but needed hints
+//	4  solved without hints, slower than the estimate
+//	5  solved without hints within the estimate
+func Grade(s interfaces.SessionStats, estimate time.Duration) int {
+	switch {
+	case s.SolutionUsed:
+		return 0
+	case !s.Solved:
+		return 1
+	case s.HintsUsed:
+		return 3
+	case estimate > 0 && s.Duration > estimate:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// Schedule applies a review of the given quality at now to a problem's
+// review state. A zero Review is treated as never reviewed.
+func Schedule(r storage.Review, quality int, now time.Time) storage.Review {
+	if r.Ease == 0 {
+		r.Ease = DefaultEase
+	}
+
+	if quality < PassingQuality {
+		if r.Repetitions > 0 {
+			r.Lapses++
+		}
+		r.Repetitions = 0
+		r.Interval = 1
+	} else {
+		switch r.Repetitions {
+		case 0:
+			r.Interval = 1
+		case 1:
+			r.Interval = 6
+		default:
+			r.Interval = int(math.Round(float64(r.Interval) * r.Ease))
+		}
+		r.Repetitions++
+	}
+
+	q := float64(5 - quality)
+	r.Ease = math.Max(MinEase, r.Ease+0.1-q*(0.08+q*0.02))
+	r.LastReviewed = now
+	r.Due = now.Add(time.Duration(r.Interval) * day)
+	return r
+}
+
+// Record grades a session and reschedules its problem
+func Record(ctx context.Context, repo storage.Repository, s interfaces.SessionStats) error {
+	if s.ProblemID == "" {
+		return nil
+	}
+	reviews, err := repo.LoadReviews(ctx)
+	if err != nil {
+		return err
+	}
+	current := storage.Review{ProblemID: s.ProblemID}
+	for _, r := range reviews {
+		if r.ProblemID == s.ProblemID {
+			current = r
+			break
+		}
+	}
+
+	reviewedAt := s.EndTime
+	if reviewedAt.IsZero() {
+		reviewedAt = time.Now()
+	}
+	return repo.SaveReview(ctx, Schedule(current, Grade(s, EstimateFor(s.ProblemID)), reviewedAt))
+}
+
+// Due returns the problems due for review at now, most overdue first.
+// Problems equally overdue are ordered hardest to remember first.
+func Due(ctx context.Context, repo storage.Repository, now time.Time) ([]storage.Review, error) {
+	reviews, err := repo.LoadReviews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	due := []storage.Review{}
+	for _, r := range reviews {
+		if !r.Due.After(now) {
+			due = append(due, r)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		if !due[i].Due.Equal(due[j].Due) {
+			return due[i].Due.Before(due[j].Due)
+		}
+		return due[i].Ease < due[j].Ease
+	})
+	return due, nil
+}
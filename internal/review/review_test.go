@@ -0,0 +1,79 @@
+package review
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrade(t *testing.T) {
+	estimate := 20 * time.Minute
+	assert.Equal(t, 0, Grade(interfaces.SessionStats{SolutionUsed: true, Solved: true}, estimate))
+	assert.Equal(t, 1, Grade(interfaces.SessionStats{}, estimate))
+	assert.Equal(t, 1, Grade(interfaces.SessionStats{SwappedTo: "two_sum"}, estimate))
+	assert.Equal(t, 3, Grade(interfaces.SessionStats{Solved: true, HintsUsed: true}, estimate))
+	assert.Equal(t, 4, Grade(interfaces.SessionStats{Solved: true, Duration: 30 * time.Minute}, estimate))
+	assert.Equal(t, 5, Grade(interfaces.SessionStats{Solved: true, Duration: 10 * time.Minute}, estimate))
+	assert.Equal(t, 5, Grade(interfaces.SessionStats{Solved: true, Duration: time.Hour}, 0), "no estimate to be slow against")
+}
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	r := Schedule(storage.Review{ProblemID: "two_sum"}, 5, now)
+	assert.Equal(t, 1, r.Interval)
+	assert.Equal(t, 1, r.Repetitions)
+	assert.InDelta(t, 2.6, r.Ease, 1e-9)
+	assert.True(t, r.Due.Equal(now.AddDate(0, 0, 1)))
+
+	r = Schedule(r, 4, now)
+	assert.Equal(t, 6, r.Interval)
+	r = Schedule(r, 4, now)
+	assert.Equal(t, 16, r.Interval, "6 days times ease 2.6")
+	assert.Equal(t, 3, r.Repetitions)
+
+	// Forgetting starts the problem over and lowers its ease
+	r = Schedule(r, 1, now)
+	assert.Equal(t, 1, r.Interval)
+	assert.Equal(t, 0, r.Repetitions)
+	assert.Equal(t, 1, r.Lapses)
+	assert.InDelta(t, 2.06, r.Ease, 1e-9)
+
+	for i := 0; i < 10; i++ {
+		r = Schedule(r, 0, now)
+	}
+	assert.Equal(t, MinEase, r.Ease)
+	assert.Equal(t, 1, r.Lapses, "failing an unlearned problem is not a lapse")
+}
+
+func TestRecordAndDue(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	defer store.Close()
+	ctx := context.Background()
+
+	original := EstimateFor
+	defer func() { EstimateFor = original }()
+	EstimateFor = func(string) time.Duration { return 20 * time.Minute }
+
+	day := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, Record(ctx, store, interfaces.SessionStats{ProblemID: "two_sum", Solved: true, Duration: 5 * time.Minute, EndTime: day}))
+	require.NoError(t, Record(ctx, store, interfaces.SessionStats{ProblemID: "two_sum", Solved: true, Duration: 5 * time.Minute, EndTime: day.AddDate(0, 0, 1)}))
+	require.NoError(t, Record(ctx, store, interfaces.SessionStats{ProblemID: "3sum", EndTime: day}))
+	require.NoError(t, Record(ctx, store, interfaces.SessionStats{ProblemID: "lru_cache", Solved: true, HintsUsed: true, EndTime: day}))
+
+	due, err := Due(ctx, store, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, due, 2, "two_sum is not due again for six days")
+	assert.Equal(t, "3sum", due[0].ProblemID, "equally overdue, the harder one first")
+	assert.Equal(t, "lru_cache", due[1].ProblemID)
+
+	due, err = Due(ctx, store, day.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	assert.Len(t, due, 3)
+}
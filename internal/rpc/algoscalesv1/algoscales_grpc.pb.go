@@ -0,0 +1,327 @@
+// gRPC service definition for the AlgoScales server.
+//
+// This mirrors the REST API under /v1 for editor plugins and the daemon.
+// Regenerate the Go code with `make proto`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: algoscales/v1/algoscales.proto
+
+package algoscalesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	AlgoScales_ListProblems_FullMethodName    = "/algoscales.v1.AlgoScales/ListProblems"
+	AlgoScales_GetProblem_FullMethodName      = "/algoscales.v1.AlgoScales/GetProblem"
+	AlgoScales_ValidateLicense_FullMethodName = "/algoscales.v1.AlgoScales/ValidateLicense"
+	AlgoScales_RegisterLicense_FullMethodName = "/algoscales.v1.AlgoScales/RegisterLicense"
+	AlgoScales_Sync_FullMethodName            = "/algoscales.v1.AlgoScales/Sync"
+	AlgoScales_GetLeaderboard_FullMethodName  = "/algoscales.v1.AlgoScales/GetLeaderboard"
+)
+
+// AlgoScalesClient is the client API for AlgoScales service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AlgoScales serves problems, licenses, sync and the leaderboard.
+//
+// Every RPC except ValidateLicense and RegisterLicense requires an
+// "authorization: Bearer <license key>" metadata entry.
+type AlgoScalesClient interface {
+	// ListProblems returns the full problem set, optionally filtered.
+	ListProblems(ctx context.Context, in *ListProblemsRequest, opts ...grpc.CallOption) (*ListProblemsResponse, error)
+	// GetProblem returns a single problem by ID.
+	GetProblem(ctx context.Context, in *GetProblemRequest, opts ...grpc.CallOption) (*Problem, error)
+	// ValidateLicense reports whether a license key is valid.
+	ValidateLicense(ctx context.Context, in *ValidateLicenseRequest, opts ...grpc.CallOption) (*ValidateLicenseResponse, error)
+	// RegisterLicense issues a new license.
+	RegisterLicense(ctx context.Context, in *RegisterLicenseRequest, opts ...grpc.CallOption) (*RegisterLicenseResponse, error)
+	// Sync returns the problems that changed since the client's version.
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+	// GetLeaderboard returns the top users by problems solved.
+	GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error)
+}
+
+type algoScalesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlgoScalesClient(cc grpc.ClientConnInterface) AlgoScalesClient {
+	return &algoScalesClient{cc}
+}
+
+func (c *algoScalesClient) ListProblems(ctx context.Context, in *ListProblemsRequest, opts ...grpc.CallOption) (*ListProblemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProblemsResponse)
+	err := c.cc.Invoke(ctx, AlgoScales_ListProblems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *algoScalesClient) GetProblem(ctx context.Context, in *GetProblemRequest, opts ...grpc.CallOption) (*Problem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Problem)
+	err := c.cc.Invoke(ctx, AlgoScales_GetProblem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *algoScalesClient) ValidateLicense(ctx context.Context, in *ValidateLicenseRequest, opts ...grpc.CallOption) (*ValidateLicenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateLicenseResponse)
+	err := c.cc.Invoke(ctx, AlgoScales_ValidateLicense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *algoScalesClient) RegisterLicense(ctx context.Context, in *RegisterLicenseRequest, opts ...grpc.CallOption) (*RegisterLicenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterLicenseResponse)
+	err := c.cc.Invoke(ctx, AlgoScales_RegisterLicense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *algoScalesClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncResponse)
+	err := c.cc.Invoke(ctx, AlgoScales_Sync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *algoScalesClient) GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLeaderboardResponse)
+	err := c.cc.Invoke(ctx, AlgoScales_GetLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AlgoScalesServer is the server API for AlgoScales service.
+// All implementations must embed UnimplementedAlgoScalesServer
+// for forward compatibility
+//
+// AlgoScales serves problems, licenses, sync and the leaderboard.
+//
+// Every RPC except ValidateLicense and RegisterLicense requires an
+// "authorization: Bearer <license key>" metadata entry.
+type AlgoScalesServer interface {
+	// ListProblems returns the full problem set, optionally filtered.
+	ListProblems(context.Context, *ListProblemsRequest) (*ListProblemsResponse, error)
+	// GetProblem returns a single problem by ID.
+	GetProblem(context.Context, *GetProblemRequest) (*Problem, error)
+	// ValidateLicense reports whether a license key is valid.
+	ValidateLicense(context.Context, *ValidateLicenseRequest) (*ValidateLicenseResponse, error)
+	// RegisterLicense issues a new license.
+	RegisterLicense(context.Context, *RegisterLicenseRequest) (*RegisterLicenseResponse, error)
+	// Sync returns the problems that changed since the client's version.
+	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
+	// GetLeaderboard returns the top users by problems solved.
+	GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error)
+	mustEmbedUnimplementedAlgoScalesServer()
+}
+
+// UnimplementedAlgoScalesServer must be embedded to have forward compatible implementations.
+type UnimplementedAlgoScalesServer struct {
+}
+
+func (UnimplementedAlgoScalesServer) ListProblems(context.Context, *ListProblemsRequest) (*ListProblemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProblems not implemented")
+}
+func (UnimplementedAlgoScalesServer) GetProblem(context.Context, *GetProblemRequest) (*Problem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProblem not implemented")
+}
+func (UnimplementedAlgoScalesServer) ValidateLicense(context.Context, *ValidateLicenseRequest) (*ValidateLicenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateLicense not implemented")
+}
+func (UnimplementedAlgoScalesServer) RegisterLicense(context.Context, *RegisterLicenseRequest) (*RegisterLicenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterLicense not implemented")
+}
+func (UnimplementedAlgoScalesServer) Sync(context.Context, *SyncRequest) (*SyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedAlgoScalesServer) GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedAlgoScalesServer) mustEmbedUnimplementedAlgoScalesServer() {}
+
+// UnsafeAlgoScalesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlgoScalesServer will
+// result in compilation errors.
+type UnsafeAlgoScalesServer interface {
+	mustEmbedUnimplementedAlgoScalesServer()
+}
+
+func RegisterAlgoScalesServer(s grpc.ServiceRegistrar, srv AlgoScalesServer) {
+	s.RegisterService(&AlgoScales_ServiceDesc, srv)
+}
+
+func _AlgoScales_ListProblems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProblemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).ListProblems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_ListProblems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).ListProblems(ctx, req.(*ListProblemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlgoScales_GetProblem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProblemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).GetProblem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_GetProblem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).GetProblem(ctx, req.(*GetProblemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlgoScales_ValidateLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateLicenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).ValidateLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_ValidateLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).ValidateLicense(ctx, req.(*ValidateLicenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlgoScales_RegisterLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterLicenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).RegisterLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_RegisterLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).RegisterLicense(ctx, req.(*RegisterLicenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlgoScales_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_Sync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlgoScales_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlgoScalesServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlgoScales_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlgoScalesServer).GetLeaderboard(ctx, req.(*GetLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AlgoScales_ServiceDesc is the grpc.ServiceDesc for AlgoScales service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AlgoScales_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "algoscales.v1.AlgoScales",
+	HandlerType: (*AlgoScalesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProblems",
+			Handler:    _AlgoScales_ListProblems_Handler,
+		},
+		{
+			MethodName: "GetProblem",
+			Handler:    _AlgoScales_GetProblem_Handler,
+		},
+		{
+			MethodName: "ValidateLicense",
+			Handler:    _AlgoScales_ValidateLicense_Handler,
+		},
+		{
+			MethodName: "RegisterLicense",
+			Handler:    _AlgoScales_RegisterLicense_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _AlgoScales_Sync_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _AlgoScales_GetLeaderboard_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "algoscales/v1/algoscales.proto",
+}
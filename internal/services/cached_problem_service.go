@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// CachedProblemService keeps the problem list in memory for long-running
+// processes, such as the daemon, reloading it once it is older than ttl
+type CachedProblemService struct {
+	ProblemService
+
+	ttl      time.Duration
+	mu       sync.Mutex
+	problems []problem.Problem
+	byID     map[string]problem.Problem
+	loadedAt time.Time
+}
+
+// NewCachedProblemService caches the problems another service lists
+func NewCachedProblemService(inner ProblemService, ttl time.Duration) *CachedProblemService {
+	return &CachedProblemService{ProblemService: inner, ttl: ttl}
+}
+
+// ListAll returns all available problems, from memory when the list is fresh
+func (s *CachedProblemService) ListAll(ctx context.Context) ([]problem.Problem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return append([]problem.Problem(nil), s.problems...), nil
+}
+
+// GetByID retrieves a specific problem by ID, from memory when the list is
+// fresh. Problems missing from the list are looked up as usual, which may
+// fetch them from the server.
+func (s *CachedProblemService) GetByID(ctx context.Context, id string) (*problem.Problem, error) {
+	s.mu.Lock()
+	err := s.load(ctx)
+	p, ok := s.byID[id]
+	s.mu.Unlock()
+	if err == nil && ok {
+		return &p, nil
+	}
+	return s.ProblemService.GetByID(ctx, id)
+}
+
+// Invalidate drops the cached list, so the next request reloads it
+func (s *CachedProblemService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// load reloads the problem list if it is stale. s.mu must be held.
+func (s *CachedProblemService) load(ctx context.Context) error {
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.ttl {
+		return nil
+	}
+	problems, err := s.ProblemService.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	s.problems = problems
+	s.byID = make(map[string]problem.Problem, len(problems))
+	for _, p := range problems {
+		s.byID[p.ID] = p
+	}
+	s.loadedAt = time.Now()
+	return nil
+}
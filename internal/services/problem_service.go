@@ -184,6 +184,7 @@ func (s *ProblemServiceImpl) convertFromInterface(p interfaces.Problem) problem.
 		testCases[i] = problem.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -201,6 +202,7 @@ func (s *ProblemServiceImpl) convertFromInterface(p interfaces.Problem) problem.
 		ID:                  p.ID,
 		Title:               p.Title,
 		Description:         p.Description,
+		Signature:           p.Signature,
 		Difficulty:          p.Difficulty,
 		Patterns:            p.Tags, // Map Tags to Patterns
 		Companies:           p.Companies,
@@ -1,17 +1,76 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 )
 
-// ServiceRegistry holds all business logic services for the application
+// Service names, in the order the registry initializes them
+const (
+	ProblemServiceName = "problems"
+	SessionServiceName = "sessions"
+	StatsServiceName   = "stats"
+)
+
+// ServiceState is how far a service has got
+type ServiceState string
+
+const (
+	// StateNotStarted is a service nothing has asked for yet
+	StateNotStarted ServiceState = "not started"
+	// StateReady is a service that is in use or passed its check
+	StateReady ServiceState = "ready"
+	// StateFailed is a service whose last check, or a dependency's, failed
+	StateFailed ServiceState = "failed"
+)
+
+// Sources of a service's implementation
+const (
+	sourceConfigured = "configured"
+)
+
+// ServiceStatus describes a service of the registry, for diagnosing
+// integration problems
+type ServiceStatus struct {
+	Name           string        `json:"name"`
+	State          ServiceState  `json:"state"`
+	DependsOn      []string      `json:"depends_on,omitempty"`
+	Source         string        `json:"source,omitempty"`         // Where the implementation came from
+	Implementation string        `json:"implementation,omitempty"` // Its Go type
+	InitTime       time.Duration `json:"init_time_ns,omitempty"`   // How long building the default took
+	LastError      string        `json:"last_error,omitempty"`
+	LastErrorAt    time.Time     `json:"last_error_at,omitzero"`
+}
+
+// serviceEntry is a service of the registry and how to make it
+type serviceEntry struct {
+	name      string
+	dependsOn []string
+	// build makes the default implementation, returning where it came from.
+	// Its dependencies are ready when it is called.
+	build func(r *ServiceRegistry) (any, string)
+	// check tells whether the service works, or is nil if there is nothing
+	// to check beyond the dependencies
+	check func(ctx context.Context, service any) error
+
+	service   any
+	source    string
+	state     ServiceState
+	initTime  time.Duration
+	lastErr   error
+	lastErrAt time.Time
+}
+
+// ServiceRegistry holds all business logic services for the application.
+// Services are built on first use, after the services they depend on.
 type ServiceRegistry struct {
-	problemService     ProblemService
-	sessionService     SessionService
-	statsService       StatsCommandService
-	mutex              sync.RWMutex
+	entries map[string]*serviceEntry
+	order   []string // Registration order, dependencies first
+	mutex   sync.Mutex
 }
 
 // Global default service registry
@@ -19,70 +78,185 @@ var DefaultRegistry = NewServiceRegistry()
 
 // NewServiceRegistry creates a new service registry
 func NewServiceRegistry() *ServiceRegistry {
-	return &ServiceRegistry{}
+	r := &ServiceRegistry{entries: make(map[string]*serviceEntry)}
+	r.register(&serviceEntry{
+		name: ProblemServiceName,
+		build: func(*ServiceRegistry) (any, string) {
+			return NewProblemService(nil), "default, problem files on disk"
+		},
+		check: func(ctx context.Context, service any) error {
+			problems, err := service.(ProblemService).ListAll(ctx)
+			if err != nil {
+				return err
+			}
+			if len(problems) == 0 {
+				return fmt.Errorf("no problems found")
+			}
+			return nil
+		},
+	})
+	r.register(&serviceEntry{
+		name:      SessionServiceName,
+		dependsOn: []string{ProblemServiceName},
+		build: func(r *ServiceRegistry) (any, string) {
+			return NewSessionService(r.get(ProblemServiceName).(ProblemService), nil), "default, session package"
+		},
+	})
+	r.register(&serviceEntry{
+		name: StatsServiceName,
+		build: func(*ServiceRegistry) (any, string) {
+			return NewStatsCommandService(nil), "default, placeholder statistics"
+		},
+		check: func(ctx context.Context, service any) error {
+			_, err := service.(StatsCommandService).GetOverallStats(ctx)
+			return err
+		},
+	})
+	return r
 }
 
-// WithProblemService sets the problem service
-func (r *ServiceRegistry) WithProblemService(service ProblemService) *ServiceRegistry {
+// register adds a service, after the services it depends on
+func (r *ServiceRegistry) register(e *serviceEntry) {
+	e.state = StateNotStarted
+	r.entries[e.name] = e
+	r.order = append(r.order, e.name)
+}
+
+// get returns a service, building it and its dependencies first if
+// nothing has yet. The caller holds the mutex.
+func (r *ServiceRegistry) get(name string) any {
+	e := r.entries[name]
+	if e.service != nil {
+		return e.service
+	}
+	for _, dep := range e.dependsOn {
+		r.get(dep)
+	}
+	started := time.Now()
+	e.service, e.source = e.build(r)
+	e.initTime = time.Since(started)
+	e.state = StateReady
+	return e.service
+}
+
+// set replaces a service. Services built from the one it replaces are
+// rebuilt on their next use, unless they were configured too.
+func (r *ServiceRegistry) set(name string, service any) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	r.problemService = service
+	e := r.entries[name]
+	e.service, e.source, e.state, e.initTime = service, sourceConfigured, StateReady, 0
+	r.resetDependents(name)
+}
+
+// resetDependents forgets the default services that depend on name. The
+// caller holds the mutex.
+func (r *ServiceRegistry) resetDependents(name string) {
+	for _, other := range r.order {
+		e := r.entries[other]
+		for _, dep := range e.dependsOn {
+			if dep == name && e.service != nil && e.source != sourceConfigured {
+				e.service, e.source, e.state, e.initTime = nil, "", StateNotStarted, 0
+				r.resetDependents(other)
+			}
+		}
+	}
+}
+
+// WithProblemService sets the problem service
+func (r *ServiceRegistry) WithProblemService(service ProblemService) *ServiceRegistry {
+	r.set(ProblemServiceName, service)
 	return r
 }
 
 // WithSessionService sets the session service
 func (r *ServiceRegistry) WithSessionService(service SessionService) *ServiceRegistry {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	r.sessionService = service
+	r.set(SessionServiceName, service)
 	return r
 }
 
 // WithStatsService sets the stats service
 func (r *ServiceRegistry) WithStatsService(service StatsCommandService) *ServiceRegistry {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	r.statsService = service
+	r.set(StatsServiceName, service)
 	return r
 }
 
 // GetProblemService returns the problem service, creating a default if none exists
 func (r *ServiceRegistry) GetProblemService() ProblemService {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-	
-	if r.problemService == nil {
-		// Create default service with nil repository (will cause fallback behavior)
-		r.problemService = NewProblemService(nil)
-	}
-	
-	return r.problemService
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.get(ProblemServiceName).(ProblemService)
 }
 
 // GetSessionService returns the session service, creating a default if none exists
 func (r *ServiceRegistry) GetSessionService() SessionService {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-	
-	if r.sessionService == nil {
-		// Create default service
-		r.sessionService = NewSessionService(r.GetProblemService(), nil)
-	}
-	
-	return r.sessionService
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.get(SessionServiceName).(SessionService)
 }
 
 // GetStatsService returns the stats service, creating a default if none exists
 func (r *ServiceRegistry) GetStatsService() StatsCommandService {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-	
-	if r.statsService == nil {
-		// Create default legacy stats service
-		r.statsService = NewStatsCommandService(nil)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.get(StatsServiceName).(StatsCommandService)
+}
+
+// Status describes each service as it is, without building any, in
+// dependency order
+func (r *ServiceRegistry) Status() []ServiceStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	statuses := make([]ServiceStatus, 0, len(r.order))
+	for _, name := range r.order {
+		e := r.entries[name]
+		status := ServiceStatus{
+			Name:      e.name,
+			State:     e.state,
+			DependsOn: e.dependsOn,
+			Source:    e.source,
+			InitTime:  e.initTime,
+		}
+		if e.service != nil {
+			status.Implementation = fmt.Sprintf("%T", e.service)
+		}
+		if e.lastErr != nil {
+			status.LastError, status.LastErrorAt = e.lastErr.Error(), e.lastErrAt
+		}
+		statuses = append(statuses, status)
 	}
-	
-	return r.statsService
+	return statuses
+}
+
+// Check builds every service in dependency order and checks that each
+// works. A service fails if its check does or a dependency failed. It
+// returns the resulting status of each service.
+func (r *ServiceRegistry) Check(ctx context.Context) []ServiceStatus {
+	r.mutex.Lock()
+	for _, name := range r.order {
+		e := r.entries[name]
+		service := r.get(name)
+
+		var err error
+		for _, dep := range e.dependsOn {
+			if r.entries[dep].state == StateFailed {
+				err = fmt.Errorf("depends on %s, which failed", dep)
+				break
+			}
+		}
+		if err == nil && e.check != nil {
+			err = e.check(ctx, service)
+		}
+
+		if err != nil {
+			e.state, e.lastErr, e.lastErrAt = StateFailed, err, time.Now()
+		} else {
+			e.state = StateReady
+		}
+	}
+	r.mutex.Unlock()
+	return r.Status()
 }
 
 // InitializeDefaults initializes the default registry with concrete implementations
@@ -92,6 +266,6 @@ func InitializeDefaults(
 	statsService interfaces.StatsService,
 ) {
 	DefaultRegistry.WithProblemService(NewProblemService(problemRepo))
-	DefaultRegistry.WithSessionService(NewSessionService(DefaultRegistry.GetProblemService(), sessionManager));
-	DefaultRegistry.WithStatsService(NewStatsCommandService(statsService));
-}
\ No newline at end of file
+	DefaultRegistry.WithSessionService(NewSessionService(DefaultRegistry.GetProblemService(), sessionManager))
+	DefaultRegistry.WithStatsService(NewStatsCommandService(statsService))
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProblemService lists fixed problems, or fails
+type stubProblemService struct {
+	LegacyProblemService
+	problems []problem.Problem
+	err      error
+}
+
+func (s *stubProblemService) ListAll(ctx context.Context) ([]problem.Problem, error) {
+	return s.problems, s.err
+}
+
+// statusOf returns the named service's status
+func statusOf(t *testing.T, statuses []ServiceStatus, name string) ServiceStatus {
+	t.Helper()
+	for _, s := range statuses {
+		if s.Name == name {
+			return s
+		}
+	}
+	require.Failf(t, "missing service", "no status for %s", name)
+	return ServiceStatus{}
+}
+
+func TestRegistryLazyInitialization(t *testing.T) {
+	r := NewServiceRegistry()
+	for _, s := range r.Status() {
+		assert.Equal(t, StateNotStarted, s.State, s.Name)
+		assert.Empty(t, s.Implementation)
+	}
+
+	// The session service brings up the problem service it depends on
+	r.GetSessionService()
+	statuses := r.Status()
+	assert.Equal(t, []string{ProblemServiceName, SessionServiceName, StatsServiceName},
+		[]string{statuses[0].Name, statuses[1].Name, statuses[2].Name})
+	assert.Equal(t, StateReady, statusOf(t, statuses, ProblemServiceName).State)
+	assert.Equal(t, "*services.LegacyProblemService", statusOf(t, statuses, ProblemServiceName).Implementation)
+	sessions := statusOf(t, statuses, SessionServiceName)
+	assert.Equal(t, StateReady, sessions.State)
+	assert.Equal(t, []string{ProblemServiceName}, sessions.DependsOn)
+	assert.Equal(t, "default, session package", sessions.Source)
+	assert.Equal(t, StateNotStarted, statusOf(t, statuses, StatsServiceName).State)
+
+	assert.Same(t, r.GetProblemService(), r.GetSessionService().(*SessionServiceImpl).problemService)
+}
+
+func TestRegistryConfiguredService(t *testing.T) {
+	r := NewServiceRegistry()
+	r.GetSessionService()
+
+	// Replacing the problem service rebuilds the session service on it
+	stub := &stubProblemService{problems: []problem.Problem{{ID: "two_sum"}}}
+	r.WithProblemService(stub)
+	problems := statusOf(t, r.Status(), ProblemServiceName)
+	assert.Equal(t, "configured", problems.Source)
+	assert.Equal(t, "*services.stubProblemService", problems.Implementation)
+	assert.Equal(t, StateNotStarted, statusOf(t, r.Status(), SessionServiceName).State)
+	assert.Same(t, stub, r.GetSessionService().(*SessionServiceImpl).problemService)
+
+	// A configured session service is kept
+	configured := NewSessionService(stub, nil)
+	r.WithSessionService(configured)
+	r.WithProblemService(&stubProblemService{})
+	assert.Same(t, configured, r.GetSessionService())
+}
+
+func TestRegistryCheck(t *testing.T) {
+	r := NewServiceRegistry()
+	r.WithProblemService(&stubProblemService{problems: []problem.Problem{{ID: "two_sum"}}})
+	for _, s := range r.Check(context.Background()) {
+		assert.Equal(t, StateReady, s.State, s.Name)
+		assert.Empty(t, s.LastError, s.Name)
+	}
+
+	// A failing service fails the services depending on it too
+	r.WithProblemService(&stubProblemService{err: errors.New("problems directory missing")})
+	statuses := r.Check(context.Background())
+	problems := statusOf(t, statuses, ProblemServiceName)
+	assert.Equal(t, StateFailed, problems.State)
+	assert.Equal(t, "problems directory missing", problems.LastError)
+	assert.False(t, problems.LastErrorAt.IsZero())
+	sessions := statusOf(t, statuses, SessionServiceName)
+	assert.Equal(t, StateFailed, sessions.State)
+	assert.Equal(t, "depends on problems, which failed", sessions.LastError)
+	assert.Equal(t, StateReady, statusOf(t, statuses, StatsServiceName).State)
+
+	r.WithProblemService(&stubProblemService{})
+	problems = statusOf(t, r.Check(context.Background()), ProblemServiceName)
+	assert.Equal(t, "no problems found", problems.LastError)
+
+	// The last error is kept once the service recovers
+	r.WithProblemService(&stubProblemService{problems: []problem.Problem{{ID: "two_sum"}}})
+	problems = statusOf(t, r.Check(context.Background()), ProblemServiceName)
+	assert.Equal(t, StateReady, problems.State)
+	assert.Equal(t, "no problems found", problems.LastError)
+}
@@ -0,0 +1,146 @@
+// Adapting problem difficulty to recent performance
+
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// DifficultyAuto asks for the difficulty recent performance calls for
+// instead of a fixed one
+const DifficultyAuto = "auto"
+
+// RecentAttempts is how many of the latest attempts at the current
+// difficulty decide whether to move up or down
+const RecentAttempts = 5
+
+// minAttemptsToStepUp is how many attempts at a difficulty are needed
+// before moving up from it
+const minAttemptsToStepUp = 3
+
+// difficulties are the difficulties from easiest to hardest
+var difficulties = []string{"easy", "medium", "hard"}
+
+// targetTimes are how quickly problems of each difficulty should be solved,
+// on average, before moving up
+var targetTimes = map[string]time.Duration{
+	"easy":   15 * time.Minute,
+	"medium": 25 * time.Minute,
+	"hard":   40 * time.Minute,
+}
+
+// DifficultyChoice is the difficulty chosen for a problem and why
+type DifficultyChoice struct {
+	Difficulty string
+	Reason     string
+}
+
+// ChooseDifficulty picks the difficulty of the next problem of pattern, or
+// of any pattern if it is empty, from the recorded sessions. History that
+// cannot be loaded starts at easy.
+func ChooseDifficulty(pattern string) DifficultyChoice {
+	history, err := stats.GetAllSessions()
+	if err != nil {
+		history = nil
+	}
+	return chooseDifficulty(pattern, history)
+}
+
+// chooseDifficulty moves up a difficulty after solving at least 4 of the
+// last RecentAttempts problems at the current one within its target time,
+// and down one after solving fewer than half of them. The current
+// difficulty is that of the latest attempt; attempts where the solution was
+// viewed count as unsolved.
+func chooseDifficulty(pattern string, history []stats.SessionStats) DifficultyChoice {
+	var attempts []stats.SessionStats
+	for _, s := range history {
+		if rankOf(s.Difficulty) >= 0 && (pattern == "" || containsString(s.Patterns, pattern)) {
+			attempts = append(attempts, s)
+		}
+	}
+	label := "problems"
+	if pattern != "" {
+		label = pattern + " problems"
+	}
+	if len(attempts) == 0 {
+		return DifficultyChoice{
+			Difficulty: "easy",
+			Reason:     fmt.Sprintf("No %s attempted yet, so starting with an easy one", label),
+		}
+	}
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].StartTime.After(attempts[j].StartTime) })
+
+	level := strings.ToLower(attempts[0].Difficulty)
+	var recent []stats.SessionStats
+	for _, s := range attempts {
+		if strings.ToLower(s.Difficulty) == level && len(recent) < RecentAttempts {
+			recent = append(recent, s)
+		}
+	}
+	solved := 0
+	var solveTime time.Duration
+	for _, s := range recent {
+		if s.Solved && !s.SolutionUsed {
+			solved++
+			solveTime += s.Duration
+		}
+	}
+	record := fmt.Sprintf("You solved %d of your last %d %s %s", solved, len(recent), level, label)
+	if len(recent) == 1 {
+		record = fmt.Sprintf("You solved %d of your 1 %s %s", solved, level, label)
+	}
+
+	rank := rankOf(level)
+	target := targetTimes[level]
+	switch {
+	case solved*2 < len(recent) && rank > 0:
+		down := difficulties[rank-1]
+		return DifficultyChoice{down, fmt.Sprintf("%s, so this one steps down to %s", record, down)}
+	case solved*2 < len(recent):
+		return DifficultyChoice{level, fmt.Sprintf("%s, so staying at easy", record)}
+	case solved*5 < len(recent)*4 || len(recent) < minAttemptsToStepUp:
+		return DifficultyChoice{level, fmt.Sprintf("%s, so staying at %s until you solve %d of %d",
+			record, level, (RecentAttempts*4+4)/5, RecentAttempts)}
+	}
+
+	average := solveTime / time.Duration(solved)
+	record += fmt.Sprintf(" in %s on average", formatMinutes(average))
+	switch {
+	case rank == len(difficulties)-1:
+		return DifficultyChoice{level, fmt.Sprintf("%s, so staying at hard, the hardest", record)}
+	case average > target:
+		return DifficultyChoice{level, fmt.Sprintf("%s, so staying at %s until you average under %s",
+			record, level, formatMinutes(target))}
+	}
+	up := difficulties[rank+1]
+	return DifficultyChoice{up, fmt.Sprintf("%s, so this one steps up to %s", record, up)}
+}
+
+// rankOf returns the position of a difficulty from easiest to hardest, or
+// -1 for one that is not known
+func rankOf(difficulty string) int {
+	if rank, ok := difficultyRank[strings.ToLower(difficulty)]; ok {
+		return rank
+	}
+	return -1
+}
+
+// containsString reports whether a slice holds s
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// formatMinutes writes a duration in whole minutes, such as "18m"
+func formatMinutes(d time.Duration) string {
+	return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+}
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+// attempts returns sessions of pattern at difficulty, the latest last, with
+// a solve time for each solved one and 0 for each unsolved one
+func attempts(start time.Time, pattern, difficulty string, minutes ...int) []stats.SessionStats {
+	var sessions []stats.SessionStats
+	for i, m := range minutes {
+		sessions = append(sessions, stats.SessionStats{
+			StartTime:  start.Add(time.Duration(i) * time.Hour),
+			Duration:   time.Duration(m) * time.Minute,
+			Solved:     m > 0,
+			Patterns:   []string{pattern},
+			Difficulty: difficulty,
+		})
+	}
+	return sessions
+}
+
+func TestChooseDifficulty(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	choice := chooseDifficulty("dfs", nil)
+	assert.Equal(t, "easy", choice.Difficulty)
+	assert.Equal(t, "No dfs problems attempted yet, so starting with an easy one", choice.Reason)
+
+	// Solving quickly steps up
+	history := attempts(start, "dfs", "Medium", 20, 15, 0, 18, 22)
+	choice = chooseDifficulty("dfs", history)
+	assert.Equal(t, "hard", choice.Difficulty)
+	assert.Equal(t, "You solved 4 of your last 5 medium dfs problems in 19m on average, so this one steps up to hard", choice.Reason)
+
+	// Other patterns do not count, but do without a pattern
+	history = append(history, attempts(start.Add(24*time.Hour), "bfs", "easy", 0, 0, 9)...)
+	assert.Equal(t, "hard", chooseDifficulty("dfs", history).Difficulty)
+	choice = chooseDifficulty("", history)
+	assert.Equal(t, "easy", choice.Difficulty)
+	assert.Equal(t, "You solved 1 of your last 3 easy problems, so staying at easy", choice.Reason)
+
+	// Solving slowly stays put
+	choice = chooseDifficulty("dfs", attempts(start, "dfs", "medium", 30, 28, 26, 35))
+	assert.Equal(t, "medium", choice.Difficulty)
+	assert.Contains(t, choice.Reason, "staying at medium until you average under 25m")
+
+	// Solving too few stays put, and fewer than half steps down
+	choice = chooseDifficulty("dfs", attempts(start, "dfs", "medium", 10, 0, 10, 10))
+	assert.Equal(t, "medium", choice.Difficulty)
+	assert.Contains(t, choice.Reason, "staying at medium until you solve 4 of 5")
+	choice = chooseDifficulty("dfs", attempts(start, "dfs", "hard", 30, 0, 0, 0, 45))
+	assert.Equal(t, "medium", choice.Difficulty)
+	assert.Equal(t, "You solved 2 of your last 5 hard dfs problems, so this one steps down to medium", choice.Reason)
+
+	// Viewing the solution does not count as solving
+	history = attempts(start, "dfs", "easy", 5, 5, 5, 5)
+	for i := range history {
+		history[i].SolutionUsed = true
+	}
+	assert.Equal(t, "easy", chooseDifficulty("dfs", history).Difficulty)
+
+	// Only the latest difficulty's attempts decide
+	history = append(attempts(start, "dfs", "easy", 0, 0, 0), attempts(start.Add(24*time.Hour), "dfs", "medium", 12, 14, 16)...)
+	assert.Equal(t, "hard", chooseDifficulty("dfs", history).Difficulty)
+}
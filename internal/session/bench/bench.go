@@ -0,0 +1,145 @@
+// Package bench times a solution against the problem's reference solution on
+// large inputs generated from the problem's constraints, so that solutions
+// which pass the tests but are too slow for the inputs the constraints allow
+// are flagged
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+const (
+	// Inputs is the number of large inputs each benchmark runs
+	Inputs = 3
+
+	// Timeout bounds each run of the inputs, for the reference and for the
+	// solution being benchmarked
+	Timeout = 2 * time.Minute
+
+	// DefaultSlowRatio flags solutions that take more than this many times
+	// as long as the reference
+	DefaultSlowRatio = 3.0
+
+	// minComparable is the total time below which a solution is too fast for
+	// its timing to be compared with the reference's
+	minComparable = 20 * time.Millisecond
+)
+
+// SlowRatio is the ratio to the reference's time above which a solution is
+// flagged as slow
+var SlowRatio = DefaultSlowRatio
+
+// executeTests runs test cases against a solution
+// Exported as variable for testing
+var executeTests = execution.ExecuteTests
+
+// Report is the outcome of benchmarking a solution
+type Report struct {
+	Inputs     int
+	User       time.Duration // Total time the solution took on the inputs
+	Reference  time.Duration // Total time the reference solution took
+	Ratio      float64       // User over Reference time; 0 when the solution is too fast to compare
+	Mismatches int           // Inputs the solution answered differently from the reference
+	Errors     int           // Inputs the solution failed on with an error
+	Error      string        // The first of those errors
+	TimedOut   bool          // The solution ran into the time limit
+	Slow       bool          // The solution timed out or exceeded SlowRatio
+}
+
+// Summary describes the report in a sentence or two
+func (r Report) Summary() string {
+	var summary string
+	switch {
+	case r.TimedOut:
+		summary = fmt.Sprintf("Your solution ran out of time on large inputs; the reference took %s. Look for a faster approach.",
+			execution.FormatDuration(r.Reference))
+	case r.Slow:
+		summary = fmt.Sprintf("Your solution took %s on large inputs, %.1fx the reference's %s. It passes the tests but would be too slow for the largest inputs allowed. Look for a faster approach.",
+			execution.FormatDuration(r.User), r.Ratio, execution.FormatDuration(r.Reference))
+	case r.Ratio > 0:
+		summary = fmt.Sprintf("Your solution took %s on large inputs, %.1fx the reference's %s.",
+			execution.FormatDuration(r.User), r.Ratio, execution.FormatDuration(r.Reference))
+	default:
+		summary = fmt.Sprintf("Your solution took %s on large inputs; the reference took %s.",
+			execution.FormatDuration(r.User), execution.FormatDuration(r.Reference))
+	}
+	if r.Mismatches > 0 {
+		summary += fmt.Sprintf(" Its answers differed from the reference's on %d of %d inputs, which is expected only if more than one answer is valid.",
+			r.Mismatches, r.Inputs)
+	}
+	if r.Errors > 0 {
+		summary += fmt.Sprintf(" It failed on %d of %d inputs: %s", r.Errors, r.Inputs, r.Error)
+	}
+	return summary
+}
+
+// Run benchmarks a solution against the problem's reference solution in the
+// same language. The reference runs first and its answers become the
+// expected results, so the report also notes where the answers differ.
+func Run(ctx context.Context, prob problem.Problem, language, code string) (Report, error) {
+	reference := prob.Solutions[language]
+	if strings.TrimSpace(reference) == "" {
+		return Report{}, fmt.Errorf("problem %s has no reference solution in %s", prob.ID, language)
+	}
+
+	cases := make([]interfaces.TestCase, Inputs)
+	for i := range cases {
+		input, err := Generate(ctx, prob, int64(i+1))
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to generate inputs: %v", err)
+		}
+		cases[i].Input = input
+	}
+	benchProb := &interfaces.Problem{
+		ID:        prob.ID,
+		Title:     prob.Title,
+		Signature: prob.Signature,
+		TestCases: cases,
+	}
+
+	results, _, err := executeTests(ctx, benchProb, reference, language, Timeout)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to run the reference solution: %v", err)
+	}
+	report := Report{Inputs: len(cases)}
+	for i, result := range results {
+		// Without an expected answer, the reference "fails" with its answer
+		if !result.Passed && result.Failure != interfaces.FailureWrongAnswer {
+			return Report{}, fmt.Errorf("reference solution failed on generated input %d: %s", i+1, result.Actual)
+		}
+		cases[i].Expected = result.Actual
+		report.Reference += result.Duration
+	}
+
+	results, _, err = executeTests(ctx, benchProb, code, language, Timeout)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to run your solution: %v", err)
+	}
+	for _, result := range results {
+		report.User += result.Duration
+		switch {
+		case result.Failure == interfaces.FailureTimeLimit:
+			report.TimedOut = true
+		case result.Failure == interfaces.FailureWrongAnswer:
+			report.Mismatches++
+		case !result.Passed:
+			if report.Errors == 0 {
+				report.Error = strings.TrimPrefix(result.Actual, "Error: ")
+			}
+			report.Errors++
+		}
+	}
+
+	if report.User >= minComparable && report.Reference > 0 {
+		report.Ratio = float64(report.User) / float64(report.Reference)
+	}
+	report.Slow = report.TimedOut || report.Ratio > SlowRatio
+	return report, nil
+}
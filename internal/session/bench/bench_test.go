@@ -0,0 +1,336 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode parses a generated input back into its JSON arguments
+func decode(t *testing.T, input string, args ...interface{}) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal([]byte("["+input+"]"), &args))
+}
+
+func twoSum() problem.Problem {
+	return problem.Problem{
+		ID: "two_sum",
+		Constraints: []string{
+			"2 <= nums.length <= 10^4",
+			"-10^9 <= nums[i] <= 10^9",
+			"-10^9 <= target <= 10^9",
+		},
+		Signature: &interfaces.FunctionSignature{
+			Name:    "twoSum",
+			Params:  []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+			Returns: "int[]",
+		},
+		Solutions: map[string]string{"python": "def two_sum(nums, target): ..."},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	input, err := Generate(context.Background(), twoSum(), 1)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(input), maxInputBytes)
+
+	var nums []int64
+	var target int64
+	decode(t, input, &nums, &target)
+	// Values of up to 11 characters only fit about 5000 to an input
+	assert.Len(t, nums, maxLength/2)
+	for _, n := range nums {
+		assert.True(t, n >= -1e9 && n <= 1e9, "%d is out of bounds", n)
+	}
+	assert.True(t, target >= -1e9 && target <= 1e9)
+
+	again, err := Generate(context.Background(), twoSum(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, input, again, "the same seed gives the same input")
+	other, err := Generate(context.Background(), twoSum(), 2)
+	require.NoError(t, err)
+	assert.NotEqual(t, input, other)
+
+	_, err = Generate(context.Background(), problem.Problem{ID: "unsigned"}, 1)
+	assert.Error(t, err)
+}
+
+func TestGenerateConstraints(t *testing.T) {
+	t.Run("SortedDistinct", func(t *testing.T) {
+		p := problem.Problem{
+			Constraints: []string{"1 <= nums.length <= 100", "-50 <= nums[i] <= 49", "All values of nums are unique", "nums is sorted in ascending order"},
+			Signature:   &interfaces.FunctionSignature{Params: []interfaces.Param{{Name: "nums", Type: "int[]"}}},
+		}
+		input, err := Generate(context.Background(), p, 1)
+		require.NoError(t, err)
+		var nums []int
+		decode(t, input, &nums)
+		require.Len(t, nums, 100)
+		for i := 1; i < len(nums); i++ {
+			assert.Less(t, nums[i-1], nums[i])
+		}
+	})
+
+	t.Run("SizesFromOtherTerms", func(t *testing.T) {
+		p := problem.Problem{
+			Constraints: []string{"1 <= n <= 50", "1 <= edges.length <= 200", "edges[i].length == 2", "0 <= ai <= bi < n", "1 <= k <= edges.length"},
+			Signature: &interfaces.FunctionSignature{Params: []interfaces.Param{
+				{Name: "n", Type: "int"}, {Name: "edges", Type: "int[][]"}, {Name: "k", Type: "int"},
+			}},
+		}
+		input, err := Generate(context.Background(), p, 1)
+		require.NoError(t, err)
+		var n, k int
+		var edges [][]int
+		decode(t, input, &n, &edges, &k)
+		assert.Equal(t, 50, n, "n sizes the edges' values, so it is the largest allowed")
+		require.Len(t, edges, 200)
+		for _, e := range edges {
+			require.Len(t, e, 2)
+			assert.True(t, e[0] >= 0 && e[0] < n && e[1] >= 0 && e[1] < n)
+		}
+		assert.True(t, k >= 1 && k <= 200)
+	})
+
+	t.Run("Grid", func(t *testing.T) {
+		p := problem.Problem{
+			Constraints: []string{"m == grid.length", "n == grid[i].length", "1 <= m, n <= 20", "grid[i][j] is '0' or '1'"},
+			Signature:   &interfaces.FunctionSignature{Params: []interfaces.Param{{Name: "grid", Type: "char[][]"}}},
+		}
+		input, err := Generate(context.Background(), p, 1)
+		require.NoError(t, err)
+		var grid [][]string
+		decode(t, input, &grid)
+		require.Len(t, grid, 20)
+		for _, row := range grid {
+			require.Len(t, row, 20)
+			for _, cell := range row {
+				assert.Contains(t, []string{"0", "1"}, cell)
+			}
+		}
+	})
+
+	t.Run("ListWithCycle", func(t *testing.T) {
+		p := problem.Problem{
+			Constraints: []string{"The number of nodes in the list is in the range [0, 300]", "-100 <= Node.val <= 100"},
+			Signature: &interfaces.FunctionSignature{Params: []interfaces.Param{
+				{Name: "head", Type: "ListNode"}, {Name: "pos", Type: "cycle"},
+			}},
+		}
+		input, err := Generate(context.Background(), p, 3)
+		require.NoError(t, err)
+		var head []int
+		var pos int
+		decode(t, input, &head, &pos)
+		require.Len(t, head, 300)
+		for _, v := range head {
+			assert.True(t, v >= -100 && v <= 100)
+		}
+		assert.True(t, pos >= -1 && pos < 300)
+	})
+
+	t.Run("ChainedBound", func(t *testing.T) {
+		p := problem.Problem{
+			Constraints: []string{"1 <= k <= points.length <= 10^4", "-10^4 <= xi, yi <= 10^4"},
+			Signature: &interfaces.FunctionSignature{Params: []interfaces.Param{
+				{Name: "points", Type: "int[][]"}, {Name: "k", Type: "int"},
+			}},
+		}
+		input, err := Generate(context.Background(), p, 1)
+		require.NoError(t, err)
+		var points [][]int
+		var k int
+		decode(t, input, &points, &k)
+		assert.LessOrEqual(t, k, len(points))
+		for _, point := range points {
+			for _, v := range point {
+				assert.True(t, v >= -1e4 && v <= 1e4)
+			}
+		}
+	})
+}
+
+func TestGenerateSize(t *testing.T) {
+	input, err := GenerateSize(context.Background(), twoSum(), 100000, 1)
+	require.NoError(t, err)
+	var nums []int64
+	var target int64
+	decode(t, input, &nums, &target)
+	assert.Len(t, nums, 100000, "the size goes beyond the constraints")
+
+	input, err = GenerateSize(context.Background(), twoSum(), 1, 1)
+	require.NoError(t, err)
+	decode(t, input, &nums, &target)
+	assert.Len(t, nums, 2, "but not below them")
+
+	_, err = GenerateSize(context.Background(), twoSum(), 0, 1)
+	assert.Error(t, err)
+}
+
+func TestGenerateSpec(t *testing.T) {
+	low, high, length := int64(-5), int64(5), int64(3)
+	p := problem.Problem{
+		Constraints: []string{"1 <= words.length <= 10^4", "0 <= k <= 10^9"},
+		Signature: &interfaces.FunctionSignature{Params: []interfaces.Param{
+			{Name: "nums", Type: "int[]"}, {Name: "word", Type: "string"}, {Name: "k", Type: "int"},
+		}},
+		Generator: &problem.InputGenerator{Params: map[string]problem.ParamSpec{
+			"nums": {Min: &low, Max: &high},
+			"word": {Chars: "ab", Length: &length},
+			"k":    {Max: &high},
+		}},
+	}
+	input, err := GenerateSize(context.Background(), p, 500, 1)
+	require.NoError(t, err)
+	var nums []int
+	var word string
+	var k int64
+	decode(t, input, &nums, &word, &k)
+	assert.Len(t, nums, 500)
+	for _, n := range nums {
+		assert.True(t, n >= -5 && n <= 5, "%d is out of bounds", n)
+	}
+	assert.Regexp(t, `^[ab]{3}$`, word)
+	assert.True(t, k >= 0 && k <= 5)
+}
+
+func TestGenerateScript(t *testing.T) {
+	original := runGeneratorScript
+	t.Cleanup(func() { runGeneratorScript = original })
+	var calls [][]string
+	runGeneratorScript = func(ctx context.Context, language, script string, args []string, timeout time.Duration) (string, error) {
+		calls = append(calls, append([]string{language}, args...))
+		if args[0] == "10000" {
+			return strings.Repeat("1", maxInputBytes+1) + "\n", nil
+		}
+		return "[" + args[0] + "], " + args[1] + "\n", nil
+	}
+
+	p := problem.Problem{ID: "scripted", Generator: &problem.InputGenerator{Script: "print(1)"}}
+	input, err := GenerateSize(context.Background(), p, 100000, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "[100000], 7", input, "scripts need no signature")
+	assert.Equal(t, []string{"python", "100000", "7"}, calls[0])
+
+	input, err = Generate(context.Background(), p, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "[5000], 1", input, "inputs too large to embed are generated again smaller")
+
+	runGeneratorScript = func(ctx context.Context, language, script string, args []string, timeout time.Duration) (string, error) {
+		return "", errors.New("exit status 1: NameError")
+	}
+	_, err = Generate(context.Background(), p, 1)
+	assert.ErrorContains(t, err, "generator script of scripted failed: exit status 1: NameError")
+}
+
+// stubExecution replaces the test runner, giving each run's durations and
+// recording the test cases it was given
+func stubExecution(t *testing.T, run func(code string, tc interfaces.TestCase) interfaces.TestResult) *[][]interfaces.TestCase {
+	t.Helper()
+	original := executeTests
+	t.Cleanup(func() { executeTests = original })
+
+	var runs [][]interfaces.TestCase
+	executeTests = func(ctx context.Context, prob *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		runs = append(runs, append([]interfaces.TestCase(nil), prob.TestCases...))
+		results := make([]interfaces.TestResult, len(prob.TestCases))
+		for i, tc := range prob.TestCases {
+			results[i] = run(code, tc)
+			results[i].Input, results[i].Expected = tc.Input, tc.Expected
+		}
+		return results, false, nil
+	}
+	return &runs
+}
+
+// reference stands in for the reference solution, answering [0,1] in a
+// millisecond; with no expected answer yet, it counts as a wrong answer
+func reference(code string, tc interfaces.TestCase) (interfaces.TestResult, bool) {
+	if !strings.HasPrefix(code, "def two_sum") {
+		return interfaces.TestResult{}, false
+	}
+	return interfaces.TestResult{Actual: "[0,1]", Failure: interfaces.FailureWrongAnswer, Duration: time.Millisecond}, true
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Slow", func(t *testing.T) {
+		runs := stubExecution(t, func(code string, tc interfaces.TestCase) interfaces.TestResult {
+			if r, ok := reference(code, tc); ok {
+				return r
+			}
+			return interfaces.TestResult{Passed: tc.Expected == "[0,1]", Actual: "[0,1]", Duration: 50 * time.Millisecond}
+		})
+
+		report, err := Run(context.Background(), twoSum(), "python", "quadratic")
+		require.NoError(t, err)
+		require.Len(t, *runs, 2)
+		assert.Equal(t, "[0,1]", (*runs)[1][0].Expected, "the reference's answers are expected")
+		assert.Equal(t, Inputs, report.Inputs)
+		assert.Equal(t, 3*time.Millisecond, report.Reference)
+		assert.Equal(t, 150*time.Millisecond, report.User)
+		assert.Equal(t, 50.0, report.Ratio)
+		assert.True(t, report.Slow)
+		assert.Zero(t, report.Mismatches)
+		assert.Contains(t, report.Summary(), "50.0x the reference's 3ms")
+	})
+
+	t.Run("Comparable", func(t *testing.T) {
+		stubExecution(t, func(code string, tc interfaces.TestCase) interfaces.TestResult {
+			if r, ok := reference(code, tc); ok {
+				return r
+			}
+			return interfaces.TestResult{Failure: interfaces.FailureWrongAnswer, Actual: "[1,0]", Duration: 2 * time.Millisecond}
+		})
+
+		report, err := Run(context.Background(), twoSum(), "python", "linear")
+		require.NoError(t, err)
+		assert.Zero(t, report.Ratio, "6ms is too fast to compare")
+		assert.False(t, report.Slow)
+		assert.Equal(t, 3, report.Mismatches)
+		assert.Contains(t, report.Summary(), "differed from the reference's on 3 of 3 inputs")
+	})
+
+	t.Run("TimedOut", func(t *testing.T) {
+		stubExecution(t, func(code string, tc interfaces.TestCase) interfaces.TestResult {
+			if r, ok := reference(code, tc); ok {
+				return r
+			}
+			return interfaces.TestResult{Failure: interfaces.FailureTimeLimit, Actual: "Error: command timed out"}
+		})
+
+		report, err := Run(context.Background(), twoSum(), "python", "exponential")
+		require.NoError(t, err)
+		assert.True(t, report.TimedOut)
+		assert.True(t, report.Slow)
+		assert.Contains(t, report.Summary(), "ran out of time")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		stubExecution(t, func(code string, tc interfaces.TestCase) interfaces.TestResult {
+			return interfaces.TestResult{Failure: interfaces.FailureRuntime, Actual: "Error: RecursionError"}
+		})
+		_, err := Run(context.Background(), twoSum(), "python", "broken")
+		assert.ErrorContains(t, err, "reference solution failed on generated input 1: Error: RecursionError")
+
+		_, err = Run(context.Background(), twoSum(), "rust", "fn two_sum() {}")
+		assert.ErrorContains(t, err, "no reference solution in rust")
+	})
+
+	t.Run("RunnerError", func(t *testing.T) {
+		original := executeTests
+		t.Cleanup(func() { executeTests = original })
+		executeTests = func(ctx context.Context, prob *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+			return nil, false, errors.New("python not found")
+		}
+		_, err := Run(context.Background(), twoSum(), "python", "anything")
+		assert.ErrorContains(t, err, "python not found")
+	})
+}
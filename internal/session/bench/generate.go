@@ -0,0 +1,540 @@
+// Large test inputs generated from a problem's signature and constraints
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+const (
+	// maxLength caps the length of generated arrays, strings and lists
+	maxLength = 10000
+
+	// maxInputBytes keeps each input short enough to embed in a harness as a
+	// string literal, which Java limits to 64KB
+	maxInputBytes = 60000
+
+	// scriptTimeout bounds each run of a problem's generator script
+	scriptTimeout = 30 * time.Second
+)
+
+// defaultLow and defaultHigh bound values the constraints say nothing about
+const (
+	defaultLow  = 0
+	defaultHigh = 1000
+)
+
+var (
+	numberPattern = regexp.MustCompile(`^(-?)(\d+)(?:\s*\^\s*(\d+))?(?:\s*([+-])\s*(\d+))?$`)
+	equality      = regexp.MustCompile(`^\s*([\w.\[\]()]+)\s*==\s*([\w.\[\]()]+)\s*$`)
+	quotedChar    = regexp.MustCompile(`'(.)'`)
+	alphabet      = []rune("abcdefghijklmnopqrstuvwxyz")
+)
+
+// Generate builds a large input for the problem, in the format of its test
+// cases, with the problem's generator script if it ships one and otherwise
+// from its function signature and constraints. Sizes are the largest the
+// constraints allow, within limits that keep the input embeddable in a test
+// harness; values are random within their bounds. The same seed always
+// gives the same input.
+func Generate(ctx context.Context, prob problem.Problem, seed int64) (string, error) {
+	if prob.Generator == nil || prob.Generator.Script == "" {
+		if prob.Signature == nil || len(prob.Signature.Params) == 0 {
+			return "", fmt.Errorf("problem %s has no function signature", prob.ID)
+		}
+	}
+	for maxLen := maxLength; ; maxLen /= 2 {
+		input, err := generate(ctx, prob, int64(maxLen), seed, false)
+		if err != nil || len(input) <= maxInputBytes || maxLen == 1 {
+			return input, err
+		}
+	}
+}
+
+// GenerateSize builds an input whose arrays, strings and lists have size
+// elements, for stress testing by hand. Unlike Generate, the size may go
+// beyond what the constraints allow, and the input beyond what a harness
+// can embed. The same seed always gives the same input.
+func GenerateSize(ctx context.Context, prob problem.Problem, size, seed int64) (string, error) {
+	if size < 1 {
+		return "", fmt.Errorf("size must be at least 1")
+	}
+	if prob.Generator == nil || prob.Generator.Script == "" {
+		if prob.Signature == nil || len(prob.Signature.Params) == 0 {
+			return "", fmt.Errorf("problem %s has no function signature", prob.ID)
+		}
+	}
+	return generate(ctx, prob, size, seed, true)
+}
+
+// generate builds one input with the problem's script, or else from its
+// constraints
+func generate(ctx context.Context, prob problem.Problem, size, seed int64, exact bool) (string, error) {
+	if prob.Generator != nil && prob.Generator.Script != "" {
+		return runScript(ctx, prob, size, seed)
+	}
+	return newGenerator(prob, seed, size, exact).input()
+}
+
+// runScript runs the problem's generator script for one input
+func runScript(ctx context.Context, prob problem.Problem, size, seed int64) (string, error) {
+	args := []string{strconv.FormatInt(size, 10), strconv.FormatInt(seed, 10)}
+	output, err := runGeneratorScript(ctx, prob.Generator.ScriptLanguage(), prob.Generator.Script, args, scriptTimeout)
+	if err != nil {
+		return "", fmt.Errorf("generator script of %s failed: %v", prob.ID, err)
+	}
+	input := strings.TrimSpace(output)
+	if input == "" {
+		return "", fmt.Errorf("generator script of %s printed no input", prob.ID)
+	}
+	return input, nil
+}
+
+// runGeneratorScript runs a generator script
+// Exported as variable for testing
+var runGeneratorScript = execution.RunScript
+
+// generator builds one input
+type generator struct {
+	prob     problem.Problem
+	params   []interfaces.Param
+	bounds   []problem.Bound
+	specs    map[string]problem.ParamSpec // Refinements the problem's generator makes, by parameter
+	aliases  map[string]string            // Terms a constraint equates, e.g. grid.length == m
+	rng      *rand.Rand
+	maxLen   int64
+	exact    bool             // Sizes are maxLen, whatever the constraints allow
+	resolved map[string]int64 // Sizes and values picked so far, e.g. "nums.length" and "n"
+}
+
+func newGenerator(prob problem.Problem, seed, maxLen int64, exact bool) *generator {
+	g := &generator{
+		prob:     prob,
+		params:   prob.Signature.Params,
+		bounds:   prob.Bounds(),
+		aliases:  make(map[string]string),
+		rng:      rand.New(rand.NewSource(seed)),
+		maxLen:   maxLen,
+		exact:    exact,
+		resolved: make(map[string]int64),
+	}
+	if prob.Generator != nil {
+		g.specs = prob.Generator.Params
+	}
+	for _, c := range prob.Constraints {
+		if m := equality.FindStringSubmatch(c); m != nil {
+			g.aliases[m[1]], g.aliases[m[2]] = m[2], m[1]
+		}
+	}
+	return g
+}
+
+// input picks sizes first, then scalars, whose bounds may refer to sizes as
+// in 1 <= k <= arr.length, then the values of each argument
+func (g *generator) input() (string, error) {
+	for _, p := range g.params {
+		if isCollection(p.Type) {
+			g.resolved[p.Name+".length"] = g.length(p)
+		}
+	}
+	for _, p := range g.params {
+		if p.Type == "int" {
+			g.resolved[p.Name] = g.scalar(p.Name)
+		}
+	}
+
+	args := make([]string, len(g.params))
+	for i, p := range g.params {
+		arg, err := g.arg(i, p)
+		if err != nil {
+			return "", err
+		}
+		args[i] = arg
+	}
+	return strings.Join(args, ", "), nil
+}
+
+// arg generates the argument for the i'th parameter
+func (g *generator) arg(i int, p interfaces.Param) (string, error) {
+	switch p.Type {
+	case "int":
+		return strconv.FormatInt(g.resolved[p.Name], 10), nil
+	case "cycle":
+		// The tail links back to a random node, or nowhere
+		if i == 0 {
+			return "", fmt.Errorf("cycle parameter %s does not follow a list", p.Name)
+		}
+		return strconv.FormatInt(g.rng.Int63n(g.resolved[g.params[i-1].Name+".length"]+1)-1, 10), nil
+	case "TreeNode", "ListNode":
+		// A complete tree, so no nulls are needed in its level order
+		low, high := g.valueRange(p.Name)
+		return formatList(g.ints(g.resolved[p.Name+".length"], low, high, false, false)), nil
+	case "string":
+		return strconv.Quote(g.text(p.Name, g.resolved[p.Name+".length"])), nil
+	}
+
+	elem, dims := p.Type, 0
+	for strings.HasSuffix(elem, "[]") {
+		elem, dims = strings.TrimSuffix(elem, "[]"), dims+1
+	}
+	switch {
+	case dims == 0 && !isScalar(elem):
+		return "", fmt.Errorf("cannot generate values of type %s", p.Type)
+	case dims == 0:
+		return g.values(p.Name, elem, 1)[0], nil
+	case dims == 1:
+		return formatList(g.row(p.Name, elem, g.resolved[p.Name+".length"])), nil
+	case dims == 2:
+		rows := make([]string, g.resolved[p.Name+".length"])
+		width := g.width(p.Name)
+		for r := range rows {
+			rows[r] = formatList(g.row(p.Name, elem, width))
+		}
+		return formatList(rows), nil
+	}
+	return "", fmt.Errorf("cannot generate values of type %s", p.Type)
+}
+
+// row generates one array of n elements, sorted or distinct if the problem
+// says its input is
+func (g *generator) row(name, elem string, n int64) []string {
+	if elem != "int" {
+		return g.values(name, elem, n)
+	}
+	low, high := g.valueRange(name)
+	return g.ints(n, low, high, g.prob.Sorted(), g.prob.Distinct())
+}
+
+// values generates n scalar values of an element type
+func (g *generator) values(name, elem string, n int64) []string {
+	values := make([]string, n)
+	low, high := g.valueRange(name)
+	for i := range values {
+		switch elem {
+		case "int":
+			values[i] = strconv.FormatInt(g.between(low, high), 10)
+		case "float":
+			values[i] = strconv.FormatFloat(float64(low)+g.rng.Float64()*float64(high-low), 'f', 2, 64)
+		case "bool":
+			values[i] = strconv.FormatBool(g.rng.Intn(2) == 0)
+		case "char", "string":
+			values[i] = strconv.Quote(g.text(name, 1))
+		}
+	}
+	return values
+}
+
+// ints generates n integers in [low, high]
+func (g *generator) ints(n, low, high int64, sorted, distinct bool) []string {
+	if distinct && high-low+1 < n {
+		n = high - low + 1
+	}
+	seen := make(map[int64]bool)
+	picked := make([]int64, 0, n)
+	for int64(len(picked)) < n {
+		v := g.between(low, high)
+		if distinct {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+		}
+		picked = append(picked, v)
+	}
+	if sorted {
+		sort.Slice(picked, func(i, j int) bool { return picked[i] < picked[j] })
+	}
+
+	values := make([]string, n)
+	for i, v := range picked {
+		values[i] = strconv.FormatInt(v, 10)
+	}
+	return values
+}
+
+// text generates n characters from the ones the problem's generator gives
+// or the constraints allow, such as "grid[i][j] is '0' or '1'", or else
+// lowercase letters
+func (g *generator) text(name string, n int64) string {
+	chars := alphabet
+	if spec := g.specs[name]; spec.Chars != "" {
+		chars = []rune(spec.Chars)
+	} else {
+		for _, c := range g.prob.Constraints {
+			if !strings.Contains(c, name) {
+				continue
+			}
+			if m := quotedChar.FindAllStringSubmatch(c, -1); m != nil {
+				chars = nil
+				for _, q := range m {
+					chars = append(chars, []rune(q[1])...)
+				}
+				break
+			}
+		}
+	}
+	var b strings.Builder
+	for i := int64(0); i < n; i++ {
+		b.WriteRune(chars[g.rng.Intn(len(chars))])
+	}
+	return b.String()
+}
+
+// length picks the size of a collection: the length the problem's generator
+// fixes, or else the largest its bounds allow, up to maxLen
+func (g *generator) length(p interfaces.Param) int64 {
+	if spec := g.specs[p.Name]; spec.Length != nil {
+		return *spec.Length
+	}
+	low, high, ok := g.bound(p.Name + ".length")
+	if !ok && (p.Type == "TreeNode" || p.Type == "ListNode") {
+		low, high, ok = g.nodeCount()
+	}
+	if !ok {
+		low, high = 0, g.maxLen
+	}
+	return g.size(low, high)
+}
+
+// width picks the length of each row of a two-dimensional array
+func (g *generator) width(name string) int64 {
+	if low, high, ok := g.bound(name + "[i].length"); ok {
+		return clampSize(low, high, g.maxLen)
+	}
+	return 2
+}
+
+// nodeCount returns the bound on the number of nodes in a tree or list
+func (g *generator) nodeCount() (int64, int64, bool) {
+	for _, b := range g.bounds {
+		if b.Size && strings.HasPrefix(b.Term, "the number of") {
+			return g.rangeOf(b)
+		}
+	}
+	return 0, 0, false
+}
+
+// scalar picks an integer argument. One other bounds refer to, like the n in
+// 0 <= ai < n, is a size and so the largest allowed; others are random.
+func (g *generator) scalar(name string) int64 {
+	low, high, ok := g.bound(name)
+	if !ok {
+		low, high = defaultLow, defaultHigh
+	}
+	// The chain in 1 <= k <= points.length <= 10^4 also caps k
+	for _, b := range g.bounds {
+		if b.Term != name {
+			continue
+		}
+		m := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*(?:<=|≤|<)\s*([\w.\[\]()]+)`).FindStringSubmatch(b.Text)
+		if m == nil {
+			continue
+		}
+		if v, ok := g.value(m[1]); ok && v < high {
+			high = v
+		}
+	}
+	low, high = g.specRange(name, low, high)
+	if g.isSize(name) {
+		return g.size(low, high)
+	}
+	return g.between(low, high)
+}
+
+// isSize reports whether a term sizes something else, as n does in
+// 0 <= ai < n and m does in m == grid.length
+func (g *generator) isSize(term string) bool {
+	if _, ok := g.aliases[term]; ok {
+		return true
+	}
+	for _, b := range g.bounds {
+		if b.High == term {
+			return true
+		}
+	}
+	return false
+}
+
+// valueRange returns the bounds on the values of a parameter, as the
+// problem's generator refines them
+func (g *generator) valueRange(name string) (int64, int64) {
+	low, high := g.boundedRange(name)
+	return g.specRange(name, low, high)
+}
+
+// specRange applies the bounds the problem's generator gives a parameter
+func (g *generator) specRange(name string, low, high int64) (int64, int64) {
+	spec := g.specs[name]
+	if spec.Min != nil {
+		low = *spec.Min
+	}
+	if spec.Max != nil {
+		high = *spec.Max
+	}
+	return low, high
+}
+
+// size picks a size in [low, high]: the largest up to maxLen, or maxLen
+// itself when sizes are exact
+func (g *generator) size(low, high int64) int64 {
+	if g.exact {
+		return clampSize(low, g.maxLen, g.maxLen)
+	}
+	return clampSize(low, high, g.maxLen)
+}
+
+// boundedRange returns the bounds the constraints put on the values of a
+// parameter: those on name[i] or Node.val, else those below an integer
+// parameter as in 0 <= ai < n, else the first bound on a term that is not a
+// parameter
+func (g *generator) boundedRange(name string) (int64, int64) {
+	for _, b := range g.bounds {
+		if !b.Size && (strings.HasPrefix(b.Term, name+"[") || b.Term == "Node.val") {
+			if low, high, ok := g.rangeOf(b); ok {
+				return low, high
+			}
+		}
+	}
+	for _, b := range g.bounds {
+		if b.Size || g.isParam(b.Term) || !g.isParam(b.High) {
+			continue
+		}
+		if low, high, ok := g.rangeOf(b); ok {
+			return low, high - 1
+		}
+	}
+	for _, b := range g.bounds {
+		if b.Size || g.isParam(b.Term) || strings.ContainsAny(b.Term, ".[") {
+			continue
+		}
+		if low, high, ok := g.rangeOf(b); ok {
+			return low, high
+		}
+	}
+	return defaultLow, defaultHigh
+}
+
+// bound returns the bounds on a term, following equalities such as
+// m == grid.length and edges[i].length == 2
+func (g *generator) bound(term string) (int64, int64, bool) {
+	for _, b := range g.bounds {
+		if b.Term == term {
+			return g.rangeOf(b)
+		}
+	}
+	if alias, ok := g.aliases[term]; ok {
+		if v, ok := g.value(alias); ok {
+			return v, v, true
+		}
+		for _, b := range g.bounds {
+			if b.Term == alias {
+				return g.rangeOf(b)
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// rangeOf evaluates both ends of a bound
+func (g *generator) rangeOf(b problem.Bound) (int64, int64, bool) {
+	low, ok := g.value(b.Low)
+	if !ok {
+		return 0, 0, false
+	}
+	high, ok := g.value(b.High)
+	if !ok || high < low {
+		return 0, 0, false
+	}
+	return low, high, true
+}
+
+// value evaluates one end of a bound: a number such as 10^4 or 2^31 - 1, or
+// a size or value already picked
+func (g *generator) value(expr string) (int64, bool) {
+	expr = strings.TrimSpace(expr)
+	if v, ok := g.resolved[expr]; ok {
+		return v, true
+	}
+	m := numberPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return 0, false
+	}
+	v, _ := strconv.ParseFloat(m[2], 64)
+	if m[3] != "" {
+		exp, _ := strconv.ParseFloat(m[3], 64)
+		v = math.Pow(v, exp)
+	}
+	if m[5] != "" {
+		addend, _ := strconv.ParseFloat(m[5], 64)
+		if m[4] == "-" {
+			addend = -addend
+		}
+		v += addend
+	}
+	if m[1] == "-" {
+		v = -v
+	}
+	// Keep values within a 32-bit int, which every language's int can hold
+	return int64(math.Max(math.MinInt32, math.Min(math.MaxInt32, v))), true
+}
+
+// isParam reports whether term names an integer parameter
+func (g *generator) isParam(term string) bool {
+	for _, p := range g.params {
+		if p.Name == term && p.Type == "int" {
+			return true
+		}
+	}
+	return false
+}
+
+// between returns a random integer in [low, high]
+func (g *generator) between(low, high int64) int64 {
+	if high <= low {
+		return low
+	}
+	return low + g.rng.Int63n(high-low+1)
+}
+
+// clampSize returns the largest size in [low, high] up to maxLen, but never
+// less than low
+func clampSize(low, high, maxLen int64) int64 {
+	if high > maxLen {
+		high = maxLen
+	}
+	if high < low {
+		return low
+	}
+	return high
+}
+
+// isCollection reports whether a type has a size to pick
+func isCollection(typ string) bool {
+	return strings.HasSuffix(typ, "[]") || typ == "string" || typ == "TreeNode" || typ == "ListNode"
+}
+
+// isScalar reports whether a type is a scalar element type
+func isScalar(typ string) bool {
+	switch typ {
+	case "int", "float", "bool", "char", "string":
+		return true
+	}
+	return false
+}
+
+// formatList formats values as a bracketed, comma-separated list
+func formatList(values []string) string {
+	return "[" + strings.Join(values, ",") + "]"
+}
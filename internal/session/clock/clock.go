@@ -0,0 +1,289 @@
+// Package clock provides the single authoritative timer for a session.
+// The session owns the clock and every UI reads from it, so the time shown
+// always matches the time recorded in stats.
+package clock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Mode selects whether a clock counts up or down
+type Mode string
+
+const (
+	// Stopwatch counts up from zero with no limit
+	Stopwatch Mode = "stopwatch"
+	// Countdown counts down from a limit
+	Countdown Mode = "countdown"
+)
+
+// TickInterval is how often UIs refresh their clock display
+const TickInterval = time.Second
+
+// Snapshot is a consistent view of a clock at one instant
+type Snapshot struct {
+	Mode      Mode
+	Limit     time.Duration
+	Elapsed   time.Duration
+	Remaining time.Duration // zero for a stopwatch
+	Paused    bool
+	Expired   bool
+}
+
+// Display returns the time a UI should show: remaining time for a
+// countdown, elapsed time for a stopwatch
+func (s Snapshot) Display() time.Duration {
+	if s.Mode == Countdown {
+		return s.Remaining
+	}
+	return s.Elapsed
+}
+
+// TickMsg is delivered to bubbletea programs each TickInterval
+type TickMsg struct {
+	Snapshot
+}
+
+// State is the persisted form of a clock
+type State struct {
+	Mode    Mode          `json:"mode"`
+	Limit   time.Duration `json:"limit"`
+	Elapsed time.Duration `json:"elapsed"`
+	Paused  bool          `json:"paused"`
+	SavedAt time.Time     `json:"saved_at"`
+}
+
+// Clock tracks active time for a session. Paused time is not counted.
+// A Clock is safe for concurrent use.
+type Clock struct {
+	mu        sync.Mutex
+	mode      Mode
+	limit     time.Duration
+	elapsed   time.Duration // accumulated before the current run
+	startedAt time.Time     // start of the current run
+	running   bool
+	expired   bool // expiry has been announced to subscribers
+	now       func() time.Time
+
+	subscribers map[int]func(Snapshot)
+	nextID      int
+}
+
+// NewStopwatch creates a clock that counts up
+func NewStopwatch() *Clock {
+	return &Clock{
+		mode:        Stopwatch,
+		now:         time.Now,
+		subscribers: make(map[int]func(Snapshot)),
+	}
+}
+
+// NewCountdown creates a clock that counts down from limit
+func NewCountdown(limit time.Duration) *Clock {
+	c := NewStopwatch()
+	c.mode = Countdown
+	c.limit = limit
+	return c
+}
+
+// WithNow sets the time source, for tests
+func (c *Clock) WithNow(now func() time.Time) *Clock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+	return c
+}
+
+// Start starts or resumes the clock
+func (c *Clock) Start() {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = true
+	c.startedAt = c.now()
+	snap := c.snapshotLocked()
+	c.mu.Unlock()
+
+	c.notify(snap)
+}
+
+// Resume is an alias for Start
+func (c *Clock) Resume() {
+	c.Start()
+}
+
+// Pause stops counting until the clock is resumed
+func (c *Clock) Pause() {
+	c.mu.Lock()
+	if !c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.elapsed += c.now().Sub(c.startedAt)
+	c.running = false
+	snap := c.snapshotLocked()
+	c.mu.Unlock()
+
+	c.notify(snap)
+}
+
+// Toggle pauses a running clock or resumes a paused one
+func (c *Clock) Toggle() {
+	if c.Paused() {
+		c.Start()
+	} else {
+		c.Pause()
+	}
+}
+
+// Paused reports whether the clock is stopped
+func (c *Clock) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.running
+}
+
+// Elapsed returns the active time so far
+func (c *Clock) Elapsed() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elapsedLocked()
+}
+
+// Remaining returns the time left on a countdown, or zero for a stopwatch
+func (c *Clock) Remaining() time.Duration {
+	return c.Snapshot().Remaining
+}
+
+// Expired reports whether a countdown has run out
+func (c *Clock) Expired() bool {
+	return c.Snapshot().Expired
+}
+
+// Snapshot returns the current state. The first snapshot taken after a
+// countdown runs out is also sent to subscribers.
+func (c *Clock) Snapshot() Snapshot {
+	c.mu.Lock()
+	snap := c.snapshotLocked()
+	announce := snap.Expired && !c.expired
+	if announce {
+		c.expired = true
+	}
+	c.mu.Unlock()
+
+	if announce {
+		c.notify(snap)
+	}
+	return snap
+}
+
+// Subscribe registers fn to be called whenever the clock starts, pauses or
+// expires. The returned function removes the subscription.
+func (c *Clock) Subscribe(fn func(Snapshot)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	c.subscribers[id] = fn
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscribers, id)
+	}
+}
+
+// State returns the clock in its persisted form
+func (c *Clock) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return State{
+		Mode:    c.mode,
+		Limit:   c.limit,
+		Elapsed: c.elapsedLocked(),
+		Paused:  !c.running,
+		SavedAt: c.now(),
+	}
+}
+
+// MarshalJSON encodes the clock's persisted state
+func (c *Clock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.State())
+}
+
+// FromState restores a persisted clock. Time between saving and restoring
+// is not counted; a clock that was running is resumed from now.
+func FromState(state State) (*Clock, error) {
+	var c *Clock
+	switch state.Mode {
+	case Stopwatch, "":
+		c = NewStopwatch()
+	case Countdown:
+		c = NewCountdown(state.Limit)
+	default:
+		return nil, fmt.Errorf("unknown clock mode %q", state.Mode)
+	}
+
+	c.elapsed = state.Elapsed
+	if !state.Paused {
+		c.running = true
+		c.startedAt = c.now()
+	}
+	return c, nil
+}
+
+// Parse restores a clock from JSON written by MarshalJSON
+func Parse(data []byte) (*Clock, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse clock state: %v", err)
+	}
+	return FromState(state)
+}
+
+// elapsedLocked returns active time; c.mu must be held
+func (c *Clock) elapsedLocked() time.Duration {
+	elapsed := c.elapsed
+	if c.running {
+		elapsed += c.now().Sub(c.startedAt)
+	}
+	return elapsed
+}
+
+// snapshotLocked builds a snapshot; c.mu must be held
+func (c *Clock) snapshotLocked() Snapshot {
+	snap := Snapshot{
+		Mode:    c.mode,
+		Limit:   c.limit,
+		Elapsed: c.elapsedLocked(),
+		Paused:  !c.running,
+	}
+	if c.mode == Countdown {
+		snap.Remaining = c.limit - snap.Elapsed
+		if snap.Remaining <= 0 {
+			snap.Remaining = 0
+			snap.Expired = true
+		}
+	}
+	return snap
+}
+
+// notify calls subscribers outside the lock so they may use the clock
+func (c *Clock) notify(snap Snapshot) {
+	c.mu.Lock()
+	subs := make([]func(Snapshot), 0, len(c.subscribers))
+	for _, fn := range c.subscribers {
+		subs = append(subs, fn)
+	}
+	c.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(snap)
+	}
+}
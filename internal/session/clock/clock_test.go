@@ -0,0 +1,135 @@
+package clock
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNow is a controllable time source
+type fakeNow struct {
+	t time.Time
+}
+
+func (f *fakeNow) now() time.Time          { return f.t }
+func (f *fakeNow) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newFakeNow() *fakeNow {
+	return &fakeNow{t: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func TestStopwatch(t *testing.T) {
+	fake := newFakeNow()
+	c := NewStopwatch().WithNow(fake.now)
+
+	assert.True(t, c.Paused())
+	fake.advance(time.Minute)
+	assert.Equal(t, time.Duration(0), c.Elapsed(), "clock should not count before it starts")
+
+	c.Start()
+	fake.advance(90 * time.Second)
+	assert.Equal(t, 90*time.Second, c.Elapsed())
+	assert.Equal(t, time.Duration(0), c.Remaining())
+	assert.False(t, c.Expired())
+	assert.Equal(t, 90*time.Second, c.Snapshot().Display())
+}
+
+func TestPauseExcludesPausedTime(t *testing.T) {
+	fake := newFakeNow()
+	c := NewStopwatch().WithNow(fake.now)
+
+	c.Start()
+	fake.advance(time.Minute)
+	c.Pause()
+	fake.advance(10 * time.Minute)
+	assert.Equal(t, time.Minute, c.Elapsed())
+	assert.True(t, c.Snapshot().Paused)
+
+	c.Toggle()
+	fake.advance(30 * time.Second)
+	assert.Equal(t, 90*time.Second, c.Elapsed())
+
+	c.Toggle()
+	assert.True(t, c.Paused())
+}
+
+func TestCountdown(t *testing.T) {
+	fake := newFakeNow()
+	c := NewCountdown(10 * time.Minute).WithNow(fake.now)
+	c.Start()
+
+	fake.advance(4 * time.Minute)
+	snap := c.Snapshot()
+	assert.Equal(t, 6*time.Minute, snap.Remaining)
+	assert.Equal(t, 6*time.Minute, snap.Display())
+	assert.False(t, snap.Expired)
+
+	fake.advance(7 * time.Minute)
+	assert.Equal(t, time.Duration(0), c.Remaining())
+	assert.True(t, c.Expired())
+	assert.Equal(t, 11*time.Minute, c.Elapsed(), "elapsed keeps counting past the limit")
+}
+
+func TestSubscribe(t *testing.T) {
+	fake := newFakeNow()
+	c := NewCountdown(time.Minute).WithNow(fake.now)
+
+	var events []Snapshot
+	unsubscribe := c.Subscribe(func(s Snapshot) {
+		events = append(events, s)
+	})
+
+	c.Start()
+	c.Start() // already running, no event
+	fake.advance(20 * time.Second)
+	c.Pause()
+	c.Resume()
+	require.Len(t, events, 3)
+	assert.False(t, events[0].Paused)
+	assert.True(t, events[1].Paused)
+	assert.Equal(t, 20*time.Second, events[1].Elapsed)
+
+	// Expiry is announced once
+	fake.advance(time.Minute)
+	c.Snapshot()
+	c.Snapshot()
+	require.Len(t, events, 4)
+	assert.True(t, events[3].Expired)
+
+	unsubscribe()
+	c.Pause()
+	assert.Len(t, events, 4)
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	fake := newFakeNow()
+	c := NewCountdown(30 * time.Minute).WithNow(fake.now)
+	c.Start()
+	fake.advance(5 * time.Minute)
+	c.Pause()
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	restored, err := Parse(data)
+	require.NoError(t, err)
+	assert.True(t, restored.Paused())
+	assert.Equal(t, 5*time.Minute, restored.Elapsed())
+	assert.Equal(t, 25*time.Minute, restored.Remaining())
+
+	// A running clock resumes when restored
+	c.Start()
+	restored, err = FromState(c.State())
+	require.NoError(t, err)
+	assert.False(t, restored.Paused())
+	assert.GreaterOrEqual(t, restored.Elapsed(), 5*time.Minute)
+
+	_, err = FromState(State{Mode: "hourglass"})
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("{"))
+	assert.Error(t, err)
+}
@@ -92,6 +92,14 @@ func (cm *CodeManagerImpl) InitializeWorkspace(prob *interfaces.Problem, languag
 		extension = ".py"
 	case "javascript":
 		extension = ".js"
+	case "rust":
+		extension = ".rs"
+	case "java":
+		extension = ".java"
+	case "cpp", "c++":
+		extension = ".cpp"
+	case "typescript", "ts":
+		extension = ".ts"
 	default:
 		extension = ".txt"
 	}
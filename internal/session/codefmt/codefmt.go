@@ -0,0 +1,171 @@
+// Package codefmt runs each language's standard formatter over user code
+// before it is shown or archived, so that reviews and comparisons of
+// solutions are not cluttered by whitespace differences. Formatters that are
+// not installed, or that reject the code, leave it unchanged.
+package codefmt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// Off disables formatting for a language in the formatters config
+const Off = "off"
+
+// DefaultCommands are the formatter commands by language. Each reads the
+// code on stdin and writes the formatted code to stdout.
+var DefaultCommands = map[string]string{
+	"go":         "gofmt",
+	"python":     "black --quiet -",
+	"javascript": "prettier --stdin-filepath solution.js",
+	"typescript": "prettier --stdin-filepath solution.ts",
+	"java":       "google-java-format -",
+	"cpp":        "clang-format --assume-filename=solution.cpp",
+	"rust":       "rustfmt --edition 2021",
+}
+
+// builtins are formatters run in-process instead of as a command. gofmt is
+// built in so Go code is formatted even without a Go toolchain installed.
+var builtins = map[string]func([]byte) ([]byte, error){
+	"gofmt": format.Source,
+}
+
+// timeout bounds a single formatter run
+const timeout = 5 * time.Second
+
+// cacheSize caps how many formatted results are remembered; views format
+// the same code on every redraw
+const cacheSize = 64
+
+// ErrUnavailable is returned when a language has no formatter, formatting
+// is turned off for it, or its formatter is not installed
+var ErrUnavailable = errors.New("no formatter available")
+
+// lookPath finds formatter executables
+// Exported as variable for testing
+var lookPath = exec.LookPath
+
+// Formatter formats code with a command per language
+type Formatter struct {
+	commands map[string]string
+
+	mu    sync.Mutex
+	cache map[cacheKey]string
+}
+
+// cacheKey identifies a formatted result
+type cacheKey struct {
+	language, code string
+}
+
+// New creates a formatter using the default commands, replaced per language
+// by overrides. An override of Off or an empty command disables formatting
+// for that language.
+func New(overrides map[string]string) *Formatter {
+	commands := make(map[string]string, len(DefaultCommands)+len(overrides))
+	for language, command := range DefaultCommands {
+		commands[language] = command
+	}
+	for language, command := range overrides {
+		commands[language] = strings.TrimSpace(command)
+	}
+	return &Formatter{commands: commands, cache: make(map[cacheKey]string)}
+}
+
+// Format returns code formatted by the language's formatter
+func (f *Formatter) Format(ctx context.Context, language, code string) (string, error) {
+	command := f.commands[language]
+	if command == "" || command == Off {
+		return "", ErrUnavailable
+	}
+	if builtin, ok := builtins[command]; ok {
+		out, err := builtin([]byte(code))
+		if err != nil {
+			return "", fmt.Errorf("failed to format %s code: %v", language, err)
+		}
+		return string(out), nil
+	}
+
+	args := strings.Fields(command)
+	path, err := lookPath(args[0])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s is not installed", ErrUnavailable, args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args[1:]...)
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("failed to format %s code: %v: %s", language, err, msg)
+		}
+		return "", fmt.Errorf("failed to format %s code: %v", language, err)
+	}
+	return stdout.String(), nil
+}
+
+// Code returns code formatted if possible and unchanged otherwise. Results
+// are cached, so it is cheap to call on every render.
+func (f *Formatter) Code(language, code string) string {
+	if strings.TrimSpace(code) == "" {
+		return code
+	}
+	key := cacheKey{language, code}
+	f.mu.Lock()
+	formatted, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok {
+		return formatted
+	}
+
+	formatted, err := f.Format(context.Background(), language, code)
+	if err != nil || strings.TrimSpace(formatted) == "" {
+		formatted = code
+	}
+
+	f.mu.Lock()
+	if len(f.cache) >= cacheSize {
+		f.cache = make(map[cacheKey]string)
+	}
+	f.cache[key] = formatted
+	f.mu.Unlock()
+	return formatted
+}
+
+// defaultFormatter is built from the config on first use
+var (
+	defaultOnce      sync.Once
+	defaultFormatter *Formatter
+)
+
+// Default returns the formatter configured in the user config
+// Exported as variable for testing
+var Default = func() *Formatter {
+	defaultOnce.Do(func() {
+		var overrides map[string]string
+		if cfg, err := config.LoadConfig(); err == nil {
+			overrides = cfg.Formatters
+		}
+		defaultFormatter = New(overrides)
+	})
+	return defaultFormatter
+}
+
+// Code formats code with the configured formatter, leaving it unchanged if
+// it cannot be formatted
+func Code(language, code string) string {
+	return Default().Code(language, code)
+}
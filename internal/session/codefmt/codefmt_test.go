@@ -0,0 +1,67 @@
+package codefmt
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatGo(t *testing.T) {
+	f := New(nil)
+
+	// Solutions are often just a function, without a package clause
+	formatted, err := f.Format(context.Background(), "go", "func twoSum(nums []int,target int) []int{\nreturn nil}")
+	require.NoError(t, err)
+	assert.Equal(t, "func twoSum(nums []int, target int) []int {\n\treturn nil\n}", formatted)
+
+	_, err = f.Format(context.Background(), "go", "func {")
+	assert.Error(t, err)
+}
+
+func TestFormatCommand(t *testing.T) {
+	if _, err := exec.LookPath("tr"); err != nil {
+		t.Skip("tr not available")
+	}
+	f := New(map[string]string{"python": "tr a-z A-Z", "go": Off, "rust": "false"})
+
+	formatted, err := f.Format(context.Background(), "python", "x = 1\n")
+	require.NoError(t, err)
+	assert.Equal(t, "X = 1\n", formatted)
+
+	_, err = f.Format(context.Background(), "go", "package main")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	_, err = f.Format(context.Background(), "rust", "fn main() {}")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+
+	_, err = f.Format(context.Background(), "cobol", "DISPLAY 'HI'.")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestFormatMissingCommand(t *testing.T) {
+	original := lookPath
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	defer func() { lookPath = original }()
+
+	_, err := New(nil).Format(context.Background(), "python", "x=1")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Contains(t, err.Error(), "black is not installed")
+}
+
+func TestCode(t *testing.T) {
+	f := New(nil)
+
+	assert.Equal(t, "x := 1", f.Code("go", "x:=1"))
+	assert.Equal(t, "func {", f.Code("go", "func {"), "unformattable code is left unchanged")
+	assert.Equal(t, "  ", f.Code("go", "  "))
+
+	// Results are cached
+	f.commands["go"] = Off
+	assert.Equal(t, "x := 1", f.Code("go", "x:=1"))
+	assert.Equal(t, "y:=2", f.Code("go", "y:=2"))
+}
@@ -0,0 +1,212 @@
+// Package cram runs cram sessions: a queue of problems across patterns,
+// worked through one after another against a single countdown
+package cram
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// DefaultProblems is how many problems a cram session queues unless told
+// otherwise
+const DefaultProblems = 5
+
+// Outcome is how a problem in a cram session ended
+type Outcome string
+
+const (
+	// Solved means every test passed
+	Solved Outcome = "solved"
+	// TimedOut means the problem's share of the countdown ran out
+	TimedOut Outcome = "timed_out"
+	// Skipped means the user moved on before solving it
+	Skipped Outcome = "skipped"
+)
+
+// Result is how one problem of a cram session went
+type Result struct {
+	Problem problem.Problem
+	Outcome Outcome
+	Time    time.Duration
+}
+
+// Session is a cram session: its queue, the countdown the whole queue
+// shares, and how each problem finished so far went
+type Session struct {
+	Queue   []problem.Problem
+	Budget  time.Duration // The countdown for the whole queue
+	Results []Result      // One per problem finished, in queue order
+	ended   bool
+}
+
+// New starts a cram session through queue with budget for all of it
+func New(queue []problem.Problem, budget time.Duration) *Session {
+	return &Session{Queue: queue, Budget: budget}
+}
+
+// PatternOrder returns the patterns of problems in the order a cram session
+// takes them: the patterns never practiced, in random order, then the
+// practiced ones from the soonest to decay
+func PatternOrder(problems []problem.Problem, forecasts []stats.PatternRetention) []string {
+	decays := make(map[string]time.Time, len(forecasts))
+	for _, f := range forecasts {
+		decays[f.Pattern] = f.DecaysAt
+	}
+	var fresh, practiced []string
+	seen := make(map[string]bool)
+	for _, p := range problems {
+		for _, pattern := range p.Patterns {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+			if _, ok := decays[pattern]; ok {
+				practiced = append(practiced, pattern)
+			} else {
+				fresh = append(fresh, pattern)
+			}
+		}
+	}
+	rand.Shuffle(len(fresh), func(i, j int) { fresh[i], fresh[j] = fresh[j], fresh[i] })
+	sort.SliceStable(practiced, func(i, j int) bool {
+		return decays[practiced[i]].Before(decays[practiced[j]])
+	})
+	return append(fresh, practiced...)
+}
+
+// Plan queues up to n problems, taking one of each pattern in order before
+// coming back to any, so a pattern only repeats once every other has had a
+// turn. Each problem counts towards the first of its patterns in order;
+// problems of no pattern in order are left out.
+func Plan(problems []problem.Problem, n int, order []string) []problem.Problem {
+	byPattern := make(map[string][]problem.Problem, len(order))
+	for _, p := range problems {
+		for _, pattern := range order {
+			if contains(p.Patterns, pattern) {
+				byPattern[pattern] = append(byPattern[pattern], p)
+				break
+			}
+		}
+	}
+	for _, list := range byPattern {
+		rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+	}
+
+	var queue []problem.Problem
+	for len(queue) < n {
+		added := false
+		for _, pattern := range order {
+			list := byPattern[pattern]
+			if len(queue) == n || len(list) == 0 {
+				continue
+			}
+			queue = append(queue, list[0])
+			byPattern[pattern] = list[1:]
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+	return queue
+}
+
+// Current returns the problem being worked on, or nil once the session is
+// done
+func (s *Session) Current() *problem.Problem {
+	if s.Done() {
+		return nil
+	}
+	return &s.Queue[len(s.Results)]
+}
+
+// Position returns the current problem's place in the queue, from 1
+func (s *Session) Position() int {
+	return min(len(s.Results)+1, len(s.Queue))
+}
+
+// Used returns the time the finished problems took
+func (s *Session) Used() time.Duration {
+	var used time.Duration
+	for _, r := range s.Results {
+		used += r.Time
+	}
+	return used
+}
+
+// Remaining returns the time left on the countdown for the problems not yet
+// finished
+func (s *Session) Remaining() time.Duration {
+	return max(s.Budget-s.Used(), 0)
+}
+
+// Allotment returns the current problem's share of the countdown: what is
+// left split evenly over the problems left, so time saved on one problem
+// carries over to the rest
+func (s *Session) Allotment() time.Duration {
+	left := len(s.Queue) - len(s.Results)
+	if left <= 0 {
+		return 0
+	}
+	return s.Remaining() / time.Duration(left)
+}
+
+// Finish records how the current problem ended after spent, and moves on
+// to the next one
+func (s *Session) Finish(outcome Outcome, spent time.Duration) {
+	current := s.Current()
+	if current == nil {
+		return
+	}
+	s.Results = append(s.Results, Result{Problem: *current, Outcome: outcome, Time: min(spent, s.Remaining())})
+}
+
+// End stops the session before the queue is worked through
+func (s *Session) End() {
+	s.ended = true
+}
+
+// Done reports whether the session is over: every problem finished, the
+// countdown run out, or the session ended early
+func (s *Session) Done() bool {
+	return s.ended || len(s.Results) >= len(s.Queue) || s.Remaining() == 0
+}
+
+// Summary totals a cram session
+type Summary struct {
+	Solved    int
+	TimedOut  int
+	Skipped   int
+	Unreached int // Problems never started
+	Time      time.Duration
+}
+
+// Summarize totals the session's results
+func (s *Session) Summarize() Summary {
+	summary := Summary{Unreached: len(s.Queue) - len(s.Results), Time: s.Used()}
+	for _, r := range s.Results {
+		switch r.Outcome {
+		case Solved:
+			summary.Solved++
+		case TimedOut:
+			summary.TimedOut++
+		case Skipped:
+			summary.Skipped++
+		}
+	}
+	return summary
+}
+
+// contains reports whether a slice holds s
+func contains(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
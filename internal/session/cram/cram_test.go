@@ -0,0 +1,78 @@
+package cram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var problems = []problem.Problem{
+	{ID: "pair_sum", Patterns: []string{"two-pointers"}},
+	{ID: "three_sum", Patterns: []string{"two-pointers", "sorting"}},
+	{ID: "two_sum", Patterns: []string{"hash-map"}},
+	{ID: "islands", Patterns: []string{"dfs"}},
+	{ID: "paths", Patterns: []string{"dfs"}},
+}
+
+func TestPatternOrder(t *testing.T) {
+	now := time.Now()
+	forecasts := []stats.PatternRetention{
+		{Pattern: "hash-map", DecaysAt: now.Add(48 * time.Hour)},
+		{Pattern: "dfs", DecaysAt: now.Add(-time.Hour)},
+	}
+	order := PatternOrder(problems, forecasts)
+	require.Len(t, order, 4)
+	assert.ElementsMatch(t, []string{"two-pointers", "sorting"}, order[:2], "never practiced first")
+	assert.Equal(t, []string{"dfs", "hash-map"}, order[2:], "then the soonest to decay")
+}
+
+func TestPlan(t *testing.T) {
+	order := []string{"dfs", "two-pointers", "hash-map", "sorting"}
+	queue := Plan(problems, 4, order)
+	require.Len(t, queue, 4)
+	assert.Contains(t, []string{"islands", "paths"}, queue[0].ID)
+	assert.Contains(t, []string{"pair_sum", "three_sum"}, queue[1].ID)
+	assert.Equal(t, "two_sum", queue[2].ID)
+	assert.Contains(t, []string{"islands", "paths"}, queue[3].ID, "dfs comes back once every pattern had a turn")
+	assert.NotEqual(t, queue[0].ID, queue[3].ID)
+
+	// Problems are not repeated, and ones of no pattern in order are left out
+	queue = Plan(problems, 10, []string{"hash-map", "sorting"})
+	require.Len(t, queue, 2)
+	assert.Equal(t, "two_sum", queue[0].ID)
+	assert.Equal(t, "three_sum", queue[1].ID)
+}
+
+func TestSession(t *testing.T) {
+	s := New(problems[:3], 30*time.Minute)
+	assert.Equal(t, "pair_sum", s.Current().ID)
+	assert.Equal(t, 1, s.Position())
+	assert.Equal(t, 10*time.Minute, s.Allotment())
+
+	// Time saved on one problem carries over to the rest
+	s.Finish(Solved, 4*time.Minute)
+	assert.Equal(t, "three_sum", s.Current().ID)
+	assert.Equal(t, 2, s.Position())
+	assert.Equal(t, 13*time.Minute, s.Allotment())
+
+	s.Finish(TimedOut, 13*time.Minute)
+	assert.Equal(t, 13*time.Minute, s.Allotment())
+	assert.False(t, s.Done())
+
+	s.Finish(Skipped, 20*time.Minute)
+	assert.True(t, s.Done())
+	assert.Nil(t, s.Current())
+	assert.Equal(t, 30*time.Minute, s.Used(), "no more than the countdown is counted")
+	assert.Equal(t, Summary{Solved: 1, TimedOut: 1, Skipped: 1, Time: 30 * time.Minute}, s.Summarize())
+
+	// Ending early leaves the rest unreached
+	s = New(problems, time.Hour)
+	s.Finish(Solved, time.Minute)
+	s.End()
+	assert.True(t, s.Done())
+	assert.Equal(t, Summary{Solved: 1, Unreached: 4, Time: time.Minute}, s.Summarize())
+}
@@ -47,6 +47,8 @@ func CreateSession(opts Options) (*Session, error) {
 		ShowSolution: sessionImpl.solutionShown,
 		Workspace:    sessionImpl.Workspace,
 		CodeFile:     sessionImpl.CodeFile,
+		Clock:        sessionImpl.clock,
+		Reason:       sessionImpl.Reason,
 	}
 
 	// Workspace is already created by the manager, so we can return directly
@@ -88,11 +90,25 @@ func getDefaultProblem() *problem.Problem {
 			"go":         "func twoSum(nums []int, target int) []int {\n    // Your solution here\n}",
 			"python":     "def two_sum(nums, target):\n    # Your solution here\n    pass",
 			"javascript": "function twoSum(nums, target) {\n    // Your solution here\n}",
+			"rust":       "pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    // Your solution here\n    todo!()\n}",
+			"java":       "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        // Your solution here\n        return new int[0];\n    }\n}",
+			"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        // Your solution here\n        return {};\n    }\n};",
+			"typescript": "function twoSum(nums: number[], target: number): number[] {\n    // Your solution here\n    return [];\n}",
 		},
 		Solutions: map[string]string{
 			"go":         "func twoSum(nums []int, target int) []int {\n    seen := make(map[int]int)\n    for i, num := range nums {\n        complement := target - num\n        if j, ok := seen[complement]; ok {\n            return []int{j, i}\n        }\n        seen[num] = i\n    }\n    return []int{}\n}",
 			"python":     "def two_sum(nums, target):\n    seen = {}\n    for i, num in enumerate(nums):\n        complement = target - num\n        if complement in seen:\n            return [seen[complement], i]\n        seen[num] = i\n    return []",
 			"javascript": "function twoSum(nums, target) {\n    const seen = {};\n    for (let i = 0; i < nums.length; i++) {\n        const complement = target - nums[i];\n        if (complement in seen) {\n            return [seen[complement], i];\n        }\n        seen[nums[i]] = i;\n    }\n    return [];\n}",
+			"rust":       "use std::collections::HashMap;\n\npub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    let mut seen = HashMap::new();\n    for (i, &num) in nums.iter().enumerate() {\n        if let Some(&j) = seen.get(&(target - num)) {\n            return vec![j as i32, i as i32];\n        }\n        seen.insert(num, i);\n    }\n    vec![]\n}",
+			"java":       "import java.util.HashMap;\nimport java.util.Map;\n\nclass Solution {\n    public int[] twoSum(int[] nums, int target) {\n        Map<Integer, Integer> seen = new HashMap<>();\n        for (int i = 0; i < nums.length; i++) {\n            Integer j = seen.get(target - nums[i]);\n            if (j != null) {\n                return new int[]{j, i};\n            }\n            seen.put(nums[i], i);\n        }\n        return new int[0];\n    }\n}",
+			"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        unordered_map<int, int> seen;\n        for (int i = 0; i < (int)nums.size(); i++) {\n            auto it = seen.find(target - nums[i]);\n            if (it != seen.end()) {\n                return {it->second, i};\n            }\n            seen[nums[i]] = i;\n        }\n        return {};\n    }\n};",
+			"typescript": "function twoSum(nums: number[], target: number): number[] {\n    const seen = new Map<number, number>();\n    for (let i = 0; i < nums.length; i++) {\n        const j = seen.get(target - nums[i]);\n        if (j !== undefined) {\n            return [j, i];\n        }\n        seen.set(nums[i], i);\n    }\n    return [];\n}",
+		},
+		Signature: &interfaces.FunctionSignature{
+			Name:      "twoSum",
+			Params:    []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+			Returns:   "int[]",
+			Unordered: true,
 		},
 		TestCases: []problem.TestCase{
 			{
@@ -0,0 +1,114 @@
+// Environment snapshots for session reproducibility
+package session
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// toolchainCommands maps a language to the command that reports its version
+var toolchainCommands = map[string][]string{
+	"go":         {"go", "version"},
+	"python":     {"python3", "--version"},
+	"javascript": {"node", "--version"},
+	"java":       {"java", "-version"},
+	"cpp":        {"g++", "--version"},
+	"typescript": {"tsc", "--version"},
+	"rust":       {"rustc", "--version"},
+}
+
+// toolchainCache avoids shelling out more than once per language per process
+var toolchainCache sync.Map
+
+// toolchainVersion returns the version string of the toolchain used for a language
+// Exported as variable for testing
+var toolchainVersion = func(language string) string {
+	if cached, ok := toolchainCache.Load(language); ok {
+		return cached.(string)
+	}
+
+	args, ok := toolchainCommands[language]
+	if !ok {
+		return "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Some tools (java) report their version on stderr
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	version := "unavailable"
+	if err == nil {
+		// Only the first line is meaningful for every supported toolchain
+		version = strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+	}
+
+	toolchainCache.Store(language, version)
+	return version
+}
+
+// CaptureEnvironment records the toolchain and problem revision a session runs against
+func CaptureEnvironment(prob problem.Problem, language string) *interfaces.EnvironmentSnapshot {
+	return &interfaces.EnvironmentSnapshot{
+		Language:       language,
+		Toolchain:      toolchainVersion(language),
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		ProblemVersion: prob.DefinitionVersion(),
+		TestSetHash:    prob.TestSetHash(),
+	}
+}
+
+// CheckProblemChanged compares a problem against the environment recorded by
+// its most recent session. It returns a user-facing warning when the problem
+// definition or its test set has changed since then, or "" otherwise.
+func CheckProblemChanged(prob problem.Problem) string {
+	sessions, err := stats.GetAllSessions()
+	if err != nil {
+		return ""
+	}
+
+	var last *interfaces.EnvironmentSnapshot
+	var lastTime time.Time
+	for _, s := range sessions {
+		if s.ProblemID != prob.ID || s.Environment == nil {
+			continue
+		}
+		if last == nil || s.StartTime.After(lastTime) {
+			last = s.Environment
+			lastTime = s.StartTime
+		}
+	}
+
+	return describeProblemChange(last, prob, lastTime)
+}
+
+// describeProblemChange formats the warning for a changed problem
+func describeProblemChange(last *interfaces.EnvironmentSnapshot, prob problem.Problem, when time.Time) string {
+	if last == nil {
+		return ""
+	}
+
+	var changes []string
+	if last.TestSetHash != prob.TestSetHash() {
+		changes = append(changes, "test cases")
+	}
+	if last.ProblemVersion != prob.DefinitionVersion() {
+		changes = append(changes, "problem definition")
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("⚠️  The %s for %s changed since your last attempt on %s; earlier results may not be comparable.",
+		strings.Join(changes, " and "), prob.ID, when.Format("2006-01-02"))
+}
@@ -0,0 +1,172 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/logging"
+)
+
+// cppCompilers are tried in order when no compiler is configured
+var cppCompilers = []string{"g++", "clang++", "c++"}
+
+// CppTestRunner implements the TestRunner interface for C++ code
+type CppTestRunner struct {
+	BaseTestRunner
+	compiler string
+}
+
+// NewCppTestRunner creates a new C++ test runner using the first compiler
+// found on the PATH
+func NewCppTestRunner() *CppTestRunner {
+	compiler := cppCompilers[0]
+	for _, candidate := range cppCompilers {
+		if _, err := exec.LookPath(candidate); err == nil {
+			compiler = candidate
+			break
+		}
+	}
+
+	return &CppTestRunner{
+		BaseTestRunner: NewBaseTestRunner("cpp"),
+		compiler:       compiler,
+	}
+}
+
+// WithCompiler sets the compiler used to build solutions, e.g. clang++
+func (r *CppTestRunner) WithCompiler(compiler string) *CppTestRunner {
+	r.compiler = compiler
+	return r
+}
+
+// ExecuteTests compiles a C++ solution and runs its tests
+func (r *CppTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Problem, code string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+	// Create a context with timeout for the entire operation
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Add logging context
+	ctx = logging.WithOperation(ctx, "ExecuteCppTests")
+	ctx = logging.WithComponent(ctx, "CppTestRunner")
+	logger := logging.TestRunnerLogger.WithContext(ctx)
+
+	// Create session snapshot for error logging
+	sessionState := &logging.SessionSnapshot{
+		ProblemID:  prob.ID,
+		Language:   "cpp",
+		Mode:       "test_execution",
+		UserCode:   code,
+		StartTime:  time.Now(),
+		Patterns:   prob.Tags,
+		Difficulty: prob.Difficulty,
+		CustomFields: map[string]string{
+			"timeout":    timeout.String(),
+			"test_count": fmt.Sprintf("%d", len(prob.TestCases)),
+			"compiler":   r.compiler,
+		},
+	}
+
+	// Log operation start
+	finishLog := logger.StartOperation(fmt.Sprintf("Execute C++ tests for problem %s", prob.ID))
+	defer func() {
+		if r := recover(); r != nil {
+			if logging.GlobalErrorLogger != nil {
+				logging.GlobalErrorLogger.LogPanic(ctx, r, "execute_cpp_tests", sessionState)
+			}
+			finishLog(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+	}()
+
+	// Create a temporary directory for the source and binary
+	testDir, err := os.MkdirTemp("", "algo-scales-cpp-test")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(testDir) // Clean up when done
+
+	// Generate test code
+	testCode, err := r.GenerateTestCode(prob, code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate test code: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(testDir, cppHarnessFile), []byte(testCode), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write test file: %v", err)
+	}
+
+	resultsFile := filepath.Join(testDir, resultsFileName)
+	binary := filepath.Join(testDir, "solution")
+
+	// Compile first so compiler errors can be reported on their own
+	compile := exec.CommandContext(ctx, r.compiler, "-std=c++17", "-O2", "-o", binary, cppHarnessFile)
+	compile.Dir = testDir
+	_, stderr, err := runCommandWithTimeout(compile, timeout)
+	if err != nil {
+		results := readResults(resultsFile, prob.TestCases, err, summarizeCppErrors(stderr.String()))
+		return withFailure(results, interfaces.FailureCompile), false, nil
+	}
+
+	// Run the compiled harness in the sandbox and read the results it wrote;
+	// a crash such as a segmentation fault explains the tests that never
+	// finished
+	cmd := ActiveSandbox.command(ctx, "cpp", testDir, len(prob.TestCases), binary)
+	results, _ := runHarness(cmd, timeout, resultsFile, prob.TestCases)
+
+	return results, allTestsPassed(results), nil
+}
+
+// GenerateTestCode creates a C++ program that runs the solution against
+// every test case. Arguments are parsed according to the solution's function
+// signature; see cpp_harness.go for the supported parameter types.
+func (r *CppTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
+	// A main in the solution would clash with the harness
+	solutionCode = cppMainPattern.ReplaceAllString(solutionCode, "${1}_solution_main(")
+	if !strings.HasSuffix(solutionCode, "\n") {
+		solutionCode += "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(cppHarnessIncludes)
+	b.WriteString(cppHarnessSupport)
+	b.WriteString("\n// LeetCode-style solutions rely on the std namespace being open\n")
+	b.WriteString("using namespace std;\n\n")
+
+	// #line directives make diagnostics refer to the solution's own lines
+	b.WriteString(fmt.Sprintf("#line 1 %q\n", cppSolutionFile))
+	b.WriteString(solutionCode)
+	b.WriteString(fmt.Sprintf("#line %d %q\n", strings.Count(b.String(), "\n")+2, cppHarnessFile))
+
+	// An unsupported signature is reported once per test instead of failing
+	// to compile at all
+	var body string
+	fn, err := findCppFunction(solutionCode, prob.StarterCode["cpp"])
+	if err == nil {
+		err = fn.validate()
+	}
+	if err != nil {
+		body = fmt.Sprintf("        throw std::invalid_argument(%s);\n", cppString(err.Error()))
+	} else {
+		body = fn.callBody("        ")
+	}
+
+	b.WriteString("\nint main() {\n")
+	b.WriteString("    algoscales::Report report;\n")
+	for i, tc := range prob.TestCases {
+		b.WriteString(fmt.Sprintf("\n    // Test case %d\n", i+1))
+		b.WriteString(fmt.Sprintf("    algoscales::run(report, %d, %s, %s, [](const algoscales::Args& args) -> std::string {\n",
+			i+1, cppString(tc.Input), cppString(tc.Expected)))
+		b.WriteString(body)
+		b.WriteString("    });\n")
+	}
+	b.WriteString("\n    return report.passed() ? 0 : 1;\n")
+	b.WriteString("}\n")
+
+	return b.String(), nil
+}
@@ -11,6 +11,7 @@ import (
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/common/logging"
+	"strconv"
 )
 
 // GoTestRunner implements the TestRunner interface for Go code
@@ -98,22 +99,36 @@ func (r *GoTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Proble
 		return nil, false, fmt.Errorf("failed to write test file: %v", err)
 	}
 	
-	logger.Info("Executing Go test with timeout of %v", timeout)
-	// Build and run the test
-	cmd := exec.CommandContext(ctx, "go", "run", mainFile)
-	
 	// Update session state with test file info
 	sessionState.CodeFile = mainFile
 	sessionState.Workspace = testDir
 	
-	// Run the command with timeout
-	stdout, stderr, err := runCommandWithTimeout(cmd, timeout)
+	resultsFile := filepath.Join(testDir, resultsFileName)
+	binary := filepath.Join(testDir, "solution")
 	
-	// Parse the results from stdout
-	output := stdout.String()
-	results := parseTestOutput(output, prob.TestCases)
+	logger.Info("Building Go test")
+	// Compile first so only the test program runs in the sandbox
+	build := exec.CommandContext(ctx, "go", "build", "-o", binary, mainFile)
+	build.Dir = testDir
+	stdout, stderr, err := runCommandWithTimeout(build, timeout)
+	if err != nil {
+		logger.Warn("Test build failed with errors: %v", stderr.String())
+		if logging.GlobalErrorLogger != nil {
+			testError := fmt.Errorf("test build failed: %v\nSTDERR:\n%s", err, stderr.String())
+			logging.GlobalErrorLogger.LogTestExecutionError(ctx, testError, "go", code, "", sessionState)
+		}
+		results := readResults(resultsFile, prob.TestCases, err, stderr.String())
+		finishLog(nil)
+		return withFailure(results, interfaces.FailureCompile), false, nil
+	}
 	
-	// If there were compile errors, include them in the results
+	logger.Info("Executing Go test with timeout of %v", timeout)
+	// Run the test program in the sandbox and read the results it wrote
+	cmd := ActiveSandbox.command(ctx, "go", testDir, len(prob.TestCases), binary)
+	results, run := runHarness(cmd, timeout, resultsFile, prob.TestCases)
+	stdout, stderr, err = run.stdout, run.stderr, run.err
+	
+	// Log crashes
 	if err != nil && len(stderr.String()) > 0 {
 		logger.Warn("Test execution failed with errors: %v", stderr.String())
 		
@@ -122,8 +137,6 @@ func (r *GoTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Proble
 			testError := fmt.Errorf("test execution failed: %v\nSTDOUT:\n%s\nSTDERR:\n%s", err, stdout.String(), stderr.String())
 			logging.GlobalErrorLogger.LogTestExecutionError(ctx, testError, "go", code, "", sessionState)
 		}
-		
-		results = addErrorToResults(results, stderr.String())
 	}
 	
 	allPassed := allTestsPassed(results)
@@ -145,29 +158,20 @@ func (r *GoTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Proble
 	return results, allPassed, nil
 }
 
-// GenerateTestCode creates test code for a given problem
+// GenerateTestCode creates test code for a given problem. Each test input
+// is parsed into arguments according to the problem's function signature.
 func (r *GoTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
-	return r.generateTestTemplate(prob, solutionCode)
-}
-
-// generateTestTemplate generates the Go test template with proper two_sum implementation
-func (r *GoTestRunner) generateTestTemplate(prob *interfaces.Problem, solutionCode string) (string, error) {
-	// For two_sum problem, we need specific parsing logic
-	if prob.ID == "two_sum" {
-		return r.generateTwoSumTestTemplate(prob, solutionCode)
-	}
-	
-	// Generic template for other problems
 	testTemplate := `package main
 
 import (
 	"fmt"
 	"os"
+%s
 )
 
 // User's solution
 %s
-
+%s%s
 func main() {
 	// Run tests
 	allPassed := true
@@ -180,124 +184,37 @@ func main() {
 }
 `
 	
-	// Generate test code for each test case
-	var testCases strings.Builder
-	for i := range prob.TestCases {
-		testCases.WriteString(fmt.Sprintf("\n\t// Test case %d\n", i+1))
-		testCases.WriteString(fmt.Sprintf("\tfmt.Printf(\"Test %d\\n\")\n", i+1))
-		testCases.WriteString("\t// TODO: Implement test logic for this problem type\n")
-		testCases.WriteString("\tfmt.Println(\"❌ FAILED: Test not implemented\")\n")
-		testCases.WriteString("\tallPassed = false\n")
-	}
-	
-	return fmt.Sprintf(testTemplate, solutionCode, testCases.String()), nil
-}
-
-// generateTwoSumTestTemplate generates specific test template for two_sum problem
-func (r *GoTestRunner) generateTwoSumTestTemplate(prob *interfaces.Problem, solutionCode string) (string, error) {
-	testTemplate := `package main
-
-import (
-	"fmt"
-	"os"
-	"strconv"
-	"strings"
-)
-
-// User's solution
-%s
-
-// parseIntArray parses a string like "[1,2,3]" into []int
-func parseIntArray(s string) ([]int, error) {
-	s = strings.TrimSpace(s)
-	s = strings.TrimPrefix(s, "[")
-	s = strings.TrimSuffix(s, "]")
-	
-	if s == "" {
-		return []int{}, nil
+	sigErr := ValidateSignature(prob.Signature)
+	helpers := ""
+	if sigErr == nil {
+		helpers = goSignatureHelpers(prob.Signature)
 	}
 	
-	parts := strings.Split(s, ",")
-	result := make([]int, len(parts))
-	
-	for i, part := range parts {
-		num, err := strconv.Atoi(strings.TrimSpace(part))
+	// Generate test code for each test case
+	var testCases strings.Builder
+	for i, tc := range prob.TestCases {
+		testCases.WriteString(fmt.Sprintf("\n\t// Test case %d: %s\n", i+1, strings.ReplaceAll(tc.Input, "\n", " ")))
+		testCases.WriteString(fmt.Sprintf("\tif !algoscalesRun(%d, %s, func() (string, error) {\n", i+1, goRawString(expectedValue(prob.Signature, tc.Expected))))
+		
+		// Tests the harness cannot call still report an error each
+		args, err := testArgs(prob.Signature, sigErr, tc.Input)
 		if err != nil {
-			return nil, err
+			testCases.WriteString(fmt.Sprintf("\t\treturn \"\", fmt.Errorf(\"%%s\", %s)\n", goRawString(err.Error())))
+		} else {
+			testCases.WriteString(goCallBody(prob.Signature, args, "\t\t"))
 		}
-		result[i] = num
-	}
-	
-	return result, nil
-}
-
-// formatIntArray formats []int as a string like "[1,2]"
-func formatIntArray(arr []int) string {
-	if len(arr) == 0 {
-		return "[]"
-	}
-	
-	parts := make([]string, len(arr))
-	for i, num := range arr {
-		parts[i] = strconv.Itoa(num)
+		testCases.WriteString("\t}) {\n")
+		testCases.WriteString("\t\tallPassed = false\n")
+		testCases.WriteString("\t}\n")
 	}
 	
-	return "[" + strings.Join(parts, ",") + "]"
+	return fmt.Sprintf(testTemplate, goHarnessImports, solutionCode, goHarnessSupport, helpers, testCases.String()), nil
 }
 
-func main() {
-	// Run tests
-	allPassed := true
-	
-	%s
-	
-	if !allPassed {
-		os.Exit(1)
+// goRawString quotes a string for Go source, as a raw string when possible
+func goRawString(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
 	}
+	return "`" + s + "`"
 }
-`
-	
-	// Generate test code for each test case
-	var testCases strings.Builder
-	for i, tc := range prob.TestCases {
-		testCases.WriteString(fmt.Sprintf("\n\t// Test case %d\n", i+1))
-		testCases.WriteString(fmt.Sprintf("\tfmt.Printf(\"Test %d\\n\")\n", i+1))
-		
-		// Parse the input - for two_sum it's "array, target"
-		testCases.WriteString(fmt.Sprintf("\t{\n\t\tinputStr := `%s`\n", tc.Input))
-		testCases.WriteString(fmt.Sprintf("\t\texpectedStr := `%s`\n", tc.Expected))
-		
-		// Parse input
-		testCases.WriteString("\t\t// Parse input\n")
-		testCases.WriteString("\t\tparts := strings.Split(inputStr, \", \")\n")
-		testCases.WriteString("\t\tif len(parts) != 2 {\n")
-		testCases.WriteString("\t\t\tfmt.Printf(\"Error: Invalid input format: %s\\n\", inputStr)\n")
-		testCases.WriteString("\t\t\tallPassed = false\n")
-		testCases.WriteString("\t\t} else {\n")
-		testCases.WriteString("\t\t\tnums, err1 := parseIntArray(parts[0])\n")
-		testCases.WriteString("\t\t\ttarget, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))\n")
-		testCases.WriteString("\t\t\tif err1 != nil || err2 != nil {\n")
-		testCases.WriteString("\t\t\t\tfmt.Printf(\"Error parsing input: %v, %v\\n\", err1, err2)\n")
-		testCases.WriteString("\t\t\t\tallPassed = false\n")
-		testCases.WriteString("\t\t\t} else {\n")
-		
-		// Execute solution
-		testCases.WriteString("\t\t\t\t// Execute solution\n")
-		testCases.WriteString("\t\t\t\tresult := twoSum(nums, target)\n")
-		
-		// Check result
-		testCases.WriteString("\t\t\t\t// Check result\n")
-		testCases.WriteString("\t\t\t\tresultStr := formatIntArray(result)\n")
-		testCases.WriteString("\t\t\t\tif resultStr == expectedStr {\n")
-		testCases.WriteString("\t\t\t\t\tfmt.Println(\"✅ PASSED\")\n")
-		testCases.WriteString("\t\t\t\t} else {\n")
-		testCases.WriteString("\t\t\t\t\tfmt.Printf(\"❌ FAILED\\nExpected: %s\\nGot: %s\\n\", expectedStr, resultStr)\n")
-		testCases.WriteString("\t\t\t\t\tallPassed = false\n")
-		testCases.WriteString("\t\t\t\t}\n")
-		testCases.WriteString("\t\t\t}\n")
-		testCases.WriteString("\t\t}\n")
-		testCases.WriteString("\t}\n")
-	}
-	
-	return fmt.Sprintf(testTemplate, solutionCode, testCases.String()), nil
-}
\ No newline at end of file
@@ -0,0 +1,282 @@
+// Support code linked into generated harnesses to time each test, capture
+// its outcome and write the result file described in results.go
+
+package execution
+
+// goHarnessSupport needs "fmt" and "os" imported by the harness; json and
+// time are imported under aliases so they cannot clash with the solution
+var goHarnessSupport = harnessReplacer.Replace(`
+// algoscalesResult is one entry of the result file read by algo-scales
+type algoscalesResult struct {
+	Test       int     ` + "`json:\"test\"`" + `
+	Status     string  ` + "`json:\"status\"`" + `
+	Actual     string  ` + "`json:\"actual,omitempty\"`" + `
+	Error      string  ` + "`json:\"error,omitempty\"`" + `
+	DurationMs float64 ` + "`json:\"durationMs\"`" + `
+}
+
+var algoscalesResults []algoscalesResult
+
+// algoscalesRun times one test, recovers panics and records the outcome
+func algoscalesRun(test int, expected string, fn func() (string, error)) bool {
+	result := algoscalesResult{Test: test}
+	start := algoscalesTime.Now()
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result.Status, result.Error = "__ERROR__", fmt.Sprintf("panic: %v", r)
+			}
+		}()
+		actual, err := fn()
+		switch {
+		case err != nil:
+			result.Status, result.Error = "__ERROR__", err.Error()
+		case actual == expected:
+			result.Status, result.Actual = "__PASS__", actual
+		default:
+			result.Status, result.Actual = "__FAIL__", actual
+		}
+	}()
+	result.DurationMs = float64(algoscalesTime.Since(start).Microseconds()) / 1000
+
+	algoscalesResults = append(algoscalesResults, result)
+	if path := os.Getenv("__RESULTS_ENV__"); path != "" {
+		data, _ := algoscalesJSON.Marshal(map[string]interface{}{"tests": algoscalesResults})
+		os.WriteFile(path, data, 0644)
+	}
+	return result.Status == "__PASS__"
+}
+
+// algoscalesDecode decodes one JSON test argument
+func algoscalesDecode(literal string, v interface{}) error {
+	return algoscalesJSON.Unmarshal([]byte(literal), v)
+}
+
+// algoscalesShow formats a result as compact JSON without string quotes
+func algoscalesShow(v interface{}) string {
+	var b algoscalesStrings.Builder
+	enc := algoscalesJSON.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return algoscalesStrings.ReplaceAll(algoscalesStrings.TrimSpace(b.String()), "\"", "")
+}
+
+// algoscalesShowUnordered formats an array result with its elements sorted,
+// for problems that accept the answer in any order
+func algoscalesShowUnordered(v interface{}) string {
+	data, _ := algoscalesJSON.Marshal(v)
+	var elems []interface{}
+	if err := algoscalesJSON.Unmarshal(data, &elems); err != nil {
+		return algoscalesShow(v)
+	}
+	shown := make([]string, len(elems))
+	for i, elem := range elems {
+		shown[i] = algoscalesShow(elem)
+	}
+	algoscalesSort.Strings(shown)
+	return "[" + algoscalesStrings.Join(shown, ",") + "]"
+}
+
+// algoscalesChar, algoscalesBytes and algoscalesGrid convert one-letter
+// strings to the bytes Go solutions take for characters
+func algoscalesChar(s string) byte {
+	if s == "" {
+		return 0
+	}
+	return s[0]
+}
+
+func algoscalesBytes(s []string) []byte {
+	out := make([]byte, len(s))
+	for i, c := range s {
+		out[i] = algoscalesChar(c)
+	}
+	return out
+}
+
+func algoscalesGrid(g [][]string) [][]byte {
+	out := make([][]byte, len(g))
+	for i, row := range g {
+		out[i] = algoscalesBytes(row)
+	}
+	return out
+}
+`)
+
+// goHarnessImports are the aliased imports goHarnessSupport relies on
+const goHarnessImports = `	algoscalesJSON "encoding/json"
+	algoscalesSort "sort"
+	algoscalesStrings "strings"
+	algoscalesTime "time"`
+
+// pythonHarnessSupport captures stderr per test by swapping sys.stderr
+var pythonHarnessSupport = harnessReplacer.Replace(`
+import io as _algoscales_io
+import json as _algoscales_json
+import os as _algoscales_os
+import sys as _algoscales_sys
+import time as _algoscales_time
+
+_algoscales_results = []
+
+def _algoscales_run(test, expected, fn):
+    """Times one test, captures its stderr and records the outcome"""
+    captured = _algoscales_io.StringIO()
+    saved_stderr = _algoscales_sys.stderr
+    _algoscales_sys.stderr = captured
+    entry = {"test": test}
+    start = _algoscales_time.perf_counter()
+    try:
+        actual = str(fn())
+        entry["status"] = "__PASS__" if actual == expected else "__FAIL__"
+        entry["actual"] = actual
+    except Exception as e:
+        entry["status"] = "__ERROR__"
+        entry["error"] = str(e) or type(e).__name__
+    finally:
+        _algoscales_sys.stderr = saved_stderr
+    entry["durationMs"] = (_algoscales_time.perf_counter() - start) * 1000
+    entry["stderr"] = captured.getvalue()
+
+    _algoscales_results.append(entry)
+    path = _algoscales_os.environ.get("__RESULTS_ENV__")
+    if path:
+        with open(path, "w") as f:
+            _algoscales_json.dump({"tests": _algoscales_results}, f)
+    return entry["status"] == "__PASS__"
+
+def _algoscales_solution(*names):
+    """Finds the solution function, or the method of a Solution class"""
+    for name in names:
+        fn = globals().get(name)
+        if callable(fn):
+            return fn
+    solution = globals().get("Solution")
+    for name in names:
+        if solution is not None and hasattr(solution, name):
+            return getattr(solution(), name)
+    raise NameError("solution function %s is not defined" % names[0])
+
+def _algoscales_show(value):
+    """Formats a result as compact JSON without string quotes"""
+    return _algoscales_json.dumps(value, separators=(",", ":"), ensure_ascii=False).replace('"', "")
+
+def _algoscales_show_unordered(value):
+    """Formats an array result with its elements sorted, for answers in any order"""
+    if not isinstance(value, (list, tuple)):
+        return _algoscales_show(value)
+    return "[" + ",".join(sorted(_algoscales_show(v) for v in value)) + "]"
+
+class _AlgoscalesNode:
+    """Stands in for TreeNode and ListNode when the solution defines neither"""
+    def __init__(self, val=0):
+        self.val = val
+        self.left = self.right = self.next = None
+
+def _algoscales_tree(values):
+    """Builds a tree from level-order values with nulls"""
+    node = globals().get("TreeNode", _AlgoscalesNode)
+    if not values or values[0] is None:
+        return None
+    root = node(values[0])
+    queue = [root]
+    i = 1
+    while queue and i < len(values):
+        current = queue.pop(0)
+        if values[i] is not None:
+            current.left = node(values[i])
+            queue.append(current.left)
+        if i + 1 < len(values) and values[i + 1] is not None:
+            current.right = node(values[i + 1])
+            queue.append(current.right)
+        i += 2
+    return root
+
+def _algoscales_list(values, cycle=-1):
+    """Builds a linked list whose tail links back to the node at index cycle"""
+    node = globals().get("ListNode", _AlgoscalesNode)
+    nodes = [node(v) for v in values]
+    for a, b in zip(nodes, nodes[1:]):
+        a.next = b
+    if nodes and 0 <= cycle < len(nodes):
+        nodes[-1].next = nodes[cycle]
+    return nodes[0] if nodes else None
+`)
+
+// javaScriptHarnessSupport captures stderr per test by wrapping
+// process.stderr.write and console.error
+var javaScriptHarnessSupport = harnessReplacer.Replace(`
+// Times one test, captures its stderr and records the outcome
+const __algoscalesRun = (() => {
+    const fs = require('fs');
+    const results = [];
+    return (test, expected, fn) => {
+        let stderr = '';
+        const savedWrite = process.stderr.write;
+        const savedError = console.error;
+        process.stderr.write = (chunk) => { stderr += String(chunk); return true; };
+        console.error = (...args) => { stderr += args.map(String).join(' ') + '\n'; };
+        const entry = { test };
+        const start = process.hrtime.bigint();
+        try {
+            const actual = String(fn());
+            entry.status = actual === expected ? '__PASS__' : '__FAIL__';
+            entry.actual = actual;
+        } catch (e) {
+            entry.status = '__ERROR__';
+            entry.error = e && e.message ? e.message : String(e);
+        } finally {
+            process.stderr.write = savedWrite;
+            console.error = savedError;
+        }
+        entry.durationMs = Number(process.hrtime.bigint() - start) / 1e6;
+        entry.stderr = stderr;
+
+        results.push(entry);
+        const path = process.env.__RESULTS_ENV__;
+        if (path) {
+            fs.writeFileSync(path, JSON.stringify({ tests: results }));
+        }
+        return entry.status === '__PASS__';
+    };
+})();
+
+// Formats a result as compact JSON without string quotes
+const __algoscalesShow = (value) => String(JSON.stringify(value === undefined ? null : value)).replace(/"/g, '');
+
+// Formats an array result with its elements sorted, for answers in any order
+const __algoscalesShowUnordered = (value) => Array.isArray(value)
+    ? '[' + value.map(__algoscalesShow).sort().join(',') + ']'
+    : __algoscalesShow(value);
+
+// Builds a node with the solution's class when it defines one
+const __algoscalesNode = (name, val) => {
+    const cls = { TreeNode: typeof TreeNode === 'function' ? TreeNode : null, ListNode: typeof ListNode === 'function' ? ListNode : null }[name];
+    const node = cls ? new cls(val) : { val };
+    if (name === 'TreeNode') { node.left = null; node.right = null; } else { node.next = null; }
+    return node;
+};
+
+// Builds a tree from level-order values with nulls
+const __algoscalesTree = (values) => {
+    if (!values.length || values[0] === null) return null;
+    const root = __algoscalesNode('TreeNode', values[0]);
+    const queue = [root];
+    for (let i = 1; queue.length && i < values.length; i += 2) {
+        const node = queue.shift();
+        if (values[i] !== null) queue.push(node.left = __algoscalesNode('TreeNode', values[i]));
+        if (i + 1 < values.length && values[i + 1] !== null) queue.push(node.right = __algoscalesNode('TreeNode', values[i + 1]));
+    }
+    return root;
+};
+
+// Builds a linked list whose tail links back to the node at index cycle
+const __algoscalesList = (values, cycle) => {
+    const nodes = values.map((v) => __algoscalesNode('ListNode', v));
+    nodes.forEach((node, i) => { if (i > 0) nodes[i - 1].next = node; });
+    if (nodes.length && cycle >= 0 && cycle < nodes.length) nodes[nodes.length - 1].next = nodes[cycle];
+    return nodes.length ? nodes[0] : null;
+};
+`)
@@ -0,0 +1,736 @@
+// Signature-driven argument parsing for the generated Java test harness
+
+package execution
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// javaHarnessClass is the public class of the generated file, which
+	// javac therefore requires to be named AlgoScalesTest.java
+	javaHarnessClass = "AlgoScalesTest"
+
+	// javaSolutionStart and javaSolutionEnd delimit the user's code in the
+	// generated file so compiler output can be mapped back to it
+	javaSolutionStart = "// User's solution\n"
+	javaSolutionEnd   = "// End of user's solution\n"
+)
+
+var (
+	// javaMethodPattern matches a method header up to its body: modifiers,
+	// return type, name, parameters and an optional throws clause
+	javaMethodPattern = regexp.MustCompile(`(?m)^[ \t]*((?:(?:public|protected|private|static|final|synchronized)\s+)*)([A-Za-z_][\w<>\[\]?, .]*?)\s+([A-Za-z_]\w*)\s*\(([^)]*)\)\s*(?:throws\s+[\w.,\s]+?)?\s*\{`)
+
+	// javaClassPattern matches a class declaration up to its body
+	javaClassPattern = regexp.MustCompile(`\bclass\s+([A-Za-z_]\w*)[^{;]*\{`)
+
+	// javaImportPattern matches import and package declarations, which must
+	// precede every class in the generated file
+	javaImportPattern = regexp.MustCompile(`(?m)^[ \t]*(import|package)[ \t]+[\w.* \t]+;[ \t]*$`)
+
+	// javaPublicTypePattern matches a public top-level type; only the harness
+	// class may be public in the generated file
+	javaPublicTypePattern = regexp.MustCompile(`(?m)^public\s+((?:(?:final|abstract|sealed|non-sealed|strictfp)\s+)*(?:class|interface|enum|record)\b)`)
+
+	// javacLinePattern matches the location prefix of a javac diagnostic
+	javacLinePattern = regexp.MustCompile(javaHarnessClass + `\.java:(\d+):`)
+)
+
+// javaNotTypes are words the method pattern can mistake for a return type
+var javaNotTypes = map[string]bool{
+	"new": true, "return": true, "else": true, "throw": true, "case": true,
+	"public": true, "protected": true, "private": true, "static": true, "final": true,
+}
+
+// javaScalarTypes are the parameter and return types parsed and shown directly
+var javaScalarTypes = map[string]bool{
+	"int": true, "long": true, "short": true, "byte": true, "double": true, "float": true,
+	"boolean": true, "char": true, "String": true,
+	"Integer": true, "Long": true, "Short": true, "Byte": true, "Double": true, "Float": true,
+	"Boolean": true, "Character": true,
+}
+
+// javaFunction is the solution method the harness calls
+type javaFunction struct {
+	name    string
+	class   string // enclosing class, usually Solution
+	static  bool
+	params  []string // declared parameter types
+	returns string
+}
+
+// findJavaFunction locates the method to test, preferring the one named in
+// the starter code, then the first method of Solution, then the first method
+func findJavaFunction(code, starterCode string) (*javaFunction, error) {
+	preferred := ""
+	for _, m := range javaMethodPattern.FindAllStringSubmatch(starterCode, -1) {
+		if isJavaMethod(m[2], m[3]) && m[3] != "main" {
+			preferred = m[3]
+			break
+		}
+	}
+
+	var chosen, first, inSolution *javaFunction
+	params := map[*javaFunction]string{}
+	for _, loc := range javaMethodPattern.FindAllStringSubmatchIndex(code, -1) {
+		returns, name := code[loc[4]:loc[5]], code[loc[6]:loc[7]]
+		if !isJavaMethod(returns, name) || name == "main" {
+			continue
+		}
+
+		fn := &javaFunction{
+			name:    name,
+			class:   enclosingJavaClass(code, loc[0]),
+			static:  strings.Contains(code[loc[2]:loc[3]], "static"),
+			returns: strings.Join(strings.Fields(returns), ""),
+		}
+		if fn.class == "" {
+			continue
+		}
+		params[fn] = code[loc[8]:loc[9]]
+
+		if name == preferred {
+			chosen = fn
+			break
+		}
+		if first == nil {
+			first = fn
+		}
+		if inSolution == nil && fn.class == "Solution" {
+			inSolution = fn
+		}
+	}
+
+	fn := chosen
+	if fn == nil {
+		fn = inSolution
+	}
+	if fn == nil {
+		fn = first
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("no solution method found")
+	}
+
+	for _, decl := range splitRustList(params[fn]) {
+		typ, err := javaParamType(decl)
+		if err != nil {
+			return nil, err
+		}
+		fn.params = append(fn.params, typ)
+	}
+
+	return fn, nil
+}
+
+// isJavaMethod filters out statements the method pattern also matches,
+// such as "else if (...) {" and constructors
+func isJavaMethod(returns, name string) bool {
+	fields := strings.Fields(returns)
+	if len(fields) == 0 || javaNotTypes[fields[0]] {
+		return false
+	}
+	switch name {
+	case "if", "for", "while", "switch", "catch", "synchronized":
+		return false
+	}
+	return true
+}
+
+// enclosingJavaClass returns the innermost class whose body contains offset
+func enclosingJavaClass(code string, offset int) string {
+	class := ""
+	for _, m := range javaClassPattern.FindAllStringSubmatchIndex(code, -1) {
+		if m[1] > offset {
+			break
+		}
+		body := code[m[1]:offset]
+		if strings.Count(body, "{")-strings.Count(body, "}") >= 0 {
+			class = code[m[2]:m[3]]
+		}
+	}
+	return class
+}
+
+// javaParamType extracts the type of a parameter declaration, dropping
+// annotations and modifiers and normalizing varargs and C-style arrays
+func javaParamType(decl string) (string, error) {
+	var fields []string
+	for _, f := range strings.Fields(decl) {
+		if strings.HasPrefix(f, "@") || f == "final" {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) < 2 {
+		return "", fmt.Errorf("unsupported parameter %q", strings.TrimSpace(decl))
+	}
+
+	name := fields[len(fields)-1]
+	typ := strings.Join(fields[:len(fields)-1], "")
+	for strings.HasSuffix(name, "[]") {
+		name = strings.TrimSuffix(name, "[]")
+		typ += "[]"
+	}
+	if strings.HasSuffix(typ, "...") {
+		typ = strings.TrimSuffix(typ, "...") + "[]"
+	}
+	return typ, nil
+}
+
+// supportedJavaType reports whether the harness can parse and show a type
+func supportedJavaType(typ string) bool {
+	if javaScalarTypes[typ] {
+		return true
+	}
+	if strings.HasSuffix(typ, "[]") {
+		return supportedJavaType(strings.TrimSuffix(typ, "[]"))
+	}
+	for _, wrapper := range []string{"List<", "ArrayList<"} {
+		if strings.HasPrefix(typ, wrapper) && strings.HasSuffix(typ, ">") {
+			return supportedJavaType(typ[len(wrapper) : len(typ)-1])
+		}
+	}
+	return false
+}
+
+// validate checks that every parameter and the return value can be handled
+func (fn *javaFunction) validate() error {
+	for i, typ := range fn.params {
+		if !supportedJavaType(typ) {
+			return fmt.Errorf("parameter %d of %s has unsupported type %s", i+1, fn.name, typ)
+		}
+	}
+	if fn.returns != "void" && !supportedJavaType(fn.returns) {
+		return fmt.Errorf("%s returns unsupported type %s", fn.name, fn.returns)
+	}
+	return nil
+}
+
+// invokeBody renders the body of the harness method that parses the
+// arguments, calls the solution and formats the result. A void method is
+// judged by its first array or list argument, as for in-place algorithms.
+func (fn *javaFunction) invokeBody(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%sAlgoScales.arity(args, %d);\n", indent, len(fn.params)))
+
+	args := make([]string, len(fn.params))
+	inPlace := ""
+	for i, typ := range fn.params {
+		args[i] = fmt.Sprintf("a%d", i)
+		b.WriteString(fmt.Sprintf("%s%s %s = (%s) AlgoScales.parse(args.get(%d), %s);\n", indent, typ, args[i], typ, i, javaString(typ)))
+		if inPlace == "" && (strings.HasSuffix(typ, "[]") || strings.Contains(typ, "List<")) {
+			inPlace = args[i]
+		}
+	}
+
+	call := fmt.Sprintf("%s(%s)", fn.name, strings.Join(args, ", "))
+	if fn.static {
+		call = fn.class + "." + call
+	} else {
+		call = "new " + fn.class + "()." + call
+	}
+
+	switch {
+	case fn.returns != "void":
+		b.WriteString(fmt.Sprintf("%sreturn AlgoScales.show(%s);\n", indent, call))
+	case inPlace != "":
+		b.WriteString(fmt.Sprintf("%s%s;\n", indent, call))
+		b.WriteString(fmt.Sprintf("%sreturn AlgoScales.show(%s);\n", indent, inPlace))
+	default:
+		b.WriteString(fmt.Sprintf("%s%s;\n", indent, call))
+		b.WriteString(fmt.Sprintf("%sreturn \"null\";\n", indent))
+	}
+
+	return b.String()
+}
+
+// prepareJavaSolution splits the solution into the imports that must lead
+// the generated file and the code itself. Imports are blanked rather than
+// removed, and public top-level types lose their modifier, so line numbers
+// in the solution stay the same.
+func prepareJavaSolution(code string) (imports, body string) {
+	var hoisted []string
+	body = javaImportPattern.ReplaceAllStringFunc(code, func(decl string) string {
+		if strings.HasPrefix(strings.TrimSpace(decl), "import") {
+			hoisted = append(hoisted, strings.TrimSpace(decl))
+		}
+		return ""
+	})
+	body = javaPublicTypePattern.ReplaceAllString(body, "$1")
+
+	if len(hoisted) > 0 {
+		imports = strings.Join(hoisted, "\n") + "\n"
+	}
+	return imports, body
+}
+
+// javaSolutionLines returns the range of generated lines holding the
+// solution, as 1-based line numbers
+func javaSolutionLines(testCode string) (first, last int) {
+	start := strings.Index(testCode, javaSolutionStart)
+	end := strings.Index(testCode, javaSolutionEnd)
+	if start < 0 || end < start {
+		return 0, 0
+	}
+	first = strings.Count(testCode[:start], "\n") + 2
+	last = strings.Count(testCode[:end], "\n")
+	return first, last
+}
+
+// mapJavacOutput rewrites diagnostics located in the solution to refer to
+// its own line numbers, so "AlgoScalesTest.java:14:" becomes
+// "Solution.java:3:". Diagnostics in the harness itself are left alone.
+func mapJavacOutput(output, testCode string) string {
+	first, last := javaSolutionLines(testCode)
+	return javacLinePattern.ReplaceAllStringFunc(output, func(loc string) string {
+		line, err := strconv.Atoi(javacLinePattern.FindStringSubmatch(loc)[1])
+		if err != nil || line < first || line > last {
+			return loc
+		}
+		return fmt.Sprintf("Solution.java:%d:", line-first+1)
+	})
+}
+
+// javaString quotes a Go string as a Java string literal; the escapes
+// rustString emits are valid Java as well
+func javaString(s string) string {
+	return rustString(s)
+}
+
+// javaHarnessSupport is the runtime the generated program links in. Inputs
+// are split on top-level commas, an optional "name =" prefix is dropped and
+// each argument is parsed into the declared type at run time. Results are
+// printed in a compact JSON-like form and compared ignoring whitespace and
+// string quotes; outcomes go to the result file, which is rewritten after
+// every test. Fully qualified names keep it independent of the solution's
+// imports.
+var javaHarnessSupport = harnessReplacer.Replace(`
+final class AlgoScales {
+    private AlgoScales() {
+    }
+
+    /** A test body: parses the arguments, calls the solution and shows the result */
+    interface Invocation {
+        String call(java.util.List<String> args) throws Throwable;
+    }
+
+    /** Splits on commas that are not nested in brackets or quotes */
+    static java.util.List<String> splitTop(String s) {
+        java.util.List<String> parts = new java.util.ArrayList<>();
+        StringBuilder cur = new StringBuilder();
+        int depth = 0;
+        char quote = 0;
+        boolean escaped = false;
+        for (char c : s.toCharArray()) {
+            if (quote != 0) {
+                cur.append(c);
+                if (escaped) {
+                    escaped = false;
+                } else if (c == '\\') {
+                    escaped = true;
+                } else if (c == quote) {
+                    quote = 0;
+                }
+                continue;
+            }
+            switch (c) {
+                case '"':
+                case '\'':
+                    quote = c;
+                    cur.append(c);
+                    break;
+                case '[':
+                case '(':
+                case '{':
+                    depth++;
+                    cur.append(c);
+                    break;
+                case ']':
+                case ')':
+                case '}':
+                    depth--;
+                    cur.append(c);
+                    break;
+                case ',':
+                    if (depth == 0) {
+                        parts.add(cur.toString().trim());
+                        cur.setLength(0);
+                    } else {
+                        cur.append(c);
+                    }
+                    break;
+                default:
+                    cur.append(c);
+            }
+        }
+        if (!cur.toString().trim().isEmpty() || !parts.isEmpty()) {
+            parts.add(cur.toString().trim());
+        }
+        return parts;
+    }
+
+    /** Drops a leading "name =" so inputs like "nums = [1,2], k = 3" work */
+    static String stripName(String arg) {
+        int eq = arg.indexOf('=');
+        if (eq > 0) {
+            String name = arg.substring(0, eq).trim();
+            boolean identifier = !name.isEmpty();
+            for (char c : name.toCharArray()) {
+                identifier &= Character.isLetterOrDigit(c) || c == '_';
+            }
+            if (identifier) {
+                return arg.substring(eq + 1).trim();
+            }
+        }
+        return arg;
+    }
+
+    static java.util.List<String> args(String input) {
+        java.util.List<String> args = new java.util.ArrayList<>();
+        for (String part : splitTop(input)) {
+            args.add(stripName(part));
+        }
+        return args;
+    }
+
+    static void arity(java.util.List<String> args, int n) {
+        if (args.size() != n) {
+            throw new IllegalArgumentException("expected " + n + " arguments, got " + args.size());
+        }
+    }
+
+    /** Parses one argument into the declared type */
+    static Object parse(String s, String type) {
+        String t = s.trim();
+        if (type.endsWith("[]")) {
+            String elem = type.substring(0, type.length() - 2);
+            java.util.List<String> items = items(t);
+            Object array = java.lang.reflect.Array.newInstance(classOf(elem), items.size());
+            for (int i = 0; i < items.size(); i++) {
+                java.lang.reflect.Array.set(array, i, parse(items.get(i), elem));
+            }
+            return array;
+        }
+
+        int lt = type.indexOf('<');
+        if (lt >= 0) {
+            String elem = type.substring(lt + 1, type.length() - 1);
+            java.util.List<Object> list = new java.util.ArrayList<>();
+            for (String item : items(t)) {
+                list.add(parse(item, elem));
+            }
+            return list;
+        }
+
+        if (t.equals("null") || t.equals("None")) {
+            if (classOf(type).isPrimitive()) {
+                throw new IllegalArgumentException("invalid " + type + " " + t);
+            }
+            return null;
+        }
+
+        try {
+            switch (type) {
+                case "int":
+                case "Integer":
+                    return Integer.parseInt(t);
+                case "long":
+                case "Long":
+                    return Long.parseLong(t);
+                case "short":
+                case "Short":
+                    return Short.parseShort(t);
+                case "byte":
+                case "Byte":
+                    return Byte.parseByte(t);
+                case "double":
+                case "Double":
+                    return Double.parseDouble(t);
+                case "float":
+                case "Float":
+                    return Float.parseFloat(t);
+                case "boolean":
+                case "Boolean":
+                    if (t.equals("true") || t.equals("True")) {
+                        return true;
+                    }
+                    if (t.equals("false") || t.equals("False")) {
+                        return false;
+                    }
+                    throw new IllegalArgumentException("invalid boolean " + t);
+                case "char":
+                case "Character":
+                    String text = unquote(t);
+                    if (text.length() != 1) {
+                        throw new IllegalArgumentException("invalid char " + t);
+                    }
+                    return text.charAt(0);
+                case "String":
+                    return unquote(t);
+                default:
+                    throw new IllegalArgumentException("unsupported type " + type);
+            }
+        } catch (NumberFormatException e) {
+            throw new IllegalArgumentException("invalid " + type + " " + t);
+        }
+    }
+
+    static Class<?> classOf(String type) {
+        if (type.endsWith("[]")) {
+            Class<?> elem = classOf(type.substring(0, type.length() - 2));
+            return java.lang.reflect.Array.newInstance(elem, 0).getClass();
+        }
+        if (type.indexOf('<') >= 0) {
+            return java.util.List.class;
+        }
+        switch (type) {
+            case "int": return int.class;
+            case "long": return long.class;
+            case "short": return short.class;
+            case "byte": return byte.class;
+            case "double": return double.class;
+            case "float": return float.class;
+            case "boolean": return boolean.class;
+            case "char": return char.class;
+            case "Integer": return Integer.class;
+            case "Long": return Long.class;
+            case "Short": return Short.class;
+            case "Byte": return Byte.class;
+            case "Double": return Double.class;
+            case "Float": return Float.class;
+            case "Boolean": return Boolean.class;
+            case "Character": return Character.class;
+            case "String": return String.class;
+            default: throw new IllegalArgumentException("unsupported type " + type);
+        }
+    }
+
+    /** Splits a [...] or {...} list into its items */
+    static java.util.List<String> items(String t) {
+        boolean brackets = t.startsWith("[") && t.endsWith("]");
+        boolean braces = t.startsWith("{") && t.endsWith("}");
+        if (t.length() < 2 || !(brackets || braces)) {
+            throw new IllegalArgumentException("expected a list, got " + t);
+        }
+        String inner = t.substring(1, t.length() - 1);
+        if (inner.trim().isEmpty()) {
+            return new java.util.ArrayList<>();
+        }
+        return splitTop(inner);
+    }
+
+    static String unquote(String t) {
+        if (t.length() >= 2 && (t.charAt(0) == '"' || t.charAt(0) == '\'') && t.charAt(t.length() - 1) == t.charAt(0)) {
+            return unescape(t.substring(1, t.length() - 1));
+        }
+        return t;
+    }
+
+    static String unescape(String s) {
+        StringBuilder out = new StringBuilder();
+        for (int i = 0; i < s.length(); i++) {
+            char c = s.charAt(i);
+            if (c != '\\' || i + 1 == s.length()) {
+                out.append(c);
+                continue;
+            }
+            char next = s.charAt(++i);
+            if (next == 'n') {
+                out.append('\n');
+            } else if (next == 't') {
+                out.append('\t');
+            } else {
+                out.append(next);
+            }
+        }
+        return out.toString();
+    }
+
+    /** Formats a value compactly, e.g. [0,1] or "abc" */
+    static String show(Object v) {
+        if (v == null) {
+            return "null";
+        }
+        if (v instanceof String || v instanceof Character) {
+            return quote(v.toString());
+        }
+        if (v.getClass().isArray()) {
+            StringBuilder b = new StringBuilder("[");
+            int n = java.lang.reflect.Array.getLength(v);
+            for (int i = 0; i < n; i++) {
+                if (i > 0) {
+                    b.append(',');
+                }
+                b.append(show(java.lang.reflect.Array.get(v, i)));
+            }
+            return b.append(']').toString();
+        }
+        if (v instanceof Iterable) {
+            StringBuilder b = new StringBuilder("[");
+            for (Object item : (Iterable<?>) v) {
+                if (b.length() > 1) {
+                    b.append(',');
+                }
+                b.append(show(item));
+            }
+            return b.append(']').toString();
+        }
+        return v.toString();
+    }
+
+    /** Quotes a string; the result is valid JSON */
+    static String quote(String s) {
+        StringBuilder b = new StringBuilder("\"");
+        for (char c : s.toCharArray()) {
+            switch (c) {
+                case '"':
+                    b.append("\\\"");
+                    break;
+                case '\\':
+                    b.append("\\\\");
+                    break;
+                case '\n':
+                    b.append("\\n");
+                    break;
+                case '\r':
+                    b.append("\\r");
+                    break;
+                case '\t':
+                    b.append("\\t");
+                    break;
+                default:
+                    if (c < 0x20) {
+                        b.append(String.format("\\u%04x", (int) c));
+                    } else {
+                        b.append(c);
+                    }
+            }
+        }
+        return b.append('"').toString();
+    }
+
+    /** Removes whitespace outside string literals */
+    static String normalize(String s) {
+        StringBuilder out = new StringBuilder();
+        boolean inStr = false;
+        boolean escaped = false;
+        for (char c : s.trim().toCharArray()) {
+            if (inStr) {
+                if (escaped) {
+                    escaped = false;
+                } else if (c == '\\') {
+                    escaped = true;
+                } else if (c == '"') {
+                    inStr = false;
+                }
+                out.append(c);
+            } else if (c == '"') {
+                inStr = true;
+                out.append(c);
+            } else if (!Character.isWhitespace(c)) {
+                out.append(c);
+            }
+        }
+        return out.toString();
+    }
+
+    static boolean matches(String got, String expected) {
+        got = normalize(got);
+        expected = normalize(expected);
+        return got.equals(expected) || got.replace("\"", "").equals(expected.replace("\"", "").replace("'", ""));
+    }
+
+    /** Collects outcomes and writes the result file */
+    static final class Report {
+        private final java.util.List<String> entries = new java.util.ArrayList<>();
+        private final int solutionLine;
+        private boolean passed = true;
+
+        /** solutionLine is the generated line holding the solution's first line */
+        Report(int solutionLine) {
+            this.solutionLine = solutionLine;
+        }
+
+        boolean passed() {
+            return passed;
+        }
+
+        /** Runs one test in the manner of assertEquals, timing it and capturing its stderr */
+        void assertEquals(int test, String input, String expected, Invocation invocation) {
+            java.io.PrintStream savedErr = System.err;
+            java.io.ByteArrayOutputStream captured = new java.io.ByteArrayOutputStream();
+            System.setErr(new java.io.PrintStream(captured, true));
+
+            String status;
+            String actual = null;
+            String error = null;
+            long start = System.nanoTime();
+            try {
+                actual = invocation.call(args(input));
+                status = matches(actual, expected) ? "__PASS__" : "__FAIL__";
+            } catch (Throwable e) {
+                status = "__ERROR__";
+                error = describe(e);
+            } finally {
+                System.err.flush();
+                System.setErr(savedErr);
+            }
+            double durationMs = (System.nanoTime() - start) / 1e6;
+
+            record(test, status, actual, error, durationMs, captured.toString());
+        }
+
+        /** Names the exception and, when it was thrown by the solution, the solution line */
+        private String describe(Throwable e) {
+            String message = e.getClass().getSimpleName();
+            if (e.getMessage() != null) {
+                message += ": " + e.getMessage();
+            }
+            for (StackTraceElement frame : e.getStackTrace()) {
+                boolean inSolution = "` + javaHarnessClass + `.java".equals(frame.getFileName())
+                    && !frame.getClassName().startsWith("AlgoScales")
+                    && frame.getLineNumber() >= solutionLine;
+                if (inSolution) {
+                    return message + " (Solution.java:" + (frame.getLineNumber() - solutionLine + 1) + ")";
+                }
+            }
+            return message;
+        }
+
+        private void record(int test, String status, String actual, String error, double durationMs, String stderr) {
+            passed &= status.equals("__PASS__");
+
+            StringBuilder entry = new StringBuilder();
+            entry.append("{\"test\":").append(test).append(",\"status\":").append(quote(status));
+            if (actual != null) {
+                entry.append(",\"actual\":").append(quote(actual));
+            }
+            if (error != null) {
+                entry.append(",\"error\":").append(quote(error));
+            }
+            entry.append(",\"durationMs\":").append(String.format(java.util.Locale.ROOT, "%.3f", durationMs));
+            if (!stderr.isEmpty()) {
+                entry.append(",\"stderr\":").append(quote(stderr));
+            }
+            entries.add(entry.append('}').toString());
+
+            String path = System.getenv("__RESULTS_ENV__");
+            if (path == null || path.isEmpty()) {
+                return;
+            }
+            String json = "{\"tests\":[" + String.join(",", entries) + "]}";
+            try (java.io.Writer w = new java.io.OutputStreamWriter(new java.io.FileOutputStream(path), java.nio.charset.StandardCharsets.UTF_8)) {
+                w.write(json);
+            } catch (java.io.IOException ignored) {
+                // The runner reports tests missing from the file
+            }
+        }
+    }
+}
+`)
@@ -0,0 +1,172 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/logging"
+)
+
+// JavaTestRunner implements the TestRunner interface for Java code
+type JavaTestRunner struct {
+	BaseTestRunner
+}
+
+// NewJavaTestRunner creates a new Java test runner
+func NewJavaTestRunner() *JavaTestRunner {
+	return &JavaTestRunner{
+		BaseTestRunner: NewBaseTestRunner("java"),
+	}
+}
+
+// ExecuteTests compiles a Java solution with javac and runs its tests
+func (r *JavaTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Problem, code string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+	// Create a context with timeout for the entire operation
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Add logging context
+	ctx = logging.WithOperation(ctx, "ExecuteJavaTests")
+	ctx = logging.WithComponent(ctx, "JavaTestRunner")
+	logger := logging.TestRunnerLogger.WithContext(ctx)
+
+	// Create session snapshot for error logging
+	sessionState := &logging.SessionSnapshot{
+		ProblemID:  prob.ID,
+		Language:   "java",
+		Mode:       "test_execution",
+		UserCode:   code,
+		StartTime:  time.Now(),
+		Patterns:   prob.Tags,
+		Difficulty: prob.Difficulty,
+		CustomFields: map[string]string{
+			"timeout":    timeout.String(),
+			"test_count": fmt.Sprintf("%d", len(prob.TestCases)),
+		},
+	}
+
+	// Log operation start
+	finishLog := logger.StartOperation(fmt.Sprintf("Execute Java tests for problem %s", prob.ID))
+	defer func() {
+		if r := recover(); r != nil {
+			if logging.GlobalErrorLogger != nil {
+				logging.GlobalErrorLogger.LogPanic(ctx, r, "execute_java_tests", sessionState)
+			}
+			finishLog(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+	}()
+
+	// Create a temporary directory for the source and classes
+	testDir, err := os.MkdirTemp("", "algo-scales-java-test")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(testDir) // Clean up when done
+
+	// Generate test code
+	testCode, err := r.GenerateTestCode(prob, code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate test code: %v", err)
+	}
+
+	// javac requires the file to be named after its public class
+	testFile := filepath.Join(testDir, javaHarnessClass+".java")
+	if err := os.WriteFile(testFile, []byte(testCode), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write test file: %v", err)
+	}
+
+	classesDir := filepath.Join(testDir, "classes")
+	if err := os.MkdirAll(classesDir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create classes directory: %v", err)
+	}
+
+	resultsFile := filepath.Join(testDir, resultsFileName)
+
+	// Compile first so compiler errors can be mapped back to the solution
+	compile := exec.CommandContext(ctx, "javac", "-encoding", "UTF-8", "-nowarn", "-d", classesDir, javaHarnessClass+".java")
+	compile.Dir = testDir
+	stdout, stderr, err := runCommandWithTimeout(compile, timeout)
+	if err != nil {
+		// javac reports diagnostics on stderr, some versions on stdout
+		output := strings.TrimSpace(stderr.String() + "\n" + stdout.String())
+		results := readResults(resultsFile, prob.TestCases, err, mapJavacOutput(output, testCode))
+		return withFailure(results, interfaces.FailureCompile), false, nil
+	}
+
+	// Run the compiled harness in the sandbox and read the results it wrote;
+	// stderr explains the tests that never ran, e.g. because the class could
+	// not be loaded
+	cmd := ActiveSandbox.command(ctx, "java", testDir, len(prob.TestCases), "java", "-cp", classesDir, javaHarnessClass)
+	results, _ := runHarness(cmd, timeout, resultsFile, prob.TestCases)
+
+	return results, allTestsPassed(results), nil
+}
+
+// GenerateTestCode creates a Java program that runs the solution against
+// every test case, one test method per case. Arguments are parsed according
+// to the solution's method signature; see java_harness.go for the supported
+// parameter types.
+func (r *JavaTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
+	imports, body := prepareJavaSolution(solutionCode)
+
+	// LeetCode-style solutions use java.util without importing it
+	var b strings.Builder
+	b.WriteString("import java.util.*;\n")
+	b.WriteString(imports)
+	b.WriteString("\n")
+	b.WriteString(javaSolutionStart)
+	solutionLine := strings.Count(b.String(), "\n") + 1
+	b.WriteString(body)
+	if !strings.HasSuffix(body, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(javaSolutionEnd)
+
+	// The invocation shared by every test; an unsupported signature is
+	// reported once per test instead of failing to run at all
+	var invoke string
+	fn, err := findJavaFunction(body, prob.StarterCode["java"])
+	if err == nil {
+		err = fn.validate()
+	}
+	if err != nil {
+		invoke = fmt.Sprintf("        throw new IllegalArgumentException(%s);\n", javaString(err.Error()))
+	} else {
+		invoke = fn.invokeBody("        ")
+	}
+
+	b.WriteString(fmt.Sprintf("\npublic class %s {\n", javaHarnessClass))
+	b.WriteString("    @SuppressWarnings(\"unchecked\")\n")
+	b.WriteString("    static String invoke(java.util.List<String> args) throws Throwable {\n")
+	b.WriteString(invoke)
+	b.WriteString("    }\n")
+
+	for i, tc := range prob.TestCases {
+		b.WriteString(fmt.Sprintf("\n    // Test case %d\n", i+1))
+		b.WriteString(fmt.Sprintf("    static void test%d(AlgoScales.Report report) {\n", i+1))
+		b.WriteString(fmt.Sprintf("        report.assertEquals(%d, %s, %s, %s::invoke);\n",
+			i+1, javaString(tc.Input), javaString(tc.Expected), javaHarnessClass))
+		b.WriteString("    }\n")
+	}
+
+	b.WriteString("\n    public static void main(String[] argv) {\n")
+	b.WriteString(fmt.Sprintf("        AlgoScales.Report report = new AlgoScales.Report(%d);\n", solutionLine))
+	for i := range prob.TestCases {
+		b.WriteString(fmt.Sprintf("        test%d(report);\n", i+1))
+	}
+	b.WriteString("        if (!report.passed()) {\n")
+	b.WriteString("            System.exit(1);\n")
+	b.WriteString("        }\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	b.WriteString(javaHarnessSupport)
+
+	return b.String(), nil
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"strconv"
 )
 
 // JavaScriptTestRunner implements the TestRunner interface for JavaScript code
@@ -50,31 +50,22 @@ func (r *JavaScriptTestRunner) ExecuteTests(ctx context.Context, prob *interface
 		return nil, false, fmt.Errorf("failed to write test file: %v", err)
 	}
 	
-	// Run the test
-	cmd := exec.CommandContext(ctx, "node", testFile)
-	
-	// Run the command with timeout
-	stdout, stderr, err := runCommandWithTimeout(cmd, timeout)
-	
-	// Parse the results from stdout
-	output := stdout.String()
-	results := parseTestOutput(output, prob.TestCases)
-	
-	// If there were errors, include them in the results
-	if err != nil && len(stderr.String()) > 0 {
-		results = addErrorToResults(results, stderr.String())
-	}
+	// Run the test in the sandbox and read the results the harness wrote
+	cmd := ActiveSandbox.command(ctx, "javascript", testDir, len(prob.TestCases), "node", testFile)
+	results, _ := runHarness(cmd, timeout, filepath.Join(testDir, resultsFileName), prob.TestCases)
 	
 	return results, allTestsPassed(results), nil
 }
 
-// GenerateTestCode creates JavaScript test code for a given problem
+// GenerateTestCode creates JavaScript test code for a given problem. Each
+// test input is parsed into arguments according to the problem's function
+// signature.
 func (r *JavaScriptTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
 	// Create the test file content template
 	testTemplate := `
 // User's solution
 %s
-
+%s
 // Test cases
 function runTests() {
     let allPassed = true;
@@ -91,41 +82,26 @@ if (!success) {
 }
 `
 	
+	sigErr := ValidateSignature(prob.Signature)
+	
 	// Generate test code for each test case
 	var testCases strings.Builder
 	for i, tc := range prob.TestCases {
-		// Use string fields directly
-		inputStr := tc.Input
-		expectedStr := tc.Expected
-		
-		testCases.WriteString(fmt.Sprintf("\n    // Test case %d\n", i+1))
-		testCases.WriteString(fmt.Sprintf("    console.log(\"Test %d\");\n", i+1))
-		testCases.WriteString(fmt.Sprintf("    const inputStr = '%s';\n", inputStr))
-		testCases.WriteString(fmt.Sprintf("    const expectedStr = '%s';\n", expectedStr))
-		
-		// Parse input (very simplified - would need to be customized)
-		testCases.WriteString("    // Parse input (simplified)\n")
-		testCases.WriteString("    // This would need to be customized based on the problem\n")
-		testCases.WriteString("    try {\n")
-		testCases.WriteString("        // Simplified parsing logic - would need to be customized\n")
-		testCases.WriteString("        // For example, parsing \"[1,2,3], 5\" for a twoSum problem\n")
-		testCases.WriteString("        // const result = twoSum(parsedArray, parsedTarget);\n")
-		testCases.WriteString("        const result = \"PLACEHOLDER\";\n")
+		testCases.WriteString(fmt.Sprintf("\n    // Test case %d: %s\n", i+1, strings.ReplaceAll(tc.Input, "\n", " ")))
+		testCases.WriteString(fmt.Sprintf("    if (!__algoscalesRun(%d, %s, () => {\n", i+1, strconv.Quote(expectedValue(prob.Signature, tc.Expected))))
 		
-		// Check result
-		testCases.WriteString("        // Check result\n")
-		testCases.WriteString("        if (String(result) === expectedStr) {\n")
-		testCases.WriteString("            console.log(\"✅ PASSED\");\n")
-		testCases.WriteString("        } else {\n")
-		testCases.WriteString("            console.log(`❌ FAILED\\nExpected: ${expectedStr}\\nGot: ${result}`);\n")
-		testCases.WriteString("            allPassed = false;\n")
-		testCases.WriteString("        }\n")
-		testCases.WriteString("    } catch (e) {\n")
-		testCases.WriteString("        console.log(`❌ ERROR: ${e.message}`);\n")
+		// Tests the harness cannot call still report an error each
+		args, err := testArgs(prob.Signature, sigErr, tc.Input)
+		if err != nil {
+			testCases.WriteString(fmt.Sprintf("        throw new Error(%s);\n", strconv.Quote(err.Error())))
+		} else {
+			testCases.WriteString(javaScriptCallBody(prob.Signature, args, "        "))
+		}
+		testCases.WriteString("    })) {\n")
 		testCases.WriteString("        allPassed = false;\n")
 		testCases.WriteString("    }\n")
 	}
 	
 	// Complete the test code
-	return fmt.Sprintf(testTemplate, solutionCode, testCases.String()), nil
+	return fmt.Sprintf(testTemplate, solutionCode, javaScriptHarnessSupport, testCases.String()), nil
 }
\ No newline at end of file
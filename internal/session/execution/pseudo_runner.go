@@ -0,0 +1,107 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// PseudoLanguage is whiteboard-style pseudo-code. It is never executed:
+// the AI grades it against the problem's reference approach instead, so it
+// can be practiced without any toolchain installed.
+const PseudoLanguage = "pseudo"
+
+// minGradeTimeout is the least time given to grading, which waits on the
+// AI rather than on the solution
+const minGradeTimeout = 2 * time.Minute
+
+// PseudoTestRunner implements the TestRunner interface for pseudo-code
+type PseudoTestRunner struct {
+	BaseTestRunner
+}
+
+// NewPseudoTestRunner creates a new pseudo-code test runner
+func NewPseudoTestRunner() *PseudoTestRunner {
+	return &PseudoTestRunner{
+		BaseTestRunner: NewBaseTestRunner(PseudoLanguage),
+	}
+}
+
+// ExecuteTests grades pseudo-code with the AI, reporting a result for each
+// criterion of the rubric. Every run is graded the same way, as pseudo-code
+// cannot be checked against individual test cases.
+func (r *PseudoTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Problem, code string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+	if timeout < minGradeTimeout {
+		timeout = minGradeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	grade, err := gradePseudoCode(ctx, referenceProblem(prob), code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to grade pseudo-code: %v", err)
+	}
+
+	results := make([]interfaces.TestResult, 0, len(grade.Scores))
+	for i, score := range grade.Scores {
+		result := interfaces.TestResult{
+			Input:    fmt.Sprintf("%s: %s", score.Criterion, ai.Rubric[i].Description),
+			Expected: rubricScore(ai.MaxRubricScore),
+			Actual:   rubricScore(score.Score),
+			Passed:   score.Met(),
+			Tier:     interfaces.TierExample,
+		}
+		if score.Comment != "" {
+			result.Actual += " - " + score.Comment
+		}
+		if !result.Passed {
+			result.Failure = interfaces.FailureWrongAnswer
+		}
+		results = append(results, result)
+	}
+	// The overall feedback goes with the first criterion, correctness
+	if len(results) > 0 {
+		results[0].Stderr = grade.Feedback
+	}
+
+	return results, grade.Passed(), nil
+}
+
+// GenerateTestCode reports that pseudo-code has no test harness
+func (r *PseudoTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
+	return "", fmt.Errorf("pseudo-code is graded by the AI, not run, so it has no test harness")
+}
+
+// rubricScore formats a score out of the rubric's maximum
+func rubricScore(score int) string {
+	return strconv.Itoa(score) + "/" + strconv.Itoa(ai.MaxRubricScore)
+}
+
+// referenceProblem returns the full problem, with its reference solutions,
+// falling back to what the runner was given when it cannot be loaded
+func referenceProblem(prob *interfaces.Problem) problem.Problem {
+	if full, err := problem.GetByID(prob.ID); err == nil {
+		return *full
+	}
+	return problem.Problem{
+		ID:          prob.ID,
+		Title:       prob.Title,
+		Description: prob.Description,
+		Difficulty:  prob.Difficulty,
+	}
+}
+
+// gradePseudoCode grades pseudo-code with the default AI agent
+// Exported as variable for testing
+var gradePseudoCode = func(ctx context.Context, prob problem.Problem, code string) (*ai.Grade, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return nil, fmt.Errorf("pseudo-code needs an AI provider, run 'algo-scales ai config': %v", err)
+	}
+	return ai.GradePseudoCode(ctx, agent, prob, code)
+}
@@ -0,0 +1,77 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGrader grades every submission with grade, recording the problem it
+// was graded against
+func stubGrader(t *testing.T, grade *ai.Grade, err error) *problem.Problem {
+	t.Helper()
+	var graded problem.Problem
+	original := gradePseudoCode
+	t.Cleanup(func() { gradePseudoCode = original })
+	gradePseudoCode = func(ctx context.Context, prob problem.Problem, code string) (*ai.Grade, error) {
+		graded = prob
+		return grade, err
+	}
+	return &graded
+}
+
+func TestPseudoTestRunner(t *testing.T) {
+	originalGetByID := problem.GetByID
+	t.Cleanup(func() { problem.GetByID = originalGetByID })
+	problem.GetByID = func(id string) (*problem.Problem, error) {
+		return &problem.Problem{ID: id, Title: "Two Sum", Solutions: map[string]string{"python": "def two_sum(): pass"}}, nil
+	}
+
+	runner, err := DefaultRegistry.GetRunner(PseudoLanguage)
+	require.NoError(t, err)
+	prob := &interfaces.Problem{ID: "two_sum", TestCases: []interfaces.TestCase{{Input: "[2,7]", Expected: "[0,1]"}}}
+
+	t.Run("Graded", func(t *testing.T) {
+		graded := stubGrader(t, &ai.Grade{
+			Scores: []ai.RubricScore{
+				{Criterion: "Correctness", Score: 2, Comment: "right"},
+				{Criterion: "Approach", Score: 2},
+				{Criterion: "Complexity", Score: 0, Comment: "not stated"},
+				{Criterion: "Edge cases", Score: 1},
+			},
+			Feedback: "State the complexity.",
+		}, nil)
+
+		results, allPassed, err := runner.ExecuteTests(context.Background(), prob, "for each num...", time.Second)
+		require.NoError(t, err)
+		assert.False(t, allPassed)
+		assert.Equal(t, "def two_sum(): pass", graded.Solutions["python"], "graded against the full problem")
+
+		require.Len(t, results, len(ai.Rubric))
+		assert.True(t, results[0].Passed)
+		assert.Equal(t, "2/2", results[0].Expected)
+		assert.Equal(t, "2/2 - right", results[0].Actual)
+		assert.Equal(t, "State the complexity.", results[0].Stderr)
+		assert.False(t, results[2].Passed)
+		assert.Equal(t, interfaces.FailureWrongAnswer, results[2].Failure)
+		assert.Contains(t, results[2].Input, "Complexity")
+		assert.True(t, results[3].Passed, "partly covering edge cases is enough")
+	})
+
+	t.Run("NoProvider", func(t *testing.T) {
+		stubGrader(t, nil, errors.New("no provider"))
+
+		_, _, err := runner.ExecuteTests(context.Background(), prob, "for each num...", time.Second)
+		assert.ErrorContains(t, err, "failed to grade pseudo-code")
+	})
+
+	_, err = runner.GenerateTestCode(prob, "")
+	assert.Error(t, err)
+}
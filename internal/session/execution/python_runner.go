@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/common/logging"
+	"strconv"
 )
 
 // PythonTestRunner implements the TestRunner interface for Python code
@@ -83,31 +83,22 @@ func (r *PythonTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Pr
 		return nil, false, fmt.Errorf("failed to write test file: %v", err)
 	}
 	
-	// Run the test
-	cmd := exec.CommandContext(ctx, "python", testFile)
-	
-	// Run the command with timeout
-	stdout, stderr, err := runCommandWithTimeout(cmd, timeout)
-	
-	// Parse the results from stdout
-	output := stdout.String()
-	results := parseTestOutput(output, prob.TestCases)
-	
-	// If there were errors, include them in the results
-	if err != nil && len(stderr.String()) > 0 {
-		results = addErrorToResults(results, stderr.String())
-	}
+	// Run the test in the sandbox and read the results the harness wrote
+	cmd := ActiveSandbox.command(ctx, "python", testDir, len(prob.TestCases), "python", testFile)
+	results, _ := runHarness(cmd, timeout, filepath.Join(testDir, resultsFileName), prob.TestCases)
 	
 	return results, allTestsPassed(results), nil
 }
 
-// GenerateTestCode creates Python test code for a given problem
+// GenerateTestCode creates Python test code for a given problem. Each test
+// input is parsed into arguments according to the problem's function
+// signature.
 func (r *PythonTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
 	// Create the test file content template
 	testTemplate := `
 # User's solution
 %s
-
+%s
 # Test cases
 def main():
     all_passed = True
@@ -122,39 +113,31 @@ if __name__ == "__main__":
         exit(1)
 `
 	
+	sigErr := ValidateSignature(prob.Signature)
+	name := ""
+	if sigErr == nil {
+		name = pythonFunctionName(prob.Signature, prob.StarterCode["python"])
+	}
+	
 	// Generate test code for each test case
 	var testCases strings.Builder
 	for i, tc := range prob.TestCases {
-		// Use string fields directly
-		inputStr := tc.Input
-		expectedStr := tc.Expected
+		testCases.WriteString(fmt.Sprintf("\n    # Test case %d: %s\n", i+1, strings.ReplaceAll(tc.Input, "\n", " ")))
+		testCases.WriteString(fmt.Sprintf("    def test_%d():\n", i+1))
 		
-		testCases.WriteString(fmt.Sprintf("\n    # Test case %d\n", i+1))
-		testCases.WriteString(fmt.Sprintf("    print(\"Test %d\")\n", i+1))
-		testCases.WriteString(fmt.Sprintf("    input_str = '%s'\n", inputStr))
-		testCases.WriteString(fmt.Sprintf("    expected_str = '%s'\n", expectedStr))
-		
-		// Parse input (very simplified - would need to be customized)
-		testCases.WriteString("    # Parse input (simplified)\n")
-		testCases.WriteString("    # This would need to be customized based on the problem\n")
-		testCases.WriteString("    try:\n")
-		testCases.WriteString("        # Simplified parsing logic - would need to be customized\n")
-		testCases.WriteString("        # For example, parsing \"[1,2,3], 5\" for a two_sum problem\n")
-		testCases.WriteString("        # result = two_sum(parsed_array, parsed_target)\n")
-		testCases.WriteString("        result = \"PLACEHOLDER\"\n")
+		// Tests the harness cannot call still report an error each
+		args, err := testArgs(prob.Signature, sigErr, tc.Input)
+		if err != nil {
+			testCases.WriteString(fmt.Sprintf("        raise ValueError(%s)\n", strconv.Quote(err.Error())))
+		} else {
+			testCases.WriteString(pythonCallBody(prob.Signature, name, args, "        "))
+		}
 		
-		// Check result
-		testCases.WriteString("        # Check result\n")
-		testCases.WriteString("        if str(result) == expected_str:\n")
-		testCases.WriteString("            print(\"✅ PASSED\")\n")
-		testCases.WriteString("        else:\n")
-		testCases.WriteString("            print(f\"❌ FAILED\\nExpected: {expected_str}\\nGot: {result}\")\n")
-		testCases.WriteString("            all_passed = False\n")
-		testCases.WriteString("    except Exception as e:\n")
-		testCases.WriteString("        print(f\"❌ ERROR: {e}\")\n")
+		// Run and record the result
+		testCases.WriteString(fmt.Sprintf("    if not _algoscales_run(%d, %s, test_%d):\n", i+1, strconv.Quote(expectedValue(prob.Signature, tc.Expected)), i+1))
 		testCases.WriteString("        all_passed = False\n")
 	}
 	
 	// Complete the test code
-	return fmt.Sprintf(testTemplate, solutionCode, testCases.String()), nil
+	return fmt.Sprintf(testTemplate, solutionCode, pythonHarnessSupport, testCases.String()), nil
 }
\ No newline at end of file
@@ -9,6 +9,12 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 )
 
+// languageAliases maps alternative language names to registered ones
+var languageAliases = map[string]string{
+	"c++": "cpp",
+	"ts":  "typescript",
+}
+
 // RunnerRegistry implements the TestRunnerRegistry interface
 type RunnerRegistry struct {
 	runners map[string]interfaces.TestRunner
@@ -25,6 +31,11 @@ func NewRunnerRegistry() *RunnerRegistry {
 	registry.RegisterRunner(NewGoTestRunner())
 	registry.RegisterRunner(NewPythonTestRunner())
 	registry.RegisterRunner(NewJavaScriptTestRunner())
+	registry.RegisterRunner(NewRustTestRunner())
+	registry.RegisterRunner(NewJavaTestRunner())
+	registry.RegisterRunner(NewCppTestRunner())
+	registry.RegisterRunner(NewTypeScriptTestRunner())
+	registry.RegisterRunner(NewPseudoTestRunner())
 	
 	return registry
 }
@@ -34,6 +45,10 @@ func (r *RunnerRegistry) GetRunner(language string) (interfaces.TestRunner, erro
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
+	if alias, ok := languageAliases[language]; ok {
+		language = alias
+	}
+	
 	if runner, ok := r.runners[language]; ok {
 		return runner, nil
 	}
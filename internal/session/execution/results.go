@@ -0,0 +1,139 @@
+// Result file protocol between generated test harnesses and the runners
+
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// ResultsEnv names the environment variable holding the path of the result
+// file. Harnesses rewrite the whole file after every test, so results of the
+// tests that finished survive a crash or timeout. Stdout is never parsed and
+// the solution may print whatever it likes.
+const ResultsEnv = "ALGOSCALES_RESULTS"
+
+// resultsFileName is the result file written inside the test directory
+const resultsFileName = "results.json"
+
+// Test statuses reported by harnesses
+const (
+	StatusPass  = "PASS"
+	StatusFail  = "FAIL"
+	StatusError = "ERROR"
+)
+
+// HarnessReport is the content of the result file
+type HarnessReport struct {
+	Tests []HarnessResult `json:"tests"`
+}
+
+// HarnessResult is the outcome of one test case as reported by a harness
+type HarnessResult struct {
+	Test       int     `json:"test"`   // 1-based test number
+	Status     string  `json:"status"` // StatusPass, StatusFail or StatusError
+	Actual     string  `json:"actual,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+	Stderr     string  `json:"stderr,omitempty"`
+}
+
+// harnessReplacer fills protocol constants into the harness support code
+var harnessReplacer = strings.NewReplacer(
+	"__RESULTS_ENV__", ResultsEnv,
+	"__PASS__", StatusPass,
+	"__FAIL__", StatusFail,
+	"__ERROR__", StatusError,
+)
+
+// withResultsFile points the command's harness at a result file
+func withResultsFile(cmd *exec.Cmd, path string) {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, ResultsEnv+"="+path)
+}
+
+// readResults builds test results from the result file. Tests missing from
+// the file are reported with the run failure, which is the compiler output
+// when the program never started.
+func readResults(path string, testCases []interfaces.TestCase, runErr error, stderr string) []interfaces.TestResult {
+	results := make([]interfaces.TestResult, len(testCases))
+	reported := make([]bool, len(testCases))
+
+	for i, tc := range testCases {
+		results[i] = interfaces.TestResult{
+			Input:    tc.Input,
+			Expected: tc.Expected,
+			Actual:   "No output captured",
+			Passed:   false,
+			Failure:  interfaces.FailureRuntime,
+			Tier:     tc.Tier.OrDefault(),
+		}
+	}
+
+	var report HarnessReport
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &report); err != nil {
+			stderr = fmt.Sprintf("invalid result file: %v\n%s", err, stderr)
+		}
+	}
+
+	for _, r := range report.Tests {
+		if r.Test <= 0 || r.Test > len(results) {
+			continue
+		}
+		result := &results[r.Test-1]
+		reported[r.Test-1] = true
+
+		result.Passed = r.Status == StatusPass
+		result.Duration = time.Duration(r.DurationMs * float64(time.Millisecond))
+		result.Stderr = r.Stderr
+
+		switch r.Status {
+		case StatusPass:
+			result.Failure = ""
+			result.Actual = result.Expected
+			if r.Actual != "" {
+				result.Actual = r.Actual
+			}
+		case StatusFail:
+			result.Failure = interfaces.FailureWrongAnswer
+			result.Actual = r.Actual
+		default:
+			result.Actual = "Error: " + r.Error
+		}
+	}
+
+	// Explain the tests the harness never got to
+	failure := strings.TrimSpace(stderr)
+	if failure == "" && runErr != nil {
+		failure = runErr.Error()
+	}
+	if failure != "" {
+		for i := range results {
+			if !reported[i] {
+				results[i].Actual = "Error: " + failure
+			}
+		}
+	}
+
+	return results
+}
+
+// withFailure sets the failure kind of every test that did not pass, e.g.
+// when the solution never got past a build step
+func withFailure(results []interfaces.TestResult, kind interfaces.FailureKind) []interfaces.TestResult {
+	for i := range results {
+		if !results[i].Passed {
+			results[i].Failure = kind
+		}
+	}
+	return results
+}
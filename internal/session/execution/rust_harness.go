@@ -0,0 +1,575 @@
+// Signature-driven argument parsing for the generated Rust test harness
+
+package execution
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// rustFnPattern matches a function header up to its body:
+	// visibility, name, generics, parameters and return type
+	rustFnPattern = regexp.MustCompile(`(?m)^[ \t]*(pub(?:\([^)]*\))?[ \t]+)?fn[ \t]+([A-Za-z_]\w*)[ \t]*(?:<[^>{(]*>)?[ \t]*\(([^)]*)\)\s*(?:->\s*([^{;]+?))?\s*(?:where[^{]*)?\{`)
+
+	// rustMainPattern matches a main function in the solution
+	rustMainPattern = regexp.MustCompile(`(?m)^([ \t]*(?:pub[ \t]+)?)fn[ \t]+main[ \t]*\(`)
+
+	// rustImplPattern matches the LeetCode-style impl block
+	rustImplPattern = regexp.MustCompile(`impl\s+Solution\s*\{`)
+
+	// rustSolutionStructPattern matches a declaration of the Solution type
+	rustSolutionStructPattern = regexp.MustCompile(`struct\s+Solution\b`)
+
+	// rustRefPattern splits a reference type into its mutability and target
+	rustRefPattern = regexp.MustCompile(`^&\s*(?:'\w+\s+)?(mut\s+)?(.+)$`)
+)
+
+// rustScalarTypes are the parameter and return types parsed and shown directly
+var rustScalarTypes = map[string]bool{
+	"i8": true, "i16": true, "i32": true, "i64": true, "i128": true, "isize": true,
+	"u8": true, "u16": true, "u32": true, "u64": true, "u128": true, "usize": true,
+	"f32": true, "f64": true, "bool": true, "char": true, "String": true,
+}
+
+// rustParam is a solution parameter and how the harness passes it
+type rustParam struct {
+	typ string // owned type parsed from the test input
+	ref bool   // passed as &value
+	mut bool   // passed as &mut value
+}
+
+// rustFunction is the solution function the harness calls
+type rustFunction struct {
+	name    string
+	params  []rustParam
+	returns string
+	method  bool // defined in impl Solution and called as Solution::name
+}
+
+// findRustFunction locates the function to test, preferring the one named in
+// the starter code, then the first pub function, then the first function
+func findRustFunction(code, starterCode string) (*rustFunction, error) {
+	preferred := ""
+	for _, m := range rustFnPattern.FindAllStringSubmatch(starterCode, -1) {
+		if m[2] != "main" {
+			preferred = m[2]
+			break
+		}
+	}
+
+	var chosen, first, firstPub []int
+	for _, loc := range rustFnPattern.FindAllStringSubmatchIndex(code, -1) {
+		name := code[loc[4]:loc[5]]
+		params := code[loc[6]:loc[7]]
+		if name == "_solution_main" || strings.Contains(params, "self") {
+			continue
+		}
+
+		if name == preferred {
+			chosen = loc
+			break
+		}
+		if first == nil {
+			first = loc
+		}
+		if firstPub == nil && loc[2] >= 0 {
+			firstPub = loc
+		}
+	}
+
+	loc := chosen
+	if loc == nil {
+		loc = firstPub
+	}
+	if loc == nil {
+		loc = first
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("no solution function found")
+	}
+
+	fn := &rustFunction{
+		name:   code[loc[4]:loc[5]],
+		method: insideRustImpl(code, loc[0]),
+	}
+	if loc[8] >= 0 {
+		fn.returns = strings.TrimSpace(code[loc[8]:loc[9]])
+	}
+
+	for _, decl := range splitRustList(code[loc[6]:loc[7]]) {
+		colon := strings.Index(decl, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("unsupported parameter %q", decl)
+		}
+		fn.params = append(fn.params, newRustParam(strings.TrimSpace(decl[colon+1:])))
+	}
+
+	return fn, nil
+}
+
+// insideRustImpl reports whether the offset lies within impl Solution { ... }
+func insideRustImpl(code string, offset int) bool {
+	loc := rustImplPattern.FindStringIndex(code)
+	if loc == nil || loc[1] > offset {
+		return false
+	}
+	depth := 1 + strings.Count(code[loc[1]:offset], "{") - strings.Count(code[loc[1]:offset], "}")
+	return depth > 0
+}
+
+// newRustParam maps a declared parameter type to the value the harness builds
+func newRustParam(declared string) rustParam {
+	p := rustParam{}
+	if m := rustRefPattern.FindStringSubmatch(declared); m != nil {
+		p.ref = true
+		p.mut = m[1] != ""
+		declared = m[2]
+	}
+
+	// Borrowed slices and strs are parsed into their owned forms
+	typ := strings.Join(strings.Fields(declared), "")
+	switch {
+	case typ == "str":
+		typ = "String"
+	case strings.HasPrefix(typ, "[") && strings.HasSuffix(typ, "]") && !strings.Contains(typ, ";"):
+		typ = "Vec<" + typ[1:len(typ)-1] + ">"
+	}
+	p.typ = typ
+
+	return p
+}
+
+// splitRustList splits a parameter list on commas outside brackets
+func splitRustList(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '<', '(', '[':
+			depth++
+		case '>', ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, list[start:])
+
+	var trimmed []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			trimmed = append(trimmed, part)
+		}
+	}
+	return trimmed
+}
+
+// supportedRustType reports whether the harness can parse and show a type
+func supportedRustType(typ string) bool {
+	if rustScalarTypes[typ] {
+		return true
+	}
+	for _, wrapper := range []string{"Vec<", "Option<"} {
+		if strings.HasPrefix(typ, wrapper) && strings.HasSuffix(typ, ">") {
+			return supportedRustType(typ[len(wrapper) : len(typ)-1])
+		}
+	}
+	return false
+}
+
+// validate checks that every parameter and the return value can be handled
+func (fn *rustFunction) validate() error {
+	for i, p := range fn.params {
+		if !supportedRustType(p.typ) {
+			return fmt.Errorf("parameter %d of %s has unsupported type %s", i+1, fn.name, p.typ)
+		}
+	}
+
+	if fn.returns == "" || fn.returns == "()" {
+		return nil
+	}
+	returns := strings.Join(strings.Fields(fn.returns), "")
+	if !supportedRustType(returns) {
+		return fmt.Errorf("%s returns unsupported type %s", fn.name, fn.returns)
+	}
+	return nil
+}
+
+// callBody renders the closure body that parses the arguments, calls the
+// solution and formats the result. A function without a return value is
+// judged by its first &mut argument, as for in-place algorithms.
+func (fn *rustFunction) callBody(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%salgoscales::arity(&args, %d)?;\n", indent, len(fn.params)))
+
+	args := make([]string, len(fn.params))
+	inPlace := ""
+	for i, p := range fn.params {
+		name := fmt.Sprintf("a%d", i)
+		b.WriteString(fmt.Sprintf("%slet mut %s = <%s as algoscales::Parse>::parse(&args[%d])?;\n", indent, name, p.typ, i))
+
+		switch {
+		case p.mut:
+			args[i] = "&mut " + name
+			if inPlace == "" {
+				inPlace = name
+			}
+		case p.ref:
+			args[i] = "&" + name
+		default:
+			args[i] = name
+		}
+	}
+
+	call := fmt.Sprintf("%s(%s)", fn.name, strings.Join(args, ", "))
+	if fn.method {
+		call = "Solution::" + call
+	}
+
+	switch {
+	case fn.returns != "" && fn.returns != "()":
+		b.WriteString(fmt.Sprintf("%slet result = %s;\n", indent, call))
+		b.WriteString(fmt.Sprintf("%sOk(algoscales::Show::show(&result))\n", indent))
+	case inPlace != "":
+		b.WriteString(fmt.Sprintf("%s%s;\n", indent, call))
+		b.WriteString(fmt.Sprintf("%sOk(algoscales::Show::show(&%s))\n", indent, inPlace))
+	default:
+		b.WriteString(fmt.Sprintf("%s%s;\n", indent, call))
+		b.WriteString(fmt.Sprintf("%sOk(String::from(\"null\"))\n", indent))
+	}
+
+	return b.String()
+}
+
+// rustString quotes a Go string as a Rust string literal
+func rustString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// rustHarnessSupport is the runtime the generated program links in. Inputs
+// are split on top-level commas, an optional "name =" prefix is dropped and
+// each argument is parsed into the declared type. Results are printed in a
+// compact JSON-like form and compared ignoring whitespace and string quotes;
+// outcomes go to the result file, which is rewritten after every test.
+var rustHarnessSupport = harnessReplacer.Replace(`
+mod algoscales {
+    pub type Outcome = Result<String, String>;
+
+    /// Splits on commas that are not nested in brackets or quotes
+    pub fn split_top(s: &str) -> Vec<String> {
+        let mut parts = Vec::new();
+        let mut cur = String::new();
+        let (mut depth, mut in_str, mut escaped) = (0i32, false, false);
+        for c in s.chars() {
+            if in_str {
+                cur.push(c);
+                if escaped {
+                    escaped = false;
+                } else if c == '\\' {
+                    escaped = true;
+                } else if c == '"' {
+                    in_str = false;
+                }
+                continue;
+            }
+            match c {
+                '"' => {
+                    in_str = true;
+                    cur.push(c);
+                }
+                '[' | '(' | '{' => {
+                    depth += 1;
+                    cur.push(c);
+                }
+                ']' | ')' | '}' => {
+                    depth -= 1;
+                    cur.push(c);
+                }
+                ',' if depth == 0 => {
+                    parts.push(cur.trim().to_string());
+                    cur.clear();
+                }
+                _ => cur.push(c),
+            }
+        }
+        if !cur.trim().is_empty() || !parts.is_empty() {
+            parts.push(cur.trim().to_string());
+        }
+        parts
+    }
+
+    /// Drops a leading "name =" so inputs like "nums = [1,2], k = 3" work
+    fn strip_name(arg: &str) -> String {
+        if let Some(eq) = arg.find('=') {
+            let name = arg[..eq].trim();
+            if !name.is_empty() && name.chars().all(|c| c.is_alphanumeric() || c == '_') {
+                return arg[eq + 1..].trim().to_string();
+            }
+        }
+        arg.to_string()
+    }
+
+    pub fn arity(args: &[String], n: usize) -> Result<(), String> {
+        if args.len() != n {
+            return Err(format!("expected {} arguments, got {}", n, args.len()));
+        }
+        Ok(())
+    }
+
+    pub trait Parse: Sized {
+        fn parse(s: &str) -> Result<Self, String>;
+    }
+
+    macro_rules! parse_number {
+        ($($t:ty),*) => {$(
+            impl Parse for $t {
+                fn parse(s: &str) -> Result<Self, String> {
+                    s.trim().parse::<$t>().map_err(|e| format!("invalid {} {:?}: {}", stringify!($t), s.trim(), e))
+                }
+            }
+        )*};
+    }
+    parse_number!(i8, i16, i32, i64, i128, isize, u8, u16, u32, u64, u128, usize, f32, f64);
+
+    impl Parse for bool {
+        fn parse(s: &str) -> Result<Self, String> {
+            match s.trim() {
+                "true" | "True" => Ok(true),
+                "false" | "False" => Ok(false),
+                other => Err(format!("invalid bool {:?}", other)),
+            }
+        }
+    }
+
+    impl Parse for String {
+        fn parse(s: &str) -> Result<Self, String> {
+            let t = s.trim();
+            for q in ['"', '\''] {
+                if t.len() >= 2 && t.starts_with(q) && t.ends_with(q) {
+                    return Ok(unescape(&t[1..t.len() - 1]));
+                }
+            }
+            Ok(t.to_string())
+        }
+    }
+
+    impl Parse for char {
+        fn parse(s: &str) -> Result<Self, String> {
+            let text = String::parse(s)?;
+            let mut chars = text.chars();
+            match (chars.next(), chars.next()) {
+                (Some(c), None) => Ok(c),
+                _ => Err(format!("invalid char {:?}", s.trim())),
+            }
+        }
+    }
+
+    impl<T: Parse> Parse for Vec<T> {
+        fn parse(s: &str) -> Result<Self, String> {
+            let t = s.trim();
+            if !(t.starts_with('[') && t.ends_with(']')) {
+                return Err(format!("expected a list, got {:?}", t));
+            }
+            let inner = &t[1..t.len() - 1];
+            if inner.trim().is_empty() {
+                return Ok(Vec::new());
+            }
+            split_top(inner).iter().map(|item| T::parse(item)).collect()
+        }
+    }
+
+    impl<T: Parse> Parse for Option<T> {
+        fn parse(s: &str) -> Result<Self, String> {
+            match s.trim() {
+                "null" | "None" => Ok(None),
+                other => T::parse(other).map(Some),
+            }
+        }
+    }
+
+    fn unescape(s: &str) -> String {
+        let mut out = String::new();
+        let mut chars = s.chars();
+        while let Some(c) = chars.next() {
+            if c != '\\' {
+                out.push(c);
+                continue;
+            }
+            match chars.next() {
+                Some('n') => out.push('\n'),
+                Some('t') => out.push('\t'),
+                Some(other) => out.push(other),
+                None => out.push('\\'),
+            }
+        }
+        out
+    }
+
+    pub trait Show {
+        fn show(&self) -> String;
+    }
+
+    macro_rules! show_display {
+        ($($t:ty),*) => {$(
+            impl Show for $t {
+                fn show(&self) -> String {
+                    self.to_string()
+                }
+            }
+        )*};
+    }
+    show_display!(i8, i16, i32, i64, i128, isize, u8, u16, u32, u64, u128, usize, f32, f64, bool);
+
+    impl Show for String {
+        fn show(&self) -> String {
+            format!("{:?}", self)
+        }
+    }
+
+    impl Show for char {
+        fn show(&self) -> String {
+            format!("{:?}", self.to_string())
+        }
+    }
+
+    impl<T: Show> Show for Vec<T> {
+        fn show(&self) -> String {
+            let items: Vec<String> = self.iter().map(|item| item.show()).collect();
+            format!("[{}]", items.join(","))
+        }
+    }
+
+    impl<T: Show> Show for Option<T> {
+        fn show(&self) -> String {
+            match self {
+                Some(value) => value.show(),
+                None => String::from("null"),
+            }
+        }
+    }
+
+    /// Removes whitespace outside string literals
+    fn normalize(s: &str) -> String {
+        let mut out = String::new();
+        let (mut in_str, mut escaped) = (false, false);
+        for c in s.trim().chars() {
+            if in_str {
+                if escaped {
+                    escaped = false;
+                } else if c == '\\' {
+                    escaped = true;
+                } else if c == '"' {
+                    in_str = false;
+                }
+                out.push(c);
+            } else if c == '"' {
+                in_str = true;
+                out.push(c);
+            } else if !c.is_whitespace() {
+                out.push(c);
+            }
+        }
+        out
+    }
+
+    pub fn matches(got: &str, expected: &str) -> bool {
+        let (got, expected) = (normalize(got), normalize(expected));
+        got == expected || got.replace('"', "") == expected.replace('"', "").replace('\'', "")
+    }
+
+    /// Collects outcomes and writes the result file
+    pub struct Report {
+        entries: Vec<String>,
+        passed: bool,
+    }
+
+    impl Report {
+        pub fn new() -> Self {
+            Report { entries: Vec::new(), passed: true }
+        }
+
+        pub fn passed(&self) -> bool {
+            self.passed
+        }
+
+        fn record(&mut self, test: usize, status: &str, actual: Option<&str>, error: Option<&str>, duration_ms: f64) {
+            self.passed &= status == "__PASS__";
+            let mut entry = format!("{{\"test\":{},\"status\":{}", test, json_string(status));
+            if let Some(actual) = actual {
+                entry.push_str(&format!(",\"actual\":{}", json_string(actual)));
+            }
+            if let Some(error) = error {
+                entry.push_str(&format!(",\"error\":{}", json_string(error)));
+            }
+            entry.push_str(&format!(",\"durationMs\":{}}}", duration_ms));
+            self.entries.push(entry);
+
+            if let Ok(path) = std::env::var("__RESULTS_ENV__") {
+                let _ = std::fs::write(path, format!("{{\"tests\":[{}]}}", self.entries.join(",")));
+            }
+        }
+    }
+
+    fn json_string(s: &str) -> String {
+        let mut out = String::from("\"");
+        for c in s.chars() {
+            match c {
+                '"' => out.push_str("\\\""),
+                '\\' => out.push_str("\\\\"),
+                '\n' => out.push_str("\\n"),
+                '\r' => out.push_str("\\r"),
+                '\t' => out.push_str("\\t"),
+                c if (c as u32) < 0x20 => out.push_str(&format!("\\u{:04x}", c as u32)),
+                c => out.push(c),
+            }
+        }
+        out.push('"');
+        out
+    }
+
+    pub fn run<F: FnOnce(Vec<String>) -> Outcome>(report: &mut Report, test: usize, input: &str, expected: &str, f: F) {
+        let args: Vec<String> = split_top(input).iter().map(|arg| strip_name(arg)).collect();
+        let start = std::time::Instant::now();
+        let outcome = std::panic::catch_unwind(std::panic::AssertUnwindSafe(|| f(args)));
+        let duration_ms = start.elapsed().as_secs_f64() * 1000.0;
+        match outcome {
+            Ok(Ok(got)) if matches(&got, expected) => report.record(test, "__PASS__", Some(&got), None, duration_ms),
+            Ok(Ok(got)) => report.record(test, "__FAIL__", Some(&got), None, duration_ms),
+            Ok(Err(e)) => report.record(test, "__ERROR__", None, Some(&e), duration_ms),
+            Err(panic) => {
+                let msg = panic
+                    .downcast_ref::<&str>()
+                    .map(|s| s.to_string())
+                    .or_else(|| panic.downcast_ref::<String>().cloned())
+                    .unwrap_or_else(|| String::from("unknown panic"));
+                report.record(test, "__ERROR__", None, Some(&format!("panicked: {}", msg)), duration_ms);
+            }
+        }
+    }
+}
+`)
@@ -0,0 +1,200 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/logging"
+)
+
+// rustManifest is the Cargo.toml of the throwaway project each run builds
+const rustManifest = `[package]
+name = "solution"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+`
+
+// RustTestRunner implements the TestRunner interface for Rust code
+type RustTestRunner struct {
+	BaseTestRunner
+	targetDir string
+}
+
+// NewRustTestRunner creates a new Rust test runner
+func NewRustTestRunner() *RustTestRunner {
+	// Build artifacts are shared between runs so cargo only recompiles the solution
+	targetDir := filepath.Join(os.TempDir(), "algo-scales-cargo-target")
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		targetDir = filepath.Join(cacheDir, "algo-scales", "cargo-target")
+	}
+
+	return &RustTestRunner{
+		BaseTestRunner: NewBaseTestRunner("rust"),
+		targetDir:      targetDir,
+	}
+}
+
+// WithTargetDir sets the cargo target directory used for builds
+func (r *RustTestRunner) WithTargetDir(dir string) *RustTestRunner {
+	r.targetDir = dir
+	return r
+}
+
+// ExecuteTests runs tests for a Rust solution
+func (r *RustTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Problem, code string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+	// Create a context with timeout for the entire operation
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Add logging context
+	ctx = logging.WithOperation(ctx, "ExecuteRustTests")
+	ctx = logging.WithComponent(ctx, "RustTestRunner")
+	logger := logging.TestRunnerLogger.WithContext(ctx)
+
+	// Create session snapshot for error logging
+	sessionState := &logging.SessionSnapshot{
+		ProblemID:  prob.ID,
+		Language:   "rust",
+		Mode:       "test_execution",
+		UserCode:   code,
+		StartTime:  time.Now(),
+		Patterns:   prob.Tags,
+		Difficulty: prob.Difficulty,
+		CustomFields: map[string]string{
+			"timeout":    timeout.String(),
+			"test_count": fmt.Sprintf("%d", len(prob.TestCases)),
+		},
+	}
+
+	// Log operation start
+	finishLog := logger.StartOperation(fmt.Sprintf("Execute Rust tests for problem %s", prob.ID))
+	defer func() {
+		if r := recover(); r != nil {
+			if logging.GlobalErrorLogger != nil {
+				logging.GlobalErrorLogger.LogPanic(ctx, r, "execute_rust_tests", sessionState)
+			}
+			finishLog(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+	}()
+
+	// Create a temporary cargo project for test execution
+	testDir, err := os.MkdirTemp("", "algo-scales-rust-test")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(testDir) // Clean up when done
+
+	if err := os.MkdirAll(filepath.Join(testDir, "src"), 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create source directory: %v", err)
+	}
+
+	// Generate test code
+	testCode, err := r.GenerateTestCode(prob, code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate test code: %v", err)
+	}
+
+	// Write the manifest and the test program
+	manifest := filepath.Join(testDir, "Cargo.toml")
+	if err := os.WriteFile(manifest, []byte(rustManifest), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write cargo manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "src", "main.rs"), []byte(testCode), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write test file: %v", err)
+	}
+
+	resultsFile := filepath.Join(testDir, resultsFileName)
+
+	// Compile first so only the test program runs in the sandbox
+	build := exec.CommandContext(ctx, "cargo", "build", "--quiet", "--offline", "--manifest-path", manifest)
+	build.Dir = testDir
+	build.Env = append(os.Environ(), "CARGO_TARGET_DIR="+r.targetDir)
+	_, stderr, err := runCommandWithTimeout(build, timeout)
+	if err != nil {
+		results := readResults(resultsFile, prob.TestCases, err, stderr.String())
+		return withFailure(results, interfaces.FailureCompile), false, nil
+	}
+
+	// The target directory is shared between runs, so take a copy of the
+	// program the sandbox can see
+	binary := filepath.Join(testDir, "solution")
+	data, err := os.ReadFile(filepath.Join(r.targetDir, "debug", "solution"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read test program: %v", err)
+	}
+	if err := os.WriteFile(binary, data, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to write test program: %v", err)
+	}
+
+	// Run the test program in the sandbox and read the results it wrote
+	cmd := ActiveSandbox.command(ctx, "rust", testDir, len(prob.TestCases), binary)
+	cmd.Env = append(os.Environ(), "RUST_BACKTRACE=0")
+	results, _ := runHarness(cmd, timeout, resultsFile, prob.TestCases)
+
+	return results, allTestsPassed(results), nil
+}
+
+// GenerateTestCode creates a Rust program that runs the solution against
+// every test case. Arguments are parsed according to the solution's function
+// signature; see rust_harness.go for the supported parameter types.
+func (r *RustTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
+	testTemplate := `#![allow(warnings)]
+
+// User's solution
+%s
+%s
+%s
+fn main() {
+    // Panics are reported as test errors, not on stderr
+    std::panic::set_hook(Box::new(|_| {}));
+    let mut report = algoscales::Report::new();
+%s
+    if !report.passed() {
+        std::process::exit(1);
+    }
+}
+`
+
+	// A main in the solution would clash with the harness
+	solutionCode = rustMainPattern.ReplaceAllString(solutionCode, "${1}fn _solution_main(")
+
+	var extra string
+	var testCases strings.Builder
+
+	fn, err := findRustFunction(solutionCode, prob.StarterCode["rust"])
+	if err == nil {
+		err = fn.validate()
+	}
+
+	if err != nil {
+		// Report the problem once per test instead of failing to run at all
+		for i := range prob.TestCases {
+			testCases.WriteString(fmt.Sprintf("\n    // Test case %d\n", i+1))
+			testCases.WriteString(fmt.Sprintf("    algoscales::run(&mut report, %d, \"\", \"\", |_| Err(String::from(%s)));\n", i+1, rustString(err.Error())))
+		}
+		return fmt.Sprintf(testTemplate, solutionCode, extra, rustHarnessSupport, testCases.String()), nil
+	}
+
+	if fn.method && !rustSolutionStructPattern.MatchString(solutionCode) {
+		extra = "pub struct Solution;\n"
+	}
+
+	for i, tc := range prob.TestCases {
+		testCases.WriteString(fmt.Sprintf("\n    // Test case %d\n", i+1))
+		testCases.WriteString(fmt.Sprintf("    algoscales::run(&mut report, %d, %s, %s, |args| {\n",
+			i+1, rustString(tc.Input), rustString(tc.Expected)))
+		testCases.WriteString(fn.callBody("        "))
+		testCases.WriteString("    });\n")
+	}
+
+	return fmt.Sprintf(testTemplate, solutionCode, extra, rustHarnessSupport, testCases.String()), nil
+}
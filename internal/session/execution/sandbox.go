@@ -0,0 +1,335 @@
+// Sandboxed execution of generated test harnesses with resource limits
+
+package execution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// Sandbox modes
+const (
+	// SandboxProcess runs the harness as a plain subprocess
+	SandboxProcess = "process"
+	// SandboxRlimit caps CPU time and memory with rlimits (Unix only)
+	SandboxRlimit = "rlimit"
+	// SandboxDocker runs the harness in a throwaway container without network
+	SandboxDocker = "docker"
+)
+
+// startupGrace is how long a harness may take to start before the per-test
+// time limit applies to its first test; containers start slower
+const (
+	startupGrace       = 2 * time.Second
+	dockerStartupGrace = 10 * time.Second
+)
+
+// defaultDockerImages are the images solutions run in by language. Compiled
+// harnesses are built on the host and only run in the container.
+var defaultDockerImages = map[string]string{
+	"go":         "debian:bookworm-slim",
+	"python":     "python:3.12-slim",
+	"javascript": "node:20-slim",
+	"typescript": "node:20-slim",
+	"java":       "eclipse-temurin:21-jre",
+	"cpp":        "gcc:13",
+	"rust":       "debian:bookworm-slim",
+}
+
+// memoryErrorPatterns are how runtimes report an allocation that failed
+var memoryErrorPatterns = []string{
+	"MemoryError",
+	"out of memory",
+	"cannot allocate memory",
+	"allocation failed",
+	"std::bad_alloc",
+	"OutOfMemoryError",
+	"memory allocation of",
+}
+
+// Sandbox runs solutions with per-test limits. Zero limits are not enforced.
+type Sandbox struct {
+	Mode     string
+	TestTime time.Duration     // Wall-clock time per test
+	CPUTime  time.Duration     // CPU time per test, enforced in rlimit and docker modes
+	MemoryMB int               // Memory of the solution process, enforced in rlimit and docker modes
+	Images   map[string]string // Docker images by language, overriding the defaults
+}
+
+// ActiveSandbox is the sandbox every runner executes solutions in
+var ActiveSandbox = Sandbox{Mode: SandboxProcess}
+
+// harnessRun is the outcome of running a harness in the sandbox
+type harnessRun struct {
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	err      error
+	timedOut bool // the run or a single test overran its time limit
+}
+
+// command builds the command running a harness in the sandbox. The harness
+// files live in dir, which containers mount at the same path.
+func (s Sandbox) command(ctx context.Context, language, dir string, tests int, name string, args ...string) *exec.Cmd {
+	switch s.mode() {
+	case SandboxRlimit:
+		var limits []string
+		if s.CPUTime > 0 {
+			// The soft limit sends SIGXCPU, the hard limit a second later SIGKILL
+			seconds := s.cpuSeconds(tests)
+			limits = append(limits, fmt.Sprintf("ulimit -S -t %d", seconds), fmt.Sprintf("ulimit -H -t %d", seconds+1))
+		}
+		if s.MemoryMB > 0 && language != "java" {
+			// The JVM reserves its heap up front; it is capped with -Xmx instead
+			limits = append(limits, fmt.Sprintf("ulimit -d %d", s.MemoryMB*1024))
+		}
+		if language == "java" && s.MemoryMB > 0 {
+			args = append([]string{fmt.Sprintf("-Xmx%dm", s.MemoryMB)}, args...)
+		}
+		script := strings.Join(append(limits, `exec "$0" "$@"`), "; ")
+		cmd := exec.CommandContext(ctx, "sh", append([]string{"-c", script, name}, args...)...)
+		cmd.Dir = dir
+		return cmd
+
+	case SandboxDocker:
+		image := s.Images[language]
+		if image == "" {
+			image = defaultDockerImages[language]
+		}
+		dockerArgs := []string{
+			"run", "--rm", "--network", "none",
+			"--name", containerName(dir),
+			"-v", dir + ":" + dir, "-w", dir,
+			"-e", ResultsEnv,
+		}
+		if runtime.GOOS != "windows" {
+			dockerArgs = append(dockerArgs, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+		}
+		if s.MemoryMB > 0 {
+			memory := fmt.Sprintf("%dm", s.MemoryMB)
+			dockerArgs = append(dockerArgs, "--memory", memory, "--memory-swap", memory)
+		}
+		if s.CPUTime > 0 {
+			dockerArgs = append(dockerArgs, "--ulimit", fmt.Sprintf("cpu=%d", s.cpuSeconds(tests)))
+		}
+		dockerArgs = append(dockerArgs, image, name)
+		cmd := exec.CommandContext(ctx, "docker", append(dockerArgs, args...)...)
+		cmd.Dir = dir
+		return cmd
+
+	default:
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		return cmd
+	}
+}
+
+// mode returns the effective sandbox mode; rlimits need a Unix shell
+func (s Sandbox) mode() string {
+	if s.Mode == SandboxRlimit && runtime.GOOS == "windows" {
+		return SandboxProcess
+	}
+	if s.Mode == "" {
+		return SandboxProcess
+	}
+	return s.Mode
+}
+
+// cpuSeconds is the CPU time budget of a whole run in whole seconds
+func (s Sandbox) cpuSeconds(tests int) int {
+	if tests < 1 {
+		tests = 1
+	}
+	return int(math.Ceil((s.CPUTime * time.Duration(tests)).Seconds()))
+}
+
+// run runs a harness command, killing it when the whole run exceeds timeout
+// or a single test exceeds the per-test time limit. Tests are timed by
+// watching the result file, which harnesses rewrite after every test.
+func (s Sandbox) run(cmd *exec.Cmd, timeout time.Duration, resultsFile string) *harnessRun {
+	run := &harnessRun{}
+	cmd.Stdout = &run.stdout
+	cmd.Stderr = &run.stderr
+
+	if run.err = cmd.Start(); run.err != nil {
+		return run
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	overall := time.NewTimer(timeout)
+	defer overall.Stop()
+
+	var tick <-chan time.Time
+	if s.TestTime > 0 {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	grace := startupGrace
+	if s.mode() == SandboxDocker {
+		grace = dockerStartupGrace
+	}
+	deadline := time.Now().Add(grace + s.TestTime)
+	reported := 0
+
+	for {
+		select {
+		case run.err = <-done:
+			return run
+		case <-overall.C:
+			run.timedOut = true
+			s.kill(cmd)
+			<-done
+			run.err = fmt.Errorf("command timed out after %v", timeout)
+			return run
+		case <-tick:
+			if n := reportedTests(resultsFile); n > reported {
+				reported = n
+				deadline = time.Now().Add(s.TestTime)
+			}
+			if time.Now().After(deadline) {
+				run.timedOut = true
+				s.kill(cmd)
+				<-done
+				run.err = fmt.Errorf("test %d exceeded the time limit of %v", reported+1, s.TestTime)
+				return run
+			}
+		}
+	}
+}
+
+// kill stops a harness; a container is stopped through docker because
+// killing the docker client would leave it running
+func (s Sandbox) kill(cmd *exec.Cmd) {
+	if s.mode() == SandboxDocker {
+		exec.Command("docker", "kill", containerName(cmd.Dir)).Run()
+	}
+	cmd.Process.Kill()
+}
+
+// containerName names the container running the harness in dir
+func containerName(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, dir)
+	return "algoscales" + name
+}
+
+// applyLimits marks the tests that ran into a limit. A test that took longer
+// than the per-test limit or failed to allocate memory is reported as such,
+// and when the run was stopped, the first test without a result is the one
+// that was running.
+func (s Sandbox) applyLimits(results []interfaces.TestResult, resultsFile string, run *harnessRun) []interfaces.TestResult {
+	for i := range results {
+		result := &results[i]
+		switch {
+		case s.TestTime > 0 && result.Duration > s.TestTime:
+			result.Passed = false
+			result.Failure = interfaces.FailureTimeLimit
+			result.Actual = fmt.Sprintf("Error: took %s, over the limit of %s", FormatDuration(result.Duration), FormatDuration(s.TestTime))
+		case !result.Passed && result.Failure == interfaces.FailureRuntime && isMemoryError(result.Actual):
+			result.Failure = interfaces.FailureMemoryLimit
+		}
+	}
+
+	running := reportedTests(resultsFile)
+	if running >= len(results) || run.err == nil {
+		return results
+	}
+
+	result := &results[running]
+	stderr := run.stderr.String()
+	switch {
+	case isMemoryError(stderr) || s.oomKilled(run):
+		result.Failure = interfaces.FailureMemoryLimit
+		if s.MemoryMB > 0 {
+			result.Actual = fmt.Sprintf("Error: memory limit of %d MB exceeded", s.MemoryMB)
+		}
+	case run.timedOut:
+		result.Failure = interfaces.FailureTimeLimit
+		result.Actual = "Error: " + run.err.Error()
+	case s.cpuLimited(run):
+		result.Failure = interfaces.FailureTimeLimit
+		result.Actual = fmt.Sprintf("Error: CPU time limit of %s per test exceeded", FormatDuration(s.CPUTime))
+	}
+	return results
+}
+
+// oomKilled reports whether docker killed the container for using more
+// memory than allowed, which it reports as exit status 137
+func (s Sandbox) oomKilled(run *harnessRun) bool {
+	if s.mode() != SandboxDocker || s.MemoryMB <= 0 || run.timedOut {
+		return false
+	}
+	exitErr, ok := run.err.(*exec.ExitError)
+	return ok && exitErr.ExitCode() == 137
+}
+
+// cpuLimited reports whether the kernel stopped the harness at its CPU limit
+func (s Sandbox) cpuLimited(run *harnessRun) bool {
+	if s.CPUTime <= 0 || s.mode() == SandboxProcess {
+		return false
+	}
+	exitErr, ok := run.err.(*exec.ExitError)
+	if !ok {
+		return false
+	}
+	if s.mode() == SandboxDocker {
+		// Docker reports the signal as 128 plus its number
+		return exitErr.ExitCode() == 128+int(syscall.SIGKILL) || exitErr.ExitCode() == 128+xcpuSignal
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && (status.Signal() == syscall.SIGKILL || int(status.Signal()) == xcpuSignal)
+}
+
+// xcpuSignal is SIGXCPU, which syscall does not define on every platform
+const xcpuSignal = 24
+
+// isMemoryError reports whether output shows a failed allocation
+func isMemoryError(output string) bool {
+	for _, pattern := range memoryErrorPatterns {
+		if strings.Contains(output, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportedTests returns how many tests the harness has reported so far. A
+// result file caught mid-write counts as none.
+func reportedTests(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var report HarnessReport
+	if json.Unmarshal(data, &report) != nil {
+		return 0
+	}
+	return len(report.Tests)
+}
+
+// runHarness runs a harness under the active sandbox and reads its results
+func runHarness(cmd *exec.Cmd, timeout time.Duration, resultsFile string, testCases []interfaces.TestCase) ([]interfaces.TestResult, *harnessRun) {
+	withResultsFile(cmd, resultsFile)
+	run := ActiveSandbox.run(cmd, timeout, resultsFile)
+	results := readResults(resultsFile, testCases, run.err, run.stderr.String())
+	return ActiveSandbox.applyLimits(results, resultsFile, run), run
+}
@@ -0,0 +1,134 @@
+package execution
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useSandbox makes the runners use a sandbox for the rest of the test
+func useSandbox(t *testing.T, sandbox Sandbox) {
+	original := ActiveSandbox
+	ActiveSandbox = sandbox
+	t.Cleanup(func() { ActiveSandbox = original })
+}
+
+// identitySignature is a one-argument function returning its int argument
+var identitySignature = &interfaces.FunctionSignature{
+	Name:    "identity",
+	Params:  []interfaces.Param{{Name: "n", Type: "int"}},
+	Returns: "int",
+}
+
+func runPython(t *testing.T, solution string, inputs ...string) []interfaces.TestResult {
+	t.Helper()
+	if _, err := exec.LookPath("python"); err != nil {
+		t.Skip("python not installed")
+	}
+
+	prob := &interfaces.Problem{ID: "identity", Signature: identitySignature}
+	for _, input := range inputs {
+		prob.TestCases = append(prob.TestCases, interfaces.TestCase{Input: input, Expected: input})
+	}
+	results, _, err := NewPythonTestRunner().ExecuteTests(context.Background(), prob, solution, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, results, len(inputs))
+	return results
+}
+
+func TestSandboxPerTestTimeLimit(t *testing.T) {
+	useSandbox(t, Sandbox{Mode: SandboxProcess, TestTime: 300 * time.Millisecond})
+
+	solution := "import time\n\ndef identity(n):\n    if n == 2:\n        time.sleep(30)\n    return n\n"
+	results := runPython(t, solution, "1", "2", "3")
+
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, interfaces.FailureTimeLimit, results[1].Failure)
+	assert.Equal(t, "Error: test 2 exceeded the time limit of 300ms", results[1].Actual)
+	assert.Equal(t, interfaces.FailureRuntime, results[2].Failure)
+}
+
+func TestSandboxRlimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("rlimits need a Unix shell")
+	}
+
+	t.Run("memory", func(t *testing.T) {
+		useSandbox(t, Sandbox{Mode: SandboxRlimit, MemoryMB: 256})
+		solution := "def identity(n):\n    if n == 2:\n        return len(bytearray(1024 * 1024 * 1024))\n    return n\n"
+		results := runPython(t, solution, "1", "2")
+
+		assert.True(t, results[0].Passed)
+		assert.Equal(t, interfaces.FailureMemoryLimit, results[1].Failure, results[1].Actual)
+	})
+
+	t.Run("cpu", func(t *testing.T) {
+		useSandbox(t, Sandbox{Mode: SandboxRlimit, CPUTime: 500 * time.Millisecond})
+		solution := "def identity(n):\n    while n == 2:\n        pass\n    return n\n"
+		results := runPython(t, solution, "1", "2")
+
+		assert.True(t, results[0].Passed)
+		assert.Equal(t, interfaces.FailureTimeLimit, results[1].Failure)
+		assert.Equal(t, "Error: CPU time limit of 500ms per test exceeded", results[1].Actual)
+	})
+}
+
+func TestSandboxCommand(t *testing.T) {
+	ctx := context.Background()
+
+	cmd := Sandbox{}.command(ctx, "python", "/tmp/run", 3, "python", "test.py")
+	assert.Equal(t, []string{"python", "test.py"}, cmd.Args)
+	assert.Equal(t, "/tmp/run", cmd.Dir)
+
+	docker := Sandbox{Mode: SandboxDocker, CPUTime: 400 * time.Millisecond, MemoryMB: 128, Images: map[string]string{"python": "my/python"}}
+	args := strings.Join(docker.command(ctx, "python", "/tmp/run", 3, "python", "/tmp/run/test.py").Args, " ")
+	assert.Contains(t, args, "docker run --rm --network none --name algoscales-tmp-run -v /tmp/run:/tmp/run -w /tmp/run -e "+ResultsEnv)
+	assert.Contains(t, args, "--memory 128m --memory-swap 128m --ulimit cpu=2 my/python python /tmp/run/test.py")
+
+	rlimit := Sandbox{Mode: SandboxRlimit, CPUTime: time.Second, MemoryMB: 64}
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, []string{"sh", "-c", `ulimit -S -t 2; ulimit -H -t 3; exec "$0" "$@"`, "java", "-Xmx64m", "-cp", "classes", "Main"},
+			rlimit.command(ctx, "java", "/tmp/run", 2, "java", "-cp", "classes", "Main").Args)
+	}
+}
+
+func TestApplyLimitsReportsSlowTests(t *testing.T) {
+	sandbox := Sandbox{TestTime: 100 * time.Millisecond}
+	results := []interfaces.TestResult{
+		{Passed: true, Duration: 50 * time.Millisecond},
+		{Passed: true, Duration: 250 * time.Millisecond},
+		{Failure: interfaces.FailureRuntime, Actual: "Error: MemoryError"},
+	}
+
+	results = sandbox.applyLimits(results, "missing.json", &harnessRun{})
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, interfaces.FailureTimeLimit, results[1].Failure)
+	assert.Equal(t, "Error: took 250ms, over the limit of 100ms", results[1].Actual)
+	assert.Equal(t, interfaces.FailureMemoryLimit, results[2].Failure)
+}
+
+func TestRunScript(t *testing.T) {
+	if _, err := exec.LookPath("python"); err != nil {
+		t.Skip("python not installed")
+	}
+	useSandbox(t, Sandbox{Mode: SandboxProcess, TestTime: time.Millisecond})
+
+	script := "import sys, time\ntime.sleep(0.1)\nprint([int(sys.argv[1])] * 3, sys.argv[2])\n"
+	output, err := RunScript(context.Background(), "python", script, []string{"2", "7"}, time.Minute)
+	require.NoError(t, err, "per-test limits do not apply")
+	assert.Equal(t, "[2, 2, 2] 7\n", output)
+
+	_, err = RunScript(context.Background(), "python", "raise ValueError('bad size')\n", nil, time.Minute)
+	assert.ErrorContains(t, err, "ValueError: bad size")
+
+	_, err = RunScript(context.Background(), "ruby", "puts 1", nil, time.Minute)
+	assert.ErrorContains(t, err, "cannot run ruby scripts")
+}
@@ -0,0 +1,55 @@
+// Scripts that problems ship, such as input generators
+
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// scriptInterpreters run scripts by language
+var scriptInterpreters = map[string]struct{ command, file string }{
+	"python":     {"python", "script.py"},
+	"javascript": {"node", "script.js"},
+}
+
+// RunScript runs a script in the sandbox with arguments and returns what it
+// printed. A script that fails or runs longer than timeout is an error
+// quoting what it printed to stderr.
+func RunScript(ctx context.Context, language, script string, args []string, timeout time.Duration) (string, error) {
+	interpreter, ok := scriptInterpreters[language]
+	if !ok {
+		return "", fmt.Errorf("cannot run %s scripts", language)
+	}
+
+	dir, err := os.MkdirTemp("", "algo-scales-script")
+	if err != nil {
+		return "", fmt.Errorf("failed to create script directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, interpreter.file)
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		return "", fmt.Errorf("failed to write script: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Per-test limits do not apply to a script, only the memory limit
+	sandbox := ActiveSandbox
+	sandbox.TestTime, sandbox.CPUTime = 0, 0
+	cmd := sandbox.command(ctx, language, dir, 1, interpreter.command, append([]string{path}, args...)...)
+	run := sandbox.run(cmd, timeout, filepath.Join(dir, resultsFileName))
+	if run.err != nil {
+		if stderr := strings.TrimSpace(run.stderr.String()); stderr != "" {
+			return "", fmt.Errorf("%v: %s", run.err, stderr)
+		}
+		return "", run.err
+	}
+	return run.stdout.String(), nil
+}
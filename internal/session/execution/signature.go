@@ -0,0 +1,519 @@
+// Signature-driven argument parsing for the Go, Python and JavaScript
+// harnesses. Test inputs are split and normalized to JSON here, so each
+// harness only decodes ready-made literals and calls the solution.
+
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+var (
+	// argNamePattern matches the "name =" prefix of an argument such as "k = 3"
+	argNamePattern = regexp.MustCompile(`^[A-Za-z_]\w*\s*=\s*`)
+
+	// pythonDefPattern matches a top-level function definition
+	pythonDefPattern = regexp.MustCompile(`(?m)^def\s+([A-Za-z_]\w*)\s*\(`)
+)
+
+// signatureScalarTypes are the element types a signature may use
+var signatureScalarTypes = map[string]bool{
+	"int": true, "float": true, "bool": true, "string": true, "char": true,
+}
+
+// signatureNodeTypes are the types built from a list of values
+var signatureNodeTypes = map[string]bool{
+	"TreeNode": true, "ListNode": true,
+}
+
+// arrayElem returns the element type of an array type such as int[]
+func arrayElem(typ string) (string, bool) {
+	if strings.HasSuffix(typ, "[]") {
+		return typ[:len(typ)-2], true
+	}
+	return "", false
+}
+
+// supportedValueType reports whether a type is a scalar or an array of one
+func supportedValueType(typ string) bool {
+	for {
+		elem, ok := arrayElem(typ)
+		if !ok {
+			return signatureScalarTypes[typ]
+		}
+		typ = elem
+	}
+}
+
+// ValidateSignature checks that harnesses can generate code for a signature
+func ValidateSignature(sig *interfaces.FunctionSignature) error {
+	if sig == nil {
+		return fmt.Errorf("problem has no function signature")
+	}
+	if sig.Name == "" {
+		return fmt.Errorf("function signature has no name")
+	}
+	for i, p := range sig.Params {
+		switch {
+		case p.Type == "cycle":
+			if i == 0 || sig.Params[i-1].Type != "ListNode" {
+				return fmt.Errorf("cycle parameter %s must follow a ListNode", p.Name)
+			}
+		case strings.HasPrefix(p.Type, "char[][][]"):
+			// Go harnesses convert at most a grid of chars to bytes
+			return fmt.Errorf("parameter %s has unsupported type %s", p.Name, p.Type)
+		case signatureNodeTypes[p.Type], supportedValueType(p.Type):
+		default:
+			return fmt.Errorf("parameter %s has unsupported type %s", p.Name, p.Type)
+		}
+	}
+	if !supportedValueType(sig.Returns) {
+		return fmt.Errorf("%s returns unsupported type %s", sig.Name, sig.Returns)
+	}
+	if _, ok := arrayElem(sig.Returns); sig.Unordered && !ok {
+		return fmt.Errorf("%s returns %s, which cannot be unordered", sig.Name, sig.Returns)
+	}
+	return nil
+}
+
+// splitTestInput splits a test input such as "[1,2], k = 3" into its
+// arguments on commas outside brackets and quotes, dropping "name =" prefixes
+func splitTestInput(input string) []string {
+	var args []string
+	depth, start := 0, 0
+	var quote rune
+	escaped := false
+	for i, c := range input {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, input[start:i])
+				start = i + 1
+			}
+		}
+	}
+	args = append(args, input[start:])
+
+	var trimmed []string
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		if !strings.HasPrefix(arg, "'") && !strings.HasPrefix(arg, "\"") {
+			arg = argNamePattern.ReplaceAllString(arg, "")
+		}
+		trimmed = append(trimmed, arg)
+	}
+	return trimmed
+}
+
+// jsonLiteral normalizes a test value to JSON, accepting Python-style
+// literals (True, None, single-quoted strings). A bare word becomes a JSON
+// string when the parameter is a string or char.
+func jsonLiteral(raw, typ string) (string, error) {
+	var b strings.Builder
+	var quote rune
+	escaped := false
+	word := func(w string) string {
+		switch w {
+		case "True":
+			return "true"
+		case "False":
+			return "false"
+		case "None":
+			return "null"
+		}
+		return w
+	}
+
+	runes := []rune(strings.TrimSpace(raw))
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+				b.WriteRune(c)
+			case c == '\\':
+				escaped = true
+				b.WriteRune(c)
+			case c == quote:
+				quote = 0
+				b.WriteByte('"')
+			case c == '"':
+				b.WriteString(`\"`)
+			default:
+				b.WriteRune(c)
+			}
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\'':
+			quote = c
+			b.WriteByte('"')
+		case unicode.IsLetter(c):
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			b.WriteString(word(string(runes[i:j])))
+			i = j - 1
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	literal := b.String()
+	if json.Valid([]byte(literal)) {
+		return literal, nil
+	}
+	if typ == "string" || typ == "char" {
+		quoted, _ := json.Marshal(strings.TrimSpace(raw))
+		return string(quoted), nil
+	}
+	return "", fmt.Errorf("invalid %s value %s", typ, strings.TrimSpace(raw))
+}
+
+// canonicalValue renders a value the way harnesses show results: compact
+// JSON without string quotes, so "[1, 3]" and "[1,3]" compare equal
+func canonicalValue(value string) string {
+	literal, err := jsonLiteral(value, "")
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+
+	var compact strings.Builder
+	var quoted bool
+	escaped := false
+	for _, c := range literal {
+		switch {
+		case quoted && escaped:
+			escaped = false
+			compact.WriteRune(c)
+		case quoted && c == '\\':
+			escaped = true
+			compact.WriteRune(c)
+		case c == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(c):
+		default:
+			compact.WriteRune(c)
+		}
+	}
+	return compact.String()
+}
+
+// expectedValue renders a test's expected value for comparison with shown
+// results. Unordered results are compared with their elements sorted.
+func expectedValue(sig *interfaces.FunctionSignature, expected string) string {
+	if sig == nil || !sig.Unordered {
+		return canonicalValue(expected)
+	}
+	literal, err := jsonLiteral(expected, "")
+	var elems []json.RawMessage
+	if err != nil || json.Unmarshal([]byte(literal), &elems) != nil {
+		return canonicalValue(expected)
+	}
+
+	shown := make([]string, len(elems))
+	for i, elem := range elems {
+		shown[i] = canonicalValue(string(elem))
+	}
+	sort.Strings(shown)
+	return "[" + strings.Join(shown, ",") + "]"
+}
+
+// signatureArgs parses a test input into one JSON literal per parameter
+func signatureArgs(sig *interfaces.FunctionSignature, input string) ([]string, error) {
+	raw := splitTestInput(input)
+	if len(raw) != len(sig.Params) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(sig.Params), len(raw))
+	}
+
+	args := make([]string, len(raw))
+	for i, p := range sig.Params {
+		literal, err := jsonLiteral(raw[i], p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", p.Name, err)
+		}
+		args[i] = literal
+	}
+	return args, nil
+}
+
+// testArgs parses a test input unless the signature itself is unusable
+func testArgs(sig *interfaces.FunctionSignature, sigErr error, input string) ([]string, error) {
+	if sigErr != nil {
+		return nil, sigErr
+	}
+	return signatureArgs(sig, input)
+}
+
+// pythonFunctionName returns the Python name of the solution function: the
+// one the starter code defines, or the signature name in snake_case
+func pythonFunctionName(sig *interfaces.FunctionSignature, starterCode string) string {
+	for _, m := range pythonDefPattern.FindAllStringSubmatch(starterCode, -1) {
+		if !strings.HasPrefix(m[1], "_") {
+			return m[1]
+		}
+	}
+	return snakeCase(sig.Name)
+}
+
+// snakeCase converts a camelCase name such as kClosest to k_closest
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, c := range name {
+		if unicode.IsUpper(c) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// goSignatureType maps a signature type to the Go type solutions declare
+func goSignatureType(typ string) string {
+	if elem, ok := arrayElem(typ); ok {
+		return "[]" + goSignatureType(elem)
+	}
+	switch typ {
+	case "float":
+		return "float64"
+	case "char":
+		return "byte"
+	case "TreeNode", "ListNode":
+		return "*" + typ
+	default:
+		return typ
+	}
+}
+
+// goDecodeType is the type a Go argument is decoded into before conversion:
+// chars arrive as one-letter strings and nodes as their values
+func goDecodeType(typ string) string {
+	if elem, ok := arrayElem(typ); ok {
+		return "[]" + goDecodeType(elem)
+	}
+	switch typ {
+	case "char":
+		return "string"
+	case "TreeNode":
+		return "[]*int"
+	case "ListNode":
+		return "[]int"
+	default:
+		return goSignatureType(typ)
+	}
+}
+
+// signatureUses reports whether any parameter has the given type
+func signatureUses(sig *interfaces.FunctionSignature, typ string) bool {
+	for _, p := range sig.Params {
+		if p.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// goSignatureHelpers declares the node builders a signature needs; they use
+// the solution's own TreeNode and ListNode types
+func goSignatureHelpers(sig *interfaces.FunctionSignature) string {
+	var b strings.Builder
+	if signatureUses(sig, "TreeNode") {
+		b.WriteString(`
+// algoscalesTree builds a tree from level-order values with nulls
+func algoscalesTree(values []*int) *TreeNode {
+	if len(values) == 0 || values[0] == nil {
+		return nil
+	}
+	root := &TreeNode{Val: *values[0]}
+	queue := []*TreeNode{root}
+	for i := 1; len(queue) > 0 && i < len(values); i += 2 {
+		node := queue[0]
+		queue = queue[1:]
+		if values[i] != nil {
+			node.Left = &TreeNode{Val: *values[i]}
+			queue = append(queue, node.Left)
+		}
+		if i+1 < len(values) && values[i+1] != nil {
+			node.Right = &TreeNode{Val: *values[i+1]}
+			queue = append(queue, node.Right)
+		}
+	}
+	return root
+}
+`)
+	}
+	if signatureUses(sig, "ListNode") {
+		b.WriteString(`
+// algoscalesList builds a linked list whose tail links back to the node at
+// index cycle, or to nothing when cycle is -1
+func algoscalesList(values []int, cycle int) *ListNode {
+	nodes := make([]*ListNode, len(values))
+	for i, v := range values {
+		nodes[i] = &ListNode{Val: v}
+		if i > 0 {
+			nodes[i-1].Next = nodes[i]
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	if cycle >= 0 && cycle < len(nodes) {
+		nodes[len(nodes)-1].Next = nodes[cycle]
+	}
+	return nodes[0]
+}
+`)
+	}
+	return b.String()
+}
+
+// goCallBody renders the Go closure body that decodes the arguments, calls
+// the solution and shows the result
+func goCallBody(sig *interfaces.FunctionSignature, args []string, indent string) string {
+	var b strings.Builder
+	var callArgs []string
+	for i, p := range sig.Params {
+		if p.Type == "cycle" {
+			continue
+		}
+		name := fmt.Sprintf("a%d", i)
+		b.WriteString(fmt.Sprintf("%svar %s %s\n", indent, name, goDecodeType(p.Type)))
+		b.WriteString(fmt.Sprintf("%sif err := algoscalesDecode(%s, &%s); err != nil {\n", indent, goRawString(args[i]), name))
+		b.WriteString(fmt.Sprintf("%s\treturn \"\", fmt.Errorf(\"argument %s: %%v\", err)\n", indent, p.Name))
+		b.WriteString(fmt.Sprintf("%s}\n", indent))
+
+		switch {
+		case p.Type == "TreeNode":
+			name = fmt.Sprintf("algoscalesTree(%s)", name)
+		case p.Type == "ListNode":
+			cycle := "-1"
+			if i+1 < len(sig.Params) && sig.Params[i+1].Type == "cycle" {
+				cycle = args[i+1]
+			}
+			name = fmt.Sprintf("algoscalesList(%s, %s)", name, cycle)
+		case p.Type == "char":
+			name = fmt.Sprintf("algoscalesChar(%s)", name)
+		case p.Type == "char[]":
+			name = fmt.Sprintf("algoscalesBytes(%s)", name)
+		case p.Type == "char[][]":
+			name = fmt.Sprintf("algoscalesGrid(%s)", name)
+		}
+		callArgs = append(callArgs, name)
+	}
+
+	b.WriteString(fmt.Sprintf("%sresult := %s(%s)\n", indent, sig.Name, strings.Join(callArgs, ", ")))
+	if _, ok := arrayElem(sig.Returns); ok {
+		// A nil slice would otherwise show as null rather than []
+		b.WriteString(fmt.Sprintf("%sif result == nil {\n%s\treturn \"[]\", nil\n%s}\n", indent, indent, indent))
+	}
+	show := "algoscalesShow"
+	if sig.Unordered {
+		show = "algoscalesShowUnordered"
+	}
+	b.WriteString(fmt.Sprintf("%sreturn %s(result), nil\n", indent, show))
+	return b.String()
+}
+
+// pythonCallBody renders the Python test function body that decodes the
+// arguments, calls the solution and shows the result
+func pythonCallBody(sig *interfaces.FunctionSignature, name string, args []string, indent string) string {
+	// The camelCase name is a fallback for solutions that keep it
+	names := strconv.Quote(name)
+	if name != sig.Name {
+		names += ", " + strconv.Quote(sig.Name)
+	}
+
+	var b strings.Builder
+	var callArgs []string
+	for i, p := range sig.Params {
+		if p.Type == "cycle" {
+			continue
+		}
+		value := fmt.Sprintf("_algoscales_json.loads(%s)", strconv.Quote(args[i]))
+		switch p.Type {
+		case "TreeNode":
+			value = fmt.Sprintf("_algoscales_tree(%s)", value)
+		case "ListNode":
+			cycle := "-1"
+			if i+1 < len(sig.Params) && sig.Params[i+1].Type == "cycle" {
+				cycle = args[i+1]
+			}
+			value = fmt.Sprintf("_algoscales_list(%s, %s)", value, cycle)
+		}
+		b.WriteString(fmt.Sprintf("%sa%d = %s\n", indent, i, value))
+		callArgs = append(callArgs, fmt.Sprintf("a%d", i))
+	}
+	show := "_algoscales_show"
+	if sig.Unordered {
+		show = "_algoscales_show_unordered"
+	}
+	b.WriteString(fmt.Sprintf("%sreturn %s(_algoscales_solution(%s)(%s))\n", indent, show, names, strings.Join(callArgs, ", ")))
+	return b.String()
+}
+
+// javaScriptCallBody renders the JavaScript closure body that builds the
+// arguments, calls the solution and shows the result. JSON literals are
+// valid JavaScript, so arguments are embedded as they are.
+func javaScriptCallBody(sig *interfaces.FunctionSignature, args []string, indent string) string {
+	var b strings.Builder
+	var callArgs []string
+	for i, p := range sig.Params {
+		if p.Type == "cycle" {
+			continue
+		}
+		value := args[i]
+		switch p.Type {
+		case "TreeNode":
+			value = fmt.Sprintf("__algoscalesTree(%s)", value)
+		case "ListNode":
+			cycle := "-1"
+			if i+1 < len(sig.Params) && sig.Params[i+1].Type == "cycle" {
+				cycle = args[i+1]
+			}
+			value = fmt.Sprintf("__algoscalesList(%s, %s)", value, cycle)
+		}
+		b.WriteString(fmt.Sprintf("%sconst a%d = %s;\n", indent, i, value))
+		callArgs = append(callArgs, fmt.Sprintf("a%d", i))
+	}
+	b.WriteString(fmt.Sprintf("%sconst solve = typeof %s === 'function' ? %s : (...args) => new Solution().%s(...args);\n", indent, sig.Name, sig.Name, sig.Name))
+	show := "__algoscalesShow"
+	if sig.Unordered {
+		show = "__algoscalesShowUnordered"
+	}
+	b.WriteString(fmt.Sprintf("%sreturn %s(solve(%s));\n", indent, show, strings.Join(callArgs, ", ")))
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTestInput(t *testing.T) {
+	assert.Equal(t, []string{"[2,7,11,15]", "9"}, splitTestInput("[2,7,11,15], 9"))
+	assert.Equal(t, []string{"[1,2]", "3"}, splitTestInput("nums = [1,2], k = 3"))
+	assert.Equal(t, []string{`"a, b"`, `'c=d'`}, splitTestInput(`"a, b", 'c=d'`))
+	assert.Equal(t, []string{`[["1","0"],["0","1"]]`}, splitTestInput(`[["1","0"],["0","1"]]`))
+	assert.Empty(t, splitTestInput(" "))
+}
+
+func TestJSONLiteral(t *testing.T) {
+	literal, err := jsonLiteral("[True, None, 'it\"s']", "")
+	require.NoError(t, err)
+	assert.Equal(t, `[true, null, "it\"s"]`, literal)
+
+	// Bare words are strings only where a string is expected
+	literal, err = jsonLiteral("hello", "string")
+	require.NoError(t, err)
+	assert.Equal(t, `"hello"`, literal)
+	_, err = jsonLiteral("[1,", "int[]")
+	assert.Error(t, err)
+}
+
+func TestCanonicalValue(t *testing.T) {
+	assert.Equal(t, "[1,3]", canonicalValue("[1, 3]"))
+	assert.Equal(t, "[[1,1],[0]]", canonicalValue(`[["1", "1"], ["0"]]`))
+	assert.Equal(t, "true", canonicalValue("True"))
+	assert.Equal(t, "a b", canonicalValue(`"a b"`))
+	assert.Equal(t, "not json", canonicalValue(" not json "))
+}
+
+func TestExpectedValue(t *testing.T) {
+	unordered := &interfaces.FunctionSignature{Name: "kClosest", Returns: "int[][]", Unordered: true}
+	assert.Equal(t, "[[-2,4],[3,3]]", expectedValue(unordered, "[[3,3],[-2,4]]"))
+	assert.Equal(t, "[[3,3],[-2,4]]", expectedValue(twoSumSignature, "[[3,3], [-2,4]]"))
+	assert.Equal(t, "3", expectedValue(unordered, "3"))
+}
+
+func TestValidateSignature(t *testing.T) {
+	assert.NoError(t, ValidateSignature(twoSumSignature))
+	assert.EqualError(t, ValidateSignature(nil), "problem has no function signature")
+
+	cycle := &interfaces.FunctionSignature{
+		Name:    "hasCycle",
+		Params:  []interfaces.Param{{Name: "pos", Type: "cycle"}},
+		Returns: "bool",
+	}
+	assert.EqualError(t, ValidateSignature(cycle), "cycle parameter pos must follow a ListNode")
+
+	unsupported := &interfaces.FunctionSignature{
+		Name:    "f",
+		Params:  []interfaces.Param{{Name: "m", Type: "map"}},
+		Returns: "int",
+	}
+	assert.EqualError(t, ValidateSignature(unsupported), "parameter m has unsupported type map")
+
+	scalar := &interfaces.FunctionSignature{Name: "f", Returns: "int", Unordered: true}
+	assert.EqualError(t, ValidateSignature(scalar), "f returns int, which cannot be unordered")
+}
+
+func TestPythonFunctionName(t *testing.T) {
+	sig := &interfaces.FunctionSignature{Name: "findMaxSumSubarray"}
+	assert.Equal(t, "find_max_sum_subarray", pythonFunctionName(sig, ""))
+	assert.Equal(t, "max_sub", pythonFunctionName(sig, "class ListNode:\n    def __init__(self):\n        pass\n\ndef max_sub(arr, k):\n    pass"))
+}
+
+func TestHarnessWithoutSignatureReportsErrors(t *testing.T) {
+	if _, err := exec.LookPath("python"); err != nil {
+		t.Skip("python not installed")
+	}
+
+	prob := &interfaces.Problem{
+		ID:        "two_sum",
+		TestCases: []interfaces.TestCase{{Input: "[2,7,11,15], 9", Expected: "[0,1]"}},
+	}
+	results, allPassed, err := NewPythonTestRunner().ExecuteTests(context.Background(), prob, "def two_sum(nums, target):\n    return [0, 1]\n", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, allPassed)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Error: problem has no function signature", results[0].Actual)
+}
+
+func TestJavaScriptHarnessCallsSolution(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not installed")
+	}
+
+	cases := []struct {
+		problem  string
+		solution string
+	}{
+		{"hash-map/two_sum.json", `function twoSum(nums, target) {
+    const seen = new Map();
+    for (let i = 0; i < nums.length; i++) {
+        if (seen.has(target - nums[i])) return [seen.get(target - nums[i]), i];
+        seen.set(nums[i], i);
+    }
+    return [];
+}`},
+		{"fast-slow-pointers/linked_list_cycle.json", `class Solution {
+    hasCycle(head) {
+        let slow = head, fast = head;
+        while (fast && fast.next) {
+            slow = slow.next;
+            fast = fast.next.next;
+            if (slow === fast) return true;
+        }
+        return false;
+    }
+}`},
+		{"bfs/level_order_traversal.json", `function levelOrder(root) {
+    const levels = [];
+    let level = root ? [root] : [];
+    while (level.length) {
+        levels.push(level.map((n) => n.val));
+        level = level.flatMap((n) => [n.left, n.right]).filter(Boolean);
+    }
+    return levels;
+}`},
+		{"dfs/number_of_islands.json", `function numIslands(grid) {
+    let count = 0;
+    const sink = (r, c) => {
+        if (r < 0 || c < 0 || r >= grid.length || c >= grid[0].length || grid[r][c] !== '1') return;
+        grid[r][c] = '0';
+        sink(r + 1, c); sink(r - 1, c); sink(r, c + 1); sink(r, c - 1);
+    };
+    grid.forEach((row, r) => row.forEach((cell, c) => { if (cell === '1') { count++; sink(r, c); } }));
+    return count;
+}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.problem, func(t *testing.T) {
+			prob := loadProblemFile(t, tc.problem)
+			results, allPassed, err := NewJavaScriptTestRunner().ExecuteTests(context.Background(), prob, tc.solution, time.Minute)
+			require.NoError(t, err)
+			assert.True(t, allPassed, "%+v", results)
+		})
+	}
+}
+
+func TestReferenceSolutionsPass(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "problems", "*", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	runners := map[string]interfaces.TestRunner{
+		"python": NewPythonTestRunner(),
+		"go":     NewGoTestRunner(),
+	}
+	tools := map[string]string{"python": "python", "go": "go"}
+
+	for language, runner := range runners {
+		if _, err := exec.LookPath(tools[language]); err != nil {
+			continue
+		}
+		for _, file := range files {
+			rel, _ := filepath.Rel(filepath.Join("..", "..", "..", "problems"), file)
+			t.Run(language+"/"+rel, func(t *testing.T) {
+				prob := loadProblemFile(t, rel)
+				solution := prob.StarterCode["solution:"+language]
+				if solution == "" {
+					t.Skip("no reference solution")
+				}
+				results, allPassed, err := runner.ExecuteTests(context.Background(), prob, solution, time.Minute)
+				require.NoError(t, err)
+				assert.True(t, allPassed, "%+v", results)
+			})
+		}
+	}
+}
+
+// loadProblemFile reads a problem from the problems directory. Its reference
+// solutions are returned under "solution:<language>" starter code keys.
+func loadProblemFile(t *testing.T, rel string) *interfaces.Problem {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "problems", rel))
+	require.NoError(t, err)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(data, &p))
+
+	prob := &interfaces.Problem{
+		ID:          p.ID,
+		StarterCode: map[string]string{},
+		Signature:   p.Signature,
+	}
+	for language, code := range p.StarterCode {
+		prob.StarterCode[language] = code
+	}
+	for language, code := range p.Solutions {
+		prob.StarterCode["solution:"+language] = code
+	}
+	for _, tc := range p.TestCases {
+		prob.TestCases = append(prob.TestCases, interfaces.TestCase{Input: tc.Input, Expected: tc.Expected})
+	}
+	return prob
+}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
-	"strings"
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
@@ -37,69 +36,6 @@ func (b *BaseTestRunner) GetLanguage() string {
 	return b.language
 }
 
-// parseTestOutput parses the test output to extract results
-func parseTestOutput(output string, testCases []interfaces.TestCase) []interfaces.TestResult {
-	// Create results array
-	results := make([]interfaces.TestResult, len(testCases))
-	
-	// Initialize with basic information
-	for i, tc := range testCases {
-		results[i] = interfaces.TestResult{
-			Input:    tc.Input,
-			Expected: tc.Expected,
-			Actual:   "No output captured",
-			Passed:   false,
-		}
-	}
-	
-	// Try to parse the output to get actual results
-	// This is a simple parser that looks for test numbers and PASSED/FAILED markers
-	lines := strings.Split(output, "\n")
-	currentTest := -1
-	
-	for _, line := range lines {
-		// Check if this is a test header line
-		if strings.HasPrefix(line, "Test ") {
-			testNumStr := strings.TrimPrefix(line, "Test ")
-			var testNum int
-			_, err := fmt.Sscanf(testNumStr, "%d", &testNum)
-			if err == nil && testNum > 0 && testNum <= len(results) {
-				currentTest = testNum - 1
-			}
-			continue
-		}
-		
-		// If we have a current test, look for PASSED/FAILED
-		if currentTest >= 0 && currentTest < len(results) {
-			if strings.Contains(line, "✅ PASSED") {
-				results[currentTest].Passed = true
-				results[currentTest].Actual = results[currentTest].Expected // Assume correct if passed
-			} else if strings.Contains(line, "❌ FAILED") {
-				results[currentTest].Passed = false
-				// Try to extract the actual output
-				if idx := strings.Index(line, "Got: "); idx >= 0 {
-					results[currentTest].Actual = strings.TrimSpace(line[idx+5:])
-				}
-			} else if strings.HasPrefix(line, "Got: ") {
-				results[currentTest].Actual = strings.TrimPrefix(line, "Got: ")
-			}
-		}
-	}
-	
-	return results
-}
-
-// addErrorToResults adds error messages to failed test results
-func addErrorToResults(results []interfaces.TestResult, errorMsg string) []interfaces.TestResult {
-	// Add error message to all failed tests
-	for i := range results {
-		if !results[i].Passed {
-			results[i].Actual = fmt.Sprintf("Error: %s", errorMsg)
-		}
-	}
-	return results
-}
-
 // allTestsPassed checks if all tests passed
 func allTestsPassed(results []interfaces.TestResult) bool {
 	for _, r := range results {
@@ -2,11 +2,18 @@ package execution
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 	
 	"github.com/stretchr/testify/assert"
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunnerRegistry(t *testing.T) {
@@ -18,6 +25,10 @@ func TestRunnerRegistry(t *testing.T) {
 	assert.Contains(t, langs, "go")
 	assert.Contains(t, langs, "python")
 	assert.Contains(t, langs, "javascript")
+	assert.Contains(t, langs, "rust")
+	assert.Contains(t, langs, "java")
+	assert.Contains(t, langs, "cpp")
+	assert.Contains(t, langs, "typescript")
 	
 	// Get runner for each language
 	goRunner, err := registry.GetRunner("go")
@@ -32,6 +43,14 @@ func TestRunnerRegistry(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "javascript", jsRunner.GetLanguage())
 	
+	cppRunner, err := registry.GetRunner("c++")
+	assert.NoError(t, err)
+	assert.Equal(t, "cpp", cppRunner.GetLanguage())
+
+	tsRunner, err := registry.GetRunner("ts")
+	assert.NoError(t, err)
+	assert.Equal(t, "typescript", tsRunner.GetLanguage())
+	
 	// Try getting a non-existent runner
 	_, err = registry.GetRunner("nonexistent")
 	assert.Error(t, err)
@@ -105,15 +124,14 @@ func TestMockTestRunner(t *testing.T) {
 }
 
 func TestHelperFunctions(t *testing.T) {
-	// Test parsing test output
-	testOutput := `Test 1
-✅ PASSED
-Test 2
-❌ FAILED
-Expected: result2
-Got: wrong
-Test 3
-✅ PASSED`
+	// Test reading a result file
+	resultsFile := filepath.Join(t.TempDir(), resultsFileName)
+	report := `{"tests":[
+		{"test":1,"status":"PASS","actual":"result1","durationMs":1.5},
+		{"test":2,"status":"FAIL","actual":"wrong","durationMs":0.25,"stderr":"debug output\n"},
+		{"test":9,"status":"PASS"}
+	]}`
+	assert.NoError(t, os.WriteFile(resultsFile, []byte(report), 0644))
 
 	testCases := []interfaces.TestCase{
 		{Input: "input1", Expected: "result1"},
@@ -121,17 +139,21 @@ Test 3
 		{Input: "input3", Expected: "result3"},
 	}
 	
-	results := parseTestOutput(testOutput, testCases)
+	results := readResults(resultsFile, testCases, errors.New("exit status 1"), "")
 	assert.Len(t, results, 3)
 	assert.True(t, results[0].Passed)
+	assert.Equal(t, 1500*time.Microsecond, results[0].Duration)
 	assert.False(t, results[1].Passed)
 	assert.Equal(t, "wrong", results[1].Actual)
-	assert.True(t, results[2].Passed)
+	assert.Equal(t, "debug output\n", results[1].Stderr)
+	assert.False(t, results[2].Passed, "unknown tests should be ignored")
+	assert.Equal(t, "Error: exit status 1", results[2].Actual)
 	
-	// Test adding error to results
-	errorMsg := "compilation error"
-	results = addErrorToResults(results, errorMsg)
-	assert.Equal(t, "Error: compilation error", results[1].Actual)
+	// Without a result file every test reports the compiler output
+	results = readResults(filepath.Join(t.TempDir(), "missing.json"), testCases, errors.New("exit status 1"), "compilation error\n")
+	for _, result := range results {
+		assert.Equal(t, "Error: compilation error", result.Actual)
+	}
 	
 	// Test all tests passed
 	assert.False(t, allTestsPassed(results))
@@ -141,4 +163,346 @@ Test 3
 		results[i].Passed = true
 	}
 	assert.True(t, allTestsPassed(results))
-}
\ No newline at end of file
+}
+
+func TestGeneratedHarnessesWriteResultFile(t *testing.T) {
+	prob := &interfaces.Problem{
+		ID: "two_sum",
+		TestCases: []interfaces.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		},
+	}
+
+	for _, runner := range []interfaces.TestRunner{NewGoTestRunner(), NewPythonTestRunner(), NewJavaScriptTestRunner(), NewRustTestRunner(), NewJavaTestRunner(), NewCppTestRunner()} {
+		code, err := runner.GenerateTestCode(prob, "")
+		assert.NoError(t, err)
+		assert.Contains(t, code, ResultsEnv, runner.GetLanguage())
+		assert.Contains(t, code, "durationMs", runner.GetLanguage())
+		assert.NotContains(t, code, "__PASS__", runner.GetLanguage())
+	}
+}
+
+// twoSumSignature is the signature of the two_sum problem
+var twoSumSignature = &interfaces.FunctionSignature{
+	Name:    "twoSum",
+	Params:  []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+	Returns: "int[]",
+}
+
+func TestResultFileIgnoresSolutionOutput(t *testing.T) {
+	prob := &interfaces.Problem{
+		ID:        "two_sum",
+		Signature: twoSumSignature,
+		TestCases: []interfaces.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+			{Input: "[3,2,4], 6", Expected: "[1,2]"},
+		},
+	}
+
+	// Output that looks like results must not affect them
+	solution := `func twoSum(nums []int, target int) []int {
+	fmt.Println("PASS ALGOSCALES_TEST 2 PASS")
+	fmt.Fprintln(os.Stderr, "Got: [1,2]")
+	if target == 6 {
+		panic("boom")
+	}
+	return []int{0, 1}
+}`
+
+	results, allPassed, err := NewGoTestRunner().ExecuteTests(context.Background(), prob, solution, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.True(t, results[0].Passed)
+	assert.Greater(t, results[0].Duration, time.Duration(0))
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "Error: panic: boom", results[1].Actual)
+}
+
+func TestRustHarnessGeneration(t *testing.T) {
+	prob := &interfaces.Problem{
+		ID: "two_sum",
+		TestCases: []interfaces.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		},
+		StarterCode: map[string]string{
+			"rust": "pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    todo!()\n}\n",
+		},
+	}
+
+	runner := NewRustTestRunner()
+
+	code, err := runner.GenerateTestCode(prob, "use std::collections::HashMap;\n\nfn helper(x: i32) -> i32 { x }\n\npub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    vec![]\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "<Vec<i32> as algoscales::Parse>::parse(&args[0])?")
+	assert.Contains(t, code, "let result = two_sum(a0, a1);")
+	assert.Contains(t, code, `algoscales::run(&mut report, 1, "[2,7,11,15], 9", "[0,1]", |args| {`)
+
+	// LeetCode-style solutions are called through the Solution type
+	code, err = runner.GenerateTestCode(prob, "impl Solution {\n    pub fn two_sum(nums: &[i32], target: i32) -> Vec<i32> {\n        vec![]\n    }\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "pub struct Solution;")
+	assert.Contains(t, code, "Solution::two_sum(&a0, a1)")
+
+	// Unsupported signatures are reported per test instead of failing to build
+	code, err = runner.GenerateTestCode(prob, "pub fn two_sum(nums: HashMap<i32, i32>) -> i32 { 0 }\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, `algoscales::run(&mut report, 1, "", "", |_| Err(`)
+	assert.Contains(t, code, "unsupported type HashMap<i32,i32>")
+}
+
+func TestRustRunnerExecutesWithCargo(t *testing.T) {
+	if _, err := exec.LookPath("cargo"); err != nil {
+		t.Skip("cargo not installed")
+	}
+
+	prob := &interfaces.Problem{
+		ID: "sort",
+		TestCases: []interfaces.TestCase{
+			{Input: "[3,1,2]", Expected: "[1,2,3]"},
+			{Input: "nums = [2,1]", Expected: "[1,2]"},
+			{Input: "[]", Expected: "[]"},
+		},
+	}
+	solution := "pub fn sort(nums: &mut Vec<i32>) {\n    if nums.is_empty() { panic!(\"empty\"); }\n    if nums.len() > 2 { nums.sort(); }\n}\n"
+
+	runner := NewRustTestRunner().WithTargetDir(t.TempDir())
+	results, allPassed, err := runner.ExecuteTests(context.Background(), prob, solution, 2*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "[2,1]", results[1].Actual)
+	assert.False(t, results[2].Passed)
+	assert.Equal(t, "Error: panicked: empty", results[2].Actual)
+}
+
+func TestJavaHarnessGeneration(t *testing.T) {
+	prob := &interfaces.Problem{
+		ID: "two_sum",
+		TestCases: []interfaces.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+			{Input: "[3,2,4], 6", Expected: "[1,2]"},
+		},
+		StarterCode: map[string]string{
+			"java": "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        return new int[0];\n    }\n}\n",
+		},
+	}
+
+	runner := NewJavaTestRunner()
+
+	solution := "import java.util.HashMap;\n\npublic class Solution {\n    private int helper(int x) { return x; }\n\n    public int[] twoSum(int[] nums, int target) {\n        return new int[0];\n    }\n}\n"
+	code, err := runner.GenerateTestCode(prob, solution)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(code, "import java.util.*;\nimport java.util.HashMap;\n"))
+	assert.Contains(t, code, "\nclass Solution {")
+	assert.Contains(t, code, "public class AlgoScalesTest {")
+	assert.Contains(t, code, `int[] a0 = (int[]) AlgoScales.parse(args.get(0), "int[]");`)
+	assert.Contains(t, code, "return AlgoScales.show(new Solution().twoSum(a0, a1));")
+	assert.Contains(t, code, `report.assertEquals(2, "[3,2,4], 6", "[1,2]", AlgoScalesTest::invoke);`)
+
+	// Static methods are called through their class
+	code, err = runner.GenerateTestCode(prob, "class Solution {\n    static void sort(List<Integer> xs) {\n    }\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "Solution.sort(a0);")
+	assert.Contains(t, code, "return AlgoScales.show(a0);")
+
+	// Unsupported signatures are reported per test instead of failing to compile
+	code, err = runner.GenerateTestCode(prob, "class Solution {\n    int count(Map<String, Integer> m) { return 0; }\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "throw new IllegalArgumentException(")
+	assert.Contains(t, code, "unsupported type Map<String,Integer>")
+}
+
+func TestMapJavacOutput(t *testing.T) {
+	prob := &interfaces.Problem{ID: "two_sum", TestCases: []interfaces.TestCase{{Input: "[1], 1", Expected: "[]"}}}
+	code, err := NewJavaTestRunner().GenerateTestCode(prob, "import java.util.*;\n\nclass Solution {\n    int[] twoSum(int[] nums, int target) {\n        return nums\n    }\n}\n")
+	assert.NoError(t, err)
+
+	// Line 5 of the solution, after the hoisted import and the marker
+	first, _ := javaSolutionLines(code)
+	line := first + 4
+	output := fmt.Sprintf("AlgoScalesTest.java:%d: error: ';' expected\nAlgoScalesTest.java:99: error: cannot find symbol", line)
+	mapped := mapJavacOutput(output, code)
+	assert.Contains(t, mapped, "Solution.java:5: error: ';' expected")
+	assert.Contains(t, mapped, "AlgoScalesTest.java:99: error: cannot find symbol")
+}
+
+func TestJavaRunnerExecutesWithJavac(t *testing.T) {
+	if _, err := exec.LookPath("javac"); err != nil {
+		t.Skip("javac not installed")
+	}
+
+	prob := &interfaces.Problem{
+		ID: "sort",
+		TestCases: []interfaces.TestCase{
+			{Input: "[3,1,2]", Expected: "[1,2,3]"},
+			{Input: "nums = [2,1]", Expected: "[1,2]"},
+			{Input: "[]", Expected: "[]"},
+		},
+	}
+	solution := "import java.util.Arrays;\n\npublic class Solution {\n    public void sort(int[] nums) {\n        if (nums.length == 0) throw new IllegalStateException(\"empty\");\n        if (nums.length > 2) Arrays.sort(nums);\n    }\n}\n"
+
+	runner := NewJavaTestRunner()
+	results, allPassed, err := runner.ExecuteTests(context.Background(), prob, solution, 2*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "[2,1]", results[1].Actual)
+	assert.Equal(t, "Error: IllegalStateException: empty (Solution.java:5)", results[2].Actual)
+
+	// Compiler errors point at the solution's own lines
+	results, allPassed, err = runner.ExecuteTests(context.Background(), prob, "class Solution {\n    void sort(int[] nums) {\n        nums.sortt();\n    }\n}\n", 2*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.Contains(t, results[0].Actual, "Solution.java:3: error: cannot find symbol")
+}
+
+func TestCppHarnessGeneration(t *testing.T) {
+	prob := &interfaces.Problem{
+		ID: "two_sum",
+		TestCases: []interfaces.TestCase{
+			{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		},
+	}
+
+	runner := NewCppTestRunner()
+
+	code, err := runner.GenerateTestCode(prob, "class Solution {\npublic:\n    vector<int> twoSum(const std::vector<int> &nums, int target) {\n        return {};\n    }\n};\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "#line 1 \"solution.cpp\"\nclass Solution {")
+	assert.Contains(t, code, "std::vector<int> a0 = algoscales::parse_as<std::vector<int>>(args[0]);")
+	assert.Contains(t, code, "return algoscales::show(Solution().twoSum(a0, a1));")
+	assert.Contains(t, code, `algoscales::run(report, 1, "[2,7,11,15], 9", "[0,1]", [](const algoscales::Args& args) -> std::string {`)
+
+	// Free functions are called directly and a main in the solution is renamed
+	code, err = runner.GenerateTestCode(prob, "void sortColors(vector<int>& nums) {\n}\n\nint main() {\n    return 0;\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "sortColors(a0);\n        return algoscales::show(a0);")
+	assert.Contains(t, code, "int _solution_main(")
+
+	// Unsupported signatures are reported per test instead of failing to compile
+	code, err = runner.GenerateTestCode(prob, "ListNode* reverse(ListNode* head) {\n    return head;\n}\n")
+	assert.NoError(t, err)
+	assert.Contains(t, code, "throw std::invalid_argument(")
+	assert.Contains(t, code, "unsupported type ListNode*")
+}
+
+func TestSummarizeCppErrors(t *testing.T) {
+	output := `solution.cpp: In member function 'int Solution::f(int)':
+solution.cpp:3:16: error: expected ';' before '}' token
+    3 |         return x
+      |                ^
+solution.cpp:2:9: warning: unused variable 'y' [-Wunused-variable]
+main.cpp:12:1: fatal error: too many errors`
+
+	assert.Equal(t, "solution.cpp:3:16: error: expected ';' before '}' token\nmain.cpp:12:1: fatal error: too many errors", summarizeCppErrors(output))
+
+	// Output without diagnostics, such as linker errors, is kept as is
+	linker := "/usr/bin/ld: undefined reference to `helper(int)'"
+	assert.Equal(t, linker, summarizeCppErrors(linker))
+}
+
+func TestCppRunnerExecutesWithCompiler(t *testing.T) {
+	runner := NewCppTestRunner()
+	if _, err := exec.LookPath(runner.compiler); err != nil {
+		t.Skip("no C++ compiler installed")
+	}
+
+	prob := &interfaces.Problem{
+		ID: "sort",
+		TestCases: []interfaces.TestCase{
+			{Input: "[3,1,2]", Expected: "[1,2,3]"},
+			{Input: "nums = [2,1]", Expected: "[1,2]"},
+			{Input: "[]", Expected: "[]"},
+		},
+	}
+	solution := "void sortNums(vector<int>& nums) {\n    cerr << \"size \" << nums.size() << endl;\n    if (nums.empty()) throw runtime_error(\"empty\");\n    if (nums.size() > 2) sort(nums.begin(), nums.end());\n}\n"
+
+	results, allPassed, err := runner.ExecuteTests(context.Background(), prob, solution, 2*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "size 3\n", results[0].Stderr)
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "[2,1]", results[1].Actual)
+	assert.Equal(t, "Error: empty", results[2].Actual)
+
+	// Compiler errors point at the solution's own lines
+	results, allPassed, err = runner.ExecuteTests(context.Background(), prob, "void sortNums(vector<int>& nums) {\n    int x = 1\n}\n", 2*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, allPassed)
+	assert.Contains(t, results[0].Actual, "Error: solution.cpp:3:")
+}
+
+func TestSummarizeTscErrors(t *testing.T) {
+	output := `solution.ts(2,5): error TS2322: Type 'string' is not assignable to type 'number'.
+solution.ts(4,12): error TS2304: Cannot find name 'foo'.
+`
+	assert.Equal(t, "solution.ts(2,5): error TS2322: Type 'string' is not assignable to type 'number'.\nsolution.ts(4,12): error TS2304: Cannot find name 'foo'.", summarizeTscErrors(output))
+
+	// Other output, such as a bad flag, is kept as is
+	assert.Equal(t, "error TS5023: Unknown compiler option '--foo'.", summarizeTscErrors("error TS5023: Unknown compiler option '--foo'.\n"))
+}
+
+// fakeTool installs an executable shell script on the PATH for the test
+func fakeTool(t *testing.T, dir, name, script string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755))
+}
+
+func TestTypeScriptRunnerReportsTypeErrors(t *testing.T) {
+	dir := t.TempDir()
+	fakeTool(t, dir, "tsc", "echo \"solution.ts(2,5): error TS2322: Type 'string' is not assignable to type 'number'.\"\nexit 2\n")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	prob := &interfaces.Problem{
+		ID:        "add",
+		TestCases: []interfaces.TestCase{{Input: "1, 2", Expected: "3"}, {Input: "2, 2", Expected: "4"}},
+	}
+	solution := "function add(a: number, b: number): number {\n    return \"oops\";\n}\n"
+
+	results, allPassed, err := NewTypeScriptTestRunner().ExecuteTests(context.Background(), prob, solution, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, allPassed)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, interfaces.FailureTypeCheck, result.Failure)
+		assert.Equal(t, "Error: solution.ts(2,5): error TS2322: Type 'string' is not assignable to type 'number'.", result.Actual)
+	}
+}
+
+func TestTypeScriptRunnerRunsAfterTypeCheck(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not installed")
+	}
+
+	// A passing type check, and an esbuild that copies the already
+	// annotation-free test file
+	dir := t.TempDir()
+	fakeTool(t, dir, "tsc", "exit 0\n")
+	fakeTool(t, dir, "esbuild", "for arg in \"$@\"; do case $arg in --outfile=*) out=${arg#--outfile=};; esac; done\ncp \"$1\" \"$out\"\n")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	prob := &interfaces.Problem{
+		ID:        "add",
+		TestCases: []interfaces.TestCase{{Input: "1, 2", Expected: "3"}, {Input: "2, 2", Expected: "5"}},
+		Signature: &interfaces.FunctionSignature{
+			Name:    "add",
+			Params:  []interfaces.Param{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}},
+			Returns: "int",
+		},
+	}
+
+	results, allPassed, err := NewTypeScriptTestRunner().WithRuntime("esbuild").ExecuteTests(context.Background(), prob, "function add(a, b) { return a + b; }\n", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, allPassed)
+	require.Len(t, results, 2)
+
+	// The harness ran, so a failure is a wrong answer rather than a type error
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, interfaces.FailureWrongAnswer, results[1].Failure)
+}
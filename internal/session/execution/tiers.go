@@ -0,0 +1,66 @@
+// Test tier selection for test runs and submissions
+
+package execution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// ExampleTests returns a copy of the problem with only its example and user
+// tests, which is what a test run executes; a submission executes every
+// tier. A problem without example tests keeps all of them, so a test run
+// always has something of the problem's own to run.
+func ExampleTests(prob *interfaces.Problem) *interfaces.Problem {
+	var examples []interfaces.TestCase
+	found := false
+	for _, tc := range prob.TestCases {
+		switch tc.Tier.OrDefault() {
+		case interfaces.TierExample:
+			found = true
+			examples = append(examples, tc)
+		case interfaces.TierUser:
+			examples = append(examples, tc)
+		}
+	}
+	if !found {
+		return prob
+	}
+
+	selected := *prob
+	selected.TestCases = examples
+	return &selected
+}
+
+// UnrunTests counts the tests a test run leaves for submission
+func UnrunTests(prob *interfaces.Problem) int {
+	return len(prob.TestCases) - len(ExampleTests(prob).TestCases)
+}
+
+// TierSummary reports how many tests of each tier passed, e.g.
+// "example 3/3, hidden 4/5, edge 2/2". It is empty when every test is an
+// example, since the overall count then says the same.
+func TierSummary(results []interfaces.TestResult) string {
+	passed := make(map[interfaces.TestTier]int)
+	total := make(map[interfaces.TestTier]int)
+	for _, r := range results {
+		tier := r.Tier.OrDefault()
+		total[tier]++
+		if r.Passed {
+			passed[tier]++
+		}
+	}
+	if len(total) == 0 || len(total) == 1 && total[interfaces.TierExample] > 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, tier := range append(interfaces.Tiers, interfaces.TierUser) {
+		if total[tier] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", tier, passed[tier], total[tier]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
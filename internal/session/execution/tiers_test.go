@@ -0,0 +1,45 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExampleTests(t *testing.T) {
+	prob := &interfaces.Problem{ID: "two_sum", TestCases: []interfaces.TestCase{
+		{Input: "a"},
+		{Input: "b", Tier: interfaces.TierHidden},
+		{Input: "c", Tier: interfaces.TierExample},
+		{Input: "d", Tier: interfaces.TierStress},
+		{Input: "e", Tier: interfaces.TierUser},
+	}}
+
+	examples := ExampleTests(prob)
+	assert.Equal(t, "two_sum", examples.ID)
+	assert.Equal(t, []interfaces.TestCase{{Input: "a"}, {Input: "c", Tier: interfaces.TierExample}, {Input: "e", Tier: interfaces.TierUser}}, examples.TestCases)
+	assert.Len(t, prob.TestCases, 5, "the problem itself is unchanged")
+	assert.Equal(t, 2, UnrunTests(prob))
+
+	// Without examples, a test run runs everything, user tests included
+	hidden := &interfaces.Problem{TestCases: []interfaces.TestCase{{Input: "b", Tier: interfaces.TierHidden}, {Input: "e", Tier: interfaces.TierUser}}}
+	assert.Same(t, hidden, ExampleTests(hidden))
+	assert.Equal(t, 0, UnrunTests(hidden))
+}
+
+func TestTierSummary(t *testing.T) {
+	assert.Empty(t, TierSummary(nil))
+	assert.Empty(t, TierSummary([]interfaces.TestResult{{Passed: true}, {Tier: interfaces.TierExample}}))
+
+	results := []interfaces.TestResult{
+		{Passed: true},
+		{Tier: interfaces.TierEdge, Passed: true},
+		{Tier: interfaces.TierHidden},
+		{Tier: interfaces.TierHidden, Passed: true},
+	}
+	assert.Equal(t, "example 1/1, hidden 1/2, edge 1/1", TierSummary(results))
+
+	results = append(results, interfaces.TestResult{Tier: interfaces.TierUser})
+	assert.Equal(t, "example 1/1, hidden 1/2, edge 1/1, user 0/1", TierSummary(results))
+}
@@ -0,0 +1,49 @@
+// Per-test timing helpers shared by the test result views
+
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+)
+
+// DefaultSlowTestThreshold flags tests slow enough to suggest a pathological
+// input or an accidentally quadratic solution
+const DefaultSlowTestThreshold = time.Second
+
+// SlowTestThreshold is the duration above which a test is highlighted as slow
+var SlowTestThreshold = DefaultSlowTestThreshold
+
+// IsSlow reports whether a test took longer than SlowTestThreshold
+func IsSlow(d time.Duration) bool {
+	return d > SlowTestThreshold
+}
+
+// FormatDuration formats a test duration compactly, e.g. "850µs", "12ms" or "1.24s"
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		if symbols.ASCII() {
+			return fmt.Sprintf("%dus", d.Microseconds())
+		}
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
+// Slowest returns the index of the longest duration, or -1 when none of the
+// tests were timed
+func Slowest(durations []time.Duration) int {
+	slowest := -1
+	for i, d := range durations {
+		if d > 0 && (slowest < 0 || d > durations[slowest]) {
+			slowest = i
+		}
+	}
+	return slowest
+}
@@ -0,0 +1,30 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDuration(t *testing.T) {
+	symbols.SetASCII(true)
+	defer symbols.SetASCII(false)
+
+	assert.Equal(t, "850us", FormatDuration(850*time.Microsecond))
+	assert.Equal(t, "12ms", FormatDuration(12*time.Millisecond+400*time.Microsecond))
+	assert.Equal(t, "1.24s", FormatDuration(1240*time.Millisecond))
+}
+
+func TestSlowTests(t *testing.T) {
+	defer func() { SlowTestThreshold = DefaultSlowTestThreshold }()
+
+	SlowTestThreshold = 100 * time.Millisecond
+	assert.False(t, IsSlow(100*time.Millisecond))
+	assert.True(t, IsSlow(101*time.Millisecond))
+
+	assert.Equal(t, 1, Slowest([]time.Duration{time.Millisecond, time.Second, 0}))
+	assert.Equal(t, -1, Slowest([]time.Duration{0, 0}))
+	assert.Equal(t, -1, Slowest(nil))
+}
@@ -0,0 +1,184 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/logging"
+)
+
+// tsSolutionFile is the file the solution is type-checked as, so
+// diagnostics refer to the solution's own lines
+const tsSolutionFile = "solution.ts"
+
+// tscFlags type-check a lone solution file without emitting anything. The
+// dom library declares console, since @types/node may not be installed.
+var tscFlags = []string{"--noEmit", "--strict", "--pretty", "false", "--target", "es2022", "--lib", "es2022,dom", "--skipLibCheck"}
+
+// tscDiagnosticPattern matches a tsc error such as
+// solution.ts(3,5): error TS2322: Type 'string' is not assignable to type 'number'.
+var tscDiagnosticPattern = regexp.MustCompile(`(?m)^.*?\(\d+,\d+\): error TS\d+: .*$`)
+
+// tsRuntimes run type-checked TypeScript, tried in order when none is configured
+var tsRuntimes = []string{"esbuild", "ts-node"}
+
+// TypeScriptTestRunner implements the TestRunner interface for TypeScript
+// code. Solutions are type-checked with tsc before they run, so type errors
+// are reported separately from wrong answers.
+type TypeScriptTestRunner struct {
+	BaseTestRunner
+	runtime string
+}
+
+// NewTypeScriptTestRunner creates a new TypeScript test runner using the
+// first runtime found on the PATH
+func NewTypeScriptTestRunner() *TypeScriptTestRunner {
+	runtime := tsRuntimes[0]
+	for _, candidate := range tsRuntimes {
+		if _, err := exec.LookPath(candidate); err == nil {
+			runtime = candidate
+			break
+		}
+	}
+
+	return &TypeScriptTestRunner{
+		BaseTestRunner: NewBaseTestRunner("typescript"),
+		runtime:        runtime,
+	}
+}
+
+// WithRuntime sets how type-checked code is run: "esbuild" bundles it for
+// node, "ts-node" runs it directly
+func (r *TypeScriptTestRunner) WithRuntime(runtime string) *TypeScriptTestRunner {
+	r.runtime = runtime
+	return r
+}
+
+// ExecuteTests type-checks a TypeScript solution and runs its tests
+func (r *TypeScriptTestRunner) ExecuteTests(ctx context.Context, prob *interfaces.Problem, code string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+	// Create a context with timeout for the entire operation
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Add logging context
+	ctx = logging.WithOperation(ctx, "ExecuteTypeScriptTests")
+	ctx = logging.WithComponent(ctx, "TypeScriptTestRunner")
+	logger := logging.TestRunnerLogger.WithContext(ctx)
+
+	// Create session snapshot for error logging
+	sessionState := &logging.SessionSnapshot{
+		ProblemID:  prob.ID,
+		Language:   "typescript",
+		Mode:       "test_execution",
+		UserCode:   code,
+		StartTime:  time.Now(),
+		Patterns:   prob.Tags,
+		Difficulty: prob.Difficulty,
+		CustomFields: map[string]string{
+			"timeout":    timeout.String(),
+			"test_count": fmt.Sprintf("%d", len(prob.TestCases)),
+			"runtime":    r.runtime,
+		},
+	}
+
+	// Log operation start
+	finishLog := logger.StartOperation(fmt.Sprintf("Execute TypeScript tests for problem %s", prob.ID))
+	defer func() {
+		if r := recover(); r != nil {
+			if logging.GlobalErrorLogger != nil {
+				logging.GlobalErrorLogger.LogPanic(ctx, r, "execute_typescript_tests", sessionState)
+			}
+			finishLog(fmt.Errorf("panic: %v", r))
+			panic(r)
+		}
+	}()
+
+	// Create a temporary directory for test execution
+	testDir, err := os.MkdirTemp("", "algo-scales-ts-test")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(testDir) // Clean up when done
+
+	resultsFile := filepath.Join(testDir, resultsFileName)
+
+	// Type-check the solution on its own; the harness is plain JavaScript
+	if err := os.WriteFile(filepath.Join(testDir, tsSolutionFile), []byte(code), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write solution file: %v", err)
+	}
+
+	check := exec.CommandContext(ctx, "tsc", append(tscFlags, tsSolutionFile)...)
+	check.Dir = testDir
+	stdout, stderr, err := runCommandWithTimeout(check, timeout)
+	if err != nil {
+		results := readResults(resultsFile, prob.TestCases, err, summarizeTscErrors(stdout.String()+stderr.String()))
+
+		// A missing tsc is not the solution's fault
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			results = withFailure(results, interfaces.FailureTypeCheck)
+		}
+		return results, false, nil
+	}
+
+	// Generate test code
+	testCode, err := r.GenerateTestCode(prob, code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate test code: %v", err)
+	}
+
+	testFile := filepath.Join(testDir, "test_solution.ts")
+	if err := os.WriteFile(testFile, []byte(testCode), 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write test file: %v", err)
+	}
+
+	var cmd *exec.Cmd
+	switch r.runtime {
+	case "ts-node":
+		// Types were checked above, so only transpile
+		cmd = ActiveSandbox.command(ctx, "typescript", testDir, len(prob.TestCases), "ts-node", "--transpile-only", testFile)
+	default:
+		bundle := filepath.Join(testDir, "test_solution.js")
+		build := exec.CommandContext(ctx, r.runtime, testFile, "--outfile="+bundle, "--platform=node", "--format=cjs", "--log-level=error")
+		build.Dir = testDir
+		_, stderr, err := runCommandWithTimeout(build, timeout)
+		if err != nil {
+			results := readResults(resultsFile, prob.TestCases, err, stderr.String())
+			return withFailure(results, interfaces.FailureCompile), false, nil
+		}
+		cmd = ActiveSandbox.command(ctx, "typescript", testDir, len(prob.TestCases), "node", bundle)
+	}
+
+	// Run the test in the sandbox and read the results the harness wrote
+	results, _ := runHarness(cmd, timeout, resultsFile, prob.TestCases)
+
+	return results, allTestsPassed(results), nil
+}
+
+// GenerateTestCode creates the test program for a TypeScript solution. The
+// harness is shared with JavaScript, since type annotations are stripped
+// before it runs.
+func (r *TypeScriptTestRunner) GenerateTestCode(prob *interfaces.Problem, solutionCode string) (string, error) {
+	return NewJavaScriptTestRunner().GenerateTestCode(prob, solutionCode)
+}
+
+// summarizeTscErrors keeps only the error lines of tsc output, falling back
+// to the raw output when none are recognized
+func summarizeTscErrors(output string) string {
+	diagnostics := tscDiagnosticPattern.FindAllString(output, -1)
+	if len(diagnostics) == 0 {
+		return strings.TrimSpace(output)
+	}
+	for i, d := range diagnostics {
+		diagnostics[i] = strings.TrimSpace(d)
+	}
+	return strings.Join(diagnostics, "\n")
+}
@@ -76,6 +76,7 @@ func (f *ProblemFormatterImpl) convertToLocalProblem(p *interfaces.Problem) prob
 		testCases[i] = problem.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -89,6 +90,7 @@ func (f *ProblemFormatterImpl) convertToLocalProblem(p *interfaces.Problem) prob
 		ID:                  p.ID,
 		Title:               p.Title,
 		Description:         p.Description,
+		Signature:           p.Signature,
 		Difficulty:          p.Difficulty,
 		Patterns:            p.Tags,
 		Companies:           p.Companies,
@@ -0,0 +1,46 @@
+// Opt-in publishing of exemplary solutions to problem halls of fame
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+)
+
+// isExemplary reports whether a finished session's solution may be
+// published: it passed every test without hints or the reference solution
+func isExemplary(solved, hintsUsed, solutionUsed bool) bool {
+	return solved && !hintsUsed && !solutionUsed
+}
+
+// publishToHallOfFame publishes an exemplary solution to its problem's hall
+// of fame when the user opted in. Failures are ignored, as with peer review.
+func publishToHallOfFame(problemID, title, language, code string) {
+	cfg, err := config.LoadConfig()
+	if err != nil || !cfg.HallOfFame || strings.TrimSpace(code) == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	if _, err := api.PublishToHallOfFame(ctx, api.GalleryEntry{
+		ProblemID: problemID,
+		Language:  language,
+		Code:      codefmt.Code(language, code),
+	}); err != nil {
+		return
+	}
+
+	_ = notifications.Add(notifications.Notification{
+		Kind:      notifications.KindHallOfFame,
+		Title:     fmt.Sprintf("Your %s solution was published to the hall of fame", title),
+		Details:   []string{fmt.Sprintf("See how others solved it: algo-scales gallery %s", problemID)},
+		ProblemID: problemID,
+	})
+}
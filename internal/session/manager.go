@@ -56,6 +56,7 @@ func (m *Manager) WithTestRunnerRegistry(registry interfaces.TestRunnerRegistry)
 func (m *Manager) StartSession(ctx context.Context, opts interfaces.SessionOptions) (interfaces.Session, error) {
 	// Choose problem based on options
 	var p *problem.Problem
+	var reason string
 	var err error
 	
 	if opts.ProblemID != "" {
@@ -70,13 +71,13 @@ func (m *Manager) StartSession(ctx context.Context, opts interfaces.SessionOptio
 		p = &localProb
 	} else if opts.Mode == interfaces.CramMode {
 		// Cram mode - choose problems from common patterns
-		p, err = m.selectCramProblem(ctx)
+		p, reason, err = m.selectCramProblem(ctx, opts.Difficulty)
 		if err != nil {
 			return nil, fmt.Errorf("failed to select problem for cram mode: %v", err)
 		}
 	} else {
 		// Filter by pattern/difficulty if specified
-		p, err = m.selectProblem(ctx, opts.Pattern, opts.Difficulty)
+		p, reason, err = m.selectProblem(ctx, opts.Pattern, opts.Difficulty)
 		if err != nil {
 			return nil, fmt.Errorf("failed to select problem: %v", err)
 		}
@@ -84,6 +85,7 @@ func (m *Manager) StartSession(ctx context.Context, opts interfaces.SessionOptio
 	
 	// Initialize session
 	session := NewSessionImpl(opts, p)
+	session.Reason = reason
 	session.hintsShown = opts.Mode == interfaces.LearnMode
 	session.ShowPattern = opts.Mode == interfaces.LearnMode
 	session.WithFileSystem(m.fs)
@@ -93,6 +95,7 @@ func (m *Manager) StartSession(ctx context.Context, opts interfaces.SessionOptio
 	if err := m.createWorkspace(session); err != nil {
 		return nil, fmt.Errorf("failed to create workspace: %v", err)
 	}
+	session.persistClock()
 	
 	// Generate a session ID
 	sessionID := fmt.Sprintf("%s-%d", p.ID, time.Now().Unix())
@@ -177,8 +180,13 @@ func (m *Manager) createWorkspace(s *SessionImpl) error {
 	return nil
 }
 
-// selectProblem chooses a problem based on pattern and difficulty
-func (m *Manager) selectProblem(ctx context.Context, pattern, difficulty string) (*problem.Problem, error) {
+// selectProblem chooses a problem based on pattern and difficulty. For
+// difficulty auto it also returns why the difficulty was chosen.
+func (m *Manager) selectProblem(ctx context.Context, pattern, difficulty string) (*problem.Problem, string, error) {
+	if difficulty == DifficultyAuto {
+		return m.selectAdaptiveProblem(ctx, pattern)
+	}
+
 	// Get all problems
 	var problems []problem.Problem
 	
@@ -186,7 +194,7 @@ func (m *Manager) selectProblem(ctx context.Context, pattern, difficulty string)
 		// Filter by both pattern and difficulty
 		interfaceProbs, err := m.problemRepo.GetByPattern(ctx, pattern)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		
 		// Convert and filter by difficulty
@@ -200,37 +208,55 @@ func (m *Manager) selectProblem(ctx context.Context, pattern, difficulty string)
 		// Filter by pattern only
 		interfaceProbs, err := m.problemRepo.GetByPattern(ctx, pattern)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		problems = m.convertInterfaceProblemsToLocal(interfaceProbs)
 	} else if difficulty != "" {
 		// Filter by difficulty only
 		interfaceProbs, err := m.problemRepo.GetByDifficulty(ctx, difficulty)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		problems = m.convertInterfaceProblemsToLocal(interfaceProbs)
 	} else {
 		// No filters, get all problems
 		interfaceProbs, err := m.problemRepo.GetAll(ctx)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		problems = m.convertInterfaceProblemsToLocal(interfaceProbs)
 	}
+	problems = problem.Visible(problems)
 	
 	if len(problems) == 0 {
-		return nil, fmt.Errorf("no problems found matching criteria")
+		return nil, "", fmt.Errorf("no problems found matching criteria")
 	}
 	
 	// Select random problem
 	rand.Seed(time.Now().UnixNano())
 	selectedIndex := rand.Intn(len(problems))
-	return &problems[selectedIndex], nil
+	return &problems[selectedIndex], "", nil
+}
+
+// selectAdaptiveProblem chooses a problem of pattern at the difficulty
+// recent performance calls for, or of any difficulty when there is none
+func (m *Manager) selectAdaptiveProblem(ctx context.Context, pattern string) (*problem.Problem, string, error) {
+	choice := ChooseDifficulty(pattern)
+	p, _, err := m.selectProblem(ctx, pattern, choice.Difficulty)
+	if err == nil {
+		return p, choice.Reason, nil
+	}
+	p, _, err = m.selectProblem(ctx, pattern, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return p, fmt.Sprintf("%s, but there are none, so this one is %s", choice.Reason, p.Difficulty), nil
 }
 
-// selectCramProblem chooses a problem for cram mode
-func (m *Manager) selectCramProblem(ctx context.Context) (*problem.Problem, error) {
+// selectCramProblem chooses a problem for cram mode. Difficulty auto picks
+// the difficulty recent performance in the chosen pattern calls for, and
+// returns why.
+func (m *Manager) selectCramProblem(ctx context.Context, difficulty string) (*problem.Problem, string, error) {
 	// For cram mode, we typically want to focus on common patterns
 	// This is a simplified implementation - may be improved in the future
 	commonPatterns := []string{
@@ -247,21 +273,24 @@ func (m *Manager) selectCramProblem(ctx context.Context) (*problem.Problem, erro
 	rand.Seed(time.Now().UnixNano())
 	patternIndex := rand.Intn(len(commonPatterns))
 	selectedPattern := commonPatterns[patternIndex]
+	if difficulty == DifficultyAuto {
+		return m.selectAdaptiveProblem(ctx, selectedPattern)
+	}
 	
 	// Get problems for this pattern
 	interfaceProbs, err := m.problemRepo.GetByPattern(ctx, selectedPattern)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	patternProblems := m.convertInterfaceProblemsToLocal(interfaceProbs)
+	patternProblems := problem.Visible(m.convertInterfaceProblemsToLocal(interfaceProbs))
 	
 	if len(patternProblems) == 0 {
-		return nil, fmt.Errorf("no problems found for pattern: %s", selectedPattern)
+		return nil, "", fmt.Errorf("no problems found for pattern: %s", selectedPattern)
 	}
 	
 	// Select random problem from this pattern
 	selectedIndex := rand.Intn(len(patternProblems))
-	return &patternProblems[selectedIndex], nil
+	return &patternProblems[selectedIndex], "", nil
 }
 
 // JoinStrings joins a slice of strings with commas
@@ -286,7 +315,9 @@ func languageExtension(language string) string {
 		"javascript": "js",
 		"java":       "java",
 		"c++":        "cpp",
+		"cpp":        "cpp",
 		"typescript": "ts",
+		"rust":       "rs",
 	}
 	
 	if ext, ok := extensions[language]; ok {
@@ -304,6 +335,7 @@ func (m *Manager) convertInterfaceToLocalProblem(p interfaces.Problem) problem.P
 		testCases[i] = problem.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -317,6 +349,7 @@ func (m *Manager) convertInterfaceToLocalProblem(p interfaces.Problem) problem.P
 		ID:                  p.ID,
 		Title:               p.Title,
 		Description:         p.Description,
+		Signature:           p.Signature,
 		Difficulty:          p.Difficulty,
 		Patterns:            p.Tags,
 		Companies:           p.Companies,
@@ -0,0 +1,41 @@
+// Opt-in sharing of solved solutions for anonymous peer review
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+)
+
+// shareForReview queues a solved solution for peer review when the user
+// opted in, and leaves a notification asking for a review in return.
+// Failures are ignored: the exchange must never get in the way of practice.
+func shareForReview(problemID, title, language, code string) {
+	cfg, err := config.LoadConfig()
+	if err != nil || !cfg.PeerReview || strings.TrimSpace(code) == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	if _, err := api.SubmitForReview(ctx, api.ReviewSubmission{
+		ProblemID: problemID,
+		Language:  language,
+		Code:      codefmt.Code(language, code),
+	}); err != nil {
+		return
+	}
+
+	_ = notifications.Add(notifications.Notification{
+		Kind:      notifications.KindReview,
+		Title:     fmt.Sprintf("Your %s solution was shared for peer review", title),
+		Details:   []string{fmt.Sprintf("Review a peer's solution in return: algo-scales peer review %s", problemID)},
+		ProblemID: problemID,
+	})
+}
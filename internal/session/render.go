@@ -11,7 +11,7 @@ import (
 // FormatProblemDescriptionWithHighlighting creates a formatted markdown description with syntax highlighting
 func (s *SessionImpl) FormatProblemDescriptionWithHighlighting() string {
 	// Create a syntax highlighter
-	highlighter := highlight.NewSyntaxHighlighter("monokai")
+	highlighter := highlight.NewSyntaxHighlighter("")
 
 	var description string
 
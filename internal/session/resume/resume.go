@@ -0,0 +1,112 @@
+// Package resume keeps the session in progress on disk, so quitting
+// mid-problem loses neither the code nor the time spent, and
+// 'algo-scales resume' can pick it up where it was left
+package resume
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// UI is the interface a session was running in, which resuming reopens
+type UI string
+
+const (
+	// CLI is the menu-driven 'algo-scales solve' workflow
+	CLI UI = "cli"
+	// TUI is the split-screen terminal UI
+	TUI UI = "tui"
+)
+
+// SaveEvery is how often a session is saved while only its clock changes.
+// Changes to the code or hints are saved as they happen.
+const SaveEvery = 15 * time.Second
+
+// State is a session in progress
+type State struct {
+	ProblemID     string      `json:"problem_id"`
+	Title         string      `json:"title"`
+	Mode          string      `json:"mode,omitempty"`
+	Language      string      `json:"language"`
+	UI            UI          `json:"ui"`
+	Code          string      `json:"code"`
+	Clock         clock.State `json:"clock"`
+	HintsShown    bool        `json:"hints_shown,omitempty"`
+	SolutionShown bool        `json:"solution_shown,omitempty"`
+	StartedAt     time.Time   `json:"started_at"`
+	SavedAt       time.Time   `json:"saved_at"`
+}
+
+// Elapsed returns the time spent on the session when it was saved
+func (s State) Elapsed() time.Duration {
+	return s.Clock.Elapsed
+}
+
+// RestoreClock returns the session's clock, running again from now unless
+// it was paused
+func (s State) RestoreClock() (*clock.Clock, error) {
+	return clock.FromState(s.Clock)
+}
+
+// backend is the user data backend the session is kept in
+// Exported as variable for testing
+var backend = storage.UserData
+
+// stateName is the file the session is kept in
+const stateName = "session.json"
+
+// Save replaces the saved session with state
+func Save(state State) error {
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	state.SavedAt = time.Now()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+	if err := b.Put(context.Background(), stateName, data); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	return nil
+}
+
+// Load returns the saved session, or nil if there is none
+func Load() (*State, error) {
+	b, err := backend()
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.Get(context.Background(), stateName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved session: %v", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode saved session: %v", err)
+	}
+	return &state, nil
+}
+
+// Clear forgets the saved session, once it is finished or discarded
+func Clear() error {
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(context.Background(), stateName); err != nil {
+		return fmt.Errorf("failed to clear saved session: %v", err)
+	}
+	return nil
+}
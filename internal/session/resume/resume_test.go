@@ -0,0 +1,60 @@
+package resume
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBackend keeps the saved session in a temporary directory for the test
+func stubBackend(t *testing.T) {
+	t.Helper()
+	original := backend
+	b := storage.NewLocalBackend(t.TempDir())
+	backend = func() (storage.Backend, error) { return b, nil }
+	t.Cleanup(func() { backend = original })
+}
+
+func TestSaveLoadClear(t *testing.T) {
+	stubBackend(t)
+
+	state, err := Load()
+	require.NoError(t, err)
+	assert.Nil(t, state, "nothing saved yet")
+	require.NoError(t, Clear(), "clearing nothing is not an error")
+
+	require.NoError(t, Save(State{
+		ProblemID:  "two_sum",
+		Title:      "Two Sum",
+		Language:   "go",
+		UI:         CLI,
+		Code:       "func twoSum() {}",
+		Clock:      clock.State{Mode: clock.Countdown, Limit: 30 * time.Minute, Elapsed: 12 * time.Minute},
+		HintsShown: true,
+	}))
+
+	state, err = Load()
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, "two_sum", state.ProblemID)
+	assert.Equal(t, "func twoSum() {}", state.Code)
+	assert.True(t, state.HintsShown)
+	assert.False(t, state.SavedAt.IsZero())
+	assert.Equal(t, 12*time.Minute, state.Elapsed())
+
+	// The clock picks up from the time saved, and keeps running
+	restored, err := state.RestoreClock()
+	require.NoError(t, err)
+	assert.False(t, restored.Paused())
+	assert.GreaterOrEqual(t, restored.Elapsed(), 12*time.Minute)
+	assert.LessOrEqual(t, restored.Remaining(), 18*time.Minute)
+
+	require.NoError(t, Clear())
+	state, err = Load()
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
@@ -9,7 +9,9 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 	"github.com/lancekrogers/algo-scales/internal/stats"
 )
 
@@ -17,9 +19,10 @@ import (
 type Mode string
 
 const (
-	LearnMode    Mode = "learn"
-	PracticeMode Mode = "practice"
-	CramMode     Mode = "cram"
+	LearnMode     Mode = "learn"
+	PracticeMode  Mode = "practice"
+	CramMode      Mode = "cram"
+	InterviewMode Mode = "interview"
 )
 
 // Options represents options for a session
@@ -30,6 +33,7 @@ type Options struct {
 	Pattern    string
 	Difficulty string
 	ProblemID  string
+	Context    string // Entry point recording the session; the CLI when empty
 }
 
 // Session represents a practice session
@@ -43,6 +47,8 @@ type Session struct {
 	ShowHints    bool
 	ShowPattern  bool
 	ShowSolution bool
+	Clock        *clock.Clock // authoritative session time
+	Reason       string       // Why the problem's difficulty was chosen, for difficulty auto
 }
 
 // Start begins a new practice session
@@ -70,18 +76,20 @@ var Start = func(opts Options) error {
 		}
 	} else if opts.Mode == CramMode {
 		// Cram mode - choose problems from common patterns
-		session.Problem, err = manager.selectCramProblem(context.TODO())
+		session.Problem, session.Reason, err = manager.selectCramProblem(context.TODO(), opts.Difficulty)
 		if err != nil {
 			return fmt.Errorf("failed to select problem for cram mode: %v", err)
 		}
 	} else {
 		// Filter by pattern/difficulty if specified
-		session.Problem, err = manager.selectProblem(context.TODO(), opts.Pattern, opts.Difficulty)
+		session.Problem, session.Reason, err = manager.selectProblem(context.TODO(), opts.Pattern, opts.Difficulty)
 		if err != nil {
 			return fmt.Errorf("failed to select problem: %v", err)
 		}
 	}
 
+	session.Clock = newSessionClock(session.Problem)
+
 	// Create workspace
 	if err := session.createWorkspace(); err != nil {
 		return fmt.Errorf("failed to create workspace: %v", err)
@@ -93,10 +101,15 @@ var Start = func(opts Options) error {
 
 // Note: These functions moved to manager.go to avoid redeclaration
 
+// WorkspaceDir returns the directory a problem's session files are written to
+func WorkspaceDir(problemID string) string {
+	return filepath.Join(os.TempDir(), "algo-scales", problemID)
+}
+
 // createWorkspace sets up a workspace for the problem
 func (s *Session) createWorkspace() error {
 	// Create workspace directory
-	workspaceDir := filepath.Join(os.TempDir(), "algo-scales", s.Problem.ID)
+	workspaceDir := WorkspaceDir(s.Problem.ID)
 	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
 		return err
 	}
@@ -106,6 +119,9 @@ func (s *Session) createWorkspace() error {
 	// Create problem description file
 	descriptionFile := filepath.Join(workspaceDir, "problem.md")
 	description := s.FormatProblemDescription()
+	if notes, _ := problem.LoadNotes(s.Problem.ID); notes != "" {
+		description += "## Your Notes\n\n" + notes
+	}
 	if err := os.WriteFile(descriptionFile, []byte(description), 0644); err != nil {
 		return err
 	}
@@ -184,22 +200,50 @@ func (s *Session) FormatProblemDescription() string {
 
 // FinishSession completes a session and records stats
 func (s *Session) FinishSession(solved bool) error {
+	return s.finish(solved, "")
+}
+
+// finish records the session's stats; swappedTo names the easier problem
+// the user switched to, if any
+func (s *Session) finish(solved bool, swappedTo string) error {
 	s.EndTime = time.Now()
+	duration := s.EndTime.Sub(s.StartTime)
+	if s.Clock != nil {
+		s.Clock.Pause()
+		duration = s.Clock.Elapsed()
+	}
 
 	// Record stats
 	sessionStats := stats.SessionStats{
 		ProblemID:    s.Problem.ID,
 		StartTime:    s.StartTime,
 		EndTime:      s.EndTime,
-		Duration:     s.EndTime.Sub(s.StartTime),
+		Duration:     duration,
 		Solved:       solved,
 		Mode:         string(s.Options.Mode),
 		HintsUsed:    s.ShowHints,
 		SolutionUsed: s.ShowSolution,
 		Patterns:     s.Problem.Patterns,
 		Difficulty:   s.Problem.Difficulty,
+		Environment:  CaptureEnvironment(*s.Problem, s.Options.Language),
+		SwappedTo:    swappedTo,
+		Context:      s.Options.Context,
+	}
+	if sessionStats.Context == "" {
+		sessionStats.Context = interfaces.ContextCLI
 	}
 
+	reportAttempt(sessionStats, s.Options.Language, nil)
+
+	// Only the user's own work is worth a peer's review
+	if solved && !s.ShowSolution && s.CodeFile != "" {
+		if code, err := os.ReadFile(s.CodeFile); err == nil {
+			shareForReview(s.Problem.ID, s.Problem.Title, s.Options.Language, string(code))
+			if isExemplary(solved, s.ShowHints, s.ShowSolution) {
+				publishToHallOfFame(s.Problem.ID, s.Problem.Title, s.Options.Language, string(code))
+			}
+		}
+	}
 	return stats.RecordSession(sessionStats)
 }
 
@@ -0,0 +1,38 @@
+// Session clock setup and persistence
+
+package session
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+)
+
+// clockFileName is where a session's clock is saved in its workspace
+const clockFileName = "clock.json"
+
+// newSessionClock creates a started clock for a problem: a countdown from
+// its estimated time, or a stopwatch when it has none
+func newSessionClock(prob *problem.Problem) *clock.Clock {
+	var c *clock.Clock
+	if prob != nil && prob.EstimatedTime > 0 {
+		c = clock.NewCountdown(time.Duration(prob.EstimatedTime) * time.Minute)
+	} else {
+		c = clock.NewStopwatch()
+	}
+	c.Start()
+	return c
+}
+
+// saveClock writes a clock's state into a workspace
+func saveClock(fs interfaces.FileSystem, workspace string, c *clock.Clock) error {
+	data, err := json.MarshalIndent(c.State(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(filepath.Join(workspace, clockFileName), data, 0644)
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/common/utils"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 	"github.com/lancekrogers/algo-scales/internal/session/execution"
 	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
 )
 
 // SessionImpl implements the Session interface
@@ -27,6 +29,9 @@ type SessionImpl struct {
 	Code         string
 	testRegistry interfaces.TestRunnerRegistry
 	fs          interfaces.FileSystem
+	failingTests []int // 1-based numbers of tests that failed on the last real run
+	clock        *clock.Clock
+	Reason       string // Why the problem's difficulty was chosen, for difficulty auto
 }
 
 // NewSessionImpl creates a new session implementation
@@ -37,6 +42,7 @@ func NewSessionImpl(opts interfaces.SessionOptions, prob *problem.Problem) *Sess
 		StartTime:    time.Now(),
 		testRegistry: execution.DefaultRegistry,
 		fs:          utils.NewFileSystem(),
+		clock:        newSessionClock(prob),
 	}
 }
 
@@ -75,13 +81,29 @@ func (s *SessionImpl) GetStartTime() time.Time {
 
 // GetTimeRemaining returns the remaining session time
 func (s *SessionImpl) GetTimeRemaining() time.Duration {
-	estimatedDuration := time.Duration(s.Problem.EstimatedTime) * time.Minute
-	elapsed := time.Since(s.StartTime)
-	remaining := estimatedDuration - elapsed
-	if remaining < 0 {
-		return 0
+	return s.clock.Remaining()
+}
+
+// GetClock returns the session clock that UIs display and subscribe to
+func (s *SessionImpl) GetClock() *clock.Clock {
+	return s.clock
+}
+
+// persistClock saves the clock to the workspace whenever it starts, pauses
+// or expires, so the session's time survives with its files
+func (s *SessionImpl) persistClock() {
+	s.clock.Subscribe(func(clock.Snapshot) {
+		s.saveClock()
+	})
+	s.saveClock()
+}
+
+// saveClock writes the clock state into the workspace, if there is one
+func (s *SessionImpl) saveClock() {
+	if s.Workspace == "" {
+		return
 	}
-	return remaining
+	_ = saveClock(s.fs, s.Workspace, s.clock)
 }
 
 // GetLanguage returns the programming language
@@ -196,8 +218,19 @@ func (s *SessionImpl) SetCode(code string) error {
 	return nil
 }
 
-// RunTests executes tests on the current solution
+// RunTests executes the example tests on the current solution
 func (s *SessionImpl) RunTests(ctx context.Context) ([]interfaces.TestResult, bool, error) {
+	return s.runTests(ctx, false)
+}
+
+// SubmitTests executes every tier of tests on the current solution
+func (s *SessionImpl) SubmitTests(ctx context.Context) ([]interfaces.TestResult, bool, error) {
+	return s.runTests(ctx, true)
+}
+
+// runTests executes the example tests, or all tests for a submission, using
+// the test runner registry
+func (s *SessionImpl) runTests(ctx context.Context, submit bool) ([]interfaces.TestResult, bool, error) {
 	// Get the test runner for this language
 	runner, err := s.testRegistry.GetRunner(s.Options.Language)
 	if err != nil {
@@ -209,15 +242,22 @@ func (s *SessionImpl) RunTests(ctx context.Context) ([]interfaces.TestResult, bo
 	
 	// Execute tests
 	interfaceProblem := s.convertProblemToInterface(*s.Problem)
-	results, allPassed, err := runner.ExecuteTests(ctx, &interfaceProblem, code, 30*time.Second)
-	if err != nil {
+	testProblem := usertests.Include(&interfaceProblem)
+	if !submit {
+		testProblem = execution.ExampleTests(testProblem)
+	}
+	results, allPassed, err := runner.ExecuteTests(ctx, testProblem, code, 30*time.Second)
+	if err == nil {
+		s.failingTests = failingTestNumbers(results)
+		RecordAttempt(s.Problem.ID, s.Options.Language, code, results, allPassed, submit)
+	} else {
 		// If real execution fails, fall back to simulation for now
 		fmt.Printf("Warning: Code execution failed (%v), falling back to simulation.\n", err)
 		
 		// Fallback: Simulate test results
-		results = make([]interfaces.TestResult, 0, len(s.Problem.TestCases))
+		results = make([]interfaces.TestResult, 0, len(testProblem.TestCases))
 		
-		for _, testCase := range s.Problem.TestCases {
+		for _, testCase := range testProblem.TestCases {
 			// Simulate a 75% pass rate
 			passed := rand.Float32() < 0.75
 			
@@ -226,6 +266,7 @@ func (s *SessionImpl) RunTests(ctx context.Context) ([]interfaces.TestResult, bo
 				Expected: testCase.Expected,
 				Actual:   testCase.Expected, // Simulate passing for now
 				Passed:   passed,
+				Tier:     testCase.Tier.OrDefault(),
 			}
 			
 			if !passed {
@@ -252,21 +293,32 @@ func (s *SessionImpl) RunTests(ctx context.Context) ([]interfaces.TestResult, bo
 // Finish completes the session and records stats
 func (s *SessionImpl) Finish(ctx context.Context, solved bool) error {
 	s.EndTime = time.Now()
+	s.clock.Pause()
 
 	// Record stats
 	sessionStats := stats.SessionStats{
 		ProblemID:    s.Problem.ID,
 		StartTime:    s.StartTime,
 		EndTime:      s.EndTime,
-		Duration:     s.EndTime.Sub(s.StartTime),
+		Duration:     s.clock.Elapsed(),
 		Solved:       solved,
 		Mode:         string(s.Options.Mode),
 		HintsUsed:    s.hintsShown,
 		SolutionUsed: s.solutionShown,
 		Patterns:     s.Problem.Patterns,
 		Difficulty:   s.Problem.Difficulty,
+		Environment:  CaptureEnvironment(*s.Problem, s.Options.Language),
 	}
 
+	reportAttempt(sessionStats, s.Options.Language, s.failingTests)
+
+	// Only the user's own work is worth a peer's review
+	if solved && !s.solutionShown {
+		shareForReview(s.Problem.ID, s.Problem.Title, s.Options.Language, s.GetCode())
+	}
+	if isExemplary(solved, s.hintsShown, s.solutionShown) {
+		publishToHallOfFame(s.Problem.ID, s.Problem.Title, s.Options.Language, s.GetCode())
+	}
 	return stats.RecordSession(sessionStats)
 }
 
@@ -279,6 +331,7 @@ func (s *SessionImpl) convertProblemToInterface(p problem.Problem) interfaces.Pr
 		testCases[i] = interfaces.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -298,6 +351,7 @@ func (s *SessionImpl) convertProblemToInterface(p problem.Problem) interfaces.Pr
 		ID:          p.ID,
 		Title:       p.Title,
 		Description: p.Description,
+		Signature:   p.Signature,
 		Pattern:     pattern,
 		Difficulty:  p.Difficulty,
 		Companies:   p.Companies,
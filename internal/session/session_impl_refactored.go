@@ -9,7 +9,9 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/common/utils"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
 )
 
 // RefactoredSessionImpl implements the Session interface with extracted components
@@ -25,6 +27,7 @@ type RefactoredSessionImpl struct {
 	formatter       interfaces.ProblemFormatter
 	codeManager     interfaces.CodeManager
 	statsRecorder   interfaces.SessionStatsRecorder
+	clock           *clock.Clock
 }
 
 // NewRefactoredSessionImpl creates a new refactored session implementation
@@ -39,6 +42,7 @@ func NewRefactoredSessionImpl(opts interfaces.SessionOptions, prob *problem.Prob
 		formatter:     NewProblemFormatter(),
 		codeManager:   NewCodeManager(fs, nil), // nil template service for now to avoid cycles
 		statsRecorder: NewSessionStatsRecorder(nil), // nil stats service for now to avoid cycles
+		clock:         newSessionClock(prob),
 	}
 }
 
@@ -83,13 +87,12 @@ func (s *RefactoredSessionImpl) GetStartTime() time.Time {
 
 // GetTimeRemaining returns the remaining session time
 func (s *RefactoredSessionImpl) GetTimeRemaining() time.Duration {
-	estimatedDuration := time.Duration(s.Problem.EstimatedTime) * time.Minute
-	elapsed := time.Since(s.StartTime)
-	remaining := estimatedDuration - elapsed
-	if remaining < 0 {
-		return 0
-	}
-	return remaining
+	return s.clock.Remaining()
+}
+
+// GetClock returns the session clock that UIs display and subscribe to
+func (s *RefactoredSessionImpl) GetClock() *clock.Clock {
+	return s.clock
 }
 
 // GetLanguage returns the programming language
@@ -134,8 +137,19 @@ func (s *RefactoredSessionImpl) SetCode(code string) error {
 	return s.codeManager.SetCode(code)
 }
 
-// RunTests runs the code tests using the test runner registry
+// RunTests executes the example tests on the current solution
 func (s *RefactoredSessionImpl) RunTests(ctx context.Context) ([]interfaces.TestResult, bool, error) {
+	return s.runTests(ctx, false)
+}
+
+// SubmitTests executes every tier of tests on the current solution
+func (s *RefactoredSessionImpl) SubmitTests(ctx context.Context) ([]interfaces.TestResult, bool, error) {
+	return s.runTests(ctx, true)
+}
+
+// runTests executes the example tests, or all tests for a submission, using
+// the test runner registry
+func (s *RefactoredSessionImpl) runTests(ctx context.Context, submit bool) ([]interfaces.TestResult, bool, error) {
 	// Get test runner for the language
 	runner, err := s.testRegistry.GetRunner(s.GetLanguage())
 	if err != nil {
@@ -147,15 +161,21 @@ func (s *RefactoredSessionImpl) RunTests(ctx context.Context) ([]interfaces.Test
 	
 	// Execute tests
 	interfaceProblem := s.convertProblemToInterface(*s.Problem)
-	results, allPassed, err := runner.ExecuteTests(ctx, &interfaceProblem, code, 30*time.Second)
-	if err != nil {
+	testProblem := usertests.Include(&interfaceProblem)
+	if !submit {
+		testProblem = execution.ExampleTests(testProblem)
+	}
+	results, allPassed, err := runner.ExecuteTests(ctx, testProblem, code, 30*time.Second)
+	if err == nil {
+		RecordAttempt(s.Problem.ID, s.GetLanguage(), code, results, allPassed, submit)
+	} else {
 		// If real execution fails, fall back to simulation for now
 		fmt.Printf("Warning: Code execution failed (%v), falling back to simulation.\n", err)
 		
 		// Fallback: Simulate test results
-		results = make([]interfaces.TestResult, 0, len(s.Problem.TestCases))
+		results = make([]interfaces.TestResult, 0, len(testProblem.TestCases))
 		
-		for _, testCase := range s.Problem.TestCases {
+		for _, testCase := range testProblem.TestCases {
 			// Simulate a 75% pass rate
 			passed := rand.Float32() < 0.75
 			
@@ -164,6 +184,7 @@ func (s *RefactoredSessionImpl) RunTests(ctx context.Context) ([]interfaces.Test
 				Expected: testCase.Expected,
 				Actual:   testCase.Expected, // Simulate passing for now
 				Passed:   passed,
+				Tier:     testCase.Tier.OrDefault(),
 			}
 			
 			if !passed {
@@ -190,19 +211,21 @@ func (s *RefactoredSessionImpl) RunTests(ctx context.Context) ([]interfaces.Test
 // Finish completes the session and records stats using the stats recorder
 func (s *RefactoredSessionImpl) Finish(ctx context.Context, solved bool) error {
 	s.EndTime = time.Now()
+	s.clock.Pause()
 
 	// Create session stats
 	sessionStats := interfaces.SessionStats{
 		ProblemID:    s.Problem.ID,
 		StartTime:    s.StartTime,
 		EndTime:      s.EndTime,
-		Duration:     s.EndTime.Sub(s.StartTime),
+		Duration:     s.clock.Elapsed(),
 		Solved:       solved,
 		Mode:         string(s.Options.Mode),
 		HintsUsed:    s.hintsShown,
 		SolutionUsed: s.solutionShown,
 		Patterns:     s.Problem.Patterns,
 		Difficulty:   s.Problem.Difficulty,
+		Environment:  CaptureEnvironment(*s.Problem, s.Options.Language),
 	}
 
 	return s.statsRecorder.RecordSession(ctx, sessionStats)
@@ -216,6 +239,7 @@ func (s *RefactoredSessionImpl) convertProblemToInterface(p problem.Problem) int
 		testCases[i] = interfaces.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -235,6 +259,7 @@ func (s *RefactoredSessionImpl) convertProblemToInterface(p problem.Problem) int
 		ID:          p.ID,
 		Title:       p.Title,
 		Description: p.Description,
+		Signature:   p.Signature,
 		Pattern:     pattern,
 		Difficulty:  p.Difficulty,
 		Companies:   p.Companies,
@@ -6,9 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -315,4 +318,93 @@ func TestJoinStrings(t *testing.T) {
 			assert.Equal(t, tc.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestCaptureEnvironment(t *testing.T) {
+	original := toolchainVersion
+	toolchainVersion = func(language string) string { return "go version go1.24.2" }
+	defer func() { toolchainVersion = original }()
+
+	testProblem := getTestProblem()
+	env := CaptureEnvironment(*testProblem, "go")
+
+	assert.Equal(t, "go", env.Language)
+	assert.Equal(t, "go version go1.24.2", env.Toolchain)
+	assert.Equal(t, testProblem.TestSetHash(), env.TestSetHash)
+	assert.Equal(t, testProblem.DefinitionVersion(), env.ProblemVersion)
+}
+
+func TestCheckProblemChanged(t *testing.T) {
+	testProblem := getTestProblem()
+	recorded := CaptureEnvironment(*testProblem, "go")
+
+	original := stats.GetAllSessions
+	stats.GetAllSessions = func() ([]stats.SessionStats, error) {
+		return []stats.SessionStats{
+			{ProblemID: testProblem.ID, StartTime: time.Now(), Environment: recorded},
+			{ProblemID: "other-problem", StartTime: time.Now()},
+		}, nil
+	}
+	defer func() { stats.GetAllSessions = original }()
+
+	// Unchanged problem produces no warning
+	assert.Empty(t, CheckProblemChanged(*testProblem))
+
+	// Changing the tests is reported
+	changed := *testProblem
+	changed.TestCases = append([]problem.TestCase{}, testProblem.TestCases...)
+	changed.TestCases = append(changed.TestCases, problem.TestCase{Input: "[], 0", Expected: "[]"})
+	warning := CheckProblemChanged(changed)
+	assert.Contains(t, warning, "test cases")
+	assert.Contains(t, warning, testProblem.ID)
+
+	// Sessions without a snapshot are ignored
+	other := *testProblem
+	other.ID = "other-problem"
+	other.Description = "edited"
+	assert.Empty(t, CheckProblemChanged(other))
+}
+
+func TestSessionClock(t *testing.T) {
+	testProblem := getTestProblem()
+	session := NewSessionImpl(interfaces.SessionOptions{Language: "go"}, testProblem)
+	session.Workspace = t.TempDir()
+
+	// Countdown from the problem's estimated time
+	sessionClock := session.GetClock()
+	require.NotNil(t, sessionClock)
+	assert.False(t, sessionClock.Paused())
+	assert.Equal(t, clock.Countdown, sessionClock.Snapshot().Mode)
+	assert.InDelta(t, float64(15*time.Minute), float64(session.GetTimeRemaining()), float64(time.Second))
+
+	// Pausing saves the clock alongside the session
+	session.persistClock()
+	sessionClock.Pause()
+
+	data, err := os.ReadFile(filepath.Join(session.Workspace, clockFileName))
+	require.NoError(t, err)
+	restored, err := clock.Parse(data)
+	require.NoError(t, err)
+	assert.True(t, restored.Paused())
+	assert.Equal(t, sessionClock.Elapsed(), restored.Elapsed())
+
+	// Paused time does not reduce the time remaining
+	remaining := session.GetTimeRemaining()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, remaining, session.GetTimeRemaining())
+}
+
+func TestSessionClockWithoutEstimate(t *testing.T) {
+	testProblem := getTestProblem()
+	testProblem.EstimatedTime = 0
+	session := NewSessionImpl(interfaces.SessionOptions{Language: "go"}, testProblem)
+
+	assert.Equal(t, clock.Stopwatch, session.GetClock().Snapshot().Mode)
+	assert.Equal(t, time.Duration(0), session.GetTimeRemaining())
+}
+
+func TestIsExemplary(t *testing.T) {
+	assert.True(t, isExemplary(true, false, false))
+	assert.False(t, isExemplary(false, false, false))
+	assert.False(t, isExemplary(true, true, false))
+	assert.False(t, isExemplary(true, false, true))
+}
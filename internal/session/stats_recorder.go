@@ -2,8 +2,12 @@ package session
 
 import (
 	"context"
+	"time"
+
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
 	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 )
 
 // SessionStatsRecorderImpl implements the SessionStatsRecorder interface
@@ -32,8 +36,34 @@ func (sr *SessionStatsRecorderImpl) RecordSession(ctx context.Context, sessionSt
 		SolutionUsed: sessionStats.SolutionUsed,
 		Patterns:     sessionStats.Patterns,
 		Difficulty:   sessionStats.Difficulty,
+		Environment:  sessionStats.Environment,
+		SwappedTo:    sessionStats.SwappedTo,
+		Context:      sessionStats.Context,
 	}
 	
 	// Use the legacy function for now to maintain compatibility
 	return stats.RecordSession(statsSession)
-}
\ No newline at end of file
+}
+
+// RecordAttempt keeps a test run and the code it ran in the progress
+// database; submitted marks a run of every tier of tests. Failures are
+// ignored: a storage problem must never get in the way of practice.
+func RecordAttempt(problemID, language, code string, results []interfaces.TestResult, allPassed, submitted bool) {
+	attempt := storage.Attempt{
+		ProblemID: problemID,
+		Language:  language,
+		Time:      time.Now(),
+		Passed:    allPassed,
+		Code:      codefmt.Code(language, code),
+		Submitted: submitted,
+	}
+	for i, r := range results {
+		attempt.Results = append(attempt.Results, storage.TestResult{
+			Number:   i + 1,
+			Passed:   r.Passed,
+			Failure:  r.Failure,
+			Duration: r.Duration,
+		})
+	}
+	_ = stats.RecordAttempt(attempt)
+}
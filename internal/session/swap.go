@@ -0,0 +1,98 @@
+// Offering an easier problem when the user is stuck
+
+package session
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// StuckFactor is how many times a problem's estimated time the user may
+// spend on it before an easier problem is offered
+const StuckFactor = 2
+
+// difficultyRank orders difficulties from easiest to hardest
+var difficultyRank = map[string]int{
+	"easy":   0,
+	"medium": 1,
+	"hard":   2,
+}
+
+// IsStuck reports whether elapsed is past StuckFactor times the problem's
+// estimate. Problems without an estimate never count as stuck.
+func IsStuck(p problem.Problem, elapsed time.Duration) bool {
+	if p.EstimatedTime <= 0 {
+		return false
+	}
+	return elapsed > time.Duration(StuckFactor*p.EstimatedTime)*time.Minute
+}
+
+// FindEasierProblem returns the hardest problem of pattern that is still
+// easier than p and not hidden, or nil if there is none. An empty pattern means p's
+// primary pattern.
+// Exported as variable for testing
+var FindEasierProblem = func(p problem.Problem, pattern string) (*problem.Problem, error) {
+	if pattern == "" && len(p.Patterns) > 0 {
+		pattern = p.Patterns[0]
+	}
+	if pattern == "" {
+		return nil, nil
+	}
+	rank, ok := difficultyRank[strings.ToLower(p.Difficulty)]
+	if !ok || rank == 0 {
+		return nil, nil
+	}
+
+	all, err := problem.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []problem.Problem
+	for _, candidate := range problem.Visible(all) {
+		candidateRank, ok := difficultyRank[strings.ToLower(candidate.Difficulty)]
+		if !ok || candidateRank >= rank || !hasPattern(candidate, pattern) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Step down as little as possible, breaking ties by ID
+	sort.Slice(candidates, func(i, j int) bool {
+		ri := difficultyRank[strings.ToLower(candidates[i].Difficulty)]
+		rj := difficultyRank[strings.ToLower(candidates[j].Difficulty)]
+		if ri != rj {
+			return ri > rj
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	return &candidates[0], nil
+}
+
+// hasPattern reports whether a problem is tagged with pattern
+func hasPattern(p problem.Problem, pattern string) bool {
+	for _, candidate := range p.Patterns {
+		if candidate == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// SwapToEasier ends the session unsolved, recording the swap in stats, and
+// starts a session on the easier problem with the same options
+func (s *Session) SwapToEasier(easier *problem.Problem) (*Session, error) {
+	if err := s.finish(false, easier.ID); err != nil {
+		return nil, err
+	}
+
+	opts := s.Options
+	opts.ProblemID = easier.ID
+	return CreateSession(opts)
+}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsStuck(t *testing.T) {
+	p := problem.Problem{EstimatedTime: 20}
+	assert.False(t, IsStuck(p, 30*time.Minute))
+	assert.False(t, IsStuck(p, 40*time.Minute))
+	assert.True(t, IsStuck(p, 41*time.Minute))
+
+	// Without an estimate there is nothing to be past
+	assert.False(t, IsStuck(problem.Problem{}, 24*time.Hour))
+}
+
+func TestFindEasierProblem(t *testing.T) {
+	original := problem.ListAll
+	defer func() { problem.ListAll = original }()
+	problem.ListAll = func() ([]problem.Problem, error) {
+		return []problem.Problem{
+			{ID: "pair-sum", Difficulty: "Easy", Patterns: []string{"two-pointers"}},
+			{ID: "container", Difficulty: "Medium", Patterns: []string{"two-pointers"}},
+			{ID: "3sum", Difficulty: "medium", Patterns: []string{"two-pointers", "sorting"}},
+			{ID: "trapping-rain", Difficulty: "Hard", Patterns: []string{"two-pointers"}},
+			{ID: "sort-colors", Difficulty: "Easy", Patterns: []string{"sorting"}},
+		}, nil
+	}
+
+	// The closest step down is preferred, ties broken by ID
+	easier, err := FindEasierProblem(problem.Problem{ID: "trapping-rain", Difficulty: "Hard", Patterns: []string{"two-pointers"}}, "")
+	require.NoError(t, err)
+	require.NotNil(t, easier)
+	assert.Equal(t, "3sum", easier.ID)
+
+	easier, err = FindEasierProblem(problem.Problem{ID: "3sum", Difficulty: "Medium", Patterns: []string{"two-pointers", "sorting"}}, "sorting")
+	require.NoError(t, err)
+	require.NotNil(t, easier)
+	assert.Equal(t, "sort-colors", easier.ID)
+
+	// Nothing is easier than easy
+	easier, err = FindEasierProblem(problem.Problem{ID: "pair-sum", Difficulty: "Easy", Patterns: []string{"two-pointers"}}, "")
+	require.NoError(t, err)
+	assert.Nil(t, easier)
+}
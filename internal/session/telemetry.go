@@ -0,0 +1,46 @@
+// Opt-in sharing of anonymous attempt results
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/api"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// telemetryTimeout bounds how long finishing a session may wait on the server
+const telemetryTimeout = 5 * time.Second
+
+// reportAttempt shares an anonymous attempt record when the user opted in.
+// Failures are ignored: telemetry must never get in the way of practice.
+func reportAttempt(sessionStats stats.SessionStats, language string, failingTests []int) {
+	cfg, err := config.LoadConfig()
+	if err != nil || !cfg.ShareTelemetry {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	_ = api.SubmitAttempt(ctx, api.Attempt{
+		ProblemID:       sessionStats.ProblemID,
+		Solved:          sessionStats.Solved,
+		DurationSeconds: int(sessionStats.Duration.Seconds()),
+		FailingTests:    failingTests,
+		Language:        language,
+	})
+}
+
+// failingTestNumbers returns the 1-based numbers of failed tests
+func failingTestNumbers(results []interfaces.TestResult) []int {
+	var failing []int
+	for i, r := range results {
+		if !r.Passed {
+			failing = append(failing, i+1)
+		}
+	}
+	return failing
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 )
 
 // GoGenerator generates Go code templates
@@ -115,9 +116,9 @@ func main() {
 		// Check result
 		testCases.WriteString("\t\t// Check result\n")
 		testCases.WriteString("\t\tif fmt.Sprintf(\"%v\", result) == expectedStr {\n")
-		testCases.WriteString("\t\t\tfmt.Println(\"✅ PASSED\")\n")
+		testCases.WriteString(fmt.Sprintf("\t\t\tfmt.Println(\"%s PASSED\")\n", symbols.Pass))
 		testCases.WriteString("\t\t} else {\n")
-		testCases.WriteString("\t\t\tfmt.Printf(\"❌ FAILED\\nExpected: %s\\nGot: %v\\n\", expectedStr, result)\n")
+		testCases.WriteString(fmt.Sprintf("\t\t\tfmt.Printf(\"%s FAILED\\nExpected: %%s\\nGot: %%v\\n\", expectedStr, result)\n", symbols.Fail))
 		testCases.WriteString("\t\t\tallPassed = false\n")
 		testCases.WriteString("\t\t}\n")
 		testCases.WriteString("\t}\n")
@@ -0,0 +1,129 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+)
+
+// javaMethodNamePattern matches a method declaration: a return type, a name
+// and an opening parenthesis
+var javaMethodNamePattern = regexp.MustCompile(`([\w<>\[\],]+)\s+([a-zA-Z0-9_]+)\s*\(`)
+
+// JavaGenerator generates Java code templates
+type JavaGenerator struct{}
+
+// NewJavaGenerator creates a new Java code generator
+func NewJavaGenerator() *JavaGenerator {
+	return &JavaGenerator{}
+}
+
+// GetLanguage returns the language this generator supports
+func (g *JavaGenerator) GetLanguage() string {
+	return "java"
+}
+
+// GetTemplate returns a code template for a problem
+func (g *JavaGenerator) GetTemplate(prob interfaces.Problem) string {
+	// First check if a starter code is provided
+	if starterCode, ok := prob.StarterCode["java"]; ok && starterCode != "" {
+		return starterCode
+	}
+
+	// Otherwise generate a default template
+	return fmt.Sprintf(`// %s
+// %s
+
+class Solution {
+    // solution implements the algorithm
+    public int solution() {
+        // Step 1: Understand the problem
+        // - Read the problem description carefully
+        // - Identify input/output requirements
+        // - Consider edge cases
+
+        // Step 2: Plan your approach
+        // - What algorithm pattern applies here?
+        // - What data structures do you need?
+        // - What's the time/space complexity?
+
+        // Step 3: Implement your solution
+        // Replace this with your actual implementation
+
+        return 0; // Update return value as needed
+    }
+}
+`, prob.Title, sanitizeCommentText(prob.Description))
+}
+
+// GetTestHarness generates a test harness for Java
+func (g *JavaGenerator) GetTestHarness(prob interfaces.Problem, solutionCode string) string {
+	// Extract method name from solution code
+	funcName := g.GetFunctionName(solutionCode)
+	if funcName == "" {
+		funcName = "solution" // Default method name
+	}
+
+	// Create a test harness template
+	testHarness := `// User's solution
+%s
+
+class Main {
+    public static void main(String[] args) {
+        // Run tests
+        boolean allPassed = true;
+        Solution solution = new Solution();
+        %s
+
+        if (!allPassed) {
+            System.exit(1);
+        } else {
+            System.out.println("All tests passed!");
+        }
+    }
+}
+`
+
+	// Generate test code for each test case
+	var testCases strings.Builder
+	for i, tc := range prob.TestCases {
+		testCases.WriteString(fmt.Sprintf("\n        // Test case %d\n", i+1))
+		testCases.WriteString(fmt.Sprintf("        System.out.print(\"Test %d: \");\n", i+1))
+		testCases.WriteString(fmt.Sprintf("        {\n            String inputStr = %q;\n", tc.Input))
+		testCases.WriteString(fmt.Sprintf("            String expectedStr = %q;\n", tc.Expected))
+
+		// Parse input and call method with parameters
+		testCases.WriteString("            // Call the solution method\n")
+		testCases.WriteString(fmt.Sprintf("            Object result = solution.%s(); // Add parameters as needed\n", funcName))
+
+		// Check result
+		testCases.WriteString("            // Check result\n")
+		testCases.WriteString("            if (String.valueOf(result).replace(\" \", \"\").equals(expectedStr.replace(\" \", \"\"))) {\n")
+		testCases.WriteString(fmt.Sprintf("                System.out.println(\"%s PASSED\");\n", symbols.Pass))
+		testCases.WriteString("            } else {\n")
+		testCases.WriteString(fmt.Sprintf("                System.out.println(\"%s FAILED\\nExpected: \" + expectedStr + \"\\nGot: \" + result);\n", symbols.Fail))
+		testCases.WriteString("                allPassed = false;\n")
+		testCases.WriteString("            }\n")
+		testCases.WriteString("        }\n")
+	}
+
+	return fmt.Sprintf(testHarness, solutionCode, testCases.String())
+}
+
+// GetFunctionName extracts the method name from Java code, skipping main
+func (g *JavaGenerator) GetFunctionName(code string) string {
+	for _, matches := range javaMethodNamePattern.FindAllStringSubmatch(code, -1) {
+		switch matches[1] {
+		case "new", "return", "else", "throw":
+			continue
+		}
+		if matches[2] == "main" {
+			continue
+		}
+		return matches[2]
+	}
+	return ""
+}
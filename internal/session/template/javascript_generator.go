@@ -6,6 +6,7 @@ import (
 	"strings"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 )
 
 // JavaScriptGenerator generates JavaScript code templates
@@ -112,13 +113,13 @@ if (!success) {
 		// Check result
 		testCases.WriteString("        // Check result\n")
 		testCases.WriteString("        if (String(result) === expectedStr) {\n")
-		testCases.WriteString("            console.log(\"✅ PASSED\");\n")
+		testCases.WriteString(fmt.Sprintf("            console.log(\"%s PASSED\");\n", symbols.Pass))
 		testCases.WriteString("        } else {\n")
-		testCases.WriteString("            console.log(`❌ FAILED\\nExpected: ${expectedStr}\\nGot: ${result}`);\n")
+		testCases.WriteString(fmt.Sprintf("            console.log(`%s FAILED\\nExpected: ${expectedStr}\\nGot: ${result}`);\n", symbols.Fail))
 		testCases.WriteString("            allPassed = false;\n")
 		testCases.WriteString("        }\n")
 		testCases.WriteString("    } catch (e) {\n")
-		testCases.WriteString("        console.log(`❌ ERROR: ${e.message}`);\n")
+		testCases.WriteString(fmt.Sprintf("        console.log(`%s ERROR: ${e.message}`);\n", symbols.Error))
 		testCases.WriteString("        allPassed = false;\n")
 		testCases.WriteString("    }\n")
 	}
@@ -0,0 +1,76 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// PseudoGenerator generates templates for whiteboard-style pseudo-code
+type PseudoGenerator struct{}
+
+// NewPseudoGenerator creates a new pseudo-code template generator
+func NewPseudoGenerator() *PseudoGenerator {
+	return &PseudoGenerator{}
+}
+
+// GetLanguage returns the language this generator supports
+func (g *PseudoGenerator) GetLanguage() string {
+	return "pseudo"
+}
+
+// GetTemplate returns a pseudo-code template for a problem
+func (g *PseudoGenerator) GetTemplate(prob interfaces.Problem) string {
+	if starterCode, ok := prob.StarterCode["pseudo"]; ok && starterCode != "" {
+		return starterCode
+	}
+
+	return fmt.Sprintf(`# %s
+# %s
+#
+# Write your solution as whiteboard-style pseudo-code. It is not run:
+# the AI grades it against the reference approach for correctness, the
+# approach, complexity and edge cases.
+
+%s`, prob.Title, strings.ReplaceAll(prob.Description, "\n", "\n# "), PseudoSkeleton(prob.Signature))
+}
+
+// PseudoSkeleton returns the function to fill in with pseudo-code, named
+// and typed after the signature when the problem has one
+func PseudoSkeleton(sig *interfaces.FunctionSignature) string {
+	header := "function solution(...)"
+	if sig != nil {
+		params := make([]string, len(sig.Params))
+		for i, p := range sig.Params {
+			params[i] = fmt.Sprintf("%s: %s", p.Name, p.Type)
+		}
+		header = fmt.Sprintf("function %s(%s) -> %s", sig.Name, strings.Join(params, ", "), sig.Returns)
+	}
+
+	return header + `
+    # Your approach here
+
+# State the time and space complexity of your solution
+# Time:
+# Space:
+`
+}
+
+// GetTestHarness returns nothing, as pseudo-code is graded rather than run
+func (g *PseudoGenerator) GetTestHarness(prob interfaces.Problem, solutionCode string) string {
+	return ""
+}
+
+// pseudoFunctionPattern matches the first function a pseudo-code solution
+// defines, whatever keyword it is written with
+var pseudoFunctionPattern = regexp.MustCompile(`(?im)^\s*(?:function|procedure|def|func|fn)\s+(\w+)`)
+
+// GetFunctionName extracts the primary function name from the code
+func (g *PseudoGenerator) GetFunctionName(code string) string {
+	if match := pseudoFunctionPattern.FindStringSubmatch(code); match != nil {
+		return match[1]
+	}
+	return ""
+}
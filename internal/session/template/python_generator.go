@@ -6,6 +6,7 @@ import (
 	"strings"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 )
 
 // PythonGenerator generates Python code templates
@@ -104,12 +105,12 @@ if __name__ == "__main__":
 		// Check result
 		testCases.WriteString("        # Check result\n")
 		testCases.WriteString("        if str(result) == expected_str:\n")
-		testCases.WriteString("            print(\"✅ PASSED\")\n")
+		testCases.WriteString(fmt.Sprintf("            print(\"%s PASSED\")\n", symbols.Pass))
 		testCases.WriteString("        else:\n")
-		testCases.WriteString("            print(f\"❌ FAILED\\nExpected: {expected_str}\\nGot: {result}\")\n")
+		testCases.WriteString(fmt.Sprintf("            print(f\"%s FAILED\\nExpected: {expected_str}\\nGot: {result}\")\n", symbols.Fail))
 		testCases.WriteString("            all_passed = False\n")
 		testCases.WriteString("    except Exception as e:\n")
-		testCases.WriteString("        print(f\"❌ ERROR: {e}\")\n")
+		testCases.WriteString(fmt.Sprintf("        print(f\"%s ERROR: {e}\")\n", symbols.Error))
 		testCases.WriteString("        all_passed = False\n")
 	}
 	
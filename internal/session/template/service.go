@@ -37,6 +37,11 @@ func NewService() *Service {
 	service.RegisterGenerator(NewGoGenerator())
 	service.RegisterGenerator(NewPythonGenerator())
 	service.RegisterGenerator(NewJavaScriptGenerator())
+	service.RegisterGenerator(NewRustGenerator())
+	service.RegisterGenerator(NewJavaGenerator())
+	service.RegisterGenerator(NewCppGenerator())
+	service.RegisterGenerator(NewTypeScriptGenerator())
+	service.RegisterGenerator(NewPseudoGenerator())
 	
 	return service
 }
@@ -32,6 +32,11 @@ func TestTemplateService(t *testing.T) {
 		assert.Contains(t, languages, "go")
 		assert.Contains(t, languages, "python")
 		assert.Contains(t, languages, "javascript")
+		assert.Contains(t, languages, "rust")
+		assert.Contains(t, languages, "java")
+		assert.Contains(t, languages, "cpp")
+		assert.Contains(t, languages, "typescript")
+		assert.Contains(t, languages, "pseudo")
 	})
 	
 	// Test GetTemplate for Go
@@ -52,6 +57,33 @@ func TestTemplateService(t *testing.T) {
 		assert.Contains(t, template, "if __name__ == \"__main__\":")
 	})
 	
+	// Test GetTemplate for Rust
+	t.Run("GetTemplate_Rust", func(t *testing.T) {
+		template, err := service.GetTemplate(testProblem, "rust")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "pub fn solution()")
+		assert.Contains(t, template, "Test Problem")
+		assert.Contains(t, template, "fn main()")
+	})
+	
+	// Test GetTemplate for Java
+	t.Run("GetTemplate_Java", func(t *testing.T) {
+		template, err := service.GetTemplate(testProblem, "java")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "class Solution")
+		assert.Contains(t, template, "Test Problem")
+		assert.Contains(t, template, "public int solution()")
+	})
+	
+	// Test GetTemplate for C++
+	t.Run("GetTemplate_Cpp", func(t *testing.T) {
+		template, err := service.GetTemplate(testProblem, "cpp")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "class Solution")
+		assert.Contains(t, template, "Test Problem")
+		assert.Contains(t, template, "int solution()")
+	})
+	
 	// Test GetTemplate for JavaScript
 	t.Run("GetTemplate_JavaScript", func(t *testing.T) {
 		template, err := service.GetTemplate(testProblem, "javascript")
@@ -61,6 +93,34 @@ func TestTemplateService(t *testing.T) {
 		assert.Contains(t, template, "function runTests()")
 	})
 	
+	// Test GetTemplate for TypeScript
+	t.Run("GetTemplate_TypeScript", func(t *testing.T) {
+		template, err := service.GetTemplate(testProblem, "typescript")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "function solution(): unknown")
+		assert.Contains(t, template, "Test Problem")
+	})
+	
+	// Test GetTemplate for pseudo-code
+	t.Run("GetTemplate_Pseudo", func(t *testing.T) {
+		template, err := service.GetTemplate(testProblem, "pseudo")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "function solution(...)")
+		assert.Contains(t, template, "# Test Problem")
+		assert.Contains(t, template, "# Time:")
+
+		withSignature := *testProblem
+		withSignature.Signature = &interfaces.FunctionSignature{
+			Name:    "square",
+			Params:  []interfaces.Param{{Name: "n", Type: "int"}},
+			Returns: "int",
+		}
+		template, err = service.GetTemplate(&withSignature, "pseudo")
+		assert.NoError(t, err)
+		assert.Contains(t, template, "function square(n: int) -> int")
+		assert.Equal(t, "square", NewPseudoGenerator().GetFunctionName(template))
+	})
+	
 	// Test GetTemplate for an unsupported language
 	t.Run("GetTemplate_Unsupported", func(t *testing.T) {
 		template, err := service.GetTemplate(testProblem, "unsupported")
@@ -150,6 +210,35 @@ function runTests() {
 			return a + b;
 		}`
 		assert.Equal(t, "calculateSum", jsGenerator.GetFunctionName(jsArrowCode))
+		
+		// Rust, skipping main
+		rustCode := `fn main() {}
+
+		pub fn calculate_sum(a: i32, b: i32) -> i32 {
+			a + b
+		}`
+		rustGenerator := NewRustGenerator()
+		assert.Equal(t, "calculate_sum", rustGenerator.GetFunctionName(rustCode))
+		
+		// Java, skipping main
+		javaCode := `class Solution {
+			public static void main(String[] args) {}
+
+			public int calculateSum(int a, int b) {
+				return a + b;
+			}
+		}`
+		javaGenerator := NewJavaGenerator()
+		assert.Equal(t, "calculateSum", javaGenerator.GetFunctionName(javaCode))
+		
+		// C++, skipping main
+		cppCode := `int main() { return 0; }
+
+		vector<int> calculateSum(const vector<int>& a, int b) {
+			return a;
+		}`
+		cppGenerator := NewCppGenerator()
+		assert.Equal(t, "calculateSum", cppGenerator.GetFunctionName(cppCode))
 	})
 }
 
@@ -0,0 +1,90 @@
+package template
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// builtinSkeletons holds one language-neutral outline per pattern
+//
+//go:embed skeletons/*.txt
+var builtinSkeletons embed.FS
+
+// skeletonExtensions maps languages to the file extension of user skeletons
+var skeletonExtensions = map[string]string{
+	"go":         "go",
+	"python":     "py",
+	"javascript": "js",
+	"typescript": "ts",
+	"java":       "java",
+	"cpp":        "cpp",
+	"rust":       "rs",
+}
+
+// SkeletonDir returns the directory holding the user's own pattern skeletons.
+// Exported as variable for testing
+var SkeletonDir = func() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".algo-scales", "templates")
+}
+
+// PatternSkeleton returns the skeleton for a pattern, ready to insert into a
+// solution file in the given language. A user's <pattern>.<ext> file in
+// SkeletonDir is used as it is; a user's <pattern>.txt outline, or else the
+// built-in one, is inserted as comments so the solution still compiles.
+func PatternSkeleton(pattern, language string) (string, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" || strings.ContainsAny(pattern, `/\.`) {
+		return "", fmt.Errorf("invalid pattern: %q", pattern)
+	}
+
+	dir := SkeletonDir()
+	if ext, ok := skeletonExtensions[language]; ok {
+		if data, err := os.ReadFile(filepath.Join(dir, pattern+"."+ext)); err == nil {
+			return strings.TrimRight(string(data), "\n"), nil
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, pattern+".txt"))
+	if err != nil {
+		data, err = builtinSkeletons.ReadFile("skeletons/" + pattern + ".txt")
+		if err != nil {
+			return "", fmt.Errorf("no skeleton for the %s pattern", pattern)
+		}
+	}
+	return commentLines(strings.TrimRight(string(data), "\n"), language), nil
+}
+
+// InsertSkeleton appends a skeleton below the code, leaving code that already
+// contains it unchanged
+func InsertSkeleton(code, skeleton string) string {
+	if strings.Contains(code, skeleton) {
+		return code
+	}
+	code = strings.TrimRight(code, "\n")
+	if code == "" {
+		return skeleton + "\n"
+	}
+	return code + "\n\n" + skeleton + "\n"
+}
+
+// commentLines turns each line of an outline into a line comment
+func commentLines(text, language string) string {
+	prefix := "//"
+	if language == "python" {
+		prefix = "#"
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = prefix
+		} else {
+			lines[i] = prefix + " " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
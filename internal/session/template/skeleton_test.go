@@ -0,0 +1,61 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternSkeleton(t *testing.T) {
+	dir := t.TempDir()
+	original := SkeletonDir
+	SkeletonDir = func() string { return dir }
+	defer func() { SkeletonDir = original }()
+
+	// Every pattern with problems has a built-in skeleton
+	patterns, err := filepath.Glob(filepath.Join("..", "..", "..", "problems", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, patterns)
+	for _, pattern := range patterns {
+		_, err := PatternSkeleton(filepath.Base(pattern), "go")
+		assert.NoError(t, err, filepath.Base(pattern))
+	}
+
+	skeleton, err := PatternSkeleton("sliding-window", "python")
+	require.NoError(t, err)
+	assert.Contains(t, skeleton, "# Sliding window skeleton\n# left = 0\n")
+
+	skeleton, err = PatternSkeleton("BFS", "go")
+	require.NoError(t, err)
+	assert.Contains(t, skeleton, "// BFS skeleton\n")
+
+	// A user's outline replaces the built-in one, and a file in the
+	// language is used as it is
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bfs.txt"), []byte("My BFS\n\nqueue it\n"), 0644))
+	skeleton, err = PatternSkeleton("bfs", "javascript")
+	require.NoError(t, err)
+	assert.Equal(t, "// My BFS\n//\n// queue it", skeleton)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bfs.py"), []byte("from collections import deque\n"), 0644))
+	skeleton, err = PatternSkeleton("bfs", "python")
+	require.NoError(t, err)
+	assert.Equal(t, "from collections import deque", skeleton)
+
+	_, err = PatternSkeleton("backtracking", "go")
+	assert.EqualError(t, err, "no skeleton for the backtracking pattern")
+	_, err = PatternSkeleton("../config", "go")
+	assert.Error(t, err)
+}
+
+func TestInsertSkeleton(t *testing.T) {
+	code := "def solve(nums):\n    pass\n"
+	inserted := InsertSkeleton(code, "# skeleton")
+	assert.Equal(t, "def solve(nums):\n    pass\n\n# skeleton\n", inserted)
+
+	// Inserting twice leaves the code alone
+	assert.Equal(t, inserted, InsertSkeleton(inserted, "# skeleton"))
+	assert.Equal(t, "# skeleton\n", InsertSkeleton("", "# skeleton"))
+}
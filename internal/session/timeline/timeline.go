@@ -0,0 +1,274 @@
+// Package timeline records what happened during a session: the code as it
+// was saved, test runs, hints and the outcome, so 'algo-scales replay' can
+// step back through how a problem was approached
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Kind is what happened at a point in a session
+type Kind string
+
+const (
+	// KindSnapshot is the code as it was saved
+	KindSnapshot Kind = "snapshot"
+	// KindTest is a run of the example tests
+	KindTest Kind = "test"
+	// KindSubmit is a run of every tier of tests
+	KindSubmit Kind = "submit"
+	// KindHint is a hint requested
+	KindHint Kind = "hint"
+	// KindSolution is the solution viewed
+	KindSolution Kind = "solution"
+	// KindResume is the session picked up again after quitting
+	KindResume Kind = "resume"
+	// KindFinish ends the session
+	KindFinish Kind = "finish"
+)
+
+// Event is one point in a session's timeline
+type Event struct {
+	Time    time.Time     `json:"time"`
+	Kind    Kind          `json:"kind"`
+	Elapsed time.Duration `json:"elapsed"` // Session time spent when it happened
+	Code    string        `json:"code,omitempty"`
+	Passed  int           `json:"passed,omitempty"`
+	Total   int           `json:"total,omitempty"`
+	Level   int           `json:"level,omitempty"` // Hint level
+	Solved  bool          `json:"solved,omitempty"`
+	Detail  string        `json:"detail,omitempty"`
+}
+
+// Describe says what happened, such as "Ran tests: 2/3 passed"
+func (e Event) Describe() string {
+	var s string
+	switch e.Kind {
+	case KindSnapshot:
+		lines := strings.Count(e.Code, "\n") + 1
+		s = fmt.Sprintf("Saved code (%d %s)", lines, plural(lines, "line"))
+	case KindTest:
+		s = fmt.Sprintf("Ran tests: %d/%d passed", e.Passed, e.Total)
+	case KindSubmit:
+		s = fmt.Sprintf("Submitted: %d/%d passed", e.Passed, e.Total)
+	case KindHint:
+		s = fmt.Sprintf("Requested hint level %d", e.Level)
+	case KindSolution:
+		s = "Viewed the solution"
+	case KindResume:
+		s = "Resumed the session"
+	case KindFinish:
+		s = "Finished: not solved"
+		if e.Solved {
+			s = "Finished: solved"
+		}
+	default:
+		s = string(e.Kind)
+	}
+	if e.Detail != "" {
+		s += " (" + e.Detail + ")"
+	}
+	return s
+}
+
+// Timeline is a session's events, in the order they happened
+type Timeline struct {
+	SessionID string    `json:"session_id"`
+	ProblemID string    `json:"problem_id"`
+	Title     string    `json:"title"`
+	Language  string    `json:"language"`
+	StartedAt time.Time `json:"started_at"`
+	Events    []Event   `json:"events"`
+}
+
+// Code returns the code as it stood at event i: the latest snapshot at or
+// before it, or "" if none was saved yet
+func (t Timeline) Code(i int) string {
+	for ; i >= 0; i-- {
+		if i < len(t.Events) && t.Events[i].Kind == KindSnapshot {
+			return t.Events[i].Code
+		}
+	}
+	return ""
+}
+
+// Summary describes a recorded timeline without its events
+type Summary struct {
+	SessionID string    `json:"session_id"`
+	ProblemID string    `json:"problem_id"`
+	Title     string    `json:"title"`
+	Language  string    `json:"language"`
+	StartedAt time.Time `json:"started_at"`
+	Events    int       `json:"events"`
+	Finished  bool      `json:"finished"`
+	Solved    bool      `json:"solved"`
+}
+
+// NewID returns the ID of the session on a problem started at start. A
+// session resumed with its start time keeps its ID, and its timeline.
+func NewID(problemID string, start time.Time) string {
+	return fmt.Sprintf("%s-%d", problemID, start.Unix())
+}
+
+// backend is the user data backend timelines are kept in
+// Exported as variable for testing
+var backend = storage.UserData
+
+// indexName is the file listing the recorded timelines
+const indexName = "timelines.json"
+
+// timelineName is the file a session's timeline is kept in
+func timelineName(id string) string {
+	return "timelines/" + id + ".json"
+}
+
+// Record adds an event to a session's timeline, starting the timeline
+// from session if it has none yet. A snapshot of unchanged code is left out.
+func Record(session Timeline, e Event) error {
+	b, err := backend()
+	if err != nil {
+		return err
+	}
+	t, err := load(b, session.SessionID)
+	if errors.Is(err, fs.ErrNotExist) {
+		session.Events = nil
+		t = &session
+	} else if err != nil {
+		return err
+	}
+
+	if e.Kind == KindSnapshot && len(t.Events) > 0 && t.Code(len(t.Events)-1) == e.Code {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	t.Events = append(t.Events, e)
+
+	if err := put(b, timelineName(t.SessionID), t); err != nil {
+		return err
+	}
+	return index(b, *t)
+}
+
+// Load returns a session's timeline
+func Load(id string) (*Timeline, error) {
+	b, err := backend()
+	if err != nil {
+		return nil, err
+	}
+	t, err := load(b, id)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no timeline was recorded for session %s", id)
+	}
+	return t, err
+}
+
+// List returns the recorded timelines, the most recent first
+func List() ([]Summary, error) {
+	b, err := backend()
+	if err != nil {
+		return nil, err
+	}
+	return loadIndex(b)
+}
+
+// load reads a session's timeline; a missing one gives an error matching
+// fs.ErrNotExist
+func load(b storage.Backend, id string) (*Timeline, error) {
+	data, err := b.Get(context.Background(), timelineName(id))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read timeline: %v", err)
+	}
+	var t Timeline
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to decode timeline: %v", err)
+	}
+	return &t, nil
+}
+
+// loadIndex reads the list of timelines
+func loadIndex(b storage.Backend) ([]Summary, error) {
+	data, err := b.Get(context.Background(), indexName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timelines: %v", err)
+	}
+	var summaries []Summary
+	if err := json.Unmarshal(data, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to decode timelines: %v", err)
+	}
+	return summaries, nil
+}
+
+// index updates a timeline's entry in the list of timelines
+func index(b storage.Backend, t Timeline) error {
+	summaries, err := loadIndex(b)
+	if err != nil {
+		return err
+	}
+	summary := Summary{
+		SessionID: t.SessionID,
+		ProblemID: t.ProblemID,
+		Title:     t.Title,
+		Language:  t.Language,
+		StartedAt: t.StartedAt,
+		Events:    len(t.Events),
+	}
+	if last := t.Events[len(t.Events)-1]; last.Kind == KindFinish {
+		summary.Finished, summary.Solved = true, last.Solved
+	}
+
+	replaced := false
+	for i, s := range summaries {
+		if s.SessionID == t.SessionID {
+			summaries[i], replaced = summary, true
+		}
+	}
+	if !replaced {
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].StartedAt.After(summaries[j].StartedAt)
+	})
+	return put(b, indexName, summaries)
+}
+
+// put writes v as JSON
+func put(b storage.Backend, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", name, err)
+	}
+	if err := b.Put(context.Background(), name, data); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// FormatElapsed formats session time as HH:MM:SS
+func FormatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// plural returns word, with an s unless n is 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,75 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBackend keeps timelines in a temporary directory for the test
+func stubBackend(t *testing.T) {
+	t.Helper()
+	original := backend
+	b := storage.NewLocalBackend(t.TempDir())
+	backend = func() (storage.Backend, error) { return b, nil }
+	t.Cleanup(func() { backend = original })
+}
+
+func TestRecord(t *testing.T) {
+	stubBackend(t)
+	start := time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+	session := Timeline{SessionID: NewID("two_sum", start), ProblemID: "two_sum", Title: "Two Sum", Language: "go", StartedAt: start}
+	assert.Equal(t, "two_sum-1772647200", session.SessionID)
+
+	list, err := List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = Load(session.SessionID)
+	assert.ErrorContains(t, err, "no timeline was recorded for session two_sum-1772647200")
+
+	events := []Event{
+		{Kind: KindSnapshot, Elapsed: time.Minute, Code: "func twoSum() {\n}"},
+		{Kind: KindSnapshot, Elapsed: 2 * time.Minute, Code: "func twoSum() {\n}"}, // Unchanged
+		{Kind: KindTest, Elapsed: 3 * time.Minute, Passed: 1, Total: 3},
+		{Kind: KindHint, Elapsed: 4 * time.Minute, Level: 1},
+		{Kind: KindSnapshot, Elapsed: 5 * time.Minute, Code: "func twoSum() {\n\treturn nil\n}"},
+		{Kind: KindSubmit, Elapsed: 6 * time.Minute, Passed: 5, Total: 5},
+		{Kind: KindFinish, Elapsed: 6 * time.Minute, Solved: true},
+	}
+	for _, e := range events {
+		require.NoError(t, Record(session, e))
+	}
+
+	timeline, err := Load(session.SessionID)
+	require.NoError(t, err)
+	require.Len(t, timeline.Events, 6, "the unchanged snapshot is left out")
+	assert.Equal(t, "Two Sum", timeline.Title)
+	assert.False(t, timeline.Events[0].Time.IsZero())
+	assert.Equal(t, "", timeline.Code(-1))
+	assert.Equal(t, "func twoSum() {\n}", timeline.Code(2), "tests run against the last code saved")
+	assert.Equal(t, "func twoSum() {\n\treturn nil\n}", timeline.Code(5))
+
+	// A second session lists before the first
+	later := Timeline{SessionID: NewID("coin_change", start.Add(time.Hour)), ProblemID: "coin_change", StartedAt: start.Add(time.Hour)}
+	require.NoError(t, Record(later, Event{Kind: KindSnapshot, Code: "def coin_change(): pass"}))
+
+	list, err = List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "coin_change", list[0].ProblemID)
+	assert.False(t, list[0].Finished)
+	assert.Equal(t, Summary{SessionID: session.SessionID, ProblemID: "two_sum", Title: "Two Sum", Language: "go", StartedAt: start, Events: 6, Finished: true, Solved: true}, list[1])
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "Saved code (1 line)", Event{Kind: KindSnapshot, Code: "pass"}.Describe())
+	assert.Equal(t, "Saved code (3 lines)", Event{Kind: KindSnapshot, Code: "a\nb\nc"}.Describe())
+	assert.Equal(t, "Ran tests: 2/3 passed", Event{Kind: KindTest, Passed: 2, Total: 3}.Describe())
+	assert.Equal(t, "Submitted: 5/5 passed", Event{Kind: KindSubmit, Passed: 5, Total: 5}.Describe())
+	assert.Equal(t, "Requested hint level 2", Event{Kind: KindHint, Level: 2}.Describe())
+	assert.Equal(t, "Finished: solved", Event{Kind: KindFinish, Solved: true}.Describe())
+	assert.Equal(t, "Finished: not solved (switched to Two Sum)", Event{Kind: KindFinish, Detail: "switched to Two Sum"}.Describe())
+}
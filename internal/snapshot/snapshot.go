@@ -0,0 +1,132 @@
+// Package snapshot packages a solution and its failing tests for sharing.
+// Snapshots are encrypted with a key that travels only in the share
+// reference, so the server storing them cannot read them.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Version is the snapshot format version
+const Version = 1
+
+// magic starts every sealed snapshot, so other files are recognized as not
+// being snapshots rather than failing to decrypt
+var magic = []byte("ASNAP1")
+
+// keySize is the AES-256 key length
+const keySize = 32
+
+// Snapshot is a solution, as it was when shared, with the tests it failed
+type Snapshot struct {
+	Version   int       `json:"version"`
+	ProblemID string    `json:"problem_id"`
+	Title     string    `json:"title"`
+	Language  string    `json:"language"`
+	Code      string    `json:"code"`
+	Note      string    `json:"note,omitempty"` // The question for whoever opens it
+	Passed    int       `json:"passed"`
+	Total     int       `json:"total"`
+	Failures  []Failure `json:"failures,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Failure is a test the solution failed
+type Failure struct {
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Tier     string `json:"tier,omitempty"`
+}
+
+// Seal encrypts a snapshot with a new key, returning the sealed blob and
+// the key to share with it
+func Seal(s Snapshot) ([]byte, string, error) {
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+
+	blob := append(append([]byte{}, magic...), nonce...)
+	blob = gcm.Seal(blob, nonce, plaintext, magic)
+	return blob, base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+// Open decrypts a sealed snapshot
+func Open(blob []byte, key string) (Snapshot, error) {
+	var s Snapshot
+	if !bytes.HasPrefix(blob, magic) {
+		return s, errors.New("not an algo-scales snapshot")
+	}
+	rawKey, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil || len(rawKey) != keySize {
+		return s, errors.New("invalid snapshot key")
+	}
+	gcm, err := newGCM(rawKey)
+	if err != nil {
+		return s, err
+	}
+
+	sealed := blob[len(magic):]
+	if len(sealed) < gcm.NonceSize() {
+		return s, errors.New("snapshot is truncated")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, magic)
+	if err != nil {
+		return s, errors.New("wrong key, or the snapshot was modified")
+	}
+
+	if err := json.Unmarshal(plaintext, &s); err != nil {
+		return s, fmt.Errorf("invalid snapshot: %v", err)
+	}
+	if s.Version > Version {
+		return s, fmt.Errorf("snapshot version %d is newer than this algo-scales supports; please upgrade", s.Version)
+	}
+	return s, nil
+}
+
+// Reference joins where a snapshot is, a server ID, URL or file, with its
+// key, the way a URL carries a fragment
+func Reference(location, key string) string {
+	return location + "#" + key
+}
+
+// ParseReference splits a reference into where the snapshot is and its key
+func ParseReference(ref string) (location, key string, err error) {
+	i := strings.LastIndex(ref, "#")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", errors.New("snapshot reference needs a key after '#'")
+	}
+	return ref[:i], ref[i+1:], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	s := Snapshot{
+		Version:   Version,
+		ProblemID: "two_sum",
+		Title:     "Two Sum",
+		Language:  "go",
+		Code:      "func twoSum(nums []int, target int) []int { return nil }",
+		Note:      "Why does this return nil?",
+		Passed:    1,
+		Total:     2,
+		Failures:  []Failure{{Input: "[3,3], 6", Expected: "[0,1]", Actual: "[]", Tier: "example"}},
+		CreatedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+
+	blob, key, err := Seal(s)
+	require.NoError(t, err)
+	assert.NotContains(t, string(blob), "twoSum", "the code is encrypted")
+
+	opened, err := Open(blob, key)
+	require.NoError(t, err)
+	assert.Equal(t, s, opened)
+
+	t.Run("WrongKey", func(t *testing.T) {
+		_, otherKey, err := Seal(s)
+		require.NoError(t, err)
+		_, err = Open(blob, otherKey)
+		assert.ErrorContains(t, err, "wrong key")
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		modified := append([]byte{}, blob...)
+		modified[len(modified)-1] ^= 1
+		_, err := Open(modified, key)
+		assert.ErrorContains(t, err, "modified")
+	})
+
+	t.Run("NotASnapshot", func(t *testing.T) {
+		_, err := Open([]byte("package main"), key)
+		assert.ErrorContains(t, err, "not an algo-scales snapshot")
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := Open(blob, "short")
+		assert.ErrorContains(t, err, "invalid snapshot key")
+	})
+}
+
+func TestParseReference(t *testing.T) {
+	location, key, err := ParseReference(Reference("https://api.example.com/v1/snapshots/abc", "k3y"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/v1/snapshots/abc", location)
+	assert.Equal(t, "k3y", key)
+
+	location, key, err = ParseReference("snap-1#k")
+	require.NoError(t, err)
+	assert.Equal(t, "snap-1", location)
+	assert.Equal(t, "k", key)
+
+	for _, ref := range []string{"snap-1", "snap-1#", "#key"} {
+		_, _, err := ParseReference(ref)
+		assert.Error(t, err, ref)
+	}
+}
@@ -0,0 +1,143 @@
+// Line-by-line comparison of two solutions
+
+package solutions
+
+import "strings"
+
+// Op is how a line differs between two solutions
+type Op byte
+
+const (
+	Same    Op = ' ' // In both solutions
+	Removed Op = '-' // Only in the earlier solution
+	Added   Op = '+' // Only in the later solution
+)
+
+// Line is a line of a diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Diff compares two solutions line by line. It gives the lines of both in
+// order, keeping the longest run of lines they share and marking the rest
+// as removed from the earlier one or added in the later one.
+func Diff(from, to string) []Line {
+	a, b := splitLines(from), splitLines(to)
+
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Same, a[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, Line{Removed, a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Added, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Removed, a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Added, b[j]})
+	}
+	return lines
+}
+
+// Row is a line of a side-by-side diff, pairing a line of the earlier
+// solution with one of the later solution
+type Row struct {
+	Left, Right int  // 1-based line numbers on each side; 0 where that side has no line
+	Changed     bool // The two sides differ
+}
+
+// SideBySide aligns a diff for showing the two solutions next to each
+// other. Shared lines sit on the same row, and within each run of changes
+// the removed lines are paired in order with the added ones.
+func SideBySide(lines []Line) []Row {
+	var rows []Row
+	left, right := 0, 0
+	var removed, added []int
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			row := Row{Changed: true}
+			if i < len(removed) {
+				row.Left = removed[i]
+			}
+			if i < len(added) {
+				row.Right = added[i]
+			}
+			rows = append(rows, row)
+		}
+		removed, added = nil, nil
+	}
+	for _, l := range lines {
+		switch l.Op {
+		case Removed:
+			left++
+			removed = append(removed, left)
+		case Added:
+			right++
+			added = append(added, right)
+		default:
+			flush()
+			left++
+			right++
+			rows = append(rows, Row{Left: left, Right: right})
+		}
+	}
+	flush()
+	return rows
+}
+
+// Changed reports whether a diff has any added or removed lines
+func Changed(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != Same {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders a diff with "-", "+" or a space before each line, and
+// blank lines left empty
+func Format(lines []Line) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(strings.TrimRight(string(l.Op)+" "+l.Text, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// splitLines splits code into lines, ignoring a final newline
+func splitLines(code string) []string {
+	code = strings.TrimRight(code, "\n")
+	if code == "" {
+		return nil
+	}
+	return strings.Split(code, "\n")
+}
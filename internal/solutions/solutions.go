@@ -0,0 +1,59 @@
+// Package solutions keeps the history of the solutions tried on each
+// problem, for listing, comparing and restoring earlier attempts
+package solutions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Solution is one attempt in a problem's history
+type Solution struct {
+	Number int // 1-based position in the history, oldest first
+	storage.Attempt
+}
+
+// TestsPassed counts the tests the solution passed
+func (s Solution) TestsPassed() int {
+	passed := 0
+	for _, r := range s.Results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// Lines counts the lines of the solution's code
+func (s Solution) Lines() int {
+	code := strings.TrimRight(s.Code, "\n")
+	if code == "" {
+		return 0
+	}
+	return strings.Count(code, "\n") + 1
+}
+
+// History numbers a problem's attempts that kept their code, oldest first.
+// Only submissions are included unless runs is set, which adds the runs of
+// the example tests. Attempts recorded before submissions were marked count
+// as runs.
+func History(attempts []storage.Attempt, problemID string, runs bool) []Solution {
+	var history []Solution
+	for _, a := range attempts {
+		if a.ProblemID != problemID || a.Code == "" || (!a.Submitted && !runs) {
+			continue
+		}
+		history = append(history, Solution{Number: len(history) + 1, Attempt: a})
+	}
+	return history
+}
+
+// Get returns the solution numbered n in a history
+func Get(history []Solution, n int) (Solution, error) {
+	if n < 1 || n > len(history) {
+		return Solution{}, fmt.Errorf("there is no solution %d; this problem has %d", n, len(history))
+	}
+	return history[n-1], nil
+}
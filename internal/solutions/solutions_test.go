@@ -0,0 +1,75 @@
+package solutions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	attempts := []storage.Attempt{
+		{ProblemID: "two_sum", Time: start, Code: "v1"},
+		{ProblemID: "two_sum", Time: start.Add(time.Minute), Code: "v1", Submitted: true,
+			Results: []storage.TestResult{{Number: 1, Passed: true}, {Number: 2}}},
+		{ProblemID: "coin_change", Time: start.Add(2 * time.Minute), Code: "dp", Submitted: true},
+		{ProblemID: "two_sum", Time: start.Add(3 * time.Minute), Submitted: true}, // Recorded without its code
+		{ProblemID: "two_sum", Time: start.Add(4 * time.Minute), Code: "v2\nreturn\n", Passed: true, Submitted: true},
+	}
+
+	history := History(attempts, "two_sum", false)
+	require.Len(t, history, 2)
+	assert.Equal(t, 1, history[0].Number)
+	assert.Equal(t, start.Add(time.Minute), history[0].Time)
+	assert.Equal(t, 1, history[0].TestsPassed())
+	assert.Equal(t, 2, history[1].Lines())
+
+	// Runs of the examples are only listed on request
+	history = History(attempts, "two_sum", true)
+	require.Len(t, history, 3)
+	assert.Equal(t, start, history[0].Time)
+	assert.Equal(t, 3, history[2].Number)
+
+	s, err := Get(history, 3)
+	require.NoError(t, err)
+	assert.True(t, s.Passed)
+	_, err = Get(history, 4)
+	assert.EqualError(t, err, "there is no solution 4; this problem has 3")
+}
+
+func TestDiff(t *testing.T) {
+	from := "func twoSum(nums []int) []int {\n\tfor i := range nums {\n\t\tfor j := range nums {\n\n\treturn nil\n}\n"
+	to := "func twoSum(nums []int) []int {\n\tseen := map[int]int{}\n\tfor i := range nums {\n\n\treturn nil\n}\n"
+
+	lines := Diff(from, to)
+	assert.True(t, Changed(lines))
+	assert.Equal(t, []Line{
+		{Same, "func twoSum(nums []int) []int {"},
+		{Added, "\tseen := map[int]int{}"},
+		{Same, "\tfor i := range nums {"},
+		{Removed, "\t\tfor j := range nums {"},
+		{Same, ""},
+		{Same, "\treturn nil"},
+		{Same, "}"},
+	}, lines)
+	assert.Equal(t, "  func twoSum(nums []int) []int {\n+ \tseen := map[int]int{}\n  \tfor i := range nums {\n- \t\tfor j := range nums {\n\n  \treturn nil\n  }\n", Format(lines))
+
+	assert.Equal(t, []Row{
+		{Left: 1, Right: 1},
+		{Right: 2, Changed: true},
+		{Left: 2, Right: 3},
+		{Left: 3, Changed: true},
+		{Left: 4, Right: 4},
+		{Left: 5, Right: 5},
+		{Left: 6, Right: 6},
+	}, SideBySide(lines))
+	// A changed line sits beside its replacement
+	assert.Equal(t, []Row{{Left: 1, Right: 1, Changed: true}, {Left: 2, Right: 2}},
+		SideBySide(Diff("a := 1\nreturn a\n", "a := 2\nreturn a\n")))
+
+	assert.False(t, Changed(Diff(from, from)))
+	assert.Equal(t, []Line{{Added, "x"}}, Diff("", "x\n"))
+}
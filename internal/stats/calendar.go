@@ -0,0 +1,102 @@
+// Contribution calendar of daily practice
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// CalendarWeeks is how many weeks the calendar covers, a year in whole
+// weeks
+const CalendarWeeks = 53
+
+// CalendarLevels is how many shades the calendar draws days in, the first
+// for days without practice
+const CalendarLevels = 5
+
+// calendarDateLayout formats the calendar's days
+const calendarDateLayout = "2006-01-02"
+
+// CalendarDay is one day of practice
+type CalendarDay struct {
+	Date      string `json:"date"`
+	Attempted int    `json:"attempted"` // Sessions started that day
+	Solved    int    `json:"solved"`
+}
+
+// Calendar is the daily practice of the past year, GitHub style: whole
+// weeks from Sunday, ending with the week of today
+type Calendar struct {
+	Days       []CalendarDay `json:"days"` // Oldest first, ending today
+	Total      int           `json:"total"`
+	ActiveDays int           `json:"active_days"`
+	Busiest    CalendarDay   `json:"busiest"` // Empty if there was no practice
+}
+
+// BuildCalendar counts the sessions started on each day of the year up to
+// now, in local time
+func BuildCalendar(sessions []interfaces.SessionStats, now time.Time) Calendar {
+	today := now.Local()
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(CalendarWeeks-1))
+
+	var cal Calendar
+	index := make(map[string]int)
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(calendarDateLayout)
+		index[date] = len(cal.Days)
+		cal.Days = append(cal.Days, CalendarDay{Date: date})
+	}
+
+	for _, s := range sessions {
+		i, ok := index[s.StartTime.Local().Format(calendarDateLayout)]
+		if !ok {
+			continue
+		}
+		cal.Days[i].Attempted++
+		if s.Solved {
+			cal.Days[i].Solved++
+		}
+		cal.Total++
+	}
+
+	for _, day := range cal.Days {
+		if day.Attempted == 0 {
+			continue
+		}
+		cal.ActiveDays++
+		if day.Attempted > cal.Busiest.Attempted {
+			cal.Busiest = day
+		}
+	}
+	return cal
+}
+
+// Level returns the shade a day is drawn in, from 0 for no practice to
+// CalendarLevels-1 for the busiest days. Shades split the busiest day's
+// count into even steps.
+func (c Calendar) Level(day CalendarDay) int {
+	if day.Attempted <= 0 || c.Busiest.Attempted <= 0 {
+		return 0
+	}
+	steps := CalendarLevels - 1
+	level := (day.Attempted*steps + c.Busiest.Attempted - 1) / c.Busiest.Attempted
+	return min(max(level, 1), steps)
+}
+
+// GetCalendar returns the practice calendar as of now
+func (s *Service) GetCalendar(ctx context.Context, now time.Time) (Calendar, error) {
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	return BuildCalendar(sessions, now), nil
+}
+
+// GetCalendar returns the practice calendar of the past year
+var GetCalendar = func() (Calendar, error) {
+	return getDefaultService().GetCalendar(context.Background(), time.Now())
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCalendar(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	sessions := []interfaces.SessionStats{
+		{ProblemID: "two_sum", StartTime: now.Add(-time.Hour), Solved: true},
+		{ProblemID: "3sum", StartTime: now.Add(-2 * time.Hour)},
+		{ProblemID: "two_sum", StartTime: now.AddDate(0, 0, -10), Solved: true},
+		{ProblemID: "old", StartTime: now.AddDate(-2, 0, 0)},
+	}
+
+	cal := BuildCalendar(sessions, now)
+	require.Len(t, cal.Days, 7*(CalendarWeeks-1)+4, "whole weeks from Sunday up to today")
+	first, err := time.Parse(calendarDateLayout, cal.Days[0].Date)
+	require.NoError(t, err)
+	assert.Equal(t, time.Sunday, first.Weekday())
+	assert.Equal(t, CalendarDay{Date: "2026-03-04", Attempted: 2, Solved: 1}, cal.Days[len(cal.Days)-1])
+	assert.Equal(t, CalendarDay{Date: "2026-02-22", Attempted: 1, Solved: 1}, cal.Days[len(cal.Days)-11])
+
+	assert.Equal(t, 3, cal.Total, "sessions older than the calendar are left out")
+	assert.Equal(t, 2, cal.ActiveDays)
+	assert.Equal(t, "2026-03-04", cal.Busiest.Date)
+}
+
+func TestCalendarLevel(t *testing.T) {
+	cal := Calendar{Busiest: CalendarDay{Attempted: 8}}
+	for attempted, want := range map[int]int{0: 0, 1: 1, 2: 1, 3: 2, 5: 3, 7: 4, 8: 4} {
+		assert.Equal(t, want, cal.Level(CalendarDay{Attempted: attempted}), "%d attempted", attempted)
+	}
+	assert.Equal(t, 0, Calendar{}.Level(CalendarDay{Attempted: 1}))
+}
+
+func TestServiceGetCalendar(t *testing.T) {
+	storage := NewMockStorage()
+	service := NewService().WithStorage(storage)
+	now := time.Now()
+	storage.AddSession(interfaces.SessionStats{ProblemID: "two_sum", StartTime: now.Add(-time.Minute)})
+
+	cal, err := service.GetCalendar(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cal.Total)
+	assert.Equal(t, 1, cal.Days[len(cal.Days)-1].Attempted)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 )
 
 // DefaultService is the default stats service instance
@@ -42,10 +43,18 @@ var RecordSession = func(stats SessionStats) error {
 		SolutionUsed: stats.SolutionUsed,
 		Patterns:     stats.Patterns,
 		Difficulty:   stats.Difficulty,
+		Environment:  stats.Environment,
+		SwappedTo:    stats.SwappedTo,
+		Context:      stats.Context,
 	}
 	return getDefaultService().RecordSession(context.Background(), interfaceStats)
 }
 
+// RecordAttempt records a run of a solution's tests
+var RecordAttempt = func(attempt storage.Attempt) error {
+	return getDefaultService().RecordAttempt(context.Background(), attempt)
+}
+
 // GetSummary returns summary statistics
 var GetSummary = func() (*Summary, error) {
 	interfaceSummary, err := getDefaultService().GetSummary(context.Background())
@@ -59,6 +68,7 @@ var GetSummary = func() (*Summary, error) {
 		TotalSolved:    interfaceSummary.TotalSolved,
 		AvgSolveTime:   interfaceSummary.AvgSolveTime,
 		SuccessRate:    interfaceSummary.SuccessRate,
+		Swaps:          interfaceSummary.Swaps,
 		FastestSolve:   interfaceSummary.FastestSolve,
 		MostChallenging: interfaceSummary.MostChallenging,
 	}
@@ -86,6 +96,20 @@ var GetByPattern = func() (map[string]PatternStats, error) {
 	return localStats, nil
 }
 
+// GetByContext returns statistics by the entry point that recorded sessions
+var GetByContext = func() (map[string]ContextStats, error) {
+	interfaceStats, err := getDefaultService().GetByContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	localStats := make(map[string]ContextStats)
+	for name, stats := range interfaceStats {
+		localStats[name] = ContextStats(stats)
+	}
+	return localStats, nil
+}
+
 // GetTrends returns usage trends over time
 var GetTrends = func() (*Trends, error) {
 	interfaceTrends, err := getDefaultService().GetTrends(context.Background())
@@ -145,6 +169,9 @@ var GetAllSessions = func() ([]SessionStats, error) {
 			SolutionUsed: s.SolutionUsed,
 			Patterns:     s.Patterns,
 			Difficulty:   s.Difficulty,
+			Environment:  s.Environment,
+			SwappedTo:    s.SwappedTo,
+			Context:      s.Context,
 		}
 	}
 	return localSessions, nil
@@ -0,0 +1,45 @@
+// Progress toward the user's goals, measured against the recorded sessions
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/goals"
+)
+
+// GetGoalProgress returns each goal's progress as of now, or nothing if no
+// goals are set
+func (s *Service) GetGoalProgress(ctx context.Context, now time.Time) ([]goals.Progress, error) {
+	list, err := goals.Load()
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	return goals.Evaluate(list, sessions, now), nil
+}
+
+// announceGoals announces the goals newly reached with the sessions
+// recorded so far. Goals are a nicety, so failures are ignored rather than
+// failing the session.
+func (s *Service) announceGoals(ctx context.Context) {
+	list, err := goals.Load()
+	if err != nil || len(list) == 0 {
+		return
+	}
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return
+	}
+	_, _ = goals.Announce(list, sessions, time.Now())
+}
+
+// GetGoalProgress returns the progress of each goal
+var GetGoalProgress = func() ([]goals.Progress, error) {
+	return getDefaultService().GetGoalProgress(context.Background(), time.Now())
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceGoals(t *testing.T) {
+	originalDir, originalInbox := utils.GetConfigDir, notifications.GetInboxPath
+	dir := t.TempDir()
+	utils.GetConfigDir = func() string { return dir }
+	notifications.GetInboxPath = func() string { return filepath.Join(dir, "notifications.json") }
+	t.Cleanup(func() { utils.GetConfigDir, notifications.GetInboxPath = originalDir, originalInbox })
+
+	service := NewService().WithStorage(NewMockStorage())
+	ctx := context.Background()
+	progress, err := service.GetGoalProgress(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, progress, "no goals set")
+
+	goal, err := goals.NewSolveGoal(1, "easy", "")
+	require.NoError(t, err)
+	_, err = goals.Set(goal)
+	require.NoError(t, err)
+
+	// Recording the session that reaches a goal announces it
+	require.NoError(t, service.RecordSession(ctx, interfaces.SessionStats{
+		ProblemID: "two_sum", Difficulty: "easy", StartTime: time.Now(), Solved: true,
+	}))
+	inbox, err := notifications.Load()
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	assert.Equal(t, "Goal reached: Solve 1 easy problem this week", inbox[0].Title)
+
+	progress, err = service.GetGoalProgress(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	assert.True(t, progress[0].Done)
+}
@@ -0,0 +1,287 @@
+// Weekly trends and weaknesses per pattern
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+const (
+	// TrendWeeks is how many weeks pattern trends cover, this week included
+	TrendWeeks = 8
+
+	// MinTrendAttempts is how many attempts a pattern needs in those weeks
+	// before it is judged
+	MinTrendAttempts = 2
+
+	// WeakThreshold is the weakness from which a pattern counts as weak
+	WeakThreshold = 0.35
+)
+
+// Weights of the parts of a pattern's weakness, adding up to 1
+const (
+	failWeight = 0.5 // Attempts left unsolved
+	hintWeight = 0.3 // Attempts that needed hints or the solution
+	slowWeight = 0.2 // Solve time over the average across patterns
+)
+
+// directionMargin is how many points the fail rate must move between the
+// first and second half of the weeks to count as a change
+const directionMargin = 15.0
+
+// Directions a pattern's fail rate can take
+const (
+	DirectionImproving = "improving"
+	DirectionDeclining = "declining"
+	DirectionSteady    = "steady"
+)
+
+// PatternWeek is one pattern's practice in one week
+type PatternWeek struct {
+	StartDate    string  `json:"start_date"` // The Monday it starts, YYYY-MM-DD
+	Attempted    int     `json:"attempted"`
+	Solved       int     `json:"solved"`
+	Hinted       int     `json:"hinted"`    // Attempts that used hints or the solution
+	FailRate     float64 `json:"fail_rate"` // Attempts left unsolved, 0-100
+	HintRate     float64 `json:"hint_rate"` // Attempts that used hints or the solution, 0-100
+	AvgSolveTime string  `json:"avg_solve_time,omitempty"`
+
+	solveTime time.Duration // Total time of the solved attempts
+}
+
+// PatternTrend is one pattern's practice over the recent weeks, and how
+// weak it looks
+type PatternTrend struct {
+	Pattern      string        `json:"pattern"`
+	Weeks        []PatternWeek `json:"weeks"` // Oldest first
+	Attempted    int           `json:"attempted"`
+	Solved       int           `json:"solved"`
+	FailRate     float64       `json:"fail_rate"`
+	HintRate     float64       `json:"hint_rate"`
+	AvgSolveTime string        `json:"avg_solve_time,omitempty"`
+	SlowFactor   float64       `json:"slow_factor,omitempty"` // Average solve time over the average across patterns
+	Direction    string        `json:"direction,omitempty"`   // How the fail rate moved; empty without practice in both halves
+	Weakness     float64       `json:"weakness"`              // From 0, strong, to 1
+	Judged       bool          `json:"judged"`                // False with fewer than MinTrendAttempts attempts
+	Weak         bool          `json:"weak"`
+	Reasons      []string      `json:"reasons,omitempty"` // What makes it weak
+
+	solveTime time.Duration
+}
+
+// PatternTrends is the recent practice of each pattern, weakest first, and
+// the pattern to practice next
+type PatternTrends struct {
+	Weeks       int            `json:"weeks"`
+	Patterns    []PatternTrend `json:"patterns"` // Judged patterns first, weakest first
+	Recommended string         `json:"recommended,omitempty"`
+	Reason      string         `json:"reason,omitempty"` // Why it is recommended
+}
+
+// Weakest returns the weak patterns, weakest first
+func (t PatternTrends) Weakest() []PatternTrend {
+	var weak []PatternTrend
+	for _, p := range t.Patterns {
+		if p.Weak {
+			weak = append(weak, p)
+		}
+	}
+	return weak
+}
+
+// BuildPatternTrends aggregates the sessions of the last TrendWeeks weeks
+// by pattern and week, in local time. Known patterns that were not
+// practiced are listed too, so one can be recommended.
+func BuildPatternTrends(sessions []interfaces.SessionStats, known []string, now time.Time) PatternTrends {
+	first := startOfWeek(now.Local()).AddDate(0, 0, -7*(TrendWeeks-1))
+	trends := PatternTrends{Weeks: TrendWeeks}
+
+	byPattern := make(map[string]*PatternTrend)
+	trend := func(pattern string) *PatternTrend {
+		if t, ok := byPattern[pattern]; ok {
+			return t
+		}
+		t := &PatternTrend{Pattern: pattern, Weeks: make([]PatternWeek, TrendWeeks)}
+		for i := range t.Weeks {
+			t.Weeks[i].StartDate = first.AddDate(0, 0, 7*i).Format("2006-01-02")
+		}
+		byPattern[pattern] = t
+		return t
+	}
+	for _, pattern := range known {
+		trend(pattern)
+	}
+
+	var solveTime time.Duration
+	var solved int
+	for _, s := range sessions {
+		start := s.StartTime.Local()
+		if start.Before(first) || start.After(now) {
+			continue
+		}
+		// Whole days, so a daylight saving change does not shift the week
+		days := int(math.Round(startOfWeek(start).Sub(first).Hours() / 24))
+		week := days / 7
+		if s.Solved {
+			solveTime += s.Duration
+			solved++
+		}
+		for _, pattern := range s.Patterns {
+			t := trend(pattern)
+			w := &t.Weeks[week]
+			w.Attempted++
+			t.Attempted++
+			if s.Solved {
+				w.Solved++
+				w.solveTime += s.Duration
+				t.Solved++
+				t.solveTime += s.Duration
+			}
+			if s.HintsUsed || s.SolutionUsed {
+				w.Hinted++
+			}
+		}
+	}
+
+	var average time.Duration
+	if solved > 0 {
+		average = solveTime / time.Duration(solved)
+	}
+	for _, t := range byPattern {
+		t.score(average)
+		trends.Patterns = append(trends.Patterns, *t)
+	}
+	sort.Slice(trends.Patterns, func(i, j int) bool {
+		a, b := trends.Patterns[i], trends.Patterns[j]
+		if a.Judged != b.Judged {
+			return a.Judged
+		}
+		if a.Weakness != b.Weakness {
+			return a.Weakness > b.Weakness
+		}
+		if a.Attempted != b.Attempted {
+			return a.Attempted > b.Attempted
+		}
+		return a.Pattern < b.Pattern
+	})
+
+	trends.Recommended, trends.Reason = recommend(trends.Patterns, known)
+	return trends
+}
+
+// score fills in the rates, direction and weakness from the weeks, given
+// the average solve time across patterns
+func (t *PatternTrend) score(average time.Duration) {
+	var hinted int
+	var early, late PatternWeek
+	for i := range t.Weeks {
+		w := &t.Weeks[i]
+		hinted += w.Hinted
+		if w.Attempted > 0 {
+			w.FailRate = rate(w.Attempted-w.Solved, w.Attempted)
+			w.HintRate = rate(w.Hinted, w.Attempted)
+		}
+		if w.Solved > 0 {
+			w.AvgSolveTime = formatDuration(w.solveTime / time.Duration(w.Solved))
+		}
+
+		half := &early
+		if i >= len(t.Weeks)/2 {
+			half = &late
+		}
+		half.Attempted += w.Attempted
+		half.Solved += w.Solved
+	}
+	if t.Attempted == 0 {
+		return
+	}
+
+	t.FailRate = rate(t.Attempted-t.Solved, t.Attempted)
+	t.HintRate = rate(hinted, t.Attempted)
+	slow := 0.0
+	if t.Solved > 0 {
+		avg := t.solveTime / time.Duration(t.Solved)
+		t.AvgSolveTime = formatDuration(avg)
+		if average > 0 {
+			t.SlowFactor = math.Round(float64(avg)/float64(average)*100) / 100
+			slow = math.Min(math.Max(t.SlowFactor-1, 0), 1)
+		}
+	}
+
+	if early.Attempted > 0 && late.Attempted > 0 {
+		change := rate(late.Attempted-late.Solved, late.Attempted) - rate(early.Attempted-early.Solved, early.Attempted)
+		switch {
+		case change <= -directionMargin:
+			t.Direction = DirectionImproving
+		case change >= directionMargin:
+			t.Direction = DirectionDeclining
+		default:
+			t.Direction = DirectionSteady
+		}
+	}
+
+	weakness := failWeight*t.FailRate/100 + hintWeight*t.HintRate/100 + slowWeight*slow
+	t.Weakness = math.Round(weakness*100) / 100
+	t.Judged = t.Attempted >= MinTrendAttempts
+	t.Weak = t.Judged && t.Weakness >= WeakThreshold
+	if !t.Weak {
+		return
+	}
+	if t.FailRate >= 30 {
+		t.Reasons = append(t.Reasons, fmt.Sprintf("fails %.0f%% of attempts", t.FailRate))
+	}
+	if t.HintRate >= 30 {
+		t.Reasons = append(t.Reasons, fmt.Sprintf("needs hints on %.0f%%", t.HintRate))
+	}
+	if t.SlowFactor >= 1.3 {
+		t.Reasons = append(t.Reasons, fmt.Sprintf("solves %.1fx slower than average", t.SlowFactor))
+	}
+	if t.Direction == DirectionDeclining {
+		t.Reasons = append(t.Reasons, "failing more often lately")
+	}
+}
+
+// recommend picks the pattern to practice next: the weakest weak pattern,
+// else a known pattern not practiced lately, else the weakest one judged
+func recommend(patterns []PatternTrend, known []string) (string, string) {
+	if len(patterns) > 0 && patterns[0].Weak {
+		return patterns[0].Pattern, "your weakest pattern"
+	}
+	for _, pattern := range known {
+		for _, p := range patterns {
+			if p.Pattern == pattern && p.Attempted == 0 {
+				return pattern, fmt.Sprintf("not practiced in the last %d weeks", TrendWeeks)
+			}
+		}
+	}
+	if len(patterns) > 0 && patterns[0].Judged {
+		return patterns[0].Pattern, "the least solid of your patterns"
+	}
+	return "", ""
+}
+
+// rate returns part as a percentage of whole
+func rate(part, whole int) float64 {
+	return float64(part) / float64(whole) * 100
+}
+
+// GetPatternTrends returns the pattern trends as of now, listing the known
+// patterns even if they were not practiced
+func (s *Service) GetPatternTrends(ctx context.Context, known []string, now time.Time) (PatternTrends, error) {
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return PatternTrends{}, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	return BuildPatternTrends(sessions, known, now), nil
+}
+
+// GetPatternTrends returns the trends of each pattern over the recent weeks
+var GetPatternTrends = func(known []string) (PatternTrends, error) {
+	return getDefaultService().GetPatternTrends(context.Background(), known, time.Now())
+}
@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPatternTrends(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	session := func(pattern string, daysAgo int, solved, hinted bool, minutes int) interfaces.SessionStats {
+		return interfaces.SessionStats{
+			Patterns:  []string{pattern},
+			StartTime: now.AddDate(0, 0, -daysAgo),
+			Solved:    solved,
+			HintsUsed: hinted,
+			Duration:  time.Duration(minutes) * time.Minute,
+		}
+	}
+	sessions := []interfaces.SessionStats{
+		session("dynamic-programming", 40, true, false, 20),
+		session("dynamic-programming", 1, false, true, 0),
+		session("dynamic-programming", 0, true, true, 40),
+		session("hash-map", 2, true, false, 10),
+		session("hash-map", 9, true, false, 10),
+		session("dfs", 0, true, false, 20),
+		session("old", 100, false, false, 0),
+	}
+
+	trends := BuildPatternTrends(sessions, []string{"hash-map", "greedy"}, now)
+	assert.Equal(t, TrendWeeks, trends.Weeks)
+	require.Len(t, trends.Patterns, 4, "practiced and known patterns, without ones only practiced long ago")
+
+	dp := trends.Patterns[0]
+	assert.Equal(t, "dynamic-programming", dp.Pattern)
+	require.Len(t, dp.Weeks, TrendWeeks)
+	assert.Equal(t, "2026-01-12", dp.Weeks[0].StartDate, "weeks start on Monday")
+	assert.Equal(t, PatternWeek{StartDate: "2026-03-02", Attempted: 2, Solved: 1, Hinted: 2,
+		FailRate: 50, HintRate: 100, AvgSolveTime: "00:40:00", solveTime: 40 * time.Minute}, dp.Weeks[TrendWeeks-1])
+	assert.Equal(t, 3, dp.Attempted)
+	assert.InDelta(t, 33.3, dp.FailRate, 0.1)
+	assert.InDelta(t, 66.7, dp.HintRate, 0.1)
+	assert.Equal(t, 1.5, dp.SlowFactor, "30 minutes against 20 across patterns")
+	assert.Equal(t, DirectionDeclining, dp.Direction)
+	assert.True(t, dp.Weak)
+	assert.Equal(t, []string{"fails 33% of attempts", "needs hints on 67%",
+		"solves 1.5x slower than average", "failing more often lately"}, dp.Reasons)
+
+	hashMap := trends.Patterns[1]
+	assert.Equal(t, "hash-map", hashMap.Pattern)
+	assert.True(t, hashMap.Judged)
+	assert.False(t, hashMap.Weak)
+	assert.Zero(t, hashMap.Weakness)
+	assert.Empty(t, hashMap.Direction, "practiced in the later weeks only")
+
+	assert.Equal(t, "dfs", trends.Patterns[2].Pattern)
+	assert.False(t, trends.Patterns[2].Judged, "one attempt is too few")
+	assert.Equal(t, "greedy", trends.Patterns[3].Pattern)
+	assert.Zero(t, trends.Patterns[3].Attempted)
+
+	assert.Equal(t, "dynamic-programming", trends.Recommended)
+	assert.Equal(t, "your weakest pattern", trends.Reason)
+	assert.Len(t, trends.Weakest(), 1)
+}
+
+func TestPatternTrendsRecommendation(t *testing.T) {
+	now := time.Now()
+	solved := interfaces.SessionStats{Patterns: []string{"hash-map"}, StartTime: now, Solved: true, Duration: time.Minute}
+	sessions := []interfaces.SessionStats{solved, solved}
+
+	trends := BuildPatternTrends(sessions, []string{"hash-map", "greedy"}, now)
+	assert.Equal(t, "greedy", trends.Recommended, "a pattern not practiced lately without weak ones")
+	assert.Equal(t, "not practiced in the last 8 weeks", trends.Reason)
+
+	trends = BuildPatternTrends(sessions, []string{"hash-map"}, now)
+	assert.Equal(t, "hash-map", trends.Recommended, "the weakest judged pattern when all are practiced")
+
+	trends = BuildPatternTrends(nil, nil, now)
+	assert.Empty(t, trends.Patterns)
+	assert.Empty(t, trends.Recommended)
+}
+
+func TestServiceGetPatternTrends(t *testing.T) {
+	storage := NewMockStorage()
+	service := NewService().WithStorage(storage)
+	now := time.Now()
+	storage.AddSession(interfaces.SessionStats{Patterns: []string{"bfs"}, StartTime: now.Add(-time.Minute)})
+
+	trends, err := service.GetPatternTrends(context.Background(), nil, now)
+	require.NoError(t, err)
+	require.Len(t, trends.Patterns, 1)
+	assert.Equal(t, 1, trends.Patterns[0].Attempted)
+	assert.Equal(t, 1, trends.Patterns[0].Weeks[TrendWeeks-1].Attempted)
+}
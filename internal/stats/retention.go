@@ -0,0 +1,198 @@
+// Forgetting-curve forecasts per pattern
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+const (
+	// RetentionThreshold is the predicted recall below which a pattern is
+	// due for a refresher
+	RetentionThreshold = 0.7
+
+	// ReminderHorizon is how far ahead decay reminders look
+	ReminderHorizon = 7 * 24 * time.Hour
+
+	// initialStability is the memory stability after a first attempt
+	initialStability = 24 * time.Hour
+)
+
+// Stability multipliers applied after each review of a pattern
+const (
+	cleanSolveGrowth  = 2.5 // Solved without hints or the solution
+	hintedSolveGrowth = 1.5 // Solved with hints
+	lapseShrink       = 0.5 // Not solved, or the solution was revealed
+)
+
+// PatternRetention is the forgetting-curve forecast for a single pattern
+type PatternRetention struct {
+	Pattern       string        `json:"pattern"`
+	Rating        float64       `json:"rating"` // Success rate, 0-100
+	Reviews       int           `json:"reviews"`
+	LastPracticed time.Time     `json:"last_practiced"`
+	Stability     time.Duration `json:"stability"`
+	Retention     float64       `json:"retention"` // Predicted recall now, 0-1
+	DecaysAt      time.Time     `json:"decays_at"` // When recall crosses RetentionThreshold
+}
+
+// Decayed reports whether recall has already dropped below the threshold
+func (r PatternRetention) Decayed(now time.Time) bool {
+	return !r.DecaysAt.After(now)
+}
+
+// DaysUntilDecay returns the whole days left before recall drops below the
+// threshold, rounded up; zero once it has
+func (r PatternRetention) DaysUntilDecay(now time.Time) int {
+	if r.Decayed(now) {
+		return 0
+	}
+	return int(math.Ceil(r.DecaysAt.Sub(now).Hours() / 24))
+}
+
+// Reminder returns a one-line warning about the pattern's predicted decay
+func (r PatternRetention) Reminder(now time.Time) string {
+	threshold := int(RetentionThreshold * 100)
+	if r.Decayed(now) {
+		return fmt.Sprintf("Your %s skills have likely decayed below %d%% recall", r.Pattern, threshold)
+	}
+
+	days := r.DaysUntilDecay(now)
+	when := fmt.Sprintf("in %d days", days)
+	if days == 1 {
+		when = "within a day"
+	}
+	return fmt.Sprintf("Your %s skills are predicted to decay below %d%% recall %s", r.Pattern, threshold, when)
+}
+
+// patternMemory is the review state replayed from a pattern's sessions
+type patternMemory struct {
+	stability time.Duration
+	last      time.Time
+	reviews   int
+	solved    int
+}
+
+// review updates the memory state with the outcome of a session
+func (p *patternMemory) review(session interfaces.SessionStats) {
+	if p.reviews == 0 {
+		p.stability = initialStability
+	}
+	p.reviews++
+	p.last = session.StartTime
+
+	switch {
+	case !session.Solved || session.SolutionUsed:
+		p.stability = time.Duration(float64(p.stability) * lapseShrink)
+		if p.stability < initialStability {
+			p.stability = initialStability
+		}
+	case session.HintsUsed:
+		p.solved++
+		p.stability = time.Duration(float64(p.stability) * hintedSolveGrowth)
+	default:
+		p.solved++
+		p.stability = time.Duration(float64(p.stability) * cleanSolveGrowth)
+	}
+}
+
+// ForecastRetention replays the sessions of each pattern through a
+// spaced-repetition schedule and predicts recall with an exponential
+// forgetting curve. The pattern's rating scales the schedule, so patterns
+// that are usually solved decay more slowly. Forecasts are ordered by the
+// time they decay, soonest first.
+func ForecastRetention(sessions []interfaces.SessionStats, now time.Time) []PatternRetention {
+	sorted := make([]interfaces.SessionStats, len(sessions))
+	copy(sorted, sessions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+
+	memories := make(map[string]*patternMemory)
+	for _, session := range sorted {
+		for _, pattern := range session.Patterns {
+			memory, ok := memories[pattern]
+			if !ok {
+				memory = &patternMemory{}
+				memories[pattern] = memory
+			}
+			memory.review(session)
+		}
+	}
+
+	forecasts := make([]PatternRetention, 0, len(memories))
+	for pattern, memory := range memories {
+		rating := float64(memory.solved) / float64(memory.reviews)
+
+		// A 100% rating lasts 1.5x the schedule, a 0% rating half of it
+		stability := time.Duration(float64(memory.stability) * (0.5 + rating))
+		elapsed := now.Sub(memory.last)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+
+		forecasts = append(forecasts, PatternRetention{
+			Pattern:       pattern,
+			Rating:        rating * 100,
+			Reviews:       memory.reviews,
+			LastPracticed: memory.last,
+			Stability:     stability,
+			Retention:     math.Exp(-elapsed.Hours() / stability.Hours()),
+			DecaysAt:      memory.last.Add(time.Duration(float64(stability) * math.Log(1/RetentionThreshold))),
+		})
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		if !forecasts[i].DecaysAt.Equal(forecasts[j].DecaysAt) {
+			return forecasts[i].DecaysAt.Before(forecasts[j].DecaysAt)
+		}
+		return forecasts[i].Pattern < forecasts[j].Pattern
+	})
+
+	return forecasts
+}
+
+// DueForRefresh returns the forecasts that decay within the horizon,
+// including patterns that have already decayed
+func DueForRefresh(forecasts []PatternRetention, now time.Time, horizon time.Duration) []PatternRetention {
+	var due []PatternRetention
+	for _, forecast := range forecasts {
+		if forecast.DecaysAt.Before(now.Add(horizon)) {
+			due = append(due, forecast)
+		}
+	}
+	return due
+}
+
+// GetRetention returns the forgetting-curve forecast for every practiced pattern
+func (s *Service) GetRetention(ctx context.Context, now time.Time) ([]PatternRetention, error) {
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	return ForecastRetention(sessions, now), nil
+}
+
+// GetForecasts returns the forgetting-curve forecast for every practiced
+// pattern, the soonest to decay first
+// Exported as variable for testing
+var GetForecasts = func() ([]PatternRetention, error) {
+	return getDefaultService().GetRetention(context.Background(), time.Now())
+}
+
+// GetReminders returns the patterns predicted to decay below the retention
+// threshold within the reminder horizon
+var GetReminders = func() ([]PatternRetention, error) {
+	now := time.Now()
+	forecasts, err := getDefaultService().GetRetention(context.Background(), now)
+	if err != nil {
+		return nil, err
+	}
+	return DueForRefresh(forecasts, now, ReminderHorizon), nil
+}
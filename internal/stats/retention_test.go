@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastRetention(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	sessions := []interfaces.SessionStats{
+		// binary-search: two clean solves, the latest three days ago
+		{ProblemID: "search", StartTime: now.Add(-10 * day), Solved: true, Patterns: []string{"binary-search"}},
+		{ProblemID: "search", StartTime: now.Add(-3 * day), Solved: true, Patterns: []string{"binary-search"}},
+		// dfs: failed once, a week ago
+		{ProblemID: "islands", StartTime: now.Add(-7 * day), Solved: false, Patterns: []string{"dfs"}},
+		// heap: solved with hints yesterday
+		{ProblemID: "closest", StartTime: now.Add(-day), Solved: true, HintsUsed: true, Patterns: []string{"heap"}},
+	}
+
+	forecasts := ForecastRetention(sessions, now)
+	require.Len(t, forecasts, 3)
+
+	// Soonest decay first
+	assert.Equal(t, "dfs", forecasts[0].Pattern)
+	assert.Equal(t, "heap", forecasts[1].Pattern)
+	assert.Equal(t, "binary-search", forecasts[2].Pattern)
+
+	dfs := forecasts[0]
+	assert.Equal(t, 0.0, dfs.Rating)
+	assert.Equal(t, 12*time.Hour, dfs.Stability)
+	assert.True(t, dfs.Decayed(now))
+	assert.Less(t, dfs.Retention, RetentionThreshold)
+	assert.Equal(t, 0, dfs.DaysUntilDecay(now))
+
+	search := forecasts[2]
+	assert.Equal(t, 100.0, search.Rating)
+	assert.Equal(t, 2, search.Reviews)
+	assert.Equal(t, now.Add(-3*day), search.LastPracticed)
+	assert.Equal(t, time.Duration(float64(24*time.Hour)*2.5*2.5*1.5), search.Stability)
+	assert.False(t, search.Decayed(now))
+	assert.Greater(t, search.Retention, RetentionThreshold)
+	assert.Equal(t, 1, search.DaysUntilDecay(now))
+
+	// Only patterns decaying within the horizon are due; heap was solved
+	// with hints, so it has already decayed too
+	due := DueForRefresh(forecasts, now, 0)
+	require.Len(t, due, 2)
+	assert.Equal(t, "dfs", due[0].Pattern)
+	assert.Equal(t, "heap", due[1].Pattern)
+	assert.Len(t, DueForRefresh(forecasts, now, ReminderHorizon), 3)
+}
+
+func TestForecastRetentionLapse(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	clean := []interfaces.SessionStats{
+		{StartTime: now.Add(-4 * day), Solved: true, Patterns: []string{"greedy"}},
+		{StartTime: now.Add(-2 * day), Solved: true, Patterns: []string{"greedy"}},
+	}
+	lapsed := []interfaces.SessionStats{
+		{StartTime: now.Add(-4 * day), Solved: true, Patterns: []string{"greedy"}},
+		{StartTime: now.Add(-2 * day), Solved: true, SolutionUsed: true, Patterns: []string{"greedy"}},
+	}
+
+	// Revealing the solution counts as a lapse and shortens the schedule
+	cleanForecast := ForecastRetention(clean, now)[0]
+	lapsedForecast := ForecastRetention(lapsed, now)[0]
+	assert.Equal(t, 50.0, lapsedForecast.Rating)
+	assert.True(t, lapsedForecast.DecaysAt.Before(cleanForecast.DecaysAt))
+	assert.Less(t, lapsedForecast.Retention, cleanForecast.Retention)
+}
+
+func TestRetentionReminder(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	forecast := PatternRetention{Pattern: "binary-search", DecaysAt: now.Add(60 * time.Hour)}
+	assert.Equal(t, "Your binary-search skills are predicted to decay below 70% recall in 3 days", forecast.Reminder(now))
+
+	forecast.DecaysAt = now.Add(time.Hour)
+	assert.Equal(t, "Your binary-search skills are predicted to decay below 70% recall within a day", forecast.Reminder(now))
+
+	forecast.DecaysAt = now.Add(-time.Hour)
+	assert.Equal(t, "Your binary-search skills have likely decayed below 70% recall", forecast.Reminder(now))
+}
+
+func TestServiceGetRetention(t *testing.T) {
+	storage := NewMockStorage()
+	service := NewService().WithStorage(storage)
+	now := time.Now()
+
+	forecasts, err := service.GetRetention(context.Background(), now)
+	require.NoError(t, err)
+	assert.Empty(t, forecasts)
+
+	storage.AddSession(interfaces.SessionStats{
+		ProblemID: "two_sum",
+		StartTime: now.Add(-time.Hour),
+		Solved:    true,
+		Patterns:  []string{"hash-map"},
+	})
+
+	forecasts, err = service.GetRetention(context.Background(), now)
+	require.NoError(t, err)
+	require.Len(t, forecasts, 1)
+	assert.Equal(t, "hash-map", forecasts[0].Pattern)
+}
@@ -2,10 +2,13 @@ package stats
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/review"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 )
 
 // Service implements the StatsService interface
@@ -13,10 +16,10 @@ type Service struct {
 	storage interfaces.StatsStorage
 }
 
-// NewService creates a new stats service with the default file storage
+// NewService creates a new stats service with the default SQLite storage
 func NewService() *Service {
 	return &Service{
-		storage: NewFileStorage(),
+		storage: storage.Default(),
 	}
 }
 
@@ -26,9 +29,32 @@ func (s *Service) WithStorage(storage interfaces.StatsStorage) *Service {
 	return s
 }
 
-// RecordSession records a session's statistics
+// RecordSession records a session's statistics, announces the goals it
+// reaches and, when the storage keeps review state, reschedules the problem
+// for spaced-repetition review
 func (s *Service) RecordSession(ctx context.Context, sessionStats interfaces.SessionStats) error {
-	return s.storage.SaveSession(ctx, sessionStats)
+	if err := s.storage.SaveSession(ctx, sessionStats); err != nil {
+		return err
+	}
+	s.announceGoals(ctx)
+	repo, ok := s.storage.(storage.Repository)
+	if !ok {
+		return nil
+	}
+	if err := review.Record(ctx, repo, sessionStats); err != nil {
+		return fmt.Errorf("failed to schedule review: %v", err)
+	}
+	return nil
+}
+
+// RecordAttempt records a run of a solution's tests. Storage that keeps no
+// attempt history ignores it.
+func (s *Service) RecordAttempt(ctx context.Context, attempt storage.Attempt) error {
+	repo, ok := s.storage.(storage.Repository)
+	if !ok {
+		return nil
+	}
+	return repo.SaveAttempt(ctx, attempt)
 }
 
 // GetSummary returns summary statistics
@@ -79,6 +105,9 @@ func (s *Service) GetSummary(ctx context.Context) (*interfaces.Summary, error) {
 
 	for _, session := range sessions {
 		problemAttempts[session.ProblemID]++
+		if session.SwappedTo != "" {
+			summary.Swaps++
+		}
 
 		if session.Solved {
 			solvedCount++
@@ -186,6 +215,45 @@ func (s *Service) GetByPattern(ctx context.Context) (map[string]interfaces.Patte
 	return patternStats, nil
 }
 
+// UnknownContext groups sessions recorded before their context was kept
+const UnknownContext = "unknown"
+
+// GetByContext returns statistics by the entry point that recorded each
+// session: the CLI, the TUI, the Neovim plugin or daily practice
+func (s *Service) GetByContext(ctx context.Context) (map[string]interfaces.ContextStats, error) {
+	sessions, err := s.storage.LoadAllSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contextStats := make(map[string]interfaces.ContextStats)
+	solveTimes := make(map[string]time.Duration)
+	for _, session := range sessions {
+		name := session.Context
+		if name == "" {
+			name = UnknownContext
+		}
+
+		stats := contextStats[name]
+		stats.Context = name
+		stats.Attempted++
+		if session.Solved {
+			stats.Solved++
+			solveTimes[name] += session.Duration
+		}
+		stats.SuccessRate = float64(stats.Solved) / float64(stats.Attempted) * 100
+		contextStats[name] = stats
+	}
+
+	for name, total := range solveTimes {
+		stats := contextStats[name]
+		stats.AvgTime = formatDuration(total / time.Duration(stats.Solved))
+		contextStats[name] = stats
+	}
+
+	return contextStats, nil
+}
+
 // GetTrends returns usage trends over time
 func (s *Service) GetTrends(ctx context.Context) (*interfaces.Trends, error) {
 	// Load all session stats
@@ -324,6 +392,9 @@ func (s *Service) GetAllSessions(ctx context.Context) ([]interfaces.SessionStats
 			SolutionUsed: session.SolutionUsed,
 			Patterns:     session.Patterns,
 			Difficulty:   session.Difficulty,
+			Environment:  session.Environment,
+			SwappedTo:    session.SwappedTo,
+			Context:      session.Context,
 		}
 	}
 	return result, nil
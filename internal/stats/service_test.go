@@ -178,4 +178,31 @@ func TestStatsService(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 0, summary.TotalAttempted)
 	})
-}
\ No newline at end of file
+}
+func TestGetByContext(t *testing.T) {
+	mockStorage := NewMockStorage()
+	service := NewService().WithStorage(mockStorage)
+
+	mockStorage.AddSession(interfaces.SessionStats{ProblemID: "a", Solved: true, Duration: 10 * time.Minute, Context: interfaces.ContextVim})
+	mockStorage.AddSession(interfaces.SessionStats{ProblemID: "b", Solved: true, Duration: 20 * time.Minute, Context: interfaces.ContextVim})
+	mockStorage.AddSession(interfaces.SessionStats{ProblemID: "c", Solved: false, Duration: 5 * time.Minute, Context: interfaces.ContextVim})
+	mockStorage.AddSession(interfaces.SessionStats{ProblemID: "d", Solved: false, Context: interfaces.ContextTUI})
+	mockStorage.AddSession(interfaces.SessionStats{ProblemID: "e", Solved: true, Duration: time.Minute})
+
+	byContext, err := service.GetByContext(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, byContext, 3)
+
+	vim := byContext[interfaces.ContextVim]
+	assert.Equal(t, 3, vim.Attempted)
+	assert.Equal(t, 2, vim.Solved)
+	assert.InDelta(t, 66.7, vim.SuccessRate, 0.1)
+	assert.Equal(t, "00:15:00", vim.AvgTime)
+
+	tui := byContext[interfaces.ContextTUI]
+	assert.Equal(t, 1, tui.Attempted)
+	assert.Empty(t, tui.AvgTime)
+
+	// Sessions from before contexts were recorded are grouped together
+	assert.Equal(t, 1, byContext[UnknownContext].Solved)
+}
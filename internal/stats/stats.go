@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 )
 
 // SessionStats represents statistics for a single session
@@ -22,6 +24,16 @@ type SessionStats struct {
 	SolutionUsed bool          `json:"solution_used"`
 	Patterns     []string      `json:"patterns"`
 	Difficulty   string        `json:"difficulty"`
+
+	// Environment is nil for sessions recorded before snapshots existed
+	Environment *interfaces.EnvironmentSnapshot `json:"environment,omitempty"`
+
+	// SwappedTo is the easier problem the user switched to when stuck
+	SwappedTo string `json:"swapped_to,omitempty"`
+
+	// Context is the entry point that recorded the session, such as the TUI
+	// or the Neovim plugin; empty for sessions recorded before it was kept
+	Context string `json:"context,omitempty"`
 }
 
 // Summary represents summary statistics
@@ -30,6 +42,7 @@ type Summary struct {
 	TotalSolved    int     `json:"total_solved"`
 	AvgSolveTime   string  `json:"avg_solve_time"`
 	SuccessRate    float64 `json:"success_rate"`
+	Swaps          int     `json:"swaps"` // Problems swapped for easier ones when stuck
 	FastestSolve   struct {
 		ProblemID string `json:"problem_id"`
 		Time      string `json:"time"`
@@ -49,6 +62,15 @@ type PatternStats struct {
 	AvgTime     string  `json:"avg_time"`
 }
 
+// ContextStats represents statistics for one practice context
+type ContextStats struct {
+	Context     string  `json:"context"`
+	Attempted   int     `json:"attempted"`
+	Solved      int     `json:"solved"`
+	SuccessRate float64 `json:"success_rate"`
+	AvgTime     string  `json:"avg_time"`
+}
+
 // Trends represents trends over time
 type Trends struct {
 	Daily  []DailyTrend  `json:"daily"`
@@ -3,6 +3,7 @@
 package stats
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/storage"
 )
 
 // Override config dir for testing
@@ -43,12 +45,8 @@ func withTestDir(t *testing.T) (string, func()) {
 	}
 }
 
-// Create sample session stats
+// Create sample session stats in the progress database
 func createSampleSessions(t *testing.T, dir string, count int) []SessionStats {
-	statsDir := filepath.Join(dir, "stats")
-	err := os.MkdirAll(statsDir, 0755)
-	require.NoError(t, err)
-
 	sessions := make([]SessionStats, count)
 	for i := 0; i < count; i++ {
 		// Alternate solved/unsolved
@@ -71,19 +69,40 @@ func createSampleSessions(t *testing.T, dir string, count int) []SessionStats {
 			Difficulty:   "Medium",
 		}
 
-		// Save to file
+		require.NoError(t, RecordSession(sessions[i]))
+	}
+
+	return sessions
+}
+
+// Create sample session stats as JSON files, as earlier versions stored them
+func createLegacySessionFiles(t *testing.T, dir string, count int) []SessionStats {
+	statsDir := filepath.Join(dir, "stats")
+	require.NoError(t, os.MkdirAll(statsDir, 0755))
+
+	sessions := make([]SessionStats, count)
+	for i := 0; i < count; i++ {
+		startTime := time.Now().Add(-time.Duration(i) * time.Hour)
+		sessions[i] = SessionStats{
+			ProblemID: "problem" + string(rune('1'+i%3)),
+			StartTime: startTime,
+			EndTime:   startTime.Add(30 * time.Minute),
+			Duration:  30 * time.Minute,
+			Solved:    i%2 == 0,
+			Mode:      "practice",
+		}
+
 		filename := filepath.Join(statsDir, "session_"+sessions[i].ProblemID+"_"+startTime.Format("20060102_150405")+".json")
 		data, err := json.MarshalIndent(sessions[i], "", "  ")
 		require.NoError(t, err)
-		err = os.WriteFile(filename, data, 0644)
-		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename, data, 0644))
 	}
 
 	return sessions
 }
 
 func TestRecordSession(t *testing.T) {
-	tempDir, cleanup := withTestDir(t)
+	_, cleanup := withTestDir(t)
 	defer cleanup()
 
 	// Create test session stats
@@ -105,24 +124,40 @@ func TestRecordSession(t *testing.T) {
 	require.NoError(t, err)
 
 	// Check that the stats were saved
-	statsDir := filepath.Join(tempDir, "stats")
-	files, err := os.ReadDir(statsDir)
-	require.NoError(t, err)
-	assert.Equal(t, 1, len(files))
-
-	// Verify file content
-	data, err := os.ReadFile(filepath.Join(statsDir, files[0].Name()))
-	require.NoError(t, err)
-
-	var savedStats SessionStats
-	err = json.Unmarshal(data, &savedStats)
+	sessions, err := GetAllSessions()
 	require.NoError(t, err)
+	require.Equal(t, 1, len(sessions))
 
+	savedStats := sessions[0]
 	assert.Equal(t, stats.ProblemID, savedStats.ProblemID)
 	assert.Equal(t, stats.Solved, savedStats.Solved)
 	assert.Equal(t, stats.Duration, savedStats.Duration)
 }
 
+func TestRecordAttempt(t *testing.T) {
+	_, cleanup := withTestDir(t)
+	defer cleanup()
+
+	attempt := storage.Attempt{
+		ProblemID: "test-problem",
+		Language:  "go",
+		Time:      time.Now(),
+		Results:   []storage.TestResult{{Number: 1, Passed: true}},
+	}
+	require.NoError(t, RecordAttempt(attempt))
+
+	store := storage.Default()
+	defer store.Close()
+	attempts, err := store.LoadAttempts(context.Background(), "test-problem")
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, "go", attempts[0].Language)
+	assert.Len(t, attempts[0].Results, 1)
+
+	// Storage without attempt history ignores attempts
+	assert.NoError(t, NewService().WithStorage(NewMockStorage()).RecordAttempt(context.Background(), attempt))
+}
+
 func TestGetSummary(t *testing.T) {
 	tempDir, cleanup := withTestDir(t)
 	defer cleanup()
@@ -153,8 +188,10 @@ func TestGetSummary(t *testing.T) {
 	err = RecordSession(unsolved)
 	require.NoError(t, err)
 
+	// The second attempt was swapped for an easier problem
 	unsolved.StartTime = time.Now().Add(-5 * time.Hour)
 	unsolved.EndTime = time.Now().Add(-4 * time.Hour)
+	unsolved.SwappedTo = "easy-problem"
 	err = RecordSession(unsolved)
 	require.NoError(t, err)
 
@@ -170,6 +207,7 @@ func TestGetSummary(t *testing.T) {
 	assert.Equal(t, 3, summary.TotalSolved)
 	assert.NotEmpty(t, summary.AvgSolveTime)
 	assert.Equal(t, float64(3)/float64(8)*100, summary.SuccessRate)
+	assert.Equal(t, 1, summary.Swaps)
 
 	assert.NotEmpty(t, summary.FastestSolve.ProblemID)
 	assert.NotEmpty(t, summary.FastestSolve.Time)
@@ -252,19 +290,18 @@ func TestReset(t *testing.T) {
 	createSampleSessions(t, tempDir, 5)
 
 	// Verify sessions were created
-	statsDir := filepath.Join(tempDir, "stats")
-	files, err := os.ReadDir(statsDir)
+	sessions, err := GetAllSessions()
 	require.NoError(t, err)
-	assert.Equal(t, 5, len(files))
+	assert.Equal(t, 5, len(sessions))
 
 	// Reset stats
 	err = Reset()
 	require.NoError(t, err)
 
-	// Verify all files were removed
-	files, err = os.ReadDir(statsDir)
+	// Verify all sessions were removed
+	sessions, err = GetAllSessions()
 	require.NoError(t, err)
-	assert.Equal(t, 0, len(files))
+	assert.Equal(t, 0, len(sessions))
 }
 
 func TestLoadAllSessions(t *testing.T) {
@@ -272,7 +309,7 @@ func TestLoadAllSessions(t *testing.T) {
 	defer cleanup()
 
 	// Create sample sessions
-	expectedSessions := createSampleSessions(t, tempDir, 3)
+	expectedSessions := createLegacySessionFiles(t, tempDir, 3)
 
 	// Load sessions
 	sessions, err := loadAllSessions()
@@ -42,6 +42,9 @@ func (s *FileStorage) SaveSession(ctx context.Context, session interfaces.Sessio
 		SolutionUsed: session.SolutionUsed,
 		Patterns:     session.Patterns,
 		Difficulty:   session.Difficulty,
+		Environment:  session.Environment,
+		SwappedTo:    session.SwappedTo,
+		Context:      session.Context,
 	}
 	// Get the stats directory
 	statsDir := filepath.Join(s.fs.GetConfigDir(), "stats")
@@ -112,6 +115,9 @@ func (s *FileStorage) LoadAllSessions(ctx context.Context) ([]interfaces.Session
 			SolutionUsed: s.SolutionUsed,
 			Patterns:     s.Patterns,
 			Difficulty:   s.Difficulty,
+			Environment:  s.Environment,
+			SwappedTo:    s.SwappedTo,
+			Context:      s.Context,
 		}
 	}
 
@@ -0,0 +1,187 @@
+// Pluggable backends for user data files
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+)
+
+// Backend kinds selectable in the storage config
+const (
+	BackendLocal  = "local"
+	BackendS3     = "s3"
+	BackendWebDAV = "webdav"
+)
+
+// backendTimeout bounds a single request to a network backend
+const backendTimeout = 30 * time.Second
+
+// Backend stores user data files, such as the progress database and cached
+// summaries, under slash-separated names like "summaries/two_sum.json"
+type Backend interface {
+	// Get returns a file's contents; missing files give an error matching
+	// fs.ErrNotExist
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Put creates or replaces a file
+	Put(ctx context.Context, name string, data []byte) error
+
+	// Delete removes a file; deleting a missing file is not an error
+	Delete(ctx context.Context, name string) error
+}
+
+// LocalBackend keeps user data in a directory on local disk
+type LocalBackend struct {
+	Dir string
+}
+
+// NewLocalBackend creates a backend storing files under dir
+func NewLocalBackend(dir string) *LocalBackend {
+	return &LocalBackend{Dir: dir}
+}
+
+// Get returns a file's contents
+func (b *LocalBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	p, err := b.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// Put writes a file, replacing it atomically
+func (b *LocalBackend) Put(ctx context.Context, name string, data []byte) error {
+	p, err := b.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Delete removes a file
+func (b *LocalBackend) Delete(ctx context.Context, name string) error {
+	p, err := b.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a file name into the directory
+func (b *LocalBackend) path(name string) (string, error) {
+	clean, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(b.Dir, filepath.FromSlash(clean)), nil
+}
+
+// cleanName validates a file name, rejecting names that would escape the
+// backend's root
+func cleanName(name string) (string, error) {
+	clean := path.Clean("/" + name)[1:]
+	if clean == "" || clean != strings.TrimPrefix(name, "/") || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid file name: %q", name)
+	}
+	return clean, nil
+}
+
+// loadStorageConfig reads the storage section of the user config, without
+// creating the config file when there is none
+// Exported as variable for testing
+var loadStorageConfig = func() (config.StorageConfig, error) {
+	data, err := os.ReadFile(filepath.Join(utils.GetConfigDir(), "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return config.StorageConfig{}, nil
+	}
+	if err != nil {
+		return config.StorageConfig{}, fmt.Errorf("failed to read config: %v", err)
+	}
+	var cfg config.UserConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config.StorageConfig{}, fmt.Errorf("failed to parse config: %v", err)
+	}
+	return cfg.Storage, nil
+}
+
+// UserData returns the backend user data is kept in, as configured. Without
+// configuration it is the local config directory.
+func UserData() (Backend, error) {
+	cfg, err := loadStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewBackend(cfg)
+}
+
+// Open returns the repository for the progress database at path. With a
+// remote backend configured the database is downloaded before first use and
+// uploaded after every change; otherwise it is used in place.
+func Open(path string) Repository {
+	cfg, err := loadStorageConfig()
+	if err == nil && (cfg.Backend == "" || cfg.Backend == BackendLocal) {
+		return NewSQLiteStore(path)
+	}
+	var backend Backend
+	if err == nil {
+		backend, err = NewBackend(cfg)
+	}
+	store := NewSyncedStore(path, backend)
+	store.err = err
+	return store
+}
+
+// NewBackend creates the backend described by a storage config
+func NewBackend(cfg config.StorageConfig) (Backend, error) {
+	client := &http.Client{Timeout: backendTimeout}
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocalBackend(utils.GetConfigDir()), nil
+	case BackendS3:
+		if cfg.S3 == nil {
+			return nil, fmt.Errorf("storage backend s3 needs an \"s3\" section in the config")
+		}
+		return NewS3Backend(*cfg.S3, client)
+	case BackendWebDAV:
+		if cfg.WebDAV == nil {
+			return nil, fmt.Errorf("storage backend webdav needs a \"webdav\" section in the config")
+		}
+		return NewWebDAVBackend(*cfg.WebDAV, client)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (use local, s3 or webdav)", cfg.Backend)
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBackend(t *testing.T) {
+	backend := NewLocalBackend(t.TempDir())
+	ctx := context.Background()
+
+	_, err := backend.Get(ctx, "summaries/two_sum.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, backend.Put(ctx, "summaries/two_sum.json", []byte("{}")))
+	data, err := backend.Get(ctx, "summaries/two_sum.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	require.NoError(t, backend.Delete(ctx, "summaries/two_sum.json"))
+	require.NoError(t, backend.Delete(ctx, "summaries/two_sum.json"), "deleting a missing file is fine")
+	_, err = backend.Get(ctx, "summaries/two_sum.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	for _, name := range []string{"", "../escape", "a/../../b", "a//b"} {
+		assert.Error(t, backend.Put(ctx, name, nil), name)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	backend, err := NewBackend(config.StorageConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &LocalBackend{}, backend)
+
+	_, err = NewBackend(config.StorageConfig{Backend: "ftp"})
+	assert.ErrorContains(t, err, "unknown storage backend")
+	_, err = NewBackend(config.StorageConfig{Backend: BackendS3})
+	assert.ErrorContains(t, err, "\"s3\" section")
+
+	t.Setenv("ALGO_SCALES_WEBDAV_PASSWORD", "secret")
+	backend, err = NewBackend(config.StorageConfig{Backend: BackendWebDAV, WebDAV: &config.WebDAVConfig{URL: "https://dav.example.com/algo"}})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", backend.(*WebDAVBackend).cfg.Password)
+}
+
+func TestOpen(t *testing.T) {
+	original := loadStorageConfig
+	defer func() { loadStorageConfig = original }()
+	path := filepath.Join(t.TempDir(), DBFileName)
+
+	loadStorageConfig = func() (config.StorageConfig, error) { return config.StorageConfig{}, nil }
+	assert.IsType(t, &SQLiteStore{}, Open(path))
+
+	// A broken remote configuration fails loudly rather than falling back
+	loadStorageConfig = func() (config.StorageConfig, error) {
+		return config.StorageConfig{Backend: BackendWebDAV}, nil
+	}
+	repo := Open(path)
+	defer repo.Close()
+	_, err := repo.LoadAllSessions(context.Background())
+	assert.ErrorContains(t, err, "webdav")
+}
+
+func TestSyncedStore(t *testing.T) {
+	remote := NewLocalBackend(t.TempDir())
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	// The first machine starts from scratch and uploads its progress
+	laptop := NewSyncedStore(filepath.Join(t.TempDir(), DBFileName), remote)
+	defer laptop.Close()
+	sessions, err := laptop.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	require.NoError(t, laptop.SaveSession(ctx, interfaces.SessionStats{ProblemID: "two_sum", StartTime: start}))
+	require.NoError(t, laptop.SaveStreak(ctx, Streak{Name: DailyStreak, Current: 3}))
+
+	// A second machine picks it up and adds to it
+	desktop := NewSyncedStore(filepath.Join(t.TempDir(), DBFileName), remote)
+	sessions, err = desktop.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	streak, err := desktop.LoadStreak(ctx, DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, 3, streak.Current)
+	require.NoError(t, desktop.SaveAttempt(ctx, Attempt{ProblemID: "two_sum", Language: "go", Time: start, Passed: true}))
+	require.NoError(t, desktop.Close())
+
+	// Which a fresh session on the first machine sees
+	again := NewSyncedStore(laptop.path, remote)
+	defer again.Close()
+	attempts, err := again.LoadAttempts(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Len(t, attempts, 1)
+}
@@ -0,0 +1,387 @@
+// Schema migrations and the import of progress kept by earlier versions
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"go.etcd.io/bbolt"
+)
+
+// Where earlier versions kept progress, relative to the config directory
+const (
+	legacyStatsDir     = "stats"
+	legacyDailyDB      = "daily.db"
+	legacyDailyBucket  = "daily_progress"
+	legacyProgressKey  = "progress"
+	legacyLockTimeout  = time.Second
+	legacySessionFiles = "session_*.json"
+)
+
+// migration upgrades the database by one version. dir is the directory
+// holding the database, which is also where earlier versions kept progress.
+type migration func(ctx context.Context, tx *sql.Tx, dir string) error
+
+// migrations are applied in order; the database's user_version records how
+// many have run
+var migrations = []migration{
+	createSchema,
+	importLegacyProgress,
+	addSwappedTo,
+	addAttemptCode,
+	createReviews,
+	createHintUsage,
+	createComplexity,
+	createUserTests,
+	addSessionContext,
+	addHintLevel,
+	addStreakFreezes,
+	addAttemptSubmitted,
+	createQuizAnswers,
+}
+
+// migrate brings the database up to the latest version, one transaction per
+// migration
+func migrate(db *sql.DB, dir string) error {
+	ctx := context.Background()
+
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := migrations[version](ctx, tx, dir); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %v", version+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSchema creates the tables
+func createSchema(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE sessions (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id    TEXT NOT NULL,
+			start_time    TEXT NOT NULL,
+			end_time      TEXT NOT NULL,
+			duration      INTEGER NOT NULL,
+			solved        INTEGER NOT NULL,
+			mode          TEXT NOT NULL,
+			hints_used    INTEGER NOT NULL,
+			solution_used INTEGER NOT NULL,
+			patterns      TEXT NOT NULL,
+			difficulty    TEXT NOT NULL,
+			environment   TEXT,
+			UNIQUE (problem_id, start_time)
+		);
+
+		CREATE TABLE attempts (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id TEXT NOT NULL,
+			language   TEXT NOT NULL,
+			time       TEXT NOT NULL,
+			passed     INTEGER NOT NULL
+		);
+		CREATE INDEX attempts_problem ON attempts (problem_id);
+
+		CREATE TABLE test_results (
+			attempt_id INTEGER NOT NULL REFERENCES attempts (id) ON DELETE CASCADE,
+			number     INTEGER NOT NULL,
+			passed     INTEGER NOT NULL,
+			failure    TEXT NOT NULL,
+			duration   INTEGER NOT NULL,
+			PRIMARY KEY (attempt_id, number)
+		);
+
+		CREATE TABLE streaks (
+			name           TEXT PRIMARY KEY,
+			current        INTEGER NOT NULL,
+			longest        INTEGER NOT NULL,
+			last_practiced TEXT,
+			position       INTEGER NOT NULL,
+			completed      TEXT NOT NULL
+		);`)
+	return err
+}
+
+// legacySession is a session as earlier versions saved it to a JSON file
+type legacySession struct {
+	ProblemID    string                          `json:"problem_id"`
+	StartTime    time.Time                       `json:"start_time"`
+	EndTime      time.Time                       `json:"end_time"`
+	Duration     time.Duration                   `json:"duration"`
+	Solved       bool                            `json:"solved"`
+	Mode         string                          `json:"mode"`
+	HintsUsed    bool                            `json:"hints_used"`
+	SolutionUsed bool                            `json:"solution_used"`
+	Patterns     []string                        `json:"patterns"`
+	Difficulty   string                          `json:"difficulty"`
+	Environment  *interfaces.EnvironmentSnapshot `json:"environment,omitempty"`
+}
+
+// legacyProgress is the daily progress earlier versions kept in BoltDB
+type legacyProgress struct {
+	Current       int       `json:"current"`
+	LastPracticed time.Time `json:"last_practiced"`
+	Completed     []string  `json:"completed"`
+	Streak        int       `json:"streak"`
+	LongestStreak int       `json:"longest_streak"`
+}
+
+// importLegacyProgress copies the session JSON files and the daily progress
+// of earlier versions into the database. The old files are left in place so
+// an older version still finds them; files that cannot be read are skipped
+// rather than blocking the upgrade.
+func importLegacyProgress(ctx context.Context, tx *sql.Tx, dir string) error {
+	statsDir := filepath.Join(dir, legacyStatsDir)
+
+	files, _ := filepath.Glob(filepath.Join(statsDir, legacySessionFiles))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var session legacySession
+		if json.Unmarshal(data, &session) != nil || session.ProblemID == "" {
+			continue
+		}
+		if err := insertLegacySession(ctx, tx, session); err != nil {
+			return err
+		}
+	}
+
+	progress, ok := readLegacyProgress(filepath.Join(statsDir, legacyDailyDB))
+	if !ok {
+		return nil
+	}
+	return insertLegacyStreak(ctx, tx, progress)
+}
+
+// insertLegacySession writes an imported session. It names the columns of
+// the sessions table as they were when this migration was written, since
+// later migrations have not run yet.
+func insertLegacySession(ctx context.Context, tx *sql.Tx, session legacySession) error {
+	patterns, err := json.Marshal(session.Patterns)
+	if err != nil {
+		return err
+	}
+	var environment any
+	if session.Environment != nil {
+		data, err := json.Marshal(session.Environment)
+		if err != nil {
+			return err
+		}
+		environment = string(data)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO sessions (problem_id, start_time, end_time, duration, solved, mode,
+			hints_used, solution_used, patterns, difficulty, environment)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ProblemID, session.StartTime.Format(timeLayout), session.EndTime.Format(timeLayout),
+		int64(session.Duration), session.Solved, session.Mode, session.HintsUsed, session.SolutionUsed,
+		string(patterns), session.Difficulty, environment)
+	if err != nil {
+		return fmt.Errorf("failed to import session: %v", err)
+	}
+	return nil
+}
+
+// insertLegacyStreak writes the imported daily progress. Like
+// insertLegacySession, it names the columns of the streaks table as they
+// were when this migration was written.
+func insertLegacyStreak(ctx context.Context, tx *sql.Tx, progress legacyProgress) error {
+	completed := progress.Completed
+	if completed == nil {
+		completed = []string{}
+	}
+	data, err := json.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	var lastPracticed any
+	if !progress.LastPracticed.IsZero() {
+		lastPracticed = progress.LastPracticed.Format(timeLayout)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT OR REPLACE INTO streaks (name, current, longest, last_practiced, position, completed)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		DailyStreak, progress.Streak, progress.LongestStreak, lastPracticed, progress.Current, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to import daily progress: %v", err)
+	}
+	return nil
+}
+
+// readLegacyProgress reads the daily progress from an earlier version's
+// BoltDB file
+func readLegacyProgress(path string) (legacyProgress, bool) {
+	var progress legacyProgress
+	if _, err := os.Stat(path); err != nil {
+		return progress, false
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true, Timeout: legacyLockTimeout})
+	if err != nil {
+		return progress, false
+	}
+	defer db.Close()
+
+	found := false
+	db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(legacyDailyBucket))
+		if bucket == nil {
+			return nil
+		}
+		if data := bucket.Get([]byte(legacyProgressKey)); data != nil {
+			found = json.Unmarshal(data, &progress) == nil
+		}
+		return nil
+	})
+	return progress, found
+}
+
+// addSwappedTo records which easier problem, if any, a session was swapped
+// for when the user got stuck
+func addSwappedTo(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE sessions ADD COLUMN swapped_to TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// addAttemptCode keeps the code each attempt was made with
+func addAttemptCode(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE attempts ADD COLUMN code TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// createReviews adds the spaced-repetition state of problems
+func createReviews(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE reviews (
+			problem_id    TEXT PRIMARY KEY,
+			ease          REAL NOT NULL,
+			interval_days INTEGER NOT NULL,
+			repetitions   INTEGER NOT NULL,
+			lapses        INTEGER NOT NULL,
+			due           TEXT NOT NULL,
+			last_reviewed TEXT NOT NULL
+		)`)
+	return err
+}
+
+// createHintUsage adds the per-problem usage the hint policy is enforced on
+func createHintUsage(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE hint_usage (
+			problem_id      TEXT PRIMARY KEY,
+			hints           INTEGER NOT NULL,
+			ai_hints        INTEGER NOT NULL,
+			failed_runs     INTEGER NOT NULL,
+			solution_viewed INTEGER NOT NULL,
+			last_hint       TEXT,
+			denied          INTEGER NOT NULL
+		)`)
+	return err
+}
+
+// createComplexity adds the complexity estimates of passing solutions
+func createComplexity(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE complexity (
+			problem_id   TEXT NOT NULL,
+			language     TEXT NOT NULL,
+			time         TEXT NOT NULL,
+			space        TEXT NOT NULL,
+			target_time  TEXT NOT NULL,
+			target_space TEXT NOT NULL,
+			verdict      TEXT NOT NULL,
+			source       TEXT NOT NULL,
+			analyzed_at  TEXT NOT NULL,
+			PRIMARY KEY (problem_id, language)
+		)`)
+	return err
+}
+
+// createUserTests adds the test cases users write for problems
+func createUserTests(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE user_tests (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id TEXT NOT NULL,
+			input      TEXT NOT NULL,
+			expected   TEXT NOT NULL,
+			added_at   TEXT NOT NULL
+		)`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `CREATE INDEX user_tests_problem ON user_tests (problem_id)`)
+	return err
+}
+
+// addSessionContext records the entry point, such as the TUI or the Neovim
+// plugin, that recorded each session. Earlier sessions are left empty since
+// their context is unknown.
+func addSessionContext(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE sessions ADD COLUMN context TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// addHintLevel records how far each problem's hints have escalated, so the
+// next hint follows on from the last one in any mode. Earlier usage starts
+// at level 0.
+func addHintLevel(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE hint_usage ADD COLUMN hint_level INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// addStreakFreezes records the freezes each streak holds against missed
+// days. Earlier streaks start with none.
+func addStreakFreezes(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE streaks ADD COLUMN freezes INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// addAttemptSubmitted marks the attempts that were submissions rather than
+// runs of the example tests. Earlier attempts cannot tell, so they count as
+// runs.
+func addAttemptSubmitted(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE attempts ADD COLUMN submitted INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// createQuizAnswers adds the answers given in the pattern quiz
+func createQuizAnswers(ctx context.Context, tx *sql.Tx, dir string) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE quiz_answers (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id TEXT NOT NULL,
+			pattern    TEXT NOT NULL,
+			chosen     TEXT NOT NULL,
+			correct    INTEGER NOT NULL,
+			time       TEXT NOT NULL
+		)`)
+	return err
+}
@@ -0,0 +1,123 @@
+// Selective deletion and backup of progress
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// prunable is a table of progress, with the column identifying each row and
+// the column holding when it was recorded
+type prunable struct {
+	table, key, when string
+}
+
+// prunables are the tables a prune deletes from, in the order of Pruned
+var prunables = []prunable{
+	{"sessions", "id", "start_time"},
+	{"attempts", "id", "time"},
+	{"reviews", "problem_id", "last_reviewed"},
+	{"hint_usage", "problem_id", "last_hint"},
+	{"complexity", "rowid", "analyzed_at"},
+	{"quiz_answers", "id", "time"},
+}
+
+// PruneProgress deletes the sessions, attempts, reviews, hint usage,
+// complexity estimates and quiz answers the prune selects, in one
+// transaction. A review, hint usage or estimate is dated by its latest
+// update, so with a cutoff it is only deleted if nothing about it changed
+// since.
+func (s *SQLiteStore) PruneProgress(ctx context.Context, prune Prune) (Pruned, error) {
+	db, err := s.open()
+	if err != nil {
+		return Pruned{}, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Pruned{}, fmt.Errorf("failed to prune progress: %v", err)
+	}
+	defer tx.Rollback()
+
+	counts := make([]int, len(prunables))
+	for i, p := range prunables {
+		if counts[i], err = pruneTable(ctx, tx, p, prune); err != nil {
+			return Pruned{}, fmt.Errorf("failed to prune %s: %v", p.table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return Pruned{}, fmt.Errorf("failed to prune progress: %v", err)
+	}
+	return Pruned{Sessions: counts[0], Attempts: counts[1], Reviews: counts[2], HintUsage: counts[3], Complexity: counts[4], QuizAnswers: counts[5]}, nil
+}
+
+// pruneTable deletes the rows of one table the prune selects. Times are
+// compared after parsing, since stored times differ in zone and precision
+// and so do not sort as text.
+func pruneTable(ctx context.Context, tx *sql.Tx, p prunable, prune Prune) (int, error) {
+	var problems map[string]bool
+	if prune.ProblemIDs != nil {
+		problems = make(map[string]bool, len(prune.ProblemIDs))
+		for _, id := range prune.ProblemIDs {
+			problems[id] = true
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT %s, problem_id, %s FROM %s`, p.key, p.when, p.table))
+	if err != nil {
+		return 0, err
+	}
+	var keys []any
+	for rows.Next() {
+		var key any
+		var problemID string
+		var when sql.NullString
+		if err := rows.Scan(&key, &problemID, &when); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if problems != nil && !problems[problemID] {
+			continue
+		}
+		if !prune.Before.IsZero() {
+			// Undated rows, such as hint usage without a hint, are kept
+			at, err := time.Parse(timeLayout, when.String)
+			if err != nil || !at.Before(prune.Before) {
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, p.table, p.key), key); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+// Backup writes a consistent copy of the database to path, which must not
+// exist
+func (s *SQLiteStore) Backup(ctx context.Context, path string) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("failed to back up progress: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// S3-compatible backend for user data
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// amzDateLayout is the timestamp format used in SigV4 signatures
+const amzDateLayout = "20060102T150405Z"
+
+// S3Backend keeps user data in an S3 bucket or an S3-compatible store such
+// as MinIO. Objects are addressed path-style: endpoint/bucket/prefix/name.
+type S3Backend struct {
+	cfg    config.S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Backend creates a backend for the bucket in cfg. Credentials missing
+// from cfg are taken from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func NewS3Backend(cfg config.S3Config, client *http.Client) (*S3Backend, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 storage needs an endpoint and a bucket")
+	}
+	if _, err := url.Parse(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %v", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if cfg.SecretAccessKey == "" {
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 storage needs credentials: set accessKeyId and secretAccessKey or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return &S3Backend{cfg: cfg, client: client, now: time.Now}, nil
+}
+
+// Get downloads an object
+func (b *S3Backend) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := b.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, notExist(name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("s3", http.MethodGet, name, resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put uploads an object
+func (b *S3Backend) Put(ctx context.Context, name string, data []byte) error {
+	resp, err := b.do(ctx, http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError("s3", http.MethodPut, name, resp)
+	}
+	return nil
+}
+
+// Delete removes an object
+func (b *S3Backend) Delete(ctx context.Context, name string) error {
+	resp, err := b.do(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return statusError("s3", http.MethodDelete, name, resp)
+}
+
+// do sends a signed request for an object
+func (b *S3Backend) do(ctx context.Context, method, name string, body []byte) (*http.Response, error) {
+	clean, err := cleanName(name)
+	if err != nil {
+		return nil, err
+	}
+	key := clean
+	if prefix := strings.Trim(b.cfg.Prefix, "/"); prefix != "" {
+		key = prefix + "/" + clean
+	}
+	u, err := url.Parse(strings.TrimRight(b.cfg.Endpoint, "/") + "/" + awsEscapePath(b.cfg.Bucket+"/"+key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 object URL: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	signS3(req, body, b.cfg, b.now().UTC())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s failed: %v", method, name, err)
+	}
+	return resp, nil
+}
+
+// signS3 signs a request with AWS Signature Version 4. The host and every
+// header already set on the request are signed.
+func signS3(req *http.Request, body []byte, cfg config.S3Config, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateLayout)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format("20060102") + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscapePath escapes each segment of a slash-separated path
+func awsEscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = awsEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsEscape percent-encodes everything but unreserved characters
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sha256Hex returns the hex-encoded SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
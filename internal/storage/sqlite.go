@@ -0,0 +1,650 @@
+// SQLite implementation of the progress repository
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+
+	_ "modernc.org/sqlite" // Pure Go driver, so builds need no C toolchain
+)
+
+// timeLayout is how times are stored in the database
+const timeLayout = time.RFC3339Nano
+
+// SQLiteStore implements Repository on a SQLite database file
+type SQLiteStore struct {
+	path string
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewSQLiteStore creates a store for the database at path. The database is
+// opened and migrated on first use.
+func NewSQLiteStore(path string) *SQLiteStore {
+	return &SQLiteStore{path: path}
+}
+
+// open opens the database once, creating and migrating it as needed
+func (s *SQLiteStore) open() (*sql.DB, error) {
+	s.once.Do(func() {
+		if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+			s.err = fmt.Errorf("failed to create database directory: %v", err)
+			return
+		}
+
+		db, err := sql.Open("sqlite", s.path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+		if err != nil {
+			s.err = fmt.Errorf("failed to open database: %v", err)
+			return
+		}
+		if err := migrate(db, filepath.Dir(s.path)); err != nil {
+			db.Close()
+			s.err = fmt.Errorf("failed to migrate database: %v", err)
+			return
+		}
+		s.db = db
+	})
+	return s.db, s.err
+}
+
+// CheckDatabase reports whether the progress database at path is intact
+// and holds the progress tables, without migrating it
+func CheckDatabase(path string) error {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow(`PRAGMA quick_check`).Scan(&result); err != nil {
+		return fmt.Errorf("damaged database: %v", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("damaged database: %s", result)
+	}
+
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('sessions', 'streaks')`).Scan(&tables); err != nil {
+		return fmt.Errorf("damaged database: %v", err)
+	}
+	if tables != 2 {
+		return fmt.Errorf("not a progress database")
+	}
+	return nil
+}
+
+// Close releases the database
+func (s *SQLiteStore) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// execer is satisfied by both databases and transactions
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SaveSession saves a session's statistics, replacing an earlier save of
+// the same session
+func (s *SQLiteStore) SaveSession(ctx context.Context, session interfaces.SessionStats) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	return insertSession(ctx, db, session)
+}
+
+// insertSession writes a session row
+func insertSession(ctx context.Context, db execer, session interfaces.SessionStats) error {
+	patterns, err := json.Marshal(session.Patterns)
+	if err != nil {
+		return err
+	}
+	var environment any
+	if session.Environment != nil {
+		data, err := json.Marshal(session.Environment)
+		if err != nil {
+			return err
+		}
+		environment = string(data)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO sessions (problem_id, start_time, end_time, duration, solved, mode,
+			hints_used, solution_used, patterns, difficulty, environment, swapped_to, context)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (problem_id, start_time) DO UPDATE SET
+			end_time = excluded.end_time, duration = excluded.duration, solved = excluded.solved,
+			mode = excluded.mode, hints_used = excluded.hints_used, solution_used = excluded.solution_used,
+			patterns = excluded.patterns, difficulty = excluded.difficulty, environment = excluded.environment,
+			swapped_to = excluded.swapped_to, context = excluded.context`,
+		session.ProblemID, session.StartTime.Format(timeLayout), session.EndTime.Format(timeLayout),
+		int64(session.Duration), session.Solved, session.Mode, session.HintsUsed, session.SolutionUsed,
+		string(patterns), session.Difficulty, environment, session.SwappedTo, session.Context)
+	if err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	return nil
+}
+
+// LoadAllSessions loads all session statistics in the order they were saved
+func (s *SQLiteStore) LoadAllSessions(ctx context.Context) ([]interfaces.SessionStats, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT problem_id, start_time, end_time, duration, solved, mode,
+			hints_used, solution_used, patterns, difficulty, environment, swapped_to, context
+		FROM sessions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %v", err)
+	}
+	defer rows.Close()
+
+	sessions := []interfaces.SessionStats{}
+	for rows.Next() {
+		var (
+			session            interfaces.SessionStats
+			startTime, endTime string
+			duration           int64
+			patterns           string
+			environment        sql.NullString
+		)
+		if err := rows.Scan(&session.ProblemID, &startTime, &endTime, &duration, &session.Solved, &session.Mode,
+			&session.HintsUsed, &session.SolutionUsed, &patterns, &session.Difficulty, &environment, &session.SwappedTo,
+			&session.Context); err != nil {
+			return nil, fmt.Errorf("failed to read session: %v", err)
+		}
+		session.StartTime, _ = time.Parse(timeLayout, startTime)
+		session.EndTime, _ = time.Parse(timeLayout, endTime)
+		session.Duration = time.Duration(duration)
+		if err := json.Unmarshal([]byte(patterns), &session.Patterns); err != nil {
+			return nil, fmt.Errorf("failed to read session patterns: %v", err)
+		}
+		if environment.Valid {
+			session.Environment = &interfaces.EnvironmentSnapshot{}
+			if err := json.Unmarshal([]byte(environment.String), session.Environment); err != nil {
+				return nil, fmt.Errorf("failed to read session environment: %v", err)
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// ClearAllSessions removes all sessions along with the attempts made in them
+// and the review schedule, hint usage and complexity estimates built from
+// them
+func (s *SQLiteStore) ClearAllSessions(ctx context.Context) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM sessions; DELETE FROM attempts; DELETE FROM reviews; DELETE FROM hint_usage; DELETE FROM complexity; DELETE FROM quiz_answers`); err != nil {
+		return fmt.Errorf("failed to clear sessions: %v", err)
+	}
+	return nil
+}
+
+// SaveAttempt records an attempt with its test results
+func (s *SQLiteStore) SaveAttempt(ctx context.Context, attempt Attempt) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save attempt: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO attempts (problem_id, language, time, passed, code, submitted) VALUES (?, ?, ?, ?, ?, ?)`,
+		attempt.ProblemID, attempt.Language, attempt.Time.Format(timeLayout), attempt.Passed, attempt.Code, attempt.Submitted)
+	if err != nil {
+		return fmt.Errorf("failed to save attempt: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to save attempt: %v", err)
+	}
+
+	for _, result := range attempt.Results {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO test_results (attempt_id, number, passed, failure, duration) VALUES (?, ?, ?, ?, ?)`,
+			id, result.Number, result.Passed, string(result.Failure), int64(result.Duration)); err != nil {
+			return fmt.Errorf("failed to save test result: %v", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadAttempts returns the attempts at a problem, oldest first; an empty
+// problem ID returns all attempts
+func (s *SQLiteStore) LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.id, a.problem_id, a.language, a.time, a.passed, a.code, a.submitted, r.number, r.passed, r.failure, r.duration
+		FROM attempts a LEFT JOIN test_results r ON r.attempt_id = a.id
+		WHERE ? = '' OR a.problem_id = ?
+		ORDER BY a.id, r.number`, problemID, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attempts: %v", err)
+	}
+	defer rows.Close()
+
+	attempts := []Attempt{}
+	lastID := int64(-1)
+	for rows.Next() {
+		var (
+			id       int64
+			attempt  Attempt
+			when     string
+			number   sql.NullInt64
+			passed   sql.NullBool
+			failure  sql.NullString
+			duration sql.NullInt64
+		)
+		if err := rows.Scan(&id, &attempt.ProblemID, &attempt.Language, &when, &attempt.Passed, &attempt.Code,
+			&attempt.Submitted, &number, &passed, &failure, &duration); err != nil {
+			return nil, fmt.Errorf("failed to read attempt: %v", err)
+		}
+		if id != lastID {
+			attempt.Time, _ = time.Parse(timeLayout, when)
+			attempts = append(attempts, attempt)
+			lastID = id
+		}
+		if number.Valid {
+			current := &attempts[len(attempts)-1]
+			current.Results = append(current.Results, TestResult{
+				Number:   int(number.Int64),
+				Passed:   passed.Bool,
+				Failure:  interfaces.FailureKind(failure.String),
+				Duration: time.Duration(duration.Int64),
+			})
+		}
+	}
+	return attempts, rows.Err()
+}
+
+// LoadStreak returns a streak, or a zero streak if it was never saved
+func (s *SQLiteStore) LoadStreak(ctx context.Context, name string) (Streak, error) {
+	streak := Streak{Name: name, Completed: []string{}}
+	db, err := s.open()
+	if err != nil {
+		return streak, err
+	}
+
+	var lastPracticed sql.NullString
+	var completed string
+	err = db.QueryRowContext(ctx, `SELECT current, longest, last_practiced, position, completed, freezes FROM streaks WHERE name = ?`, name).
+		Scan(&streak.Current, &streak.Longest, &lastPracticed, &streak.Position, &completed, &streak.Freezes)
+	if err == sql.ErrNoRows {
+		return streak, nil
+	}
+	if err != nil {
+		return streak, fmt.Errorf("failed to load streak: %v", err)
+	}
+
+	if lastPracticed.Valid {
+		streak.LastPracticed, _ = time.Parse(timeLayout, lastPracticed.String)
+	}
+	if err := json.Unmarshal([]byte(completed), &streak.Completed); err != nil {
+		return streak, fmt.Errorf("failed to read streak: %v", err)
+	}
+	return streak, nil
+}
+
+// SaveStreak stores a streak
+func (s *SQLiteStore) SaveStreak(ctx context.Context, streak Streak) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	return upsertStreak(ctx, db, streak)
+}
+
+// upsertStreak writes a streak row; a never-practiced streak has no date
+func upsertStreak(ctx context.Context, db execer, streak Streak) error {
+	completed := streak.Completed
+	if completed == nil {
+		completed = []string{}
+	}
+	data, err := json.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	var lastPracticed any
+	if !streak.LastPracticed.IsZero() {
+		lastPracticed = streak.LastPracticed.Format(timeLayout)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO streaks (name, current, longest, last_practiced, position, completed, freezes)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			current = excluded.current, longest = excluded.longest, last_practiced = excluded.last_practiced,
+			position = excluded.position, completed = excluded.completed, freezes = excluded.freezes`,
+		streak.Name, streak.Current, streak.Longest, lastPracticed, streak.Position, string(data), streak.Freezes)
+	if err != nil {
+		return fmt.Errorf("failed to save streak: %v", err)
+	}
+	return nil
+}
+
+// LoadReviews returns the review state of every problem that has one,
+// soonest due first
+func (s *SQLiteStore) LoadReviews(ctx context.Context) ([]Review, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT problem_id, ease, interval_days, repetitions, lapses, due, last_reviewed
+		FROM reviews ORDER BY due, problem_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews := []Review{}
+	for rows.Next() {
+		var review Review
+		var due, lastReviewed string
+		if err := rows.Scan(&review.ProblemID, &review.Ease, &review.Interval, &review.Repetitions,
+			&review.Lapses, &due, &lastReviewed); err != nil {
+			return nil, fmt.Errorf("failed to read review: %v", err)
+		}
+		review.Due, _ = time.Parse(timeLayout, due)
+		review.LastReviewed, _ = time.Parse(timeLayout, lastReviewed)
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// SaveReview stores a problem's review state
+func (s *SQLiteStore) SaveReview(ctx context.Context, review Review) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO reviews (problem_id, ease, interval_days, repetitions, lapses, due, last_reviewed)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (problem_id) DO UPDATE SET
+			ease = excluded.ease, interval_days = excluded.interval_days, repetitions = excluded.repetitions,
+			lapses = excluded.lapses, due = excluded.due, last_reviewed = excluded.last_reviewed`,
+		review.ProblemID, review.Ease, review.Interval, review.Repetitions, review.Lapses,
+		review.Due.UTC().Format(timeLayout), review.LastReviewed.UTC().Format(timeLayout))
+	if err != nil {
+		return fmt.Errorf("failed to save review: %v", err)
+	}
+	return nil
+}
+
+// LoadHintUsage returns a problem's hint usage, or zero usage if none was
+// saved
+func (s *SQLiteStore) LoadHintUsage(ctx context.Context, problemID string) (HintUsage, error) {
+	usage := HintUsage{ProblemID: problemID}
+	db, err := s.open()
+	if err != nil {
+		return usage, err
+	}
+
+	row := db.QueryRowContext(ctx, `
+		SELECT problem_id, hints, ai_hints, hint_level, failed_runs, solution_viewed, last_hint, denied
+		FROM hint_usage WHERE problem_id = ?`, problemID)
+	usage, err = scanHintUsage(row)
+	if err == sql.ErrNoRows {
+		return HintUsage{ProblemID: problemID}, nil
+	}
+	if err != nil {
+		return usage, fmt.Errorf("failed to load hint usage: %v", err)
+	}
+	return usage, nil
+}
+
+// LoadAllHintUsage returns the hint usage of every problem that has any
+func (s *SQLiteStore) LoadAllHintUsage(ctx context.Context) ([]HintUsage, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT problem_id, hints, ai_hints, hint_level, failed_runs, solution_viewed, last_hint, denied
+		FROM hint_usage ORDER BY problem_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hint usage: %v", err)
+	}
+	defer rows.Close()
+
+	all := []HintUsage{}
+	for rows.Next() {
+		usage, err := scanHintUsage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hint usage: %v", err)
+		}
+		all = append(all, usage)
+	}
+	return all, rows.Err()
+}
+
+// SaveHintUsage stores a problem's hint usage
+func (s *SQLiteStore) SaveHintUsage(ctx context.Context, usage HintUsage) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	var lastHint any
+	if !usage.LastHint.IsZero() {
+		lastHint = usage.LastHint.UTC().Format(timeLayout)
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO hint_usage (problem_id, hints, ai_hints, hint_level, failed_runs, solution_viewed, last_hint, denied)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (problem_id) DO UPDATE SET
+			hints = excluded.hints, ai_hints = excluded.ai_hints, hint_level = excluded.hint_level,
+			failed_runs = excluded.failed_runs, solution_viewed = excluded.solution_viewed,
+			last_hint = excluded.last_hint, denied = excluded.denied`,
+		usage.ProblemID, usage.Hints, usage.AIHints, usage.Level, usage.FailedRuns, usage.SolutionViewed, lastHint, usage.Denied)
+	if err != nil {
+		return fmt.Errorf("failed to save hint usage: %v", err)
+	}
+	return nil
+}
+
+// scanHintUsage reads a hint_usage row
+func scanHintUsage(row interface{ Scan(...any) error }) (HintUsage, error) {
+	var usage HintUsage
+	var lastHint sql.NullString
+	if err := row.Scan(&usage.ProblemID, &usage.Hints, &usage.AIHints, &usage.Level, &usage.FailedRuns,
+		&usage.SolutionViewed, &lastHint, &usage.Denied); err != nil {
+		return usage, err
+	}
+	if lastHint.Valid {
+		usage.LastHint, _ = time.Parse(timeLayout, lastHint.String)
+	}
+	return usage, nil
+}
+
+// LoadComplexity returns the latest complexity estimate of every problem and
+// language
+func (s *SQLiteStore) LoadComplexity(ctx context.Context) ([]Complexity, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT problem_id, language, time, space, target_time, target_space, verdict, source, analyzed_at
+		FROM complexity ORDER BY problem_id, language`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load complexity: %v", err)
+	}
+	defer rows.Close()
+
+	all := []Complexity{}
+	for rows.Next() {
+		var c Complexity
+		var analyzedAt string
+		if err := rows.Scan(&c.ProblemID, &c.Language, &c.Time, &c.Space, &c.TargetTime, &c.TargetSpace,
+			&c.Verdict, &c.Source, &analyzedAt); err != nil {
+			return nil, fmt.Errorf("failed to read complexity: %v", err)
+		}
+		c.AnalyzedAt, _ = time.Parse(timeLayout, analyzedAt)
+		all = append(all, c)
+	}
+	return all, rows.Err()
+}
+
+// SaveComplexity stores a complexity estimate, replacing the previous one for
+// the problem and language
+func (s *SQLiteStore) SaveComplexity(ctx context.Context, c Complexity) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO complexity (problem_id, language, time, space, target_time, target_space, verdict, source, analyzed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (problem_id, language) DO UPDATE SET
+			time = excluded.time, space = excluded.space, target_time = excluded.target_time,
+			target_space = excluded.target_space, verdict = excluded.verdict, source = excluded.source,
+			analyzed_at = excluded.analyzed_at`,
+		c.ProblemID, c.Language, c.Time, c.Space, c.TargetTime, c.TargetSpace, c.Verdict, c.Source,
+		c.AnalyzedAt.UTC().Format(timeLayout))
+	if err != nil {
+		return fmt.Errorf("failed to save complexity: %v", err)
+	}
+	return nil
+}
+
+// LoadUserTests returns the tests the user wrote for a problem, oldest first
+func (s *SQLiteStore) LoadUserTests(ctx context.Context, problemID string) ([]UserTest, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, problem_id, input, expected, added_at
+		FROM user_tests WHERE problem_id = ? ORDER BY id`, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user tests: %v", err)
+	}
+	defer rows.Close()
+
+	tests := []UserTest{}
+	for rows.Next() {
+		var t UserTest
+		var addedAt string
+		if err := rows.Scan(&t.ID, &t.ProblemID, &t.Input, &t.Expected, &addedAt); err != nil {
+			return nil, fmt.Errorf("failed to read user test: %v", err)
+		}
+		t.AddedAt, _ = time.Parse(timeLayout, addedAt)
+		tests = append(tests, t)
+	}
+	return tests, rows.Err()
+}
+
+// SaveUserTest adds a user test, returning it with its ID
+func (s *SQLiteStore) SaveUserTest(ctx context.Context, test UserTest) (UserTest, error) {
+	db, err := s.open()
+	if err != nil {
+		return test, err
+	}
+
+	res, err := db.ExecContext(ctx, `INSERT INTO user_tests (problem_id, input, expected, added_at) VALUES (?, ?, ?, ?)`,
+		test.ProblemID, test.Input, test.Expected, test.AddedAt.UTC().Format(timeLayout))
+	if err != nil {
+		return test, fmt.Errorf("failed to save user test: %v", err)
+	}
+	if test.ID, err = res.LastInsertId(); err != nil {
+		return test, fmt.Errorf("failed to save user test: %v", err)
+	}
+	return test, nil
+}
+
+// DeleteUserTest removes a user test, reporting whether it existed
+func (s *SQLiteStore) DeleteUserTest(ctx context.Context, id int64) (bool, error) {
+	db, err := s.open()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := db.ExecContext(ctx, `DELETE FROM user_tests WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user test: %v", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user test: %v", err)
+	}
+	return n > 0, nil
+}
+
+// LoadQuizAnswers returns every answer given in the pattern quiz, oldest first
+func (s *SQLiteStore) LoadQuizAnswers(ctx context.Context) ([]QuizAnswer, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, problem_id, pattern, chosen, correct, time
+		FROM quiz_answers ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quiz answers: %v", err)
+	}
+	defer rows.Close()
+
+	answers := []QuizAnswer{}
+	for rows.Next() {
+		var a QuizAnswer
+		var at string
+		if err := rows.Scan(&a.ID, &a.ProblemID, &a.Pattern, &a.Chosen, &a.Correct, &at); err != nil {
+			return nil, fmt.Errorf("failed to read quiz answer: %v", err)
+		}
+		a.Time, _ = time.Parse(timeLayout, at)
+		answers = append(answers, a)
+	}
+	return answers, rows.Err()
+}
+
+// SaveQuizAnswer records an answer given in the pattern quiz
+func (s *SQLiteStore) SaveQuizAnswer(ctx context.Context, answer QuizAnswer) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO quiz_answers (problem_id, pattern, chosen, correct, time) VALUES (?, ?, ?, ?, ?)`,
+		answer.ProblemID, answer.Pattern, answer.Chosen, answer.Correct, answer.Time.UTC().Format(timeLayout))
+	if err != nil {
+		return fmt.Errorf("failed to save quiz answer: %v", err)
+	}
+	return nil
+}
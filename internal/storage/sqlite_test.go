@@ -0,0 +1,390 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// newTestStore opens a store in a fresh directory
+func newTestStore(t *testing.T) (*SQLiteStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store := NewSQLiteStore(filepath.Join(dir, DBFileName))
+	t.Cleanup(func() { store.Close() })
+	return store, dir
+}
+
+func TestSessions(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	session := interfaces.SessionStats{
+		ProblemID:   "two_sum",
+		StartTime:   start,
+		EndTime:     start.Add(20 * time.Minute),
+		Duration:    20 * time.Minute,
+		Solved:      true,
+		Mode:        "practice",
+		Patterns:    []string{"hash-map"},
+		Difficulty:  "easy",
+		Environment: &interfaces.EnvironmentSnapshot{Language: "go"},
+		Context:     interfaces.ContextVim,
+	}
+	require.NoError(t, store.SaveSession(ctx, session))
+
+	// Saving the same session again replaces it
+	session.HintsUsed = true
+	require.NoError(t, store.SaveSession(ctx, session))
+	require.NoError(t, store.SaveSession(ctx, interfaces.SessionStats{ProblemID: "3sum", StartTime: start, SwappedTo: "two_sum"}))
+
+	sessions, err := store.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "two_sum", sessions[0].ProblemID)
+	assert.True(t, sessions[0].StartTime.Equal(start))
+	assert.Equal(t, 20*time.Minute, sessions[0].Duration)
+	assert.True(t, sessions[0].Solved)
+	assert.True(t, sessions[0].HintsUsed)
+	assert.Equal(t, []string{"hash-map"}, sessions[0].Patterns)
+	assert.Equal(t, "go", sessions[0].Environment.Language)
+	assert.Nil(t, sessions[1].Environment)
+	assert.Empty(t, sessions[0].SwappedTo)
+	assert.Equal(t, "two_sum", sessions[1].SwappedTo)
+	assert.Equal(t, interfaces.ContextVim, sessions[0].Context)
+	assert.Empty(t, sessions[1].Context)
+
+	require.NoError(t, store.ClearAllSessions(ctx))
+	sessions, err = store.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestAttempts(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.SaveAttempt(ctx, Attempt{
+		ProblemID: "two_sum",
+		Language:  "python",
+		Time:      time.Now(),
+		Results: []TestResult{
+			{Number: 1, Passed: true, Duration: time.Millisecond},
+			{Number: 2, Failure: interfaces.FailureTimeLimit, Duration: time.Second},
+		},
+	}))
+	require.NoError(t, store.SaveAttempt(ctx, Attempt{ProblemID: "two_sum", Language: "go", Time: time.Now(), Passed: true, Code: "package main", Submitted: true}))
+	require.NoError(t, store.SaveAttempt(ctx, Attempt{ProblemID: "3sum", Language: "go", Time: time.Now()}))
+
+	attempts, err := store.LoadAttempts(ctx, "two_sum")
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, "python", attempts[0].Language)
+	require.Len(t, attempts[0].Results, 2)
+	assert.Equal(t, TestResult{Number: 2, Failure: interfaces.FailureTimeLimit, Duration: time.Second}, attempts[0].Results[1])
+	assert.True(t, attempts[1].Passed)
+	assert.Equal(t, "package main", attempts[1].Code)
+	assert.True(t, attempts[1].Submitted)
+	assert.False(t, attempts[0].Submitted)
+	assert.Empty(t, attempts[1].Results)
+
+	all, err := store.LoadAttempts(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	// Clearing the history removes attempts and their results
+	require.NoError(t, store.ClearAllSessions(ctx))
+	all, err = store.LoadAttempts(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestStreaks(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	streak, err := store.LoadStreak(ctx, DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, Streak{Name: DailyStreak, Completed: []string{}}, streak)
+
+	practiced := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveStreak(ctx, Streak{Name: DailyStreak, Current: 3, Longest: 5, LastPracticed: practiced, Position: 2, Completed: []string{"bfs"}, Freezes: 1}))
+
+	streak, err = store.LoadStreak(ctx, DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, 3, streak.Current)
+	assert.Equal(t, 5, streak.Longest)
+	assert.Equal(t, 2, streak.Position)
+	assert.True(t, streak.LastPracticed.Equal(practiced))
+	assert.Equal(t, []string{"bfs"}, streak.Completed)
+	assert.Equal(t, 1, streak.Freezes)
+}
+
+func TestReviews(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	reviews, err := store.LoadReviews(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reviews)
+
+	reviewed := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveReview(ctx, Review{ProblemID: "two_sum", Ease: 2.5, Interval: 6, Repetitions: 2, LastReviewed: reviewed, Due: reviewed.AddDate(0, 0, 6)}))
+	require.NoError(t, store.SaveReview(ctx, Review{ProblemID: "3sum", Ease: 1.3, Interval: 1, Lapses: 1, LastReviewed: reviewed, Due: reviewed.AddDate(0, 0, 1)}))
+	// Saving again replaces the earlier state
+	require.NoError(t, store.SaveReview(ctx, Review{ProblemID: "two_sum", Ease: 2.6, Interval: 16, Repetitions: 3, LastReviewed: reviewed, Due: reviewed.AddDate(0, 0, 16)}))
+
+	reviews, err = store.LoadReviews(ctx)
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, "3sum", reviews[0].ProblemID, "soonest due first")
+	assert.Equal(t, 1, reviews[0].Lapses)
+	assert.Equal(t, 16, reviews[1].Interval)
+	assert.Equal(t, 2.6, reviews[1].Ease)
+	assert.True(t, reviews[1].Due.Equal(reviewed.AddDate(0, 0, 16)))
+
+	require.NoError(t, store.ClearAllSessions(ctx))
+	reviews, err = store.LoadReviews(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reviews)
+}
+
+func TestHintUsage(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	usage, err := store.LoadHintUsage(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, HintUsage{ProblemID: "two_sum"}, usage)
+
+	shown := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveHintUsage(ctx, HintUsage{ProblemID: "two_sum", Hints: 1, LastHint: shown}))
+	require.NoError(t, store.SaveHintUsage(ctx, HintUsage{ProblemID: "3sum", FailedRuns: 2, Denied: 1}))
+	// Saving again replaces the earlier usage
+	require.NoError(t, store.SaveHintUsage(ctx, HintUsage{ProblemID: "two_sum", Hints: 2, AIHints: 1, Level: 2, SolutionViewed: true, LastHint: shown.Add(time.Minute)}))
+
+	usage, err = store.LoadHintUsage(ctx, "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Hints)
+	assert.Equal(t, 1, usage.AIHints)
+	assert.Equal(t, 2, usage.Level)
+	assert.True(t, usage.SolutionViewed)
+	assert.True(t, usage.LastHint.Equal(shown.Add(time.Minute)))
+
+	all, err := store.LoadAllHintUsage(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "3sum", all[0].ProblemID)
+	assert.True(t, all[0].LastHint.IsZero())
+	assert.Equal(t, 2, all[0].FailedRuns)
+
+	require.NoError(t, store.ClearAllSessions(ctx))
+	all, err = store.LoadAllHintUsage(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestComplexity(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	analyzed := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveComplexity(ctx, Complexity{
+		ProblemID: "two_sum", Language: "go", Time: "O(n^2)", Space: "O(1)",
+		TargetTime: "O(n)", TargetSpace: "O(n)", Verdict: "slower", Source: "heuristic", AnalyzedAt: analyzed,
+	}))
+	require.NoError(t, store.SaveComplexity(ctx, Complexity{ProblemID: "two_sum", Language: "python", Time: "O(n)", Space: "O(n)", Verdict: "unknown", Source: "ai"}))
+	// A later solution in the same language replaces the earlier estimate
+	require.NoError(t, store.SaveComplexity(ctx, Complexity{
+		ProblemID: "two_sum", Language: "go", Time: "O(n)", Space: "O(n)",
+		TargetTime: "O(n)", TargetSpace: "O(n)", Verdict: "optimal", Source: "ai", AnalyzedAt: analyzed.Add(time.Hour),
+	}))
+
+	all, err := store.LoadComplexity(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "go", all[0].Language)
+	assert.Equal(t, "optimal", all[0].Verdict)
+	assert.Equal(t, "O(n)", all[0].TargetTime)
+	assert.True(t, all[0].AnalyzedAt.Equal(analyzed.Add(time.Hour)))
+	assert.Equal(t, "python", all[1].Language)
+
+	require.NoError(t, store.ClearAllSessions(ctx))
+	all, err = store.LoadComplexity(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestUserTests(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	added := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	first, err := store.SaveUserTest(ctx, UserTest{ProblemID: "two_sum", Input: "[3,3], 6", Expected: "[0,1]", AddedAt: added})
+	require.NoError(t, err)
+	assert.NotZero(t, first.ID)
+	_, err = store.SaveUserTest(ctx, UserTest{ProblemID: "two_sum", Input: "[], 0", Expected: "[]", AddedAt: added.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = store.SaveUserTest(ctx, UserTest{ProblemID: "coin_change", Input: "[1], 0", Expected: "0", AddedAt: added})
+	require.NoError(t, err)
+
+	tests, err := store.LoadUserTests(ctx, "two_sum")
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+	assert.Equal(t, first.ID, tests[0].ID)
+	assert.Equal(t, "[3,3], 6", tests[0].Input)
+	assert.Equal(t, "[0,1]", tests[0].Expected)
+	assert.True(t, tests[0].AddedAt.Equal(added))
+	assert.Equal(t, "[], 0", tests[1].Input)
+
+	// User tests are the user's own work, not progress, so a reset keeps them
+	require.NoError(t, store.ClearAllSessions(ctx))
+	deleted, err := store.DeleteUserTest(ctx, first.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = store.DeleteUserTest(ctx, first.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	tests, err = store.LoadUserTests(ctx, "two_sum")
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+	assert.Equal(t, "[], 0", tests[0].Input)
+}
+
+func TestQuizAnswers(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	answered := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveQuizAnswer(ctx, QuizAnswer{ProblemID: "two_sum", Pattern: "hash-map", Chosen: "two-pointers", Time: answered}))
+	require.NoError(t, store.SaveQuizAnswer(ctx, QuizAnswer{ProblemID: "coin_change", Pattern: "dynamic-programming", Chosen: "dynamic-programming", Correct: true, Time: answered.Add(time.Minute)}))
+
+	answers, err := store.LoadQuizAnswers(ctx)
+	require.NoError(t, err)
+	require.Len(t, answers, 2)
+	assert.NotZero(t, answers[0].ID)
+	assert.Equal(t, "two_sum", answers[0].ProblemID)
+	assert.Equal(t, "hash-map", answers[0].Pattern)
+	assert.Equal(t, "two-pointers", answers[0].Chosen)
+	assert.False(t, answers[0].Correct)
+	assert.True(t, answers[0].Time.Equal(answered))
+	assert.True(t, answers[1].Correct)
+
+	require.NoError(t, store.ClearAllSessions(ctx))
+	answers, err = store.LoadQuizAnswers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+}
+
+func TestPruneProgress(t *testing.T) {
+	store, dir := newTestStore(t)
+	ctx := context.Background()
+
+	old := time.Date(2023, 6, 1, 9, 0, 0, 0, time.UTC)
+	recent := time.Date(2026, 3, 1, 9, 0, 0, 0, time.FixedZone("PST", -8*3600))
+	for _, id := range []string{"two_sum", "coin_change"} {
+		for _, at := range []time.Time{old, recent} {
+			require.NoError(t, store.SaveSession(ctx, interfaces.SessionStats{ProblemID: id, StartTime: at, EndTime: at, Patterns: []string{"any"}}))
+			require.NoError(t, store.SaveAttempt(ctx, Attempt{ProblemID: id, Language: "go", Time: at, Results: []TestResult{{Number: 1, Passed: true}}}))
+		}
+		require.NoError(t, store.SaveReview(ctx, Review{ProblemID: id, Due: recent, LastReviewed: old}))
+		require.NoError(t, store.SaveHintUsage(ctx, HintUsage{ProblemID: id, FailedRuns: 1}))
+		require.NoError(t, store.SaveComplexity(ctx, Complexity{ProblemID: id, Language: "go", AnalyzedAt: recent}))
+		require.NoError(t, store.SaveQuizAnswer(ctx, QuizAnswer{ProblemID: id, Pattern: "dfs", Chosen: "bfs", Time: old}))
+	}
+
+	backup := filepath.Join(dir, "backups", "progress.db")
+	require.NoError(t, store.Backup(ctx, backup))
+	assert.Error(t, store.Backup(ctx, backup), "a backup is never overwritten")
+
+	// Everything about coin_change from before 2024
+	pruned, err := store.PruneProgress(ctx, Prune{ProblemIDs: []string{"coin_change"}, Before: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Equal(t, Pruned{Sessions: 1, Attempts: 1, Reviews: 1, QuizAnswers: 1}, pruned)
+
+	sessions, err := store.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 3)
+	attempts, err := store.LoadAttempts(ctx, "coin_change")
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.True(t, attempts[0].Time.Equal(recent))
+	usage, err := store.LoadAllHintUsage(ctx)
+	require.NoError(t, err)
+	assert.Len(t, usage, 2, "hint usage without a hint is undated and kept")
+
+	// Everything else
+	pruned, err = store.PruneProgress(ctx, Prune{})
+	require.NoError(t, err)
+	assert.Equal(t, Pruned{Sessions: 3, Attempts: 3, Reviews: 1, HintUsage: 2, Complexity: 2, QuizAnswers: 1}, pruned)
+	assert.Equal(t, 12, pruned.Total())
+
+	// The backup still holds the progress from before
+	copied := NewSQLiteStore(backup)
+	defer copied.Close()
+	sessions, err = copied.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 4)
+}
+
+func TestMigratesLegacyProgress(t *testing.T) {
+	dir := t.TempDir()
+	statsDir := filepath.Join(dir, legacyStatsDir)
+	require.NoError(t, os.MkdirAll(statsDir, 0755))
+
+	// Session files as earlier versions wrote them, plus one that is corrupt
+	start := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	for i, id := range []string{"two_sum", "3sum"} {
+		data, err := json.Marshal(legacySession{ProblemID: id, StartTime: start.Add(time.Duration(i) * time.Hour), Duration: time.Minute, Solved: true, Patterns: []string{"two-pointers"}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(statsDir, "session_"+id+".json"), data, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(statsDir, "session_broken.json"), []byte("{"), 0644))
+
+	// Daily progress as earlier versions kept it in BoltDB
+	bolt, err := bbolt.Open(filepath.Join(statsDir, legacyDailyDB), 0600, nil)
+	require.NoError(t, err)
+	progress, err := json.Marshal(legacyProgress{Current: 4, LastPracticed: start, Completed: []string{"dfs"}, Streak: 2, LongestStreak: 6})
+	require.NoError(t, err)
+	require.NoError(t, bolt.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(legacyDailyBucket))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(legacyProgressKey), progress)
+	}))
+	require.NoError(t, bolt.Close())
+
+	ctx := context.Background()
+	store := NewSQLiteStore(filepath.Join(dir, DBFileName))
+	sessions, err := store.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	streak, err := store.LoadStreak(ctx, DailyStreak)
+	require.NoError(t, err)
+	assert.Equal(t, Streak{Name: DailyStreak, Current: 2, Longest: 6, LastPracticed: streak.LastPracticed, Position: 4, Completed: []string{"dfs"}}, streak)
+	assert.True(t, streak.LastPracticed.Equal(start))
+	require.NoError(t, store.Close())
+
+	// The import runs once, so cleared history stays cleared
+	store = NewSQLiteStore(filepath.Join(dir, DBFileName))
+	require.NoError(t, store.ClearAllSessions(ctx))
+	require.NoError(t, store.Close())
+
+	store = NewSQLiteStore(filepath.Join(dir, DBFileName))
+	defer store.Close()
+	sessions, err = store.LoadAllSessions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
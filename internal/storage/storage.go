@@ -0,0 +1,211 @@
+// Package storage persists practice progress in a local SQLite database
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+)
+
+// DBFileName is the name of the progress database in the config directory
+const DBFileName = "progress.db"
+
+// DailyStreak is the name of the daily scale practice streak
+const DailyStreak = "daily"
+
+// Attempt is one run of a solution against a problem's tests
+type Attempt struct {
+	ProblemID string
+	Language  string
+	Time      time.Time
+	Passed    bool
+	Code      string // The solution as it was tested
+	Submitted bool   // Every tier of tests ran, rather than only the examples
+	Results   []TestResult
+}
+
+// TestResult is the outcome of a single test within an attempt
+type TestResult struct {
+	Number   int // 1-based test case number
+	Passed   bool
+	Failure  interfaces.FailureKind
+	Duration time.Duration
+}
+
+// Streak tracks consecutive days of practice along with the position in a
+// practice rotation
+type Streak struct {
+	Name          string
+	Current       int
+	Longest       int
+	LastPracticed time.Time
+	Position      int
+	Completed     []string
+	Freezes       int // Freezes held, each covering one missed day
+}
+
+// Review is the spaced-repetition state of a problem
+type Review struct {
+	ProblemID    string
+	Ease         float64 // SM-2 ease factor
+	Interval     int     // Days until the next review
+	Repetitions  int     // Successful reviews in a row
+	Lapses       int     // Times the problem was failed after being learned
+	Due          time.Time
+	LastReviewed time.Time
+}
+
+// HintUsage is how much help a problem has had since it was last solved
+type HintUsage struct {
+	ProblemID      string
+	Hints          int // Hints shown, AI hints included
+	AIHints        int // AI hints among Hints
+	Level          int // Highest hint level shown, 0 if none
+	FailedRuns     int // Test runs that did not pass
+	SolutionViewed bool
+	LastHint       time.Time // Zero if no hint was shown
+	Denied         int       // Requests refused by the hint policy, kept when the problem is solved
+}
+
+// Complexity is the estimated complexity of a problem's latest passing
+// solution in a language, next to the reference solution's
+type Complexity struct {
+	ProblemID   string
+	Language    string
+	Time        string // Big-O notation, e.g. O(n log n)
+	Space       string
+	TargetTime  string // Empty if the problem has no reference solution
+	TargetSpace string
+	Verdict     string // "optimal", "slower", "more-space" or "unknown"
+	Source      string // "heuristic" or "ai"
+	AnalyzedAt  time.Time
+}
+
+// UserTest is a test case a user wrote for a problem. User tests are run
+// alongside the problem's own tests and are kept when progress is reset.
+type UserTest struct {
+	ID        int64 // Assigned when the test is saved
+	ProblemID string
+	Input     string
+	Expected  string
+	AddedAt   time.Time
+}
+
+// QuizAnswer is one answer in the pattern quiz: the pattern picked for a
+// problem's description, and the pattern that was right
+type QuizAnswer struct {
+	ID        int64 // Assigned when the answer is saved
+	ProblemID string
+	Pattern   string // The right answer
+	Chosen    string
+	Correct   bool
+	Time      time.Time
+}
+
+// Prune selects the progress to delete. Zero fields select everything.
+type Prune struct {
+	ProblemIDs []string  // Only progress on these problems; nil for all problems
+	Before     time.Time // Only progress from before this time
+}
+
+// Pruned counts the records a prune deleted
+type Pruned struct {
+	Sessions    int
+	Attempts    int
+	Reviews     int
+	HintUsage   int
+	Complexity  int
+	QuizAnswers int
+}
+
+// Total is the number of records deleted
+func (p Pruned) Total() int {
+	return p.Sessions + p.Attempts + p.Reviews + p.HintUsage + p.Complexity + p.QuizAnswers
+}
+
+// Repository stores sessions, attempts, streaks, reviews, hint usage,
+// complexity estimates, user tests and quiz answers. It extends the stats
+// storage so the stats service can use it directly.
+type Repository interface {
+	interfaces.StatsStorage
+
+	// SaveAttempt records an attempt with its test results
+	SaveAttempt(ctx context.Context, attempt Attempt) error
+
+	// LoadAttempts returns the attempts at a problem, oldest first; an empty
+	// problem ID returns all attempts
+	LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error)
+
+	// LoadStreak returns a streak, or a zero streak if it was never saved
+	LoadStreak(ctx context.Context, name string) (Streak, error)
+
+	// SaveStreak stores a streak
+	SaveStreak(ctx context.Context, streak Streak) error
+
+	// LoadReviews returns the review state of every problem that has one
+	LoadReviews(ctx context.Context) ([]Review, error)
+
+	// SaveReview stores a problem's review state
+	SaveReview(ctx context.Context, review Review) error
+
+	// LoadHintUsage returns a problem's hint usage, or zero usage if none
+	// was saved
+	LoadHintUsage(ctx context.Context, problemID string) (HintUsage, error)
+
+	// LoadAllHintUsage returns the hint usage of every problem that has any
+	LoadAllHintUsage(ctx context.Context) ([]HintUsage, error)
+
+	// SaveHintUsage stores a problem's hint usage
+	SaveHintUsage(ctx context.Context, usage HintUsage) error
+
+	// LoadComplexity returns the latest complexity estimate of every
+	// problem and language
+	LoadComplexity(ctx context.Context) ([]Complexity, error)
+
+	// SaveComplexity stores a complexity estimate, replacing the previous
+	// one for the problem and language
+	SaveComplexity(ctx context.Context, c Complexity) error
+
+	// LoadUserTests returns the tests the user wrote for a problem, oldest
+	// first
+	LoadUserTests(ctx context.Context, problemID string) ([]UserTest, error)
+
+	// SaveUserTest adds a user test, returning it with its ID
+	SaveUserTest(ctx context.Context, test UserTest) (UserTest, error)
+
+	// DeleteUserTest removes a user test, reporting whether it existed
+	DeleteUserTest(ctx context.Context, id int64) (bool, error)
+
+	// LoadQuizAnswers returns every answer given in the pattern quiz,
+	// oldest first
+	LoadQuizAnswers(ctx context.Context) ([]QuizAnswer, error)
+
+	// SaveQuizAnswer records an answer given in the pattern quiz
+	SaveQuizAnswer(ctx context.Context, answer QuizAnswer) error
+
+	// PruneProgress deletes the sessions, attempts, reviews, hint usage,
+	// complexity estimates and quiz answers the prune selects. Streaks are
+	// left to the caller to recount from the sessions that remain.
+	PruneProgress(ctx context.Context, prune Prune) (Pruned, error)
+
+	// Backup writes a copy of the database to path, which must not exist
+	Backup(ctx context.Context, path string) error
+
+	// Close releases the database
+	Close() error
+}
+
+// DBPath returns the path of the progress database
+// Exported as variable for testing
+var DBPath = func() string {
+	return filepath.Join(utils.GetConfigDir(), DBFileName)
+}
+
+// Default returns the repository at DBPath, kept in sync with the configured
+// storage backend. The database is opened, created and migrated on first use.
+func Default() Repository {
+	return Open(DBPath())
+}
@@ -0,0 +1,237 @@
+// Progress database kept in sync with a remote backend
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+)
+
+// SyncedStore is a SQLite store whose database file is mirrored in a
+// backend. The remote copy is downloaded once before first use and uploaded
+// after every change, so the last machine to write wins.
+type SyncedStore struct {
+	*SQLiteStore
+	backend Backend
+	name    string
+	once    sync.Once
+	err     error
+}
+
+// NewSyncedStore creates a store for the local database at path, mirrored in
+// backend under the same file name
+func NewSyncedStore(path string, backend Backend) *SyncedStore {
+	return &SyncedStore{
+		SQLiteStore: NewSQLiteStore(path),
+		backend:     backend,
+		name:        filepath.Base(path),
+	}
+}
+
+// pull downloads the remote database over the local one, once. Without a
+// remote copy yet, the local database is kept and uploaded on first change.
+func (s *SyncedStore) pull(ctx context.Context) error {
+	s.once.Do(func() {
+		if s.err != nil {
+			return
+		}
+		data, err := s.backend.Get(ctx, s.name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		if err != nil {
+			s.err = fmt.Errorf("failed to download progress: %v", err)
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+			s.err = fmt.Errorf("failed to create database directory: %v", err)
+			return
+		}
+		if err := NewLocalBackend(filepath.Dir(s.path)).Put(ctx, s.name, data); err != nil {
+			s.err = fmt.Errorf("failed to save downloaded progress: %v", err)
+		}
+	})
+	return s.err
+}
+
+// push uploads the local database
+func (s *SyncedStore) push(ctx context.Context) error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read database: %v", err)
+	}
+	if err := s.backend.Put(ctx, s.name, data); err != nil {
+		return fmt.Errorf("failed to upload progress: %v", err)
+	}
+	return nil
+}
+
+// write runs a change against the local database and uploads the result
+func (s *SyncedStore) write(ctx context.Context, change func() error) error {
+	if err := s.pull(ctx); err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	return s.push(ctx)
+}
+
+// SaveSession records a session and uploads the database
+func (s *SyncedStore) SaveSession(ctx context.Context, session interfaces.SessionStats) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveSession(ctx, session) })
+}
+
+// LoadAllSessions returns every recorded session
+func (s *SyncedStore) LoadAllSessions(ctx context.Context) ([]interfaces.SessionStats, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadAllSessions(ctx)
+}
+
+// ClearAllSessions deletes all progress and uploads the database
+func (s *SyncedStore) ClearAllSessions(ctx context.Context) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.ClearAllSessions(ctx) })
+}
+
+// SaveAttempt records an attempt and uploads the database
+func (s *SyncedStore) SaveAttempt(ctx context.Context, attempt Attempt) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveAttempt(ctx, attempt) })
+}
+
+// LoadAttempts returns the attempts at a problem
+func (s *SyncedStore) LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadAttempts(ctx, problemID)
+}
+
+// LoadStreak returns a streak
+func (s *SyncedStore) LoadStreak(ctx context.Context, name string) (Streak, error) {
+	if err := s.pull(ctx); err != nil {
+		return Streak{}, err
+	}
+	return s.SQLiteStore.LoadStreak(ctx, name)
+}
+
+// SaveStreak stores a streak and uploads the database
+func (s *SyncedStore) SaveStreak(ctx context.Context, streak Streak) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveStreak(ctx, streak) })
+}
+
+// LoadReviews returns the review state of every problem that has one
+func (s *SyncedStore) LoadReviews(ctx context.Context) ([]Review, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadReviews(ctx)
+}
+
+// SaveReview stores a problem's review state and uploads the database
+func (s *SyncedStore) SaveReview(ctx context.Context, review Review) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveReview(ctx, review) })
+}
+
+// LoadHintUsage reads from the remote copy, pulled once
+func (s *SyncedStore) LoadHintUsage(ctx context.Context, problemID string) (HintUsage, error) {
+	if err := s.pull(ctx); err != nil {
+		return HintUsage{ProblemID: problemID}, err
+	}
+	return s.SQLiteStore.LoadHintUsage(ctx, problemID)
+}
+
+// LoadAllHintUsage reads from the remote copy, pulled once
+func (s *SyncedStore) LoadAllHintUsage(ctx context.Context) ([]HintUsage, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadAllHintUsage(ctx)
+}
+
+// SaveHintUsage writes locally and pushes the result
+func (s *SyncedStore) SaveHintUsage(ctx context.Context, usage HintUsage) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveHintUsage(ctx, usage) })
+}
+
+// LoadComplexity returns the latest complexity estimates
+func (s *SyncedStore) LoadComplexity(ctx context.Context) ([]Complexity, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadComplexity(ctx)
+}
+
+// SaveComplexity stores a complexity estimate and uploads the database
+func (s *SyncedStore) SaveComplexity(ctx context.Context, c Complexity) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveComplexity(ctx, c) })
+}
+
+// LoadUserTests reads from the remote copy, pulled once
+func (s *SyncedStore) LoadUserTests(ctx context.Context, problemID string) ([]UserTest, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadUserTests(ctx, problemID)
+}
+
+// SaveUserTest adds a user test and uploads the database
+func (s *SyncedStore) SaveUserTest(ctx context.Context, test UserTest) (UserTest, error) {
+	err := s.write(ctx, func() error {
+		var err error
+		test, err = s.SQLiteStore.SaveUserTest(ctx, test)
+		return err
+	})
+	return test, err
+}
+
+// DeleteUserTest removes a user test and uploads the database
+func (s *SyncedStore) DeleteUserTest(ctx context.Context, id int64) (bool, error) {
+	var deleted bool
+	err := s.write(ctx, func() error {
+		var err error
+		deleted, err = s.SQLiteStore.DeleteUserTest(ctx, id)
+		return err
+	})
+	return deleted, err
+}
+
+// LoadQuizAnswers reads from the remote copy, pulled once
+func (s *SyncedStore) LoadQuizAnswers(ctx context.Context) ([]QuizAnswer, error) {
+	if err := s.pull(ctx); err != nil {
+		return nil, err
+	}
+	return s.SQLiteStore.LoadQuizAnswers(ctx)
+}
+
+// SaveQuizAnswer records a quiz answer and uploads the database
+func (s *SyncedStore) SaveQuizAnswer(ctx context.Context, answer QuizAnswer) error {
+	return s.write(ctx, func() error { return s.SQLiteStore.SaveQuizAnswer(ctx, answer) })
+}
+
+// PruneProgress deletes the selected progress and uploads the database
+func (s *SyncedStore) PruneProgress(ctx context.Context, prune Prune) (Pruned, error) {
+	var pruned Pruned
+	err := s.write(ctx, func() error {
+		var err error
+		pruned, err = s.SQLiteStore.PruneProgress(ctx, prune)
+		return err
+	})
+	return pruned, err
+}
+
+// Backup copies the database, as pulled from the backend, to path
+func (s *SyncedStore) Backup(ctx context.Context, path string) error {
+	if err := s.pull(ctx); err != nil {
+		return err
+	}
+	return s.SQLiteStore.Backup(ctx, path)
+}
@@ -0,0 +1,165 @@
+// WebDAV backend for user data
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+)
+
+// WebDAVBackend keeps user data in a WebDAV collection, such as a Nextcloud
+// folder. Missing sub-collections are created on upload.
+type WebDAVBackend struct {
+	cfg    config.WebDAVConfig
+	base   *url.URL
+	client *http.Client
+}
+
+// NewWebDAVBackend creates a backend for the collection in cfg. A password
+// missing from cfg is taken from ALGO_SCALES_WEBDAV_PASSWORD.
+func NewWebDAVBackend(cfg config.WebDAVConfig, client *http.Client) (*WebDAVBackend, error) {
+	base, err := url.Parse(strings.TrimRight(cfg.URL, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("webdav storage needs a collection URL, got %q", cfg.URL)
+	}
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv("ALGO_SCALES_WEBDAV_PASSWORD")
+	}
+	return &WebDAVBackend{cfg: cfg, base: base, client: client}, nil
+}
+
+// Get downloads a file
+func (b *WebDAVBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := b.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, notExist(name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("webdav", http.MethodGet, name, resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put uploads a file, creating its parent collections if the server reports
+// them missing
+func (b *WebDAVBackend) Put(ctx context.Context, name string, data []byte) error {
+	status, err := b.put(ctx, name, data)
+	if err == nil || status != http.StatusConflict {
+		return err
+	}
+	if err := b.mkcolParents(ctx, name); err != nil {
+		return err
+	}
+	_, err = b.put(ctx, name, data)
+	return err
+}
+
+// put uploads a file once, returning the response status
+func (b *WebDAVBackend) put(ctx context.Context, name string, data []byte) (int, error) {
+	resp, err := b.do(ctx, http.MethodPut, name, data)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, statusError("webdav", http.MethodPut, name, resp)
+}
+
+// mkcolParents creates the collections leading to a file. Collections that
+// already exist answer 405, which is fine.
+func (b *WebDAVBackend) mkcolParents(ctx context.Context, name string) error {
+	dir := path.Dir(name)
+	if dir == "." {
+		return nil
+	}
+	collection := ""
+	for _, segment := range strings.Split(dir, "/") {
+		collection = path.Join(collection, segment)
+		resp, err := b.do(ctx, "MKCOL", collection+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusCreated, http.StatusMethodNotAllowed:
+		default:
+			return statusError("webdav", "MKCOL", collection, resp)
+		}
+	}
+	return nil
+}
+
+// Delete removes a file
+func (b *WebDAVBackend) Delete(ctx context.Context, name string) error {
+	resp, err := b.do(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return statusError("webdav", http.MethodDelete, name, resp)
+}
+
+// do sends an authenticated request for a file or collection under the base
+func (b *WebDAVBackend) do(ctx context.Context, method, name string, body []byte) (*http.Response, error) {
+	clean, err := cleanName(strings.TrimSuffix(name, "/"))
+	if err != nil {
+		return nil, err
+	}
+	u := *b.base
+	u.Path = b.base.Path + "/" + clean
+	if strings.HasSuffix(name, "/") {
+		u.Path += "/"
+	}
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if b.cfg.Username != "" || b.cfg.Password != "" {
+		req.SetBasicAuth(b.cfg.Username, b.cfg.Password)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav %s %s failed: %v", method, name, err)
+	}
+	return resp, nil
+}
+
+// notExist reports a missing file so callers can match fs.ErrNotExist
+func notExist(name string) error {
+	return fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+}
+
+// statusError describes an unexpected response from a storage server
+func statusError(kind, method, name string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	msg := fmt.Sprintf("%s %s %s failed: %s", kind, method, name, resp.Status)
+	if detail := strings.TrimSpace(string(body)); detail != "" {
+		msg += ": " + detail
+	}
+	return fmt.Errorf("%s", msg)
+}
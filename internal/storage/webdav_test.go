@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebDAVBackend(t *testing.T) {
+	var mu sync.Mutex
+	files := make(map[string]string)
+	collections := map[string]bool{"/dav": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, data)
+		case http.MethodPut:
+			if !collections[path.Dir(r.URL.Path)] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		case "MKCOL":
+			dir := strings.TrimSuffix(r.URL.Path, "/")
+			if collections[dir] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			collections[dir] = true
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			delete(files, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	backend, err := NewWebDAVBackend(config.WebDAVConfig{URL: server.URL + "/dav/", Username: "me", Password: "secret"}, server.Client())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = backend.Get(ctx, "summaries/two_sum.json")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// The summaries collection is created on first upload
+	require.NoError(t, backend.Put(ctx, "summaries/two_sum.json", []byte("{}")))
+	assert.True(t, collections["/dav/summaries"])
+	require.NoError(t, backend.Put(ctx, "summaries/3sum.json", []byte("[]")))
+	data, err := backend.Get(ctx, "summaries/two_sum.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	require.NoError(t, backend.Delete(ctx, "summaries/two_sum.json"))
+	assert.NotContains(t, files, "/dav/summaries/two_sum.json")
+
+	_, err = NewWebDAVBackend(config.WebDAVConfig{URL: "not a url"}, nil)
+	assert.Error(t, err)
+}
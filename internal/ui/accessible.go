@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/solutions"
+	"github.com/lancekrogers/algo-scales/internal/ui/diffview"
+	"github.com/lancekrogers/algo-scales/internal/ui/heatmap"
+)
+
+// Accessible mode draws the session, daily and stats screens as one field
+// per line, in reading order, so a screen reader can follow them. Colors
+// that carry meaning, such as the timer's, are spelled out instead.
+
+// viewSessionAccessible renders the session screen as plain fields
+func (m Model) viewSessionAccessible() string {
+	var b strings.Builder
+	p := m.session.problem
+
+	b.WriteString(access.Field("Screen", "Session") + "\n")
+	b.WriteString(access.Field("Problem", p.Title) + "\n")
+	if p.Difficulty != "" {
+		b.WriteString(access.Field("Difficulty", p.Difficulty) + "\n")
+	}
+	if m.session.bookmarked {
+		b.WriteString(access.Field("Bookmarked", "yes") + "\n")
+	}
+	b.WriteString(access.Field("Time", timerDescription(m.session.elapsed(),
+		m.session.clock != nil && m.session.clock.Paused())) + "\n")
+	if m.session.cram != nil {
+		b.WriteString(access.Field("Cram", fmt.Sprintf("%s, %s left for this problem",
+			m.cramStatus(), formatDuration(m.session.remaining()))) + "\n")
+	}
+
+	switch {
+	case m.session.confirmQuit:
+		b.WriteString(access.Field("Confirm", "Really quit? Press q or ctrl+c again to confirm, any other key to cancel.") + "\n")
+	case m.session.addTest != nil:
+		b.WriteString(access.Field("Prompt", access.Plain(m.userTestPrompt())) + "\n")
+	case m.session.message != "":
+		b.WriteString(access.Field("Message", m.session.message) + "\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.session.editor != nil:
+		b.WriteString(access.Field("Editor", access.Plain(m.session.editor.View())))
+	case m.session.showBigO:
+		b.WriteString(access.Field("Big-O reference", access.Plain(complexity.Render())))
+	default:
+		b.WriteString(access.Plain(m.session.viewport.View()))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(access.Field("Keys", strings.Join(m.sessionActions(), "; ")))
+	return b.String()
+}
+
+// sessionContentAccessible is the session viewport's content as plain fields
+func (m Model) sessionContentAccessible() string {
+	var fields []string
+	p := m.session.problem
+
+	description := p.Description
+	if description == "" {
+		description = "No description available"
+	}
+	fields = append(fields, access.Field("Description", description))
+	for i, example := range p.Examples {
+		fields = append(fields,
+			access.Field(fmt.Sprintf("Example %d input", i+1), example.Input),
+			access.Field(fmt.Sprintf("Example %d output", i+1), example.Output))
+		if example.Explanation != "" {
+			fields = append(fields, access.Field(fmt.Sprintf("Example %d explanation", i+1), example.Explanation))
+		}
+	}
+	if m.session.notes != "" {
+		fields = append(fields, access.Field("Your notes", strings.TrimSpace(m.session.notes)))
+	}
+	if m.session.showClarify {
+		fields = append(fields, access.Field("Clarifying questions", access.Plain(m.clarifyContent())))
+	}
+	if m.session.testResults != "" {
+		fields = append(fields, access.Field("Test results", access.Plain(m.session.testResults)))
+	}
+	if fb := m.session.complexity; fb != nil {
+		fields = append(fields, access.Field("Complexity", access.Plain(complexityContent(*fb))))
+	}
+	if m.session.showHint && p.PatternExplanation != "" {
+		fields = append(fields, access.Field("Pattern explanation", p.PatternExplanation))
+	}
+	if m.session.showSolution && len(p.SolutionWalkthrough) > 0 {
+		var steps []string
+		for i, step := range p.SolutionWalkthrough {
+			steps = append(steps, fmt.Sprintf("Step %d: %s", i+1, step))
+		}
+		fields = append(fields, access.Field("Solution walkthrough", strings.Join(steps, "\n")))
+	}
+	if m.session.showSolution && m.session.showDiff {
+		if code, reference, err := m.referenceDiff(); err != nil {
+			fields = append(fields, access.Field("Diff with reference", fmt.Sprintf("Cannot compare: %v", err)))
+		} else {
+			diff := solutions.Format(solutions.Diff(code, reference))
+			fields = append(fields, access.Field("Diff with reference", diffview.Summary(code, reference)+"\n"+strings.TrimRight(diff, "\n")))
+		}
+	}
+	return strings.Join(fields, "\n")
+}
+
+// timerDescription spells out what the timer's color shows
+func timerDescription(elapsed time.Duration, paused bool) string {
+	desc := formatDuration(elapsed)
+	switch {
+	case elapsed > 30*time.Minute:
+		desc += ", over 30 minutes"
+	case elapsed > 20*time.Minute:
+		desc += ", over 20 minutes"
+	}
+	if paused {
+		desc += ", paused"
+	}
+	return desc
+}
+
+// viewDailyAccessible renders the daily scale screen as plain fields
+func (m Model) viewDailyAccessible() string {
+	var b strings.Builder
+	b.WriteString(access.Field("Screen", "Daily Scales") + "\n")
+	if m.daily.loading {
+		b.WriteString(access.Field("Loading", "daily scale"))
+		return b.String()
+	}
+
+	scale := getScaleInfo(m.daily.currentScale)
+	b.WriteString(access.Field("Scale", scale.MusicalName) + "\n")
+	b.WriteString(access.Field("Pattern", scale.Pattern) + "\n")
+	b.WriteString(access.Field("Description", scale.Description) + "\n")
+	if p, ok := m.daily.progress.(daily.ScaleProgress); ok {
+		b.WriteString(access.Field("Streak", fmt.Sprintf("%d days", p.Streak)) + "\n")
+		if limit := daily.MaxFreezes(); limit > 0 {
+			b.WriteString(access.Field("Streak freezes", fmt.Sprintf("%d of %d", min(p.Freezes, limit), limit)) + "\n")
+		}
+		b.WriteString(access.Field("Completed today", fmt.Sprintf("%d of 12 scales", len(p.Completed))) + "\n")
+		if !p.LastPracticed.IsZero() {
+			b.WriteString(access.Field("Last practice", p.LastPracticed.Format("Jan 2, 3:04 PM")) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(access.Field("Keys", strings.Join(dailyActions, "; ")))
+	return b.String()
+}
+
+// viewStatsAccessible renders the stats screen as plain fields
+func (m Model) viewStatsAccessible() string {
+	var b strings.Builder
+	b.WriteString(access.Field("Screen", "Statistics") + "\n\n")
+	if m.stats.loading {
+		b.WriteString(access.Field("Loading", "statistics"))
+		return b.String()
+	}
+	b.WriteString(m.stats.viewport.View())
+	b.WriteString("\n\n")
+	b.WriteString(access.Field("Keys", strings.Join(statsActions, "; ")))
+	return b.String()
+}
+
+// statsContentAccessible is the stats viewport's content as plain fields
+func (m Model) statsContentAccessible() string {
+	s := m.stats.summary
+	fields := []string{
+		access.Field("Problems attempted", fmt.Sprint(s.TotalAttempted)),
+		access.Field("Problems solved", fmt.Sprint(s.TotalSolved)),
+		access.Field("Success rate", fmt.Sprintf("%.1f%%", s.SuccessRate*100)),
+		access.Field("Average solve time", s.AvgSolveTime),
+	}
+	if s.FastestSolve.ProblemID != "" {
+		fields = append(fields, access.Field("Fastest solve",
+			fmt.Sprintf("%s in %s", s.FastestSolve.ProblemID, s.FastestSolve.Time)))
+	}
+	if s.MostChallenging.ProblemID != "" {
+		fields = append(fields, access.Field("Most challenging",
+			fmt.Sprintf("%s, %d attempts", s.MostChallenging.ProblemID, s.MostChallenging.Attempts)))
+	}
+	for _, p := range m.stats.goals {
+		progress := fmt.Sprintf("%s, %d of %d", p.Description, p.Current, p.Target)
+		if p.Done {
+			progress += ", reached"
+		}
+		fields = append(fields, access.Field("Goal", progress))
+	}
+	if len(m.stats.calendar.Days) > 0 {
+		fields = append(fields, access.Field("Practice calendar", heatmap.Summary(m.stats.calendar)))
+	}
+	for _, line := range daily.DescribePlan(m.stats.rotation) {
+		fields = append(fields, access.Field("Weekly rotation", line))
+	}
+	return strings.Join(fields, "\n")
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/ui/view"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessibleModel returns a ready model in accessible mode, on a screen
+func accessibleModel(t *testing.T, state State) Model {
+	t.Helper()
+	access.SetEnabled(true)
+	t.Cleanup(func() { access.SetEnabled(false) })
+
+	model := New()
+	model.ready = true
+	model.state = state
+	next, _ := model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m := next.(Model)
+	m.session.viewport = view.NewViewport(96, 30)
+	m.stats.viewport = view.NewViewport(96, 30)
+	return m
+}
+
+// assertPlain checks a screen has no colors or box drawing
+func assertPlain(t *testing.T, view string) {
+	t.Helper()
+	assert.NotContains(t, view, "\x1b[")
+	assert.NotContains(t, view, "│")
+	assert.NotContains(t, view, "╭")
+}
+
+func TestAccessibleSession(t *testing.T) {
+	m := accessibleModel(t, StateSession)
+	next, _ := m.Update(sessionStartedMsg{
+		sessionID: "accessible",
+		problem: problem.Problem{
+			Title:       "Two Sum",
+			Difficulty:  "easy",
+			Description: "Find two numbers that add up to target.",
+			Examples:    []problem.Example{{Input: "[2,7], 9", Output: "[0,1]"}},
+		},
+		clock: clock.NewStopwatch(),
+	})
+	m = next.(Model)
+	m.session.viewport.SetContent(m.sessionContent())
+
+	view := m.View()
+	assertPlain(t, view)
+	assert.Contains(t, view, "Screen: Session\nProblem: Two Sum\nDifficulty: easy\nTime: 00:00:00")
+	assert.Contains(t, view, "Description: Find two numbers that add up to target.")
+	assert.Contains(t, view, "Example 1 input: [2,7], 9\nExample 1 output: [0,1]")
+	assert.Contains(t, view, "Keys: e: Edit Code; E: $EDITOR; t: Run Tests")
+}
+
+func TestTimerDescription(t *testing.T) {
+	assert.Equal(t, "00:05:00", timerDescription(5*time.Minute, false))
+	assert.Equal(t, "00:25:00, over 20 minutes, paused", timerDescription(25*time.Minute, true))
+	assert.Equal(t, "00:31:00, over 30 minutes", timerDescription(31*time.Minute, false))
+}
+
+func TestAccessibleDaily(t *testing.T) {
+	original := daily.MaxFreezes
+	t.Cleanup(func() { daily.MaxFreezes = original })
+	daily.MaxFreezes = func() int { return 2 }
+
+	m := accessibleModel(t, StateDaily)
+	next, _ := m.Update(dailyScaleLoadedMsg{
+		scale:    "two-pointers",
+		progress: daily.ScaleProgress{Streak: 3, Completed: []string{"sliding-window"}, Freezes: 1},
+	})
+
+	view := next.(Model).View()
+	assertPlain(t, view)
+	assert.Equal(t, "Screen: Daily Scales\n"+
+		"Scale: G Major\n"+
+		"Pattern: Two Pointers\n"+
+		"Description: Balanced and efficient, the workhorse of array manipulation\n"+
+		"Streak: 3 days\n"+
+		"Streak freezes: 1 of 2\n"+
+		"Completed today: 1 of 12 scales\n\n"+
+		"Keys: Enter: Practice this scale; n: Next scale; r: Reset progress; Esc: Back", view)
+}
+
+func TestAccessibleStats(t *testing.T) {
+	m := accessibleModel(t, StateStats)
+	summary := stats.Summary{TotalAttempted: 10, TotalSolved: 8, SuccessRate: 0.8, AvgSolveTime: "15m"}
+	summary.FastestSolve.ProblemID, summary.FastestSolve.Time = "two_sum", "4m"
+	calendar := stats.Calendar{Days: []stats.CalendarDay{{Date: "2026-03-04", Attempted: 2}}, Total: 2, ActiveDays: 1}
+	calendar.Busiest = calendar.Days[0]
+	progress := []goals.Progress{{Goal: goals.Goal{Target: 3}, Description: "Keep a 3-day practice streak", Current: 3, Done: true}}
+	next, _ := m.Update(statsLoadedMsg{stats: summary, calendar: calendar, goals: progress})
+
+	view := next.(Model).View()
+	assertPlain(t, view)
+	assert.Contains(t, view, "Screen: Statistics")
+	assert.Contains(t, view, "Problems attempted: 10\nProblems solved: 8\nSuccess rate: 80.0%\nAverage solve time: 15m\nFastest solve: two_sum in 4m\n"+
+		"Goal: Keep a 3-day practice streak, 3 of 3, reached\n"+
+		"Practice calendar: 2 problems practiced on 1 day in the past year, most on Mar 4 (2)")
+	assert.Contains(t, view, "Keys: r: Refresh; Esc: Back")
+}
+
+func TestAccessibleOtherScreens(t *testing.T) {
+	m := accessibleModel(t, StateHome)
+	view := m.View()
+	assertPlain(t, view)
+	require.NotEmpty(t, view)
+	assert.NotContains(t, view, "⠋")
+}
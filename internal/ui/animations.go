@@ -160,7 +160,7 @@ func (a Animation) applyPulse(content string) string {
 	
 	// Apply a style with varying intensity
 	pulseStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(primaryColor)).
+		Foreground(primaryColor).
 		Bold(intensity > 0.7)
 	
 	return pulseStyle.Render(content)
@@ -258,8 +258,8 @@ func (s SelectionHighlight) Apply(content string) string {
 	intensity := 0.7 + 0.3*math.Sin(float64(s.frame)*0.1)
 	
 	highlightStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color(primaryColor)).
-		Foreground(lipgloss.Color("255")).
+		Background(primaryColor).
+		Foreground(onColor).
 		Bold(true)
 	
 	if intensity < 0.85 {
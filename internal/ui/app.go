@@ -5,6 +5,7 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
 )
 
 // StartTUI starts the terminal user interface
@@ -16,6 +17,7 @@ func StartTUI() error {
 	}
 
 	// Create the model
+	uiInitDone := profiling.Track("UI init")
 	model := NewModel()
 
 	// Setup program options
@@ -31,6 +33,7 @@ func StartTUI() error {
 
 	// Create and run the program
 	p := tea.NewProgram(model, opts...)
+	uiInitDone()
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running program: %w", err)
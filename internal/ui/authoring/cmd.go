@@ -0,0 +1,26 @@
+package authoring
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runProgram runs a Bubble Tea program
+// Exported as variable for testing
+var runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	return tea.NewProgram(m, opts...).Run()
+}
+
+// Run opens the authoring screen for the problem file at path, which is
+// created on first save if it does not exist
+func Run(path string) error {
+	p, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if _, err := runProgram(NewModel(path, p), tea.WithAltScreen()); err != nil {
+		return fmt.Errorf("error running authoring UI: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Package authoring implements an interactive screen for writing problems,
+// with a live preview of the statement and generated test harnesses
+package authoring
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+)
+
+// Field is one editable field of a problem. Key is the field's JSON name,
+// which validation errors refer to.
+type Field struct {
+	Key       string
+	Label     string
+	Help      string
+	Multiline bool
+	PerLang   bool // Edited separately for each language
+}
+
+// Fields are the editable fields, in form order
+var Fields = []Field{
+	{Key: "id", Label: "ID", Help: "lowercase letters, digits, '-' and '_'"},
+	{Key: "title", Label: "Title"},
+	{Key: "difficulty", Label: "Difficulty", Help: "easy, medium or hard"},
+	{Key: "patterns", Label: "Patterns", Help: "comma-separated, e.g. two-pointers, hash-map"},
+	{Key: "estimated_time", Label: "Estimated time", Help: "minutes"},
+	{Key: "companies", Label: "Companies", Help: "comma-separated"},
+	{Key: "signature", Label: "Signature", Help: "e.g. twoSum(nums int[], target int) int[], add 'unordered' for any-order results"},
+	{Key: "description", Label: "Description", Multiline: true},
+	{Key: "examples", Label: "Examples", Help: "one per line as input => output; indent a line below for an explanation", Multiline: true},
+	{Key: "constraints", Label: "Constraints", Help: "one per line", Multiline: true},
+	{Key: "pattern_explanation", Label: "Pattern explanation", Multiline: true},
+	{Key: "solution_walkthrough", Label: "Solution walkthrough", Help: "one step per line", Multiline: true},
+	{Key: "test_cases", Label: "Test cases", Help: "one per line as input => expected; start a line with hidden:, edge: or stress: for tests only submissions run", Multiline: true},
+	{Key: "starter_code", Label: "Starter code", Multiline: true, PerLang: true},
+	{Key: "solutions", Label: "Reference solution", Multiline: true, PerLang: true},
+}
+
+// arrow separates inputs from outputs in examples and test cases
+const arrow = " => "
+
+// Draft is a problem in the text form it is edited in
+type Draft struct {
+	Values map[string]string            // Field key to text, for shared fields
+	Code   map[string]map[string]string // Field key to language to code, for per-language fields
+
+	// Generator is the problem's input generator, which is not edited here
+	// but kept when the problem is saved
+	Generator *problem.InputGenerator
+}
+
+// NewDraft converts a problem into a draft
+func NewDraft(p problem.Problem) Draft {
+	d := Draft{
+		Values: map[string]string{
+			"id":                   p.ID,
+			"title":                p.Title,
+			"difficulty":           p.Difficulty,
+			"patterns":             strings.Join(p.Patterns, ", "),
+			"companies":            strings.Join(p.Companies, ", "),
+			"signature":            problem.FormatSignature(p.Signature),
+			"description":          p.Description,
+			"examples":             formatExamples(p.Examples),
+			"constraints":          strings.Join(p.Constraints, "\n"),
+			"pattern_explanation":  p.PatternExplanation,
+			"solution_walkthrough": strings.Join(p.SolutionWalkthrough, "\n"),
+			"test_cases":           formatTestCases(p.TestCases),
+		},
+		Code: map[string]map[string]string{
+			"starter_code": copyMap(p.StarterCode),
+			"solutions":    copyMap(p.Solutions),
+		},
+		Generator: p.Generator,
+	}
+	if p.EstimatedTime > 0 {
+		d.Values["estimated_time"] = strconv.Itoa(p.EstimatedTime)
+	}
+	return d
+}
+
+// Problem converts the draft back into a problem. Text that cannot be
+// parsed is reported per field key and left out of the problem.
+func (d Draft) Problem() (problem.Problem, map[string][]string) {
+	errs := make(map[string][]string)
+	p := problem.Problem{
+		ID:                  strings.TrimSpace(d.Values["id"]),
+		Title:               strings.TrimSpace(d.Values["title"]),
+		Difficulty:          strings.TrimSpace(d.Values["difficulty"]),
+		Patterns:            splitList(d.Values["patterns"]),
+		Companies:           splitList(d.Values["companies"]),
+		Description:         strings.TrimSpace(d.Values["description"]),
+		Constraints:         splitLines(d.Values["constraints"]),
+		PatternExplanation:  strings.TrimSpace(d.Values["pattern_explanation"]),
+		SolutionWalkthrough: splitLines(d.Values["solution_walkthrough"]),
+		StarterCode:         nonEmpty(d.Code["starter_code"]),
+		Solutions:           nonEmpty(d.Code["solutions"]),
+		Generator:           d.Generator,
+	}
+
+	if text := strings.TrimSpace(d.Values["estimated_time"]); text != "" {
+		minutes, err := strconv.Atoi(text)
+		if err != nil {
+			errs["estimated_time"] = append(errs["estimated_time"], fmt.Sprintf("%q is not a number of minutes", text))
+		}
+		p.EstimatedTime = minutes
+	}
+
+	if text := strings.TrimSpace(d.Values["signature"]); text != "" {
+		sig, err := problem.ParseSignature(text)
+		if err != nil {
+			errs["signature"] = append(errs["signature"], err.Error())
+		}
+		p.Signature = sig
+	}
+
+	examples, exampleErrs := parseExamples(d.Values["examples"])
+	p.Examples = examples
+	errs["examples"] = append(errs["examples"], exampleErrs...)
+
+	tests, testErrs := parseTestCases(d.Values["test_cases"])
+	p.TestCases = tests
+	errs["test_cases"] = append(errs["test_cases"], testErrs...)
+
+	for key, list := range errs {
+		if len(list) == 0 {
+			delete(errs, key)
+		}
+	}
+	return p, errs
+}
+
+// Check converts the draft and validates the result as a pack import would,
+// adding whether the harnesses can call solutions. Issues are keyed by field.
+func (d Draft) Check() (problem.Problem, map[string][]string) {
+	p, issues := d.Problem()
+	for _, fieldErr := range problem.Validate(p) {
+		issues[fieldErr.Field] = append(issues[fieldErr.Field], fieldErr.Message)
+	}
+	if p.Signature != nil && len(issues["signature"]) == 0 {
+		if err := execution.ValidateSignature(p.Signature); err != nil {
+			issues["signature"] = append(issues["signature"], err.Error())
+		}
+	}
+	return p, issues
+}
+
+// Languages returns the languages harnesses can be generated for, sorted.
+// Pseudo-code is graded rather than run, so it has no harness.
+func Languages() []string {
+	var languages []string
+	for _, language := range execution.DefaultRegistry.GetSupportedLanguages() {
+		if language != execution.PseudoLanguage {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// formatExamples writes examples one per line, with explanations indented
+// on the line below
+func formatExamples(examples []problem.Example) string {
+	var lines []string
+	for _, ex := range examples {
+		lines = append(lines, ex.Input+arrow+ex.Output)
+		if ex.Explanation != "" {
+			lines = append(lines, "  "+ex.Explanation)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseExamples reads examples written by formatExamples
+func parseExamples(text string) ([]problem.Example, []string) {
+	var examples []problem.Example
+	var errs []string
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(examples) == 0 {
+				errs = append(errs, fmt.Sprintf("line %d: explanation before any example", i+1))
+				continue
+			}
+			last := &examples[len(examples)-1]
+			last.Explanation = strings.TrimSpace(last.Explanation + " " + strings.TrimSpace(line))
+			continue
+		}
+		input, output, ok := strings.Cut(line, arrow)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("line %d: expected input => output", i+1))
+			continue
+		}
+		examples = append(examples, problem.Example{Input: strings.TrimSpace(input), Output: strings.TrimSpace(output)})
+	}
+	return examples, errs
+}
+
+// formatTestCases writes test cases one per line, prefixing those outside
+// the example tier with their tier
+func formatTestCases(tests []problem.TestCase) string {
+	lines := make([]string, len(tests))
+	for i, tc := range tests {
+		lines[i] = strings.ReplaceAll(tc.Input, "\n", " ") + arrow + strings.ReplaceAll(tc.Expected, "\n", " ")
+		if tc.Tier != "" && tc.Tier != interfaces.TierExample {
+			lines[i] = string(tc.Tier) + ": " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseTestCases reads test cases written by formatTestCases
+func parseTestCases(text string) ([]problem.TestCase, []string) {
+	var tests []problem.TestCase
+	var errs []string
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		input, expected, ok := strings.Cut(line, arrow)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("line %d: expected input => expected output", i+1))
+			continue
+		}
+		tier, input := cutTier(input)
+		tests = append(tests, problem.TestCase{Input: strings.TrimSpace(input), Expected: strings.TrimSpace(expected), Tier: tier})
+	}
+	return tests, errs
+}
+
+// cutTier splits a tier prefix such as "hidden:" off a test case's input.
+// Inputs never start with a tier name, so anything else is left as input.
+func cutTier(input string) (interfaces.TestTier, string) {
+	prefix, rest, ok := strings.Cut(input, ":")
+	if !ok {
+		return "", input
+	}
+	tier := interfaces.TestTier(strings.TrimSpace(prefix))
+	for _, known := range interfaces.Tiers {
+		if tier == known && known != interfaces.TierExample {
+			return tier, rest
+		}
+	}
+	return "", input
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitLines splits text into its non-empty lines
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// copyMap copies a map so the draft can be edited independently
+func copyMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// nonEmpty drops languages without code, returning nil if none are left
+func nonEmpty(m map[string]string) map[string]string {
+	var out map[string]string
+	for k, v := range m {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package authoring
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var twoSum = problem.Problem{
+	ID:            "two_sum",
+	Title:         "Two Sum",
+	Difficulty:    "easy",
+	Patterns:      []string{"hash-map"},
+	EstimatedTime: 15,
+	Description:   "Return the indices of the two numbers that add up to target.",
+	Examples: []problem.Example{
+		{Input: "nums = [2,7,11,15], target = 9", Output: "[0,1]", Explanation: "nums[0] + nums[1] == 9"},
+		{Input: "nums = [3,3], target = 6", Output: "[0,1]"},
+	},
+	Constraints: []string{"2 <= nums.length <= 10^4"},
+	TestCases: []problem.TestCase{
+		{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		{Input: "[3,3], 6", Expected: "[0,1]", Tier: interfaces.TierEdge},
+	},
+	Solutions: map[string]string{"go": "func twoSum(nums []int, target int) []int { return nil }"},
+	Signature: &interfaces.FunctionSignature{
+		Name:      "twoSum",
+		Params:    []interfaces.Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+		Returns:   "int[]",
+		Unordered: true,
+	},
+	Generator: &problem.InputGenerator{Script: "print([1] * 100000, 2)"},
+}
+
+func TestDraftRoundTrip(t *testing.T) {
+	d := NewDraft(twoSum)
+	assert.Equal(t, "twoSum(nums int[], target int) int[] unordered", d.Values["signature"])
+	assert.Equal(t, "nums = [2,7,11,15], target = 9 => [0,1]\n  nums[0] + nums[1] == 9\nnums = [3,3], target = 6 => [0,1]", d.Values["examples"])
+	assert.Equal(t, "[2,7,11,15], 9 => [0,1]\nedge: [3,3], 6 => [0,1]", d.Values["test_cases"])
+
+	p, issues := d.Check()
+	assert.Empty(t, issues)
+	assert.Equal(t, twoSum, p)
+}
+
+func TestDraftIssues(t *testing.T) {
+	d := NewDraft(problem.Problem{})
+	d.Values["id"] = "Two Sum"
+	d.Values["difficulty"] = "tricky"
+	d.Values["estimated_time"] = "soon"
+	d.Values["signature"] = "twoSum(nums int[], target) int"
+	d.Values["test_cases"] = "[1,2], 3 => 0\nno arrow here"
+
+	_, issues := d.Check()
+	assert.Contains(t, issues["id"][0], "may only contain")
+	assert.Contains(t, issues["difficulty"][0], "must be easy, medium or hard")
+	assert.Equal(t, []string{`"soon" is not a number of minutes`}, issues["estimated_time"])
+	assert.Equal(t, []string{`parameter "target" must be a name and a type`}, issues["signature"])
+	assert.Equal(t, []string{"line 2: expected input => expected output"}, issues["test_cases"])
+	assert.Contains(t, issues, "title")
+	assert.Contains(t, issues, "patterns")
+
+	// Signatures the harnesses cannot call are flagged too
+	d.Values["signature"] = "twoSum(nums map) int"
+	_, issues = d.Check()
+	assert.Equal(t, []string{"parameter nums has unsupported type map"}, issues["signature"])
+}
+
+func TestLoadAndSave(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := Load(filepath.Join(dir, "max_window.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "max_window", p.ID, "new files take their ID from the name")
+
+	for _, name := range []string{"two_sum.json", "two_sum.yaml"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, Save(path, twoSum))
+		loaded, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, twoSum, loaded, name)
+
+		// Saved files import as a pack
+		pack, err := problem.LoadPack(path)
+		require.NoError(t, err, name)
+		assert.Len(t, pack.Problems, 1)
+	}
+}
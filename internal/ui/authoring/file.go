@@ -0,0 +1,96 @@
+// Reading and writing the problem file being authored
+
+package authoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"gopkg.in/yaml.v3"
+)
+
+// isYAML reports whether a problem file is written as YAML rather than JSON
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Load reads the problem in a JSON or YAML file. Unlike a pack import it
+// does not validate, so half-written problems can be opened again. A missing
+// file gives a new problem whose ID is taken from the file name.
+func Load(path string) (problem.Problem, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		return problem.Problem{ID: id, Difficulty: "easy"}, nil
+	}
+	if err != nil {
+		return problem.Problem{}, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	// Problem only has JSON tags, so YAML goes through JSON
+	if isYAML(path) {
+		var generic any
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return problem.Problem{}, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		if data, err = json.Marshal(generic); err != nil {
+			return problem.Problem{}, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+	}
+
+	var p problem.Problem
+	if err := json.Unmarshal(data, &p); err != nil {
+		return problem.Problem{}, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// Save writes a problem as JSON, or as YAML if the file name says so
+func Save(path string, p problem.Problem) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(p); err != nil {
+		return fmt.Errorf("failed to encode problem: %v", err)
+	}
+	data := buf.Bytes()
+
+	if isYAML(path) {
+		var generic map[string]any
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("failed to encode problem: %v", err)
+		}
+		// Leave out unset fields rather than writing nulls
+		for k, v := range generic {
+			if v == nil {
+				delete(generic, k)
+			}
+		}
+		var out bytes.Buffer
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return fmt.Errorf("failed to encode problem: %v", err)
+		}
+		data = out.Bytes()
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
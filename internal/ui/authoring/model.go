@@ -0,0 +1,549 @@
+package authoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// testTimeout bounds a run of the reference solution
+const testTimeout = 30 * time.Second
+
+// collapsedLines is how many lines of an unfocused multi-line field show
+const collapsedLines = 3
+
+// previewTab is what the preview pane shows
+type previewTab int
+
+const (
+	statementTab previewTab = iota // Rendered problem statement
+	harnessTab                     // Generated test harness for the language
+	testsTab                       // Results of the last reference solution run
+	tabCount
+)
+
+// runTests runs code against a problem's tests
+// Exported as variable for testing
+var runTests = execution.ExecuteTests
+
+// generateHarness returns the test program a language's runner would build
+// Exported as variable for testing
+var generateHarness = func(prob *interfaces.Problem, code, language string) (string, error) {
+	runner, err := execution.DefaultRegistry.GetRunner(language)
+	if err != nil {
+		return "", err
+	}
+	return runner.GenerateTestCode(prob, code)
+}
+
+// testRunMsg carries the results of a reference solution run
+type testRunMsg struct {
+	language string
+	results  []interfaces.TestResult
+	passed   bool
+	err      error
+}
+
+// Styles used by the authoring screen
+var (
+	titleStyle   lipgloss.Style
+	labelStyle   = lipgloss.NewStyle().Bold(true)
+	focusStyle   lipgloss.Style
+	helpStyle    = lipgloss.NewStyle().Faint(true)
+	issueStyle   lipgloss.Style
+	okStyle      lipgloss.Style
+	tabStyle     = lipgloss.NewStyle().Padding(0, 1)
+	activeTab    = tabStyle.Copy().Bold(true).Reverse(true)
+	previewStyle lipgloss.Style
+)
+
+func init() {
+	theme.OnChange(func(t theme.Theme) {
+		titleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Primary)
+		focusStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+		issueStyle = lipgloss.NewStyle().Foreground(t.Error)
+		okStyle = lipgloss.NewStyle().Foreground(t.Success)
+		previewStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Border)
+	})
+}
+
+// Model is the authoring screen: a form on the left and a preview on the right
+type Model struct {
+	path      string
+	draft     Draft
+	languages []string
+	language  string
+
+	focus  int
+	inputs []textinput.Model // Single-line fields, by field index
+	areas  []textarea.Model  // Multi-line fields, by field index
+
+	problem problem.Problem
+	issues  map[string][]string
+
+	preview    viewport.Model
+	tab        previewTab
+	testOutput string
+	testing    bool
+
+	status      string
+	dirty       bool
+	confirmQuit bool
+	width       int
+	height      int
+}
+
+// NewModel creates an authoring screen for a problem saved to path
+func NewModel(path string, p problem.Problem) Model {
+	m := Model{
+		path:      path,
+		draft:     NewDraft(p),
+		languages: Languages(),
+		language:  "go",
+		inputs:    make([]textinput.Model, len(Fields)),
+		areas:     make([]textarea.Model, len(Fields)),
+		preview:   viewport.New(60, 20),
+		status:    "Editing " + path,
+	}
+	// Start on a language the problem has a solution in
+	if p.Solutions[m.language] == "" {
+		for _, lang := range m.languages {
+			if p.Solutions[lang] != "" {
+				m.language = lang
+				break
+			}
+		}
+	}
+
+	for i, f := range Fields {
+		if f.Multiline {
+			area := textarea.New()
+			area.ShowLineNumbers = f.PerLang
+			area.CharLimit = 0
+			area.SetHeight(10)
+			area.SetValue(m.value(f))
+			m.areas[i] = area
+		} else {
+			input := textinput.New()
+			input.Prompt = ""
+			input.SetValue(m.value(f))
+			m.inputs[i] = input
+		}
+	}
+	m.focusField(0)
+	m.refresh()
+	return m
+}
+
+// Init implements tea.Model
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case testRunMsg:
+		m.testing = false
+		m.testOutput = formatTestRun(msg)
+		m.tab = testsTab
+		m.status = "Test run finished"
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() != "esc" && msg.String() != "ctrl+c" {
+			m.confirmQuit = false
+		}
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			if m.dirty && !m.confirmQuit {
+				m.confirmQuit = true
+				m.status = "Unsaved changes: press esc again to quit without saving, or ctrl+s to save"
+				return m, nil
+			}
+			return m, tea.Quit
+		case "tab":
+			m.focusField((m.focus + 1) % len(Fields))
+			return m, nil
+		case "shift+tab":
+			m.focusField((m.focus + len(Fields) - 1) % len(Fields))
+			return m, nil
+		case "enter":
+			if !Fields[m.focus].Multiline {
+				m.focusField((m.focus + 1) % len(Fields))
+				return m, nil
+			}
+		case "ctrl+s":
+			m.save()
+			return m, nil
+		case "ctrl+t":
+			return m, m.runTests()
+		case "ctrl+p":
+			m.tab = (m.tab + 1) % tabCount
+			m.refresh()
+			return m, nil
+		case "ctrl+l":
+			m.switchLanguage()
+			return m, nil
+		case "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+		}
+	}
+
+	// Everything else edits the focused field
+	var cmd tea.Cmd
+	f := Fields[m.focus]
+	before := m.value(f)
+	if f.Multiline {
+		m.areas[m.focus], cmd = m.areas[m.focus].Update(msg)
+		m.setValue(f, m.areas[m.focus].Value())
+	} else {
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+		m.setValue(f, m.inputs[m.focus].Value())
+	}
+	if m.value(f) != before {
+		m.dirty = true
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m Model) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	formWidth := m.width / 2
+	header := titleStyle.Render("Problem Authoring") + helpStyle.Render("  "+m.path+"  language: "+m.language)
+
+	bodyHeight := m.height - 4
+	form := lipgloss.NewStyle().Width(formWidth).Render(m.formView(formWidth-2, bodyHeight))
+	preview := lipgloss.JoinVertical(lipgloss.Left, m.tabsView(), previewStyle.Render(m.preview.View()))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, preview)
+
+	status := m.status
+	if n := m.issueCount(); n > 0 {
+		status += issueStyle.Render(fmt.Sprintf("  %s %d issue(s)", symbols.Warning, n))
+	} else {
+		status += okStyle.Render(fmt.Sprintf("  %s valid", symbols.Check))
+	}
+	keys := helpStyle.Render("tab/shift+tab field · ctrl+s save · ctrl+t test solution · ctrl+p preview · ctrl+l language · pgup/pgdn scroll · esc quit")
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, status, keys)
+}
+
+// formView renders the fields with their inline issues, scrolled so the
+// focused field is in view
+func (m Model) formView(width, height int) string {
+	var lines []string
+	focusLine := 0
+	for i, f := range Fields {
+		label := f.Label
+		if f.PerLang {
+			label += " (" + m.language + ")"
+		}
+		if i == m.focus {
+			focusLine = len(lines)
+			lines = append(lines, focusStyle.Render("▸ "+label))
+			if f.Help != "" {
+				lines = append(lines, helpStyle.Render("  "+f.Help))
+			}
+			if f.Multiline {
+				lines = append(lines, strings.Split(m.areas[i].View(), "\n")...)
+			} else {
+				lines = append(lines, "  "+m.inputs[i].View())
+			}
+		} else {
+			lines = append(lines, labelStyle.Render("  "+label))
+			lines = append(lines, collapse(m.value(f), width-4)...)
+		}
+		for _, issue := range m.issues[f.Key] {
+			lines = append(lines, issueStyle.Render(fmt.Sprintf("  %s %s", symbols.Cross, issue)))
+		}
+		lines = append(lines, "")
+	}
+
+	start := focusLine - 1
+	if start > len(lines)-height {
+		start = len(lines) - height
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// tabsView renders the preview tab bar
+func (m Model) tabsView() string {
+	names := []string{"Statement", "Harness (" + m.language + ")", "Tests"}
+	rendered := make([]string, len(names))
+	for i, name := range names {
+		if previewTab(i) == m.tab {
+			rendered[i] = activeTab.Render(name)
+		} else {
+			rendered[i] = tabStyle.Render(name)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+// collapse shows the first lines of an unfocused field's value
+func collapse(value string, width int) []string {
+	if strings.TrimSpace(value) == "" {
+		return []string{helpStyle.Render("    (empty)")}
+	}
+	all := strings.Split(value, "\n")
+	shown := all
+	if len(shown) > collapsedLines {
+		shown = shown[:collapsedLines]
+	}
+	lines := make([]string, 0, len(shown)+1)
+	for _, line := range shown {
+		if width > 0 && len(line) > width {
+			line = line[:width-1] + "…"
+		}
+		lines = append(lines, "    "+line)
+	}
+	if len(all) > collapsedLines {
+		lines = append(lines, helpStyle.Render(fmt.Sprintf("    … %d more line(s)", len(all)-collapsedLines)))
+	}
+	return lines
+}
+
+// value returns a field's text, for the current language if per-language
+func (m Model) value(f Field) string {
+	if f.PerLang {
+		return m.draft.Code[f.Key][m.language]
+	}
+	return m.draft.Values[f.Key]
+}
+
+// setValue stores a field's text
+func (m *Model) setValue(f Field, value string) {
+	if f.PerLang {
+		if m.draft.Code[f.Key] == nil {
+			m.draft.Code[f.Key] = make(map[string]string)
+		}
+		m.draft.Code[f.Key][m.language] = value
+		return
+	}
+	m.draft.Values[f.Key] = value
+}
+
+// focusField moves the cursor to field i
+func (m *Model) focusField(i int) {
+	if Fields[m.focus].Multiline {
+		m.areas[m.focus].Blur()
+	} else {
+		m.inputs[m.focus].Blur()
+	}
+	m.focus = i
+	if Fields[i].Multiline {
+		m.areas[i].Focus()
+	} else {
+		m.inputs[i].Focus()
+	}
+}
+
+// switchLanguage moves to the next language, loading its code into the
+// per-language fields
+func (m *Model) switchLanguage() {
+	for i, lang := range m.languages {
+		if lang == m.language {
+			m.language = m.languages[(i+1)%len(m.languages)]
+			break
+		}
+	}
+	for i, f := range Fields {
+		if f.PerLang {
+			m.areas[i].SetValue(m.value(f))
+		}
+	}
+	m.status = "Language: " + m.language
+	m.refresh()
+}
+
+// resize fits the editors and preview to the window
+func (m *Model) resize() {
+	formWidth := m.width / 2
+	for i, f := range Fields {
+		if f.Multiline {
+			m.areas[i].SetWidth(formWidth - 4)
+			m.areas[i].SetHeight(max(5, m.height/3))
+		} else {
+			m.inputs[i].Width = formWidth - 6
+		}
+	}
+	m.preview.Width = m.width - formWidth - 2
+	m.preview.Height = m.height - 7
+	m.refresh()
+}
+
+// refresh re-validates the draft and re-renders the preview
+func (m *Model) refresh() {
+	m.problem, m.issues = m.draft.Check()
+
+	var content string
+	switch m.tab {
+	case statementTab:
+		prob := m.problem
+		s := &session.Session{Problem: &prob, ShowPattern: true, ShowSolution: true}
+		content = s.FormatProblemDescription()
+	case harnessTab:
+		content = m.harness()
+	case testsTab:
+		content = m.testOutput
+		if m.testing {
+			content = "Running the " + m.language + " reference solution..."
+		} else if content == "" {
+			content = "Press ctrl+t to run the " + m.language + " reference solution against the test cases."
+		}
+	}
+	if m.preview.Width > 0 {
+		content = lipgloss.NewStyle().Width(m.preview.Width).Render(content)
+	}
+	m.preview.SetContent(content)
+}
+
+// harness generates the test program for the current language, wrapping the
+// reference solution, or the starter code if there is none yet
+func (m Model) harness() string {
+	code := m.problem.Solutions[m.language]
+	if code == "" {
+		code = m.problem.StarterCode[m.language]
+	}
+	prob := toInterfaceProblem(m.problem)
+	harness, err := generateHarness(&prob, code, m.language)
+	if err != nil {
+		return fmt.Sprintf("%s Cannot generate a %s harness: %v", symbols.Cross, m.language, err)
+	}
+	if note := m.issues["signature"]; len(note) > 0 {
+		harness = fmt.Sprintf("%s Tests cannot call the solution until the signature is fixed\n\n%s", symbols.Warning, harness)
+	}
+	return harness
+}
+
+// save writes the problem file. Problems with issues are saved too, so work
+// is never lost, but the status says what is left to fix.
+func (m *Model) save() {
+	if err := Save(m.path, m.problem); err != nil {
+		m.status = fmt.Sprintf("%s %v", symbols.Cross, err)
+		return
+	}
+	m.dirty = false
+	m.confirmQuit = false
+	m.status = "Saved " + m.path
+	if n := m.issueCount(); n > 0 {
+		m.status += fmt.Sprintf(" (fix %d issue(s) before importing)", n)
+	}
+}
+
+// runTests starts a run of the reference solution for the current language
+func (m *Model) runTests() tea.Cmd {
+	code := m.problem.Solutions[m.language]
+	if strings.TrimSpace(code) == "" {
+		m.status = fmt.Sprintf("No %s reference solution to test", m.language)
+		return nil
+	}
+	if len(m.problem.TestCases) == 0 {
+		m.status = "Add test cases before running the reference solution"
+		return nil
+	}
+	m.testing = true
+	m.tab = testsTab
+	m.status = "Running tests..."
+	m.refresh()
+
+	prob := toInterfaceProblem(m.problem)
+	language := m.language
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		results, passed, err := runTests(ctx, &prob, code, language, testTimeout)
+		return testRunMsg{language: language, results: results, passed: passed, err: err}
+	}
+}
+
+// issueCount counts the issues across all fields
+func (m Model) issueCount() int {
+	n := 0
+	for _, list := range m.issues {
+		n += len(list)
+	}
+	return n
+}
+
+// formatTestRun describes a reference solution run
+func formatTestRun(msg testRunMsg) string {
+	if msg.err != nil {
+		return fmt.Sprintf("%s Could not run the %s reference solution:\n\n%v", symbols.Cross, msg.language, msg.err)
+	}
+	var b strings.Builder
+	passed := 0
+	for i, r := range msg.results {
+		if r.Passed {
+			passed++
+			fmt.Fprintf(&b, "%s Test %d\n", symbols.Check, i+1)
+			continue
+		}
+		fmt.Fprintf(&b, "%s Test %d\n    input:    %s\n    expected: %s\n    got:      %s\n", symbols.Cross, i+1, r.Input, r.Expected, r.Actual)
+		if r.Failure != "" {
+			fmt.Fprintf(&b, "    failure:  %s\n", r.Failure)
+		}
+	}
+	verdict := fmt.Sprintf("%s reference solution passes %d/%d tests", msg.language, passed, len(msg.results))
+	if msg.passed {
+		return okStyle.Render(symbols.Check.String()+" "+verdict) + "\n\n" + b.String()
+	}
+	return issueStyle.Render(symbols.Cross.String()+" "+verdict) + "\n\n" + b.String()
+}
+
+// toInterfaceProblem converts a problem for the test runners
+func toInterfaceProblem(p problem.Problem) interfaces.Problem {
+	testCases := make([]interfaces.TestCase, len(p.TestCases))
+	for i, tc := range p.TestCases {
+		testCases[i] = interfaces.TestCase{Input: tc.Input, Expected: tc.Expected, Tier: tc.Tier}
+	}
+
+	var pattern string
+	if len(p.Patterns) > 0 {
+		pattern = p.Patterns[0]
+	}
+
+	return interfaces.Problem{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Signature:   p.Signature,
+		Pattern:     pattern,
+		Difficulty:  p.Difficulty,
+		Companies:   p.Companies,
+		Tags:        p.Patterns,
+		TestCases:   testCases,
+		StarterCode: p.StarterCode,
+	}
+}
@@ -0,0 +1,139 @@
+package authoring
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// press sends keys to the model
+func press(t *testing.T, m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var updated tea.Model
+		updated, cmd = m.Update(key)
+		m = updated.(Model)
+	}
+	return m, cmd
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "ctrl+t":
+		return tea.KeyMsg{Type: tea.KeyCtrlT}
+	case "ctrl+p":
+		return tea.KeyMsg{Type: tea.KeyCtrlP}
+	case "ctrl+l":
+		return tea.KeyMsg{Type: tea.KeyCtrlL}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	m := NewModel(filepath.Join(t.TempDir(), "two_sum.json"), twoSum)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	return updated.(Model)
+}
+
+func TestEditingUpdatesPreview(t *testing.T) {
+	m := newTestModel(t)
+	assert.Contains(t, m.preview.View(), "# Two Sum")
+	assert.Contains(t, m.View(), "valid")
+
+	// Typing into the title re-renders the statement
+	m, _ = press(t, m, key("tab"), key("!"))
+	assert.Equal(t, "Two Sum!", m.draft.Values["title"])
+	assert.True(t, m.dirty)
+	assert.Contains(t, m.preview.View(), "# Two Sum!")
+
+	// Invalid input is reported inline under its field
+	m, _ = press(t, m, key("tab"), key("?"))
+	assert.Equal(t, []string{`difficulty "easy?" must be easy, medium or hard`}, m.issues["difficulty"])
+	assert.Contains(t, m.View(), `difficulty "easy?" must be easy, medium or hard`)
+	assert.Contains(t, m.View(), "1 issue(s)")
+}
+
+func TestHarnessPreview(t *testing.T) {
+	original := generateHarness
+	defer func() { generateHarness = original }()
+	var gotCode, gotLanguage string
+	generateHarness = func(prob *interfaces.Problem, code, language string) (string, error) {
+		gotCode, gotLanguage = code, language
+		return "// harness for " + prob.Signature.Name, nil
+	}
+
+	m := newTestModel(t)
+	m, _ = press(t, m, key("ctrl+p"))
+	assert.Equal(t, harnessTab, m.tab)
+	assert.Contains(t, m.preview.View(), "// harness for twoSum")
+	assert.Equal(t, "go", gotLanguage)
+	assert.Equal(t, twoSum.Solutions["go"], gotCode)
+
+	// Switching language regenerates the harness and swaps per-language code
+	m, _ = press(t, m, key("ctrl+l"))
+	assert.NotEqual(t, "go", m.language)
+	assert.Equal(t, m.language, gotLanguage)
+	assert.Empty(t, m.value(Fields[len(Fields)-1]))
+}
+
+func TestRunReferenceSolution(t *testing.T) {
+	original := runTests
+	defer func() { runTests = original }()
+	runTests = func(ctx context.Context, p *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		return []interfaces.TestResult{
+			{Passed: true},
+			{Input: "[3,3], 6", Expected: "[0,1]", Actual: "null", Failure: interfaces.FailureWrongAnswer},
+		}, false, nil
+	}
+
+	m := newTestModel(t)
+	m, cmd := press(t, m, key("ctrl+t"))
+	require.NotNil(t, cmd)
+	assert.True(t, m.testing)
+
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	assert.False(t, m.testing)
+	assert.Equal(t, testsTab, m.tab)
+	assert.Contains(t, m.preview.View(), "go reference solution passes 1/2 tests")
+	assert.Contains(t, m.preview.View(), "got:      null")
+
+	// Languages without a reference solution have nothing to run
+	m, _ = press(t, m, key("ctrl+l"))
+	_, cmd = press(t, m, key("ctrl+t"))
+	assert.Nil(t, cmd)
+}
+
+func TestSaveAndQuit(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = press(t, m, key("tab"), key("!"))
+
+	// Quitting with unsaved changes asks first
+	m, cmd := press(t, m, key("esc"))
+	assert.Nil(t, cmd)
+	assert.Contains(t, m.status, "Unsaved changes")
+
+	m, _ = press(t, m, key("ctrl+s"))
+	assert.False(t, m.dirty)
+	saved, err := Load(m.path)
+	require.NoError(t, err)
+	assert.Equal(t, "Two Sum!", saved.Title)
+
+	_, cmd = press(t, m, key("esc"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
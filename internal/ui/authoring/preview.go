@@ -0,0 +1,184 @@
+// Read-only preview of a finished problem, as solvers will see it
+
+package authoring
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session"
+)
+
+// previewPage is what the preview screen shows
+type previewPage int
+
+const (
+	statementPage previewPage = iota // The statement as a practice session shows it
+	starterPage                      // Starter code for the language
+	solutionPage                     // Reference solution for the language
+	issuesPage                       // What lint found
+	pageCount
+)
+
+// PreviewModel shows a problem as solvers will see it, with its code and
+// the issues found in it, without editing anything
+type PreviewModel struct {
+	problem   problem.Problem
+	issues    []string
+	languages []string
+	language  int
+
+	page     previewPage
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// NewPreviewModel creates a preview of a problem, listing issues found in it
+func NewPreviewModel(p problem.Problem, issues []string) PreviewModel {
+	m := PreviewModel{problem: p, issues: issues, viewport: viewport.New(80, 20)}
+	for _, lang := range Languages() {
+		if p.StarterCode[lang] != "" || p.Solutions[lang] != "" {
+			m.languages = append(m.languages, lang)
+		}
+	}
+	m.refresh()
+	return m
+}
+
+// Preview opens the preview screen for a problem
+func Preview(p problem.Problem, issues []string) error {
+	if _, err := runProgram(NewPreviewModel(p, issues), tea.WithAltScreen()); err != nil {
+		return fmt.Errorf("error running preview UI: %v", err)
+	}
+	return nil
+}
+
+// Init implements tea.Model
+func (m PreviewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m PreviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width = msg.Width - 2
+		m.viewport.Height = msg.Height - 6
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "tab":
+			m.page = (m.page + 1) % pageCount
+			m.refresh()
+			return m, nil
+		case "shift+tab":
+			m.page = (m.page + pageCount - 1) % pageCount
+			m.refresh()
+			return m, nil
+		case "l", "ctrl+l":
+			if len(m.languages) > 0 {
+				m.language = (m.language + 1) % len(m.languages)
+				m.refresh()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View implements tea.Model
+func (m PreviewModel) View() string {
+	header := titleStyle.Render("Problem Preview") + helpStyle.Render("  "+m.problem.ID)
+	if lang := m.currentLanguage(); lang != "" {
+		header += helpStyle.Render("  language: " + lang)
+	}
+
+	status := okStyle.Render(fmt.Sprintf("%s no issues found", symbols.Check))
+	if len(m.issues) > 0 {
+		status = issueStyle.Render(fmt.Sprintf("%s %d issue(s) to fix before publishing", symbols.Warning, len(m.issues)))
+	}
+	keys := helpStyle.Render("tab/shift+tab page · l language · ↑/↓ pgup/pgdn scroll · q quit")
+	return lipgloss.JoinVertical(lipgloss.Left, header, m.pagesView(), previewStyle.Render(m.viewport.View()), status, keys)
+}
+
+// pagesView renders the page tab bar
+func (m PreviewModel) pagesView() string {
+	lang := m.currentLanguage()
+	names := []string{"Statement", "Starter code (" + lang + ")", "Solution (" + lang + ")", fmt.Sprintf("Issues (%d)", len(m.issues))}
+	rendered := make([]string, len(names))
+	for i, name := range names {
+		if previewPage(i) == m.page {
+			rendered[i] = activeTab.Render(name)
+		} else {
+			rendered[i] = tabStyle.Render(name)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+// currentLanguage is the language whose code is shown, if the problem has
+// code in any
+func (m PreviewModel) currentLanguage() string {
+	if len(m.languages) == 0 {
+		return ""
+	}
+	return m.languages[m.language]
+}
+
+// refresh renders the current page into the viewport
+func (m *PreviewModel) refresh() {
+	lang := m.currentLanguage()
+	var content string
+	switch m.page {
+	case statementPage:
+		prob := m.problem
+		s := &session.Session{Problem: &prob, ShowPattern: true}
+		content = s.FormatProblemDescription()
+	case starterPage:
+		content = m.problem.StarterCode[lang]
+		if content == "" {
+			content = "No starter code in " + languageName(lang) + "."
+		}
+	case solutionPage:
+		content = m.problem.Solutions[lang]
+		if content == "" {
+			content = "No reference solution in " + languageName(lang) + "."
+		}
+	case issuesPage:
+		content = fmt.Sprintf("%s No issues found.", symbols.Check)
+		if len(m.issues) > 0 {
+			lines := make([]string, len(m.issues))
+			for i, issue := range m.issues {
+				lines[i] = fmt.Sprintf("%s %s", symbols.Cross, issue)
+			}
+			content = strings.Join(lines, "\n")
+		}
+	}
+	if m.viewport.Width > 0 {
+		content = lipgloss.NewStyle().Width(m.viewport.Width).Render(content)
+	}
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
+
+// languageName names a language in a message, or all of them if none is set
+func languageName(lang string) string {
+	if lang == "" {
+		return "any language"
+	}
+	return lang
+}
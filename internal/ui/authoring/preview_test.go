@@ -0,0 +1,52 @@
+package authoring
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+)
+
+// pressPreview sends keys to the preview
+func pressPreview(m PreviewModel, keys ...tea.KeyMsg) (PreviewModel, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var updated tea.Model
+		updated, cmd = m.Update(k)
+		m = updated.(PreviewModel)
+	}
+	return m, cmd
+}
+
+func TestPreview(t *testing.T) {
+	p := twoSum
+	p.StarterCode = map[string]string{"go": "func twoSum() {}", "python": "def two_sum(): pass"}
+	updated, _ := NewPreviewModel(p, []string{"two_sum: no examples"}).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := updated.(PreviewModel)
+	assert.Contains(t, m.viewport.View(), "# Two Sum")
+	assert.Contains(t, m.View(), "1 issue(s) to fix before publishing")
+
+	m, _ = pressPreview(m, key("tab"))
+	assert.Equal(t, starterPage, m.page)
+	assert.Contains(t, m.View(), "Starter code (go)")
+	assert.Contains(t, m.viewport.View(), "func twoSum() {}")
+
+	m, _ = pressPreview(m, key("l"))
+	assert.Equal(t, "python", m.currentLanguage(), "l moves to the next language")
+	assert.Contains(t, m.viewport.View(), "def two_sum(): pass")
+
+	m, _ = pressPreview(m, key("tab"), key("tab"))
+	assert.Equal(t, issuesPage, m.page)
+	assert.Contains(t, m.viewport.View(), "two_sum: no examples")
+
+	_, cmd := pressPreview(m, key("q"))
+	assert.NotNil(t, cmd, "q quits")
+}
+
+func TestPreviewWithoutCode(t *testing.T) {
+	m := NewPreviewModel(problem.Problem{ID: "bare", Title: "Bare"}, nil)
+	m, _ = pressPreview(m, key("tab"))
+	assert.Contains(t, m.viewport.View(), "No starter code in any language.")
+	assert.Contains(t, m.View(), "no issues found")
+}
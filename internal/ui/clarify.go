@@ -0,0 +1,120 @@
+// Clarifying questions panel for the session screen
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+)
+
+// aiConfigured reports whether the AI assistant is set up
+// Exported as variable for testing
+var aiConfigured = ai.Configured
+
+// clarifyWithAI asks the AI assistant the questions the problem does not
+// settle, returning the answers it gave before any error
+// Exported as variable for testing
+var clarifyWithAI = func(prob problem.Problem, questions []string) (map[string]string, error) {
+	agent, err := ai.GetDefaultAgent()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	answers := make(map[string]string)
+	for _, question := range questions {
+		answer, err := ai.Clarify(ctx, agent, prob, question)
+		if err != nil {
+			return answers, err
+		}
+		answers[question] = answer
+	}
+	return answers, nil
+}
+
+// clarifyMsg carries the AI's answers to the unsettled questions
+type clarifyMsg struct {
+	answers map[string]string
+	err     error
+}
+
+// toggleClarify shows or hides the clarifying questions. The first time they
+// are shown, the AI is asked whatever the problem does not settle.
+func (m Model) toggleClarify() (Model, tea.Cmd) {
+	m.session.showClarify = !m.session.showClarify
+	m.session.viewport.SetContent(m.sessionContent())
+	if !m.session.showClarify || m.session.clarifyAI != nil || m.session.clarifyAsking || !aiConfigured() {
+		return m, nil
+	}
+
+	var questions []string
+	for _, c := range m.session.problem.Clarify() {
+		if !c.Answered() {
+			questions = append(questions, c.Question)
+		}
+	}
+	if len(questions) == 0 {
+		return m, nil
+	}
+	m.session.clarifyAsking = true
+	m.session.viewport.SetContent(m.sessionContent())
+	prob := m.session.problem
+	return m, func() tea.Msg {
+		answers, err := clarifyWithAI(prob, questions)
+		return clarifyMsg{answers: answers, err: err}
+	}
+}
+
+// receiveClarifications stores the AI's answers. They are kept even if
+// incomplete, so the AI is not asked again in the same session.
+func (m Model) receiveClarifications(msg clarifyMsg) Model {
+	m.session.clarifyAsking = false
+	m.session.clarifyAI = msg.answers
+	if m.session.clarifyAI == nil {
+		m.session.clarifyAI = make(map[string]string)
+	}
+	if msg.err != nil {
+		m.session.message = fmt.Sprintf("The AI could not answer every question: %v", msg.err)
+	}
+	m.session.viewport.SetContent(m.sessionContent())
+	return m
+}
+
+// clarifyContent renders the clarifying questions with their answers from
+// the problem, or else from the AI
+func (m Model) clarifyContent() string {
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(accentColor).
+		Render("❓ Clarifying Questions"))
+	content.WriteString("\n\n")
+	content.WriteString("Ask these before you start coding.\n\n")
+
+	sourceStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	for _, c := range m.session.problem.Clarify() {
+		content.WriteString("Q: " + c.Question + "\n")
+		switch answer := m.session.clarifyAI[c.Question]; {
+		case c.Answered():
+			content.WriteString("A: " + c.Answer + "\n")
+			content.WriteString(sourceStyle.Render("   From: "+strings.Join(c.Evidence, "; ")) + "\n")
+		case answer != "":
+			content.WriteString("A: " + answer + "\n")
+			content.WriteString(sourceStyle.Render("   From: AI assistant") + "\n")
+		case m.session.clarifyAsking:
+			content.WriteString("A: Asking the AI assistant...\n")
+		default:
+			content.WriteString("A: Not stated in the problem. Ask your interviewer, or state your assumption.\n")
+		}
+		content.WriteString("\n")
+	}
+	return content.String()
+}
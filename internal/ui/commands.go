@@ -10,6 +10,7 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/common/config"
 	"github.com/lancekrogers/algo-scales/internal/daily"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/lancekrogers/algo-scales/internal/ui/splitscreen"
 )
@@ -52,27 +53,32 @@ func loadProblemsForPattern(pattern string) tea.Cmd {
 			return problemsErrorMsg{err: err}
 		}
 		
-		// Convert pattern name to directory/tag format for matching
-		// e.g., "Two Pointers" -> "two-pointers"
-		normalizedPattern := strings.ToLower(strings.ReplaceAll(pattern, " ", "-"))
-		normalizedPattern = strings.ReplaceAll(normalizedPattern, "&", "")
-		normalizedPattern = strings.ReplaceAll(normalizedPattern, "/", "-")
-		
-		// Filter problems by pattern
-		filtered := make([]problem.Problem, 0)
-		for _, p := range problems {
-			for _, tag := range p.Patterns {
-				// Also normalize the tag from the problem
-				normalizedTag := strings.ToLower(tag)
-				if normalizedTag == normalizedPattern || tag == pattern {
-					filtered = append(filtered, p)
-					break
-				}
+		return problemsLoadedMsg{problems: filterProblemsByPattern(problems, pattern)}
+	}
+}
+
+// filterProblemsByPattern returns the problems tagged with a pattern, given
+// either as a display name or as a tag
+func filterProblemsByPattern(problems []problem.Problem, pattern string) []problem.Problem {
+	// Convert pattern name to directory/tag format for matching
+	// e.g., "Two Pointers" -> "two-pointers"
+	normalizedPattern := strings.ToLower(strings.ReplaceAll(pattern, " ", "-"))
+	normalizedPattern = strings.ReplaceAll(normalizedPattern, "&", "")
+	normalizedPattern = strings.ReplaceAll(normalizedPattern, "/", "-")
+
+	// Filter problems by pattern
+	filtered := make([]problem.Problem, 0)
+	for _, p := range problems {
+		for _, tag := range p.Patterns {
+			// Also normalize the tag from the problem
+			normalizedTag := strings.ToLower(tag)
+			if normalizedTag == normalizedPattern || tag == pattern {
+				filtered = append(filtered, p)
+				break
 			}
 		}
-		
-		return problemsLoadedMsg{problems: filtered}
 	}
+	return filtered
 }
 
 // loadStats loads user statistics
@@ -82,7 +88,10 @@ func loadStats() tea.Cmd {
 		if err != nil {
 			return statsErrorMsg{err: err}
 		}
-		return statsLoadedMsg{stats: *summary}
+		// The summary is still worth showing without the calendar or goals
+		calendar, _ := stats.GetCalendar()
+		progress, _ := stats.GetGoalProgress()
+		return statsLoadedMsg{stats: *summary, calendar: calendar, rotation: daily.Plan(), goals: progress}
 	}
 }
 
@@ -115,13 +124,6 @@ func loadDailyScale() tea.Cmd {
 	}
 }
 
-// sessionTick generates periodic ticks for the session timer
-func sessionTick() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return sessionTickMsg{}
-	})
-}
-
 // startSession creates a command to start a new session
 func startSession(prob problem.Problem) tea.Cmd {
 	return func() tea.Msg {
@@ -129,6 +131,8 @@ func startSession(prob problem.Problem) tea.Cmd {
 		sessionID := fmt.Sprintf("session-%s-%d", prob.ID, time.Now().Unix())
 		return sessionStartedMsg{
 			sessionID: sessionID,
+			problem:   prob,
+			clock:     clock.NewStopwatch(),
 		}
 	}
 }
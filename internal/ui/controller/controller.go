@@ -12,6 +12,7 @@ import (
 	"github.com/lancekrogers/algo-scales/internal/common/highlight"
 	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
 	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
 	"github.com/lancekrogers/algo-scales/internal/problem"
 	"github.com/lancekrogers/algo-scales/internal/session"
 	"github.com/lancekrogers/algo-scales/internal/stats"
@@ -44,7 +45,7 @@ type Controller struct {
 func NewController(m *model.UIModel) *Controller {
 	return &Controller{
 		Model:             m,
-		syntaxHighlighter: highlight.NewSyntaxHighlighter("monokai"),
+		syntaxHighlighter: highlight.NewSyntaxHighlighter(""),
 		spinners:          view.NewCustomSpinners(),
 		patternViz:        view.NewPatternVisualization(),
 		sessionManager:    session.NewManager(),
@@ -164,6 +165,21 @@ func (c *Controller) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case model.AchievementUnlockedMsg:
+		// Mark the achievement earned and let the user know via the inbox
+		if achievement, exists := c.Model.Achievements[msg.AchievementID]; exists && !achievement.Earned {
+			achievement.Earned = true
+			achievement.EarnedDate = time.Now()
+			c.Model.Achievements[msg.AchievementID] = achievement
+			if err := notifications.Add(notifications.Notification{
+				Kind:    notifications.KindAchievement,
+				Title:   fmt.Sprintf("%s Achievement unlocked: %s", achievement.Icon, achievement.Title),
+				Details: []string{achievement.Description},
+			}); err != nil {
+				log.Printf("Failed to record achievement notification: %v", err)
+			}
+		}
+
 	case model.SelectionMsg:
 		// Handle selection changes based on app state
 		cmd = c.handleSelection(msg.Index)
@@ -438,6 +454,7 @@ func (c *Controller) submitSolution() tea.Cmd {
 				Expected: result.Expected,
 				Actual:   result.Actual,
 				Passed:   result.Passed,
+				Duration: result.Duration,
 			}
 		}
 
@@ -501,6 +518,10 @@ func (c *Controller) updateStatistics() {
 		SolutionUsed: c.activeSession.IsSolutionShown(),
 		Patterns:     problem.Tags,
 		Difficulty:   problem.Difficulty,
+		Context:      interfaces.ContextTUI,
+	}
+	if c.Model.Session.Problem != nil {
+		sessionStats.Environment = session.CaptureEnvironment(*c.Model.Session.Problem, c.Model.Session.Language)
 	}
 	
 	// Record stats using the stats service
@@ -551,6 +572,7 @@ func (c *Controller) convertInterfaceToLocalProblem(p interfaces.Problem) proble
 		testCases[i] = problem.TestCase{
 			Input:    tc.Input,
 			Expected: tc.Expected,
+			Tier:     tc.Tier,
 		}
 	}
 	
@@ -568,6 +590,7 @@ func (c *Controller) convertInterfaceToLocalProblem(p interfaces.Problem) proble
 		ID:                  p.ID,
 		Title:               p.Title,
 		Description:         p.Description,
+		Signature:           p.Signature,
 		Difficulty:          p.Difficulty,
 		Patterns:            p.Tags, // Map Tags to Patterns
 		Companies:           p.Companies,
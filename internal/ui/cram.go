@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+)
+
+// StartCram runs the TUI straight into a cram session, ending on its summary
+func StartCram(c *cram.Session) error {
+	model := NewModel()
+	model.state = StateSession
+	model.previousState = StateSession
+	model.session.cram = c
+
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running program: %w", err)
+	}
+	return nil
+}
+
+// startCramProblem starts the cram session's current problem, with its
+// share of the countdown
+func startCramProblem(c *cram.Session) tea.Cmd {
+	return func() tea.Msg {
+		prob := c.Current()
+		if prob == nil {
+			return nil
+		}
+		return sessionStartedMsg{
+			sessionID: fmt.Sprintf("cram-%s-%d", prob.ID, time.Now().Unix()),
+			problem:   *prob,
+			clock:     clock.NewCountdown(c.Allotment()),
+		}
+	}
+}
+
+// advanceCram records how the cram session's current problem ended and
+// starts the next one, or shows the summary after the last
+func (m Model) advanceCram(outcome cram.Outcome) (Model, tea.Cmd) {
+	c := m.session.cram
+	if m.session.clock != nil {
+		m.session.clock.Pause()
+	}
+	spent := m.session.elapsed()
+	m.recordSession(spent, outcome == cram.Solved)
+	c.Finish(outcome, spent)
+	if c.Done() {
+		return m.navigate(StateCramSummary), nil
+	}
+
+	title := m.session.problem.Title
+	m.session = sessionModel{
+		cram:     c,
+		viewport: m.session.viewport,
+		message:  fmt.Sprintf("%s %s. Problem %d of %d.", title, outcomeDescription(outcome, spent), c.Position(), len(c.Queue)),
+	}
+	return m, startCramProblem(c)
+}
+
+// endCram stops the cram session early and shows its summary
+func (m Model) endCram() Model {
+	if m.session.clock != nil {
+		m.session.clock.Pause()
+	}
+	m.session.cram.End()
+	return m.navigate(StateCramSummary)
+}
+
+// cramStatus describes where the cram session stands, such as "Cram 2 of
+// 5, 31:40 left in all"
+func (m Model) cramStatus() string {
+	c := m.session.cram
+	left := max(0, int(c.Remaining()-m.session.elapsed()))
+	return fmt.Sprintf("Cram %d of %d, %s left in all", c.Position(), len(c.Queue), formatDuration(time.Duration(left)))
+}
+
+// outcomeDescription says how a cram problem ended, such as "solved in
+// 00:04:12"
+func outcomeDescription(outcome cram.Outcome, spent time.Duration) string {
+	switch outcome {
+	case cram.Solved:
+		return "solved in " + formatDuration(spent)
+	case cram.TimedOut:
+		return "timed out after " + formatDuration(spent)
+	default:
+		return "skipped after " + formatDuration(spent)
+	}
+}
+
+// updateCramSummary handles keys on the cram summary
+func (m Model) updateCramSummary(msg tea.Msg) (Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "enter" {
+		m.session = sessionModel{}
+		m.previousState = StateHome
+		return m.navigate(StateHome), loadReminders()
+	}
+	return m, nil
+}
+
+// viewCramSummary lists how each problem of the cram session went
+func (m Model) viewCramSummary() string {
+	c := m.session.cram
+	if c == nil {
+		return ""
+	}
+	summary := c.Summarize()
+
+	var b strings.Builder
+	if access.Enabled() {
+		b.WriteString(access.Field("Screen", "Cram Summary") + "\n")
+	} else {
+		b.WriteString(titleStyle.Render("Cram Summary"))
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Solved %d of %d in %s\n\n", summary.Solved, len(c.Queue), formatDuration(summary.Time))
+	for _, r := range c.Results {
+		mark := symbols.Check.String()
+		if r.Outcome != cram.Solved {
+			mark = symbols.Cross.String()
+		}
+		fmt.Fprintf(&b, "%s %s (%s): %s\n", mark, r.Problem.Title, strings.Join(r.Problem.Patterns, ", "), outcomeDescription(r.Outcome, r.Time))
+	}
+	for _, p := range c.Queue[len(c.Results):] {
+		fmt.Fprintf(&b, "- %s (%s): not reached\n", p.Title, strings.Join(p.Patterns, ", "))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("Enter: Home • q: Quit"))
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCramSession(t *testing.T) {
+	original := stats.RecordSession
+	defer func() { stats.RecordSession = original }()
+	var recorded []stats.SessionStats
+	stats.RecordSession = func(s stats.SessionStats) error {
+		recorded = append(recorded, s)
+		return nil
+	}
+
+	queue := []problem.Problem{
+		{ID: "two_sum", Title: "Two Sum", Patterns: []string{"hash-map"}},
+		{ID: "islands", Title: "Number of Islands", Patterns: []string{"dfs"}},
+		{ID: "pair_sum", Title: "Pair Sum", Patterns: []string{"two-pointers"}},
+		{ID: "paths", Title: "Unique Paths", Patterns: []string{"dynamic-programming"}},
+	}
+	c := cram.New(queue, 40*time.Minute)
+	m := NewModel()
+	m.state = StateSession
+	m.session.cram = c
+
+	// Each problem starts on its share of the countdown, on a fake clock
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	start := func(m Model) Model {
+		msg := startCramProblem(c)().(sessionStartedMsg)
+		msg.clock.WithNow(func() time.Time { return now })
+		m, _ = m.updateSession(msg)
+		return m
+	}
+	m = start(m)
+	assert.Equal(t, "Two Sum", m.session.problem.Title)
+	assert.Equal(t, 10*time.Minute, m.session.remaining())
+
+	// Solving moves straight on, with the time saved carried over
+	now = now.Add(4 * time.Minute)
+	m, cmd := m.updateSession(submitResultsMsg{results: "Running tests...\n\n3/3 tests passed"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "Two Sum solved in 00:04:00. Problem 2 of 4.", m.session.message)
+	m = start(m)
+	assert.Equal(t, "Number of Islands", m.session.problem.Title)
+	assert.Equal(t, 12*time.Minute, m.session.remaining())
+	assert.Empty(t, m.session.testResults)
+
+	// Running out of time moves on by itself
+	now = now.Add(12 * time.Minute)
+	m, _ = m.updateSession(clock.TickMsg{})
+	assert.Equal(t, "Number of Islands timed out after 00:12:00. Problem 3 of 4.", m.session.message)
+	m = start(m)
+
+	// n skips
+	now = now.Add(time.Minute)
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Contains(t, m.session.message, "Pair Sum skipped after 00:01:00")
+	m = start(m)
+
+	// Quitting asks first, and any other key cancels
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = next.(Model)
+	assert.True(t, m.session.confirmQuit)
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	assert.False(t, m.session.confirmQuit)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	next, _ = next.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	assert.Equal(t, StateCramSummary, m.state)
+
+	m.ready = true
+	view := m.viewCramSummary()
+	assert.Contains(t, view, "Solved 1 of 4 in 00:17:00")
+	assert.Contains(t, view, "Two Sum (hash-map): solved in 00:04:00")
+	assert.Contains(t, view, "Number of Islands (dfs): timed out after 00:12:00")
+	assert.Contains(t, view, "Unique Paths (dynamic-programming): not reached")
+
+	require.Len(t, recorded, 3)
+	assert.True(t, recorded[0].Solved)
+	assert.False(t, recorded[1].Solved)
+	assert.Equal(t, string(interfaces.CramMode), recorded[2].Mode)
+
+	m, _ = m.updateCramSummary(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, StateHome, m.state)
+	assert.Nil(t, m.session.cram)
+}
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
 	"github.com/lancekrogers/algo-scales/internal/daily"
 )
 
@@ -42,12 +43,15 @@ func (m Model) updateDaily(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders the daily scale screen
 func (m Model) viewDaily() string {
+	if access.Enabled() {
+		return m.viewDailyAccessible()
+	}
 	var b strings.Builder
 	
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		MarginBottom(2)
 	
 	b.WriteString(titleStyle.Render("🎵 Daily Scales"))
@@ -55,7 +59,7 @@ func (m Model) viewDaily() string {
 	
 	if m.daily.loading {
 		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214"))
+			Foreground(warningColor)
 		b.WriteString(loadingStyle.Render("Loading daily scale..."))
 		return b.String()
 	}
@@ -63,7 +67,7 @@ func (m Model) viewDaily() string {
 	// Scale information
 	scaleBoxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(primaryColor).
 		Padding(1, 2).
 		Width(50).
 		Align(lipgloss.Center)
@@ -82,17 +86,23 @@ func (m Model) viewDaily() string {
 	// Progress information
 	if p, ok := m.daily.progress.(daily.ScaleProgress); ok {
 		progressStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243"))
+			Foreground(mutedColor)
 		
 		// Streak information
 		streakStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("214"))
+			Foreground(warningColor)
 		
 		if p.Streak > 0 {
 			streakText := fmt.Sprintf("🔥 %d day streak!", p.Streak)
 			b.WriteString(streakStyle.Render(streakText))
-			b.WriteString("\n\n")
+			b.WriteString("\n")
+			if limit := daily.MaxFreezes(); limit > 0 {
+				freezeText := fmt.Sprintf("Streak freezes: %d/%d", min(p.Freezes, limit), limit)
+				b.WriteString(progressStyle.Render(freezeText))
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
 		}
 		
 		// Completion progress
@@ -111,20 +121,21 @@ func (m Model) viewDaily() string {
 	
 	// Action bar
 	actionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-	
-	actions := []string{
-		"Enter: Practice this scale",
-		"n: Next scale",
-		"r: Reset progress",
-		"Esc: Back",
-	}
+		Foreground(mutedColor)
 	
-	b.WriteString(actionStyle.Render(strings.Join(actions, " • ")))
+	b.WriteString(actionStyle.Render(strings.Join(dailyActions, " • ")))
 	
 	return b.String()
 }
 
+// dailyActions lists the daily scale screen's keys
+var dailyActions = []string{
+	"Enter: Practice this scale",
+	"n: Next scale",
+	"r: Reset progress",
+	"Esc: Back",
+}
+
 // scaleToPattern converts a scale pattern to a problem pattern
 func scaleToPattern(scale string) string {
 	// Map scale names to pattern names
@@ -0,0 +1,98 @@
+// Package diffview draws two solutions side by side, aligned line by line,
+// with both sides syntax highlighted and the lines that differ marked. It
+// draws with lipgloss alone, so the CLI and the lite build can use it as
+// well as the TUI.
+package diffview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/solutions"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// minColumn is the narrowest a column is drawn, however little room there is
+const minColumn = 24
+
+// separator divides the two columns
+const separator = " │ "
+
+// Render draws the left solution beside the right one in columns that fit
+// width, each under its title. Lines only on the left are marked - and
+// lines only on the right +.
+func Render(left, right, language string, width int, leftTitle, rightTitle string) string {
+	t := theme.Current()
+	title := lipgloss.NewStyle().Bold(true).Foreground(t.Secondary)
+	muted := lipgloss.NewStyle().Foreground(t.Muted)
+	removed := lipgloss.NewStyle().Bold(true).Foreground(t.Error)
+	added := lipgloss.NewStyle().Bold(true).Foreground(t.Success)
+
+	left, right = expandTabs(left), expandTabs(right)
+	rows := solutions.SideBySide(solutions.Diff(left, right))
+	h := highlight.NewSyntaxHighlighter("")
+	leftLines := h.HighlightLines(strings.TrimRight(left, "\n"), language)
+	rightLines := h.HighlightLines(strings.TrimRight(right, "\n"), language)
+
+	column := max((width-lipgloss.Width(separator))/2, minColumn)
+	digits := len(fmt.Sprint(max(len(leftLines), len(rightLines))))
+
+	// cell draws one side of a row: the line number, the change marker and
+	// the highlighted code, cut and padded to the column
+	cell := func(lines []string, n int, changed bool, marker lipgloss.Style, sign string) string {
+		if n == 0 {
+			return strings.Repeat(" ", column)
+		}
+		gutter := muted.Render(fmt.Sprintf("%*d ", digits, n))
+		mark := "  "
+		if changed {
+			mark = marker.Render(sign) + " "
+		}
+		return pad(gutter+mark+lines[n-1], column)
+	}
+
+	var b strings.Builder
+	b.WriteString(pad(title.Render(leftTitle), column))
+	b.WriteString(muted.Render(separator))
+	b.WriteString(title.Render(rightTitle))
+	b.WriteString("\n")
+	for _, row := range rows {
+		b.WriteString(cell(leftLines, row.Left, row.Changed, removed, "-"))
+		b.WriteString(muted.Render(separator))
+		b.WriteString(strings.TrimRight(cell(rightLines, row.Right, row.Changed, added, "+"), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Summary counts the lines that differ between the user's code, on the left,
+// and the reference solution
+func Summary(left, right string) string {
+	var removed, added int
+	for _, l := range solutions.Diff(left, right) {
+		switch l.Op {
+		case solutions.Removed:
+			removed++
+		case solutions.Added:
+			added++
+		}
+	}
+	if removed == 0 && added == 0 {
+		return "Your code matches the reference solution."
+	}
+	return fmt.Sprintf("%d line(s) only in yours, %d only in the reference.", removed, added)
+}
+
+// pad cuts s to width, keeping its colors, and pads it with spaces
+func pad(s string, width int) string {
+	s = lipgloss.NewStyle().MaxWidth(width).Render(s)
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+}
+
+// expandTabs replaces tabs so columns line up whatever the code's
+// indentation
+func expandTabs(code string) string {
+	return strings.ReplaceAll(code, "\t", "    ")
+}
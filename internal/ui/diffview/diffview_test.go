@@ -0,0 +1,33 @@
+package diffview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	highlight.SetColors(highlight.NoColor)
+	mine := "func twoSum(nums []int) []int {\n\tfor i := range nums {\n\t\tfor j := range nums {\n\treturn nil\n}\n"
+	reference := "func twoSum(nums []int) []int {\n\tseen := map[int]int{}\n\tfor i := range nums {\n\treturn nil\n}\n"
+
+	lines := strings.Split(strings.TrimSuffix(ansi.Strip(Render(mine, reference, "go", 80, "Yours", "Reference")), "\n"), "\n")
+	require.Len(t, lines, 7, "titles and six rows")
+	assert.Equal(t, "Yours"+strings.Repeat(" ", 33)+" │ Reference", lines[0])
+	assert.Equal(t, "1   func twoSum(nums []int) []int {    │ 1   func twoSum(nums []int) []int {", lines[1])
+	assert.Equal(t, strings.Repeat(" ", 38)+" │ 2 +     seen := map[int]int{}", lines[2], "an added line leaves its left side empty")
+	assert.Equal(t, "3 -         for j := range nums {      │", strings.TrimRight(lines[4], " "))
+
+	// Long lines are cut to the column
+	narrow := strings.Split(ansi.Strip(Render("x := "+strings.Repeat("a", 100), "", "go", 60, "Yours", "Reference")), "\n")
+	assert.Equal(t, 28, len([]rune(strings.SplitN(narrow[1], " │", 2)[0])))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "Your code matches the reference solution.", Summary("a\nb\n", "a\nb"))
+	assert.Equal(t, "1 line(s) only in yours, 2 only in the reference.", Summary("a\nb\n", "a\nc\nd\n"))
+}
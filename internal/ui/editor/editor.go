@@ -0,0 +1,1152 @@
+// Package editor is the code editor embedded in the session screens. It
+// edits with vim's normal, insert and visual modes, keeps an undo history,
+// and asks the session to save the code on :w, so a solution can be written
+// without leaving the TUI.
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// tabWidth is how many columns a tab is shown as
+const tabWidth = 4
+
+// undoLimit is how many changes can be undone
+const undoLimit = 500
+
+// Mode is the vim mode the editor is in
+type Mode int
+
+const (
+	Normal Mode = iota
+	Insert
+	Visual
+	VisualLine
+	Command // Typing a : command
+)
+
+// String returns the mode as the status line shows it
+func (m Mode) String() string {
+	switch m {
+	case Insert:
+		return "INSERT"
+	case Visual:
+		return "VISUAL"
+	case VisualLine:
+		return "VISUAL LINE"
+	case Command:
+		return "COMMAND"
+	default:
+		return "NORMAL"
+	}
+}
+
+// SaveMsg asks the session to save the code, on :w. Close is set for :wq
+// and :x, after which the session closes the editor once the code is saved.
+type SaveMsg struct {
+	Code  string
+	Close bool
+}
+
+// CloseMsg asks the session to close the editor, on :q
+type CloseMsg struct{}
+
+// pos is a place in the buffer: a line, and a rune in it
+type pos struct {
+	row, col int
+}
+
+// before reports whether p comes before q in the buffer
+func (p pos) before(q pos) bool {
+	return p.row < q.row || (p.row == q.row && p.col < q.col)
+}
+
+// snapshot is the buffer as it was before a change, for undo and redo
+type snapshot struct {
+	lines  [][]rune
+	cursor pos
+}
+
+// Model is the editor's state
+type Model struct {
+	lines   [][]rune
+	cursor  pos
+	wantCol int // The column vertical motions aim for
+	mode    Mode
+
+	anchor  pos      // Where the visual selection started
+	pending []string // Normal mode keys typed so far, such as a count and an operator
+	cmdline string   // The : command being typed
+
+	register []rune // Text yanked or deleted last
+	linewise bool   // Whether the register holds whole lines
+
+	undo []snapshot
+	redo []snapshot
+
+	saved   string // The code as last saved
+	message string // Shown in the status line until the next key
+
+	width, height int
+	top, left     int // The first line and column shown
+}
+
+// New creates an editor holding code, in normal mode
+func New(code string) Model {
+	m := Model{width: 80, height: 20}
+	m.setValue(code)
+	m.saved = m.Value()
+	return m
+}
+
+// Value returns the code being edited
+func (m Model) Value() string {
+	lines := make([]string, len(m.lines))
+	for i, line := range m.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetValue replaces the code being edited, as an undoable change
+func (m *Model) SetValue(code string) {
+	m.checkpoint()
+	m.setValue(code)
+}
+
+func (m *Model) setValue(code string) {
+	m.lines = nil
+	for _, line := range strings.Split(code, "\n") {
+		m.lines = append(m.lines, []rune(line))
+	}
+	m.cursor = pos{}
+	m.clamp()
+}
+
+// Mode returns the mode the editor is in
+func (m Model) Mode() Mode {
+	return m.mode
+}
+
+// Dirty reports whether the code has changed since it was last saved
+func (m Model) Dirty() bool {
+	return m.Value() != m.saved
+}
+
+// MarkSaved records that the session has saved the code
+func (m *Model) MarkSaved() {
+	m.saved = m.Value()
+	m.message = fmt.Sprintf("%d lines written", len(m.lines))
+}
+
+// SetMessage shows a message in the status line, such as why saving failed
+func (m *Model) SetMessage(message string) {
+	m.message = message
+}
+
+// SetSize sets the space the editor is drawn in, status line included
+func (m *Model) SetSize(width, height int) {
+	m.width, m.height = width, height
+	m.scroll()
+}
+
+// Update handles a key press
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.message = ""
+
+	var cmd tea.Cmd
+	if keyMsg.Type == tea.KeyRunes && len(keyMsg.Runes) > 1 && m.mode != Insert {
+		// Several runes arrive at once when text is pasted; outside insert
+		// mode each is a key of its own
+		var cmds []tea.Cmd
+		for _, r := range keyMsg.Runes {
+			cmds = append(cmds, m.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}))
+		}
+		cmd = tea.Batch(cmds...)
+	} else {
+		cmd = m.key(keyMsg)
+	}
+	m.clamp()
+	m.scroll()
+	return m, cmd
+}
+
+// key handles a key in whichever mode the editor is in
+func (m *Model) key(msg tea.KeyMsg) tea.Cmd {
+	switch m.mode {
+	case Insert:
+		m.insertKey(msg)
+	case Command:
+		return m.commandKey(msg)
+	default:
+		m.normalKey(msg.String())
+	}
+	return nil
+}
+
+// normalKey handles a key in normal or visual mode. Keys build up into a
+// count, an operator and a motion, as in 3dw, and nothing happens until
+// they make a whole command.
+func (m *Model) normalKey(key string) {
+	if key == "esc" || (key == "ctrl+c" && m.pending != nil) {
+		if m.pending == nil && m.visual() {
+			m.mode = Normal
+		}
+		m.pending = nil
+		return
+	}
+	m.pending = append(m.pending, key)
+	if m.runPending(m.pending) {
+		m.pending = nil
+	}
+}
+
+// runPending runs the keys typed so far, reporting false while they only
+// begin a command
+func (m *Model) runPending(keys []string) bool {
+	count, keys := parseCount(keys)
+	if len(keys) == 0 {
+		return false
+	}
+
+	// Shorthands for an operator and a motion
+	if aliases, ok := shorthands[keys[0]]; ok && !m.visual() {
+		return m.runPending(append(append(countKeys(count), aliases...), keys[1:]...))
+	}
+
+	op := ""
+	if isOperator(keys[0]) {
+		op, keys = keys[0], keys[1:]
+		if m.visual() {
+			m.applyVisual(op)
+			return true
+		}
+		var opCount int
+		opCount, keys = parseCount(keys)
+		if opCount > 0 {
+			count = max(count, 1) * opCount
+		}
+		if len(keys) == 0 {
+			return false
+		}
+		if keys[0] == op {
+			// dd, cc and yy work on whole lines
+			start := m.cursor.row
+			m.apply(op, pos{start, 0}, pos{min(start+max(count, 1)-1, len(m.lines)-1), 0}, true, false)
+			return true
+		}
+	}
+
+	target, kind, status := m.motion(keys, count, op)
+	switch status {
+	case motionPending:
+		return false
+	case motionNone:
+		if op != "" {
+			return true
+		}
+		if m.visual() {
+			m.visualCommand(keys)
+			return true
+		}
+		return m.command(keys, max(count, 1))
+	}
+
+	if op != "" {
+		m.apply(op, m.cursor, target, kind == linewise, kind == inclusive)
+		return true
+	}
+	m.cursor = target
+	if kind != linewise || keys[0] == "G" || keys[0] == "g" {
+		m.wantCol = m.cursor.col
+	}
+	if keys[0] == "$" || keys[0] == "end" {
+		m.wantCol = 1 << 30
+	}
+	return true
+}
+
+// shorthands are commands that are an operator and a motion
+var shorthands = map[string][]string{
+	"x": {"d", "l"},
+	"X": {"d", "h"},
+	"D": {"d", "$"},
+	"C": {"c", "$"},
+	"s": {"c", "l"},
+	"Y": {"y", "y"},
+}
+
+func isOperator(key string) bool {
+	return key == "d" || key == "c" || key == "y"
+}
+
+// parseCount splits a leading count off keys, returning 0 when there is
+// none. A leading 0 is the motion, not a count.
+func parseCount(keys []string) (int, []string) {
+	count := 0
+	for len(keys) > 0 && len(keys[0]) == 1 && keys[0][0] >= '0' && keys[0][0] <= '9' {
+		if keys[0] == "0" && count == 0 {
+			break
+		}
+		count = count*10 + int(keys[0][0]-'0')
+		keys = keys[1:]
+	}
+	return count, keys
+}
+
+// countKeys turns a count back into keys
+func countKeys(count int) []string {
+	if count == 0 {
+		return nil
+	}
+	var keys []string
+	for _, r := range strconv.Itoa(count) {
+		keys = append(keys, string(r))
+	}
+	return keys
+}
+
+// How a motion's range is taken by an operator
+type motionKind int
+
+const (
+	exclusive motionKind = iota // Up to the target
+	inclusive                   // Up to and including the target
+	linewise                    // Whole lines, from the cursor's to the target's
+)
+
+// Whether keys make a motion
+type motionStatus int
+
+const (
+	motionDone    motionStatus = iota
+	motionPending              // The keys begin a motion
+	motionNone                 // The keys are not a motion
+)
+
+// motion returns where a motion moves the cursor to, count times. op is the
+// operator it is for, if any, as cw and dw stop at the end of the line.
+func (m *Model) motion(keys []string, count int, op string) (pos, motionKind, motionStatus) {
+	n := max(count, 1)
+	p := m.cursor
+	switch keys[0] {
+	case "h", "left", "backspace":
+		p.col = max(p.col-n, 0)
+	case "l", "right", " ":
+		p.col = min(p.col+n, len(m.lines[p.row]))
+	case "j", "down", "enter":
+		p.row = min(p.row+n, len(m.lines)-1)
+		p.col = min(m.wantCol, len(m.lines[p.row]))
+		return p, linewise, motionDone
+	case "k", "up":
+		p.row = max(p.row-n, 0)
+		p.col = min(m.wantCol, len(m.lines[p.row]))
+		return p, linewise, motionDone
+	case "0", "home":
+		p.col = 0
+	case "^":
+		p.col = firstNonBlank(m.lines[p.row])
+	case "$", "end":
+		p.row = min(p.row+n-1, len(m.lines)-1)
+		p.col = max(len(m.lines[p.row])-1, 0)
+		return p, inclusive, motionDone
+	case "w":
+		if op == "c" && p.col < len(m.lines[p.row]) && !unicode.IsSpace(m.lines[p.row][p.col]) {
+			// cw changes to the end of the word, as ce does
+			return m.motion(append([]string{"e"}, keys[1:]...), count, op)
+		}
+		for i := 0; i < n; i++ {
+			next := m.wordForward(p)
+			if op != "" && next.row > p.row && i == n-1 {
+				// dw at the end of a line stops there
+				next = pos{p.row, len(m.lines[p.row])}
+			}
+			p = next
+		}
+	case "b":
+		for i := 0; i < n; i++ {
+			p = m.wordBackward(p)
+		}
+	case "e":
+		for i := 0; i < n; i++ {
+			p = m.wordEnd(p)
+		}
+		return p, inclusive, motionDone
+	case "G":
+		p.row = len(m.lines) - 1
+		if count > 0 {
+			p.row = min(count, len(m.lines)) - 1
+		}
+		p.col = firstNonBlank(m.lines[p.row])
+		return p, linewise, motionDone
+	case "g":
+		if len(keys) == 1 {
+			return p, exclusive, motionPending
+		}
+		if keys[1] != "g" {
+			return p, exclusive, motionNone
+		}
+		p.row = min(n, len(m.lines)) - 1
+		p.col = firstNonBlank(m.lines[p.row])
+		return p, linewise, motionDone
+	default:
+		return p, exclusive, motionNone
+	}
+	return p, exclusive, motionDone
+}
+
+// command runs a normal mode command that is not a motion, reporting false
+// while its keys are incomplete
+func (m *Model) command(keys []string, n int) bool {
+	line := m.lines[m.cursor.row]
+	switch keys[0] {
+	case "i":
+		m.startInsert()
+	case "a":
+		m.startInsert()
+		m.cursor.col = min(m.cursor.col+1, len(line))
+	case "I":
+		m.startInsert()
+		m.cursor.col = firstNonBlank(line)
+	case "A":
+		m.startInsert()
+		m.cursor.col = len(line)
+	case "o", "O":
+		m.checkpoint()
+		row := m.cursor.row
+		if keys[0] == "o" {
+			row++
+		}
+		indent := leadingSpace(line)
+		m.insertLines(row, [][]rune{indent})
+		m.cursor = pos{row, len(indent)}
+		m.mode = Insert
+	case "r":
+		if len(keys) == 1 {
+			return false
+		}
+		r := []rune(keys[1])
+		if len(r) != 1 || m.cursor.col+n > len(line) {
+			return true
+		}
+		m.checkpoint()
+		for i := 0; i < n; i++ {
+			m.lines[m.cursor.row][m.cursor.col+i] = r[0]
+		}
+		m.cursor.col += n - 1
+	case "J":
+		m.join(max(n, 2))
+	case "p", "P":
+		m.put(keys[0] == "P", n)
+	case "u":
+		for i := 0; i < n; i++ {
+			m.undoChange()
+		}
+	case "ctrl+r":
+		for i := 0; i < n; i++ {
+			m.redoChange()
+		}
+	case "v", "V":
+		m.mode = Visual
+		if keys[0] == "V" {
+			m.mode = VisualLine
+		}
+		m.anchor = m.cursor
+	case ":":
+		m.mode = Command
+		m.cmdline = ""
+	case "ctrl+c":
+		m.message = "Type :q to close the editor"
+	}
+	m.wantCol = m.cursor.col
+	return true
+}
+
+// visualCommand handles the keys that mean something else in visual mode
+func (m *Model) visualCommand(keys []string) {
+	switch keys[0] {
+	case "o":
+		m.anchor, m.cursor = m.cursor, m.anchor
+	case "v", "V":
+		mode := Visual
+		if keys[0] == "V" {
+			mode = VisualLine
+		}
+		if m.mode == mode {
+			m.mode = Normal
+		} else {
+			m.mode = mode
+		}
+	case "x":
+		m.applyVisual("d")
+	}
+}
+
+// visual reports whether a selection is being made
+func (m Model) visual() bool {
+	return m.mode == Visual || m.mode == VisualLine
+}
+
+// applyVisual runs an operator on the selection
+func (m *Model) applyVisual(op string) {
+	start, end := m.anchor, m.cursor
+	lines := m.mode == VisualLine
+	m.mode = Normal
+	if end.before(start) {
+		start, end = end, start
+	}
+	m.cursor = start
+	m.apply(op, start, end, lines, true)
+}
+
+// apply runs an operator from the cursor over a range. Whole lines are taken
+// when lines is set; otherwise the range ends before to, or at it when
+// inclusive.
+func (m *Model) apply(op string, from, to pos, lines, inclusive bool) {
+	if to.before(from) {
+		from, to = to, from
+	}
+	if lines {
+		m.applyLines(op, from.row, to.row)
+		return
+	}
+	if inclusive {
+		to.col = min(to.col+1, len(m.lines[to.row]))
+	}
+	if from == to {
+		return
+	}
+	m.register, m.linewise = m.text(from, to), false
+	switch op {
+	case "y":
+		m.cursor = from
+	case "d", "c":
+		m.checkpoint()
+		m.delete(from, to)
+		m.cursor = from
+		if op == "c" {
+			m.mode = Insert
+		}
+	}
+	m.wantCol = m.cursor.col
+}
+
+// applyLines runs an operator on the lines from first to last
+func (m *Model) applyLines(op string, first, last int) {
+	var text []rune
+	for row := first; row <= last; row++ {
+		text = append(text, m.lines[row]...)
+		if row < last {
+			text = append(text, '\n')
+		}
+	}
+	m.register, m.linewise = text, true
+
+	switch op {
+	case "y":
+		m.cursor.row = first
+	case "d":
+		m.checkpoint()
+		m.lines = append(m.lines[:first], m.lines[last+1:]...)
+		if len(m.lines) == 0 {
+			m.lines = [][]rune{{}}
+		}
+		m.cursor.row = min(first, len(m.lines)-1)
+		m.cursor.col = firstNonBlank(m.lines[m.cursor.row])
+	case "c":
+		m.checkpoint()
+		indent := leadingSpace(m.lines[first])
+		m.lines = append(m.lines[:first+1], m.lines[last+1:]...)
+		m.lines[first] = indent
+		m.cursor = pos{first, len(indent)}
+		m.mode = Insert
+	}
+	m.wantCol = m.cursor.col
+}
+
+// text returns the text from from up to to
+func (m Model) text(from, to pos) []rune {
+	if from.row == to.row {
+		return append([]rune(nil), m.lines[from.row][from.col:to.col]...)
+	}
+	text := append([]rune(nil), m.lines[from.row][from.col:]...)
+	for row := from.row + 1; row < to.row; row++ {
+		text = append(append(text, '\n'), m.lines[row]...)
+	}
+	return append(append(text, '\n'), m.lines[to.row][:to.col]...)
+}
+
+// delete removes the text from from up to to
+func (m *Model) delete(from, to pos) {
+	joined := append(append([]rune(nil), m.lines[from.row][:from.col]...), m.lines[to.row][to.col:]...)
+	m.lines = append(m.lines[:from.row+1], m.lines[to.row+1:]...)
+	m.lines[from.row] = joined
+}
+
+// insertText puts text, which may hold newlines, at p and returns where it
+// ends
+func (m *Model) insertText(p pos, text []rune) pos {
+	line := m.lines[p.row]
+	tail := append([]rune(nil), line[p.col:]...)
+	parts := strings.Split(string(text), "\n")
+
+	m.lines[p.row] = append(line[:p.col:p.col], []rune(parts[0])...)
+	var added [][]rune
+	for _, part := range parts[1:] {
+		added = append(added, []rune(part))
+	}
+	m.insertLines(p.row+1, added)
+
+	end := pos{p.row + len(parts) - 1, len(m.lines[p.row+len(parts)-1])}
+	m.lines[end.row] = append(m.lines[end.row], tail...)
+	return end
+}
+
+// insertLines inserts whole lines before row
+func (m *Model) insertLines(row int, lines [][]rune) {
+	rest := append([][]rune(nil), m.lines[row:]...)
+	m.lines = append(append(m.lines[:row], lines...), rest...)
+}
+
+// put pastes the register n times, after the cursor or before it
+func (m *Model) put(before bool, n int) {
+	if len(m.register) == 0 {
+		return
+	}
+	m.checkpoint()
+	if m.linewise {
+		var lines [][]rune
+		for i := 0; i < n; i++ {
+			for _, line := range strings.Split(string(m.register), "\n") {
+				lines = append(lines, []rune(line))
+			}
+		}
+		row := m.cursor.row
+		if !before {
+			row++
+		}
+		m.insertLines(row, lines)
+		m.cursor = pos{row, firstNonBlank(m.lines[row])}
+		return
+	}
+
+	p := m.cursor
+	if !before && len(m.lines[p.row]) > 0 {
+		p.col++
+	}
+	var text []rune
+	for i := 0; i < n; i++ {
+		text = append(text, m.register...)
+	}
+	end := m.insertText(p, text)
+	m.cursor = pos{end.row, max(end.col-1, 0)}
+}
+
+// join joins n lines from the cursor's, with a space between each
+func (m *Model) join(n int) {
+	row := m.cursor.row
+	if row == len(m.lines)-1 {
+		return
+	}
+	m.checkpoint()
+	for i := 1; i < n && row < len(m.lines)-1; i++ {
+		line := []rune(strings.TrimRight(string(m.lines[row]), " \t"))
+		next := []rune(strings.TrimLeft(string(m.lines[row+1]), " \t"))
+		m.cursor.col = len(line)
+		if len(line) > 0 && len(next) > 0 {
+			line = append(line, ' ')
+		}
+		m.lines[row] = append(line, next...)
+		m.lines = append(m.lines[:row+1], m.lines[row+2:]...)
+	}
+}
+
+// startInsert switches to insert mode. The whole insert is one change for
+// undo, and is dropped from the history if nothing is typed.
+func (m *Model) startInsert() {
+	m.checkpoint()
+	m.mode = Insert
+}
+
+// insertKey handles a key in insert mode
+func (m *Model) insertKey(msg tea.KeyMsg) {
+	line := m.lines[m.cursor.row]
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.mode = Normal
+		m.cursor.col = max(m.cursor.col-1, 0)
+		if last := len(m.undo) - 1; last >= 0 && equal(m.undo[last].lines, m.lines) {
+			m.undo = m.undo[:last]
+		}
+	case "enter":
+		indent := leadingSpace(line)
+		m.cursor = m.insertText(m.cursor, append([]rune{'\n'}, indent...))
+	case "backspace":
+		if m.cursor.col > 0 {
+			m.delete(pos{m.cursor.row, m.cursor.col - 1}, m.cursor)
+			m.cursor.col--
+		} else if m.cursor.row > 0 {
+			prev := pos{m.cursor.row - 1, len(m.lines[m.cursor.row-1])}
+			m.delete(prev, m.cursor)
+			m.cursor = prev
+		}
+	case "delete":
+		if m.cursor.col < len(line) {
+			m.delete(m.cursor, pos{m.cursor.row, m.cursor.col + 1})
+		} else if m.cursor.row < len(m.lines)-1 {
+			m.delete(m.cursor, pos{m.cursor.row + 1, 0})
+		}
+	case "left":
+		m.cursor.col = max(m.cursor.col-1, 0)
+	case "right":
+		m.cursor.col = min(m.cursor.col+1, len(line))
+	case "up", "down":
+		if msg.String() == "up" {
+			m.cursor.row = max(m.cursor.row-1, 0)
+		} else {
+			m.cursor.row = min(m.cursor.row+1, len(m.lines)-1)
+		}
+		m.cursor.col = min(m.wantCol, len(m.lines[m.cursor.row]))
+		return
+	case "home":
+		m.cursor.col = 0
+	case "end":
+		m.cursor.col = len(line)
+	case "tab":
+		m.cursor = m.insertText(m.cursor, []rune{'\t'})
+	case " ":
+		m.cursor = m.insertText(m.cursor, []rune{' '})
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.cursor = m.insertText(m.cursor, msg.Runes)
+		}
+	}
+	m.wantCol = m.cursor.col
+}
+
+// commandKey handles a key while a : command is typed
+func (m *Model) commandKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.mode = Normal
+	case "enter":
+		m.mode = Normal
+		return m.execute(strings.TrimSpace(m.cmdline))
+	case "backspace":
+		if m.cmdline == "" {
+			m.mode = Normal
+		} else {
+			r := []rune(m.cmdline)
+			m.cmdline = string(r[:len(r)-1])
+		}
+	case " ":
+		m.cmdline += " "
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.cmdline += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// execute runs a : command
+func (m *Model) execute(command string) tea.Cmd {
+	switch command {
+	case "":
+		return nil
+	case "w":
+		return saveCmd(m.Value(), false)
+	case "wq", "x":
+		return saveCmd(m.Value(), true)
+	case "q":
+		if m.Dirty() {
+			m.message = "No write since last change (add ! to override)"
+			return nil
+		}
+		return closeCmd
+	case "q!":
+		return closeCmd
+	}
+	if line, err := strconv.Atoi(command); err == nil {
+		m.cursor.row = min(max(line, 1), len(m.lines)) - 1
+		m.cursor.col = firstNonBlank(m.lines[m.cursor.row])
+		return nil
+	}
+	m.message = fmt.Sprintf("Not an editor command: %s", command)
+	return nil
+}
+
+func saveCmd(code string, close bool) tea.Cmd {
+	return func() tea.Msg {
+		return SaveMsg{Code: code, Close: close}
+	}
+}
+
+func closeCmd() tea.Msg {
+	return CloseMsg{}
+}
+
+// checkpoint records the buffer before a change, for undo
+func (m *Model) checkpoint() {
+	m.undo = append(m.undo, m.snapshot())
+	if len(m.undo) > undoLimit {
+		m.undo = m.undo[1:]
+	}
+	m.redo = nil
+}
+
+func (m Model) snapshot() snapshot {
+	lines := make([][]rune, len(m.lines))
+	for i, line := range m.lines {
+		lines[i] = append([]rune(nil), line...)
+	}
+	return snapshot{lines: lines, cursor: m.cursor}
+}
+
+func (m *Model) undoChange() {
+	if len(m.undo) == 0 {
+		m.message = "Already at oldest change"
+		return
+	}
+	m.redo = append(m.redo, m.snapshot())
+	m.restore(m.undo[len(m.undo)-1])
+	m.undo = m.undo[:len(m.undo)-1]
+}
+
+func (m *Model) redoChange() {
+	if len(m.redo) == 0 {
+		m.message = "Already at newest change"
+		return
+	}
+	m.undo = append(m.undo, m.snapshot())
+	m.restore(m.redo[len(m.redo)-1])
+	m.redo = m.redo[:len(m.redo)-1]
+}
+
+func (m *Model) restore(s snapshot) {
+	m.lines = s.lines
+	m.cursor = s.cursor
+}
+
+// Word motions. Letters, digits and underscores make words, other
+// non-blank runes make words of their own, and an empty line is a word.
+
+// runeClass returns 0 for blanks, 1 for word runes and 2 for the rest
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// at returns the rune at p, reporting false at the end of a line
+func (m Model) at(p pos) (rune, bool) {
+	if p.col >= len(m.lines[p.row]) {
+		return 0, false
+	}
+	return m.lines[p.row][p.col], true
+}
+
+// next returns the place after p, crossing into the next line
+func (m Model) next(p pos) (pos, bool) {
+	if p.col < len(m.lines[p.row])-1 {
+		return pos{p.row, p.col + 1}, true
+	}
+	if p.row < len(m.lines)-1 {
+		return pos{p.row + 1, 0}, true
+	}
+	return p, false
+}
+
+// prev returns the place before p, crossing into the previous line
+func (m Model) prev(p pos) (pos, bool) {
+	if p.col > 0 {
+		return pos{p.row, min(p.col-1, max(len(m.lines[p.row])-1, 0))}, true
+	}
+	if p.row > 0 {
+		return pos{p.row - 1, max(len(m.lines[p.row-1])-1, 0)}, true
+	}
+	return p, false
+}
+
+// blankAt reports whether p is a blank, counting the end of a non-empty
+// line as one
+func (m Model) blankAt(p pos) bool {
+	r, ok := m.at(p)
+	if !ok {
+		return len(m.lines[p.row]) > 0
+	}
+	return runeClass(r) == 0
+}
+
+// wordForward returns the start of the next word, w
+func (m Model) wordForward(p pos) pos {
+	if r, ok := m.at(p); ok && runeClass(r) != 0 {
+		class := runeClass(r)
+		for {
+			if p.col+1 >= len(m.lines[p.row]) {
+				p.col = len(m.lines[p.row])
+				break
+			}
+			p.col++
+			if runeClass(m.lines[p.row][p.col]) != class {
+				break
+			}
+		}
+	}
+	for m.blankAt(p) {
+		if p.col >= len(m.lines[p.row]) {
+			if p.row == len(m.lines)-1 {
+				return p
+			}
+			p = pos{p.row + 1, 0}
+			continue
+		}
+		p.col++
+	}
+	return p
+}
+
+// wordBackward returns the start of the word before p, b
+func (m Model) wordBackward(p pos) pos {
+	var ok bool
+	if p, ok = m.prev(p); !ok {
+		return p
+	}
+	for m.blankAt(p) {
+		if p, ok = m.prev(p); !ok {
+			return p
+		}
+	}
+	r, _ := m.at(p)
+	class := runeClass(r)
+	for p.col > 0 && runeClass(m.lines[p.row][p.col-1]) == class {
+		p.col--
+	}
+	return p
+}
+
+// wordEnd returns the end of the word after p, e
+func (m Model) wordEnd(p pos) pos {
+	var ok bool
+	if p, ok = m.next(p); !ok {
+		return p
+	}
+	for m.blankAt(p) || len(m.lines[p.row]) == 0 {
+		if p, ok = m.next(p); !ok {
+			return p
+		}
+	}
+	r, _ := m.at(p)
+	class := runeClass(r)
+	for p.col+1 < len(m.lines[p.row]) && runeClass(m.lines[p.row][p.col+1]) == class {
+		p.col++
+	}
+	return p
+}
+
+func firstNonBlank(line []rune) int {
+	return len(leadingSpace(line))
+}
+
+// leadingSpace returns a line's indentation
+func leadingSpace(line []rune) []rune {
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return append([]rune(nil), line[:i]...)
+}
+
+func equal(a, b [][]rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if string(a[i]) != string(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// clamp keeps the cursor in the buffer. Outside insert mode it rests on a
+// rune rather than after the last one.
+func (m *Model) clamp() {
+	if len(m.lines) == 0 {
+		m.lines = [][]rune{{}}
+	}
+	m.cursor.row = min(max(m.cursor.row, 0), len(m.lines)-1)
+	last := len(m.lines[m.cursor.row])
+	if m.mode != Insert && last > 0 {
+		last--
+	}
+	m.cursor.col = min(max(m.cursor.col, 0), last)
+	if m.visual() {
+		m.anchor.row = min(m.anchor.row, len(m.lines)-1)
+	}
+}
+
+// Drawing
+
+var (
+	gutterStyle    lipgloss.Style
+	cursorStyle    = lipgloss.NewStyle().Reverse(true)
+	selectionStyle lipgloss.Style
+	modeStyle      lipgloss.Style
+	messageStyle   lipgloss.Style
+)
+
+func init() {
+	theme.OnChange(func(t theme.Theme) {
+		gutterStyle = lipgloss.NewStyle().Foreground(t.Muted)
+		selectionStyle = lipgloss.NewStyle().Background(t.Border)
+		modeStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Primary)
+		messageStyle = lipgloss.NewStyle().Foreground(t.Warning)
+	})
+}
+
+// textHeight is how many lines of code are shown above the status line
+func (m Model) textHeight() int {
+	return max(m.height-1, 1)
+}
+
+// gutterWidth is the width of the line numbers and the space after them
+func (m Model) gutterWidth() int {
+	return len(strconv.Itoa(len(m.lines))) + 1
+}
+
+// displayCol returns the column a rune in a line is drawn at, with tabs
+// expanded
+func displayCol(line []rune, col int) int {
+	width := 0
+	for i := 0; i < col && i < len(line); i++ {
+		if line[i] == '\t' {
+			width += tabWidth
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// scroll keeps the cursor on screen
+func (m *Model) scroll() {
+	height := m.textHeight()
+	if m.cursor.row < m.top {
+		m.top = m.cursor.row
+	} else if m.cursor.row >= m.top+height {
+		m.top = m.cursor.row - height + 1
+	}
+
+	width := max(m.width-m.gutterWidth(), 1)
+	col := displayCol(m.lines[m.cursor.row], m.cursor.col)
+	if col < m.left {
+		m.left = col
+	} else if col >= m.left+width {
+		m.left = col - width + 1
+	}
+}
+
+// selected reports whether a rune is in the visual selection
+func (m Model) selected(p pos) bool {
+	if !m.visual() {
+		return false
+	}
+	start, end := m.anchor, m.cursor
+	if end.before(start) {
+		start, end = end, start
+	}
+	if m.mode == VisualLine {
+		return p.row >= start.row && p.row <= end.row
+	}
+	return !p.before(start) && !end.before(p)
+}
+
+// View draws the code with line numbers, and a status line
+func (m Model) View() string {
+	var b strings.Builder
+	gutter := m.gutterWidth()
+	width := max(m.width-gutter, 1)
+
+	for row := m.top; row < m.top+m.textHeight(); row++ {
+		if row >= len(m.lines) {
+			b.WriteString(gutterStyle.Render(fmt.Sprintf("%*s", gutter-1, "~")))
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(gutterStyle.Render(fmt.Sprintf("%*d ", gutter-1, row+1)))
+		b.WriteString(m.renderLine(row, width))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.statusLine())
+	return b.String()
+}
+
+// renderLine draws the part of a line that fits in width columns
+func (m Model) renderLine(row, width int) string {
+	var b strings.Builder
+	line := m.lines[row]
+	col := 0
+	for i := 0; i <= len(line); i++ {
+		cell := " "
+		cells := 1
+		if i < len(line) {
+			cell = string(line[i])
+			if line[i] == '\t' {
+				cell, cells = strings.Repeat(" ", tabWidth), tabWidth
+			}
+		} else if m.cursor != (pos{row, i}) {
+			break
+		}
+		if col >= m.left && col+cells <= m.left+width {
+			p := pos{row, i}
+			switch {
+			case p == m.cursor:
+				b.WriteString(cursorStyle.Render(cell))
+			case i < len(line) && m.selected(p):
+				b.WriteString(selectionStyle.Render(cell))
+			default:
+				b.WriteString(cell)
+			}
+		}
+		col += cells
+	}
+	return b.String()
+}
+
+// statusLine shows the mode, the : command or a message, and where the
+// cursor is
+func (m Model) statusLine() string {
+	left := modeStyle.Render("-- " + m.mode.String() + " --")
+	switch {
+	case m.mode == Command:
+		left = ":" + m.cmdline
+	case m.message != "":
+		left += " " + messageStyle.Render(m.message)
+	case len(m.pending) > 0:
+		left += " " + strings.Join(m.pending, "")
+	}
+
+	right := fmt.Sprintf("%d:%d", m.cursor.row+1, m.cursor.col+1)
+	if m.Dirty() {
+		right = "[+] " + right
+	}
+	padding := max(m.width-lipgloss.Width(left)-lipgloss.Width(right), 1)
+	return left + strings.Repeat(" ", padding) + right
+}
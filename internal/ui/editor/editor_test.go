@@ -0,0 +1,177 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// specialKeys are the keys tests name rather than type
+var specialKeys = map[string]tea.KeyType{
+	"esc":       tea.KeyEsc,
+	"enter":     tea.KeyEnter,
+	"backspace": tea.KeyBackspace,
+	"tab":       tea.KeyTab,
+	"ctrl+r":    tea.KeyCtrlR,
+	" ":         tea.KeySpace,
+}
+
+// press sends keys to the editor one at a time. Names in specialKeys are
+// pressed as themselves; anything else is typed a rune at a time.
+func press(t *testing.T, m Model, keys ...string) (Model, []tea.Msg) {
+	t.Helper()
+	var msgs []tea.Msg
+	send := func(msg tea.KeyMsg) {
+		var cmd tea.Cmd
+		m, cmd = m.Update(msg)
+		if cmd != nil {
+			msgs = append(msgs, cmd())
+		}
+	}
+	for _, key := range keys {
+		if keyType, ok := specialKeys[key]; ok {
+			send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		for _, r := range key {
+			if r == ' ' {
+				send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+			} else {
+				send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+		}
+	}
+	return m, msgs
+}
+
+func TestInsertMode(t *testing.T) {
+	m := New("")
+	assert.Equal(t, Normal, m.Mode())
+
+	m, _ = press(t, m, "i", "func f() {", "enter", "tab", "return 1", "enter", "esc")
+	assert.Equal(t, Normal, m.Mode())
+	assert.Equal(t, "func f() {\n\treturn 1\n\t", m.Value(), "new lines keep the indentation")
+
+	m, _ = press(t, m, "A", "backspace", "backspace", "}", "esc")
+	assert.Equal(t, "func f() {\n\treturn 1}", m.Value(), "backspace joins lines")
+	assert.True(t, m.Dirty())
+}
+
+func TestMotionsAndOperators(t *testing.T) {
+	code := "one two three\nfour five\nsix"
+	tests := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{"DeleteWord", []string{"dw"}, "two three\nfour five\nsix"},
+		{"DeleteWordsWithCount", []string{"2dw"}, "three\nfour five\nsix"},
+		{"DeleteWordAtEndOfLine", []string{"wwdw"}, "one two \nfour five\nsix"},
+		{"ChangeWord", []string{"wcw", "2", "esc"}, "one 2 three\nfour five\nsix"},
+		{"DeleteToEnd", []string{"wD"}, "one \nfour five\nsix"},
+		{"DeleteLines", []string{"jdd"}, "one two three\nsix"},
+		{"DeleteDown", []string{"dj"}, "six"},
+		{"DeleteToLastLine", []string{"jdG"}, "one two three"},
+		{"DeleteChars", []string{"3x"}, " two three\nfour five\nsix"},
+		{"ChangeLine", []string{"jcc", "4 5", "esc"}, "one two three\n4 5\nsix"},
+		{"ReplaceChar", []string{"$rE"}, "one two threE\nfour five\nsix"},
+		{"JoinLines", []string{"J"}, "one two three four five\nsix"},
+		{"WordEnd", []string{"de"}, " two three\nfour five\nsix"},
+		{"WordBackAcrossLines", []string{"jbD"}, "one two \nfour five\nsix"},
+		{"OpenBelow", []string{"o", "zero", "esc"}, "one two three\nzero\nfour five\nsix"},
+		{"GotoLine", []string{"3Gx"}, "one two three\nfour five\nix"},
+		{"GotoFirstLine", []string{"Ggg", "x"}, "ne two three\nfour five\nsix"},
+		{"YankAndPutLine", []string{"yyjp"}, "one two three\nfour five\none two three\nsix"},
+		{"YankAndPutWord", []string{"yw$p"}, "one two threeone \nfour five\nsix"},
+		{"DeleteAndPutBefore", []string{"jddkP"}, "four five\none two three\nsix"},
+		{"VisualDelete", []string{"wvld"}, "one o three\nfour five\nsix"},
+		{"VisualLineYank", []string{"Vjy", "Gp"}, "one two three\nfour five\nsix\none two three\nfour five"},
+		{"VisualChange", []string{"vec", "1", "esc"}, "1 two three\nfour five\nsix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := press(t, New(code), tt.keys...)
+			assert.Equal(t, tt.want, m.Value())
+			assert.Equal(t, Normal, m.Mode())
+		})
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	m := New("a\nb")
+	m, _ = press(t, m, "dd", "i", "x", "y", "esc", "i", "esc")
+	assert.Equal(t, "xyb", m.Value())
+
+	// Typing in one insert is one change, and an empty insert is none
+	m, _ = press(t, m, "u")
+	assert.Equal(t, "b", m.Value())
+	m, _ = press(t, m, "u")
+	assert.Equal(t, "a\nb", m.Value())
+	m, _ = press(t, m, "u")
+	assert.Equal(t, "a\nb", m.Value())
+	assert.Contains(t, m.View(), "Already at oldest change")
+
+	m, _ = press(t, m, "ctrl+r", "ctrl+r")
+	assert.Equal(t, "xyb", m.Value())
+
+	// A new change drops what could be redone
+	m, _ = press(t, m, "u", "x", "ctrl+r")
+	assert.Equal(t, "", m.Value())
+}
+
+func TestCommands(t *testing.T) {
+	m := New("x := 1")
+
+	_, msgs := press(t, m, ":w", "enter")
+	assert.Equal(t, []tea.Msg{SaveMsg{Code: "x := 1"}}, msgs)
+
+	m, _ = press(t, m, "x")
+	_, msgs = press(t, m, ":q", "enter")
+	assert.Empty(t, msgs, "unsaved changes are not thrown away")
+	m, msgs = press(t, m, ":q!", "enter")
+	assert.Equal(t, []tea.Msg{CloseMsg{}}, msgs)
+
+	_, msgs = press(t, m, ":wq", "enter")
+	assert.Equal(t, []tea.Msg{SaveMsg{Code: " := 1", Close: true}}, msgs)
+
+	m.MarkSaved()
+	assert.False(t, m.Dirty())
+	_, msgs = press(t, m, ":q", "enter")
+	assert.Equal(t, []tea.Msg{CloseMsg{}}, msgs)
+
+	m, msgs = press(t, m, ":nope", "enter")
+	assert.Empty(t, msgs)
+	assert.Contains(t, m.View(), "Not an editor command: nope")
+
+	// Esc leaves a command unrun
+	m, msgs = press(t, m, ":q!", "esc")
+	assert.Empty(t, msgs)
+	assert.Equal(t, Normal, m.Mode())
+}
+
+func TestView(t *testing.T) {
+	m := New("func main() {\n\tfmt.Println(\"hi\")\n}")
+	m.SetSize(40, 5)
+
+	lines := strings.Split(m.View(), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "1 func main() {", lines[0])
+	assert.Equal(t, "2     fmt.Println(\"hi\")", lines[1], "tabs are expanded")
+	assert.Equal(t, "~", lines[3])
+	assert.Contains(t, lines[4], "-- NORMAL --")
+	assert.Contains(t, lines[4], "1:1")
+
+	m, _ = press(t, m, "i", "x")
+	assert.Contains(t, m.View(), "-- INSERT --")
+	assert.Contains(t, m.View(), "[+]")
+
+	// Long files scroll to keep the cursor in view
+	m = New(strings.Repeat("line\n", 20) + "last")
+	m.SetSize(40, 5)
+	m, _ = press(t, m, "G")
+	assert.Contains(t, m.View(), "21 last")
+	assert.NotContains(t, m.View(), " 1 line")
+}
@@ -0,0 +1,259 @@
+// Package finder lets the user find a problem in the terminal by typing
+// parts of its name, pattern or company, narrowing the list as they type
+package finder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// shown is how many matches the finder lists at once
+const shown = 10
+
+// runProgram runs a Bubble Tea program
+// Exported as variable for testing
+var runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	return tea.NewProgram(m, opts...).Run()
+}
+
+// Run shows the finder over problems, starting from query, and returns the
+// ID of the problem chosen, or "" if the user cancelled
+func Run(problems []problem.Problem, query string) (string, error) {
+	final, err := runProgram(New(problems, query))
+	if err != nil {
+		return "", fmt.Errorf("error running problem finder: %v", err)
+	}
+	return final.(Model).Chosen(), nil
+}
+
+// Model narrows a list of problems to those matching what has been typed
+type Model struct {
+	problems []problem.Problem
+	query    string
+	matches  []problem.Problem
+	selected int
+	chosen   string
+	quit     bool
+}
+
+// New creates a finder over problems, starting from query
+func New(problems []problem.Problem, query string) Model {
+	m := Model{problems: problems, query: query}
+	m.matches = Filter(problems, query)
+	return m
+}
+
+// Chosen returns the ID of the problem the user chose, or "" if they
+// cancelled
+func (m Model) Chosen() string {
+	return m.chosen
+}
+
+// Matches returns the problems matching what has been typed, best first
+func (m Model) Matches() []problem.Problem {
+	return m.matches
+}
+
+// Init implements tea.Model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.Type {
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.selected > 0 {
+			m.selected--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.selected < len(m.matches)-1 {
+			m.selected++
+		}
+	case tea.KeyEnter:
+		if len(m.matches) == 0 {
+			return m, nil
+		}
+		m.chosen = m.matches[m.selected].ID
+		m.quit = true
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.quit = true
+		return m, tea.Quit
+	case tea.KeyBackspace:
+		if m.query != "" {
+			runes := []rune(m.query)
+			m.setQuery(string(runes[:len(runes)-1]))
+		}
+	case tea.KeyCtrlU:
+		m.setQuery("")
+	case tea.KeyRunes, tea.KeySpace:
+		m.setQuery(m.query + string(key.Runes))
+	}
+	return m, nil
+}
+
+// setQuery narrows the matches to a new query, selecting the best
+func (m *Model) setQuery(query string) {
+	m.query = query
+	m.matches = Filter(m.problems, query)
+	m.selected = 0
+}
+
+// View implements tea.Model
+func (m Model) View() string {
+	if m.quit {
+		return ""
+	}
+	t := theme.Current()
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(t.Primary).Render("Find a problem"))
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(t.Secondary).Render("> ") + m.query + "█\n\n")
+
+	if len(m.matches) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(t.Muted).Render("No problems match."))
+		b.WriteString("\n")
+	}
+	// Keep the selection in view as it moves down the list
+	first := max(0, m.selected-shown+1)
+	for i := first; i < len(m.matches) && i < first+shown; i++ {
+		p := m.matches[i]
+		line := fmt.Sprintf("%s (%s, %s)", p.Title, p.Difficulty, strings.Join(p.Patterns, ", "))
+		if i == m.selected {
+			b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(t.Secondary).Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\n%s", lipgloss.NewStyle().Foreground(t.Muted).Render(
+		fmt.Sprintf("%d of %d • ↑/↓: Choose • Enter: Select • Esc: Cancel", len(m.matches), len(m.problems))))
+	return b.String()
+}
+
+// Field bonuses: a word matching a problem's title ranks it above one
+// matching its patterns, and that above one matching its companies
+const (
+	titleBonus   = 6
+	patternBonus = 3
+	companyBonus = 0
+)
+
+// Filter returns the problems whose title, ID, patterns or companies hold
+// every word of query as a subsequence, best matches first. An empty query
+// keeps them all in order.
+func Filter(problems []problem.Problem, query string) []problem.Problem {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return problems
+	}
+
+	type scored struct {
+		problem problem.Problem
+		score   int
+	}
+	var found []scored
+	for _, p := range problems {
+		total := 0
+		for _, word := range words {
+			score, ok := bestField(p, word)
+			if !ok {
+				total = -1
+				break
+			}
+			total += score
+		}
+		if total >= 0 {
+			found = append(found, scored{problem: p, score: total})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score > found[j].score
+	})
+
+	matches := make([]problem.Problem, len(found))
+	for i, f := range found {
+		matches[i] = f.problem
+	}
+	return matches
+}
+
+// bestField scores a word against the field of a problem it matches best,
+// counting what kind of field that is
+func bestField(p problem.Problem, word string) (int, bool) {
+	fields := []struct {
+		text  string
+		bonus int
+	}{
+		{p.Title, titleBonus},
+		{p.ID, titleBonus},
+		{strings.Join(p.Patterns, " "), patternBonus},
+		{strings.Join(p.Companies, " "), companyBonus},
+	}
+	best, found := 0, false
+	for _, f := range fields {
+		if score, ok := fuzzyScore(strings.ToLower(f.text), word); ok && (!found || score+f.bonus > best) {
+			best, found = score+f.bonus, true
+		}
+	}
+	return best, found
+}
+
+// fuzzyScore reports whether pattern's characters appear in text in order,
+// and how well: characters that follow one another or start a word score
+// more than ones scattered through it. Each place the pattern could start is
+// tried, keeping the best.
+func fuzzyScore(text, pattern string) (int, bool) {
+	runes, want := []rune(text), []rune(pattern)
+	if len(want) == 0 {
+		return 0, true
+	}
+	best, found := 0, false
+	for start := range runes {
+		if runes[start] != want[0] {
+			continue
+		}
+		if score, ok := scoreFrom(runes, want, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom matches want against runes from start, taking each character
+// at its first place after the one before
+func scoreFrom(runes, want []rune, start int) (int, bool) {
+	score, prev := 0, start-2
+	i := start
+	for _, r := range want {
+		for i < len(runes) && runes[i] != r {
+			i++
+		}
+		if i == len(runes) {
+			return 0, false
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 2
+		}
+		prev = i
+		i++
+	}
+	return score, true
+}
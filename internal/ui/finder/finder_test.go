@@ -0,0 +1,87 @@
+package finder
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var problems = []problem.Problem{
+	{ID: "two_sum", Title: "Two Sum", Difficulty: "easy", Patterns: []string{"hash-map"}},
+	{ID: "max_subarray", Title: "Maximum Subarray", Difficulty: "medium", Patterns: []string{"dynamic-programming"}},
+	{ID: "min_window", Title: "Minimum Window Substring", Difficulty: "hard", Patterns: []string{"sliding-window"}},
+}
+
+func ids(problems []problem.Problem) []string {
+	var ids []string
+	for _, p := range problems {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func press(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+	var model tea.Model = m
+	for _, key := range keys {
+		model, _ = model.Update(key)
+	}
+	return model.(Model)
+}
+
+func typed(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFilter(t *testing.T) {
+	assert.Equal(t, ids(problems), ids(Filter(problems, "")))
+	assert.Equal(t, "two_sum", Filter(problems, "sum")[0].ID, "a run of characters beats scattered ones")
+	assert.Equal(t, []string{"max_subarray", "min_window"}, ids(Filter(problems, "sub")), "ties keep their order")
+	assert.Equal(t, "min_window", Filter(problems, "mws")[0].ID, "the starts of words count most")
+	assert.Equal(t, []string{"min_window"}, ids(Filter(problems, "min sliding")), "every word must match")
+	assert.Empty(t, Filter(problems, "zz"))
+
+	ranked := []problem.Problem{
+		{ID: "a", Title: "Alpha", Companies: []string{"Heap Labs"}},
+		{ID: "b", Title: "Beta", Patterns: []string{"heap"}},
+		{ID: "c", Title: "Heap Sort"},
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, ids(Filter(ranked, "heap")), "titles rank above patterns, and patterns above companies")
+}
+
+func TestFinder(t *testing.T) {
+	m := New(problems, "")
+	assert.Len(t, m.Matches(), 3)
+
+	m = press(t, m, typed("s"), typed("u"), typed("b"))
+	assert.Equal(t, []string{"max_subarray", "min_window"}, ids(m.Matches()))
+	assert.Contains(t, m.View(), "> Maximum Subarray")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Contains(t, m.View(), "> Minimum Window Substring", "down stops at the last match")
+
+	// Typing more narrows and selects the best match again
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, typed("two"))
+	assert.Equal(t, "two_sum", m.Matches()[0].ID)
+	assert.Equal(t, "two_sum", press(t, m, tea.KeyMsg{Type: tea.KeyEnter}).Chosen())
+
+	assert.Empty(t, press(t, m, tea.KeyMsg{Type: tea.KeyEsc}).Chosen())
+	none := press(t, m, typed("zz"), tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Empty(t, none.Chosen(), "enter with nothing matching does nothing")
+	assert.Contains(t, none.View(), "No problems match.")
+}
+
+func TestRun(t *testing.T) {
+	original := runProgram
+	t.Cleanup(func() { runProgram = original })
+	runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+		return press(t, m.(Model), tea.KeyMsg{Type: tea.KeyEnter}), nil
+	}
+
+	chosen, err := Run(problems, "window")
+	require.NoError(t, err)
+	assert.Equal(t, "min_window", chosen)
+}
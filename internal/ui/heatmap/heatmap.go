@@ -0,0 +1,124 @@
+// Package heatmap draws the practice calendar as a GitHub-style grid of
+// weeks by weekday, shaded by how much was practiced each day. It draws
+// with lipgloss alone, so the CLI and the lite build can use it as well as
+// the TUI.
+package heatmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// shades are the cells of each calendar level, lightest first. Their
+// glyphs differ as well as their colors, so the calendar reads without
+// color too.
+var shades = [stats.CalendarLevels]symbols.Symbol{
+	{Unicode: "·", ASCII: "."},
+	{Unicode: "░", ASCII: "-"},
+	{Unicode: "▒", ASCII: "+"},
+	{Unicode: "▓", ASCII: "*"},
+	{Unicode: "█", ASCII: "#"},
+}
+
+// weekdayLabels label the rows, every other day as on GitHub
+var weekdayLabels = [7]string{"", "Mon", "", "Wed", "", "Fri", ""}
+
+// labelWidth is the width of the weekday labels column
+const labelWidth = 4
+
+// Render draws the calendar: month names over the weeks, a row per
+// weekday from Sunday, and a legend of the shades
+func Render(cal stats.Calendar) string {
+	t := theme.Current()
+	empty := lipgloss.NewStyle().Foreground(t.Border)
+	filled := lipgloss.NewStyle().Foreground(t.Success)
+	label := lipgloss.NewStyle().Foreground(t.Muted)
+
+	cell := func(level int) string {
+		if level == 0 {
+			return empty.Render(shades[0].String())
+		}
+		return filled.Render(shades[level].String())
+	}
+
+	var b strings.Builder
+	b.WriteString(label.Render(monthLabels(cal)) + "\n")
+
+	weeks := (len(cal.Days) + 6) / 7
+	for weekday := 0; weekday < 7; weekday++ {
+		row := []string{label.Render(fmt.Sprintf("%-*s", labelWidth, weekdayLabels[weekday]))}
+		for week := 0; week < weeks; week++ {
+			i := week*7 + weekday
+			if i >= len(cal.Days) {
+				break
+			}
+			row = append(row, cell(cal.Level(cal.Days[i])))
+		}
+		b.WriteString(strings.Join(row, "") + "\n")
+	}
+
+	legend := []string{label.Render(strings.Repeat(" ", labelWidth) + "Less")}
+	for level := range shades {
+		legend = append(legend, cell(level))
+	}
+	legend = append(legend, label.Render("More"))
+	b.WriteString(strings.Join(legend, " "))
+	return b.String()
+}
+
+// monthLabels names each month over the week it starts in, skipping names
+// that would run into the one before. The current month's name may run
+// past the last week.
+func monthLabels(cal stats.Calendar) string {
+	line := []byte(strings.Repeat(" ", labelWidth+(len(cal.Days)+6)/7+len("Jan")))
+	next := 0
+	for i := 0; i < len(cal.Days); i += 7 {
+		first, err := time.Parse("2006-01-02", cal.Days[i].Date)
+		if err != nil {
+			continue
+		}
+		// Name the month in the week holding its first day
+		for d := 0; d < 7; d++ {
+			day := first.AddDate(0, 0, d)
+			if day.Day() != 1 {
+				continue
+			}
+			col := labelWidth + i/7
+			name := day.Format("Jan")
+			if col >= next && col+len(name) <= len(line) {
+				copy(line[col:], name)
+				next = col + len(name) + 1
+			}
+			break
+		}
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// Summary describes the calendar in one line, for screen readers and for
+// the top of the calendar
+func Summary(cal stats.Calendar) string {
+	if cal.Total == 0 {
+		return "No problems practiced in the past year"
+	}
+	summary := fmt.Sprintf("%d %s practiced on %d %s in the past year",
+		cal.Total, plural(cal.Total, "problem"), cal.ActiveDays, plural(cal.ActiveDays, "day"))
+	if busiest, err := time.Parse("2006-01-02", cal.Busiest.Date); err == nil {
+		summary += fmt.Sprintf(", most on %s (%d)", busiest.Format("Jan 2"), cal.Busiest.Attempted)
+	}
+	return summary
+}
+
+// plural returns word, with an s unless n is one
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,56 @@
+package heatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// calendar builds a calendar ending on a Wednesday with a busy today
+func calendar() stats.Calendar {
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	var sessions []interfaces.SessionStats
+	for i := 0; i < 4; i++ {
+		sessions = append(sessions, interfaces.SessionStats{ProblemID: "two_sum", StartTime: now.Add(-time.Hour)})
+	}
+	sessions = append(sessions, interfaces.SessionStats{ProblemID: "3sum", StartTime: now.AddDate(0, 0, -1)})
+	return stats.BuildCalendar(sessions, now)
+}
+
+func TestRender(t *testing.T) {
+	symbols.SetASCII(false)
+	lines := strings.Split(ansi.Strip(Render(calendar())), "\n")
+	require.Len(t, lines, 9, "months, seven weekdays and the legend")
+
+	assert.Contains(t, lines[0], "Mar")
+	assert.Contains(t, lines[0], "Jan")
+	assert.True(t, strings.HasPrefix(lines[2], "Mon "))
+	assert.Equal(t, labelWidth+stats.CalendarWeeks, len([]rune(lines[1])))
+	assert.True(t, strings.HasSuffix(lines[3], "░"), "yesterday, a quarter of the busiest day")
+	assert.True(t, strings.HasSuffix(lines[4], "█"), "today, the busiest day")
+	assert.Equal(t, labelWidth+stats.CalendarWeeks-1, len([]rune(lines[5])), "Thursday is still to come")
+	assert.Equal(t, "    Less · ░ ▒ ▓ █ More", lines[8])
+}
+
+func TestRenderASCII(t *testing.T) {
+	symbols.SetASCII(true)
+	defer symbols.SetASCII(false)
+
+	out := ansi.Strip(Render(calendar()))
+	assert.Contains(t, out, "Less . - + * # More")
+	for _, r := range out {
+		assert.Less(t, r, rune(128))
+	}
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "5 problems practiced on 2 days in the past year, most on Mar 4 (4)", Summary(calendar()))
+	assert.Equal(t, "No problems practiced in the past year", Summary(stats.Calendar{}))
+}
@@ -17,6 +17,10 @@ func (m Model) updateHome(msg tea.Msg) (Model, tea.Cmd) {
 		if cmdMsg := cmd(); cmdMsg != nil {
 			if selMsg, ok := cmdMsg.(SelectionChangedMsg); ok {
 				m = m.navigate(selMsg.State)
+				if selMsg.State == StateNotifications {
+					m.notifications.loading = true
+					return m, loadNotifications()
+				}
 				return m, nil
 			}
 		}
@@ -109,8 +109,8 @@ func DefaultKeyMap() KeyMap {
 		
 		// Session specific
 		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit code"),
+			key.WithKeys("e", "E"),
+			key.WithHelp("e/E", "edit code, in $EDITOR with E"),
 		),
 		Test: key.NewBinding(
 			key.WithKeys("t"),
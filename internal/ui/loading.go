@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
 )
 
 // LoadingScreen displays a loading animation with a message
@@ -45,6 +46,9 @@ func (l LoadingScreen) View() string {
 	if l.width == 0 || l.height == 0 {
 		return ""
 	}
+	if access.Enabled() {
+		return access.Field("Loading", l.message)
+	}
 
 	var b strings.Builder
 
@@ -4,7 +4,10 @@ import (
 	"time"
 	
 	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/goals"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
 	"github.com/lancekrogers/algo-scales/internal/stats"
 )
 
@@ -26,7 +29,10 @@ type problemsErrorMsg struct {
 }
 
 type statsLoadedMsg struct {
-	stats stats.Summary
+	stats    stats.Summary
+	calendar stats.Calendar
+	rotation []daily.RotationWeek
+	goals    []goals.Progress
 }
 
 type statsErrorMsg struct {
@@ -40,10 +46,10 @@ type startSessionMsg struct {
 
 type sessionStartedMsg struct {
 	sessionID string
+	problem   problem.Problem
+	clock     *clock.Clock
 }
 
-type sessionTickMsg struct{}
-
 type sessionCompletedMsg struct {
 	duration time.Duration
 	solved   bool
@@ -6,11 +6,22 @@ import (
 	"time"
 	
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
 	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/cram"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
 	"github.com/lancekrogers/algo-scales/internal/stats"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+	"github.com/lancekrogers/algo-scales/internal/ui/privacy"
+	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
 // New creates a new model instance
@@ -31,6 +42,7 @@ func New() Model {
 				"Start Practice Session", 
 				"Daily Scales",
 				"View Statistics",
+				"Notifications",
 				"Settings",
 			},
 		},
@@ -41,6 +53,7 @@ func New() Model {
 		stats:         statsModel{},
 		daily:         dailyModel{},
 		settings:      settingsModel{},
+		notifications: notificationsModel{},
 		keys:          globalKeyMap{
 			Quit: key.NewBinding(
 				key.WithKeys("ctrl+c"),
@@ -73,6 +86,7 @@ type Model struct {
 	stats         statsModel
 	daily         dailyModel
 	settings      settingsModel
+	notifications notificationsModel
 	
 	// Common data
 	config    config.UserConfig
@@ -98,6 +112,10 @@ type Model struct {
 type homeModel struct {
 	selectedOption int
 	options        []string
+	unread         int // Unread notifications shown as a badge
+	reminders      []stats.PatternRetention
+	paused         *resume.State // Session left in progress, to continue
+	message        string
 	width          int
 	height         int
 }
@@ -114,9 +132,30 @@ func (m homeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+
+	case problemsErrorMsg:
+		m.message = fmt.Sprintf("Could not start a refresher: %v", msg.err)
+		return m, nil
+
+	case sessionErrorMsg:
+		m.message = fmt.Sprintf("Could not resume the session: %v", msg.err)
+		return m, nil
 		
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "c":
+			// Continue the session left in progress
+			if m.paused != nil {
+				m.message = ""
+				return m, resumePausedSession(*m.paused)
+			}
+		case "r":
+			// Queue a refresher for the pattern that decays first
+			if len(m.reminders) > 0 {
+				m.message = ""
+				pattern := m.reminders[0].Pattern
+				return m, func() tea.Msg { return refresherRequestedMsg{pattern: pattern} }
+			}
 		case "up", "k":
 			if m.selectedOption > 0 {
 				m.selectedOption--
@@ -134,7 +173,9 @@ func (m homeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg { return SelectionChangedMsg{State: StateDaily} }
 			case 2: // View Statistics  
 				return m, func() tea.Msg { return SelectionChangedMsg{State: StateStats} }
-			case 3: // Settings
+			case 3: // Notifications
+				return m, func() tea.Msg { return SelectionChangedMsg{State: StateNotifications} }
+			case 4: // Settings
 				return m, func() tea.Msg { return SelectionChangedMsg{State: StateSettings} }
 			}
 		}
@@ -152,6 +193,9 @@ func (m homeModel) View() string {
 	
 	// Menu options
 	for i, option := range m.options {
+		if option == "Notifications" && m.unread > 0 {
+			option = fmt.Sprintf("%s %s", option, badgeStyle.Render(fmt.Sprintf(" %d ", m.unread)))
+		}
 		cursor := "  "
 		if i == m.selectedOption {
 			cursor = cursorStyle.Render("> ")
@@ -159,10 +203,45 @@ func (m homeModel) View() string {
 		}
 		b.WriteString(fmt.Sprintf("%s%s\n", cursor, option))
 	}
+
+	// Practice reminders from the forgetting-curve forecast
+	if len(m.reminders) > 0 {
+		now := time.Now()
+		b.WriteString("\n")
+		b.WriteString(subtitleStyle.Render("Practice Reminders"))
+		b.WriteString("\n")
+		for i, reminder := range m.reminders {
+			if i == maxHomeReminders {
+				b.WriteString(helpStyle.Render(fmt.Sprintf("  ...and %d more", len(m.reminders)-i)))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString(fmt.Sprintf("%s %s\n", warningStyle.Render(symbols.Warning.String()), reminder.Reminder(now)))
+		}
+	}
+	// The session left in progress
+	if m.paused != nil {
+		b.WriteString("\n")
+		b.WriteString(subtitleStyle.Render("Session in Progress"))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("%s (%s), %s in\n", m.paused.Title, m.paused.Language, formatDuration(m.paused.Elapsed())))
+	}
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(errorStyle.Render(m.message))
+		b.WriteString("\n")
+	}
 	
 	// Help text
+	help := []string{"↑/↓: Navigate", "Enter: Select"}
+	if m.paused != nil {
+		help = append(help, "c: Continue "+m.paused.Title)
+	}
+	if len(m.reminders) > 0 {
+		help = append(help, "r: Refresh "+m.reminders[0].Pattern)
+	}
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: Navigate • Enter: Select • q: Quit"))
+	b.WriteString(helpStyle.Render(strings.Join(append(help, "q: Quit"), " • ")))
 	
 	return b.String()
 }
@@ -177,6 +256,8 @@ type patternModel struct {
 // problemListModel represents the problem list state
 type problemListModel struct {
 	problems      []problem.Problem
+	query         string            // Filter typed to narrow the list
+	matches       []problem.Problem // Problems matching the filter, best first
 	selectedIndex int
 	pattern       string
 	loading       bool
@@ -187,29 +268,43 @@ type problemDetailModel struct {
 	problem  problem.Problem
 	showHint bool
 	showInfo bool
-	viewport viewport.Model
+	viewport view.Viewport
 }
 
 // sessionModel represents the active session state
 type sessionModel struct {
-	sessionID    string
-	problem      problem.Problem
-	showHint     bool
-	showSolution bool
-	timerPaused  bool
-	startTime    time.Time
-	duration     time.Duration
-	viewport     viewport.Model
-	testResults  string
-	message      string
-	confirmQuit  bool
+	sessionID     string
+	problem       problem.Problem
+	showHint      bool
+	showSolution  bool
+	showDiff      bool // The diff against the reference solution, once the solution is shown
+	showBigO      bool              // Big-O reference overlay
+	showClarify   bool              // Clarifying questions panel
+	clarifyAI     map[string]string // AI answers to questions the problem does not settle
+	clarifyAsking bool
+	clock         *clock.Clock
+	viewport      view.Viewport
+	testResults   string
+	complexity    *complexity.Feedback // Set once a passing solution is analyzed
+	message       string
+	confirmQuit   bool
+	addTest       *userTestDraft // Set while a user test is being typed
+	editor        *editor.Model  // Set while the built-in editor is open
+	privacy       privacy.Screen // Blanks the session for screen sharing or when idle
+	cram          *cram.Session  // Set during a cram session
+	bookmarked    bool           // The problem is bookmarked
+	notes         string         // The user's Markdown notes on the problem
+	editingNotes  bool           // The built-in editor holds the notes rather than the code
 }
 
 // statsModel represents the statistics view state
 type statsModel struct {
-	loading bool
-	summary stats.Summary
-	viewport viewport.Model
+	loading  bool
+	summary  stats.Summary
+	calendar stats.Calendar       // Practice of the past year, empty until loaded
+	rotation []daily.RotationWeek // Coming weeks of the daily rotation, if one is configured
+	goals    []goals.Progress     // Progress toward the user's goals, if any are set
+	viewport view.Viewport
 }
 
 // dailyModel represents the daily challenge state
@@ -228,6 +323,14 @@ type settingsModel struct {
 	message        string
 }
 
+// notificationsModel represents the notifications inbox state
+type notificationsModel struct {
+	items         []notifications.Notification
+	selectedIndex int
+	loading       bool
+	err           error
+}
+
 // globalKeyMap defines global keyboard shortcuts
 type globalKeyMap struct {
 	Quit key.Binding
@@ -244,6 +347,7 @@ func NewModel() Model {
 				"Start Practice Session",
 				"Daily Scales",
 				"View Statistics",
+				"Notifications",
 				"Settings",
 			},
 		},
@@ -267,10 +371,17 @@ func NewModel() Model {
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	// Load initial data
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		loadProblems(),
 		loadConfig(),
-	)
+		loadUnreadCount(),
+		loadReminders(),
+		loadPausedSession(),
+	}
+	if m.session.cram != nil {
+		cmds = append(cmds, startCramProblem(m.session.cram))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the model
@@ -290,7 +401,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Propagate window size to all components
 		m.home.width = msg.Width
 		m.home.height = msg.Height
-		// Components will get dimensions passed when rendering
+		// Components will get dimensions passed when rendering, except the
+		// session screen, which sizes its viewport and editor
+		if m.state == StateSession {
+			return m.updateSession(msg)
+		}
 		return m, nil
 		
 	case animationTickMsg:
@@ -322,9 +437,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case configLoadedMsg:
 		m.config = msg.config
 		
+	case unreadCountMsg:
+		m.home.unread = msg.count
+
+	case remindersLoadedMsg:
+		m.home.reminders = msg.reminders
+
+	case pausedSessionMsg:
+		m.home.paused = msg.state
+
+	case refresherRequestedMsg:
+		return m, loadRefresher(msg.pattern)
+
+	case refresherLoadedMsg:
+		m.problems.pattern = msg.pattern
+		m.problemDetail.problem = msg.problem
+		m.problemDetail.showHint = false
+		m.problemDetail.showInfo = false
+		m = m.navigate(StateProblemDetail)
+		return m, AnimationTick()
+		
 	case navigateBackMsg:
 		m, cmd = m.handleBack()
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, cmd, loadUnreadCount(), loadReminders(), loadPausedSession())
 		// Start slide animation
 		m.animation = NewAnimation(AnimationSlideLeft, 300*time.Millisecond)
 		cmds = append(cmds, AnimationTick())
@@ -336,13 +471,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 		
 	case tea.KeyMsg:
-		// Handle global key bindings
+		// A blanked session only comes back, whatever the key
+		if m.state == StateSession && m.session.privacy.HandleKey(msg.String(), time.Now()) {
+			return m, nil
+		}
+		// Handle global key bindings, which the editor takes for itself
 		switch {
+		case m.state == StateSession && m.session.editor != nil:
+		case m.state == StateSession && m.session.cram != nil && (key.Matches(msg, m.keys.Quit) || key.Matches(msg, m.keys.Back)):
+			// Leaving a cram session asks first, then shows its summary
+		case m.state == StateProblemList && m.problems.takesKey(msg):
+			// The problem list's filter takes what is typed into it
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Back):
 			m, cmd = m.handleBack()
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, loadUnreadCount(), loadReminders(), loadPausedSession())
 			// Start slide animation
 			m.animation = NewAnimation(AnimationSlideLeft, 300*time.Millisecond)
 			cmds = append(cmds, AnimationTick())
@@ -397,10 +541,19 @@ func (m Model) View() string {
 		content = m.viewDaily()
 	case StateSettings:
 		content = m.viewSettings()
+	case StateNotifications:
+		content = m.viewNotifications()
+	case StateCramSummary:
+		content = m.viewCramSummary()
 	default:
 		content = "Unknown state"
 	}
 	
+	// Accessible mode draws plain text, without animation
+	if access.Enabled() {
+		return access.Plain(content)
+	}
+	
 	// Apply animation if active
 	if !m.animation.Complete {
 		content = m.animation.Apply(content, m.width, m.height)
@@ -417,7 +570,7 @@ func (m Model) handleBack() (Model, tea.Cmd) {
 	} else {
 		// Default back navigation
 		switch m.state {
-		case StatePatternSelection, StateDaily, StateStats, StateSettings:
+		case StatePatternSelection, StateDaily, StateStats, StateSettings, StateNotifications:
 			m.state = StateHome
 		case StateProblemList:
 			m.state = StatePatternSelection
@@ -467,6 +620,10 @@ func (m Model) routeUpdate(msg tea.Msg) (Model, tea.Cmd) {
 		return m.updateDaily(msg)
 	case StateSettings:
 		return m.updateSettings(msg)
+	case StateNotifications:
+		return m.updateNotifications(msg)
+	case StateCramSummary:
+		return m.updateCramSummary(msg)
 	default:
 		return m, nil
 	}
@@ -83,6 +83,7 @@ type TestResult struct {
 	Expected string
 	Actual   string
 	Passed   bool
+	Duration time.Duration
 }
 
 // Statistics represents user statistics
@@ -1,11 +1,30 @@
 package ui
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/common/utils"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/goals"
+	"github.com/lancekrogers/algo-scales/internal/hints"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
 	"github.com/lancekrogers/algo-scales/internal/stats"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+	"github.com/lancekrogers/algo-scales/internal/ui/privacy"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -211,6 +230,43 @@ func TestStatsLoadedMsg_Update(t *testing.T) {
 	assert.NotNil(t, m.stats)
 }
 
+func TestStatsCalendarContent(t *testing.T) {
+	model := New()
+	model.ready = true
+	model.state = StateStats
+	now := time.Now()
+	calendar := stats.BuildCalendar([]interfaces.SessionStats{{ProblemID: "two_sum", StartTime: now}}, now)
+
+	updatedModel, _ := model.Update(statsLoadedMsg{calendar: calendar})
+	content := updatedModel.(Model).statsContent()
+	assert.Contains(t, content, "Practice Calendar")
+	assert.Contains(t, content, "1 problem practiced on 1 day in the past year")
+	assert.Contains(t, content, "More")
+
+	assert.NotContains(t, content, "Weekly Rotation")
+
+	// No calendar, as when sessions could not be loaded
+	updatedModel, _ = model.Update(statsLoadedMsg{})
+	assert.NotContains(t, updatedModel.(Model).statsContent(), "Practice Calendar")
+
+	rotation := []daily.RotationWeek{{Start: "2026-03-01", Patterns: []string{"dfs", "bfs"}}}
+	updatedModel, _ = model.Update(statsLoadedMsg{calendar: calendar, rotation: rotation})
+	content = updatedModel.(Model).statsContent()
+	assert.Contains(t, content, "Weekly Rotation")
+	assert.Contains(t, content, "This week, from Mar 1: dfs, bfs")
+	assert.NotContains(t, content, "Goals")
+
+	progress := []goals.Progress{
+		{Goal: goals.Goal{ID: "solve-medium", Target: 10}, Description: "Solve 10 medium problems this week", Current: 4},
+		{Goal: goals.Goal{ID: "streak", Target: 3}, Description: "Keep a 3-day practice streak", Current: 3, Done: true},
+	}
+	updatedModel, _ = model.Update(statsLoadedMsg{goals: progress})
+	content = updatedModel.(Model).statsContent()
+	assert.Contains(t, content, "Goals")
+	assert.Contains(t, content, "4/10  Solve 10 medium problems this week")
+	assert.Contains(t, content, "3/3  Keep a 3-day practice streak")
+}
+
 func TestProblemLoadedMsg_Update(t *testing.T) {
 	model := New()
 	model.ready = true
@@ -231,4 +287,542 @@ func TestProblemLoadedMsg_Update(t *testing.T) {
 	// Check that the update was handled
 	// The specific behavior depends on the implementation
 	assert.NotNil(t, m)
-}
\ No newline at end of file
+}
+func TestHomeUnreadBadge(t *testing.T) {
+	model := New()
+	assert.NotContains(t, model.home.View(), " 3 ")
+
+	updatedModel, _ := model.Update(unreadCountMsg{count: 3})
+	m, ok := updatedModel.(Model)
+	require.True(t, ok)
+
+	assert.Equal(t, 3, m.home.unread)
+	assert.Contains(t, m.home.View(), " 3 ")
+}
+
+func TestNotificationsNavigation(t *testing.T) {
+	model := New()
+	model.state = StateNotifications
+	model.notifications.items = []notifications.Notification{
+		{ID: "a", Kind: notifications.KindAchievement, Title: "Unlocked"},
+		{ID: "b", Kind: notifications.KindProblemUpdate, Title: "Two Sum was updated", Details: []string{"constraints clarified"}},
+	}
+
+	m, _ := model.updateNotifications(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, m.notifications.selectedIndex)
+
+	// The selected entry's changelog is expanded
+	assert.Contains(t, m.viewNotifications(), "constraints clarified")
+}
+
+func TestHomePracticeReminders(t *testing.T) {
+	model := New()
+	assert.NotContains(t, model.home.View(), "Practice Reminders")
+
+	reminders := []stats.PatternRetention{
+		{Pattern: "binary-search", DecaysAt: time.Now().Add(60 * time.Hour)},
+		{Pattern: "heap", DecaysAt: time.Now().Add(100 * time.Hour)},
+	}
+	updatedModel, _ := model.Update(remindersLoadedMsg{reminders: reminders})
+	m, ok := updatedModel.(Model)
+	require.True(t, ok)
+
+	view := m.home.View()
+	assert.Contains(t, view, "Practice Reminders")
+	assert.Contains(t, view, "Your binary-search skills are predicted to decay below 70% recall in 3 days")
+	assert.Contains(t, view, "r: Refresh binary-search")
+
+	// r queues a refresher for the pattern that decays first
+	_, cmd := m.home.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	require.NotNil(t, cmd)
+	assert.Equal(t, refresherRequestedMsg{pattern: "binary-search"}, cmd())
+
+	// The refresher opens the chosen problem
+	prob := problem.Problem{ID: "search_in_rotated_array", Title: "Search in Rotated Array"}
+	updatedModel, _ = m.Update(refresherLoadedMsg{pattern: "binary-search", problem: prob})
+	m, ok = updatedModel.(Model)
+	require.True(t, ok)
+	assert.Equal(t, StateProblemDetail, m.state)
+	assert.Equal(t, prob.ID, m.problemDetail.problem.ID)
+}
+
+func TestHomePausedSession(t *testing.T) {
+	model := New()
+	assert.NotContains(t, model.home.View(), "Session in Progress")
+
+	state := &resume.State{ProblemID: "two_sum", Title: "Two Sum", Language: "go", Clock: clock.State{Elapsed: 12*time.Minute + 30*time.Second}}
+	updatedModel, _ := model.Update(pausedSessionMsg{state: state})
+	m, ok := updatedModel.(Model)
+	require.True(t, ok)
+
+	view := m.home.View()
+	assert.Contains(t, view, "Session in Progress")
+	assert.Contains(t, view, "Two Sum (go), 00:12:30 in")
+	assert.Contains(t, view, "c: Continue Two Sum")
+
+	// c reopens it
+	_, cmd := m.home.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	assert.NotNil(t, cmd)
+
+	// A session that cannot be reopened says why
+	updated, _ := m.home.Update(sessionErrorMsg{err: assert.AnError})
+	assert.Contains(t, updated.View(), "Could not resume the session")
+}
+
+func TestPickRefresher(t *testing.T) {
+	now := time.Now()
+	problems := []problem.Problem{{ID: "recent"}, {ID: "old"}, {ID: "never"}}
+
+	sessions := []stats.SessionStats{
+		{ProblemID: "recent", StartTime: now.Add(-time.Hour)},
+		{ProblemID: "old", StartTime: now.Add(-72 * time.Hour)},
+	}
+	assert.Equal(t, "never", pickRefresher(problems, sessions).ID)
+
+	sessions = append(sessions, stats.SessionStats{ProblemID: "never", StartTime: now})
+	assert.Equal(t, "old", pickRefresher(problems, sessions).ID)
+
+	assert.Nil(t, pickRefresher(nil, sessions))
+}
+
+func TestAutoSubmit(t *testing.T) {
+	passed := "Running tests...\n\n3/3 tests passed"
+	failed := "Running tests...\n\nTest 3: FAILED\n2/3 tests passed"
+
+	model := New()
+	model.state = StateSession
+	model.session.sessionID = "auto-submit"
+
+	// Without auto-submit, passing tests wait for Enter
+	m, _ := model.updateSession(testResultsMsg{results: passed})
+	assert.Empty(t, m.session.message)
+
+	model.config.AutoSubmit = true
+	m, _ = model.updateSession(testResultsMsg{results: failed})
+	assert.Empty(t, m.session.message)
+
+	m, cmd := model.updateSession(testResultsMsg{results: passed})
+	assert.Contains(t, m.session.message, "All tests passed")
+	assert.NotNil(t, cmd)
+}
+
+func TestSessionTestTiers(t *testing.T) {
+	original := executeTests
+	defer func() { executeTests = original }()
+	originalRecord := recordAttempt
+	defer func() { recordAttempt = originalRecord }()
+	var submitted []bool
+	recordAttempt = func(problemID, language, code string, results []interfaces.TestResult, allPassed, submit bool) {
+		submitted = append(submitted, submit)
+	}
+	var ran []interfaces.TestCase
+	edgePasses := false
+	executeTests = func(ctx context.Context, prob *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		ran = prob.TestCases
+		var results []interfaces.TestResult
+		for _, tc := range prob.TestCases {
+			passed := tc.Tier == "" || edgePasses
+			results = append(results, interfaces.TestResult{Input: tc.Input, Expected: tc.Expected, Actual: "1", Passed: passed, Tier: tc.Tier.OrDefault()})
+		}
+		return results, false, nil
+	}
+
+	model := New()
+	model.state = StateSession
+	model.session.sessionID = "test-tiers"
+	model.session.problem = problem.Problem{TestCases: []problem.TestCase{
+		{Input: "[1]", Expected: "1"},
+		{Input: "[]", Expected: "0", Tier: interfaces.TierEdge},
+	}}
+	_, codeFile := ensureCodeFile(model.session.sessionID, "go", model.session.problem)
+	defer os.RemoveAll(filepath.Dir(codeFile))
+
+	// Running tests only runs the examples
+	msg := runTests(model.session.sessionID, "go", model.session.problem)()
+	assert.Len(t, ran, 1)
+	results := msg.(testResultsMsg).results
+	assert.Contains(t, results, "1/1 tests passed")
+	assert.Contains(t, results, "Press Enter to submit against 1 more tests")
+
+	// Submitting runs every tier and stays in the session until it passes
+	m, _ := model.updateSession(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "Submitting...", m.session.message)
+	msg = submitTests(model.session.sessionID, "go", model.session.problem)()
+	assert.Len(t, ran, 2)
+	results = msg.(submitResultsMsg).results
+	assert.Contains(t, results, "Test 2 [edge]: FAILED")
+	assert.Contains(t, results, "1/2 tests passed (example 1/1, edge 0/1)")
+
+	m, _ = m.updateSession(msg)
+	assert.Contains(t, m.session.message, "Submission failed")
+	assert.Equal(t, []bool{false, true}, submitted, "each run is recorded, marking submissions")
+
+	edgePasses = true
+	m, cmd := m.updateSession(submitTests(model.session.sessionID, "go", model.session.problem)())
+	assert.Contains(t, m.session.message, "All tests passed")
+	assert.NotNil(t, cmd)
+}
+
+func TestSessionUserTests(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), storage.DBFileName)
+	originalOpen := openUserTests
+	openUserTests = func() *usertests.Suite { return usertests.NewSuite(storage.NewSQLiteStore(dbPath)) }
+	defer func() { openUserTests = originalOpen }()
+	originalExecute := executeTests
+	defer func() { executeTests = originalExecute }()
+	originalRecord := recordAttempt
+	defer func() { recordAttempt = originalRecord }()
+	recordAttempt = func(string, string, string, []interfaces.TestResult, bool, bool) {}
+	executeTests = func(ctx context.Context, prob *interfaces.Problem, code, language string, timeout time.Duration) ([]interfaces.TestResult, bool, error) {
+		var results []interfaces.TestResult
+		for _, tc := range prob.TestCases {
+			results = append(results, interfaces.TestResult{Input: tc.Input, Expected: tc.Expected, Actual: "[0,1]", Passed: tc.Expected == "[0,1]", Tier: tc.Tier.OrDefault()})
+		}
+		return results, false, nil
+	}
+
+	model := New()
+	model.state = StateSession
+	model.session.sessionID = "test-user-tests"
+	model.session.problem = problem.Problem{ID: "two_sum", TestCases: []problem.TestCase{{Input: "[2,7,11,15], 9", Expected: "[0,1]"}}}
+	_, codeFile := ensureCodeFile(model.session.sessionID, "go", model.session.problem)
+	defer os.RemoveAll(filepath.Dir(codeFile))
+
+	typeText := func(m Model, text string) Model {
+		for _, r := range text {
+			m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return m
+	}
+
+	// Keys type into the test rather than driving the session
+	m, _ := model.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.NotNil(t, m.session.addTest)
+	m = typeText(m, "[3,3], 6")
+	assert.False(t, m.session.showSolution)
+	assert.Contains(t, m.userTestPrompt(), "[3,3], 6█")
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyEnter})
+	m = typeText(m, "[0,2")
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = typeText(m, "1]")
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, m.session.addTest)
+	assert.Equal(t, "Added user test 1. Press 't' to run it with the examples.", m.session.message)
+
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = typeText(m, "[1,2], 3")
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyEnter})
+	m = typeText(m, "[1,0]")
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, "Test not added.", m.session.message)
+
+	// User tests run with the examples and are labelled in the results
+	results := runTests(m.session.sessionID, "go", m.session.problem)().(testResultsMsg).results
+	assert.Contains(t, results, "Test 2 [user]: PASSED")
+	assert.Contains(t, results, "Test 3 [user]: FAILED")
+	assert.Contains(t, results, "2/3 tests passed (example 1/1, user 1/2)")
+}
+
+func TestSessionPrivacy(t *testing.T) {
+	model := New()
+	model.ready = true
+	model.width, model.height = 100, 30
+	model.state = StateSession
+	next, _ := model.Update(sessionStartedMsg{sessionID: "privacy", problem: problem.Problem{Title: "Two Sum"}, clock: clock.NewStopwatch()})
+	m := next.(Model)
+	require.Contains(t, m.View(), "Two Sum")
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = next.(Model)
+	assert.NotContains(t, m.View(), "Two Sum")
+	assert.Contains(t, m.View(), "Press any key to show the session.")
+
+	// The key that restores the session does nothing else, even quit or back
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	assert.Nil(t, cmd)
+	assert.Equal(t, StateSession, m.state)
+	assert.Contains(t, m.View(), "Two Sum")
+
+	// The session blanks itself once idle
+	m.session.privacy = privacy.New(time.Minute, time.Now().Add(-2*time.Minute))
+	next, _ = m.Update(clock.TickMsg{})
+	assert.True(t, next.(Model).session.privacy.Blanked())
+}
+
+func TestSessionEditor(t *testing.T) {
+	model := New()
+	model.ready = true
+	model.width, model.height = 100, 30
+	model.state = StateSession
+	next, _ := model.Update(sessionStartedMsg{sessionID: "test-editor", problem: problem.Problem{Title: "Two Sum", StarterCode: map[string]string{"go": "func twoSum() {}"}}, clock: clock.NewStopwatch()})
+	m := next.(Model)
+	_, codeFile := ensureCodeFile(m.session.sessionID, m.config.Language, m.session.problem)
+	defer os.RemoveAll(filepath.Dir(codeFile))
+
+	// press sends keys, and the messages their commands return
+	var run func(m Model, msg tea.Msg) Model
+	run = func(m Model, msg tea.Msg) Model {
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, cmd := range batch {
+				m = run(m, cmd())
+			}
+			return m
+		}
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		if cmd != nil {
+			m = run(m, cmd())
+		}
+		return m
+	}
+	press := func(m Model, keys ...tea.KeyMsg) Model {
+		for _, k := range keys {
+			m = run(m, k)
+		}
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m = press(m, runes("e"))
+	require.NotNil(t, m.session.editor)
+	assert.Contains(t, m.View(), "func twoSum() {}")
+	assert.Contains(t, m.View(), "-- NORMAL --")
+
+	// Keys go to the editor, even the ones that quit or go back
+	m = press(m, runes("A"), runes(" // q"), tea.KeyMsg{Type: tea.KeyEsc}, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, StateSession, m.state)
+	require.NotNil(t, m.session.editor)
+	assert.Equal(t, "func twoSum() {} // q", m.session.editor.Value())
+
+	m = press(m, runes(":"), runes("w"), tea.KeyMsg{Type: tea.KeyEnter})
+	saved, err := os.ReadFile(codeFile)
+	require.NoError(t, err)
+	assert.Equal(t, "func twoSum() {} // q", string(saved))
+	assert.False(t, m.session.editor.Dirty())
+
+	m = press(m, runes(":"), runes("q"), tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, m.session.editor)
+	assert.Equal(t, "Editor closed. Press 't' to run tests.", m.session.message)
+}
+
+func TestSessionHintPolicy(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	defer store.Close()
+	policy := config.HintPolicy{MaxHints: 1, SolutionAfterFailures: 1}
+	original := openHintTracker
+	openHintTracker = func() *hints.Tracker { return hints.NewTracker(policy, nopCloser{store}) }
+	defer func() { openHintTracker = original }()
+
+	model := New()
+	model.state = StateSession
+	model.session.problem = problem.Problem{ID: "two_sum"}
+
+	// The solution stays hidden until a test run fails
+	m, _ := model.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.False(t, m.session.showSolution)
+	assert.Contains(t, m.session.message, "unlocks after 1 failed test run")
+
+	m, _ = m.updateSession(testResultsMsg{results: "Test 3: FAILED\n2/3 tests passed"})
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.True(t, m.session.showSolution)
+
+	// Toggling the hint only counts once per session
+	for i := 0; i < 3; i++ {
+		m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	}
+	assert.True(t, m.session.showHint)
+
+	// A new session of the same problem shows the hint it already had for free
+	m, _ = m.updateSession(sessionStartedMsg{sessionID: "next", problem: model.session.problem, clock: clock.NewStopwatch()})
+	m.session.showHint = false
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	assert.True(t, m.session.showHint)
+	usage, err := store.LoadHintUsage(context.Background(), "two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, 1, usage.Hints)
+	assert.Equal(t, hints.LevelPattern, usage.Level)
+
+	// The next level, as vim mode would ask for, is over the limit
+	decision, err := openHintTracker().Request(context.Background(), "two_sum", hints.KindHint)
+	require.NoError(t, err)
+	assert.Contains(t, decision.Reason, "Hint limit reached")
+}
+
+func TestSessionComplexity(t *testing.T) {
+	model := New()
+	model.state = StateSession
+
+	// Only a passing run is analyzed
+	m, cmd := model.updateSession(testResultsMsg{results: "Test 3: FAILED\n2/3 tests passed"})
+	assert.Nil(t, cmd)
+	m, cmd = m.updateSession(testResultsMsg{results: "3/3 tests passed"})
+	assert.NotNil(t, cmd)
+
+	m, _ = m.updateSession(complexityMsg{feedback: complexity.Feedback{
+		Time: complexity.Quadratic, Space: complexity.Constant,
+		TargetTime: complexity.Linear, TargetSpace: complexity.Linear, HasTarget: true,
+	}})
+	require.NotNil(t, m.session.complexity)
+	content := m.sessionContent()
+	assert.Contains(t, content, "Your solution: O(n^2) time, O(1) space")
+	assert.Contains(t, content, "slower than the reference")
+
+	// A new run clears the previous analysis
+	m, _ = m.updateSession(testResultsMsg{results: "Test 1: FAILED\n0/1 tests passed"})
+	assert.Nil(t, m.session.complexity)
+}
+
+func TestSessionClarify(t *testing.T) {
+	originalConfigured, originalClarify := aiConfigured, clarifyWithAI
+	defer func() { aiConfigured, clarifyWithAI = originalConfigured, originalClarify }()
+	aiConfigured = func() bool { return true }
+	var asked []string
+	clarifyWithAI = func(prob problem.Problem, questions []string) (map[string]string, error) {
+		asked = questions
+		return map[string]string{"Is the input sorted?": "No, assume it is unsorted."}, nil
+	}
+
+	model := New()
+	model.state = StateSession
+	model.session.problem = problem.Problem{ID: "two_sum", Constraints: []string{"2 <= nums.length <= 10^4", "Only one valid answer exists"}}
+
+	m, cmd := model.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	require.NotNil(t, cmd, "unsettled questions go to the AI")
+	assert.True(t, m.session.showClarify)
+	assert.Contains(t, m.sessionContent(), "A: Asking the AI assistant...")
+	assert.Contains(t, m.sessionContent(), "A: No, nums.length is at least 2.")
+
+	m, _ = m.updateSession(cmd())
+	assert.Contains(t, asked, "Is the input sorted?")
+	assert.NotContains(t, asked, "Can the input be empty?")
+	content := m.sessionContent()
+	assert.Contains(t, content, "A: No, assume it is unsorted.")
+	assert.Contains(t, content, "A: Not stated in the problem.")
+
+	// The AI is asked once per session
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	assert.False(t, m.session.showClarify)
+	_, cmd = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	assert.Nil(t, cmd)
+}
+
+// nopCloser keeps a shared test store open when a tracker is closed
+type nopCloser struct{ hints.Store }
+
+func TestSubmitRecordsTUISession(t *testing.T) {
+	original := stats.RecordSession
+	defer func() { stats.RecordSession = original }()
+	var recorded []stats.SessionStats
+	stats.RecordSession = func(s stats.SessionStats) error {
+		recorded = append(recorded, s)
+		return nil
+	}
+
+	model := New()
+	model.state = StateSession
+	model.session.sessionID = "record"
+	model.session.problem = problem.Problem{ID: "two_sum", Patterns: []string{"hash-map"}}
+	model.session.showHint = true
+
+	model.submitSolution()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "two_sum", recorded[0].ProblemID)
+	assert.Equal(t, interfaces.ContextTUI, recorded[0].Context)
+	assert.True(t, recorded[0].HintsUsed)
+}
+
+func TestSessionBookmark(t *testing.T) {
+	original := utils.GetConfigDir
+	dir := t.TempDir()
+	utils.GetConfigDir = func() string { return dir }
+	t.Cleanup(func() { utils.GetConfigDir = original })
+
+	m := NewModel()
+	m.state = StateSession
+	m.width = 100
+	m.ready = true
+	m.session.problem = problem.Problem{ID: "two_sum", Title: "Two Sum"}
+
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	assert.True(t, m.session.bookmarked)
+	assert.True(t, problem.IsBookmarked("two_sum"))
+	assert.Contains(t, m.viewSession(), symbols.Star.String()+" Two Sum")
+
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	assert.False(t, m.session.bookmarked)
+	assert.False(t, problem.IsBookmarked("two_sum"))
+	assert.Equal(t, "Bookmark removed.", m.session.message)
+}
+
+func TestSessionNotes(t *testing.T) {
+	original := utils.GetConfigDir
+	dir := t.TempDir()
+	utils.GetConfigDir = func() string { return dir }
+	t.Cleanup(func() { utils.GetConfigDir = original })
+
+	m := NewModel()
+	m.state = StateSession
+	m.width = 100
+	m.height = 40
+	m.ready = true
+	m.session.problem = problem.Problem{ID: "two_sum", Title: "Two Sum"}
+
+	// A problem without notes starts from the outline
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	require.NotNil(t, m.session.editor)
+	assert.True(t, m.session.editingNotes)
+	assert.Equal(t, problem.NotesOutline(m.session.problem), m.session.editor.Value())
+
+	m, _ = m.updateSession(editor.SaveMsg{Code: "Sort first, then two pointers.", Close: true})
+	assert.Nil(t, m.session.editor)
+	assert.False(t, m.session.editingNotes)
+	assert.Equal(t, "Notes saved.", m.session.message)
+	notes, err := problem.LoadNotes("two_sum")
+	require.NoError(t, err)
+	assert.Equal(t, "Sort first, then two pointers.\n", notes)
+	assert.Contains(t, m.sessionContent(), "Sort first, then two pointers.")
+
+	// Closing the notes unsaved keeps them as they were
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m, _ = m.updateSession(editor.CloseMsg{})
+	assert.False(t, m.session.editingNotes)
+	assert.Equal(t, "Notes closed.", m.session.message)
+	assert.Equal(t, "Sort first, then two pointers.\n", m.session.notes)
+}
+
+func TestSessionReferenceDiff(t *testing.T) {
+	m := NewModel()
+	m.state = StateSession
+	m.width = 100
+	m.height = 40
+	m.ready = true
+	m.config.Language = "go"
+	m.session.sessionID = "test-reference-diff"
+	m.session.problem = problem.Problem{
+		ID:          "two_sum",
+		Title:       "Two Sum",
+		StarterCode: map[string]string{"go": "func twoSum(nums []int) []int {\n\treturn nil\n}\n"},
+		Solutions:   map[string]string{"go": "func twoSum(nums []int) []int {\n\tseen := map[int]int{}\n\treturn nil\n}\n"},
+	}
+	_, codeFile := ensureCodeFile(m.session.sessionID, "go", m.session.problem)
+	defer os.RemoveAll(filepath.Dir(codeFile))
+
+	// The diff waits for the solution to be shown
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.False(t, m.session.showDiff)
+	assert.Contains(t, m.session.message, "Show the solution with 's'")
+
+	m.session.showSolution = true
+	m, _ = m.updateSession(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.True(t, m.session.showDiff)
+	content := m.sessionContent()
+	assert.Contains(t, content, "Your Code vs. Reference")
+	assert.Contains(t, content, "seen := map[int]int{}")
+	assert.Contains(t, content, "0 line(s) only in yours, 1 only in the reference.")
+
+	m.config.Language = "python"
+	assert.Contains(t, m.sessionContent(), "Cannot compare: this problem has no reference solution in python")
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/notifications"
+)
+
+// Notification messages
+type notificationsLoadedMsg struct {
+	items []notifications.Notification
+	err   error
+}
+
+type unreadCountMsg struct {
+	count int
+}
+
+// loadUnreadCount loads the unread badge count for the home screen
+func loadUnreadCount() tea.Cmd {
+	return func() tea.Msg {
+		count, err := notifications.UnreadCount()
+		if err != nil {
+			return nil
+		}
+		return unreadCountMsg{count: count}
+	}
+}
+
+// loadNotifications loads the notifications inbox
+func loadNotifications() tea.Cmd {
+	return func() tea.Msg {
+		items, err := notifications.Load()
+		return notificationsLoadedMsg{items: items, err: err}
+	}
+}
+
+// markNotificationRead persists the read state of a single notification
+func markNotificationRead(id string) tea.Cmd {
+	return func() tea.Msg {
+		if err := notifications.MarkRead(id); err != nil {
+			return notificationsLoadedMsg{err: err}
+		}
+		return loadUnreadCount()()
+	}
+}
+
+// markAllNotificationsRead persists the read state of every notification
+func markAllNotificationsRead() tea.Cmd {
+	return func() tea.Msg {
+		if err := notifications.MarkAllRead(); err != nil {
+			return notificationsLoadedMsg{err: err}
+		}
+		return unreadCountMsg{count: 0}
+	}
+}
+
+// Update handles updates for the notifications screen
+func (m Model) updateNotifications(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case notificationsLoadedMsg:
+		m.notifications.items = msg.items
+		m.notifications.err = msg.err
+		m.notifications.loading = false
+		m.notifications.selectedIndex = 0
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.notifications.selectedIndex > 0 {
+				m.notifications.selectedIndex--
+			}
+		case "down", "j":
+			if m.notifications.selectedIndex < len(m.notifications.items)-1 {
+				m.notifications.selectedIndex++
+			}
+		case "enter", " ":
+			// Mark the selected entry as read
+			if m.notifications.selectedIndex < len(m.notifications.items) {
+				n := &m.notifications.items[m.notifications.selectedIndex]
+				if !n.Read {
+					n.Read = true
+					return m, markNotificationRead(n.ID)
+				}
+			}
+		case "a":
+			// Mark everything as read
+			for i := range m.notifications.items {
+				m.notifications.items[i].Read = true
+			}
+			return m, markAllNotificationsRead()
+		case "r":
+			m.notifications.loading = true
+			return m, loadNotifications()
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the notifications screen
+func (m Model) viewNotifications() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("🔔 Notifications"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.notifications.loading:
+		b.WriteString(warningStyle.Render("Loading notifications..."))
+		return b.String()
+	case m.notifications.err != nil:
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.notifications.err)))
+		return b.String()
+	case len(m.notifications.items) == 0:
+		b.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render("No notifications yet."))
+	}
+
+	detailStyle := lipgloss.NewStyle().Foreground(lightGray).PaddingLeft(4)
+	dateStyle := lipgloss.NewStyle().Foreground(mutedColor)
+
+	unreadMarker := lipgloss.NewStyle().Foreground(errorColor).Render("●")
+
+	for i, n := range m.notifications.items {
+		cursor := "  "
+		title := n.Title
+		if i == m.notifications.selectedIndex {
+			cursor = cursorStyle.Render("> ")
+			title = selectedItemStyle.Render(title)
+		}
+		marker := " "
+		if !n.Read {
+			marker = unreadMarker
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s %s  %s\n", cursor, marker, n.Kind.Icon(), title, dateStyle.Render(n.CreatedAt.Format("Jan 2 15:04"))))
+
+		// Expand the changelog for the selected entry only
+		if i == m.notifications.selectedIndex {
+			for _, detail := range n.Details {
+				b.WriteString(detailStyle.Render("• "+detail) + "\n")
+			}
+		}
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓: Navigate • Enter: Mark read • a: Mark all read • r: Refresh • Esc: Back"))
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+// Package privacy blanks a session screen, hiding the problem and code
+// while screen sharing or away from the keyboard. A key blanks the screen
+// at once, and it can also blank itself after a while without key presses;
+// any key brings it back.
+package privacy
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// Key blanks the screen
+const Key = "ctrl+l"
+
+// BlankAfter is how long a session screen can go without a key press before
+// it blanks itself; zero for never. It is set from the config at startup.
+var BlankAfter time.Duration
+
+// Screen tracks whether a session screen is blanked
+type Screen struct {
+	idle       time.Duration // Blank after this long without a key press; 0 for never
+	lastActive time.Time
+	blanked    bool
+}
+
+// New creates a screen, shown, that blanks itself after idle without a key
+// press, or only on Key if idle is zero
+func New(idle time.Duration, now time.Time) Screen {
+	return Screen{idle: idle, lastActive: now}
+}
+
+// Blanked reports whether the screen is blanked
+func (s Screen) Blanked() bool {
+	return s.blanked
+}
+
+// HandleKey handles a key press and reports whether it was used up: Key
+// blanks the screen, and on a blanked screen any key only restores it.
+// Other keys are left to the session.
+func (s *Screen) HandleKey(key string, now time.Time) bool {
+	s.lastActive = now
+	if s.blanked {
+		s.blanked = false
+		return true
+	}
+	if key == Key {
+		s.blanked = true
+		return true
+	}
+	return false
+}
+
+// Tick blanks the screen once it has gone idle
+func (s *Screen) Tick(now time.Time) {
+	if s.idle > 0 && !s.blanked && now.Sub(s.lastActive) >= s.idle {
+		s.blanked = true
+	}
+}
+
+// View renders a blanked screen of the given size
+func View(width, height int) string {
+	message := lipgloss.NewStyle().
+		Foreground(theme.Current().Muted).
+		Render("Hidden. Press any key to show the session.")
+	if width <= 0 || height <= 0 {
+		return message
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, message,
+		lipgloss.WithWhitespaceChars(" "))
+}
@@ -0,0 +1,51 @@
+package privacy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	screen := New(0, now)
+
+	assert.False(t, screen.HandleKey("j", now), "other keys are left to the session")
+	assert.False(t, screen.Blanked())
+
+	assert.True(t, screen.HandleKey(Key, now))
+	assert.True(t, screen.Blanked())
+
+	// Any key restores the screen without reaching the session
+	assert.True(t, screen.HandleKey("q", now))
+	assert.False(t, screen.Blanked())
+
+	// Without an idle time, the screen never blanks itself
+	screen.Tick(now.Add(24 * time.Hour))
+	assert.False(t, screen.Blanked())
+}
+
+func TestTick(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	screen := New(5*time.Minute, now)
+
+	screen.Tick(now.Add(4 * time.Minute))
+	assert.False(t, screen.Blanked())
+
+	// A key press restarts the idle time
+	screen.HandleKey("j", now.Add(4*time.Minute))
+	screen.Tick(now.Add(6 * time.Minute))
+	assert.False(t, screen.Blanked())
+
+	screen.Tick(now.Add(9 * time.Minute))
+	assert.True(t, screen.Blanked())
+}
+
+func TestView(t *testing.T) {
+	view := View(60, 5)
+	assert.Contains(t, view, "Press any key to show the session.")
+	assert.Len(t, strings.Split(view, "\n"), 5)
+	assert.NotContains(t, View(0, 0), "\n")
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
 // Update handles updates for the problem detail screen
@@ -18,7 +18,7 @@ func (m Model) updateProblemDetail(msg tea.Msg) (Model, tea.Cmd) {
 		// Handle error from splitscreen session
 		m.problemDetail.showInfo = true
 		errorContent := m.problemDetailContent() + "\n\n" + lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
+			Foreground(errorColor).
 			Bold(true).
 			Render(fmt.Sprintf("Error starting session: %v", msg.err))
 		m.problemDetail.viewport.SetContent(errorContent)
@@ -30,7 +30,7 @@ func (m Model) updateProblemDetail(msg tea.Msg) (Model, tea.Cmd) {
 		
 		// Initialize or update viewport
 		if m.problemDetail.viewport.Width == 0 {
-			m.problemDetail.viewport = viewport.New(msg.Width-4, msg.Height-10)
+			m.problemDetail.viewport = view.NewViewport(msg.Width-4, msg.Height-10)
 			m.problemDetail.viewport.SetContent(m.problemDetailContent())
 		} else {
 			m.problemDetail.viewport.Width = msg.Width - 4
@@ -67,21 +67,21 @@ func (m Model) viewProblemDetail() string {
 	// Title bar
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		MarginBottom(1)
 	
-	difficultyColor := "243"
+	difficultyColor := mutedColor
 	switch m.problemDetail.problem.Difficulty {
 	case "easy":
-		difficultyColor = "46"
+		difficultyColor = successColor
 	case "medium":
-		difficultyColor = "214"
+		difficultyColor = warningColor
 	case "hard":
-		difficultyColor = "196"
+		difficultyColor = errorColor
 	}
 	
 	diffStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(difficultyColor)).
+		Foreground(difficultyColor).
 		Bold(true)
 	
 	title := fmt.Sprintf("%s %s", 
@@ -97,7 +97,7 @@ func (m Model) viewProblemDetail() string {
 	
 	// Action bar
 	actionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(mutedColor)
 	
 	actions := []string{
 		"Enter: Start Session",
@@ -110,7 +110,7 @@ func (m Model) viewProblemDetail() string {
 	
 	// Progress indicator
 	progressStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		Align(lipgloss.Right)
 	
 	progress := fmt.Sprintf("%.0f%% ", m.problemDetail.viewport.ScrollPercent())
@@ -127,7 +127,7 @@ func (m Model) problemDetailContent() string {
 	// Description
 	content.WriteString(lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("212")).
+		Foreground(secondaryColor).
 		Render("Description"))
 	content.WriteString("\n\n")
 	content.WriteString(p.Description)
@@ -137,7 +137,7 @@ func (m Model) problemDetailContent() string {
 	if len(p.Examples) > 0 {
 		content.WriteString(lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("212")).
+			Foreground(secondaryColor).
 			Render("Examples"))
 		content.WriteString("\n\n")
 		
@@ -156,7 +156,7 @@ func (m Model) problemDetailContent() string {
 	if m.problemDetail.showHint && p.PatternExplanation != "" {
 		content.WriteString(lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("214")).
+			Foreground(warningColor).
 			Render("💡 Pattern Explanation"))
 		content.WriteString("\n\n")
 		content.WriteString(p.PatternExplanation)
@@ -167,7 +167,7 @@ func (m Model) problemDetailContent() string {
 	if m.problemDetail.showInfo {
 		content.WriteString(lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("62")).
+			Foreground(primaryColor).
 			Render("ℹ️  Additional Information"))
 		content.WriteString("\n\n")
 		
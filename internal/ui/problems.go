@@ -6,109 +6,215 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/ui/finder"
 )
 
+// previewMinWidth is how wide the screen must be for the preview to sit
+// beside the list rather than below it
+const previewMinWidth = 100
+
+// takesKey reports whether the problem list keeps a key for its filter
+// rather than letting it quit or go back: letters are typed, and backspace
+// and esc edit the filter while it holds anything
+func (m problemListModel) takesKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		return true
+	case tea.KeyBackspace, tea.KeyEsc:
+		return m.query != ""
+	}
+	return false
+}
+
+// setQuery narrows the list to the problems matching query, selecting the
+// best
+func (m *problemListModel) setQuery(query string) {
+	m.query = query
+	m.matches = finder.Filter(m.problems, query)
+	m.selectedIndex = 0
+}
+
+// selected returns the problem the cursor is on, if any
+func (m problemListModel) selected() (problem.Problem, bool) {
+	if m.selectedIndex >= len(m.matches) {
+		return problem.Problem{}, false
+	}
+	return m.matches[m.selectedIndex], true
+}
+
 // Update handles updates for the problem list screen
 func (m Model) updateProblemList(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case problemsLoadedMsg:
 		m.problems.problems = msg.problems
 		m.problems.loading = false
+		m.problems.setQuery("")
 		return m, nil
-		
+
 	case problemsErrorMsg:
 		m.problems.loading = false
 		// Handle error (could set an error message)
 		return m, nil
-		
+
 	case tea.KeyMsg:
 		if m.problems.loading {
 			return m, nil
 		}
-		
-		switch msg.String() {
-		case "up", "k":
+
+		switch msg.Type {
+		case tea.KeyUp, tea.KeyCtrlP, tea.KeyShiftTab:
 			if m.problems.selectedIndex > 0 {
 				m.problems.selectedIndex--
 			}
-		case "down", "j":
-			if m.problems.selectedIndex < len(m.problems.problems)-1 {
+		case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+			if m.problems.selectedIndex < len(m.problems.matches)-1 {
 				m.problems.selectedIndex++
 			}
-		case "enter", "right", "l":
-			if len(m.problems.problems) > 0 {
-				selectedProblem := m.problems.problems[m.problems.selectedIndex]
-				m.problemDetail.problem = selectedProblem
+		case tea.KeyEnter, tea.KeyRight:
+			if p, ok := m.problems.selected(); ok {
+				m.problemDetail.problem = p
 				return m.navigate(StateProblemDetail), nil
 			}
+		case tea.KeyBackspace:
+			runes := []rune(m.problems.query)
+			if len(runes) > 0 {
+				m.problems.setQuery(string(runes[:len(runes)-1]))
+			}
+		case tea.KeyEsc, tea.KeyCtrlU:
+			m.problems.setQuery("")
+		case tea.KeyRunes, tea.KeySpace:
+			m.problems.setQuery(m.problems.query + string(msg.Runes))
 		}
 	}
 	return m, nil
 }
 
-// View renders the problem list screen
+// View renders the problem list screen: a filter, the problems matching it,
+// and a preview of the one selected
 func (m Model) viewProblemList() string {
 	var b strings.Builder
-	
+
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		MarginBottom(2)
-	
+
 	b.WriteString(titleStyle.Render(fmt.Sprintf("%s Problems", m.problems.pattern)))
 	b.WriteString("\n\n")
-	
+
 	if m.problems.loading {
 		b.WriteString("Loading problems...")
 		return b.String()
 	}
-	
+
 	if len(m.problems.problems) == 0 {
 		b.WriteString("No problems found for this pattern.")
 		return b.String()
 	}
-	
-	// Problem list
-	for i, problem := range m.problems.problems {
-		cursor := "  "
-		if i == m.problems.selectedIndex {
-			cursor = "> "
-		}
-		
-		// Difficulty color
-		diffColor := "243"
-		switch problem.Difficulty {
-		case "easy":
-			diffColor = "46"  // Green
-		case "medium":
-			diffColor = "214" // Orange
-		case "hard":
-			diffColor = "196" // Red
+
+	if access.Enabled() {
+		b.WriteString(access.Field("Filter", m.problems.query) + "\n")
+	} else {
+		b.WriteString(cursorStyle.Render("> ") + m.problems.query + "█\n")
+	}
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%d of %d", len(m.problems.matches), len(m.problems.problems))))
+	b.WriteString("\n\n")
+
+	// Screen readers cannot follow panels side by side
+	beside := m.width >= previewMinWidth && !access.Enabled()
+	listWidth := m.width * 2 / 5
+	previewWidth := max(40, m.width-4)
+	if beside {
+		previewWidth = m.width - listWidth - 6
+	}
+
+	list := m.viewProblemMatches()
+	preview := ""
+	if p, ok := m.problems.selected(); ok {
+		preview = m.viewProblemPreview(p, previewWidth)
+	}
+	if beside {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).Render(list),
+			lipgloss.NewStyle().
+				Width(previewWidth).
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(primaryColor).
+				Padding(0, 1).
+				Render(preview)))
+	} else {
+		b.WriteString(list)
+		if preview != "" {
+			b.WriteString("\n")
+			b.WriteString(preview)
 		}
-		
-		diffStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(diffColor))
-		
-		line := fmt.Sprintf("%s%-30s %s", cursor, problem.Title, diffStyle.Render(problem.Difficulty))
-		
+	}
+
+	// Help text
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("Type to filter • ↑/↓: Navigate • Enter: Select • Esc: Clear/Back • ctrl+c: Quit"))
+
+	return b.String()
+}
+
+// viewProblemMatches lists the problems matching the filter, keeping the
+// selected one in view
+func (m Model) viewProblemMatches() string {
+	if len(m.problems.matches) == 0 {
+		return mutedStyle.Render("No problems match.")
+	}
+
+	rows := 10
+	if m.height > 0 {
+		rows = max(5, m.height-18)
+	}
+	first := max(0, m.problems.selectedIndex-rows+1)
+
+	var b strings.Builder
+	for i := first; i < len(m.problems.matches) && i < first+rows; i++ {
+		p := m.problems.matches[i]
+		diffStyle := getDifficultyStyle(p.Difficulty)
 		if i == m.problems.selectedIndex {
-			line = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("212")).
-				Render(problem.Title) + " " + diffStyle.Render(problem.Difficulty)
-			line = cursor + line
+			b.WriteString(cursorStyle.Render("> ") + selectedItemStyle.Render(p.Title) + " " + diffStyle.Render(p.Difficulty))
+		} else {
+			b.WriteString(fmt.Sprintf("  %-30s %s", p.Title, diffStyle.Render(p.Difficulty)))
 		}
-		
-		b.WriteString(line + "\n")
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// viewProblemPreview describes a problem in brief: its patterns, the
+// companies that ask it and the start of its description, wrapped to width
+func (m Model) viewProblemPreview(p problem.Problem, width int) string {
+	var b strings.Builder
+	b.WriteString(selectedItemStyle.Render(p.Title))
+	b.WriteString("\n")
+	b.WriteString(getDifficultyStyle(p.Difficulty).Render(p.Difficulty))
+	if len(p.Patterns) > 0 {
+		b.WriteString(" • " + strings.Join(p.Patterns, ", "))
 	}
-	
-	// Help text
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		MarginTop(2)
-	
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: Navigate • Enter: Select • Esc: Back"))
-	
+	if len(p.Companies) > 0 {
+		b.WriteString(mutedStyle.Render("Asked by " + strings.Join(p.Companies, ", ")))
+		b.WriteString("\n")
+	}
+	if p.Description != "" {
+		b.WriteString("\n")
+		wrapped := lipgloss.NewStyle().Width(width).Render(strings.TrimSpace(p.Description))
+		b.WriteString(previewLines(wrapped, 8))
+	}
 	return b.String()
-}
\ No newline at end of file
+}
+
+// previewLines returns up to n lines of text, marking any cut
+func previewLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:n], "\n") + "\n…"
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemListFinder(t *testing.T) {
+	m := NewModel()
+	m.ready = true
+	m.width = 120
+	m.height = 40
+	m = m.navigate(StateProblemList)
+	m.problems.pattern = "Sliding Window"
+	m, _ = m.updateProblemList(problemsLoadedMsg{problems: []problem.Problem{
+		{ID: "max_subarray", Title: "Maximum Subarray", Difficulty: "medium", Patterns: []string{"sliding-window"}, Companies: []string{"Amazon"}},
+		{ID: "min_window", Title: "Minimum Window Substring", Difficulty: "hard", Patterns: []string{"sliding-window"}, Companies: []string{"Google"},
+			Description: "Find the smallest window of s holding every character of t."},
+		{ID: "longest_substring", Title: "Longest Substring", Difficulty: "medium", Patterns: []string{"sliding-window"}, Companies: []string{"Google"}},
+	}})
+
+	press := func(m Model, keys ...tea.KeyMsg) Model {
+		for _, k := range keys {
+			next, _ := m.Update(k)
+			m = next.(Model)
+		}
+		return m
+	}
+	typed := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	// Letters the rest of the TUI binds, such as q, are typed into the filter
+	m = press(m, typed("g"), typed("o"), typed("o"), typed("q"))
+	assert.Equal(t, StateProblemList, m.state)
+	assert.Equal(t, "gooq", m.problems.query)
+	assert.Empty(t, m.problems.matches)
+	assert.Contains(t, m.View(), "No problems match.")
+
+	// Backspace edits the filter; the matches keep the best ranked first
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	require.Len(t, m.problems.matches, 2)
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyUp})
+	selected, ok := m.problems.selected()
+	require.True(t, ok)
+	assert.Equal(t, "min_window", selected.ID)
+
+	view := m.View()
+	assert.Contains(t, view, "2 of 3")
+	assert.Contains(t, view, "Asked by Google")
+	assert.Contains(t, view, "Find the smallest window", "the selected problem is previewed")
+
+	// Esc clears the filter before it goes back
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, StateProblemList, m.state)
+	assert.Empty(t, m.problems.query)
+	assert.Len(t, m.problems.matches, 3)
+
+	m = press(m, typed("min"), tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, StateProblemDetail, m.state)
+	assert.Equal(t, "min_window", m.problemDetail.problem.ID)
+}
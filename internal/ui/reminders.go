@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/stats"
+)
+
+// Practice reminder messages
+type remindersLoadedMsg struct {
+	reminders []stats.PatternRetention
+}
+
+type refresherRequestedMsg struct {
+	pattern string
+}
+
+type refresherLoadedMsg struct {
+	pattern string
+	problem problem.Problem
+}
+
+// maxHomeReminders caps the reminders listed on the home screen
+const maxHomeReminders = 3
+
+// loadReminders loads the patterns predicted to decay soon
+func loadReminders() tea.Cmd {
+	return func() tea.Msg {
+		reminders, err := stats.GetReminders()
+		if err != nil {
+			return nil
+		}
+		return remindersLoadedMsg{reminders: reminders}
+	}
+}
+
+// loadRefresher picks the problem for a refresher session on a pattern
+func loadRefresher(pattern string) tea.Cmd {
+	return func() tea.Msg {
+		problems, err := problem.LoadLocalProblems()
+		if err != nil {
+			return problemsErrorMsg{err: err}
+		}
+
+		// Without history every problem counts as never practiced
+		sessions, _ := stats.GetAllSessions()
+
+		prob := pickRefresher(filterProblemsByPattern(problem.Visible(problems), pattern), sessions)
+		if prob == nil {
+			return problemsErrorMsg{err: fmt.Errorf("no problems found for pattern %s", pattern)}
+		}
+		return refresherLoadedMsg{pattern: pattern, problem: *prob}
+	}
+}
+
+// pickRefresher returns the problem practiced least recently, preferring
+// problems that were never practiced
+func pickRefresher(problems []problem.Problem, sessions []stats.SessionStats) *problem.Problem {
+	lastPracticed := make(map[string]time.Time)
+	for _, session := range sessions {
+		if session.StartTime.After(lastPracticed[session.ProblemID]) {
+			lastPracticed[session.ProblemID] = session.StartTime
+		}
+	}
+
+	var picked *problem.Problem
+	for i := range problems {
+		if picked == nil || lastPracticed[problems[i].ID].Before(lastPracticed[picked.ID]) {
+			picked = &problems[i]
+		}
+	}
+	return picked
+}
@@ -0,0 +1,152 @@
+// Package replay steps through a recorded session's timeline in the
+// terminal, showing the code as it stood at each event
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// eventsShown is how many events the timeline lists around the current one
+const eventsShown = 9
+
+// runProgram runs a Bubble Tea program
+// Exported as variable for testing
+var runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	return tea.NewProgram(m, opts...).Run()
+}
+
+// Run steps through a timeline until the user quits
+func Run(t timeline.Timeline) error {
+	if _, err := runProgram(New(t), tea.WithAltScreen()); err != nil {
+		return fmt.Errorf("error running replay: %v", err)
+	}
+	return nil
+}
+
+// Model steps through a timeline, one event at a time
+type Model struct {
+	timeline timeline.Timeline
+	current  int
+	quit     bool
+}
+
+// New creates a model at the first event of a timeline
+func New(t timeline.Timeline) Model {
+	return Model{timeline: t}
+}
+
+// Current returns the index of the event shown
+func (m Model) Current() int {
+	return m.current
+}
+
+// Init implements tea.Model
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	last := len(m.timeline.Events) - 1
+	switch key.String() {
+	case "right", "l", "n", " ":
+		m.current = min(m.current+1, max(last, 0))
+	case "left", "h", "p":
+		m.current = max(m.current-1, 0)
+	case "home", "g":
+		m.current = 0
+	case "end", "G":
+		m.current = max(last, 0)
+	case "esc", "q", "ctrl+c":
+		m.quit = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View implements tea.Model
+func (m Model) View() string {
+	if m.quit {
+		return ""
+	}
+	t := theme.Current()
+	title := lipgloss.NewStyle().Bold(true).Foreground(t.Primary)
+	muted := lipgloss.NewStyle().Foreground(t.Muted)
+	added := lipgloss.NewStyle().Foreground(t.Success)
+	selected := lipgloss.NewStyle().Bold(true).Foreground(t.Secondary)
+
+	var b strings.Builder
+	b.WriteString(title.Render(fmt.Sprintf("Replay: %s (%s)", m.timeline.Title, m.timeline.Language)))
+	b.WriteString("\n")
+	b.WriteString(muted.Render(fmt.Sprintf("Session %s, started %s", m.timeline.SessionID, m.timeline.StartedAt.Local().Format("Jan 2, 2006 3:04 PM"))))
+	b.WriteString("\n\n")
+
+	events := m.timeline.Events
+	if len(events) == 0 {
+		b.WriteString("Nothing was recorded in this session.\n\n")
+		b.WriteString(muted.Render("q: Quit"))
+		return m.plain(b.String())
+	}
+
+	e := events[m.current]
+	b.WriteString(selected.Render(fmt.Sprintf("Step %d of %d", m.current+1, len(events))))
+	b.WriteString(fmt.Sprintf("  %s  %s\n\n", timeline.FormatElapsed(e.Elapsed), e.Describe()))
+
+	// The code as it stood, marking the lines this snapshot added
+	code := m.timeline.Code(m.current)
+	if code == "" {
+		b.WriteString(muted.Render("No code saved yet."))
+		b.WriteString("\n")
+	} else {
+		var before map[string]bool
+		if e.Kind == timeline.KindSnapshot {
+			before = make(map[string]bool)
+			for _, line := range strings.Split(m.timeline.Code(m.current-1), "\n") {
+				before[line] = true
+			}
+		}
+		for i, line := range strings.Split(code, "\n") {
+			marker := "  "
+			if before != nil && !before[line] && strings.TrimSpace(line) != "" {
+				marker = added.Render("+ ")
+			}
+			b.WriteString(fmt.Sprintf("%s %s%s\n", muted.Render(fmt.Sprintf("%3d", i+1)), marker, line))
+		}
+	}
+
+	// The events around the current one
+	b.WriteString("\n")
+	from := max(0, min(m.current-eventsShown/2, len(events)-eventsShown))
+	for i := from; i < len(events) && i < from+eventsShown; i++ {
+		line := fmt.Sprintf("%s  %s", timeline.FormatElapsed(events[i].Elapsed), events[i].Describe())
+		if i == m.current {
+			b.WriteString(selected.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(muted.Render("←/→: Step • g/G: First/Last • q: Quit"))
+	return m.plain(b.String())
+}
+
+// plain draws the view without styling in accessible mode
+func (m Model) plain(view string) string {
+	if access.Enabled() {
+		return access.Plain(view)
+	}
+	return view
+}
@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func press(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	var model tea.Model = m
+	for _, key := range keys {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	return model.(Model)
+}
+
+func sampleTimeline() timeline.Timeline {
+	return timeline.Timeline{
+		SessionID: "two_sum-1772647200",
+		Title:     "Two Sum",
+		Language:  "go",
+		StartedAt: time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC),
+		Events: []timeline.Event{
+			{Kind: timeline.KindSnapshot, Elapsed: time.Minute, Code: "func twoSum() {\n}"},
+			{Kind: timeline.KindTest, Elapsed: 3 * time.Minute, Passed: 1, Total: 3},
+			{Kind: timeline.KindSnapshot, Elapsed: 5 * time.Minute, Code: "func twoSum() {\n\treturn nil\n}"},
+			{Kind: timeline.KindFinish, Elapsed: 6*time.Minute + 5*time.Second, Solved: true},
+		},
+	}
+}
+
+func TestStepping(t *testing.T) {
+	m := New(sampleTimeline())
+	view := m.View()
+	assert.Contains(t, view, "Replay: Two Sum (go)")
+	assert.Contains(t, view, "Step 1 of 4")
+	assert.Contains(t, view, "00:01:00  Saved code (2 lines)")
+	assert.Contains(t, view, "  1 + func twoSum() {")
+
+	// Test runs show the code they ran against, without marking it
+	m = press(t, m, "l")
+	assert.Equal(t, 1, m.Current())
+	view = m.View()
+	assert.Contains(t, view, "> 00:03:00  Ran tests: 1/3 passed")
+	assert.Contains(t, view, "  1   func twoSum() {")
+
+	// Only the lines a snapshot added are marked
+	m = press(t, m, "n")
+	view = m.View()
+	assert.Contains(t, view, "  1   func twoSum() {")
+	assert.Contains(t, view, "  2 + \treturn nil")
+
+	assert.Equal(t, 3, press(t, m, "G", "l").Current(), "stepping stops at the last event")
+	assert.Equal(t, 0, press(t, m, "h", "h", "h").Current(), "and at the first")
+	assert.Equal(t, 0, press(t, m, "G", "g").Current())
+	assert.Empty(t, press(t, m, "q").View(), "the replay clears itself when done")
+}
+
+func TestEmptyTimeline(t *testing.T) {
+	m := New(timeline.Timeline{Title: "Two Sum"})
+	assert.Contains(t, m.View(), "Nothing was recorded in this session.")
+	assert.Equal(t, 0, press(t, m, "l", "G").Current())
+}
+
+func TestRun(t *testing.T) {
+	original := runProgram
+	t.Cleanup(func() { runProgram = original })
+	var started Model
+	runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+		started = m.(Model)
+		return m, nil
+	}
+
+	require.NoError(t, Run(sampleTimeline()))
+	assert.Equal(t, "two_sum-1772647200", started.timeline.SessionID)
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/ui/splitscreen"
+)
+
+// pausedSessionMsg carries the session saved for resuming, nil if none
+type pausedSessionMsg struct {
+	state *resume.State
+}
+
+// loadPausedSession loads the session left in progress
+func loadPausedSession() tea.Cmd {
+	return func() tea.Msg {
+		state, err := resume.Load()
+		if err != nil {
+			return nil
+		}
+		return pausedSessionMsg{state: state}
+	}
+}
+
+// resumePausedSession reopens the session left in progress in the
+// split-screen UI, then reloads what is left saved
+func resumePausedSession(state resume.State) tea.Cmd {
+	return func() tea.Msg {
+		if err := splitscreen.Resume(state); err != nil {
+			return sessionErrorMsg{err: err}
+		}
+		return loadPausedSession()()
+	}
+}
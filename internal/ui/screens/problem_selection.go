@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
@@ -47,7 +48,7 @@ func NewProblemSelectionModel(allProblems []problem.Problem, language, mode stri
 	// Create spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Style = lipgloss.NewStyle().Foreground(theme.Current().Primary)
 	
 	return ProblemSelectionModel{
 		State:             StatePatternSelection,
@@ -275,13 +276,13 @@ func (m ProblemSelectionModel) renderProblemList() string {
 		var difficultyStyle lipgloss.Style
 		switch prob.Difficulty {
 		case "easy":
-			difficultyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#2ecc71"))
+			difficultyStyle = lipgloss.NewStyle().Foreground(theme.Current().Success)
 		case "medium":
-			difficultyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f1c40f"))
+			difficultyStyle = lipgloss.NewStyle().Foreground(theme.Current().Warning)
 		case "hard":
-			difficultyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#e74c3c"))
+			difficultyStyle = lipgloss.NewStyle().Foreground(theme.Current().Error)
 		default:
-			difficultyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7f8c8d"))
+			difficultyStyle = lipgloss.NewStyle().Foreground(theme.Current().Muted)
 		}
 		
 		// Format option
@@ -10,12 +10,15 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/bubbles/timer"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
@@ -27,6 +30,7 @@ type SessionKeyMap struct {
 	RunTests     key.Binding
 	Submit       key.Binding
 	Skip         key.Binding
+	Pause        key.Binding
 	Help         key.Binding
 	Quit         key.Binding
 }
@@ -58,6 +62,10 @@ func NewSessionKeyMap() SessionKeyMap {
 			key.WithKeys("n"),
 			key.WithHelp("n", "next problem"),
 		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause timer"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "toggle help"),
@@ -86,7 +94,7 @@ type SessionModel struct {
 	ProblemViewport viewport.Model
 	CodeViewport    viewport.Model
 	CodeInput       textinput.Model
-	Timer           timer.Model
+	Clock           *clock.Clock
 	TimeRemaining   time.Duration
 	Spinner         spinner.Model
 	Help            help.Model
@@ -117,6 +125,7 @@ type TestResult struct {
 	Expected string
 	Actual   string
 	Passed   bool
+	Duration time.Duration
 }
 
 // NewSessionModel creates a new session model
@@ -127,7 +136,7 @@ func NewSessionModel(prob *problem.Problem, mode, language string, currentPatter
 	// Create help component
 	help := help.New()
 
-	// Create timer
+	// Create session clock
 	var timerDuration time.Duration
 	switch mode {
 	case "learn":
@@ -139,16 +148,14 @@ func NewSessionModel(prob *problem.Problem, mode, language string, currentPatter
 	default:
 		timerDuration = 30 * time.Minute
 	}
-	
-	t := timer.NewWithInterval(timerDuration, time.Second)
 
 	// Create spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Style = lipgloss.NewStyle().Foreground(theme.Current().Primary)
 
 	// Create syntax highlighter
-	syntaxHighlighter := highlight.NewSyntaxHighlighter("monokai")
+	syntaxHighlighter := highlight.NewSyntaxHighlighter("")
 
 	// Create pattern visualization
 	patternViz := view.NewPatternVisualization()
@@ -170,7 +177,7 @@ func NewSessionModel(prob *problem.Problem, mode, language string, currentPatter
 		CurrentPattern:    currentPattern,
 		KeyMap:            keyMap,
 		Help:              help,
-		Timer:             t,
+		Clock:             clock.NewCountdown(timerDuration),
 		Spinner:           s,
 		Message:           "Press '?' for help, 'e' to open editor",
 		MessageStyle:      view.InfoStyle,
@@ -182,8 +189,9 @@ func NewSessionModel(prob *problem.Problem, mode, language string, currentPatter
 
 // Init initializes the session model
 func (m SessionModel) Init() tea.Cmd {
+	m.Clock.Start()
 	return tea.Batch(
-		m.Timer.Init(),
+		view.ClockTick(m.Clock),
 		spinner.Tick,
 	)
 }
@@ -323,12 +331,14 @@ func (m SessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							Expected: "Expected 1",
 							Actual:   "Actual 1",
 							Passed:   true,
+							Duration: 3 * time.Millisecond,
 						},
 						{
 							Input:    "Input 2",
 							Expected: "Expected 2",
 							Actual:   "Not matching",
 							Passed:   false,
+							Duration: 2 * time.Millisecond,
 						},
 					},
 					AllPassed: false,
@@ -341,6 +351,16 @@ func (m SessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.MessageStyle = view.SuccessStyle
 			return m, nil
 
+		case key.Matches(msg, m.KeyMap.Pause):
+			m.Clock.Toggle()
+			if m.Clock.Paused() {
+				m.Message = "Timer paused"
+			} else {
+				m.Message = "Timer resumed"
+			}
+			m.MessageStyle = view.InfoStyle
+			return m, nil
+
 		case key.Matches(msg, m.KeyMap.Skip):
 			if m.Mode == "cram" && !m.ProblemCompleted {
 				// In Cram mode, ask for confirmation before skipping
@@ -352,14 +372,23 @@ func (m SessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-	case timer.TickMsg:
-		// Update the timer
-		var timerCmd tea.Cmd
-		m.Timer, timerCmd = m.Timer.Update(msg)
-		cmds = append(cmds, timerCmd)
+	case clock.TickMsg:
+		// Update time remaining from the session clock
+		m.TimeRemaining = msg.Remaining
+
+		if msg.Expired {
+			// Timer has expired
+			m.Message = "Time's up!"
+			m.MessageStyle = view.ErrorStyle
 
-		// Update time remaining
-		m.TimeRemaining = m.Timer.Timeout
+			// In cram mode, move to next problem
+			if m.Mode == "cram" {
+				m.Message += " Moving to next problem..."
+				return m, tea.Quit
+			}
+			break
+		}
+		cmds = append(cmds, view.ClockTick(m.Clock))
 
 		// Change timer style if less than 5 minutes left
 		if m.TimeRemaining < 5*time.Minute && m.TimeRemaining > 0 {
@@ -369,17 +398,6 @@ func (m SessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case timer.TimeoutMsg:
-		// Timer has expired
-		m.Message = "Time's up!"
-		m.MessageStyle = view.ErrorStyle
-
-		// In cram mode, move to next problem
-		if m.Mode == "cram" {
-			m.Message += " Moving to next problem..."
-			return m, tea.Quit
-		}
-
 	case spinner.TickMsg:
 		// Update the spinner
 		var spinnerCmd tea.Cmd
@@ -491,6 +509,9 @@ func (m SessionModel) formatTimer() string {
 	mins := int(m.TimeRemaining.Minutes()) % 60
 	secs := int(m.TimeRemaining.Seconds()) % 60
 	timeStr := fmt.Sprintf("%02d:%02d:%02d", hours, mins, secs)
+	if m.Clock != nil && m.Clock.Paused() {
+		timeStr += " (paused)"
+	}
 	
 	if m.TimeRemaining < 5*time.Minute {
 		return view.TimerWarningStyle.Copy().
@@ -633,19 +654,21 @@ func (m SessionModel) formatCodeContent() string {
 	// Start with the code section header
 	content := view.HeaderStyle.Render("Your Solution:") + "\n\n"
 
-	// Add highlighted code
-	highlightedCode, _ := m.SyntaxHighlighter.Highlight(m.Code, m.Language)
+	// Add highlighted code, formatted so whitespace does not distract
+	highlightedCode, _ := m.SyntaxHighlighter.Highlight(codefmt.Code(m.Language, m.Code), m.Language)
 	content += highlightedCode + "\n\n"
 
 	// Add test results if available
 	if len(m.TestResults) > 0 {
 		content += view.HeaderStyle.Render("Test Results:") + "\n\n"
 		
+		durations := make([]time.Duration, len(m.TestResults))
 		for i, result := range m.TestResults {
+			durations[i] = result.Duration
 			if result.Passed {
-				content += view.SuccessStyle.Render(fmt.Sprintf("✓ Test %d: PASSED", i+1)) + "\n"
+				content += view.SuccessStyle.Render(fmt.Sprintf("%s Test %d: PASSED", symbols.Check, i+1)) + view.FormatTestDuration(result.Duration) + "\n"
 			} else {
-				content += view.ErrorStyle.Render(fmt.Sprintf("✗ Test %d: FAILED", i+1)) + "\n"
+				content += view.ErrorStyle.Render(fmt.Sprintf("%s Test %d: FAILED", symbols.Cross, i+1)) + view.FormatTestDuration(result.Duration) + "\n"
 				content += fmt.Sprintf("  Input: %s\n", result.Input)
 				content += fmt.Sprintf("  Expected: %s\n", result.Expected)
 				content += fmt.Sprintf("  Actual: %s\n", result.Actual)
@@ -653,6 +676,8 @@ func (m SessionModel) formatCodeContent() string {
 			content += "\n"
 		}
 		
+		content += view.FormatSlowestTest(durations)
+		
 		if m.AllPassed {
 			content += view.SuccessStyle.Render("All tests passed! 🎉") + "\n"
 		}
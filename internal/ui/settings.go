@@ -7,6 +7,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/common/config"
+	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
 // Settings options
@@ -15,6 +17,8 @@ var settingsOptions = []string{
 	"Timer Duration",
 	"Editor Command",
 	"Theme",
+	"Code Theme",
+	"Auto-Submit",
 	"Reset Statistics",
 	"Clear Cache",
 }
@@ -83,7 +87,7 @@ func (m Model) viewSettings() string {
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		MarginBottom(2)
 	
 	b.WriteString(titleStyle.Render("⚙️  Settings"))
@@ -101,13 +105,13 @@ func (m Model) viewSettings() string {
 		
 		// Add current value
 		valueStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("243"))
+			Foreground(mutedColor)
 		
 		value := m.getSettingValue(i)
 		if m.settings.editing && i == m.settings.selectedOption {
 			// Show editing value
 			value = m.settings.editValue + "█"
-			valueStyle = valueStyle.Bold(true).Foreground(lipgloss.Color("214"))
+			valueStyle = valueStyle.Bold(true).Foreground(warningColor)
 		}
 		
 		line += valueStyle.Render(value)
@@ -116,7 +120,7 @@ func (m Model) viewSettings() string {
 		if i == m.settings.selectedOption {
 			line = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("212")).
+				Foreground(secondaryColor).
 				Render(line)
 		}
 		
@@ -128,7 +132,7 @@ func (m Model) viewSettings() string {
 		b.WriteString("\n")
 		messageStyle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("214"))
+			Foreground(warningColor)
 		b.WriteString(messageStyle.Render(m.settings.message))
 		b.WriteString("\n")
 	}
@@ -136,7 +140,7 @@ func (m Model) viewSettings() string {
 	// Help text
 	b.WriteString("\n")
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+		Foreground(mutedColor)
 	
 	if m.settings.editing {
 		b.WriteString(helpStyle.Render("Type to edit • Enter: Save • Esc: Cancel"))
@@ -157,10 +161,20 @@ func (m Model) getSettingValue(index int) string {
 	case 2: // Editor Command
 		return m.config.EditorCommand
 	case 3: // Theme
-		return m.config.Theme
-	case 4: // Reset Statistics
+		return theme.Current().Name
+	case 4: // Code Theme
+		if m.config.CodeTheme == "" {
+			return highlight.DefaultTheme
+		}
+		return m.config.CodeTheme
+	case 5: // Auto-Submit
+		if m.config.AutoSubmit {
+			return "On (finish when all tests pass)"
+		}
+		return "Off"
+	case 6: // Reset Statistics
 		return "Press Enter to reset"
-	case 5: // Clear Cache
+	case 7: // Clear Cache
 		return "Press Enter to clear"
 	default:
 		return "Unknown"
@@ -175,7 +189,7 @@ func (m Model) handleSettingSelection() (Model, tea.Cmd) {
 	}
 	
 	switch m.settings.selectedOption {
-	case 0, 1, 2, 3: // Editable fields
+	case 0, 1, 2, 3, 4: // Editable fields
 		m.settings.editing = true
 		m.settings.editingField = settingsOptions[m.settings.selectedOption]
 		m.settings.editValue = m.getSettingValue(m.settings.selectedOption)
@@ -183,9 +197,12 @@ func (m Model) handleSettingSelection() (Model, tea.Cmd) {
 		if m.settings.selectedOption == 1 {
 			m.settings.editValue = fmt.Sprintf("%d", m.config.TimerDuration)
 		}
-	case 4: // Reset Statistics
+	case 5: // Auto-Submit
+		m.config.AutoSubmit = !m.config.AutoSubmit
+		return m, saveConfig(m.config)
+	case 6: // Reset Statistics
 		return m.resetStatistics()
-	case 5: // Clear Cache
+	case 7: // Clear Cache
 		return m.clearCache()
 	}
 	
@@ -231,7 +248,18 @@ func (m Model) saveSettingValue() (Model, tea.Cmd) {
 		}
 		m.config.EditorCommand = m.settings.editValue
 	case 3: // Theme
-		m.config.Theme = m.settings.editValue
+		if err := saveThemePreset(m.settings.editValue); err != nil {
+			m.settings.message = err.Error()
+			m.settings.editing = false
+			return m, nil
+		}
+	case 4: // Code Theme
+		if err := highlight.SetTheme(m.settings.editValue); err != nil {
+			m.settings.message = fmt.Sprintf("Unknown code theme. Choose from: %s", strings.Join(highlight.Themes(), ", "))
+			m.settings.editing = false
+			return m, nil
+		}
+		m.config.CodeTheme = m.settings.editValue
 	}
 	
 	m.settings.editing = false
@@ -240,6 +268,25 @@ func (m Model) saveSettingValue() (Model, tea.Cmd) {
 	return m, saveConfig(m.config)
 }
 
+// saveThemePreset switches to a theme preset and saves it in the theme
+// file, keeping the colors set there
+func saveThemePreset(preset string) error {
+	f, err := theme.ReadFile(theme.Path())
+	if err != nil {
+		return err
+	}
+	f.Preset = preset
+	t, err := f.Theme()
+	if err != nil {
+		return err
+	}
+	if err := theme.WriteFile(theme.Path(), f); err != nil {
+		return err
+	}
+	theme.Set(t)
+	return nil
+}
+
 // resetStatistics resets all user statistics
 func (m Model) resetStatistics() (Model, tea.Cmd) {
 	// This would reset statistics in a real implementation
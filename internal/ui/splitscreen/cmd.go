@@ -5,6 +5,7 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/profiling"
 	"github.com/lancekrogers/algo-scales/internal/problem"
 )
 
@@ -21,6 +22,7 @@ var (
 // StartUI launches the split-screen UI with the given problem
 func StartUI(p *problem.Problem) error {
 	// Create the model
+	uiInitDone := profiling.Track("UI init")
 	m := NewModel()
 	
 	// Set the current problem if provided
@@ -28,20 +30,30 @@ func StartUI(p *problem.Problem) error {
 		m.SetProblem(p)
 	}
 	
+	uiInitDone()
+	return run(m)
+}
+
+// run runs the split-screen UI from m, saving the session as it was left
+func run(m Model) error {
 	// Program options
 	opts := []tea.ProgramOption{
 		tea.WithAltScreen(),       // Use alternate screen buffer
 		tea.WithMouseCellMotion(), // Enable mouse support
 	}
-	
+
 	// Create and run the program
-	_, err := runProgram(m, opts...)
+	final, err := runProgram(m, opts...)
 	
 	// Check for errors
 	if err != nil {
 		return fmt.Errorf("error running split-screen UI: %v", err)
 	}
 	
+	// Quitting saves the clock to the second, for 'algo-scales resume'
+	if left, ok := final.(Model); ok && left.currentProblem != nil {
+		left.saveProgress()
+	}
 	return nil
 }
 
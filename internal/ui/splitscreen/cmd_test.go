@@ -21,6 +21,7 @@ func (m *mockProgram) Run() (tea.Model, error) {
 
 // TestStartWithSampleProblem tests the sample problem initialization
 func TestStartWithSampleProblem(t *testing.T) {
+	stubSaveSession(t)
 	// Override os.Exit for testing
 	oldOsExit := osExit
 	defer func() { osExit = oldOsExit }()
@@ -6,12 +6,18 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/complexity"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+	"github.com/lancekrogers/algo-scales/internal/ui/privacy"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
 // Model represents the main application model for the split-screen UI
@@ -21,8 +27,8 @@ type Model struct {
 	windowHeight int
 
 	// Panel components
-	problemView  viewport.Model  // Left panel: Problem description
-	codeEditor   textarea.Model  // Right panel: Code editor
+	problemView  view.Viewport   // Left panel: Problem description
+	codeEditor   editor.Model    // Right panel: Code editor
 	terminal     viewport.Model  // Bottom panel: Command output
 	terminalInput textinput.Model // Bottom panel: Command input
 	
@@ -31,17 +37,22 @@ type Model struct {
 	codeLanguage    string
 	theme           ScaleTheme
 	styles          map[string]lipgloss.Style
-	elapsedTime     time.Duration
-	startTime       time.Time
+	clock           *clock.Clock // session time shown in the status bar
 	runningCommand  bool
 	showHelp        bool
+	showBigO        bool           // Big-O reference overlay
+	privacy         privacy.Screen // Blanks the panels for screen sharing or when idle
 	ready           bool
 	
 	// Current problem
 	currentProblem *problem.Problem
-	
-	// Vim mode (for code editor)
-	vimMode VimMode
+	startedAt      time.Time
+
+	// What was last saved for resuming, so unchanged sessions are not
+	// written on every key
+	savedCode     string
+	savedLanguage string
+	lastSaved     time.Time
 }
 
 // focusedPanel represents which panel currently has focus
@@ -53,22 +64,16 @@ const (
 	terminalPanel
 )
 
-// VimMode represents the current vim editing mode
-type VimMode int
-
-const (
-	NormalMode VimMode = iota
-	InsertMode
-	VisualMode
-)
-
 // NewModel creates a new model with initialized components
 func NewModel() Model {
 	// Initialize with empty components
 	// They will be properly set up once we know the terminal dimensions
 	
 	// Set default theme
-	defaultTheme := MajorTheme
+	defaultTheme := FromTheme(theme.Current())
+	
+	sessionClock := clock.NewStopwatch()
+	sessionClock.Start()
 	
 	return Model{
 		// Default values
@@ -76,9 +81,11 @@ func NewModel() Model {
 		codeLanguage: "go",      // Default language
 		theme:        defaultTheme,
 		styles:       ThemeStyles(defaultTheme),
-		vimMode:      InsertMode,
+		codeEditor:   editor.New(""),
 		showHelp:     false,
 		ready:        false,
+		clock:        sessionClock,
+		privacy:      privacy.New(privacy.BlankAfter, time.Now()),
 	}
 }
 
@@ -91,8 +98,15 @@ func (m Model) Init() tea.Cmd {
 }
 
 
-// Update implements tea.Model
+// Update implements tea.Model, saving the session for resuming as it
+// changes
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m.persist(time.Now()), cmd
+}
+
+// update handles a message
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -103,10 +117,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 		
 	case tea.KeyMsg:
+		// Blanked panels only come back, whatever the key
+		if m.privacy.HandleKey(msg.String(), time.Now()) {
+			return m, nil
+		}
+		
+		// Outside normal mode, the code editor takes every key but quitting
+		// and switching language
+		if m.focusedPanel == codePanel && m.codeEditor.Mode() != editor.Normal && msg.String() != "ctrl+c" && msg.String() != "ctrl+s" {
+			var cmd tea.Cmd
+			m.codeEditor, cmd = m.codeEditor.Update(msg)
+			return m, cmd
+		}
+		
 		// Handle global key presses first
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
 			return m, tea.Quit
+		case "esc":
+			// Esc is the editor's in the code panel
+			if m.focusedPanel != codePanel {
+				return m, tea.Quit
+			}
 			
 		case "tab":
 			// Cycle focus between panels
@@ -140,6 +172,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		
+		// The Big-O reference opens from the problem panel, where b is not
+		// typed, and b closes it from anywhere
+		if msg.String() == "b" && (m.showBigO || m.focusedPanel == problemPanel) {
+			m.showBigO = !m.showBigO
+			return m, nil
+		}
+		
 		// Route key messages to the focused panel
 		switch m.focusedPanel {
 		case problemPanel:
@@ -182,6 +221,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
+	case editor.SaveMsg:
+		// The code stays in the editor, where the terminal's commands read it
+		m.codeEditor.MarkSaved()
+		m.record(timeline.KindSnapshot)
+		if msg.Close {
+			return m, tea.Quit
+		}
+		
+	case editor.CloseMsg:
+		return m, tea.Quit
+		
 	case execResultMsg:
 		// Process command execution results
 		m.runningCommand = false
@@ -189,8 +239,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.terminal.GotoBottom()
 		
 	case statusTickMsg:
-		// Update elapsed time
-		m.elapsedTime = time.Since(m.startTime)
+		// Redraw the status bar from the session clock
+		m.privacy.Tick(time.Now())
 		cmds = append(cmds, waitForActivity(time.Second))
 	}
 
@@ -259,16 +309,7 @@ func (m Model) View() string {
 	
 	// Add vim mode indicator to code editor title if in code panel
 	if m.focusedPanel == codePanel {
-		var modeText string
-		switch m.vimMode {
-		case NormalMode:
-			modeText = " [NORMAL] "
-		case InsertMode:
-			modeText = " [INSERT] "
-		case VisualMode:
-			modeText = " [VISUAL] "
-		}
-		codeTitle += modeText
+		codeTitle += " [" + m.codeEditor.Mode().String() + "] "
 	}
 	
 	// Apply title styles with border titles
@@ -297,23 +338,29 @@ func (m Model) View() string {
 	}
 
 	// Render panel content
-	leftPanelRendered := problemPanelStyle.Render(m.problemView.View())
-	rightPanelRendered := codePanelStyle.Render(m.codeEditor.View())
+	problemContent, codeContent := m.problemView.View(), m.codeEditor.View()
+	terminalContent := m.terminal.View() + "\n\n> " + m.terminalInput.View()
+	if m.privacy.Blanked() {
+		problemContent = privacy.View(leftPanelWidth-4, topSectionHeight-2)
+		codeContent, terminalContent = "", ""
+	}
+	leftPanelRendered := problemPanelStyle.Render(problemContent)
+	rightPanelRendered := codePanelStyle.Render(codeContent)
 	
 	// Combine terminal viewport and input for bottom panel
-	terminalContent := m.terminal.View() + "\n\n> " + m.terminalInput.View()
 	bottomPanelRendered := bottomPanelStyle.Render(terminalContent)
 
 	// Format status bar
-	hours := int(m.elapsedTime.Hours())
-	minutes := int(m.elapsedTime.Minutes()) % 60
-	seconds := int(m.elapsedTime.Seconds()) % 60
+	elapsed := m.clock.Elapsed()
+	hours := int(elapsed.Hours())
+	minutes := int(elapsed.Minutes()) % 60
+	seconds := int(elapsed.Seconds()) % 60
 	timeStr := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.theme.BrightColor)).
 		Render(
 			lipgloss.NewStyle().Bold(true).Render("Time:") + 
 			lipgloss.NewStyle().Render(
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#f8e71c")).
+				lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.ContrastColor)).
 				Render(
 					lipgloss.NewStyle().Bold(true).
 					Render(
@@ -411,7 +458,7 @@ func (m Model) View() string {
 	// Format key bindings
 	keybindingsStr := "Tab: Switch Panel | Ctrl+S: Switch Language | ?: Toggle Help | Ctrl+C: Quit"
 	if m.showHelp {
-		keybindingsStr = "k/j: Scroll Up/Down | Ctrl+R: Run Code | Esc: Exit Help | Tab: Switch Panel"
+		keybindingsStr = "k/j: Scroll Up/Down | b: Big-O Reference | Ctrl+R: Run Code | Ctrl+L: Hide | Esc: Normal Mode | :w/:q: Save/Quit | Tab: Switch Panel"
 	}
 	
 	helpStr := lipgloss.NewStyle().
@@ -422,7 +469,7 @@ func (m Model) View() string {
 	statusBarStyle := lipgloss.NewStyle().
 		Width(m.windowWidth).
 		Padding(0, 1).
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(lipgloss.Color(m.theme.BrightColor)).
 		Background(lipgloss.Color(m.theme.BaseColor))
 	
 	// Format status bar content with proper spacing
@@ -440,11 +487,23 @@ func (m Model) View() string {
 
 	// Join horizontal panels (left and right)
 	topSection := lipgloss.JoinHorizontal(lipgloss.Top, leftPanelRendered, rightPanelRendered)
+	if m.showBigO && !m.privacy.Blanked() {
+		topSection = lipgloss.Place(m.windowWidth, lipgloss.Height(topSection), lipgloss.Center, lipgloss.Center, m.bigOOverlay())
+	}
 	
 	// Join vertical sections (top, bottom, and status bar)
 	return lipgloss.JoinVertical(lipgloss.Left, topSection, bottomPanelRendered, statusBar)
 }
 
+// bigOOverlay renders the Big-O reference tables in a box
+func (m Model) bigOOverlay() string {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.theme.AccentColor)).
+		Padding(0, 1).
+		Render(complexity.Render())
+}
+
 // updateWindowSize updates the window dimensions and adjusts all components accordingly
 func (m Model) updateWindowSize(width, height int) Model {
 	m.windowWidth = width
@@ -456,15 +515,11 @@ func (m Model) updateWindowSize(width, height int) Model {
 	topSectionHeight := height - 10 // Bottom panel is 10 rows high
 	
 	// Adjust problem view
-	m.problemView = viewport.New(leftPanelWidth-4, topSectionHeight-2) // Adjust for border and padding
+	m.problemView = view.NewViewport(leftPanelWidth-4, topSectionHeight-2) // Adjust for border and padding
 	m.problemView.SetContent("Loading problem description...")
 	
-	// Adjust code editor
-	m.codeEditor = textarea.New()
-	m.codeEditor.SetWidth(rightPanelWidth - 4)
-	m.codeEditor.SetHeight(topSectionHeight - 2)
-	m.codeEditor.ShowLineNumbers = true
-	m.codeEditor.Placeholder = "// Write your code here"
+	// Adjust code editor, keeping the code typed so far
+	m.codeEditor.SetSize(rightPanelWidth-4, topSectionHeight-2)
 	
 	// Adjust terminal
 	m.terminal = viewport.New(width-4, 6) // Adjust for border and padding
@@ -486,6 +541,7 @@ func (m Model) updateWindowSize(width, height int) Model {
 // SetProblem sets the current problem and updates the problem view
 func (m *Model) SetProblem(p *problem.Problem) {
 	m.currentProblem = p
+	m.startedAt = time.Now()
 	
 	// Format the problem description
 	description := fmt.Sprintf("# %s\n\n", p.Title)
@@ -526,8 +582,6 @@ func (m *Model) SetFocus(panel focusedPanel) {
 	switch panel {
 	case terminalPanel:
 		m.terminalInput.Focus()
-	case codePanel:
-		m.codeEditor.Focus()
 	}
 }
 
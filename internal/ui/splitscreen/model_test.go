@@ -6,11 +6,12 @@ import (
 	"testing"
 	"time"
 
-	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+	"github.com/lancekrogers/algo-scales/internal/ui/privacy"
 )
 
 // TestModelInit tests that the model initializes correctly
@@ -26,8 +27,8 @@ func TestModelInit(t *testing.T) {
 		t.Errorf("expected default focused panel to be codePanel, got %v", m.focusedPanel)
 	}
 	
-	if m.vimMode != InsertMode {
-		t.Errorf("expected default vim mode to be InsertMode, got %v", m.vimMode)
+	if m.codeEditor.Mode() != editor.Normal {
+		t.Errorf("expected the code editor to start in normal mode, got %v", m.codeEditor.Mode())
 	}
 	
 	// Check that the model initializes
@@ -316,15 +317,18 @@ func TestTimerUpdate(t *testing.T) {
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
 	m = newModel.(Model)
 	
-	// Set a start time
-	initialTime := m.elapsedTime
+	// Drive the session clock from a fake time source
+	now := time.Now()
+	m.clock.WithNow(func() time.Time { return now })
+	initialTime := m.clock.Elapsed()
+	now = now.Add(time.Second)
 	
 	// Send a timer tick
 	newModel, _ = m.Update(statusTickMsg{})
 	m = newModel.(Model)
 	
 	// Time should have been updated
-	if m.elapsedTime <= initialTime {
+	if m.clock.Elapsed() <= initialTime {
 		t.Errorf("expected elapsed time to increase after tick")
 	}
 }
@@ -428,20 +432,118 @@ func (m mockViewport) View() string {
 	return m.content
 }
 
-type mockTextarea struct {
-	textarea.Model
+type mockTextinput struct {
+	textinput.Model
 	content string
 }
 
-func (m mockTextarea) View() string {
+func (m mockTextinput) View() string {
 	return m.content
 }
+// TestBigOOverlay tests that b toggles the Big-O reference without taking
+// the key away from the code editor
+func TestBigOOverlay(t *testing.T) {
+	next, _ := NewModel().Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	m := next.(Model)
+	b := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}}
 
-type mockTextinput struct {
-	textinput.Model
-	content string
+	// In the code editor, b is the editor's
+	next, _ = m.Update(b)
+	m = next.(Model)
+	if m.showBigO {
+		t.Error("expected b to be left to the code editor")
+	}
+
+	m.focusedPanel = problemPanel
+	next, _ = m.Update(b)
+	m = next.(Model)
+	if !m.showBigO {
+		t.Fatal("expected b to open the Big-O reference from the problem panel")
+	}
+	if !strings.Contains(m.View(), "Balanced BST") {
+		t.Error("expected the Big-O reference in the view")
+	}
+
+	// b closes the reference from any panel
+	m.focusedPanel = codePanel
+	next, _ = m.Update(b)
+	m = next.(Model)
+	if m.showBigO {
+		t.Error("expected b to close the Big-O reference")
+	}
 }
 
-func (m mockTextinput) View() string {
-	return m.content
-}
\ No newline at end of file
+// TestCodeEditorKeys tests that the code editor gets its keys, and that
+// :wq quits
+func TestCodeEditorKeys(t *testing.T) {
+	next, _ := NewModel().Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	m := next.(Model)
+	keys := func(keys ...tea.KeyMsg) tea.Cmd {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			next, cmd = m.Update(k)
+			m = next.(Model)
+		}
+		return cmd
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// Insert mode types ? and tab rather than toggling help or switching panel
+	if cmd := keys(runes("i"), runes("x?"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil {
+		t.Error("expected esc to leave insert mode, not quit")
+	}
+	if m.codeEditor.Value() != "x?\t" || m.showHelp || m.focusedPanel != codePanel {
+		t.Errorf("expected the keys to be typed, got %q", m.codeEditor.Value())
+	}
+	if !strings.Contains(m.View(), "-- NORMAL --") {
+		t.Error("expected the code panel to show the editor's mode")
+	}
+
+	cmd := keys(runes(":"), runes("wq"), tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected :wq to save")
+	}
+	next, cmd = m.Update(cmd())
+	m = next.(Model)
+	if m.codeEditor.Dirty() {
+		t.Error("expected the code to be saved")
+	}
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Error("expected :wq to quit")
+	}
+}
+
+func TestPrivacyBlank(t *testing.T) {
+	stubSaveSession(t)
+	next, _ := NewModel().Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	m := next.(Model)
+	m.SetProblem(&problem.Problem{Title: "Two Sum", Description: "Find two numbers"})
+	m.codeEditor.SetValue("func twoSum() {}")
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = next.(Model)
+	view := m.View()
+	if strings.Contains(view, "Find two numbers") || strings.Contains(view, "twoSum") {
+		t.Error("expected the problem and code to be hidden")
+	}
+	if !strings.Contains(view, "Press any key to show the session.") {
+		t.Error("expected the blanked panels to say how to restore them")
+	}
+
+	// The key that restores the panels is not typed into the editor
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = next.(Model)
+	if m.privacy.Blanked() || !strings.Contains(m.View(), "Find two numbers") {
+		t.Error("expected any key to restore the panels")
+	}
+	if m.codeEditor.Value() != "func twoSum() {}" {
+		t.Errorf("expected the restoring key to be swallowed, got code %q", m.codeEditor.Value())
+	}
+
+	// Panels blank themselves once idle
+	m.privacy = privacy.New(time.Minute, time.Now().Add(-2*time.Minute))
+	next, _ = m.Update(statusTickMsg{})
+	if !next.(Model).privacy.Blanked() {
+		t.Error("expected idle panels to blank themselves")
+	}
+}
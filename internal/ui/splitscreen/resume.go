@@ -0,0 +1,96 @@
+// Saving the session for resuming and replay, and reopening a saved one
+
+package splitscreen
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+)
+
+// saveSession keeps the session in progress for 'algo-scales resume'
+// Exported as variable for testing
+var saveSession = resume.Save
+
+// recordEvent adds to the session's timeline for 'algo-scales replay'
+// Exported as variable for testing
+var recordEvent = timeline.Record
+
+// Resume reopens a saved session with its code, language and clock as they
+// were left
+func Resume(state resume.State) error {
+	p, err := problem.GetByID(state.ProblemID)
+	if err != nil {
+		return fmt.Errorf("failed to load problem %s: %v", state.ProblemID, err)
+	}
+	sessionClock, err := state.RestoreClock()
+	if err != nil {
+		return err
+	}
+
+	m := NewModel()
+	m.SetProblem(p)
+	m.startedAt = state.StartedAt
+	m.clock = sessionClock
+	if state.Language != "" {
+		m.codeLanguage = state.Language
+	}
+	m.codeEditor = editor.New(state.Code)
+	m.record(timeline.KindResume)
+	return run(m)
+}
+
+// persist saves the session when its code or language changed, or when
+// resume.SaveEvery passed since it was last saved
+func (m Model) persist(now time.Time) Model {
+	if m.currentProblem == nil {
+		return m
+	}
+	code := m.codeEditor.Value()
+	if code == m.savedCode && m.codeLanguage == m.savedLanguage && now.Sub(m.lastSaved) < resume.SaveEvery {
+		return m
+	}
+	if err := m.saveProgress(); err != nil {
+		// Keep the work going; the next change tries again
+		return m
+	}
+	m.savedCode, m.savedLanguage, m.lastSaved = code, m.codeLanguage, now
+	return m
+}
+
+// record adds an event to the session's timeline, with the code as it
+// stands for snapshots
+func (m Model) record(kind timeline.Kind) {
+	if m.currentProblem == nil {
+		return
+	}
+	e := timeline.Event{Kind: kind, Elapsed: m.clock.Elapsed()}
+	if kind == timeline.KindSnapshot {
+		e.Code = m.codeEditor.Value()
+	}
+	// A timeline that cannot be saved does not hold up the session
+	_ = recordEvent(timeline.Timeline{
+		SessionID: timeline.NewID(m.currentProblem.ID, m.startedAt),
+		ProblemID: m.currentProblem.ID,
+		Title:     m.currentProblem.Title,
+		Language:  m.codeLanguage,
+		StartedAt: m.startedAt,
+	}, e)
+}
+
+// saveProgress saves the session as it stands
+func (m Model) saveProgress() error {
+	return saveSession(resume.State{
+		ProblemID: m.currentProblem.ID,
+		Title:     m.currentProblem.Title,
+		Language:  m.codeLanguage,
+		UI:        resume.TUI,
+		Code:      m.codeEditor.Value(),
+		Clock:     m.clock.State(),
+		StartedAt: m.startedAt,
+	})
+}
@@ -0,0 +1,115 @@
+package splitscreen
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/problem"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+	"github.com/lancekrogers/algo-scales/internal/session/resume"
+	"github.com/lancekrogers/algo-scales/internal/session/timeline"
+	"github.com/lancekrogers/algo-scales/internal/ui/editor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSaveSession records the sessions saved during the test instead of
+// writing them, and leaves their timelines unwritten
+func stubSaveSession(t *testing.T) *[]resume.State {
+	t.Helper()
+	stubRecordEvent(t)
+	var saved []resume.State
+	original := saveSession
+	saveSession = func(state resume.State) error {
+		saved = append(saved, state)
+		return nil
+	}
+	t.Cleanup(func() { saveSession = original })
+	return &saved
+}
+
+// stubRecordEvent records the timeline events of the test instead of
+// writing them
+func stubRecordEvent(t *testing.T) *[]timeline.Event {
+	t.Helper()
+	var events []timeline.Event
+	original := recordEvent
+	recordEvent = func(session timeline.Timeline, e timeline.Event) error {
+		events = append(events, e)
+		return nil
+	}
+	t.Cleanup(func() { recordEvent = original })
+	return &events
+}
+
+func TestPersist(t *testing.T) {
+	saved := stubSaveSession(t)
+	m := NewModel()
+
+	// Without a problem there is nothing to resume
+	m = m.persist(time.Now())
+	assert.Empty(t, *saved)
+
+	m.SetProblem(&problem.Problem{ID: "two_sum", Title: "Two Sum"})
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = next.(Model)
+	require.Len(t, *saved, 1, "the first update saves the new session")
+	assert.Equal(t, "two_sum", (*saved)[0].ProblemID)
+	assert.Equal(t, resume.TUI, (*saved)[0].UI)
+
+	// Only a change or the clock's interval saves again
+	now := time.Now()
+	m = m.persist(now)
+	assert.Len(t, *saved, 1)
+	m.codeEditor.SetValue("func twoSum() {}")
+	m = m.persist(now)
+	require.Len(t, *saved, 2)
+	assert.Equal(t, "func twoSum() {}", (*saved)[1].Code)
+	m = m.persist(now.Add(resume.SaveEvery))
+	assert.Len(t, *saved, 3)
+}
+
+func TestResume(t *testing.T) {
+	saved := stubSaveSession(t)
+	original := runProgram
+	t.Cleanup(func() { runProgram = original })
+	var started Model
+	runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+		started = m.(Model)
+		return m, nil
+	}
+
+	err := Resume(resume.State{
+		ProblemID: "two_sum",
+		Language:  "python",
+		Code:      "def two_sum(nums, target):\n    pass",
+		Clock:     clock.State{Mode: clock.Stopwatch, Elapsed: 10 * time.Minute},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, started.currentProblem)
+	assert.Equal(t, "two_sum", started.currentProblem.ID)
+	assert.Equal(t, "python", started.codeLanguage)
+	assert.Equal(t, "def two_sum(nums, target):\n    pass", started.codeEditor.Value())
+	assert.GreaterOrEqual(t, started.clock.Elapsed(), 10*time.Minute)
+
+	// Quitting saves the session as it was left
+	require.NotEmpty(t, *saved)
+	assert.Equal(t, "python", (*saved)[len(*saved)-1].Language)
+
+	assert.Error(t, Resume(resume.State{ProblemID: "no_such_problem"}))
+}
+
+func TestRecordSnapshot(t *testing.T) {
+	stubSaveSession(t)
+	events := stubRecordEvent(t)
+	m := NewModel()
+	m.SetProblem(&problem.Problem{ID: "two_sum", Title: "Two Sum"})
+	m.codeEditor.SetValue("func twoSum() {}")
+
+	// Saving in the editor adds the code to the timeline
+	m.Update(editor.SaveMsg{})
+	require.Len(t, *events, 1)
+	assert.Equal(t, timeline.KindSnapshot, (*events)[0].Kind)
+	assert.Equal(t, "func twoSum() {}", (*events)[0].Code)
+}
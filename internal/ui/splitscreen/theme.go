@@ -2,6 +2,7 @@ package splitscreen
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
 // ScaleTheme represents a color theme based on a musical scale
@@ -57,57 +58,70 @@ var (
 	}
 )
 
+// FromTheme maps the TUI theme onto a scale theme, which is how the split
+// screen follows the theme chosen with `algo-scales config theme`
+func FromTheme(t theme.Theme) ScaleTheme {
+	return ScaleTheme{
+		Name:          t.Name,
+		BaseColor:     string(t.Primary),
+		AccentColor:   string(t.Accent),
+		ContrastColor: string(t.Warning),
+		MutedColor:    string(t.Muted),
+		BrightColor:   string(t.Text),
+	}
+}
+
 // ThemeStyles generates Lipgloss styles from a theme
-func ThemeStyles(theme ScaleTheme) map[string]lipgloss.Style {
+func ThemeStyles(scale ScaleTheme) map[string]lipgloss.Style {
 	return map[string]lipgloss.Style{
 		"title": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.BrightColor)).
-			Background(lipgloss.Color(theme.BaseColor)).
+			Foreground(lipgloss.Color(scale.BrightColor)).
+			Background(lipgloss.Color(scale.BaseColor)).
 			Bold(true).
 			Padding(0, 1),
 			
 		"panel": lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(theme.AccentColor)).
+			BorderForeground(lipgloss.Color(scale.AccentColor)).
 			Padding(1, 2),
 			
 		"activePanel": lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(theme.BrightColor)).
+			BorderForeground(lipgloss.Color(scale.BrightColor)).
 			Padding(1, 2),
 			
 		"heading": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.AccentColor)).
+			Foreground(lipgloss.Color(scale.AccentColor)).
 			Bold(true),
 			
 		"subheading": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.ContrastColor)).
+			Foreground(lipgloss.Color(scale.ContrastColor)).
 			Bold(true),
 			
 		"text": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.BrightColor)),
+			Foreground(lipgloss.Color(scale.BrightColor)),
 			
 		"mutedText": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.MutedColor)),
+			Foreground(lipgloss.Color(scale.MutedColor)),
 			
 		"statusBar": lipgloss.NewStyle().
-			Background(lipgloss.Color(theme.BaseColor)).
-			Foreground(lipgloss.Color(theme.BrightColor)).
+			Background(lipgloss.Color(scale.BaseColor)).
+			Foreground(lipgloss.Color(scale.BrightColor)).
 			Padding(0, 1),
 			
 		"timer": lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.ContrastColor)).
+			Foreground(lipgloss.Color(scale.ContrastColor)).
 			Bold(true),
 			
 		"success": lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#2ECC71")),
+			Foreground(theme.Current().Success),
 			
 		"error": lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E74C3C")),
+			Foreground(theme.Current().Error),
 			
 		"infoBlock": lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(theme.AccentColor)).
+			BorderForeground(lipgloss.Color(scale.AccentColor)).
 			Padding(1, 2),
 	}
 }
\ No newline at end of file
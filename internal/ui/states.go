@@ -12,6 +12,8 @@ const (
 	StateStats
 	StateDaily
 	StateSettings
+	StateNotifications
+	StateCramSummary
 )
 
 // String returns the string representation of the state
@@ -33,6 +35,10 @@ func (s State) String() string {
 		return "daily"
 	case StateSettings:
 		return "settings"
+	case StateNotifications:
+		return "notifications"
+	case StateCramSummary:
+		return "cram_summary"
 	default:
 		return "unknown"
 	}
@@ -19,6 +19,7 @@ func TestStateString(t *testing.T) {
 		{StateStats, "stats"},
 		{StateDaily, "daily"},
 		{StateSettings, "settings"},
+		{StateNotifications, "notifications"},
 		{State(999), "unknown"},
 	}
 
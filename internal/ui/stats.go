@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/access"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/ui/heatmap"
+	"github.com/lancekrogers/algo-scales/internal/ui/view"
 )
 
 // Update handles updates for the stats screen
@@ -20,7 +24,7 @@ func (m Model) updateStats(msg tea.Msg) (Model, tea.Cmd) {
 		
 		// Initialize or update viewport
 		if m.stats.viewport.Width == 0 {
-			m.stats.viewport = viewport.New(msg.Width-4, msg.Height-8)
+			m.stats.viewport = view.NewViewport(msg.Width-4, msg.Height-8)
 			m.stats.viewport.SetContent(m.statsContent())
 		} else {
 			m.stats.viewport.Width = msg.Width - 4
@@ -29,6 +33,9 @@ func (m Model) updateStats(msg tea.Msg) (Model, tea.Cmd) {
 		
 	case statsLoadedMsg:
 		m.stats.summary = msg.stats
+		m.stats.calendar = msg.calendar
+		m.stats.rotation = msg.rotation
+		m.stats.goals = msg.goals
 		m.stats.loading = false
 		m.stats.viewport.SetContent(m.statsContent())
 		
@@ -49,12 +56,15 @@ func (m Model) updateStats(msg tea.Msg) (Model, tea.Cmd) {
 
 // View renders the stats screen
 func (m Model) viewStats() string {
+	if access.Enabled() {
+		return m.viewStatsAccessible()
+	}
 	var b strings.Builder
 	
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("62")).
+		Foreground(primaryColor).
 		MarginBottom(2)
 	
 	b.WriteString(titleStyle.Render("📊 Statistics"))
@@ -62,7 +72,7 @@ func (m Model) viewStats() string {
 	
 	if m.stats.loading {
 		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("214"))
+			Foreground(warningColor)
 		b.WriteString(loadingStyle.Render("Loading statistics..."))
 		return b.String()
 	}
@@ -73,27 +83,31 @@ func (m Model) viewStats() string {
 	
 	// Action bar
 	actionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
-	
-	actions := []string{
-		"r: Refresh",
-		"Esc: Back",
-	}
+		Foreground(mutedColor)
 	
-	b.WriteString(actionStyle.Render(strings.Join(actions, " • ")))
+	b.WriteString(actionStyle.Render(strings.Join(statsActions, " • ")))
 	
 	return b.String()
 }
 
+// statsActions lists the stats screen's keys
+var statsActions = []string{
+	"r: Refresh",
+	"Esc: Back",
+}
+
 // statsContent generates the content for the stats viewport
 func (m Model) statsContent() string {
+	if access.Enabled() {
+		return m.statsContentAccessible()
+	}
 	var content strings.Builder
 	s := m.stats.summary
 	
 	// Overview
 	overviewStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("212")).
+		Foreground(secondaryColor).
 		MarginBottom(1)
 	
 	content.WriteString(overviewStyle.Render("Overview"))
@@ -101,7 +115,7 @@ func (m Model) statsContent() string {
 	
 	statsBoxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(primaryColor).
 		Padding(1, 2).
 		Width(40)
 	
@@ -132,7 +146,7 @@ func (m Model) statsContent() string {
 		)
 		
 		successStyle := statsBoxStyle.Copy().
-			BorderForeground(lipgloss.Color("46"))
+			BorderForeground(successColor)
 		
 		content.WriteString(successStyle.Render(fastestContent))
 		content.WriteString("\n\n")
@@ -151,12 +165,44 @@ func (m Model) statsContent() string {
 		)
 		
 		challengeStyle := statsBoxStyle.Copy().
-			BorderForeground(lipgloss.Color("214"))
+			BorderForeground(warningColor)
 		
 		content.WriteString(challengeStyle.Render(challengingContent))
 		content.WriteString("\n\n")
 	}
 	
+	// Progress toward the user's goals
+	if len(m.stats.goals) > 0 {
+		content.WriteString(overviewStyle.Render("🎯 Goals"))
+		content.WriteString("\n\n")
+		for _, p := range m.stats.goals {
+			line := fmt.Sprintf("%s %d/%d  %s", renderProgressBar(p.Fraction(), 20), p.Current, p.Target, p.Description)
+			if p.Done {
+				line += " " + lipgloss.NewStyle().Foreground(successColor).Render(symbols.Check.String())
+			}
+			content.WriteString(line + "\n")
+		}
+		content.WriteString("\n")
+	}
+	
+	// Practice calendar of the past year
+	if len(m.stats.calendar.Days) > 0 {
+		content.WriteString(overviewStyle.Render("📅 Practice Calendar"))
+		content.WriteString("\n\n")
+		content.WriteString(heatmap.Summary(m.stats.calendar))
+		content.WriteString("\n\n")
+		content.WriteString(heatmap.Render(m.stats.calendar))
+		content.WriteString("\n\n")
+	}
+	
+	// Patterns of the daily rotation's coming weeks
+	if len(m.stats.rotation) > 0 {
+		content.WriteString(overviewStyle.Render("🔁 Weekly Rotation"))
+		content.WriteString("\n\n")
+		content.WriteString(strings.Join(daily.DescribePlan(m.stats.rotation), "\n"))
+		content.WriteString("\n\n")
+	}
+	
 	// Pattern Breakdown (if we add PatternStats in the future)
 	
 	return content.String()
@@ -2,144 +2,204 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
-// Common colors
+// Common colors, from the theme in use
 var (
-	primaryColor   = lipgloss.Color("62")  // Cyan
-	secondaryColor = lipgloss.Color("212") // Light blue
-	successColor   = lipgloss.Color("46")  // Green
-	warningColor   = lipgloss.Color("214") // Orange
-	errorColor     = lipgloss.Color("196") // Red
-	mutedColor     = lipgloss.Color("241") // Gray
-	darkGray       = lipgloss.Color("238")
-	lightGray      = lipgloss.Color("245")
-	backgroundColor = lipgloss.Color("235")
+	primaryColor    lipgloss.Color
+	secondaryColor  lipgloss.Color
+	successColor    lipgloss.Color
+	warningColor    lipgloss.Color
+	errorColor      lipgloss.Color
+	mutedColor      lipgloss.Color
+	darkGray        lipgloss.Color
+	lightGray       lipgloss.Color
+	backgroundColor lipgloss.Color
+	accentColor     lipgloss.Color
+	onColor         lipgloss.Color // Text on colored backgrounds
 )
 
-// Common styles
+// Common styles, rebuilt whenever the theme changes
 var (
+	titleStyle          lipgloss.Style
+	subtitleStyle       lipgloss.Style
+	helpStyle           lipgloss.Style
+	mutedStyle          lipgloss.Style
+	errorStyle          lipgloss.Style
+	successStyle        lipgloss.Style
+	warningStyle        lipgloss.Style
+	selectedItemStyle   lipgloss.Style
+	badgeStyle          lipgloss.Style
+	cursorStyle         lipgloss.Style
+	boxStyle            lipgloss.Style
+	successBoxStyle     lipgloss.Style
+	warningBoxStyle     lipgloss.Style
+	codeBlockStyle      lipgloss.Style
+	loadingStyle        lipgloss.Style
+	progressBarStyle    lipgloss.Style
+	progressEmptyStyle  lipgloss.Style
+	timerNormalStyle    lipgloss.Style
+	timerWarningStyle   lipgloss.Style
+	timerDangerStyle    lipgloss.Style
+	easyStyle           lipgloss.Style
+	mediumStyle         lipgloss.Style
+	hardStyle           lipgloss.Style
+	activeTabStyle      lipgloss.Style
+	inactiveTabStyle    lipgloss.Style
+	buttonStyle         lipgloss.Style
+	disabledButtonStyle lipgloss.Style
+)
+
+func init() {
+	theme.OnChange(applyTheme)
+}
+
+// applyTheme builds the common colors and styles from a theme
+func applyTheme(t theme.Theme) {
+	primaryColor = t.Primary
+	secondaryColor = t.Secondary
+	successColor = t.Success
+	warningColor = t.Warning
+	errorColor = t.Error
+	mutedColor = t.Muted
+	darkGray = t.Border
+	lightGray = t.Subtle
+	backgroundColor = t.Surface
+	accentColor = t.Accent
+	onColor = t.OnPrimary
+
 	// Title styles
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(2)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(2)
 
 	// Subtitle styles
 	subtitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(secondaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(secondaryColor).
+		MarginBottom(1)
 
 	// Help text style
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(2)
+		Foreground(mutedColor).
+		MarginTop(2)
+
+	// Quiet text, such as counts, without help text's margin
+	mutedStyle = lipgloss.NewStyle().
+		Foreground(mutedColor)
 
 	// Error style
 	errorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(errorColor)
+		Bold(true).
+		Foreground(errorColor)
 
 	// Success style
 	successStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(successColor)
+		Bold(true).
+		Foreground(successColor)
 
 	// Warning style
 	warningStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(warningColor)
+		Bold(true).
+		Foreground(warningColor)
 
 	// Selected item style
 	selectedItemStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(secondaryColor)
+		Bold(true).
+		Foreground(secondaryColor)
+
+	// Badge style for unread counts
+	badgeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(onColor).
+		Background(errorColor)
 
 	// Cursor style
 	cursorStyle = lipgloss.NewStyle().
-			Foreground(primaryColor)
+		Foreground(primaryColor)
 
 	// Box styles
 	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
 
 	successBoxStyle = boxStyle.Copy().
-			BorderForeground(successColor)
+		BorderForeground(successColor)
 
 	warningBoxStyle = boxStyle.Copy().
-			BorderForeground(warningColor)
+		BorderForeground(warningColor)
 
 	// Code block style
 	codeBlockStyle = lipgloss.NewStyle().
-			Foreground(lightGray).
-			Background(backgroundColor).
-			Padding(0, 1)
+		Foreground(lightGray).
+		Background(backgroundColor).
+		Padding(0, 1)
 
 	// Loading style
 	loadingStyle = lipgloss.NewStyle().
-			Foreground(warningColor).
-			Bold(true)
+		Foreground(warningColor).
+		Bold(true)
 
 	// Progress bar styles
 	progressBarStyle = lipgloss.NewStyle().
-				Foreground(successColor)
+		Foreground(successColor)
 
 	progressEmptyStyle = lipgloss.NewStyle().
-				Foreground(darkGray)
+		Foreground(darkGray)
 
 	// Timer styles
 	timerNormalStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(successColor)
+		Bold(true).
+		Foreground(successColor)
 
 	timerWarningStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(warningColor)
+		Bold(true).
+		Foreground(warningColor)
 
 	timerDangerStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(errorColor)
+		Bold(true).
+		Foreground(errorColor)
 
 	// Difficulty styles
 	easyStyle = lipgloss.NewStyle().
-			Foreground(successColor).
-			Bold(true)
+		Foreground(successColor).
+		Bold(true)
 
 	mediumStyle = lipgloss.NewStyle().
-			Foreground(warningColor).
-			Bold(true)
+		Foreground(warningColor).
+		Bold(true)
 
 	hardStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
+		Foreground(errorColor).
+		Bold(true)
 
 	// Tab styles
 	activeTabStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			Background(backgroundColor).
-			Padding(0, 2)
+		Bold(true).
+		Foreground(primaryColor).
+		Background(backgroundColor).
+		Padding(0, 2)
 
 	inactiveTabStyle = lipgloss.NewStyle().
-				Foreground(mutedColor).
-				Padding(0, 2)
+		Foreground(mutedColor).
+		Padding(0, 2)
 
 	// Button styles
 	buttonStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255")).
-			Background(primaryColor).
-			Padding(0, 2).
-			MarginRight(1)
+		Foreground(onColor).
+		Background(primaryColor).
+		Padding(0, 2).
+		MarginRight(1)
 
 	disabledButtonStyle = lipgloss.NewStyle().
-				Foreground(mutedColor).
-				Background(darkGray).
-				Padding(0, 2).
-				MarginRight(1)
-)
+		Foreground(mutedColor).
+		Background(darkGray).
+		Padding(0, 2).
+		MarginRight(1)
+}
 
 // Helper functions
 func getDifficultyStyle(difficulty string) lipgloss.Style {
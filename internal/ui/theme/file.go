@@ -0,0 +1,162 @@
+// Theme files
+
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// hexColor matches #rgb and #rrggbb colors
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// File is a theme file: a preset, and colors that override its own
+type File struct {
+	Preset string            `yaml:"preset,omitempty"`
+	Colors map[string]string `yaml:"colors,omitempty"` // Role to color, as #rrggbb or a 0-255 terminal color
+}
+
+// roles are the colors a theme file can set, by name
+var roles = map[string]func(*Theme) *lipgloss.Color{
+	"primary":    func(t *Theme) *lipgloss.Color { return &t.Primary },
+	"secondary":  func(t *Theme) *lipgloss.Color { return &t.Secondary },
+	"accent":     func(t *Theme) *lipgloss.Color { return &t.Accent },
+	"success":    func(t *Theme) *lipgloss.Color { return &t.Success },
+	"warning":    func(t *Theme) *lipgloss.Color { return &t.Warning },
+	"error":      func(t *Theme) *lipgloss.Color { return &t.Error },
+	"text":       func(t *Theme) *lipgloss.Color { return &t.Text },
+	"muted":      func(t *Theme) *lipgloss.Color { return &t.Muted },
+	"subtle":     func(t *Theme) *lipgloss.Color { return &t.Subtle },
+	"border":     func(t *Theme) *lipgloss.Color { return &t.Border },
+	"surface":    func(t *Theme) *lipgloss.Color { return &t.Surface },
+	"on_primary": func(t *Theme) *lipgloss.Color { return &t.OnPrimary },
+}
+
+// Roles returns the names of the colors a theme file can set, sorted
+func Roles() []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Path returns where the theme file is kept:
+// $XDG_CONFIG_HOME/algo-scales/theme.yaml, or ~/.config/algo-scales/theme.yaml
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "algo-scales", "theme.yaml")
+}
+
+// ReadFile reads a theme file. A missing file is an empty one.
+func ReadFile(path string) (File, error) {
+	var f File
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("%s: %v", path, err)
+	}
+	return f, nil
+}
+
+// WriteFile writes a theme file, creating its directory
+func WriteFile(path string, f File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create theme directory: %v", err)
+	}
+	header := "# Colors of the algo-scales TUI. Presets: " + strings.Join(Presets(), ", ") + "\n" +
+		"# Colors override the preset's: " + strings.Join(Roles(), ", ") + "\n"
+	return os.WriteFile(path, append([]byte(header), data...), 0644)
+}
+
+// Theme builds the theme the file describes. An unknown preset, role or
+// color is an error.
+func (f File) Theme() (Theme, error) {
+	name := f.Preset
+	if name == "" {
+		name = DefaultPreset
+	}
+	t, ok := Preset(name)
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme preset %q; choose from: %s", name, strings.Join(Presets(), ", "))
+	}
+
+	var problems []string
+	for _, role := range sortedKeys(f.Colors) {
+		color := strings.TrimSpace(f.Colors[role])
+		field, ok := roles[role]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("unknown color %q (use %s)", role, strings.Join(Roles(), ", ")))
+		case !validColor(color):
+			problems = append(problems, fmt.Sprintf("%s: %q is not a #rrggbb or 0-255 color", role, color))
+		default:
+			*field(&t) = lipgloss.Color(color)
+		}
+	}
+	if len(problems) > 0 {
+		return Theme{}, fmt.Errorf("invalid theme: %s", strings.Join(problems, "; "))
+	}
+	return t, nil
+}
+
+// Load reads the theme file at Path and makes its theme the one in use. A
+// missing file leaves the default theme; a broken one is an error and
+// leaves the theme as it was.
+func Load() error {
+	f, err := ReadFile(Path())
+	if err != nil {
+		return err
+	}
+	t, err := f.Theme()
+	if err != nil {
+		return fmt.Errorf("%s: %v", Path(), err)
+	}
+	Set(t)
+	return nil
+}
+
+// validColor reports whether a color is #rgb, #rrggbb or a terminal color
+// number
+func validColor(color string) bool {
+	if hexColor.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// sortedKeys returns a map's keys in order, so errors are reported stably
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
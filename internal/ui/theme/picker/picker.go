@@ -0,0 +1,144 @@
+// Package picker lets the user choose a theme preset in the terminal,
+// previewing each one before it is saved.
+package picker
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
+)
+
+// runProgram runs a Bubble Tea program
+// Exported as variable for testing
+var runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	return tea.NewProgram(m, opts...).Run()
+}
+
+// Run shows the picker starting at the named preset and returns the preset
+// chosen, or "" if the user cancelled
+func Run(current string) (string, error) {
+	final, err := runProgram(New(current))
+	if err != nil {
+		return "", fmt.Errorf("error running theme picker: %v", err)
+	}
+	return final.(Picker).Chosen(), nil
+}
+
+// Picker lets the user choose a preset, previewing each one
+type Picker struct {
+	presets  []theme.Theme
+	selected int
+	chosen   string
+	quit     bool
+}
+
+// New creates a picker starting at the named preset
+func New(current string) Picker {
+	p := Picker{presets: presets()}
+	for i, name := range theme.Presets() {
+		if name == current {
+			p.selected = i
+		}
+	}
+	return p
+}
+
+// Chosen returns the preset the user chose, or "" if they cancelled
+func (p Picker) Chosen() string {
+	return p.chosen
+}
+
+// Init implements tea.Model
+func (p Picker) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (p Picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.presets)-1 {
+			p.selected++
+		}
+	case "enter":
+		p.chosen = p.presets[p.selected].Name
+		p.quit = true
+		return p, tea.Quit
+	case "esc", "q", "ctrl+c":
+		p.quit = true
+		return p, tea.Quit
+	}
+	return p, nil
+}
+
+// View implements tea.Model
+func (p Picker) View() string {
+	if p.quit {
+		return ""
+	}
+	t := p.presets[p.selected]
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(t.Primary).Render("Choose a theme"))
+	b.WriteString("\n\n")
+	for i, preset := range p.presets {
+		if i == p.selected {
+			b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(t.Secondary).Render("> " + preset.Name))
+		} else {
+			b.WriteString("  " + preset.Name)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(Preview(t))
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(t.Muted).Render("↑/↓: Choose • Enter: Save • Esc: Cancel"))
+	return b.String()
+}
+
+// Preview renders a sample of a theme's colors in use
+func Preview(t theme.Theme) string {
+	swatch := func(name string, c lipgloss.Color) string {
+		return lipgloss.NewStyle().Foreground(t.OnPrimary).Background(c).Padding(0, 1).Render(name)
+	}
+	var b strings.Builder
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		swatch("primary", t.Primary), " ",
+		swatch("secondary", t.Secondary), " ",
+		swatch("accent", t.Accent)))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%s  %s  %s\n",
+		lipgloss.NewStyle().Bold(true).Foreground(t.Success).Render(symbols.Pass.String()+" Passed"),
+		lipgloss.NewStyle().Bold(true).Foreground(t.Warning).Render(symbols.Warning.String()+" Slow"),
+		lipgloss.NewStyle().Bold(true).Foreground(t.Error).Render(symbols.Fail.String()+" Failed")))
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary).
+		Padding(0, 1).
+		Render(lipgloss.NewStyle().Foreground(t.Text).Render("func twoSum(nums []int) []int") + "\n" +
+			lipgloss.NewStyle().Foreground(t.Muted).Render("Press Enter to run the tests"))
+	b.WriteString(box)
+	return b.String()
+}
+
+// presets returns the built-in themes in the order they are offered
+func presets() []theme.Theme {
+	var themes []theme.Theme
+	for _, name := range theme.Presets() {
+		t, _ := theme.Preset(name)
+		themes = append(themes, t)
+	}
+	return themes
+}
@@ -0,0 +1,47 @@
+package picker
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func press(t *testing.T, p Picker, keys ...string) Picker {
+	t.Helper()
+	var m tea.Model = p
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m.(Picker)
+}
+
+func TestPicker(t *testing.T) {
+	p := New("solarized")
+	assert.Contains(t, p.View(), "> solarized")
+
+	assert.Equal(t, "high-contrast", press(t, p, "j", "j", "enter").Chosen(), "down stops at the last preset")
+	assert.Equal(t, "dark", press(t, p, "k", "k", "k", "enter").Chosen(), "up stops at the first preset")
+	assert.Empty(t, press(t, p, "j", "esc").Chosen())
+	assert.Empty(t, press(t, p, "enter").View(), "the picker clears itself when done")
+}
+
+func TestRun(t *testing.T) {
+	original := runProgram
+	t.Cleanup(func() { runProgram = original })
+	runProgram = func(m tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+		return press(t, m.(Picker), "j", "enter"), nil
+	}
+
+	chosen, err := Run("dark")
+	require.NoError(t, err)
+	assert.Equal(t, "light", chosen)
+}
@@ -0,0 +1,152 @@
+// Package theme holds the colors every TUI screen is drawn with. The theme
+// starts from a built-in preset and can be changed in a theme file.
+package theme
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultPreset is the preset used when no theme file chooses one
+const DefaultPreset = "dark"
+
+// Theme is the palette the screens draw with, by the role each color plays
+type Theme struct {
+	Name      string         // Preset the theme is based on
+	Primary   lipgloss.Color // Titles, borders and the cursor
+	Secondary lipgloss.Color // Headings and selected items
+	Accent    lipgloss.Color // Spinners, links and other highlights
+	Success   lipgloss.Color // Passed tests, easy problems and good news
+	Warning   lipgloss.Color // Timers running late, medium problems and prompts
+	Error     lipgloss.Color // Failures, hard problems and errors
+	Text      lipgloss.Color // Body text where a screen sets it
+	Muted     lipgloss.Color // Help lines and other quiet text
+	Subtle    lipgloss.Color // Secondary text such as code and labels
+	Border    lipgloss.Color // Dim borders, selections and empty progress
+	Surface   lipgloss.Color // Background of code blocks, tabs and panels
+	OnPrimary lipgloss.Color // Text on colored backgrounds
+}
+
+// presets are the built-in themes, in the order they are offered
+var presets = []Theme{
+	{
+		// The terminal's 256-color palette, as the screens always used
+		Name:      "dark",
+		Primary:   "62",
+		Secondary: "212",
+		Accent:    "39",
+		Success:   "46",
+		Warning:   "214",
+		Error:     "196",
+		Text:      "252",
+		Muted:     "241",
+		Subtle:    "245",
+		Border:    "238",
+		Surface:   "235",
+		OnPrimary: "255",
+	},
+	{
+		Name:      "light",
+		Primary:   "25",
+		Secondary: "125",
+		Accent:    "31",
+		Success:   "28",
+		Warning:   "130",
+		Error:     "160",
+		Text:      "235",
+		Muted:     "244",
+		Subtle:    "240",
+		Border:    "250",
+		Surface:   "254",
+		OnPrimary: "231",
+	},
+	{
+		// Ethan Schoonover's Solarized, dark variant
+		Name:      "solarized",
+		Primary:   "#268bd2",
+		Secondary: "#d33682",
+		Accent:    "#2aa198",
+		Success:   "#859900",
+		Warning:   "#b58900",
+		Error:     "#dc322f",
+		Text:      "#93a1a1",
+		Muted:     "#586e75",
+		Subtle:    "#839496",
+		Border:    "#073642",
+		Surface:   "#002b36",
+		OnPrimary: "#fdf6e3",
+	},
+	{
+		// The 16 basic colors at full brightness, with no dim text
+		Name:      "high-contrast",
+		Primary:   "14",
+		Secondary: "13",
+		Accent:    "11",
+		Success:   "10",
+		Warning:   "11",
+		Error:     "9",
+		Text:      "15",
+		Muted:     "15",
+		Subtle:    "15",
+		Border:    "15",
+		Surface:   "0",
+		OnPrimary: "0",
+	},
+}
+
+// Presets returns the names of the built-in themes
+func Presets() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Preset returns a built-in theme by name
+func Preset(name string) (Theme, bool) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Theme{}, false
+}
+
+// The theme in use and the screens to tell when it changes
+var (
+	mu       sync.RWMutex
+	current  = presets[0]
+	watchers []func(Theme)
+)
+
+// Current returns the theme in use
+func Current() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Set changes the theme in use and rebuilds the styles made from it
+func Set(t Theme) {
+	mu.Lock()
+	current = t
+	fns := append([]func(Theme){}, watchers...)
+	mu.Unlock()
+
+	for _, fn := range fns {
+		fn(t)
+	}
+}
+
+// OnChange builds styles from the theme now and again whenever it changes.
+// Packages whose styles are variables call it from init.
+func OnChange(fn func(Theme)) {
+	mu.Lock()
+	watchers = append(watchers, fn)
+	t := current
+	mu.Unlock()
+
+	fn(t)
+}
@@ -0,0 +1,107 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetsSetEveryColor(t *testing.T) {
+	assert.Equal(t, []string{"dark", "light", "solarized", "high-contrast"}, Presets())
+	for _, name := range Presets() {
+		preset, ok := Preset(name)
+		require.True(t, ok, name)
+		v := reflect.ValueOf(preset)
+		for i := 0; i < v.NumField(); i++ {
+			assert.NotEmpty(t, v.Field(i).Interface(), "%s.%s", name, v.Type().Field(i).Name)
+		}
+	}
+	_, ok := Preset("neon")
+	assert.False(t, ok)
+}
+
+func TestRolesCoverEveryColor(t *testing.T) {
+	// Every color but the name can be set in a theme file
+	assert.Len(t, Roles(), reflect.TypeOf(Theme{}).NumField()-1)
+}
+
+func TestFileTheme(t *testing.T) {
+	theme, err := File{}.Theme()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPreset, theme.Name)
+
+	theme, err = File{Preset: "light", Colors: map[string]string{"primary": "#abc", "on_primary": " 16 "}}.Theme()
+	require.NoError(t, err)
+	light, _ := Preset("light")
+	assert.Equal(t, lipgloss.Color("#abc"), theme.Primary)
+	assert.Equal(t, lipgloss.Color("16"), theme.OnPrimary)
+	assert.Equal(t, light.Error, theme.Error)
+
+	_, err = File{Preset: "neon"}.Theme()
+	assert.ErrorContains(t, err, `unknown theme preset "neon"`)
+
+	_, err = File{Colors: map[string]string{"primary": "blue", "glow": "#fff", "muted": "256"}}.Theme()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown color "glow"`)
+	assert.Contains(t, err.Error(), `primary: "blue"`)
+	assert.Contains(t, err.Error(), `muted: "256"`)
+}
+
+func TestReadWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "algo-scales", "theme.yaml")
+
+	f, err := ReadFile(path)
+	require.NoError(t, err, "a missing file is an empty one")
+	assert.Equal(t, File{}, f)
+
+	want := File{Preset: "solarized", Colors: map[string]string{"accent": "#ff00ff"}}
+	require.NoError(t, WriteFile(path, want))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Colors of the algo-scales TUI"))
+
+	f, err = ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, f)
+
+	require.NoError(t, os.WriteFile(path, []byte("preset: [dark"), 0644))
+	_, err = ReadFile(path)
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original := Current()
+	t.Cleanup(func() { Set(original) })
+	assert.Equal(t, filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "algo-scales", "theme.yaml"), Path())
+
+	require.NoError(t, Load())
+	assert.Equal(t, original, Current(), "no file leaves the theme as it was")
+
+	require.NoError(t, WriteFile(Path(), File{Preset: "high-contrast"}))
+	require.NoError(t, Load())
+	assert.Equal(t, "high-contrast", Current().Name)
+
+	require.NoError(t, WriteFile(Path(), File{Preset: "light", Colors: map[string]string{"text": "white"}}))
+	assert.Error(t, Load())
+	assert.Equal(t, "high-contrast", Current().Name, "a broken file leaves the theme as it was")
+}
+
+func TestOnChange(t *testing.T) {
+	original := Current()
+	t.Cleanup(func() { Set(original) })
+
+	var seen []string
+	OnChange(func(t Theme) { seen = append(seen, t.Name) })
+	assert.Equal(t, []string{original.Name}, seen, "a watcher starts with the theme in use")
+
+	light, _ := Preset("light")
+	Set(light)
+	assert.Equal(t, []string{original.Name, "light"}, seen)
+}
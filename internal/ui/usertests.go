@@ -0,0 +1,106 @@
+// Custom test cases added from the session screen
+
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/common/logging"
+	"github.com/lancekrogers/algo-scales/internal/usertests"
+)
+
+// openUserTests opens the user tests of every problem
+// Exported as variable for testing
+var openUserTests = usertests.Open
+
+// userTestDraft is a user test being typed on the session screen, input
+// first and then the expected output
+type userTestDraft struct {
+	input          string
+	expected       string
+	typingExpected bool
+}
+
+// startUserTest begins typing a user test for the session's problem
+func (m Model) startUserTest() (Model, tea.Cmd) {
+	m.session.addTest = &userTestDraft{}
+	m.session.message = ""
+	return m, nil
+}
+
+// updateUserTest handles keys while a user test is typed. Enter moves from
+// the input to the expected output and then saves the test; Esc cancels.
+func (m Model) updateUserTest(msg tea.KeyMsg) (Model, tea.Cmd) {
+	draft := *m.session.addTest
+	field := &draft.input
+	if draft.typingExpected {
+		field = &draft.expected
+	}
+
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.session.addTest = nil
+		m.session.message = "Test not added."
+		return m, nil
+	case "enter":
+		if !draft.typingExpected {
+			draft.typingExpected = true
+			m.session.addTest = &draft
+			return m, nil
+		}
+		m.session.addTest = nil
+		return m.saveUserTest(draft), nil
+	case "backspace":
+		if runes := []rune(*field); len(runes) > 0 {
+			*field = string(runes[:len(runes)-1])
+		}
+	case " ":
+		*field += " "
+	default:
+		if msg.Type == tea.KeyRunes {
+			*field += string(msg.Runes)
+		}
+	}
+	m.session.addTest = &draft
+	return m, nil
+}
+
+// saveUserTest stores a typed test for the session's problem
+func (m Model) saveUserTest(draft userTestDraft) Model {
+	suite := openUserTests()
+	defer suite.Close()
+
+	if _, err := suite.Add(context.Background(), m.session.problem.ID, draft.input, draft.expected); err != nil {
+		m.session.message = fmt.Sprintf("Test not added: %v", err)
+		return m
+	}
+	tests, _ := suite.List(context.Background(), m.session.problem.ID)
+	m.session.message = fmt.Sprintf("Added user test %d. Press 't' to run it with the examples.", len(tests))
+	return m
+}
+
+// userTestPrompt shows the user test being typed
+func (m Model) userTestPrompt() string {
+	draft := m.session.addTest
+	if !draft.typingExpected {
+		return fmt.Sprintf("New test input (e.g. [2,7,11,15], 9): %s█  Enter: next • Esc: cancel", draft.input)
+	}
+	return fmt.Sprintf("Input: %s  Expected output: %s█  Enter: add • Esc: cancel", draft.input, draft.expected)
+}
+
+// includeUserTests appends the problem's user tests to a test run. Tests
+// that cannot be read are left out rather than failing the run.
+func includeUserTests(prob *interfaces.Problem) *interfaces.Problem {
+	suite := openUserTests()
+	defer suite.Close()
+
+	withUser, err := suite.Include(context.Background(), prob)
+	if err != nil {
+		logging.NewLogger("UserTests").WithContext(context.Background()).Warn("failed to load user tests: %v", err)
+		return prob
+	}
+	return withUser
+}
@@ -0,0 +1,16 @@
+package view
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lancekrogers/algo-scales/internal/session/clock"
+)
+
+// ClockTick returns a command that delivers a clock.TickMsg after
+// clock.TickInterval. Models return it again from Update to keep refreshing.
+func ClockTick(c *clock.Clock) tea.Cmd {
+	return tea.Tick(clock.TickInterval, func(time.Time) tea.Msg {
+		return clock.TickMsg{Snapshot: c.Snapshot()}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
 // PatternVisualization provides ASCII/Unicode art representations of algorithm patterns
@@ -133,7 +134,7 @@ func (pv *PatternVisualization) visualizeFastSlow(data string, width int) string
 	listViz := ""
 	for i, node := range nodes {
 		nodeStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff")).
+			Foreground(theme.Current().OnPrimary).
 			Background(scale.SecondaryColor).
 			Padding(0, 1).
 			Bold(true)
@@ -181,7 +182,7 @@ func (pv *PatternVisualization) visualizeHashMap(data string, width int) string
 	
 	// Create a simple hash table visualization
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffffff")).
+		Foreground(theme.Current().OnPrimary).
 		Background(scale.PrimaryColor).
 		Padding(0, 1).
 		Bold(true)
@@ -191,7 +192,7 @@ func (pv *PatternVisualization) visualizeHashMap(data string, width int) string
 		Bold(true)
 		
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffffff"))
+		Foreground(theme.Current().OnPrimary)
 		
 	// Table header
 	table := headerStyle.Render(" Key ") + " │ " + headerStyle.Render(" Value ") + "\n"
@@ -310,7 +311,7 @@ func (pv *PatternVisualization) visualizeDP(data string, width int) string {
 	
 	// Create a simple DP table (e.g., for fibonacci)
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffffff")).
+		Foreground(theme.Current().OnPrimary).
 		Background(scale.PrimaryColor).
 		Padding(0, 1).
 		Bold(true)
@@ -340,7 +341,7 @@ func (pv *PatternVisualization) visualizeGreedy(data string, width int) string {
 	
 	// Example: coin change problem with greedy approach
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffffff")).
+		Foreground(theme.Current().OnPrimary).
 		Background(scale.PrimaryColor).
 		Padding(0, 1).
 		Bold(true)
@@ -392,10 +393,10 @@ func (pv *PatternVisualization) visualizeUnionFind(data string, width int) strin
 			scale.PrimaryColor,
 			scale.SecondaryColor,
 			scale.AccentColor,
-			lipgloss.Color("#2ecc71"),
+			gMajorGreen,
 		}
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff")).
+			Foreground(theme.Current().OnPrimary).
 			Background(colors[id % len(colors)]).
 			Padding(0, 1).
 			Bold(true)
@@ -4,6 +4,7 @@ package view
 import (
 	"strings"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
 // MusicScale represents a musical scale and its associated colors
@@ -62,15 +63,6 @@ var (
 	bbMajorBrown       = lipgloss.Color("#795548")
 	bbMajorLightBrown  = lipgloss.Color("#a1887f")
 	bbMajorDarkBrown   = lipgloss.Color("#5d4037")
-
-	// Default colors
-	subtleGray       = lipgloss.Color("#6c757d")
-	defaultFg        = lipgloss.Color("#eeeeee")
-	defaultBg        = lipgloss.Color("#333333")
-	successGreen     = lipgloss.Color("#28a745")
-	errorRed         = lipgloss.Color("#dc3545")
-	warningYellow    = lipgloss.Color("#ffc107")
-	infoBlue         = lipgloss.Color("#17a2b8")
 )
 
 // Musical scale definitions with their pattern associations
@@ -165,141 +157,182 @@ var MusicScales = map[string]MusicScale{
 	},
 }
 
-// Base styles for UI components
+// Colors from the theme in use, for the components that are not styles
+var (
+	subtleGray lipgloss.Color
+	emptyColor lipgloss.Color
+)
+
+// Base styles for UI components, rebuilt whenever the theme changes
 var (
 	// Text styles
+	BaseStyle     lipgloss.Style
+	TitleStyle    lipgloss.Style
+	SubtitleStyle lipgloss.Style
+	HelpStyle     lipgloss.Style
+	SuccessStyle  lipgloss.Style
+	ErrorStyle    lipgloss.Style
+	WarningStyle  lipgloss.Style
+	InfoStyle     lipgloss.Style
+
+	// Box styles
+	BorderedBoxStyle lipgloss.Style
+	CodeBoxStyle     lipgloss.Style
+	ProblemBoxStyle  lipgloss.Style
+	HorizontalLine   string
+
+	// Menu styles
+	FocusedItemStyle   lipgloss.Style
+	UnfocusedItemStyle lipgloss.Style
+	MenuBoxStyle       lipgloss.Style
+
+	// Status bar style
+	StatusBarStyle    lipgloss.Style
+	TimerStyle        lipgloss.Style
+	TimerWarningStyle lipgloss.Style
+
+	// Button styles
+	ButtonStyle       lipgloss.Style
+	ActiveButtonStyle lipgloss.Style
+	HeaderStyle       lipgloss.Style
+)
+
+func init() {
+	theme.OnChange(applyTheme)
+}
+
+// applyTheme builds the base styles from a theme
+func applyTheme(t theme.Theme) {
+	subtleGray = t.Muted
+	emptyColor = t.Border
+
 	BaseStyle = lipgloss.NewStyle().
-		Foreground(defaultFg).
-		Background(defaultBg)
+		Foreground(t.Text).
+		Background(t.Surface)
 
 	TitleStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#7D56F4")).
+		Foreground(t.OnPrimary).
+		Background(t.Primary).
 		Padding(0, 1).
 		MarginBottom(1).
 		Width(60).
 		Align(lipgloss.Center)
 
 	SubtitleStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#BBDEFB")).
+		Foreground(t.Subtle).
 		Italic(true).
 		MarginBottom(1)
 
 	HelpStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#A5D6A7")).
-		Background(lipgloss.Color("#1B5E20")).
+		Foreground(t.Muted).
 		Padding(0, 1).
 		MarginTop(1)
 
 	SuccessStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#2E7D32")).
+		Foreground(t.OnPrimary).
+		Background(t.Success).
 		Bold(true).
 		Padding(0, 1)
 
 	ErrorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#C62828")).
+		Foreground(t.OnPrimary).
+		Background(t.Error).
 		Bold(true).
 		Padding(0, 1)
 
 	WarningStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#F9A825")).
+		Foreground(t.Surface).
+		Background(t.Warning).
 		Bold(true).
 		Padding(0, 1)
 
 	InfoStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#1565C0")).
+		Foreground(t.OnPrimary).
+		Background(t.Accent).
 		Padding(0, 1)
 
-	// Box styles
 	BorderedBoxStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#64B5F6")).
+		BorderForeground(t.Accent).
 		Padding(1).
 		MarginTop(1).
 		MarginBottom(1)
 
 	CodeBoxStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FF9800")).
-		Background(lipgloss.Color("#263238")).
-		Foreground(lipgloss.Color("#ECEFF1")).
+		BorderForeground(t.Warning).
+		Background(t.Surface).
+		Foreground(t.Text).
 		Padding(1).
 		MarginTop(1).
 		MarginBottom(1)
 
 	ProblemBoxStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#64B5F6")).
-		Background(lipgloss.Color("#1A237E")).
-		Foreground(lipgloss.Color("#E8EAF6")).
+		BorderForeground(t.Accent).
+		Background(t.Surface).
+		Foreground(t.Text).
 		Padding(1).
 		MarginTop(1).
 		MarginBottom(1)
 
 	HorizontalLine = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#64B5F6")).
+		Foreground(t.Accent).
 		Render("─────────────────────────────────────")
 
-	// Menu styles
 	FocusedItemStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#6200EA")).
+		Foreground(t.OnPrimary).
+		Background(t.Primary).
 		Bold(true).
 		Padding(0, 1)
 
 	UnfocusedItemStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E1F5FE")).
+		Foreground(t.Text).
 		Padding(0, 1)
 
 	MenuBoxStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#B39DDB")).
+		BorderForeground(t.Secondary).
 		Padding(1).
 		Width(60)
 
-	// Status bar style
 	StatusBarStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#303F9F")).
+		Foreground(t.OnPrimary).
+		Background(t.Primary).
 		Bold(true).
 		Padding(0, 1)
-		
+
 	TimerStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#F57C00")).
+		Foreground(t.OnPrimary).
+		Background(t.Warning).
 		Bold(true).
 		Padding(0, 1)
-		
+
 	TimerWarningStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#D32F2F")).
+		Foreground(t.OnPrimary).
+		Background(t.Error).
 		Bold(true).
 		Padding(0, 1)
-	
-	// Button styles	
+
 	ButtonStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#1976D2")).
+		Foreground(t.OnPrimary).
+		Background(t.Accent).
 		Padding(0, 2).
 		MarginRight(1).
 		Bold(true)
-		
+
 	ActiveButtonStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#673AB7")).
+		Foreground(t.OnPrimary).
+		Background(t.Primary).
 		Padding(0, 2).
 		MarginRight(1).
 		Bold(true)
-		
+
 	HeaderStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#B3E5FC")).
+		Foreground(t.Secondary).
 		Bold(true)
-)
+}
 
 // GetPatternStyle returns styles for a specific algorithm pattern
 func GetPatternStyle(pattern string) (lipgloss.Style, lipgloss.Style, lipgloss.Style) {
@@ -348,8 +381,8 @@ func ProgressBar(width int, percent float64, pattern string) string {
 	
 	// Create the empty part of the progress bar
 	emptyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#333333")).
-		Background(lipgloss.Color("#333333"))
+		Foreground(emptyColor).
+		Background(emptyColor)
 
 	filled := filledStyle.Render(strings.Repeat("█", filledWidth))
 	empty := emptyStyle.Render(strings.Repeat("░", width-filledWidth))
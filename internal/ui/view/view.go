@@ -9,7 +9,11 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lancekrogers/algo-scales/internal/common/highlight"
+	"github.com/lancekrogers/algo-scales/internal/common/symbols"
+	"github.com/lancekrogers/algo-scales/internal/session/codefmt"
+	"github.com/lancekrogers/algo-scales/internal/session/execution"
 	"github.com/lancekrogers/algo-scales/internal/ui/model"
+	"github.com/lancekrogers/algo-scales/internal/ui/theme"
 )
 
 // View handles rendering the UI based on the model state
@@ -28,12 +32,12 @@ func NewView(m *model.UIModel) *View {
 	// Initialize spinner
 	s := spinner.New()
 	s.Spinner = spinner.MiniDot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#3498db"))
+	s.Style = lipgloss.NewStyle().Foreground(theme.Current().Accent)
 
 	return &View{
 		Model:             m,
 		spinner:           s,
-		syntaxHighlighter: NewSyntaxHighlighter("monokai"),
+		syntaxHighlighter: NewSyntaxHighlighter(""),
 		patternViz:        NewPatternVisualization(),
 	}
 }
@@ -254,11 +258,11 @@ func (v *View) renderProblemSelectionScreen(width, height int) string {
 			difficultyStyle := lipgloss.NewStyle()
 			switch problem.Difficulty {
 			case "easy":
-				difficultyStyle = difficultyStyle.Foreground(lipgloss.Color("#2ecc71"))
+				difficultyStyle = difficultyStyle.Foreground(theme.Current().Success)
 			case "medium":
-				difficultyStyle = difficultyStyle.Foreground(lipgloss.Color("#f1c40f"))
+				difficultyStyle = difficultyStyle.Foreground(theme.Current().Warning)
 			case "hard":
-				difficultyStyle = difficultyStyle.Foreground(lipgloss.Color("#e74c3c"))
+				difficultyStyle = difficultyStyle.Foreground(theme.Current().Error)
 			}
 			
 			// Highlight selected problem
@@ -349,13 +353,11 @@ func (v *View) renderSessionScreen(width, height int) string {
 		)
 	}
 	
-	// Highlight code
-	highlightedCode, err := v.syntaxHighlighter.Highlight(
-		v.Model.Session.Code, 
-		v.Model.Session.Language,
-	)
+	// Format and highlight code
+	code := codefmt.Code(v.Model.Session.Language, v.Model.Session.Code)
+	highlightedCode, err := v.syntaxHighlighter.Highlight(code, v.Model.Session.Language)
 	if err != nil {
-		highlightedCode = v.Model.Session.Code
+		highlightedCode = code
 	}
 	
 	// Format test results
@@ -365,12 +367,14 @@ func (v *View) renderSessionScreen(width, height int) string {
 		testOutput.WriteString(primaryStyle.Render("Test Results:") + "\n")
 		
 		allPassed := true
+		durations := make([]time.Duration, len(v.Model.Session.TestResults))
 		for i, test := range v.Model.Session.TestResults {
+			durations[i] = test.Duration
 			testOutput.WriteString(fmt.Sprintf("Test %d: ", i+1))
 			if test.Passed {
-				testOutput.WriteString(SuccessStyle.Render("✓ PASSED") + "\n")
+				testOutput.WriteString(SuccessStyle.Render(symbols.Check.String() + " PASSED") + FormatTestDuration(test.Duration) + "\n")
 			} else {
-				testOutput.WriteString(ErrorStyle.Render("✗ FAILED") + "\n")
+				testOutput.WriteString(ErrorStyle.Render(symbols.Cross.String() + " FAILED") + FormatTestDuration(test.Duration) + "\n")
 				testOutput.WriteString(fmt.Sprintf("  Input: %s\n", test.Input))
 				testOutput.WriteString(fmt.Sprintf("  Expected: %s\n", test.Expected))
 				testOutput.WriteString(fmt.Sprintf("  Got: %s\n", test.Actual))
@@ -378,6 +382,8 @@ func (v *View) renderSessionScreen(width, height int) string {
 			}
 		}
 		
+		testOutput.WriteString(FormatSlowestTest(durations))
+		
 		if allPassed {
 			testOutput.WriteString("\n" + SuccessStyle.Render("All tests passed! 🎉"))
 		}
@@ -483,11 +489,11 @@ func (v *View) renderStatisticsScreen(width, height int) string {
 		var color lipgloss.Color
 		switch diff {
 		case "easy":
-			color = lipgloss.Color("#2ecc71")
+			color = theme.Current().Success
 		case "medium":
-			color = lipgloss.Color("#f1c40f")
+			color = theme.Current().Warning
 		case "hard":
-			color = lipgloss.Color("#e74c3c")
+			color = theme.Current().Error
 		}
 		
 		diffStyle := lipgloss.NewStyle().Foreground(color)
@@ -532,4 +538,29 @@ func formatDuration(d time.Duration) string {
 		return fmt.Sprintf("%dh %dm", h, m)
 	}
 	return fmt.Sprintf("%dm", m)
-}
\ No newline at end of file
+}
+
+// durationStyle keeps test durations visually secondary to the result
+var durationStyle = lipgloss.NewStyle().Faint(true)
+
+// FormatTestDuration renders a test's duration after its result, highlighting
+// tests slower than execution.SlowTestThreshold
+func FormatTestDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	text := execution.FormatDuration(d)
+	if execution.IsSlow(d) {
+		return " " + WarningStyle.Render(fmt.Sprintf("%s %s slow", symbols.Warning, text))
+	}
+	return " " + durationStyle.Render("("+text+")")
+}
+
+// FormatSlowestTest names the slowest of several timed tests
+func FormatSlowestTest(durations []time.Duration) string {
+	slowest := execution.Slowest(durations)
+	if len(durations) < 2 || slowest < 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nSlowest: Test %d%s\n", slowest+1, FormatTestDuration(durations[slowest]))
+}
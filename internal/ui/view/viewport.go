@@ -0,0 +1,277 @@
+// Lazily rendered, word-wrapped viewport for long content
+
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxCachedWidths bounds how many wrap widths are kept after resizes
+const maxCachedWidths = 4
+
+// Viewport is a drop-in replacement for the bubbles viewport that wraps
+// content to its width. Lines are wrapped on demand, only as far down as
+// has been displayed, and the result is cached per width, so scrolling
+// very long descriptions only touches the visible rows.
+type Viewport struct {
+	Width   int
+	Height  int
+	YOffset int // first visible wrapped row
+
+	KeyMap          viewport.KeyMap
+	MouseWheelDelta int
+
+	lines  []string // content split into raw lines
+	wraps  *wrapCaches
+	anchor int // raw line at the top, used to keep position across resizes
+}
+
+// wrapCaches holds wrapped rows for each width the content was shown at.
+// It is shared by value copies of a Viewport.
+type wrapCaches struct {
+	byWidth map[int]*wrapCache
+	order   []int // least recently used first
+}
+
+// wrapCache holds the wrapped rows for a prefix of the raw lines
+type wrapCache struct {
+	rows    []string
+	rowLine []int // raw line each row belongs to
+	first   []int // first row of each wrapped raw line
+}
+
+// NewViewport creates a viewport with the given dimensions
+func NewViewport(width, height int) Viewport {
+	return Viewport{
+		Width:           width,
+		Height:          height,
+		KeyMap:          viewport.DefaultKeyMap(),
+		MouseWheelDelta: 3,
+		wraps:           &wrapCaches{byWidth: make(map[int]*wrapCache)},
+	}
+}
+
+// SetContent replaces the content and clears the wrap cache
+func (m *Viewport) SetContent(s string) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	m.lines = strings.Split(s, "\n")
+	m.wraps = &wrapCaches{byWidth: make(map[int]*wrapCache)}
+
+	// Stay on the same line when content is refreshed
+	m.anchor = min(m.anchor, len(m.lines)-1)
+	m.YOffset = 0
+	m.SetYOffset(m.cache().first[m.anchor])
+}
+
+// Init implements tea.Model
+func (m Viewport) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles scrolling keys and the mouse wheel
+func (m Viewport) Update(msg tea.Msg) (Viewport, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.LineDown(m.Height)
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.LineUp(m.Height)
+		case key.Matches(msg, m.KeyMap.HalfPageDown):
+			m.HalfViewDown()
+		case key.Matches(msg, m.KeyMap.HalfPageUp):
+			m.HalfViewUp()
+		case key.Matches(msg, m.KeyMap.Down):
+			m.LineDown(1)
+		case key.Matches(msg, m.KeyMap.Up):
+			m.LineUp(1)
+		}
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelDown:
+			m.LineDown(m.MouseWheelDelta)
+		case tea.MouseButtonWheelUp:
+			m.LineUp(m.MouseWheelDelta)
+		}
+	}
+
+	return m, nil
+}
+
+// View renders only the visible rows
+func (m Viewport) View() string {
+	if m.Height <= 0 {
+		return ""
+	}
+
+	c := m.cache()
+	m.ensureRows(c, m.YOffset+m.Height)
+
+	top := min(m.YOffset, len(c.rows))
+	bottom := min(m.YOffset+m.Height, len(c.rows))
+
+	var b strings.Builder
+	for i := top; i < bottom; i++ {
+		b.WriteString(c.rows[i])
+		b.WriteByte('\n')
+	}
+	// Pad so the viewport keeps its height
+	for i := bottom - top; i < m.Height; i++ {
+		b.WriteByte('\n')
+	}
+
+	out := b.String()
+	return out[:len(out)-1]
+}
+
+// SetYOffset scrolls to a wrapped row, clamped to the content
+func (m *Viewport) SetYOffset(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	c := m.cache()
+	m.ensureRows(c, n+m.Height)
+	maxOffset := max(0, len(c.rows)-m.Height)
+	if n > maxOffset {
+		n = maxOffset
+	}
+
+	m.YOffset = n
+	if n < len(c.rowLine) {
+		m.anchor = c.rowLine[n]
+	}
+}
+
+// LineDown scrolls down by n rows
+func (m *Viewport) LineDown(n int) {
+	m.cache() // sync the offset after a resize
+	m.SetYOffset(m.YOffset + n)
+}
+
+// LineUp scrolls up by n rows
+func (m *Viewport) LineUp(n int) {
+	m.cache() // sync the offset after a resize
+	m.SetYOffset(m.YOffset - n)
+}
+
+// HalfViewDown scrolls down by half the viewport height
+func (m *Viewport) HalfViewDown() {
+	m.LineDown(m.Height / 2)
+}
+
+// HalfViewUp scrolls up by half the viewport height
+func (m *Viewport) HalfViewUp() {
+	m.LineUp(m.Height / 2)
+}
+
+// GotoTop scrolls to the top
+func (m *Viewport) GotoTop() {
+	m.SetYOffset(0)
+}
+
+// GotoBottom scrolls to the bottom; this wraps all remaining content
+func (m *Viewport) GotoBottom() {
+	m.SetYOffset(m.TotalLineCount())
+}
+
+// AtTop reports whether the viewport is scrolled to the top
+func (m Viewport) AtTop() bool {
+	return m.YOffset <= 0
+}
+
+// AtBottom reports whether the last row is visible
+func (m Viewport) AtBottom() bool {
+	return m.YOffset >= max(0, m.TotalLineCount()-m.Height)
+}
+
+// ScrollPercent returns how far the viewport is scrolled, from 0 to 1
+func (m Viewport) ScrollPercent() float64 {
+	total := m.TotalLineCount()
+	if m.Height >= total {
+		return 1.0
+	}
+	return float64(m.YOffset) / float64(total-m.Height)
+}
+
+// TotalLineCount returns the number of wrapped rows; this wraps all content
+func (m Viewport) TotalLineCount() int {
+	c := m.cache()
+	m.ensureRows(c, -1)
+	return len(c.rows)
+}
+
+// cache returns the wrap cache for the current width, restoring the scroll
+// position from the anchor line when the width changed
+func (m *Viewport) cache() *wrapCache {
+	if m.wraps == nil {
+		m.wraps = &wrapCaches{byWidth: make(map[int]*wrapCache)}
+	}
+
+	w := m.wraps
+	c, ok := w.byWidth[m.Width]
+	if !ok {
+		c = &wrapCache{}
+		w.byWidth[m.Width] = c
+		if len(w.order) >= maxCachedWidths {
+			delete(w.byWidth, w.order[0])
+			w.order = w.order[1:]
+		}
+	} else {
+		for i, width := range w.order {
+			if width == m.Width {
+				w.order = append(w.order[:i], w.order[i+1:]...)
+				break
+			}
+		}
+	}
+	w.order = append(w.order, m.Width)
+
+	// A resize keeps the same raw line at the top
+	if m.YOffset >= len(c.rowLine) || c.rowLine[m.YOffset] != m.anchor {
+		m.ensureLine(c, m.anchor)
+		if m.anchor < len(c.first) {
+			m.YOffset = c.first[m.anchor]
+		}
+	}
+	return c
+}
+
+// ensureRows wraps raw lines until at least n rows exist, or all lines
+// when n is negative
+func (m Viewport) ensureRows(c *wrapCache, n int) {
+	for len(c.first) < len(m.lines) && (n < 0 || len(c.rows) < n) {
+		m.wrapNext(c)
+	}
+}
+
+// ensureLine wraps raw lines up to and including line
+func (m Viewport) ensureLine(c *wrapCache, line int) {
+	for len(c.first) <= line && len(c.first) < len(m.lines) {
+		m.wrapNext(c)
+	}
+}
+
+// wrapNext wraps the next unwrapped raw line into the cache
+func (m Viewport) wrapNext(c *wrapCache) {
+	line := len(c.first)
+	c.first = append(c.first, len(c.rows))
+
+	raw := m.lines[line]
+	if m.Width <= 0 || ansi.StringWidth(raw) <= m.Width {
+		c.rows = append(c.rows, raw)
+		c.rowLine = append(c.rowLine, line)
+		return
+	}
+
+	for _, row := range strings.Split(ansi.Wrap(raw, m.Width, ""), "\n") {
+		c.rows = append(c.rows, row)
+		c.rowLine = append(c.rowLine, line)
+	}
+}
@@ -0,0 +1,172 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// longContent builds n lines, every third one longer than a typical panel
+func longContent(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i%3 == 0 {
+			fmt.Fprintf(&b, "%d: %s\n", i, strings.Repeat("lorem ipsum dolor sit amet ", 6))
+		} else {
+			fmt.Fprintf(&b, "%d: short line\n", i)
+		}
+	}
+	return b.String()
+}
+
+func TestViewportWrapsToWidth(t *testing.T) {
+	vp := NewViewport(20, 5)
+	vp.SetContent("short\n" + strings.Repeat("word ", 10) + "\nlast")
+
+	lines := strings.Split(vp.View(), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 rows, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if ansi.StringWidth(line) > 20 {
+			t.Fatalf("row %q is wider than the viewport", line)
+		}
+	}
+	if lines[0] != "short" || lines[4] != "last" {
+		t.Fatalf("unexpected rows: %q", lines)
+	}
+	if vp.TotalLineCount() != 5 {
+		t.Fatalf("expected 5 wrapped rows, got %d", vp.TotalLineCount())
+	}
+}
+
+func TestViewportWrapsLazily(t *testing.T) {
+	vp := NewViewport(40, 10)
+	vp.SetContent(longContent(10000))
+	vp.View()
+
+	c := vp.wraps.byWidth[40]
+	if len(c.first) > 20 {
+		t.Fatalf("expected only the visible lines to be wrapped, got %d", len(c.first))
+	}
+
+	vp.LineDown(25)
+	if vp.YOffset != 25 {
+		t.Fatalf("expected offset 25, got %d", vp.YOffset)
+	}
+	if len(c.first) == 10000 {
+		t.Fatal("scrolling a little should not wrap everything")
+	}
+
+	vp.GotoBottom()
+	if !vp.AtBottom() || !strings.Contains(vp.View(), "9999: ") {
+		t.Fatal("expected the last line at the bottom")
+	}
+}
+
+func TestViewportKeepsLineAcrossResize(t *testing.T) {
+	vp := NewViewport(40, 10)
+	vp.SetContent(longContent(300))
+	vp.LineDown(50)
+	line := vp.wraps.byWidth[40].rowLine[vp.YOffset]
+
+	vp.Width = 25
+	vp.LineDown(0)
+	c := vp.wraps.byWidth[25]
+	if got := c.rowLine[vp.YOffset]; got != line {
+		t.Fatalf("expected line %d at the top after resize, got %d", line, got)
+	}
+	if !strings.HasPrefix(vp.View(), fmt.Sprintf("%d: ", line)) {
+		t.Fatalf("expected the view to start at line %d", line)
+	}
+
+	// Going back reuses the cached wrap
+	cached := vp.wraps.byWidth[40]
+	vp.Width = 40
+	vp.LineDown(0)
+	if vp.wraps.byWidth[40] != cached {
+		t.Fatal("expected the wrap for width 40 to be reused")
+	}
+}
+
+func TestViewportKeys(t *testing.T) {
+	vp := NewViewport(40, 10)
+	vp.SetContent(longContent(100))
+
+	vp, _ = vp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if vp.YOffset != 1 {
+		t.Fatalf("expected j to scroll one row, got %d", vp.YOffset)
+	}
+	vp, _ = vp.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if vp.YOffset != 11 {
+		t.Fatalf("expected pgdown to scroll a page, got %d", vp.YOffset)
+	}
+	vp, _ = vp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if vp.YOffset != 6 {
+		t.Fatalf("expected u to scroll half a page up, got %d", vp.YOffset)
+	}
+}
+
+func TestViewportSetContentKeepsPosition(t *testing.T) {
+	vp := NewViewport(40, 10)
+	vp.SetContent(longContent(100))
+	vp.LineDown(30)
+	line := vp.wraps.byWidth[40].rowLine[vp.YOffset]
+
+	vp.SetContent(longContent(100) + "appended results\n")
+	if got := vp.wraps.byWidth[40].rowLine[vp.YOffset]; got != line {
+		t.Fatalf("expected to stay on line %d, got %d", line, got)
+	}
+}
+
+// Scrolling one row at a time through 10k lines and rendering each frame,
+// as happens when holding down j
+func BenchmarkViewportScroll10k(b *testing.B) {
+	content := longContent(10000)
+	vp := NewViewport(60, 40)
+	vp.SetContent(content)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vp.LineDown(1)
+		if vp.AtBottom() {
+			vp.GotoTop()
+		}
+		_ = vp.View()
+	}
+}
+
+// The bubbles viewport re-renders the visible block through lipgloss on
+// every frame; kept as a baseline for the benchmark above
+func BenchmarkBubblesViewportScroll10k(b *testing.B) {
+	content := longContent(10000)
+	vp := viewport.New(60, 40)
+	vp.SetContent(content)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vp.LineDown(1)
+		if vp.AtBottom() {
+			vp.GotoTop()
+		}
+		_ = vp.View()
+	}
+}
+
+func BenchmarkViewportSetContent10k(b *testing.B) {
+	content := longContent(10000)
+	vp := NewViewport(60, 40)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vp.SetContent(content)
+		_ = vp.View()
+	}
+}
@@ -0,0 +1,212 @@
+// Package userdata locates, exports and purges the user's local data
+package userdata
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/ai"
+	"github.com/lancekrogers/algo-scales/internal/daily"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Category groups user data locations
+type Category string
+
+const (
+	CategoryStats         Category = "stats"
+	CategoryLogs          Category = "logs"
+	CategoryAI            Category = "ai"
+	CategoryWorkspaces    Category = "workspaces"
+	CategoryNotifications Category = "notifications"
+	CategoryConfig        Category = "config"
+)
+
+// Location is a file or directory holding user data
+type Location struct {
+	Category Category `json:"category"`
+	Path     string   `json:"path"`
+	// Purgeable locations are removed by Purge; the rest (configuration and
+	// license) are only exported
+	Purgeable bool `json:"purgeable"`
+}
+
+// Manifest describes the contents of an export archive
+type Manifest struct {
+	ExportedAt time.Time  `json:"exported_at"`
+	Locations  []Location `json:"locations"`
+	Files      int        `json:"files"`
+}
+
+// Locations returns every location that may hold user data
+// Exported as variable for testing
+var Locations = func() []Location {
+	homeDir, _ := os.UserHomeDir()
+	configDir := filepath.Join(homeDir, ".algo-scales")
+
+	locations := []Location{
+		{Category: CategoryStats, Path: storage.DBPath(), Purgeable: true},
+		{Category: CategoryStats, Path: filepath.Join(configDir, "stats"), Purgeable: true},
+		{Category: CategoryStats, Path: filepath.Join(configDir, "timelines"), Purgeable: true},
+		{Category: CategoryStats, Path: filepath.Join(configDir, "timelines.json"), Purgeable: true},
+		{Category: CategoryNotifications, Path: filepath.Join(configDir, "notifications.json"), Purgeable: true},
+		{Category: CategoryAI, Path: filepath.Join(configDir, "claude-sessions"), Purgeable: true},
+		{Category: CategoryAI, Path: filepath.Join(configDir, "ai-assistant.log"), Purgeable: true},
+		{Category: CategoryWorkspaces, Path: filepath.Join(os.TempDir(), "algo-scales"), Purgeable: true},
+		{Category: CategoryWorkspaces, Path: daily.GetDailyWorkspacePath(), Purgeable: true},
+		{Category: CategoryWorkspaces, Path: filepath.Join(configDir, "session.json"), Purgeable: true},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "config.json")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "ai-config.yaml")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "license.json")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "hidden.json")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "goals.json")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "bookmarks.json")},
+		{Category: CategoryConfig, Path: filepath.Join(configDir, "notes")},
+	}
+
+	if userConfigDir, err := os.UserConfigDir(); err == nil {
+		locations = append(locations, Location{
+			Category:  CategoryLogs,
+			Path:      filepath.Join(userConfigDir, "algo-scales", "logs"),
+			Purgeable: true,
+		})
+	}
+
+	// Honour custom AI transcript and log locations, without creating a
+	// default AI config as a side effect of looking
+	if _, err := os.Stat(filepath.Join(configDir, "ai-config.yaml")); err == nil {
+		if cfg, err := ai.LoadConfig(); err == nil {
+			if cfg.Claude != nil && cfg.Claude.SessionDir != "" {
+				locations = append(locations, Location{Category: CategoryAI, Path: cfg.Claude.SessionDir, Purgeable: true})
+			}
+			if cfg.Logging != nil && cfg.Logging.LogFile != "" {
+				locations = append(locations, Location{Category: CategoryAI, Path: cfg.Logging.LogFile, Purgeable: true})
+			}
+		}
+	}
+
+	return dedupe(locations)
+}
+
+// Export writes every existing user data file into a zip archive along
+// with a manifest.json describing where each entry came from. It returns
+// the number of files written.
+func Export(w io.Writer) (int, error) {
+	archive := zip.NewWriter(w)
+	locations := existing(Locations())
+	files := 0
+
+	for i, loc := range locations {
+		// Prefix with the index so two locations in one category cannot collide
+		prefix := fmt.Sprintf("%s/%d-%s", loc.Category, i, filepath.Base(loc.Path))
+
+		err := filepath.WalkDir(loc.Path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(loc.Path, path)
+			if err != nil {
+				return err
+			}
+			name := prefix
+			if rel != "." {
+				name = prefix + "/" + filepath.ToSlash(rel)
+			}
+
+			if err := addFile(archive, name, path); err != nil {
+				return err
+			}
+			files++
+			return nil
+		})
+		if err != nil {
+			return files, fmt.Errorf("failed to export %s: %v", loc.Path, err)
+		}
+	}
+
+	manifest, err := json.MarshalIndent(Manifest{
+		ExportedAt: time.Now(),
+		Locations:  locations,
+		Files:      files,
+	}, "", "  ")
+	if err != nil {
+		return files, fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	mw, err := archive.Create("manifest.json")
+	if err != nil {
+		return files, err
+	}
+	if _, err := mw.Write(manifest); err != nil {
+		return files, err
+	}
+
+	return files, archive.Close()
+}
+
+// Purge removes all purgeable user data and returns the paths removed.
+// Configuration, the license and downloaded problems are kept.
+func Purge() ([]string, error) {
+	var removed []string
+	for _, loc := range existing(Locations()) {
+		if !loc.Purgeable {
+			continue
+		}
+		if err := os.RemoveAll(loc.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %v", loc.Path, err)
+		}
+		removed = append(removed, loc.Path)
+	}
+	return removed, nil
+}
+
+// addFile copies a file from disk into the archive
+func addFile(archive *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// existing filters locations down to those present on disk
+func existing(locations []Location) []Location {
+	var result []Location
+	for _, loc := range locations {
+		if _, err := os.Stat(loc.Path); err == nil {
+			result = append(result, loc)
+		}
+	}
+	return result
+}
+
+// dedupe removes repeated paths, keeping the first occurrence
+func dedupe(locations []Location) []Location {
+	seen := make(map[string]bool)
+	var result []Location
+	for _, loc := range locations {
+		clean := filepath.Clean(loc.Path)
+		if seen[clean] {
+			continue
+		}
+		seen[clean] = true
+		result = append(result, loc)
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package userdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestLocations points Locations at a temporary tree
+func setupTestLocations(t *testing.T) string {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "stats"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stats", "session_a.json"), []byte(`{}`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "workspace", "two-sum"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "workspace", "two-sum", "solution.go"), []byte("package main"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"language":"go"}`), 0644))
+
+	original := Locations
+	Locations = func() []Location {
+		return []Location{
+			{Category: CategoryStats, Path: filepath.Join(dir, "stats"), Purgeable: true},
+			{Category: CategoryWorkspaces, Path: filepath.Join(dir, "workspace"), Purgeable: true},
+			{Category: CategoryLogs, Path: filepath.Join(dir, "missing-logs"), Purgeable: true},
+			{Category: CategoryConfig, Path: filepath.Join(dir, "config.json")},
+		}
+	}
+	t.Cleanup(func() { Locations = original })
+
+	return dir
+}
+
+func TestExport(t *testing.T) {
+	setupTestLocations(t)
+
+	var buf bytes.Buffer
+	files, err := Export(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, files)
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"stats/0-stats/session_a.json",
+		"workspaces/1-workspace/two-sum/solution.go",
+		"config/2-config.json",
+		"manifest.json",
+	}, names)
+}
+
+func TestPurge(t *testing.T) {
+	dir := setupTestLocations(t)
+
+	removed, err := Purge()
+	require.NoError(t, err)
+	assert.Len(t, removed, 2)
+
+	assert.NoDirExists(t, filepath.Join(dir, "stats"))
+	assert.NoDirExists(t, filepath.Join(dir, "workspace"))
+
+	// Configuration is kept
+	assert.FileExists(t, filepath.Join(dir, "config.json"))
+}
+
+func TestDedupe(t *testing.T) {
+	locations := dedupe([]Location{
+		{Category: CategoryAI, Path: "/tmp/a/"},
+		{Category: CategoryAI, Path: "/tmp/a"},
+		{Category: CategoryAI, Path: "/tmp/b"},
+	})
+	assert.Len(t, locations, 2)
+}
@@ -0,0 +1,123 @@
+// Package usertests keeps the test cases users write for problems. They are
+// stored per problem in the progress database, so tests added from the CLI
+// show up in the TUI and vim mode, and are run with the problem's own tests
+// under the "user" tier.
+package usertests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+)
+
+// Store keeps user tests
+type Store interface {
+	LoadUserTests(ctx context.Context, problemID string) ([]storage.UserTest, error)
+	SaveUserTest(ctx context.Context, test storage.UserTest) (storage.UserTest, error)
+	DeleteUserTest(ctx context.Context, id int64) (bool, error)
+}
+
+// Suite reads and writes the user tests in a store
+type Suite struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewSuite creates a suite over the tests in store
+func NewSuite(store Store) *Suite {
+	return &Suite{store: store, now: time.Now}
+}
+
+// Add saves a test for a problem. Input and expected output are written the
+// way the problem's own tests are, e.g. "[2,7,11,15], 9" and "[0,1]".
+func (s *Suite) Add(ctx context.Context, problemID, input, expected string) (storage.UserTest, error) {
+	input, expected = strings.TrimSpace(input), strings.TrimSpace(expected)
+	if input == "" {
+		return storage.UserTest{}, fmt.Errorf("a test needs an input")
+	}
+	if expected == "" {
+		return storage.UserTest{}, fmt.Errorf("a test needs an expected output")
+	}
+	return s.store.SaveUserTest(ctx, storage.UserTest{
+		ProblemID: problemID,
+		Input:     input,
+		Expected:  expected,
+		AddedAt:   s.now(),
+	})
+}
+
+// List returns a problem's user tests, oldest first
+func (s *Suite) List(ctx context.Context, problemID string) ([]storage.UserTest, error) {
+	return s.store.LoadUserTests(ctx, problemID)
+}
+
+// Remove deletes a problem's user test by its number in List, counting
+// from one
+func (s *Suite) Remove(ctx context.Context, problemID string, number int) (storage.UserTest, error) {
+	tests, err := s.store.LoadUserTests(ctx, problemID)
+	if err != nil {
+		return storage.UserTest{}, err
+	}
+	if number < 1 || number > len(tests) {
+		return storage.UserTest{}, fmt.Errorf("%s has no user test %d (it has %d)", problemID, number, len(tests))
+	}
+	test := tests[number-1]
+	if _, err := s.store.DeleteUserTest(ctx, test.ID); err != nil {
+		return test, err
+	}
+	return test, nil
+}
+
+// Include returns a copy of the problem with its user tests appended, or the
+// problem itself if it has none
+func (s *Suite) Include(ctx context.Context, prob *interfaces.Problem) (*interfaces.Problem, error) {
+	tests, err := s.store.LoadUserTests(ctx, prob.ID)
+	if err != nil || len(tests) == 0 {
+		return prob, err
+	}
+
+	withUser := *prob
+	withUser.TestCases = append(append([]interfaces.TestCase{}, prob.TestCases...), TestCases(tests)...)
+	return &withUser, nil
+}
+
+// TestCases converts user tests to test cases in the user tier
+func TestCases(tests []storage.UserTest) []interfaces.TestCase {
+	cases := make([]interfaces.TestCase, len(tests))
+	for i, t := range tests {
+		cases[i] = interfaces.TestCase{Input: t.Input, Expected: t.Expected, Tier: interfaces.TierUser}
+	}
+	return cases
+}
+
+// Close releases the store if it holds resources
+func (s *Suite) Close() error {
+	if closer, ok := s.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Open returns a suite over the progress database
+// Exported as variable for testing
+var Open = func() *Suite {
+	return NewSuite(storage.Default())
+}
+
+// Include appends the problem's user tests from the progress database. A
+// database that cannot be read leaves the problem as it is, so test runs
+// are never blocked on it.
+func Include(prob *interfaces.Problem) *interfaces.Problem {
+	suite := Open()
+	defer suite.Close()
+	withUser, err := suite.Include(context.Background(), prob)
+	if err != nil {
+		return prob
+	}
+	return withUser
+}
@@ -0,0 +1,56 @@
+package usertests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/lancekrogers/algo-scales/internal/common/interfaces"
+	"github.com/lancekrogers/algo-scales/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuite(t *testing.T) {
+	store := storage.NewSQLiteStore(filepath.Join(t.TempDir(), storage.DBFileName))
+	suite := NewSuite(store)
+	defer suite.Close()
+	ctx := context.Background()
+
+	_, err := suite.Add(ctx, "two_sum", " ", "[0,1]")
+	assert.EqualError(t, err, "a test needs an input")
+	_, err = suite.Add(ctx, "two_sum", "[3,3], 6", "")
+	assert.EqualError(t, err, "a test needs an expected output")
+
+	added, err := suite.Add(ctx, "two_sum", " [3,3], 6 ", "[0,1]")
+	require.NoError(t, err)
+	assert.Equal(t, "[3,3], 6", added.Input)
+	_, err = suite.Add(ctx, "two_sum", "[1,2], 3", "[0,1]")
+	require.NoError(t, err)
+
+	prob := &interfaces.Problem{ID: "two_sum", TestCases: []interfaces.TestCase{{Input: "[2,7,11,15], 9", Expected: "[0,1]"}}}
+	withUser, err := suite.Include(ctx, prob)
+	require.NoError(t, err)
+	assert.Equal(t, []interfaces.TestCase{
+		{Input: "[2,7,11,15], 9", Expected: "[0,1]"},
+		{Input: "[3,3], 6", Expected: "[0,1]", Tier: interfaces.TierUser},
+		{Input: "[1,2], 3", Expected: "[0,1]", Tier: interfaces.TierUser},
+	}, withUser.TestCases)
+	assert.Len(t, prob.TestCases, 1, "the problem itself is unchanged")
+
+	other := &interfaces.Problem{ID: "coin_change"}
+	withUser, err = suite.Include(ctx, other)
+	require.NoError(t, err)
+	assert.Same(t, other, withUser)
+
+	removed, err := suite.Remove(ctx, "two_sum", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "[3,3], 6", removed.Input)
+	_, err = suite.Remove(ctx, "two_sum", 2)
+	assert.EqualError(t, err, "two_sum has no user test 2 (it has 1)")
+
+	tests, err := suite.List(ctx, "two_sum")
+	require.NoError(t, err)
+	require.Len(t, tests, 1)
+	assert.Equal(t, "[1,2], 3", tests[0].Input)
+}
@@ -0,0 +1,425 @@
+// Device-code login and the bearer tokens clients use in place of their
+// license key
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// accessTokenTTL is how long an access token is accepted. Access tokens
+	// cannot be revoked, so this bounds how long a stolen one is useful.
+	accessTokenTTL = 15 * time.Minute
+	// refreshTokenTTL is how long a refresh token can be exchanged, so a
+	// machine not used for this long has to log in again
+	refreshTokenTTL = 90 * 24 * time.Hour
+	// deviceCodeTTL is how long a login waits to be approved
+	deviceCodeTTL = 10 * time.Minute
+	// devicePollInterval is how often clients may ask whether a login was
+	// approved; slow_down adds another interval
+	devicePollInterval = 5 * time.Second
+
+	// accessTokenPrefix starts every access token and versions its format
+	accessTokenPrefix = "AT1."
+	// refreshTokenPrefix starts every refresh token
+	refreshTokenPrefix = "RT1."
+
+	// deviceGrantType is the OAuth grant type of a device code (RFC 8628)
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// userCodeAlphabet has no vowels, so user codes never spell words, and
+	// no characters that are easily confused
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+)
+
+// RefreshToken is a stored refresh token, kept by the hash of its value
+type RefreshToken struct {
+	LicenseKey string
+	ExpiresAt  time.Time
+}
+
+// deviceAuthorization is a login waiting for the user to approve it in a
+// browser
+type deviceAuthorization struct {
+	UserCode   string
+	LicenseKey string // Set once approved
+	ExpiresAt  time.Time
+	Interval   time.Duration
+	LastPoll   time.Time
+}
+
+// Device logins last minutes, so they are kept in each process like peer
+// reviews. Behind a load balancer, polls have to reach the replica that
+// started the login.
+var (
+	deviceAuthsMu sync.Mutex
+	deviceAuths   = make(map[string]*deviceAuthorization) // Keyed by device code
+)
+
+// tokenResponse is returned when a login is approved or a token refreshed
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// accessClaims are what an access token carries
+type accessClaims struct {
+	LicenseKey string `json:"key"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// requireAuth authenticates requests with an "Authorization: Bearer" header
+// holding an access token or, for clients that have not logged in, a
+// license key
+func requireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		licenseKey, ok := authenticate(c.GetHeader("Authorization"))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired credentials",
+			})
+			return
+		}
+
+		c.Set("license_key", licenseKey)
+		c.Next()
+	}
+}
+
+// authenticate returns the license an Authorization header vouches for
+func authenticate(header string) (string, bool) {
+	credential, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(credential, accessTokenPrefix) {
+		licenseKey, err := parseAccessToken(credential, time.Now())
+		if err != nil || !isValidLicense(licenseKey) {
+			return "", false
+		}
+		return licenseKey, true
+	}
+	return credential, isValidLicense(credential)
+}
+
+// startDeviceAuthorization begins a login: the client shows the user code
+// and polls getToken while the user approves it at the verification URI
+func startDeviceAuthorization(c *gin.Context) {
+	deviceCode := randomToken("")
+	now := time.Now()
+
+	deviceAuthsMu.Lock()
+	for code, auth := range deviceAuths {
+		if now.After(auth.ExpiresAt) {
+			delete(deviceAuths, code)
+		}
+	}
+	auth := &deviceAuthorization{
+		UserCode:  newUserCode(),
+		ExpiresAt: now.Add(deviceCodeTTL),
+		Interval:  devicePollInterval,
+	}
+	deviceAuths[deviceCode] = auth
+	deviceAuthsMu.Unlock()
+
+	verificationURI := requestOrigin(c) + "/device"
+	c.JSON(http.StatusOK, gin.H{
+		"device_code":               deviceCode,
+		"user_code":                 auth.UserCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?code=" + auth.UserCode,
+		"expires_in":                int(deviceCodeTTL.Seconds()),
+		"interval":                  int(devicePollInterval.Seconds()),
+	})
+}
+
+// getToken exchanges an approved device code, or a refresh token, for a new
+// access token and refresh token. Errors use the OAuth error codes.
+func getToken(c *gin.Context) {
+	var req struct {
+		GrantType    string `json:"grant_type"`
+		DeviceCode   string `json:"device_code"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", "Invalid request")
+		return
+	}
+
+	switch req.GrantType {
+	case deviceGrantType:
+		licenseKey, code, description := pollDeviceAuthorization(req.DeviceCode, time.Now())
+		if code != "" {
+			oauthError(c, http.StatusBadRequest, code, description)
+			return
+		}
+		issueTokens(c, licenseKey)
+	case "refresh_token":
+		token, ok, err := store.TakeRefreshToken(c.Request.Context(), hashToken(req.RefreshToken))
+		if err != nil {
+			oauthError(c, http.StatusInternalServerError, "server_error", "Failed to load refresh token")
+			return
+		}
+		if !ok || time.Now().After(token.ExpiresAt) || !isValidLicense(token.LicenseKey) {
+			oauthError(c, http.StatusBadRequest, "invalid_grant", "Refresh token is invalid or expired, log in again")
+			return
+		}
+		issueTokens(c, token.LicenseKey)
+	default:
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "Unsupported grant type")
+	}
+}
+
+// revokeToken logs a machine out by deleting its refresh token. Its access
+// token keeps working until it expires.
+func revokeToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	_, revoked, err := store.TakeRefreshToken(c.Request.Context(), hashToken(req.RefreshToken))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// devicePage is where users approve a login with their license key
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Log in to AlgoScales</title></head>
+<body style="font-family: sans-serif; max-width: 28rem; margin: 4rem auto">
+<h1>Log in to AlgoScales</h1>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if not .Done}}<form method="post" action="/device">
+<p><label>Code shown in your terminal<br><input name="user_code" value="{{.UserCode}}" autocomplete="off" required></label></p>
+<p><label>License key<br><input name="license_key" type="password" required></label></p>
+<p><button type="submit">Approve</button></p>
+</form>{{end}}
+</body>
+</html>
+`))
+
+// showDevicePage serves the form for approving a login
+func showDevicePage(c *gin.Context) {
+	renderDevicePage(c, http.StatusOK, c.Query("code"), "", false)
+}
+
+// approveDevice approves the login with a user code for the license given
+func approveDevice(c *gin.Context) {
+	userCode := normalizeUserCode(c.PostForm("user_code"))
+	licenseKey := strings.TrimSpace(c.PostForm("license_key"))
+	if !isValidLicense(licenseKey) {
+		renderDevicePage(c, http.StatusUnauthorized, userCode, "That license key is not valid.", false)
+		return
+	}
+
+	deviceAuthsMu.Lock()
+	defer deviceAuthsMu.Unlock()
+	now := time.Now()
+	for _, auth := range deviceAuths {
+		if auth.UserCode == userCode && now.Before(auth.ExpiresAt) && auth.LicenseKey == "" {
+			auth.LicenseKey = licenseKey
+			renderDevicePage(c, http.StatusOK, "", "Approved. You can close this page and return to your terminal.", true)
+			return
+		}
+	}
+	renderDevicePage(c, http.StatusNotFound, userCode, "That code is unknown or has expired. Run 'algo-scales login' again.", false)
+}
+
+// renderDevicePage writes the device page
+func renderDevicePage(c *gin.Context, status int, userCode, message string, done bool) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	devicePage.Execute(c.Writer, struct {
+		UserCode, Message string
+		Done              bool
+	}{userCode, message, done})
+}
+
+// pollDeviceAuthorization returns the license that approved a device code,
+// or the OAuth error code and description to answer the poll with. An
+// approved code is used up.
+func pollDeviceAuthorization(deviceCode string, now time.Time) (licenseKey, code, description string) {
+	deviceAuthsMu.Lock()
+	defer deviceAuthsMu.Unlock()
+
+	auth, ok := deviceAuths[deviceCode]
+	switch {
+	case !ok:
+		return "", "invalid_grant", "Unknown device code"
+	case now.After(auth.ExpiresAt):
+		delete(deviceAuths, deviceCode)
+		return "", "expired_token", "The login was not approved in time"
+	case auth.LicenseKey != "":
+		delete(deviceAuths, deviceCode)
+		return auth.LicenseKey, "", ""
+	case now.Sub(auth.LastPoll) < auth.Interval:
+		auth.Interval += devicePollInterval
+		auth.LastPoll = now
+		return "", "slow_down", "Polling too often"
+	default:
+		auth.LastPoll = now
+		return "", "authorization_pending", "Waiting for the login to be approved"
+	}
+}
+
+// issueTokens answers with a new access token and refresh token for a
+// license
+func issueTokens(c *gin.Context, licenseKey string) {
+	now := time.Now()
+	refreshToken := randomToken(refreshTokenPrefix)
+	err := store.SaveRefreshToken(c.Request.Context(), hashToken(refreshToken), RefreshToken{
+		LicenseKey: licenseKey,
+		ExpiresAt:  now.Add(refreshTokenTTL),
+	})
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to save refresh token")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, tokenResponse{
+		AccessToken:  signAccessToken(licenseKey, now.Add(accessTokenTTL)),
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	})
+}
+
+// oauthError answers with an OAuth error
+func oauthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+// signAccessToken creates an access token for a license. The token is the
+// license key and expiry sealed with AES-GCM under a key derived from the
+// signing key, so any replica with the same signing key can open it and the
+// license key cannot be read from it.
+func signAccessToken(licenseKey string, expiresAt time.Time) string {
+	payload, err := json.Marshal(accessClaims{LicenseKey: licenseKey, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		// Claims are plain values, so encoding cannot fail
+		panic(err)
+	}
+	aead := accessTokenCipher()
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	return accessTokenPrefix + base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, payload, nil))
+}
+
+// parseAccessToken returns the license an access token was issued for
+func parseAccessToken(token string, now time.Time) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, accessTokenPrefix))
+	if err != nil {
+		return "", fmt.Errorf("not an access token: %v", err)
+	}
+	aead := accessTokenCipher()
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("not an access token")
+	}
+	payload, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("access token was not issued by this server")
+	}
+
+	var claims accessClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("not an access token: %v", err)
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return "", errors.New("access token has expired")
+	}
+	return claims.LicenseKey, nil
+}
+
+// accessTokenCipher seals access tokens with a key derived from the
+// signing key
+func accessTokenCipher() cipher.AEAD {
+	key := sha256.Sum256(append([]byte("algo-scales access token\x00"), signingKey.Seed()...))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		// A SHA-256 sum is always a valid AES-256 key
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return aead
+}
+
+// randomToken returns a random, unguessable token with a prefix
+func randomToken(prefix string) string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return prefix + base64.RawURLEncoding.EncodeToString(b)
+}
+
+// hashToken returns the hash refresh tokens are stored by, so a leaked
+// database does not leak usable tokens
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newUserCode returns a short code for the user to type, like BCDF-GHJK
+func newUserCode() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	code := make([]byte, 0, 9)
+	for i, v := range b {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, userCodeAlphabet[int(v)%len(userCodeAlphabet)])
+	}
+	return string(code)
+}
+
+// normalizeUserCode accepts a user code typed in any case, with or without
+// its dash and spaces
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// requestOrigin returns the scheme and host the client reached the server
+// at, for links back to it
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
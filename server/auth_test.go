@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAccessToken(t *testing.T) {
+	now := time.Now()
+	token := signAccessToken(testLicense, now.Add(time.Minute))
+	if !strings.HasPrefix(token, accessTokenPrefix) || strings.Contains(token, testLicense) {
+		t.Fatalf("expected an opaque access token, got %q", token)
+	}
+	if licenseKey, err := parseAccessToken(token, now); err != nil || licenseKey != testLicense {
+		t.Fatalf("expected the token to name the test license, got %q, %v", licenseKey, err)
+	}
+	if _, err := parseAccessToken(token, now.Add(time.Minute)); err == nil {
+		t.Fatal("expected an expired token to be refused")
+	}
+	if _, err := parseAccessToken(token[:len(token)-2]+"AA", now); err == nil {
+		t.Fatal("expected a tampered token to be refused")
+	}
+
+	// Tokens sealed by a server with another signing key are refused
+	original := signingKey
+	_, signingKey, _ = ed25519.GenerateKey(nil)
+	_, err := parseAccessToken(token, now)
+	signingKey = original
+	if err == nil {
+		t.Fatal("expected a token from another server to be refused")
+	}
+}
+
+func TestUserCodes(t *testing.T) {
+	code := newUserCode()
+	if len(code) != 9 || code[4] != '-' || strings.Trim(strings.Replace(code, "-", "", 1), userCodeAlphabet) != "" {
+		t.Fatalf("unexpected user code %q", code)
+	}
+	if got := normalizeUserCode(" bcdf ghjk"); got != "BCDF-GHJK" {
+		t.Fatalf("expected BCDF-GHJK, got %q", got)
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	post := func(path, body string, v interface{}) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body)))
+		if v != nil {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+		}
+		return w.Code
+	}
+	authorized := func(token string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/problems", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	var device struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		Interval        int    `json:"interval"`
+	}
+	if code := post("/v1/auth/device", "", &device); code != http.StatusOK {
+		t.Fatalf("expected a device code, got %d", code)
+	}
+	if device.VerificationURI != "http://example.com/device" || device.Interval != 5 {
+		t.Fatalf("unexpected device authorization %+v", device)
+	}
+
+	var tokens struct {
+		tokenResponse
+		Error string `json:"error"`
+	}
+	poll := func() int {
+		t.Helper()
+		// Clients wait an interval between polls
+		deviceAuthsMu.Lock()
+		if auth, ok := deviceAuths[device.DeviceCode]; ok {
+			auth.LastPoll = time.Time{}
+		}
+		deviceAuthsMu.Unlock()
+		tokens.Error = ""
+		return post("/v1/auth/token", `{"grant_type":"`+deviceGrantType+`","device_code":"`+device.DeviceCode+`"}`, &tokens)
+	}
+	if poll(); tokens.Error != "authorization_pending" {
+		t.Fatalf("expected the login to be pending, got %q", tokens.Error)
+	}
+	post("/v1/auth/token", `{"grant_type":"`+deviceGrantType+`","device_code":"`+device.DeviceCode+`"}`, &tokens)
+	if tokens.Error != "slow_down" {
+		t.Fatalf("expected a poll within the interval to be slowed down, got %q", tokens.Error)
+	}
+
+	// The user approves the login in a browser
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/device?code="+device.UserCode, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `value="`+device.UserCode+`"`) {
+		t.Fatalf("expected the approval form with the code filled in, got %d: %s", w.Code, w.Body.String())
+	}
+	approve := func(userCode, licenseKey string) int {
+		form := url.Values{"user_code": {userCode}, "license_key": {licenseKey}}
+		req := httptest.NewRequest(http.MethodPost, "/device", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := approve(device.UserCode, "not-a-license"); code != http.StatusUnauthorized {
+		t.Fatalf("expected an invalid license to be refused, got %d", code)
+	}
+	if code := approve("BCDF-BCDF", testLicense); code != http.StatusNotFound {
+		t.Fatalf("expected an unknown code to be refused, got %d", code)
+	}
+	if code := approve(strings.ToLower(strings.Replace(device.UserCode, "-", "", 1)), testLicense); code != http.StatusOK {
+		t.Fatalf("expected the login to be approved, got %d", code)
+	}
+
+	if code := poll(); code != http.StatusOK || tokens.TokenType != "Bearer" || tokens.ExpiresIn != 900 {
+		t.Fatalf("expected tokens, got %d: %+v", code, tokens)
+	}
+	if code := poll(); code != http.StatusBadRequest || tokens.Error != "invalid_grant" {
+		t.Fatalf("expected the device code to be used up, got %d: %q", code, tokens.Error)
+	}
+	if code := authorized(tokens.AccessToken); code != http.StatusOK {
+		t.Fatalf("expected the access token to be accepted, got %d", code)
+	}
+	if code := authorized(""); code != http.StatusUnauthorized {
+		t.Fatalf("expected problems to need credentials, got %d", code)
+	}
+	if code := authorized(testLicense); code != http.StatusOK {
+		t.Fatalf("expected license keys to still be accepted, got %d", code)
+	}
+
+	// Refresh tokens are rotated on use
+	first := tokens.RefreshToken
+	refresh := func(token string) int {
+		tokens.Error = ""
+		return post("/v1/auth/token", `{"grant_type":"refresh_token","refresh_token":"`+token+`"}`, &tokens)
+	}
+	if code := refresh(first); code != http.StatusOK || tokens.RefreshToken == first {
+		t.Fatalf("expected a new refresh token, got %d: %+v", code, tokens)
+	}
+	if code := authorized(tokens.AccessToken); code != http.StatusOK {
+		t.Fatalf("expected the refreshed access token to be accepted, got %d", code)
+	}
+	if refresh(first); tokens.Error != "invalid_grant" {
+		t.Fatalf("expected a used refresh token to be refused, got %q", tokens.Error)
+	}
+
+	// Logging out revokes the refresh token
+	second := tokens.RefreshToken
+	var revoked struct {
+		Revoked bool `json:"revoked"`
+	}
+	if post("/v1/auth/revoke", `{"refresh_token":"`+second+`"}`, &revoked); !revoked.Revoked {
+		t.Fatal("expected the refresh token to be revoked")
+	}
+	if refresh(second); tokens.Error != "invalid_grant" {
+		t.Fatalf("expected a revoked refresh token to be refused, got %q", tokens.Error)
+	}
+	if code := post("/v1/auth/token", `{"grant_type":"password"}`, &tokens); code != http.StatusBadRequest || tokens.Error != "unsupported_grant_type" {
+		t.Fatalf("expected other grants to be refused, got %d: %q", code, tokens.Error)
+	}
+}
+
+func TestPollDeviceAuthorization_Expired(t *testing.T) {
+	now := time.Now()
+	deviceAuthsMu.Lock()
+	deviceAuths["expired"] = &deviceAuthorization{UserCode: "BCDF-GHJK", ExpiresAt: now.Add(-time.Second), Interval: devicePollInterval}
+	deviceAuthsMu.Unlock()
+
+	if _, code, _ := pollDeviceAuthorization("expired", now); code != "expired_token" {
+		t.Fatalf("expected expired_token, got %q", code)
+	}
+	if _, code, _ := pollDeviceAuthorization("expired", now); code != "invalid_grant" {
+		t.Fatalf("expected the expired code to be forgotten, got %q", code)
+	}
+}
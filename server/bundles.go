@@ -0,0 +1,175 @@
+// Content-addressed problem bundles with compression, for CDN caching
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Cache policies. Bundle URLs embed the content hash, so a bundle never
+// changes and can be cached forever; the manifest pointing at the current
+// bundle is revalidated frequently.
+const (
+	bundleCacheControl   = "public, max-age=31536000, immutable"
+	manifestCacheControl = "private, max-age=60"
+)
+
+// BundleManifest points clients at the current problem bundle
+type BundleManifest struct {
+	Version string `json:"version"`
+	Hash    string `json:"hash"` // hex SHA-256 of the uncompressed bundle
+	Size    int    `json:"size"`
+	URL     string `json:"url"`
+}
+
+// bundle is a serialized problem set with precomputed encodings
+type bundle struct {
+	hash     string
+	identity []byte
+	gzip     []byte
+	brotli   []byte
+}
+
+var (
+	bundleOnce    sync.Once
+	currentBundle *bundle
+	bundleErr     error
+)
+
+// getBundle serializes and compresses the problem set once
+func getBundle() (*bundle, error) {
+	bundleOnce.Do(func() {
+		currentBundle, bundleErr = buildBundle(problemsDB)
+	})
+	return currentBundle, bundleErr
+}
+
+// buildBundle encodes a problem set and precomputes its compressed forms
+func buildBundle(set ProblemSet) (*bundle, error) {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+
+	b := &bundle{hash: hex.EncodeToString(sum[:]), identity: data}
+
+	var gz bytes.Buffer
+	gw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	b.gzip = gz.Bytes()
+
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, brotli.BestCompression)
+	if _, err := bw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+	b.brotli = br.Bytes()
+
+	return b, nil
+}
+
+// getBundleManifest returns the location and hash of the current bundle
+func getBundleManifest(c *gin.Context) {
+	b, err := getBundle()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build bundle"})
+		return
+	}
+
+	c.Header("Cache-Control", manifestCacheControl)
+	c.JSON(http.StatusOK, BundleManifest{
+		Version: problemsDB.Version,
+		Hash:    b.hash,
+		Size:    len(b.identity),
+		URL:     "/v1/bundles/" + b.hash + ".json",
+	})
+}
+
+// getBundleByHash serves a bundle by content hash. The hash is the only
+// thing identifying the response, so any cache may store and share it.
+func getBundleByHash(c *gin.Context) {
+	b, err := getBundle()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build bundle"})
+		return
+	}
+
+	if strings.TrimSuffix(c.Param("name"), ".json") != b.hash {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+		return
+	}
+
+	etag := `"` + b.hash + `"`
+	c.Header("Cache-Control", bundleCacheControl)
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	writeEncoded(c, b, "application/json")
+}
+
+// writeEncoded sends the best encoding of a bundle the client accepts
+func writeEncoded(c *gin.Context, b *bundle, contentType string) {
+	c.Header("Vary", "Accept-Encoding")
+
+	body := b.identity
+	switch negotiateEncoding(c.GetHeader("Accept-Encoding")) {
+	case "br":
+		c.Header("Content-Encoding", "br")
+		body = b.brotli
+	case "gzip":
+		c.Header("Content-Encoding", "gzip")
+		body = b.gzip
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// negotiateEncoding picks br, gzip or identity from an Accept-Encoding header,
+// preferring brotli when the client accepts both
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		rejected := false
+		for _, param := range fields[1:] {
+			if q := strings.TrimSpace(param); q == "q=0" || q == "q=0.0" || q == "q=0.00" || q == "q=0.000" {
+				rejected = true
+			}
+		}
+		if name != "" && !rejected {
+			accepted[name] = true
+		}
+	}
+
+	switch {
+	case accepted["br"]:
+		return "br"
+	case accepted["gzip"]:
+		return "gzip"
+	default:
+		return ""
+	}
+}
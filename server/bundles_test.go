@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                     "",
+		"gzip":                 "gzip",
+		"gzip, deflate, br":    "br",
+		"br;q=0, gzip":         "gzip",
+		"identity":             "",
+		"GZIP;q=0.5, deflate":  "gzip",
+		"br;q=0, gzip;q=0.000": "",
+	}
+	for header, want := range cases {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestBundleDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	// The manifest requires a license
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/problems/manifest", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/problems/manifest", nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.URL != "/v1/bundles/"+manifest.Hash+".json" {
+		t.Fatalf("unexpected bundle url %q", manifest.URL)
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"": func(r io.Reader) (io.Reader, error) { return r, nil },
+		"gzip": func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+		"br": func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+	for encoding, decode := range decoders {
+		req := httptest.NewRequest(http.MethodGet, manifest.URL, nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d", encoding, w.Code)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != encoding {
+			t.Fatalf("expected Content-Encoding %q, got %q", encoding, got)
+		}
+		if got := w.Header().Get("Cache-Control"); got != bundleCacheControl {
+			t.Fatalf("unexpected Cache-Control %q", got)
+		}
+
+		body, err := decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != manifest.Hash {
+			t.Fatalf("%q: body does not match manifest hash", encoding)
+		}
+	}
+
+	// Revalidation by ETag
+	req = httptest.NewRequest(http.MethodGet, manifest.URL, nil)
+	req.Header.Set("If-None-Match", `"`+manifest.Hash+`"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", w.Code)
+	}
+
+	// Unknown hashes are not served
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/bundles/deadbeef.json", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
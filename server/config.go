@@ -0,0 +1,278 @@
+// Server configuration from the environment, overridable by flags
+
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the server's configuration. Every setting can be given as an
+// environment variable, so containers need no config file, and overridden
+// by a flag.
+type Config struct {
+	Port        string // REST port (PORT, -port)
+	GRPCPort    string // gRPC port (GRPC_PORT, -grpc-port)
+	DatabaseURL string // Store DSN, see openStore (DATABASE_URL, -database-url)
+
+	// SigningKey is the base64 Ed25519 seed or private key licenses are
+	// signed with (LICENSE_SIGNING_KEY, or a file named by
+	// LICENSE_SIGNING_KEY_FILE or -signing-key-file)
+	SigningKey string
+
+	// CORSOrigins may call the REST API from a browser; "*" allows any
+	// origin (CORS_ALLOWED_ORIGINS, -cors-origins, comma separated)
+	CORSOrigins []string
+
+	// RateLimit is the requests per second allowed from each client IP,
+	// with bursts of RateBurst; 0 disables limiting (RATE_LIMIT_RPS,
+	// RATE_LIMIT_BURST, -rate-limit, -rate-burst)
+	RateLimit float64
+	RateBurst int
+
+	// LicenseRateLimit is the requests per second allowed for each license
+	// across all its machines and IPs, with bursts of LicenseRateBurst; 0
+	// disables limiting (LICENSE_RATE_LIMIT_RPS, LICENSE_RATE_LIMIT_BURST,
+	// -license-rate-limit, -license-rate-burst)
+	LicenseRateLimit float64
+	LicenseRateBurst int
+
+	// APIKeys may register licenses, given in an X-API-Key header; without
+	// any, anyone may (API_KEYS, -api-keys, comma separated)
+	APIKeys []string
+
+	// LogFormat is how requests are logged: "text" or "json" (LOG_FORMAT,
+	// -log-format)
+	LogFormat string
+
+	// TrustedProxies may set X-Forwarded-For, which then names the client
+	// IP; without any the connection's address is used (TRUSTED_PROXIES,
+	// -trusted-proxies, comma separated IPs or CIDRs)
+	TrustedProxies []string
+
+	// ShutdownTimeout is how long in-flight requests get to finish after
+	// SIGTERM (SHUTDOWN_TIMEOUT, -shutdown-timeout)
+	ShutdownTimeout time.Duration
+
+	// Production refuses to start without the settings replicas must
+	// share: a database and a signing key (SERVER_ENV=production,
+	// -production)
+	Production bool
+}
+
+// defaultConfig is the configuration without any settings
+func defaultConfig() Config {
+	return Config{
+		Port:             "8080",
+		GRPCPort:         "9090",
+		RateLimit:        10,
+		RateBurst:        20,
+		LicenseRateLimit: 5,
+		LicenseRateBurst: 30,
+		LogFormat:        "text",
+		ShutdownTimeout:  15 * time.Second,
+	}
+}
+
+// loadConfig reads the configuration from the environment and then the
+// command-line arguments, which take precedence
+func loadConfig(args []string, getenv func(string) string) (Config, error) {
+	cfg := defaultConfig()
+	if err := cfg.readEnv(getenv); err != nil {
+		return Config{}, err
+	}
+
+	var (
+		signingKeyFile = getenv("LICENSE_SIGNING_KEY_FILE")
+		corsOrigins    = strings.Join(cfg.CORSOrigins, ",")
+		trustedProxies = strings.Join(cfg.TrustedProxies, ",")
+		apiKeys        = strings.Join(cfg.APIKeys, ",")
+	)
+	fs := flag.NewFlagSet("algo-scales-server", flag.ContinueOnError)
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "REST port")
+	fs.StringVar(&cfg.GRPCPort, "grpc-port", cfg.GRPCPort, "gRPC port")
+	fs.StringVar(&cfg.DatabaseURL, "database-url", cfg.DatabaseURL, "postgres:// or sqlite:// database")
+	fs.StringVar(&signingKeyFile, "signing-key-file", signingKeyFile, "file holding the license signing key")
+	fs.StringVar(&corsOrigins, "cors-origins", corsOrigins, "comma-separated origins allowed to call the API from a browser")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", cfg.RateLimit, "requests per second allowed from each client; 0 disables")
+	fs.IntVar(&cfg.RateBurst, "rate-burst", cfg.RateBurst, "requests a client may burst above the rate limit")
+	fs.Float64Var(&cfg.LicenseRateLimit, "license-rate-limit", cfg.LicenseRateLimit, "requests per second allowed for each license; 0 disables")
+	fs.IntVar(&cfg.LicenseRateBurst, "license-rate-burst", cfg.LicenseRateBurst, "requests a license may burst above its rate limit")
+	fs.StringVar(&apiKeys, "api-keys", apiKeys, "comma-separated API keys allowed to register licenses")
+	fs.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "request log format: text or json")
+	fs.StringVar(&trustedProxies, "trusted-proxies", trustedProxies, "comma-separated proxies trusted to set X-Forwarded-For")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "time in-flight requests get to finish on shutdown")
+	fs.BoolVar(&cfg.Production, "production", cfg.Production, "require a database and signing key")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	cfg.CORSOrigins = splitList(corsOrigins)
+	cfg.TrustedProxies = splitList(trustedProxies)
+	cfg.APIKeys = splitList(apiKeys)
+
+	if signingKeyFile != "" {
+		data, err := os.ReadFile(signingKeyFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read signing key: %v", err)
+		}
+		cfg.SigningKey = strings.TrimSpace(string(data))
+	}
+
+	return cfg, cfg.validate()
+}
+
+// readEnv applies the settings given in the environment
+func (cfg *Config) readEnv(getenv func(string) string) error {
+	if v := getenv("PORT"); v != "" {
+		cfg.Port = v
+	}
+	if v := getenv("GRPC_PORT"); v != "" {
+		cfg.GRPCPort = v
+	}
+	cfg.DatabaseURL = getenv("DATABASE_URL")
+	cfg.SigningKey = getenv("LICENSE_SIGNING_KEY")
+	cfg.CORSOrigins = splitList(getenv("CORS_ALLOWED_ORIGINS"))
+	cfg.TrustedProxies = splitList(getenv("TRUSTED_PROXIES"))
+	cfg.APIKeys = splitList(getenv("API_KEYS"))
+	cfg.Production = getenv("SERVER_ENV") == "production"
+
+	if v := getenv("RATE_LIMIT_RPS"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid RATE_LIMIT_RPS %q", v)
+		}
+		cfg.RateLimit = rate
+	}
+	if v := getenv("RATE_LIMIT_BURST"); v != "" {
+		burst, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid RATE_LIMIT_BURST %q", v)
+		}
+		cfg.RateBurst = burst
+	}
+	if v := getenv("LICENSE_RATE_LIMIT_RPS"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid LICENSE_RATE_LIMIT_RPS %q", v)
+		}
+		cfg.LicenseRateLimit = rate
+	}
+	if v := getenv("LICENSE_RATE_LIMIT_BURST"); v != "" {
+		burst, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid LICENSE_RATE_LIMIT_BURST %q", v)
+		}
+		cfg.LicenseRateBurst = burst
+	}
+	if v := getenv("LOG_FORMAT"); v != "" {
+		cfg.LogFormat = v
+	}
+	if v := getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid SHUTDOWN_TIMEOUT %q", v)
+		}
+		cfg.ShutdownTimeout = timeout
+	}
+	return nil
+}
+
+// validate checks the settings fit together
+func (cfg Config) validate() error {
+	if cfg.RateLimit < 0 || (cfg.RateLimit > 0 && cfg.RateBurst < 1) {
+		return fmt.Errorf("the rate limit must be 0 or more, with a burst of at least 1")
+	}
+	if cfg.LicenseRateLimit < 0 || (cfg.LicenseRateLimit > 0 && cfg.LicenseRateBurst < 1) {
+		return fmt.Errorf("the license rate limit must be 0 or more, with a burst of at least 1")
+	}
+	for _, key := range cfg.APIKeys {
+		if len(key) < minAPIKeyLength {
+			return fmt.Errorf("API keys must be at least %d characters", minAPIKeyLength)
+		}
+	}
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		return fmt.Errorf("invalid log format %q: expected text or json", cfg.LogFormat)
+	}
+	if cfg.SigningKey != "" {
+		if _, err := parseSigningKey(cfg.SigningKey); err != nil {
+			return err
+		}
+	}
+	if cfg.Production {
+		// Replicas must share licenses and progress, and accept each
+		// other's licenses
+		if cfg.DatabaseURL == "" {
+			return fmt.Errorf("production servers need DATABASE_URL")
+		}
+		if cfg.SigningKey == "" {
+			return fmt.Errorf("production servers need LICENSE_SIGNING_KEY or LICENSE_SIGNING_KEY_FILE")
+		}
+	}
+	return nil
+}
+
+// signingKey returns the key licenses are signed with. Without one the
+// server signs with a throwaway key, so its licenses only work against
+// itself until it restarts.
+func (cfg Config) signingKey() ed25519.PrivateKey {
+	if cfg.SigningKey != "" {
+		// Checked by validate
+		key, _ := parseSigningKey(cfg.SigningKey)
+		return key
+	}
+	log.Println("LICENSE_SIGNING_KEY is not set, signing licenses with a temporary key")
+	return temporarySigningKey()
+}
+
+// logger returns the request logger in the configured format
+func (cfg Config) logger() *slog.Logger {
+	if cfg.LogFormat == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// parseSigningKey decodes a base64 Ed25519 seed or private key
+func parseSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid license signing key: %v", err)
+	case len(key) == ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case len(key) == ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	default:
+		return nil, fmt.Errorf("invalid license signing key: expected %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(key))
+	}
+}
+
+// temporarySigningKey generates a signing key that lasts as long as the
+// process
+func temporarySigningKey() ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalf("Failed to generate license signing key: %v", err)
+	}
+	return key
+}
+
+// splitList splits a comma-separated setting, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
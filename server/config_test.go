@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// envFrom returns a getenv over a fixed environment
+func envFrom(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := loadConfig(nil, envFrom(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, defaultConfig()) {
+		t.Fatalf("expected the defaults without settings, got %+v", cfg)
+	}
+
+	seed := base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize))
+	keyFile := filepath.Join(t.TempDir(), "signing-key")
+	if err := os.WriteFile(keyFile, []byte(seed+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flags override the environment
+	cfg, err = loadConfig(
+		[]string{"-port", "9000", "-rate-limit", "0", "-license-rate-burst", "8", "-signing-key-file", keyFile},
+		envFrom(map[string]string{
+			"PORT":                     "8000",
+			"DATABASE_URL":             "sqlite:///data/server.db",
+			"CORS_ALLOWED_ORIGINS":     "https://a.example, https://b.example",
+			"RATE_LIMIT_RPS":           "5",
+			"TRUSTED_PROXIES":          "10.0.0.0/8",
+			"SHUTDOWN_TIMEOUT":         "30s",
+			"SERVER_ENV":               "production",
+			"LICENSE_RATE_LIMIT_RPS":   "2",
+			"LICENSE_RATE_LIMIT_BURST": "4",
+			"API_KEYS":                 "key-0123456789abcdef, key-fedcba9876543210",
+			"LOG_FORMAT":               "json",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "9000" || cfg.RateLimit != 0 || cfg.DatabaseURL != "sqlite:///data/server.db" || cfg.SigningKey != seed {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.CORSOrigins, []string{"https://a.example", "https://b.example"}) || !reflect.DeepEqual(cfg.TrustedProxies, []string{"10.0.0.0/8"}) {
+		t.Fatalf("unexpected lists %v and %v", cfg.CORSOrigins, cfg.TrustedProxies)
+	}
+	if !cfg.Production || cfg.ShutdownTimeout != 30*time.Second {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.LicenseRateLimit != 2 || cfg.LicenseRateBurst != 8 || cfg.LogFormat != "json" ||
+		!reflect.DeepEqual(cfg.APIKeys, []string{"key-0123456789abcdef", "key-fedcba9876543210"}) {
+		t.Fatalf("unexpected limits, API keys or log format %+v", cfg)
+	}
+	if !cfg.signingKey().Equal(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))) {
+		t.Fatal("expected the configured signing key")
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	for name, env := range map[string]map[string]string{
+		"rate":               {"RATE_LIMIT_RPS": "fast"},
+		"burst":              {"RATE_LIMIT_BURST": "0"},
+		"license rate":       {"LICENSE_RATE_LIMIT_RPS": "-1"},
+		"license burst":      {"LICENSE_RATE_LIMIT_BURST": "none"},
+		"short API key":      {"API_KEYS": "secret"},
+		"log format":         {"LOG_FORMAT": "xml"},
+		"signing key":        {"LICENSE_SIGNING_KEY": "c2hvcnQ="},
+		"production without": {"SERVER_ENV": "production"},
+		"production no key":  {"SERVER_ENV": "production", "DATABASE_URL": "sqlite:///tmp/db"},
+	} {
+		if _, err := loadConfig(nil, envFrom(env)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := loadConfig([]string{"-unknown"}, envFrom(nil)); err == nil {
+		t.Error("expected an error for an unknown flag")
+	}
+}
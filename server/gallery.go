@@ -0,0 +1,169 @@
+// Opt-in hall-of-fame galleries of exemplary solutions
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GalleryEntry is a solution published to a problem's hall of fame. The
+// author is kept server-side only so entries stay anonymous.
+type GalleryEntry struct {
+	ID          string    `json:"id"`
+	ProblemID   string    `json:"problem_id"`
+	Language    string    `json:"language"`
+	Code        string    `json:"code"`
+	Upvotes     int       `json:"upvotes"`
+	PublishedAt time.Time `json:"published_at"`
+	Upvoted     bool      `json:"upvoted"` // Whether the caller upvoted the entry
+	Mine        bool      `json:"mine"`    // Whether the caller published the entry
+	Author      string    `json:"-"`
+}
+
+var (
+	galleryMu      sync.Mutex
+	gallerySeq     int
+	galleryEntries = make(map[string]*GalleryEntry)
+	galleryVotes   = make(map[string]map[string]bool) // Voters keyed by entry ID
+)
+
+// publishToGallery adds the caller's solution to a problem's hall of fame.
+// Publishing again in the same language replaces the earlier solution but
+// keeps its upvotes.
+func publishToGallery(c *gin.Context) {
+	var entry GalleryEntry
+	if err := c.BindJSON(&entry); err != nil || entry.ProblemID == "" || entry.Language == "" || strings.TrimSpace(entry.Code) == "" || len(entry.Code) > maxReviewCodeBytes {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request",
+		})
+		return
+	}
+	caller := c.GetString("license_key")
+
+	galleryMu.Lock()
+	defer galleryMu.Unlock()
+
+	for _, existing := range galleryEntries {
+		if existing.Author == caller && existing.ProblemID == entry.ProblemID && existing.Language == entry.Language {
+			existing.Code = entry.Code
+			existing.PublishedAt = time.Now()
+			c.JSON(http.StatusOK, gin.H{
+				"id": existing.ID,
+			})
+			return
+		}
+	}
+
+	gallerySeq++
+	stored := &GalleryEntry{
+		ID:          fmt.Sprintf("hof-%d", gallerySeq),
+		ProblemID:   entry.ProblemID,
+		Language:    entry.Language,
+		Code:        entry.Code,
+		PublishedAt: time.Now(),
+		Author:      caller,
+	}
+	galleryEntries[stored.ID] = stored
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id": stored.ID,
+	})
+}
+
+// getGallery returns a problem's hall of fame, most upvoted first
+func getGallery(c *gin.Context) {
+	caller := c.GetString("license_key")
+	problemID := c.Param("id")
+
+	galleryMu.Lock()
+	entries := []GalleryEntry{}
+	for _, entry := range galleryEntries {
+		if entry.ProblemID != problemID {
+			continue
+		}
+		view := *entry
+		view.Upvotes = len(galleryVotes[entry.ID])
+		view.Upvoted = galleryVotes[entry.ID][caller]
+		view.Mine = entry.Author == caller
+		entries = append(entries, view)
+	}
+	galleryMu.Unlock()
+
+	// Most upvoted first, then the earliest published
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Upvotes != entries[j].Upvotes {
+			return entries[i].Upvotes > entries[j].Upvotes
+		}
+		return entries[i].PublishedAt.Before(entries[j].PublishedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
+
+// upvoteGalleryEntry records the caller's upvote of another user's entry
+func upvoteGalleryEntry(c *gin.Context) {
+	caller := c.GetString("license_key")
+
+	galleryMu.Lock()
+	defer galleryMu.Unlock()
+
+	entry, ok := galleryEntries[c.Param("id")]
+	switch {
+	case !ok:
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Entry not found",
+		})
+		return
+	case entry.Author == caller:
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot upvote your own solution",
+		})
+		return
+	case galleryVotes[entry.ID][caller]:
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "You already upvoted this solution",
+		})
+		return
+	}
+
+	if galleryVotes[entry.ID] == nil {
+		galleryVotes[entry.ID] = make(map[string]bool)
+	}
+	galleryVotes[entry.ID][caller] = true
+
+	c.JSON(http.StatusOK, gin.H{
+		"upvotes": len(galleryVotes[entry.ID]),
+	})
+}
+
+// deleteGalleryData removes a user's entries, the upvotes of them and the
+// upvotes they cast. It returns the number of records removed.
+func deleteGalleryData(licenseKey string) int {
+	galleryMu.Lock()
+	defer galleryMu.Unlock()
+
+	deleted := 0
+	for id, entry := range galleryEntries {
+		if entry.Author == licenseKey {
+			deleted += 1 + len(galleryVotes[id])
+			delete(galleryEntries, id)
+			delete(galleryVotes, id)
+		}
+	}
+	for _, voters := range galleryVotes {
+		if voters[licenseKey] {
+			deleted++
+			delete(voters, licenseKey)
+		}
+	}
+	return deleted
+}
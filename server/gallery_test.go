@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHallOfFameGallery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	alice, bob, carol := testLicenseFor("alice-hof"), testLicenseFor("bob-hof"), testLicenseFor("carol-hof")
+
+	publish := func(license, language, code string) (int, string) {
+		w := reviewRequest(r, http.MethodPost, "/v1/gallery/entries", license, GalleryEntry{ProblemID: "jump-game", Language: language, Code: code})
+		var body struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.ID
+	}
+
+	if code, _ := publish(alice, "go", " "); code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", code)
+	}
+	code, aliceID := publish(alice, "go", "func canJump() {}")
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	_, bobID := publish(bob, "python", "def can_jump(): pass")
+
+	// Upvotes are one per user and never for your own solution
+	upvote := func(license, id string) int {
+		return reviewRequest(r, http.MethodPost, "/v1/gallery/entries/"+id+"/upvote", license, nil).Code
+	}
+	if code := upvote(bob, bobID); code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", code)
+	}
+	if code := upvote(alice, bobID); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := upvote(alice, bobID); code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", code)
+	}
+	if code := upvote(carol, bobID); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := upvote(carol, "hof-missing"); code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+
+	// Republishing replaces the code but keeps the entry and its upvotes
+	if code := upvote(bob, aliceID); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code, id := publish(alice, "go", "func canJump() bool { return true }"); code != http.StatusOK || id != aliceID {
+		t.Fatalf("expected the entry to be updated, got %d %s", code, id)
+	}
+
+	w := reviewRequest(r, http.MethodGet, "/v1/problems/jump-game/gallery", alice, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(bob)) {
+		t.Fatalf("gallery leaked an author: %s", w.Body.String())
+	}
+	var gallery struct {
+		Entries []GalleryEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &gallery); err != nil {
+		t.Fatal(err)
+	}
+	if len(gallery.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", gallery.Entries)
+	}
+	top, second := gallery.Entries[0], gallery.Entries[1]
+	if top.ID != bobID || top.Upvotes != 2 || !top.Upvoted || top.Mine {
+		t.Fatalf("unexpected top entry: %+v", top)
+	}
+	if second.ID != aliceID || second.Upvotes != 1 || second.Upvoted || !second.Mine || second.Code != "func canJump() bool { return true }" {
+		t.Fatalf("unexpected second entry: %+v", second)
+	}
+
+	// Deleting user data removes the entry, its upvote and the upvote cast
+	if deleted := deleteGalleryData(alice); deleted != 3 {
+		t.Fatalf("expected 3 deleted records, got %d", deleted)
+	}
+	if deleted := deleteGalleryData(bob); deleted != 2 {
+		t.Fatalf("expected 2 deleted records, got %d", deleted)
+	}
+	if deleted := deleteGalleryData(carol); deleted != 0 {
+		t.Fatalf("expected 0 deleted records, got %d", deleted)
+	}
+}
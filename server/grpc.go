@@ -0,0 +1,208 @@
+// gRPC implementation of the API, served alongside REST
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	pb "github.com/lancekrogers/algo-scales/internal/rpc/algoscalesv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultLeaderboardLimit is used when a request does not set a limit
+const defaultLeaderboardLimit = 10
+
+// LeaderboardEntry is a user's standing on the leaderboard
+type LeaderboardEntry struct {
+	DisplayName string `json:"display_name"`
+	Solved      int    `json:"solved"`
+}
+
+// Leaderboard standings, keyed by license
+var (
+	leaderboardMu sync.Mutex
+	leaderboardDB = make(map[string]LeaderboardEntry)
+)
+
+// publicMethods do not require a license
+var publicMethods = map[string]bool{
+	pb.AlgoScales_ValidateLicense_FullMethodName: true,
+	pb.AlgoScales_RegisterLicense_FullMethodName: true,
+}
+
+// grpcServer implements the AlgoScales gRPC service
+type grpcServer struct {
+	pb.UnimplementedAlgoScalesServer
+}
+
+// newGRPCServer creates a gRPC server with authentication
+func newGRPCServer() *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(licenseInterceptor))
+	pb.RegisterAlgoScalesServer(s, &grpcServer{})
+	return s
+}
+
+// licenseInterceptor is the gRPC counterpart of requireAuth, and of
+// requireAPIKey for registering licenses
+func licenseInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if info.FullMethod == pb.AlgoScales_RegisterLicense_FullMethodName && len(apiKeys) > 0 {
+		for _, value := range md.Get("x-api-key") {
+			if validAPIKey(value) {
+				return handler(ctx, req)
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	for _, value := range md.Get("authorization") {
+		if _, ok := authenticate(value); ok {
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "Invalid license")
+}
+
+// ListProblems returns all problems, optionally filtered
+func (s *grpcServer) ListProblems(ctx context.Context, req *pb.ListProblemsRequest) (*pb.ListProblemsResponse, error) {
+	resp := &pb.ListProblemsResponse{
+		Version:         problemsDB.Version,
+		LastUpdatedUnix: problemsDB.LastUpdated.Unix(),
+	}
+
+	for _, p := range problemsDB.Problems {
+		if req.GetDifficulty() != "" && !strings.EqualFold(p.Difficulty, req.GetDifficulty()) {
+			continue
+		}
+		if req.GetPattern() != "" && !containsString(p.Patterns, req.GetPattern()) {
+			continue
+		}
+		resp.Problems = append(resp.Problems, problemToProto(p))
+	}
+	return resp, nil
+}
+
+// GetProblem returns a single problem
+func (s *grpcServer) GetProblem(ctx context.Context, req *pb.GetProblemRequest) (*pb.Problem, error) {
+	for _, p := range problemsDB.Problems {
+		if p.ID == req.GetId() {
+			return problemToProto(p), nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "problem not found: %s", req.GetId())
+}
+
+// ValidateLicense reports whether a license key is valid
+func (s *grpcServer) ValidateLicense(ctx context.Context, req *pb.ValidateLicenseRequest) (*pb.ValidateLicenseResponse, error) {
+	return &pb.ValidateLicenseResponse{Valid: isValidLicense(req.GetLicenseKey())}, nil
+}
+
+// RegisterLicense issues a new license
+func (s *grpcServer) RegisterLicense(ctx context.Context, req *pb.RegisterLicenseRequest) (*pb.RegisterLicenseResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	license, err := issueLicense(ctx, req.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to issue license")
+	}
+	return &pb.RegisterLicenseResponse{
+		LicenseKey: license.LicenseKey,
+		Email:      license.Email,
+		ExpiryUnix: license.ExpiryDate.Unix(),
+	}, nil
+}
+
+// Sync returns the problem set when the client's version is out of date
+func (s *grpcServer) Sync(ctx context.Context, req *pb.SyncRequest) (*pb.SyncResponse, error) {
+	resp := &pb.SyncResponse{Version: problemsDB.Version}
+	if req.GetSinceVersion() == problemsDB.Version {
+		resp.UpToDate = true
+		return resp, nil
+	}
+
+	for _, p := range problemsDB.Problems {
+		resp.Problems = append(resp.Problems, problemToProto(p))
+	}
+	return resp, nil
+}
+
+// GetLeaderboard returns the top users by problems solved
+func (s *grpcServer) GetLeaderboard(ctx context.Context, req *pb.GetLeaderboardRequest) (*pb.GetLeaderboardResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+
+	leaderboardMu.Lock()
+	entries := make([]LeaderboardEntry, 0, len(leaderboardDB))
+	for _, e := range leaderboardDB {
+		entries = append(entries, e)
+	}
+	leaderboardMu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Solved != entries[j].Solved {
+			return entries[i].Solved > entries[j].Solved
+		}
+		return entries[i].DisplayName < entries[j].DisplayName
+	})
+
+	resp := &pb.GetLeaderboardResponse{}
+	for i, e := range entries {
+		if i >= limit {
+			break
+		}
+		resp.Entries = append(resp.Entries, &pb.LeaderboardEntry{
+			Rank:        int32(i + 1),
+			DisplayName: e.DisplayName,
+			Solved:      int32(e.Solved),
+		})
+	}
+	return resp, nil
+}
+
+// problemToProto converts a problem to its wire representation
+func problemToProto(p Problem) *pb.Problem {
+	out := &pb.Problem{
+		Id:                  p.ID,
+		Title:               p.Title,
+		Difficulty:          p.Difficulty,
+		Patterns:            p.Patterns,
+		EstimatedTime:       int32(p.EstimatedTime),
+		Companies:           p.Companies,
+		Description:         p.Description,
+		Constraints:         p.Constraints,
+		PatternExplanation:  p.PatternExplanation,
+		SolutionWalkthrough: p.SolutionWalkthrough,
+		StarterCode:         p.StarterCode,
+		Solutions:           p.Solutions,
+	}
+	for _, e := range p.Examples {
+		out.Examples = append(out.Examples, &pb.Example{Input: e.Input, Output: e.Output, Explanation: e.Explanation})
+	}
+	for _, tc := range p.TestCases {
+		out.TestCases = append(out.TestCases, &pb.TestCase{Input: tc.Input, Expected: tc.Expected})
+	}
+	return out
+}
+
+// containsString reports whether a slice contains a string
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
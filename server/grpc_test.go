@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/lancekrogers/algo-scales/internal/rpc/algoscalesv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient starts an in-memory gRPC server and returns a client for it
+func newTestGRPCClient(t *testing.T) pb.AlgoScalesClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := newGRPCServer()
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewAlgoScalesClient(conn)
+}
+
+func authorized(licenseKey string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+licenseKey)
+}
+
+func TestGRPCRequiresLicense(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	_, err := client.ListProblems(context.Background(), &pb.ListProblemsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	// License endpoints are public
+	resp, err := client.RegisterLicense(context.Background(), &pb.RegisterLicenseRequest{Email: "dev@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetLicenseKey() == "" {
+		t.Fatal("expected a license key")
+	}
+
+	valid, err := client.ValidateLicense(context.Background(), &pb.ValidateLicenseRequest{LicenseKey: resp.GetLicenseKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid.GetValid() {
+		t.Fatal("issued license should be valid")
+	}
+}
+
+func TestGRPCRegisterLicenseAPIKey(t *testing.T) {
+	client := newTestGRPCClient(t)
+	setAPIKeys([]string{"key-0123456789abcdef"})
+	defer func() { apiKeys = nil }()
+
+	_, err := client.RegisterLicense(context.Background(), &pb.RegisterLicenseRequest{Email: "dev@example.com"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without an API key, got %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "key-0123456789abcdef")
+	if _, err := client.RegisterLicense(ctx, &pb.RegisterLicenseRequest{Email: "dev@example.com"}); err != nil {
+		t.Fatalf("expected the API key to be accepted, got %v", err)
+	}
+}
+
+func TestGRPCProblems(t *testing.T) {
+	client := newTestGRPCClient(t)
+	ctx := authorized(testLicense)
+
+	all, err := client.ListProblems(ctx, &pb.ListProblemsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.GetProblems()) != len(problemsDB.Problems) {
+		t.Fatalf("expected %d problems, got %d", len(problemsDB.Problems), len(all.GetProblems()))
+	}
+
+	easy, err := client.ListProblems(ctx, &pb.ListProblemsRequest{Difficulty: "easy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range easy.GetProblems() {
+		if p.GetDifficulty() != "Easy" {
+			t.Fatalf("unexpected difficulty %q", p.GetDifficulty())
+		}
+	}
+
+	want := problemsDB.Problems[0]
+	got, err := client.GetProblem(ctx, &pb.GetProblemRequest{Id: want.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetTitle() != want.Title || len(got.GetTestCases()) != len(want.TestCases) {
+		t.Fatalf("problem %s did not round-trip", want.ID)
+	}
+
+	_, err = client.GetProblem(ctx, &pb.GetProblemRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPCSync(t *testing.T) {
+	client := newTestGRPCClient(t)
+	ctx := authorized(testLicense)
+
+	full, err := client.Sync(ctx, &pb.SyncRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.GetUpToDate() || len(full.GetProblems()) == 0 {
+		t.Fatal("a client without a version should receive the full set")
+	}
+
+	current, err := client.Sync(ctx, &pb.SyncRequest{SinceVersion: full.GetVersion()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !current.GetUpToDate() || len(current.GetProblems()) != 0 {
+		t.Fatal("a client on the current version should be up to date")
+	}
+}
+
+func TestGRPCLeaderboard(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	leaderboardMu.Lock()
+	leaderboardDB = map[string]LeaderboardEntry{
+		"LICENSE-a": {DisplayName: "ada", Solved: 12},
+		"LICENSE-b": {DisplayName: "bob", Solved: 30},
+		"LICENSE-c": {DisplayName: "cy", Solved: 5},
+	}
+	leaderboardMu.Unlock()
+
+	resp, err := client.GetLeaderboard(authorized(testLicense), &pb.GetLeaderboardRequest{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := resp.GetEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].GetDisplayName() != "bob" || entries[0].GetRank() != 1 || entries[1].GetDisplayName() != "ada" {
+		t.Fatalf("unexpected order: %v", entries)
+	}
+}
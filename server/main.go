@@ -3,13 +3,23 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lancekrogers/algo-scales/internal/license"
+	"google.golang.org/grpc"
 )
 
 // License represents a user license
@@ -18,7 +28,6 @@ type License struct {
 	Email        string    `json:"email"`
 	PurchaseDate time.Time `json:"purchase_date"`
 	ExpiryDate   time.Time `json:"expiry_date"` // For potential subscription model
-	Signature    string    `json:"signature"`
 }
 
 // Problem represents an algorithm problem
@@ -36,6 +45,7 @@ type Problem struct {
 	SolutionWalkthrough []string          `json:"solution_walkthrough"`
 	StarterCode         map[string]string `json:"starter_code"`
 	Solutions           map[string]string `json:"solutions"`
+	Signature           *Signature        `json:"signature,omitempty"`
 	TestCases           []TestCase        `json:"test_cases"`
 }
 
@@ -50,6 +60,21 @@ type Example struct {
 type TestCase struct {
 	Input    string `json:"input"`
 	Expected string `json:"expected"`
+	Tier     string `json:"tier,omitempty"` // example, hidden, edge or stress; example when empty
+}
+
+// Signature describes the function test harnesses call for a problem
+type Signature struct {
+	Name      string  `json:"name"`
+	Params    []Param `json:"params"`
+	Returns   string  `json:"returns"`
+	Unordered bool    `json:"unordered,omitempty"`
+}
+
+// Param is a named, typed parameter of a Signature
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // ProblemSet represents a set of problems
@@ -59,46 +84,217 @@ type ProblemSet struct {
 	Problems    []Problem `json:"problems"`
 }
 
-// Database would normally be a real database, but for demo we'll use in-memory
+// UserData holds everything a client has synced
+type UserData struct {
+	Records []ProgressRecord `json:"records"`
+}
+
+// Licenses, problem sets, progress, profiles, refresh tokens and telemetry
+// are kept in the store, which is in memory until main opens the database,
+// so replicas sharing a database serve the same data. Peer reviews,
+// galleries, the gRPC leaderboard and device logins are still kept in each
+// process.
 var (
-	problemsDB = getSampleProblems()
-	licensesDB = make(map[string]License)
+	store      Store = newMemoryStore()
+	problemsDB       = getSampleProblems()
+
+	// signingKey is replaced by the configured key in main
+	signingKey = temporarySigningKey()
 )
 
 func main() {
-	r := gin.Default()
+	cfg, err := loadConfig(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	signingKey = cfg.signingKey()
+	requestLog = cfg.logger()
+	setAPIKeys(cfg.APIKeys)
+	if len(cfg.APIKeys) == 0 {
+		log.Println("API_KEYS is not set, anyone can register licenses")
+	}
+	if cfg.LicenseRateLimit > 0 {
+		licenseLimiter = newRateLimiter(cfg.LicenseRateLimit, cfg.LicenseRateBurst)
+	}
+
+	s, err := openStore(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer s.Close()
+	store = s
+
+	if problemsDB, err = loadProblemSet(context.Background(), store); err != nil {
+		log.Fatalf("Failed to load problems: %v", err)
+	}
+
+	r := setupRouter(cfg.middleware()...)
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
+	// The gRPC API runs alongside REST on its own port
+	grpcServer := newGRPCServer()
+	go func() {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on port %s: %v", cfg.GRPCPort, err)
+		}
+		log.Printf("Starting gRPC server on port %s...\n", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Printf("Starting server on port %s...\n", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server stopped: %v", err)
+		}
+	}()
+
+	// Orchestrators send SIGTERM and wait before killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	shutdown(srv, grpcServer, cfg.ShutdownTimeout)
+}
+
+// shutdown stops taking new requests and waits up to timeout for the ones
+// in flight
+func shutdown(srv *http.Server, grpcServer *grpc.Server, timeout time.Duration) {
+	log.Println("Shutting down...")
+	draining.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Failed to finish requests: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// setupRouter registers middleware and routes, running the given
+// middleware after logging and recovery
+func setupRouter(middleware ...gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
 
 	// Middleware
-	r.Use(gin.Logger())
+	r.Use(logRequests())
 	r.Use(gin.Recovery())
+	r.Use(middleware...)
+
+	// Probes for load balancers and orchestrators
+	r.GET("/healthz", healthz)
+	r.GET("/readyz", readyz)
 
 	// Routes
-	r.GET("/v1/problems", getProblems)
 	r.POST("/v1/validate-license", validateLicense)
-	r.POST("/v1/register-license", registerLicense)
+	r.POST("/v1/register-license", requireAPIKey(), registerLicense)
+	r.GET("/v1/bundles/:name", getBundleByHash)
+	r.GET("/v1/snapshots/:id", getSnapshot)
+
+	// Device-code login, approved in a browser at /device
+	r.POST("/v1/auth/device", startDeviceAuthorization)
+	r.POST("/v1/auth/token", getToken)
+	r.POST("/v1/auth/revoke", revokeToken)
+	r.GET("/device", showDevicePage)
+	r.POST("/device", approveDevice)
+
+	// Routes that act on a user's own data
+	authorized := r.Group("/v1", requireAuth(), limitLicense())
+	authorized.GET("/problems", getProblems)
+	authorized.DELETE("/user-data", deleteUserData)
+	authorized.GET("/progress", getProgress)
+	authorized.POST("/progress", pushProgress)
+	authorized.POST("/telemetry/attempts", recordAttempt)
+	authorized.GET("/problems/:id/stats", getProblemStats)
+	authorized.GET("/problems/manifest", getBundleManifest)
+	authorized.GET("/problems/updates", getProblemUpdates)
+	authorized.GET("/problems/:id", getProblem)
+	authorized.POST("/reviews/submissions", submitForReview)
+	authorized.GET("/reviews/next", getNextReview)
+	authorized.POST("/reviews/submissions/:id/reviews", postReview)
+	authorized.GET("/reviews/inbox", getReviewInbox)
+	authorized.GET("/problems/:id/gallery", getGallery)
+	authorized.POST("/gallery/entries", publishToGallery)
+	authorized.POST("/gallery/entries/:id/upvote", upvoteGalleryEntry)
+	authorized.POST("/snapshots", uploadSnapshot)
+	authorized.GET("/profile", getProfile)
+	authorized.PUT("/profile", putProfile)
+	authorized.GET("/users/:id/progress", getUserProgress)
+	authorized.GET("/leaderboard", getLeaderboard)
+
+	return r
+}
+
+// deleteUserData removes all synced data belonging to the caller
+func deleteUserData(c *gin.Context) {
+	licenseKey := c.GetString("license_key")
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	deleted, err := store.DeleteProgress(c.Request.Context(), licenseKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data"})
+		return
 	}
+	if removed, err := store.DeleteProfile(c.Request.Context(), licenseKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data"})
+		return
+	} else if removed {
+		deleted++
+	}
+	deleted += deleteReviewData(licenseKey)
+	deleted += deleteGalleryData(licenseKey)
+	deleted += deleteSnapshotData(licenseKey)
 
-	log.Printf("Starting server on port %s...\n", port)
-	r.Run(":" + port)
+	c.JSON(http.StatusOK, gin.H{
+		"deleted": deleted,
+	})
 }
 
 // getProblems returns all problems
 func getProblems(c *gin.Context) {
-	// Verify license in request
-	licenseKey := c.Query("license")
-	if !isValidLicense(licenseKey) {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid license",
-		})
+	b, err := getBundle()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build bundle"})
 		return
 	}
 
-	c.JSON(http.StatusOK, problemsDB)
+	// Served to licensed callers only, so shared caches must not store it
+	c.Header("Cache-Control", "private, no-cache")
+	writeEncoded(c, b, "application/json")
+}
+
+// getProblem returns a single problem, for clients filling their local cache
+func getProblem(c *gin.Context) {
+	for _, p := range problemsDB.Problems {
+		if p.ID == c.Param("id") {
+			c.JSON(http.StatusOK, p)
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"error": "Problem not found",
+	})
 }
 
 // validateLicense validates a license
@@ -139,23 +335,16 @@ func registerLicense(c *gin.Context) {
 		return
 	}
 
-	// Generate license key
-	licenseKey := generateLicenseKey(req.Email)
-
-	// Create license
-	license := License{
-		LicenseKey:   licenseKey,
-		Email:        req.Email,
-		PurchaseDate: time.Now(),
-		ExpiryDate:   time.Now().AddDate(1, 0, 0), // Valid for 1 year
-		Signature:    generateSignature(licenseKey, req.Email),
+	license, err := issueLicense(c.Request.Context(), req.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue license",
+		})
+		return
 	}
 
-	// Save license
-	licensesDB[licenseKey] = license
-
 	c.JSON(http.StatusOK, gin.H{
-		"license_key": licenseKey,
+		"license_key": license.LicenseKey,
 		"email":       req.Email,
 		"expiry_date": license.ExpiryDate,
 	})
@@ -163,29 +352,49 @@ func registerLicense(c *gin.Context) {
 
 // Helper functions
 
-// isValidLicense checks if a license is valid
+// issueLicense creates and stores a new license for an email
+func issueLicense(ctx context.Context, email string) (License, error) {
+	now := time.Now()
+	lic := License{
+		Email:        email,
+		PurchaseDate: now,
+		ExpiryDate:   now.AddDate(1, 0, 0), // Valid for 1 year
+	}
+	lic.LicenseKey = signLicense(license.Claims{
+		ID:        generateLicenseID(),
+		Email:     email,
+		IssuedAt:  lic.PurchaseDate,
+		ExpiresAt: lic.ExpiryDate,
+	})
+
+	if err := store.SaveLicense(ctx, lic); err != nil {
+		return License{}, err
+	}
+	return lic, nil
+}
+
+// isValidLicense checks that a license key was signed by this server and
+// has not expired
 func isValidLicense(licenseKey string) bool {
-	// In a real implementation, this would check a database
-	// For demo, we'll validate any non-empty license key
-	return licenseKey != ""
+	claims, err := license.Parse(licenseKey, signingKey.Public().(ed25519.PublicKey))
+	return err == nil && !claims.Expired(time.Now())
 }
 
-// generateLicenseKey generates a license key from an email
-func generateLicenseKey(email string) string {
-	// In a real implementation, this would generate a cryptographically secure key
-	// For demo, we'll just use a simple hash. Handle short emails gracefully.
-	prefix := email
-	if len(prefix) > 4 {
-		prefix = prefix[:4]
+// signLicense signs claims with the server's key
+func signLicense(claims license.Claims) string {
+	key, err := license.Sign(claims, signingKey)
+	if err != nil {
+		// Claims are plain values, so encoding cannot fail
+		panic(err)
 	}
-	return fmt.Sprintf("LICENSE-%s-%d", prefix, time.Now().Unix())
+	return key
 }
 
-// generateSignature creates a signature for a license
-func generateSignature(licenseKey, email string) string {
-	// In a real implementation, this would use private key cryptography
-	// For demo, we'll just return a dummy signature
-	return "valid-signature-" + licenseKey[:4]
+// generateLicenseID returns a random identifier for a new license
+func generateLicenseID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
 }
 
 // getSampleProblems returns a set of sample problems
@@ -230,11 +439,25 @@ func getSampleProblems() ProblemSet {
 					"go":         "func twoSum(nums []int, target int) []int {\n    // Your code here\n}",
 					"python":     "def two_sum(nums, target):\n    # Your code here\n    pass",
 					"javascript": "function twoSum(nums, target) {\n    // Your code here\n}",
+					"rust":       "pub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    // Your code here\n    todo!()\n}",
+					"java":       "class Solution {\n    public int[] twoSum(int[] nums, int target) {\n        // Your code here\n        return new int[0];\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        // Your code here\n        return {};\n    }\n};",
+					"typescript": "function twoSum(nums: number[], target: number): number[] {\n    // Your code here\n    return [];\n}",
 				},
 				Solutions: map[string]string{
 					"go":         "func twoSum(nums []int, target int) []int {\n    seen := make(map[int]int)\n    for i, num := range nums {\n        complement := target - num\n        if j, ok := seen[complement]; ok {\n            return []int{j, i}\n        }\n        seen[num] = i\n    }\n    return []int{}\n}",
 					"python":     "def two_sum(nums, target):\n    seen = {}\n    for i, num in enumerate(nums):\n        complement = target - num\n        if complement in seen:\n            return [seen[complement], i]\n        seen[num] = i\n    return []",
 					"javascript": "function twoSum(nums, target) {\n    const seen = {};\n    for (let i = 0; i < nums.length; i++) {\n        const complement = target - nums[i];\n        if (complement in seen) {\n            return [seen[complement], i];\n        }\n        seen[nums[i]] = i;\n    }\n    return [];\n}",
+					"rust":       "use std::collections::HashMap;\n\npub fn two_sum(nums: Vec<i32>, target: i32) -> Vec<i32> {\n    let mut seen = HashMap::new();\n    for (i, &num) in nums.iter().enumerate() {\n        if let Some(&j) = seen.get(&(target - num)) {\n            return vec![j as i32, i as i32];\n        }\n        seen.insert(num, i);\n    }\n    vec![]\n}",
+					"java":       "import java.util.HashMap;\nimport java.util.Map;\n\nclass Solution {\n    public int[] twoSum(int[] nums, int target) {\n        Map<Integer, Integer> seen = new HashMap<>();\n        for (int i = 0; i < nums.length; i++) {\n            Integer j = seen.get(target - nums[i]);\n            if (j != null) {\n                return new int[]{j, i};\n            }\n            seen.put(nums[i], i);\n        }\n        return new int[0];\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    vector<int> twoSum(vector<int>& nums, int target) {\n        unordered_map<int, int> seen;\n        for (int i = 0; i < (int)nums.size(); i++) {\n            auto it = seen.find(target - nums[i]);\n            if (it != seen.end()) {\n                return {it->second, i};\n            }\n            seen[nums[i]] = i;\n        }\n        return {};\n    }\n};",
+					"typescript": "function twoSum(nums: number[], target: number): number[] {\n    const seen = new Map<number, number>();\n    for (let i = 0; i < nums.length; i++) {\n        const j = seen.get(target - nums[i]);\n        if (j !== undefined) {\n            return [j, i];\n        }\n        seen.set(nums[i], i);\n    }\n    return [];\n}",
+				},
+				Signature: &Signature{
+					Name:      "twoSum",
+					Params:    []Param{{Name: "nums", Type: "int[]"}, {Name: "target", Type: "int"}},
+					Returns:   "int[]",
+					Unordered: true,
 				},
 				TestCases: []TestCase{
 					{
@@ -282,11 +505,24 @@ func getSampleProblems() ProblemSet {
 					"go":         "func maxSubArray(nums []int) int {\n    // Your code here\n}",
 					"python":     "def max_subarray(nums):\n    # Your code here\n    pass",
 					"javascript": "function maxSubArray(nums) {\n    // Your code here\n}",
+					"rust":       "pub fn max_sub_array(nums: Vec<i32>) -> i32 {\n    // Your code here\n    todo!()\n}",
+					"java":       "class Solution {\n    public int maxSubArray(int[] nums) {\n        // Your code here\n        return 0;\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    int maxSubArray(vector<int>& nums) {\n        // Your code here\n        return 0;\n    }\n};",
+					"typescript": "function maxSubArray(nums: number[]): number {\n    // Your code here\n    return 0;\n}",
 				},
 				Solutions: map[string]string{
 					"go":         "func maxSubArray(nums []int) int {\n    if len(nums) == 0 {\n        return 0\n    }\n    \n    currentSum := nums[0]\n    maxSum := nums[0]\n    \n    for i := 1; i < len(nums); i++ {\n        currentSum = max(nums[i], currentSum + nums[i])\n        maxSum = max(maxSum, currentSum)\n    }\n    \n    return maxSum\n}\n\nfunc max(a, b int) int {\n    if a > b {\n        return a\n    }\n    return b\n}",
 					"python":     "def max_subarray(nums):\n    if not nums:\n        return 0\n        \n    current_sum = max_sum = nums[0]\n    \n    for num in nums[1:]:\n        current_sum = max(num, current_sum + num)\n        max_sum = max(max_sum, current_sum)\n        \n    return max_sum",
 					"javascript": "function maxSubArray(nums) {\n    if (nums.length === 0) {\n        return 0;\n    }\n    \n    let currentSum = nums[0];\n    let maxSum = nums[0];\n    \n    for (let i = 1; i < nums.length; i++) {\n        currentSum = Math.max(nums[i], currentSum + nums[i]);\n        maxSum = Math.max(maxSum, currentSum);\n    }\n    \n    return maxSum;\n}",
+					"rust":       "pub fn max_sub_array(nums: Vec<i32>) -> i32 {\n    let mut current_sum = nums[0];\n    let mut max_sum = nums[0];\n    for &num in &nums[1..] {\n        current_sum = num.max(current_sum + num);\n        max_sum = max_sum.max(current_sum);\n    }\n    max_sum\n}",
+					"java":       "class Solution {\n    public int maxSubArray(int[] nums) {\n        int currentSum = nums[0];\n        int maxSum = nums[0];\n        for (int i = 1; i < nums.length; i++) {\n            currentSum = Math.max(nums[i], currentSum + nums[i]);\n            maxSum = Math.max(maxSum, currentSum);\n        }\n        return maxSum;\n    }\n}",
+					"cpp":        "class Solution {\npublic:\n    int maxSubArray(vector<int>& nums) {\n        int currentSum = nums[0];\n        int maxSum = nums[0];\n        for (size_t i = 1; i < nums.size(); i++) {\n            currentSum = max(nums[i], currentSum + nums[i]);\n            maxSum = max(maxSum, currentSum);\n        }\n        return maxSum;\n    }\n};",
+					"typescript": "function maxSubArray(nums: number[]): number {\n    let currentSum = nums[0];\n    let maxSum = nums[0];\n    for (let i = 1; i < nums.length; i++) {\n        currentSum = Math.max(nums[i], currentSum + nums[i]);\n        maxSum = Math.max(maxSum, currentSum);\n    }\n    return maxSum;\n}",
+				},
+				Signature: &Signature{
+					Name:    "maxSubArray",
+					Params:  []Param{{Name: "nums", Type: "int[]"}},
+					Returns: "int",
 				},
 				TestCases: []TestCase{
 					{
@@ -1,10 +1,148 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
 
-func TestGenerateLicenseKey_ShortEmail(t *testing.T) {
-	key := generateLicenseKey("a@b")
-	if key == "" {
-		t.Fatal("expected non-empty license key")
+	"github.com/gin-gonic/gin"
+	"github.com/lancekrogers/algo-scales/internal/license"
+)
+
+// Licenses signed by the test server for authorized requests
+var (
+	testLicense  = testLicenseFor("test")
+	otherLicense = testLicenseFor("other")
+)
+
+// testLicenseFor issues a valid license for a test user
+func testLicenseFor(name string) string {
+	lic, err := issueLicense(context.Background(), name+"@example.com")
+	if err != nil {
+		panic(err)
+	}
+	return lic.LicenseKey
+}
+
+func TestIssueLicense_ShortEmail(t *testing.T) {
+	lic, err := issueLicense(context.Background(), "a@b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isValidLicense(lic.LicenseKey) {
+		t.Fatalf("expected issued license to be valid, got %q", lic.LicenseKey)
+	}
+
+	stored, ok, err := store.GetLicense(context.Background(), lic.LicenseKey)
+	if err != nil || !ok {
+		t.Fatalf("expected issued license to be stored, got %v, %v", ok, err)
+	}
+	if stored.Email != "a@b" {
+		t.Fatalf("expected stored email a@b, got %q", stored.Email)
+	}
+}
+
+func TestIsValidLicense(t *testing.T) {
+	if isValidLicense("") || isValidLicense("LICENSE-test") {
+		t.Fatal("unsigned license keys should be rejected")
+	}
+
+	// Keys signed by another server are rejected
+	_, other, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := license.Sign(license.Claims{ID: "forged", Email: "a@b"}, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isValidLicense(forged) {
+		t.Fatal("license signed by another key should be rejected")
+	}
+
+	expired := signLicense(license.Claims{ID: "old", Email: "a@b", ExpiresAt: time.Now().Add(-time.Hour)})
+	if isValidLicense(expired) {
+		t.Fatal("expired license should be rejected")
+	}
+}
+
+func TestDeleteUserData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	ctx := context.Background()
+	store.DeleteProgress(ctx, testLicense)
+	store.DeleteProgress(ctx, otherLicense)
+	now := time.Now()
+	if _, err := store.MergeProgress(ctx, testLicense, []ProgressRecord{{Kind: "session", Key: "a", UpdatedAt: now}, {Kind: "session", Key: "b", UpdatedAt: now}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.MergeProgress(ctx, otherLicense, []ProgressRecord{{Kind: "session", Key: "a", UpdatedAt: now}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unauthenticated requests are rejected
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/user-data", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/user-data", nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Deleted != 2 {
+		t.Fatalf("expected 2 deleted records, got %d", body.Deleted)
+	}
+	if snapshot, _ := store.LoadProgress(ctx, testLicense); len(snapshot.Records) != 0 {
+		t.Fatal("caller data should be removed")
+	}
+	if snapshot, _ := store.LoadProgress(ctx, otherLicense); len(snapshot.Records) != 1 {
+		t.Fatal("other users' data must be untouched")
+	}
+}
+
+func TestGetProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	want := problemsDB.Problems[0]
+	req := httptest.NewRequest(http.MethodGet, "/v1/problems/"+want.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var got Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != want.ID {
+		t.Fatalf("expected %s, got %s", want.ID, got.ID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/problems/missing", nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
 	}
 }
@@ -0,0 +1,299 @@
+// CORS, rate limiting, API keys, request logging and health checks
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// readinessTimeout bounds the database check behind /readyz
+	readinessTimeout = 2 * time.Second
+
+	// minAPIKeyLength keeps API keys long enough not to be guessed
+	minAPIKeyLength = 16
+
+	// maxRequestIDLength bounds the X-Request-ID taken from clients
+	maxRequestIDLength = 64
+)
+
+// draining is set once the server starts shutting down, so load balancers
+// stop sending it requests while in-flight ones finish
+var draining atomic.Bool
+
+// These are replaced from the configuration in main
+var (
+	// requestLog receives a line for every request
+	requestLog = slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// licenseLimiter limits each license's requests to the routes that need
+	// one; nil leaves licenses unlimited
+	licenseLimiter *rateLimiter
+
+	// apiKeys holds the SHA-256 of each API key; when empty, registering
+	// licenses is open to anyone
+	apiKeys map[[sha256.Size]byte]bool
+)
+
+// middleware returns the handlers the configuration adds to every request
+func (cfg Config) middleware() []gin.HandlerFunc {
+	var handlers []gin.HandlerFunc
+	if len(cfg.CORSOrigins) > 0 {
+		handlers = append(handlers, cors(cfg.CORSOrigins))
+	}
+	if cfg.RateLimit > 0 {
+		handlers = append(handlers, newRateLimiter(cfg.RateLimit, cfg.RateBurst).middleware())
+	}
+	return handlers
+}
+
+// cors lets browsers on the allowed origins call the API and answers their
+// preflight requests
+func cors(origins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		c.Writer.Header().Add("Vary", "Origin")
+		if origin == "" || (!allowed["*"] && !allowed[origin]) {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-None-Match, X-API-Key, X-Request-ID")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		h.Set("Access-Control-Expose-Headers", "ETag, Retry-After, X-Request-ID")
+		h.Set("Access-Control-Max-Age", "600")
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimiter is a token bucket per client, an IP or a license. Buckets are
+// kept in memory, so each replica limits the clients it serves.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // Tokens added per second
+	burst     float64 // Bucket size
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket holds a client's tokens as of last
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter allows rate requests per second from each client, in
+// bursts of up to burst
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from a client's bucket, reporting whether there was
+// one and, if not, how long until there is
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep forgets clients whose buckets have refilled, once a minute
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// middleware rejects requests from client IPs over the limit. Health
+// checks are never limited.
+func (l *rateLimiter) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path := c.Request.URL.Path; path == "/healthz" || path == "/readyz" {
+			c.Next()
+			return
+		}
+		l.limit(c, c.ClientIP(), "Too many requests")
+	}
+}
+
+// limitLicense rejects requests from licenses over licenseLimiter's limit,
+// however many machines and IPs they come from. It runs after requireAuth.
+func limitLicense() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if licenseLimiter == nil {
+			c.Next()
+			return
+		}
+		licenseLimiter.limit(c, c.GetString("license_key"), "Too many requests for this license")
+	}
+}
+
+// limit answers 429 with a Retry-After header when client is over the
+// limit, and otherwise passes the request on
+func (l *rateLimiter) limit(c *gin.Context, client, message string) {
+	if ok, wait := l.allow(client); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error": message,
+		})
+		return
+	}
+	c.Next()
+}
+
+// setAPIKeys replaces the API keys accepted by requireAPIKey
+func setAPIKeys(keys []string) {
+	apiKeys = make(map[[sha256.Size]byte]bool, len(keys))
+	for _, key := range keys {
+		apiKeys[sha256.Sum256([]byte(key))] = true
+	}
+}
+
+// validAPIKey reports whether key is one of the API keys. Keys are looked
+// up by hash, so the time taken does not tell how much of a key matched.
+func validAPIKey(key string) bool {
+	return key != "" && apiKeys[sha256.Sum256([]byte(key))]
+}
+
+// requireAPIKey rejects requests without a valid X-API-Key header, unless
+// no API keys are configured
+func requireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(apiKeys) > 0 && !validAPIKey(c.GetHeader("X-API-Key")) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid API key",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// logRequests writes a structured line to requestLog for every request,
+// tagged with the caller's X-Request-ID or a new one, which is echoed in
+// the response. Query strings are left out, so credentials passed in them
+// never reach the logs. Health checks are logged at debug level, so they
+// do not drown out the rest.
+func logRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Header("X-Request-ID", requestID)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("ip", c.ClientIP()),
+		}
+		if licenseKey := c.GetString("license_key"); licenseKey != "" {
+			attrs = append(attrs, slog.String("user", userIDFor(licenseKey)))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch path := c.Request.URL.Path; {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case path == "/healthz" || path == "/readyz":
+			level = slog.LevelDebug
+		}
+		requestLog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// newRequestID returns a random identifier for a request
+func newRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// healthz reports that the process is up
+func healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readyz reports whether the server can take requests: it is not shutting
+// down and its database answers
+func readyz(c *gin.Context) {
+	if draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	if err := store.Ping(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "database unavailable"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	// The burst is allowed, then one request per half second
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow("1.2.3.4"); !ok {
+			t.Fatalf("request %d of the burst was limited", i+1)
+		}
+	}
+	ok, wait := l.allow("1.2.3.4")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected to wait 500ms, got %v and %v", ok, wait)
+	}
+	if ok, _ := l.allow("5.6.7.8"); !ok {
+		t.Fatal("other clients must not be limited")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.allow("1.2.3.4"); !ok {
+		t.Fatal("expected a token after waiting")
+	}
+
+	// Idle clients are forgotten
+	now = now.Add(2 * time.Minute)
+	l.allow("9.9.9.9")
+	if len(l.buckets) != 1 {
+		t.Fatalf("expected idle buckets to be swept, got %d", len(l.buckets))
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter(newRateLimiter(1, 1).middleware())
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	if w := get("/v1/bundles/missing"); w.Code == http.StatusTooManyRequests {
+		t.Fatal("the first request must not be limited")
+	}
+	w := get("/v1/bundles/missing")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected 429 with Retry-After, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := get("/healthz"); w.Code != http.StatusOK {
+		t.Fatalf("health checks must not be limited, got %d", w.Code)
+	}
+}
+
+func TestLicenseRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	licenseLimiter = newRateLimiter(1, 1)
+	defer func() { licenseLimiter = nil }()
+	r := setupRouter()
+
+	get := func(licenseKey, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/progress", nil)
+		req.Header.Set("Authorization", "Bearer "+licenseKey)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := get(testLicense, "1.2.3.4"); w.Code != http.StatusOK {
+		t.Fatalf("the first request must not be limited, got %d", w.Code)
+	}
+	// A license is limited however many IPs it uses
+	w := get(testLicense, "5.6.7.8")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected 429 with Retry-After, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := get(otherLicense, "5.6.7.8"); w.Code != http.StatusOK {
+		t.Fatalf("other licenses must not be limited, got %d", w.Code)
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	register := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/register-license", strings.NewReader(`{"email":"dev@example.com"}`))
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := register(""); code != http.StatusOK {
+		t.Fatalf("expected registration to be open without API keys, got %d", code)
+	}
+
+	setAPIKeys([]string{"key-0123456789abcdef"})
+	defer func() { apiKeys = nil }()
+	if code := register(""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without an API key, got %d", code)
+	}
+	if code := register("key-0123456789abcdeg"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong API key, got %d", code)
+	}
+	if code := register("key-0123456789abcdef"); code != http.StatusOK {
+		t.Fatalf("expected the API key to be accepted, got %d", code)
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	orig := requestLog
+	requestLog = slog.New(slog.NewJSONHandler(&buf, nil))
+	defer func() { requestLog = orig }()
+	r := setupRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/problems/two-sum?token=secret", nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	req.Header.Set("X-Request-ID", "req-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Header().Get("X-Request-ID") != "req-1" {
+		t.Fatalf("expected the request ID to be echoed, got %q", w.Header().Get("X-Request-ID"))
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON line, got %q: %v", buf.String(), err)
+	}
+	for key, want := range map[string]interface{}{
+		"msg":        "request",
+		"level":      "INFO",
+		"request_id": "req-1",
+		"method":     "GET",
+		"path":       "/v1/problems/two-sum",
+		"route":      "/v1/problems/:id",
+		"status":     float64(http.StatusOK),
+		"user":       userIDFor(testLicense),
+	} {
+		if line[key] != want {
+			t.Errorf("expected %s %v, got %v", key, want, line[key])
+		}
+	}
+	if strings.Contains(buf.String(), "secret") || strings.Contains(buf.String(), testLicense) {
+		t.Fatalf("credentials must not be logged: %s", buf.String())
+	}
+
+	// Failures are warnings, and requests without an ID get one
+	buf.Reset()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/progress", nil))
+	if id := w.Header().Get("X-Request-ID"); len(id) != 16 {
+		t.Fatalf("expected a new request ID, got %q", id)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil || line["level"] != "WARN" || line["status"] != float64(http.StatusUnauthorized) {
+		t.Fatalf("expected a warning for the unauthorized request, got %s", buf.String())
+	}
+
+	// Health checks are only logged at debug level
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if buf.Len() != 0 {
+		t.Fatalf("expected health checks not to be logged, got %s", buf.String())
+	}
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter(cors([]string{"https://app.example"}))
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/v1/progress", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := preflight("https://app.example")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Fatalf("expected an allowed preflight, got %d %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if w := preflight("https://evil.example"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("other origins must not be allowed")
+	}
+}
+
+// failingStore is a store whose database is down
+type failingStore struct {
+	*memoryStore
+}
+
+func (failingStore) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestProbes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("expected a healthy server, got %d", code)
+	}
+	if code := probe("/readyz"); code != http.StatusOK {
+		t.Fatalf("expected a ready server, got %d", code)
+	}
+
+	orig := store
+	defer func() { store = orig }()
+	store = failingStore{newMemoryStore()}
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready without a database, got %d", code)
+	}
+	store = orig
+
+	draining.Store(true)
+	defer draining.Store(false)
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready while draining, got %d", code)
+	}
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("expected a healthy server while draining, got %d", code)
+	}
+}
@@ -0,0 +1,109 @@
+// Progress sync between a user's machines, resolved last write wins
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProgressRecord is one piece of a user's progress, such as a session or a
+// streak. Records are identified by kind and key; of two versions of a
+// record, the one updated last wins.
+type ProgressRecord struct {
+	Kind      string          `json:"kind"`
+	Key       string          `json:"key"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ProgressSnapshot is everything a user has synced
+type ProgressSnapshot struct {
+	Records   []ProgressRecord `json:"records"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// getProgress returns the caller's synced progress
+func getProgress(c *gin.Context) {
+	snapshot, err := store.LoadProgress(c.Request.Context(), c.GetString("license_key"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load progress"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// pushProgress merges the records a client sends into the caller's
+// progress and returns the merged progress, so one round trip both pushes
+// and pulls
+func pushProgress(c *gin.Context) {
+	var body struct {
+		Records []ProgressRecord `json:"records"`
+	}
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	for _, r := range body.Records {
+		if r.Kind == "" || r.Key == "" || r.UpdatedAt.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Records need a kind, key and update time"})
+			return
+		}
+	}
+
+	snapshot, err := store.MergeProgress(c.Request.Context(), c.GetString("license_key"), body.Records)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save progress"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// mergeProgress adds incoming records to a user's data, replacing stored
+// versions that are older. It returns the number of records that changed.
+func mergeProgress(data *UserData, incoming []ProgressRecord) int {
+	index := make(map[[2]string]int, len(data.Records))
+	for i, r := range data.Records {
+		index[[2]string{r.Kind, r.Key}] = i
+	}
+
+	changed := 0
+	for _, r := range incoming {
+		id := [2]string{r.Kind, r.Key}
+		i, ok := index[id]
+		switch {
+		case !ok:
+			index[id] = len(data.Records)
+			data.Records = append(data.Records, r)
+		case r.UpdatedAt.After(data.Records[i].UpdatedAt):
+			data.Records[i] = r
+		default:
+			continue
+		}
+		changed++
+	}
+	return changed
+}
+
+// progressSnapshot copies records ordered by kind and key. The snapshot is
+// as recent as its newest record.
+func progressSnapshot(records []ProgressRecord) ProgressSnapshot {
+	snapshot := ProgressSnapshot{Records: append([]ProgressRecord{}, records...)}
+	for _, r := range records {
+		if r.UpdatedAt.After(snapshot.UpdatedAt) {
+			snapshot.UpdatedAt = r.UpdatedAt
+		}
+	}
+	sort.Slice(snapshot.Records, func(i, j int) bool {
+		a, b := snapshot.Records[i], snapshot.Records[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Key < b.Key
+	})
+	return snapshot
+}
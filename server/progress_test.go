@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMergeProgress(t *testing.T) {
+	older := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	data := &UserData{Records: []ProgressRecord{
+		{Kind: "streak", Key: "daily", UpdatedAt: newer, Data: json.RawMessage(`{"Current":3}`)},
+		{Kind: "daily", Key: "2026-05-01", UpdatedAt: older, Data: json.RawMessage(`{"completed":false}`)},
+	}}
+
+	changed := mergeProgress(data, []ProgressRecord{
+		{Kind: "streak", Key: "daily", UpdatedAt: older, Data: json.RawMessage(`{"Current":1}`)},          // Older: ignored
+		{Kind: "daily", Key: "2026-05-01", UpdatedAt: newer, Data: json.RawMessage(`{"completed":true}`)}, // Newer: wins
+		{Kind: "session", Key: "two-sum@2026-05-01T09:00:00Z", UpdatedAt: older, Data: json.RawMessage(`{}`)},
+	})
+	if changed != 2 || len(data.Records) != 3 {
+		t.Fatalf("expected 2 changes and 3 records, got %d and %d", changed, len(data.Records))
+	}
+	if string(data.Records[0].Data) != `{"Current":3}` {
+		t.Fatalf("an older record must not replace a newer one, got %s", data.Records[0].Data)
+	}
+	if string(data.Records[1].Data) != `{"completed":true}` {
+		t.Fatalf("a newer record must replace an older one, got %s", data.Records[1].Data)
+	}
+}
+
+func TestProgressSync(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+	store.DeleteProgress(context.Background(), testLicense)
+
+	do := func(method, body string) (int, ProgressSnapshot) {
+		t.Helper()
+		req := httptest.NewRequest(method, "/v1/progress", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+testLicense)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var snapshot ProgressSnapshot
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &snapshot); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code, snapshot
+	}
+
+	if code, snapshot := do(http.MethodGet, ""); code != http.StatusOK || len(snapshot.Records) != 0 {
+		t.Fatalf("expected no progress yet, got %d with %d records", code, len(snapshot.Records))
+	}
+
+	code, snapshot := do(http.MethodPost, `{"records":[{"kind":"streak","key":"daily","updated_at":"2026-05-01T09:00:00Z","data":{"Current":2}}]}`)
+	if code != http.StatusOK || len(snapshot.Records) != 1 || snapshot.UpdatedAt.IsZero() {
+		t.Fatalf("expected the pushed record back, got %d with %+v", code, snapshot)
+	}
+
+	// Another machine pulls it
+	if _, snapshot := do(http.MethodGet, ""); len(snapshot.Records) != 1 || string(snapshot.Records[0].Data) != `{"Current":2}` {
+		t.Fatalf("expected the synced streak, got %+v", snapshot.Records)
+	}
+
+	if code, _ := do(http.MethodPost, `{"records":[{"kind":"streak","data":{}}]}`); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a record without a key, got %d", code)
+	}
+}
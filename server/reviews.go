@@ -0,0 +1,226 @@
+// Opt-in anonymous peer review exchange
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReviewCodeBytes bounds the size of a submitted solution
+const maxReviewCodeBytes = 64 * 1024
+
+// ReviewSubmission is a solution queued for review. The author is kept
+// server-side only so reviewers never learn who wrote it.
+type ReviewSubmission struct {
+	ID          string    `json:"id"`
+	ProblemID   string    `json:"problem_id"`
+	Language    string    `json:"language"`
+	Code        string    `json:"code"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	Author      string    `json:"-"`
+}
+
+// Review is feedback on a submission. The reviewer is kept server-side only.
+type Review struct {
+	SubmissionID string    `json:"submission_id"`
+	ProblemID    string    `json:"problem_id"`
+	Rating       int       `json:"rating"` // 1-5
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+	Reviewer     string    `json:"-"`
+}
+
+var (
+	reviewsMu         sync.Mutex
+	reviewSeq         int
+	reviewSubmissions = make(map[string]*ReviewSubmission)
+	reviewsDB         = make(map[string][]Review) // Keyed by submission ID
+)
+
+// submitForReview queues the caller's solution for peer review
+func submitForReview(c *gin.Context) {
+	var sub ReviewSubmission
+	if err := c.BindJSON(&sub); err != nil || sub.ProblemID == "" || strings.TrimSpace(sub.Code) == "" || len(sub.Code) > maxReviewCodeBytes {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request",
+		})
+		return
+	}
+
+	reviewsMu.Lock()
+	reviewSeq++
+	sub.ID = fmt.Sprintf("rev-%d", reviewSeq)
+	sub.Author = c.GetString("license_key")
+	sub.SubmittedAt = time.Now()
+	reviewSubmissions[sub.ID] = &sub
+	reviewsMu.Unlock()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id": sub.ID,
+	})
+}
+
+// getNextReview hands the caller a solution from another user to review.
+// Only users who submitted a solution to the same problem may review it.
+func getNextReview(c *gin.Context) {
+	caller := c.GetString("license_key")
+	problemID := c.Query("problem_id")
+
+	reviewsMu.Lock()
+	defer reviewsMu.Unlock()
+
+	// Problems the caller has solved and shared
+	solved := make(map[string]bool)
+	for _, sub := range reviewSubmissions {
+		if sub.Author == caller {
+			solved[sub.ProblemID] = true
+		}
+	}
+	if problemID != "" && !solved[problemID] {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Submit your own solution to this problem before reviewing",
+		})
+		return
+	}
+
+	var candidates []*ReviewSubmission
+	for _, sub := range reviewSubmissions {
+		if sub.Author == caller || !solved[sub.ProblemID] || (problemID != "" && sub.ProblemID != problemID) {
+			continue
+		}
+		if reviewedBy(sub.ID, caller) {
+			continue
+		}
+		candidates = append(candidates, sub)
+	}
+	if len(candidates) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No solutions are waiting for review",
+		})
+		return
+	}
+
+	// Solutions with the fewest reviews first, then the longest waiting
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(reviewsDB[a.ID]) != len(reviewsDB[b.ID]) {
+			return len(reviewsDB[a.ID]) < len(reviewsDB[b.ID])
+		}
+		return a.SubmittedAt.Before(b.SubmittedAt)
+	})
+
+	c.JSON(http.StatusOK, candidates[0])
+}
+
+// postReview stores the caller's review of another user's submission
+func postReview(c *gin.Context) {
+	caller := c.GetString("license_key")
+
+	var review Review
+	if err := c.BindJSON(&review); err != nil || review.Rating < 1 || review.Rating > 5 || strings.TrimSpace(review.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request",
+		})
+		return
+	}
+
+	reviewsMu.Lock()
+	defer reviewsMu.Unlock()
+
+	sub, ok := reviewSubmissions[c.Param("id")]
+	switch {
+	case !ok:
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Submission not found",
+		})
+		return
+	case sub.Author == caller:
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "You cannot review your own solution",
+		})
+		return
+	case reviewedBy(sub.ID, caller):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "You already reviewed this solution",
+		})
+		return
+	}
+
+	review.SubmissionID = sub.ID
+	review.ProblemID = sub.ProblemID
+	review.Reviewer = caller
+	review.CreatedAt = time.Now()
+	reviewsDB[sub.ID] = append(reviewsDB[sub.ID], review)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status": "recorded",
+	})
+}
+
+// getReviewInbox returns the reviews of the caller's submissions, newest first
+func getReviewInbox(c *gin.Context) {
+	caller := c.GetString("license_key")
+
+	reviewsMu.Lock()
+	reviews := []Review{}
+	for _, sub := range reviewSubmissions {
+		if sub.Author == caller {
+			reviews = append(reviews, reviewsDB[sub.ID]...)
+		}
+	}
+	reviewsMu.Unlock()
+
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+	})
+}
+
+// reviewedBy reports whether a user already reviewed a submission.
+// Callers must hold reviewsMu.
+func reviewedBy(submissionID, reviewer string) bool {
+	for _, r := range reviewsDB[submissionID] {
+		if r.Reviewer == reviewer {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteReviewData removes a user's submissions, the reviews of them and
+// the reviews they wrote. It returns the number of records removed.
+func deleteReviewData(licenseKey string) int {
+	reviewsMu.Lock()
+	defer reviewsMu.Unlock()
+
+	deleted := 0
+	for id, sub := range reviewSubmissions {
+		if sub.Author == licenseKey {
+			deleted += 1 + len(reviewsDB[id])
+			delete(reviewSubmissions, id)
+			delete(reviewsDB, id)
+		}
+	}
+	for id, reviews := range reviewsDB {
+		kept := reviews[:0]
+		for _, r := range reviews {
+			if r.Reviewer == licenseKey {
+				deleted++
+				continue
+			}
+			kept = append(kept, r)
+		}
+		reviewsDB[id] = kept
+	}
+	return deleted
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reviewRequest sends an authorized request with an optional JSON body
+func reviewRequest(r *gin.Engine, method, path, license string, body interface{}) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+license)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPeerReviewExchange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	alice, bob := testLicenseFor("alice"), testLicenseFor("bob")
+
+	// Reviewing requires a solution of your own to the same problem
+	w := reviewRequest(r, http.MethodGet, "/v1/reviews/next?problem_id=two-sum", bob, nil)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+
+	w = reviewRequest(r, http.MethodPost, "/v1/reviews/submissions", alice, ReviewSubmission{ProblemID: "two-sum", Language: "go", Code: "func twoSum() {}"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.ID == "" {
+		t.Fatalf("expected a submission id, got %s", w.Body.String())
+	}
+
+	// Alice has nobody else's solution to review yet
+	w = reviewRequest(r, http.MethodGet, "/v1/reviews/next?problem_id=two-sum", alice, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+
+	w = reviewRequest(r, http.MethodPost, "/v1/reviews/submissions", bob, ReviewSubmission{ProblemID: "two-sum", Language: "python", Code: "def two_sum(): pass"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+
+	// Bob is handed Alice's solution without learning who wrote it
+	w = reviewRequest(r, http.MethodGet, "/v1/reviews/next?problem_id=two-sum", bob, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(alice)) {
+		t.Fatalf("submission leaked its author: %s", w.Body.String())
+	}
+	var next ReviewSubmission
+	if err := json.Unmarshal(w.Body.Bytes(), &next); err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != created.ID || next.Code != "func twoSum() {}" {
+		t.Fatalf("unexpected submission: %+v", next)
+	}
+
+	// Authors cannot review themselves and reviews need a rating and comment
+	path := "/v1/reviews/submissions/" + created.ID + "/reviews"
+	if w := reviewRequest(r, http.MethodPost, path, alice, Review{Rating: 5, Comment: "great"}); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	if w := reviewRequest(r, http.MethodPost, path, bob, Review{Rating: 9, Comment: "great"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	if w := reviewRequest(r, http.MethodPost, path, bob, Review{Rating: 4, Comment: "Clear, consider naming the map"}); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+	if w := reviewRequest(r, http.MethodPost, path, bob, Review{Rating: 4, Comment: "again"}); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	// The review shows up anonymously in Alice's inbox
+	w = reviewRequest(r, http.MethodGet, "/v1/reviews/inbox", alice, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(bob)) {
+		t.Fatalf("review leaked its reviewer: %s", w.Body.String())
+	}
+	var inbox struct {
+		Reviews []Review `json:"reviews"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &inbox); err != nil {
+		t.Fatal(err)
+	}
+	if len(inbox.Reviews) != 1 || inbox.Reviews[0].Rating != 4 || inbox.Reviews[0].ProblemID != "two-sum" {
+		t.Fatalf("unexpected inbox: %+v", inbox.Reviews)
+	}
+
+	// Deleting user data removes the submission and its review
+	if deleted := deleteReviewData(alice); deleted != 2 {
+		t.Fatalf("expected 2 deleted records, got %d", deleted)
+	}
+	if deleted := deleteReviewData(bob); deleted != 1 {
+		t.Fatalf("expected 1 deleted record, got %d", deleted)
+	}
+}
@@ -0,0 +1,118 @@
+// Shared session snapshots for asking others for help
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxSnapshotBytes bounds the size of an uploaded snapshot
+	maxSnapshotBytes = 256 * 1024
+	// maxSnapshotsPerUser bounds the snapshots a user can have at once
+	maxSnapshotsPerUser = 50
+	// snapshotTTL is how long a snapshot can be opened after it is shared
+	snapshotTTL = 30 * 24 * time.Hour
+)
+
+// Snapshot is an encrypted session snapshot. The server never has the key,
+// so it stores the data as given and serves it to anyone with the ID.
+type Snapshot struct {
+	Data      []byte
+	Author    string
+	ExpiresAt time.Time
+}
+
+var (
+	snapshotsMu sync.Mutex
+	snapshots   = make(map[string]*Snapshot)
+)
+
+// uploadSnapshot stores the caller's encrypted snapshot and returns its ID
+func uploadSnapshot(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSnapshotBytes+1))
+	if err != nil || len(data) == 0 || len(data) > maxSnapshotBytes {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid snapshot",
+		})
+		return
+	}
+	caller := c.GetString("license_key")
+
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	now := time.Now()
+	mine := 0
+	for id, s := range snapshots {
+		if now.After(s.ExpiresAt) {
+			delete(snapshots, id)
+		} else if s.Author == caller {
+			mine++
+		}
+	}
+	if mine >= maxSnapshotsPerUser {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": "Too many snapshots shared; older ones expire after 30 days",
+		})
+		return
+	}
+
+	id := generateSnapshotID()
+	snapshots[id] = &Snapshot{Data: data, Author: caller, ExpiresAt: now.Add(snapshotTTL)}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         id,
+		"expires_at": snapshots[id].ExpiresAt,
+	})
+}
+
+// getSnapshot serves an encrypted snapshot. It needs no license, so the
+// people a snapshot is shared with can open it.
+func getSnapshot(c *gin.Context) {
+	snapshotsMu.Lock()
+	s, ok := snapshots[c.Param("id")]
+	if ok && time.Now().After(s.ExpiresAt) {
+		delete(snapshots, c.Param("id"))
+		ok = false
+	}
+	snapshotsMu.Unlock()
+
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Snapshot not found or expired",
+		})
+		return
+	}
+	c.Data(http.StatusOK, "application/octet-stream", s.Data)
+}
+
+// deleteSnapshotData removes a user's snapshots. It returns the number of
+// records removed.
+func deleteSnapshotData(licenseKey string) int {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	deleted := 0
+	for id, s := range snapshots {
+		if s.Author == licenseKey {
+			delete(snapshots, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// generateSnapshotID returns a random, unguessable snapshot ID
+func generateSnapshotID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
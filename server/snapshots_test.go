@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSnapshots(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	alice := testLicenseFor("alice-snapshot")
+
+	upload := func(license string, data []byte) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/snapshots", bytes.NewReader(data))
+		req.Header.Set("Authorization", "Bearer "+license)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var body struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.ID
+	}
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/snapshots/"+id, nil))
+		return w
+	}
+
+	if code, _ := upload("not-a-license", []byte("sealed")); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", code)
+	}
+	if code, _ := upload(alice, nil); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty snapshot, got %d", code)
+	}
+	if code, _ := upload(alice, make([]byte, maxSnapshotBytes+1)); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an oversized snapshot, got %d", code)
+	}
+
+	code, id := upload(alice, []byte("sealed"))
+	if code != http.StatusCreated || len(id) != 32 {
+		t.Fatalf("expected 201 with an ID, got %d %q", code, id)
+	}
+
+	// Anyone with the ID can fetch it, without a license
+	if w := get(id); w.Code != http.StatusOK || w.Body.String() != "sealed" {
+		t.Fatalf("expected the snapshot, got %d %q", w.Code, w.Body.String())
+	}
+	if w := get("missing"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+
+	// Expired snapshots are gone
+	snapshotsMu.Lock()
+	snapshots[id].ExpiresAt = time.Now().Add(-time.Minute)
+	snapshotsMu.Unlock()
+	if w := get(id); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an expired snapshot, got %d", w.Code)
+	}
+
+	// Deleting user data removes the user's snapshots
+	upload(alice, []byte("one"))
+	upload(alice, []byte("two"))
+	if deleted := deleteSnapshotData(alice); deleted != 2 {
+		t.Fatalf("expected 2 snapshots deleted, got %d", deleted)
+	}
+}
@@ -0,0 +1,533 @@
+// SQL storage for licenses, problem sets, progress, profiles, refresh
+// tokens and telemetry, on Postgres or SQLite
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"  // Postgres driver
+	_ "modernc.org/sqlite" // Pure Go driver, so builds need no C toolchain
+)
+
+// dialect holds what differs between the SQL databases the server supports
+type dialect struct {
+	driver string
+
+	// migrations are applied in order; schema_migrations records which ran
+	migrations []string
+
+	// numbered reports whether placeholders are written $1, $2, ... rather
+	// than ?
+	numbered bool
+
+	// maxOpenConns is the default connection pool size
+	maxOpenConns int
+}
+
+var postgresDialect = dialect{
+	driver: "postgres",
+	migrations: []string{
+		`CREATE TABLE licenses (
+			license_key   TEXT PRIMARY KEY,
+			email         TEXT NOT NULL,
+			purchase_date TIMESTAMPTZ NOT NULL,
+			expiry_date   TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX licenses_email ON licenses (email)`,
+		`CREATE TABLE problem_sets (
+			id           BIGSERIAL PRIMARY KEY,
+			version      TEXT NOT NULL,
+			last_updated TIMESTAMPTZ NOT NULL,
+			data         JSONB NOT NULL
+		)`,
+		`CREATE TABLE progress_records (
+			license_key TEXT NOT NULL,
+			kind        TEXT NOT NULL,
+			record_key  TEXT NOT NULL,
+			updated_at  BIGINT NOT NULL,
+			data        TEXT NOT NULL,
+			PRIMARY KEY (license_key, kind, record_key)
+		);
+		CREATE TABLE attempts (
+			id               BIGSERIAL PRIMARY KEY,
+			problem_id       TEXT NOT NULL,
+			solved           BOOLEAN NOT NULL,
+			duration_seconds INTEGER NOT NULL,
+			failing_tests    TEXT NOT NULL,
+			language         TEXT NOT NULL,
+			received_at      TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX attempts_problem ON attempts (problem_id)`,
+		`CREATE TABLE profiles (
+			license_key  TEXT PRIMARY KEY,
+			display_name TEXT NOT NULL,
+			team         TEXT NOT NULL,
+			private      BOOLEAN NOT NULL,
+			updated_at   TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX progress_records_kind ON progress_records (kind)`,
+		`CREATE TABLE refresh_tokens (
+			token_hash  TEXT PRIMARY KEY,
+			license_key TEXT NOT NULL,
+			expires_at  TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX refresh_tokens_license ON refresh_tokens (license_key)`,
+	},
+	numbered:     true,
+	maxOpenConns: 10,
+}
+
+var sqliteDialect = dialect{
+	driver: "sqlite",
+	migrations: []string{
+		`CREATE TABLE licenses (
+			license_key   TEXT PRIMARY KEY,
+			email         TEXT NOT NULL,
+			purchase_date TIMESTAMP NOT NULL,
+			expiry_date   TIMESTAMP NOT NULL
+		);
+		CREATE INDEX licenses_email ON licenses (email)`,
+		`CREATE TABLE problem_sets (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			version      TEXT NOT NULL,
+			last_updated TIMESTAMP NOT NULL,
+			data         TEXT NOT NULL
+		)`,
+		`CREATE TABLE progress_records (
+			license_key TEXT NOT NULL,
+			kind        TEXT NOT NULL,
+			record_key  TEXT NOT NULL,
+			updated_at  INTEGER NOT NULL,
+			data        TEXT NOT NULL,
+			PRIMARY KEY (license_key, kind, record_key)
+		);
+		CREATE TABLE attempts (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem_id       TEXT NOT NULL,
+			solved           BOOLEAN NOT NULL,
+			duration_seconds INTEGER NOT NULL,
+			failing_tests    TEXT NOT NULL,
+			language         TEXT NOT NULL,
+			received_at      TIMESTAMP NOT NULL
+		);
+		CREATE INDEX attempts_problem ON attempts (problem_id)`,
+		`CREATE TABLE profiles (
+			license_key  TEXT PRIMARY KEY,
+			display_name TEXT NOT NULL,
+			team         TEXT NOT NULL,
+			private      BOOLEAN NOT NULL,
+			updated_at   TIMESTAMP NOT NULL
+		);
+		CREATE INDEX progress_records_kind ON progress_records (kind)`,
+		`CREATE TABLE refresh_tokens (
+			token_hash  TEXT PRIMARY KEY,
+			license_key TEXT NOT NULL,
+			expires_at  TIMESTAMP NOT NULL
+		);
+		CREATE INDEX refresh_tokens_license ON refresh_tokens (license_key)`,
+	},
+	// SQLite allows a single writer, so more connections only wait on
+	// each other
+	maxOpenConns: 1,
+}
+
+// sqlStore is a Store on a SQL database
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// openSQLStore connects to a database, sizes its connection pool and
+// brings its schema up to date
+func openSQLStore(d dialect, dataSource string) (*sqlStore, error) {
+	if d.driver == "sqlite" {
+		dataSource += "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	}
+	db, err := sql.Open(d.driver, dataSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+	configurePool(db, d)
+
+	s := &sqlStore{db: db, dialect: d}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	}
+	return s, nil
+}
+
+// configurePool sizes the connection pool, which DB_MAX_OPEN_CONNS,
+// DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME override
+func configurePool(db *sql.DB, d dialect) {
+	maxOpen := envInt("DB_MAX_OPEN_CONNS", d.maxOpenConns)
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(envInt("DB_MAX_IDLE_CONNS", maxOpen))
+
+	lifetime := 30 * time.Minute
+	if value := os.Getenv("DB_CONN_MAX_LIFETIME"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			lifetime = parsed
+		}
+	}
+	db.SetConnMaxLifetime(lifetime)
+}
+
+// envInt reads a positive integer from the environment
+func envInt(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// migrate applies the migrations that have not run yet, one transaction
+// per migration
+func (s *sqlStore) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	var version int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(s.dialect.migrations); version++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, s.dialect.migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %v", version+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebind rewrites ? placeholders for databases that number them
+func (s *sqlStore) rebind(query string) string {
+	if !s.dialect.numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *sqlStore) SaveLicense(ctx context.Context, lic License) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`
+		INSERT INTO licenses (license_key, email, purchase_date, expiry_date)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (license_key) DO UPDATE SET
+			email = excluded.email, purchase_date = excluded.purchase_date, expiry_date = excluded.expiry_date`),
+		lic.LicenseKey, lic.Email, lic.PurchaseDate.UTC(), lic.ExpiryDate.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save license: %v", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) GetLicense(ctx context.Context, licenseKey string) (License, bool, error) {
+	lic := License{LicenseKey: licenseKey}
+	err := s.db.QueryRowContext(ctx, s.rebind(`
+		SELECT email, purchase_date, expiry_date FROM licenses WHERE license_key = ?`), licenseKey).
+		Scan(&lic.Email, &lic.PurchaseDate, &lic.ExpiryDate)
+	if err == sql.ErrNoRows {
+		return License{}, false, nil
+	}
+	if err != nil {
+		return License{}, false, fmt.Errorf("failed to load license: %v", err)
+	}
+	return lic, true, nil
+}
+
+func (s *sqlStore) SaveProblemSet(ctx context.Context, set ProblemSet) error {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`
+		INSERT INTO problem_sets (version, last_updated, data) VALUES (?, ?, ?)`),
+		set.Version, set.LastUpdated.UTC(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save problem set: %v", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) LoadProblemSet(ctx context.Context) (ProblemSet, bool, error) {
+	return s.loadProblemSet(ctx, `SELECT data FROM problem_sets ORDER BY id DESC LIMIT 1`)
+}
+
+func (s *sqlStore) LoadProblemSetVersion(ctx context.Context, version string) (ProblemSet, bool, error) {
+	return s.loadProblemSet(ctx, s.rebind(`SELECT data FROM problem_sets WHERE version = ? ORDER BY id DESC LIMIT 1`), version)
+}
+
+// loadProblemSet decodes the problem set a query selects
+func (s *sqlStore) loadProblemSet(ctx context.Context, query string, args ...any) (ProblemSet, bool, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&data)
+	if err == sql.ErrNoRows {
+		return ProblemSet{}, false, nil
+	}
+	if err != nil {
+		return ProblemSet{}, false, fmt.Errorf("failed to load problem set: %v", err)
+	}
+
+	var set ProblemSet
+	if err := json.Unmarshal([]byte(data), &set); err != nil {
+		return ProblemSet{}, false, fmt.Errorf("invalid problem set: %v", err)
+	}
+	return set, true, nil
+}
+
+// MergeProgress upserts each record unless the stored version is at least
+// as new. Update times are kept as Unix nanoseconds so both databases
+// compare them exactly.
+func (s *sqlStore) MergeProgress(ctx context.Context, licenseKey string, records []ProgressRecord) (ProgressSnapshot, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ProgressSnapshot{}, err
+	}
+	defer tx.Rollback()
+
+	query := s.rebind(`
+		INSERT INTO progress_records (license_key, kind, record_key, updated_at, data)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (license_key, kind, record_key) DO UPDATE SET
+			updated_at = excluded.updated_at, data = excluded.data
+		WHERE excluded.updated_at > progress_records.updated_at`)
+	for _, r := range records {
+		if _, err := tx.ExecContext(ctx, query, licenseKey, r.Kind, r.Key, r.UpdatedAt.UnixNano(), string(r.Data)); err != nil {
+			return ProgressSnapshot{}, fmt.Errorf("failed to save progress: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return ProgressSnapshot{}, fmt.Errorf("failed to save progress: %v", err)
+	}
+	return s.LoadProgress(ctx, licenseKey)
+}
+
+func (s *sqlStore) LoadProgress(ctx context.Context, licenseKey string) (ProgressSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`
+		SELECT kind, record_key, updated_at, data FROM progress_records WHERE license_key = ?`), licenseKey)
+	if err != nil {
+		return ProgressSnapshot{}, fmt.Errorf("failed to load progress: %v", err)
+	}
+	defer rows.Close()
+
+	var records []ProgressRecord
+	for rows.Next() {
+		var r ProgressRecord
+		var updatedAt int64
+		var data string
+		if err := rows.Scan(&r.Kind, &r.Key, &updatedAt, &data); err != nil {
+			return ProgressSnapshot{}, fmt.Errorf("failed to load progress: %v", err)
+		}
+		r.UpdatedAt = time.Unix(0, updatedAt).UTC()
+		r.Data = json.RawMessage(data)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return ProgressSnapshot{}, fmt.Errorf("failed to load progress: %v", err)
+	}
+	return progressSnapshot(records), nil
+}
+
+func (s *sqlStore) DeleteProgress(ctx context.Context, licenseKey string) (int, error) {
+	result, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM progress_records WHERE license_key = ?`), licenseKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete progress: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func (s *sqlStore) LoadAllProgress(ctx context.Context, kind string) (map[string][]ProgressRecord, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`
+		SELECT license_key, record_key, updated_at, data FROM progress_records WHERE kind = ?`), kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load progress: %v", err)
+	}
+	defer rows.Close()
+
+	all := make(map[string][]ProgressRecord)
+	for rows.Next() {
+		r := ProgressRecord{Kind: kind}
+		var licenseKey, data string
+		var updatedAt int64
+		if err := rows.Scan(&licenseKey, &r.Key, &updatedAt, &data); err != nil {
+			return nil, fmt.Errorf("failed to load progress: %v", err)
+		}
+		r.UpdatedAt = time.Unix(0, updatedAt).UTC()
+		r.Data = json.RawMessage(data)
+		all[licenseKey] = append(all[licenseKey], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load progress: %v", err)
+	}
+	return all, nil
+}
+
+func (s *sqlStore) SaveProfile(ctx context.Context, licenseKey string, profile Profile) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`
+		INSERT INTO profiles (license_key, display_name, team, private, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (license_key) DO UPDATE SET
+			display_name = excluded.display_name, team = excluded.team,
+			private = excluded.private, updated_at = excluded.updated_at`),
+		licenseKey, profile.DisplayName, profile.Team, profile.Private, profile.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %v", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) LoadProfiles(ctx context.Context) (map[string]Profile, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT license_key, display_name, team, private, updated_at FROM profiles`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profiles: %v", err)
+	}
+	defer rows.Close()
+
+	profiles := make(map[string]Profile)
+	for rows.Next() {
+		var licenseKey string
+		var p Profile
+		if err := rows.Scan(&licenseKey, &p.DisplayName, &p.Team, &p.Private, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to load profiles: %v", err)
+		}
+		profiles[licenseKey] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load profiles: %v", err)
+	}
+	return profiles, nil
+}
+
+func (s *sqlStore) DeleteProfile(ctx context.Context, licenseKey string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM profiles WHERE license_key = ?`), licenseKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete profile: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+func (s *sqlStore) SaveRefreshToken(ctx context.Context, tokenHash string, token RefreshToken) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Expired tokens are only ever removed here and when used
+	if _, err := tx.ExecContext(ctx, s.rebind(`DELETE FROM refresh_tokens WHERE license_key = ? AND expires_at < ?`),
+		token.LicenseKey, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save refresh token: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO refresh_tokens (token_hash, license_key, expires_at) VALUES (?, ?, ?)`),
+		tokenHash, token.LicenseKey, token.ExpiresAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save refresh token: %v", err)
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) TakeRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, bool, error) {
+	var token RefreshToken
+	err := s.db.QueryRowContext(ctx, s.rebind(`DELETE FROM refresh_tokens WHERE token_hash = ? RETURNING license_key, expires_at`),
+		tokenHash).Scan(&token.LicenseKey, &token.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return RefreshToken{}, false, nil
+	}
+	if err != nil {
+		return RefreshToken{}, false, fmt.Errorf("failed to load refresh token: %v", err)
+	}
+	return token, true, nil
+}
+
+func (s *sqlStore) DeleteRefreshTokens(ctx context.Context, licenseKey string) (int, error) {
+	result, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM refresh_tokens WHERE license_key = ?`), licenseKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete refresh tokens: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func (s *sqlStore) SaveAttempt(ctx context.Context, attempt Attempt) error {
+	failing := attempt.FailingTests
+	if failing == nil {
+		failing = []int{}
+	}
+	data, err := json.Marshal(failing)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`
+		INSERT INTO attempts (problem_id, solved, duration_seconds, failing_tests, language, received_at)
+		VALUES (?, ?, ?, ?, ?, ?)`),
+		attempt.ProblemID, attempt.Solved, attempt.DurationSeconds, string(data), attempt.Language, attempt.ReceivedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save attempt: %v", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`
+		SELECT solved, duration_seconds, failing_tests, language, received_at
+		FROM attempts WHERE problem_id = ? ORDER BY id`), problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attempts: %v", err)
+	}
+	defer rows.Close()
+
+	var attempts []Attempt
+	for rows.Next() {
+		a := Attempt{ProblemID: problemID}
+		var failing string
+		if err := rows.Scan(&a.Solved, &a.DurationSeconds, &failing, &a.Language, &a.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("failed to load attempts: %v", err)
+		}
+		if err := json.Unmarshal([]byte(failing), &a.FailingTests); err != nil {
+			return nil, fmt.Errorf("invalid attempt: %v", err)
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+func (s *sqlStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,304 @@
+// Persistent storage for licenses, problem sets, progress, profiles,
+// refresh tokens and telemetry
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store keeps the server's licenses, problem sets, synced progress,
+// profiles, refresh tokens and telemetry. Replicas that share a store can
+// serve any request.
+type Store interface {
+	// SaveLicense stores a license, replacing one with the same key
+	SaveLicense(ctx context.Context, lic License) error
+
+	// GetLicense returns a stored license; ok is false when there is none
+	GetLicense(ctx context.Context, licenseKey string) (lic License, ok bool, err error)
+
+	// SaveProblemSet stores a problem set as the current one
+	SaveProblemSet(ctx context.Context, set ProblemSet) error
+
+	// LoadProblemSet returns the current problem set; ok is false when none
+	// has been stored yet
+	LoadProblemSet(ctx context.Context) (set ProblemSet, ok bool, err error)
+
+	// LoadProblemSetVersion returns the last problem set stored with a
+	// version; ok is false when there is none
+	LoadProblemSetVersion(ctx context.Context, version string) (set ProblemSet, ok bool, err error)
+
+	// MergeProgress adds records to a user's progress, keeping the newer
+	// version of each, and returns the merged progress
+	MergeProgress(ctx context.Context, licenseKey string, records []ProgressRecord) (ProgressSnapshot, error)
+
+	// LoadProgress returns a user's progress
+	LoadProgress(ctx context.Context, licenseKey string) (ProgressSnapshot, error)
+
+	// DeleteProgress removes a user's progress, returning the number of
+	// records removed
+	DeleteProgress(ctx context.Context, licenseKey string) (int, error)
+
+	// LoadAllProgress returns every user's records of one kind, keyed by
+	// license
+	LoadAllProgress(ctx context.Context, kind string) (map[string][]ProgressRecord, error)
+
+	// SaveProfile stores a user's profile, replacing the previous one
+	SaveProfile(ctx context.Context, licenseKey string, profile Profile) error
+
+	// LoadProfiles returns every stored profile, keyed by license
+	LoadProfiles(ctx context.Context) (map[string]Profile, error)
+
+	// DeleteProfile removes a user's profile, reporting whether there was one
+	DeleteProfile(ctx context.Context, licenseKey string) (bool, error)
+
+	// SaveRefreshToken stores a refresh token under the hash of its value
+	SaveRefreshToken(ctx context.Context, tokenHash string, token RefreshToken) error
+
+	// TakeRefreshToken removes and returns a refresh token, so each one is
+	// used once; ok is false when there is none
+	TakeRefreshToken(ctx context.Context, tokenHash string) (token RefreshToken, ok bool, err error)
+
+	// DeleteRefreshTokens removes a user's refresh tokens, returning the
+	// number removed
+	DeleteRefreshTokens(ctx context.Context, licenseKey string) (int, error)
+
+	// SaveAttempt stores an anonymous attempt
+	SaveAttempt(ctx context.Context, attempt Attempt) error
+
+	// LoadAttempts returns the attempts at a problem, oldest first
+	LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error)
+
+	// Ping checks the store can be reached
+	Ping(ctx context.Context) error
+
+	// Close releases the store's connections
+	Close() error
+}
+
+// openStore opens the store named by a DATABASE_URL: a postgres:// or
+// postgresql:// URL, or sqlite:// followed by a file path. Without one the
+// server keeps everything in memory and loses it on restart.
+func openStore(databaseURL string) (Store, error) {
+	switch {
+	case databaseURL == "":
+		log.Println("DATABASE_URL is not set, keeping licenses and problems in memory")
+		return newMemoryStore(), nil
+	case strings.HasPrefix(databaseURL, "postgres://"), strings.HasPrefix(databaseURL, "postgresql://"):
+		return openSQLStore(postgresDialect, databaseURL)
+	case strings.HasPrefix(databaseURL, "sqlite://"):
+		return openSQLStore(sqliteDialect, strings.TrimPrefix(databaseURL, "sqlite://"))
+	default:
+		return nil, fmt.Errorf("unsupported DATABASE_URL %q: expected postgres:// or sqlite://", databaseURL)
+	}
+}
+
+// loadProblemSet returns the store's problem set, seeding the store with
+// the sample problems the first time it is used
+func loadProblemSet(ctx context.Context, s Store) (ProblemSet, error) {
+	set, ok, err := s.LoadProblemSet(ctx)
+	if err != nil || ok {
+		return set, err
+	}
+
+	set = getSampleProblems()
+	if err := s.SaveProblemSet(ctx, set); err != nil {
+		return ProblemSet{}, err
+	}
+	return set, nil
+}
+
+// memoryStore is a Store that lasts as long as the process
+type memoryStore struct {
+	mu       sync.Mutex
+	licenses map[string]License
+	problems []ProblemSet // Every set stored, oldest first
+	progress map[string]*UserData
+	profiles map[string]Profile
+	tokens   map[string]RefreshToken // Keyed by token hash
+	attempts map[string][]Attempt    // Keyed by problem ID
+}
+
+// newMemoryStore creates an empty in-memory store
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		licenses: make(map[string]License),
+		progress: make(map[string]*UserData),
+		profiles: make(map[string]Profile),
+		tokens:   make(map[string]RefreshToken),
+		attempts: make(map[string][]Attempt),
+	}
+}
+
+func (s *memoryStore) SaveLicense(ctx context.Context, lic License) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.licenses[lic.LicenseKey] = lic
+	return nil
+}
+
+func (s *memoryStore) GetLicense(ctx context.Context, licenseKey string) (License, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lic, ok := s.licenses[licenseKey]
+	return lic, ok, nil
+}
+
+func (s *memoryStore) SaveProblemSet(ctx context.Context, set ProblemSet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.problems = append(s.problems, set)
+	return nil
+}
+
+func (s *memoryStore) LoadProblemSet(ctx context.Context) (ProblemSet, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.problems) == 0 {
+		return ProblemSet{}, false, nil
+	}
+	return s.problems[len(s.problems)-1], true, nil
+}
+
+func (s *memoryStore) LoadProblemSetVersion(ctx context.Context, version string) (ProblemSet, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.problems) - 1; i >= 0; i-- {
+		if s.problems[i].Version == version {
+			return s.problems[i], true, nil
+		}
+	}
+	return ProblemSet{}, false, nil
+}
+
+func (s *memoryStore) MergeProgress(ctx context.Context, licenseKey string, records []ProgressRecord) (ProgressSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.progress[licenseKey]
+	if !ok {
+		data = &UserData{}
+		s.progress[licenseKey] = data
+	}
+	mergeProgress(data, records)
+	return progressSnapshot(data.Records), nil
+}
+
+func (s *memoryStore) LoadProgress(ctx context.Context, licenseKey string) (ProgressSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []ProgressRecord
+	if data, ok := s.progress[licenseKey]; ok {
+		records = data.Records
+	}
+	return progressSnapshot(records), nil
+}
+
+func (s *memoryStore) DeleteProgress(ctx context.Context, licenseKey string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	if data, ok := s.progress[licenseKey]; ok {
+		deleted = len(data.Records)
+		delete(s.progress, licenseKey)
+	}
+	return deleted, nil
+}
+
+func (s *memoryStore) LoadAllProgress(ctx context.Context, kind string) (map[string][]ProgressRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make(map[string][]ProgressRecord)
+	for licenseKey, data := range s.progress {
+		for _, r := range data.Records {
+			if r.Kind == kind {
+				all[licenseKey] = append(all[licenseKey], r)
+			}
+		}
+	}
+	return all, nil
+}
+
+func (s *memoryStore) SaveProfile(ctx context.Context, licenseKey string, profile Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[licenseKey] = profile
+	return nil
+}
+
+func (s *memoryStore) LoadProfiles(ctx context.Context) (map[string]Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profiles := make(map[string]Profile, len(s.profiles))
+	for licenseKey, p := range s.profiles {
+		profiles[licenseKey] = p
+	}
+	return profiles, nil
+}
+
+func (s *memoryStore) DeleteProfile(ctx context.Context, licenseKey string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.profiles[licenseKey]
+	delete(s.profiles, licenseKey)
+	return ok, nil
+}
+
+func (s *memoryStore) SaveRefreshToken(ctx context.Context, tokenHash string, token RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for hash, t := range s.tokens {
+		if t.LicenseKey == token.LicenseKey && now.After(t.ExpiresAt) {
+			delete(s.tokens, hash)
+		}
+	}
+	s.tokens[tokenHash] = token
+	return nil
+}
+
+func (s *memoryStore) TakeRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[tokenHash]
+	delete(s.tokens, tokenHash)
+	return token, ok, nil
+}
+
+func (s *memoryStore) DeleteRefreshTokens(ctx context.Context, licenseKey string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for hash, t := range s.tokens {
+		if t.LicenseKey == licenseKey {
+			delete(s.tokens, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *memoryStore) SaveAttempt(ctx context.Context, attempt Attempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts[attempt.ProblemID] = append(s.attempts[attempt.ProblemID], attempt)
+	return nil
+}
+
+func (s *memoryStore) LoadAttempts(ctx context.Context, problemID string) ([]Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Attempt(nil), s.attempts[problemID]...), nil
+}
+
+func (s *memoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenStore(t *testing.T) {
+	s, err := openStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.(*memoryStore); !ok {
+		t.Fatalf("expected an in-memory store without a DATABASE_URL, got %T", s)
+	}
+
+	if _, err := openStore("mysql://localhost/algo"); err == nil {
+		t.Fatal("expected an error for an unsupported database")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, newMemoryStore())
+}
+
+func TestSQLiteStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.db")
+	s, err := openStore("sqlite://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+	s.Close()
+
+	// Everything survives a restart, and migrations are not rerun
+	s, err = openStore("sqlite://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, ok, err := s.GetLicense(context.Background(), "key-1"); err != nil || !ok {
+		t.Fatalf("expected the license to survive a restart, got %v, %v", ok, err)
+	}
+	if set, ok, err := s.LoadProblemSet(context.Background()); err != nil || !ok || set.Version != "2.0.0" {
+		t.Fatalf("expected the problem set to survive a restart, got %q, %v, %v", set.Version, ok, err)
+	}
+	if deleted, err := s.DeleteProgress(context.Background(), "key-1"); err != nil || deleted != 2 {
+		t.Fatalf("expected the progress to survive a restart, got %d, %v", deleted, err)
+	}
+}
+
+// TestPostgresStore runs against the database in TEST_DATABASE_URL, which
+// it adds tables to
+func TestPostgresStore(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	s, err := openStore(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	testStore(t, s)
+}
+
+// testStore checks the behavior every Store shares, on an empty store
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	if _, ok, err := s.GetLicense(ctx, "key-1"); err != nil || ok {
+		t.Fatalf("expected no license in an empty store, got %v, %v", ok, err)
+	}
+	purchased := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	lic := License{LicenseKey: "key-1", Email: "a@example.com", PurchaseDate: purchased, ExpiryDate: purchased.AddDate(1, 0, 0)}
+	if err := s.SaveLicense(ctx, lic); err != nil {
+		t.Fatal(err)
+	}
+	lic.Email = "b@example.com"
+	if err := s.SaveLicense(ctx, lic); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := s.GetLicense(ctx, "key-1")
+	if err != nil || !ok {
+		t.Fatalf("expected the saved license, got %v, %v", ok, err)
+	}
+	if got.Email != "b@example.com" || !got.PurchaseDate.Equal(lic.PurchaseDate) || !got.ExpiryDate.Equal(lic.ExpiryDate) {
+		t.Fatalf("unexpected license %+v", got)
+	}
+
+	// The first load seeds the sample problems; later saves replace them
+	set, err := loadProblemSet(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Problems) != len(getSampleProblems().Problems) {
+		t.Fatalf("expected the sample problems, got %d", len(set.Problems))
+	}
+	set.Version = "2.0.0"
+	set.Problems = set.Problems[:1]
+	if err := s.SaveProblemSet(ctx, set); err != nil {
+		t.Fatal(err)
+	}
+	set, err = loadProblemSet(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Version != "2.0.0" || len(set.Problems) != 1 || set.Problems[0].Signature == nil {
+		t.Fatalf("expected the saved problem set, got %q with %d problems", set.Version, len(set.Problems))
+	}
+
+	// Progress keeps the newer version of each record
+	older := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	if _, err := s.MergeProgress(ctx, "key-1", []ProgressRecord{
+		{Kind: "streak", Key: "daily", UpdatedAt: newer, Data: json.RawMessage(`{"Current":3}`)},
+		{Kind: "daily", Key: "2026-05-01", UpdatedAt: older, Data: json.RawMessage(`{"completed":false}`)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := s.MergeProgress(ctx, "key-1", []ProgressRecord{
+		{Kind: "streak", Key: "daily", UpdatedAt: older, Data: json.RawMessage(`{"Current":1}`)},
+		{Kind: "daily", Key: "2026-05-01", UpdatedAt: newer, Data: json.RawMessage(`{"completed":true}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Records) != 2 || !snapshot.UpdatedAt.Equal(newer) {
+		t.Fatalf("expected 2 records updated at %v, got %+v", newer, snapshot)
+	}
+	if string(snapshot.Records[0].Data) != `{"completed":true}` || string(snapshot.Records[1].Data) != `{"Current":3}` {
+		t.Fatalf("expected the newer versions, got %s and %s", snapshot.Records[0].Data, snapshot.Records[1].Data)
+	}
+	if snapshot, err := s.LoadProgress(ctx, "key-2"); err != nil || len(snapshot.Records) != 0 {
+		t.Fatalf("expected no progress for another user, got %+v, %v", snapshot, err)
+	}
+
+	all, err := s.LoadAllProgress(ctx, "streak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || len(all["key-1"]) != 1 || string(all["key-1"][0].Data) != `{"Current":3}` {
+		t.Fatalf("expected key-1's streak, got %+v", all)
+	}
+
+	// Profiles are replaced whole and can be deleted
+	if err := s.SaveProfile(ctx, "key-2", Profile{DisplayName: "Bo", UpdatedAt: older}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveProfile(ctx, "key-2", Profile{DisplayName: "Bo", Team: "cs101", Private: true, UpdatedAt: newer}); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := s.LoadProfiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := profiles["key-2"]; len(profiles) != 1 || p.Team != "cs101" || !p.Private || !p.UpdatedAt.Equal(newer) {
+		t.Fatalf("expected the newer profile, got %+v", profiles)
+	}
+	if deleted, err := s.DeleteProfile(ctx, "key-2"); err != nil || !deleted {
+		t.Fatalf("expected the profile to be deleted, got %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteProfile(ctx, "key-2"); err != nil || deleted {
+		t.Fatalf("expected no profile left to delete, got %v, %v", deleted, err)
+	}
+
+	// Refresh tokens are used once
+	later := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	for _, hash := range []string{"hash-1", "hash-2"} {
+		if err := s.SaveRefreshToken(ctx, hash, RefreshToken{LicenseKey: "key-1", ExpiresAt: later}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if token, ok, err := s.TakeRefreshToken(ctx, "hash-1"); err != nil || !ok || token.LicenseKey != "key-1" || !token.ExpiresAt.Equal(later) {
+		t.Fatalf("expected key-1's refresh token, got %+v, %v, %v", token, ok, err)
+	}
+	if _, ok, err := s.TakeRefreshToken(ctx, "hash-1"); err != nil || ok {
+		t.Fatalf("expected the refresh token to be used up, got %v, %v", ok, err)
+	}
+	if deleted, err := s.DeleteRefreshTokens(ctx, "key-1"); err != nil || deleted != 1 {
+		t.Fatalf("expected key-1's other refresh token deleted, got %d, %v", deleted, err)
+	}
+
+	if err := s.SaveAttempt(ctx, Attempt{ProblemID: "two-sum", Solved: false, DurationSeconds: 90, FailingTests: []int{2, 3}, Language: "go", ReceivedAt: older}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveAttempt(ctx, Attempt{ProblemID: "two-sum", Solved: true, DurationSeconds: 60, ReceivedAt: newer}); err != nil {
+		t.Fatal(err)
+	}
+	attempts, err := s.LoadAttempts(ctx, "two-sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 2 || attempts[0].Language != "go" || len(attempts[0].FailingTests) != 2 || !attempts[1].Solved {
+		t.Fatalf("unexpected attempts %+v", attempts)
+	}
+
+	if err := s.Ping(ctx); err != nil {
+		t.Fatalf("expected the store to answer, got %v", err)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	pg := &sqlStore{dialect: postgresDialect}
+	if got := pg.rebind("SELECT a FROM t WHERE b = ? AND c = ?"); got != "SELECT a FROM t WHERE b = $1 AND c = $2" {
+		t.Fatalf("unexpected postgres query %q", got)
+	}
+	lite := &sqlStore{dialect: sqliteDialect}
+	if got := lite.rebind("SELECT ?"); got != "SELECT ?" {
+		t.Fatalf("unexpected sqlite query %q", got)
+	}
+}
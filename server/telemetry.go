@@ -0,0 +1,132 @@
+// Opt-in attempt telemetry and per-problem statistics
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxFailingTests is the number of most common failing tests reported
+const maxFailingTests = 5
+
+// Attempt is an anonymous record of one session, sent by clients that opted in
+type Attempt struct {
+	ProblemID       string    `json:"problem_id"`
+	Solved          bool      `json:"solved"`
+	DurationSeconds int       `json:"duration_seconds"`
+	FailingTests    []int     `json:"failing_tests,omitempty"` // 1-based test case numbers
+	Language        string    `json:"language,omitempty"`
+	ReceivedAt      time.Time `json:"-"`
+}
+
+// FailingTest counts how often a test case failed
+type FailingTest struct {
+	Test     int `json:"test"`
+	Failures int `json:"failures"`
+}
+
+// ProblemStats aggregates attempts for a single problem
+type ProblemStats struct {
+	ProblemID          string        `json:"problem_id"`
+	Attempts           int           `json:"attempts"`
+	SolveRate          float64       `json:"solve_rate"`
+	MedianSolveSeconds int           `json:"median_solve_seconds"`
+	CommonFailingTests []FailingTest `json:"common_failing_tests"`
+}
+
+// recordAttempt stores an attempt sent by an opted-in client. Attempts are
+// stored anonymously: no license or user identifier is kept.
+func recordAttempt(c *gin.Context) {
+	var attempt Attempt
+	if err := c.BindJSON(&attempt); err != nil || attempt.ProblemID == "" || attempt.DurationSeconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request",
+		})
+		return
+	}
+	attempt.ReceivedAt = time.Now()
+
+	if err := store.SaveAttempt(c.Request.Context(), attempt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attempt"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"status": "recorded",
+	})
+}
+
+// getProblemStats returns aggregate statistics for a problem
+func getProblemStats(c *gin.Context) {
+	id := c.Param("id")
+
+	attempts, err := store.LoadAttempts(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, aggregateAttempts(id, attempts))
+}
+
+// aggregateAttempts computes solve rate, median solve time and the most
+// common failing tests for a set of attempts
+func aggregateAttempts(problemID string, attempts []Attempt) ProblemStats {
+	stats := ProblemStats{
+		ProblemID:          problemID,
+		Attempts:           len(attempts),
+		CommonFailingTests: []FailingTest{},
+	}
+	if len(attempts) == 0 {
+		return stats
+	}
+
+	var solveTimes []int
+	failures := make(map[int]int)
+	for _, a := range attempts {
+		if a.Solved {
+			solveTimes = append(solveTimes, a.DurationSeconds)
+		}
+		for _, test := range a.FailingTests {
+			failures[test]++
+		}
+	}
+
+	stats.SolveRate = float64(len(solveTimes)) / float64(len(attempts))
+	stats.MedianSolveSeconds = median(solveTimes)
+
+	for test, count := range failures {
+		stats.CommonFailingTests = append(stats.CommonFailingTests, FailingTest{Test: test, Failures: count})
+	}
+	sort.Slice(stats.CommonFailingTests, func(i, j int) bool {
+		a, b := stats.CommonFailingTests[i], stats.CommonFailingTests[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.Test < b.Test
+	})
+	if len(stats.CommonFailingTests) > maxFailingTests {
+		stats.CommonFailingTests = stats.CommonFailingTests[:maxFailingTests]
+	}
+
+	return stats
+}
+
+// median returns the median of the values, or 0 for an empty slice
+func median(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAggregateAttempts(t *testing.T) {
+	stats := aggregateAttempts("two-sum", []Attempt{
+		{Solved: true, DurationSeconds: 300},
+		{Solved: true, DurationSeconds: 100},
+		{Solved: true, DurationSeconds: 200},
+		{Solved: false, DurationSeconds: 900, FailingTests: []int{3, 2}},
+		{Solved: false, DurationSeconds: 600, FailingTests: []int{3}},
+	})
+
+	if stats.Attempts != 5 {
+		t.Fatalf("expected 5 attempts, got %d", stats.Attempts)
+	}
+	if stats.SolveRate != 0.6 {
+		t.Fatalf("expected solve rate 0.6, got %v", stats.SolveRate)
+	}
+	if stats.MedianSolveSeconds != 200 {
+		t.Fatalf("expected median 200, got %d", stats.MedianSolveSeconds)
+	}
+	if len(stats.CommonFailingTests) != 2 || stats.CommonFailingTests[0] != (FailingTest{Test: 3, Failures: 2}) {
+		t.Fatalf("unexpected failing tests: %+v", stats.CommonFailingTests)
+	}
+}
+
+func TestProblemStatsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	body, _ := json.Marshal(Attempt{ProblemID: "max-subarray", Solved: true, DurationSeconds: 120})
+	req := httptest.NewRequest(http.MethodPost, "/v1/telemetry/attempts", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/problems/max-subarray/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+testLicense)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var stats ProblemStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Attempts != 1 || stats.MedianSolveSeconds != 120 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,76 @@
+// Problem set deltas, so clients download only what changed since their version
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemUpdates lists the problems that changed since a client's version
+type ProblemUpdates struct {
+	Version  string    `json:"version"`
+	Since    string    `json:"since"`
+	Full     bool      `json:"full"`     // The since version is unknown, so every problem is listed
+	Problems []Problem `json:"problems"` // Added or changed problems
+	Removed  []string  `json:"removed"`  // IDs of problems no longer in the set
+}
+
+// getProblemUpdates returns the problems added, changed or removed since
+// the version in the since query parameter
+func getProblemUpdates(c *gin.Context) {
+	since := c.Query("since")
+	updates := ProblemUpdates{
+		Version:  problemsDB.Version,
+		Since:    since,
+		Problems: []Problem{},
+		Removed:  []string{},
+	}
+
+	if since != problemsDB.Version {
+		old, ok, err := store.LoadProblemSetVersion(c.Request.Context(), since)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load problem set"})
+			return
+		}
+		if ok {
+			updates.Problems, updates.Removed = diffProblemSets(old, problemsDB)
+		} else {
+			updates.Full = true
+			updates.Problems = append(updates.Problems, problemsDB.Problems...)
+		}
+	}
+
+	c.Header("Cache-Control", manifestCacheControl)
+	c.JSON(http.StatusOK, updates)
+}
+
+// diffProblemSets returns the problems in current that are new or differ
+// from old, and the IDs of old problems current no longer has
+func diffProblemSets(old, current ProblemSet) (changed []Problem, removed []string) {
+	previous := make(map[string][]byte, len(old.Problems))
+	for _, p := range old.Problems {
+		data, _ := json.Marshal(p)
+		previous[p.ID] = data
+	}
+
+	changed = []Problem{}
+	for _, p := range current.Problems {
+		data, _ := json.Marshal(p)
+		if before, ok := previous[p.ID]; !ok || !bytes.Equal(before, data) {
+			changed = append(changed, p)
+		}
+		delete(previous, p.ID)
+	}
+
+	removed = []string{}
+	for _, p := range old.Problems {
+		if _, ok := previous[p.ID]; ok {
+			removed = append(removed, p.ID)
+		}
+	}
+	return changed, removed
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetProblemUpdates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter()
+
+	// An older set where the first problem has since changed, the second
+	// was added and a third was removed
+	old := ProblemSet{Version: "0.9.0"}
+	first := problemsDB.Problems[0]
+	first.Title = "Old title"
+	old.Problems = append(old.Problems, first, Problem{ID: "retired"})
+	old.Problems = append(old.Problems, problemsDB.Problems[2:]...)
+	if err := store.SaveProblemSet(context.Background(), old); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(since string) ProblemUpdates {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v1/problems/updates?since="+since, nil)
+		req.Header.Set("Authorization", "Bearer "+testLicense)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var updates ProblemUpdates
+		if err := json.Unmarshal(w.Body.Bytes(), &updates); err != nil {
+			t.Fatal(err)
+		}
+		return updates
+	}
+
+	updates := get("0.9.0")
+	if updates.Full || updates.Version != problemsDB.Version {
+		t.Fatalf("expected a delta to %s, got %+v", problemsDB.Version, updates)
+	}
+	if len(updates.Problems) != 2 || updates.Problems[0].ID != problemsDB.Problems[0].ID || updates.Problems[1].ID != problemsDB.Problems[1].ID {
+		t.Fatalf("expected the changed and added problems, got %d", len(updates.Problems))
+	}
+	if len(updates.Removed) != 1 || updates.Removed[0] != "retired" {
+		t.Fatalf("expected the retired problem to be removed, got %v", updates.Removed)
+	}
+
+	// Clients already on the current version get nothing
+	if updates := get(problemsDB.Version); updates.Full || len(updates.Problems) != 0 || len(updates.Removed) != 0 {
+		t.Fatalf("expected no updates, got %+v", updates)
+	}
+
+	// An unknown version gets the whole set
+	if updates := get("unknown"); !updates.Full || len(updates.Problems) != len(problemsDB.Problems) {
+		t.Fatalf("expected every problem, got full=%v with %d", updates.Full, len(updates.Problems))
+	}
+}
@@ -0,0 +1,432 @@
+// Profiles, per-user progress and weekly leaderboards, so a team or class
+// can follow each other's practice
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionKind is the kind of the progress records clients sync sessions as
+const sessionKind = "session"
+
+// maxProfileFieldLength bounds display names and team names, in characters
+const maxProfileFieldLength = 40
+
+// defaultWeeklyLimit is how many standings a leaderboard lists unless asked
+// for a limit, and maxWeeklyLimit the most it lists
+const (
+	defaultWeeklyLimit = 25
+	maxWeeklyLimit     = 100
+)
+
+// Profile is how a user appears to others: the name shown on leaderboards,
+// the team or class they practice with, and whether they opted out of
+// sharing their progress. Users who never saved one are shown under a name
+// made from their user ID.
+type Profile struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Team        string    `json:"team,omitempty"`
+	Private     bool      `json:"private"` // Hidden from leaderboards and other users
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// UserProgress is a user's practice as others see it, totalled from the
+// sessions they synced
+type UserProgress struct {
+	UserID          string            `json:"user_id"`
+	DisplayName     string            `json:"display_name"`
+	Team            string            `json:"team,omitempty"`
+	Sessions        int               `json:"sessions"`
+	Attempted       int               `json:"attempted"` // Different problems attempted
+	Solved          int               `json:"solved"`    // Different problems solved
+	SuccessRate     float64           `json:"success_rate"`
+	PracticeMinutes int               `json:"practice_minutes"`
+	ActiveDays      int               `json:"active_days"`
+	LastPracticed   *time.Time        `json:"last_practiced,omitempty"`
+	ThisWeek        WeeklyStanding    `json:"this_week"`
+	Patterns        []PatternProgress `json:"patterns"` // Most attempted first
+}
+
+// PatternProgress is a user's practice of one pattern
+type PatternProgress struct {
+	Pattern   string `json:"pattern"`
+	Attempted int    `json:"attempted"` // Sessions
+	Solved    int    `json:"solved"`
+}
+
+// WeeklyStanding is a user's practice in one week and their place on its
+// leaderboard
+type WeeklyStanding struct {
+	Rank            int    `json:"rank,omitempty"`
+	UserID          string `json:"user_id"`
+	DisplayName     string `json:"display_name"`
+	Team            string `json:"team,omitempty"`
+	Solved          int    `json:"solved"` // Different problems solved
+	Sessions        int    `json:"sessions"`
+	PracticeMinutes int    `json:"practice_minutes"`
+}
+
+// WeeklyLeaderboard ranks the users who practiced in an ISO week, UTC
+type WeeklyLeaderboard struct {
+	Week    string           `json:"week"` // e.g. 2026-W42
+	Start   time.Time        `json:"start"`
+	End     time.Time        `json:"end"`
+	Team    string           `json:"team,omitempty"`
+	Users   int              `json:"users"` // Users ranked, beyond the limit too
+	Entries []WeeklyStanding `json:"entries"`
+	Me      *WeeklyStanding  `json:"me,omitempty"` // The caller, if ranked
+}
+
+// syncedSession is the part of a synced session record the server reads.
+// Clients send sessions with Go's default field names.
+type syncedSession struct {
+	ProblemID string
+	StartTime time.Time
+	Duration  time.Duration
+	Solved    bool
+	Patterns  []string
+}
+
+// member is a user with a profile or synced sessions
+type member struct {
+	licenseKey string
+	profile    Profile
+	sessions   []syncedSession
+}
+
+// userIDFor derives the public ID of the user holding a license, so users
+// can be named in URLs without their license being revealed
+func userIDFor(licenseKey string) string {
+	sum := sha256.Sum256([]byte(licenseKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// defaultProfile is the profile of a user who never saved one
+func defaultProfile(licenseKey string) Profile {
+	id := userIDFor(licenseKey)
+	return Profile{UserID: id, DisplayName: "user-" + id[:8]}
+}
+
+// loadMembers returns every user with a profile or synced sessions
+func loadMembers(ctx context.Context) (map[string]*member, error) {
+	profiles, err := store.LoadProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.LoadAllProgress(ctx, sessionKind)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]*member, len(records))
+	get := func(licenseKey string) *member {
+		m, ok := members[licenseKey]
+		if !ok {
+			m = &member{licenseKey: licenseKey, profile: defaultProfile(licenseKey)}
+			members[licenseKey] = m
+		}
+		return m
+	}
+	for licenseKey, p := range profiles {
+		p.UserID = userIDFor(licenseKey)
+		get(licenseKey).profile = p
+	}
+	for licenseKey, rs := range records {
+		m := get(licenseKey)
+		for _, r := range rs {
+			var s syncedSession
+			// A record the server cannot read is left out rather than
+			// failing everyone's totals
+			if err := json.Unmarshal(r.Data, &s); err != nil || s.ProblemID == "" {
+				continue
+			}
+			m.sessions = append(m.sessions, s)
+		}
+	}
+	return members, nil
+}
+
+// getProfile returns the caller's profile
+func getProfile(c *gin.Context) {
+	licenseKey := c.GetString("license_key")
+	profiles, err := store.LoadProfiles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load profile"})
+		return
+	}
+	profile, ok := profiles[licenseKey]
+	if !ok {
+		profile = defaultProfile(licenseKey)
+	}
+	profile.UserID = userIDFor(licenseKey)
+	c.JSON(http.StatusOK, profile)
+}
+
+// putProfile replaces the caller's profile
+func putProfile(c *gin.Context) {
+	var body struct {
+		DisplayName string `json:"display_name"`
+		Team        string `json:"team"`
+		Private     bool   `json:"private"`
+	}
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	licenseKey := c.GetString("license_key")
+	profile := defaultProfile(licenseKey)
+	if name := strings.TrimSpace(body.DisplayName); name != "" {
+		profile.DisplayName = name
+	}
+	profile.Team = strings.TrimSpace(body.Team)
+	profile.Private = body.Private
+	profile.UpdatedAt = time.Now().UTC()
+	if utf8.RuneCountInString(profile.DisplayName) > maxProfileFieldLength || utf8.RuneCountInString(profile.Team) > maxProfileFieldLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Display and team names are limited to %d characters", maxProfileFieldLength)})
+		return
+	}
+
+	if err := store.SaveProfile(c.Request.Context(), licenseKey, profile); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save profile"})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// getUserProgress returns a user's progress. "me" names the caller. Users
+// who opted out are only visible to themselves, and appear not to exist to
+// everyone else.
+func getUserProgress(c *gin.Context) {
+	licenseKey := c.GetString("license_key")
+	id := c.Param("id")
+	if id == "me" {
+		id = userIDFor(licenseKey)
+	}
+
+	members, err := loadMembers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load progress"})
+		return
+	}
+	var found *member
+	for _, m := range members {
+		if m.profile.UserID == id {
+			found = m
+			break
+		}
+	}
+	if found == nil && id == userIDFor(licenseKey) {
+		found = &member{licenseKey: licenseKey, profile: defaultProfile(licenseKey)}
+	}
+	if found == nil || (found.profile.Private && found.licenseKey != licenseKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, userProgress(found, time.Now()))
+}
+
+// getLeaderboard ranks the users who practiced in a week: the current week
+// unless week names another, as 2026-W42. team limits it to one team.
+func getLeaderboard(c *gin.Context) {
+	now := time.Now()
+	start, err := parseWeek(c.Query("week"), now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := defaultWeeklyLimit
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxWeeklyLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxWeeklyLimit)})
+			return
+		}
+	}
+
+	members, err := loadMembers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load progress"})
+		return
+	}
+	board := weeklyLeaderboard(members, start, strings.TrimSpace(c.Query("team")))
+
+	caller := userIDFor(c.GetString("license_key"))
+	for i := range board.Entries {
+		if board.Entries[i].UserID == caller {
+			me := board.Entries[i]
+			board.Me = &me
+		}
+	}
+	if len(board.Entries) > limit {
+		board.Entries = board.Entries[:limit]
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// weeklyLeaderboard ranks the members who practiced in the week from start
+// and did not opt out, by problems solved, then sessions. Members who tie
+// share a rank.
+func weeklyLeaderboard(members map[string]*member, start time.Time, team string) WeeklyLeaderboard {
+	end := start.AddDate(0, 0, 7)
+	year, week := start.ISOWeek()
+	board := WeeklyLeaderboard{Week: fmt.Sprintf("%d-W%02d", year, week), Start: start, End: end, Team: team, Entries: []WeeklyStanding{}}
+
+	for _, m := range members {
+		if m.profile.Private || (team != "" && !strings.EqualFold(m.profile.Team, team)) {
+			continue
+		}
+		standing := weeklyStanding(m, start, end)
+		if standing.Sessions > 0 {
+			board.Entries = append(board.Entries, standing)
+		}
+	}
+	sort.Slice(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Solved != b.Solved {
+			return a.Solved > b.Solved
+		}
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+		return a.DisplayName < b.DisplayName
+	})
+	for i := range board.Entries {
+		entry := &board.Entries[i]
+		entry.Rank = i + 1
+		if i > 0 {
+			if prev := board.Entries[i-1]; prev.Solved == entry.Solved && prev.Sessions == entry.Sessions {
+				entry.Rank = prev.Rank
+			}
+		}
+	}
+	board.Users = len(board.Entries)
+	return board
+}
+
+// weeklyStanding totals a member's sessions started between start and end
+func weeklyStanding(m *member, start, end time.Time) WeeklyStanding {
+	standing := WeeklyStanding{UserID: m.profile.UserID, DisplayName: m.profile.DisplayName, Team: m.profile.Team}
+	solved := make(map[string]bool)
+	var practice time.Duration
+	for _, s := range m.sessions {
+		if s.StartTime.Before(start) || !s.StartTime.Before(end) {
+			continue
+		}
+		standing.Sessions++
+		practice += s.Duration
+		if s.Solved {
+			solved[s.ProblemID] = true
+		}
+	}
+	standing.Solved = len(solved)
+	standing.PracticeMinutes = int(practice.Minutes())
+	return standing
+}
+
+// userProgress totals a member's sessions, as of now
+func userProgress(m *member, now time.Time) UserProgress {
+	progress := UserProgress{
+		UserID:      m.profile.UserID,
+		DisplayName: m.profile.DisplayName,
+		Team:        m.profile.Team,
+		Sessions:    len(m.sessions),
+		Patterns:    []PatternProgress{},
+	}
+
+	attempted, solved, days := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	patterns := make(map[string]*PatternProgress)
+	solvedSessions := 0
+	var practice time.Duration
+	for _, s := range m.sessions {
+		attempted[s.ProblemID] = true
+		days[s.StartTime.UTC().Format("2006-01-02")] = true
+		practice += s.Duration
+		if s.Solved {
+			solved[s.ProblemID] = true
+			solvedSessions++
+		}
+		if progress.LastPracticed == nil || s.StartTime.After(*progress.LastPracticed) {
+			last := s.StartTime
+			progress.LastPracticed = &last
+		}
+		for _, name := range s.Patterns {
+			p, ok := patterns[name]
+			if !ok {
+				p = &PatternProgress{Pattern: name}
+				patterns[name] = p
+			}
+			p.Attempted++
+			if s.Solved {
+				p.Solved++
+			}
+		}
+	}
+	progress.Attempted, progress.Solved, progress.ActiveDays = len(attempted), len(solved), len(days)
+	progress.PracticeMinutes = int(practice.Minutes())
+	if len(m.sessions) > 0 {
+		progress.SuccessRate = float64(solvedSessions) / float64(len(m.sessions)) * 100
+	}
+
+	for _, p := range patterns {
+		progress.Patterns = append(progress.Patterns, *p)
+	}
+	sort.Slice(progress.Patterns, func(i, j int) bool {
+		a, b := progress.Patterns[i], progress.Patterns[j]
+		if a.Attempted != b.Attempted {
+			return a.Attempted > b.Attempted
+		}
+		return a.Pattern < b.Pattern
+	})
+
+	start := weekStart(now)
+	progress.ThisWeek = weeklyStanding(m, start, start.AddDate(0, 0, 7))
+	return progress
+}
+
+// parseWeek returns the Monday, UTC, that starts an ISO week written as
+// 2026-W42, or the current week when none is given
+func parseWeek(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return weekStart(now), nil
+	}
+	invalid := fmt.Errorf("invalid week %q: expected a week such as 2026-W42", value)
+	yearPart, weekPart, ok := strings.Cut(value, "-W")
+	if !ok {
+		return time.Time{}, invalid
+	}
+	year, err := strconv.Atoi(yearPart)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	week, err := strconv.Atoi(weekPart)
+	if err != nil || week < 1 {
+		return time.Time{}, invalid
+	}
+
+	// January 4th is always in the first ISO week
+	start := weekStart(time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)).AddDate(0, 0, 7*(week-1))
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid week %q: %d has no week %d", value, year, week)
+	}
+	return start, nil
+}
+
+// weekStart returns midnight UTC on the Monday of t's ISO week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
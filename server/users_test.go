@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseWeek(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC) // A Friday
+	start, err := parseWeek("", now)
+	if err != nil || !start.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the current week to start on Monday the 12th, got %v, %v", start, err)
+	}
+
+	for value, want := range map[string]time.Time{
+		"2026-W42": time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		"2026-W01": time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC),
+		"2020-W53": time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC),
+	} {
+		if got, err := parseWeek(value, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseWeek(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"2026-42", "2026-W0", "2025-W53", "this week"} {
+		if _, err := parseWeek(value, now); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestWeeklyLeaderboard(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	session := func(id string, day int, solved bool) syncedSession {
+		return syncedSession{ProblemID: id, StartTime: start.AddDate(0, 0, day), Duration: 20 * time.Minute, Solved: solved}
+	}
+	members := map[string]*member{
+		"a": {profile: Profile{UserID: "a", DisplayName: "Ada", Team: "cs101"}, sessions: []syncedSession{
+			session("two_sum", 0, true), session("two_sum", 1, true), session("three_sum", 2, true),
+			session("old", -1, true), // The week before
+		}},
+		"b": {profile: Profile{UserID: "b", DisplayName: "Bo", Team: "cs101"}, sessions: []syncedSession{session("two_sum", 3, true), session("lru", 4, false)}},
+		"c": {profile: Profile{UserID: "c", DisplayName: "Cy", Team: "CS101"}, sessions: []syncedSession{session("two_sum", 3, true), session("lru", 6, false)}},
+		"d": {profile: Profile{UserID: "d", DisplayName: "Di", Private: true}, sessions: []syncedSession{session("two_sum", 0, true)}},
+		"e": {profile: Profile{UserID: "e", DisplayName: "Ed"}, sessions: []syncedSession{session("two_sum", 7, true)}}, // The week after
+	}
+
+	board := weeklyLeaderboard(members, start, "")
+	if board.Week != "2026-W42" || !board.End.Equal(start.AddDate(0, 0, 7)) {
+		t.Fatalf("unexpected week %s to %v", board.Week, board.End)
+	}
+	if board.Users != 3 || len(board.Entries) != 3 {
+		t.Fatalf("expected the 3 users who practiced and did not opt out, got %+v", board.Entries)
+	}
+	want := []struct {
+		name         string
+		rank, solved int
+	}{{"Ada", 1, 2}, {"Bo", 2, 1}, {"Cy", 2, 1}}
+	for i, w := range want {
+		e := board.Entries[i]
+		if e.DisplayName != w.name || e.Rank != w.rank || e.Solved != w.solved {
+			t.Errorf("entry %d: got %s ranked %d with %d solved; want %s ranked %d with %d", i, e.DisplayName, e.Rank, e.Solved, w.name, w.rank, w.solved)
+		}
+	}
+	if board.Entries[0].Sessions != 3 || board.Entries[0].PracticeMinutes != 60 {
+		t.Errorf("expected 3 sessions and 60 minutes this week, got %+v", board.Entries[0])
+	}
+
+	if board := weeklyLeaderboard(members, start, "cs101"); board.Users != 3 || board.Team != "cs101" {
+		t.Fatalf("expected the team's 3 users, matching case-insensitively, got %+v", board.Entries)
+	}
+	if board := weeklyLeaderboard(members, start, "other"); len(board.Entries) != 0 {
+		t.Fatalf("expected nobody on another team, got %+v", board.Entries)
+	}
+}
+
+func TestUserProgressAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	original := store
+	store = newMemoryStore()
+	t.Cleanup(func() { store = original })
+	r := setupRouter()
+
+	do := func(license, method, path, body string, v interface{}) int {
+		t.Helper()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+license)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if v != nil && w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code
+	}
+	push := func(license string, solved ...string) {
+		t.Helper()
+		var records []string
+		for i, id := range solved {
+			started := time.Now().UTC().Add(-time.Duration(i+1) * time.Minute).Format(time.RFC3339Nano)
+			records = append(records, fmt.Sprintf(`{"kind":"session","key":"%s@%s","updated_at":"%s","data":{"ProblemID":"%s","StartTime":"%s","Duration":600000000000,"Solved":true,"Patterns":["hash-map"]}}`,
+				id, started, started, id, started))
+		}
+		body := `{"records":[` + records[0]
+		for _, rec := range records[1:] {
+			body += "," + rec
+		}
+		if code := do(license, http.MethodPost, "/v1/progress", body+"]}", nil); code != http.StatusOK {
+			t.Fatalf("pushing progress failed with %d", code)
+		}
+	}
+
+	alice, bob, carol := testLicenseFor("alice-team"), testLicenseFor("bob-team"), testLicenseFor("carol-team")
+	push(alice, "two_sum", "three_sum")
+	push(bob, "two_sum")
+	push(carol, "two_sum", "three_sum", "lru")
+
+	var profile Profile
+	if code := do(alice, http.MethodPut, "/v1/profile", `{"display_name":" Alice ","team":"cs101"}`, &profile); code != http.StatusOK {
+		t.Fatalf("expected the profile to be saved, got %d", code)
+	}
+	if profile.DisplayName != "Alice" || profile.Team != "cs101" || profile.UserID != userIDFor(alice) {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	do(carol, http.MethodPut, "/v1/profile", `{"display_name":"Carol","team":"cs101","private":true}`, nil)
+	if code := do(bob, http.MethodPut, "/v1/profile", fmt.Sprintf(`{"display_name":"%045d"}`, 0), nil); code != http.StatusBadRequest {
+		t.Fatalf("expected a long name to be refused, got %d", code)
+	}
+	if do(bob, http.MethodGet, "/v1/profile", "", &profile); profile.DisplayName != "user-"+userIDFor(bob)[:8] {
+		t.Fatalf("expected a default profile, got %+v", profile)
+	}
+
+	// Others see Alice's progress; only Carol sees hers
+	var progress UserProgress
+	if code := do(bob, http.MethodGet, "/v1/users/"+userIDFor(alice)+"/progress", "", &progress); code != http.StatusOK {
+		t.Fatalf("expected Alice's progress, got %d", code)
+	}
+	if progress.DisplayName != "Alice" || progress.Solved != 2 || progress.ThisWeek.Solved != 2 || progress.PracticeMinutes != 20 ||
+		len(progress.Patterns) != 1 || progress.Patterns[0].Solved != 2 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	if code := do(bob, http.MethodGet, "/v1/users/"+userIDFor(carol)+"/progress", "", nil); code != http.StatusNotFound {
+		t.Fatalf("expected a private user to be hidden, got %d", code)
+	}
+	if code := do(carol, http.MethodGet, "/v1/users/me/progress", "", &progress); code != http.StatusOK || progress.Solved != 3 {
+		t.Fatalf("expected Carol to see her own progress, got %d with %+v", code, progress)
+	}
+	if code := do(bob, http.MethodGet, "/v1/users/nobody/progress", "", nil); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown user, got %d", code)
+	}
+
+	var board WeeklyLeaderboard
+	if code := do(bob, http.MethodGet, "/v1/leaderboard", "", &board); code != http.StatusOK {
+		t.Fatalf("expected the leaderboard, got %d", code)
+	}
+	if board.Users != 2 || board.Entries[0].DisplayName != "Alice" || board.Me == nil || board.Me.Rank != 2 {
+		t.Fatalf("expected Alice then Bob, without Carol, got %+v (me %+v)", board.Entries, board.Me)
+	}
+	var team WeeklyLeaderboard
+	if do(bob, http.MethodGet, "/v1/leaderboard?team=cs101&limit=1", "", &team); team.Users != 1 || team.Me != nil {
+		t.Fatalf("expected only Alice on cs101, got %+v", team.Entries)
+	}
+	if code := do(bob, http.MethodGet, "/v1/leaderboard?week=last", "", nil); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid week, got %d", code)
+	}
+	if code := do(bob, http.MethodGet, "/v1/leaderboard?limit=0", "", nil); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid limit, got %d", code)
+	}
+	if code := do("not-a-license", http.MethodGet, "/v1/leaderboard", "", nil); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a license, got %d", code)
+	}
+
+	// Deleting their data removes Alice's profile and progress
+	var deleted struct {
+		Deleted int `json:"deleted"`
+	}
+	do(alice, http.MethodDelete, "/v1/user-data", "", &deleted)
+	if deleted.Deleted != 3 {
+		t.Fatalf("expected 2 sessions and the profile deleted, got %d", deleted.Deleted)
+	}
+	if do(bob, http.MethodGet, "/v1/leaderboard", "", &board); board.Users != 1 {
+		t.Fatalf("expected only Bob left, got %+v", board.Entries)
+	}
+}